@@ -0,0 +1,87 @@
+// Package amp implements the receive side of atomic multi-path payments,
+// reconstructing the preimages of a payment's shards from the shares of its
+// root seed carried within their onion payloads.
+package amp
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// Share is a share of the root seed of an atomic multi-path payment. The root
+// seed is the XOR of the shares carried by all of the payment's shards, so it
+// can only be reconstructed once every shard has been received.
+type Share [32]byte
+
+// Xor returns the XOR of the share with the passed share.
+func (s Share) Xor(other Share) Share {
+	var xor Share
+	for i := range s {
+		xor[i] = s[i] ^ other[i]
+	}
+
+	return xor
+}
+
+// ChildDesc describes a single shard of an atomic multi-path payment, as
+// carried within its onion payload.
+type ChildDesc struct {
+	// Share is the share of the root seed carried by the shard.
+	Share Share
+
+	// Index is the index used to derive the shard's preimage from the
+	// root seed.
+	Index uint32
+}
+
+// Child is a shard of an atomic multi-path payment, along with the preimage
+// derived for it from the root seed, and the hash the shard pays to.
+type Child struct {
+	ChildDesc
+
+	// Preimage is the preimage of the shard.
+	Preimage [32]byte
+
+	// Hash is the hash of the preimage, which the shard's HTLC pays to.
+	Hash [32]byte
+}
+
+// DeriveChild derives the preimage and hash of the described shard from the
+// passed root seed. The preimage is the SHA256 of the root seed followed by
+// the big endian encoding of the shard's index.
+func DeriveChild(root Share, desc ChildDesc) *Child {
+	var index [4]byte
+	binary.BigEndian.PutUint32(index[:], desc.Index)
+
+	h := sha256.New()
+	h.Write(root[:])
+	h.Write(index[:])
+
+	var preimage [32]byte
+	copy(preimage[:], h.Sum(nil))
+
+	return &Child{
+		ChildDesc: desc,
+		Preimage:  preimage,
+		Hash:      sha256.Sum256(preimage[:]),
+	}
+}
+
+// ReconstructChildren reconstructs the root seed from the shares of the
+// described shards, then derives the preimage and hash of each of them. The
+// returned children are ordered as the passed descriptions. Should any shard
+// be missing, the derived preimages are meaningless, as the reconstructed
+// root seed is incorrect.
+func ReconstructChildren(descs ...ChildDesc) []*Child {
+	var root Share
+	for _, desc := range descs {
+		root = root.Xor(desc.Share)
+	}
+
+	children := make([]*Child, 0, len(descs))
+	for _, desc := range descs {
+		children = append(children, DeriveChild(root, desc))
+	}
+
+	return children
+}
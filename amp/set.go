@@ -0,0 +1,151 @@
+package amp
+
+import (
+	"errors"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+var (
+	// ErrSetIDMismatch is returned when attempting to add a shard to a
+	// set whose ID differs from the shard's.
+	ErrSetIDMismatch = errors.New("shard belongs to a different set")
+
+	// ErrTotalMismatch is returned when attempting to add a shard whose
+	// total payment amount differs from that of the other shards of the
+	// set.
+	ErrTotalMismatch = errors.New("shard total differs from set total")
+
+	// ErrDuplicateShard is returned when attempting to add a shard whose
+	// child index has already been used by another shard of the set.
+	ErrDuplicateShard = errors.New("shard child index already used")
+
+	// ErrSetOverpaid is returned when attempting to add a shard to a set
+	// whose shards already carry the total amount of the payment.
+	ErrSetOverpaid = errors.New("set has already been fully paid")
+
+	// ErrSetIncomplete is returned when attempting to settle a set whose
+	// shards don't yet carry the total amount of the payment.
+	ErrSetIncomplete = errors.New("set hasn't been fully paid")
+
+	// ErrHashMismatch is returned when settling a set where the hash
+	// derived for a shard differs from the hash its HTLC pays to. This
+	// means the sender misconstructed the payment, and every shard must
+	// be failed back.
+	ErrHashMismatch = errors.New("derived shard hash doesn't match " +
+		"htlc payment hash")
+)
+
+// Shard is an HTLC received as part of an atomic multi-path payment.
+type Shard struct {
+	// Hash is the payment hash of the HTLC.
+	Hash [32]byte
+
+	// Amt is the amount of the HTLC.
+	Amt lnwire.MilliAtom
+
+	// Record is the shard data carried within the HTLC's onion payload.
+	Record *lnwire.AMP
+}
+
+// Set accumulates the shards of a single atomic multi-path payment, which are
+// held until the total amount of the payment has arrived. Only then can the
+// root seed be reconstructed, allowing all of the shards to be settled at
+// once. Until then, none of their preimages are known, so the payment can't
+// be partially claimed.
+//
+// NOTE: A Set isn't safe for concurrent use.
+type Set struct {
+	// SetID identifies the payment the set belongs to.
+	SetID [32]byte
+
+	// Total is the total amount of the payment, as carried within the
+	// onion payloads of the shards.
+	Total lnwire.MilliAtom
+
+	shards   []*Shard
+	indexes  map[uint32]struct{}
+	received lnwire.MilliAtom
+}
+
+// NewSet creates an empty set for the payment with the passed set ID and
+// total amount.
+func NewSet(setID [32]byte, total lnwire.MilliAtom) *Set {
+	return &Set{
+		SetID:   setID,
+		Total:   total,
+		indexes: make(map[uint32]struct{}),
+	}
+}
+
+// AddShard adds the passed shard of a payment of the passed total amount to
+// the set. The shard is rejected if it doesn't belong to the set, or if the
+// set has already been fully paid.
+func (s *Set) AddShard(shard *Shard, total lnwire.MilliAtom) error {
+	switch {
+	case shard.Record.SetID != s.SetID:
+		return ErrSetIDMismatch
+
+	case total != s.Total:
+		return ErrTotalMismatch
+
+	case s.IsComplete():
+		return ErrSetOverpaid
+	}
+
+	if _, ok := s.indexes[shard.Record.ChildIndex]; ok {
+		return ErrDuplicateShard
+	}
+	s.indexes[shard.Record.ChildIndex] = struct{}{}
+
+	s.shards = append(s.shards, shard)
+	s.received += shard.Amt
+
+	return nil
+}
+
+// Received returns the total amount of the shards added to the set.
+func (s *Set) Received() lnwire.MilliAtom {
+	return s.received
+}
+
+// IsComplete returns true if the shards of the set carry the total amount of
+// the payment.
+func (s *Set) IsComplete() bool {
+	return s.received >= s.Total
+}
+
+// Shards returns the shards of the set, in the order they were added.
+func (s *Set) Shards() []*Shard {
+	return s.shards
+}
+
+// Settle reconstructs the root seed of a fully paid set, returning the
+// preimage of each of its shards in the order they were added. If the hash
+// derived for any shard doesn't match its HTLC, then ErrHashMismatch is
+// returned, and none of the shards can be settled.
+func (s *Set) Settle() ([][32]byte, error) {
+	if !s.IsComplete() {
+		return nil, ErrSetIncomplete
+	}
+
+	descs := make([]ChildDesc, 0, len(s.shards))
+	for _, shard := range s.shards {
+		descs = append(descs, ChildDesc{
+			Share: Share(shard.Record.RootShare),
+			Index: shard.Record.ChildIndex,
+		})
+	}
+
+	children := ReconstructChildren(descs...)
+	preimages := make([][32]byte, 0, len(children))
+	for i, child := range children {
+		if child.Hash != s.shards[i].Hash {
+			return nil, ErrHashMismatch
+		}
+
+		preimages = append(preimages, child.Preimage)
+	}
+
+	return preimages, nil
+}
@@ -0,0 +1,147 @@
+package amp
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// splitRoot splits the passed root seed into the passed number of random
+// shares, whose XOR is the root seed.
+func splitRoot(t *testing.T, root Share, numShares int) []Share {
+	shares := make([]Share, numShares)
+	last := root
+	for i := 0; i < numShares-1; i++ {
+		if _, err := rand.Read(shares[i][:]); err != nil {
+			t.Fatalf("unable to generate share: %v", err)
+		}
+		last = last.Xor(shares[i])
+	}
+	shares[numShares-1] = last
+
+	return shares
+}
+
+// TestSetSettle asserts that the shards of a set can only be settled once
+// the total amount has arrived, and that the preimages reconstructed for them
+// are those derived by the sender.
+func TestSetSettle(t *testing.T) {
+	t.Parallel()
+
+	var root Share
+	if _, err := rand.Read(root[:]); err != nil {
+		t.Fatalf("unable to generate root seed: %v", err)
+	}
+	setID := [32]byte{1}
+
+	// The sender splits the payment into three shards, each paying to the
+	// hash of a preimage derived from the root seed.
+	const total = lnwire.MilliAtom(3000)
+	shares := splitRoot(t, root, 3)
+	shards := make([]*Shard, len(shares))
+	children := make([]*Child, len(shares))
+	for i, share := range shares {
+		desc := ChildDesc{Share: share, Index: uint32(i)}
+		children[i] = DeriveChild(root, desc)
+		shards[i] = &Shard{
+			Hash:   children[i].Hash,
+			Amt:    total / 3,
+			Record: lnwire.NewAMP(share, setID, desc.Index),
+		}
+	}
+
+	set := NewSet(setID, total)
+
+	// Shards which don't belong to the set should be rejected.
+	otherSet := &Shard{
+		Hash:   shards[0].Hash,
+		Amt:    shards[0].Amt,
+		Record: lnwire.NewAMP(shares[0], [32]byte{2}, 0),
+	}
+	if err := set.AddShard(otherSet, total); err != ErrSetIDMismatch {
+		t.Fatalf("expected ErrSetIDMismatch, got %v", err)
+	}
+	if err := set.AddShard(shards[0], total+1); err != ErrTotalMismatch {
+		t.Fatalf("expected ErrTotalMismatch, got %v", err)
+	}
+
+	// Until all shards have arrived, the set can't be settled.
+	for _, shard := range shards[:2] {
+		if err := set.AddShard(shard, total); err != nil {
+			t.Fatalf("unable to add shard: %v", err)
+		}
+	}
+	if err := set.AddShard(shards[1], total); err != ErrDuplicateShard {
+		t.Fatalf("expected ErrDuplicateShard, got %v", err)
+	}
+	if _, err := set.Settle(); err != ErrSetIncomplete {
+		t.Fatalf("expected ErrSetIncomplete, got %v", err)
+	}
+
+	// Once the final shard arrives, the preimages of all shards should
+	// match those derived by the sender.
+	if err := set.AddShard(shards[2], total); err != nil {
+		t.Fatalf("unable to add shard: %v", err)
+	}
+	if !set.IsComplete() {
+		t.Fatalf("expected set to be complete")
+	}
+	preimages, err := set.Settle()
+	if err != nil {
+		t.Fatalf("unable to settle set: %v", err)
+	}
+	for i, preimage := range preimages {
+		if preimage != children[i].Preimage {
+			t.Fatalf("expected preimage %x for shard %v, got %x",
+				children[i].Preimage, i, preimage)
+		}
+	}
+
+	// No further shards can be added to a fully paid set.
+	extra := &Shard{
+		Hash:   shards[0].Hash,
+		Amt:    1,
+		Record: lnwire.NewAMP(shares[0], setID, 3),
+	}
+	if err := set.AddShard(extra, total); err != ErrSetOverpaid {
+		t.Fatalf("expected ErrSetOverpaid, got %v", err)
+	}
+}
+
+// TestSetSettleHashMismatch asserts that a set can't be settled if the hash
+// derived for any of its shards doesn't match its HTLC.
+func TestSetSettleHashMismatch(t *testing.T) {
+	t.Parallel()
+
+	var root Share
+	if _, err := rand.Read(root[:]); err != nil {
+		t.Fatalf("unable to generate root seed: %v", err)
+	}
+	setID := [32]byte{1}
+
+	shares := splitRoot(t, root, 2)
+	set := NewSet(setID, 2000)
+	for i, share := range shares {
+		child := DeriveChild(root, ChildDesc{
+			Share: share,
+			Index: uint32(i),
+		})
+		if i == 1 {
+			child.Hash = [32]byte{}
+		}
+
+		shard := &Shard{
+			Hash:   child.Hash,
+			Amt:    1000,
+			Record: lnwire.NewAMP(share, setID, uint32(i)),
+		}
+		if err := set.AddShard(shard, 2000); err != nil {
+			t.Fatalf("unable to add shard: %v", err)
+		}
+	}
+
+	if _, err := set.Settle(); err != ErrHashMismatch {
+		t.Fatalf("expected ErrHashMismatch, got %v", err)
+	}
+}
@@ -15,6 +15,9 @@ import (
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/htlcswitch"
 	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/subscribe"
+	"github.com/roasbeef/btcd/blockchain"
 	"github.com/roasbeef/btcd/btcec"
 	"github.com/roasbeef/btcd/chaincfg/chainhash"
 	"github.com/roasbeef/btcd/txscript"
@@ -28,7 +31,24 @@ import (
 // is critical that such state is persisted on disk, so that if our node
 // restarts at any point during the retribution procedure, we can recover and
 // continue from the persisted state.
-var retributionBucket = []byte("retribution")
+var retributionBucket = []byte("retributions")
+
+// legacyRetributionBucket stores the retributions persisted before every
+// breached output was swept by a single justice transaction, whose layout
+// records our own output, the revoked output and the HTLC outputs separately.
+// Its retributions are converted to the current layout as they're read, and
+// are moved to the retributionBucket once they're next added.
+var legacyRetributionBucket = []byte("retribution")
+
+// justiceConfTarget is the confirmation target, in blocks, used to estimate
+// the fee of justice transactions. The remote party is able to sweep their
+// own outputs once their CSV delays have passed, so we'll aim for a timely
+// confirmation.
+const justiceConfTarget = 6
+
+// errBrarShuttingDown is returned when the breach arbiter is unable to
+// complete a task due to shutting down.
+var errBrarShuttingDown = errors.New("breach arbiter shutting down")
 
 // breachArbiter is a special subsystem which is responsible for watching and
 // acting on the detection of any attempted uncooperative channel breaches by
@@ -46,6 +66,10 @@ type breachArbiter struct {
 	estimator  lnwallet.FeeEstimator
 	htlcSwitch *htlcswitch.Switch
 
+	// eventBus, if non-nil, is notified each time a watched channel is
+	// closed or breached.
+	eventBus *subscribe.Server
+
 	retributionStore RetributionStore
 
 	// breachObservers is a map which tracks all the active breach
@@ -83,7 +107,8 @@ type breachArbiter struct {
 // its dependent objects.
 func newBreachArbiter(wallet *lnwallet.LightningWallet, db *channeldb.DB,
 	notifier chainntnfs.ChainNotifier, h *htlcswitch.Switch,
-	chain lnwallet.BlockChainIO, fe lnwallet.FeeEstimator) *breachArbiter {
+	chain lnwallet.BlockChainIO, fe lnwallet.FeeEstimator,
+	eventBus *subscribe.Server) *breachArbiter {
 
 	return &breachArbiter{
 		wallet:     wallet,
@@ -92,6 +117,7 @@ func newBreachArbiter(wallet *lnwallet.LightningWallet, db *channeldb.DB,
 		chainIO:    chain,
 		htlcSwitch: h,
 		estimator:  fe,
+		eventBus:   eventBus,
 
 		retributionStore: newRetributionStore(db),
 
@@ -205,7 +231,7 @@ func (b *breachArbiter) Start() error {
 			// cease.
 			b.htlcSwitch.CloseLink(
 				&chanState.FundingOutpoint,
-				htlcswitch.CloseBreach,
+				htlcswitch.CloseBreach, nil,
 			)
 
 			// Ensure channeldb is consistent with the persisted
@@ -353,6 +379,11 @@ func (b *breachArbiter) contractObserver(
 
 	defer b.wg.Done()
 
+	// shortChanIDs maps the funding outpoint of each watched channel to
+	// its short channel ID, which is included in the close events sent
+	// over the event bus.
+	shortChanIDs := make(map[wire.OutPoint]lnwire.ShortChannelID)
+
 	// For each active channel found within the database, we launch a
 	// detected breachObserver goroutine for that channel and also track
 	// the new goroutine within the breachObservers map so we can cancel it
@@ -361,6 +392,7 @@ func (b *breachArbiter) contractObserver(
 		settleSignal := make(chan struct{})
 		chanPoint := channel.ChannelPoint()
 		b.breachObservers[*chanPoint] = settleSignal
+		shortChanIDs[*chanPoint] = channel.ShortChanID()
 
 		b.wg.Add(1)
 		go b.breachObserver(channel, settleSignal)
@@ -409,6 +441,13 @@ out:
 
 			delete(b.breachObservers, breachInfo.chanPoint)
 
+			sendEvent(b.eventBus, channelCloseEvent{
+				chanPoint:   breachInfo.chanPoint,
+				shortChanID: shortChanIDs[breachInfo.chanPoint],
+				breach:      true,
+			})
+			delete(shortChanIDs, breachInfo.chanPoint)
+
 		case contract := <-b.newContracts:
 			// A new channel has just been opened within the
 			// daemon, so we launch a new breachObserver to handle
@@ -429,6 +468,7 @@ out:
 			}
 
 			b.breachObservers[*chanPoint] = settleSignal
+			shortChanIDs[*chanPoint] = contract.ShortChanID()
 
 			brarLog.Debugf("New contract detected, launching " +
 				"breachObserver")
@@ -459,6 +499,12 @@ out:
 			// map.
 			close(killSignal)
 			delete(b.breachObservers, *chanPoint)
+
+			sendEvent(b.eventBus, channelCloseEvent{
+				chanPoint:   *chanPoint,
+				shortChanID: shortChanIDs[*chanPoint],
+			})
+			delete(shortChanIDs, *chanPoint)
 		case <-b.quit:
 			break out
 		}
@@ -479,10 +525,9 @@ func (b *breachArbiter) exactRetribution(
 
 	defer b.wg.Done()
 
-	// TODO(roasbeef): state needs to be checkpointed here
-
+	var breachHeight uint32
 	select {
-	case _, ok := <-confChan.Confirmed:
+	case breachConf, ok := <-confChan.Confirmed:
 		// If the second value is !ok, then the channel has been closed
 		// signifying a daemon shutdown, so we exit.
 		if !ok {
@@ -491,6 +536,7 @@ func (b *breachArbiter) exactRetribution(
 
 		// Otherwise, if this is a real confirmation notification, then
 		// we fall through to complete our duty.
+		breachHeight = breachConf.BlockHeight
 	case <-b.quit:
 		return
 	}
@@ -498,86 +544,256 @@ func (b *breachArbiter) exactRetribution(
 	brarLog.Debugf("Breach transaction %v has been confirmed, sweeping "+
 		"revoked funds", breachInfo.commitHash)
 
-	// With the breach transaction confirmed, we now create the justice tx
-	// which will claim ALL the funds within the channel.
-	justiceTx, err := b.createJusticeTx(breachInfo)
-	if err != nil {
-		brarLog.Errorf("unable to create justice tx: %v", err)
-		return
-	}
+	// With the breach transaction confirmed, we now sweep all the breached
+	// outputs with a single justice transaction. The remote party may
+	// however spend any of the HTLC outputs with their second-level
+	// transactions before our justice transaction confirms, rendering it
+	// invalid. Should this happen, we'll sweep the outputs of their
+	// second-level transactions instead, crafting a new justice
+	// transaction until all breached outputs have been swept.
+	var claimedFunds btcutil.Amount
+	for len(breachInfo.breachedOutputs) > 0 {
+		justiceTx, err := b.createJusticeTx(breachInfo)
+		if err != nil {
+			brarLog.Errorf("unable to create justice tx: %v", err)
+			return
+		}
+		justiceTXID := justiceTx.TxHash()
+
+		brarLog.Debugf("Broadcasting justice tx: %v",
+			newLogClosure(func() string {
+				return spew.Sdump(justiceTx)
+			}))
+
+		// If one of the breached outputs has already been spent by a
+		// second-level transaction, then the justice transaction will
+		// be rejected. We'll still wait for the spend below, allowing
+		// us to craft a new one.
+		if err := b.wallet.PublishTransaction(justiceTx); err != nil {
+			brarLog.Errorf("unable to broadcast justice tx %v: %v",
+				justiceTXID, err)
+		}
 
-	brarLog.Debugf("Broadcasting justice tx: %v",
-		newLogClosure(func() string {
-			return spew.Sdump(justiceTx)
-		}))
+		index, spend, err := b.waitForBreachedSpend(
+			breachInfo, breachHeight,
+		)
+		if err != nil {
+			if err != errBrarShuttingDown {
+				brarLog.Errorf("unable to wait for spend of "+
+					"breached outputs: %v", err)
+			}
+			return
+		}
 
-	_, currentHeight, err := b.chainIO.GetBestBlock()
-	if err != nil {
-		brarLog.Errorf("unable to get current height: %v", err)
-		return
+		// The spend is detected as soon as the spending transaction
+		// enters the mempool, so we'll wait for it to confirm before
+		// acting on it.
+		err = b.waitForConfirmation(spend.SpenderTxHash, breachHeight)
+		if err != nil {
+			if err != errBrarShuttingDown {
+				brarLog.Errorf("unable to wait for "+
+					"confirmation of spend %v: %v",
+					spend.SpenderTxHash, err)
+			}
+			return
+		}
+
+		// If our justice transaction was confirmed, then all the
+		// breached outputs have been swept and justice has been
+		// served.
+		if spend.SpenderTxHash.IsEqual(&justiceTXID) {
+			claimedFunds += btcutil.Amount(justiceTx.TxOut[0].Value)
+			breachInfo.breachedOutputs = nil
+			break
+		}
+
+		// Otherwise, the output was spent by another transaction.
+		// We'll swap it with the output of the spending transaction
+		// if it was a second-level HTLC transaction, or drop it
+		// otherwise, then checkpoint the remaining outputs before
+		// crafting a new justice transaction.
+		breachInfo.breachedOutputs = splitBreachedOutputs(
+			breachInfo.breachedOutputs, index, spend,
+		)
+		if err := b.retributionStore.Add(breachInfo); err != nil {
+			brarLog.Errorf("unable to persist retribution info "+
+				"to db: %v", err)
+		}
 	}
 
-	// Finally, broadcast the transaction, finalizing the channels'
-	// retribution against the cheating counterparty.
-	if err := b.wallet.PublishTransaction(justiceTx); err != nil {
-		brarLog.Errorf("unable to broadcast "+
-			"justice tx: %v", err)
-		return
+	brarLog.Infof("Justice for ChannelPoint(%v) has been served, %v "+
+		"revoked funds have been claimed", breachInfo.chanPoint,
+		claimedFunds)
+
+	// With the channel closed, mark it in the database as such.
+	err := b.db.MarkChanFullyClosed(&breachInfo.chanPoint)
+	if err != nil {
+		brarLog.Errorf("unable to mark chan as closed: %v", err)
 	}
 
-	// As a conclusionary step, we register for a notification to be
-	// dispatched once the justice tx is confirmed. After confirmation we
-	// notify the caller that initiated the retribution workflow that the
-	// deed has been done.
-	justiceTXID := justiceTx.TxHash()
-	confChan, err = b.notifier.RegisterConfirmationsNtfn(&justiceTXID, 1,
-		uint32(currentHeight))
+	// Justice has been carried out; we can safely delete the retribution
+	// info from the database.
+	err = b.retributionStore.Remove(&breachInfo.chanPoint)
 	if err != nil {
-		brarLog.Errorf("unable to register for conf for txid: %v",
-			justiceTXID)
-		return
+		brarLog.Errorf("unable to remove retribution from the db: %v",
+			err)
 	}
 
-	select {
-	case _, ok := <-confChan.Confirmed:
-		if !ok {
-			return
-		}
+	// TODO(roasbeef): add peer to blacklist?
 
-		// TODO(roasbeef): factor in HTLCs
-		revokedFunds := breachInfo.revokedOutput.amt
-		totalFunds := revokedFunds + breachInfo.selfOutput.amt
+	// TODO(roasbeef): close other active channels with offending peer
 
-		brarLog.Infof("Justice for ChannelPoint(%v) has "+
-			"been served, %v revoked funds (%v total) "+
-			"have been claimed", breachInfo.chanPoint,
-			revokedFunds, totalFunds)
+	close(breachInfo.doneChan)
+}
 
-		// With the channel closed, mark it in the database as such.
-		err := b.db.MarkChanFullyClosed(&breachInfo.chanPoint)
-		if err != nil {
-			brarLog.Errorf("unable to mark chan as closed: %v", err)
-		}
+// waitForBreachedSpend blocks until any of the breached outputs of the
+// retribution has been spent, returning the index of the spent output along
+// with the details of its spend. The passed height hint should be the height
+// at which the breach transaction confirmed.
+func (b *breachArbiter) waitForBreachedSpend(breachInfo *retributionInfo,
+	heightHint uint32) (int, *chainntnfs.SpendDetail, error) {
+
+	type breachedSpend struct {
+		index  int
+		detail *chainntnfs.SpendDetail
+	}
+
+	// We'll register for the spend of each breached output, forwarding
+	// the first spend detected to the spends channel.
+	var wg sync.WaitGroup
+	defer wg.Wait()
 
-		// Justice has been carried out; we can safely delete the
-		// retribution info from the database.
-		err = b.retributionStore.Remove(&breachInfo.chanPoint)
+	spends := make(chan breachedSpend)
+	exit := make(chan struct{})
+	defer close(exit)
+
+	for i := range breachInfo.breachedOutputs {
+		outpoint := breachInfo.breachedOutputs[i].outpoint
+		spendNtfn, err := b.notifier.RegisterSpendNtfn(
+			&outpoint, heightHint,
+		)
 		if err != nil {
-			brarLog.Errorf("unable to remove retribution "+
-				"from the db: %v", err)
+			return 0, nil, err
 		}
+		defer spendNtfn.Cancel()
 
-		// TODO(roasbeef): add peer to blacklist?
+		wg.Add(1)
+		go func(index int) {
+			defer wg.Done()
 
-		// TODO(roasbeef): close other active channels with offending
-		// peer
+			select {
+			case detail, ok := <-spendNtfn.Spend:
+				if !ok {
+					return
+				}
 
-		close(breachInfo.doneChan)
+				select {
+				case spends <- breachedSpend{index, detail}:
+				case <-exit:
+				}
 
-		return
+			case <-exit:
+			}
+		}(i)
+	}
+
+	select {
+	case spend := <-spends:
+		return spend.index, spend.detail, nil
 	case <-b.quit:
-		return
+		return 0, nil, errBrarShuttingDown
+	}
+}
+
+// waitForConfirmation blocks until the passed transaction, which spends one
+// of the breached outputs, has confirmed. The passed height hint should be the
+// height at which the breach transaction confirmed.
+func (b *breachArbiter) waitForConfirmation(txid *chainhash.Hash,
+	heightHint uint32) error {
+
+	confNtfn, err := b.notifier.RegisterConfirmationsNtfn(
+		txid, 1, heightHint,
+	)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case _, ok := <-confNtfn.Confirmed:
+		if !ok {
+			return errBrarShuttingDown
+		}
+		return nil
+
+	case <-b.quit:
+		return errBrarShuttingDown
+	}
+}
+
+// splitBreachedOutputs returns the breached outputs that remain to be swept
+// after the output at the passed index was spent by a transaction other than
+// our justice transaction. If the output was an HTLC spent by the remote
+// party's second-level transaction, then it's replaced by the output of the
+// second-level transaction, which we're able to sweep using its revocation
+// clause. Otherwise, there's nothing left for us to claim, so the output is
+// dropped.
+func splitBreachedOutputs(outputs []breachedOutput, index int,
+	spend *chainntnfs.SpendDetail) []breachedOutput {
+
+	spent := outputs[index]
+	remaining := make([]breachedOutput, 0, len(outputs))
+	remaining = append(remaining, outputs[:index]...)
+	remaining = append(remaining, outputs[index+1:]...)
+
+	if !spent.twoStageClaim || !isSecondLevelSpend(&spent, spend) {
+		brarLog.Warnf("Breached output %v spent by %v, no longer "+
+			"sweeping it", spent.outpoint, spend.SpenderTxHash)
+		return remaining
+	}
+
+	brarLog.Infof("Breached htlc output %v spent by second-level tx %v, "+
+		"sweeping its output instead", spent.outpoint,
+		spend.SpenderTxHash)
+
+	// The second-level output is swept using the same revocation key as
+	// the HTLC output, so we only need to swap in its witness script and
+	// output.
+	secondLevelOutput := spend.SpendingTx.TxOut[0]
+	signDesc := spent.signDescriptor
+	signDesc.WitnessScript = spent.secondLevelWitnessScript
+	signDesc.Output = secondLevelOutput
+
+	return append(remaining, breachedOutput{
+		amt: btcutil.Amount(secondLevelOutput.Value),
+		outpoint: wire.OutPoint{
+			Hash:  *spend.SpenderTxHash,
+			Index: 0,
+		},
+		signDescriptor: signDesc,
+		witnessType:    lnwallet.HtlcSecondLevelRevoke,
+	})
+}
+
+// isSecondLevelSpend returns true if the spend of the breached HTLC output was
+// made by the remote party's second-level HTLC transaction, which pays to the
+// output's second-level witness script.
+func isSecondLevelSpend(bo *breachedOutput,
+	spend *chainntnfs.SpendDetail) bool {
+
+	spendingTx := spend.SpendingTx
+	if spendingTx == nil || len(spendingTx.TxOut) != 1 {
+		return false
+	}
+
+	builder := txscript.NewScriptBuilder()
+	builder.AddOp(txscript.OP_0)
+	builder.AddData(chainhash.HashB(bo.secondLevelWitnessScript))
+	pkScript, err := builder.Script()
+	if err != nil {
+		return false
 	}
+
+	return bytes.Equal(spendingTx.TxOut[0].PkScript, pkScript)
 }
 
 // breachObserver notifies the breachArbiter contract observer goroutine that a
@@ -684,76 +900,16 @@ func (b *breachArbiter) breachObserver(contract *lnwallet.LightningChannel,
 		// breached in order to ensure any incoming or outgoing
 		// multi-hop HTLCs aren't sent over this link, nor any other
 		// links associated with this peer.
-		b.htlcSwitch.CloseLink(chanPoint, htlcswitch.CloseBreach)
+		b.htlcSwitch.CloseLink(chanPoint, htlcswitch.CloseBreach, nil)
 		chanInfo := contract.StateSnapshot()
 
 		// TODO(roasbeef): need to handle case of remote broadcast
 		// mid-local initiated state-transition, possible
 		// false-positive?
 
-		// First we generate the witness generation function which will
-		// be used to sweep the output only we can satisfy on the
-		// commitment transaction. This output is just a regular p2wkh
-		// output.
-		localSignDesc := breachInfo.LocalOutputSignDesc
-		localWitness := func(tx *wire.MsgTx, hc *txscript.TxSigHashes,
-			inputIndex int) ([][]byte, error) {
-
-			desc := localSignDesc
-			desc.SigHashes = hc
-			desc.InputIndex = inputIndex
-
-			return lnwallet.CommitSpendNoDelay(
-				b.wallet.Cfg.Signer, &desc, tx)
-		}
-
-		// Next we create the witness generation function that will be
-		// used to sweep the cheating counterparty's output by taking
-		// advantage of the revocation clause within the output's
-		// witness script.
-		remoteSignDesc := breachInfo.RemoteOutputSignDesc
-		remoteWitness := func(tx *wire.MsgTx, hc *txscript.TxSigHashes,
-			inputIndex int) ([][]byte, error) {
-
-			desc := breachInfo.RemoteOutputSignDesc
-			desc.SigHashes = hc
-			desc.InputIndex = inputIndex
-
-			return lnwallet.CommitSpendRevoke(
-				b.wallet.Cfg.Signer, &desc, tx)
-		}
-
 		// Assemble the retribution information that parameterizes the
 		// construction of transactions required to correct the breach.
-		// TODO(roasbeef): populate htlc breaches
-		retInfo := &retributionInfo{
-			commitHash: breachInfo.BreachTransaction.TxHash(),
-			chanPoint:  *chanPoint,
-
-			remoteIdentity: chanInfo.RemoteIdentity,
-			capacity:       chanInfo.Capacity,
-			settledBalance: chanInfo.LocalBalance.ToSatoshis(),
-
-			selfOutput: &breachedOutput{
-				amt:            btcutil.Amount(localSignDesc.Output.Value),
-				outpoint:       breachInfo.LocalOutpoint,
-				signDescriptor: localSignDesc,
-				witnessType:    lnwallet.CommitmentNoDelay,
-				witnessFunc:    localWitness,
-			},
-
-			revokedOutput: &breachedOutput{
-				amt:            btcutil.Amount(remoteSignDesc.Output.Value),
-				outpoint:       breachInfo.RemoteOutpoint,
-				signDescriptor: remoteSignDesc,
-				witnessType:    lnwallet.CommitmentRevoke,
-				witnessFunc:    remoteWitness,
-			},
-
-			htlcOutputs: []*breachedOutput{},
-
-			doneChan: make(chan struct{}),
-		}
+		retInfo := newRetributionInfo(chanPoint, breachInfo, chanInfo)
 
 		// Persist the pending retribution state to disk.
 		if err := b.retributionStore.Add(retInfo); err != nil {
@@ -798,7 +954,15 @@ type breachedOutput struct {
 	witnessType    lnwallet.WitnessType
 	witnessFunc    lnwallet.WitnessGenerator
 
+	// twoStageClaim denotes whether the output is an HTLC output, which
+	// the remote party may spend with their second-level HTLC transaction
+	// before our justice transaction confirms.
 	twoStageClaim bool
+
+	// secondLevelWitnessScript is the witness script of the output of the
+	// remote party's second-level HTLC transaction. It's only set for
+	// outputs with a two stage claim.
+	secondLevelWitnessScript []byte
 }
 
 // retributionInfo encapsulates all the data needed to sweep all the contested
@@ -818,13 +982,115 @@ type retributionInfo struct {
 	capacity       btcutil.Amount
 	settledBalance btcutil.Amount
 
-	selfOutput *breachedOutput
+	// breachedOutputs contains all the outputs we have yet to sweep. These
+	// are initially the outputs of the breach transaction, though an HTLC
+	// output is replaced by the output of the remote party's second-level
+	// transaction should they spend it before our justice transaction
+	// confirms.
+	breachedOutputs []breachedOutput
 
-	revokedOutput *breachedOutput
+	doneChan chan struct{}
+}
 
-	htlcOutputs []*breachedOutput
+// newRetributionInfo constructs a retributionInfo from the BreachRetribution
+// generated by the breached channel, containing all the outputs of the breach
+// transaction that we're entitled to sweep.
+func newRetributionInfo(chanPoint *wire.OutPoint,
+	breachInfo *lnwallet.BreachRetribution,
+	chanInfo *channeldb.ChannelSnapshot) *retributionInfo {
+
+	var breachedOutputs []breachedOutput
+
+	// First, we'll add the output paying to us, which is just a regular
+	// p2wkh output. Either commitment output may have been trimmed as
+	// dust, in which case there's nothing to sweep.
+	localSignDesc := breachInfo.LocalOutputSignDesc
+	if localSignDesc.Output.Value > 0 {
+		breachedOutputs = append(breachedOutputs, breachedOutput{
+			amt:            btcutil.Amount(localSignDesc.Output.Value),
+			outpoint:       breachInfo.LocalOutpoint,
+			signDescriptor: localSignDesc,
+			witnessType:    lnwallet.CommitmentNoDelay,
+		})
+	}
 
-	doneChan chan struct{}
+	// Next, we'll add the cheating counterparty's output, which is swept
+	// by taking advantage of the revocation clause within the output's
+	// witness script.
+	remoteSignDesc := breachInfo.RemoteOutputSignDesc
+	if remoteSignDesc.Output.Value > 0 {
+		breachedOutputs = append(breachedOutputs, breachedOutput{
+			amt:            btcutil.Amount(remoteSignDesc.Output.Value),
+			outpoint:       breachInfo.RemoteOutpoint,
+			signDescriptor: remoteSignDesc,
+			witnessType:    lnwallet.CommitmentRevoke,
+		})
+	}
+
+	// Finally, we'll add each of the HTLC outputs, which are also swept
+	// using the revocation clause of their scripts.
+	for _, htlc := range breachInfo.HtlcRetributions {
+		witnessType := lnwallet.HtlcOfferedRevoke
+		if htlc.IsIncoming {
+			witnessType = lnwallet.HtlcAcceptedRevoke
+		}
+
+		breachedOutputs = append(breachedOutputs, breachedOutput{
+			amt:                      btcutil.Amount(htlc.SignDesc.Output.Value),
+			outpoint:                 htlc.OutPoint,
+			signDescriptor:           htlc.SignDesc,
+			witnessType:              witnessType,
+			twoStageClaim:            true,
+			secondLevelWitnessScript: htlc.SecondLevelWitnessScript,
+		})
+	}
+
+	return &retributionInfo{
+		commitHash: breachInfo.BreachTransaction.TxHash(),
+		chanPoint:  *chanPoint,
+
+		remoteIdentity: chanInfo.RemoteIdentity,
+		capacity:       chanInfo.Capacity,
+		settledBalance: chanInfo.LocalBalance.ToSatoshis(),
+
+		breachedOutputs: breachedOutputs,
+
+		doneChan: make(chan struct{}),
+	}
+}
+
+// justiceTxWeight returns the estimated weight of a justice transaction which
+// sweeps the passed breached outputs into a single p2wkh output.
+func justiceTxWeight(outputs []breachedOutput) (uint64, error) {
+	// The base size of the transaction consists of its version, inputs,
+	// single output and lock time.
+	baseSize := 4 + wire.VarIntSerializeSize(uint64(len(outputs))) +
+		len(outputs)*lnwallet.InputSize + 1 +
+		lnwallet.CommitmentKeyHashOutput + 4
+
+	witnessSize := lnwallet.WitnessHeaderSize
+	for _, bo := range outputs {
+		switch bo.witnessType {
+		case lnwallet.CommitmentNoDelay:
+			witnessSize += lnwallet.P2WKHWitnessSize
+
+		case lnwallet.CommitmentRevoke, lnwallet.HtlcSecondLevelRevoke:
+			witnessSize += lnwallet.ToLocalPenaltyWitnessSize
+
+		// An HTLC we offered is received by the remote party on their
+		// commitment transaction, and vice versa.
+		case lnwallet.HtlcOfferedRevoke:
+			witnessSize += lnwallet.AcceptedHtlcPenaltyWitnessSize
+		case lnwallet.HtlcAcceptedRevoke:
+			witnessSize += lnwallet.OfferedHtlcPenaltyWitnessSize
+
+		default:
+			return 0, fmt.Errorf("unable to estimate witness size "+
+				"of %v", bo.witnessType)
+		}
+	}
+
+	return uint64(blockchain.WitnessScaleFactor*baseSize + witnessSize), nil
 }
 
 // createJusticeTx creates a transaction which exacts "justice" by sweeping ALL
@@ -843,55 +1109,54 @@ func (b *breachArbiter) createJusticeTx(
 		return nil, err
 	}
 
-	r.selfOutput.witnessFunc = r.selfOutput.witnessType.GenWitnessFunc(
-		&b.wallet.Cfg.Signer, &r.selfOutput.signDescriptor)
-
-	r.revokedOutput.witnessFunc = r.revokedOutput.witnessType.GenWitnessFunc(
-		&b.wallet.Cfg.Signer, &r.revokedOutput.signDescriptor)
-
-	for i := range r.htlcOutputs {
-		r.htlcOutputs[i].witnessFunc = r.htlcOutputs[i].witnessType.GenWitnessFunc(
-			&b.wallet.Cfg.Signer, &r.htlcOutputs[i].signDescriptor)
-	}
-
 	// Before creating the actual TxOut, we'll need to calculate the proper
 	// fee to attach to the transaction to ensure a timely confirmation.
-	// TODO(roasbeef): remove hard-coded fee
-	totalAmt := r.selfOutput.amt + r.revokedOutput.amt
-	sweepedAmt := int64(totalAmt - 5000)
+	txWeight, err := justiceTxWeight(r.breachedOutputs)
+	if err != nil {
+		return nil, err
+	}
+	feePerWeight := b.estimator.EstimateFeePerWeight(justiceConfTarget)
+	txFee := btcutil.Amount(feePerWeight * txWeight)
+
+	var totalAmt btcutil.Amount
+	for _, bo := range r.breachedOutputs {
+		totalAmt += bo.amt
+	}
+	if totalAmt <= txFee {
+		return nil, fmt.Errorf("breached outputs worth %v are unable "+
+			"to pay justice tx fee of %v", totalAmt, txFee)
+	}
 
 	// With the fee calculated, we can now create the justice transaction
 	// using the information gathered above.
 	justiceTx := wire.NewMsgTx(2)
 	justiceTx.AddTxOut(&wire.TxOut{
 		PkScript: pkScriptOfJustice,
-		Value:    sweepedAmt,
-	})
-	justiceTx.AddTxIn(&wire.TxIn{
-		PreviousOutPoint: r.selfOutput.outpoint,
-	})
-	justiceTx.AddTxIn(&wire.TxIn{
-		PreviousOutPoint: r.revokedOutput.outpoint,
+		Value:    int64(totalAmt - txFee),
 	})
+	for _, bo := range r.breachedOutputs {
+		justiceTx.AddTxIn(&wire.TxIn{
+			PreviousOutPoint: bo.outpoint,
+		})
+	}
 
 	hashCache := txscript.NewTxSigHashes(justiceTx)
 
 	// Finally, using the witness generation functions attached to the
 	// retribution information, we'll populate the inputs with fully valid
-	// witnesses for both commitment outputs, and all the pending HTLCs at
-	// this state in the channel's history.
-	// TODO(roasbeef): handle the 2-layer HTLCs
-	localWitness, err := r.selfOutput.witnessFunc(justiceTx, hashCache, 0)
-	if err != nil {
-		return nil, err
-	}
-	justiceTx.TxIn[0].Witness = localWitness
+	// witnesses for each of the breached outputs.
+	for i := range r.breachedOutputs {
+		bo := &r.breachedOutputs[i]
+		bo.witnessFunc = bo.witnessType.GenWitnessFunc(
+			&b.wallet.Cfg.Signer, &bo.signDescriptor,
+		)
 
-	remoteWitness, err := r.revokedOutput.witnessFunc(justiceTx, hashCache, 1)
-	if err != nil {
-		return nil, err
+		witness, err := bo.witnessFunc(justiceTx, hashCache, i)
+		if err != nil {
+			return nil, err
+		}
+		justiceTx.TxIn[i].Witness = witness
 	}
-	justiceTx.TxIn[1].Witness = remoteWitness
 
 	return justiceTx, nil
 }
@@ -914,9 +1179,20 @@ func (b *breachArbiter) craftCommitSweepTx(
 		return nil, err
 	}
 
-	// TODO(roasbeef): use proper fees
+	// The sweep transaction has a single p2wkh input and output, so we'll
+	// estimate its fee using the same weight as a justice transaction
+	// sweeping only our own commitment output.
+	txWeight, err := justiceTxWeight([]breachedOutput{
+		{witnessType: lnwallet.CommitmentNoDelay},
+	})
+	if err != nil {
+		return nil, err
+	}
+	feePerWeight := b.estimator.EstimateFeePerWeight(justiceConfTarget)
+	txFee := int64(feePerWeight * txWeight)
+
 	outputAmt := closeInfo.SelfOutputSignDesc.Output.Value
-	sweepAmt := int64(outputAmt - 5000)
+	sweepAmt := outputAmt - txFee
 
 	if sweepAmt <= 0 {
 		// TODO(roasbeef): add output to special pool, can be swept
@@ -1027,7 +1303,19 @@ func (rs *retributionStore) Add(ret *retributionInfo) error {
 			return err
 		}
 
-		return retBucket.Put(outBuf.Bytes(), retBuf.Bytes())
+		err = retBucket.Put(outBuf.Bytes(), retBuf.Bytes())
+		if err != nil {
+			return err
+		}
+
+		// Now that the retribution has been stored in the current
+		// layout, any legacy record of it is superseded.
+		legacyBucket := tx.Bucket(legacyRetributionBucket)
+		if legacyBucket == nil {
+			return nil
+		}
+
+		return legacyBucket.Delete(outBuf.Bytes())
 	})
 }
 
@@ -1035,12 +1323,13 @@ func (rs *retributionStore) Add(ret *retributionInfo) error {
 func (rs *retributionStore) Remove(key *wire.OutPoint) error {
 	return rs.db.Update(func(tx *bolt.Tx) error {
 		retBucket := tx.Bucket(retributionBucket)
+		legacyBucket := tx.Bucket(legacyRetributionBucket)
 
-		// We return an error if the bucket is not already created,
+		// We return an error if neither bucket is already created,
 		// since normal operation of the breach arbiter should never try
 		// to remove a finalized retribution state that is not already
 		// stored in the db.
-		if retBucket == nil {
+		if retBucket == nil && legacyBucket == nil {
 			return errors.New("unable to remove retribution " +
 				"because the db bucket doesn't exist.")
 		}
@@ -1050,7 +1339,16 @@ func (rs *retributionStore) Remove(key *wire.OutPoint) error {
 			return err
 		}
 
-		return retBucket.Delete(outBuf.Bytes())
+		for _, bucket := range []*bolt.Bucket{retBucket, legacyBucket} {
+			if bucket == nil {
+				continue
+			}
+			if err := bucket.Delete(outBuf.Bytes()); err != nil {
+				return err
+			}
+		}
+
+		return nil
 	})
 }
 
@@ -1058,26 +1356,44 @@ func (rs *retributionStore) Remove(key *wire.OutPoint) error {
 // callback function on each retribution.
 func (rs *retributionStore) ForAll(cb func(*retributionInfo) error) error {
 	return rs.db.View(func(tx *bolt.Tx) error {
-		// If the bucket does not exist, then there are no pending
-		// retributions.
-		retBucket := tx.Bucket(retributionBucket)
-		if retBucket == nil {
-			return nil
+		err := forEachRetribution(
+			tx, retributionBucket, (*retributionInfo).Decode, cb,
+		)
+		if err != nil {
+			return err
 		}
 
-		// Otherwise, we fetch each serialized retribution info,
-		// deserialize it, and execute the passed in callback function
-		// on it.
-		return retBucket.ForEach(func(outBytes, retBytes []byte) error {
-			ret := &retributionInfo{}
-			if err := ret.Decode(
-				bytes.NewBuffer(retBytes),
-			); err != nil {
-				return err
-			}
+		// Retributions persisted in the legacy layout are converted as
+		// they're decoded.
+		return forEachRetribution(
+			tx, legacyRetributionBucket,
+			(*retributionInfo).decodeLegacy, cb,
+		)
+	})
+}
 
-			return cb(ret)
-		})
+// forEachRetribution decodes each retribution stored within the given bucket
+// using the passed decoder, and executes the passed callback function on it.
+func forEachRetribution(tx *bolt.Tx, bucketKey []byte,
+	decode func(*retributionInfo, io.Reader) error,
+	cb func(*retributionInfo) error) error {
+
+	// If the bucket does not exist, then there are no pending retributions
+	// stored within it.
+	retBucket := tx.Bucket(bucketKey)
+	if retBucket == nil {
+		return nil
+	}
+
+	// Otherwise, we fetch each serialized retribution info, deserialize
+	// it, and execute the passed in callback function on it.
+	return retBucket.ForEach(func(outBytes, retBytes []byte) error {
+		ret := &retributionInfo{}
+		if err := decode(ret, bytes.NewBuffer(retBytes)); err != nil {
+			return err
+		}
+
+		return cb(ret)
 	})
 }
 
@@ -1108,30 +1424,97 @@ func (ret *retributionInfo) Encode(w io.Writer) error {
 		return err
 	}
 
-	if err := ret.selfOutput.Encode(w); err != nil {
+	numOutputs := len(ret.breachedOutputs)
+	if err := wire.WriteVarInt(w, 0, uint64(numOutputs)); err != nil {
+		return err
+	}
+
+	for i := 0; i < numOutputs; i++ {
+		if err := ret.breachedOutputs[i].Encode(w); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Dencode deserializes a retribution from the passed byte stream.
+func (ret *retributionInfo) Decode(r io.Reader) error {
+	if err := ret.decodeHeader(r); err != nil {
+		return err
+	}
+
+	numOutputsU64, err := wire.ReadVarInt(r, 0)
+	if err != nil {
+		return err
+	}
+	numOutputs := int(numOutputsU64)
+
+	ret.breachedOutputs = make([]breachedOutput, numOutputs)
+	for i := 0; i < numOutputs; i++ {
+		if err := ret.breachedOutputs[i].Decode(r); err != nil {
+			return err
+		}
+	}
+
+	return checkRetributionEnd(r)
+}
+
+// decodeLegacy deserializes a retribution stored in the legacy layout from
+// the passed byte stream, converting its outputs to a single list of breached
+// outputs. Commitment outputs which were trimmed as dust are dropped, as
+// there's nothing to sweep.
+func (ret *retributionInfo) decodeLegacy(r io.Reader) error {
+	if err := ret.decodeHeader(r); err != nil {
 		return err
 	}
 
-	if err := ret.revokedOutput.Encode(w); err != nil {
+	var selfOutput, revokedOutput breachedOutput
+	if err := selfOutput.decodeLegacy(r); err != nil {
+		return err
+	}
+	if err := revokedOutput.decodeLegacy(r); err != nil {
 		return err
 	}
 
-	numHtlcOutputs := len(ret.htlcOutputs)
-	if err := wire.WriteVarInt(w, 0, uint64(numHtlcOutputs)); err != nil {
+	numHtlcOutputsU64, err := wire.ReadVarInt(r, 0)
+	if err != nil {
 		return err
 	}
+	numHtlcOutputs := int(numHtlcOutputsU64)
 
+	ret.breachedOutputs = make([]breachedOutput, 0, 2+numHtlcOutputs)
+	for _, output := range []breachedOutput{selfOutput, revokedOutput} {
+		if output.amt > 0 {
+			ret.breachedOutputs = append(ret.breachedOutputs, output)
+		}
+	}
 	for i := 0; i < numHtlcOutputs; i++ {
-		if err := ret.htlcOutputs[i].Encode(w); err != nil {
+		var htlcOutput breachedOutput
+		if err := htlcOutput.decodeLegacy(r); err != nil {
 			return err
 		}
+		ret.breachedOutputs = append(ret.breachedOutputs, htlcOutput)
+	}
+
+	return checkRetributionEnd(r)
+}
+
+// checkRetributionEnd ensures that a decoded retribution accounts for all of
+// the bytes of its record, as otherwise it was written in another layout.
+func checkRetributionEnd(r io.Reader) error {
+	var scratch [1]byte
+	if _, err := io.ReadFull(r, scratch[:]); err != io.EOF {
+		return errors.New("retribution has unexpected trailing bytes")
 	}
 
 	return nil
 }
 
-// Dencode deserializes a retribution from the passed byte stream.
-func (ret *retributionInfo) Decode(r io.Reader) error {
+// decodeHeader deserializes the fields of a retribution preceding its outputs
+// from the passed byte stream, which are shared by the current and legacy
+// layouts.
+func (ret *retributionInfo) decodeHeader(r io.Reader) error {
 	var scratch [33]byte
 
 	if _, err := io.ReadFull(r, scratch[:32]); err != nil {
@@ -1167,30 +1550,6 @@ func (ret *retributionInfo) Decode(r io.Reader) error {
 	ret.settledBalance = btcutil.Amount(
 		binary.BigEndian.Uint64(scratch[:8]))
 
-	ret.selfOutput = &breachedOutput{}
-	if err := ret.selfOutput.Decode(r); err != nil {
-		return err
-	}
-
-	ret.revokedOutput = &breachedOutput{}
-	if err := ret.revokedOutput.Decode(r); err != nil {
-		return err
-	}
-
-	numHtlcOutputsU64, err := wire.ReadVarInt(r, 0)
-	if err != nil {
-		return err
-	}
-	numHtlcOutputs := int(numHtlcOutputsU64)
-
-	ret.htlcOutputs = make([]*breachedOutput, numHtlcOutputs)
-	for i := 0; i < numHtlcOutputs; i++ {
-		ret.htlcOutputs[i] = &breachedOutput{}
-		if err := ret.htlcOutputs[i].Decode(r); err != nil {
-			return err
-		}
-	}
-
 	return nil
 }
 
@@ -1226,11 +1585,41 @@ func (bo *breachedOutput) Encode(w io.Writer) error {
 		return err
 	}
 
+	// The second-level witness script is only present for outputs with a
+	// two stage claim.
+	if bo.twoStageClaim {
+		err := wire.WriteVarBytes(w, 0, bo.secondLevelWitnessScript)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
 // Decode deserializes a breachedOutput from the passed byte stream.
 func (bo *breachedOutput) Decode(r io.Reader) error {
+	if err := bo.decodeLegacy(r); err != nil {
+		return err
+	}
+
+	// The second-level witness script is only present for outputs with a
+	// two stage claim.
+	if bo.twoStageClaim {
+		script, err := wire.ReadVarBytes(r, 0, 500, "secondLevelScript")
+		if err != nil {
+			return err
+		}
+		bo.secondLevelWitnessScript = script
+	}
+
+	return nil
+}
+
+// decodeLegacy deserializes a breachedOutput stored in the legacy layout,
+// which lacks the trailing second-level witness script, from the passed byte
+// stream.
+func (bo *breachedOutput) decodeLegacy(r io.Reader) error {
 	var scratch [8]byte
 
 	if _, err := io.ReadFull(r, scratch[:8]); err != nil {
@@ -4,14 +4,19 @@ package main
 
 import (
 	"bytes"
+	"encoding/binary"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"reflect"
 	"sync"
 	"testing"
 
+	"github.com/boltdb/bolt"
 	"github.com/btcsuite/btclog"
+	"github.com/davecgh/go-spew/spew"
+	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/roasbeef/btcd/btcec"
@@ -177,6 +182,11 @@ var (
 			outpoint:      breachOutPoints[0],
 			witnessType:   lnwallet.CommitmentNoDelay,
 			twoStageClaim: true,
+			secondLevelWitnessScript: []byte{
+				0x00, 0x14, 0xee, 0x91, 0x41, 0x7e, 0x85, 0x6c,
+				0xde, 0x10, 0xa2, 0x91, 0x1e, 0xdc, 0xbd, 0xbd,
+				0x69, 0xe2, 0xef, 0xb5, 0x71, 0x48,
+			},
 		},
 
 		{
@@ -206,9 +216,6 @@ var (
 			chanPoint:      breachOutPoints[0],
 			capacity:       btcutil.Amount(1e7),
 			settledBalance: btcutil.Amount(1e7),
-			selfOutput:     &breachedOutputs[0],
-			revokedOutput:  &breachedOutputs[1],
-			htlcOutputs:    []*breachedOutput{},
 		},
 		{
 			commitHash: [chainhash.HashSize]byte{
@@ -220,12 +227,6 @@ var (
 			chanPoint:      breachOutPoints[1],
 			capacity:       btcutil.Amount(1e7),
 			settledBalance: btcutil.Amount(1e7),
-			selfOutput:     &breachedOutputs[0],
-			revokedOutput:  &breachedOutputs[1],
-			htlcOutputs: []*breachedOutput{
-				&breachedOutputs[1],
-				&breachedOutputs[2],
-			},
 		},
 	}
 )
@@ -236,6 +237,15 @@ func init() {
 		panic(err)
 	}
 
+	// With the breached outputs initialized, we can now populate the
+	// outputs of each retribution.
+	retributions[0].breachedOutputs = []breachedOutput{
+		breachedOutputs[0], breachedOutputs[1],
+	}
+	retributions[1].breachedOutputs = []breachedOutput{
+		breachedOutputs[0], breachedOutputs[1], breachedOutputs[2],
+	}
+
 	// Populate a retribution map to for convenience, to allow lookups by
 	// channel point.
 	for i := range retributions {
@@ -382,25 +392,320 @@ func TestRetributionSerialization(t *testing.T) {
 	}
 }
 
+// encodeLegacyRetribution serializes the passed retribution in the legacy
+// layout, with the passed outputs as our own output and the revoked output,
+// and no HTLC outputs. The encoding of outputs without a two stage claim is
+// unchanged from the legacy layout.
+func encodeLegacyRetribution(w io.Writer, ret *retributionInfo,
+	selfOutput, revokedOutput *breachedOutput) error {
+
+	var scratch [8]byte
+
+	if _, err := w.Write(ret.commitHash[:]); err != nil {
+		return err
+	}
+	if err := writeOutpoint(w, &ret.chanPoint); err != nil {
+		return err
+	}
+	if _, err := w.Write(
+		ret.remoteIdentity.SerializeCompressed()); err != nil {
+		return err
+	}
+
+	binary.BigEndian.PutUint64(scratch[:], uint64(ret.capacity))
+	if _, err := w.Write(scratch[:]); err != nil {
+		return err
+	}
+	binary.BigEndian.PutUint64(scratch[:], uint64(ret.settledBalance))
+	if _, err := w.Write(scratch[:]); err != nil {
+		return err
+	}
+
+	if err := selfOutput.Encode(w); err != nil {
+		return err
+	}
+	if err := revokedOutput.Encode(w); err != nil {
+		return err
+	}
+
+	return wire.WriteVarInt(w, 0, 0)
+}
+
+// TestLegacyRetributionDecode asserts that retributions persisted in the
+// legacy layout are converted when read from the retribution store, and are
+// moved to the current layout once added again.
+func TestLegacyRetributionDecode(t *testing.T) {
+	selfOutput := breachedOutputs[0]
+	selfOutput.twoStageClaim = false
+	selfOutput.secondLevelWitnessScript = nil
+	revokedOutput := breachedOutputs[1]
+
+	ret := retributions[0]
+	ret.breachedOutputs = []breachedOutput{selfOutput, revokedOutput}
+
+	var legacyBuf bytes.Buffer
+	err := encodeLegacyRetribution(
+		&legacyBuf, &ret, &selfOutput, &revokedOutput,
+	)
+	if err != nil {
+		t.Fatalf("unable to serialize legacy retribution: %v", err)
+	}
+	legacyBytes := legacyBuf.Bytes()
+
+	// The legacy record should be decoded with both of its outputs, while
+	// it shouldn't be mistaken for a record in the current layout.
+	desRet := &retributionInfo{}
+	if err := desRet.decodeLegacy(bytes.NewReader(legacyBytes)); err != nil {
+		t.Fatalf("unable to deserialize legacy retribution: %v", err)
+	}
+	if !reflect.DeepEqual(&ret, desRet) {
+		t.Fatalf("expected retribution %v, got %v", spew.Sdump(ret),
+			spew.Sdump(desRet))
+	}
+	err = (&retributionInfo{}).Decode(bytes.NewReader(legacyBytes))
+	if err == nil {
+		t.Fatalf("legacy retribution decoded in the current layout")
+	}
+
+	// A record with trailing bytes should be rejected.
+	var retBuf bytes.Buffer
+	if err := ret.Encode(&retBuf); err != nil {
+		t.Fatalf("unable to serialize retribution: %v", err)
+	}
+	retBuf.WriteByte(0)
+	if err := (&retributionInfo{}).Decode(&retBuf); err == nil {
+		t.Fatalf("retribution with trailing bytes decoded")
+	}
+
+	// A legacy commitment output trimmed as dust should be dropped.
+	dustOutput := selfOutput
+	dustOutput.amt = 0
+	legacyBuf.Reset()
+	err = encodeLegacyRetribution(
+		&legacyBuf, &ret, &dustOutput, &revokedOutput,
+	)
+	if err != nil {
+		t.Fatalf("unable to serialize legacy retribution: %v", err)
+	}
+	desRet = &retributionInfo{}
+	if err := desRet.decodeLegacy(&legacyBuf); err != nil {
+		t.Fatalf("unable to deserialize legacy retribution: %v", err)
+	}
+	expectedOutputs := []breachedOutput{revokedOutput}
+	if !reflect.DeepEqual(desRet.breachedOutputs, expectedOutputs) {
+		t.Fatalf("expected outputs %v, got %v",
+			spew.Sdump(expectedOutputs),
+			spew.Sdump(desRet.breachedOutputs))
+	}
+
+	// Finally, we'll store the legacy record as it was persisted before,
+	// and ensure that the retribution store returns it converted.
+	tempDirName, err := ioutil.TempDir("", "channeldb")
+	if err != nil {
+		t.Fatalf("unable to initialize temp "+
+			"directory for channeldb: %v", err)
+	}
+	defer os.RemoveAll(tempDirName)
+
+	db, err := channeldb.Open(tempDirName)
+	if err != nil {
+		t.Fatalf("unable to open channeldb: %v", err)
+	}
+	defer db.Close()
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		legacyBucket, err := tx.CreateBucketIfNotExists(
+			legacyRetributionBucket,
+		)
+		if err != nil {
+			return err
+		}
+
+		var outBuf bytes.Buffer
+		if err := writeOutpoint(&outBuf, &ret.chanPoint); err != nil {
+			return err
+		}
+
+		return legacyBucket.Put(outBuf.Bytes(), legacyBytes)
+	})
+	if err != nil {
+		t.Fatalf("unable to store legacy retribution: %v", err)
+	}
+
+	rs := newRetributionStore(db)
+	var stored []*retributionInfo
+	err = rs.ForAll(func(r *retributionInfo) error {
+		stored = append(stored, r)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unable to list retributions: %v", err)
+	}
+	if !reflect.DeepEqual(stored, []*retributionInfo{&ret}) {
+		t.Fatalf("expected retribution %v, got %v", spew.Sdump(ret),
+			spew.Sdump(stored))
+	}
+
+	// Once added again, the retribution should be stored only once, in
+	// the current layout, and be removable.
+	if err := rs.Add(&ret); err != nil {
+		t.Fatalf("unable to add retribution: %v", err)
+	}
+	if count := countRetributions(t, rs); count != 1 {
+		t.Fatalf("expected 1 retribution, found %v", count)
+	}
+	if err := rs.Remove(&ret.chanPoint); err != nil {
+		t.Fatalf("unable to remove retribution: %v", err)
+	}
+	if count := countRetributions(t, rs); count != 0 {
+		t.Fatalf("expected 0 retributions, found %v", count)
+	}
+}
+
+// TestJusticeTxWeight asserts that the estimated weight of a justice
+// transaction accounts for the witness of each breached output it sweeps.
+func TestJusticeTxWeight(t *testing.T) {
+	t.Parallel()
+
+	// A justice transaction sweeping a single p2wkh output shares the
+	// size of a regular p2wkh spend.
+	weight, err := justiceTxWeight([]breachedOutput{
+		{witnessType: lnwallet.CommitmentNoDelay},
+	})
+	if err != nil {
+		t.Fatalf("unable to estimate weight: %v", err)
+	}
+	if weight != 439 {
+		t.Fatalf("expected weight of 439, got %v", weight)
+	}
+
+	// Each additional input adds its own size along with its witness.
+	weight, err = justiceTxWeight([]breachedOutput{
+		{witnessType: lnwallet.CommitmentNoDelay},
+		{witnessType: lnwallet.CommitmentRevoke},
+		{witnessType: lnwallet.HtlcOfferedRevoke},
+		{witnessType: lnwallet.HtlcAcceptedRevoke},
+	})
+	if err != nil {
+		t.Fatalf("unable to estimate weight: %v", err)
+	}
+	expectedWeight := uint64(439 + 3*4*lnwallet.InputSize +
+		lnwallet.ToLocalPenaltyWitnessSize +
+		lnwallet.AcceptedHtlcPenaltyWitnessSize +
+		lnwallet.OfferedHtlcPenaltyWitnessSize)
+	if weight != expectedWeight {
+		t.Fatalf("expected weight of %v, got %v", expectedWeight,
+			weight)
+	}
+
+	// Outputs we don't know how to sweep should be rejected.
+	_, err = justiceTxWeight([]breachedOutput{
+		{witnessType: lnwallet.HtlcOfferedTimeoutSecondLevel},
+	})
+	if err == nil {
+		t.Fatalf("expected estimation of unknown witness to fail")
+	}
+}
+
+// TestSplitBreachedOutputs asserts that a breached HTLC output spent by the
+// remote party's second-level transaction is replaced by the output of that
+// transaction, while any other spent output is no longer swept.
+func TestSplitBreachedOutputs(t *testing.T) {
+	t.Parallel()
+
+	secondLevelScript := breachedOutputs[0].secondLevelWitnessScript
+	builder := txscript.NewScriptBuilder()
+	builder.AddOp(txscript.OP_0)
+	builder.AddData(chainhash.HashB(secondLevelScript))
+	secondLevelPkScript, err := builder.Script()
+	if err != nil {
+		t.Fatalf("unable to create pkscript: %v", err)
+	}
+
+	// The first breached output is an HTLC, which the remote party spends
+	// with their second-level transaction.
+	secondLevelTx := wire.NewMsgTx(2)
+	secondLevelTx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: breachedOutputs[0].outpoint,
+	})
+	secondLevelTx.AddTxOut(&wire.TxOut{
+		Value:    9e6,
+		PkScript: secondLevelPkScript,
+	})
+	secondLevelHash := secondLevelTx.TxHash()
+
+	outputs := []breachedOutput{
+		breachedOutputs[0], breachedOutputs[1], breachedOutputs[2],
+	}
+	remaining := splitBreachedOutputs(outputs, 0, &chainntnfs.SpendDetail{
+		SpentOutPoint: &breachedOutputs[0].outpoint,
+		SpenderTxHash: &secondLevelHash,
+		SpendingTx:    secondLevelTx,
+	})
+	if len(remaining) != 3 {
+		t.Fatalf("expected 3 outputs, got %v", len(remaining))
+	}
+	if !reflect.DeepEqual(remaining[:2], outputs[1:]) {
+		t.Fatalf("unspent outputs were modified")
+	}
+
+	secondLevelOutput := remaining[2]
+	expectedOutpoint := wire.OutPoint{Hash: secondLevelHash, Index: 0}
+	if secondLevelOutput.outpoint != expectedOutpoint {
+		t.Fatalf("expected outpoint %v, got %v", expectedOutpoint,
+			secondLevelOutput.outpoint)
+	}
+	if secondLevelOutput.amt != 9e6 {
+		t.Fatalf("expected amount of %v, got %v", btcutil.Amount(9e6),
+			secondLevelOutput.amt)
+	}
+	if secondLevelOutput.witnessType != lnwallet.HtlcSecondLevelRevoke {
+		t.Fatalf("expected witness type %v, got %v",
+			lnwallet.HtlcSecondLevelRevoke,
+			secondLevelOutput.witnessType)
+	}
+	if secondLevelOutput.twoStageClaim {
+		t.Fatalf("second-level output shouldn't have a two stage claim")
+	}
+	signDesc := secondLevelOutput.signDescriptor
+	if !bytes.Equal(signDesc.WitnessScript, secondLevelScript) {
+		t.Fatalf("sign descriptor has wrong witness script")
+	}
+	if signDesc.Output != secondLevelTx.TxOut[0] {
+		t.Fatalf("sign descriptor has wrong output")
+	}
+
+	// Should the remaining HTLC output be spent by a transaction which
+	// isn't a second-level transaction, then it's no longer swept.
+	sweepTx := secondLevelTx.Copy()
+	sweepTx.TxOut[0].PkScript = breachSignDescs[0].Output.PkScript
+	sweepHash := sweepTx.TxHash()
+
+	remaining = splitBreachedOutputs(outputs, 0, &chainntnfs.SpendDetail{
+		SpentOutPoint: &breachedOutputs[0].outpoint,
+		SpenderTxHash: &sweepHash,
+		SpendingTx:    sweepTx,
+	})
+	if !reflect.DeepEqual(remaining, outputs[1:]) {
+		t.Fatalf("expected spent output to be dropped")
+	}
+}
+
 // copyRetInfo creates a complete copy of the given retributionInfo.
 func copyRetInfo(retInfo *retributionInfo) *retributionInfo {
-	nHtlcs := len(retInfo.htlcOutputs)
+	nOutputs := len(retInfo.breachedOutputs)
 
 	ret := &retributionInfo{
-		commitHash:     retInfo.commitHash,
-		chanPoint:      retInfo.chanPoint,
-		remoteIdentity: retInfo.remoteIdentity,
-		capacity:       retInfo.capacity,
-		settledBalance: retInfo.settledBalance,
-		selfOutput:     retInfo.selfOutput,
-		revokedOutput:  retInfo.revokedOutput,
-		htlcOutputs:    make([]*breachedOutput, nHtlcs),
-		doneChan:       retInfo.doneChan,
+		commitHash:      retInfo.commitHash,
+		chanPoint:       retInfo.chanPoint,
+		remoteIdentity:  retInfo.remoteIdentity,
+		capacity:        retInfo.capacity,
+		settledBalance:  retInfo.settledBalance,
+		breachedOutputs: make([]breachedOutput, nOutputs),
+		doneChan:        retInfo.doneChan,
 	}
 
-	for i, htlco := range retInfo.htlcOutputs {
-		ret.htlcOutputs[i] = htlco
-	}
+	copy(ret.breachedOutputs, retInfo.breachedOutputs)
 
 	return ret
 }
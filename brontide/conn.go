@@ -91,6 +91,14 @@ func (c *Conn) ReadNextMessage() ([]byte, error) {
 	return c.noise.ReadMessage(c.conn)
 }
 
+// ReadNextMessageBuf reads the next _full_ message like ReadNextMessage, but
+// the message is read into a buffer drawn from a shared pool rather than
+// being allocated. The returned buffer MUST be handed back through
+// ReleaseBuffer once the caller is done with the message.
+func (c *Conn) ReadNextMessageBuf() ([]byte, *Buffer, error) {
+	return c.noise.ReadMessageBuf(c.conn)
+}
+
 // Read reads data from the connection.  Read can be made to time out and
 // return a Error with Timeout() == true after a fixed time limit; see
 // SetDeadline and SetReadDeadline.
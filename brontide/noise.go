@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"sync"
 
 	"golang.org/x/crypto/chacha20poly1305"
 	"golang.org/x/crypto/hkdf"
@@ -42,7 +43,32 @@ var (
 		"the max allowed message length of (2^16)-1")
 )
 
-// TODO(roasbeef): free buffer pool?
+// maxCipherMessageSize is the size of the largest possible message on the
+// wire: the encrypted+MAC'd length prefix followed by the encrypted+MAC'd
+// packet itself.
+const maxCipherMessageSize = lengthHeaderSize + macSize + math.MaxUint16 +
+	macSize
+
+// Buffer is a buffer large enough to hold the largest possible message on the
+// wire.
+type Buffer [maxCipherMessageSize]byte
+
+// bufferPool is a pool of buffers used to encrypt outgoing messages and to
+// read and decrypt incoming messages in place. Pooling the buffers allows
+// messages to be framed without incurring any allocations once the pool is
+// warm.
+var bufferPool = &sync.Pool{
+	New: func() interface{} { return new(Buffer) },
+}
+
+// ReleaseBuffer returns a buffer handed out by ReadMessageBuf to the shared
+// pool. The buffer, and any message read into it, must no longer be accessed
+// once this method returns.
+func ReleaseBuffer(buf *Buffer) {
+	if buf != nil {
+		bufferPool.Put(buf)
+	}
+}
 
 // ecdh performs an ECDH operation between pub and priv. The returned value is
 // the sha256 of the compressed shared point.
@@ -681,16 +707,15 @@ func (b *Machine) WriteMessage(w io.Writer, p []byte) error {
 	var pktLen [2]byte
 	binary.BigEndian.PutUint16(pktLen[:], fullLength)
 
-	// First, write out the encrypted+MAC'd length prefix for the packet.
-	cipherLen := b.sendCipher.Encrypt(nil, nil, pktLen[:])
-	if _, err := w.Write(cipherLen); err != nil {
-		return err
-	}
+	// We'll encrypt the length prefix and the packet itself into a pooled
+	// buffer, so the whole message can be written out without any
+	// allocations. We only write out a single packet, as any fragmentation
+	// should have taken place at a higher level.
+	buf := bufferPool.Get().(*Buffer)
+	defer bufferPool.Put(buf)
 
-	// Finally, write out the encrypted packet itself. We only write out a
-	// single packet, as any fragmentation should have taken place at a
-	// higher level.
-	cipherText := b.sendCipher.Encrypt(nil, nil, p)
+	cipherText := b.sendCipher.Encrypt(nil, buf[:0], pktLen[:])
+	cipherText = b.sendCipher.Encrypt(nil, cipherText, p)
 	_, err := w.Write(cipherText)
 	return err
 }
@@ -698,25 +723,51 @@ func (b *Machine) WriteMessage(w io.Writer, p []byte) error {
 // ReadMessage attempts to read the next message from the passed io.Reader. In
 // the case of an authentication error, a non-nil error is returned.
 func (b *Machine) ReadMessage(r io.Reader) ([]byte, error) {
+	msg, buf, err := b.ReadMessageBuf(r)
+	if err != nil {
+		return nil, err
+	}
+	defer ReleaseBuffer(buf)
+
+	plainText := make([]byte, len(msg))
+	copy(plainText, msg)
+
+	return plainText, nil
+}
+
+// ReadMessageBuf attempts to read the next message from the passed io.Reader
+// like ReadMessage, but reads and decrypts the message in place within a
+// buffer drawn from a shared pool rather than allocating. The returned buffer
+// MUST be handed back through ReleaseBuffer once the caller is done with the
+// message.
+func (b *Machine) ReadMessageBuf(r io.Reader) ([]byte, *Buffer, error) {
 	var cipherLen [lengthHeaderSize + macSize]byte
 	if _, err := io.ReadFull(r, cipherLen[:]); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// Attempt to decrypt+auth the packet length present in the stream.
-	pktLenBytes, err := b.recvCipher.Decrypt(nil, nil, cipherLen[:])
+	var pktLenBytes [lengthHeaderSize]byte
+	_, err := b.recvCipher.Decrypt(nil, pktLenBytes[:0], cipherLen[:])
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// Next, using the length read from the packet header, read the
-	// encrypted packet itself.
-	var cipherText [math.MaxUint16 + macSize]byte
-	pktLen := uint32(binary.BigEndian.Uint16(pktLenBytes)) + macSize
-	if _, err := io.ReadFull(r, cipherText[:pktLen]); err != nil {
-		return nil, err
+	// encrypted packet itself. We only draw a buffer from the pool once
+	// the length is known, so idle connections don't hold onto one.
+	buf := bufferPool.Get().(*Buffer)
+	pktLen := uint32(binary.BigEndian.Uint16(pktLenBytes[:])) + macSize
+	if _, err := io.ReadFull(r, buf[:pktLen]); err != nil {
+		ReleaseBuffer(buf)
+		return nil, nil, err
+	}
+
+	plainText, err := b.recvCipher.Decrypt(nil, buf[:0], buf[:pktLen])
+	if err != nil {
+		ReleaseBuffer(buf)
+		return nil, nil, err
 	}
 
-	// TODO(roasbeef): modify to let pass in slice
-	return b.recvCipher.Decrypt(nil, nil, cipherText[:pktLen])
+	return plainText, buf, nil
 }
@@ -206,6 +206,41 @@ func TestWriteMessageChunking(t *testing.T) {
 	}
 }
 
+// TestReadMessageBuf checks that messages read into pooled buffers match the
+// messages written, including when the buffers are reused between reads.
+func TestReadMessageBuf(t *testing.T) {
+	t.Parallel()
+
+	initiator := Machine{initiator: true}
+	initiator.split()
+	responder := Machine{}
+	responder.split()
+
+	msgs := [][]byte{
+		bytes.Repeat([]byte{0x01}, math.MaxUint16),
+		[]byte("hello"),
+		{},
+	}
+
+	var buf bytes.Buffer
+	for _, msg := range msgs {
+		if err := initiator.WriteMessage(&buf, msg); err != nil {
+			t.Fatalf("unable to write message: %v", err)
+		}
+	}
+
+	for i, msg := range msgs {
+		readMsg, readBuf, err := responder.ReadMessageBuf(&buf)
+		if err != nil {
+			t.Fatalf("unable to read message %d: %v", i, err)
+		}
+		if !bytes.Equal(readMsg, msg) {
+			t.Fatalf("message %d doesn't match", i)
+		}
+		ReleaseBuffer(readBuf)
+	}
+}
+
 // TestBolt0008TestVectors ensures that our implementation of brontide exactly
 // matches the test vectors within the specification.
 func TestBolt0008TestVectors(t *testing.T) {
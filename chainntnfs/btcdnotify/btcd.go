@@ -37,6 +37,13 @@ type chainUpdate struct {
 	blockHeight int32
 }
 
+// connectedBlock pairs an update to the current main chain with the block it
+// connected, once the block has been fetched.
+type connectedBlock struct {
+	update *chainUpdate
+	block  *wire.MsgBlock
+}
+
 // txUpdate encapsulates a transaction related notification sent from btcd to
 // the registered RPC client. This struct is used as an element within an
 // unbounded queue in order to avoid blocking the main rpc dispatch rule.
@@ -58,8 +65,15 @@ type BtcdNotifier struct {
 	started int32 // To be used atomically.
 	stopped int32 // To be used atomically.
 
+	// chainConn is the websockets connection to btcd used to register
+	// for, and receive, all notifications.
 	chainConn *rpcclient.Client
 
+	// queries is a pool of connections to btcd used for all queries
+	// which don't involve notifications. This ensures a slow query can't
+	// delay the delivery of notifications over chainConn.
+	queries *queryPool
+
 	notificationCancels  chan interface{}
 	notificationRegistry chan interface{}
 
@@ -76,6 +90,10 @@ type BtcdNotifier struct {
 	chainUpdateSignal chan struct{}
 	chainUpdateMtx    sync.Mutex
 
+	// connectedBlocks carries each connected block, once fetched, from
+	// the blockFetcher to the notificationDispatcher.
+	connectedBlocks chan *connectedBlock
+
 	txUpdates      []*txUpdate
 	txUpdateSignal chan struct{}
 	txUpdateMtx    sync.Mutex
@@ -105,6 +123,7 @@ func New(config *rpcclient.ConnConfig) (*BtcdNotifier, error) {
 		disconnectedBlockHashes: make(chan *blockNtfn, 20),
 
 		chainUpdateSignal: make(chan struct{}),
+		connectedBlocks:   make(chan *connectedBlock),
 		txUpdateSignal:    make(chan struct{}),
 
 		quit: make(chan struct{}),
@@ -126,6 +145,17 @@ func New(config *rpcclient.ConnConfig) (*BtcdNotifier, error) {
 	}
 	notifier.chainConn = chainConn
 
+	// Queries are issued over a separate pool of HTTP POST connections,
+	// so we create those now as well.
+	queries, err := newQueryPool(
+		config, defaultQueryPoolSize, defaultQueryTimeout,
+	)
+	if err != nil {
+		chainConn.Shutdown()
+		return nil, err
+	}
+	notifier.queries = queries
+
 	return notifier, nil
 }
 
@@ -146,12 +176,18 @@ func (b *BtcdNotifier) Start() error {
 		return err
 	}
 
-	_, currentHeight, err := b.chainConn.GetBestBlock()
+	var currentHeight int32
+	err := b.queries.query(func(c *rpcclient.Client) error {
+		var err error
+		_, currentHeight, err = c.GetBestBlock()
+		return err
+	})
 	if err != nil {
 		return err
 	}
 
-	b.wg.Add(1)
+	b.wg.Add(2)
+	go b.blockFetcher()
 	go b.notificationDispatcher(currentHeight)
 
 	return nil
@@ -164,9 +200,10 @@ func (b *BtcdNotifier) Stop() error {
 		return nil
 	}
 
-	// Shutdown the rpc client, this gracefully disconnects from btcd, and
+	// Shutdown the rpc clients, this gracefully disconnects from btcd, and
 	// cleans up all related resources.
 	b.chainConn.Shutdown()
+	b.queries.stop()
 
 	close(b.quit)
 	b.wg.Wait()
@@ -236,6 +273,50 @@ func (b *BtcdNotifier) onRedeemingTx(tx *btcutil.Tx, details *btcjson.BlockDetai
 	}()
 }
 
+// blockFetcher fetches each block connected to the main chain, in the order
+// they were connected, and hands it to the notificationDispatcher. As blocks
+// are fetched outside of the dispatcher, a slow fetch only delays the
+// notifications triggered by the block itself, rather than the registration
+// and dispatch of every other notification.
+//
+// NOTE: This MUST be run as a goroutine.
+func (b *BtcdNotifier) blockFetcher() {
+	defer b.wg.Done()
+
+	for {
+		select {
+		case <-b.chainUpdateSignal:
+		case <-b.quit:
+			return
+		}
+
+		// A new update is available, so pop the new chain update from
+		// the front of the update queue.
+		b.chainUpdateMtx.Lock()
+		update := b.chainUpdates[0]
+		b.chainUpdates[0] = nil // Set to nil to prevent GC leak.
+		b.chainUpdates = b.chainUpdates[1:]
+		b.chainUpdateMtx.Unlock()
+
+		var newBlock *wire.MsgBlock
+		err := b.queries.query(func(c *rpcclient.Client) error {
+			var err error
+			newBlock, err = c.GetBlock(update.blockHash)
+			return err
+		})
+		if err != nil {
+			chainntnfs.Log.Errorf("Unable to get block: %v", err)
+			continue
+		}
+
+		select {
+		case b.connectedBlocks <- &connectedBlock{update, newBlock}:
+		case <-b.quit:
+			return
+		}
+	}
+}
+
 // notificationDispatcher is the primary goroutine which handles client
 // notification registrations, as well as notification dispatches.
 func (b *BtcdNotifier) notificationDispatcher(currentHeight int32) {
@@ -314,23 +395,12 @@ out:
 			chainntnfs.Log.Warnf("Block disconnected from main "+
 				"chain: %v", staleBlockHash)
 
-		case <-b.chainUpdateSignal:
-			// A new update is available, so pop the new chain
-			// update from the front of the update queue.
-			b.chainUpdateMtx.Lock()
-			update := b.chainUpdates[0]
-			b.chainUpdates[0] = nil // Set to nil to prevent GC leak.
-			b.chainUpdates = b.chainUpdates[1:]
-			b.chainUpdateMtx.Unlock()
+		case connected := <-b.connectedBlocks:
+			update := connected.update
+			newBlock := connected.block
 
 			currentHeight = update.blockHeight
 
-			newBlock, err := b.chainConn.GetBlock(update.blockHash)
-			if err != nil {
-				chainntnfs.Log.Errorf("Unable to get block: %v", err)
-				continue
-			}
-
 			chainntnfs.Log.Infof("New block: height=%v, sha=%v",
 				update.blockHeight, update.blockHash)
 
@@ -426,7 +496,12 @@ func (b *BtcdNotifier) attemptHistoricalDispatch(msg *confirmationsNotification,
 
 	// If the transaction already has some or all of the confirmations,
 	// then we may be able to dispatch it immediately.
-	tx, err := b.chainConn.GetRawTransactionVerbose(msg.txid)
+	var tx *btcjson.TxRawResult
+	err := b.queries.query(func(c *rpcclient.Client) error {
+		var err error
+		tx, err = c.GetRawTransactionVerbose(msg.txid)
+		return err
+	})
 	if err != nil || tx == nil || tx.BlockHash == "" {
 		if err != nil {
 			chainntnfs.Log.Warnf("unable to query for txid(%v): %v",
@@ -444,7 +519,12 @@ func (b *BtcdNotifier) attemptHistoricalDispatch(msg *confirmationsNotification,
 			"historical dispatch: %v", tx.BlockHash, err)
 		return false
 	}
-	block, err := b.chainConn.GetBlockVerbose(blockHash)
+	var block *btcjson.GetBlockVerboseResult
+	err = b.queries.query(func(c *rpcclient.Client) error {
+		var err error
+		block, err = c.GetBlockVerbose(blockHash)
+		return err
+	})
 	if err != nil {
 		chainntnfs.Log.Errorf("unable to get block hash: %v", err)
 		return false
@@ -653,13 +733,25 @@ func (b *BtcdNotifier) RegisterSpendNtfn(outpoint *wire.OutPoint,
 	// is registered, the output hasn't already been spent. If the output
 	// is no longer in the UTXO set, the chain will be rescanned from the point
 	// where the output was added. The rescan will dispatch the notification.
-	txout, err := b.chainConn.GetTxOut(&outpoint.Hash, outpoint.Index, true)
+	var txout *btcjson.GetTxOutResult
+	err := b.queries.query(func(c *rpcclient.Client) error {
+		var err error
+		txout, err = c.GetTxOut(&outpoint.Hash, outpoint.Index, true)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
 
 	if txout == nil {
-		transaction, err := b.chainConn.GetRawTransactionVerbose(&outpoint.Hash)
+		var transaction *btcjson.TxRawResult
+		err := b.queries.query(func(c *rpcclient.Client) error {
+			var err error
+			transaction, err = c.GetRawTransactionVerbose(
+				&outpoint.Hash,
+			)
+			return err
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,120 @@
+package btcdnotify
+
+import (
+	"errors"
+	"time"
+
+	"github.com/roasbeef/btcd/rpcclient"
+)
+
+const (
+	// defaultQueryPoolSize is the default number of connections within the
+	// query pool, and therefore the maximum number of queries that can be
+	// in flight concurrently.
+	defaultQueryPoolSize = 3
+
+	// defaultQueryTimeout is the default duration a single query may take
+	// before it's abandoned.
+	defaultQueryTimeout = time.Second * 30
+)
+
+var (
+	// ErrQueryTimeout is returned when a query to btcd doesn't complete
+	// within the query timeout.
+	ErrQueryTimeout = errors.New("chainntnfs: timeout while waiting for " +
+		"query response from btcd")
+)
+
+// queryPool is a small pool of HTTP POST rpcclient connections used to issue
+// bursty queries (getblock, gettxout, etc) to btcd. Queries are kept off of
+// the websockets connection used for notifications, as a slow response to a
+// large query would otherwise delay the delivery of any notifications queued
+// behind it. Each query is additionally bounded by a timeout.
+type queryPool struct {
+	// clients is a buffered channel holding all idle connections. A
+	// connection is removed from the channel for the duration of a query,
+	// so it also acts as a semaphore bounding the number of concurrent
+	// queries.
+	clients chan *rpcclient.Client
+
+	// all houses every connection within the pool so they can be shut
+	// down.
+	all []*rpcclient.Client
+
+	timeout time.Duration
+
+	quit chan struct{}
+}
+
+// newQueryPool creates a new pool of size connections to the btcd node
+// detailed within the passed configuration. The configuration is copied, so
+// the caller's configuration isn't modified.
+func newQueryPool(config *rpcclient.ConnConfig, size int,
+	timeout time.Duration) (*queryPool, error) {
+
+	// As HTTP POST connections don't support notifications, we don't
+	// pass any notification handlers.
+	queryConfig := *config
+	queryConfig.HTTPPostMode = true
+	queryConfig.DisableConnectOnNew = false
+
+	p := &queryPool{
+		clients: make(chan *rpcclient.Client, size),
+		timeout: timeout,
+		quit:    make(chan struct{}),
+	}
+	for i := 0; i < size; i++ {
+		client, err := rpcclient.New(&queryConfig, nil)
+		if err != nil {
+			p.stop()
+			return nil, err
+		}
+
+		p.all = append(p.all, client)
+		p.clients <- client
+	}
+
+	return p, nil
+}
+
+// query executes the passed closure with an idle connection from the pool. If
+// all connections are busy, then this method blocks until one becomes
+// available. If the query doesn't complete within the pool's timeout, then
+// ErrQueryTimeout is returned, though the connection is only returned to the
+// pool once the query finally completes.
+func (p *queryPool) query(f func(*rpcclient.Client) error) error {
+	timeout := time.After(p.timeout)
+
+	var client *rpcclient.Client
+	select {
+	case client = <-p.clients:
+	case <-timeout:
+		return ErrQueryTimeout
+	case <-p.quit:
+		return ErrChainNotifierShuttingDown
+	}
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- f(client)
+		p.clients <- client
+	}()
+
+	select {
+	case err := <-errChan:
+		return err
+	case <-timeout:
+		return ErrQueryTimeout
+	case <-p.quit:
+		return ErrChainNotifierShuttingDown
+	}
+}
+
+// stop shuts down all connections within the pool, causing any pending
+// queries to fail.
+func (p *queryPool) stop() {
+	close(p.quit)
+	for _, client := range p.all {
+		client.Shutdown()
+	}
+}
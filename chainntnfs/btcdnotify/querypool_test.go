@@ -0,0 +1,115 @@
+package btcdnotify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/roasbeef/btcd/rpcclient"
+)
+
+// newTestRPCServer creates a test JSON-RPC server which responds to all
+// getblockcount requests after waiting for the passed delay.
+func newTestRPCServer(delay time.Duration) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter,
+		r *http.Request) {
+
+		var req struct {
+			ID uint64 `json:"id"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		time.Sleep(delay)
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"result": 100,
+			"error":  nil,
+			"id":     req.ID,
+		})
+	}))
+}
+
+func newTestQueryPool(t *testing.T, url string, size int,
+	timeout time.Duration) *queryPool {
+
+	pool, err := newQueryPool(&rpcclient.ConnConfig{
+		Host:       strings.TrimPrefix(url, "http://"),
+		User:       "user",
+		Pass:       "pass",
+		DisableTLS: true,
+	}, size, timeout)
+	if err != nil {
+		t.Fatalf("unable to create query pool: %v", err)
+	}
+
+	return pool
+}
+
+// TestQueryPoolConcurrency tests that the query pool is able to execute as
+// many queries concurrently as it has connections.
+func TestQueryPoolConcurrency(t *testing.T) {
+	t.Parallel()
+
+	const delay = 200 * time.Millisecond
+	server := newTestRPCServer(delay)
+	defer server.Close()
+
+	const poolSize = 3
+	pool := newTestQueryPool(t, server.URL, poolSize, 5*time.Second)
+	defer pool.stop()
+
+	// We'll launch as many queries as there are connections within the
+	// pool. As each connection handles a single query at a time, they
+	// should only complete within a single delay if they're executed
+	// concurrently.
+	start := time.Now()
+	errChan := make(chan error, poolSize)
+	for i := 0; i < poolSize; i++ {
+		go func() {
+			errChan <- pool.query(func(c *rpcclient.Client) error {
+				height, err := c.GetBlockCount()
+				if err == nil && height != 100 {
+					t.Errorf("expected height 100, got %v",
+						height)
+				}
+				return err
+			})
+		}()
+	}
+	for i := 0; i < poolSize; i++ {
+		if err := <-errChan; err != nil {
+			t.Fatalf("unable to execute query: %v", err)
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed >= delay*poolSize {
+		t.Fatalf("queries weren't executed concurrently, took %v",
+			elapsed)
+	}
+}
+
+// TestQueryPoolTimeout tests that a query which doesn't complete within the
+// pool's timeout is abandoned.
+func TestQueryPoolTimeout(t *testing.T) {
+	t.Parallel()
+
+	server := newTestRPCServer(time.Second)
+	defer server.Close()
+
+	pool := newTestQueryPool(t, server.URL, 1, 100*time.Millisecond)
+	defer pool.stop()
+
+	err := pool.query(func(c *rpcclient.Client) error {
+		_, err := c.GetBlockCount()
+		return err
+	})
+	if err != ErrQueryTimeout {
+		t.Fatalf("expected ErrQueryTimeout, got %v", err)
+	}
+}
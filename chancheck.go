@@ -0,0 +1,82 @@
+package main
+
+import (
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwallet/btcwallet"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// checkChannelConsistency cross-checks the open channels within the database
+// against the current state of the chain. Every open channel's funding output
+// should either still be unspent, or, if it has been spent, the channel
+// should have a close summary within the database. A channel whose funding
+// output has been spent without our knowledge indicates that the channel
+// state within the database has silently diverged from the chain, so
+// continuing to use it risks loss of funds. The set of all inconsistent
+// channels is returned.
+func checkChannelConsistency(chanDB *channeldb.DB,
+	chainIO lnwallet.BlockChainIO) (map[wire.OutPoint]struct{}, error) {
+
+	openChans, err := chanDB.FetchAllChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	// We'll gather the channel points of all closed channels (pending or
+	// otherwise) upfront so we can determine if a spent funding output is
+	// already known to us.
+	closedChans, err := chanDB.FetchClosedChannels(false)
+	if err != nil {
+		return nil, err
+	}
+	knownCloses := make(map[wire.OutPoint]struct{}, len(closedChans))
+	for _, closeSummary := range closedChans {
+		knownCloses[closeSummary.ChanPoint] = struct{}{}
+	}
+
+	inconsistentChans := make(map[wire.OutPoint]struct{})
+	for _, dbChan := range openChans {
+		// Pending channels don't yet have a confirmed funding output,
+		// so there's nothing to check.
+		if dbChan.IsPending {
+			continue
+		}
+
+		chanPoint := dbChan.FundingOutpoint
+		_, err := chainIO.GetUtxo(
+			&chanPoint, dbChan.FundingBroadcastHeight,
+		)
+		switch {
+		case err == nil:
+			continue
+
+		// If we're unable to query for the funding output, then we
+		// can't determine whether the channel is consistent, so we'll
+		// give it the benefit of the doubt.
+		case err != btcwallet.ErrOutputSpent:
+			ltndLog.Warnf("Unable to check funding output of "+
+				"ChannelPoint(%v): %v", chanPoint, err)
+			continue
+		}
+
+		if _, ok := knownCloses[chanPoint]; ok {
+			continue
+		}
+
+		ltndLog.Errorf("INCONSISTENT CHANNEL STATE: the funding output "+
+			"of ChannelPoint(%v) with peer %x has been spent, but "+
+			"no record of the channel being closed exists. The "+
+			"channel won't be made available for forwarding. Use "+
+			"`lncli getcommitmenttxns` to inspect the latest "+
+			"commitment state and `lncli pendingchannels` to "+
+			"determine whether the close is being resolved. If "+
+			"it isn't, `lncli closechannel --force` can be used "+
+			"to recover the channel's funds", chanPoint,
+			dbChan.IdentityPub.SerializeCompressed())
+
+		inconsistentChans[chanPoint] = struct{}{}
+	}
+
+	return inconsistentChans, nil
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btclog"
+	"github.com/lightningnetwork/lnd/lnwallet/btcwallet"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// mockSpentChainIO is a mock chain backend which reports all outputs as
+// spent.
+type mockSpentChainIO struct {
+	mockChainIO
+}
+
+func (*mockSpentChainIO) GetUtxo(op *wire.OutPoint,
+	heightHint uint32) (*wire.TxOut, error) {
+	return nil, btcwallet.ErrOutputSpent
+}
+
+// TestCheckChannelConsistency tests that open channels whose funding output
+// has been spent without a close record are flagged as inconsistent.
+func TestCheckChannelConsistency(t *testing.T) {
+	disablePeerLogger(t)
+	ltndLog = btclog.Disabled
+
+	p, aliceChan, _, cleanUp, err := createTestPeer(nil, nil)
+	if err != nil {
+		t.Fatalf("unable to create test channels: %v", err)
+	}
+	defer cleanUp()
+
+	chanPoint := *aliceChan.ChannelPoint()
+
+	// If the funding output is still unspent, then the channel should be
+	// deemed consistent.
+	inconsistentChans, err := checkChannelConsistency(
+		p.server.chanDB, &mockChainIO{},
+	)
+	if err != nil {
+		t.Fatalf("unable to check channels: %v", err)
+	}
+	if len(inconsistentChans) != 0 {
+		t.Fatalf("expected no inconsistent channels, got %v",
+			len(inconsistentChans))
+	}
+
+	// However, once the funding output has been spent, the channel should
+	// be flagged, as we have no record of it being closed.
+	inconsistentChans, err = checkChannelConsistency(
+		p.server.chanDB, &mockSpentChainIO{},
+	)
+	if err != nil {
+		t.Fatalf("unable to check channels: %v", err)
+	}
+	if _, ok := inconsistentChans[chanPoint]; !ok ||
+		len(inconsistentChans) != 1 {
+
+		t.Fatalf("expected ChannelPoint(%v) to be inconsistent, "+
+			"got %v", chanPoint, inconsistentChans)
+	}
+}
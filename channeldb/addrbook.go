@@ -0,0 +1,251 @@
+package channeldb
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/roasbeef/btcd/btcec"
+)
+
+var (
+	// peerAddrBookBucket stores the addresses each of our channel
+	// counterparties is known to be reachable at. Unlike the addresses
+	// within the channel graph, the entries aren't dependent on the peer's
+	// NodeAnnouncement being present within the graph, allowing us to
+	// reconnect to channel peers which have yet to announce themselves,
+	// or whose announcements have been pruned.
+	//
+	// maps: pubKey -> PeerAddresses
+	peerAddrBookBucket = []byte("peer-addr-book")
+)
+
+const (
+	// maxDialedAddrs is the maximum number of successfully dialed
+	// addresses stored for each peer. Once exceeded, the least recently
+	// dialed address is evicted.
+	maxDialedAddrs = 5
+)
+
+// PeerAddresses is an entry within the peer address book, recording the
+// addresses a peer is known to be reachable at.
+type PeerAddresses struct {
+	// PubKey is the identity public key of the peer.
+	PubKey *btcec.PublicKey
+
+	// Advertised is the set of addresses the peer last advertised within
+	// its NodeAnnouncement.
+	Advertised []*net.TCPAddr
+
+	// Dialed is the set of addresses we've successfully established an
+	// outbound connection to the peer over, ordered from most to least
+	// recently dialed.
+	Dialed []*net.TCPAddr
+
+	// LastConnected is the last time a connection was established with
+	// the peer, in either direction.
+	LastConnected time.Time
+}
+
+// Addresses returns the combined set of the peer's dialed and advertised
+// addresses, without duplicates. The dialed addresses come first, as they're
+// known to have been reachable.
+func (p *PeerAddresses) Addresses() []*net.TCPAddr {
+	addrs := make([]*net.TCPAddr, 0, len(p.Dialed)+len(p.Advertised))
+	seen := make(map[string]struct{})
+	for _, set := range [][]*net.TCPAddr{p.Dialed, p.Advertised} {
+		for _, addr := range set {
+			if _, ok := seen[addr.String()]; ok {
+				continue
+			}
+			seen[addr.String()] = struct{}{}
+
+			addrs = append(addrs, addr)
+		}
+	}
+
+	return addrs
+}
+
+// RecordPeerConnection updates the address book entry of the target peer to
+// reflect a newly established connection. If the connection was outbound,
+// then the dialed address is recorded as the peer's most recently dialed
+// address. If advertised is non-nil, then it replaces the set of addresses
+// the peer is known to advertise.
+func (d *DB) RecordPeerConnection(pub *btcec.PublicKey, dialed *net.TCPAddr,
+	advertised []*net.TCPAddr, connTime time.Time) error {
+
+	return d.Update(func(tx *bolt.Tx) error {
+		addrBook, err := tx.CreateBucketIfNotExists(peerAddrBookBucket)
+		if err != nil {
+			return err
+		}
+
+		pubBytes := pub.SerializeCompressed()
+
+		// We'll start from the existing entry of the peer, if one
+		// exists.
+		entry := &PeerAddresses{PubKey: pub}
+		if entryBytes := addrBook.Get(pubBytes); entryBytes != nil {
+			entry, err = deserializePeerAddresses(
+				bytes.NewReader(entryBytes),
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		entry.LastConnected = connTime
+		if advertised != nil {
+			entry.Advertised = advertised
+		}
+
+		// The dialed address is moved to the front of the dialed
+		// addresses, evicting the least recently dialed address if
+		// we've exceeded the limit.
+		if dialed != nil {
+			dialedAddrs := []*net.TCPAddr{dialed}
+			for _, addr := range entry.Dialed {
+				if addr.String() == dialed.String() {
+					continue
+				}
+				dialedAddrs = append(dialedAddrs, addr)
+			}
+			if len(dialedAddrs) > maxDialedAddrs {
+				dialedAddrs = dialedAddrs[:maxDialedAddrs]
+			}
+			entry.Dialed = dialedAddrs
+		}
+
+		var b bytes.Buffer
+		if err := serializePeerAddresses(&b, entry); err != nil {
+			return err
+		}
+
+		return addrBook.Put(pubBytes, b.Bytes())
+	})
+}
+
+// FetchPeerAddresses returns the address book entry of the target peer. If
+// the address book doesn't contain an entry for the peer, then
+// ErrPeerAddressesNotFound is returned.
+func (d *DB) FetchPeerAddresses(pub *btcec.PublicKey) (*PeerAddresses, error) {
+	var entry *PeerAddresses
+	err := d.View(func(tx *bolt.Tx) error {
+		addrBook := tx.Bucket(peerAddrBookBucket)
+		if addrBook == nil {
+			return ErrPeerAddressesNotFound
+		}
+
+		entryBytes := addrBook.Get(pub.SerializeCompressed())
+		if entryBytes == nil {
+			return ErrPeerAddressesNotFound
+		}
+
+		var err error
+		entry, err = deserializePeerAddresses(bytes.NewReader(entryBytes))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// FetchAllPeerAddresses returns every entry within the peer address book.
+func (d *DB) FetchAllPeerAddresses() ([]*PeerAddresses, error) {
+	var entries []*PeerAddresses
+	err := d.View(func(tx *bolt.Tx) error {
+		addrBook := tx.Bucket(peerAddrBookBucket)
+		if addrBook == nil {
+			return nil
+		}
+
+		return addrBook.ForEach(func(k, v []byte) error {
+			entry, err := deserializePeerAddresses(bytes.NewReader(v))
+			if err != nil {
+				return err
+			}
+
+			entries = append(entries, entry)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// writeTCPAddrs writes the passed set of TCP addresses to the writer as a
+// count followed by the string encoding of each address.
+func writeTCPAddrs(w io.Writer, addrs []*net.TCPAddr) error {
+	if err := writeElement(w, uint16(len(addrs))); err != nil {
+		return err
+	}
+	for _, addr := range addrs {
+		if err := writeElement(w, addr.String()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readTCPAddrs reads a set of TCP addresses encoded by writeTCPAddrs.
+func readTCPAddrs(r io.Reader) ([]*net.TCPAddr, error) {
+	var numAddrs uint16
+	if err := readElement(r, &numAddrs); err != nil {
+		return nil, err
+	}
+
+	addrs := make([]*net.TCPAddr, numAddrs)
+	for i := range addrs {
+		var addrString string
+		if err := readElement(r, &addrString); err != nil {
+			return nil, err
+		}
+
+		addr, err := net.ResolveTCPAddr("tcp", addrString)
+		if err != nil {
+			return nil, err
+		}
+		addrs[i] = addr
+	}
+
+	return addrs, nil
+}
+
+func serializePeerAddresses(w io.Writer, p *PeerAddresses) error {
+	if err := writeElements(w, p.PubKey, p.LastConnected); err != nil {
+		return err
+	}
+	if err := writeTCPAddrs(w, p.Advertised); err != nil {
+		return err
+	}
+
+	return writeTCPAddrs(w, p.Dialed)
+}
+
+func deserializePeerAddresses(r io.Reader) (*PeerAddresses, error) {
+	p := &PeerAddresses{}
+	if err := readElements(r, &p.PubKey, &p.LastConnected); err != nil {
+		return nil, err
+	}
+
+	var err error
+	p.Advertised, err = readTCPAddrs(r)
+	if err != nil {
+		return nil, err
+	}
+	p.Dialed, err = readTCPAddrs(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
@@ -0,0 +1,116 @@
+package channeldb
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/roasbeef/btcd/btcec"
+)
+
+// TestPeerAddressBook asserts that the peer address book records the
+// advertised and dialed addresses of each peer, ordering the dialed addresses
+// by recency and evicting the least recently dialed.
+func TestPeerAddressBook(t *testing.T) {
+	t.Parallel()
+
+	cdb, cleanUp, err := makeTestDB()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+	defer cleanUp()
+
+	_, pub := btcec.PrivKeyFromBytes(btcec.S256(), key[:])
+
+	// Before any connections have been recorded, the peer shouldn't be
+	// found.
+	if _, err := cdb.FetchPeerAddresses(pub); err != ErrPeerAddressesNotFound {
+		t.Fatalf("expected ErrPeerAddressesNotFound, got: %v", err)
+	}
+
+	addrs := make([]*net.TCPAddr, maxDialedAddrs+1)
+	for i := range addrs {
+		addrs[i], err = net.ResolveTCPAddr(
+			"tcp", fmt.Sprintf("10.0.0.%v:9735", i+1),
+		)
+		if err != nil {
+			t.Fatalf("unable to create test addr: %v", err)
+		}
+	}
+
+	// We'll first record an inbound connection, which only carries the
+	// peer's advertised addresses.
+	advertised := addrs[:2]
+	connTime := time.Unix(1000, 0)
+	err = cdb.RecordPeerConnection(pub, nil, advertised, connTime)
+	if err != nil {
+		t.Fatalf("unable to record connection: %v", err)
+	}
+
+	entry, err := cdb.FetchPeerAddresses(pub)
+	if err != nil {
+		t.Fatalf("unable to fetch peer addresses: %v", err)
+	}
+	if !reflect.DeepEqual(entry.Advertised, advertised) {
+		t.Fatalf("expected advertised addrs %v, got %v", advertised,
+			entry.Advertised)
+	}
+	if len(entry.Dialed) != 0 {
+		t.Fatalf("expected no dialed addrs, got %v", entry.Dialed)
+	}
+	if !entry.LastConnected.Equal(connTime) {
+		t.Fatalf("expected last connected %v, got %v", connTime,
+			entry.LastConnected)
+	}
+
+	// Next, we'll dial each of the addresses in turn, without knowledge
+	// of the peer's advertised addresses. The advertised addresses should
+	// be retained, and only the most recently dialed addresses kept.
+	for _, addr := range addrs {
+		err := cdb.RecordPeerConnection(pub, addr, nil, connTime)
+		if err != nil {
+			t.Fatalf("unable to record connection: %v", err)
+		}
+	}
+
+	// Finally, we'll re-dial an address we've dialed previously, which
+	// should move it to the front.
+	err = cdb.RecordPeerConnection(pub, addrs[3], nil, connTime)
+	if err != nil {
+		t.Fatalf("unable to record connection: %v", err)
+	}
+
+	entry, err = cdb.FetchPeerAddresses(pub)
+	if err != nil {
+		t.Fatalf("unable to fetch peer addresses: %v", err)
+	}
+	if !reflect.DeepEqual(entry.Advertised, advertised) {
+		t.Fatalf("expected advertised addrs %v, got %v", advertised,
+			entry.Advertised)
+	}
+	expectedDialed := []*net.TCPAddr{
+		addrs[3], addrs[5], addrs[4], addrs[2], addrs[1],
+	}
+	if !reflect.DeepEqual(entry.Dialed, expectedDialed) {
+		t.Fatalf("expected dialed addrs %v, got %v", expectedDialed,
+			entry.Dialed)
+	}
+
+	// The combined addresses should contain each address once, with the
+	// dialed addresses first.
+	expectedAddrs := append(expectedDialed, addrs[0])
+	if !reflect.DeepEqual(entry.Addresses(), expectedAddrs) {
+		t.Fatalf("expected addrs %v, got %v", expectedAddrs,
+			entry.Addresses())
+	}
+
+	entries, err := cdb.FetchAllPeerAddresses()
+	if err != nil {
+		t.Fatalf("unable to fetch all peer addresses: %v", err)
+	}
+	if len(entries) != 1 || !entries[0].PubKey.IsEqual(pub) {
+		t.Fatalf("expected single entry for peer, got %v", entries)
+	}
+}
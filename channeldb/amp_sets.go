@@ -0,0 +1,368 @@
+package channeldb
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/wire"
+)
+
+var (
+	// ampSetBucket is a top-level bucket which houses the sets of shards
+	// of the atomic multi-path payments received by our node. Each set is
+	// keyed by its set ID.
+	//
+	// maps: setID -> ampSet
+	ampSetBucket = []byte("amp-sets")
+
+	// ampShardIndexBucket is a sub-bucket of the ampSetBucket which
+	// indexes the sets by the payment hashes of their shards, allowing the
+	// set of a held shard to be found once its link is restarted.
+	//
+	// maps: paymentHash -> setID
+	ampShardIndexBucket = []byte("amp-shard-index")
+
+	// ErrAMPSetNotFound is returned when no set of shards of an atomic
+	// multi-path payment is found for a set ID or payment hash.
+	ErrAMPSetNotFound = fmt.Errorf("amp set not found")
+)
+
+const (
+	// MaxAMPShards is the maximum number of shards of a single atomic
+	// multi-path payment. As all of the shards are settled within a single
+	// invoice, this is bound by the number of HTLCs an invoice may hold.
+	MaxAMPShards = MaxInvoiceHTLCs
+)
+
+// AMPShard is a shard of an atomic multi-path payment received by our node,
+// along with the HTLC carrying it.
+type AMPShard struct {
+	// Htlc is the HTLC which carries the shard.
+	Htlc InvoiceHTLC
+
+	// PaymentHash is the payment hash of the HTLC.
+	PaymentHash [32]byte
+
+	// RootShare is the share of the payment's root seed carried by the
+	// shard.
+	RootShare [32]byte
+
+	// ChildIndex is the index used to derive the shard's preimage from the
+	// root seed.
+	ChildIndex uint32
+}
+
+// AMPSet is the set of shards of an atomic multi-path payment received by our
+// node. The shards are held while the set is accepted, until either all of
+// them have arrived and the set is settled, or the set is canceled.
+type AMPSet struct {
+	// SetID identifies the payment the set belongs to.
+	SetID [32]byte
+
+	// Total is the total amount of the payment.
+	Total lnwire.MilliAtom
+
+	// State is the state of the set, which is ContractAccepted until the
+	// set is either settled or canceled.
+	State ContractState
+
+	// Shards are the shards of the set, in the order they were received.
+	Shards []AMPShard
+
+	// Preimages are the preimages of the shards, in the same order as the
+	// shards. They're only known once the set has been settled.
+	Preimages [][32]byte
+}
+
+// FetchAMPSet returns the set of shards with the passed set ID. If no such
+// set exists, then ErrAMPSetNotFound is returned.
+func (d *DB) FetchAMPSet(setID [32]byte) (*AMPSet, error) {
+	var set *AMPSet
+	err := d.View(func(tx *bolt.Tx) error {
+		sets := tx.Bucket(ampSetBucket)
+		if sets == nil {
+			return ErrAMPSetNotFound
+		}
+
+		var err error
+		set, err = fetchAMPSet(sets, setID[:])
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return set, nil
+}
+
+// LookupAMPSet returns the set holding the shard with the passed payment
+// hash. If no such set exists, then ErrAMPSetNotFound is returned.
+func (d *DB) LookupAMPSet(paymentHash [32]byte) (*AMPSet, error) {
+	var set *AMPSet
+	err := d.View(func(tx *bolt.Tx) error {
+		sets := tx.Bucket(ampSetBucket)
+		if sets == nil {
+			return ErrAMPSetNotFound
+		}
+		shardIndex := sets.Bucket(ampShardIndexBucket)
+		if shardIndex == nil {
+			return ErrAMPSetNotFound
+		}
+
+		setID := shardIndex.Get(paymentHash[:])
+		if setID == nil {
+			return ErrAMPSetNotFound
+		}
+
+		var err error
+		set, err = fetchAMPSet(sets, setID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return set, nil
+}
+
+// PutAMPSet writes the passed set of shards, replacing any with the same set
+// ID, and indexes the set by the payment hashes of its shards.
+func (d *DB) PutAMPSet(set *AMPSet) error {
+	return d.Update(func(tx *bolt.Tx) error {
+		return putAMPSet(tx, set)
+	})
+}
+
+// SettleAMPSet settles the accepted set with the passed set ID, recording the
+// passed preimages of its shards. Within the same transaction, a settled
+// invoice for the total amount of the payment is added, recording the HTLCs
+// of the shards as having settled it. As a single invoice can't pay to the
+// hash of every shard, the invoice is identified by the hash of the first
+// shard, and reveals its preimage.
+func (d *DB) SettleAMPSet(setID [32]byte, preimages [][32]byte) error {
+	return d.Update(func(tx *bolt.Tx) error {
+		sets := tx.Bucket(ampSetBucket)
+		if sets == nil {
+			return ErrAMPSetNotFound
+		}
+		set, err := fetchAMPSet(sets, setID[:])
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case set.State == ContractSettled:
+			return ErrInvoiceAlreadySettled
+		case set.State == ContractCanceled:
+			return ErrInvoiceAlreadyCanceled
+		case len(preimages) != len(set.Shards):
+			return fmt.Errorf("expected %v preimages for amp set "+
+				"%x, got %v", len(set.Shards), setID[:],
+				len(preimages))
+		}
+
+		settleTime := time.Now()
+		invoice := &Invoice{
+			CreationDate: settleTime,
+			Terms: ContractTerm{
+				PaymentPreimage: preimages[0],
+				PaymentHash:     sha256.Sum256(preimages[0][:]),
+				Value:           set.Total,
+			},
+			Htlcs: make([]InvoiceHTLC, 0, len(set.Shards)),
+		}
+		for i := range set.Shards {
+			set.Shards[i].Htlc.SettleTime = settleTime
+			invoice.Htlcs = append(invoice.Htlcs, set.Shards[i].Htlc)
+		}
+
+		set.State = ContractSettled
+		set.Preimages = preimages
+		if err := putAMPSet(tx, set); err != nil {
+			return err
+		}
+
+		if err := addInvoice(tx, invoice); err != nil {
+			return err
+		}
+
+		invoices := tx.Bucket(invoiceBucket)
+		invoiceNum := invoices.Bucket(invoiceIndexBucket).Get(
+			invoice.Terms.PaymentHash[:],
+		)
+		settleIndex, err := invoices.CreateBucketIfNotExists(
+			settleIndexBucket,
+		)
+		if err != nil {
+			return err
+		}
+
+		return markInvoiceSettled(
+			invoices, settleIndex, invoiceNum, invoice,
+		)
+	})
+}
+
+// CancelAMPSet cancels the accepted set with the passed set ID, after which
+// none of its shards can be settled.
+func (d *DB) CancelAMPSet(setID [32]byte) error {
+	return d.Update(func(tx *bolt.Tx) error {
+		sets := tx.Bucket(ampSetBucket)
+		if sets == nil {
+			return ErrAMPSetNotFound
+		}
+		set, err := fetchAMPSet(sets, setID[:])
+		if err != nil {
+			return err
+		}
+
+		switch set.State {
+		case ContractSettled:
+			return ErrInvoiceAlreadySettled
+		case ContractCanceled:
+			return ErrInvoiceAlreadyCanceled
+		}
+
+		set.State = ContractCanceled
+		return putAMPSet(tx, set)
+	})
+}
+
+// putAMPSet writes the passed set within the passed transaction, indexing it
+// by the payment hashes of its shards.
+func putAMPSet(tx *bolt.Tx, set *AMPSet) error {
+	if len(set.Shards) > MaxAMPShards {
+		return fmt.Errorf("amp set has %v shards, max is %v",
+			len(set.Shards), MaxAMPShards)
+	}
+
+	sets, err := tx.CreateBucketIfNotExists(ampSetBucket)
+	if err != nil {
+		return err
+	}
+	shardIndex, err := sets.CreateBucketIfNotExists(ampShardIndexBucket)
+	if err != nil {
+		return err
+	}
+
+	for _, shard := range set.Shards {
+		err := shardIndex.Put(shard.PaymentHash[:], set.SetID[:])
+		if err != nil {
+			return err
+		}
+	}
+
+	var b bytes.Buffer
+	if err := serializeAMPSet(&b, set); err != nil {
+		return err
+	}
+
+	return sets.Put(set.SetID[:], b.Bytes())
+}
+
+// fetchAMPSet reads the set with the passed set ID from the passed bucket.
+func fetchAMPSet(sets *bolt.Bucket, setID []byte) (*AMPSet, error) {
+	setBytes := sets.Get(setID)
+	if setBytes == nil {
+		return nil, ErrAMPSetNotFound
+	}
+
+	set, err := deserializeAMPSet(bytes.NewReader(setBytes))
+	if err != nil {
+		return nil, err
+	}
+	copy(set.SetID[:], setID)
+
+	return set, nil
+}
+
+func serializeAMPSet(w io.Writer, set *AMPSet) error {
+	err := writeElements(w, set.Total, uint8(set.State))
+	if err != nil {
+		return err
+	}
+
+	if err := wire.WriteVarInt(w, 0, uint64(len(set.Shards))); err != nil {
+		return err
+	}
+	for _, shard := range set.Shards {
+		htlc := shard.Htlc
+		err := writeElements(
+			w, htlc.ChanID, htlc.HtlcID, htlc.Amt, htlc.Expiry,
+			htlc.AcceptHeight, htlc.SettleTime, shard.PaymentHash,
+			shard.RootShare, shard.ChildIndex,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := wire.WriteVarInt(w, 0, uint64(len(set.Preimages))); err != nil {
+		return err
+	}
+	for _, preimage := range set.Preimages {
+		if err := writeElement(w, preimage); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func deserializeAMPSet(r io.Reader) (*AMPSet, error) {
+	set := &AMPSet{}
+
+	var state uint8
+	if err := readElements(r, &set.Total, &state); err != nil {
+		return nil, err
+	}
+	set.State = ContractState(state)
+
+	numShards, err := wire.ReadVarInt(r, 0)
+	if err != nil {
+		return nil, err
+	}
+	if numShards > MaxAMPShards {
+		return nil, fmt.Errorf("amp set has %v shards, max is %v",
+			numShards, MaxAMPShards)
+	}
+	if numShards > 0 {
+		set.Shards = make([]AMPShard, numShards)
+	}
+	for i := range set.Shards {
+		shard := &set.Shards[i]
+		htlc := &shard.Htlc
+		err := readElements(
+			r, &htlc.ChanID, &htlc.HtlcID, &htlc.Amt, &htlc.Expiry,
+			&htlc.AcceptHeight, &htlc.SettleTime, &shard.PaymentHash,
+			&shard.RootShare, &shard.ChildIndex,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	numPreimages, err := wire.ReadVarInt(r, 0)
+	if err != nil {
+		return nil, err
+	}
+	if numPreimages > numShards {
+		return nil, fmt.Errorf("amp set has %v preimages for %v "+
+			"shards", numPreimages, numShards)
+	}
+	if numPreimages > 0 {
+		set.Preimages = make([][32]byte, numPreimages)
+	}
+	for i := range set.Preimages {
+		if err := readElement(r, &set.Preimages[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return set, nil
+}
@@ -0,0 +1,115 @@
+package channeldb
+
+import (
+	"crypto/sha256"
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// TestAMPSets tests that the sets of shards of atomic multi-path payments can
+// be found by either their set ID or the payment hashes of their shards, and
+// that settling a set records a settled invoice paid by its shards.
+func TestAMPSets(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+
+	setID := [32]byte{1}
+	if _, err := db.FetchAMPSet(setID); err != ErrAMPSetNotFound {
+		t.Fatalf("expected ErrAMPSetNotFound, got %v", err)
+	}
+
+	preimages := [][32]byte{{2}, {3}}
+	set := &AMPSet{
+		SetID: setID,
+		Total: 3000,
+		State: ContractAccepted,
+	}
+	for i, preimage := range preimages {
+		set.Shards = append(set.Shards, AMPShard{
+			Htlc: InvoiceHTLC{
+				ChanID:       lnwire.NewShortChanIDFromInt(uint64(i)),
+				HtlcID:       uint64(i),
+				Amt:          1500,
+				Expiry:       100,
+				AcceptHeight: 50,
+			},
+			PaymentHash: sha256.Sum256(preimage[:]),
+			RootShare:   [32]byte{byte(i)},
+			ChildIndex:  uint32(i),
+		})
+	}
+	if err := db.PutAMPSet(set); err != nil {
+		t.Fatalf("unable to put amp set: %v", err)
+	}
+
+	// The set should be found by its set ID, as well as by the payment
+	// hash of each of its shards.
+	fetched, err := db.FetchAMPSet(setID)
+	if err != nil {
+		t.Fatalf("unable to fetch amp set: %v", err)
+	}
+	if !reflect.DeepEqual(fetched, set) {
+		t.Fatalf("expected amp set %v, got %v", spew.Sdump(set),
+			spew.Sdump(fetched))
+	}
+	for _, shard := range set.Shards {
+		fetched, err := db.LookupAMPSet(shard.PaymentHash)
+		if err != nil {
+			t.Fatalf("unable to look up amp set: %v", err)
+		}
+		if fetched.SetID != setID {
+			t.Fatalf("expected set %x, got %x", setID, fetched.SetID)
+		}
+	}
+
+	// Settling the set should record the preimages of its shards, and add
+	// a settled invoice which reveals the preimage of its first shard.
+	if err := db.SettleAMPSet(setID, preimages); err != nil {
+		t.Fatalf("unable to settle amp set: %v", err)
+	}
+	fetched, err = db.FetchAMPSet(setID)
+	if err != nil {
+		t.Fatalf("unable to fetch amp set: %v", err)
+	}
+	if fetched.State != ContractSettled {
+		t.Fatalf("expected amp set to be settled, is %v",
+			fetched.State)
+	}
+	if !reflect.DeepEqual(fetched.Preimages, preimages) {
+		t.Fatalf("expected preimages %x, got %x", preimages,
+			fetched.Preimages)
+	}
+
+	invoice, err := db.LookupInvoice(set.Shards[0].PaymentHash)
+	if err != nil {
+		t.Fatalf("unable to look up invoice of amp set: %v", err)
+	}
+	if invoice.Terms.State != ContractSettled {
+		t.Fatalf("expected invoice to be settled, is %v",
+			invoice.Terms.State)
+	}
+	if invoice.Terms.PaymentPreimage != preimages[0] {
+		t.Fatalf("expected preimage %x, got %x", preimages[0],
+			invoice.Terms.PaymentPreimage)
+	}
+	if invoice.AmtPaid() != set.Total {
+		t.Fatalf("expected invoice to be paid %v, got %v", set.Total,
+			invoice.AmtPaid())
+	}
+
+	// A settled set can be neither settled nor canceled again.
+	if err := db.SettleAMPSet(setID, preimages); err != ErrInvoiceAlreadySettled {
+		t.Fatalf("expected ErrInvoiceAlreadySettled, got %v", err)
+	}
+	if err := db.CancelAMPSet(setID); err != ErrInvoiceAlreadySettled {
+		t.Fatalf("expected ErrInvoiceAlreadySettled, got %v", err)
+	}
+}
@@ -0,0 +1,48 @@
+package channeldb
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/boltdb/bolt"
+)
+
+// backupFilePrefix is the prefix of the temporary files database snapshots
+// are written to while a backup is in progress.
+const backupFilePrefix = "channel.db.backup"
+
+// Backup writes a consistent snapshot of the entire database to the passed
+// writer, returning the number of bytes written. The snapshot is a valid
+// database file which can be opened in place of the original. As the snapshot
+// is taken within a read-only transaction, it's safe to back up the database
+// while it's in use: concurrent writes aren't blocked, and won't be reflected
+// within the snapshot.
+//
+// An open read-only transaction prevents bolt from remapping the database as
+// it grows, so the snapshot is first copied to a temporary file alongside the
+// database. The transaction is thus only held open for as long as the copy
+// takes on disk, rather than for as long as the writer takes to consume the
+// snapshot, which may be a slow remote client.
+func (d *DB) Backup(w io.Writer) (int64, error) {
+	snapshot, err := ioutil.TempFile(d.dbPath, backupFilePrefix)
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(snapshot.Name())
+	defer snapshot.Close()
+
+	err = d.View(func(tx *bolt.Tx) error {
+		_, err := tx.WriteTo(snapshot)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := snapshot.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	return io.Copy(w, snapshot)
+}
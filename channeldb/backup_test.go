@@ -0,0 +1,128 @@
+package channeldb
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/boltdb/bolt"
+)
+
+// txCheckWriter is a writer which records whether any transaction of the
+// database was open while it was written to.
+type txCheckWriter struct {
+	bytes.Buffer
+
+	db     *DB
+	openTx bool
+}
+
+func (w *txCheckWriter) Write(p []byte) (int, error) {
+	if w.db.Stats().OpenTxN != 0 {
+		w.openTx = true
+	}
+
+	return w.Buffer.Write(p)
+}
+
+// TestBackup asserts that a backup of the database is a consistent snapshot
+// which can be opened as a database, and which doesn't reflect any writes made
+// after it was taken. The snapshot should be fully taken before it's written
+// out, so that no transaction is held open while the writer consumes it.
+func TestBackup(t *testing.T) {
+	t.Parallel()
+
+	cdb, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+
+	var (
+		testBucket = []byte("test-bucket")
+		beforeKey  = []byte("before")
+		afterKey   = []byte("after")
+		value      = bytes.Repeat([]byte{1}, 100)
+	)
+
+	put := func(key []byte) {
+		err := cdb.Update(func(tx *bolt.Tx) error {
+			b, err := tx.CreateBucketIfNotExists(testBucket)
+			if err != nil {
+				return err
+			}
+			return b.Put(key, value)
+		})
+		if err != nil {
+			t.Fatalf("unable to write data: %v", err)
+		}
+	}
+
+	// We'll write a key before taking the backup, and another after. Only
+	// the first should be found within the backup.
+	put(beforeKey)
+
+	backup := &txCheckWriter{db: cdb}
+	n, err := cdb.Backup(backup)
+	if err != nil {
+		t.Fatalf("unable to back up db: %v", err)
+	}
+	if n != int64(backup.Len()) {
+		t.Fatalf("expected %v bytes written, got %v", backup.Len(), n)
+	}
+	if backup.openTx {
+		t.Fatalf("transaction held open while writing out backup")
+	}
+
+	// The temporary snapshot shouldn't outlive the backup.
+	snapshots, err := filepath.Glob(
+		filepath.Join(cdb.dbPath, backupFilePrefix+"*"),
+	)
+	if err != nil {
+		t.Fatalf("unable to list snapshots: %v", err)
+	}
+	if len(snapshots) != 0 {
+		t.Fatalf("snapshots not removed: %v", snapshots)
+	}
+
+	put(afterKey)
+
+	// Write the backup out as a channeldb within a fresh directory, and
+	// open it as we would the original.
+	backupDir, err := ioutil.TempDir("", "channeldb-backup")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(backupDir)
+
+	backupPath := filepath.Join(backupDir, dbName)
+	err = ioutil.WriteFile(backupPath, backup.Bytes(), dbFilePermission)
+	if err != nil {
+		t.Fatalf("unable to write backup: %v", err)
+	}
+
+	backupDB, err := Open(backupDir)
+	if err != nil {
+		t.Fatalf("unable to open backup: %v", err)
+	}
+	defer backupDB.Close()
+
+	err = backupDB.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(testBucket)
+		if b == nil {
+			t.Fatalf("test bucket not found within backup")
+		}
+		if !bytes.Equal(b.Get(beforeKey), value) {
+			t.Fatalf("key written before backup not found")
+		}
+		if b.Get(afterKey) != nil {
+			t.Fatalf("key written after backup found")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unable to read backup: %v", err)
+	}
+}
@@ -0,0 +1,162 @@
+package channeldb
+
+import (
+	"sync"
+	"time"
+
+	"github.com/boltdb/bolt"
+)
+
+// batchRequest is a single database mutation which may be coalesced with other
+// concurrent mutations into a single transaction.
+type batchRequest struct {
+	// update applies the mutation within the transaction shared by the
+	// batch. If an error is returned, then the entire batch is rolled
+	// back and each request is retried within its own transaction. As a
+	// result, update may be called multiple times, so it must reset any
+	// state it accumulates outside of the transaction.
+	update func(tx *bolt.Tx) error
+
+	// onCommit, if non-nil, is called once the transaction containing the
+	// request has been committed, or has failed with the passed error. The
+	// error it returns is delivered to the caller. This allows a request
+	// to report an expected outcome, such as an edge already existing,
+	// without failing the other requests within its batch.
+	onCommit func(err error) error
+
+	// lazy signals that the caller is willing to wait for the batch
+	// commit interval to elapse, allowing the request to be coalesced
+	// with other concurrent requests. Otherwise, the pending batch is
+	// committed right away, so that callers applying updates serially
+	// aren't stalled by the interval.
+	lazy bool
+
+	errChan chan error
+}
+
+// BatchOption is a functional option which modifies how a database mutation
+// is scheduled within a batch.
+type BatchOption func(*batchRequest)
+
+// LazyAdd signals that the mutation may be held back for up to the batch
+// commit interval, in order to coalesce it with other mutations. This should
+// only be used by callers which apply mutations concurrently, as each
+// mutation blocks until its batch has been committed.
+func LazyAdd() BatchOption {
+	return func(r *batchRequest) {
+		r.lazy = true
+	}
+}
+
+// deliver delivers the result of the transaction containing the request to
+// the caller.
+func (r *batchRequest) deliver(err error) {
+	if r.onCommit != nil {
+		err = r.onCommit(err)
+	}
+
+	r.errChan <- err
+}
+
+// batchScheduler coalesces the mutations which arrive within a window of time
+// into a single database transaction. This drastically reduces the number of
+// transactions, and therefore fsyncs, incurred when applying bursts of small
+// mutations, such as the graph updates received during the initial graph
+// sync.
+type batchScheduler struct {
+	db       *DB
+	interval time.Duration
+
+	mu    sync.Mutex
+	batch *batch
+}
+
+// newBatchScheduler creates a new batchScheduler which coalesces the
+// mutations arriving within each interval into a single transaction.
+func newBatchScheduler(db *DB, interval time.Duration) *batchScheduler {
+	return &batchScheduler{
+		db:       db,
+		interval: interval,
+	}
+}
+
+// execute adds the request to the pending batch, creating a new batch if
+// one isn't already pending, and blocks until the request has been applied.
+// Unless the request is lazy, the batch is committed right away along with
+// any lazy requests already pending.
+func (s *batchScheduler) execute(req *batchRequest) error {
+	req.errChan = make(chan error, 1)
+
+	s.mu.Lock()
+	if s.batch == nil {
+		s.batch = &batch{
+			db:    s.db,
+			clear: s.clear,
+		}
+		time.AfterFunc(s.interval, s.batch.trigger)
+	}
+	b := s.batch
+	b.reqs = append(b.reqs, req)
+	s.mu.Unlock()
+
+	if !req.lazy {
+		b.trigger()
+	}
+
+	return <-req.errChan
+}
+
+// clear removes the target batch as the pending batch, so that no further
+// requests are added to it.
+func (s *batchScheduler) clear(b *batch) {
+	s.mu.Lock()
+	if s.batch == b {
+		s.batch = nil
+	}
+	s.mu.Unlock()
+}
+
+// batch is a set of requests which are to be applied within a single
+// transaction.
+type batch struct {
+	db    *DB
+	reqs  []*batchRequest
+	clear func(b *batch)
+	start sync.Once
+}
+
+// trigger commits the batch, unless it has already been committed by either
+// the expiry of the batch commit interval or a request which isn't lazy.
+func (b *batch) trigger() {
+	b.start.Do(b.run)
+}
+
+// run applies all the requests within the batch within a single transaction.
+// If the transaction fails, then each request is retried within its own
+// transaction, so that a single failing request doesn't cause the others to
+// fail.
+func (b *batch) run() {
+	// Once the batch has been cleared, no further requests will be added
+	// to it, so it's safe to access the set of requests.
+	b.clear(b)
+
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		for _, req := range b.reqs {
+			if err := req.update(tx); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		for _, req := range b.reqs {
+			req.deliver(b.db.Update(req.update))
+		}
+		return
+	}
+
+	for _, req := range b.reqs {
+		req.deliver(nil)
+	}
+}
@@ -0,0 +1,191 @@
+package channeldb
+
+import (
+	"crypto/sha256"
+	"io/ioutil"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/roasbeef/btcd/wire"
+)
+
+// TestBatchedGraphUpdates asserts that graph updates which are coalesced into
+// a single transaction are all applied, and that each caller is delivered the
+// outcome of its own update, even if another update within the same batch
+// failed.
+func TestBatchedGraphUpdates(t *testing.T) {
+	t.Parallel()
+
+	tempDirName, err := ioutil.TempDir("", "channeldb")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDirName)
+
+	db, err := Open(
+		tempDirName, OptionSetBatchCommitInterval(50*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("unable to open db: %v", err)
+	}
+	defer db.Close()
+
+	graph := db.ChannelGraph()
+
+	node1, err := createTestVertex(db)
+	if err != nil {
+		t.Fatalf("unable to create node: %v", err)
+	}
+	node2, err := createTestVertex(db)
+	if err != nil {
+		t.Fatalf("unable to create node: %v", err)
+	}
+
+	// We'll concurrently add both nodes along with a series of channels
+	// between them, so that the updates are coalesced into a batch.
+	const numChannels = 20
+	edges := make([]*ChannelEdgeInfo, numChannels)
+	for i := 0; i < numChannels; i++ {
+		edges[i] = &ChannelEdgeInfo{
+			ChannelID:   uint64(i + 1),
+			ChainHash:   key,
+			NodeKey1:    node1.PubKey,
+			NodeKey2:    node2.PubKey,
+			BitcoinKey1: node1.PubKey,
+			BitcoinKey2: node2.PubKey,
+			ChannelPoint: wire.OutPoint{
+				Hash: sha256.Sum256([]byte{byte(i)}),
+			},
+			Capacity: 1000,
+		}
+	}
+
+	var wg sync.WaitGroup
+	errChan := make(chan error, numChannels+2)
+	for _, node := range []*LightningNode{node1, node2} {
+		wg.Add(1)
+		go func(node *LightningNode) {
+			defer wg.Done()
+			errChan <- graph.AddLightningNode(node, LazyAdd())
+		}(node)
+	}
+	for _, edge := range edges {
+		wg.Add(1)
+		go func(edge *ChannelEdgeInfo) {
+			defer wg.Done()
+			errChan <- graph.AddChannelEdge(edge, LazyAdd())
+		}(edge)
+	}
+	wg.Wait()
+	close(errChan)
+
+	for err := range errChan {
+		if err != nil {
+			t.Fatalf("unable to apply graph update: %v", err)
+		}
+	}
+
+	// Next, we'll concurrently re-add the first channel, add a policy
+	// for each of the channels, and add a policy for an unknown channel.
+	// The duplicate channel and unknown policy should only fail their own
+	// updates.
+	var dupErr, unknownErr error
+	policyErrs := make([]error, numChannels)
+
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		dupErr = graph.AddChannelEdge(edges[0], LazyAdd())
+	}()
+	go func() {
+		defer wg.Done()
+		policy := randEdgePolicy(numChannels+1, wire.OutPoint{}, db)
+		policy.Node = node2
+		policy.Signature = testSig
+		unknownErr = graph.UpdateEdgePolicy(policy, LazyAdd())
+	}()
+	for i, edge := range edges {
+		wg.Add(1)
+		go func(i int, edge *ChannelEdgeInfo) {
+			defer wg.Done()
+
+			policy := randEdgePolicy(
+				edge.ChannelID, edge.ChannelPoint, db,
+			)
+			policy.Node = node2
+			policy.Signature = testSig
+			policyErrs[i] = graph.UpdateEdgePolicy(
+				policy, LazyAdd(),
+			)
+		}(i, edge)
+	}
+	wg.Wait()
+
+	if dupErr != ErrEdgeAlreadyExist {
+		t.Fatalf("expected ErrEdgeAlreadyExist, got: %v", dupErr)
+	}
+	if unknownErr != ErrEdgeNotFound {
+		t.Fatalf("expected ErrEdgeNotFound, got: %v", unknownErr)
+	}
+	for i, err := range policyErrs {
+		if err != nil {
+			t.Fatalf("unable to update policy of channel %v: %v",
+				edges[i].ChannelID, err)
+		}
+	}
+
+	// Finally, each of the channels should be found within the graph
+	// along with the policy added above.
+	for _, edge := range edges {
+		_, policy1, _, err := graph.FetchChannelEdgesByID(
+			edge.ChannelID,
+		)
+		if err != nil {
+			t.Fatalf("unable to fetch channel %v: %v",
+				edge.ChannelID, err)
+		}
+		if policy1 == nil {
+			t.Fatalf("policy of channel %v not found",
+				edge.ChannelID)
+		}
+	}
+}
+
+// TestGraphUpdateNotLazy asserts that graph updates which aren't lazy are
+// committed right away, rather than waiting for the batch commit interval.
+func TestGraphUpdateNotLazy(t *testing.T) {
+	t.Parallel()
+
+	tempDirName, err := ioutil.TempDir("", "channeldb")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDirName)
+
+	db, err := Open(tempDirName, OptionSetBatchCommitInterval(time.Hour))
+	if err != nil {
+		t.Fatalf("unable to open db: %v", err)
+	}
+	defer db.Close()
+
+	node, err := createTestVertex(db)
+	if err != nil {
+		t.Fatalf("unable to create node: %v", err)
+	}
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- db.ChannelGraph().AddLightningNode(node)
+	}()
+
+	select {
+	case err := <-errChan:
+		if err != nil {
+			t.Fatalf("unable to add node: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("update wasn't committed before the batch interval")
+	}
+}
@@ -0,0 +1,104 @@
+package channeldb
+
+import (
+	"github.com/boltdb/bolt"
+	"github.com/roasbeef/btcd/btcec"
+)
+
+var (
+	// nodeBlacklistBucket is a top-level bucket which houses the set of
+	// nodes that the operator has instructed us to never route through.
+	// The keys of the bucket are the compressed public keys of the
+	// blacklisted nodes, and the value is a single byte which encodes the
+	// flags of the entry.
+	//
+	// maps: pubKey -> flags
+	nodeBlacklistBucket = []byte("node-blacklist")
+)
+
+const (
+	// blacklistAvoidDestFlag is set within the flags byte of a blacklist
+	// entry if the node should also be avoided as the final destination
+	// of a payment.
+	blacklistAvoidDestFlag byte = 1 << 0
+)
+
+// BlacklistedNode is an entry within the node blacklist. Nodes within the
+// blacklist will never be used as an intermediate hop when carrying out path
+// finding, and optionally may never be used as the destination of a payment.
+type BlacklistedNode struct {
+	// PubKey is the identity public key of the blacklisted node.
+	PubKey [33]byte
+
+	// AvoidAsDestination indicates whether we should also refuse to route
+	// payments which have this node as their final destination.
+	AvoidAsDestination bool
+}
+
+// AddBlacklistedNode adds a new node to the node blacklist. If the node is
+// already present within the blacklist, then its entry is overwritten with the
+// passed entry.
+func (c *ChannelGraph) AddBlacklistedNode(node *BlacklistedNode) error {
+	return c.db.Update(func(tx *bolt.Tx) error {
+		blacklist, err := tx.CreateBucketIfNotExists(nodeBlacklistBucket)
+		if err != nil {
+			return err
+		}
+
+		var flags byte
+		if node.AvoidAsDestination {
+			flags |= blacklistAvoidDestFlag
+		}
+
+		return blacklist.Put(node.PubKey[:], []byte{flags})
+	})
+}
+
+// RemoveBlacklistedNode removes the target node from the node blacklist. If
+// the node isn't found within the blacklist, then ErrNodeNotBlacklisted is
+// returned.
+func (c *ChannelGraph) RemoveBlacklistedNode(pub *btcec.PublicKey) error {
+	return c.db.Update(func(tx *bolt.Tx) error {
+		blacklist := tx.Bucket(nodeBlacklistBucket)
+		if blacklist == nil {
+			return ErrNodeNotBlacklisted
+		}
+
+		pubBytes := pub.SerializeCompressed()
+		if blacklist.Get(pubBytes) == nil {
+			return ErrNodeNotBlacklisted
+		}
+
+		return blacklist.Delete(pubBytes)
+	})
+}
+
+// FetchBlacklistedNodes returns all the nodes that are currently present
+// within the node blacklist. If the blacklist is empty, then a nil slice is
+// returned.
+func (c *ChannelGraph) FetchBlacklistedNodes() ([]*BlacklistedNode, error) {
+	var nodes []*BlacklistedNode
+	err := c.db.View(func(tx *bolt.Tx) error {
+		blacklist := tx.Bucket(nodeBlacklistBucket)
+		if blacklist == nil {
+			return nil
+		}
+
+		return blacklist.ForEach(func(k, v []byte) error {
+			node := &BlacklistedNode{}
+			copy(node.PubKey[:], k)
+
+			if len(v) > 0 {
+				node.AvoidAsDestination = v[0]&blacklistAvoidDestFlag != 0
+			}
+
+			nodes = append(nodes, node)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return nodes, nil
+}
@@ -0,0 +1,97 @@
+package channeldb
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/roasbeef/btcd/btcec"
+)
+
+// TestNodeBlacklist tests the add/fetch/remove operations of the node
+// blacklist.
+func TestNodeBlacklist(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+
+	graph := db.ChannelGraph()
+
+	// With no entries added, the blacklist should be empty.
+	nodes, err := graph.FetchBlacklistedNodes()
+	if err != nil {
+		t.Fatalf("unable to fetch blacklist: %v", err)
+	}
+	if len(nodes) != 0 {
+		t.Fatalf("expected empty blacklist, instead have %v entries",
+			len(nodes))
+	}
+
+	// Removing a node from an empty blacklist should fail.
+	_, pub1 := btcec.PrivKeyFromBytes(btcec.S256(), key[:])
+	if err := graph.RemoveBlacklistedNode(pub1); err != ErrNodeNotBlacklisted {
+		t.Fatalf("expected ErrNodeNotBlacklisted, instead got %v", err)
+	}
+
+	// We'll now add two entries, one of which should also be avoided as
+	// a destination.
+	entry1 := &BlacklistedNode{}
+	copy(entry1.PubKey[:], pub1.SerializeCompressed())
+
+	priv2, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+	entry2 := &BlacklistedNode{
+		AvoidAsDestination: true,
+	}
+	copy(entry2.PubKey[:], priv2.PubKey().SerializeCompressed())
+
+	if err := graph.AddBlacklistedNode(entry1); err != nil {
+		t.Fatalf("unable to add blacklist entry: %v", err)
+	}
+	if err := graph.AddBlacklistedNode(entry2); err != nil {
+		t.Fatalf("unable to add blacklist entry: %v", err)
+	}
+
+	nodes, err = graph.FetchBlacklistedNodes()
+	if err != nil {
+		t.Fatalf("unable to fetch blacklist: %v", err)
+	}
+	if len(nodes) != 2 {
+		t.Fatalf("expected 2 entries, instead have %v", len(nodes))
+	}
+	for _, node := range nodes {
+		var expected *BlacklistedNode
+		switch node.PubKey {
+		case entry1.PubKey:
+			expected = entry1
+		case entry2.PubKey:
+			expected = entry2
+		default:
+			t.Fatalf("unknown blacklist entry: %x", node.PubKey[:])
+		}
+
+		if !reflect.DeepEqual(node, expected) {
+			t.Fatalf("entries don't match: expected %v, got %v",
+				expected, node)
+		}
+	}
+
+	// Finally, removing the first entry should leave only the second
+	// within the blacklist.
+	if err := graph.RemoveBlacklistedNode(pub1); err != nil {
+		t.Fatalf("unable to remove blacklist entry: %v", err)
+	}
+	nodes, err = graph.FetchBlacklistedNodes()
+	if err != nil {
+		t.Fatalf("unable to fetch blacklist: %v", err)
+	}
+	if len(nodes) != 1 || !reflect.DeepEqual(nodes[0], entry2) {
+		t.Fatalf("expected only second entry to remain, instead "+
+			"have: %v", nodes)
+	}
+}
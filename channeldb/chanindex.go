@@ -0,0 +1,96 @@
+package channeldb
+
+import (
+	"bytes"
+
+	"github.com/boltdb/bolt"
+	"github.com/roasbeef/btcd/wire"
+)
+
+var (
+	// outpointIndexBucket is a top-level bucket which maps the funding
+	// outpoint of each open channel to the public key of the node the
+	// channel is with, which is the key of the bucket within the
+	// openChannelBucket that houses the channel. This allows a channel to
+	// be fetched by its funding outpoint without scanning all open
+	// channels.
+	//
+	// maps: outPoint -> nodePub
+	outpointIndexBucket = []byte("oib")
+)
+
+// FetchChannel returns the open channel with the passed funding outpoint. If
+// no such channel exists, then ErrChannelNotFound is returned.
+func (d *DB) FetchChannel(chanPoint wire.OutPoint) (*OpenChannel, error) {
+	var channel *OpenChannel
+	err := d.View(func(tx *bolt.Tx) error {
+		var b bytes.Buffer
+		if err := writeOutpoint(&b, &chanPoint); err != nil {
+			return err
+		}
+
+		var err error
+		channel, err = fetchIndexedChannel(tx, b.Bytes())
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	channel.Db = d
+	return channel, nil
+}
+
+// fetchIndexedChannel fetches the open channel with the passed serialized
+// funding outpoint, using the outpoint index to locate the bucket of the node
+// the channel is with.
+func fetchIndexedChannel(tx *bolt.Tx, outpointBytes []byte) (*OpenChannel,
+	error) {
+
+	outpointIndex := tx.Bucket(outpointIndexBucket)
+	openChanBucket := tx.Bucket(openChannelBucket)
+	if outpointIndex == nil || openChanBucket == nil {
+		return nil, ErrChannelNotFound
+	}
+
+	nodePub := outpointIndex.Get(outpointBytes)
+	if nodePub == nil {
+		return nil, ErrChannelNotFound
+	}
+	nodeChanBucket := openChanBucket.Bucket(nodePub)
+	if nodeChanBucket == nil {
+		return nil, ErrChannelNotFound
+	}
+
+	var chanPoint wire.OutPoint
+	err := readOutpoint(bytes.NewReader(outpointBytes), &chanPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return fetchOpenChannel(openChanBucket, nodeChanBucket, &chanPoint)
+}
+
+// putChanIndex adds the channel with the passed serialized funding outpoint
+// to the outpoint index.
+func putChanIndex(tx *bolt.Tx, channel *OpenChannel,
+	outpointBytes []byte) error {
+
+	outpointIndex, err := tx.CreateBucketIfNotExists(outpointIndexBucket)
+	if err != nil {
+		return err
+	}
+	nodePub := channel.IdentityPub.SerializeCompressed()
+	return outpointIndex.Put(outpointBytes, nodePub)
+}
+
+// delChanIndex removes the channel with the passed serialized funding
+// outpoint from the outpoint index.
+func delChanIndex(tx *bolt.Tx, outpointBytes []byte) error {
+	outpointIndex := tx.Bucket(outpointIndexBucket)
+	if outpointIndex == nil {
+		return nil
+	}
+
+	return outpointIndex.Delete(outpointBytes)
+}
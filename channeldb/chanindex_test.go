@@ -0,0 +1,109 @@
+package channeldb
+
+import (
+	"net"
+	"testing"
+
+	"github.com/boltdb/bolt"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// assertFetchChannel asserts that the channel can be fetched by its funding
+// outpoint.
+func assertFetchChannel(t *testing.T, cdb *DB, chanPoint wire.OutPoint) {
+	channel, err := cdb.FetchChannel(chanPoint)
+	if err != nil {
+		t.Fatalf("unable to fetch channel by outpoint: %v", err)
+	}
+	if channel.FundingOutpoint != chanPoint {
+		t.Fatalf("expected channel %v, got %v", chanPoint,
+			channel.FundingOutpoint)
+	}
+}
+
+// TestChannelIndex tests that open channels can be fetched by their funding
+// outpoint over the course of their lifetime.
+func TestChannelIndex(t *testing.T) {
+	t.Parallel()
+
+	cdb, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+
+	// Initially, the index doesn't know of the channel.
+	state, err := createTestChannelState(cdb)
+	if err != nil {
+		t.Fatalf("unable to create channel state: %v", err)
+	}
+	_, err = cdb.FetchChannel(state.FundingOutpoint)
+	if err != ErrChannelNotFound {
+		t.Fatalf("expected ErrChannelNotFound, got %v", err)
+	}
+
+	// A pending channel is indexed as soon as it's synced.
+	addr := &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 18555}
+	if err := state.SyncPending(addr, 99); err != nil {
+		t.Fatalf("unable to save channel state: %v", err)
+	}
+	assertFetchChannel(t, cdb, state.FundingOutpoint)
+
+	// It should remain indexed once marked as open.
+	shortChanID := lnwire.ShortChannelID{BlockHeight: 100, TxIndex: 2}
+	err = cdb.MarkChannelAsOpen(&state.FundingOutpoint, shortChanID)
+	if err != nil {
+		t.Fatalf("unable to mark channel as open: %v", err)
+	}
+	assertFetchChannel(t, cdb, state.FundingOutpoint)
+
+	// Once closed, the channel should be removed from the index.
+	closeSummary := &ChannelCloseSummary{
+		ChanPoint: state.FundingOutpoint,
+		RemotePub: state.IdentityPub,
+		CloseType: CooperativeClose,
+	}
+	if err := state.CloseChannel(closeSummary); err != nil {
+		t.Fatalf("unable to close channel: %v", err)
+	}
+	_, err = cdb.FetchChannel(state.FundingOutpoint)
+	if err != ErrChannelNotFound {
+		t.Fatalf("expected ErrChannelNotFound, got %v", err)
+	}
+}
+
+// TestMigrateChannelIndex checks that the outpoint index is rebuilt from the
+// existing open channels.
+func TestMigrateChannelIndex(t *testing.T) {
+	t.Parallel()
+
+	cdb, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+
+	state, err := createTestChannelState(cdb)
+	if err != nil {
+		t.Fatalf("unable to create channel state: %v", err)
+	}
+	addr := &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 18555}
+	if err := state.SyncPending(addr, 99); err != nil {
+		t.Fatalf("unable to save channel state: %v", err)
+	}
+
+	// Remove the index to simulate a database created before it was
+	// introduced, then run the migration to rebuild it.
+	err = cdb.Update(func(tx *bolt.Tx) error {
+		return tx.DeleteBucket(outpointIndexBucket)
+	})
+	if err != nil {
+		t.Fatalf("unable to delete channel index: %v", err)
+	}
+	if err := cdb.Update(migrateChannelIndex); err != nil {
+		t.Fatalf("unable to migrate channel index: %v", err)
+	}
+
+	assertFetchChannel(t, cdb, state.FundingOutpoint)
+}
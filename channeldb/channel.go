@@ -6,11 +6,13 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"strings"
 	"sync"
 
 	"github.com/boltdb/bolt"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/shachain"
+	"github.com/lightningnetwork/lnd/tlv"
 	"github.com/roasbeef/btcd/btcec"
 	"github.com/roasbeef/btcd/chaincfg/chainhash"
 	"github.com/roasbeef/btcd/wire"
@@ -70,6 +72,7 @@ var (
 	commitFeePrefix    = []byte("cfp")
 	isPendingPrefix    = []byte("pdg")
 	confInfoPrefix     = []byte("conf-info")
+	chanStatusPrefix   = []byte("cst")
 
 	// chanIDKey stores the node, and channelID for an active channel.
 	chanIDKey = []byte("cik")
@@ -98,6 +101,12 @@ var (
 	revocationStateKey = []byte("esk")
 )
 
+// confInfoLen is the length of the value stored under the confInfoPrefix:
+// broadcast height (4) || short channel ID (8) || confirmation height (4) ||
+// funding fee (8). Channels created before the last two fields were added
+// store only the first 12 bytes.
+const confInfoLen = 24
+
 // ChannelType is an enum-like type that describes one of several possible
 // channel types. Each open channel is associated with a particular type as the
 // channel type may determine how higher level operations are conducted such as
@@ -119,6 +128,75 @@ const (
 	DualFunder = 1
 )
 
+// ChannelStatus is a bit vector used to indicate whether an OpenChannel is in
+// the default usable state, or some other "exceptional" state. Multiple
+// statuses may be applied to a channel at once.
+type ChannelStatus uint8
+
+const (
+	// NOTE: iota isn't used here for this enum needs to be stable
+	// long-term as it will be persisted to the database.
+
+	// ChanStatusDefault is the normal state of an open channel.
+	ChanStatusDefault ChannelStatus = 0
+
+	// ChanStatusBorked indicates that the channel has entered an
+	// irreconcilable state, triggered by a state desynchronization or
+	// channel breach. Channels in this state should never be added to the
+	// htlc switch, and no further updates to their state are permitted.
+	ChanStatusBorked ChannelStatus = 1
+
+	// ChanStatusCommitBroadcasted indicates that a commitment for this
+	// channel has been broadcast.
+	ChanStatusCommitBroadcasted ChannelStatus = 2
+
+	// ChanStatusLocalDataLoss indicates that we have lost channel state
+	// for this channel, and broadcasting our latest commitment might be
+	// considered a breach.
+	ChanStatusLocalDataLoss ChannelStatus = 4
+)
+
+// chanStatusStrings maps a ChannelStatus to a human friendly string that
+// describes that status.
+var chanStatusStrings = map[ChannelStatus]string{
+	ChanStatusDefault:           "ChanStatusDefault",
+	ChanStatusBorked:            "ChanStatusBorked",
+	ChanStatusCommitBroadcasted: "ChanStatusCommitBroadcasted",
+	ChanStatusLocalDataLoss:     "ChanStatusLocalDataLoss",
+}
+
+// orderedChanStatusFlags is an in-order list of all the non-default channel
+// status flags.
+var orderedChanStatusFlags = []ChannelStatus{
+	ChanStatusBorked,
+	ChanStatusCommitBroadcasted,
+	ChanStatusLocalDataLoss,
+}
+
+// String returns a human-readable representation of the ChannelStatus, with
+// each set flag separated by a pipe.
+func (c ChannelStatus) String() string {
+	if c == ChanStatusDefault {
+		return chanStatusStrings[ChanStatusDefault]
+	}
+
+	var statuses []string
+	for _, flag := range orderedChanStatusFlags {
+		if c&flag == flag {
+			statuses = append(statuses, chanStatusStrings[flag])
+			c &^= flag
+		}
+	}
+
+	// Any remaining bits are unknown flags, which we'll print in hex so
+	// they aren't silently dropped.
+	if c != 0 {
+		statuses = append(statuses, fmt.Sprintf("%#x", uint8(c)))
+	}
+
+	return strings.Join(statuses, "|")
+}
+
 // ChannelConstraints represents a set of constraints meant to allow a node to
 // limit their exposure, enact flow control and ensure that all HTLC's are
 // economically relevant This struct will be mirrored for both sides of the
@@ -135,7 +213,7 @@ type ChannelConstraints struct {
 	// MaxPendingAmount is the maximum pending HTLC value that can be
 	// present within the channel at a particular time. This value is set
 	// by the initiator of the channel and must be upheld at all times.
-	MaxPendingAmount lnwire.MilliSatoshi
+	MaxPendingAmount lnwire.MilliAtom
 
 	// ChanReserve is an absolute reservation on the channel for this
 	// particular node. This means that the current settled balance for
@@ -151,7 +229,7 @@ type ChannelConstraints struct {
 	// If any HTLC's below this amount are offered, then the HTLC will be
 	// rejected. This, in tandem with the dust limit allows a node to
 	// regulate the smallest HTLC that it deems economically relevant.
-	MinHTLC lnwire.MilliSatoshi
+	MinHTLC lnwire.MilliAtom
 
 	// MaxAcceptedHtlcs is the maximum amount of HTLC's that are to be
 	// accepted by the owner of this set of constraints. This allows each
@@ -238,6 +316,10 @@ type OpenChannel struct {
 	// confirmed.
 	IsPending bool
 
+	// ChanStatus is the current status of this channel. If it is not in
+	// the state Default, it should not be used for forwarding payments.
+	ChanStatus ChannelStatus
+
 	// IsInitiator is a bool which indicates if we were the original
 	// initiator for the channel. This value may affect how higher levels
 	// negotiate fees, or close the channel.
@@ -249,6 +331,16 @@ type OpenChannel struct {
 	// been confirmed before a certain height.
 	FundingBroadcastHeight uint32
 
+	// FundingConfirmationHeight is the height at which the funding
+	// transaction reached the required number of confirmations and the
+	// channel was marked open. This is zero while the channel is pending.
+	FundingConfirmationHeight uint32
+
+	// FundingFee is the on-chain fee we paid for the funding transaction.
+	// This is zero if the remote node initiated the channel, as the fee
+	// is paid entirely by the initiator.
+	FundingFee btcutil.Amount
+
 	// IdentityPub is the identity public key of the remote node this
 	// channel has been established with.
 	IdentityPub *btcec.PublicKey
@@ -270,11 +362,11 @@ type OpenChannel struct {
 
 	// LocalBalance is the current available settled balance within the
 	// channel directly spendable by us.
-	LocalBalance lnwire.MilliSatoshi
+	LocalBalance lnwire.MilliAtom
 
 	// RemoteBalance is the current available settled balance within the
 	// channel directly spendable by the remote node.
-	RemoteBalance lnwire.MilliSatoshi
+	RemoteBalance lnwire.MilliAtom
 
 	// CommitFee is the amount calculated to be paid in fees for the
 	// current set of commitment transactions. The fee amount is persisted
@@ -298,6 +390,22 @@ type OpenChannel struct {
 	// for normal transactional use.
 	NumConfsRequired uint16
 
+	// UpfrontShutdownScript is the script to which our funds are paid
+	// upon a cooperative close, as committed to when the channel was
+	// funded. If empty, then no such commitment was made.
+	UpfrontShutdownScript []byte
+
+	// RemoteUpfrontShutdownScript is the script to which the remote
+	// party's funds must be paid upon a cooperative close, as committed to
+	// by them when the channel was funded. If empty, then they made no
+	// such commitment.
+	RemoteUpfrontShutdownScript []byte
+
+	// LeaseExpiry is the absolute block height until which the funds of
+	// the channel have been leased to the remote party. If zero, then the
+	// channel isn't leased.
+	LeaseExpiry uint32
+
 	// RemoteCurrentRevocation is the current revocation for their
 	// commitment transaction. However, since this the derived public key,
 	// we don't yet have the private key so we aren't yet able to verify
@@ -327,11 +435,11 @@ type OpenChannel struct {
 
 	// TotalMSatSent is the total number of milli-satoshis we've sent
 	// within this channel.
-	TotalMSatSent lnwire.MilliSatoshi
+	TotalMSatSent lnwire.MilliAtom
 
 	// TotalMSatReceived is the total number of milli-satoshis we've
 	// received within this channel.
-	TotalMSatReceived lnwire.MilliSatoshi
+	TotalMSatReceived lnwire.MilliAtom
 
 	// Htlcs is the list of active, uncleared HTLCs currently pending
 	// within the channel.
@@ -389,6 +497,12 @@ func (c *OpenChannel) fullSync(tx *bolt.Tx) error {
 		}
 	}
 
+	// Similarly, add the channel to the global outpoint index which
+	// allows it to be fetched directly.
+	if err := putChanIndex(tx, c, b.Bytes()); err != nil {
+		return err
+	}
+
 	return putOpenChannel(chanBucket, nodeChanBucket, c)
 }
 
@@ -438,6 +552,60 @@ func (c *OpenChannel) SyncPending(addr *net.TCPAddr, pendingHeight uint32) error
 	})
 }
 
+// HasChanStatus returns true if the channel has the passed status flag set.
+func (c *OpenChannel) HasChanStatus(status ChannelStatus) bool {
+	c.RLock()
+	defer c.RUnlock()
+
+	return c.hasChanStatus(status)
+}
+
+func (c *OpenChannel) hasChanStatus(status ChannelStatus) bool {
+	// Special case ChanStatusDefault since it isn't actually a flag, but
+	// a particular combination (or lack-there-of) of flags.
+	if status == ChanStatusDefault {
+		return c.ChanStatus == ChanStatusDefault
+	}
+
+	return c.ChanStatus&status == status
+}
+
+// ApplyChanStatus applies the passed status flag to the channel, persisting
+// the resulting status to disk. Flags which have already been applied are
+// left untouched, and status flags can never be cleared.
+func (c *OpenChannel) ApplyChanStatus(status ChannelStatus) error {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.Db.Update(func(tx *bolt.Tx) error {
+		chanBucket, err := tx.CreateBucketIfNotExists(openChannelBucket)
+		if err != nil {
+			return err
+		}
+
+		newStatus := c.ChanStatus | status
+		if err := putChanStatus(chanBucket, &c.FundingOutpoint,
+			newStatus); err != nil {
+			return err
+		}
+
+		c.ChanStatus = newStatus
+		return nil
+	})
+}
+
+// MarkBorked marks the channel as having entered an irreconcilable state,
+// preventing any further updates to its state.
+func (c *OpenChannel) MarkBorked() error {
+	return c.ApplyChanStatus(ChanStatusBorked)
+}
+
+// MarkCommitmentBroadcasted marks the channel as having broadcast one of its
+// commitment transactions.
+func (c *OpenChannel) MarkCommitmentBroadcasted() error {
+	return c.ApplyChanStatus(ChanStatusCommitBroadcasted)
+}
+
 // UpdateCommitment updates the on-disk state of our currently broadcastable
 // commitment state. This method is to be called once we have revoked our prior
 // commitment state, accepting the new state as defined by the passed
@@ -448,6 +616,10 @@ func (c *OpenChannel) UpdateCommitment(newCommitment *wire.MsgTx,
 	c.Lock()
 	defer c.Unlock()
 
+	if c.hasChanStatus(ChanStatusBorked) {
+		return ErrChanBorked
+	}
+
 	return c.Db.Update(func(tx *bolt.Tx) error {
 		chanBucket, err := tx.CreateBucketIfNotExists(openChannelBucket)
 		if err != nil {
@@ -519,7 +691,7 @@ type HTLC struct {
 	RHash [32]byte
 
 	// Amt is the amount of milli-satoshis this HTLC escrows.
-	Amt lnwire.MilliSatoshi
+	Amt lnwire.MilliAtom
 
 	// RefundTimeout is the absolute timeout on the HTLC that the sender
 	// must wait before reclaiming the funds in limbo.
@@ -557,11 +729,11 @@ func (h *HTLC) Copy() HTLC {
 type ChannelDelta struct {
 	// LocalBalance is our current balance at this particular update
 	// number.
-	LocalBalance lnwire.MilliSatoshi
+	LocalBalance lnwire.MilliAtom
 
 	// RemoteBalanceis the balance of the remote node at this particular
 	// update number.
-	RemoteBalance lnwire.MilliSatoshi
+	RemoteBalance lnwire.MilliAtom
 
 	// CommitFee is the fee that has been subtracted from the channel
 	// initiator's balance at this point in the commitment chain.
@@ -593,6 +765,10 @@ func (c *OpenChannel) InsertNextRevocation(revKey *btcec.PublicKey) error {
 	c.Lock()
 	defer c.Unlock()
 
+	if c.hasChanStatus(ChanStatusBorked) {
+		return ErrChanBorked
+	}
+
 	return c.Db.Update(func(tx *bolt.Tx) error {
 		chanBucket, err := tx.CreateBucketIfNotExists(openChannelBucket)
 		if err != nil {
@@ -616,6 +792,13 @@ func (c *OpenChannel) InsertNextRevocation(revKey *btcec.PublicKey) error {
 // this log can be consulted in order to reconstruct the state needed to
 // rectify the situation.
 func (c *OpenChannel) AppendToRevocationLog(delta *ChannelDelta) error {
+	c.RLock()
+	borked := c.hasChanStatus(ChanStatusBorked)
+	c.RUnlock()
+	if borked {
+		return ErrChanBorked
+	}
+
 	return c.Db.Update(func(tx *bolt.Tx) error {
 		chanBucket, err := tx.CreateBucketIfNotExists(openChannelBucket)
 		if err != nil {
@@ -683,13 +866,21 @@ func (c *OpenChannel) RevocationLogTail() (*ChannelDelta, error) {
 		// store the update number on disk in a big-endian format,
 		// this'll retrieve the latest entry.
 		cursor := logBucket.Cursor()
-		_, tailLogEntry := cursor.Last()
+		tailLogKey, tailLogEntry := cursor.Last()
+		if tailLogKey == nil {
+			return ErrNoPastDeltas
+		}
 		logEntryReader := bytes.NewReader(tailLogEntry)
 
 		// Once we have the entry, we'll decode it into the channel
-		// delta pointer we created above.
+		// delta pointer we created above. The update number of the
+		// entry is stored within the trailing bytes of its key.
+		updateNum := byteOrder.Uint64(tailLogKey[len(tailLogKey)-8:])
+
 		var dbErr error
-		delta, dbErr = deserializeChannelDelta(logEntryReader)
+		delta, dbErr = deserializeRevocationLogEntry(
+			logEntryReader, updateNum,
+		)
 		if dbErr != nil {
 			return dbErr
 		}
@@ -839,9 +1030,73 @@ type ChannelCloseSummary struct {
 	// funds have been swept.
 	IsPending bool
 
+	// HtlcResolutions details how each of the HTLC's that were still
+	// pending on the commitment transaction which closed the channel are
+	// to be resolved on-chain.
+	HtlcResolutions []HtlcResolutionSummary
+
 	// TODO(roasbeef): also store short_chan_id?
 }
 
+// HtlcOutcome is an enum which details how an HTLC that was still pending at
+// the time of channel closure is ultimately resolved.
+type HtlcOutcome uint8
+
+const (
+	// NOTE: iota isn't used here for this enum needs to be stable
+	// long-term as it will be persisted to the database.
+
+	// HtlcOutcomeTimeout indicates that the HTLC was offered by us, and
+	// will be swept back into our wallet once its timeout has passed.
+	HtlcOutcomeTimeout HtlcOutcome = 0
+
+	// HtlcOutcomeUnclaimed indicates that the HTLC was offered to us, but
+	// won't be claimed on-chain. As a result, the remote party will sweep
+	// it once its timeout has passed.
+	HtlcOutcomeUnclaimed HtlcOutcome = 1
+
+	// HtlcOutcomeDust indicates that the HTLC was trimmed from the
+	// commitment transaction as its value was below the dust limit. The
+	// value of the HTLC was instead paid to the miners as fees.
+	HtlcOutcomeDust HtlcOutcome = 2
+)
+
+// String returns a human readable version of the target HtlcOutcome.
+func (h HtlcOutcome) String() string {
+	switch h {
+	case HtlcOutcomeTimeout:
+		return "Timeout"
+	case HtlcOutcomeUnclaimed:
+		return "Unclaimed"
+	case HtlcOutcomeDust:
+		return "Dust"
+	default:
+		return "Unknown"
+	}
+}
+
+// HtlcResolutionSummary is a compact summary of an HTLC which was still
+// pending at the time of channel closure, along with the manner in which it
+// is to be resolved.
+type HtlcResolutionSummary struct {
+	// RHash is the payment hash of the HTLC.
+	RHash [32]byte
+
+	// Amt is the amount of milli-satoshis the HTLC was for.
+	Amt lnwire.MilliAtom
+
+	// Incoming denotes whether the HTLC was offered to us, or offered by
+	// us.
+	Incoming bool
+
+	// RefundTimeout is the absolute timeout on the HTLC that the sender
+	// must wait before reclaiming the funds in limbo.
+	RefundTimeout uint32
+
+	// Outcome details how the HTLC is resolved on-chain.
+	Outcome HtlcOutcome
+}
+
 // CloseChannel closes a previously active lightning channel. Closing a channel
 // entails deleting all saved state within the database concerning this
 // channel. This method also takes a struct that summarizes the state of the
@@ -890,6 +1145,11 @@ func (c *OpenChannel) CloseChannel(summary *ChannelCloseSummary) error {
 			return err
 		}
 
+		// Along with the global outpoint index.
+		if err := delChanIndex(tx, outPointBytes); err != nil {
+			return err
+		}
+
 		// Now that the index to this channel has been deleted, purge
 		// the remaining channel metadata from the database.
 		if err := deleteOpenChannel(chanBucket, nodeChanBucket,
@@ -922,13 +1182,13 @@ type ChannelSnapshot struct {
 	ChannelPoint *wire.OutPoint
 
 	Capacity      btcutil.Amount
-	LocalBalance  lnwire.MilliSatoshi
-	RemoteBalance lnwire.MilliSatoshi
+	LocalBalance  lnwire.MilliAtom
+	RemoteBalance lnwire.MilliAtom
 
 	NumUpdates uint64
 
-	TotalMilliSatoshisSent     lnwire.MilliSatoshi
-	TotalMilliSatoshisReceived lnwire.MilliSatoshi
+	TotalMilliSatoshisSent     lnwire.MilliAtom
+	TotalMilliSatoshisReceived lnwire.MilliAtom
 
 	Htlcs []HTLC
 }
@@ -1008,6 +1268,28 @@ func serializeChannelCloseSummary(w io.Writer, cs *ChannelCloseSummary) error {
 		return err
 	}
 
+	numHtlcs := uint16(len(cs.HtlcResolutions))
+	if err := binary.Write(w, byteOrder, numHtlcs); err != nil {
+		return err
+	}
+	for _, htlc := range cs.HtlcResolutions {
+		if _, err := w.Write(htlc.RHash[:]); err != nil {
+			return err
+		}
+		if err := binary.Write(w, byteOrder, htlc.Amt); err != nil {
+			return err
+		}
+		if err := writeBool(w, htlc.Incoming); err != nil {
+			return err
+		}
+		if err := binary.Write(w, byteOrder, htlc.RefundTimeout); err != nil {
+			return err
+		}
+		if _, err := w.Write([]byte{byte(htlc.Outcome)}); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -1069,6 +1351,43 @@ func deserializeCloseChannelSummary(r io.Reader) (*ChannelCloseSummary, error) {
 		return nil, err
 	}
 
+	// Summaries written before HTLC resolutions were tracked end directly
+	// after the remote public key, so we'll treat a missing count as no
+	// HTLC's at all.
+	var numHtlcs uint16
+	err = binary.Read(r, byteOrder, &numHtlcs)
+	switch {
+	case err == io.EOF:
+		return c, nil
+	case err != nil:
+		return nil, err
+	}
+
+	if numHtlcs > 0 {
+		c.HtlcResolutions = make([]HtlcResolutionSummary, numHtlcs)
+	}
+	for i := uint16(0); i < numHtlcs; i++ {
+		htlc := &c.HtlcResolutions[i]
+		if _, err := io.ReadFull(r, htlc.RHash[:]); err != nil {
+			return nil, err
+		}
+		if err := binary.Read(r, byteOrder, &htlc.Amt); err != nil {
+			return nil, err
+		}
+		htlc.Incoming, err = readBool(r)
+		if err != nil {
+			return nil, err
+		}
+		if err := binary.Read(r, byteOrder, &htlc.RefundTimeout); err != nil {
+			return nil, err
+		}
+		var outcome [1]byte
+		if _, err := io.ReadFull(r, outcome[:]); err != nil {
+			return nil, err
+		}
+		htlc.Outcome = HtlcOutcome(outcome[0])
+	}
+
 	return c, nil
 }
 
@@ -1096,6 +1415,12 @@ func putOpenChannel(openChanBucket *bolt.Bucket, nodeChanBucket *bolt.Bucket,
 	if err := putChanIsPending(openChanBucket, channel); err != nil {
 		return err
 	}
+	err := putChanStatus(
+		openChanBucket, &channel.FundingOutpoint, channel.ChanStatus,
+	)
+	if err != nil {
+		return err
+	}
 	if err := putChanConfInfo(openChanBucket, channel); err != nil {
 		return err
 	}
@@ -1177,6 +1502,9 @@ func fetchOpenChannel(openChanBucket *bolt.Bucket, nodeChanBucket *bolt.Bucket,
 	if err = fetchChanIsPending(openChanBucket, channel); err != nil {
 		return nil, err
 	}
+	if err = fetchChanStatus(openChanBucket, channel); err != nil {
+		return nil, err
+	}
 	if err := fetchChanConfInfo(openChanBucket, channel); err != nil {
 		return nil, err
 	}
@@ -1207,6 +1535,9 @@ func deleteOpenChannel(openChanBucket *bolt.Bucket, nodeChanBucket *bolt.Bucket,
 	if err := deleteChanIsPending(openChanBucket, channelID); err != nil {
 		return err
 	}
+	if err := deleteChanStatus(openChanBucket, channelID); err != nil {
+		return err
+	}
 	if err := deleteChanConfInfo(openChanBucket, channelID); err != nil {
 		return err
 	}
@@ -1303,11 +1634,11 @@ func fetchChanCapacity(openChanBucket *bolt.Bucket, channel *OpenChannel) error
 
 	copy(keyPrefix[:3], selfBalancePrefix)
 	selfBalanceBytes := openChanBucket.Get(keyPrefix)
-	channel.LocalBalance = lnwire.MilliSatoshi(byteOrder.Uint64(selfBalanceBytes))
+	channel.LocalBalance = lnwire.MilliAtom(byteOrder.Uint64(selfBalanceBytes))
 
 	copy(keyPrefix[:3], theirBalancePrefix)
 	theirBalanceBytes := openChanBucket.Get(keyPrefix)
-	channel.RemoteBalance = lnwire.MilliSatoshi(byteOrder.Uint64(theirBalanceBytes))
+	channel.RemoteBalance = lnwire.MilliAtom(byteOrder.Uint64(theirBalanceBytes))
 
 	return nil
 }
@@ -1437,11 +1768,11 @@ func fetchChanAmountsTransferred(openChanBucket *bolt.Bucket, channel *OpenChann
 
 	copy(keyPrefix[:3], satSentPrefix)
 	totalSentBytes := openChanBucket.Get(keyPrefix)
-	channel.TotalMSatSent = lnwire.MilliSatoshi(byteOrder.Uint64(totalSentBytes))
+	channel.TotalMSatSent = lnwire.MilliAtom(byteOrder.Uint64(totalSentBytes))
 
 	copy(keyPrefix[:3], satReceivedPrefix)
 	totalReceivedBytes := openChanBucket.Get(keyPrefix)
-	channel.TotalMSatReceived = lnwire.MilliSatoshi(byteOrder.Uint64(totalReceivedBytes))
+	channel.TotalMSatReceived = lnwire.MilliAtom(byteOrder.Uint64(totalReceivedBytes))
 
 	return nil
 }
@@ -1494,6 +1825,51 @@ func fetchChanIsPending(openChanBucket *bolt.Bucket, channel *OpenChannel) error
 	return nil
 }
 
+func putChanStatus(openChanBucket *bolt.Bucket, chanPoint *wire.OutPoint,
+	status ChannelStatus) error {
+
+	var b bytes.Buffer
+	if err := writeOutpoint(&b, chanPoint); err != nil {
+		return err
+	}
+
+	keyPrefix := make([]byte, len(chanStatusPrefix)+b.Len())
+	copy(keyPrefix[:len(chanStatusPrefix)], chanStatusPrefix)
+	copy(keyPrefix[len(chanStatusPrefix):], b.Bytes())
+
+	return openChanBucket.Put(keyPrefix, []byte{byte(status)})
+}
+
+func deleteChanStatus(openChanBucket *bolt.Bucket, chanID []byte) error {
+	keyPrefix := make([]byte, len(chanStatusPrefix)+len(chanID))
+	copy(keyPrefix[:len(chanStatusPrefix)], chanStatusPrefix)
+	copy(keyPrefix[len(chanStatusPrefix):], chanID)
+	return openChanBucket.Delete(keyPrefix)
+}
+
+func fetchChanStatus(openChanBucket *bolt.Bucket, channel *OpenChannel) error {
+	var b bytes.Buffer
+	if err := writeOutpoint(&b, &channel.FundingOutpoint); err != nil {
+		return err
+	}
+
+	keyPrefix := make([]byte, len(chanStatusPrefix)+b.Len())
+	copy(keyPrefix[:len(chanStatusPrefix)], chanStatusPrefix)
+	copy(keyPrefix[len(chanStatusPrefix):], b.Bytes())
+
+	// Channels created before the status was introduced won't have it
+	// stored, in which case they're in the default state.
+	statusBytes := openChanBucket.Get(keyPrefix)
+	if len(statusBytes) == 0 {
+		channel.ChanStatus = ChanStatusDefault
+		return nil
+	}
+
+	channel.ChanStatus = ChannelStatus(statusBytes[0])
+
+	return nil
+}
+
 func putChanConfInfo(openChanBucket *bolt.Bucket, channel *OpenChannel) error {
 	var b bytes.Buffer
 	if err := writeOutpoint(&b, &channel.FundingOutpoint); err != nil {
@@ -1504,10 +1880,13 @@ func putChanConfInfo(openChanBucket *bolt.Bucket, channel *OpenChannel) error {
 	copy(keyPrefix[:len(confInfoPrefix)], confInfoPrefix)
 	copy(keyPrefix[len(confInfoPrefix):], b.Bytes())
 
-	// We store the conf info in the following format: broadcast || open.
-	var scratch [12]byte
-	byteOrder.PutUint32(scratch[:], channel.FundingBroadcastHeight)
-	byteOrder.PutUint64(scratch[4:], channel.ShortChanID.ToUint64())
+	// We store the conf info in the following format:
+	// broadcast || open || confirmation height || funding fee.
+	var scratch [confInfoLen]byte
+	byteOrder.PutUint32(scratch[:4], channel.FundingBroadcastHeight)
+	byteOrder.PutUint64(scratch[4:12], channel.ShortChanID.ToUint64())
+	byteOrder.PutUint32(scratch[12:16], channel.FundingConfirmationHeight)
+	byteOrder.PutUint64(scratch[16:], uint64(channel.FundingFee))
 
 	return openChanBucket.Put(keyPrefix, scratch[:])
 }
@@ -1525,7 +1904,23 @@ func fetchChanConfInfo(openChanBucket *bolt.Bucket, channel *OpenChannel) error
 	confInfoBytes := openChanBucket.Get(keyPrefix)
 	channel.FundingBroadcastHeight = byteOrder.Uint32(confInfoBytes[:4])
 	channel.ShortChanID = lnwire.NewShortChanIDFromInt(
-		byteOrder.Uint64(confInfoBytes[4:]),
+		byteOrder.Uint64(confInfoBytes[4:12]),
+	)
+
+	// Channels created before the opening metadata was recorded only
+	// store the broadcast height and short channel ID. For those, the
+	// confirmation height is that of the block the funding transaction
+	// was included in, and the funding fee is unknown.
+	if len(confInfoBytes) < confInfoLen {
+		channel.FundingConfirmationHeight = channel.ShortChanID.BlockHeight
+		return nil
+	}
+
+	channel.FundingConfirmationHeight = byteOrder.Uint32(
+		confInfoBytes[12:16],
+	)
+	channel.FundingFee = btcutil.Amount(
+		byteOrder.Uint64(confInfoBytes[16:]),
 	)
 
 	return nil
@@ -1873,9 +2268,74 @@ func putChanFundingInfo(nodeChanBucket *bolt.Bucket, channel *OpenChannel) error
 		return err
 	}
 
+	// Finally, the optional fields follow as a TLV stream, which allows
+	// new fields to be added without migrating existing channels.
+	if err := chanFundingTLVStream(channel).Encode(&b); err != nil {
+		return err
+	}
+
 	return nodeChanBucket.Put(fundTxnKey, b.Bytes())
 }
 
+const (
+	// upfrontShutdownScriptType is the TLV type of the channel's upfront
+	// shutdown script within its funding info.
+	upfrontShutdownScriptType tlv.Type = 1
+
+	// leaseExpiryType is the TLV type of the channel's lease expiry
+	// within its funding info.
+	leaseExpiryType tlv.Type = 3
+
+	// remoteUpfrontShutdownScriptType is the TLV type of the remote
+	// party's upfront shutdown script within the channel's funding info.
+	remoteUpfrontShutdownScriptType tlv.Type = 5
+)
+
+// chanFundingTLVStream returns the TLV stream of the optional fields stored
+// along with the channel's funding info. Fields set to their zero value are
+// omitted.
+func chanFundingTLVStream(channel *OpenChannel) *tlv.Stream {
+	var records []tlv.Record
+	if len(channel.UpfrontShutdownScript) != 0 {
+		records = append(records, tlv.MakePrimitiveRecord(
+			upfrontShutdownScriptType,
+			&channel.UpfrontShutdownScript,
+		))
+	}
+	if channel.LeaseExpiry != 0 {
+		records = append(records, tlv.MakePrimitiveRecord(
+			leaseExpiryType, &channel.LeaseExpiry,
+		))
+	}
+	if len(channel.RemoteUpfrontShutdownScript) != 0 {
+		records = append(records, tlv.MakePrimitiveRecord(
+			remoteUpfrontShutdownScriptType,
+			&channel.RemoteUpfrontShutdownScript,
+		))
+	}
+
+	return tlv.MustNewStream(records...)
+}
+
+// parseChanFundingTLVRecords populates the channel's optional fields from the
+// TLV stream stored along with its funding info. Channels written before the
+// stream was introduced have an empty stream, leaving the fields unset.
+func parseChanFundingTLVRecords(r io.Reader, channel *OpenChannel) error {
+	stream := tlv.MustNewStream(
+		tlv.MakePrimitiveRecord(
+			upfrontShutdownScriptType,
+			&channel.UpfrontShutdownScript,
+		),
+		tlv.MakePrimitiveRecord(leaseExpiryType, &channel.LeaseExpiry),
+		tlv.MakePrimitiveRecord(
+			remoteUpfrontShutdownScriptType,
+			&channel.RemoteUpfrontShutdownScript,
+		),
+	)
+
+	return stream.Decode(r)
+}
+
 func deleteChanFundingInfo(nodeChanBucket *bolt.Bucket, chanID []byte) error {
 	fundTxnKey := make([]byte, len(fundingTxnKey)+len(chanID))
 	copy(fundTxnKey[:3], fundingTxnKey)
@@ -1919,7 +2379,7 @@ func fetchChanFundingInfo(nodeChanBucket *bolt.Bucket, channel *OpenChannel) err
 	}
 	channel.NumConfsRequired = byteOrder.Uint16(scratch[:])
 
-	return nil
+	return parseChanFundingTLVRecords(infoBytes, channel)
 }
 
 func putChanRevocationState(nodeChanBucket *bolt.Bucket, channel *OpenChannel) error {
@@ -2152,67 +2612,87 @@ func deleteCurrentHtlcs(nodeChanBucket *bolt.Bucket, o *wire.OutPoint) error {
 	return nodeChanBucket.Delete(htlcKey[:])
 }
 
-func serializeChannelDelta(w io.Writer, delta *ChannelDelta) error {
-	// TODO(roasbeef): could use compression here to reduce on-disk space.
-	var scratch [8]byte
-	byteOrder.PutUint64(scratch[:], uint64(delta.LocalBalance))
-	if _, err := w.Write(scratch[:]); err != nil {
+// serializeRevocationLogEntry serializes the subset of the passed delta which
+// is required in order to construct the justice transactions sweeping the
+// remote party's commitment at that state. The update number isn't serialized
+// as it's already encoded within the key of the log entry. The commitment fee,
+// the fee rate and the signatures of the HTLCs are omitted as they aren't
+// needed to sweep a breached commitment, as are any HTLCs trimmed as dust, as
+// they have no output on the commitment to sweep.
+func serializeRevocationLogEntry(w io.Writer, delta *ChannelDelta) error {
+	err := wire.WriteVarInt(w, 0, uint64(delta.LocalBalance))
+	if err != nil {
 		return err
 	}
-	byteOrder.PutUint64(scratch[:], uint64(delta.RemoteBalance))
-	if _, err := w.Write(scratch[:]); err != nil {
+	err = wire.WriteVarInt(w, 0, uint64(delta.RemoteBalance))
+	if err != nil {
 		return err
 	}
 
-	byteOrder.PutUint64(scratch[:], delta.UpdateNum)
-	if _, err := w.Write(scratch[:]); err != nil {
-		return err
+	var numHtlcs uint64
+	for _, htlc := range delta.Htlcs {
+		if htlc.OutputIndex >= 0 {
+			numHtlcs++
+		}
 	}
-
-	numHtlcs := uint64(len(delta.Htlcs))
 	if err := wire.WriteVarInt(w, 0, numHtlcs); err != nil {
 		return err
 	}
+
 	for _, htlc := range delta.Htlcs {
-		if err := serializeHTLC(w, htlc); err != nil {
-			return err
+		if htlc.OutputIndex < 0 {
+			continue
 		}
-	}
 
-	byteOrder.PutUint64(scratch[:], uint64(delta.CommitFee))
-	if _, err := w.Write(scratch[:]); err != nil {
-		return err
-	}
+		if _, err := w.Write(htlc.RHash[:]); err != nil {
+			return err
+		}
+		if err := wire.WriteVarInt(w, 0, uint64(htlc.Amt)); err != nil {
+			return err
+		}
+		err := binary.Write(w, byteOrder, htlc.RefundTimeout)
+		if err != nil {
+			return err
+		}
+		err = wire.WriteVarInt(w, 0, uint64(htlc.OutputIndex))
+		if err != nil {
+			return err
+		}
 
-	byteOrder.PutUint64(scratch[:], uint64(delta.FeePerKw))
-	if _, err := w.Write(scratch[:]); err != nil {
-		return err
+		var boolByte [1]byte
+		if htlc.Incoming {
+			boolByte[0] = 1
+		}
+		if _, err := w.Write(boolByte[:]); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
-func deserializeChannelDelta(r io.Reader) (*ChannelDelta, error) {
-	var (
-		err     error
-		scratch [8]byte
-	)
-
-	delta := &ChannelDelta{}
+// deserializeRevocationLogEntry deserializes a revocation log entry for the
+// passed update number. As the log only retains the data required to sweep a
+// breached commitment, the commitment fee and fee rate of the returned delta
+// are zero, and its HTLCs lack signatures.
+func deserializeRevocationLogEntry(r io.Reader,
+	updateNum uint64) (*ChannelDelta, error) {
 
-	if _, err := r.Read(scratch[:]); err != nil {
-		return nil, err
+	delta := &ChannelDelta{
+		UpdateNum: updateNum,
 	}
-	delta.LocalBalance = lnwire.MilliSatoshi(byteOrder.Uint64(scratch[:]))
-	if _, err := r.Read(scratch[:]); err != nil {
+
+	localBalance, err := wire.ReadVarInt(r, 0)
+	if err != nil {
 		return nil, err
 	}
-	delta.RemoteBalance = lnwire.MilliSatoshi(byteOrder.Uint64(scratch[:]))
+	delta.LocalBalance = lnwire.MilliAtom(localBalance)
 
-	if _, err := r.Read(scratch[:]); err != nil {
+	remoteBalance, err := wire.ReadVarInt(r, 0)
+	if err != nil {
 		return nil, err
 	}
-	delta.UpdateNum = byteOrder.Uint64(scratch[:])
+	delta.RemoteBalance = lnwire.MilliAtom(remoteBalance)
 
 	numHtlcs, err := wire.ReadVarInt(r, 0)
 	if err != nil {
@@ -2220,22 +2700,35 @@ func deserializeChannelDelta(r io.Reader) (*ChannelDelta, error) {
 	}
 	delta.Htlcs = make([]*HTLC, numHtlcs)
 	for i := uint64(0); i < numHtlcs; i++ {
-		htlc, err := deserializeHTLC(r)
+		htlc := &HTLC{}
+
+		if _, err := io.ReadFull(r, htlc.RHash[:]); err != nil {
+			return nil, err
+		}
+		amt, err := wire.ReadVarInt(r, 0)
 		if err != nil {
 			return nil, err
 		}
+		htlc.Amt = lnwire.MilliAtom(amt)
 
-		delta.Htlcs[i] = htlc
-	}
-	if _, err := r.Read(scratch[:]); err != nil {
-		return nil, err
-	}
-	delta.CommitFee = btcutil.Amount(byteOrder.Uint64(scratch[:]))
+		err = binary.Read(r, byteOrder, &htlc.RefundTimeout)
+		if err != nil {
+			return nil, err
+		}
+		outputIndex, err := wire.ReadVarInt(r, 0)
+		if err != nil {
+			return nil, err
+		}
+		htlc.OutputIndex = int32(outputIndex)
 
-	if _, err := r.Read(scratch[:]); err != nil {
-		return nil, err
+		var boolByte [1]byte
+		if _, err := io.ReadFull(r, boolByte[:]); err != nil {
+			return nil, err
+		}
+		htlc.Incoming = boolByte[0] == 1
+
+		delta.Htlcs[i] = htlc
 	}
-	delta.FeePerKw = btcutil.Amount(byteOrder.Uint64(scratch[:]))
 
 	return delta, nil
 }
@@ -2265,7 +2758,7 @@ func appendChannelLogEntry(log *bolt.Bucket, delta *ChannelDelta,
 	chanPoint *wire.OutPoint) error {
 
 	var b bytes.Buffer
-	if err := serializeChannelDelta(&b, delta); err != nil {
+	if err := serializeRevocationLogEntry(&b, delta); err != nil {
 		return err
 	}
 
@@ -2284,7 +2777,7 @@ func fetchChannelLogEntry(log *bolt.Bucket, chanPoint *wire.OutPoint,
 
 	deltaReader := bytes.NewReader(deltaBytes)
 
-	return deserializeChannelDelta(deltaReader)
+	return deserializeRevocationLogEntry(deltaReader, updateNum)
 }
 
 func wipeChannelLogEntries(log *bolt.Bucket, o *wire.OutPoint) error {
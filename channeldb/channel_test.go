@@ -9,6 +9,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/boltdb/bolt"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/shachain"
@@ -131,9 +132,9 @@ func createTestChannelState(cdb *DB) (*OpenChannel, error) {
 	localCfg := ChannelConfig{
 		ChannelConstraints: ChannelConstraints{
 			DustLimit:        btcutil.Amount(rand.Int63()),
-			MaxPendingAmount: lnwire.MilliSatoshi(rand.Int63()),
+			MaxPendingAmount: lnwire.MilliAtom(rand.Int63()),
 			ChanReserve:      btcutil.Amount(rand.Int63()),
-			MinHTLC:          lnwire.MilliSatoshi(rand.Int63()),
+			MinHTLC:          lnwire.MilliAtom(rand.Int63()),
 			MaxAcceptedHtlcs: uint16(rand.Int31()),
 		},
 		CsvDelay:            uint16(rand.Int31()),
@@ -145,9 +146,9 @@ func createTestChannelState(cdb *DB) (*OpenChannel, error) {
 	remoteCfg := ChannelConfig{
 		ChannelConstraints: ChannelConstraints{
 			DustLimit:        btcutil.Amount(rand.Int63()),
-			MaxPendingAmount: lnwire.MilliSatoshi(rand.Int63()),
+			MaxPendingAmount: lnwire.MilliAtom(rand.Int63()),
 			ChanReserve:      btcutil.Amount(rand.Int63()),
-			MinHTLC:          lnwire.MilliSatoshi(rand.Int63()),
+			MinHTLC:          lnwire.MilliAtom(rand.Int63()),
 			MaxAcceptedHtlcs: uint16(rand.Int31()),
 		},
 		CsvDelay:            uint16(rand.Int31()),
@@ -160,31 +161,35 @@ func createTestChannelState(cdb *DB) (*OpenChannel, error) {
 	chanID := lnwire.NewShortChanIDFromInt(uint64(rand.Int63()))
 
 	return &OpenChannel{
-		ChanType:                SingleFunder,
-		ChainHash:               key,
-		FundingOutpoint:         *testOutpoint,
-		ShortChanID:             chanID,
-		IsInitiator:             true,
-		IsPending:               true,
-		IdentityPub:             pubKey,
-		LocalChanCfg:            localCfg,
-		RemoteChanCfg:           remoteCfg,
-		CommitFee:               btcutil.Amount(rand.Int63()),
-		FeePerKw:                btcutil.Amount(5000),
-		Capacity:                btcutil.Amount(10000),
-		LocalBalance:            lnwire.MilliSatoshi(3000),
-		RemoteBalance:           lnwire.MilliSatoshi(9000),
-		CommitTx:                *testTx,
-		CommitSig:               bytes.Repeat([]byte{1}, 71),
-		NumConfsRequired:        4,
-		RemoteCurrentRevocation: privKey.PubKey(),
-		RemoteNextRevocation:    privKey.PubKey(),
-		RevocationProducer:      producer,
-		RevocationStore:         store,
-		NumUpdates:              0,
-		TotalMSatSent:           8,
-		TotalMSatReceived:       2,
-		Db:                      cdb,
+		ChanType:                    SingleFunder,
+		ChainHash:                   key,
+		FundingOutpoint:             *testOutpoint,
+		ShortChanID:                 chanID,
+		IsInitiator:                 true,
+		IsPending:                   true,
+		IdentityPub:                 pubKey,
+		LocalChanCfg:                localCfg,
+		RemoteChanCfg:               remoteCfg,
+		CommitFee:                   btcutil.Amount(rand.Int63()),
+		FundingFee:                  btcutil.Amount(1234),
+		FeePerKw:                    btcutil.Amount(5000),
+		Capacity:                    btcutil.Amount(10000),
+		LocalBalance:                lnwire.MilliAtom(3000),
+		RemoteBalance:               lnwire.MilliAtom(9000),
+		CommitTx:                    *testTx,
+		CommitSig:                   bytes.Repeat([]byte{1}, 71),
+		NumConfsRequired:            4,
+		UpfrontShutdownScript:       bytes.Repeat([]byte{2}, 22),
+		LeaseExpiry:                 1000,
+		RemoteUpfrontShutdownScript: bytes.Repeat([]byte{3}, 34),
+		RemoteCurrentRevocation:     privKey.PubKey(),
+		RemoteNextRevocation:        privKey.PubKey(),
+		RevocationProducer:          producer,
+		RevocationStore:             store,
+		NumUpdates:                  0,
+		TotalMSatSent:               8,
+		TotalMSatReceived:           2,
+		Db:                          cdb,
 	}, nil
 }
 
@@ -339,7 +344,7 @@ func TestChannelStateTransition(t *testing.T) {
 	// Half of the HTLCs are incoming, while the other half are outgoing.
 	var (
 		htlcs   []*HTLC
-		htlcAmt lnwire.MilliSatoshi
+		htlcAmt lnwire.MilliAtom
 	)
 	for i := uint32(0); i < 10; i++ {
 		var incoming bool
@@ -368,8 +373,8 @@ func TestChannelStateTransition(t *testing.T) {
 	newTx := channel.CommitTx.Copy()
 	newTx.TxIn[0].Sequence = newSequence
 	delta := &ChannelDelta{
-		LocalBalance:  lnwire.MilliSatoshi(1e8),
-		RemoteBalance: lnwire.MilliSatoshi(1e8),
+		LocalBalance:  lnwire.MilliAtom(1e8),
+		RemoteBalance: lnwire.MilliAtom(1e8),
 		Htlcs:         htlcs,
 		UpdateNum:     1,
 	}
@@ -446,7 +451,9 @@ func TestChannelStateTransition(t *testing.T) {
 	}
 
 	// The two deltas (the original vs the on-disk version) should
-	// identical, and all HTLC data should properly be retained.
+	// identical, and all HTLC data required to sweep a breached commitment
+	// should properly be retained. The signatures of the HTLCs aren't
+	// retained within the revocation log.
 	if delta.LocalBalance != diskDelta.LocalBalance {
 		t.Fatal("local balances don't match")
 	}
@@ -456,10 +463,15 @@ func TestChannelStateTransition(t *testing.T) {
 	if delta.UpdateNum != diskDelta.UpdateNum {
 		t.Fatal("update number doesn't match")
 	}
+	if len(delta.Htlcs) != len(diskDelta.Htlcs) {
+		t.Fatalf("expected %v htlcs, got %v", len(delta.Htlcs),
+			len(diskDelta.Htlcs))
+	}
 	for i := 0; i < len(delta.Htlcs); i++ {
-		originalHTLC := delta.Htlcs[i]
+		originalHTLC := *delta.Htlcs[i]
+		originalHTLC.Signature = nil
 		diskHTLC := diskDelta.Htlcs[i]
-		if !reflect.DeepEqual(originalHTLC, diskHTLC) {
+		if !reflect.DeepEqual(&originalHTLC, diskHTLC) {
 			t.Fatalf("htlc's dont match: %v vs %v",
 				spew.Sdump(originalHTLC),
 				spew.Sdump(diskHTLC))
@@ -627,6 +639,18 @@ func TestFetchPendingChannels(t *testing.T) {
 			broadcastHeight)
 	}
 
+	// The confirmation height should now be recorded, while the funding
+	// fee should've been left untouched.
+	if openChans[0].FundingConfirmationHeight != chanOpenLoc.BlockHeight {
+		t.Fatalf("confirmation height mismatch: expected %v, got %v",
+			chanOpenLoc.BlockHeight,
+			openChans[0].FundingConfirmationHeight)
+	}
+	if openChans[0].FundingFee != state.FundingFee {
+		t.Fatalf("funding fee mismatch: expected %v, got %v",
+			state.FundingFee, openChans[0].FundingFee)
+	}
+
 	pendingChannels, err = cdb.FetchPendingChannels()
 	if err != nil {
 		t.Fatalf("unable to list pending channels: %v", err)
@@ -688,6 +712,22 @@ func TestFetchClosedChannels(t *testing.T) {
 		TimeLockedBalance: state.LocalBalance.ToSatoshis() + 10000,
 		CloseType:         ForceClose,
 		IsPending:         true,
+		HtlcResolutions: []HtlcResolutionSummary{
+			{
+				RHash:         key,
+				Amt:           10000,
+				Incoming:      false,
+				RefundTimeout: 144,
+				Outcome:       HtlcOutcomeTimeout,
+			},
+			{
+				RHash:         rev,
+				Amt:           500,
+				Incoming:      true,
+				RefundTimeout: 288,
+				Outcome:       HtlcOutcomeDust,
+			},
+		},
 	}
 	if err := state.CloseChannel(summary); err != nil {
 		t.Fatalf("unable to close channel: %v", err)
@@ -746,3 +786,296 @@ func TestFetchClosedChannels(t *testing.T) {
 			"got %v", 0, len(closed))
 	}
 }
+
+// TestLegacyCloseSummaryDeserialization tests that close summaries written
+// before HTLC resolutions were tracked can still be read from disk.
+func TestLegacyCloseSummaryDeserialization(t *testing.T) {
+	t.Parallel()
+
+	_, pub := btcec.PrivKeyFromBytes(btcec.S256(), key[:])
+	summary := &ChannelCloseSummary{
+		ChanPoint:      wire.OutPoint{Hash: rev, Index: 1},
+		ClosingTXID:    key,
+		RemotePub:      pub,
+		Capacity:       btcutil.Amount(10000),
+		SettledBalance: btcutil.Amount(5000),
+		CloseType:      CooperativeClose,
+	}
+
+	var b bytes.Buffer
+	if err := serializeChannelCloseSummary(&b, summary); err != nil {
+		t.Fatalf("unable to serialize summary: %v", err)
+	}
+
+	// Legacy summaries end directly after the remote public key, so we'll
+	// strip the trailing HTLC count before reading the summary back.
+	legacyBytes := b.Bytes()[:b.Len()-2]
+	legacySummary, err := deserializeCloseChannelSummary(
+		bytes.NewReader(legacyBytes),
+	)
+	if err != nil {
+		t.Fatalf("unable to deserialize legacy summary: %v", err)
+	}
+	if !reflect.DeepEqual(summary, legacySummary) {
+		t.Fatalf("summaries don't match: expected %v got %v",
+			spew.Sdump(summary), spew.Sdump(legacySummary))
+	}
+}
+
+// TestLegacyConfInfo asserts that the confirmation info of channels created
+// before the opening metadata was recorded is still readable, with the
+// confirmation height derived from the short channel ID.
+func TestLegacyConfInfo(t *testing.T) {
+	t.Parallel()
+
+	cdb, cleanUp, err := makeTestDB()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+	defer cleanUp()
+
+	state, err := createTestChannelState(cdb)
+	if err != nil {
+		t.Fatalf("unable to create channel state: %v", err)
+	}
+	addr := &net.TCPAddr{
+		IP:   net.ParseIP("127.0.0.1"),
+		Port: 18555,
+	}
+	if err := state.SyncPending(addr, 99); err != nil {
+		t.Fatalf("unable to save channel state: %v", err)
+	}
+	err = cdb.MarkChannelAsOpen(&state.FundingOutpoint, state.ShortChanID)
+	if err != nil {
+		t.Fatalf("unable to mark channel as open: %v", err)
+	}
+
+	// Truncate the stored conf info to the legacy format, which only
+	// contains the broadcast height and short channel ID.
+	var b bytes.Buffer
+	if err := writeOutpoint(&b, &state.FundingOutpoint); err != nil {
+		t.Fatalf("unable to write outpoint: %v", err)
+	}
+	confInfoKey := append(append([]byte{}, confInfoPrefix...), b.Bytes()...)
+	err = cdb.Update(func(tx *bolt.Tx) error {
+		openChanBucket := tx.Bucket(openChannelBucket)
+		legacyInfo := append(
+			[]byte{}, openChanBucket.Get(confInfoKey)[:12]...,
+		)
+		return openChanBucket.Put(confInfoKey, legacyInfo)
+	})
+	if err != nil {
+		t.Fatalf("unable to truncate conf info: %v", err)
+	}
+
+	openChans, err := cdb.FetchAllChannels()
+	if err != nil {
+		t.Fatalf("unable to fetch channels: %v", err)
+	}
+	if len(openChans) != 1 {
+		t.Fatalf("expected 1 channel, got %v", len(openChans))
+	}
+
+	channel := openChans[0]
+	if channel.ShortChanID != state.ShortChanID {
+		t.Fatalf("short chan ID mismatch: expected %v, got %v",
+			state.ShortChanID, channel.ShortChanID)
+	}
+	if channel.FundingConfirmationHeight != state.ShortChanID.BlockHeight {
+		t.Fatalf("confirmation height mismatch: expected %v, got %v",
+			state.ShortChanID.BlockHeight,
+			channel.FundingConfirmationHeight)
+	}
+	if channel.FundingFee != 0 {
+		t.Fatalf("expected no funding fee, got %v", channel.FundingFee)
+	}
+}
+
+// TestLegacyFundingInfo tests that channels whose funding info was stored
+// before the optional fields were appended as a TLV stream are still readable,
+// and have the optional fields unset.
+func TestLegacyFundingInfo(t *testing.T) {
+	t.Parallel()
+
+	cdb, cleanUp, err := makeTestDB()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+	defer cleanUp()
+
+	state, err := createTestChannelState(cdb)
+	if err != nil {
+		t.Fatalf("unable to create channel state: %v", err)
+	}
+	if err := state.FullSync(); err != nil {
+		t.Fatalf("unable to save channel state: %v", err)
+	}
+
+	// Truncate the stored funding info to the legacy format, which ends
+	// after the initiator flag, channel type, chain hash and number of
+	// required confirmations.
+	var b bytes.Buffer
+	if err := writeOutpoint(&b, &state.FundingOutpoint); err != nil {
+		t.Fatalf("unable to write outpoint: %v", err)
+	}
+	fundTxnKey := append(append([]byte{}, fundingTxnKey...), b.Bytes()...)
+	err = cdb.Update(func(tx *bolt.Tx) error {
+		nodeChanBucket := tx.Bucket(openChannelBucket).Bucket(
+			state.IdentityPub.SerializeCompressed(),
+		)
+		legacyInfo := append(
+			[]byte{}, nodeChanBucket.Get(fundTxnKey)[:36]...,
+		)
+		return nodeChanBucket.Put(fundTxnKey, legacyInfo)
+	})
+	if err != nil {
+		t.Fatalf("unable to truncate funding info: %v", err)
+	}
+
+	openChans, err := cdb.FetchOpenChannels(state.IdentityPub)
+	if err != nil {
+		t.Fatalf("unable to fetch channels: %v", err)
+	}
+	if len(openChans) != 1 {
+		t.Fatalf("expected 1 channel, got %v", len(openChans))
+	}
+
+	channel := openChans[0]
+	if channel.NumConfsRequired != state.NumConfsRequired {
+		t.Fatalf("num confs mismatch: expected %v, got %v",
+			state.NumConfsRequired, channel.NumConfsRequired)
+	}
+	if channel.UpfrontShutdownScript != nil {
+		t.Fatalf("expected no upfront shutdown script, got %x",
+			channel.UpfrontShutdownScript)
+	}
+	if channel.LeaseExpiry != 0 {
+		t.Fatalf("expected no lease expiry, got %v",
+			channel.LeaseExpiry)
+	}
+	if channel.RemoteUpfrontShutdownScript != nil {
+		t.Fatalf("expected no remote upfront shutdown script, got %x",
+			channel.RemoteUpfrontShutdownScript)
+	}
+}
+
+// TestFundingInfoTLVEncoding tests that the optional fields of a channel's
+// funding info are encoded as the same bytes that were written before the
+// stream moved to the tlv package. As the type and length of each record are
+// below 0xfd, their BigSize encoding matches the varint encoding used
+// previously, so channels stored since then remain readable.
+func TestFundingInfoTLVEncoding(t *testing.T) {
+	t.Parallel()
+
+	channel := &OpenChannel{
+		UpfrontShutdownScript:       bytes.Repeat([]byte{2}, 22),
+		LeaseExpiry:                 1000,
+		RemoteUpfrontShutdownScript: bytes.Repeat([]byte{3}, 34),
+	}
+
+	var expected []byte
+	expected = append(expected, 1, 22)
+	expected = append(expected, channel.UpfrontShutdownScript...)
+	expected = append(expected, 3, 4, 0x00, 0x00, 0x03, 0xe8)
+	expected = append(expected, 5, 34)
+	expected = append(expected, channel.RemoteUpfrontShutdownScript...)
+
+	var b bytes.Buffer
+	if err := chanFundingTLVStream(channel).Encode(&b); err != nil {
+		t.Fatalf("unable to encode funding tlv stream: %v", err)
+	}
+	if !bytes.Equal(b.Bytes(), expected) {
+		t.Fatalf("expected stream %x, got %x", expected, b.Bytes())
+	}
+
+	decoded := &OpenChannel{}
+	err := parseChanFundingTLVRecords(bytes.NewReader(expected), decoded)
+	if err != nil {
+		t.Fatalf("unable to decode funding tlv stream: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, channel) {
+		t.Fatalf("expected channel %v, got %v", spew.Sdump(channel),
+			spew.Sdump(decoded))
+	}
+}
+
+// TestChannelStatus tests that status flags applied to a channel are
+// persisted, and that borked channels reject further state updates.
+func TestChannelStatus(t *testing.T) {
+	t.Parallel()
+
+	cdb, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+
+	channel, err := createTestChannelState(cdb)
+	if err != nil {
+		t.Fatalf("unable to create channel state: %v", err)
+	}
+	if err := channel.FullSync(); err != nil {
+		t.Fatalf("unable to save and serialize channel state: %v", err)
+	}
+
+	// fetchChannel reads the channel back from disk.
+	fetchChannel := func() *OpenChannel {
+		openChannels, err := cdb.FetchOpenChannels(channel.IdentityPub)
+		if err != nil {
+			t.Fatalf("unable to fetch open channel: %v", err)
+		}
+		if len(openChannels) != 1 {
+			t.Fatalf("expected 1 channel, got %v", len(openChannels))
+		}
+		return openChannels[0]
+	}
+
+	// A freshly created channel should be in the default state.
+	if !fetchChannel().HasChanStatus(ChanStatusDefault) {
+		t.Fatalf("channel should be in the default state")
+	}
+
+	// Applying multiple flags should result in all of them being set.
+	if err := channel.MarkCommitmentBroadcasted(); err != nil {
+		t.Fatalf("unable to mark commitment broadcast: %v", err)
+	}
+	if err := channel.MarkBorked(); err != nil {
+		t.Fatalf("unable to mark channel borked: %v", err)
+	}
+
+	dbChannel := fetchChannel()
+	if dbChannel.HasChanStatus(ChanStatusDefault) {
+		t.Fatalf("channel shouldn't be in the default state")
+	}
+	if !dbChannel.HasChanStatus(ChanStatusBorked) ||
+		!dbChannel.HasChanStatus(ChanStatusCommitBroadcasted) {
+
+		t.Fatalf("expected borked and commit broadcast flags, got %v",
+			dbChannel.ChanStatus)
+	}
+	if dbChannel.HasChanStatus(ChanStatusLocalDataLoss) {
+		t.Fatalf("unexpected flag set: %v", dbChannel.ChanStatus)
+	}
+
+	expectedStatus := "ChanStatusBorked|ChanStatusCommitBroadcasted"
+	if dbChannel.ChanStatus.String() != expectedStatus {
+		t.Fatalf("expected status %v, got %v", expectedStatus,
+			dbChannel.ChanStatus)
+	}
+
+	// Now that the channel is borked, any attempts to update its state
+	// should fail.
+	delta := &ChannelDelta{
+		LocalBalance:  lnwire.NewMSatFromSatoshis(btcutil.SatoshiPerBitcoin),
+		RemoteBalance: lnwire.NewMSatFromSatoshis(btcutil.SatoshiPerBitcoin),
+		UpdateNum:     1,
+	}
+	err = dbChannel.UpdateCommitment(&dbChannel.CommitTx,
+		dbChannel.CommitSig, delta)
+	if err != ErrChanBorked {
+		t.Fatalf("expected ErrChanBorked, got %v", err)
+	}
+	if err := dbChannel.AppendToRevocationLog(delta); err != ErrChanBorked {
+		t.Fatalf("expected ErrChanBorked, got %v", err)
+	}
+}
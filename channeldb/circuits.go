@@ -0,0 +1,132 @@
+package channeldb
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/boltdb/bolt"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+var (
+	// circuitBucket is a top-level bucket which houses the payment
+	// circuits of the HTLCs forwarded by the switch, allowing the settles
+	// and fails of those HTLCs to be sent back to their incoming channel
+	// even after a restart. Each circuit is keyed by its payment hash, and
+	// is removed once it has been completed.
+	//
+	// maps: paymentHash -> circuit
+	circuitBucket = []byte("circuits")
+)
+
+// PaymentCircuit is the persisted form of a payment circuit opened by the
+// switch when forwarding an HTLC, identifying the channel the settle or fail
+// of the HTLC is to be sent back over.
+type PaymentCircuit struct {
+	// PaymentHash is the payment hash of the forwarded HTLC.
+	PaymentHash [32]byte
+
+	// IncomingChan identifies the channel the HTLC was received over.
+	IncomingChan lnwire.ShortChannelID
+
+	// OutgoingChan identifies the channel the HTLC was forwarded over.
+	OutgoingChan lnwire.ShortChannelID
+
+	// IncomingAmount is the value of the HTLC received over the incoming
+	// channel.
+	IncomingAmount lnwire.MilliAtom
+
+	// OutgoingAmount is the value of the HTLC forwarded over the outgoing
+	// channel.
+	OutgoingAmount lnwire.MilliAtom
+
+	// RefCount is the number of HTLCs with the same payment hash which
+	// share the circuit.
+	RefCount uint32
+
+	// OnionBlob is the onion packet of the incoming HTLC, from which the
+	// obfuscator used to encrypt the failure of the HTLC can be
+	// re-derived.
+	OnionBlob []byte
+}
+
+// UpdateCircuits writes the passed circuits, replacing any existing circuit
+// with the same payment hash, and deletes the circuits of the passed payment
+// hashes. All changes are applied within a single database transaction.
+func (d *DB) UpdateCircuits(circuits []*PaymentCircuit,
+	deleted [][32]byte) error {
+
+	if len(circuits) == 0 && len(deleted) == 0 {
+		return nil
+	}
+
+	return d.Update(func(tx *bolt.Tx) error {
+		circuitIndex, err := tx.CreateBucketIfNotExists(circuitBucket)
+		if err != nil {
+			return err
+		}
+
+		for _, circuit := range circuits {
+			var b bytes.Buffer
+			if err := serializeCircuit(&b, circuit); err != nil {
+				return err
+			}
+
+			err := circuitIndex.Put(circuit.PaymentHash[:], b.Bytes())
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, hash := range deleted {
+			if err := circuitIndex.Delete(hash[:]); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// FetchCircuits returns all circuits which have yet to be deleted.
+func (d *DB) FetchCircuits() ([]*PaymentCircuit, error) {
+	var circuits []*PaymentCircuit
+	err := d.View(func(tx *bolt.Tx) error {
+		circuitIndex := tx.Bucket(circuitBucket)
+		if circuitIndex == nil {
+			return nil
+		}
+
+		return circuitIndex.ForEach(func(k, v []byte) error {
+			circuit, err := deserializeCircuit(bytes.NewReader(v))
+			if err != nil {
+				return err
+			}
+			copy(circuit.PaymentHash[:], k)
+
+			circuits = append(circuits, circuit)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return circuits, nil
+}
+
+func serializeCircuit(w io.Writer, c *PaymentCircuit) error {
+	return writeElements(w, c.IncomingChan, c.OutgoingChan,
+		c.IncomingAmount, c.OutgoingAmount, c.RefCount, c.OnionBlob)
+}
+
+func deserializeCircuit(r io.Reader) (*PaymentCircuit, error) {
+	c := &PaymentCircuit{}
+	err := readElements(r, &c.IncomingChan, &c.OutgoingChan,
+		&c.IncomingAmount, &c.OutgoingAmount, &c.RefCount, &c.OnionBlob)
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
@@ -0,0 +1,84 @@
+package channeldb
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// TestPaymentCircuits tests that circuits are retained until they're deleted,
+// and that writing a circuit replaces any with the same payment hash.
+func TestPaymentCircuits(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+
+	// Without any circuits written, none should be returned.
+	circuits, err := db.FetchCircuits()
+	if err != nil {
+		t.Fatalf("unable to fetch circuits: %v", err)
+	}
+	if len(circuits) != 0 {
+		t.Fatalf("expected no circuits, got %v", len(circuits))
+	}
+
+	circuit1 := &PaymentCircuit{
+		PaymentHash:    [32]byte{1},
+		IncomingChan:   lnwire.NewShortChanIDFromInt(1),
+		OutgoingChan:   lnwire.NewShortChanIDFromInt(2),
+		IncomingAmount: 1100,
+		OutgoingAmount: 1000,
+		RefCount:       1,
+		OnionBlob:      []byte{1, 2, 3},
+	}
+	circuit2 := &PaymentCircuit{
+		PaymentHash:    [32]byte{2},
+		IncomingChan:   lnwire.NewShortChanIDFromInt(3),
+		OutgoingChan:   lnwire.NewShortChanIDFromInt(4),
+		IncomingAmount: 2100,
+		OutgoingAmount: 2000,
+		RefCount:       1,
+		OnionBlob:      []byte{4, 5, 6},
+	}
+	err = db.UpdateCircuits([]*PaymentCircuit{circuit1, circuit2}, nil)
+	if err != nil {
+		t.Fatalf("unable to write circuits: %v", err)
+	}
+
+	circuits, err = db.FetchCircuits()
+	if err != nil {
+		t.Fatalf("unable to fetch circuits: %v", err)
+	}
+	expected := []*PaymentCircuit{circuit1, circuit2}
+	if !reflect.DeepEqual(circuits, expected) {
+		t.Fatalf("expected circuits %v, got %v", spew.Sdump(expected),
+			spew.Sdump(circuits))
+	}
+
+	// Sharing the first circuit, and deleting the second, within a single
+	// update should leave only the first circuit, with its new reference
+	// count.
+	circuit1.RefCount = 2
+	err = db.UpdateCircuits(
+		[]*PaymentCircuit{circuit1}, [][32]byte{circuit2.PaymentHash},
+	)
+	if err != nil {
+		t.Fatalf("unable to update circuits: %v", err)
+	}
+
+	circuits, err = db.FetchCircuits()
+	if err != nil {
+		t.Fatalf("unable to fetch circuits: %v", err)
+	}
+	expected = []*PaymentCircuit{circuit1}
+	if !reflect.DeepEqual(circuits, expected) {
+		t.Fatalf("expected circuits %v, got %v", spew.Sdump(expected),
+			spew.Sdump(circuits))
+	}
+}
@@ -0,0 +1,262 @@
+package channeldb
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+// writeElement is a one-stop shop to write the big endian representation of
+// any element which is to be serialized for storage on disk. The passed
+// io.Writer should be backed by an appropriately sized byte slice, or be able
+// to dynamically expand to accommodate additional data.
+func writeElement(w io.Writer, element interface{}) error {
+	var scratch [8]byte
+
+	switch e := element.(type) {
+	case bool:
+		var b [1]byte
+		if e {
+			b[0] = 1
+		}
+		if _, err := w.Write(b[:]); err != nil {
+			return err
+		}
+
+	case uint8:
+		if _, err := w.Write([]byte{e}); err != nil {
+			return err
+		}
+
+	case uint16:
+		byteOrder.PutUint16(scratch[:2], e)
+		if _, err := w.Write(scratch[:2]); err != nil {
+			return err
+		}
+
+	case uint32:
+		byteOrder.PutUint32(scratch[:4], e)
+		if _, err := w.Write(scratch[:4]); err != nil {
+			return err
+		}
+
+	case uint64:
+		byteOrder.PutUint64(scratch[:], e)
+		if _, err := w.Write(scratch[:]); err != nil {
+			return err
+		}
+
+	case int64:
+		byteOrder.PutUint64(scratch[:], uint64(e))
+		if _, err := w.Write(scratch[:]); err != nil {
+			return err
+		}
+
+	case lnwire.MilliAtom:
+		return writeElement(w, uint64(e))
+
+	case btcutil.Amount:
+		return writeElement(w, uint64(e))
+
+	case time.Time:
+		// A zero time is stored as a zero unix nano timestamp to
+		// ensure it survives a round trip.
+		var unixNano uint64
+		if !e.IsZero() {
+			unixNano = uint64(e.UnixNano())
+		}
+		return writeElement(w, unixNano)
+
+	case [32]byte:
+		if _, err := w.Write(e[:]); err != nil {
+			return err
+		}
+
+	case [33]byte:
+		if _, err := w.Write(e[:]); err != nil {
+			return err
+		}
+
+	case []byte:
+		if err := wire.WriteVarBytes(w, 0, e); err != nil {
+			return err
+		}
+
+	case string:
+		if err := wire.WriteVarString(w, 0, e); err != nil {
+			return err
+		}
+
+	case *btcec.PublicKey:
+		if _, err := w.Write(e.SerializeCompressed()); err != nil {
+			return err
+		}
+
+	case wire.OutPoint:
+		if err := writeOutpoint(w, &e); err != nil {
+			return err
+		}
+
+	case lnwire.ShortChannelID:
+		return writeElement(w, e.ToUint64())
+
+	default:
+		return fmt.Errorf("unknown type in writeElement: %T", e)
+	}
+
+	return nil
+}
+
+// writeElements writes each element in the elements slice to the passed
+// io.Writer using writeElement.
+func writeElements(w io.Writer, elements ...interface{}) error {
+	for _, element := range elements {
+		if err := writeElement(w, element); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readElement is a one-stop utility function to deserialize any datastructure
+// encoded using the serialization format of the database.
+func readElement(r io.Reader, element interface{}) error {
+	var scratch [8]byte
+
+	switch e := element.(type) {
+	case *bool:
+		var b [1]byte
+		if _, err := io.ReadFull(r, b[:]); err != nil {
+			return err
+		}
+		*e = b[0] != 0
+
+	case *uint8:
+		var b [1]byte
+		if _, err := io.ReadFull(r, b[:]); err != nil {
+			return err
+		}
+		*e = b[0]
+
+	case *uint16:
+		if _, err := io.ReadFull(r, scratch[:2]); err != nil {
+			return err
+		}
+		*e = byteOrder.Uint16(scratch[:2])
+
+	case *uint32:
+		if _, err := io.ReadFull(r, scratch[:4]); err != nil {
+			return err
+		}
+		*e = byteOrder.Uint32(scratch[:4])
+
+	case *uint64:
+		if _, err := io.ReadFull(r, scratch[:]); err != nil {
+			return err
+		}
+		*e = byteOrder.Uint64(scratch[:])
+
+	case *int64:
+		if _, err := io.ReadFull(r, scratch[:]); err != nil {
+			return err
+		}
+		*e = int64(byteOrder.Uint64(scratch[:]))
+
+	case *lnwire.MilliAtom:
+		var a uint64
+		if err := readElement(r, &a); err != nil {
+			return err
+		}
+		*e = lnwire.MilliAtom(a)
+
+	case *btcutil.Amount:
+		var a uint64
+		if err := readElement(r, &a); err != nil {
+			return err
+		}
+		*e = btcutil.Amount(a)
+
+	case *time.Time:
+		var unixNano uint64
+		if err := readElement(r, &unixNano); err != nil {
+			return err
+		}
+
+		*e = time.Time{}
+		if unixNano != 0 {
+			*e = time.Unix(0, int64(unixNano))
+		}
+
+	case *[32]byte:
+		if _, err := io.ReadFull(r, e[:]); err != nil {
+			return err
+		}
+
+	case *[33]byte:
+		if _, err := io.ReadFull(r, e[:]); err != nil {
+			return err
+		}
+
+	case *[]byte:
+		b, err := wire.ReadVarBytes(r, 0, 66000, "[]byte")
+		if err != nil {
+			return err
+		}
+		*e = b
+
+	case *string:
+		s, err := wire.ReadVarString(r, 0)
+		if err != nil {
+			return err
+		}
+		*e = s
+
+	case **btcec.PublicKey:
+		var b [33]byte
+		if _, err := io.ReadFull(r, b[:]); err != nil {
+			return err
+		}
+
+		pubKey, err := btcec.ParsePubKey(b[:], btcec.S256())
+		if err != nil {
+			return err
+		}
+		*e = pubKey
+
+	case *wire.OutPoint:
+		if err := readOutpoint(r, e); err != nil {
+			return err
+		}
+
+	case *lnwire.ShortChannelID:
+		var a uint64
+		if err := readElement(r, &a); err != nil {
+			return err
+		}
+		*e = lnwire.NewShortChanIDFromInt(a)
+
+	default:
+		return fmt.Errorf("unknown type in readElement: %T", e)
+	}
+
+	return nil
+}
+
+// readElements deserializes a variable number of elements into the passed
+// io.Reader, with each element being deserialized according to the
+// readElement function.
+func readElements(r io.Reader, elements ...interface{}) error {
+	for _, element := range elements {
+		if err := readElement(r, element); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package channeldb
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// TestMilliAtomEncoding asserts that milli-atom amounts are stored using the
+// same 8-byte big endian encoding used for them when they were known as
+// milli-satoshis, so that existing databases decode identically without a
+// migration.
+func TestMilliAtomEncoding(t *testing.T) {
+	t.Parallel()
+
+	const amt = 0x0102030405060708
+	legacy := []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08}
+
+	var b bytes.Buffer
+	if err := writeElement(&b, lnwire.MilliAtom(amt)); err != nil {
+		t.Fatalf("unable to write amount: %v", err)
+	}
+	if !bytes.Equal(b.Bytes(), legacy) {
+		t.Fatalf("expected encoding %x, got %x", legacy, b.Bytes())
+	}
+
+	var decoded lnwire.MilliAtom
+	if err := readElement(bytes.NewReader(legacy), &decoded); err != nil {
+		t.Fatalf("unable to read amount: %v", err)
+	}
+	if decoded != amt {
+		t.Fatalf("expected amount %v, got %v", lnwire.MilliAtom(amt),
+			decoded)
+	}
+}
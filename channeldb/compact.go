@@ -0,0 +1,233 @@
+package channeldb
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/boltdb/bolt"
+)
+
+const (
+	// compactTxMaxSize is the maximum number of bytes copied into the
+	// compacted database within a single transaction. Splitting the copy
+	// across several transactions bounds the memory used while compacting
+	// large databases.
+	compactTxMaxSize = 64 * 1024 * 1024
+
+	// compactTempSuffix is the suffix appended to the database file name
+	// to form the name of the temporary file the database is compacted
+	// into.
+	compactTempSuffix = ".compact"
+)
+
+// Compact compacts the channeldb located within dbPath if the space which
+// can be reclaimed by doing so is at least minFreeRatio of the size of the
+// database file. Compaction is carried out by copying every bucket, key and
+// value into a fresh database file, which then atomically replaces the
+// original. If the process is interrupted before the swap, then the original
+// database is left untouched. A boolean is returned indicating whether the
+// database was compacted.
+//
+// NOTE: This must be called before the database is opened.
+func Compact(dbPath string, minFreeRatio float64) (bool, error) {
+	path := filepath.Join(dbPath, dbName)
+	if !fileExists(path) {
+		return false, nil
+	}
+
+	// Any compacted file left over from a prior interrupted attempt is
+	// incomplete, so we'll remove it before starting.
+	tempPath := path + compactTempSuffix
+	if err := os.Remove(tempPath); err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+
+	src, err := bolt.Open(path, dbFilePermission, &bolt.Options{
+		Timeout: time.Second,
+	})
+	if err != nil {
+		return false, err
+	}
+	defer src.Close()
+
+	// Before doing any work, we'll check whether enough space can be
+	// reclaimed to warrant compacting the database at all.
+	fileInfo, err := os.Stat(path)
+	if err != nil {
+		return false, err
+	}
+	origSize := fileInfo.Size()
+
+	// Bolt only updates its freelist statistics once a read-write
+	// transaction has closed, so we'll begin one, noting the size of the
+	// database it sees, then immediately roll it back so nothing is
+	// written. The reclaimable space is then all of the file which isn't
+	// occupied by in-use pages.
+	tx, err := src.Begin(true)
+	if err != nil {
+		return false, err
+	}
+	dbSize := tx.Size()
+	if err := tx.Rollback(); err != nil {
+		return false, err
+	}
+	usedBytes := dbSize - int64(src.Stats().FreeAlloc)
+	freeBytes := origSize - usedBytes
+	if origSize == 0 || float64(freeBytes)/float64(origSize) < minFreeRatio {
+		log.Debugf("Skipping compaction of %v, %v of %v bytes "+
+			"reclaimable", path, freeBytes, origSize)
+		return false, nil
+	}
+
+	log.Infof("Compacting database %v, %v of %v bytes reclaimable",
+		path, freeBytes, origSize)
+
+	dst, err := bolt.Open(tempPath, dbFilePermission, nil)
+	if err != nil {
+		return false, err
+	}
+
+	if err := compactDB(dst, src); err != nil {
+		dst.Close()
+		os.Remove(tempPath)
+		return false, err
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(tempPath)
+		return false, err
+	}
+
+	// With the compacted copy fully written and synced, we'll close the
+	// original and atomically swap the two files.
+	if err := src.Close(); err != nil {
+		os.Remove(tempPath)
+		return false, err
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		os.Remove(tempPath)
+		return false, err
+	}
+
+	// Finally, sync the directory to ensure the rename itself is durable.
+	dir, err := os.Open(dbPath)
+	if err != nil {
+		return false, err
+	}
+	defer dir.Close()
+	if err := dir.Sync(); err != nil {
+		return false, err
+	}
+
+	if fileInfo, err := os.Stat(path); err == nil {
+		log.Infof("Compacted database %v from %v to %v bytes", path,
+			origSize, fileInfo.Size())
+	}
+
+	return true, nil
+}
+
+// compactDB copies every bucket, key and value within src into dst. The copy
+// is split across several transactions within dst to bound memory usage.
+func compactDB(dst, src *bolt.DB) error {
+	var size int64
+
+	tx, err := dst.Begin(true)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		tx.Rollback()
+	}()
+
+	// The final commit takes place within the source transaction, as the
+	// keys and values being written point into the memory-mapped source
+	// database, and must remain valid until they're written out.
+	return src.View(func(srcTx *bolt.Tx) error {
+		err := srcTx.ForEach(func(name []byte, b *bolt.Bucket) error {
+			return compactBucket(
+				dst, &tx, &size, [][]byte{name}, b,
+			)
+		})
+		if err != nil {
+			return err
+		}
+
+		return tx.Commit()
+	})
+}
+
+// compactBucket recursively copies the passed source bucket, located at the
+// passed path of bucket names, into the destination database. If the size of
+// the current destination transaction exceeds compactTxMaxSize, then it's
+// committed and a new one begun.
+func compactBucket(dst *bolt.DB, tx **bolt.Tx, size *int64, path [][]byte,
+	src *bolt.Bucket) error {
+
+	// fetchDstBucket returns the destination bucket corresponding to the
+	// source bucket. As the transaction may be replaced while copying, the
+	// bucket must be re-fetched from the current transaction each time.
+	fetchDstBucket := func() *bolt.Bucket {
+		b := (*tx).Bucket(path[0])
+		for _, name := range path[1:] {
+			b = b.Bucket(name)
+		}
+		return b
+	}
+
+	// Create the destination bucket within its parent, carrying over the
+	// sequence number as some buckets rely on it for unique IDs.
+	var (
+		dstBucket *bolt.Bucket
+		err       error
+	)
+	if len(path) == 1 {
+		dstBucket, err = (*tx).CreateBucket(path[0])
+	} else {
+		parentPath := path[:len(path)-1]
+		parent := (*tx).Bucket(parentPath[0])
+		for _, name := range parentPath[1:] {
+			parent = parent.Bucket(name)
+		}
+		dstBucket, err = parent.CreateBucket(path[len(path)-1])
+	}
+	if err != nil {
+		return err
+	}
+	if err := dstBucket.SetSequence(src.Sequence()); err != nil {
+		return err
+	}
+
+	return src.ForEach(func(k, v []byte) error {
+		// If the value is nil, then this is a nested bucket which
+		// we'll copy recursively.
+		if v == nil {
+			childPath := make([][]byte, len(path)+1)
+			copy(childPath, path)
+			childPath[len(path)] = k
+
+			return compactBucket(
+				dst, tx, size, childPath, src.Bucket(k),
+			)
+		}
+
+		// If adding this pair would cause the transaction to exceed
+		// its maximum size, then we'll commit it and begin a new one.
+		pairSize := int64(len(k) + len(v))
+		if *size+pairSize > compactTxMaxSize {
+			if err := (*tx).Commit(); err != nil {
+				return err
+			}
+
+			newTx, err := dst.Begin(true)
+			if err != nil {
+				return err
+			}
+			*tx = newTx
+			*size = 0
+		}
+		*size += pairSize
+
+		return fetchDstBucket().Put(k, v)
+	})
+}
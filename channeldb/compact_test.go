@@ -0,0 +1,161 @@
+package channeldb
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/boltdb/bolt"
+)
+
+// TestCompact tests that a database with a large amount of reclaimable space
+// is compacted, and that all buckets, keys, values and sequence numbers are
+// preserved.
+func TestCompact(t *testing.T) {
+	t.Parallel()
+
+	tempDirName, err := ioutil.TempDir("", "channeldb")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDirName)
+
+	cdb, err := Open(tempDirName)
+	if err != nil {
+		t.Fatalf("unable to open db: %v", err)
+	}
+
+	var (
+		testBucket = []byte("test-bucket")
+		nestedKey  = []byte("nested")
+		keepKey    = []byte("keep")
+		keepValue  = bytes.Repeat([]byte{1}, 100)
+	)
+
+	// We'll fill the database with a large amount of data, keeping only a
+	// single key within a nested bucket. We'll also bump the sequence of
+	// the nested bucket, as it must survive compaction.
+	const numKeys = 1000
+	err = cdb.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucket(testBucket)
+		if err != nil {
+			return err
+		}
+		nested, err := b.CreateBucket(nestedKey)
+		if err != nil {
+			return err
+		}
+		if err := nested.SetSequence(42); err != nil {
+			return err
+		}
+		if err := nested.Put(keepKey, keepValue); err != nil {
+			return err
+		}
+
+		for i := 0; i < numKeys; i++ {
+			var k [8]byte
+			byteOrder.PutUint64(k[:], uint64(i))
+			if err := b.Put(k[:], make([]byte, 4096)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unable to write data: %v", err)
+	}
+
+	// Next, we'll delete all the data, leaving a large amount of free
+	// space within the database file.
+	err = cdb.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(testBucket)
+		for i := 0; i < numKeys; i++ {
+			var k [8]byte
+			byteOrder.PutUint64(k[:], uint64(i))
+			if err := b.Delete(k[:]); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unable to delete data: %v", err)
+	}
+	if err := cdb.Close(); err != nil {
+		t.Fatalf("unable to close db: %v", err)
+	}
+
+	dbFile := filepath.Join(tempDirName, dbName)
+	origInfo, err := os.Stat(dbFile)
+	if err != nil {
+		t.Fatalf("unable to stat db: %v", err)
+	}
+
+	// An unreachable threshold shouldn't result in compaction.
+	compacted, err := Compact(tempDirName, 1.1)
+	if err != nil {
+		t.Fatalf("unable to compact db: %v", err)
+	}
+	if compacted {
+		t.Fatalf("db shouldn't have been compacted")
+	}
+
+	compacted, err = Compact(tempDirName, 0.5)
+	if err != nil {
+		t.Fatalf("unable to compact db: %v", err)
+	}
+	if !compacted {
+		t.Fatalf("db should have been compacted")
+	}
+
+	newInfo, err := os.Stat(dbFile)
+	if err != nil {
+		t.Fatalf("unable to stat db: %v", err)
+	}
+	if newInfo.Size() >= origInfo.Size() {
+		t.Fatalf("db wasn't reduced in size: %v vs %v", newInfo.Size(),
+			origInfo.Size())
+	}
+	if fileExists(dbFile + compactTempSuffix) {
+		t.Fatalf("temporary compaction file wasn't removed")
+	}
+
+	// Finally, the compacted database should be usable, and contain all
+	// of the data which wasn't deleted.
+	cdb, err = Open(tempDirName)
+	if err != nil {
+		t.Fatalf("unable to open compacted db: %v", err)
+	}
+	defer cdb.Close()
+
+	err = cdb.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(testBucket)
+		if b == nil {
+			t.Fatalf("test bucket not found")
+		}
+		nested := b.Bucket(nestedKey)
+		if nested == nil {
+			t.Fatalf("nested bucket not found")
+		}
+		if nested.Sequence() != 42 {
+			t.Fatalf("expected sequence 42, got %v",
+				nested.Sequence())
+		}
+		if !bytes.Equal(nested.Get(keepKey), keepValue) {
+			t.Fatalf("wrong value for kept key")
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unable to read db: %v", err)
+	}
+
+	if _, err := cdb.FetchMeta(nil); err != nil {
+		t.Fatalf("unable to fetch meta: %v", err)
+	}
+}
@@ -27,6 +27,11 @@ type migration func(tx *bolt.Tx) error
 type version struct {
 	number    uint32
 	migration migration
+
+	// chunkedMigration, if set, is applied in place of the migration
+	// above, for heavyweight migrations that must be applied over a
+	// series of transactions.
+	chunkedMigration *chunkedMigration
 }
 
 var (
@@ -40,6 +45,47 @@ var (
 			number:    0,
 			migration: nil,
 		},
+		{
+			// The version of the database where payments are
+			// stored along with each of their HTLC attempts.
+			number:    1,
+			migration: migrateOutgoingPayments,
+		},
+		{
+			// The version of the database where every invoice is
+			// assigned an add index, and settled invoices are
+			// additionally assigned a settle index.
+			number:    2,
+			migration: migrateInvoiceIndexes,
+		},
+		{
+			// The version of the database where the revocation
+			// log only retains the data required to construct
+			// justice transactions.
+			number:           3,
+			chunkedMigration: migrateRevocationLog,
+		},
+		{
+			// The version of the database where payments are
+			// indexed by their sequence number, allowing them to
+			// be queried in pages.
+			number:    4,
+			migration: migratePaymentsIndex,
+		},
+		{
+			// The version of the database where graph nodes and
+			// channels are indexed by their update time, allowing
+			// the updates within a time horizon to be queried.
+			number:    5,
+			migration: migrateGraphUpdateIndexes,
+		},
+		{
+			// The version of the database where open channels are
+			// indexed by their funding outpoint, allowing them to
+			// be fetched directly.
+			number:    6,
+			migration: migrateChannelIndex,
+		},
 	}
 
 	// Big endian is the preferred byte order, due to cursor scans over
@@ -57,20 +103,39 @@ var bufPool = &sync.Pool{
 type DB struct {
 	*bolt.DB
 	dbPath string
+
+	// graphBatch coalesces concurrent graph updates into batched
+	// transactions. If nil, batching is disabled.
+	graphBatch *batchScheduler
 }
 
 // Open opens an existing channeldb. Any necessary schemas migrations due to
-// updates will take place as necessary.
-func Open(dbPath string) (*DB, error) {
+// updates will take place as necessary. The passed modifiers can be used to
+// customize the default Options of the database.
+func Open(dbPath string, modifiers ...OptionModifier) (*DB, error) {
+	opts := DefaultOptions()
+	for _, modifier := range modifiers {
+		modifier(&opts)
+	}
+
 	path := filepath.Join(dbPath, dbName)
 
 	if !fileExists(path) {
+		// A read-only database can't be created, so there's nothing
+		// to open.
+		if opts.ReadOnly {
+			return nil, ErrDBNotFound
+		}
+
 		if err := createChannelDB(dbPath); err != nil {
 			return nil, err
 		}
 	}
 
-	bdb, err := bolt.Open(path, dbFilePermission, nil)
+	bdb, err := bolt.Open(path, dbFilePermission, &bolt.Options{
+		ReadOnly: opts.ReadOnly,
+		Timeout:  opts.LockTimeout,
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -80,6 +145,23 @@ func Open(dbPath string) (*DB, error) {
 		dbPath: dbPath,
 	}
 
+	// As migrations can't be applied to a read-only database, we'll only
+	// ensure that it's already at the latest version.
+	if opts.ReadOnly {
+		if err := chanDB.checkVersion(dbVersions); err != nil {
+			bdb.Close()
+			return nil, err
+		}
+
+		return chanDB, nil
+	}
+
+	if opts.BatchCommitInterval > 0 {
+		chanDB.graphBatch = newBatchScheduler(
+			chanDB, opts.BatchCommitInterval,
+		)
+	}
+
 	// Synchronize the version of database and apply migrations if needed.
 	if err := chanDB.syncVersions(dbVersions); err != nil {
 		bdb.Close()
@@ -394,11 +476,16 @@ func (d *DB) MarkChannelAsOpen(outpoint *wire.OutPoint,
 		copy(confInfoKey[:len(confInfoPrefix)], confInfoPrefix)
 		copy(confInfoKey[len(confInfoPrefix):], b.Bytes())
 
+		// The funding transaction was confirmed within the block the
+		// short channel ID points to, so we'll record its height as the
+		// confirmation height. Legacy values are extended to the
+		// current length in the process.
 		confInfoBytes := openChanBucket.Get(confInfoKey)
-		infoCopy := make([]byte, len(confInfoBytes))
+		infoCopy := make([]byte, confInfoLen)
 		copy(infoCopy[:], confInfoBytes)
 
-		byteOrder.PutUint64(infoCopy[4:], openLoc.ToUint64())
+		byteOrder.PutUint64(infoCopy[4:12], openLoc.ToUint64())
+		byteOrder.PutUint32(infoCopy[12:16], openLoc.BlockHeight)
 
 		return openChanBucket.Put(confInfoKey, infoCopy)
 	})
@@ -479,6 +566,24 @@ func (d *DB) MarkChanFullyClosed(chanPoint *wire.OutPoint) error {
 	})
 }
 
+// checkVersion returns ErrDBNeedsMigration if the database isn't at the latest
+// version, as is required to open it in read-only mode.
+func (d *DB) checkVersion(versions []version) error {
+	meta, err := d.FetchMeta(nil)
+	if err != nil {
+		if err == ErrMetaNotFound {
+			return ErrDBNeedsMigration
+		}
+		return err
+	}
+
+	if meta.DbVersionNumber < getLatestDBVersion(versions) {
+		return ErrDBNeedsMigration
+	}
+
+	return nil
+}
+
 // syncVersions function is used for safe db version synchronization. It applies
 // migration functions to the current database and recovers the previous
 // state of db if at least one error/panic appeared during migration.
@@ -503,29 +608,51 @@ func (d *DB) syncVersions(versions []version) error {
 
 	log.Infof("Performing database schema migration")
 
-	// Otherwise, we fetch the migrations which need to applied, and
-	// execute them serially within a single database transaction to ensure
-	// the migration is atomic.
-	migrations, migrationVersions := getMigrationsToApply(versions,
-		meta.DbVersionNumber)
-	return d.Update(func(tx *bolt.Tx) error {
-		for i, migration := range migrations {
-			if migration == nil {
-				continue
-			}
+	migrationTracker.start(meta.DbVersionNumber, latestVersion)
+	defer migrationTracker.finish()
 
-			log.Infof("Applying migration #%v", migrationVersions[i])
+	// Otherwise, we apply each of the migrations which need to be applied
+	// serially. Each migration is applied atomically along with the bump
+	// of the database version, so if we're interrupted, then we'll resume
+	// from the first migration which wasn't applied.
+	for _, v := range versions {
+		if v.number <= meta.DbVersionNumber {
+			continue
+		}
 
-			if err := migration(tx); err != nil {
-				log.Infof("Unable to apply migration #%v",
-					migrationVersions[i])
+		if v.chunkedMigration != nil {
+			err := d.applyChunkedMigration(v, meta)
+			if err != nil {
 				return err
 			}
+
+			migrationTracker.finishMigration()
+			continue
 		}
 
-		meta.DbVersionNumber = latestVersion
-		return putMeta(meta, tx)
-	})
+		migrationTracker.startMigration(v.number, 0, 0)
+		err := d.Update(func(tx *bolt.Tx) error {
+			if v.migration != nil {
+				log.Infof("Applying migration #%v", v.number)
+
+				if err := v.migration(tx); err != nil {
+					log.Infof("Unable to apply migration #%v",
+						v.number)
+					return err
+				}
+			}
+
+			meta.DbVersionNumber = v.number
+			return putMeta(meta, tx)
+		})
+		if err != nil {
+			return err
+		}
+
+		migrationTracker.finishMigration()
+	}
+
+	return nil
 }
 
 // ChannelGraph returns a new instance of the directed channel graph.
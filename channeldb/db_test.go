@@ -5,6 +5,9 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
+
+	"github.com/boltdb/bolt"
 )
 
 func TestOpenWithCreate(t *testing.T) {
@@ -33,3 +36,93 @@ func TestOpenWithCreate(t *testing.T) {
 		t.Fatalf("channeldb failed to create data directory")
 	}
 }
+
+// TestOpenReadOnly tests that a database opened in read-only mode can be read
+// but not mutated, and that it's neither created nor migrated.
+func TestOpenReadOnly(t *testing.T) {
+	t.Parallel()
+
+	tempDirName, err := ioutil.TempDir("", "channeldb")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDirName)
+
+	// A database which doesn't exist can't be opened, and shouldn't be
+	// created.
+	dbPath := filepath.Join(tempDirName, "cdb")
+	if _, err := Open(dbPath, OptionReadOnly()); err != ErrDBNotFound {
+		t.Fatalf("expected ErrDBNotFound, got %v", err)
+	}
+	if fileExists(dbPath) {
+		t.Fatalf("read-only open created data directory")
+	}
+
+	cdb, err := Open(dbPath)
+	if err != nil {
+		t.Fatalf("unable to create channeldb: %v", err)
+	}
+
+	// While the database is held open by a writer, a read-only open
+	// should time out waiting for the lock.
+	_, err = Open(
+		dbPath, OptionReadOnly(),
+		OptionSetLockTimeout(100*time.Millisecond),
+	)
+	if err != bolt.ErrTimeout {
+		t.Fatalf("expected bolt.ErrTimeout, got %v", err)
+	}
+	if err := cdb.Close(); err != nil {
+		t.Fatalf("unable to close channeldb: %v", err)
+	}
+
+	// Once closed, any number of readers may open the database at once.
+	readers := make([]*DB, 2)
+	for i := range readers {
+		readers[i], err = Open(dbPath, OptionReadOnly())
+		if err != nil {
+			t.Fatalf("unable to open read-only channeldb: %v", err)
+		}
+		defer readers[i].Close()
+	}
+
+	meta, err := readers[0].FetchMeta(nil)
+	if err != nil {
+		t.Fatalf("unable to fetch meta: %v", err)
+	}
+	if meta.DbVersionNumber != getLatestDBVersion(dbVersions) {
+		t.Fatalf("expected version %v, got %v",
+			getLatestDBVersion(dbVersions), meta.DbVersionNumber)
+	}
+	if err := readers[1].PutMeta(meta); err != bolt.ErrDatabaseReadOnly {
+		t.Fatalf("expected bolt.ErrDatabaseReadOnly, got %v", err)
+	}
+}
+
+// TestOpenReadOnlyNeedsMigration tests that a database which hasn't been
+// migrated to the latest version can't be opened in read-only mode.
+func TestOpenReadOnlyNeedsMigration(t *testing.T) {
+	t.Parallel()
+
+	tempDirName, err := ioutil.TempDir("", "channeldb")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDirName)
+
+	cdb, err := Open(tempDirName)
+	if err != nil {
+		t.Fatalf("unable to create channeldb: %v", err)
+	}
+	if err := cdb.PutMeta(&Meta{DbVersionNumber: 0}); err != nil {
+		t.Fatalf("unable to put meta: %v", err)
+	}
+	if err := cdb.Close(); err != nil {
+		t.Fatalf("unable to close channeldb: %v", err)
+	}
+
+	_, err = Open(tempDirName, OptionReadOnly())
+	if err != ErrDBNeedsMigration {
+		t.Fatalf("expected ErrDBNeedsMigration, got %v", err)
+	}
+}
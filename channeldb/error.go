@@ -15,6 +15,10 @@ var (
 	// channels within the database.
 	ErrNoActiveChannels = fmt.Errorf("no active channels exist")
 
+	// ErrChannelNotFound is returned when attempting to fetch a specific
+	// open channel which doesn't exist.
+	ErrChannelNotFound = fmt.Errorf("channel not found")
+
 	// ErrNoPastDeltas is returned when the channel delta bucket hasn't been
 	// created.
 	ErrNoPastDeltas = fmt.Errorf("channel has no recorded deltas")
@@ -31,10 +35,72 @@ var (
 	// payment hash already exists.
 	ErrDuplicateInvoice = fmt.Errorf("invoice with payment hash already exists")
 
+	// ErrInvoiceAlreadySettled is returned when attempting to accept or
+	// cancel an invoice which has already been settled.
+	ErrInvoiceAlreadySettled = fmt.Errorf("invoice already settled")
+
+	// ErrInvoiceAlreadyCanceled is returned when attempting to update an
+	// invoice which has already been canceled.
+	ErrInvoiceAlreadyCanceled = fmt.Errorf("invoice already canceled")
+
+	// ErrInvoiceAlreadyAccepted is returned when attempting to accept an
+	// HTLC paying a hold invoice which already has an HTLC accepted.
+	ErrInvoiceAlreadyAccepted = fmt.Errorf("invoice already accepted")
+
+	// ErrInvoiceNotAccepted is returned when attempting to settle a hold
+	// invoice which has no HTLC accepted to pay it.
+	ErrInvoiceNotAccepted = fmt.Errorf("invoice hasn't been accepted")
+
+	// ErrNotHoldInvoice is returned when attempting to accept or settle an
+	// invoice as a hold invoice, while its preimage is already known.
+	ErrNotHoldInvoice = fmt.Errorf("invoice isn't a hold invoice")
+
+	// ErrHoldInvoicePreimageUnknown is returned when attempting to settle
+	// a hold invoice directly, rather than once its preimage is known.
+	ErrHoldInvoicePreimageUnknown = fmt.Errorf("preimage of hold " +
+		"invoice unknown")
+
+	// ErrInvoicePreimageMismatch is returned when attempting to settle a
+	// hold invoice with a preimage which doesn't match its payment hash.
+	ErrInvoicePreimageMismatch = fmt.Errorf("preimage doesn't match " +
+		"invoice payment hash")
+
 	// ErrNoPaymentsCreated is returned when bucket of payments hasn't been
 	// created.
 	ErrNoPaymentsCreated = fmt.Errorf("there are no existing payments")
 
+	// ErrPaymentNotInitiated is returned when a payment is being updated
+	// or fetched, but it hasn't yet been initiated.
+	ErrPaymentNotInitiated = fmt.Errorf("payment isn't initiated")
+
+	// ErrPaymentInFlight is returned when a payment is being initiated,
+	// but a prior payment to the same hash is still in flight.
+	ErrPaymentInFlight = fmt.Errorf("payment is in transition")
+
+	// ErrAlreadyPaid is returned when a payment is being initiated, but a
+	// prior payment to the same hash has already succeeded.
+	ErrAlreadyPaid = fmt.Errorf("invoice is already paid")
+
+	// ErrPaymentAlreadySucceeded is returned in the event we attempt to
+	// change the status of a payment already succeeded.
+	ErrPaymentAlreadySucceeded = fmt.Errorf("payment is already succeeded")
+
+	// ErrPaymentAlreadyFailed is returned in the event we attempt to
+	// register a new attempt for a payment that has already failed.
+	ErrPaymentAlreadyFailed = fmt.Errorf("payment has already failed")
+
+	// ErrAttemptNotFound is returned when an HTLC attempt is being
+	// updated, but it can't be found within the target payment.
+	ErrAttemptNotFound = fmt.Errorf("htlc attempt not found")
+
+	// ErrAttemptAlreadySettled is returned when we try to update an HTLC
+	// attempt that has already been settled.
+	ErrAttemptAlreadySettled = fmt.Errorf("attempt already settled")
+
+	// ErrAttemptAlreadyFailed is returned when we try to update an HTLC
+	// attempt that has already been failed.
+	ErrAttemptAlreadyFailed = fmt.Errorf("attempt already failed")
+
 	// ErrNodeNotFound is returned when node bucket exists, but node with
 	// specific identity can't be found.
 	ErrNodeNotFound = fmt.Errorf("link node with target identity not found")
@@ -85,4 +151,57 @@ var (
 	// ErrNoClosedChannels is returned when a node is queries for all the
 	// channels it has closed, but it hasn't yet closed any channels.
 	ErrNoClosedChannels = fmt.Errorf("no channel have been closed yet")
+
+	// ErrNodeNotBlacklisted is returned when an attempt is made to remove
+	// a node from the node blacklist, but the node isn't present within
+	// it.
+	ErrNodeNotBlacklisted = fmt.Errorf("node not found in blacklist")
+
+	// ErrChanBorked is returned when a caller attempts to mutate the state
+	// of a channel which has been marked as borked.
+	ErrChanBorked = fmt.Errorf("cannot mutate borked channel")
+
+	// ErrNoWitnesses is returned when a witness being looked up isn't
+	// present within the witness cache.
+	ErrNoWitnesses = fmt.Errorf("no witnesses")
+
+	// ErrUnknownWitnessType is returned when attempting to store or
+	// retrieve a witness of a type the witness cache doesn't recognize.
+	ErrUnknownWitnessType = fmt.Errorf("unknown witness type")
+
+	// ErrSettleConsumerNotFound is returned when attempting to operate on
+	// an invoice settlement consumer which hasn't been registered.
+	ErrSettleConsumerNotFound = fmt.Errorf("invoice settlement consumer " +
+		"not found")
+
+	// ErrSettleIndexUnknown is returned when a consumer attempts to
+	// acknowledge an invoice settlement event with a settle index which
+	// hasn't yet been assigned.
+	ErrSettleIndexUnknown = fmt.Errorf("settle index is beyond the " +
+		"latest settled invoice")
+
+	// ErrTooManySettleConsumers is returned when attempting to register
+	// a new invoice settlement consumer beyond the maximum number of
+	// consumers.
+	ErrTooManySettleConsumers = fmt.Errorf("too many invoice settlement " +
+		"consumers")
+
+	// ErrPeerAddressesNotFound is returned when the address book doesn't
+	// contain any addresses for the target peer.
+	ErrPeerAddressesNotFound = fmt.Errorf("no addresses found for peer")
+
+	// ErrDBNotFound is returned when attempting to open a database in
+	// read-only mode which doesn't yet exist, as it can't be created.
+	ErrDBNotFound = fmt.Errorf("channel db not found")
+
+	// ErrDBNeedsMigration is returned when attempting to open a database
+	// in read-only mode which hasn't yet been migrated to the latest
+	// version, as the migrations can't be applied.
+	ErrDBNeedsMigration = fmt.Errorf("channel db requires migration " +
+		"which can't be applied in read-only mode")
+
+	// ErrReplayedPacket is returned when attempting to add an onion
+	// packet to the replay log which has already been processed.
+	ErrReplayedPacket = fmt.Errorf("onion packet has already been " +
+		"processed")
 )
@@ -1 +1,217 @@
 package channeldb
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+var (
+	// forwardingLogBucket is a top-level bucket which houses the log of
+	// HTLCs that were successfully forwarded by the switch. Keys are
+	// prefixed by the time of the forward, so the log is ordered
+	// chronologically, while the sequence number ensures that forwards
+	// occurring within the same nanosecond don't overwrite each other.
+	//
+	// maps: timestampNanos || seqNum ->
+	//   incomingChanID || outgoingChanID || amtIn || amtOut
+	forwardingLogBucket = []byte("forwarding-log")
+)
+
+const (
+	// forwardingEventKeySize is the size of the key of a forwarding event
+	// within the forwarding log.
+	forwardingEventKeySize = 16
+
+	// forwardingEventSize is the size of a serialized forwarding event.
+	forwardingEventSize = 32
+)
+
+// ForwardingEvent is an HTLC which was successfully forwarded across our
+// node, that is, one which was settled by the outgoing channel.
+type ForwardingEvent struct {
+	// Timestamp is the time at which the forward was settled.
+	Timestamp time.Time
+
+	// IncomingChanID is the channel the HTLC was received over.
+	IncomingChanID lnwire.ShortChannelID
+
+	// OutgoingChanID is the channel the HTLC was forwarded over.
+	OutgoingChanID lnwire.ShortChannelID
+
+	// AmtIn is the value of the incoming HTLC.
+	AmtIn lnwire.MilliAtom
+
+	// AmtOut is the value of the outgoing HTLC.
+	AmtOut lnwire.MilliAtom
+}
+
+// Fee returns the fee earned by forwarding the HTLC.
+func (f *ForwardingEvent) Fee() lnwire.MilliAtom {
+	if f.AmtOut > f.AmtIn {
+		return 0
+	}
+
+	return f.AmtIn - f.AmtOut
+}
+
+// AddForwardingEvents appends the passed events to the forwarding log within
+// a single transaction.
+func (d *DB) AddForwardingEvents(events []ForwardingEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	return d.Update(func(tx *bolt.Tx) error {
+		fwdLog, err := tx.CreateBucketIfNotExists(forwardingLogBucket)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			seqNum, err := fwdLog.NextSequence()
+			if err != nil {
+				return err
+			}
+
+			var key [forwardingEventKeySize]byte
+			byteOrder.PutUint64(
+				key[:8], uint64(event.Timestamp.UnixNano()),
+			)
+			byteOrder.PutUint64(key[8:], seqNum)
+
+			var value [forwardingEventSize]byte
+			byteOrder.PutUint64(
+				value[:8], event.IncomingChanID.ToUint64(),
+			)
+			byteOrder.PutUint64(
+				value[8:16], event.OutgoingChanID.ToUint64(),
+			)
+			byteOrder.PutUint64(value[16:24], uint64(event.AmtIn))
+			byteOrder.PutUint64(value[24:], uint64(event.AmtOut))
+
+			if err := fwdLog.Put(key[:], value[:]); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// ForwardingEvents returns every event within the forwarding log which
+// occurred at or after the passed time, ordered by time.
+func (d *DB) ForwardingEvents(since time.Time) ([]ForwardingEvent, error) {
+	var events []ForwardingEvent
+	err := d.View(func(tx *bolt.Tx) error {
+		fwdLog := tx.Bucket(forwardingLogBucket)
+		if fwdLog == nil {
+			return nil
+		}
+
+		var start [8]byte
+		if since.UnixNano() > 0 {
+			byteOrder.PutUint64(start[:], uint64(since.UnixNano()))
+		}
+
+		c := fwdLog.Cursor()
+		for k, v := c.Seek(start[:]); k != nil; k, v = c.Next() {
+			if len(k) != forwardingEventKeySize ||
+				len(v) != forwardingEventSize {
+
+				continue
+			}
+
+			timestamp := int64(byteOrder.Uint64(k[:8]))
+			events = append(events, ForwardingEvent{
+				Timestamp: time.Unix(0, timestamp),
+				IncomingChanID: lnwire.NewShortChanIDFromInt(
+					byteOrder.Uint64(v[:8]),
+				),
+				OutgoingChanID: lnwire.NewShortChanIDFromInt(
+					byteOrder.Uint64(v[8:16]),
+				),
+				AmtIn: lnwire.MilliAtom(
+					byteOrder.Uint64(v[16:24]),
+				),
+				AmtOut: lnwire.MilliAtom(
+					byteOrder.Uint64(v[24:]),
+				),
+			})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// DeleteForwardingEvents removes every event within the forwarding log which
+// occurred before the passed time, returning the number of events removed.
+func (d *DB) DeleteForwardingEvents(before time.Time) (int, error) {
+	var numDeleted int
+	err := d.Update(func(tx *bolt.Tx) error {
+		fwdLog := tx.Bucket(forwardingLogBucket)
+		if fwdLog == nil {
+			return nil
+		}
+
+		var end [8]byte
+		if before.UnixNano() > 0 {
+			byteOrder.PutUint64(end[:], uint64(before.UnixNano()))
+		}
+
+		// As the log is ordered chronologically, the expired events
+		// are at its start. Their keys are gathered before deleting
+		// them, as the cursor can't be relied upon while the bucket
+		// is being modified.
+		var expired [][]byte
+		c := fwdLog.Cursor()
+		for k, _ := c.First(); k != nil; k, _ = c.Next() {
+			if bytes.Compare(k, end[:]) >= 0 {
+				break
+			}
+
+			expired = append(expired, k)
+		}
+
+		for _, k := range expired {
+			if err := fwdLog.Delete(k); err != nil {
+				return err
+			}
+		}
+
+		numDeleted = len(expired)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return numDeleted, nil
+}
+
+// ForwardingFees returns the fees earned by each channel from the forwards
+// which occurred at or after the passed time. The fee of a forward is
+// attributed to its outgoing channel, as it's the policy of the outgoing
+// channel which determines the fee charged.
+func (d *DB) ForwardingFees(
+	since time.Time) (map[lnwire.ShortChannelID]lnwire.MilliAtom, error) {
+
+	events, err := d.ForwardingEvents(since)
+	if err != nil {
+		return nil, err
+	}
+
+	fees := make(map[lnwire.ShortChannelID]lnwire.MilliAtom)
+	for i := range events {
+		fees[events[i].OutgoingChanID] += events[i].Fee()
+	}
+
+	return fees, nil
+}
@@ -0,0 +1,134 @@
+package channeldb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// TestForwardingLog tests that forwarding events can be added to, retrieved
+// from and pruned from the forwarding log, and that the fees earned by each
+// channel are aggregated correctly.
+func TestForwardingLog(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+
+	// Querying an empty log should return no events or fees.
+	events, err := db.ForwardingEvents(time.Time{})
+	if err != nil {
+		t.Fatalf("unable to fetch forwarding events: %v", err)
+	}
+	if len(events) != 0 {
+		t.Fatalf("expected no events, got %v", len(events))
+	}
+
+	chanA := lnwire.NewShortChanIDFromInt(1)
+	chanB := lnwire.NewShortChanIDFromInt(2)
+	chanC := lnwire.NewShortChanIDFromInt(3)
+
+	now := time.Unix(0, time.Now().UnixNano())
+	fwds := []ForwardingEvent{
+		{
+			Timestamp:      now.Add(-48 * time.Hour),
+			IncomingChanID: chanA,
+			OutgoingChanID: chanB,
+			AmtIn:          1100,
+			AmtOut:         1000,
+		},
+		{
+			Timestamp:      now.Add(-time.Hour),
+			IncomingChanID: chanA,
+			OutgoingChanID: chanB,
+			AmtIn:          2020,
+			AmtOut:         2000,
+		},
+		{
+			Timestamp:      now.Add(-time.Hour),
+			IncomingChanID: chanB,
+			OutgoingChanID: chanC,
+			AmtIn:          5005,
+			AmtOut:         5000,
+		},
+	}
+	if err := db.AddForwardingEvents(fwds); err != nil {
+		t.Fatalf("unable to add forwarding events: %v", err)
+	}
+
+	// All events should be returned in chronological order, including
+	// the two which occurred at the same time.
+	events, err = db.ForwardingEvents(time.Time{})
+	if err != nil {
+		t.Fatalf("unable to fetch forwarding events: %v", err)
+	}
+	if len(events) != len(fwds) {
+		t.Fatalf("expected %v events, got %v", len(fwds), len(events))
+	}
+	for i, event := range events {
+		if !event.Timestamp.Equal(fwds[i].Timestamp) {
+			t.Fatalf("expected timestamp %v, got %v",
+				fwds[i].Timestamp, event.Timestamp)
+		}
+		event.Timestamp = fwds[i].Timestamp
+		if event != fwds[i] {
+			t.Fatalf("expected event %v, got %v", fwds[i], event)
+		}
+	}
+
+	// The fees should be attributed to the outgoing channel of each
+	// forward, and only those within the window should be accounted for.
+	assertFees := func(since time.Time,
+		expected map[lnwire.ShortChannelID]lnwire.MilliAtom) {
+
+		fees, err := db.ForwardingFees(since)
+		if err != nil {
+			t.Fatalf("unable to fetch forwarding fees: %v", err)
+		}
+		if len(fees) != len(expected) {
+			t.Fatalf("expected fees for %v channels, got %v",
+				len(expected), len(fees))
+		}
+		for chanID, fee := range expected {
+			if fees[chanID] != fee {
+				t.Fatalf("expected fee %v for channel %v, "+
+					"got %v", fee, chanID, fees[chanID])
+			}
+		}
+	}
+	assertFees(now.Add(-24*time.Hour), map[lnwire.ShortChannelID]lnwire.MilliAtom{
+		chanB: 20,
+		chanC: 5,
+	})
+	assertFees(now.Add(-7*24*time.Hour), map[lnwire.ShortChannelID]lnwire.MilliAtom{
+		chanB: 120,
+		chanC: 5,
+	})
+	assertFees(now, map[lnwire.ShortChannelID]lnwire.MilliAtom{})
+
+	// Pruning the log should only remove the events which occurred
+	// before the cutoff, after which they no longer count towards the
+	// fees earned.
+	numDeleted, err := db.DeleteForwardingEvents(now.Add(-24 * time.Hour))
+	if err != nil {
+		t.Fatalf("unable to delete forwarding events: %v", err)
+	}
+	if numDeleted != 1 {
+		t.Fatalf("expected 1 deleted event, got %v", numDeleted)
+	}
+	events, err = db.ForwardingEvents(time.Time{})
+	if err != nil {
+		t.Fatalf("unable to fetch forwarding events: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %v", len(events))
+	}
+	assertFees(now.Add(-7*24*time.Hour), map[lnwire.ShortChannelID]lnwire.MilliAtom{
+		chanB: 20,
+		chanC: 5,
+	})
+}
@@ -41,6 +41,14 @@ var (
 	// future UI layer to add an additional degree of confirmation.
 	aliasIndexBucket = []byte("alias")
 
+	// nodeUpdateIndexBucket is a sub-bucket that's nested within the main
+	// nodeBucket. This bucket indexes each node by the time of its most
+	// recent update, allowing the nodes updated within a particular time
+	// horizon to be retrieved with a range scan.
+	//
+	// maps: updateTime || pubKey -> nil
+	nodeUpdateIndexBucket = []byte("graph-node-update-index")
+
 	// edgeBucket is a bucket which houses all of the edge or channel
 	// information within the channel graph. This bucket essentially acts
 	// as an adjacency list, which in conjunction with a range scan, can be
@@ -72,6 +80,16 @@ var (
 	// maps: chanID -> pubKey1 || pubKey2 || restofEdgeInfo
 	edgeIndexBucket = []byte("edge-index")
 
+	// edgeUpdateIndexBucket is a sub-bucket that's nested within the main
+	// edgeBucket. This bucket indexes each channel by the time of the most
+	// recent update of each of its directed edges, allowing the channels
+	// updated within a particular time horizon to be retrieved with a
+	// range scan. A channel whose directed edges were last updated at the
+	// same time has a single entry.
+	//
+	// maps: updateTime || chanID -> nil
+	edgeUpdateIndexBucket = []byte("edge-update-index")
+
 	// channelPointBucket maps a channel's full outpoint (txid:index) to
 	// its short 8-byte channel ID. This bucket resides within the
 	// edgeBucket above, and can be used to quickly remove an edge due to
@@ -123,6 +141,11 @@ const (
 	tcp4Addr  addressType = 0
 	tcp6Addr  addressType = 1
 	onionAddr addressType = 2
+	dnsAddr   addressType = 3
+
+	// opaqueAddrs denotes the raw trailing portion of a node's address
+	// list, holding addresses of a type unknown to us.
+	opaqueAddrs addressType = 4
 )
 
 // ForEachChannel iterates through all the channel edges stored within the
@@ -336,10 +359,36 @@ func (c *ChannelGraph) SetSourceNode(node *LightningNode) error {
 // in a channel update.
 //
 // TODO(roasbeef): also need sig of announcement
-func (c *ChannelGraph) AddLightningNode(node *LightningNode) error {
-	return c.db.Update(func(tx *bolt.Tx) error {
-		return addLightningNode(tx, node)
-	})
+func (c *ChannelGraph) AddLightningNode(node *LightningNode,
+	opts ...BatchOption) error {
+
+	return c.batchUpdate(&batchRequest{
+		update: func(tx *bolt.Tx) error {
+			return addLightningNode(tx, node)
+		},
+	}, opts...)
+}
+
+// batchUpdate applies the graph update described by the request. If batching
+// is enabled, then the update is coalesced with any other graph updates
+// pending within the batch commit interval into a single transaction.
+// Otherwise, the update is applied within its own transaction.
+func (c *ChannelGraph) batchUpdate(req *batchRequest,
+	opts ...BatchOption) error {
+
+	for _, opt := range opts {
+		opt(req)
+	}
+
+	if c.db.graphBatch != nil {
+		return c.db.graphBatch.execute(req)
+	}
+
+	err := c.db.Update(req.update)
+	if req.onCommit != nil {
+		return req.onCommit(err)
+	}
+	return err
 }
 
 func addLightningNode(tx *bolt.Tx, node *LightningNode) error {
@@ -402,16 +451,33 @@ func (c *ChannelGraph) DeleteLightningNode(nodePub *btcec.PublicKey) error {
 			return err
 		}
 
-		aliases, err := tx.CreateBucketIfNotExists(aliasIndexBucket)
-		if err != nil {
+		return delLightningNode(nodes, pub)
+	})
+}
+
+// delLightningNode removes the node with the passed public key from the nodes
+// bucket, along with its entries within the alias and update indexes.
+func delLightningNode(nodes *bolt.Bucket, pub []byte) error {
+	if aliases := nodes.Bucket(aliasIndexBucket); aliases != nil {
+		if err := aliases.Delete(pub); err != nil {
 			return err
 		}
+	}
 
-		if err := aliases.Delete(pub); err != nil {
+	// Remove the node's entry within the update index, which is keyed by
+	// the update time stored within the node's record.
+	nodeBytes := nodes.Get(pub)
+	updateIndex := nodes.Bucket(nodeUpdateIndexBucket)
+	if len(nodeBytes) >= 8 && updateIndex != nil {
+		var indexKey [8 + 33]byte
+		copy(indexKey[:8], nodeBytes[:8])
+		copy(indexKey[8:], pub)
+		if err := updateIndex.Delete(indexKey[:]); err != nil {
 			return err
 		}
-		return nodes.Delete(pub)
-	})
+	}
+
+	return nodes.Delete(pub)
 }
 
 // AddChannelEdge adds a new (undirected, blank) edge to the graph database. An
@@ -420,12 +486,21 @@ func (c *ChannelGraph) DeleteLightningNode(nodePub *btcec.PublicKey) error {
 // the keys involved in creation of the channel, and the set of features that
 // the channel supports. The chanPoint and chanID are used to uniquely identify
 // the edge globally within the database.
-func (c *ChannelGraph) AddChannelEdge(edge *ChannelEdgeInfo) error {
+func (c *ChannelGraph) AddChannelEdge(edge *ChannelEdgeInfo,
+	opts ...BatchOption) error {
+
 	// Construct the channel's primary key which is the 8-byte channel ID.
 	var chanKey [8]byte
 	binary.BigEndian.PutUint64(chanKey[:], edge.ChannelID)
 
-	return c.db.Update(func(tx *bolt.Tx) error {
+	// As the edge may be added within a batch along with other graph
+	// updates, the edge already existing mustn't fail the entire batch.
+	// Instead, we'll note its existence and report it once the batch has
+	// been committed.
+	var alreadyExists bool
+	update := func(tx *bolt.Tx) error {
+		alreadyExists = false
+
 		edges, err := tx.CreateBucketIfNotExists(edgeBucket)
 		if err != nil {
 			return err
@@ -443,7 +518,8 @@ func (c *ChannelGraph) AddChannelEdge(edge *ChannelEdgeInfo) error {
 		// created. If so, then we can exit early as this method is
 		// meant to be idempotent.
 		if edgeInfo := edgeIndex.Get(chanKey[:]); edgeInfo != nil {
-			return ErrEdgeAlreadyExist
+			alreadyExists = true
+			return nil
 		}
 
 		// If the edge hasn't been created yet, then we'll first add it
@@ -460,7 +536,17 @@ func (c *ChannelGraph) AddChannelEdge(edge *ChannelEdgeInfo) error {
 			return err
 		}
 		return chanIndex.Put(b.Bytes(), chanKey[:])
-	})
+	}
+
+	return c.batchUpdate(&batchRequest{
+		update: update,
+		onCommit: func(err error) error {
+			if err == nil && alreadyExists {
+				return ErrEdgeAlreadyExist
+			}
+			return err
+		},
+	}, opts...)
 }
 
 // HasChannelEdge returns true if the database knows of a channel edge with the
@@ -601,7 +687,7 @@ func (c *ChannelGraph) PruneGraph(spentOutputs []*wire.OutPoint,
 
 			var opBytes bytes.Buffer
 			if err := writeOutpoint(&opBytes, chanPoint); err != nil {
-				return nil
+				return err
 			}
 
 			// First attempt to see if the channel exists within
@@ -652,6 +738,88 @@ func (c *ChannelGraph) PruneGraph(spentOutputs []*wire.OutPoint,
 	return chansClosed, nil
 }
 
+// PruneGraphNodes removes all nodes from the channel graph which no longer
+// have any channels, such as those whose channels have all been closed. The
+// source node is never removed. The number of nodes removed is returned. This
+// is intended to be run after the graph has been pruned of closed channels.
+func (c *ChannelGraph) PruneGraphNodes() (int, error) {
+	var numPruned int
+	err := c.db.Update(func(tx *bolt.Tx) error {
+		numPruned = 0
+
+		nodes := tx.Bucket(nodeBucket)
+		if nodes == nil {
+			return nil
+		}
+
+		// We'll first gather the set of nodes which remain connected
+		// to at least one channel. Each entry within the edge index
+		// leads with the public keys of the channel's two nodes.
+		connected := make(map[[33]byte]struct{})
+		var edgeIndex *bolt.Bucket
+		if edges := tx.Bucket(edgeBucket); edges != nil {
+			edgeIndex = edges.Bucket(edgeIndexBucket)
+		}
+		if edgeIndex != nil {
+			err := edgeIndex.ForEach(func(_, edgeInfo []byte) error {
+				var pub1, pub2 [33]byte
+				copy(pub1[:], edgeInfo[:33])
+				copy(pub2[:], edgeInfo[33:66])
+				connected[pub1] = struct{}{}
+				connected[pub2] = struct{}{}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		// As the nodes bucket can't be modified while iterating over
+		// it, we'll collect the unconnected nodes before deleting
+		// them.
+		sourcePub := nodes.Get(sourceKey)
+		var unconnected [][]byte
+		err := nodes.ForEach(func(pub, nodeBytes []byte) error {
+			// Skip the source key, the nested index buckets, and
+			// the source node itself.
+			if len(pub) != 33 || nodeBytes == nil ||
+				bytes.Equal(pub, sourcePub) {
+
+				return nil
+			}
+
+			var nodePub [33]byte
+			copy(nodePub[:], pub)
+			if _, ok := connected[nodePub]; ok {
+				return nil
+			}
+
+			unconnected = append(unconnected, nodePub[:])
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, pub := range unconnected {
+			if err := delLightningNode(nodes, pub); err != nil {
+				return err
+			}
+
+			log.Tracef("Pruned unconnected node %x from channel "+
+				"graph", pub)
+		}
+		numPruned = len(unconnected)
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return numPruned, nil
+}
+
 // PruneTip returns the block height and hash of the latest block that has been
 // used to prune channels in the graph. Knowing the "prune tip" allows callers
 // to tell if the graph is currently in sync with the current best known UTXO
@@ -780,22 +948,42 @@ func delChannelByEdge(edges *bolt.Bucket, edgeIndex *bolt.Bucket,
 
 	// With the latter half constructed, copy over the first public
 	// key to delete the edge in this direction, then the second to
-	// delete the edge in the opposite direction.
-	copy(edgeKey[:33], nodeKeys[:33])
-	if edges.Get(edgeKey[:]) != nil {
-		if err := edges.Delete(edgeKey[:]); err != nil {
-			return err
+	// delete the edge in the opposite direction. The update index entry
+	// of each direction is removed along with it.
+	updateIndex := edges.Bucket(edgeUpdateIndexBucket)
+	for _, nodeKey := range [][]byte{nodeKeys[:33], nodeKeys[33:66]} {
+		copy(edgeKey[:33], nodeKey)
+
+		edgeBytes := edges.Get(edgeKey[:])
+		if edgeBytes == nil {
+			continue
 		}
-	}
-	copy(edgeKey[:33], nodeKeys[33:])
-	if edges.Get(edgeKey[:]) != nil {
+
+		if updateIndex != nil {
+			updateUnix, err := edgePolicyUpdateTime(edgeBytes)
+			if err != nil {
+				return err
+			}
+
+			var indexKey [8 + 8]byte
+			byteOrder.PutUint64(indexKey[:8], updateUnix)
+			copy(indexKey[8:], chanID)
+			if err := updateIndex.Delete(indexKey[:]); err != nil {
+				return err
+			}
+		}
+
 		if err := edges.Delete(edgeKey[:]); err != nil {
 			return err
 		}
 	}
 
 	// Finally, with the edge data deleted, we can purge the
-	// information from the two edge indexes.
+	// information from the two edge indexes, along with the zombie
+	// index.
+	if err := delZombieEdge(edges, chanID); err != nil {
+		return err
+	}
 	if err := edgeIndex.Delete(chanID); err != nil {
 		return err
 	}
@@ -809,8 +997,16 @@ func delChannelByEdge(edges *bolt.Bucket, edgeIndex *bolt.Bucket,
 // updated, otherwise it's the second node's information. The node ordering is
 // determined tby the lexicographical ordering of the identity public keys of
 // the nodes on either side of the channel.
-func (c *ChannelGraph) UpdateEdgePolicy(edge *ChannelEdgePolicy) error {
-	return c.db.Update(func(tx *bolt.Tx) error {
+func (c *ChannelGraph) UpdateEdgePolicy(edge *ChannelEdgePolicy,
+	opts ...BatchOption) error {
+
+	// As with AddChannelEdge, an unknown edge mustn't fail the batch the
+	// update is a part of, so we'll report it once the batch has been
+	// committed.
+	var edgeNotFound bool
+	update := func(tx *bolt.Tx) error {
+		edgeNotFound = false
+
 		edges, err := tx.CreateBucketIfNotExists(edgeBucket)
 		if err != nil {
 			return err
@@ -829,13 +1025,14 @@ func (c *ChannelGraph) UpdateEdgePolicy(edge *ChannelEdgePolicy) error {
 		// nodes which connect this channel edge.
 		nodeInfo := edgeIndex.Get(chanID[:])
 		if nodeInfo == nil {
-			return ErrEdgeNotFound
+			edgeNotFound = true
+			return nil
 		}
 
 		// Depending on the flags value passed above, either the first
 		// or second edge policy is being updated.
 		var fromNode, toNode []byte
-		if edge.Flags == 0 {
+		if edge.ChannelFlags&lnwire.ChanUpdateDirection == 0 {
 			fromNode = nodeInfo[:33]
 			toNode = nodeInfo[33:67]
 		} else {
@@ -843,10 +1040,26 @@ func (c *ChannelGraph) UpdateEdgePolicy(edge *ChannelEdgePolicy) error {
 			toNode = nodeInfo[:33]
 		}
 
-		// Finally, with the direction of the edge being updated
-		// identified, we update the on-disk edge representation.
-		return putChanEdgePolicy(edges, edge, fromNode, toNode)
-	})
+		// With the direction of the edge being updated identified, we
+		// update the on-disk edge representation.
+		if err := putChanEdgePolicy(edges, edge, fromNode, toNode); err != nil {
+			return err
+		}
+
+		// Finally, if the channel was marked as a zombie, then this
+		// fresh update resurrects it.
+		return resurrectZombieEdge(edges, chanID[:], edge.LastUpdate)
+	}
+
+	return c.batchUpdate(&batchRequest{
+		update: update,
+		onCommit: func(err error) error {
+			if err == nil && edgeNotFound {
+				return ErrEdgeNotFound
+			}
+			return err
+		},
+	}, opts...)
 }
 
 // LightningNode represents an individual vertex/node within the channel graph.
@@ -1202,10 +1415,13 @@ type ChannelEdgePolicy struct {
 	// was received.
 	LastUpdate time.Time
 
-	// Flags is a bitfield which signals the capabilities of the channel as
-	// well as the directed edge this update applies to.
-	// TODO(roasbeef):  make into wire struct
-	Flags uint16
+	// MessageFlags is a bitfield which indicates the presence of optional
+	// fields (like max_htlc) in the policy.
+	MessageFlags lnwire.ChanUpdateMsgFlags
+
+	// ChannelFlags is a bitfield which signals the capabilities of the
+	// channel as well as the directed edge this update applies to.
+	ChannelFlags lnwire.ChanUpdateChanFlags
 
 	// TimeLockDelta is the number of blocks this node will subtract from
 	// the expiry of an incoming HTLC. This value expresses the time buffer
@@ -1214,15 +1430,20 @@ type ChannelEdgePolicy struct {
 
 	// MinHTLC is the smallest value HTLC this node will accept, expressed
 	// in millisatoshi.
-	MinHTLC lnwire.MilliSatoshi
+	MinHTLC lnwire.MilliAtom
+
+	// MaxHTLC is the largest value HTLC this node will accept, expressed
+	// in millisatoshi. It is only set if the MessageFlags signal its
+	// presence.
+	MaxHTLC lnwire.MilliAtom
 
 	// FeeBaseMSat is the base HTLC fee that will be charged for forwarding
 	// ANY HTLC, expressed in mSAT's.
-	FeeBaseMSat lnwire.MilliSatoshi
+	FeeBaseMSat lnwire.MilliAtom
 
 	// FeeProportionalMillionths is the rate that the node will charge for
 	// HTLCs for each millionth of a satoshi forwarded.
-	FeeProportionalMillionths lnwire.MilliSatoshi
+	FeeProportionalMillionths lnwire.MilliAtom
 
 	// Node is the LightningNode that this directed edge leads to. Using
 	// this pointer the channel graph can further be traversed.
@@ -1366,6 +1587,144 @@ func (c *ChannelGraph) FetchChannelEdgesByID(chanID uint64) (*ChannelEdgeInfo, *
 	return edgeInfo, policy1, policy2, nil
 }
 
+// ChannelEdge bundles the information of a channel with its two directed edge
+// policies. Either policy may be nil if it hasn't yet been announced.
+type ChannelEdge struct {
+	// Info is the static information of the channel.
+	Info *ChannelEdgeInfo
+
+	// Policy1 is the routing policy of the first node of the channel.
+	Policy1 *ChannelEdgePolicy
+
+	// Policy2 is the routing policy of the second node of the channel.
+	Policy2 *ChannelEdgePolicy
+}
+
+// ChanUpdatesInHorizon returns all the known channels which have had at least
+// one of their directed edges updated within the passed time horizon,
+// inclusive of both ends. Each channel is returned at most once, along with
+// both of its edge policies, in order of the time of its matching update.
+// Channels marked as zombies are omitted, as they're never relayed to peers.
+func (c *ChannelGraph) ChanUpdatesInHorizon(startTime,
+	endTime time.Time) ([]ChannelEdge, error) {
+
+	var edgesInHorizon []ChannelEdge
+	err := c.db.View(func(tx *bolt.Tx) error {
+		// If no edge policies have been written yet, then there are no
+		// updates within any horizon.
+		nodes := tx.Bucket(nodeBucket)
+		edges := tx.Bucket(edgeBucket)
+		if nodes == nil || edges == nil {
+			return nil
+		}
+		edgeIndex := edges.Bucket(edgeIndexBucket)
+		updateIndex := edges.Bucket(edgeUpdateIndexBucket)
+		if edgeIndex == nil || updateIndex == nil {
+			return nil
+		}
+		zombieIndex := edges.Bucket(zombieIndexBucket)
+
+		// As the index is keyed by the update time, we can seek to the
+		// start of the horizon and scan forward until we pass its end.
+		var startKey, endKey [8 + 8]byte
+		byteOrder.PutUint64(startKey[:8], uint64(startTime.Unix()))
+		byteOrder.PutUint64(endKey[:8], uint64(endTime.Unix()))
+		copy(endKey[8:], bytes.Repeat([]byte{0xff}, 8))
+
+		seen := make(map[uint64]struct{})
+		cursor := updateIndex.Cursor()
+		for k, _ := cursor.Seek(startKey[:]); k != nil &&
+			bytes.Compare(k, endKey[:]) <= 0; k, _ = cursor.Next() {
+
+			chanID := k[8:]
+
+			// A channel with both directions updated within the
+			// horizon has two entries, so we'll skip any we've
+			// already returned.
+			id := byteOrder.Uint64(chanID)
+			if _, ok := seen[id]; ok {
+				continue
+			}
+			seen[id] = struct{}{}
+
+			if zombieIndex != nil && zombieIndex.Get(chanID) != nil {
+				continue
+			}
+
+			info, err := fetchChanEdgeInfo(edgeIndex, chanID)
+			if err != nil {
+				return err
+			}
+
+			policy1, policy2, err := fetchChanEdgePolicies(
+				edgeIndex, edges, nodes, chanID, c.db,
+			)
+			if err != nil {
+				return err
+			}
+
+			edgesInHorizon = append(edgesInHorizon, ChannelEdge{
+				Info:    info,
+				Policy1: policy1,
+				Policy2: policy2,
+			})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return edgesInHorizon, nil
+}
+
+// NodeUpdatesInHorizon returns all the known nodes which were last updated
+// within the passed time horizon, inclusive of both ends, in order of the time
+// of their last update.
+func (c *ChannelGraph) NodeUpdatesInHorizon(startTime,
+	endTime time.Time) ([]LightningNode, error) {
+
+	var nodesInHorizon []LightningNode
+	err := c.db.View(func(tx *bolt.Tx) error {
+		// If no nodes have been written yet, then there are no updates
+		// within any horizon.
+		nodes := tx.Bucket(nodeBucket)
+		if nodes == nil {
+			return nil
+		}
+		updateIndex := nodes.Bucket(nodeUpdateIndexBucket)
+		if updateIndex == nil {
+			return nil
+		}
+
+		var startKey, endKey [8 + 33]byte
+		byteOrder.PutUint64(startKey[:8], uint64(startTime.Unix()))
+		byteOrder.PutUint64(endKey[:8], uint64(endTime.Unix()))
+		copy(endKey[8:], bytes.Repeat([]byte{0xff}, 33))
+
+		cursor := updateIndex.Cursor()
+		for k, _ := cursor.Seek(startKey[:]); k != nil &&
+			bytes.Compare(k, endKey[:]) <= 0; k, _ = cursor.Next() {
+
+			node, err := fetchLightningNode(nodes, k[8:])
+			if err != nil {
+				return err
+			}
+			node.db = c.db
+
+			nodesInHorizon = append(nodesInHorizon, *node)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return nodesInHorizon, nil
+}
+
 // ChannelView returns the verifiable edge information for each active channel
 // within the known channel graph. The set of UTXO's returned are the ones that
 // need to be watched on chain to detect channel closes on the resident
@@ -1412,6 +1771,12 @@ func (c *ChannelGraph) NewChannelEdgePolicy() *ChannelEdgePolicy {
 	return &ChannelEdgePolicy{db: c.db}
 }
 
+// Database returns a pointer to the underlying database, which allows callers
+// to open a transaction spanning several reads of the graph.
+func (c *ChannelGraph) Database() *DB {
+	return c.db
+}
+
 func putLightningNode(nodeBucket *bolt.Bucket, aliasBucket *bolt.Bucket, node *LightningNode) error {
 	var (
 		scratch [16]byte
@@ -1435,6 +1800,29 @@ func putLightningNode(nodeBucket *bolt.Bucket, aliasBucket *bolt.Bucket, node *L
 		return err
 	}
 
+	// Before writing the node, we'll replace its entry within the update
+	// index, removing the entry for its prior update if it was already
+	// known.
+	updateIndex, err := nodeBucket.CreateBucketIfNotExists(
+		nodeUpdateIndexBucket,
+	)
+	if err != nil {
+		return err
+	}
+
+	var indexKey [8 + 33]byte
+	copy(indexKey[8:], nodePub)
+	if oldNode := nodeBucket.Get(nodePub); len(oldNode) >= 8 {
+		copy(indexKey[:8], oldNode[:8])
+		if err := updateIndex.Delete(indexKey[:]); err != nil {
+			return err
+		}
+	}
+	copy(indexKey[:8], scratch[:8])
+	if err := updateIndex.Put(indexKey[:], nil); err != nil {
+		return err
+	}
+
 	// If we got a node announcement for this node, we will have the rest of
 	// the data available. If not we don't have more data to write.
 	if !node.HaveNodeAnnouncement {
@@ -1478,13 +1866,14 @@ func putLightningNode(nodeBucket *bolt.Bucket, aliasBucket *bolt.Bucket, node *L
 	}
 
 	for _, address := range node.Addresses {
-		if address.Network() == "tcp" {
-			if address.(*net.TCPAddr).IP.To4() != nil {
+		switch addr := address.(type) {
+		case *net.TCPAddr:
+			if addr.IP.To4() != nil {
 				scratch[0] = uint8(tcp4Addr)
 				if _, err := b.Write(scratch[:1]); err != nil {
 					return err
 				}
-				copy(scratch[:4], address.(*net.TCPAddr).IP.To4())
+				copy(scratch[:4], addr.IP.To4())
 				if _, err := b.Write(scratch[:4]); err != nil {
 					return err
 				}
@@ -1493,20 +1882,49 @@ func putLightningNode(nodeBucket *bolt.Bucket, aliasBucket *bolt.Bucket, node *L
 				if _, err := b.Write(scratch[:1]); err != nil {
 					return err
 				}
-				copy(scratch[:], address.(*net.TCPAddr).IP.To16())
+				copy(scratch[:], addr.IP.To16())
 				if _, err := b.Write(scratch[:]); err != nil {
 					return err
 				}
 			}
-			byteOrder.PutUint16(scratch[:2],
-				uint16(address.(*net.TCPAddr).Port))
+			byteOrder.PutUint16(scratch[:2], uint16(addr.Port))
+			if _, err := b.Write(scratch[:2]); err != nil {
+				return err
+			}
+
+		case *lnwire.DNSHostnameAddress:
+			scratch[0] = uint8(dnsAddr)
+			if _, err := b.Write(scratch[:1]); err != nil {
+				return err
+			}
+			err := wire.WriteVarString(&b, 0, addr.Hostname)
+			if err != nil {
+				return err
+			}
+			byteOrder.PutUint16(scratch[:2], addr.Port)
 			if _, err := b.Write(scratch[:2]); err != nil {
 				return err
 			}
+
+		case *lnwire.OpaqueAddrs:
+			scratch[0] = uint8(opaqueAddrs)
+			if _, err := b.Write(scratch[:1]); err != nil {
+				return err
+			}
+			byteOrder.PutUint16(scratch[:2], addr.NumAddrs)
+			if _, err := b.Write(scratch[:2]); err != nil {
+				return err
+			}
+			if err := wire.WriteVarBytes(&b, 0, addr.Payload); err != nil {
+				return err
+			}
+
+		default:
+			return ErrUnknownAddressType
 		}
 	}
 
-	err := wire.WriteVarBytes(&b, 0, node.AuthSig.Serialize())
+	err = wire.WriteVarBytes(&b, 0, node.AuthSig.Serialize())
 	if err != nil {
 		return err
 	}
@@ -1629,6 +2047,33 @@ func deserializeLightningNode(r io.Reader) (*LightningNode, error) {
 			}
 			addr.Port = int(byteOrder.Uint16(scratch[:2]))
 			address = addr
+		case dnsAddr:
+			hostname, err := wire.ReadVarString(r, 0)
+			if err != nil {
+				return nil, err
+			}
+			if _, err := r.Read(scratch[:2]); err != nil {
+				return nil, err
+			}
+			address = &lnwire.DNSHostnameAddress{
+				Hostname: hostname,
+				Port:     byteOrder.Uint16(scratch[:2]),
+			}
+		case opaqueAddrs:
+			if _, err := r.Read(scratch[:2]); err != nil {
+				return nil, err
+			}
+			numAddrs := byteOrder.Uint16(scratch[:2])
+			payload, err := wire.ReadVarBytes(
+				r, 0, lnwire.MaxMessagePayload, "opaque addrs",
+			)
+			if err != nil {
+				return nil, err
+			}
+			address = &lnwire.OpaqueAddrs{
+				NumAddrs: numAddrs,
+				Payload:  payload,
+			}
 		default:
 			return nil, ErrUnknownAddressType
 		}
@@ -1834,7 +2279,13 @@ func putChanEdgePolicy(edges *bolt.Bucket, edge *ChannelEdgePolicy, from, to []b
 		return err
 	}
 
-	if err := binary.Write(&b, byteOrder, edge.Flags); err != nil {
+	// The message and channel flags are stored as a pair of bytes, which
+	// matches the layout of the single flags field policies were
+	// previously stored with.
+	if err := binary.Write(&b, byteOrder, edge.MessageFlags); err != nil {
+		return err
+	}
+	if err := binary.Write(&b, byteOrder, edge.ChannelFlags); err != nil {
 		return err
 	}
 	if err := binary.Write(&b, byteOrder, edge.TimeLockDelta); err != nil {
@@ -1854,9 +2305,91 @@ func putChanEdgePolicy(edges *bolt.Bucket, edge *ChannelEdgePolicy, from, to []b
 		return err
 	}
 
+	// The max HTLC is appended to the end of the policy only if signalled
+	// within the message flags, so policies stored before it was known
+	// can still be read.
+	if edge.MessageFlags.HasMaxHtlc() {
+		err := binary.Write(&b, byteOrder, uint64(edge.MaxHTLC))
+		if err != nil {
+			return err
+		}
+	}
+
+	// Before writing the policy, we'll replace the channel's entry within
+	// the update index, removing the entry for the prior update of this
+	// direction if one was already known.
+	updateIndex, err := edges.CreateBucketIfNotExists(edgeUpdateIndexBucket)
+	if err != nil {
+		return err
+	}
+	if oldEdge := edges.Get(edgeKey[:]); oldEdge != nil {
+		oldUnix, err := edgePolicyUpdateTime(oldEdge)
+		if err != nil {
+			return err
+		}
+
+		err = delEdgeUpdateIndex(
+			edges, updateIndex, edgeKey[33:], to, oldUnix,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	var indexKey [8 + 8]byte
+	byteOrder.PutUint64(indexKey[:8], updateUnix)
+	copy(indexKey[8:], edgeKey[33:])
+	if err := updateIndex.Put(indexKey[:], nil); err != nil {
+		return err
+	}
+
 	return edges.Put(edgeKey[:], b.Bytes()[:])
 }
 
+// delEdgeUpdateIndex removes the update index entry for a directed edge of the
+// passed channel updated at the passed time. As the entry is shared by both
+// directions of the channel if they were updated at the same time, the entry
+// is retained if the opposite direction, advertised by otherNode, still
+// references it.
+func delEdgeUpdateIndex(edges, updateIndex *bolt.Bucket, chanID,
+	otherNode []byte, updateUnix uint64) error {
+
+	var otherKey [33 + 8]byte
+	copy(otherKey[:33], otherNode)
+	copy(otherKey[33:], chanID)
+	if otherEdge := edges.Get(otherKey[:]); otherEdge != nil {
+		otherUnix, err := edgePolicyUpdateTime(otherEdge)
+		if err != nil {
+			return err
+		}
+		if otherUnix == updateUnix {
+			return nil
+		}
+	}
+
+	var indexKey [8 + 8]byte
+	byteOrder.PutUint64(indexKey[:8], updateUnix)
+	copy(indexKey[8:], chanID)
+	return updateIndex.Delete(indexKey[:])
+}
+
+// edgePolicyUpdateTime extracts the update time from a serialized edge policy
+// without deserializing the remainder of the policy.
+func edgePolicyUpdateTime(edgeBytes []byte) (uint64, error) {
+	r := bytes.NewReader(edgeBytes)
+	if _, err := wire.ReadVarBytes(r, 0, 80, "sig"); err != nil {
+		return 0, err
+	}
+
+	// Skip over the channel ID which precedes the update time.
+	var scratch [8 + 8]byte
+	if _, err := io.ReadFull(r, scratch[:]); err != nil {
+		return 0, err
+	}
+
+	return byteOrder.Uint64(scratch[8:]), nil
+}
+
 func fetchChanEdgePolicy(edges *bolt.Bucket, chanID []byte,
 	nodePub []byte, nodes *bolt.Bucket) (*ChannelEdgePolicy, error) {
 
@@ -1941,7 +2474,10 @@ func deserializeChanEdgePolicy(r io.Reader,
 	unix := int64(byteOrder.Uint64(scratch[:]))
 	edge.LastUpdate = time.Unix(unix, 0)
 
-	if err := binary.Read(r, byteOrder, &edge.Flags); err != nil {
+	if err := binary.Read(r, byteOrder, &edge.MessageFlags); err != nil {
+		return nil, err
+	}
+	if err := binary.Read(r, byteOrder, &edge.ChannelFlags); err != nil {
 		return nil, err
 	}
 	if err := binary.Read(r, byteOrder, &edge.TimeLockDelta); err != nil {
@@ -1952,23 +2488,30 @@ func deserializeChanEdgePolicy(r io.Reader,
 	if err := binary.Read(r, byteOrder, &n); err != nil {
 		return nil, err
 	}
-	edge.MinHTLC = lnwire.MilliSatoshi(n)
+	edge.MinHTLC = lnwire.MilliAtom(n)
 
 	if err := binary.Read(r, byteOrder, &n); err != nil {
 		return nil, err
 	}
-	edge.FeeBaseMSat = lnwire.MilliSatoshi(n)
+	edge.FeeBaseMSat = lnwire.MilliAtom(n)
 
 	if err := binary.Read(r, byteOrder, &n); err != nil {
 		return nil, err
 	}
-	edge.FeeProportionalMillionths = lnwire.MilliSatoshi(n)
+	edge.FeeProportionalMillionths = lnwire.MilliAtom(n)
 
 	var pub [33]byte
 	if _, err := r.Read(pub[:]); err != nil {
 		return nil, err
 	}
 
+	if edge.MessageFlags.HasMaxHtlc() {
+		if err := binary.Read(r, byteOrder, &n); err != nil {
+			return nil, err
+		}
+		edge.MaxHTLC = lnwire.MilliAtom(n)
+	}
+
 	node, err := fetchLightningNode(nodes, pub[:])
 	if err != nil {
 		return nil, err
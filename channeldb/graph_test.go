@@ -26,7 +26,17 @@ var (
 		Port: 9000}
 	anotherAddr, _ = net.ResolveTCPAddr("tcp",
 		"[2001:db8:85a3:0:0:8a2e:370:7334]:80")
-	testAddrs = []net.Addr{testAddr, anotherAddr}
+	testDNSAddr = &lnwire.DNSHostnameAddress{
+		Hostname: "ln.example.com",
+		Port:     9735,
+	}
+	testOpaqueAddrs = &lnwire.OpaqueAddrs{
+		NumAddrs: 1,
+		Payload:  []byte{0x0b, 0x01, 0x02, 0x03},
+	}
+	testAddrs = []net.Addr{
+		testAddr, anotherAddr, testDNSAddr, testOpaqueAddrs,
+	}
 
 	randSource = prand.NewSource(time.Now().Unix())
 	randInts   = prand.New(randSource)
@@ -489,7 +499,7 @@ func TestEdgeInfoUpdates(t *testing.T) {
 		Signature:                 testSig,
 		ChannelID:                 chanID,
 		LastUpdate:                time.Unix(433453, 0),
-		Flags:                     0,
+		ChannelFlags:              0,
 		TimeLockDelta:             99,
 		MinHTLC:                   2342135,
 		FeeBaseMSat:               4352345,
@@ -501,9 +511,11 @@ func TestEdgeInfoUpdates(t *testing.T) {
 		Signature:                 testSig,
 		ChannelID:                 chanID,
 		LastUpdate:                time.Unix(124234, 0),
-		Flags:                     1,
+		MessageFlags:              lnwire.ChanUpdateOptionMaxHtlc,
+		ChannelFlags:              lnwire.ChanUpdateDirection,
 		TimeLockDelta:             99,
 		MinHTLC:                   2342135,
+		MaxHTLC:                   13928598,
 		FeeBaseMSat:               4352345,
 		FeeProportionalMillionths: 90392423,
 		Node: firstNode,
@@ -575,9 +587,9 @@ func randEdgePolicy(chanID uint64, op wire.OutPoint, db *DB) *ChannelEdgePolicy
 		ChannelID:                 chanID,
 		LastUpdate:                time.Unix(update, 0),
 		TimeLockDelta:             uint16(prand.Int63()),
-		MinHTLC:                   lnwire.MilliSatoshi(prand.Int63()),
-		FeeBaseMSat:               lnwire.MilliSatoshi(prand.Int63()),
-		FeeProportionalMillionths: lnwire.MilliSatoshi(prand.Int63()),
+		MinHTLC:                   lnwire.MilliAtom(prand.Int63()),
+		FeeBaseMSat:               lnwire.MilliAtom(prand.Int63()),
+		FeeProportionalMillionths: lnwire.MilliAtom(prand.Int63()),
 		db: db,
 	}
 }
@@ -678,7 +690,7 @@ func TestGraphTraversal(t *testing.T) {
 		// Create and add an edge with random data that points from
 		// node1 -> node2.
 		edge := randEdgePolicy(chanID, op, db)
-		edge.Flags = 0
+		edge.ChannelFlags = 0
 		edge.Node = secondNode
 		edge.Signature = testSig
 		if err := graph.UpdateEdgePolicy(edge); err != nil {
@@ -688,7 +700,7 @@ func TestGraphTraversal(t *testing.T) {
 		// Create another random edge that points from node2 -> node1
 		// this time.
 		edge = randEdgePolicy(chanID, op, db)
-		edge.Flags = 1
+		edge.ChannelFlags = 1
 		edge.Node = firstNode
 		edge.Signature = testSig
 		if err := graph.UpdateEdgePolicy(edge); err != nil {
@@ -868,7 +880,7 @@ func TestGraphPruning(t *testing.T) {
 		// Create and add an edge with random data that points from
 		// node_i -> node_i+1
 		edge := randEdgePolicy(chanID, op, db)
-		edge.Flags = 0
+		edge.ChannelFlags = 0
 		edge.Node = graphNodes[i]
 		edge.Signature = testSig
 		if err := graph.UpdateEdgePolicy(edge); err != nil {
@@ -878,7 +890,7 @@ func TestGraphPruning(t *testing.T) {
 		// Create another random edge that points from node_i+1 ->
 		// node_i this time.
 		edge = randEdgePolicy(chanID, op, db)
-		edge.Flags = 1
+		edge.ChannelFlags = 1
 		edge.Node = graphNodes[i]
 		edge.Signature = testSig
 		if err := graph.UpdateEdgePolicy(edge); err != nil {
@@ -1031,9 +1043,13 @@ func compareEdgePolicies(a, b *ChannelEdgePolicy) error {
 		return fmt.Errorf("LastUpdate doesn't match: expected %#v, \n "+
 			"got %#v", a.LastUpdate, b.LastUpdate)
 	}
-	if a.Flags != b.Flags {
-		return fmt.Errorf("Flags doesn't match: expected %v, "+
-			"got %v", a.Flags, b.Flags)
+	if a.MessageFlags != b.MessageFlags {
+		return fmt.Errorf("MessageFlags doesn't match: expected %v, "+
+			"got %v", a.MessageFlags, b.MessageFlags)
+	}
+	if a.ChannelFlags != b.ChannelFlags {
+		return fmt.Errorf("ChannelFlags doesn't match: expected %v, "+
+			"got %v", a.ChannelFlags, b.ChannelFlags)
 	}
 	if a.TimeLockDelta != b.TimeLockDelta {
 		return fmt.Errorf("TimeLockDelta doesn't match: expected %v, "+
@@ -1043,6 +1059,10 @@ func compareEdgePolicies(a, b *ChannelEdgePolicy) error {
 		return fmt.Errorf("MinHTLC doesn't match: expected %v, "+
 			"got %v", a.MinHTLC, b.MinHTLC)
 	}
+	if a.MaxHTLC != b.MaxHTLC {
+		return fmt.Errorf("MaxHTLC doesn't match: expected %v, "+
+			"got %v", a.MaxHTLC, b.MaxHTLC)
+	}
 	if a.FeeBaseMSat != b.FeeBaseMSat {
 		return fmt.Errorf("FeeBaseMSat doesn't match: expected %v, "+
 			"got %v", a.FeeBaseMSat, b.FeeBaseMSat)
@@ -1061,3 +1081,311 @@ func compareEdgePolicies(a, b *ChannelEdgePolicy) error {
 	}
 	return nil
 }
+
+// assertHorizonNodes asserts that the nodes returned by a horizon query match
+// the expected nodes, in order.
+func assertHorizonNodes(t *testing.T, graph *ChannelGraph, start, end int64,
+	expected ...*LightningNode) {
+
+	nodes, err := graph.NodeUpdatesInHorizon(
+		time.Unix(start, 0), time.Unix(end, 0),
+	)
+	if err != nil {
+		t.Fatalf("unable to query nodes in horizon: %v", err)
+	}
+	if len(nodes) != len(expected) {
+		t.Fatalf("expected %v nodes in horizon [%v, %v], got %v",
+			len(expected), start, end, len(nodes))
+	}
+	for i, node := range nodes {
+		if !node.PubKey.IsEqual(expected[i].PubKey) {
+			t.Fatalf("node %v in horizon [%v, %v] mismatch", i,
+				start, end)
+		}
+	}
+}
+
+// TestNodeUpdatesInHorizon tests that nodes are returned by horizon queries
+// according to the time of their most recent update.
+func TestNodeUpdatesInHorizon(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+
+	graph := db.ChannelGraph()
+
+	// An empty graph has no updates within any horizon.
+	assertHorizonNodes(t, graph, 0, 10000)
+
+	var nodes []*LightningNode
+	for i := int64(1); i <= 5; i++ {
+		node, err := createTestVertex(db)
+		if err != nil {
+			t.Fatalf("unable to create test node: %v", err)
+		}
+		node.LastUpdate = time.Unix(i*1000, 0)
+		if err := graph.AddLightningNode(node); err != nil {
+			t.Fatalf("unable to add node: %v", err)
+		}
+		nodes = append(nodes, node)
+	}
+
+	// Both ends of the horizon are inclusive.
+	assertHorizonNodes(t, graph, 2000, 4000, nodes[1], nodes[2], nodes[3])
+	assertHorizonNodes(t, graph, 6000, 10000)
+
+	// Once the first node is updated, it should only be found within the
+	// horizon of its new update.
+	nodes[0].LastUpdate = time.Unix(3500, 0)
+	if err := graph.AddLightningNode(nodes[0]); err != nil {
+		t.Fatalf("unable to update node: %v", err)
+	}
+	assertHorizonNodes(t, graph, 0, 1500)
+	assertHorizonNodes(t, graph, 3000, 4000, nodes[2], nodes[0], nodes[3])
+
+	// Deleted nodes should no longer be returned.
+	if err := graph.DeleteLightningNode(nodes[2].PubKey); err != nil {
+		t.Fatalf("unable to delete node: %v", err)
+	}
+	assertHorizonNodes(t, graph, 3000, 4000, nodes[0], nodes[3])
+}
+
+// assertHorizonChans asserts that the channels returned by a horizon query
+// match the expected channel IDs, in order.
+func assertHorizonChans(t *testing.T, graph *ChannelGraph, start, end int64,
+	expected ...uint64) {
+
+	chans, err := graph.ChanUpdatesInHorizon(
+		time.Unix(start, 0), time.Unix(end, 0),
+	)
+	if err != nil {
+		t.Fatalf("unable to query channels in horizon: %v", err)
+	}
+
+	var chanIDs []uint64
+	for _, channel := range chans {
+		chanIDs = append(chanIDs, channel.Info.ChannelID)
+	}
+	if !reflect.DeepEqual(chanIDs, expected) {
+		t.Fatalf("expected channels %v in horizon [%v, %v], got %v",
+			expected, start, end, chanIDs)
+	}
+}
+
+// TestChanUpdatesInHorizon tests that channels are returned by horizon queries
+// according to the times of the most recent updates of their directed edges,
+// and that each channel is returned at most once.
+func TestChanUpdatesInHorizon(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+
+	graph := db.ChannelGraph()
+
+	// An empty graph has no updates within any horizon.
+	assertHorizonChans(t, graph, 0, 10000)
+
+	node1, err := createTestVertex(db)
+	if err != nil {
+		t.Fatalf("unable to create test node: %v", err)
+	}
+	if err := graph.AddLightningNode(node1); err != nil {
+		t.Fatalf("unable to add node: %v", err)
+	}
+	node2, err := createTestVertex(db)
+	if err != nil {
+		t.Fatalf("unable to create test node: %v", err)
+	}
+	if err := graph.AddLightningNode(node2); err != nil {
+		t.Fatalf("unable to add node: %v", err)
+	}
+	firstNode, secondNode := node1, node2
+	if bytes.Compare(node2.PubKey.SerializeCompressed(),
+		node1.PubKey.SerializeCompressed()) == -1 {
+
+		firstNode, secondNode = node2, node1
+	}
+
+	var chanPoints []wire.OutPoint
+	for i := uint32(0); i < 3; i++ {
+		chanPoint := wire.OutPoint{Hash: rev, Index: i}
+		edgeInfo := &ChannelEdgeInfo{
+			ChannelID:    uint64(i + 1),
+			ChainHash:    key,
+			NodeKey1:     firstNode.PubKey,
+			NodeKey2:     secondNode.PubKey,
+			BitcoinKey1:  firstNode.PubKey,
+			BitcoinKey2:  secondNode.PubKey,
+			ChannelPoint: chanPoint,
+			Capacity:     1000,
+		}
+		if err := graph.AddChannelEdge(edgeInfo); err != nil {
+			t.Fatalf("unable to create channel edge: %v", err)
+		}
+		chanPoints = append(chanPoints, chanPoint)
+	}
+
+	updatePolicy := func(chanID uint64, flags lnwire.ChanUpdateChanFlags,
+		update int64) {
+
+		node := secondNode
+		if flags == 1 {
+			node = firstNode
+		}
+		policy := &ChannelEdgePolicy{
+			Signature:    testSig,
+			ChannelID:    chanID,
+			LastUpdate:   time.Unix(update, 0),
+			ChannelFlags: flags,
+			Node:         node,
+			db:           db,
+		}
+		if err := graph.UpdateEdgePolicy(policy); err != nil {
+			t.Fatalf("unable to update edge: %v", err)
+		}
+	}
+
+	// The directions of the first channel are updated at different times,
+	// while both directions of the second are updated at the same time.
+	// The third channel has no edge policies at all.
+	updatePolicy(1, 0, 1000)
+	updatePolicy(1, 1, 5000)
+	updatePolicy(2, 0, 2000)
+	updatePolicy(2, 1, 2000)
+
+	assertHorizonChans(t, graph, 0, 1500, 1)
+	assertHorizonChans(t, graph, 2000, 2000, 2)
+	assertHorizonChans(t, graph, 4000, 5000, 1)
+	assertHorizonChans(t, graph, 0, 10000, 1, 2)
+
+	// As the second direction of the second channel still shares the
+	// same update time, updating the first direction shouldn't remove
+	// the channel from the earlier horizon.
+	updatePolicy(2, 0, 6000)
+	assertHorizonChans(t, graph, 2000, 2000, 2)
+	assertHorizonChans(t, graph, 6000, 6000, 2)
+
+	// Once both directions have been updated, it should only be found
+	// within the horizons of the new updates.
+	updatePolicy(2, 1, 7000)
+	assertHorizonChans(t, graph, 2000, 2000)
+	assertHorizonChans(t, graph, 5000, 10000, 1, 2)
+
+	// Closed channels should no longer be returned.
+	if err := graph.DeleteChannelEdge(&chanPoints[0]); err != nil {
+		t.Fatalf("unable to delete edge: %v", err)
+	}
+	assertHorizonChans(t, graph, 0, 10000, 2)
+}
+
+// TestPruneGraphNodes tests that nodes without any remaining channels are
+// pruned from the graph, while the source node and connected nodes remain.
+func TestPruneGraphNodes(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+
+	graph := db.ChannelGraph()
+
+	sourceNode, err := createTestVertex(db)
+	if err != nil {
+		t.Fatalf("unable to create source node: %v", err)
+	}
+	if err := graph.SetSourceNode(sourceNode); err != nil {
+		t.Fatalf("unable to set source node: %v", err)
+	}
+
+	nodes := make([]*LightningNode, 3)
+	for i := range nodes {
+		nodes[i], err = createTestVertex(db)
+		if err != nil {
+			t.Fatalf("unable to create test node: %v", err)
+		}
+		if err := graph.AddLightningNode(nodes[i]); err != nil {
+			t.Fatalf("unable to add node: %v", err)
+		}
+	}
+
+	addChannel := func(chanID uint64, node1, node2 *LightningNode) {
+		if bytes.Compare(node2.PubKey.SerializeCompressed(),
+			node1.PubKey.SerializeCompressed()) == -1 {
+
+			node1, node2 = node2, node1
+		}
+		edgeInfo := &ChannelEdgeInfo{
+			ChannelID:   chanID,
+			ChainHash:   key,
+			NodeKey1:    node1.PubKey,
+			NodeKey2:    node2.PubKey,
+			BitcoinKey1: node1.PubKey,
+			BitcoinKey2: node2.PubKey,
+			ChannelPoint: wire.OutPoint{
+				Hash:  rev,
+				Index: uint32(chanID),
+			},
+			Capacity: 1000,
+		}
+		if err := graph.AddChannelEdge(edgeInfo); err != nil {
+			t.Fatalf("unable to create channel edge: %v", err)
+		}
+	}
+
+	// The first two nodes share a channel, while the third has a channel
+	// with the source node.
+	addChannel(1, nodes[0], nodes[1])
+	addChannel(2, sourceNode, nodes[2])
+
+	// As all nodes are connected, none should be pruned.
+	numPruned, err := graph.PruneGraphNodes()
+	if err != nil {
+		t.Fatalf("unable to prune graph nodes: %v", err)
+	}
+	if numPruned != 0 {
+		t.Fatalf("expected no nodes pruned, got %v", numPruned)
+	}
+
+	// Once the channel between the first two nodes is closed, both should
+	// be pruned.
+	spent := []*wire.OutPoint{{Hash: rev, Index: 1}}
+	blockHash := chainhash.Hash(rev)
+	if _, err := graph.PruneGraph(spent, &blockHash, 100); err != nil {
+		t.Fatalf("unable to prune graph: %v", err)
+	}
+	numPruned, err = graph.PruneGraphNodes()
+	if err != nil {
+		t.Fatalf("unable to prune graph nodes: %v", err)
+	}
+	if numPruned != 2 {
+		t.Fatalf("expected 2 nodes pruned, got %v", numPruned)
+	}
+
+	for i, node := range nodes {
+		_, found, err := graph.HasLightningNode(node.PubKey)
+		if err != nil {
+			t.Fatalf("unable to query for node: %v", err)
+		}
+		if found != (i == 2) {
+			t.Fatalf("node %v: expected found=%v, got %v", i,
+				i == 2, found)
+		}
+	}
+	_, err = graph.LookupAlias(nodes[0].PubKey)
+	if err != ErrNodeAliasNotFound {
+		t.Fatalf("expected ErrNodeAliasNotFound, got %v", err)
+	}
+	if _, err := graph.SourceNode(); err != nil {
+		t.Fatalf("unable to fetch source node: %v", err)
+	}
+}
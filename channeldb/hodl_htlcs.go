@@ -0,0 +1,129 @@
+package channeldb
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/boltdb/bolt"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+var (
+	// hodlHtlcBucket is a top-level bucket which houses the HTLCs paying
+	// hold invoices that are held by the link of the channel they were
+	// received over, until their invoice is either settled or canceled.
+	// Retaining them allows the link to resume holding them once it's
+	// restarted. Each HTLC is keyed by the short channel ID of its
+	// channel, followed by its index within the channel.
+	//
+	// maps: chanID || htlcID -> hodlHtlc
+	hodlHtlcBucket = []byte("hodl-htlcs")
+)
+
+// HodlHtlc is an HTLC paying a hold invoice which is held by the link of the
+// channel it was received over.
+type HodlHtlc struct {
+	// ChanID is the short channel ID of the channel the HTLC was received
+	// over.
+	ChanID lnwire.ShortChannelID
+
+	// HtlcID is the index of the HTLC within the channel.
+	HtlcID uint64
+
+	// PaymentHash is the payment hash of the HTLC.
+	PaymentHash [32]byte
+
+	// Expiry is the absolute expiry height of the HTLC.
+	Expiry uint32
+
+	// OnionBlob is the onion packet of the HTLC, from which the
+	// obfuscator used to fail the HTLC back can be re-derived.
+	OnionBlob []byte
+}
+
+// hodlHtlcKey returns the key of the held HTLC with the passed channel and
+// HTLC ID.
+func hodlHtlcKey(chanID lnwire.ShortChannelID, htlcID uint64) []byte {
+	var k [16]byte
+	byteOrder.PutUint64(k[:8], chanID.ToUint64())
+	byteOrder.PutUint64(k[8:], htlcID)
+	return k[:]
+}
+
+// AddHodlHtlc adds the passed HTLC to the set of held HTLCs.
+func (d *DB) AddHodlHtlc(htlc *HodlHtlc) error {
+	var b bytes.Buffer
+	if err := serializeHodlHtlc(&b, htlc); err != nil {
+		return err
+	}
+
+	return d.Update(func(tx *bolt.Tx) error {
+		htlcs, err := tx.CreateBucketIfNotExists(hodlHtlcBucket)
+		if err != nil {
+			return err
+		}
+
+		return htlcs.Put(hodlHtlcKey(htlc.ChanID, htlc.HtlcID), b.Bytes())
+	})
+}
+
+// DeleteHodlHtlc removes the held HTLC with the passed channel and HTLC ID
+// once it has been resolved. Deleting an HTLC which isn't found is a noop.
+func (d *DB) DeleteHodlHtlc(chanID lnwire.ShortChannelID, htlcID uint64) error {
+	return d.Update(func(tx *bolt.Tx) error {
+		htlcs := tx.Bucket(hodlHtlcBucket)
+		if htlcs == nil {
+			return nil
+		}
+
+		return htlcs.Delete(hodlHtlcKey(chanID, htlcID))
+	})
+}
+
+// FetchHodlHtlcs returns the HTLCs received over the passed channel which are
+// still held, in the order they were received.
+func (d *DB) FetchHodlHtlcs(chanID lnwire.ShortChannelID) ([]*HodlHtlc, error) {
+	var held []*HodlHtlc
+	err := d.View(func(tx *bolt.Tx) error {
+		htlcs := tx.Bucket(hodlHtlcBucket)
+		if htlcs == nil {
+			return nil
+		}
+
+		prefix := make([]byte, 8)
+		byteOrder.PutUint64(prefix, chanID.ToUint64())
+
+		c := htlcs.Cursor()
+		for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			htlc, err := deserializeHodlHtlc(bytes.NewReader(v))
+			if err != nil {
+				return err
+			}
+			htlc.ChanID = chanID
+			htlc.HtlcID = byteOrder.Uint64(k[8:])
+
+			held = append(held, htlc)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return held, nil
+}
+
+func serializeHodlHtlc(w io.Writer, htlc *HodlHtlc) error {
+	return writeElements(w, htlc.PaymentHash, htlc.Expiry, htlc.OnionBlob)
+}
+
+func deserializeHodlHtlc(r io.Reader) (*HodlHtlc, error) {
+	htlc := &HodlHtlc{}
+	err := readElements(r, &htlc.PaymentHash, &htlc.Expiry, &htlc.OnionBlob)
+	if err != nil {
+		return nil, err
+	}
+
+	return htlc, nil
+}
@@ -0,0 +1,96 @@
+package channeldb
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// TestHodlHtlcs tests that held HTLCs are retained until they're deleted, and
+// that only the HTLCs held over the requested channel are returned.
+func TestHodlHtlcs(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+
+	chanID1 := lnwire.NewShortChanIDFromInt(1)
+	chanID2 := lnwire.NewShortChanIDFromInt(2)
+
+	// Without any HTLCs added, none should be returned.
+	htlcs, err := db.FetchHodlHtlcs(chanID1)
+	if err != nil {
+		t.Fatalf("unable to fetch held htlcs: %v", err)
+	}
+	if len(htlcs) != 0 {
+		t.Fatalf("expected no held htlcs, got %v", len(htlcs))
+	}
+
+	htlc1 := &HodlHtlc{
+		ChanID:      chanID1,
+		HtlcID:      0,
+		PaymentHash: [32]byte{1},
+		Expiry:      100,
+		OnionBlob:   []byte{1, 2, 3},
+	}
+	htlc2 := &HodlHtlc{
+		ChanID:      chanID1,
+		HtlcID:      1,
+		PaymentHash: [32]byte{2},
+		Expiry:      200,
+		OnionBlob:   []byte{4, 5, 6},
+	}
+	htlc3 := &HodlHtlc{
+		ChanID:      chanID2,
+		HtlcID:      0,
+		PaymentHash: [32]byte{3},
+		Expiry:      300,
+		OnionBlob:   []byte{7, 8, 9},
+	}
+	for _, htlc := range []*HodlHtlc{htlc1, htlc2, htlc3} {
+		if err := db.AddHodlHtlc(htlc); err != nil {
+			t.Fatalf("unable to add held htlc: %v", err)
+		}
+	}
+
+	htlcs, err = db.FetchHodlHtlcs(chanID1)
+	if err != nil {
+		t.Fatalf("unable to fetch held htlcs: %v", err)
+	}
+	expected := []*HodlHtlc{htlc1, htlc2}
+	if !reflect.DeepEqual(htlcs, expected) {
+		t.Fatalf("expected held htlcs %v, got %v", spew.Sdump(expected),
+			spew.Sdump(htlcs))
+	}
+
+	// Once resolved, the first HTLC should no longer be returned, while
+	// the HTLCs of the other channel should be unaffected.
+	if err := db.DeleteHodlHtlc(chanID1, htlc1.HtlcID); err != nil {
+		t.Fatalf("unable to delete held htlc: %v", err)
+	}
+
+	htlcs, err = db.FetchHodlHtlcs(chanID1)
+	if err != nil {
+		t.Fatalf("unable to fetch held htlcs: %v", err)
+	}
+	expected = []*HodlHtlc{htlc2}
+	if !reflect.DeepEqual(htlcs, expected) {
+		t.Fatalf("expected held htlcs %v, got %v", spew.Sdump(expected),
+			spew.Sdump(htlcs))
+	}
+
+	htlcs, err = db.FetchHodlHtlcs(chanID2)
+	if err != nil {
+		t.Fatalf("unable to fetch held htlcs: %v", err)
+	}
+	expected = []*HodlHtlc{htlc3}
+	if !reflect.DeepEqual(htlcs, expected) {
+		t.Fatalf("expected held htlcs %v, got %v", spew.Sdump(expected),
+			spew.Sdump(htlcs))
+	}
+}
@@ -1,6 +1,7 @@
 package channeldb
 
 import (
+	"bytes"
 	"crypto/rand"
 	"crypto/sha256"
 	"reflect"
@@ -11,11 +12,14 @@ import (
 	"github.com/lightningnetwork/lnd/lnwire"
 )
 
-func randInvoice(value lnwire.MilliSatoshi) (*Invoice, error) {
-	var pre [32]byte
+func randInvoice(value lnwire.MilliAtom) (*Invoice, error) {
+	var pre, addr [32]byte
 	if _, err := rand.Read(pre[:]); err != nil {
 		return nil, err
 	}
+	if _, err := rand.Read(addr[:]); err != nil {
+		return nil, err
+	}
 
 	i := &Invoice{
 		// Use single second precision to avoid false positive test
@@ -23,7 +27,9 @@ func randInvoice(value lnwire.MilliSatoshi) (*Invoice, error) {
 		CreationDate: time.Unix(time.Now().Unix(), 0),
 		Terms: ContractTerm{
 			PaymentPreimage: pre,
+			PaymentHash:     sha256.Sum256(pre[:]),
 			Value:           value,
+			PaymentAddr:     addr,
 		},
 	}
 	i.Memo = []byte("memo")
@@ -75,14 +81,14 @@ func TestInvoiceWorkflow(t *testing.T) {
 
 	// Settle the invoice, the versin retreived from the database should
 	// now have the settled bit toggle to true.
-	if err := db.SettleInvoice(paymentHash); err != nil {
+	if err := db.SettleInvoice(paymentHash, nil); err != nil {
 		t.Fatalf("unable to settle invoice: %v", err)
 	}
 	dbInvoice2, err := db.LookupInvoice(paymentHash)
 	if err != nil {
 		t.Fatalf("unable to fetch invoice: %v", err)
 	}
-	if !dbInvoice2.Terms.Settled {
+	if dbInvoice2.Terms.State != ContractSettled {
 		t.Fatalf("invoice should now be settled but isn't")
 	}
 
@@ -136,3 +142,334 @@ func TestInvoiceWorkflow(t *testing.T) {
 		}
 	}
 }
+
+// TestInvoiceAddSettleIndexes tests that invoices are assigned monotonically
+// increasing add and settle indexes, and that callers can query for all
+// invoices added or settled since a particular index.
+func TestInvoiceAddSettleIndexes(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+
+	const numInvoices = 10
+	invoices := make([]*Invoice, numInvoices)
+	for i := 0; i < numInvoices; i++ {
+		invoice, err := randInvoice(lnwire.MilliAtom(i + 1))
+		if err != nil {
+			t.Fatalf("unable to create invoice: %v", err)
+		}
+		if err := db.AddInvoice(invoice); err != nil {
+			t.Fatalf("unable to add invoice: %v", err)
+		}
+
+		// The add indexes should start at one, and increment with
+		// each new invoice.
+		if invoice.AddIndex != uint64(i+1) {
+			t.Fatalf("expected add index %v, got %v", i+1,
+				invoice.AddIndex)
+		}
+
+		invoices[i] = invoice
+	}
+
+	// An index of zero should return no invoices, while a valid index
+	// should return all invoices added after it, in order.
+	added, err := db.InvoicesAddedSince(0)
+	if err != nil {
+		t.Fatalf("unable to query add index: %v", err)
+	}
+	if len(added) != 0 {
+		t.Fatalf("expected no invoices, got %v", len(added))
+	}
+	added, err = db.InvoicesAddedSince(numInvoices / 2)
+	if err != nil {
+		t.Fatalf("unable to query add index: %v", err)
+	}
+	if !reflect.DeepEqual(added, invoices[numInvoices/2:]) {
+		t.Fatalf("wrong invoices returned: expected %v, got %v",
+			spew.Sdump(invoices[numInvoices/2:]), spew.Sdump(added))
+	}
+
+	// Next, we'll settle the invoices in reverse order, ensuring each is
+	// assigned the next settle index.
+	for i := numInvoices - 1; i >= 0; i-- {
+		invoice := invoices[i]
+		payHash := sha256.Sum256(invoice.Terms.PaymentPreimage[:])
+		if err := db.SettleInvoice(payHash, nil); err != nil {
+			t.Fatalf("unable to settle invoice: %v", err)
+		}
+
+		invoice.Terms.State = ContractSettled
+		invoice.SettleIndex = uint64(numInvoices - i)
+	}
+
+	// Settling an invoice a second time should not assign it a new
+	// settle index.
+	payHash := sha256.Sum256(invoices[0].Terms.PaymentPreimage[:])
+	if err := db.SettleInvoice(payHash, nil); err != nil {
+		t.Fatalf("unable to settle invoice: %v", err)
+	}
+	dbInvoice, err := db.LookupInvoice(payHash)
+	if err != nil {
+		t.Fatalf("unable to lookup invoice: %v", err)
+	}
+	if !reflect.DeepEqual(dbInvoice, invoices[0]) {
+		t.Fatalf("wrong invoice: expected %v, got %v",
+			spew.Sdump(invoices[0]), spew.Sdump(dbInvoice))
+	}
+
+	// Querying for invoices settled since the first settle index should
+	// return all but the first settled invoice, in settlement order.
+	settled, err := db.InvoicesSettledSince(1)
+	if err != nil {
+		t.Fatalf("unable to query settle index: %v", err)
+	}
+	if len(settled) != numInvoices-1 {
+		t.Fatalf("expected %v invoices, got %v", numInvoices-1,
+			len(settled))
+	}
+	for i, invoice := range settled {
+		expected := invoices[numInvoices-2-i]
+		if !reflect.DeepEqual(invoice, expected) {
+			t.Fatalf("wrong invoice #%v: expected %v, got %v", i,
+				spew.Sdump(expected), spew.Sdump(invoice))
+		}
+	}
+
+	// Finally, querying past the latest index should return nothing.
+	settled, err = db.InvoicesSettledSince(numInvoices)
+	if err != nil {
+		t.Fatalf("unable to query settle index: %v", err)
+	}
+	if len(settled) != 0 {
+		t.Fatalf("expected no invoices, got %v", len(settled))
+	}
+}
+
+// TestInvoiceHtlcs asserts that every HTLC settling an invoice is recorded
+// exactly once, and that only the first settle assigns a settle index.
+func TestInvoiceHtlcs(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+
+	invoice, err := randInvoice(lnwire.NewMSatFromSatoshis(1000))
+	if err != nil {
+		t.Fatalf("unable to create invoice: %v", err)
+	}
+	if err := db.AddInvoice(invoice); err != nil {
+		t.Fatalf("unable to add invoice: %v", err)
+	}
+	payHash := sha256.Sum256(invoice.Terms.PaymentPreimage[:])
+
+	// The invoice is overpaid by two HTLCs, received over different
+	// channels.
+	htlc1 := InvoiceHTLC{
+		ChanID:       lnwire.NewShortChanIDFromInt(1),
+		HtlcID:       5,
+		Amt:          lnwire.NewMSatFromSatoshis(1000),
+		Expiry:       500,
+		AcceptHeight: 100,
+		SettleTime:   time.Unix(time.Now().Unix(), 0),
+	}
+	htlc2 := htlc1
+	htlc2.ChanID = lnwire.NewShortChanIDFromInt(2)
+	htlc2.Amt = lnwire.NewMSatFromSatoshis(200)
+
+	// Settling with the first HTLC twice, as would happen if the settle
+	// were replayed, should only record it once.
+	for i := 0; i < 2; i++ {
+		if err := db.SettleInvoice(payHash, &htlc1); err != nil {
+			t.Fatalf("unable to settle invoice: %v", err)
+		}
+	}
+	if err := db.SettleInvoice(payHash, &htlc2); err != nil {
+		t.Fatalf("unable to settle invoice: %v", err)
+	}
+
+	dbInvoice, err := db.LookupInvoice(payHash)
+	if err != nil {
+		t.Fatalf("unable to lookup invoice: %v", err)
+	}
+
+	invoice.Terms.State = ContractSettled
+	invoice.SettleIndex = 1
+	invoice.Htlcs = []InvoiceHTLC{htlc1, htlc2}
+	if !reflect.DeepEqual(dbInvoice, invoice) {
+		t.Fatalf("wrong invoice: expected %v, got %v",
+			spew.Sdump(invoice), spew.Sdump(dbInvoice))
+	}
+
+	expectedPaid := lnwire.NewMSatFromSatoshis(1200)
+	if dbInvoice.AmtPaid() != expectedPaid {
+		t.Fatalf("expected amount paid %v, got %v", expectedPaid,
+			dbInvoice.AmtPaid())
+	}
+}
+
+// TestLegacyInvoiceDeserialization asserts that invoices serialized before
+// the settling HTLCs were recorded can still be read.
+func TestLegacyInvoiceDeserialization(t *testing.T) {
+	t.Parallel()
+
+	invoice, err := randInvoice(lnwire.NewMSatFromSatoshis(1000))
+	if err != nil {
+		t.Fatalf("unable to create invoice: %v", err)
+	}
+	invoice.AddIndex = 1
+
+	var b bytes.Buffer
+	if err := serializeInvoice(&b, invoice); err != nil {
+		t.Fatalf("unable to serialize invoice: %v", err)
+	}
+
+	// Invoices written before the payment address was stored end directly
+	// after the payment hash, so we'll strip the trailing address before
+	// reading the invoice back, which should leave its address zero.
+	serialized := b.Bytes()
+	legacyBytes := serialized[:len(serialized)-32]
+	legacyInvoice, err := deserializeInvoice(bytes.NewReader(legacyBytes))
+	if err != nil {
+		t.Fatalf("unable to deserialize legacy invoice: %v", err)
+	}
+	invoice.Terms.PaymentAddr = [32]byte{}
+	if !reflect.DeepEqual(invoice, legacyInvoice) {
+		t.Fatalf("invoices don't match: expected %v got %v",
+			spew.Sdump(invoice), spew.Sdump(legacyInvoice))
+	}
+
+	// Legacy invoices end directly after the settle index, so we'll strip
+	// the trailing HTLC count and payment hash as well.
+	legacyBytes = serialized[:len(serialized)-32-1-32]
+	legacyInvoice, err = deserializeInvoice(bytes.NewReader(legacyBytes))
+	if err != nil {
+		t.Fatalf("unable to deserialize legacy invoice: %v", err)
+	}
+	if !reflect.DeepEqual(invoice, legacyInvoice) {
+		t.Fatalf("invoices don't match: expected %v got %v",
+			spew.Sdump(invoice), spew.Sdump(legacyInvoice))
+	}
+}
+
+// TestHoldInvoice asserts that a hold invoice is indexed by its payment hash,
+// and transitions through the accepted state before being either settled
+// once its preimage is known, or canceled.
+func TestHoldInvoice(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+
+	// Create two hold invoices, only knowing their payment hashes.
+	var (
+		preimages [2][32]byte
+		hashes    [2][32]byte
+	)
+	for i := range preimages {
+		invoice, err := randInvoice(lnwire.NewMSatFromSatoshis(1000))
+		if err != nil {
+			t.Fatalf("unable to create invoice: %v", err)
+		}
+		preimages[i] = invoice.Terms.PaymentPreimage
+		hashes[i] = invoice.Terms.PaymentHash
+		invoice.Terms.PaymentPreimage = UnknownPreimage
+
+		if err := db.AddInvoice(invoice); err != nil {
+			t.Fatalf("unable to add invoice: %v", err)
+		}
+	}
+
+	// A hold invoice can't be settled directly, nor before an HTLC paying
+	// it has been accepted.
+	err = db.SettleInvoice(hashes[0], nil)
+	if err != ErrHoldInvoicePreimageUnknown {
+		t.Fatalf("expected ErrHoldInvoicePreimageUnknown, got %v", err)
+	}
+	if err := db.SettleHoldInvoice(preimages[0]); err != ErrInvoiceNotAccepted {
+		t.Fatalf("expected ErrInvoiceNotAccepted, got %v", err)
+	}
+
+	// Accept an HTLC paying both invoices. Only a single HTLC may be
+	// accepted.
+	htlc := InvoiceHTLC{
+		ChanID:       lnwire.NewShortChanIDFromInt(1),
+		HtlcID:       5,
+		Amt:          lnwire.NewMSatFromSatoshis(1000),
+		Expiry:       500,
+		AcceptHeight: 100,
+	}
+	for _, hash := range hashes {
+		if err := db.AcceptInvoice(hash, &htlc); err != nil {
+			t.Fatalf("unable to accept invoice: %v", err)
+		}
+	}
+	if err := db.AcceptInvoice(hashes[0], &htlc); err != ErrInvoiceAlreadyAccepted {
+		t.Fatalf("expected ErrInvoiceAlreadyAccepted, got %v", err)
+	}
+
+	// Settling the first invoice should reveal its preimage, and settle
+	// the accepted HTLC.
+	if err := db.SettleHoldInvoice(preimages[0]); err != nil {
+		t.Fatalf("unable to settle hold invoice: %v", err)
+	}
+	invoice, err := db.LookupInvoice(hashes[0])
+	if err != nil {
+		t.Fatalf("unable to lookup invoice: %v", err)
+	}
+	if invoice.Terms.State != ContractSettled {
+		t.Fatalf("expected settled invoice, got %v", invoice.Terms.State)
+	}
+	if invoice.Terms.PaymentPreimage != preimages[0] {
+		t.Fatalf("expected preimage to be revealed")
+	}
+	if invoice.SettleIndex != 1 {
+		t.Fatalf("expected settle index 1, got %v", invoice.SettleIndex)
+	}
+	if len(invoice.Htlcs) != 1 || invoice.Htlcs[0].SettleTime.IsZero() {
+		t.Fatalf("expected settled htlc, got %v",
+			spew.Sdump(invoice.Htlcs))
+	}
+	if err := db.CancelInvoice(hashes[0]); err != ErrInvoiceAlreadySettled {
+		t.Fatalf("expected ErrInvoiceAlreadySettled, got %v", err)
+	}
+
+	// Canceling the second invoice should discard its accepted HTLC, after
+	// which it can no longer be settled.
+	if err := db.CancelInvoice(hashes[1]); err != nil {
+		t.Fatalf("unable to cancel invoice: %v", err)
+	}
+	invoice, err = db.LookupInvoice(hashes[1])
+	if err != nil {
+		t.Fatalf("unable to lookup invoice: %v", err)
+	}
+	if invoice.Terms.State != ContractCanceled {
+		t.Fatalf("expected canceled invoice, got %v", invoice.Terms.State)
+	}
+	if len(invoice.Htlcs) != 0 {
+		t.Fatalf("expected no htlcs, got %v", len(invoice.Htlcs))
+	}
+	err = db.SettleHoldInvoice(preimages[1])
+	if err != ErrInvoiceAlreadyCanceled {
+		t.Fatalf("expected ErrInvoiceAlreadyCanceled, got %v", err)
+	}
+
+	// Neither invoice should be reported as pending any longer.
+	pending, err := db.FetchAllInvoices(true)
+	if err != nil {
+		t.Fatalf("unable to fetch invoices: %v", err)
+	}
+	if len(pending) != 0 {
+		t.Fatalf("expected no pending invoices, got %v", len(pending))
+	}
+}
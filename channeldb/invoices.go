@@ -33,6 +33,22 @@ var (
 	// stored within the invoiceIndexBucket. Within the invoiceBucket
 	// invoices are uniquely identified by the invoice ID.
 	numInvoicesKey = []byte("nik")
+
+	// addIndexBucket is the name of the sub-bucket within the
+	// invoiceBucket which indexes all invoices by their add index. The
+	// add index is a monotonically increasing uint64 assigned to each
+	// invoice as it's added, allowing callers to efficiently query for
+	// all invoices added since a particular point. The bucket's sequence
+	// tracks the latest add index, and each entry maps an add index to
+	// the invoice's key within the invoiceBucket.
+	addIndexBucket = []byte("invoice-add-index")
+
+	// settleIndexBucket is the name of the sub-bucket within the
+	// invoiceBucket which indexes all settled invoices by their settle
+	// index. Similar to the add index, the settle index is a
+	// monotonically increasing uint64 assigned to each invoice as it's
+	// settled.
+	settleIndexBucket = []byte("invoice-settle-index")
 )
 
 const (
@@ -43,24 +59,104 @@ const (
 	// MaxReceiptSize is the maximum size of the payment receipt stored
 	// within the database along side incoming/outgoing invoices.
 	MaxReceiptSize = 1024
+
+	// MaxInvoiceHTLCs is the maximum number of HTLCs recorded as having
+	// settled a single invoice.
+	MaxInvoiceHTLCs = 1024
+)
+
+// UnknownPreimage is the preimage of a hold invoice, whose actual preimage
+// isn't known until the invoice is settled.
+var UnknownPreimage [32]byte
+
+// ContractState describes the state the invoice is in.
+type ContractState uint8
+
+const (
+	// ContractOpen means the invoice has only been created.
+	ContractOpen ContractState = 0
+
+	// ContractSettled means the htlc is settled and the invoice has been
+	// paid.
+	ContractSettled ContractState = 1
+
+	// ContractCanceled means the invoice has been canceled.
+	ContractCanceled ContractState = 2
+
+	// ContractAccepted means the HTLC paying a hold invoice has been
+	// accepted, and is held until the invoice is either settled or
+	// canceled.
+	ContractAccepted ContractState = 3
 )
 
+// String returns a human readable identifier for the ContractState type.
+func (c ContractState) String() string {
+	switch c {
+	case ContractOpen:
+		return "Open"
+	case ContractSettled:
+		return "Settled"
+	case ContractCanceled:
+		return "Canceled"
+	case ContractAccepted:
+		return "Accepted"
+	}
+
+	return "Unknown"
+}
+
+// InvoiceHTLC records an HTLC which was settled to pay an invoice. The HTLCs
+// settling an invoice are retained so that overpayments, and invoices paid
+// by several HTLCs, can be observed after the fact.
+type InvoiceHTLC struct {
+	// ChanID is the short channel ID of the channel the HTLC was received
+	// over.
+	ChanID lnwire.ShortChannelID
+
+	// HtlcID is the index of the HTLC within the channel.
+	HtlcID uint64
+
+	// Amt is the amount of the HTLC.
+	Amt lnwire.MilliAtom
+
+	// Expiry is the absolute expiry height of the HTLC.
+	Expiry uint32
+
+	// AcceptHeight is the block height at which the HTLC was accepted.
+	AcceptHeight uint32
+
+	// SettleTime is the time at which the HTLC was settled.
+	SettleTime time.Time
+}
+
 // ContractTerm is a companion struct to the Invoice struct. This struct houses
 // the necessary conditions required before the invoice can be considered fully
 // settled by the payee.
 type ContractTerm struct {
 	// PaymentPreimage is the preimage which is to be revealed in the
 	// occasion that an HTLC paying to the hash of this preimage is
-	// extended.
+	// extended. For hold invoices, this is UnknownPreimage until the
+	// invoice is settled.
 	PaymentPreimage [32]byte
 
+	// PaymentHash is the hash HTLCs paying the invoice are locked to. It's
+	// derived from the preimage when the invoice is added, unless the
+	// preimage is unknown, in which case it must be provided.
+	PaymentHash [32]byte
+
 	// Value is the expected amount of milli-satoshis to be payed to an
 	// HTLC which can be satisfied by the above preimage.
-	Value lnwire.MilliSatoshi
+	Value lnwire.MilliAtom
 
-	// Settled indicates if this particular contract term has been fully
-	// settled by the payer.
-	Settled bool
+	// State describes the state the invoice is in.
+	State ContractState
+
+	// PaymentAddr is the payment address, or payment secret, of the
+	// invoice. Multi-path payments to the invoice must carry it within
+	// the payload of their final hop, which prevents intermediate nodes
+	// from probing the receiver with shards of their own. It's zero for
+	// invoices added before the address was stored.
+	PaymentAddr [32]byte
 }
 
 // Invoice is a payment invoice generated by a payee in order to request
@@ -96,6 +192,35 @@ type Invoice struct {
 	// TODO(roasbeef): later allow for multiple terms to fulfill the final
 	// invoice: payment fragmentation, etc.
 	Terms ContractTerm
+
+	// AddIndex is an auto-incrementing integer that acts as a monotonically
+	// increasing sequence number for all invoices created. Clients can use
+	// this to resume a stream of invoice additions without missing any
+	// events. This field is populated by the database when the invoice is
+	// added.
+	AddIndex uint64
+
+	// SettleIndex is an auto-incrementing integer that acts as a
+	// monotonically increasing sequence number for all settled invoices.
+	// Clients can use this to resume a stream of invoice settlements
+	// without missing any events. This field is zero until the invoice
+	// has been settled.
+	SettleIndex uint64
+
+	// Htlcs is the set of HTLCs which settled the invoice, in the order
+	// they were settled. Invoices settled before HTLCs were recorded
+	// have no HTLCs, even if settled.
+	Htlcs []InvoiceHTLC
+}
+
+// AmtPaid returns the total amount of the HTLCs which settled the invoice.
+func (i *Invoice) AmtPaid() lnwire.MilliAtom {
+	var amtPaid lnwire.MilliAtom
+	for _, htlc := range i.Htlcs {
+		amtPaid += htlc.Amt
+	}
+
+	return amtPaid
 }
 
 func validateInvoice(i *Invoice) error {
@@ -114,45 +239,67 @@ func validateInvoice(i *Invoice) error {
 // AddInvoice inserts the targeted invoice into the database. If the invoice
 // has *any* payment hashes which already exists within the database, then the
 // insertion will be aborted and rejected due to the strict policy banning any
-// duplicate payment hashes.
+// duplicate payment hashes. On success, the AddIndex of the passed invoice is
+// populated with the add index assigned to it.
 func (d *DB) AddInvoice(i *Invoice) error {
 	if err := validateInvoice(i); err != nil {
 		return err
 	}
+
+	// The payment hash of an invoice whose preimage is known is always
+	// derived from it, while that of a hold invoice must be provided.
+	switch {
+	case i.Terms.PaymentPreimage != UnknownPreimage:
+		i.Terms.PaymentHash = sha256.Sum256(i.Terms.PaymentPreimage[:])
+	case i.Terms.PaymentHash == [32]byte{}:
+		return fmt.Errorf("hold invoice must have a payment hash")
+	}
+
 	return d.Update(func(tx *bolt.Tx) error {
-		invoices, err := tx.CreateBucketIfNotExists(invoiceBucket)
-		if err != nil {
-			return err
-		}
+		return addInvoice(tx, i)
+	})
+}
 
-		invoiceIndex, err := invoices.CreateBucketIfNotExists(invoiceIndexBucket)
-		if err != nil {
-			return err
-		}
+// addInvoice inserts the passed invoice, whose payment hash has already been
+// set, within the passed transaction.
+func addInvoice(tx *bolt.Tx, i *Invoice) error {
+	invoices, err := tx.CreateBucketIfNotExists(invoiceBucket)
+	if err != nil {
+		return err
+	}
 
-		// Ensure that an invoice an identical payment hash doesn't
-		// already exist within the index.
-		paymentHash := sha256.Sum256(i.Terms.PaymentPreimage[:])
-		if invoiceIndex.Get(paymentHash[:]) != nil {
-			return ErrDuplicateInvoice
-		}
+	invoiceIndex, err := invoices.CreateBucketIfNotExists(invoiceIndexBucket)
+	if err != nil {
+		return err
+	}
 
-		// If the current running payment ID counter hasn't yet been
-		// created, then create it now.
-		var invoiceNum uint32
-		invoiceCounter := invoiceIndex.Get(numInvoicesKey)
-		if invoiceCounter == nil {
-			var scratch [4]byte
-			byteOrder.PutUint32(scratch[:], invoiceNum)
-			if err := invoiceIndex.Put(numInvoicesKey, scratch[:]); err != nil {
-				return nil
-			}
-		} else {
-			invoiceNum = byteOrder.Uint32(invoiceCounter)
+	// Ensure that an invoice an identical payment hash doesn't
+	// already exist within the index.
+	paymentHash := i.Terms.PaymentHash
+	if invoiceIndex.Get(paymentHash[:]) != nil {
+		return ErrDuplicateInvoice
+	}
+
+	// If the current running payment ID counter hasn't yet been
+	// created, then create it now.
+	var invoiceNum uint32
+	invoiceCounter := invoiceIndex.Get(numInvoicesKey)
+	if invoiceCounter == nil {
+		var scratch [4]byte
+		byteOrder.PutUint32(scratch[:], invoiceNum)
+		if err := invoiceIndex.Put(numInvoicesKey, scratch[:]); err != nil {
+			return nil
 		}
+	} else {
+		invoiceNum = byteOrder.Uint32(invoiceCounter)
+	}
 
-		return putInvoice(invoices, invoiceIndex, i, invoiceNum)
-	})
+	addIndex, err := invoices.CreateBucketIfNotExists(addIndexBucket)
+	if err != nil {
+		return err
+	}
+
+	return putInvoice(invoices, invoiceIndex, addIndex, i, invoiceNum)
 }
 
 // LookupInvoice attempts to look up an invoice according to it's 32 byte
@@ -199,7 +346,7 @@ func (d *DB) LookupInvoice(paymentHash [32]byte) (*Invoice, error) {
 
 // FetchAllInvoices returns all invoices currently stored within the database.
 // If the pendingOnly param is true, then only unsettled invoices will be
-// returned, skipping all invoices that are fully settled.
+// returned, skipping all invoices that are either settled or canceled.
 func (d *DB) FetchAllInvoices(pendingOnly bool) ([]*Invoice, error) {
 	var invoices []*Invoice
 
@@ -223,7 +370,9 @@ func (d *DB) FetchAllInvoices(pendingOnly bool) ([]*Invoice, error) {
 				return err
 			}
 
-			if pendingOnly && invoice.Terms.Settled {
+			if pendingOnly && (invoice.Terms.State == ContractSettled ||
+				invoice.Terms.State == ContractCanceled) {
+
 				return nil
 			}
 
@@ -239,11 +388,95 @@ func (d *DB) FetchAllInvoices(pendingOnly bool) ([]*Invoice, error) {
 	return invoices, nil
 }
 
+// InvoicesAddedSince can be used by callers to seek into the event time series
+// of all the invoices added in the database. The specified sinceAddIndex
+// should be the highest add index that the caller knows of. This method will
+// return all invoices with an add index greater than the specified
+// sinceAddIndex, ordered by their add index.
+//
+// NOTE: The index starts from 1, as a result. We enforce that specifying a
+// value below the starting index value is a noop.
+func (d *DB) InvoicesAddedSince(sinceAddIndex uint64) ([]*Invoice, error) {
+	return d.invoicesSince(addIndexBucket, sinceAddIndex)
+}
+
+// InvoicesSettledSince can be used by callers to catch up any settled invoices
+// they missed within the settled invoice time series. The specified
+// sinceSettleIndex should be the highest settle index that the caller knows
+// of. This method will return all invoices with a settle index greater than
+// the specified sinceSettleIndex, ordered by their settle index.
+//
+// NOTE: The index starts from 1, as a result. We enforce that specifying a
+// value below the starting index value is a noop.
+func (d *DB) InvoicesSettledSince(sinceSettleIndex uint64) ([]*Invoice, error) {
+	return d.invoicesSince(settleIndexBucket, sinceSettleIndex)
+}
+
+// invoicesSince returns all invoices within the target index bucket with an
+// index greater than sinceIndex.
+func (d *DB) invoicesSince(indexBucket []byte,
+	sinceIndex uint64) ([]*Invoice, error) {
+
+	var invoices []*Invoice
+
+	// If an index of zero was specified, then in order to maintain
+	// backwards compat, we won't send out any new invoices.
+	if sinceIndex == 0 {
+		return invoices, nil
+	}
+
+	var startIndex [8]byte
+	byteOrder.PutUint64(startIndex[:], sinceIndex)
+
+	err := d.View(func(tx *bolt.Tx) error {
+		invoiceB := tx.Bucket(invoiceBucket)
+		if invoiceB == nil {
+			return ErrNoInvoicesCreated
+		}
+
+		index := invoiceB.Bucket(indexBucket)
+		if index == nil {
+			return nil
+		}
+
+		// We'll now run through each entry in the index which has an
+		// index greater than the since index. As the keys are
+		// big-endian, the cursor will visit them in order.
+		c := index.Cursor()
+		for k, invoiceKey := c.Seek(startIndex[:]); k != nil; k, invoiceKey = c.Next() {
+			// The seek above positions the cursor at the since
+			// index itself if it exists, which the caller already
+			// knows of, so we skip it.
+			if bytes.Equal(k, startIndex[:]) {
+				continue
+			}
+
+			invoice, err := fetchInvoice(invoiceKey, invoiceB)
+			if err != nil {
+				return err
+			}
+
+			invoices = append(invoices, invoice)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return invoices, nil
+}
+
 // SettleInvoice attempts to mark an invoice corresponding to the passed
 // payment hash as fully settled. If an invoice matching the passed payment
 // hash doesn't existing within the database, then the action will fail with a
-// "not found" error.
-func (d *DB) SettleInvoice(paymentHash [32]byte) error {
+// "not found" error. Settling an invoice assigns it the next settle index.
+// If non-nil, the passed HTLC is recorded as having settled the invoice. This
+// is also the case if the invoice was already settled, however the invoice
+// retains its original settle index, and an HTLC which has already been
+// recorded isn't recorded again.
+func (d *DB) SettleInvoice(paymentHash [32]byte, htlc *InvoiceHTLC) error {
 	return d.Update(func(tx *bolt.Tx) error {
 		invoices, err := tx.CreateBucketIfNotExists(invoiceBucket)
 		if err != nil {
@@ -261,11 +494,158 @@ func (d *DB) SettleInvoice(paymentHash [32]byte) error {
 			return ErrInvoiceNotFound
 		}
 
-		return settleInvoice(invoices, invoiceNum)
+		settleIndex, err := invoices.CreateBucketIfNotExists(
+			settleIndexBucket,
+		)
+		if err != nil {
+			return err
+		}
+
+		return settleInvoice(invoices, settleIndex, invoiceNum, htlc)
+	})
+}
+
+// AcceptInvoice attempts to mark the hold invoice corresponding to the passed
+// payment hash as accepted, recording the passed HTLC as the one paying it.
+// The HTLC is then held until the invoice is either settled through
+// SettleHoldInvoice, or canceled through CancelInvoice. Only open hold
+// invoices may be accepted.
+func (d *DB) AcceptInvoice(paymentHash [32]byte, htlc *InvoiceHTLC) error {
+	return d.updateInvoice(paymentHash, func(invoice *Invoice) error {
+		if invoice.Terms.PaymentPreimage != UnknownPreimage {
+			return ErrNotHoldInvoice
+		}
+
+		switch invoice.Terms.State {
+		case ContractAccepted:
+			return ErrInvoiceAlreadyAccepted
+		case ContractSettled:
+			return ErrInvoiceAlreadySettled
+		case ContractCanceled:
+			return ErrInvoiceAlreadyCanceled
+		}
+
+		invoice.Terms.State = ContractAccepted
+		invoice.Htlcs = append(invoice.Htlcs, *htlc)
+
+		return nil
+	})
+}
+
+// SettleHoldInvoice settles the accepted hold invoice paid to the hash of the
+// passed preimage, which is revealed by the invoice from then on. The HTLC
+// which was accepted is recorded as having settled the invoice at the current
+// time, and the invoice is assigned the next settle index.
+func (d *DB) SettleHoldInvoice(preimage [32]byte) error {
+	paymentHash := sha256.Sum256(preimage[:])
+
+	return d.Update(func(tx *bolt.Tx) error {
+		invoices, err := tx.CreateBucketIfNotExists(invoiceBucket)
+		if err != nil {
+			return err
+		}
+		invoiceIndex, err := invoices.CreateBucketIfNotExists(invoiceIndexBucket)
+		if err != nil {
+			return err
+		}
+
+		invoiceNum := invoiceIndex.Get(paymentHash[:])
+		if invoiceNum == nil {
+			return ErrInvoiceNotFound
+		}
+
+		invoice, err := fetchInvoice(invoiceNum, invoices)
+		if err != nil {
+			return err
+		}
+
+		switch invoice.Terms.State {
+		case ContractOpen:
+			return ErrInvoiceNotAccepted
+		case ContractSettled:
+			return ErrInvoiceAlreadySettled
+		case ContractCanceled:
+			return ErrInvoiceAlreadyCanceled
+		}
+
+		invoice.Terms.PaymentPreimage = preimage
+		settleTime := time.Now()
+		for i := range invoice.Htlcs {
+			invoice.Htlcs[i].SettleTime = settleTime
+		}
+
+		settleIndex, err := invoices.CreateBucketIfNotExists(
+			settleIndexBucket,
+		)
+		if err != nil {
+			return err
+		}
+
+		return markInvoiceSettled(
+			invoices, settleIndex, invoiceNum, invoice,
+		)
+	})
+}
+
+// CancelInvoice attempts to cancel the invoice corresponding to the passed
+// payment hash, after which it can no longer be paid. Any HTLC accepted to
+// pay a hold invoice is discarded, as it's expected to be failed back by the
+// caller. Settled invoices can't be canceled.
+func (d *DB) CancelInvoice(paymentHash [32]byte) error {
+	return d.updateInvoice(paymentHash, func(invoice *Invoice) error {
+		switch invoice.Terms.State {
+		case ContractSettled:
+			return ErrInvoiceAlreadySettled
+		case ContractCanceled:
+			return ErrInvoiceAlreadyCanceled
+		}
+
+		invoice.Terms.State = ContractCanceled
+		invoice.Htlcs = nil
+
+		return nil
+	})
+}
+
+// updateInvoice applies the passed update to the invoice corresponding to the
+// passed payment hash, writing it back should the update succeed.
+func (d *DB) updateInvoice(paymentHash [32]byte,
+	update func(*Invoice) error) error {
+
+	return d.Update(func(tx *bolt.Tx) error {
+		invoices := tx.Bucket(invoiceBucket)
+		if invoices == nil {
+			return ErrNoInvoicesCreated
+		}
+		invoiceIndex := invoices.Bucket(invoiceIndexBucket)
+		if invoiceIndex == nil {
+			return ErrNoInvoicesCreated
+		}
+
+		invoiceNum := invoiceIndex.Get(paymentHash[:])
+		if invoiceNum == nil {
+			return ErrInvoiceNotFound
+		}
+
+		invoice, err := fetchInvoice(invoiceNum, invoices)
+		if err != nil {
+			return err
+		}
+
+		if err := update(invoice); err != nil {
+			return err
+		}
+
+		var buf bytes.Buffer
+		if err := serializeInvoice(&buf, invoice); err != nil {
+			return err
+		}
+
+		return invoices.Put(invoiceNum, buf.Bytes())
 	})
 }
 
-func putInvoice(invoices *bolt.Bucket, invoiceIndex *bolt.Bucket,
+func putInvoice(invoices, invoiceIndex, addIndex *bolt.Bucket,
 	i *Invoice, invoiceNum uint32) error {
 
 	// Create the invoice key which is just the big-endian representation
@@ -285,11 +665,25 @@ func putInvoice(invoices *bolt.Bucket, invoiceIndex *bolt.Bucket,
 	// Add the payment hash to the invoice index. This'll let us quickly
 	// identify if we can settle an incoming payment, and also to possibly
 	// allow a single invoice to have multiple payment installations.
-	paymentHash := sha256.Sum256(i.Terms.PaymentPreimage[:])
+	paymentHash := i.Terms.PaymentHash
 	if err := invoiceIndex.Put(paymentHash[:], invoiceKey[:]); err != nil {
 		return err
 	}
 
+	// Next, we'll obtain the next add index for this invoice, and map it
+	// to the invoice's key within the add index bucket.
+	nextAddSeqNo, err := addIndex.NextSequence()
+	if err != nil {
+		return err
+	}
+	var seqNoBytes [8]byte
+	byteOrder.PutUint64(seqNoBytes[:], nextAddSeqNo)
+	if err := addIndex.Put(seqNoBytes[:], invoiceKey[:]); err != nil {
+		return err
+	}
+
+	i.AddIndex = nextAddSeqNo
+
 	// Finally, serialize the invoice itself to be written to the disk.
 	var buf bytes.Buffer
 	if err := serializeInvoice(&buf, i); err != nil {
@@ -325,11 +719,41 @@ func serializeInvoice(w io.Writer, i *Invoice) error {
 		return err
 	}
 
-	var settleByte [1]byte
-	if i.Terms.Settled {
-		settleByte[0] = 1
+	// The state is written where a settled flag was previously, which
+	// remains compatible as open and settled invoices are zero and one.
+	stateByte := [1]byte{byte(i.Terms.State)}
+	if _, err := w.Write(stateByte[:]); err != nil {
+		return err
+	}
+
+	byteOrder.PutUint64(scratch[:], i.AddIndex)
+	if _, err := w.Write(scratch[:]); err != nil {
+		return err
 	}
-	if _, err := w.Write(settleByte[:]); err != nil {
+	byteOrder.PutUint64(scratch[:], i.SettleIndex)
+	if _, err := w.Write(scratch[:]); err != nil {
+		return err
+	}
+
+	if err := wire.WriteVarInt(w, 0, uint64(len(i.Htlcs))); err != nil {
+		return err
+	}
+	for _, htlc := range i.Htlcs {
+		err := writeElements(
+			w, htlc.ChanID, htlc.HtlcID, htlc.Amt,
+			htlc.Expiry, htlc.AcceptHeight, htlc.SettleTime,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	// The payment hash is written after the HTLCs, as it was added after
+	// them, followed by the payment address, which was added last.
+	if _, err := w.Write(i.Terms.PaymentHash[:]); err != nil {
+		return err
+	}
+	if _, err := w.Write(i.Terms.PaymentAddr[:]); err != nil {
 		return err
 	}
 
@@ -372,30 +796,160 @@ func deserializeInvoice(r io.Reader) (*Invoice, error) {
 	if _, err := io.ReadFull(r, invoice.Terms.PaymentPreimage[:]); err != nil {
 		return nil, err
 	}
+
+	// Invoices written before the payment hash was stored can only have
+	// a known preimage, so the hash is derived from it unless it's read
+	// below.
+	invoice.Terms.PaymentHash = sha256.Sum256(
+		invoice.Terms.PaymentPreimage[:],
+	)
+
 	var scratch [8]byte
 	if _, err := io.ReadFull(r, scratch[:]); err != nil {
 		return nil, err
 	}
-	invoice.Terms.Value = lnwire.MilliSatoshi(byteOrder.Uint64(scratch[:]))
+	invoice.Terms.Value = lnwire.MilliAtom(byteOrder.Uint64(scratch[:]))
+
+	var stateByte [1]byte
+	if _, err := io.ReadFull(r, stateByte[:]); err != nil {
+		return nil, err
+	}
+	invoice.Terms.State = ContractState(stateByte[0])
+
+	if _, err := io.ReadFull(r, scratch[:]); err != nil {
+		return nil, err
+	}
+	invoice.AddIndex = byteOrder.Uint64(scratch[:])
 
-	var settleByte [1]byte
-	if _, err := io.ReadFull(r, settleByte[:]); err != nil {
+	if _, err := io.ReadFull(r, scratch[:]); err != nil {
+		return nil, err
+	}
+	invoice.SettleIndex = byteOrder.Uint64(scratch[:])
+
+	// Invoices written before the settling HTLCs were recorded end
+	// directly after the settle index.
+	numHtlcs, err := wire.ReadVarInt(r, 0)
+	switch {
+	case err == io.EOF:
+		return invoice, nil
+	case err != nil:
 		return nil, err
+	case numHtlcs > MaxInvoiceHTLCs:
+		return nil, fmt.Errorf("invoice has %v htlcs, max is %v",
+			numHtlcs, MaxInvoiceHTLCs)
+	}
+
+	if numHtlcs > 0 {
+		invoice.Htlcs = make([]InvoiceHTLC, numHtlcs)
+	}
+	for i := range invoice.Htlcs {
+		htlc := &invoice.Htlcs[i]
+		err := readElements(
+			r, &htlc.ChanID, &htlc.HtlcID, &htlc.Amt, &htlc.Expiry,
+			&htlc.AcceptHeight, &htlc.SettleTime,
+		)
+		if err != nil {
+			return nil, err
+		}
 	}
-	if settleByte[0] == 1 {
-		invoice.Terms.Settled = true
+
+	var paymentHash [32]byte
+	_, err = io.ReadFull(r, paymentHash[:])
+	switch {
+	case err == io.EOF:
+		return invoice, nil
+	case err != nil:
+		return nil, err
+	default:
+		invoice.Terms.PaymentHash = paymentHash
+	}
+
+	// Invoices written before the payment address was stored end directly
+	// after the payment hash, leaving their address zero.
+	var paymentAddr [32]byte
+	_, err = io.ReadFull(r, paymentAddr[:])
+	switch {
+	case err == io.EOF:
+	case err != nil:
+		return nil, err
+	default:
+		invoice.Terms.PaymentAddr = paymentAddr
 	}
 
 	return invoice, nil
 }
 
-func settleInvoice(invoices *bolt.Bucket, invoiceNum []byte) error {
+func settleInvoice(invoices, settleIndex *bolt.Bucket, invoiceNum []byte,
+	htlc *InvoiceHTLC) error {
+
 	invoice, err := fetchInvoice(invoiceNum, invoices)
 	if err != nil {
 		return err
 	}
 
-	invoice.Terms.Settled = true
+	// Canceled invoices can no longer be paid, while hold invoices may
+	// only be settled once their preimage is known.
+	switch {
+	case invoice.Terms.State == ContractCanceled:
+		return ErrInvoiceAlreadyCanceled
+	case invoice.Terms.PaymentPreimage == UnknownPreimage:
+		return ErrHoldInvoicePreimageUnknown
+	}
+
+	// Record the HTLC, unless it has already been recorded, which may be
+	// the case if the settle is replayed after a restart.
+	var htlcAdded bool
+	if htlc != nil && !invoice.hasHtlc(htlc.ChanID, htlc.HtlcID) {
+		if len(invoice.Htlcs) >= MaxInvoiceHTLCs {
+			return fmt.Errorf("invoice already has max of %v "+
+				"htlcs", MaxInvoiceHTLCs)
+		}
+
+		invoice.Htlcs = append(invoice.Htlcs, *htlc)
+		htlcAdded = true
+	}
+
+	// If the invoice has already been settled, then we'll only write out
+	// any newly recorded HTLC, so we don't assign it another settle index.
+	if invoice.Terms.State == ContractSettled {
+		if !htlcAdded {
+			return nil
+		}
+
+		var buf bytes.Buffer
+		if err := serializeInvoice(&buf, invoice); err != nil {
+			return err
+		}
+
+		return invoices.Put(invoiceNum[:], buf.Bytes())
+	}
+
+	return markInvoiceSettled(invoices, settleIndex, invoiceNum, invoice)
+}
+
+// markInvoiceSettled transitions the passed invoice to the settled state,
+// assigning it the next settle index, and writes it out.
+func markInvoiceSettled(invoices, settleIndex *bolt.Bucket, invoiceNum []byte,
+	invoice *Invoice) error {
+
+	invoice.Terms.State = ContractSettled
+
+	// Now that we know the invoice hasn't already been settled, we'll
+	// obtain the next settle index, and map it to the invoice's key
+	// within the settle index bucket. The key is copied as the passed
+	// slice points into the memory-mapped database.
+	nextSettleSeqNo, err := settleIndex.NextSequence()
+	if err != nil {
+		return err
+	}
+	var seqNoBytes [8]byte
+	byteOrder.PutUint64(seqNoBytes[:], nextSettleSeqNo)
+	invoiceKey := append([]byte(nil), invoiceNum...)
+	if err := settleIndex.Put(seqNoBytes[:], invoiceKey); err != nil {
+		return err
+	}
+
+	invoice.SettleIndex = nextSettleSeqNo
 
 	var buf bytes.Buffer
 	if err := serializeInvoice(&buf, invoice); err != nil {
@@ -406,3 +960,15 @@ func settleInvoice(invoices *bolt.Bucket, invoiceNum []byte) error {
 
 	return invoices.Put(invoiceNum[:], buf.Bytes())
 }
+
+// hasHtlc returns true if the HTLC with the passed channel and HTLC ID has
+// been recorded as having settled the invoice.
+func (i *Invoice) hasHtlc(chanID lnwire.ShortChannelID, htlcID uint64) bool {
+	for _, htlc := range i.Htlcs {
+		if htlc.ChanID == chanID && htlc.HtlcID == htlcID {
+			return true
+		}
+	}
+
+	return false
+}
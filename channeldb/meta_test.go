@@ -2,9 +2,11 @@ package channeldb
 
 import (
 	"bytes"
+	"fmt"
 	"testing"
 
 	"github.com/boltdb/bolt"
+	"github.com/davecgh/go-spew/spew"
 	"github.com/go-errors/errors"
 )
 
@@ -52,13 +54,13 @@ func TestOrderOfMigrations(t *testing.T) {
 
 	appliedMigration := -1
 	versions := []version{
-		{0, nil},
-		{1, nil},
-		{2, func(tx *bolt.Tx) error {
+		{number: 0},
+		{number: 1},
+		{number: 2, migration: func(tx *bolt.Tx) error {
 			appliedMigration = 2
 			return nil
 		}},
-		{3, func(tx *bolt.Tx) error {
+		{number: 3, migration: func(tx *bolt.Tx) error {
 			appliedMigration = 3
 			return nil
 		}},
@@ -380,3 +382,176 @@ func TestMigrationWithoutErrors(t *testing.T) {
 		migrationWithoutErrors,
 		false)
 }
+
+// TestChunkedMigrationResume checks that a chunked migration which is
+// interrupted part way through resumes from its last checkpoint, rather than
+// starting over, and that the database version is only bumped once the final
+// chunk has been migrated.
+//
+// NOTE: This test isn't run in parallel as it inspects the global migration
+// status.
+func TestChunkedMigrationResume(t *testing.T) {
+	cdb, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const (
+		numItems  = 10
+		chunkSize = 3
+	)
+
+	bucketKey := []byte("somebucket")
+	beforeMigration := []byte("beforemigration")
+	afterMigration := []byte("aftermigration")
+
+	// Populate the database with the items to be migrated, and start off
+	// at the base version of the database.
+	err = cdb.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(bucketKey)
+		if err != nil {
+			return err
+		}
+		for i := byte(0); i < numItems; i++ {
+			if err := bucket.Put([]byte{i}, beforeMigration); err != nil {
+				return err
+			}
+		}
+
+		return putMeta(&Meta{DbVersionNumber: 0}, tx)
+	})
+	if err != nil {
+		t.Fatalf("unable to populate db: %v", err)
+	}
+
+	// The migration rewrites the value of each item within the bucket,
+	// tracking how many times each item is migrated. Once failAfter
+	// chunks have been migrated, it'll fail in order to simulate the
+	// daemon being interrupted.
+	migrated := make(map[byte]int)
+	var numChunks, failAfter int
+	chunked := &chunkedMigration{
+		countItems: func(tx *bolt.Tx) (uint64, error) {
+			return uint64(tx.Bucket(bucketKey).Stats().KeyN), nil
+		},
+		migrateChunk: func(tx *bolt.Tx, checkpoint []byte) ([]byte,
+			uint64, error) {
+
+			if failAfter != 0 && numChunks == failAfter {
+				return nil, 0, errors.New("interrupted")
+			}
+			numChunks++
+
+			bucket := tx.Bucket(bucketKey)
+			c := bucket.Cursor()
+
+			k, _ := c.First()
+			if checkpoint != nil {
+				c.Seek(checkpoint)
+				k, _ = c.Next()
+			}
+
+			var (
+				last []byte
+				n    uint64
+			)
+			for ; k != nil && n < chunkSize; k, _ = c.Next() {
+				if err := bucket.Put(k, afterMigration); err != nil {
+					return nil, 0, err
+				}
+				migrated[k[0]]++
+
+				last = k
+				n++
+			}
+
+			// If we've reached the end of the bucket, then the
+			// migration is complete.
+			if k == nil {
+				return nil, n, nil
+			}
+
+			return last, n, nil
+		},
+	}
+
+	versions := []version{
+		{number: 0},
+		{number: 1, chunkedMigration: chunked},
+	}
+
+	// Interrupt the migration after two chunks. The database version
+	// should remain unchanged, while the checkpoint reflects the items
+	// migrated so far.
+	failAfter = 2
+	if err := cdb.syncVersions(versions); err == nil {
+		t.Fatal("expected migration to be interrupted")
+	}
+
+	meta, err := cdb.FetchMeta(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.DbVersionNumber != 0 {
+		t.Fatalf("expected db version 0 after interrupted migration, "+
+			"got %v", meta.DbVersionNumber)
+	}
+	if len(migrated) != 2*chunkSize {
+		t.Fatalf("expected %v items migrated, got %v", 2*chunkSize,
+			len(migrated))
+	}
+	status := CurrentMigrationStatus()
+	if status.Migrating || status.ItemsMigrated != 2*chunkSize {
+		t.Fatalf("unexpected migration status: %v", spew.Sdump(status))
+	}
+
+	// Resuming the migration should only migrate the remaining items,
+	// then bump the database version and remove the checkpoint.
+	failAfter = 0
+	if err := cdb.syncVersions(versions); err != nil {
+		t.Fatalf("unable to resume migration: %v", err)
+	}
+
+	meta, err = cdb.FetchMeta(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.DbVersionNumber != 1 {
+		t.Fatalf("expected db version 1, got %v", meta.DbVersionNumber)
+	}
+	for i := byte(0); i < numItems; i++ {
+		if migrated[i] != 1 {
+			t.Fatalf("expected item %v to be migrated once, was "+
+				"migrated %v times", i, migrated[i])
+		}
+	}
+
+	status = CurrentMigrationStatus()
+	if status.Migrating || status.CurrentVersion != 1 ||
+		status.ItemsMigrated != numItems ||
+		status.TotalItems != numItems {
+
+		t.Fatalf("unexpected migration status: %v", spew.Sdump(status))
+	}
+
+	err = cdb.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketKey)
+		for i := byte(0); i < numItems; i++ {
+			v := bucket.Get([]byte{i})
+			if !bytes.Equal(v, afterMigration) {
+				return fmt.Errorf("item %v wasn't migrated", i)
+			}
+		}
+
+		checkpoints := tx.Bucket(migrationCheckpointBucket)
+		if checkpoints != nil && checkpoints.Stats().KeyN != 0 {
+			return fmt.Errorf("checkpoint wasn't removed")
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
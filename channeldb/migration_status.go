@@ -0,0 +1,275 @@
+package channeldb
+
+import (
+	"sync"
+	"time"
+
+	"github.com/boltdb/bolt"
+)
+
+var (
+	// migrationCheckpointBucket houses the checkpoint of each chunked
+	// migration which is currently in progress. Once a chunked migration
+	// completes, its checkpoint is removed within the same transaction
+	// that bumps the version of the database.
+	//
+	// maps: dbVersion -> itemsMigrated || checkpoint
+	migrationCheckpointBucket = []byte("migration-checkpoints")
+
+	// migrationLogInterval is the minimum amount of time between each
+	// message logged to report the progress of a chunked migration.
+	migrationLogInterval = time.Second * 30
+
+	// migrationTracker tracks the progress of the migrations being applied
+	// as the database is opened.
+	migrationTracker = &migrationProgress{}
+)
+
+// chunkedMigration is a heavyweight migration which is applied over a series
+// of database transactions, rather than within a single one. Migrating a
+// large database within a single transaction requires holding every dirty
+// page in memory, and all progress is lost if the daemon is interrupted. A
+// chunked migration instead persists a checkpoint after each chunk, allowing
+// an interrupted migration to resume where it left off.
+type chunkedMigration struct {
+	// countItems returns the total number of items to be migrated. This
+	// is only used in order to report the progress of the migration.
+	countItems func(tx *bolt.Tx) (uint64, error)
+
+	// migrateChunk migrates the next chunk of items, beginning after the
+	// item identified by the passed checkpoint, or from the first item if
+	// the checkpoint is nil. It returns the checkpoint of the last item
+	// migrated along with the number of items migrated within the chunk.
+	// A nil checkpoint signals that the migration is complete.
+	migrateChunk func(tx *bolt.Tx, checkpoint []byte) ([]byte, uint64, error)
+}
+
+// MigrationStatus is a snapshot of the progress of the migrations being
+// applied as the database is opened.
+type MigrationStatus struct {
+	// Migrating is true if migrations are currently being applied.
+	Migrating bool
+
+	// CurrentVersion is the version the database is currently at.
+	CurrentVersion uint32
+
+	// LatestVersion is the version the database is being migrated to.
+	LatestVersion uint32
+
+	// Migration is the number of the migration currently being applied.
+	Migration uint32
+
+	// ItemsMigrated is the number of items the current migration has
+	// migrated so far. This is only tracked for chunked migrations.
+	ItemsMigrated uint64
+
+	// TotalItems is the total number of items the current migration
+	// needs to migrate, or zero if unknown.
+	TotalItems uint64
+
+	// StartTime is the time the current migration was started.
+	StartTime time.Time
+}
+
+// CurrentMigrationStatus returns a snapshot of the progress of the migrations
+// being applied as the database is opened. It's safe to call concurrently
+// with Open.
+func CurrentMigrationStatus() MigrationStatus {
+	return migrationTracker.snapshot()
+}
+
+// migrationProgress tracks the progress of the database migrations, and
+// periodically logs the progress of long running migrations.
+type migrationProgress struct {
+	sync.Mutex
+
+	status  MigrationStatus
+	lastLog time.Time
+}
+
+// start marks the beginning of the migration of the database from the current
+// version to the latest version.
+func (m *migrationProgress) start(currentVersion, latestVersion uint32) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.status = MigrationStatus{
+		Migrating:      true,
+		CurrentVersion: currentVersion,
+		LatestVersion:  latestVersion,
+	}
+}
+
+// startMigration marks the beginning of the application of a single
+// migration, which has already migrated itemsMigrated of totalItems items.
+func (m *migrationProgress) startMigration(migration uint32,
+	itemsMigrated, totalItems uint64) {
+
+	m.Lock()
+	defer m.Unlock()
+
+	m.status.Migration = migration
+	m.status.ItemsMigrated = itemsMigrated
+	m.status.TotalItems = totalItems
+	m.status.StartTime = time.Now()
+	m.lastLog = m.status.StartTime
+}
+
+// addProgress records that numItems more items have been migrated by the
+// current migration, logging the progress of the migration if we haven't
+// done so recently.
+func (m *migrationProgress) addProgress(numItems uint64) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.status.ItemsMigrated += numItems
+
+	if time.Since(m.lastLog) < migrationLogInterval {
+		return
+	}
+	m.lastLog = time.Now()
+
+	if m.status.TotalItems == 0 {
+		log.Infof("Migration #%v: migrated %v items in %v",
+			m.status.Migration, m.status.ItemsMigrated,
+			time.Since(m.status.StartTime))
+		return
+	}
+
+	log.Infof("Migration #%v: migrated %v/%v items (%.2f%%) in %v",
+		m.status.Migration, m.status.ItemsMigrated,
+		m.status.TotalItems, float64(m.status.ItemsMigrated)/
+			float64(m.status.TotalItems)*100,
+		time.Since(m.status.StartTime))
+}
+
+// finishMigration marks the migration currently being applied as complete,
+// having brought the database up to its version.
+func (m *migrationProgress) finishMigration() {
+	m.Lock()
+	defer m.Unlock()
+
+	log.Infof("Migration #%v completed in %v", m.status.Migration,
+		time.Since(m.status.StartTime))
+
+	m.status.CurrentVersion = m.status.Migration
+}
+
+// finish marks the end of the migration of the database, whether all
+// migrations were successfully applied or not.
+func (m *migrationProgress) finish() {
+	m.Lock()
+	defer m.Unlock()
+
+	m.status.Migrating = false
+}
+
+// snapshot returns a copy of the current migration status.
+func (m *migrationProgress) snapshot() MigrationStatus {
+	m.Lock()
+	defer m.Unlock()
+
+	return m.status
+}
+
+// applyChunkedMigration applies the chunked migration for the target version,
+// resuming from its last checkpoint if it was previously interrupted. Each
+// chunk is migrated within its own transaction along with its checkpoint, and
+// the final chunk also removes the checkpoint and bumps the version of the
+// database.
+func (d *DB) applyChunkedMigration(v version, meta *Meta) error {
+	var versionKey [4]byte
+	byteOrder.PutUint32(versionKey[:], v.number)
+
+	// Before we begin, we'll fetch the checkpoint of the migration, if it
+	// was previously interrupted, along with the total number of items to
+	// be migrated.
+	var (
+		checkpoint    []byte
+		itemsMigrated uint64
+		totalItems    uint64
+	)
+	err := d.View(func(tx *bolt.Tx) error {
+		checkpoints := tx.Bucket(migrationCheckpointBucket)
+		if checkpoints != nil {
+			if c := checkpoints.Get(versionKey[:]); c != nil {
+				itemsMigrated = byteOrder.Uint64(c[:8])
+				checkpoint = append([]byte(nil), c[8:]...)
+			}
+		}
+
+		var err error
+		totalItems, err = v.chunkedMigration.countItems(tx)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if checkpoint != nil {
+		log.Infof("Resuming migration #%v after %v items", v.number,
+			itemsMigrated)
+	} else {
+		log.Infof("Applying migration #%v", v.number)
+	}
+	migrationTracker.startMigration(v.number, itemsMigrated, totalItems)
+
+	for {
+		var numMigrated uint64
+		err := d.Update(func(tx *bolt.Tx) error {
+			next, n, err := v.chunkedMigration.migrateChunk(
+				tx, checkpoint,
+			)
+			if err != nil {
+				return err
+			}
+
+			// The checkpoint may reference memory which is only
+			// valid for the duration of the transaction, so we'll
+			// copy it before persisting it.
+			checkpoint = nil
+			if next != nil {
+				checkpoint = append([]byte{}, next...)
+			}
+			numMigrated = n
+
+			checkpoints, err := tx.CreateBucketIfNotExists(
+				migrationCheckpointBucket,
+			)
+			if err != nil {
+				return err
+			}
+
+			// If the migration is complete, then we'll remove its
+			// checkpoint and bump the version of the database in
+			// the same transaction as the final chunk.
+			if checkpoint == nil {
+				if err := checkpoints.Delete(versionKey[:]); err != nil {
+					return err
+				}
+
+				meta.DbVersionNumber = v.number
+				return putMeta(meta, tx)
+			}
+
+			var itemsBytes [8]byte
+			byteOrder.PutUint64(
+				itemsBytes[:], itemsMigrated+numMigrated,
+			)
+			return checkpoints.Put(
+				versionKey[:], append(itemsBytes[:], checkpoint...),
+			)
+		})
+		if err != nil {
+			log.Infof("Unable to apply migration #%v", v.number)
+			return err
+		}
+
+		itemsMigrated += numMigrated
+		migrationTracker.addProgress(numMigrated)
+
+		if checkpoint == nil {
+			return nil
+		}
+	}
+}
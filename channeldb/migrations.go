@@ -1 +1,878 @@
 package channeldb
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+
+	"github.com/boltdb/bolt"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+var (
+	// legacyPaymentBucket is the name of the bucket which stored all
+	// completed payments prior to the introduction of per-attempt HTLC
+	// tracking. Within the bucket, each payment was keyed by a
+	// monotonically increasing uint64.
+	legacyPaymentBucket = []byte("payments")
+)
+
+// legacyOutgoingPayment is the format payments were stored in before the
+// payments bucket was redesigned to track each individual HTLC attempt. It is
+// only retained in order to migrate existing payments.
+type legacyOutgoingPayment struct {
+	Invoice
+
+	// Fee is the total fee paid for the payment in milli-satoshis.
+	Fee lnwire.MilliAtom
+
+	// TimeLockLength is the total cumulative time-lock in the HTLC
+	// extended from the second-to-last hop to the destination.
+	TimeLockLength uint32
+
+	// Path encodes the path the payment took through the network. The
+	// path excludes the outgoing node and consists of the compressed
+	// public key of each of the nodes involved in the payment.
+	Path [][33]byte
+
+	// PaymentHash is the payment hash (r-hash) used to send the payment.
+	PaymentHash [32]byte
+}
+
+// migrateOutgoingPayments moves all payments stored within the legacy flat
+// payments bucket into the new payments bucket, which tracks each payment
+// along with every HTLC attempt made in order to complete it. As only
+// successful payments were stored within the legacy bucket, each payment is
+// migrated as a payment with a single settled HTLC attempt. Payments made to
+// an already migrated payment hash are retained as duplicates of it.
+func migrateOutgoingPayments(tx *bolt.Tx) error {
+	legacyPayments := tx.Bucket(legacyPaymentBucket)
+	if legacyPayments == nil {
+		return nil
+	}
+
+	var payments []*legacyOutgoingPayment
+	err := legacyPayments.ForEach(func(k, v []byte) error {
+		// If the value is nil, then we ignore it as it may be a
+		// sub-bucket.
+		if v == nil {
+			return nil
+		}
+
+		payment, err := deserializeLegacyOutgoingPayment(
+			bytes.NewReader(v),
+		)
+		if err != nil {
+			return err
+		}
+
+		payments = append(payments, payment)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Migrating %v payments to new payments bucket",
+		len(payments))
+
+	paymentsRoot, err := tx.CreateBucketIfNotExists(paymentsRootBucket)
+	if err != nil {
+		return err
+	}
+
+	var numDuplicates int
+	for _, p := range payments {
+		sequenceNum, err := paymentsRoot.NextSequence()
+		if err != nil {
+			return err
+		}
+		var seqBytes [8]byte
+		byteOrder.PutUint64(seqBytes[:], sequenceNum)
+
+		// As the legacy bucket didn't enforce unique payment hashes,
+		// any further payments to the same hash are stored as
+		// duplicates within the bucket of the first, keyed by their
+		// own sequence number.
+		bucket := paymentsRoot.Bucket(p.PaymentHash[:])
+		if bucket != nil {
+			duplicates, err := bucket.CreateBucketIfNotExists(
+				paymentDuplicateBucket,
+			)
+			if err != nil {
+				return err
+			}
+			bucket, err = duplicates.CreateBucket(seqBytes[:])
+			if err != nil {
+				return err
+			}
+			numDuplicates++
+		} else {
+			bucket, err = paymentsRoot.CreateBucket(p.PaymentHash[:])
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := bucket.Put(paymentSequenceKey, seqBytes[:]); err != nil {
+			return err
+		}
+
+		// Note that each value is serialized into its own buffer, as
+		// bolt requires values to remain valid for the lifetime of the
+		// transaction.
+		var infoBytes bytes.Buffer
+		err = serializePaymentCreationInfo(&infoBytes, &PaymentCreationInfo{
+			PaymentHash:  p.PaymentHash,
+			Value:        p.Terms.Value,
+			CreationDate: p.CreationDate,
+		})
+		if err != nil {
+			return err
+		}
+		err = bucket.Put(paymentCreationInfoKey, infoBytes.Bytes())
+		if err != nil {
+			return err
+		}
+
+		// Reconstruct as much of the route as the legacy format
+		// allows. Only the public keys of each hop were stored.
+		route := Route{
+			TotalTimeLock: p.TimeLockLength,
+			TotalFees:     p.Fee,
+			TotalAmount:   p.Terms.Value + p.Fee,
+			Hops:          make([]*Hop, len(p.Path)),
+		}
+		for i, pub := range p.Path {
+			route.Hops[i] = &Hop{
+				PubKeyBytes: pub,
+			}
+		}
+
+		attemptID, err := paymentsRoot.NextSequence()
+		if err != nil {
+			return err
+		}
+
+		htlcsBucket, err := bucket.CreateBucket(paymentHtlcsBucket)
+		if err != nil {
+			return err
+		}
+
+		var attemptBytes bytes.Buffer
+		err = serializeHTLCAttemptInfo(&attemptBytes, &HTLCAttemptInfo{
+			AttemptID:   attemptID,
+			Route:       route,
+			AttemptTime: p.CreationDate,
+		})
+		if err != nil {
+			return err
+		}
+		err = htlcsBucket.Put(
+			htlcBucketKey(htlcAttemptInfoKey, attemptID),
+			attemptBytes.Bytes(),
+		)
+		if err != nil {
+			return err
+		}
+
+		var settleBytes bytes.Buffer
+		err = serializeHTLCSettleInfo(&settleBytes, &HTLCSettleInfo{
+			Preimage:   p.Terms.PaymentPreimage,
+			SettleTime: p.CreationDate,
+		})
+		if err != nil {
+			return err
+		}
+		err = htlcsBucket.Put(
+			htlcBucketKey(htlcSettleInfoKey, attemptID),
+			settleBytes.Bytes(),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Infof("Migration of payments complete, %v duplicate payments "+
+		"retained", numDuplicates)
+
+	return tx.DeleteBucket(legacyPaymentBucket)
+}
+
+func serializeLegacyOutgoingPayment(w io.Writer, p *legacyOutgoingPayment) error {
+	var scratch [8]byte
+
+	if err := serializeLegacyInvoice(w, &p.Invoice); err != nil {
+		return err
+	}
+
+	byteOrder.PutUint64(scratch[:], uint64(p.Fee))
+	if _, err := w.Write(scratch[:]); err != nil {
+		return err
+	}
+
+	// First write out the length of the bytes to prefix the value.
+	pathLen := uint32(len(p.Path))
+	byteOrder.PutUint32(scratch[:4], pathLen)
+	if _, err := w.Write(scratch[:4]); err != nil {
+		return err
+	}
+
+	// Then with the path written, we write out the series of public keys
+	// involved in the path.
+	for _, hop := range p.Path {
+		if _, err := w.Write(hop[:]); err != nil {
+			return err
+		}
+	}
+
+	byteOrder.PutUint32(scratch[:4], p.TimeLockLength)
+	if _, err := w.Write(scratch[:4]); err != nil {
+		return err
+	}
+
+	if _, err := w.Write(p.PaymentHash[:]); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func deserializeLegacyOutgoingPayment(r io.Reader) (*legacyOutgoingPayment, error) {
+	var scratch [8]byte
+
+	p := &legacyOutgoingPayment{}
+
+	inv, err := deserializeLegacyInvoice(r)
+	if err != nil {
+		return nil, err
+	}
+	p.Invoice = *inv
+
+	if _, err := r.Read(scratch[:]); err != nil {
+		return nil, err
+	}
+	p.Fee = lnwire.MilliAtom(byteOrder.Uint64(scratch[:]))
+
+	if _, err = r.Read(scratch[:4]); err != nil {
+		return nil, err
+	}
+	pathLen := byteOrder.Uint32(scratch[:4])
+
+	path := make([][33]byte, pathLen)
+	for i := uint32(0); i < pathLen; i++ {
+		if _, err := r.Read(path[i][:]); err != nil {
+			return nil, err
+		}
+	}
+	p.Path = path
+
+	if _, err = r.Read(scratch[:4]); err != nil {
+		return nil, err
+	}
+	p.TimeLockLength = byteOrder.Uint32(scratch[:4])
+
+	if _, err := r.Read(p.PaymentHash[:]); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// migrateInvoiceIndexes assigns an add index to every existing invoice, and a
+// settle index to every existing settled invoice, populating the add and
+// settle index buckets accordingly. As the original creation and settlement
+// order isn't known, indexes are assigned in order of invoice number. Each
+// invoice is then re-written using the current serialization format which
+// includes both indexes.
+func migrateInvoiceIndexes(tx *bolt.Tx) error {
+	invoices := tx.Bucket(invoiceBucket)
+	if invoices == nil {
+		return nil
+	}
+
+	// First, we'll read out all existing invoices along with their keys.
+	// Bolt's cursor iterates in key order, and as the keys are big-endian
+	// invoice numbers, the invoices will be visited in the order they
+	// were created.
+	var (
+		invoiceKeys [][]byte
+		legacy      []*Invoice
+	)
+	err := invoices.ForEach(func(k, v []byte) error {
+		// If the value is nil, then this is a sub-bucket, so we'll
+		// skip it.
+		if v == nil {
+			return nil
+		}
+
+		invoice, err := deserializeLegacyInvoice(bytes.NewReader(v))
+		if err != nil {
+			return err
+		}
+
+		invoiceKeys = append(invoiceKeys, append([]byte(nil), k...))
+		legacy = append(legacy, invoice)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Migrating %v invoices to include add and settle indexes",
+		len(legacy))
+
+	addIndex, err := invoices.CreateBucketIfNotExists(addIndexBucket)
+	if err != nil {
+		return err
+	}
+	settleIndex, err := invoices.CreateBucketIfNotExists(settleIndexBucket)
+	if err != nil {
+		return err
+	}
+
+	for i, invoice := range legacy {
+		invoiceKey := invoiceKeys[i]
+
+		invoice.AddIndex, err = addIndex.NextSequence()
+		if err != nil {
+			return err
+		}
+		var addKey [8]byte
+		byteOrder.PutUint64(addKey[:], invoice.AddIndex)
+		if err := addIndex.Put(addKey[:], invoiceKey); err != nil {
+			return err
+		}
+
+		if invoice.Terms.State == ContractSettled {
+			invoice.SettleIndex, err = settleIndex.NextSequence()
+			if err != nil {
+				return err
+			}
+			var settleKey [8]byte
+			byteOrder.PutUint64(settleKey[:], invoice.SettleIndex)
+			err := settleIndex.Put(settleKey[:], invoiceKey)
+			if err != nil {
+				return err
+			}
+		}
+
+		var b bytes.Buffer
+		if err := serializeInvoice(&b, invoice); err != nil {
+			return err
+		}
+		if err := invoices.Put(invoiceKey, b.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	log.Infof("Migration of invoice indexes complete!")
+
+	return nil
+}
+
+// serializeLegacyInvoice serializes an invoice using the format in use prior
+// to the addition of the add and settle indexes.
+func serializeLegacyInvoice(w io.Writer, i *Invoice) error {
+	if err := wire.WriteVarBytes(w, 0, i.Memo[:]); err != nil {
+		return err
+	}
+	if err := wire.WriteVarBytes(w, 0, i.Receipt[:]); err != nil {
+		return err
+	}
+
+	birthBytes, err := i.CreationDate.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	if err := wire.WriteVarBytes(w, 0, birthBytes); err != nil {
+		return err
+	}
+
+	if _, err := w.Write(i.Terms.PaymentPreimage[:]); err != nil {
+		return err
+	}
+
+	var scratch [8]byte
+	byteOrder.PutUint64(scratch[:], uint64(i.Terms.Value))
+	if _, err := w.Write(scratch[:]); err != nil {
+		return err
+	}
+
+	var settleByte [1]byte
+	if i.Terms.State == ContractSettled {
+		settleByte[0] = 1
+	}
+	if _, err := w.Write(settleByte[:]); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// deserializeLegacyInvoice deserializes an invoice written using the format
+// in use prior to the addition of the add and settle indexes.
+func deserializeLegacyInvoice(r io.Reader) (*Invoice, error) {
+	var err error
+	invoice := &Invoice{}
+
+	invoice.Memo, err = wire.ReadVarBytes(r, 0, MaxMemoSize, "")
+	if err != nil {
+		return nil, err
+	}
+	invoice.Receipt, err = wire.ReadVarBytes(r, 0, MaxReceiptSize, "")
+	if err != nil {
+		return nil, err
+	}
+
+	birthBytes, err := wire.ReadVarBytes(r, 0, 300, "birth")
+	if err != nil {
+		return nil, err
+	}
+	if err := invoice.CreationDate.UnmarshalBinary(birthBytes); err != nil {
+		return nil, err
+	}
+
+	if _, err := io.ReadFull(r, invoice.Terms.PaymentPreimage[:]); err != nil {
+		return nil, err
+	}
+	var scratch [8]byte
+	if _, err := io.ReadFull(r, scratch[:]); err != nil {
+		return nil, err
+	}
+	invoice.Terms.Value = lnwire.MilliAtom(byteOrder.Uint64(scratch[:]))
+
+	var settleByte [1]byte
+	if _, err := io.ReadFull(r, settleByte[:]); err != nil {
+		return nil, err
+	}
+	if settleByte[0] == 1 {
+		invoice.Terms.State = ContractSettled
+	}
+
+	// Legacy invoices always have a known preimage, from which their
+	// payment hash is derived.
+	invoice.Terms.PaymentHash = sha256.Sum256(
+		invoice.Terms.PaymentPreimage[:],
+	)
+
+	return invoice, nil
+}
+
+// revocationLogChunkSize is the maximum number of revocation log entries
+// converted within each transaction of the revocation log migration.
+var revocationLogChunkSize = 2000
+
+// migrateRevocationLog converts every entry within the revocation logs of all
+// open channels from the legacy format, which stored the full ChannelDelta of
+// each state, into the compact format which only retains the data required to
+// construct justice transactions. As the revocation logs of busy channels may
+// be very large, the migration is applied in chunks.
+//
+// The checkpoint of the migration is the node public key of the last entry
+// migrated, followed by the key of the entry within its log.
+var migrateRevocationLog = &chunkedMigration{
+	countItems:   countRevocationLogEntries,
+	migrateChunk: migrateRevocationLogChunk,
+}
+
+// countRevocationLogEntries returns the total number of entries within the
+// revocation logs of all open channels.
+func countRevocationLogEntries(tx *bolt.Tx) (uint64, error) {
+	openChanBucket := tx.Bucket(openChannelBucket)
+	if openChanBucket == nil {
+		return 0, nil
+	}
+
+	var numEntries uint64
+	err := openChanBucket.ForEach(func(nodePub, v []byte) error {
+		// Only the nested buckets of each node are of interest, so
+		// we'll skip any other keys.
+		if v != nil {
+			return nil
+		}
+
+		nodeChanBucket := openChanBucket.Bucket(nodePub)
+		logBucket := nodeChanBucket.Bucket(channelLogBucket)
+		if logBucket == nil {
+			return nil
+		}
+
+		numEntries += uint64(logBucket.Stats().KeyN)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return numEntries, nil
+}
+
+// migrateRevocationLogChunk converts the next chunk of revocation log entries
+// following the passed checkpoint into the compact format.
+func migrateRevocationLogChunk(tx *bolt.Tx, checkpoint []byte) ([]byte,
+	uint64, error) {
+
+	openChanBucket := tx.Bucket(openChannelBucket)
+	if openChanBucket == nil {
+		return nil, 0, nil
+	}
+
+	var startNode, startKey []byte
+	if checkpoint != nil {
+		startNode = checkpoint[:33]
+		startKey = checkpoint[33:]
+	}
+
+	// First, we'll read out the next chunk of entries following the
+	// checkpoint. The entries are decoded before any are rewritten, as
+	// modifying a bucket invalidates any cursors over it.
+	type logEntry struct {
+		logBucket *bolt.Bucket
+		nodePub   []byte
+		key       []byte
+		delta     *ChannelDelta
+	}
+	var entries []logEntry
+
+	nodeCursor := openChanBucket.Cursor()
+	nodePub, v := nodeCursor.First()
+	if startNode != nil {
+		nodePub, v = nodeCursor.Seek(startNode)
+	}
+	for ; nodePub != nil && len(entries) < revocationLogChunkSize; nodePub, v = nodeCursor.Next() {
+		// Only the nested buckets of each node are of interest, so
+		// we'll skip any other keys.
+		if v != nil {
+			continue
+		}
+
+		nodeChanBucket := openChanBucket.Bucket(nodePub)
+		logBucket := nodeChanBucket.Bucket(channelLogBucket)
+		if logBucket == nil {
+			continue
+		}
+
+		logCursor := logBucket.Cursor()
+		k, logBytes := logCursor.First()
+		if bytes.Equal(nodePub, startNode) {
+			k, logBytes = logCursor.Seek(startKey)
+			if bytes.Equal(k, startKey) {
+				k, logBytes = logCursor.Next()
+			}
+		}
+		for ; k != nil && len(entries) < revocationLogChunkSize; k, logBytes = logCursor.Next() {
+			delta, err := deserializeLegacyChannelDelta(
+				bytes.NewReader(logBytes),
+			)
+			if err != nil {
+				return nil, 0, err
+			}
+
+			entries = append(entries, logEntry{
+				logBucket: logBucket,
+				nodePub:   append([]byte(nil), nodePub...),
+				key:       append([]byte(nil), k...),
+				delta:     delta,
+			})
+		}
+	}
+
+	// With the chunk read, we'll now rewrite each entry using the compact
+	// format.
+	for _, entry := range entries {
+		var b bytes.Buffer
+		if err := serializeRevocationLogEntry(&b, entry.delta); err != nil {
+			return nil, 0, err
+		}
+		if err := entry.logBucket.Put(entry.key, b.Bytes()); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	numMigrated := uint64(len(entries))
+
+	// If the chunk wasn't filled, then there are no entries left to be
+	// migrated.
+	if len(entries) < revocationLogChunkSize {
+		log.Infof("Migration of revocation logs complete!")
+		return nil, numMigrated, nil
+	}
+
+	last := entries[len(entries)-1]
+	return append(last.nodePub, last.key...), numMigrated, nil
+}
+
+// serializeLegacyChannelDelta serializes a revocation log entry using the
+// format in use prior to the compact revocation log, which stored the full
+// ChannelDelta of each state. It's only retained in order to test the
+// migration of existing logs.
+func serializeLegacyChannelDelta(w io.Writer, delta *ChannelDelta) error {
+	var scratch [8]byte
+	byteOrder.PutUint64(scratch[:], uint64(delta.LocalBalance))
+	if _, err := w.Write(scratch[:]); err != nil {
+		return err
+	}
+	byteOrder.PutUint64(scratch[:], uint64(delta.RemoteBalance))
+	if _, err := w.Write(scratch[:]); err != nil {
+		return err
+	}
+
+	byteOrder.PutUint64(scratch[:], delta.UpdateNum)
+	if _, err := w.Write(scratch[:]); err != nil {
+		return err
+	}
+
+	numHtlcs := uint64(len(delta.Htlcs))
+	if err := wire.WriteVarInt(w, 0, numHtlcs); err != nil {
+		return err
+	}
+	for _, htlc := range delta.Htlcs {
+		if err := serializeHTLC(w, htlc); err != nil {
+			return err
+		}
+	}
+
+	byteOrder.PutUint64(scratch[:], uint64(delta.CommitFee))
+	if _, err := w.Write(scratch[:]); err != nil {
+		return err
+	}
+
+	byteOrder.PutUint64(scratch[:], uint64(delta.FeePerKw))
+	if _, err := w.Write(scratch[:]); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// deserializeLegacyChannelDelta deserializes a revocation log entry stored
+// using the format in use prior to the compact revocation log.
+func deserializeLegacyChannelDelta(r io.Reader) (*ChannelDelta, error) {
+	var (
+		err     error
+		scratch [8]byte
+	)
+
+	delta := &ChannelDelta{}
+
+	if _, err := r.Read(scratch[:]); err != nil {
+		return nil, err
+	}
+	delta.LocalBalance = lnwire.MilliAtom(byteOrder.Uint64(scratch[:]))
+	if _, err := r.Read(scratch[:]); err != nil {
+		return nil, err
+	}
+	delta.RemoteBalance = lnwire.MilliAtom(byteOrder.Uint64(scratch[:]))
+
+	if _, err := r.Read(scratch[:]); err != nil {
+		return nil, err
+	}
+	delta.UpdateNum = byteOrder.Uint64(scratch[:])
+
+	numHtlcs, err := wire.ReadVarInt(r, 0)
+	if err != nil {
+		return nil, err
+	}
+	delta.Htlcs = make([]*HTLC, numHtlcs)
+	for i := uint64(0); i < numHtlcs; i++ {
+		htlc, err := deserializeHTLC(r)
+		if err != nil {
+			return nil, err
+		}
+
+		delta.Htlcs[i] = htlc
+	}
+	if _, err := r.Read(scratch[:]); err != nil {
+		return nil, err
+	}
+	delta.CommitFee = btcutil.Amount(byteOrder.Uint64(scratch[:]))
+
+	if _, err := r.Read(scratch[:]); err != nil {
+		return nil, err
+	}
+	delta.FeePerKw = btcutil.Amount(byteOrder.Uint64(scratch[:]))
+
+	return delta, nil
+}
+
+// migratePaymentsIndex builds the payments index for all existing payments,
+// mapping the sequence number of each payment to its payment hash.
+func migratePaymentsIndex(tx *bolt.Tx) error {
+	payments := tx.Bucket(paymentsRootBucket)
+	if payments == nil {
+		return nil
+	}
+
+	paymentsIndex, err := tx.CreateBucketIfNotExists(paymentsIndexBucket)
+	if err != nil {
+		return err
+	}
+
+	var numPayments int
+	err = payments.ForEach(func(k, v []byte) error {
+		bucket := payments.Bucket(k)
+		if bucket == nil {
+			return fmt.Errorf("non bucket element in payments " +
+				"bucket")
+		}
+
+		seqBytes := bucket.Get(paymentSequenceKey)
+		if seqBytes == nil {
+			return fmt.Errorf("sequence number not found for "+
+				"payment %x", k)
+		}
+
+		numPayments++
+		if err := paymentsIndex.Put(seqBytes, k); err != nil {
+			return err
+		}
+
+		// Duplicate payments are keyed by their sequence number, so
+		// they're indexed under it along with the shared payment hash.
+		duplicates := bucket.Bucket(paymentDuplicateBucket)
+		if duplicates == nil {
+			return nil
+		}
+		return duplicates.ForEach(func(seqBytes, _ []byte) error {
+			numPayments++
+			return paymentsIndex.Put(seqBytes, k)
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Migration of payments index complete, %v payments "+
+		"indexed", numPayments)
+
+	return nil
+}
+
+// migrateGraphUpdateIndexes populates the node and edge update indexes of the
+// channel graph, which allow the nodes and channels updated within a time
+// horizon to be queried, from the update times of the existing nodes and edge
+// policies.
+func migrateGraphUpdateIndexes(tx *bolt.Tx) error {
+	var numNodes, numEdges int
+
+	nodes := tx.Bucket(nodeBucket)
+	if nodes != nil {
+		nodeUpdateIndex, err := nodes.CreateBucketIfNotExists(
+			nodeUpdateIndexBucket,
+		)
+		if err != nil {
+			return err
+		}
+
+		err = nodes.ForEach(func(pubKey, nodeBytes []byte) error {
+			// Skip the source key and any nested buckets, neither
+			// of which are nodes.
+			if len(pubKey) != 33 || nodeBytes == nil {
+				return nil
+			}
+
+			var indexKey [8 + 33]byte
+			copy(indexKey[:8], nodeBytes[:8])
+			copy(indexKey[8:], pubKey)
+
+			numNodes++
+			return nodeUpdateIndex.Put(indexKey[:], nil)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	edges := tx.Bucket(edgeBucket)
+	if edges != nil {
+		edgeUpdateIndex, err := edges.CreateBucketIfNotExists(
+			edgeUpdateIndexBucket,
+		)
+		if err != nil {
+			return err
+		}
+
+		// Directed edge policies are keyed by the advertising node's
+		// public key followed by the channel ID.
+		err = edges.ForEach(func(edgeKey, edgeBytes []byte) error {
+			if len(edgeKey) != 33+8 || edgeBytes == nil {
+				return nil
+			}
+
+			updateUnix, err := edgePolicyUpdateTime(edgeBytes)
+			if err != nil {
+				return err
+			}
+
+			var indexKey [8 + 8]byte
+			byteOrder.PutUint64(indexKey[:8], updateUnix)
+			copy(indexKey[8:], edgeKey[33:])
+
+			numEdges++
+			return edgeUpdateIndex.Put(indexKey[:], nil)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Infof("Migration of graph update indexes complete, %v nodes and "+
+		"%v edge policies indexed", numNodes, numEdges)
+
+	return nil
+}
+
+// migrateChannelIndex populates the outpoint index from the existing open
+// channels, which are found within the channel ID bucket of each node's bucket
+// within the open channel bucket.
+func migrateChannelIndex(tx *bolt.Tx) error {
+	openChanBucket := tx.Bucket(openChannelBucket)
+	if openChanBucket == nil {
+		return nil
+	}
+
+	outpointIndex, err := tx.CreateBucketIfNotExists(outpointIndexBucket)
+	if err != nil {
+		return err
+	}
+
+	var numChannels int
+	indexChannel := func(nodePub, outpointBytes []byte) error {
+		if err := outpointIndex.Put(outpointBytes, nodePub); err != nil {
+			return err
+		}
+
+		numChannels++
+		return nil
+	}
+
+	err = openChanBucket.ForEach(func(nodePub, v []byte) error {
+		// Only the nested node buckets contain channels, the
+		// remaining keys hold the prefixed channel fields.
+		if v != nil {
+			return nil
+		}
+		chanIndexBucket := openChanBucket.Bucket(nodePub).Bucket(
+			chanIDBucket,
+		)
+		if chanIndexBucket == nil {
+			return nil
+		}
+
+		return chanIndexBucket.ForEach(func(k, _ []byte) error {
+			return indexChannel(nodePub, k)
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Migration of channel outpoint index complete, %v channels "+
+		"indexed", numChannels)
+
+	return nil
+}
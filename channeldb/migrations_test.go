@@ -0,0 +1,525 @@
+package channeldb
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/davecgh/go-spew/spew"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/wire"
+)
+
+func makeFakeLegacyPayment(preimage [32]byte) *legacyOutgoingPayment {
+	fakeInvoice := &Invoice{
+		// Use single second precision to avoid false positive test
+		// failures due to the monotonic time component.
+		CreationDate: time.Unix(time.Now().Unix(), 0),
+		Memo:         []byte("fake memo"),
+		Receipt:      []byte("fake receipt"),
+	}
+
+	copy(fakeInvoice.Terms.PaymentPreimage[:], preimage[:])
+	fakeInvoice.Terms.Value = lnwire.NewMSatFromSatoshis(10000)
+
+	fakePath := make([][33]byte, 3)
+	for i := 0; i < 3; i++ {
+		copy(fakePath[i][:], bytes.Repeat([]byte{byte(i)}, 33))
+	}
+
+	return &legacyOutgoingPayment{
+		Invoice:        *fakeInvoice,
+		Fee:            101,
+		Path:           fakePath,
+		TimeLockLength: 1000,
+		PaymentHash:    sha256.Sum256(preimage[:]),
+	}
+}
+
+// TestMigrateOutgoingPayments checks that payments stored within the legacy
+// payments bucket are properly migrated into the new payments bucket.
+func TestMigrateOutgoingPayments(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+
+	var preimage2 [32]byte
+	copy(preimage2[:], bytes.Repeat([]byte{2}, 32))
+	duplicate := makeFakeLegacyPayment(rev)
+	duplicate.Fee = 202
+	legacyPayments := []*legacyOutgoingPayment{
+		makeFakeLegacyPayment(rev),
+		makeFakeLegacyPayment(preimage2),
+		duplicate,
+	}
+
+	// First, we'll write the set of payments into the legacy bucket
+	// using the legacy format.
+	err = db.Update(func(tx *bolt.Tx) error {
+		payments, err := tx.CreateBucketIfNotExists(legacyPaymentBucket)
+		if err != nil {
+			return err
+		}
+
+		for i, p := range legacyPayments {
+			var b bytes.Buffer
+			if err := serializeLegacyOutgoingPayment(&b, p); err != nil {
+				return err
+			}
+
+			var k [8]byte
+			binary.BigEndian.PutUint64(k[:], uint64(i))
+			if err := payments.Put(k[:], b.Bytes()); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unable to write legacy payments: %v", err)
+	}
+
+	// With the legacy payments written, we'll now apply the migration.
+	if err := db.Update(migrateOutgoingPayments); err != nil {
+		t.Fatalf("unable to migrate payments: %v", err)
+	}
+
+	// The legacy bucket should no longer exist.
+	err = db.View(func(tx *bolt.Tx) error {
+		if tx.Bucket(legacyPaymentBucket) != nil {
+			t.Fatalf("legacy payments bucket still exists")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unable to read db: %v", err)
+	}
+
+	payments, err := db.FetchPayments()
+	if err != nil {
+		t.Fatalf("unable to fetch payments: %v", err)
+	}
+	if len(payments) != len(legacyPayments) {
+		t.Fatalf("expected %v payments, got %v", len(legacyPayments),
+			len(payments))
+	}
+
+	for i, p := range payments {
+		legacy := legacyPayments[i]
+
+		if p.Status != StatusSucceeded {
+			t.Fatalf("expected status %v, got %v", StatusSucceeded,
+				p.Status)
+		}
+
+		expectedInfo := &PaymentCreationInfo{
+			PaymentHash:  legacy.PaymentHash,
+			Value:        legacy.Terms.Value,
+			CreationDate: legacy.CreationDate,
+		}
+		if !reflect.DeepEqual(p.Info, expectedInfo) {
+			t.Fatalf("wrong creation info: expected %v, got %v",
+				spew.Sdump(expectedInfo), spew.Sdump(p.Info))
+		}
+
+		if len(p.HTLCs) != 1 {
+			t.Fatalf("expected 1 htlc attempt, got %v",
+				len(p.HTLCs))
+		}
+		htlc := p.HTLCs[0]
+		if htlc.Settle.Preimage != legacy.Terms.PaymentPreimage {
+			t.Fatalf("wrong preimage: expected %x, got %x",
+				legacy.Terms.PaymentPreimage,
+				htlc.Settle.Preimage)
+		}
+		if htlc.Route.TotalFees != legacy.Fee ||
+			htlc.Route.TotalTimeLock != legacy.TimeLockLength {
+
+			t.Fatalf("wrong route: %v", spew.Sdump(htlc.Route))
+		}
+		for j, hop := range htlc.Route.Hops {
+			if hop.PubKeyBytes != legacy.Path[j] {
+				t.Fatalf("wrong hop #%v: expected %x, got %x",
+					j, legacy.Path[j], hop.PubKeyBytes)
+			}
+		}
+	}
+
+	// Only the first payment to the duplicated hash should be returned
+	// when fetching it by its payment hash.
+	payment, err := db.FetchPayment(legacyPayments[0].PaymentHash)
+	if err != nil {
+		t.Fatalf("unable to fetch payment: %v", err)
+	}
+	if payment.SequenceNum != payments[0].SequenceNum {
+		t.Fatalf("expected payment %v, got %v",
+			payments[0].SequenceNum, payment.SequenceNum)
+	}
+
+	// Once indexed, the duplicate payment should also be found by a
+	// payments query.
+	if err := db.Update(migratePaymentsIndex); err != nil {
+		t.Fatalf("unable to migrate payments index: %v", err)
+	}
+	resp, err := db.QueryPayments(PaymentsQuery{})
+	if err != nil {
+		t.Fatalf("unable to query payments: %v", err)
+	}
+	assertPaymentHashes(
+		t, resp, legacyPayments[0].PaymentHash,
+		legacyPayments[1].PaymentHash, duplicate.PaymentHash,
+	)
+	if resp.Payments[2].HTLCs[0].Route.TotalFees != duplicate.Fee {
+		t.Fatalf("duplicate payment not returned by query")
+	}
+}
+
+// TestMigrateInvoiceIndexes checks that invoices stored using the legacy
+// format are assigned add and settle indexes by the migration.
+func TestMigrateInvoiceIndexes(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+
+	// We'll create a set of invoices, settling every other one.
+	const numInvoices = 5
+	legacyInvoices := make([]*Invoice, numInvoices)
+	for i := 0; i < numInvoices; i++ {
+		invoice, err := randInvoice(lnwire.MilliAtom(i + 1))
+		if err != nil {
+			t.Fatalf("unable to create invoice: %v", err)
+		}
+		if i%2 == 0 {
+			invoice.Terms.State = ContractSettled
+		}
+
+		legacyInvoices[i] = invoice
+	}
+
+	// Next, we'll write the invoices to disk using the legacy format.
+	err = db.Update(func(tx *bolt.Tx) error {
+		invoices, err := tx.CreateBucketIfNotExists(invoiceBucket)
+		if err != nil {
+			return err
+		}
+
+		for i, invoice := range legacyInvoices {
+			var b bytes.Buffer
+			if err := serializeLegacyInvoice(&b, invoice); err != nil {
+				return err
+			}
+
+			var k [4]byte
+			binary.BigEndian.PutUint32(k[:], uint32(i))
+			if err := invoices.Put(k[:], b.Bytes()); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unable to write legacy invoices: %v", err)
+	}
+
+	if err := db.Update(migrateInvoiceIndexes); err != nil {
+		t.Fatalf("unable to migrate invoices: %v", err)
+	}
+
+	// The invoices should have been assigned add indexes in order, and
+	// settled invoices should have been assigned settle indexes in order.
+	var settleIndex uint64
+	for i, invoice := range legacyInvoices {
+		invoice.AddIndex = uint64(i + 1)
+		if invoice.Terms.State == ContractSettled {
+			settleIndex++
+			invoice.SettleIndex = settleIndex
+		}
+	}
+
+	added, err := db.InvoicesAddedSince(1)
+	if err != nil {
+		t.Fatalf("unable to query add index: %v", err)
+	}
+	if !reflect.DeepEqual(added, legacyInvoices[1:]) {
+		t.Fatalf("wrong invoices: expected %v, got %v",
+			spew.Sdump(legacyInvoices[1:]), spew.Sdump(added))
+	}
+
+	settled, err := db.InvoicesSettledSince(1)
+	if err != nil {
+		t.Fatalf("unable to query settle index: %v", err)
+	}
+	expected := []*Invoice{legacyInvoices[2], legacyInvoices[4]}
+	if !reflect.DeepEqual(settled, expected) {
+		t.Fatalf("wrong invoices: expected %v, got %v",
+			spew.Sdump(expected), spew.Sdump(settled))
+	}
+}
+
+// TestMigrateRevocationLog checks that the revocation log entries of all
+// channels are converted into the compact format, retaining all the data
+// required to construct justice transactions, across several chunks.
+func TestMigrateRevocationLog(t *testing.T) {
+	cdb, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+
+	// Use a small chunk size so the migration spans several chunks, and
+	// crosses from the log of one node to the next mid-chunk.
+	defer func(size int) {
+		revocationLogChunkSize = size
+	}(revocationLogChunkSize)
+	revocationLogChunkSize = 4
+
+	chanPoint := wire.OutPoint{Index: 1}
+	nodePubs := [][]byte{
+		bytes.Repeat([]byte{2}, 33),
+		bytes.Repeat([]byte{3}, 33),
+	}
+
+	// Each node's log contains several states, the first of which carries
+	// an HTLC trimmed as dust that has no output on the commitment.
+	const numStates = 3
+	makeDelta := func(updateNum uint64) *ChannelDelta {
+		delta := &ChannelDelta{
+			LocalBalance:  lnwire.MilliAtom(1000 * updateNum),
+			RemoteBalance: lnwire.MilliAtom(2000 * updateNum),
+			CommitFee:     500,
+			FeePerKw:      100,
+			UpdateNum:     updateNum,
+			Htlcs: []*HTLC{
+				{
+					Signature:     bytes.Repeat([]byte{1}, 71),
+					RHash:         sha256.Sum256([]byte{byte(updateNum)}),
+					Amt:           lnwire.MilliAtom(updateNum),
+					RefundTimeout: 500000,
+					OutputIndex:   2,
+					Incoming:      true,
+				},
+			},
+		}
+		if updateNum == 0 {
+			delta.Htlcs = append(delta.Htlcs, &HTLC{
+				Amt:         1,
+				OutputIndex: -1,
+			})
+		}
+		return delta
+	}
+
+	err = cdb.Update(func(tx *bolt.Tx) error {
+		openChanBucket, err := tx.CreateBucketIfNotExists(
+			openChannelBucket,
+		)
+		if err != nil {
+			return err
+		}
+
+		// Keys which aren't node buckets should be skipped.
+		if err := openChanBucket.Put([]byte("a"), []byte("b")); err != nil {
+			return err
+		}
+
+		for _, nodePub := range nodePubs {
+			nodeChanBucket, err := openChanBucket.CreateBucket(nodePub)
+			if err != nil {
+				return err
+			}
+			logBucket, err := nodeChanBucket.CreateBucket(
+				channelLogBucket,
+			)
+			if err != nil {
+				return err
+			}
+
+			for i := uint64(0); i < numStates; i++ {
+				var b bytes.Buffer
+				err := serializeLegacyChannelDelta(&b, makeDelta(i))
+				if err != nil {
+					return err
+				}
+				k := makeLogKey(&chanPoint, i)
+				if err := logBucket.Put(k[:], b.Bytes()); err != nil {
+					return err
+				}
+			}
+		}
+
+		return putMeta(&Meta{DbVersionNumber: 0}, tx)
+	})
+	if err != nil {
+		t.Fatalf("unable to write legacy revocation logs: %v", err)
+	}
+
+	versions := []version{
+		{number: 0},
+		{number: 1, chunkedMigration: migrateRevocationLog},
+	}
+	if err := cdb.syncVersions(versions); err != nil {
+		t.Fatalf("unable to migrate revocation logs: %v", err)
+	}
+
+	// Each entry should now only retain the balances and the HTLCs which
+	// have an output on the commitment, without their signatures.
+	err = cdb.View(func(tx *bolt.Tx) error {
+		openChanBucket := tx.Bucket(openChannelBucket)
+		for _, nodePub := range nodePubs {
+			logBucket := openChanBucket.Bucket(nodePub).Bucket(
+				channelLogBucket,
+			)
+
+			for i := uint64(0); i < numStates; i++ {
+				delta, err := fetchChannelLogEntry(
+					logBucket, &chanPoint, i,
+				)
+				if err != nil {
+					return err
+				}
+
+				expected := makeDelta(i)
+				expected.CommitFee = 0
+				expected.FeePerKw = 0
+				expected.Htlcs = expected.Htlcs[:1]
+				expected.Htlcs[0].Signature = nil
+				if !reflect.DeepEqual(delta, expected) {
+					t.Fatalf("wrong delta: expected %v, "+
+						"got %v", spew.Sdump(expected),
+						spew.Sdump(delta))
+				}
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unable to fetch revocation logs: %v", err)
+	}
+}
+
+// TestMigratePaymentsIndex asserts that the payments index is built for all
+// payments which existed prior to its introduction.
+func TestMigratePaymentsIndex(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+
+	hashes := initTestPayments(t, db, 5)
+
+	// Remove the index to simulate a database created before it was
+	// introduced, then run the migration to rebuild it.
+	err = db.Update(func(tx *bolt.Tx) error {
+		return tx.DeleteBucket(paymentsIndexBucket)
+	})
+	if err != nil {
+		t.Fatalf("unable to delete payments index: %v", err)
+	}
+	if err := db.Update(migratePaymentsIndex); err != nil {
+		t.Fatalf("unable to migrate payments index: %v", err)
+	}
+
+	resp, err := db.QueryPayments(PaymentsQuery{IncludeIncomplete: true})
+	if err != nil {
+		t.Fatalf("unable to query payments: %v", err)
+	}
+	assertPaymentHashes(t, resp, hashes...)
+}
+
+// TestMigrateGraphUpdateIndexes checks that the graph update indexes are
+// rebuilt from the existing nodes and edge policies.
+func TestMigrateGraphUpdateIndexes(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+
+	graph := db.ChannelGraph()
+
+	node1, err := createTestVertex(db)
+	if err != nil {
+		t.Fatalf("unable to create test node: %v", err)
+	}
+	node1.LastUpdate = time.Unix(1000, 0)
+	if err := graph.SetSourceNode(node1); err != nil {
+		t.Fatalf("unable to set source node: %v", err)
+	}
+	node2, err := createTestVertex(db)
+	if err != nil {
+		t.Fatalf("unable to create test node: %v", err)
+	}
+	node2.LastUpdate = time.Unix(2000, 0)
+	if err := graph.AddLightningNode(node2); err != nil {
+		t.Fatalf("unable to add node: %v", err)
+	}
+	firstNode, secondNode := node1, node2
+	if bytes.Compare(node2.PubKey.SerializeCompressed(),
+		node1.PubKey.SerializeCompressed()) == -1 {
+
+		firstNode, secondNode = node2, node1
+	}
+
+	edgeInfo := &ChannelEdgeInfo{
+		ChannelID:    1,
+		ChainHash:    key,
+		NodeKey1:     firstNode.PubKey,
+		NodeKey2:     secondNode.PubKey,
+		BitcoinKey1:  firstNode.PubKey,
+		BitcoinKey2:  secondNode.PubKey,
+		ChannelPoint: wire.OutPoint{Hash: rev},
+		Capacity:     1000,
+	}
+	if err := graph.AddChannelEdge(edgeInfo); err != nil {
+		t.Fatalf("unable to create channel edge: %v", err)
+	}
+	policy := &ChannelEdgePolicy{
+		Signature:  testSig,
+		ChannelID:  1,
+		LastUpdate: time.Unix(3000, 0),
+		Node:       secondNode,
+		db:         db,
+	}
+	if err := graph.UpdateEdgePolicy(policy); err != nil {
+		t.Fatalf("unable to update edge: %v", err)
+	}
+
+	// Remove the indexes to simulate a database created before they were
+	// introduced, then run the migration to rebuild them.
+	err = db.Update(func(tx *bolt.Tx) error {
+		err := tx.Bucket(nodeBucket).DeleteBucket(nodeUpdateIndexBucket)
+		if err != nil {
+			return err
+		}
+		return tx.Bucket(edgeBucket).DeleteBucket(edgeUpdateIndexBucket)
+	})
+	if err != nil {
+		t.Fatalf("unable to delete update indexes: %v", err)
+	}
+	if err := db.Update(migrateGraphUpdateIndexes); err != nil {
+		t.Fatalf("unable to migrate graph update indexes: %v", err)
+	}
+
+	assertHorizonNodes(t, graph, 0, 10000, node1, node2)
+	assertHorizonChans(t, graph, 3000, 3000, 1)
+}
@@ -0,0 +1,165 @@
+package channeldb
+
+import (
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+var (
+	// missionControlBucket is a top-level bucket which houses the history
+	// of past payment attempts as recorded by the router's mission
+	// control. Each entry either pertains to a single node, in which case
+	// the second public key of the key is zeroed out, or to a directed
+	// pair of nodes that are connected by one or more channels. The value
+	// holds the times of the last failed and last successful attempts
+	// involving the node or pair.
+	//
+	// maps: fromPubKey || toPubKey ->
+	//   lastFailUnix || lastFailAmt || lastSuccessUnix || lastSuccessAmt
+	missionControlBucket = []byte("mission-control")
+)
+
+// MissionControlEntry records the outcome of past payment attempts involving
+// either a single node, or a directed pair of nodes.
+type MissionControlEntry struct {
+	// From is the node that the entry pertains to, or the node at the
+	// start of the directed pair.
+	From [33]byte
+
+	// To is the node at the end of the directed pair. If the entry
+	// pertains to a single node, then this is the zero value.
+	To [33]byte
+
+	// LastFail is the time of the last payment attempt that failed due to
+	// the node or pair.
+	LastFail time.Time
+
+	// LastFailAmt is the amount of the last payment attempt that failed
+	// due to the pair. It's always zero for node entries.
+	LastFailAmt lnwire.MilliAtom
+
+	// LastSuccess is the time of the last payment attempt that was
+	// successfully carried by the node or pair.
+	LastSuccess time.Time
+
+	// LastSuccessAmt is the amount of the last payment attempt that was
+	// successfully carried by the pair. It's always zero for node
+	// entries.
+	LastSuccessAmt lnwire.MilliAtom
+}
+
+// IsNodeEntry returns true if the entry pertains to a single node rather than
+// to a directed pair of nodes.
+func (m *MissionControlEntry) IsNodeEntry() bool {
+	return m.To == [33]byte{}
+}
+
+// PutMissionControlEntries writes the passed entries to the mission control
+// history, overwriting any existing entries for the same nodes or pairs.
+func (c *ChannelGraph) PutMissionControlEntries(
+	entries []*MissionControlEntry) error {
+
+	return c.db.Update(func(tx *bolt.Tx) error {
+		history, err := tx.CreateBucketIfNotExists(missionControlBucket)
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			var key [66]byte
+			copy(key[:33], entry.From[:])
+			copy(key[33:], entry.To[:])
+
+			var value [32]byte
+			byteOrder.PutUint64(value[:8], timeToUnix(entry.LastFail))
+			byteOrder.PutUint64(value[8:16], uint64(entry.LastFailAmt))
+			byteOrder.PutUint64(
+				value[16:24], timeToUnix(entry.LastSuccess),
+			)
+			byteOrder.PutUint64(
+				value[24:], uint64(entry.LastSuccessAmt),
+			)
+
+			if err := history.Put(key[:], value[:]); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// FetchMissionControlEntries returns every entry within the mission control
+// history. If the history is empty, then a nil slice is returned.
+func (c *ChannelGraph) FetchMissionControlEntries() ([]*MissionControlEntry,
+	error) {
+
+	var entries []*MissionControlEntry
+	err := c.db.View(func(tx *bolt.Tx) error {
+		history := tx.Bucket(missionControlBucket)
+		if history == nil {
+			return nil
+		}
+
+		return history.ForEach(func(k, v []byte) error {
+			if len(k) != 66 || len(v) != 32 {
+				return nil
+			}
+
+			entry := &MissionControlEntry{
+				LastFail: unixToTime(byteOrder.Uint64(v[:8])),
+				LastFailAmt: lnwire.MilliAtom(
+					byteOrder.Uint64(v[8:16]),
+				),
+				LastSuccess: unixToTime(byteOrder.Uint64(v[16:24])),
+				LastSuccessAmt: lnwire.MilliAtom(
+					byteOrder.Uint64(v[24:]),
+				),
+			}
+			copy(entry.From[:], k[:33])
+			copy(entry.To[:], k[33:])
+
+			entries = append(entries, entry)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// ResetMissionControl removes every entry from the mission control history.
+func (c *ChannelGraph) ResetMissionControl() error {
+	return c.db.Update(func(tx *bolt.Tx) error {
+		err := tx.DeleteBucket(missionControlBucket)
+		if err != nil && err != bolt.ErrBucketNotFound {
+			return err
+		}
+
+		return nil
+	})
+}
+
+// timeToUnix converts the passed time to a unix timestamp, mapping the zero
+// time to a zero timestamp.
+func timeToUnix(t time.Time) uint64 {
+	if t.IsZero() {
+		return 0
+	}
+
+	return uint64(t.Unix())
+}
+
+// unixToTime converts the passed unix timestamp to a time, mapping a zero
+// timestamp to the zero time.
+func unixToTime(unix uint64) time.Time {
+	if unix == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(int64(unix), 0)
+}
@@ -0,0 +1,97 @@
+package channeldb
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestMissionControlEntries tests the put/fetch/reset operations of the
+// mission control history.
+func TestMissionControlEntries(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+
+	graph := db.ChannelGraph()
+
+	// With no entries added, the history should be empty.
+	entries, err := graph.FetchMissionControlEntries()
+	if err != nil {
+		t.Fatalf("unable to fetch history: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("expected empty history, instead have %v entries",
+			len(entries))
+	}
+
+	// We'll add an entry for a single node, along with one for a pair of
+	// nodes.
+	now := time.Unix(time.Now().Unix(), 0)
+	nodeEntry := &MissionControlEntry{
+		From:     [33]byte{0x02, 0x01},
+		LastFail: now,
+	}
+	pairEntry := &MissionControlEntry{
+		From:           [33]byte{0x02, 0x01},
+		To:             [33]byte{0x03, 0x02},
+		LastFail:       now.Add(-time.Hour),
+		LastFailAmt:    5000,
+		LastSuccess:    now,
+		LastSuccessAmt: 1000,
+	}
+	err = graph.PutMissionControlEntries(
+		[]*MissionControlEntry{nodeEntry, pairEntry},
+	)
+	if err != nil {
+		t.Fatalf("unable to put entries: %v", err)
+	}
+
+	// Both entries should be returned as is, with the node entry first
+	// as its key sorts before that of the pair.
+	entries, err = graph.FetchMissionControlEntries()
+	if err != nil {
+		t.Fatalf("unable to fetch history: %v", err)
+	}
+	expected := []*MissionControlEntry{nodeEntry, pairEntry}
+	if !reflect.DeepEqual(entries, expected) {
+		t.Fatalf("expected entries %v, got %v", expected, entries)
+	}
+	if !entries[0].IsNodeEntry() || entries[1].IsNodeEntry() {
+		t.Fatalf("node and pair entries not distinguished")
+	}
+
+	// Putting an entry for the same pair should overwrite the existing
+	// one.
+	pairEntry.LastFail = now
+	err = graph.PutMissionControlEntries(
+		[]*MissionControlEntry{pairEntry},
+	)
+	if err != nil {
+		t.Fatalf("unable to put entries: %v", err)
+	}
+	entries, err = graph.FetchMissionControlEntries()
+	if err != nil {
+		t.Fatalf("unable to fetch history: %v", err)
+	}
+	if len(entries) != 2 || !entries[1].LastFail.Equal(now) {
+		t.Fatalf("pair entry not overwritten: %v", entries)
+	}
+
+	// Finally, once reset, the history should be empty once again.
+	if err := graph.ResetMissionControl(); err != nil {
+		t.Fatalf("unable to reset history: %v", err)
+	}
+	entries, err = graph.FetchMissionControlEntries()
+	if err != nil {
+		t.Fatalf("unable to fetch history: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("expected empty history, instead have %v entries",
+			len(entries))
+	}
+}
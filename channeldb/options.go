@@ -0,0 +1,59 @@
+package channeldb
+
+import "time"
+
+// Options holds parameters for tuning and customizing a channeldb.DB.
+type Options struct {
+	// BatchCommitInterval is the maximum duration the database waits
+	// before committing a batch of lazy graph updates. Updates which
+	// aren't lazy commit the pending batch right away. A zero interval
+	// disables batching, committing each update within its own
+	// transaction.
+	BatchCommitInterval time.Duration
+
+	// ReadOnly opens the database in read-only mode, allowing external
+	// tooling to inspect a database without any risk of mutating it. The
+	// database must already exist and be at the latest version, as it
+	// can be neither created nor migrated. All write transactions fail
+	// with bolt.ErrDatabaseReadOnly.
+	//
+	// NOTE: A read-only database only takes a shared lock on the database
+	// file, which can't be obtained while the file is locked by a
+	// running node. Copying the database first avoids this.
+	ReadOnly bool
+
+	// LockTimeout is the maximum duration to wait to obtain the lock on
+	// the database file. A zero timeout waits indefinitely.
+	LockTimeout time.Duration
+}
+
+// DefaultOptions returns an Options populated with default values.
+func DefaultOptions() Options {
+	return Options{}
+}
+
+// OptionModifier is a function signature for modifying the default Options.
+type OptionModifier func(*Options)
+
+// OptionSetBatchCommitInterval sets the batch commit interval for lazy graph
+// updates.
+func OptionSetBatchCommitInterval(interval time.Duration) OptionModifier {
+	return func(o *Options) {
+		o.BatchCommitInterval = interval
+	}
+}
+
+// OptionReadOnly opens the database in read-only mode.
+func OptionReadOnly() OptionModifier {
+	return func(o *Options) {
+		o.ReadOnly = true
+	}
+}
+
+// OptionSetLockTimeout sets the maximum duration to wait to obtain the lock on
+// the database file.
+func OptionSetLockTimeout(timeout time.Duration) OptionModifier {
+	return func(o *Options) {
+		o.LockTimeout = timeout
+	}
+}
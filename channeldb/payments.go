@@ -2,112 +2,593 @@ package channeldb
 
 import (
 	"bytes"
-	"encoding/binary"
+	"fmt"
 	"io"
+	"sort"
+	"time"
 
 	"github.com/boltdb/bolt"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/btcec"
 )
 
 var (
-	// paymentBucket is the name of the bucket within the database that
-	// stores all data related to payments.
+	// paymentsRootBucket is the name of the top-level bucket within the
+	// database that stores all data related to payments. Within this
+	// bucket, each payment hash has its own sub-bucket keyed by its
+	// payment hash.
 	//
-	// Within the payments bucket, each invoice is keyed by its invoice ID
-	// which is a monotonically increasing uint64.  BoltDB's sequence
-	// feature is used for generating monotonically increasing id.
-	paymentBucket = []byte("payments")
+	// Bucket hierarchy:
+	//
+	// root-bucket
+	//      |
+	//      |-- <paymenthash>
+	//      |        |--sequence-key: <sequence number>
+	//      |        |--creation-info-key: <creation info>
+	//      |        |--fail-info-key: <(optional) fail info>
+	//      |        |
+	//      |        |--payment-htlcs-bucket (shard-bucket)
+	//      |        |        |
+	//      |        |        |-- ai<htlc attempt ID>: <htlc attempt info>
+	//      |        |        |-- si<htlc attempt ID>: <(optional) settle info>
+	//      |        |        |-- fi<htlc attempt ID>: <(optional) fail info>
+	//      |        |        |
+	//      |        |       ...
+	//      |        |
+	//      |        |--payment-duplicate-bucket (optional)
+	//      |        |        |
+	//      |        |        |-- <seq-num>
+	//      |        |        |       |--sequence-key: <sequence number>
+	//      |        |        |       |--creation-info-key: <creation info>
+	//      |        |        |       |--payment-htlcs-bucket
+	//      |        |        |
+	//      |        |       ...
+	//      |
+	//      |-- <paymenthash>
+	//      |        |
+	//      |       ...
+	//     ...
+	//
+	// The sequence of the root bucket itself is used to assign both
+	// payment sequence numbers and HTLC attempt IDs, ensuring that both
+	// are unique across all payments.
+	paymentsRootBucket = []byte("payments-root-bucket")
+
+	// paymentsIndexBucket is the name of the top-level bucket which
+	// indexes the payments by their sequence number, allowing them to be
+	// queried in order of creation without reading every payment.
+	//
+	// maps: sequenceNumber -> paymentHash
+	paymentsIndexBucket = []byte("payments-index-bucket")
+
+	// paymentSequenceKey is a key used in the payment's sub-bucket to
+	// store the sequence number of the payment.
+	paymentSequenceKey = []byte("payment-sequence-key")
+
+	// paymentCreationInfoKey is a key used in the payment's sub-bucket to
+	// store the creation info of the payment.
+	paymentCreationInfoKey = []byte("payment-creation-info")
+
+	// paymentFailInfoKey is a key used in the payment's sub-bucket to
+	// store information about the reason a payment failed.
+	paymentFailInfoKey = []byte("payment-fail-info")
+
+	// paymentHtlcsBucket is the name of the sub-bucket within a payment's
+	// bucket that stores every HTLC attempt made for the payment.
+	paymentHtlcsBucket = []byte("payment-htlcs-bucket")
+
+	// paymentDuplicateBucket is the name of an optional sub-bucket within
+	// a payment's bucket that stores the payments made to the same payment
+	// hash prior to payment hashes being unique. Each duplicate payment has
+	// its own sub-bucket, keyed by its sequence number, which is laid out
+	// in the same way as the bucket of a regular payment.
+	paymentDuplicateBucket = []byte("payment-duplicate-bucket")
+
+	// htlcAttemptInfoKey is the key prefix used for the info of an HTLC
+	// attempt within the payment's HTLC bucket.
+	htlcAttemptInfoKey = []byte("ai")
+
+	// htlcSettleInfoKey is the key prefix used for the settle info of an
+	// HTLC attempt within the payment's HTLC bucket.
+	htlcSettleInfoKey = []byte("si")
+
+	// htlcFailInfoKey is the key prefix used for the fail info of an HTLC
+	// attempt within the payment's HTLC bucket.
+	htlcFailInfoKey = []byte("fi")
 )
 
-// OutgoingPayment represents a successful payment between the daemon and a
-// remote node. Details such as the total fee paid, and the time of the payment
-// are stored.
-type OutgoingPayment struct {
-	Invoice
+// FailureReason encodes the reason a payment ultimately failed.
+//
+// NOTE: iota isn't used here as the reason is persisted to disk, so the value
+// of each reason must never change.
+type FailureReason byte
 
-	// Fee is the total fee paid for the payment in milli-satoshis.
-	Fee lnwire.MilliSatoshi
+const (
+	// FailureReasonTimeout indicates that the payment did timeout before a
+	// successful payment attempt was made.
+	FailureReasonTimeout FailureReason = 0
 
-	// TotalTimeLock is the total cumulative time-lock in the HTLC extended
-	// from the second-to-last hop to the destination.
-	TimeLockLength uint32
+	// FailureReasonNoRoute indicates no successful route to the
+	// destination was found during path finding.
+	FailureReasonNoRoute FailureReason = 1
 
-	// Path encodes the path the payment took throuhg the network. The path
-	// excludes the outgoing node and consists of the hex-encoded
-	// compressed public key of each of the nodes involved in the payment.
-	Path [][33]byte
+	// FailureReasonError indicates that an unexpected error happened
+	// during payment.
+	FailureReasonError FailureReason = 2
 
-	// PaymentHash is the payment hash (r-hash) used to send the payment.
-	//
-	// TODO(roasbeef): weave through preimage on payment success to can
-	// store only supplemental info the embedded Invoice
+	// FailureReasonIncorrectPaymentDetails indicates that either the hash
+	// is unknown or the final cltv delta or amount is incorrect.
+	FailureReasonIncorrectPaymentDetails FailureReason = 3
+
+	// FailureReasonInsufficientBalance indicates that we didn't have
+	// enough balance to complete the payment.
+	FailureReasonInsufficientBalance FailureReason = 4
+
+	// FailureReasonPeerOffline indicates that we weren't connected to the
+	// first hop of any route to the destination.
+	FailureReasonPeerOffline FailureReason = 5
+
+	// FailureReasonFeeLimit indicates that every route to the destination
+	// required fees exceeding the fee limit of the payment.
+	FailureReasonFeeLimit FailureReason = 6
+)
+
+// String returns a human readable FailureReason.
+func (r FailureReason) String() string {
+	switch r {
+	case FailureReasonTimeout:
+		return "timeout"
+	case FailureReasonNoRoute:
+		return "no_route"
+	case FailureReasonError:
+		return "error"
+	case FailureReasonIncorrectPaymentDetails:
+		return "incorrect_payment_details"
+	case FailureReasonInsufficientBalance:
+		return "insufficient_balance"
+	case FailureReasonPeerOffline:
+		return "peer_offline"
+	case FailureReasonFeeLimit:
+		return "fee_limit"
+	}
+
+	return "unknown"
+}
+
+// PaymentStatus represent current status of payment.
+type PaymentStatus byte
+
+const (
+	// StatusUnknown is the status where a payment has never been
+	// initiated and hence is unknown.
+	StatusUnknown PaymentStatus = 0
+
+	// StatusInFlight is the status where a payment has been initiated, but
+	// a response has not been received.
+	StatusInFlight PaymentStatus = 1
+
+	// StatusSucceeded is the status where a payment has been initiated
+	// and the payment was completed successfully.
+	StatusSucceeded PaymentStatus = 2
+
+	// StatusFailed is the status where a payment has been initiated and a
+	// failure result has come back.
+	StatusFailed PaymentStatus = 3
+)
+
+// String returns a human readable PaymentStatus.
+func (ps PaymentStatus) String() string {
+	switch ps {
+	case StatusUnknown:
+		return "Unknown"
+	case StatusInFlight:
+		return "In Flight"
+	case StatusSucceeded:
+		return "Succeeded"
+	case StatusFailed:
+		return "Failed"
+	default:
+		return "Unknown"
+	}
+}
+
+// PaymentCreationInfo is the information necessary to have ready when
+// initiating a payment, moving it into state InFlight.
+type PaymentCreationInfo struct {
+	// PaymentHash is the hash this payment is paying to.
 	PaymentHash [32]byte
+
+	// Value is the amount we are paying.
+	Value lnwire.MilliAtom
+
+	// CreationDate is the time when this payment was initiated.
+	CreationDate time.Time
+
+	// PaymentRequest is the full payment request, if any.
+	PaymentRequest []byte
+}
+
+// HTLCAttemptInfo contains static information about a specific HTLC attempt
+// for a payment. This information is used by the router to handle any
+// errors coming back after an attempt is made, and to query the switch about
+// the status of the attempt.
+type HTLCAttemptInfo struct {
+	// AttemptID is the unique ID used for this attempt. It is assigned by
+	// the database when the attempt is registered.
+	AttemptID uint64
+
+	// Route is the route attempted to send the HTLC.
+	Route Route
+
+	// AttemptTime is the time at which this HTLC was attempted.
+	AttemptTime time.Time
+
+	// SessionKey is the ephemeral key used for the onion packet of this
+	// attempt. It allows the router to decrypt any error sent back for
+	// the attempt, even after a restart. It's nil for attempts recorded
+	// before the session key was persisted.
+	SessionKey *btcec.PrivateKey
+}
+
+// HTLCSettleInfo encapsulates the information that augments an HTLCAttempt
+// in the event that the HTLC is successful.
+type HTLCSettleInfo struct {
+	// Preimage is the preimage of a successful HTLC. This serves as a
+	// proof of payment.
+	Preimage [32]byte
+
+	// SettleTime is the time at which this HTLC was settled.
+	SettleTime time.Time
+}
+
+// HTLCFailInfo encapsulates the information that augments an HTLCAttempt in
+// the event that the HTLC fails.
+type HTLCFailInfo struct {
+	// FailTime is the time at which this HTLC was failed.
+	FailTime time.Time
+
+	// Message is a human readable description of the reason the HTLC
+	// attempt failed.
+	Message string
+}
+
+// HTLCAttempt contains information about a specific HTLC attempt for a given
+// payment. It contains the HTLCAttemptInfo used to send the HTLC, as well as
+// a timestamp and any known outcome of the attempt.
+type HTLCAttempt struct {
+	HTLCAttemptInfo
+
+	// Settle is the preimage of a successful payment. This serves as a
+	// proof of payment. It will only be non-nil for settled payments.
+	Settle *HTLCSettleInfo
+
+	// Failure will be non-nil if the HTLC failed, and contains information
+	// about the failure.
+	Failure *HTLCFailInfo
+}
+
+// MPPayment is a wrapper around a payment's PaymentCreationInfo and
+// HTLCAttempts. All payments will have the PaymentCreationInfo set, and the
+// HTLCs slice will contain every attempt that has been made to complete the
+// payment.
+type MPPayment struct {
+	// SequenceNum is a unique identifier used to sort the payments in
+	// order of creation.
+	SequenceNum uint64
+
+	// Info holds all static information about this payment, and is
+	// populated when the payment is initiated.
+	Info *PaymentCreationInfo
+
+	// HTLCs holds the information about individual HTLCs that we send in
+	// order to make the payment.
+	HTLCs []HTLCAttempt
+
+	// FailureReason is the failure reason code indicating the reason the
+	// payment failed. It is only non-nil for failed payments.
+	FailureReason *FailureReason
+
+	// Status is the current PaymentStatus of this payment.
+	Status PaymentStatus
+}
+
+// SettledAttempt returns the first settled HTLC attempt of the payment, or
+// nil if no attempt has been settled yet.
+func (m *MPPayment) SettledAttempt() *HTLCAttempt {
+	for i := range m.HTLCs {
+		if m.HTLCs[i].Settle != nil {
+			return &m.HTLCs[i]
+		}
+	}
+
+	return nil
+}
+
+// InFlightHTLCs returns the HTLC attempts of the payment which have neither
+// been settled nor failed.
+func (m *MPPayment) InFlightHTLCs() []HTLCAttempt {
+	var inflights []HTLCAttempt
+	for _, h := range m.HTLCs {
+		if h.Settle != nil || h.Failure != nil {
+			continue
+		}
+
+		inflights = append(inflights, h)
+	}
+
+	return inflights
+}
+
+// InitPayment checks or records the given PaymentCreationInfo with the DB,
+// making sure it does not already exist as an in-flight or succeeded payment.
+// A payment which has previously failed may be re-initiated, in which case
+// its prior attempts are discarded.
+func (db *DB) InitPayment(paymentHash [32]byte,
+	info *PaymentCreationInfo) error {
+
+	var b bytes.Buffer
+	if err := serializePaymentCreationInfo(&b, info); err != nil {
+		return err
+	}
+	infoBytes := b.Bytes()
+
+	return db.Update(func(tx *bolt.Tx) error {
+		payments, err := tx.CreateBucketIfNotExists(paymentsRootBucket)
+		if err != nil {
+			return err
+		}
+
+		paymentsIndex, err := tx.CreateBucketIfNotExists(
+			paymentsIndexBucket,
+		)
+		if err != nil {
+			return err
+		}
+
+		// If the payment already exists, then we'll ensure it isn't
+		// either in flight or already paid before wiping the prior
+		// state, including its entry within the payments index.
+		if bucket := payments.Bucket(paymentHash[:]); bucket != nil {
+			payment, err := fetchPayment(bucket)
+			if err != nil {
+				return err
+			}
+
+			switch payment.Status {
+			case StatusInFlight:
+				return ErrPaymentInFlight
+			case StatusSucceeded:
+				return ErrAlreadyPaid
+			}
+
+			// Only the state of the prior payment itself is wiped,
+			// so that any duplicate payments within the bucket are
+			// retained.
+			err = bucket.DeleteBucket(paymentHtlcsBucket)
+			if err != nil && err != bolt.ErrBucketNotFound {
+				return err
+			}
+			if err := bucket.Delete(paymentFailInfoKey); err != nil {
+				return err
+			}
+
+			var seqBytes [8]byte
+			byteOrder.PutUint64(seqBytes[:], payment.SequenceNum)
+			if err := paymentsIndex.Delete(seqBytes[:]); err != nil {
+				return err
+			}
+		}
+
+		bucket, err := payments.CreateBucketIfNotExists(paymentHash[:])
+		if err != nil {
+			return err
+		}
+
+		// Obtain a new sequence number for this payment. This is used
+		// to sort the payments in order of creation.
+		sequenceNum, err := payments.NextSequence()
+		if err != nil {
+			return err
+		}
+
+		var seqBytes [8]byte
+		byteOrder.PutUint64(seqBytes[:], sequenceNum)
+		if err := bucket.Put(paymentSequenceKey, seqBytes[:]); err != nil {
+			return err
+		}
+		err = paymentsIndex.Put(seqBytes[:], paymentHash[:])
+		if err != nil {
+			return err
+		}
+
+		if err := bucket.Put(paymentCreationInfoKey, infoBytes); err != nil {
+			return err
+		}
+
+		_, err = bucket.CreateBucket(paymentHtlcsBucket)
+		return err
+	})
 }
 
-// AddPayment saves a successful payment to the database. It is assumed that
-// all payment are sent using unique payment hashes.
-func (db *DB) AddPayment(payment *OutgoingPayment) error {
-	// Validate the field of the inner voice within the outgoing payment,
-	// these must also adhere to the same constraints as regular invoices.
-	if err := validateInvoice(&payment.Invoice); err != nil {
+// RegisterAttempt atomically records the provided HTLCAttemptInfo to the
+// target payment. A unique attempt ID is assigned to the attempt, and written
+// to the AttemptID field of the passed struct.
+func (db *DB) RegisterAttempt(paymentHash [32]byte,
+	attempt *HTLCAttemptInfo) error {
+
+	return db.Update(func(tx *bolt.Tx) error {
+		payments, bucket, err := fetchPaymentBucket(tx, paymentHash)
+		if err != nil {
+			return err
+		}
+
+		payment, err := fetchPayment(bucket)
+		if err != nil {
+			return err
+		}
+
+		// We cannot register a new attempt if the payment already has
+		// reached a terminal condition.
+		switch {
+		case payment.Status == StatusSucceeded:
+			return ErrPaymentAlreadySucceeded
+		case payment.FailureReason != nil:
+			return ErrPaymentAlreadyFailed
+		}
+
+		attemptID, err := payments.NextSequence()
+		if err != nil {
+			return err
+		}
+		attempt.AttemptID = attemptID
+
+		var b bytes.Buffer
+		if err := serializeHTLCAttemptInfo(&b, attempt); err != nil {
+			return err
+		}
+
+		htlcsBucket := bucket.Bucket(paymentHtlcsBucket)
+		return htlcsBucket.Put(
+			htlcBucketKey(htlcAttemptInfoKey, attemptID), b.Bytes(),
+		)
+	})
+}
+
+// SettleAttempt marks the given attempt settled with the preimage. If this is
+// a multi shard payment, this might implicitly mean that the full payment
+// succeeded.
+func (db *DB) SettleAttempt(paymentHash [32]byte, attemptID uint64,
+	settleInfo *HTLCSettleInfo) error {
+
+	var b bytes.Buffer
+	if err := serializeHTLCSettleInfo(&b, settleInfo); err != nil {
 		return err
 	}
 
-	// We first serialize the payment before starting the database
-	// transaction so we can avoid creating a DB payment in the case of a
-	// serialization error.
+	return db.updateHtlcKey(paymentHash, attemptID, htlcSettleInfoKey,
+		b.Bytes())
+}
+
+// FailAttempt marks the given payment attempt failed.
+func (db *DB) FailAttempt(paymentHash [32]byte, attemptID uint64,
+	failInfo *HTLCFailInfo) error {
+
 	var b bytes.Buffer
-	if err := serializeOutgoingPayment(&b, payment); err != nil {
+	if err := serializeHTLCFailInfo(&b, failInfo); err != nil {
 		return err
 	}
-	paymentBytes := b.Bytes()
 
-	return db.Batch(func(tx *bolt.Tx) error {
-		payments, err := tx.CreateBucketIfNotExists(paymentBucket)
+	return db.updateHtlcKey(paymentHash, attemptID, htlcFailInfoKey,
+		b.Bytes())
+}
+
+// updateHtlcKey updates a database key for the specified htlc.
+func (db *DB) updateHtlcKey(paymentHash [32]byte, attemptID uint64,
+	key, value []byte) error {
+
+	return db.Update(func(tx *bolt.Tx) error {
+		_, bucket, err := fetchPaymentBucket(tx, paymentHash)
 		if err != nil {
 			return err
 		}
 
-		// Obtain the new unique sequence number for this payment.
-		paymentID, err := payments.NextSequence()
+		htlcsBucket := bucket.Bucket(paymentHtlcsBucket)
+		if htlcsBucket == nil {
+			return ErrAttemptNotFound
+		}
+
+		// Make sure the shard is not already failed or settled.
+		if htlcsBucket.Get(htlcBucketKey(htlcAttemptInfoKey, attemptID)) == nil {
+			return ErrAttemptNotFound
+		}
+		if htlcsBucket.Get(htlcBucketKey(htlcFailInfoKey, attemptID)) != nil {
+			return ErrAttemptAlreadyFailed
+		}
+		if htlcsBucket.Get(htlcBucketKey(htlcSettleInfoKey, attemptID)) != nil {
+			return ErrAttemptAlreadySettled
+		}
+
+		return htlcsBucket.Put(htlcBucketKey(key, attemptID), value)
+	})
+}
+
+// FailPayment transitions a payment into the Failed state, and records the
+// reason the payment failed. After invoking this method, InitPayment should
+// return nil on its next call for this payment hash, allowing the switch to
+// make a subsequent payment.
+func (db *DB) FailPayment(paymentHash [32]byte, reason FailureReason) error {
+	return db.Update(func(tx *bolt.Tx) error {
+		_, bucket, err := fetchPaymentBucket(tx, paymentHash)
 		if err != nil {
 			return err
 		}
 
-		// We use BigEndian for keys as it orders keys in
-		// ascending order. This allows bucket scans to order payments
-		// in the order in which they were created.
-		paymentIDBytes := make([]byte, 8)
-		binary.BigEndian.PutUint64(paymentIDBytes, paymentID)
+		payment, err := fetchPayment(bucket)
+		if err != nil {
+			return err
+		}
+		if payment.Status == StatusSucceeded {
+			return ErrPaymentAlreadySucceeded
+		}
 
-		return payments.Put(paymentIDBytes, paymentBytes)
+		return bucket.Put(paymentFailInfoKey, []byte{byte(reason)})
 	})
 }
 
-// FetchAllPayments returns all outgoing payments in DB.
-func (db *DB) FetchAllPayments() ([]*OutgoingPayment, error) {
-	var payments []*OutgoingPayment
+// FetchPayment returns information about a payment from the database. Any
+// duplicate payments made to the same payment hash prior to payment hashes
+// being unique aren't returned.
+func (db *DB) FetchPayment(paymentHash [32]byte) (*MPPayment, error) {
+	var payment *MPPayment
+	err := db.View(func(tx *bolt.Tx) error {
+		_, bucket, err := fetchPaymentBucket(tx, paymentHash)
+		if err != nil {
+			return err
+		}
+
+		payment, err = fetchPayment(bucket)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	return payment, nil
+}
+
+// FetchPayments returns all sent payments found in the DB, sorted in the
+// order they were created.
+func (db *DB) FetchPayments() ([]*MPPayment, error) {
+	var payments []*MPPayment
 	err := db.View(func(tx *bolt.Tx) error {
-		bucket := tx.Bucket(paymentBucket)
-		if bucket == nil {
-			return ErrNoPaymentsCreated
+		paymentsBucket := tx.Bucket(paymentsRootBucket)
+		if paymentsBucket == nil {
+			return nil
 		}
 
-		return bucket.ForEach(func(k, v []byte) error {
-			// If the value is nil, then we ignore it as it may be
-			// a sub-bucket.
-			if v == nil {
-				return nil
+		return paymentsBucket.ForEach(func(k, v []byte) error {
+			bucket := paymentsBucket.Bucket(k)
+			if bucket == nil {
+				// We only expect sub-buckets to be found in
+				// this top-level bucket.
+				return fmt.Errorf("non bucket element in " +
+					"payments bucket")
 			}
 
-			r := bytes.NewReader(v)
-			payment, err := deserializeOutgoingPayment(r)
+			p, err := fetchPayment(bucket)
 			if err != nil {
 				return err
 			}
+			payments = append(payments, p)
+
+			// Any duplicate payments made to the same payment hash
+			// are returned alongside it.
+			duplicates, err := fetchDuplicatePayments(bucket)
+			if err != nil {
+				return err
+			}
+			payments = append(payments, duplicates...)
 
-			payments = append(payments, payment)
 			return nil
 		})
 	})
@@ -115,98 +596,482 @@ func (db *DB) FetchAllPayments() ([]*OutgoingPayment, error) {
 		return nil, err
 	}
 
+	// Before returning, sort the payments by their sequence number.
+	sort.Slice(payments, func(i, j int) bool {
+		return payments[i].SequenceNum < payments[j].SequenceNum
+	})
+
 	return payments, nil
 }
 
+// FetchInFlightPayments returns all payments with status InFlight. These are
+// the payments which will need to be resumed after a restart.
+func (db *DB) FetchInFlightPayments() ([]*MPPayment, error) {
+	payments, err := db.FetchPayments()
+	if err != nil {
+		return nil, err
+	}
+
+	var inFlights []*MPPayment
+	for _, p := range payments {
+		if p.Status != StatusInFlight {
+			continue
+		}
+
+		inFlights = append(inFlights, p)
+	}
+
+	return inFlights, nil
+}
+
+// PaymentsQuery represents a query to the payments database, starting or
+// ending at a certain index offset.
+type PaymentsQuery struct {
+	// IndexOffset is the sequence number of the payment the query starts
+	// after, or ends before if Reversed is set. The payment with this
+	// sequence number is excluded from the response. An offset of zero
+	// starts the query at the oldest payment, or the newest payment if
+	// Reversed is set.
+	IndexOffset uint64
+
+	// MaxPayments is the maximum number of payments to return. If zero,
+	// then all matching payments are returned.
+	MaxPayments uint64
+
+	// Reversed indicates that the query should walk backwards from the
+	// index offset, returning the payments created before it rather than
+	// after it.
+	Reversed bool
+
+	// IncludeIncomplete indicates that in-flight and failed payments
+	// should be included in the response, rather than only the payments
+	// which succeeded.
+	IncludeIncomplete bool
+}
+
+// PaymentsResponse contains the result of a query to the payments database.
+type PaymentsResponse struct {
+	// Payments is the set of payments returned by the query, ordered by
+	// their sequence number regardless of the direction of the query.
+	Payments []*MPPayment
+
+	// FirstIndexOffset is the sequence number of the first payment
+	// within the response. It can be used as the IndexOffset of a
+	// reversed query to fetch the preceding page of payments.
+	FirstIndexOffset uint64
+
+	// LastIndexOffset is the sequence number of the last payment within
+	// the response. It can be used as the IndexOffset of a forward query
+	// to fetch the following page of payments.
+	LastIndexOffset uint64
+}
+
+// QueryPayments returns a page of the payments found in the DB, as specified
+// by the passed query. Unlike FetchPayments, only the payments within the
+// requested page are read, by walking the payments index.
+func (db *DB) QueryPayments(query PaymentsQuery) (PaymentsResponse, error) {
+	var resp PaymentsResponse
+	err := db.View(func(tx *bolt.Tx) error {
+		payments := tx.Bucket(paymentsRootBucket)
+		paymentsIndex := tx.Bucket(paymentsIndexBucket)
+		if payments == nil || paymentsIndex == nil {
+			return nil
+		}
+
+		c := paymentsIndex.Cursor()
+
+		// First, we'll position the cursor at the first payment to be
+		// considered, and pick the direction in which to walk the
+		// index from there. As the index offset itself is excluded,
+		// we may need to step past it.
+		var (
+			k, v []byte
+			next func() ([]byte, []byte)
+		)
+		var offset [8]byte
+		byteOrder.PutUint64(offset[:], query.IndexOffset)
+		if query.Reversed {
+			next = c.Prev
+			switch {
+			case query.IndexOffset == 0:
+				k, v = c.Last()
+
+			default:
+				// Seek lands on the first key at or after the
+				// offset, so the payment before it is the
+				// first to precede the offset. If no such key
+				// exists, every payment precedes the offset.
+				k, _ = c.Seek(offset[:])
+				if k == nil {
+					k, v = c.Last()
+				} else {
+					k, v = c.Prev()
+				}
+			}
+		} else {
+			next = c.Next
+			k, v = c.Seek(offset[:])
+			if k != nil && bytes.Equal(k, offset[:]) {
+				k, v = c.Next()
+			}
+		}
+
+		for ; k != nil; k, v = next() {
+			if query.MaxPayments != 0 &&
+				uint64(len(resp.Payments)) >= query.MaxPayments {
+
+				break
+			}
+
+			bucket := payments.Bucket(v)
+			if bucket == nil {
+				return fmt.Errorf("payment %x referenced by "+
+					"index not found", v)
+			}
+
+			p, err := fetchPaymentWithSequenceNumber(bucket, k)
+			if err != nil {
+				return err
+			}
+
+			if !query.IncludeIncomplete &&
+				p.Status != StatusSucceeded {
+
+				continue
+			}
+
+			resp.Payments = append(resp.Payments, p)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return PaymentsResponse{}, err
+	}
+
+	// If the query was reversed, the payments were collected newest
+	// first, so we'll restore their order of creation.
+	if query.Reversed {
+		for i, j := 0, len(resp.Payments)-1; i < j; i, j = i+1, j-1 {
+			resp.Payments[i], resp.Payments[j] =
+				resp.Payments[j], resp.Payments[i]
+		}
+	}
+
+	if len(resp.Payments) > 0 {
+		resp.FirstIndexOffset = resp.Payments[0].SequenceNum
+		resp.LastIndexOffset =
+			resp.Payments[len(resp.Payments)-1].SequenceNum
+	}
+
+	return resp, nil
+}
+
 // DeleteAllPayments deletes all payments from DB.
 func (db *DB) DeleteAllPayments() error {
 	return db.Update(func(tx *bolt.Tx) error {
-		err := tx.DeleteBucket(paymentBucket)
-		if err != nil && err != bolt.ErrBucketNotFound {
-			return err
+		for _, bucket := range [][]byte{
+			paymentsRootBucket, paymentsIndexBucket,
+		} {
+			err := tx.DeleteBucket(bucket)
+			if err != nil && err != bolt.ErrBucketNotFound {
+				return err
+			}
+
+			if _, err := tx.CreateBucket(bucket); err != nil {
+				return err
+			}
 		}
 
-		_, err = tx.CreateBucket(paymentBucket)
-		return err
+		return nil
 	})
 }
 
-func serializeOutgoingPayment(w io.Writer, p *OutgoingPayment) error {
-	var scratch [8]byte
+// fetchPaymentBucket fetches the root payments bucket along with the
+// sub-bucket for the target payment hash. If the payment hasn't been
+// initiated, then ErrPaymentNotInitiated is returned.
+func fetchPaymentBucket(tx *bolt.Tx, paymentHash [32]byte) (*bolt.Bucket,
+	*bolt.Bucket, error) {
 
-	if err := serializeInvoice(w, &p.Invoice); err != nil {
-		return err
+	payments := tx.Bucket(paymentsRootBucket)
+	if payments == nil {
+		return nil, nil, ErrPaymentNotInitiated
 	}
 
-	byteOrder.PutUint64(scratch[:], uint64(p.Fee))
-	if _, err := w.Write(scratch[:]); err != nil {
-		return err
+	bucket := payments.Bucket(paymentHash[:])
+	if bucket == nil {
+		return nil, nil, ErrPaymentNotInitiated
 	}
 
-	// First write out the length of the bytes to prefix the value.
-	pathLen := uint32(len(p.Path))
-	byteOrder.PutUint32(scratch[:4], pathLen)
-	if _, err := w.Write(scratch[:4]); err != nil {
-		return err
+	return payments, bucket, nil
+}
+
+// fetchPayment reads the full state of a payment from its sub-bucket, and
+// derives its current status.
+func fetchPayment(bucket *bolt.Bucket) (*MPPayment, error) {
+	seqBytes := bucket.Get(paymentSequenceKey)
+	if seqBytes == nil {
+		return nil, fmt.Errorf("sequence number not found")
+	}
+	sequenceNum := byteOrder.Uint64(seqBytes)
+
+	infoBytes := bucket.Get(paymentCreationInfoKey)
+	if infoBytes == nil {
+		return nil, fmt.Errorf("creation info not found")
 	}
+	creationInfo, err := deserializePaymentCreationInfo(
+		bytes.NewReader(infoBytes),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var htlcs []HTLCAttempt
+	if htlcsBucket := bucket.Bucket(paymentHtlcsBucket); htlcsBucket != nil {
+		htlcs, err = fetchHtlcAttempts(htlcsBucket)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var failureReason *FailureReason
+	if b := bucket.Get(paymentFailInfoKey); len(b) > 0 {
+		reason := FailureReason(b[0])
+		failureReason = &reason
+	}
+
+	// With all the attempts read, we'll now derive the status of the
+	// payment. A single settled attempt means the payment succeeded. If
+	// we have a failure reason and no attempts remain in flight, the
+	// payment has failed. In all other cases it is still in flight.
+	status := StatusInFlight
+	var numInFlight int
+	for _, h := range htlcs {
+		switch {
+		case h.Settle != nil:
+			status = StatusSucceeded
+		case h.Failure == nil:
+			numInFlight++
+		}
+	}
+	if status != StatusSucceeded && failureReason != nil &&
+		numInFlight == 0 {
+
+		status = StatusFailed
+	}
+
+	return &MPPayment{
+		SequenceNum:   sequenceNum,
+		Info:          creationInfo,
+		HTLCs:         htlcs,
+		FailureReason: failureReason,
+		Status:        status,
+	}, nil
+}
+
+// fetchDuplicatePayments reads every duplicate payment stored within the
+// bucket of a payment, in the order they were created.
+func fetchDuplicatePayments(bucket *bolt.Bucket) ([]*MPPayment, error) {
+	duplicates := bucket.Bucket(paymentDuplicateBucket)
+	if duplicates == nil {
+		return nil, nil
+	}
+
+	var payments []*MPPayment
+	err := duplicates.ForEach(func(k, v []byte) error {
+		duplicate := duplicates.Bucket(k)
+		if duplicate == nil {
+			return fmt.Errorf("non bucket element in duplicate " +
+				"payments bucket")
+		}
 
-	// Then with the path written, we write out the series of public keys
-	// involved in the path.
-	for _, hop := range p.Path {
-		if _, err := w.Write(hop[:]); err != nil {
+		p, err := fetchPayment(duplicate)
+		if err != nil {
 			return err
 		}
+
+		payments = append(payments, p)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	byteOrder.PutUint32(scratch[:4], p.TimeLockLength)
-	if _, err := w.Write(scratch[:4]); err != nil {
-		return err
+	return payments, nil
+}
+
+// fetchPaymentWithSequenceNumber reads the payment with the passed serialized
+// sequence number from the bucket of its payment hash. As the bucket may also
+// hold duplicate payments, the sequence number is used to locate the payment
+// it refers to.
+func fetchPaymentWithSequenceNumber(bucket *bolt.Bucket,
+	seqBytes []byte) (*MPPayment, error) {
+
+	if bytes.Equal(bucket.Get(paymentSequenceKey), seqBytes) {
+		return fetchPayment(bucket)
 	}
 
-	if _, err := w.Write(p.PaymentHash[:]); err != nil {
-		return err
+	var duplicate *bolt.Bucket
+	if duplicates := bucket.Bucket(paymentDuplicateBucket); duplicates != nil {
+		duplicate = duplicates.Bucket(seqBytes)
+	}
+	if duplicate == nil {
+		return nil, fmt.Errorf("payment with sequence number %x not "+
+			"found", seqBytes)
 	}
 
-	return nil
+	return fetchPayment(duplicate)
 }
 
-func deserializeOutgoingPayment(r io.Reader) (*OutgoingPayment, error) {
-	var scratch [8]byte
+// fetchHtlcAttempts retrieves all HTLC attempts made for the payment found in
+// the given bucket, ordered by their attempt ID.
+func fetchHtlcAttempts(bucket *bolt.Bucket) ([]HTLCAttempt, error) {
+	var htlcs []HTLCAttempt
+
+	// As the attempt ID is encoded in big endian after the key prefix,
+	// iterating over the attempt info prefix yields the attempts in the
+	// order they were made.
+	c := bucket.Cursor()
+	prefix := htlcAttemptInfoKey
+	for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+		attemptInfo, err := deserializeHTLCAttemptInfo(bytes.NewReader(v))
+		if err != nil {
+			return nil, err
+		}
+		attemptID := attemptInfo.AttemptID
 
-	p := &OutgoingPayment{}
+		htlc := HTLCAttempt{
+			HTLCAttemptInfo: *attemptInfo,
+		}
 
-	inv, err := deserializeInvoice(r)
+		if v := bucket.Get(htlcBucketKey(htlcSettleInfoKey, attemptID)); v != nil {
+			htlc.Settle, err = deserializeHTLCSettleInfo(
+				bytes.NewReader(v),
+			)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if v := bucket.Get(htlcBucketKey(htlcFailInfoKey, attemptID)); v != nil {
+			htlc.Failure, err = deserializeHTLCFailInfo(
+				bytes.NewReader(v),
+			)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		htlcs = append(htlcs, htlc)
+	}
+
+	return htlcs, nil
+}
+
+// htlcBucketKey creates a composite key from prefix and id where the result
+// is simply the two concatenated.
+func htlcBucketKey(prefix []byte, attemptID uint64) []byte {
+	key := make([]byte, len(prefix)+8)
+	copy(key, prefix)
+	byteOrder.PutUint64(key[len(prefix):], attemptID)
+	return key
+}
+
+func serializePaymentCreationInfo(w io.Writer, c *PaymentCreationInfo) error {
+	return writeElements(
+		w, c.PaymentHash, c.Value, c.CreationDate, c.PaymentRequest,
+	)
+}
+
+func deserializePaymentCreationInfo(r io.Reader) (*PaymentCreationInfo, error) {
+	c := &PaymentCreationInfo{}
+	err := readElements(
+		r, &c.PaymentHash, &c.Value, &c.CreationDate, &c.PaymentRequest,
+	)
 	if err != nil {
 		return nil, err
 	}
-	p.Invoice = *inv
 
-	if _, err := r.Read(scratch[:]); err != nil {
+	// Normalize an empty payment request to nil so callers can easily
+	// detect its absence.
+	if len(c.PaymentRequest) == 0 {
+		c.PaymentRequest = nil
+	}
+
+	return c, nil
+}
+
+func serializeHTLCAttemptInfo(w io.Writer, a *HTLCAttemptInfo) error {
+	if err := writeElements(w, a.AttemptID, a.AttemptTime); err != nil {
+		return err
+	}
+
+	if err := serializeRoute(w, &a.Route); err != nil {
+		return err
+	}
+
+	// The session key is written last, such that attempts recorded
+	// without one can still be read.
+	if a.SessionKey == nil {
+		return nil
+	}
+
+	var sessionKey [32]byte
+	copy(sessionKey[:], a.SessionKey.Serialize())
+	return writeElements(w, sessionKey)
+}
+
+func deserializeHTLCAttemptInfo(r io.Reader) (*HTLCAttemptInfo, error) {
+	a := &HTLCAttemptInfo{}
+	if err := readElements(r, &a.AttemptID, &a.AttemptTime); err != nil {
 		return nil, err
 	}
-	p.Fee = lnwire.MilliSatoshi(byteOrder.Uint64(scratch[:]))
 
-	if _, err = r.Read(scratch[:4]); err != nil {
+	route, err := deserializeRoute(r)
+	if err != nil {
 		return nil, err
 	}
-	pathLen := byteOrder.Uint32(scratch[:4])
+	a.Route = route
 
-	path := make([][33]byte, pathLen)
-	for i := uint32(0); i < pathLen; i++ {
-		if _, err := r.Read(path[i][:]); err != nil {
-			return nil, err
-		}
+	var sessionKey [32]byte
+	_, err = io.ReadFull(r, sessionKey[:])
+	switch {
+	// Attempts recorded before the session key was persisted end right
+	// after the route.
+	case err == io.EOF:
+		return a, nil
+
+	case err != nil:
+		return nil, err
 	}
-	p.Path = path
 
-	if _, err = r.Read(scratch[:4]); err != nil {
+	a.SessionKey, _ = btcec.PrivKeyFromBytes(btcec.S256(), sessionKey[:])
+
+	return a, nil
+}
+
+func serializeHTLCSettleInfo(w io.Writer, s *HTLCSettleInfo) error {
+	return writeElements(w, s.Preimage, s.SettleTime)
+}
+
+func deserializeHTLCSettleInfo(r io.Reader) (*HTLCSettleInfo, error) {
+	s := &HTLCSettleInfo{}
+	if err := readElements(r, &s.Preimage, &s.SettleTime); err != nil {
 		return nil, err
 	}
-	p.TimeLockLength = byteOrder.Uint32(scratch[:4])
 
-	if _, err := r.Read(p.PaymentHash[:]); err != nil {
+	return s, nil
+}
+
+func serializeHTLCFailInfo(w io.Writer, f *HTLCFailInfo) error {
+	return writeElements(w, f.FailTime, f.Message)
+}
+
+func deserializeHTLCFailInfo(r io.Reader) (*HTLCFailInfo, error) {
+	f := &HTLCFailInfo{}
+	if err := readElements(r, &f.FailTime, &f.Message); err != nil {
 		return nil, err
 	}
 
-	return p, nil
+	return f, nil
 }
@@ -11,34 +11,9 @@ import (
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/btcec"
 )
 
-func makeFakePayment() *OutgoingPayment {
-	fakeInvoice := &Invoice{
-		// Use single second precision to avoid false positive test
-		// failures due to the monotonic time component.
-		CreationDate: time.Unix(time.Now().Unix(), 0),
-		Memo:         []byte("fake memo"),
-		Receipt:      []byte("fake receipt"),
-	}
-
-	copy(fakeInvoice.Terms.PaymentPreimage[:], rev[:])
-	fakeInvoice.Terms.Value = lnwire.NewMSatFromSatoshis(10000)
-
-	fakePath := make([][33]byte, 3)
-	for i := 0; i < 3; i++ {
-		copy(fakePath[i][:], bytes.Repeat([]byte{byte(i)}, 33))
-	}
-
-	return &OutgoingPayment{
-		Invoice:        *fakeInvoice,
-		Fee:            101,
-		Path:           fakePath,
-		TimeLockLength: 1000,
-		PaymentHash:    sha256.Sum256(rev[:]),
-	}
-}
-
 // randomBytes creates random []byte with length in range [minLen, maxLen)
 func randomBytes(minLen, maxLen int) ([]byte, error) {
 	randBuf := make([]byte, minLen+rand.Intn(maxLen-minLen))
@@ -51,131 +26,291 @@ func randomBytes(minLen, maxLen int) ([]byte, error) {
 	return randBuf, nil
 }
 
-func makeRandomFakePayment() (*OutgoingPayment, error) {
-	var err error
-	fakeInvoice := &Invoice{
+func makeFakeRoute() Route {
+	route := Route{
+		TotalTimeLock: 1000,
+		TotalFees:     101,
+		TotalAmount:   lnwire.NewMSatFromSatoshis(10000) + 101,
+	}
+	for i := 0; i < 3; i++ {
+		hop := &Hop{
+			ChannelID:        uint64(i + 1),
+			OutgoingTimeLock: uint32(1000 - i*10),
+			AmtToForward:     lnwire.NewMSatFromSatoshis(10000),
+			Fee:              lnwire.MilliAtom(i),
+		}
+		copy(hop.PubKeyBytes[:], bytes.Repeat([]byte{byte(i)}, 33))
+
+		route.Hops = append(route.Hops, hop)
+	}
+
+	return route
+}
+
+func makeFakeCreationInfo(preimage [32]byte) *PaymentCreationInfo {
+	return &PaymentCreationInfo{
+		PaymentHash: sha256.Sum256(preimage[:]),
+		Value:       lnwire.NewMSatFromSatoshis(10000),
 		// Use single second precision to avoid false positive test
 		// failures due to the monotonic time component.
-		CreationDate: time.Unix(time.Now().Unix(), 0),
+		CreationDate:   time.Unix(time.Now().Unix(), 0),
+		PaymentRequest: []byte("fake payment request"),
 	}
+}
+
+func TestPaymentSerialization(t *testing.T) {
+	t.Parallel()
+
+	info := makeFakeCreationInfo(rev)
 
-	fakeInvoice.Memo, err = randomBytes(1, 50)
+	var b bytes.Buffer
+	if err := serializePaymentCreationInfo(&b, info); err != nil {
+		t.Fatalf("unable to serialize creation info: %v", err)
+	}
+	newInfo, err := deserializePaymentCreationInfo(&b)
 	if err != nil {
-		return nil, err
+		t.Fatalf("unable to deserialize creation info: %v", err)
+	}
+	if !reflect.DeepEqual(info, newInfo) {
+		t.Fatalf("Payment infos do not match after "+
+			"serialization/deserialization %v vs %v",
+			spew.Sdump(info), spew.Sdump(newInfo))
 	}
 
-	fakeInvoice.Receipt, err = randomBytes(1, 50)
-	if err != nil {
-		return nil, err
+	attempt := &HTLCAttemptInfo{
+		AttemptID:   44,
+		Route:       makeFakeRoute(),
+		AttemptTime: time.Unix(time.Now().Unix(), 0),
 	}
 
-	preImg, err := randomBytes(32, 33)
+	b.Reset()
+	if err := serializeHTLCAttemptInfo(&b, attempt); err != nil {
+		t.Fatalf("unable to serialize attempt: %v", err)
+	}
+	newAttempt, err := deserializeHTLCAttemptInfo(&b)
 	if err != nil {
-		return nil, err
+		t.Fatalf("unable to deserialize attempt: %v", err)
+	}
+	if !reflect.DeepEqual(attempt, newAttempt) {
+		t.Fatalf("Attempts do not match after "+
+			"serialization/deserialization %v vs %v",
+			spew.Sdump(attempt), spew.Sdump(newAttempt))
 	}
-	copy(fakeInvoice.Terms.PaymentPreimage[:], preImg)
 
-	fakeInvoice.Terms.Value = lnwire.MilliSatoshi(rand.Intn(10000))
+	// An attempt carrying the session key of its onion packet should
+	// have it restored as well.
+	attempt.SessionKey, _ = btcec.PrivKeyFromBytes(
+		btcec.S256(), bytes.Repeat([]byte{1}, 32),
+	)
 
-	fakePathLen := 1 + rand.Intn(5)
-	fakePath := make([][33]byte, fakePathLen)
-	for i := 0; i < fakePathLen; i++ {
-		b, err := randomBytes(33, 34)
-		if err != nil {
-			return nil, err
-		}
-		copy(fakePath[i][:], b)
+	b.Reset()
+	if err := serializeHTLCAttemptInfo(&b, attempt); err != nil {
+		t.Fatalf("unable to serialize attempt: %v", err)
 	}
-
-	rHash := sha256.Sum256(fakeInvoice.Terms.PaymentPreimage[:])
-	fakePayment := &OutgoingPayment{
-		Invoice:        *fakeInvoice,
-		Fee:            lnwire.MilliSatoshi(rand.Intn(1001)),
-		Path:           fakePath,
-		TimeLockLength: uint32(rand.Intn(10000)),
-		PaymentHash:    rHash,
+	newAttempt, err = deserializeHTLCAttemptInfo(&b)
+	if err != nil {
+		t.Fatalf("unable to deserialize attempt: %v", err)
+	}
+	if !reflect.DeepEqual(attempt, newAttempt) {
+		t.Fatalf("Attempts do not match after "+
+			"serialization/deserialization %v vs %v",
+			spew.Sdump(attempt), spew.Sdump(newAttempt))
 	}
-
-	return fakePayment, nil
 }
 
-func TestOutgoingPaymentSerialization(t *testing.T) {
+func TestPaymentWorkflow(t *testing.T) {
 	t.Parallel()
 
-	fakePayment := makeFakePayment()
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
 
-	var b bytes.Buffer
-	if err := serializeOutgoingPayment(&b, fakePayment); err != nil {
-		t.Fatalf("unable to serialize outgoing payment: %v", err)
+	info := makeFakeCreationInfo(rev)
+	payHash := info.PaymentHash
+
+	// Attempting to fetch or update a payment that hasn't yet been
+	// initiated should fail.
+	if _, err := db.FetchPayment(payHash); err != ErrPaymentNotInitiated {
+		t.Fatalf("expected ErrPaymentNotInitiated, got %v", err)
+	}
+	err = db.RegisterAttempt(payHash, &HTLCAttemptInfo{})
+	if err != ErrPaymentNotInitiated {
+		t.Fatalf("expected ErrPaymentNotInitiated, got %v", err)
 	}
 
-	newPayment, err := deserializeOutgoingPayment(&b)
-	if err != nil {
-		t.Fatalf("unable to deserialize outgoing payment: %v", err)
+	if err := db.InitPayment(payHash, info); err != nil {
+		t.Fatalf("unable to init payment: %v", err)
 	}
 
-	if !reflect.DeepEqual(fakePayment, newPayment) {
-		t.Fatalf("Payments do not match after "+
-			"serialization/deserialization %v vs %v",
-			spew.Sdump(fakePayment),
-			spew.Sdump(newPayment),
-		)
+	// A second initialization should be rejected as the payment is now
+	// in flight.
+	if err := db.InitPayment(payHash, info); err != ErrPaymentInFlight {
+		t.Fatalf("expected ErrPaymentInFlight, got %v", err)
 	}
-}
 
-func TestOutgoingPaymentWorkflow(t *testing.T) {
-	t.Parallel()
+	// We'll now register two attempts, failing the first and settling
+	// the second.
+	attempt1 := &HTLCAttemptInfo{
+		Route:       makeFakeRoute(),
+		AttemptTime: time.Unix(time.Now().Unix(), 0),
+	}
+	if err := db.RegisterAttempt(payHash, attempt1); err != nil {
+		t.Fatalf("unable to register attempt: %v", err)
+	}
+	failInfo := &HTLCFailInfo{
+		FailTime: time.Unix(time.Now().Unix(), 0),
+		Message:  "temporary channel failure",
+	}
+	err = db.FailAttempt(payHash, attempt1.AttemptID, failInfo)
+	if err != nil {
+		t.Fatalf("unable to fail attempt: %v", err)
+	}
 
-	db, cleanUp, err := makeTestDB()
-	defer cleanUp()
+	payment, err := db.FetchPayment(payHash)
 	if err != nil {
-		t.Fatalf("unable to make test db: %v", err)
+		t.Fatalf("unable to fetch payment: %v", err)
+	}
+	if payment.Status != StatusInFlight {
+		t.Fatalf("expected status %v, got %v", StatusInFlight,
+			payment.Status)
 	}
 
-	fakePayment := makeFakePayment()
-	if err = db.AddPayment(fakePayment); err != nil {
-		t.Fatalf("unable to put payment in DB: %v", err)
+	attempt2 := &HTLCAttemptInfo{
+		Route:       makeFakeRoute(),
+		AttemptTime: time.Unix(time.Now().Unix(), 0),
+	}
+	if err := db.RegisterAttempt(payHash, attempt2); err != nil {
+		t.Fatalf("unable to register attempt: %v", err)
+	}
+	if attempt1.AttemptID == attempt2.AttemptID {
+		t.Fatalf("attempt IDs should be unique")
+	}
+	settleInfo := &HTLCSettleInfo{
+		Preimage:   rev,
+		SettleTime: time.Unix(time.Now().Unix(), 0),
+	}
+	err = db.SettleAttempt(payHash, attempt2.AttemptID, settleInfo)
+	if err != nil {
+		t.Fatalf("unable to settle attempt: %v", err)
+	}
+
+	// An attempt may only be resolved once.
+	err = db.FailAttempt(payHash, attempt2.AttemptID, failInfo)
+	if err != ErrAttemptAlreadySettled {
+		t.Fatalf("expected ErrAttemptAlreadySettled, got %v", err)
 	}
 
-	payments, err := db.FetchAllPayments()
+	payment, err = db.FetchPayment(payHash)
 	if err != nil {
-		t.Fatalf("unable to fetch payments from DB: %v", err)
+		t.Fatalf("unable to fetch payment: %v", err)
+	}
+	expectedPayment := &MPPayment{
+		SequenceNum: payment.SequenceNum,
+		Info:        info,
+		HTLCs: []HTLCAttempt{
+			{
+				HTLCAttemptInfo: *attempt1,
+				Failure:         failInfo,
+			},
+			{
+				HTLCAttemptInfo: *attempt2,
+				Settle:          settleInfo,
+			},
+		},
+		Status: StatusSucceeded,
+	}
+	if !reflect.DeepEqual(payment, expectedPayment) {
+		t.Fatalf("Wrong payment after reading from DB."+
+			"Got %v, want %v", spew.Sdump(payment),
+			spew.Sdump(expectedPayment))
+	}
+	if payment.SettledAttempt().Settle.Preimage != rev {
+		t.Fatalf("wrong preimage for settled attempt")
 	}
 
-	expectedPayments := []*OutgoingPayment{fakePayment}
-	if !reflect.DeepEqual(payments, expectedPayments) {
-		t.Fatalf("Wrong payments after reading from DB."+
-			"Got %v, want %v",
-			spew.Sdump(payments),
-			spew.Sdump(expectedPayments),
-		)
+	// Now that the payment has succeeded, it can't be initiated again.
+	if err := db.InitPayment(payHash, info); err != ErrAlreadyPaid {
+		t.Fatalf("expected ErrAlreadyPaid, got %v", err)
 	}
 
-	// Make some random payments
-	for i := 0; i < 5; i++ {
-		randomPayment, err := makeRandomFakePayment()
-		if err != nil {
-			t.Fatalf("Internal error in tests: %v", err)
-		}
+	// Next, we'll create a second payment which fails.
+	var preimage2 [32]byte
+	copy(preimage2[:], bytes.Repeat([]byte{2}, 32))
+	info2 := makeFakeCreationInfo(preimage2)
+	if err := db.InitPayment(info2.PaymentHash, info2); err != nil {
+		t.Fatalf("unable to init payment: %v", err)
+	}
+	attempt3 := &HTLCAttemptInfo{
+		Route:       makeFakeRoute(),
+		AttemptTime: time.Unix(time.Now().Unix(), 0),
+	}
+	if err := db.RegisterAttempt(info2.PaymentHash, attempt3); err != nil {
+		t.Fatalf("unable to register attempt: %v", err)
+	}
 
-		if err = db.AddPayment(randomPayment); err != nil {
-			t.Fatalf("unable to put payment in DB: %v", err)
-		}
+	// With an attempt still in flight, failing the payment should leave
+	// it in flight.
+	err = db.FailPayment(info2.PaymentHash, FailureReasonNoRoute)
+	if err != nil {
+		t.Fatalf("unable to fail payment: %v", err)
+	}
+	inFlight, err := db.FetchInFlightPayments()
+	if err != nil {
+		t.Fatalf("unable to fetch in-flight payments: %v", err)
+	}
+	if len(inFlight) != 1 || inFlight[0].Info.PaymentHash != info2.PaymentHash {
+		t.Fatalf("expected second payment to be in flight, "+
+			"instead got: %v", spew.Sdump(inFlight))
+	}
 
-		expectedPayments = append(expectedPayments, randomPayment)
+	// Although a failure reason was recorded, the payment can't be
+	// initiated again while an attempt is still in flight.
+	err = db.InitPayment(info2.PaymentHash, info2)
+	if err != ErrPaymentInFlight {
+		t.Fatalf("expected ErrPaymentInFlight, got %v", err)
 	}
 
-	payments, err = db.FetchAllPayments()
+	// Once the attempt is failed, the payment should transition to the
+	// failed state.
+	err = db.FailAttempt(info2.PaymentHash, attempt3.AttemptID, failInfo)
+	if err != nil {
+		t.Fatalf("unable to fail attempt: %v", err)
+	}
+	payment, err = db.FetchPayment(info2.PaymentHash)
 	if err != nil {
-		t.Fatalf("Can't get payments from DB: %v", err)
+		t.Fatalf("unable to fetch payment: %v", err)
+	}
+	if payment.Status != StatusFailed {
+		t.Fatalf("expected status %v, got %v", StatusFailed,
+			payment.Status)
+	}
+	if *payment.FailureReason != FailureReasonNoRoute {
+		t.Fatalf("expected failure reason %v, got %v",
+			FailureReasonNoRoute, *payment.FailureReason)
 	}
 
-	if !reflect.DeepEqual(payments, expectedPayments) {
-		t.Fatalf("Wrong payments after reading from DB."+
-			"Got %v, want %v",
-			spew.Sdump(payments),
-			spew.Sdump(expectedPayments),
-		)
+	// A failed payment may be re-initiated.
+	if err := db.InitPayment(info2.PaymentHash, info2); err != nil {
+		t.Fatalf("unable to re-init payment: %v", err)
+	}
+
+	// Both payments should be returned in the order they were created.
+	payments, err := db.FetchPayments()
+	if err != nil {
+		t.Fatalf("unable to fetch payments: %v", err)
+	}
+	if len(payments) != 2 {
+		t.Fatalf("expected 2 payments, got %v", len(payments))
+	}
+	if payments[0].Info.PaymentHash != payHash ||
+		payments[1].Info.PaymentHash != info2.PaymentHash {
+
+		t.Fatalf("payments not returned in order of creation")
+	}
+	if len(payments[1].HTLCs) != 0 {
+		t.Fatalf("re-initiated payment should have no attempts")
 	}
 
 	// Delete all payments.
@@ -184,7 +319,7 @@ func TestOutgoingPaymentWorkflow(t *testing.T) {
 	}
 
 	// Check that there is no payments after deletion
-	paymentsAfterDeletion, err := db.FetchAllPayments()
+	paymentsAfterDeletion, err := db.FetchPayments()
 	if err != nil {
 		t.Fatalf("Can't get payments after deletion: %v", err)
 	}
@@ -193,3 +328,169 @@ func TestOutgoingPaymentWorkflow(t *testing.T) {
 			len(paymentsAfterDeletion), 0)
 	}
 }
+
+// initTestPayments initiates numPayments payments, settling every other one
+// starting with the first, and returns their payment hashes in the order they
+// were created.
+func initTestPayments(t *testing.T, db *DB, numPayments int) [][32]byte {
+	var hashes [][32]byte
+	for i := 0; i < numPayments; i++ {
+		var preimage [32]byte
+		preimage[0] = byte(i)
+		info := makeFakeCreationInfo(preimage)
+
+		if err := db.InitPayment(info.PaymentHash, info); err != nil {
+			t.Fatalf("unable to init payment: %v", err)
+		}
+		hashes = append(hashes, info.PaymentHash)
+
+		if i%2 != 0 {
+			continue
+		}
+
+		attempt := &HTLCAttemptInfo{
+			Route:       makeFakeRoute(),
+			AttemptTime: time.Unix(time.Now().Unix(), 0),
+		}
+		err := db.RegisterAttempt(info.PaymentHash, attempt)
+		if err != nil {
+			t.Fatalf("unable to register attempt: %v", err)
+		}
+		err = db.SettleAttempt(
+			info.PaymentHash, attempt.AttemptID, &HTLCSettleInfo{
+				Preimage:   preimage,
+				SettleTime: time.Unix(time.Now().Unix(), 0),
+			},
+		)
+		if err != nil {
+			t.Fatalf("unable to settle attempt: %v", err)
+		}
+	}
+
+	return hashes
+}
+
+// assertPaymentHashes asserts that the payments within the response have the
+// expected payment hashes, in order.
+func assertPaymentHashes(t *testing.T, resp PaymentsResponse,
+	expected ...[32]byte) {
+
+	if len(resp.Payments) != len(expected) {
+		t.Fatalf("expected %v payments, got %v", len(expected),
+			len(resp.Payments))
+	}
+	for i, p := range resp.Payments {
+		if p.Info.PaymentHash != expected[i] {
+			t.Fatalf("expected payment %v to be %x, got %x", i,
+				expected[i], p.Info.PaymentHash)
+		}
+	}
+
+	if len(expected) == 0 {
+		return
+	}
+	if resp.FirstIndexOffset != resp.Payments[0].SequenceNum {
+		t.Fatalf("expected first index offset %v, got %v",
+			resp.Payments[0].SequenceNum, resp.FirstIndexOffset)
+	}
+	last := resp.Payments[len(resp.Payments)-1]
+	if resp.LastIndexOffset != last.SequenceNum {
+		t.Fatalf("expected last index offset %v, got %v",
+			last.SequenceNum, resp.LastIndexOffset)
+	}
+}
+
+// TestQueryPayments asserts that payments can be paged through in either
+// direction, optionally excluding the payments which haven't succeeded.
+func TestQueryPayments(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+
+	// Querying an empty database should return no payments.
+	resp, err := db.QueryPayments(PaymentsQuery{IncludeIncomplete: true})
+	if err != nil {
+		t.Fatalf("unable to query payments: %v", err)
+	}
+	assertPaymentHashes(t, resp)
+
+	// Payments 0, 2 and 4 succeed, while 1, 3 and 5 remain in flight.
+	h := initTestPayments(t, db, 6)
+
+	query := func(q PaymentsQuery) PaymentsResponse {
+		resp, err := db.QueryPayments(q)
+		if err != nil {
+			t.Fatalf("unable to query payments: %v", err)
+		}
+		return resp
+	}
+
+	all := query(PaymentsQuery{IncludeIncomplete: true})
+	assertPaymentHashes(t, all, h...)
+	assertPaymentHashes(t, query(PaymentsQuery{}), h[0], h[2], h[4])
+
+	// Page forwards through all payments, two at a time.
+	page1 := query(PaymentsQuery{MaxPayments: 2, IncludeIncomplete: true})
+	assertPaymentHashes(t, page1, h[0], h[1])
+	page2 := query(PaymentsQuery{
+		IndexOffset:       page1.LastIndexOffset,
+		MaxPayments:       2,
+		IncludeIncomplete: true,
+	})
+	assertPaymentHashes(t, page2, h[2], h[3])
+	page3 := query(PaymentsQuery{
+		IndexOffset:       page2.LastIndexOffset,
+		MaxPayments:       2,
+		IncludeIncomplete: true,
+	})
+	assertPaymentHashes(t, page3, h[4], h[5])
+	assertPaymentHashes(t, query(PaymentsQuery{
+		IndexOffset:       page3.LastIndexOffset,
+		IncludeIncomplete: true,
+	}))
+
+	// Page backwards from the newest payment, excluding the incomplete
+	// payments.
+	rev1 := query(PaymentsQuery{MaxPayments: 2, Reversed: true})
+	assertPaymentHashes(t, rev1, h[2], h[4])
+	rev2 := query(PaymentsQuery{
+		IndexOffset: rev1.FirstIndexOffset,
+		MaxPayments: 2,
+		Reversed:    true,
+	})
+	assertPaymentHashes(t, rev2, h[0])
+
+	// A reversed query with an offset beyond the newest payment should
+	// start at the newest payment.
+	assertPaymentHashes(t, query(PaymentsQuery{
+		IndexOffset:       all.LastIndexOffset + 100,
+		MaxPayments:       1,
+		Reversed:          true,
+		IncludeIncomplete: true,
+	}), h[5])
+
+	// Once a payment fails, it may be re-initiated, after which it should
+	// be ordered as the newest payment.
+	if err := db.FailPayment(h[1], FailureReasonNoRoute); err != nil {
+		t.Fatalf("unable to fail payment: %v", err)
+	}
+	var preimage [32]byte
+	preimage[0] = 1
+	if err := db.InitPayment(h[1], makeFakeCreationInfo(preimage)); err != nil {
+		t.Fatalf("unable to re-init payment: %v", err)
+	}
+	assertPaymentHashes(
+		t, query(PaymentsQuery{IncludeIncomplete: true}),
+		h[0], h[2], h[3], h[4], h[5], h[1],
+	)
+
+	// Deleting all payments should also clear the index.
+	if err := db.DeleteAllPayments(); err != nil {
+		t.Fatalf("unable to delete payments: %v", err)
+	}
+	assertPaymentHashes(t, query(PaymentsQuery{IncludeIncomplete: true}))
+}
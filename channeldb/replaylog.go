@@ -0,0 +1,88 @@
+package channeldb
+
+import (
+	"github.com/boltdb/bolt"
+)
+
+var (
+	// replayLogBucket is a top-level bucket which houses the log of onion
+	// packets processed by our node, used to reject replayed packets.
+	// Each packet is identified by a prefix of the hash of its shared
+	// secret, and remains within the log until the incoming HTLC which
+	// carried it has expired, after which the packet can no longer be
+	// replayed successfully.
+	//
+	// maps: hashPrefix -> cltvExpiry
+	replayLogBucket = []byte("sphinx-replay-log")
+)
+
+// HashPrefixSize is the size of the prefix of the shared secret hash which
+// identifies an onion packet within the replay log.
+const HashPrefixSize = 20
+
+// HashPrefix is a prefix of the hash of the shared secret of an onion packet,
+// which identifies the packet within the replay log.
+type HashPrefix [HashPrefixSize]byte
+
+// PutReplayEntry adds the onion packet identified by the passed hash prefix to
+// the replay log, where it's kept until the passed CLTV expiry height has
+// been reached. If the packet is already present within the log, then
+// ErrReplayedPacket is returned.
+func (d *DB) PutReplayEntry(hash *HashPrefix, cltv uint32) error {
+	return d.Update(func(tx *bolt.Tx) error {
+		replayLog, err := tx.CreateBucketIfNotExists(replayLogBucket)
+		if err != nil {
+			return err
+		}
+
+		if replayLog.Get(hash[:]) != nil {
+			return ErrReplayedPacket
+		}
+
+		var expiry [4]byte
+		byteOrder.PutUint32(expiry[:], cltv)
+		return replayLog.Put(hash[:], expiry[:])
+	})
+}
+
+// GarbageCollectReplayLog removes every entry from the replay log whose CLTV
+// expiry is at or below the passed height, returning the number of entries
+// removed.
+func (d *DB) GarbageCollectReplayLog(height uint32) (uint32, error) {
+	var numExpired uint32
+	err := d.Update(func(tx *bolt.Tx) error {
+		numExpired = 0
+
+		replayLog := tx.Bucket(replayLogBucket)
+		if replayLog == nil {
+			return nil
+		}
+
+		var expired [][]byte
+		err := replayLog.ForEach(func(k, v []byte) error {
+			if len(v) == 4 && byteOrder.Uint32(v) > height {
+				return nil
+			}
+
+			expired = append(expired, k)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, k := range expired {
+			if err := replayLog.Delete(k); err != nil {
+				return err
+			}
+			numExpired++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return numExpired, nil
+}
@@ -0,0 +1,62 @@
+package channeldb
+
+import (
+	"testing"
+)
+
+// TestReplayLog tests that onion packets added to the replay log are detected
+// as replays until their CLTV expiry has been reached.
+func TestReplayLog(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+
+	hash1 := &HashPrefix{1}
+	hash2 := &HashPrefix{2}
+
+	if err := db.PutReplayEntry(hash1, 100); err != nil {
+		t.Fatalf("unable to add replay entry: %v", err)
+	}
+	if err := db.PutReplayEntry(hash2, 200); err != nil {
+		t.Fatalf("unable to add replay entry: %v", err)
+	}
+
+	// Adding either packet again should be detected as a replay, even
+	// with a different expiry.
+	if err := db.PutReplayEntry(hash1, 300); err != ErrReplayedPacket {
+		t.Fatalf("expected ErrReplayedPacket, got %v", err)
+	}
+	if err := db.PutReplayEntry(hash2, 200); err != ErrReplayedPacket {
+		t.Fatalf("expected ErrReplayedPacket, got %v", err)
+	}
+
+	// Garbage collecting below both expiries shouldn't remove anything.
+	numExpired, err := db.GarbageCollectReplayLog(99)
+	if err != nil {
+		t.Fatalf("unable to garbage collect replay log: %v", err)
+	}
+	if numExpired != 0 {
+		t.Fatalf("expected no expired entries, got %v", numExpired)
+	}
+
+	// Once the first packet's expiry is reached, it should be removed
+	// from the log, allowing it to be added again, while the second
+	// packet should still be detected as a replay.
+	numExpired, err = db.GarbageCollectReplayLog(100)
+	if err != nil {
+		t.Fatalf("unable to garbage collect replay log: %v", err)
+	}
+	if numExpired != 1 {
+		t.Fatalf("expected 1 expired entry, got %v", numExpired)
+	}
+	if err := db.PutReplayEntry(hash2, 200); err != ErrReplayedPacket {
+		t.Fatalf("expected ErrReplayedPacket, got %v", err)
+	}
+	if err := db.PutReplayEntry(hash1, 300); err != nil {
+		t.Fatalf("unable to add replay entry: %v", err)
+	}
+}
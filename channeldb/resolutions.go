@@ -0,0 +1,192 @@
+package channeldb
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/boltdb/bolt"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+var (
+	// resolutionMsgBucket is a top-level bucket which houses the
+	// resolutions of outgoing HTLCs which have been resolved on-chain, yet
+	// are still to be settled or failed back upstream by the switch. Each
+	// resolution is keyed by a sequence number assigned when it's added,
+	// and is removed once handled.
+	//
+	// maps: seqNum -> resolutionMsg
+	resolutionMsgBucket = []byte("resolution-msgs")
+)
+
+const (
+	// resolutionFailure and resolutionPreimage are the types of resolution
+	// messages, indicating whether the HTLC is to be failed or settled
+	// upstream.
+	resolutionFailure  uint8 = 0
+	resolutionPreimage uint8 = 1
+)
+
+// ResolutionMsg is the persisted resolution of an outgoing HTLC which has been
+// resolved on-chain. It's retained until the HTLC has been settled or failed
+// back upstream, so that the resolution isn't lost should we restart before
+// then.
+type ResolutionMsg struct {
+	// ID is the sequence number assigned to the resolution once it's been
+	// added to the database.
+	ID uint64
+
+	// SourceChan identifies the channel the outgoing HTLC was sent over.
+	SourceChan lnwire.ShortChannelID
+
+	// PayHash is the payment hash of the resolved HTLC.
+	PayHash [32]byte
+
+	// Amt is the amount of the resolved HTLC.
+	Amt lnwire.MilliAtom
+
+	// Failure will be non-nil if the HTLC is to be failed upstream.
+	Failure lnwire.FailureMessage
+
+	// PreImage will be non-nil if the HTLC is to be settled upstream.
+	PreImage *[32]byte
+}
+
+// AddResolutionMsg adds the passed resolution to the database, assigning it
+// its ID.
+func (d *DB) AddResolutionMsg(msg *ResolutionMsg) error {
+	var b bytes.Buffer
+	if err := serializeResolutionMsg(&b, msg); err != nil {
+		return err
+	}
+
+	return d.Update(func(tx *bolt.Tx) error {
+		resolutions, err := tx.CreateBucketIfNotExists(
+			resolutionMsgBucket,
+		)
+		if err != nil {
+			return err
+		}
+
+		id, err := resolutions.NextSequence()
+		if err != nil {
+			return err
+		}
+
+		var k [8]byte
+		byteOrder.PutUint64(k[:], id)
+		if err := resolutions.Put(k[:], b.Bytes()); err != nil {
+			return err
+		}
+
+		msg.ID = id
+		return nil
+	})
+}
+
+// FetchResolutionMsgs returns all resolutions which have yet to be deleted,
+// in the order they were added.
+func (d *DB) FetchResolutionMsgs() ([]*ResolutionMsg, error) {
+	var msgs []*ResolutionMsg
+	err := d.View(func(tx *bolt.Tx) error {
+		resolutions := tx.Bucket(resolutionMsgBucket)
+		if resolutions == nil {
+			return nil
+		}
+
+		return resolutions.ForEach(func(k, v []byte) error {
+			msg, err := deserializeResolutionMsg(bytes.NewReader(v))
+			if err != nil {
+				return err
+			}
+			msg.ID = byteOrder.Uint64(k)
+
+			msgs = append(msgs, msg)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return msgs, nil
+}
+
+// DeleteResolutionMsg removes the resolution with the passed ID once it has
+// been handled. Deleting a resolution which isn't found is a noop.
+func (d *DB) DeleteResolutionMsg(id uint64) error {
+	return d.Update(func(tx *bolt.Tx) error {
+		resolutions := tx.Bucket(resolutionMsgBucket)
+		if resolutions == nil {
+			return nil
+		}
+
+		var k [8]byte
+		byteOrder.PutUint64(k[:], id)
+		return resolutions.Delete(k[:])
+	})
+}
+
+func serializeResolutionMsg(w io.Writer, msg *ResolutionMsg) error {
+	err := writeElements(w, msg.SourceChan, msg.PayHash, msg.Amt)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case msg.PreImage != nil:
+		return writeElements(w, resolutionPreimage, *msg.PreImage)
+
+	case msg.Failure != nil:
+		var failure bytes.Buffer
+		if err := lnwire.EncodeFailure(&failure, msg.Failure, 0); err != nil {
+			return err
+		}
+		return writeElements(w, resolutionFailure, failure.Bytes())
+
+	default:
+		return fmt.Errorf("resolution for %x has neither a preimage "+
+			"nor a failure", msg.PayHash[:])
+	}
+}
+
+func deserializeResolutionMsg(r io.Reader) (*ResolutionMsg, error) {
+	msg := &ResolutionMsg{}
+	err := readElements(r, &msg.SourceChan, &msg.PayHash, &msg.Amt)
+	if err != nil {
+		return nil, err
+	}
+
+	var resolutionType uint8
+	if err := readElement(r, &resolutionType); err != nil {
+		return nil, err
+	}
+
+	switch resolutionType {
+	case resolutionPreimage:
+		var preimage [32]byte
+		if err := readElement(r, &preimage); err != nil {
+			return nil, err
+		}
+		msg.PreImage = &preimage
+
+	case resolutionFailure:
+		var failure []byte
+		if err := readElement(r, &failure); err != nil {
+			return nil, err
+		}
+		msg.Failure, err = lnwire.DecodeFailure(
+			bytes.NewReader(failure), 0,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, fmt.Errorf("unknown resolution type %v",
+			resolutionType)
+	}
+
+	return msg, nil
+}
@@ -0,0 +1,84 @@
+package channeldb
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// TestResolutionMsgs tests that resolutions are retained, in the order they
+// were added, until they're deleted.
+func TestResolutionMsgs(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+
+	// Without any resolutions added, none should be returned.
+	msgs, err := db.FetchResolutionMsgs()
+	if err != nil {
+		t.Fatalf("unable to fetch resolutions: %v", err)
+	}
+	if len(msgs) != 0 {
+		t.Fatalf("expected no resolutions, got %v", len(msgs))
+	}
+
+	preimage := [32]byte{1, 2, 3}
+	settle := &ResolutionMsg{
+		SourceChan: lnwire.NewShortChanIDFromInt(1),
+		PayHash:    [32]byte{4, 5, 6},
+		Amt:        1000,
+		PreImage:   &preimage,
+	}
+	fail := &ResolutionMsg{
+		SourceChan: lnwire.NewShortChanIDFromInt(2),
+		PayHash:    [32]byte{7, 8, 9},
+		Amt:        2000,
+		Failure:    &lnwire.FailPermanentChannelFailure{},
+	}
+	for _, msg := range []*ResolutionMsg{settle, fail} {
+		if err := db.AddResolutionMsg(msg); err != nil {
+			t.Fatalf("unable to add resolution: %v", err)
+		}
+	}
+	if settle.ID == 0 || fail.ID <= settle.ID {
+		t.Fatalf("unexpected resolution IDs %v and %v", settle.ID,
+			fail.ID)
+	}
+
+	msgs, err = db.FetchResolutionMsgs()
+	if err != nil {
+		t.Fatalf("unable to fetch resolutions: %v", err)
+	}
+	expected := []*ResolutionMsg{settle, fail}
+	if !reflect.DeepEqual(msgs, expected) {
+		t.Fatalf("expected resolutions %v, got %v",
+			spew.Sdump(expected), spew.Sdump(msgs))
+	}
+
+	// A resolution without either a preimage or a failure is invalid.
+	err = db.AddResolutionMsg(&ResolutionMsg{
+		SourceChan: lnwire.NewShortChanIDFromInt(3),
+	})
+	if err == nil {
+		t.Fatalf("expected invalid resolution to be rejected")
+	}
+
+	// Once deleted, a resolution should no longer be returned.
+	if err := db.DeleteResolutionMsg(settle.ID); err != nil {
+		t.Fatalf("unable to delete resolution: %v", err)
+	}
+	msgs, err = db.FetchResolutionMsgs()
+	if err != nil {
+		t.Fatalf("unable to fetch resolutions: %v", err)
+	}
+	if !reflect.DeepEqual(msgs, []*ResolutionMsg{fail}) {
+		t.Fatalf("expected resolution %v, got %v", spew.Sdump(fail),
+			spew.Sdump(msgs))
+	}
+}
@@ -0,0 +1,105 @@
+package channeldb
+
+import (
+	"io"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// Hop is the on-disk representation of a single hop within a payment route.
+// It contains the information required to reconstruct the path a particular
+// HTLC attempt took through the network.
+type Hop struct {
+	// PubKeyBytes is the raw bytes of the public key of the target node.
+	PubKeyBytes [33]byte
+
+	// ChannelID is the unique channel ID for the channel that leads to
+	// this hop.
+	ChannelID uint64
+
+	// OutgoingTimeLock is the timelock value that should be used when
+	// crafting the _outgoing_ HTLC from this hop.
+	OutgoingTimeLock uint32
+
+	// AmtToForward is the amount that this hop will forward to the next
+	// hop.
+	AmtToForward lnwire.MilliAtom
+
+	// Fee is the total fee that this hop will subtract from the incoming
+	// payment.
+	Fee lnwire.MilliAtom
+}
+
+// Route is the on-disk representation of a route through the channel graph
+// that was used to attempt a payment.
+type Route struct {
+	// TotalTimeLock is the cumulative (final) time lock across the entire
+	// route.
+	TotalTimeLock uint32
+
+	// TotalFees is the sum of the fees paid at each hop within the route.
+	TotalFees lnwire.MilliAtom
+
+	// TotalAmount is the total amount of funds required to complete a
+	// payment over this route, including fees.
+	TotalAmount lnwire.MilliAtom
+
+	// Hops contains details concerning the specific forwarding details at
+	// each hop.
+	Hops []*Hop
+}
+
+// serializeRoute writes the passed route to the given io.Writer.
+func serializeRoute(w io.Writer, r *Route) error {
+	err := writeElements(
+		w, r.TotalTimeLock, r.TotalFees, r.TotalAmount,
+		uint32(len(r.Hops)),
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, h := range r.Hops {
+		err := writeElements(
+			w, h.PubKeyBytes, h.ChannelID, h.OutgoingTimeLock,
+			h.AmtToForward, h.Fee,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deserializeRoute reads a route previously written with serializeRoute from
+// the passed io.Reader.
+func deserializeRoute(r io.Reader) (Route, error) {
+	var (
+		route   Route
+		numHops uint32
+	)
+	err := readElements(
+		r, &route.TotalTimeLock, &route.TotalFees, &route.TotalAmount,
+		&numHops,
+	)
+	if err != nil {
+		return route, err
+	}
+
+	route.Hops = make([]*Hop, numHops)
+	for i := uint32(0); i < numHops; i++ {
+		h := &Hop{}
+		err := readElements(
+			r, &h.PubKeyBytes, &h.ChannelID, &h.OutgoingTimeLock,
+			&h.AmtToForward, &h.Fee,
+		)
+		if err != nil {
+			return route, err
+		}
+
+		route.Hops[i] = h
+	}
+
+	return route, nil
+}
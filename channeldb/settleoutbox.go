@@ -0,0 +1,254 @@
+package channeldb
+
+import (
+	"time"
+
+	"github.com/boltdb/bolt"
+)
+
+var (
+	// settleConsumerBucket is a top-level bucket which houses the set of
+	// durable consumers of invoice settlement events. Each consumer
+	// records the settle index of the latest event it has acknowledged.
+	// Together with the settle index, this forms an outbox for each
+	// consumer: every invoice with a settle index beyond the consumer's
+	// acknowledged index is yet to be delivered, and will be re-delivered
+	// until acknowledged, even across restarts. Each consumer also
+	// records the time it was last active, allowing idle consumers to be
+	// pruned. Consumers registered before the time was recorded lack it.
+	//
+	// maps: consumerID -> ackedSettleIndex || lastActiveUnix
+	settleConsumerBucket = []byte("invoice-settle-consumers")
+)
+
+// putSettleConsumer records the acknowledged settle index of the consumer,
+// marking it as active as of now.
+func putSettleConsumer(consumers *bolt.Bucket, consumerID string,
+	ackedIndex uint64) error {
+
+	var v [16]byte
+	byteOrder.PutUint64(v[:8], ackedIndex)
+	byteOrder.PutUint64(v[8:], uint64(time.Now().Unix()))
+	return consumers.Put([]byte(consumerID), v[:])
+}
+
+// RegisterSettleConsumer registers a new durable consumer of invoice
+// settlement events, returning the settle index of the latest event it has
+// acknowledged. Newly registered consumers begin with the latest settle index,
+// so only invoices settled after registration are delivered to them. If the
+// consumer has already been registered, then its existing acknowledged index
+// is returned, and it's marked as active. If maxConsumers is non-zero, then
+// ErrTooManySettleConsumers is returned rather than registering a new
+// consumer beyond it.
+func (d *DB) RegisterSettleConsumer(consumerID string,
+	maxConsumers int) (uint64, error) {
+
+	var ackedIndex uint64
+	err := d.Update(func(tx *bolt.Tx) error {
+		consumers, err := tx.CreateBucketIfNotExists(settleConsumerBucket)
+		if err != nil {
+			return err
+		}
+
+		if v := consumers.Get([]byte(consumerID)); v != nil {
+			ackedIndex = byteOrder.Uint64(v[:8])
+			return putSettleConsumer(consumers, consumerID, ackedIndex)
+		}
+
+		if maxConsumers > 0 {
+			var numConsumers int
+			err := consumers.ForEach(func(_, _ []byte) error {
+				numConsumers++
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			if numConsumers >= maxConsumers {
+				return ErrTooManySettleConsumers
+			}
+		}
+
+		ackedIndex = latestSettleIndex(tx)
+		return putSettleConsumer(consumers, consumerID, ackedIndex)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return ackedIndex, nil
+}
+
+// AckSettleEvents acknowledges the delivery of all invoice settlement events
+// up to and including the passed settle index to the target consumer. These
+// events won't be delivered to the consumer again. Acknowledging an index at
+// or below the consumer's current acknowledged index only marks the consumer
+// as active.
+func (d *DB) AckSettleEvents(consumerID string, settleIndex uint64) error {
+	return d.Update(func(tx *bolt.Tx) error {
+		consumers := tx.Bucket(settleConsumerBucket)
+		if consumers == nil {
+			return ErrSettleConsumerNotFound
+		}
+
+		v := consumers.Get([]byte(consumerID))
+		if v == nil {
+			return ErrSettleConsumerNotFound
+		}
+		ackedIndex := byteOrder.Uint64(v[:8])
+		if settleIndex > ackedIndex {
+			if settleIndex > latestSettleIndex(tx) {
+				return ErrSettleIndexUnknown
+			}
+			ackedIndex = settleIndex
+		}
+
+		return putSettleConsumer(consumers, consumerID, ackedIndex)
+	})
+}
+
+// RemoveSettleConsumer removes the target consumer of invoice settlement
+// events, such that no further events are retained for it.
+func (d *DB) RemoveSettleConsumer(consumerID string) error {
+	return d.Update(func(tx *bolt.Tx) error {
+		consumers := tx.Bucket(settleConsumerBucket)
+		if consumers == nil {
+			return ErrSettleConsumerNotFound
+		}
+		if consumers.Get([]byte(consumerID)) == nil {
+			return ErrSettleConsumerNotFound
+		}
+
+		return consumers.Delete([]byte(consumerID))
+	})
+}
+
+// PruneSettleConsumers removes all consumers of invoice settlement events that
+// were last active before idleBefore, except for those within the passed set
+// of active consumers. Consumers registered before their last active time was
+// recorded are marked as active as of now instead. The IDs of the removed
+// consumers are returned.
+func (d *DB) PruneSettleConsumers(idleBefore time.Time,
+	active map[string]struct{}) ([]string, error) {
+
+	var pruned []string
+	err := d.Update(func(tx *bolt.Tx) error {
+		consumers := tx.Bucket(settleConsumerBucket)
+		if consumers == nil {
+			return nil
+		}
+
+		// We'll first gather the consumers to be updated, as the
+		// bucket can't be modified while iterating over it.
+		var idle, legacy []string
+		var legacyIndexes []uint64
+		err := consumers.ForEach(func(k, v []byte) error {
+			consumerID := string(k)
+			if _, ok := active[consumerID]; ok {
+				return nil
+			}
+
+			if len(v) < 16 {
+				legacy = append(legacy, consumerID)
+				legacyIndexes = append(
+					legacyIndexes, byteOrder.Uint64(v[:8]),
+				)
+				return nil
+			}
+
+			lastActive := time.Unix(int64(byteOrder.Uint64(v[8:])), 0)
+			if lastActive.Before(idleBefore) {
+				idle = append(idle, consumerID)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for i, consumerID := range legacy {
+			err := putSettleConsumer(
+				consumers, consumerID, legacyIndexes[i],
+			)
+			if err != nil {
+				return err
+			}
+		}
+		for _, consumerID := range idle {
+			if err := consumers.Delete([]byte(consumerID)); err != nil {
+				return err
+			}
+		}
+
+		pruned = idle
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pruned, nil
+}
+
+// PendingSettleEvents returns all invoices settled after the latest event
+// acknowledged by the target consumer, ordered by their settle index.
+func (d *DB) PendingSettleEvents(consumerID string) ([]*Invoice, error) {
+	var invoices []*Invoice
+	err := d.View(func(tx *bolt.Tx) error {
+		consumers := tx.Bucket(settleConsumerBucket)
+		if consumers == nil {
+			return ErrSettleConsumerNotFound
+		}
+
+		v := consumers.Get([]byte(consumerID))
+		if v == nil {
+			return ErrSettleConsumerNotFound
+		}
+		ackedIndex := byteOrder.Uint64(v[:8])
+
+		invoiceB := tx.Bucket(invoiceBucket)
+		if invoiceB == nil {
+			return nil
+		}
+		settleIndex := invoiceB.Bucket(settleIndexBucket)
+		if settleIndex == nil {
+			return nil
+		}
+
+		var startIndex [8]byte
+		byteOrder.PutUint64(startIndex[:], ackedIndex+1)
+
+		c := settleIndex.Cursor()
+		for k, invoiceKey := c.Seek(startIndex[:]); k != nil; k, invoiceKey = c.Next() {
+			invoice, err := fetchInvoice(invoiceKey, invoiceB)
+			if err != nil {
+				return err
+			}
+
+			invoices = append(invoices, invoice)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return invoices, nil
+}
+
+// latestSettleIndex returns the settle index assigned to the most recently
+// settled invoice, or zero if no invoices have been settled.
+func latestSettleIndex(tx *bolt.Tx) uint64 {
+	invoices := tx.Bucket(invoiceBucket)
+	if invoices == nil {
+		return 0
+	}
+	settleIndex := invoices.Bucket(settleIndexBucket)
+	if settleIndex == nil {
+		return 0
+	}
+
+	return settleIndex.Sequence()
+}
@@ -0,0 +1,217 @@
+package channeldb
+
+import (
+	"crypto/sha256"
+	"testing"
+	"time"
+
+	"github.com/boltdb/bolt"
+)
+
+// TestSettleOutbox tests that invoice settlement events are delivered to
+// durable consumers until they're acknowledged.
+func TestSettleOutbox(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+
+	// settle adds and settles a new invoice, returning its settle index.
+	settle := func() uint64 {
+		invoice, err := randInvoice(1000)
+		if err != nil {
+			t.Fatalf("unable to create invoice: %v", err)
+		}
+		if err := db.AddInvoice(invoice); err != nil {
+			t.Fatalf("unable to add invoice: %v", err)
+		}
+		hash := sha256.Sum256(invoice.Terms.PaymentPreimage[:])
+		if err := db.SettleInvoice(hash, nil); err != nil {
+			t.Fatalf("unable to settle invoice: %v", err)
+		}
+		invoice, err = db.LookupInvoice(hash)
+		if err != nil {
+			t.Fatalf("unable to lookup invoice: %v", err)
+		}
+
+		return invoice.SettleIndex
+	}
+
+	// assertPending asserts that the consumer's pending events have the
+	// expected settle indexes.
+	assertPending := func(consumerID string, expected ...uint64) {
+		pending, err := db.PendingSettleEvents(consumerID)
+		if err != nil {
+			t.Fatalf("unable to fetch pending events: %v", err)
+		}
+		if len(pending) != len(expected) {
+			t.Fatalf("expected %v pending events, got %v",
+				len(expected), len(pending))
+		}
+		for i, invoice := range pending {
+			if invoice.SettleIndex != expected[i] {
+				t.Fatalf("expected settle index %v, got %v",
+					expected[i], invoice.SettleIndex)
+			}
+		}
+	}
+
+	// Operating on a consumer which hasn't been registered should fail.
+	if _, err := db.PendingSettleEvents("a"); err != ErrSettleConsumerNotFound {
+		t.Fatalf("expected ErrSettleConsumerNotFound, got %v", err)
+	}
+	if err := db.AckSettleEvents("a", 1); err != ErrSettleConsumerNotFound {
+		t.Fatalf("expected ErrSettleConsumerNotFound, got %v", err)
+	}
+
+	// An invoice settled before the consumer is registered shouldn't be
+	// delivered to it.
+	settle()
+	ackedIndex, err := db.RegisterSettleConsumer("a", 0)
+	if err != nil {
+		t.Fatalf("unable to register consumer: %v", err)
+	}
+	if ackedIndex != 1 {
+		t.Fatalf("expected acked index 1, got %v", ackedIndex)
+	}
+	assertPending("a")
+
+	// Invoices settled afterwards should remain pending until they're
+	// acknowledged.
+	index2 := settle()
+	index3 := settle()
+	assertPending("a", index2, index3)
+
+	if err := db.AckSettleEvents("a", index2); err != nil {
+		t.Fatalf("unable to ack events: %v", err)
+	}
+	assertPending("a", index3)
+
+	// Acknowledging an older index is a noop, while acknowledging an
+	// index which hasn't been assigned should fail.
+	if err := db.AckSettleEvents("a", 1); err != nil {
+		t.Fatalf("unable to ack events: %v", err)
+	}
+	assertPending("a", index3)
+	if err := db.AckSettleEvents("a", index3+1); err != ErrSettleIndexUnknown {
+		t.Fatalf("expected ErrSettleIndexUnknown, got %v", err)
+	}
+
+	// Re-registering the consumer should return its existing index.
+	ackedIndex, err = db.RegisterSettleConsumer("a", 0)
+	if err != nil {
+		t.Fatalf("unable to register consumer: %v", err)
+	}
+	if ackedIndex != index2 {
+		t.Fatalf("expected acked index %v, got %v", index2, ackedIndex)
+	}
+
+	if err := db.AckSettleEvents("a", index3); err != nil {
+		t.Fatalf("unable to ack events: %v", err)
+	}
+	assertPending("a")
+}
+
+// TestSettleConsumerLifecycle tests that the number of settlement consumers
+// can be bounded, and that consumers can be removed either explicitly or once
+// they've been idle for too long.
+func TestSettleConsumerLifecycle(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+
+	// Registering consumers beyond the maximum should fail, though an
+	// existing consumer can still be re-registered.
+	if _, err := db.RegisterSettleConsumer("a", 2); err != nil {
+		t.Fatalf("unable to register consumer: %v", err)
+	}
+	if _, err := db.RegisterSettleConsumer("b", 2); err != nil {
+		t.Fatalf("unable to register consumer: %v", err)
+	}
+	_, err = db.RegisterSettleConsumer("c", 2)
+	if err != ErrTooManySettleConsumers {
+		t.Fatalf("expected ErrTooManySettleConsumers, got %v", err)
+	}
+	if _, err := db.RegisterSettleConsumer("a", 2); err != nil {
+		t.Fatalf("unable to re-register consumer: %v", err)
+	}
+
+	// Once a consumer is removed, there's room for another.
+	if err := db.RemoveSettleConsumer("b"); err != nil {
+		t.Fatalf("unable to remove consumer: %v", err)
+	}
+	if _, err := db.PendingSettleEvents("b"); err != ErrSettleConsumerNotFound {
+		t.Fatalf("expected ErrSettleConsumerNotFound, got %v", err)
+	}
+	if err := db.RemoveSettleConsumer("b"); err != ErrSettleConsumerNotFound {
+		t.Fatalf("expected ErrSettleConsumerNotFound, got %v", err)
+	}
+	if _, err := db.RegisterSettleConsumer("c", 2); err != nil {
+		t.Fatalf("unable to register consumer: %v", err)
+	}
+
+	// Pruning with a cutoff in the past shouldn't remove any consumers.
+	pruned, err := db.PruneSettleConsumers(
+		time.Now().Add(-time.Hour), nil,
+	)
+	if err != nil {
+		t.Fatalf("unable to prune consumers: %v", err)
+	}
+	if len(pruned) != 0 {
+		t.Fatalf("expected no pruned consumers, got %v", pruned)
+	}
+
+	// We'll also add a consumer in the format used before consumers
+	// recorded when they were last active.
+	err = db.Update(func(tx *bolt.Tx) error {
+		consumers := tx.Bucket(settleConsumerBucket)
+		var v [8]byte
+		return consumers.Put([]byte("legacy"), v[:])
+	})
+	if err != nil {
+		t.Fatalf("unable to add legacy consumer: %v", err)
+	}
+
+	// Pruning with a cutoff in the future should remove every consumer
+	// other than the active one, and the legacy one, which only now
+	// records its last active time.
+	active := map[string]struct{}{"c": {}}
+	pruned, err = db.PruneSettleConsumers(
+		time.Now().Add(time.Hour), active,
+	)
+	if err != nil {
+		t.Fatalf("unable to prune consumers: %v", err)
+	}
+	if len(pruned) != 1 || pruned[0] != "a" {
+		t.Fatalf("expected consumer a to be pruned, got %v", pruned)
+	}
+	for _, consumerID := range []string{"c", "legacy"} {
+		if _, err := db.PendingSettleEvents(consumerID); err != nil {
+			t.Fatalf("unable to fetch pending events of %v: %v",
+				consumerID, err)
+		}
+	}
+	if _, err := db.PendingSettleEvents("a"); err != ErrSettleConsumerNotFound {
+		t.Fatalf("expected ErrSettleConsumerNotFound, got %v", err)
+	}
+
+	// Now that its last active time has been recorded, the legacy
+	// consumer is pruned like any other.
+	pruned, err = db.PruneSettleConsumers(
+		time.Now().Add(time.Hour), active,
+	)
+	if err != nil {
+		t.Fatalf("unable to prune consumers: %v", err)
+	}
+	if len(pruned) != 1 || pruned[0] != "legacy" {
+		t.Fatalf("expected legacy consumer to be pruned, got %v",
+			pruned)
+	}
+}
@@ -0,0 +1,150 @@
+package channeldb
+
+import (
+	"crypto/sha256"
+
+	"github.com/boltdb/bolt"
+)
+
+var (
+	// witnessBucketKey is a top-level bucket which houses a sub-bucket for
+	// each class of witness that can be stored within the WitnessCache.
+	// Each sub-bucket maps the key a witness is retrieved by, to the
+	// witness itself. For sha256 preimages, the key is the payment hash.
+	//
+	// maps: witnessType -> (witnessKey -> witness)
+	witnessBucketKey = []byte("witness-cache")
+)
+
+// WitnessType is an enum that denotes which class of witness is being
+// stored within, or retrieved from, the WitnessCache.
+type WitnessType uint8
+
+const (
+	// NOTE: iota isn't used here for this enum needs to be stable
+	// long-term as it will be persisted to the database.
+
+	// Sha256HashWitness is a witness that is simply the pre image to a
+	// hash image. In order to map to this specific witness type, the
+	// witness key is the sha256 of the witness itself.
+	Sha256HashWitness WitnessType = 1
+)
+
+// toDBKey is a helper method that maps a witness type to the key of the
+// sub-bucket that houses witnesses of that type.
+func (w WitnessType) toDBKey() ([]byte, error) {
+	switch w {
+	case Sha256HashWitness:
+		return []byte{byte(w)}, nil
+
+	default:
+		return nil, ErrUnknownWitnessType
+	}
+}
+
+// WitnessCache is a persistent cache of all witnesses we've learned over the
+// course of the daemon's lifetime, such as the preimages of HTLCs settled
+// downstream of us. Witnesses are persisted so that any on-chain contracts
+// requiring them can still be resolved after a restart.
+type WitnessCache struct {
+	db *DB
+}
+
+// NewWitnessCache returns a new instance of the witness cache, backed by the
+// target database.
+func (d *DB) NewWitnessCache() *WitnessCache {
+	return &WitnessCache{
+		db: d,
+	}
+}
+
+// AddWitness adds a new witness of wType to the witness cache. The type of the
+// witness determines the key it can later be retrieved by.
+func (w *WitnessCache) AddWitness(wType WitnessType, witness []byte) error {
+	witnessTypeKey, err := wType.toDBKey()
+	if err != nil {
+		return err
+	}
+
+	var witnessKey []byte
+	switch wType {
+	case Sha256HashWitness:
+		hash := sha256.Sum256(witness)
+		witnessKey = hash[:]
+	}
+
+	return w.db.Update(func(tx *bolt.Tx) error {
+		witnessBucket, err := tx.CreateBucketIfNotExists(witnessBucketKey)
+		if err != nil {
+			return err
+		}
+		witnessTypeBucket, err := witnessBucket.CreateBucketIfNotExists(
+			witnessTypeKey,
+		)
+		if err != nil {
+			return err
+		}
+
+		return witnessTypeBucket.Put(witnessKey, witness)
+	})
+}
+
+// LookupWitness attempts to lookup a witness of wType by its witnessKey. If
+// the witness isn't found within the cache, then ErrNoWitnesses is returned.
+func (w *WitnessCache) LookupWitness(wType WitnessType,
+	witnessKey []byte) ([]byte, error) {
+
+	witnessTypeKey, err := wType.toDBKey()
+	if err != nil {
+		return nil, err
+	}
+
+	var witness []byte
+	err = w.db.View(func(tx *bolt.Tx) error {
+		witnessBucket := tx.Bucket(witnessBucketKey)
+		if witnessBucket == nil {
+			return ErrNoWitnesses
+		}
+		witnessTypeBucket := witnessBucket.Bucket(witnessTypeKey)
+		if witnessTypeBucket == nil {
+			return ErrNoWitnesses
+		}
+
+		dbWitness := witnessTypeBucket.Get(witnessKey)
+		if dbWitness == nil {
+			return ErrNoWitnesses
+		}
+
+		witness = make([]byte, len(dbWitness))
+		copy(witness, dbWitness)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return witness, nil
+}
+
+// DeleteWitness deletes the witness of wType identified by witnessKey from the
+// cache. Deleting a witness that isn't present within the cache is a noop.
+func (w *WitnessCache) DeleteWitness(wType WitnessType, witnessKey []byte) error {
+	witnessTypeKey, err := wType.toDBKey()
+	if err != nil {
+		return err
+	}
+
+	return w.db.Update(func(tx *bolt.Tx) error {
+		witnessBucket := tx.Bucket(witnessBucketKey)
+		if witnessBucket == nil {
+			return nil
+		}
+		witnessTypeBucket := witnessBucket.Bucket(witnessTypeKey)
+		if witnessTypeBucket == nil {
+			return nil
+		}
+
+		return witnessTypeBucket.Delete(witnessKey)
+	})
+}
@@ -0,0 +1,57 @@
+package channeldb
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+)
+
+// TestWitnessCacheRetrieval tests that we're able to add, lookup, and delete
+// witnesses within the witness cache.
+func TestWitnessCacheRetrieval(t *testing.T) {
+	t.Parallel()
+
+	cdb, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+
+	wCache := cdb.NewWitnessCache()
+
+	preimage := bytes.Repeat([]byte{1}, 32)
+	hash := sha256.Sum256(preimage)
+
+	// Looking up a witness before any have been added should fail.
+	_, err = wCache.LookupWitness(Sha256HashWitness, hash[:])
+	if err != ErrNoWitnesses {
+		t.Fatalf("expected ErrNoWitnesses, got %v", err)
+	}
+
+	// Once added, we should be able to retrieve the preimage by its hash.
+	if err := wCache.AddWitness(Sha256HashWitness, preimage); err != nil {
+		t.Fatalf("unable to add witness: %v", err)
+	}
+	dbPreimage, err := wCache.LookupWitness(Sha256HashWitness, hash[:])
+	if err != nil {
+		t.Fatalf("unable to lookup witness: %v", err)
+	}
+	if !bytes.Equal(preimage, dbPreimage) {
+		t.Fatalf("witnesses don't match: expected %x, got %x",
+			preimage, dbPreimage)
+	}
+
+	// After deleting the witness, it should no longer be found.
+	if err := wCache.DeleteWitness(Sha256HashWitness, hash[:]); err != nil {
+		t.Fatalf("unable to delete witness: %v", err)
+	}
+	_, err = wCache.LookupWitness(Sha256HashWitness, hash[:])
+	if err != ErrNoWitnesses {
+		t.Fatalf("expected ErrNoWitnesses, got %v", err)
+	}
+
+	// Finally, operating on an unknown witness type should fail.
+	if err := wCache.AddWitness(WitnessType(99), preimage); err != ErrUnknownWitnessType {
+		t.Fatalf("expected ErrUnknownWitnessType, got %v", err)
+	}
+}
@@ -0,0 +1,213 @@
+package channeldb
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/boltdb/bolt"
+)
+
+var (
+	// zombieIndexBucket is a sub-bucket nested within the edgeBucket which
+	// houses the set of zombie channels. A channel is considered a zombie
+	// once neither of its directed edges has been updated for a long
+	// period of time, indicating that the channel is likely unusable even
+	// though it remains open on-chain. Zombie channels are excluded from
+	// path finding and aren't relayed to peers, but remain within the
+	// graph so they can be resurrected if a fresh update arrives. The
+	// value is the time of the most recent update for the channel at the
+	// point it was marked as a zombie.
+	//
+	// maps: chanID -> lastUpdateUnix
+	zombieIndexBucket = []byte("zombie-index")
+)
+
+// MarkZombieEdges adds every channel whose most recent edge update took place
+// before the staleBefore time to the zombie index. Channels for which no edge
+// updates have been received, or which involve the source node, are never
+// marked as zombies. The channel IDs of all newly marked zombie channels are
+// returned.
+func (c *ChannelGraph) MarkZombieEdges(staleBefore time.Time) ([]uint64, error) {
+	var newZombies []uint64
+	err := c.db.Update(func(tx *bolt.Tx) error {
+		edges, err := tx.CreateBucketIfNotExists(edgeBucket)
+		if err != nil {
+			return err
+		}
+		edgeIndex, err := edges.CreateBucketIfNotExists(edgeIndexBucket)
+		if err != nil {
+			return err
+		}
+		zombieIndex, err := edges.CreateBucketIfNotExists(zombieIndexBucket)
+		if err != nil {
+			return err
+		}
+		nodes := tx.Bucket(nodeBucket)
+		if nodes == nil {
+			return ErrGraphNotFound
+		}
+		sourcePub := nodes.Get(sourceKey)
+
+		// We'll first gather the channels to be marked, as the edge
+		// index can't be modified while iterating over it.
+		var zombieKeys [][]byte
+		var zombieUpdates []time.Time
+		err = edgeIndex.ForEach(func(chanID, edgeInfo []byte) error {
+			if zombieIndex.Get(chanID) != nil {
+				return nil
+			}
+
+			// Our own channels are never considered zombies.
+			if sourcePub != nil &&
+				(bytes.Equal(edgeInfo[:33], sourcePub) ||
+					bytes.Equal(edgeInfo[33:66], sourcePub)) {
+
+				return nil
+			}
+
+			edge1, edge2, err := fetchChanEdgePolicies(
+				edgeIndex, edges, nodes, chanID, c.db,
+			)
+			if err != nil {
+				return err
+			}
+
+			var lastUpdate time.Time
+			if edge1 != nil {
+				lastUpdate = edge1.LastUpdate
+			}
+			if edge2 != nil && edge2.LastUpdate.After(lastUpdate) {
+				lastUpdate = edge2.LastUpdate
+			}
+			if lastUpdate.IsZero() || !lastUpdate.Before(staleBefore) {
+				return nil
+			}
+
+			key := make([]byte, len(chanID))
+			copy(key, chanID)
+			zombieKeys = append(zombieKeys, key)
+			zombieUpdates = append(zombieUpdates, lastUpdate)
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for i, chanID := range zombieKeys {
+			var lastUpdate [8]byte
+			byteOrder.PutUint64(
+				lastUpdate[:], uint64(zombieUpdates[i].Unix()),
+			)
+			if err := zombieIndex.Put(chanID, lastUpdate[:]); err != nil {
+				return err
+			}
+
+			newZombies = append(newZombies, byteOrder.Uint64(chanID))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newZombies, nil
+}
+
+// IsZombieEdge returns true if the channel identified by the passed channel ID
+// is currently marked as a zombie.
+func (c *ChannelGraph) IsZombieEdge(chanID uint64) (bool, error) {
+	var isZombie bool
+	err := c.db.View(func(tx *bolt.Tx) error {
+		edges := tx.Bucket(edgeBucket)
+		if edges == nil {
+			return nil
+		}
+		zombieIndex := edges.Bucket(zombieIndexBucket)
+		if zombieIndex == nil {
+			return nil
+		}
+
+		var k [8]byte
+		byteOrder.PutUint64(k[:], chanID)
+		isZombie = zombieIndex.Get(k[:]) != nil
+
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return isZombie, nil
+}
+
+// FetchZombieEdges returns the set of channel IDs of all channels which are
+// currently marked as zombies. An optional transaction may be provided. If
+// none is provided, then a new one will be created.
+func (c *ChannelGraph) FetchZombieEdges(
+	tx *bolt.Tx) (map[uint64]struct{}, error) {
+
+	zombies := make(map[uint64]struct{})
+	fetchZombies := func(tx *bolt.Tx) error {
+		edges := tx.Bucket(edgeBucket)
+		if edges == nil {
+			return nil
+		}
+		zombieIndex := edges.Bucket(zombieIndexBucket)
+		if zombieIndex == nil {
+			return nil
+		}
+
+		return zombieIndex.ForEach(func(k, _ []byte) error {
+			zombies[byteOrder.Uint64(k)] = struct{}{}
+			return nil
+		})
+	}
+
+	var err error
+	if tx == nil {
+		err = c.db.View(fetchZombies)
+	} else {
+		err = fetchZombies(tx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return zombies, nil
+}
+
+// resurrectZombieEdge removes the channel from the zombie index if the passed
+// update is more recent than the last update known for the channel at the
+// point it was marked as a zombie.
+func resurrectZombieEdge(edges *bolt.Bucket, chanID []byte,
+	update time.Time) error {
+
+	zombieIndex := edges.Bucket(zombieIndexBucket)
+	if zombieIndex == nil {
+		return nil
+	}
+
+	lastUpdate := zombieIndex.Get(chanID)
+	if lastUpdate == nil {
+		return nil
+	}
+
+	zombieSince := time.Unix(int64(byteOrder.Uint64(lastUpdate)), 0)
+	if !update.After(zombieSince) {
+		return nil
+	}
+
+	return zombieIndex.Delete(chanID)
+}
+
+// delZombieEdge removes the channel from the zombie index, if present.
+func delZombieEdge(edges *bolt.Bucket, chanID []byte) error {
+	zombieIndex := edges.Bucket(zombieIndexBucket)
+	if zombieIndex == nil {
+		return nil
+	}
+
+	return zombieIndex.Delete(chanID)
+}
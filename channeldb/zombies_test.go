@@ -0,0 +1,156 @@
+package channeldb
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/roasbeef/btcd/wire"
+)
+
+// TestZombieIndex tests that stale channels are marked as zombies, that fresh
+// updates resurrect them, and that closing a zombie channel removes it from
+// the index.
+func TestZombieIndex(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+
+	graph := db.ChannelGraph()
+
+	node1, err := createTestVertex(db)
+	if err != nil {
+		t.Fatalf("unable to create test node: %v", err)
+	}
+	if err := graph.AddLightningNode(node1); err != nil {
+		t.Fatalf("unable to add node: %v", err)
+	}
+	node2, err := createTestVertex(db)
+	if err != nil {
+		t.Fatalf("unable to create test node: %v", err)
+	}
+	if err := graph.AddLightningNode(node2); err != nil {
+		t.Fatalf("unable to add node: %v", err)
+	}
+	firstNode, secondNode := node1, node2
+	if bytes.Compare(node2.PubKey.SerializeCompressed(),
+		node1.PubKey.SerializeCompressed()) == -1 {
+
+		firstNode, secondNode = node2, node1
+	}
+
+	// We'll add two channels between the nodes, the first of which we'll
+	// leave without any edge policies.
+	var chanIDs []uint64
+	for i := uint32(0); i < 2; i++ {
+		chanID := uint64(i + 1)
+		edgeInfo := &ChannelEdgeInfo{
+			ChannelID:   chanID,
+			ChainHash:   key,
+			NodeKey1:    firstNode.PubKey,
+			NodeKey2:    secondNode.PubKey,
+			BitcoinKey1: firstNode.PubKey,
+			BitcoinKey2: secondNode.PubKey,
+			ChannelPoint: wire.OutPoint{
+				Hash:  rev,
+				Index: i,
+			},
+			Capacity: 1000,
+		}
+		if err := graph.AddChannelEdge(edgeInfo); err != nil {
+			t.Fatalf("unable to create channel edge: %v", err)
+		}
+		chanIDs = append(chanIDs, chanID)
+	}
+
+	staleTime := time.Unix(1000, 0)
+	policy := &ChannelEdgePolicy{
+		Signature:    testSig,
+		ChannelID:    chanIDs[1],
+		LastUpdate:   staleTime,
+		ChannelFlags: 0,
+		Node:         secondNode,
+		db:           db,
+	}
+	if err := graph.UpdateEdgePolicy(policy); err != nil {
+		t.Fatalf("unable to update edge: %v", err)
+	}
+
+	// With a horizon before the channel's last update, no channels should
+	// be marked.
+	zombies, err := graph.MarkZombieEdges(staleTime)
+	if err != nil {
+		t.Fatalf("unable to mark zombies: %v", err)
+	}
+	if len(zombies) != 0 {
+		t.Fatalf("expected no zombies, got %v", zombies)
+	}
+
+	// Once the horizon passes the last update, only the channel with an
+	// edge policy should be marked.
+	horizon := staleTime.Add(time.Hour)
+	zombies, err = graph.MarkZombieEdges(horizon)
+	if err != nil {
+		t.Fatalf("unable to mark zombies: %v", err)
+	}
+	if len(zombies) != 1 || zombies[0] != chanIDs[1] {
+		t.Fatalf("expected zombie %v, got %v", chanIDs[1], zombies)
+	}
+	isZombie, err := graph.IsZombieEdge(chanIDs[1])
+	if err != nil {
+		t.Fatalf("unable to query zombie index: %v", err)
+	}
+	if !isZombie {
+		t.Fatalf("channel should be a zombie")
+	}
+	zombieSet, err := graph.FetchZombieEdges(nil)
+	if err != nil {
+		t.Fatalf("unable to fetch zombies: %v", err)
+	}
+	if _, ok := zombieSet[chanIDs[1]]; !ok || len(zombieSet) != 1 {
+		t.Fatalf("unexpected zombie set: %v", zombieSet)
+	}
+
+	// Marking again shouldn't return the existing zombie.
+	zombies, err = graph.MarkZombieEdges(horizon)
+	if err != nil {
+		t.Fatalf("unable to mark zombies: %v", err)
+	}
+	if len(zombies) != 0 {
+		t.Fatalf("expected no new zombies, got %v", zombies)
+	}
+
+	// A fresh update for the channel should resurrect it.
+	policy.LastUpdate = horizon.Add(time.Hour)
+	if err := graph.UpdateEdgePolicy(policy); err != nil {
+		t.Fatalf("unable to update edge: %v", err)
+	}
+	isZombie, err = graph.IsZombieEdge(chanIDs[1])
+	if err != nil {
+		t.Fatalf("unable to query zombie index: %v", err)
+	}
+	if isZombie {
+		t.Fatalf("channel should have been resurrected")
+	}
+
+	// Mark the channel as a zombie once more, then delete it. It should
+	// no longer be found within the zombie index.
+	if _, err := graph.MarkZombieEdges(policy.LastUpdate.Add(time.Hour)); err != nil {
+		t.Fatalf("unable to mark zombies: %v", err)
+	}
+	err = graph.DeleteChannelEdge(&wire.OutPoint{Hash: rev, Index: 1})
+	if err != nil {
+		t.Fatalf("unable to delete edge: %v", err)
+	}
+	zombieSet, err = graph.FetchZombieEdges(nil)
+	if err != nil {
+		t.Fatalf("unable to fetch zombies: %v", err)
+	}
+	if len(zombieSet) != 0 {
+		t.Fatalf("expected empty zombie index, got %v", zombieSet)
+	}
+}
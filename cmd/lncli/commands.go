@@ -323,6 +323,16 @@ var openChannelCommand = cli.Command{
 			Usage: "the number of satoshis to push to the remote " +
 				"side as part of the initial commitment state",
 		},
+		cli.Int64Flag{
+			Name: "dust_limit",
+			Usage: "(optional) the dust limit in satoshis to " +
+				"propose for our commitment transaction",
+		},
+		cli.StringFlag{
+			Name: "close_address",
+			Usage: "(optional) an address to commit to paying our " +
+				"funds out to upon a cooperative close",
+		},
 		cli.BoolFlag{
 			Name:  "block",
 			Usage: "block and wait until the channel is fully open",
@@ -395,6 +405,13 @@ func openChannel(ctx *cli.Context) error {
 		}
 	}
 
+	if ctx.IsSet("dust_limit") {
+		req.DustLimit = ctx.Int64("dust_limit")
+	}
+	if ctx.IsSet("close_address") {
+		req.CloseAddress = ctx.String("close_address")
+	}
+
 	stream, err := client.OpenChannel(ctxb, req)
 	if err != nil {
 		return err
@@ -476,6 +493,11 @@ var closeChannelCommand = cli.Command{
 			Name:  "block",
 			Usage: "block until the channel is closed",
 		},
+		cli.StringFlag{
+			Name: "delivery_addr",
+			Usage: "(optional) an address to deliver our funds to " +
+				"upon a cooperative close",
+		},
 	},
 	Action: closeChannel,
 }
@@ -499,8 +521,9 @@ func closeChannel(ctx *cli.Context) error {
 
 	// TODO(roasbeef): implement time deadline within server
 	req := &lnrpc.CloseChannelRequest{
-		ChannelPoint: &lnrpc.ChannelPoint{},
-		Force:        ctx.Bool("force"),
+		ChannelPoint:    &lnrpc.ChannelPoint{},
+		Force:           ctx.Bool("force"),
+		DeliveryAddress: ctx.String("delivery_addr"),
 	}
 
 	switch {
@@ -739,6 +762,83 @@ func listChannels(ctx *cli.Context) error {
 	return nil
 }
 
+var closedChannelsCommand = cli.Command{
+	Name:  "closedchannels",
+	Usage: "list all closed channels",
+	Description: "List the channels this node was formerly a participant " +
+		"in. If none of the closure type flags are set, then channels " +
+		"of all closure types are listed.",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "cooperative",
+			Usage: "list channels that were closed cooperatively",
+		},
+		cli.BoolFlag{
+			Name:  "force",
+			Usage: "list channels that were force closed",
+		},
+		cli.BoolFlag{
+			Name: "breach",
+			Usage: "list channels where the remote party broadcast " +
+				"a revoked state",
+		},
+		cli.BoolFlag{
+			Name: "funding_canceled",
+			Usage: "list channels that never fully opened before " +
+				"being closed",
+		},
+	},
+	Action: closedChannels,
+}
+
+func closedChannels(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.ClosedChannelsRequest{
+		Cooperative:     ctx.Bool("cooperative"),
+		Force:           ctx.Bool("force"),
+		Breach:          ctx.Bool("breach"),
+		FundingCanceled: ctx.Bool("funding_canceled"),
+	}
+	resp, err := client.ClosedChannels(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var migrationStatusCommand = cli.Command{
+	Name:  "migrationstatus",
+	Usage: "display the progress of any database migrations",
+	Description: "Display the progress of any database migrations being " +
+		"applied as lnd starts up. This command is available before " +
+		"lnd has finished opening its database.",
+	Action: migrationStatus,
+}
+
+func migrationStatus(ctx *cli.Context) error {
+	ctxb := context.Background()
+	conn := getClientConn(ctx)
+	defer conn.Close()
+
+	client := lnrpc.NewDatabaseStateClient(conn)
+
+	req := &lnrpc.MigrationStatusRequest{}
+	resp, err := client.MigrationStatus(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
 var sendPaymentCommand = cli.Command{
 	Name:  "sendpayment",
 	Usage: "send a payment over lightning",
@@ -766,10 +866,80 @@ var sendPaymentCommand = cli.Command{
 			Name:  "pay_req",
 			Usage: "a zbase32-check encoded payment request to fulfill",
 		},
+		cli.Int64Flag{
+			Name: "fee_limit",
+			Usage: "the maximum total fee in satoshis to pay for the " +
+				"payment, if neither this nor --fee_limit_percent " +
+				"is set the fee is limited to the payment amount",
+		},
+		cli.Int64Flag{
+			Name: "fee_limit_percent",
+			Usage: "the maximum total fee to pay for the payment, as " +
+				"a percentage of the payment amount",
+		},
+		cli.Uint64Flag{
+			Name: "cltv_limit",
+			Usage: "the maximum total time lock delta, in blocks, of " +
+				"the routes attempted (default: 2016)",
+		},
+		cli.Uint64Flag{
+			Name: "max_parts",
+			Usage: "the maximum number of shards the payment may " +
+				"be split into, requires payment_addr " +
+				"(default: 1)",
+		},
+		cli.StringFlag{
+			Name: "payment_addr",
+			Usage: "the hex-encoded payment address of the " +
+				"invoice being paid",
+		},
+		outgoingChanIDFlag,
+		lastHopFlag,
 	},
 	Action: sendPayment,
 }
 
+// outgoingChanIDFlag and lastHopFlag restrict the routes considered by the
+// sendpayment and queryroutes commands.
+var (
+	outgoingChanIDFlag = cli.StringSliceFlag{
+		Name: "outgoing_chan_id",
+		Usage: "the id of a channel of ours that the first hop may go " +
+			"over, can be repeated to permit several channels",
+	}
+	lastHopFlag = cli.StringFlag{
+		Name: "last_hop",
+		Usage: "the hex-encoded pubkey of the node that must precede " +
+			"the destination",
+	}
+)
+
+// parseRouteRestrictions parses the outgoing channel and last hop
+// restrictions set on the command line.
+func parseRouteRestrictions(ctx *cli.Context) ([]uint64, []byte, error) {
+	var outgoingChanIDs []uint64
+	for _, chanIDStr := range ctx.StringSlice("outgoing_chan_id") {
+		chanID, err := strconv.ParseUint(chanIDStr, 10, 64)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to decode "+
+				"outgoing_chan_id: %v", err)
+		}
+		outgoingChanIDs = append(outgoingChanIDs, chanID)
+	}
+
+	var lastHop []byte
+	if ctx.IsSet("last_hop") {
+		var err error
+		lastHop, err = hex.DecodeString(ctx.String("last_hop"))
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to decode "+
+				"last_hop: %v", err)
+		}
+	}
+
+	return outgoingChanIDs, lastHop, nil
+}
+
 func sendPayment(ctx *cli.Context) error {
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
@@ -852,6 +1022,26 @@ func sendPayment(ctx *cli.Context) error {
 		}
 	}
 
+	req.FeeLimitSat = ctx.Int64("fee_limit")
+	req.FeeLimitPercent = ctx.Int64("fee_limit_percent")
+	req.CltvLimit = uint32(ctx.Uint64("cltv_limit"))
+	req.MaxParts = uint32(ctx.Uint64("max_parts"))
+
+	if ctx.IsSet("payment_addr") {
+		paymentAddr, err := hex.DecodeString(ctx.String("payment_addr"))
+		if err != nil {
+			return err
+		}
+		req.PaymentAddr = paymentAddr
+	}
+
+	outgoingChanIDs, lastHop, err := parseRouteRestrictions(ctx)
+	if err != nil {
+		return err
+	}
+	req.OutgoingChanIds = outgoingChanIDs
+	req.LastHopPubkey = lastHop
+
 	paymentStream, err := client.SendPayment(context.Background())
 	if err != nil {
 		return err
@@ -868,12 +1058,19 @@ func sendPayment(ctx *cli.Context) error {
 
 	paymentStream.CloseSend()
 
+	var failureReason string
+	if resp.PaymentError != "" {
+		failureReason = resp.PaymentFailureReason.String()
+	}
+
 	printJSON(struct {
 		E string       `json:"payment_error"`
+		F string       `json:"payment_failure_reason,omitempty"`
 		P string       `json:"payment_preimage"`
 		R *lnrpc.Route `json:"payment_route"`
 	}{
 		E: resp.PaymentError,
+		F: failureReason,
 		P: hex.EncodeToString(resp.PaymentPreimage),
 		R: resp.PaymentRoute,
 	})
@@ -900,6 +1097,12 @@ var addInvoiceCommand = cli.Command{
 			Name:  "preimage",
 			Usage: "the hex-encoded preimage (32 byte) which will allow settling an incoming HTLC payable to this preimage",
 		},
+		cli.StringFlag{
+			Name: "hash",
+			Usage: "the hex-encoded payment hash (32 byte) of a hold " +
+				"invoice, whose preimage is only provided once " +
+				"the invoice is settled through settleinvoice",
+		},
 		cli.Int64Flag{
 			Name:  "value",
 			Usage: "the value of this invoice in satoshis",
@@ -911,6 +1114,7 @@ var addInvoiceCommand = cli.Command{
 func addInvoice(ctx *cli.Context) error {
 	var (
 		preimage []byte
+		hash     []byte
 		receipt  []byte
 		value    int64
 		err      error
@@ -945,6 +1149,18 @@ func addInvoice(ctx *cli.Context) error {
 		return fmt.Errorf("unable to parse preimage: %v", err)
 	}
 
+	if ctx.IsSet("hash") {
+		if preimage != nil {
+			return fmt.Errorf("only one of preimage and hash may " +
+				"be set")
+		}
+
+		hash, err = hex.DecodeString(ctx.String("hash"))
+		if err != nil {
+			return fmt.Errorf("unable to parse hash: %v", err)
+		}
+	}
+
 	receipt, err = hex.DecodeString(ctx.String("receipt"))
 	if err != nil {
 		return fmt.Errorf("unable to parse receipt: %v", err)
@@ -954,6 +1170,7 @@ func addInvoice(ctx *cli.Context) error {
 		Memo:      ctx.String("memo"),
 		Receipt:   receipt,
 		RPreimage: preimage,
+		RHash:     hash,
 		Value:     value,
 	}
 
@@ -963,16 +1180,125 @@ func addInvoice(ctx *cli.Context) error {
 	}
 
 	printJSON(struct {
-		RHash  string `json:"r_hash"`
-		PayReq string `json:"pay_req"`
+		RHash       string             `json:"r_hash"`
+		PayReq      string             `json:"pay_req"`
+		PaymentAddr string             `json:"payment_addr"`
+		RouteHints  []*lnrpc.RouteHint `json:"route_hints,omitempty"`
 	}{
-		RHash:  hex.EncodeToString(resp.RHash),
-		PayReq: resp.PaymentRequest,
+		RHash:       hex.EncodeToString(resp.RHash),
+		PayReq:      resp.PaymentRequest,
+		PaymentAddr: hex.EncodeToString(resp.PaymentAddr),
+		RouteHints:  resp.RouteHints,
 	})
 
 	return nil
 }
 
+var settleInvoiceCommand = cli.Command{
+	Name:      "settleinvoice",
+	Usage:     "Settle an accepted hold invoice.",
+	ArgsUsage: "preimage",
+	Description: `
+	Settle the accepted hold invoice paid to the hash of the given preimage,
+	settling the HTLC held for it.`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "preimage",
+			Usage: "the hex-encoded preimage (32 byte) of the hold invoice",
+		},
+	},
+	Action: settleInvoice,
+}
+
+func settleInvoice(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	var (
+		preimage []byte
+		err      error
+	)
+
+	switch {
+	case ctx.IsSet("preimage"):
+		preimage, err = hex.DecodeString(ctx.String("preimage"))
+	case ctx.Args().Present():
+		preimage, err = hex.DecodeString(ctx.Args().First())
+	default:
+		return fmt.Errorf("preimage argument missing")
+	}
+
+	if err != nil {
+		return fmt.Errorf("unable to decode preimage argument: %v", err)
+	}
+
+	req := &lnrpc.SettleInvoiceMsg{
+		Preimage: preimage,
+	}
+
+	resp, err := client.SettleInvoice(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var cancelInvoiceCommand = cli.Command{
+	Name:      "cancelinvoice",
+	Usage:     "Cancel an invoice which hasn't been settled.",
+	ArgsUsage: "rhash",
+	Description: `
+	Cancel the invoice with the given payment hash, after which it can no
+	longer be paid. Should an HTLC be held for a hold invoice, it's failed
+	back to the payer.`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "rhash",
+			Usage: "the hex-encoded payment hash (32 byte) of the invoice",
+		},
+	},
+	Action: cancelInvoice,
+}
+
+func cancelInvoice(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	var (
+		rHash []byte
+		err   error
+	)
+
+	switch {
+	case ctx.IsSet("rhash"):
+		rHash, err = hex.DecodeString(ctx.String("rhash"))
+	case ctx.Args().Present():
+		rHash, err = hex.DecodeString(ctx.Args().First())
+	default:
+		return fmt.Errorf("rhash argument missing")
+	}
+
+	if err != nil {
+		return fmt.Errorf("unable to decode rhash argument: %v", err)
+	}
+
+	req := &lnrpc.CancelInvoiceMsg{
+		PaymentHash: rHash,
+	}
+
+	resp, err := client.CancelInvoice(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
 var lookupInvoiceCommand = cli.Command{
 	Name:      "lookupinvoice",
 	Usage:     "Lookup an existing invoice by its payment hash.",
@@ -1243,8 +1569,33 @@ func drawChannelGraph(graph *lnrpc.ChannelGraph) error {
 }
 
 var listPaymentsCommand = cli.Command{
-	Name:   "listpayments",
-	Usage:  "list all outgoing payments",
+	Name:  "listpayments",
+	Usage: "list outgoing payments",
+	Description: "Lists the outgoing payments, oldest first. By default " +
+		"only the payments which succeeded are listed. The payments " +
+		"can be paged through by passing the first or last index " +
+		"offset of a previous response as --index_offset.",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name: "include_incomplete",
+			Usage: "also list the payments which are in flight " +
+				"or failed",
+		},
+		cli.Uint64Flag{
+			Name: "index_offset",
+			Usage: "the sequence number of the payment to start " +
+				"after, or end before if --reversed is set",
+		},
+		cli.Uint64Flag{
+			Name:  "max_payments",
+			Usage: "the maximum number of payments to list",
+		},
+		cli.BoolFlag{
+			Name: "reversed",
+			Usage: "list the payments created before the index " +
+				"offset, starting from the newest payment",
+		},
+	},
 	Action: listPayments,
 }
 
@@ -1252,7 +1603,12 @@ func listPayments(ctx *cli.Context) error {
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
-	req := &lnrpc.ListPaymentsRequest{}
+	req := &lnrpc.ListPaymentsRequest{
+		IncludeIncomplete: ctx.Bool("include_incomplete"),
+		IndexOffset:       ctx.Uint64("index_offset"),
+		MaxPayments:       ctx.Uint64("max_payments"),
+		Reversed:          ctx.Bool("reversed"),
+	}
 
 	payments, err := client.ListPayments(context.Background(), req)
 	if err != nil {
@@ -1263,6 +1619,64 @@ func listPayments(ctx *cli.Context) error {
 	return nil
 }
 
+var trackPaymentCommand = cli.Command{
+	Name:      "trackpayment",
+	Usage:     "track the progress of an outgoing payment",
+	ArgsUsage: "payment_hash",
+	Description: "Prints the current state of the payment with the " +
+		"passed payment hash, followed by its new state each time " +
+		"it changes, until the payment either succeeds or fails.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "payment_hash",
+			Usage: "the 32 byte hex-encoded payment hash of the " +
+				"payment to track",
+		},
+	},
+	Action: trackPayment,
+}
+
+func trackPayment(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	var (
+		payHash []byte
+		err     error
+	)
+
+	switch {
+	case ctx.IsSet("payment_hash"):
+		payHash, err = hex.DecodeString(ctx.String("payment_hash"))
+	case ctx.Args().Present():
+		payHash, err = hex.DecodeString(ctx.Args().First())
+	default:
+		return fmt.Errorf("payment_hash argument missing")
+	}
+
+	if err != nil {
+		return fmt.Errorf("unable to decode payment_hash: %v", err)
+	}
+
+	stream, err := client.TrackPayment(
+		context.Background(), &lnrpc.PaymentHash{RHash: payHash},
+	)
+	if err != nil {
+		return err
+	}
+
+	for {
+		payment, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		printRespJSON(payment)
+	}
+}
+
 var getChanInfoCommand = cli.Command{
 	Name:  "getchaninfo",
 	Usage: "get the state of a channel",
@@ -1370,6 +1784,20 @@ var queryRoutesCommand = cli.Command{
 			Name:  "amt",
 			Usage: "the amount to send expressed in satoshis",
 		},
+		outgoingChanIDFlag,
+		lastHopFlag,
+		cli.StringSliceFlag{
+			Name: "ignore_node",
+			Usage: "the hex-encoded pubkey of a node the routes may " +
+				"not go through, can be repeated",
+		},
+		cli.StringSliceFlag{
+			Name: "ignore_edge",
+			Usage: "a directed edge the routes may not traverse, " +
+				"as chan_id:direction where a direction of 1 " +
+				"leads from the node with the greater pubkey " +
+				"to the other, can be repeated",
+		},
 	},
 	Action: queryRoutes,
 }
@@ -1409,9 +1837,46 @@ func queryRoutes(ctx *cli.Context) error {
 		return fmt.Errorf("amt argument missing")
 	}
 
+	outgoingChanIDs, lastHop, err := parseRouteRestrictions(ctx)
+	if err != nil {
+		return err
+	}
+
+	var ignoredNodes [][]byte
+	for _, nodeStr := range ctx.StringSlice("ignore_node") {
+		node, err := hex.DecodeString(nodeStr)
+		if err != nil {
+			return fmt.Errorf("unable to decode ignore_node: %v",
+				err)
+		}
+		ignoredNodes = append(ignoredNodes, node)
+	}
+
+	var ignoredEdges []*lnrpc.EdgeLocator
+	for _, edgeStr := range ctx.StringSlice("ignore_edge") {
+		parts := strings.Split(edgeStr, ":")
+		if len(parts) != 2 || (parts[1] != "0" && parts[1] != "1") {
+			return fmt.Errorf("ignore_edge must be of the form " +
+				"chan_id:direction")
+		}
+		chanID, err := strconv.ParseUint(parts[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("unable to decode ignore_edge: %v",
+				err)
+		}
+		ignoredEdges = append(ignoredEdges, &lnrpc.EdgeLocator{
+			ChannelId:        chanID,
+			DirectionReverse: parts[1] == "1",
+		})
+	}
+
 	req := &lnrpc.QueryRoutesRequest{
-		PubKey: dest,
-		Amt:    amt,
+		PubKey:          dest,
+		Amt:             amt,
+		OutgoingChanIds: outgoingChanIDs,
+		LastHopPubkey:   lastHop,
+		IgnoredNodes:    ignoredNodes,
+		IgnoredEdges:    ignoredEdges,
 	}
 
 	route, err := client.QueryRoutes(ctxb, req)
@@ -1423,36 +1888,160 @@ func queryRoutes(ctx *cli.Context) error {
 	return nil
 }
 
-var getNetworkInfoCommand = cli.Command{
-	Name:  "getnetworkinfo",
-	Usage: "getnetworkinfo",
-	Description: "returns a set of statistics pertaining to the known channel " +
-		"graph",
-	Action: getNetworkInfo,
+var buildRouteCommand = cli.Command{
+	Name:  "buildroute",
+	Usage: "Build a route over a manually selected sequence of hops.",
+	Description: "Builds a fully specified route to the last of the passed " +
+		"hops, traversing each of them in order, with the fees, time " +
+		"locks and channels filled in from the local graph",
+	ArgsUsage: "--amt=X --hops=pubkey1,pubkey2,...",
+	Flags: []cli.Flag{
+		cli.Int64Flag{
+			Name:  "amt",
+			Usage: "the amount to send expressed in satoshis",
+		},
+		cli.StringFlag{
+			Name: "hops",
+			Usage: "a comma separated list of the 33-byte hex-encoded " +
+				"public keys of the hops to traverse, with the " +
+				"last being the payment destination",
+		},
+	},
+	Action: buildRoute,
 }
 
-func getNetworkInfo(ctx *cli.Context) error {
+func buildRoute(ctx *cli.Context) error {
 	ctxb := context.Background()
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
-	req := &lnrpc.NetworkInfoRequest{}
+	if !ctx.IsSet("amt") {
+		return fmt.Errorf("amt argument missing")
+	}
+	if !ctx.IsSet("hops") {
+		return fmt.Errorf("hops argument missing")
+	}
 
-	netInfo, err := client.GetNetworkInfo(ctxb, req)
+	req := &lnrpc.BuildRouteRequest{
+		Amt:        ctx.Int64("amt"),
+		HopPubkeys: strings.Split(ctx.String("hops"), ","),
+	}
+
+	route, err := client.BuildRoute(ctxb, req)
 	if err != nil {
 		return err
 	}
 
-	printRespJSON(netInfo)
+	printRespJSON(route)
 	return nil
 }
 
-var debugLevelCommand = cli.Command{
-	Name:        "debuglevel",
-	Usage:       "Set the debug level.",
-	Description: "Logging level for all subsystems {trace, debug, info, warn, error, critical} -- You may also specify <subsystem>=<level>,<subsystem2>=<level>,... to set the log level for individual subsystems -- Use show to list available subsystems",
+var sendToRouteCommand = cli.Command{
+	Name:  "sendtoroute",
+	Usage: "Send a payment over a predefined route.",
+	Description: "Sends a payment over the passed route, as returned by " +
+		"buildroute or queryroutes. Only a single attempt is made. If " +
+		"the payment fails, then the node along the route that " +
+		"caused the failure is reported, along with its failure " +
+		"message. The route may be passed as JSON, or read from " +
+		"stdin by passing '-', e.g.: lncli buildroute ... | lncli " +
+		"sendtoroute --payment_hash=<hash> --route=-. If the route " +
+		"leads back to our own node, then the payment hash may be " +
+		"omitted, in which case the payment is settled by our own " +
+		"node, shifting the balance from the first channel of the " +
+		"route to the last, e.g.: lncli buildroute --amt=X " +
+		"--hops=<peer1>,<peer2>,<own pubkey> | lncli sendtoroute " +
+		"--route=-",
+	ArgsUsage: "[--payment_hash=H] --route=R",
 	Flags: []cli.Flag{
-		cli.BoolFlag{
+		cli.StringFlag{
+			Name: "payment_hash",
+			Usage: "the hash to use within the payment's HTLC, " +
+				"which may be omitted for circular payments",
+		},
+		cli.StringFlag{
+			Name: "route",
+			Usage: "the JSON encoded route to send the payment over, " +
+				"or '-' to read it from stdin",
+		},
+	},
+	Action: sendToRoute,
+}
+
+func sendToRoute(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	if !ctx.IsSet("route") {
+		return fmt.Errorf("route argument missing")
+	}
+
+	routeJSON := ctx.String("route")
+	if routeJSON == "-" {
+		b, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("unable to read route from stdin: %v",
+				err)
+		}
+		routeJSON = string(b)
+	}
+
+	// The route may either be passed on its own, or wrapped within the
+	// response of buildroute.
+	route := &lnrpc.Route{}
+	buildResp := &lnrpc.BuildRouteResponse{}
+	err := jsonpb.UnmarshalString(routeJSON, buildResp)
+	if err == nil && buildResp.Route != nil {
+		route = buildResp.Route
+	} else if err := jsonpb.UnmarshalString(routeJSON, route); err != nil {
+		return fmt.Errorf("unable to decode route: %v", err)
+	}
+
+	req := &lnrpc.SendToRouteRequest{
+		PaymentHashString: ctx.String("payment_hash"),
+		Route:             route,
+	}
+
+	resp, err := client.SendToRouteSync(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var getNetworkInfoCommand = cli.Command{
+	Name:  "getnetworkinfo",
+	Usage: "getnetworkinfo",
+	Description: "returns a set of statistics pertaining to the known channel " +
+		"graph",
+	Action: getNetworkInfo,
+}
+
+func getNetworkInfo(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.NetworkInfoRequest{}
+
+	netInfo, err := client.GetNetworkInfo(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(netInfo)
+	return nil
+}
+
+var debugLevelCommand = cli.Command{
+	Name:        "debuglevel",
+	Usage:       "Set the debug level.",
+	Description: "Logging level for all subsystems {trace, debug, info, warn, error, critical} -- You may also specify <subsystem>=<level>,<subsystem2>=<level>,... to set the log level for individual subsystems -- Use show to list available subsystems",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
 			Name:  "show",
 			Usage: "if true, then the list of available sub-systems will be printed out",
 		},
@@ -1669,10 +2258,11 @@ func verifyMessage(ctx *cli.Context) error {
 
 var feeReportCommand = cli.Command{
 	Name:  "feereport",
-	Usage: "display the current fee policies of all active channels",
+	Usage: "display the current fee policies and earnings of all active channels",
 	Description: "Returns the current fee policies of all active " +
-		"channels. Fee policies can be updated using the " +
-		"updateFees command. ",
+		"channels, along with the fees earned by forwarding " +
+		"payments over the past day, week, and month. Fee " +
+		"policies can be updated using the updateFees command. ",
 	Action: feeReport,
 }
 
@@ -1774,25 +2364,10 @@ func updateFees(ctx *cli.Context) error {
 	}
 
 	if chanPointStr != "" {
-		split := strings.Split(chanPointStr, ":")
-		if len(split) != 2 {
-			return fmt.Errorf("expecting chan_point to be in format of: " +
-				"txid:index")
-		}
-
-		txHash, err := chainhash.NewHashFromStr(split[0])
+		chanPoint, err = parseChanPoint(chanPointStr)
 		if err != nil {
 			return err
 		}
-		index, err := strconv.ParseInt(split[1], 10, 32)
-		if err != nil {
-			return fmt.Errorf("unable to decode output index: %v", err)
-		}
-
-		chanPoint = &lnrpc.ChannelPoint{
-			FundingTxid: txHash[:],
-			OutputIndex: uint32(index),
-		}
 	}
 
 	req := &lnrpc.FeeUpdateRequest{
@@ -1818,3 +2393,754 @@ func updateFees(ctx *cli.Context) error {
 	printRespJSON(resp)
 	return nil
 }
+
+// parseChanPoint parses a channel point encoded as funding_txid:output_index.
+func parseChanPoint(chanPointStr string) (*lnrpc.ChannelPoint, error) {
+	split := strings.Split(chanPointStr, ":")
+	if len(split) != 2 {
+		return nil, fmt.Errorf("expecting chan_point to be in format " +
+			"of: txid:index")
+	}
+
+	txHash, err := chainhash.NewHashFromStr(split[0])
+	if err != nil {
+		return nil, err
+	}
+	index, err := strconv.ParseInt(split[1], 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode output index: %v", err)
+	}
+
+	return &lnrpc.ChannelPoint{
+		FundingTxid: txHash[:],
+		OutputIndex: uint32(index),
+	}, nil
+}
+
+var updateChannelPolicyCommand = cli.Command{
+	Name:  "updatechanpolicy",
+	Usage: "update the forwarding policy for all channels, or a single channel",
+	ArgsUsage: "base_fee_msat fee_rate [--time_lock_delta=X] " +
+		"[--min_htlc_msat=X] [--max_htlc_msat=X] [--chan_point=X]",
+	Description: `Updates the forwarding policy for all channels, or just a
+		particular channel identified by its channel point. Besides the
+		fees, the time lock delta and HTLC limits of the channels may be
+		updated, and are left unchanged if omitted. The new policy is
+		applied to the forwarding links of the channels immediately, and
+		broadcast to the rest of the network within the next batch.
+		Channel points are encoded as: funding_txid:output_index`,
+	Flags: []cli.Flag{
+		cli.Int64Flag{
+			Name: "base_fee_msat",
+			Usage: "the base fee in milli-atoms that will be " +
+				"charged for each forwarded HTLC, regardless " +
+				"of payment size",
+		},
+		cli.Float64Flag{
+			Name: "fee_rate",
+			Usage: "the fee rate that will be charged " +
+				"proportionally based on the value of each " +
+				"forwarded HTLC, the lowest possible rate is 0.000001",
+		},
+		cli.Uint64Flag{
+			Name: "time_lock_delta",
+			Usage: "the number of blocks the time lock of forwarded " +
+				"HTLCs must be reduced by",
+		},
+		cli.Int64Flag{
+			Name:  "min_htlc_msat",
+			Usage: "the smallest HTLC in milli-atoms that will be forwarded",
+		},
+		cli.Int64Flag{
+			Name: "max_htlc_msat",
+			Usage: "the largest HTLC in milli-atoms that will be " +
+				"forwarded, which may not exceed the capacity of " +
+				"the channel",
+		},
+		cli.StringFlag{
+			Name: "chan_point",
+			Usage: "the channel whose policy should be updated, if " +
+				"nil the policies for all channels will be " +
+				"updated. Takes the form of: txid:output_index",
+		},
+	},
+	Action: updateChannelPolicy,
+}
+
+func updateChannelPolicy(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	var (
+		baseFee int64
+		feeRate float64
+		err     error
+	)
+	args := ctx.Args()
+
+	switch {
+	case ctx.IsSet("base_fee_msat"):
+		baseFee = ctx.Int64("base_fee_msat")
+	case args.Present():
+		baseFee, err = strconv.ParseInt(args.First(), 10, 64)
+		if err != nil {
+			return fmt.Errorf("unable to decode base_fee_msat: %v", err)
+		}
+		args = args.Tail()
+	default:
+		return fmt.Errorf("base_fee_msat argument missing")
+	}
+
+	switch {
+	case ctx.IsSet("fee_rate"):
+		feeRate = ctx.Float64("fee_rate")
+	case args.Present():
+		feeRate, err = strconv.ParseFloat(args.First(), 64)
+		if err != nil {
+			return fmt.Errorf("unable to decode fee_rate: %v", err)
+		}
+	default:
+		return fmt.Errorf("fee_rate argument missing")
+	}
+
+	req := &lnrpc.PolicyUpdateRequest{
+		BaseFeeMsat:   baseFee,
+		FeeRate:       feeRate,
+		TimeLockDelta: uint32(ctx.Uint64("time_lock_delta")),
+		MinHtlcMsat:   ctx.Int64("min_htlc_msat"),
+		MaxHtlcMsat:   ctx.Int64("max_htlc_msat"),
+	}
+
+	if ctx.IsSet("chan_point") {
+		req.ChanPoint, err = parseChanPoint(ctx.String("chan_point"))
+		if err != nil {
+			return err
+		}
+	} else {
+		req.Global = true
+	}
+
+	resp, err := client.UpdateChannelPolicy(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var listBlacklistCommand = cli.Command{
+	Name:  "listblacklist",
+	Usage: "list all nodes within the node blacklist",
+	Description: "Returns the set of nodes which will never be used as " +
+		"an intermediate hop when routing payments. The blacklist " +
+		"can be modified using the updateblacklist command.",
+	Action: listBlacklist,
+}
+
+func listBlacklist(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.ListBlacklistRequest{}
+	resp, err := client.ListBlacklist(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var updateBlacklistCommand = cli.Command{
+	Name:  "updateblacklist",
+	Usage: "add or remove nodes from the node blacklist",
+	Description: ` Adds nodes to, or removes nodes from the persistent node
+	blacklist. Blacklisted nodes will never be used as an intermediate hop
+	when routing payments. If --avoid_dest is set, then the added nodes
+	will also never be used as the destination of a payment.`,
+	Flags: []cli.Flag{
+		cli.StringSliceFlag{
+			Name:  "add",
+			Usage: "the hex-encoded pubkey of a node to blacklist",
+		},
+		cli.StringSliceFlag{
+			Name: "remove",
+			Usage: "the hex-encoded pubkey of a node to remove " +
+				"from the blacklist",
+		},
+		cli.BoolFlag{
+			Name: "avoid_dest",
+			Usage: "if set, then the added nodes will also " +
+				"never be used as the destination of a payment",
+		},
+	},
+	Action: updateBlacklist,
+}
+
+func updateBlacklist(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	if !ctx.IsSet("add") && !ctx.IsSet("remove") {
+		return fmt.Errorf("at least one of --add or --remove must be set")
+	}
+
+	req := &lnrpc.UpdateBlacklistRequest{
+		Remove: ctx.StringSlice("remove"),
+	}
+	for _, pubKey := range ctx.StringSlice("add") {
+		req.Add = append(req.Add, &lnrpc.BlacklistedNode{
+			PubKey:             pubKey,
+			AvoidAsDestination: ctx.Bool("avoid_dest"),
+		})
+	}
+
+	resp, err := client.UpdateBlacklist(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var getCommitmentTxnsCommand = cli.Command{
+	Name:  "getcommitmenttxns",
+	Usage: "Get the signed commitment and HTLC transactions of a channel.",
+	Description: "Returns our current fully signed commitment transaction " +
+		"for the target channel, along with the fully signed HTLC " +
+		"timeout transactions spending from it. These are the " +
+		"transactions which would be broadcast if the channel were " +
+		"force closed. Nothing is broadcast by this command.",
+	ArgsUsage: "funding_txid [output_index]",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "funding_txid",
+			Usage: "the txid of the channel's funding transaction",
+		},
+		cli.IntFlag{
+			Name: "output_index",
+			Usage: "the output index for the funding output of the funding " +
+				"transaction",
+		},
+	},
+	Action: getCommitmentTxns,
+}
+
+func getCommitmentTxns(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	args := ctx.Args()
+	var txid string
+
+	// Show command help if no arguments provieded
+	if ctx.NArg() == 0 && ctx.NumFlags() == 0 {
+		cli.ShowCommandHelp(ctx, "getcommitmenttxns")
+		return nil
+	}
+
+	switch {
+	case ctx.IsSet("funding_txid"):
+		txid = ctx.String("funding_txid")
+	case args.Present():
+		txid = args.First()
+		args = args.Tail()
+	default:
+		return fmt.Errorf("funding txid argument missing")
+	}
+
+	txidhash, err := chainhash.NewHashFromStr(txid)
+	if err != nil {
+		return err
+	}
+	req := &lnrpc.CommitmentTxnsRequest{
+		ChannelPoint: &lnrpc.ChannelPoint{
+			FundingTxid: txidhash[:],
+		},
+	}
+
+	switch {
+	case ctx.IsSet("output_index"):
+		req.ChannelPoint.OutputIndex = uint32(ctx.Int("output_index"))
+	case args.Present():
+		index, err := strconv.ParseInt(args.First(), 10, 32)
+		if err != nil {
+			return fmt.Errorf("unable to decode output index: %v", err)
+		}
+		req.ChannelPoint.OutputIndex = uint32(index)
+	}
+
+	resp, err := client.GetCommitmentTxns(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var exportChannelDBCommand = cli.Command{
+	Name:  "exportchanneldb",
+	Usage: "Export a snapshot of the channel database to a file.",
+	Description: "Writes a consistent snapshot of the channel database, " +
+		"taken while lnd is running, to the target file. The file " +
+		"can be restored in place of channel.db while lnd is stopped.",
+	ArgsUsage: "output_file",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "output_file",
+			Usage: "the file to write the snapshot to, which mustn't exist",
+		},
+	},
+	Action: exportChannelDB,
+}
+
+func exportChannelDB(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	var outputFile string
+	switch {
+	case ctx.IsSet("output_file"):
+		outputFile = ctx.String("output_file")
+	case ctx.Args().Present():
+		outputFile = ctx.Args().First()
+	default:
+		cli.ShowCommandHelp(ctx, "exportchanneldb")
+		return nil
+	}
+
+	// We'll write the snapshot to a temporary file first, only moving it
+	// into place once it has been received in its entirety.
+	tempFile := outputFile + ".tmp"
+	if _, err := os.Stat(outputFile); err == nil {
+		return fmt.Errorf("output file %v already exists", outputFile)
+	}
+	f, err := os.OpenFile(tempFile, os.O_WRONLY|os.O_CREATE|os.O_EXCL,
+		0600)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tempFile)
+
+	stream, err := client.ExportChannelDB(
+		ctxb, &lnrpc.ExportChannelDBRequest{},
+	)
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	var n int64
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			f.Close()
+			return err
+		}
+
+		if _, err := f.Write(chunk.Chunk); err != nil {
+			f.Close()
+			return err
+		}
+		n += int64(len(chunk.Chunk))
+	}
+
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tempFile, outputFile); err != nil {
+		return err
+	}
+
+	printJSON(struct {
+		File  string `json:"output_file"`
+		Bytes int64  `json:"bytes_written"`
+	}{
+		File:  outputFile,
+		Bytes: n,
+	})
+	return nil
+}
+
+var sendCustomCommand = cli.Command{
+	Name:  "sendcustom",
+	Usage: "Send a custom peer message to a connected peer.",
+	Description: "Sends a message of the given type, which must be at " +
+		"least 32768, with the given hex encoded data to a connected " +
+		"peer. The data isn't interpreted by lnd.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "peer",
+			Usage: "the identity pubkey of the peer to send the message to",
+		},
+		cli.Uint64Flag{
+			Name:  "type",
+			Usage: "the message type",
+		},
+		cli.StringFlag{
+			Name:  "data",
+			Usage: "the hex encoded payload of the message",
+		},
+	},
+	Action: sendCustom,
+}
+
+func sendCustom(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	if !ctx.IsSet("peer") || !ctx.IsSet("type") {
+		cli.ShowCommandHelp(ctx, "sendcustom")
+		return nil
+	}
+
+	peer, err := hex.DecodeString(ctx.String("peer"))
+	if err != nil {
+		return fmt.Errorf("unable to decode peer pubkey: %v", err)
+	}
+	data, err := hex.DecodeString(ctx.String("data"))
+	if err != nil {
+		return fmt.Errorf("unable to decode data: %v", err)
+	}
+
+	resp, err := client.SendCustomMessage(
+		ctxb, &lnrpc.SendCustomMessageRequest{
+			Peer: peer,
+			Type: uint32(ctx.Uint64("type")),
+			Data: data,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var subscribeCustomCommand = cli.Command{
+	Name:  "subscribecustom",
+	Usage: "Print the custom peer messages received from any peer.",
+	Description: "Prints each custom peer message received from any " +
+		"connected peer until interrupted.",
+	Action: subscribeCustom,
+}
+
+func subscribeCustom(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	stream, err := client.SubscribeCustomMessages(
+		ctxb, &lnrpc.SubscribeCustomMessagesRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		printJSON(struct {
+			Peer string `json:"peer"`
+			Type uint32 `json:"type"`
+			Data string `json:"data"`
+		}{
+			Peer: hex.EncodeToString(msg.Peer),
+			Type: msg.Type,
+			Data: hex.EncodeToString(msg.Data),
+		})
+	}
+}
+
+var subscribeHtlcEventsCommand = cli.Command{
+	Name:  "subscribehtlcevents",
+	Usage: "Print the HTLC events of the switch.",
+	Description: "Prints an event each time an HTLC is forwarded, fails " +
+		"to be forwarded, is failed by a channel link, or is " +
+		"resolved, until interrupted.",
+	Action: subscribeHtlcEvents,
+}
+
+func subscribeHtlcEvents(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	stream, err := client.SubscribeHtlcEvents(
+		ctxb, &lnrpc.SubscribeHtlcEventsRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	for {
+		event, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		printJSON(struct {
+			EventType       string `json:"event_type"`
+			PaymentHash     string `json:"payment_hash"`
+			IncomingChanID  uint64 `json:"incoming_chan_id"`
+			OutgoingChanID  uint64 `json:"outgoing_chan_id"`
+			IncomingAmtMsat int64  `json:"incoming_amt_msat"`
+			OutgoingAmtMsat int64  `json:"outgoing_amt_msat"`
+			TimestampNs     uint64 `json:"timestamp_ns"`
+			FailureCode     uint32 `json:"failure_code"`
+			FailureDetail   string `json:"failure_detail"`
+		}{
+			EventType:       event.EventType.String(),
+			PaymentHash:     hex.EncodeToString(event.PaymentHash),
+			IncomingChanID:  event.IncomingChanId,
+			OutgoingChanID:  event.OutgoingChanId,
+			IncomingAmtMsat: event.IncomingAmtMsat,
+			OutgoingAmtMsat: event.OutgoingAmtMsat,
+			TimestampNs:     event.TimestampNs,
+			FailureCode:     event.FailureCode,
+			FailureDetail:   event.FailureDetail,
+		})
+	}
+}
+
+var queryMissionControlCommand = cli.Command{
+	Name:  "querymc",
+	Usage: "query the internal mission control state",
+	Description: "Returns the history of past payment attempts recorded " +
+		"by mission control for each node and directed pair of " +
+		"nodes. Nodes and pairs that recently caused payments to " +
+		"fail are penalized during path finding.",
+	Action: queryMissionControl,
+}
+
+func queryMissionControl(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.QueryMissionControlRequest{}
+	resp, err := client.QueryMissionControl(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var resetMissionControlCommand = cli.Command{
+	Name:  "resetmc",
+	Usage: "reset the internal mission control state",
+	Description: "Wipes the history of past payment attempts recorded " +
+		"by mission control, lifting all penalties resulting from " +
+		"past payment failures.",
+	Action: resetMissionControl,
+}
+
+func resetMissionControl(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.ResetMissionControlRequest{}
+	_, err := client.ResetMissionControl(ctxb, req)
+	return err
+}
+
+var queryProbabilityCommand = cli.Command{
+	Name:  "queryprob",
+	Usage: "estimate the success probability of forwarding a payment",
+	Description: "Returns the estimated probability of a payment of the " +
+		"passed amount being successfully forwarded from one node to " +
+		"another, as used by path finding, along with the mission " +
+		"control history of the pair and the receiving node the " +
+		"estimate is based on.",
+	ArgsUsage: "from_node to_node amt_msat",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "from_node",
+			Usage: "the 33-byte hex-encoded public key of the node " +
+				"forwarding the payment",
+		},
+		cli.StringFlag{
+			Name: "to_node",
+			Usage: "the 33-byte hex-encoded public key of the node " +
+				"receiving the payment",
+		},
+		cli.Int64Flag{
+			Name:  "amt_msat",
+			Usage: "the amount to forward expressed in milli-atoms",
+		},
+	},
+	Action: queryProbability,
+}
+
+func queryProbability(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	var (
+		args = ctx.Args()
+		req  = &lnrpc.QueryProbabilityRequest{}
+		err  error
+	)
+
+	switch {
+	case ctx.IsSet("from_node"):
+		req.FromNode = ctx.String("from_node")
+	case args.Present():
+		req.FromNode = args.First()
+		args = args.Tail()
+	default:
+		return fmt.Errorf("from_node argument missing")
+	}
+
+	switch {
+	case ctx.IsSet("to_node"):
+		req.ToNode = ctx.String("to_node")
+	case args.Present():
+		req.ToNode = args.First()
+		args = args.Tail()
+	default:
+		return fmt.Errorf("to_node argument missing")
+	}
+
+	switch {
+	case ctx.IsSet("amt_msat"):
+		req.AmtMsat = ctx.Int64("amt_msat")
+	case args.Present():
+		req.AmtMsat, err = strconv.ParseInt(args.First(), 10, 64)
+		if err != nil {
+			return fmt.Errorf("unable to decode amt_msat: %v", err)
+		}
+	default:
+		return fmt.Errorf("amt_msat argument missing")
+	}
+
+	resp, err := client.QueryProbability(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var pendingSweepsCommand = cli.Command{
+	Name:  "pendingsweeps",
+	Usage: "list the outputs currently being swept into the wallet",
+	Description: "Returns each output the sweeper is currently sweeping " +
+		"back into the wallet, along with the number of broadcast " +
+		"attempts made so far, the height of the next attempt, and " +
+		"the fee rate at which it would be swept next.",
+	Action: pendingSweeps,
+}
+
+func pendingSweeps(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.PendingSweepsRequest{}
+	resp, err := client.PendingSweeps(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var bumpFeeCommand = cli.Command{
+	Name:      "bumpfee",
+	Usage:     "bump the fee rate of an output currently being swept",
+	ArgsUsage: "outpoint fee_per_byte",
+	Description: `Bumps the fee rate of an output the sweeper is currently
+		sweeping back into the wallet. The output is swept immediately
+		at no less than the requested fee rate, batched with any other
+		outputs due to be swept at a similar fee rate. Outpoints are
+		encoded as: txid:output_index`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "outpoint",
+			Usage: "the output whose fee rate should be bumped. " +
+				"Takes the form of: txid:output_index",
+		},
+		cli.Int64Flag{
+			Name:  "fee_per_byte",
+			Usage: "the fee rate in atoms per byte to sweep the output at",
+		},
+	},
+	Action: bumpFee,
+}
+
+func bumpFee(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	var (
+		outpoint   string
+		feePerByte int64
+		err        error
+	)
+	args := ctx.Args()
+
+	switch {
+	case ctx.IsSet("outpoint"):
+		outpoint = ctx.String("outpoint")
+	case args.Present():
+		outpoint = args.First()
+		args = args.Tail()
+	default:
+		return fmt.Errorf("outpoint argument missing")
+	}
+
+	switch {
+	case ctx.IsSet("fee_per_byte"):
+		feePerByte = ctx.Int64("fee_per_byte")
+	case args.Present():
+		feePerByte, err = strconv.ParseInt(args.First(), 10, 64)
+		if err != nil {
+			return fmt.Errorf("unable to decode fee_per_byte: %v", err)
+		}
+	default:
+		return fmt.Errorf("fee_per_byte argument missing")
+	}
+
+	req := &lnrpc.BumpFeeRequest{
+		Outpoint:   outpoint,
+		FeePerByte: feePerByte,
+	}
+	resp, err := client.BumpFee(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
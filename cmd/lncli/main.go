@@ -152,13 +152,20 @@ func main() {
 		sendPaymentCommand,
 		addInvoiceCommand,
 		lookupInvoiceCommand,
+		settleInvoiceCommand,
+		cancelInvoiceCommand,
 		listInvoicesCommand,
 		listChannelsCommand,
+		closedChannelsCommand,
+		migrationStatusCommand,
 		listPaymentsCommand,
+		trackPaymentCommand,
 		describeGraphCommand,
 		getChanInfoCommand,
 		getNodeInfoCommand,
 		queryRoutesCommand,
+		buildRouteCommand,
+		sendToRouteCommand,
 		getNetworkInfoCommand,
 		debugLevelCommand,
 		decodePayReqComamnd,
@@ -168,6 +175,19 @@ func main() {
 		verifyMessageCommand,
 		feeReportCommand,
 		updateFeesCommand,
+		updateChannelPolicyCommand,
+		listBlacklistCommand,
+		updateBlacklistCommand,
+		getCommitmentTxnsCommand,
+		exportChannelDBCommand,
+		sendCustomCommand,
+		subscribeCustomCommand,
+		subscribeHtlcEventsCommand,
+		queryMissionControlCommand,
+		resetMissionControlCommand,
+		queryProbabilityCommand,
+		pendingSweepsCommand,
+		bumpFeeCommand,
 	}
 
 	if err := app.Run(os.Args); err != nil {
@@ -14,7 +14,9 @@ import (
 
 	flags "github.com/btcsuite/go-flags"
 	"github.com/lightningnetwork/lnd/brontide"
+	"github.com/lightningnetwork/lnd/htlcswitch/hodl"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing"
 	"github.com/roasbeef/btcd/btcec"
 	"github.com/roasbeef/btcutil"
 )
@@ -35,6 +37,40 @@ const (
 	defaultRPCHost            = "localhost"
 	defaultMaxPendingChannels = 1
 	defaultNumChanConfs       = 1
+	defaultZombieHorizon      = time.Hour * 24 * 14
+
+	defaultHTLCInterceptTimeout = 30 * time.Second
+
+	// defaultMaxDustExposure is the default maximum dust exposure of a
+	// channel, in atoms.
+	defaultMaxDustExposure = 500000
+
+	defaultLinkBatchInterval = 50 * time.Millisecond
+
+	defaultExpiryGraceDelta = 2
+
+	defaultExtSignerBatchInterval = 50 * time.Millisecond
+	defaultExtSignerMaxBatchSize  = 20
+	defaultExtSignerCacheSize     = 500
+	defaultExtSignerTimeout       = 30 * time.Second
+
+	defaultDBCompactThreshold    = 0.25
+	defaultDBBatchCommitInterval = 500 * time.Millisecond
+
+	defaultThrottleInterval = time.Hour
+
+	defaultMaxLinkFlaps          = 5
+	defaultMaxLinkFailures       = 50
+	defaultDampingInterval       = time.Hour
+	defaultDampingStableInterval = 30 * time.Minute
+
+	defaultCoopCloseMaxFeeMultiple = 2
+	defaultCoopCloseAcceptWindow   = 0.5
+
+	defaultMCPenaltyHalfLife       = routing.DefaultPenaltyHalfLife
+	defaultMCAprioriHopProbability = routing.DefaultAprioriHopProbability
+	defaultMCAttemptCost           = int64(routing.DefaultAttemptCost.ToSatoshis())
+	defaultMCMinRouteProbability   = routing.DefaultMinRouteProbability
 )
 
 var (
@@ -86,6 +122,52 @@ type autoPilotConfig struct {
 	Allocation  float64 `long:"allocation" description:"The percentage of total funds that should be committed to automatic channel establishment"`
 }
 
+type externalSignerConfig struct {
+	URL           string        `long:"url" description:"The URL of an external signer bridge which will be used to sign all channel and node announcements. If unset, announcements are signed locally."`
+	BatchInterval time.Duration `long:"batchinterval" description:"The duration to wait for additional signing requests before dispatching a batch to the external signer"`
+	MaxBatchSize  int           `long:"maxbatchsize" description:"The maximum number of signing requests sent to the external signer within a single batch"`
+	CacheSize     int           `long:"cachesize" description:"The maximum number of recent signatures to cache"`
+	Timeout       time.Duration `long:"timeout" description:"The duration to wait for a response from the external signer"`
+}
+
+type quirksConfig struct {
+	NoCloseFeeSteps bool `long:"noclosefeesteps" description:"Abort cooperative closes with peers which propose fees that aren't strictly between the previous proposals of both parties, rather than tolerating them"`
+	NoLenientGossip bool `long:"nolenientgossip" description:"Disconnect from peers which send malformed gossip messages, rather than ignoring the messages"`
+}
+
+type dbConfig struct {
+	Compact          bool    `long:"compact" description:"Compact the database on startup if the fraction of its file which can be reclaimed is at least compactthreshold"`
+	CompactThreshold float64 `long:"compactthreshold" description:"The minimum fraction of the database file, between 0 and 1, which must be reclaimable for it to be compacted on startup"`
+
+	BatchCommitInterval time.Duration `long:"batch-commit-interval" description:"The maximum duration to wait before committing a batch of graph updates which may be coalesced with other concurrent updates. Set to 0 to commit each update individually"`
+}
+
+type throttleConfig struct {
+	MaxForward        int64         `long:"maxforward" description:"The maximum total value in satoshis of the HTLCs forwarded across all channels within each interval. Set to 0 to disable."`
+	MaxChannelForward int64         `long:"maxchannelforward" description:"The maximum total value in satoshis of the HTLCs forwarded over each outgoing channel within each interval. Set to 0 to disable."`
+	MaxPeerForward    int64         `long:"maxpeerforward" description:"The maximum total value in satoshis of the HTLCs forwarded to each peer within each interval. Set to 0 to disable."`
+	Interval          time.Duration `long:"interval" description:"The duration of the sliding window over which the forwarding limits are enforced"`
+}
+
+type dampingConfig struct {
+	MaxFlaps       int           `long:"maxflaps" description:"The maximum number of times the link of a channel may go down within each interval before the channel is advertised as disabled. Set to 0 to disable."`
+	MaxFailures    int           `long:"maxfailures" description:"The maximum number of HTLCs the link of a channel may fail to add to the channel within each interval before the channel is advertised as disabled. Set to 0 to disable."`
+	Interval       time.Duration `long:"interval" description:"The duration of the sliding window over which link flaps and failures are counted"`
+	StableInterval time.Duration `long:"stableinterval" description:"The duration the link of a disabled channel must be active without any flaps or failures before the channel is re-enabled"`
+}
+
+type coopCloseConfig struct {
+	MaxFeeMultiple float64 `long:"maxfeemultiple" description:"The multiple of the estimated ideal closing fee which bounds the fees agreed to during cooperative close fee negotiation, both above and below the ideal fee"`
+	AcceptWindow   float64 `long:"acceptwindow" description:"The fraction of the estimated ideal closing fee the peer's proposed fee may deviate from it while still being accepted outright during cooperative close fee negotiation"`
+}
+
+type missionControlConfig struct {
+	PenaltyHalfLife       time.Duration `long:"penaltyhalflife" description:"The duration after which the reduction of the success probability of a node or channel that caused a payment to fail is halved"`
+	AprioriHopProbability float64       `long:"hopprob" description:"The assumed probability of a payment being successfully forwarded over a hop without any history"`
+	AttemptCost           int64         `long:"attemptcost" description:"The virtual cost of a payment attempt in atoms, used to trade off the fees of a route against its probability of succeeding"`
+	MinRouteProbability   float64       `long:"minrtprob" description:"The minimum estimated probability of success for a route to be considered during path finding"`
+}
+
 // config defines the configuration options for lnd.
 //
 // See loadConfig for further details regarding the configuration
@@ -103,7 +185,10 @@ type config struct {
 	LogDir       string `long:"logdir" description:"Directory to log output."`
 
 	Listeners   []string `long:"listen" description:"Add an interface/port to listen for connections (default all interfaces port: 9735)"`
-	ExternalIPs []string `long:"externalip" description:"Add an ip to the list of local addresses we claim to listen on to peers"`
+	ExternalIPs []string `long:"externalip" description:"Add an ip or hostname to the list of local addresses we claim to listen on to peers"`
+
+	BlacklistNodes []string `long:"blacklistnode" description:"Add the hex-encoded public key of a node which should never be used as an intermediate hop when routing payments"`
+	BlacklistDests []string `long:"blacklistdest" description:"Add the hex-encoded public key of a node which should never be used as either an intermediate hop or the destination when routing payments"`
 
 	DebugLevel string `short:"d" long:"debuglevel" description:"Logging level for all subsystems {trace, debug, info, warn, error, critical} -- You may also specify <subsystem>=<level>,<subsystem2>=<level>,... to set the log level for individual subsystems -- Use show to list available subsystems"`
 
@@ -115,14 +200,46 @@ type config struct {
 	DebugHTLC          bool `long:"debughtlc" description:"Activate the debug htlc mode. With the debug HTLC mode, all payments sent use a pre-determined R-Hash. Additionally, all HTLCs sent to a node with the debug HTLC R-Hash are immediately settled in the next available state transition."`
 	MaxPendingChannels int  `long:"maxpendingchannels" description:"The maximum number of incoming pending channels permitted per peer."`
 
+	EnableUpfrontShutdown bool `long:"enable-upfront-shutdown" description:"Commit to paying out to a script generated by the wallet upon the cooperative close of each channel extended to us, preventing our funds from being paid out to any other script should the node be compromised."`
+
+	PingPadBytes uint16 `long:"pingpadbytes" description:"The number of padding bytes to include within each ping sent to peers, which can be used to generate cover traffic."`
+
+	ChanConsistencyCheck bool `long:"chanconsistencycheck" description:"On startup, check that the funding output of every open channel is either unspent or has a known close record. Channels which fail the check won't be used for forwarding."`
+
 	Litecoin *chainConfig `group:"Litecoin" namespace:"litecoin"`
 	Bitcoin  *chainConfig `group:"Bitcoin" namespace:"bitcoin"`
 
 	DefaultNumChanConfs int `long:"defaultchanconfs" description:"The default number of confirmations a channel must have before it's considered open."`
 
+	ZombieHorizon time.Duration `long:"zombiehorizon" description:"The duration after which a channel within the graph that hasn't received any updates is marked as a zombie, and no longer used for path finding or relayed to peers until a fresh update arrives. Set to 0 to disable."`
+
+	HTLCInterceptTimeout time.Duration `long:"htlcintercepttimeout" description:"The duration an HTLC handed to an HTLC interceptor is held awaiting its resolution, after which it's failed back"`
+
+	MaxDustExposure int64 `long:"maxdustexposure" description:"The maximum total value in atoms of the dust HTLCs on either commitment of a channel. Dust HTLCs are burned to fees should the channel be force closed, so new dust HTLCs which would exceed this value are failed. Set to 0 to disable."`
+
+	LinkBatchInterval time.Duration `long:"linkbatchinterval" description:"The interval at which a channel commits the HTLC adds, settles and fails it has pending, bundling them within a single commitment update"`
+
+	ExpiryGraceDelta uint32 `long:"expirygracedelta" description:"The minimum number of blocks incoming HTLCs, and the HTLCs forwarded for them, must leave before their expiry. HTLCs arriving closer to expiry are rejected, as they may need to be resolved on-chain"`
+
 	NeutrinoMode *neutrinoConfig `group:"neutrino" namespace:"neutrino"`
 
 	Autopilot *autoPilotConfig `group:"autopilot" namespace:"autopilot"`
+
+	ExternalSigner *externalSignerConfig `group:"externalsigner" namespace:"externalsigner"`
+
+	DB *dbConfig `group:"db" namespace:"db"`
+
+	Quirks *quirksConfig `group:"quirks" namespace:"quirks"`
+
+	Throttle *throttleConfig `group:"throttle" namespace:"throttle"`
+
+	Damping *dampingConfig `group:"damping" namespace:"damping"`
+
+	CoopClose *coopCloseConfig `group:"coopclose" namespace:"coopclose"`
+
+	MissionControl *missionControlConfig `group:"missioncontrol" namespace:"missioncontrol"`
+
+	Hodl *hodl.Config `group:"hodl" namespace:"hodl"`
 }
 
 // loadConfig initializes and parses the config using a config file and command
@@ -135,19 +252,24 @@ type config struct {
 // 	4) Parse CLI options and overwrite/add any specified options
 func loadConfig() (*config, error) {
 	defaultCfg := config{
-		ConfigFile:          defaultConfigFile,
-		DataDir:             defaultDataDir,
-		DebugLevel:          defaultLogLevel,
-		TLSCertPath:         defaultTLSCertPath,
-		TLSKeyPath:          defaultTLSKeyPath,
-		AdminMacPath:        defaultAdminMacPath,
-		ReadMacPath:         defaultReadMacPath,
-		LogDir:              defaultLogDir,
-		PeerPort:            defaultPeerPort,
-		RPCPort:             defaultRPCPort,
-		RESTPort:            defaultRESTPort,
-		MaxPendingChannels:  defaultMaxPendingChannels,
-		DefaultNumChanConfs: defaultNumChanConfs,
+		ConfigFile:           defaultConfigFile,
+		DataDir:              defaultDataDir,
+		DebugLevel:           defaultLogLevel,
+		TLSCertPath:          defaultTLSCertPath,
+		TLSKeyPath:           defaultTLSKeyPath,
+		AdminMacPath:         defaultAdminMacPath,
+		ReadMacPath:          defaultReadMacPath,
+		LogDir:               defaultLogDir,
+		PeerPort:             defaultPeerPort,
+		RPCPort:              defaultRPCPort,
+		RESTPort:             defaultRESTPort,
+		MaxPendingChannels:   defaultMaxPendingChannels,
+		DefaultNumChanConfs:  defaultNumChanConfs,
+		ZombieHorizon:        defaultZombieHorizon,
+		HTLCInterceptTimeout: defaultHTLCInterceptTimeout,
+		MaxDustExposure:      defaultMaxDustExposure,
+		LinkBatchInterval:    defaultLinkBatchInterval,
+		ExpiryGraceDelta:     defaultExpiryGraceDelta,
 		Bitcoin: &chainConfig{
 			RPCHost: defaultRPCHost,
 			RPCCert: defaultBtcdRPCCertFile,
@@ -160,6 +282,37 @@ func loadConfig() (*config, error) {
 			MaxChannels: 5,
 			Allocation:  0.6,
 		},
+		ExternalSigner: &externalSignerConfig{
+			BatchInterval: defaultExtSignerBatchInterval,
+			MaxBatchSize:  defaultExtSignerMaxBatchSize,
+			CacheSize:     defaultExtSignerCacheSize,
+			Timeout:       defaultExtSignerTimeout,
+		},
+		DB: &dbConfig{
+			CompactThreshold:    defaultDBCompactThreshold,
+			BatchCommitInterval: defaultDBBatchCommitInterval,
+		},
+		Quirks: &quirksConfig{},
+		Throttle: &throttleConfig{
+			Interval: defaultThrottleInterval,
+		},
+		Damping: &dampingConfig{
+			MaxFlaps:       defaultMaxLinkFlaps,
+			MaxFailures:    defaultMaxLinkFailures,
+			Interval:       defaultDampingInterval,
+			StableInterval: defaultDampingStableInterval,
+		},
+		CoopClose: &coopCloseConfig{
+			MaxFeeMultiple: defaultCoopCloseMaxFeeMultiple,
+			AcceptWindow:   defaultCoopCloseAcceptWindow,
+		},
+		MissionControl: &missionControlConfig{
+			PenaltyHalfLife:       defaultMCPenaltyHalfLife,
+			AprioriHopProbability: defaultMCAprioriHopProbability,
+			AttemptCost:           defaultMCAttemptCost,
+			MinRouteProbability:   defaultMCMinRouteProbability,
+		},
+		Hodl: &hodl.Config{},
 	}
 
 	// Pre-parse the command line options to pick up an alternative config
@@ -311,6 +464,154 @@ func loadConfig() (*config, error) {
 		}
 	}
 
+	// Validate the database compaction threshold.
+	if cfg.DB.CompactThreshold < 0 || cfg.DB.CompactThreshold > 1 {
+		str := "%s: The database compaction threshold must be between " +
+			"0 and 1"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// Validate the graph update batch commit interval.
+	if cfg.DB.BatchCommitInterval < 0 {
+		str := "%s: The database batch commit interval must not be " +
+			"negative"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// Validate the forwarding limits.
+	if cfg.Throttle.MaxForward < 0 || cfg.Throttle.MaxChannelForward < 0 ||
+		cfg.Throttle.MaxPeerForward < 0 {
+
+		str := "%s: The forwarding limits must not be negative"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+	if cfg.MaxDustExposure < 0 {
+		str := "%s: The maximum dust exposure must not be negative"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+	if cfg.LinkBatchInterval <= 0 {
+		str := "%s: The link batch interval must be positive"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+	// The grace period must leave room within the time lock delta of the
+	// active chain's forwarding policy, or every forwarded HTLC would be
+	// rejected.
+	timeLockDelta := defaultBitcoinForwardingPolicy.TimeLockDelta
+	if registeredChains.PrimaryChain() == litecoinChain {
+		timeLockDelta = defaultLitecoinForwardingPolicy.TimeLockDelta
+	}
+	if cfg.ExpiryGraceDelta == 0 || cfg.ExpiryGraceDelta >= timeLockDelta {
+		str := "%s: The expiry grace delta must be positive and below " +
+			"the time lock delta of %v"
+		err := fmt.Errorf(str, funcName, timeLockDelta)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+	if cfg.Throttle.Interval <= 0 {
+		str := "%s: The forwarding limit interval must be positive"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// Validate the flap damping parameters.
+	if cfg.Damping.MaxFlaps < 0 || cfg.Damping.MaxFailures < 0 {
+		str := "%s: The flap damping limits must not be negative"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+	if cfg.Damping.Interval <= 0 || cfg.Damping.StableInterval <= 0 {
+		str := "%s: The flap damping intervals must be positive"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// Validate the cooperative close fee negotiation parameters.
+	if cfg.CoopClose.MaxFeeMultiple < 1 {
+		str := "%s: The cooperative close max fee multiple must be " +
+			"at least 1"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+	if cfg.CoopClose.AcceptWindow < 0 || cfg.CoopClose.AcceptWindow > 1 {
+		str := "%s: The cooperative close accept window must be " +
+			"within [0, 1]"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// Validate the mission control parameters.
+	if cfg.MissionControl.PenaltyHalfLife <= 0 {
+		str := "%s: The mission control penalty half life must be " +
+			"positive"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+	if cfg.MissionControl.AprioriHopProbability <= 0 ||
+		cfg.MissionControl.AprioriHopProbability > 1 {
+
+		str := "%s: The mission control apriori hop probability must " +
+			"be within (0, 1]"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+	if cfg.MissionControl.AttemptCost < 0 {
+		str := "%s: The mission control attempt cost must not be " +
+			"negative"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+	if cfg.MissionControl.MinRouteProbability < 0 ||
+		cfg.MissionControl.MinRouteProbability > 1 {
+
+		str := "%s: The mission control minimum route probability " +
+			"must be within [0, 1]"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// Ensure our pings remain within the maximum payload of a ping.
+	if cfg.PingPadBytes > lnwire.MaxPingPadBytes {
+		str := "%s: The ping padding must not exceed %d bytes"
+		err := fmt.Errorf(str, funcName, lnwire.MaxPingPadBytes)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
 	// At this point, we'll save the base data directory in order to ensure
 	// we don't store the macaroon database within any of the chain
 	// namespaced directories.
@@ -0,0 +1,766 @@
+package contractcourt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/boltdb/bolt"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// ArbitratorState is an enum that details the current state of the
+// ChannelArbitrator's state machine.
+type ArbitratorState uint8
+
+const (
+	// StateDefault is the default state. In this state, no major actions
+	// need to be executed.
+	StateDefault ArbitratorState = 0
+
+	// StateBroadcastCommit is a state that indicates that the channel
+	// arbitrator has decided to go to chain, and is about to broadcast
+	// our latest commitment transaction.
+	StateBroadcastCommit ArbitratorState = 1
+
+	// StateCommitmentBroadcasted is a state that indicates that our
+	// commitment transaction has been broadcast, and we're waiting for it
+	// to confirm.
+	StateCommitmentBroadcasted ArbitratorState = 2
+
+	// StateContractClosed is a state that indicates our commitment
+	// transaction has been confirmed, so the outputs of the contract must
+	// now be resolved.
+	StateContractClosed ArbitratorState = 3
+
+	// StateWaitingFullResolution is a state that indicates that the
+	// resolvers of the contract's outputs have been launched, and we're
+	// waiting for all of them to complete.
+	StateWaitingFullResolution ArbitratorState = 4
+
+	// StateFullyResolved is the final state of the channel arbitrator. In
+	// this state, every output of the contract has been resolved.
+	StateFullyResolved ArbitratorState = 5
+)
+
+// String returns a human readable string describing the ArbitratorState.
+func (a ArbitratorState) String() string {
+	switch a {
+	case StateDefault:
+		return "StateDefault"
+
+	case StateBroadcastCommit:
+		return "StateBroadcastCommit"
+
+	case StateCommitmentBroadcasted:
+		return "StateCommitmentBroadcasted"
+
+	case StateContractClosed:
+		return "StateContractClosed"
+
+	case StateWaitingFullResolution:
+		return "StateWaitingFullResolution"
+
+	case StateFullyResolved:
+		return "StateFullyResolved"
+
+	default:
+		return fmt.Sprintf("unknown state: %d", a)
+	}
+}
+
+// ContractResolutions houses the information required to resolve the outputs
+// of our commitment transaction once it's been broadcast.
+type ContractResolutions struct {
+	// CommitTx is our commitment transaction which closed the channel.
+	CommitTx *wire.MsgTx
+
+	// ShortChanID is the short channel ID of the closed channel, which
+	// identifies it within the resolution messages sent to the switch.
+	ShortChanID lnwire.ShortChannelID
+
+	// OutputsIncubated is true if our delayed commitment output, or any
+	// of our outgoing HTLC outputs, were handed to the utxo nursery, which
+	// sweeps them once mature.
+	OutputsIncubated bool
+
+	// HtlcResolutions allows each of our outgoing HTLCs present on the
+	// commitment transaction to be timed out once expired.
+	HtlcResolutions []lnwallet.OutgoingHtlcResolution
+
+	// IncomingHtlcResolutions allows each of the incoming HTLCs present
+	// on the commitment transaction to be claimed, should we know its
+	// preimage.
+	IncomingHtlcResolutions []lnwallet.IncomingHtlcResolution
+}
+
+var (
+	// ErrNoContractResolutions is returned when the contract resolutions
+	// are fetched from an arbitrator log to which none have been written.
+	ErrNoContractResolutions = fmt.Errorf("no contract resolutions exist")
+
+	// ErrNoChainActions is returned when the chain actions are fetched from
+	// an arbitrator log to which none have been written.
+	ErrNoChainActions = fmt.Errorf("no chain actions exist")
+)
+
+// ArbitratorLog is the persistent log of a ChannelArbitrator. The log records
+// the current state of the arbitrator, the decisions it took when going to
+// chain, and the progress made resolving the contract, allowing the arbitrator
+// to resume exactly where it left off after a restart.
+type ArbitratorLog interface {
+	// CurrentState returns the current state of the arbitrator. If no
+	// state has been committed, then StateDefault is returned.
+	CurrentState() (ArbitratorState, error)
+
+	// CommitState persists the passed state as the current state of the
+	// arbitrator.
+	CommitState(ArbitratorState) error
+
+	// LogChainActions persists the actions the arbitrator decided to take
+	// for each HTLC when going to chain.
+	LogChainActions(ChainActionMap) error
+
+	// FetchChainActions returns the actions previously logged. If none
+	// exist, then ErrNoChainActions is returned.
+	FetchChainActions() (ChainActionMap, error)
+
+	// LogContractResolutions persists the information required to resolve
+	// the outputs of our broadcast commitment transaction.
+	LogContractResolutions(*ContractResolutions) error
+
+	// FetchContractResolutions returns the contract resolutions previously
+	// logged. If none exist, then ErrNoContractResolutions is returned.
+	FetchContractResolutions() (*ContractResolutions, error)
+
+	// MarkResolved records that the output identified by the passed
+	// resolver key has been fully resolved.
+	MarkResolved(key []byte) error
+
+	// IsResolved returns true if the output identified by the passed
+	// resolver key has been marked as fully resolved.
+	IsResolved(key []byte) (bool, error)
+
+	// LogSecondLevelConf records the height at which the second-level
+	// HTLC transaction of the output identified by the passed resolver
+	// key confirmed, after which only its time-locked output remains to
+	// be swept.
+	LogSecondLevelConf(key []byte, confHeight uint32) error
+
+	// FetchSecondLevelConf returns the confirmation height of the
+	// second-level HTLC transaction of the output identified by the
+	// passed resolver key. If none has been logged, then false is
+	// returned.
+	FetchSecondLevelConf(key []byte) (uint32, bool, error)
+
+	// WipeHistory removes all state of the arbitrator from the log.
+	WipeHistory() error
+}
+
+var (
+	// arbitratorLogBucket is the top-level bucket which houses the log of
+	// each channel arbitrator. Within it, each channel has a sub-bucket
+	// keyed by its funding outpoint.
+	//
+	// maps: chanPoint -> {stateKey, actionsKey, resolutionsKey,
+	//                     resolvedBucket, secondLevelBucket}
+	arbitratorLogBucket = []byte("arbitrator-log")
+
+	// stateKey is the key under which the current state of the arbitrator
+	// is stored.
+	stateKey = []byte("state")
+
+	// actionsKey is the key under which the logged chain actions are
+	// stored.
+	actionsKey = []byte("chain-actions")
+
+	// resolutionsKey is the key under which the logged contract
+	// resolutions are stored.
+	resolutionsKey = []byte("resolutions")
+
+	// resolvedBucket is the sub-bucket which holds the key of each
+	// resolver which has been fully resolved.
+	resolvedBucket = []byte("resolved")
+
+	// secondLevelBucket is the sub-bucket which holds the confirmation
+	// height of the second-level HTLC transaction of each resolver, keyed
+	// by the resolver's key.
+	secondLevelBucket = []byte("second-level-confs")
+
+	byteOrder = binary.BigEndian
+)
+
+// boltArbitratorLog is an implementation of the ArbitratorLog interface backed
+// by the bolt database of channeldb.
+type boltArbitratorLog struct {
+	db *channeldb.DB
+
+	scopeKey []byte
+}
+
+// newBoltArbitratorLog returns a new arbitrator log for the channel with the
+// passed funding outpoint.
+func newBoltArbitratorLog(db *channeldb.DB,
+	chanPoint wire.OutPoint) *boltArbitratorLog {
+
+	var scopeKey [36]byte
+	copy(scopeKey[:], chanPoint.Hash[:])
+	byteOrder.PutUint32(scopeKey[32:], chanPoint.Index)
+
+	return &boltArbitratorLog{
+		db:       db,
+		scopeKey: scopeKey[:],
+	}
+}
+
+// A compile time check to ensure boltArbitratorLog meets the ArbitratorLog
+// interface.
+var _ ArbitratorLog = (*boltArbitratorLog)(nil)
+
+// scopedBucket returns the bucket of the arbitrator's channel, creating it if
+// it doesn't yet exist.
+func (b *boltArbitratorLog) scopedBucket(tx *bolt.Tx) (*bolt.Bucket, error) {
+	logBucket, err := tx.CreateBucketIfNotExists(arbitratorLogBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	return logBucket.CreateBucketIfNotExists(b.scopeKey)
+}
+
+// fetchScopedBucket returns the bucket of the arbitrator's channel, or nil if
+// nothing has been logged for the channel.
+func (b *boltArbitratorLog) fetchScopedBucket(tx *bolt.Tx) *bolt.Bucket {
+	logBucket := tx.Bucket(arbitratorLogBucket)
+	if logBucket == nil {
+		return nil
+	}
+
+	return logBucket.Bucket(b.scopeKey)
+}
+
+// CurrentState returns the current state of the arbitrator. If no state has
+// been committed, then StateDefault is returned.
+//
+// NOTE: This is part of the ArbitratorLog interface.
+func (b *boltArbitratorLog) CurrentState() (ArbitratorState, error) {
+	state := StateDefault
+	err := b.db.View(func(tx *bolt.Tx) error {
+		scopeBucket := b.fetchScopedBucket(tx)
+		if scopeBucket == nil {
+			return nil
+		}
+
+		stateBytes := scopeBucket.Get(stateKey)
+		if len(stateBytes) == 1 {
+			state = ArbitratorState(stateBytes[0])
+		}
+
+		return nil
+	})
+	if err != nil {
+		return StateDefault, err
+	}
+
+	return state, nil
+}
+
+// CommitState persists the passed state as the current state of the
+// arbitrator.
+//
+// NOTE: This is part of the ArbitratorLog interface.
+func (b *boltArbitratorLog) CommitState(state ArbitratorState) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		scopeBucket, err := b.scopedBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		return scopeBucket.Put(stateKey, []byte{uint8(state)})
+	})
+}
+
+// LogChainActions persists the actions the arbitrator decided to take for
+// each HTLC when going to chain.
+//
+// NOTE: This is part of the ArbitratorLog interface.
+func (b *boltArbitratorLog) LogChainActions(actions ChainActionMap) error {
+	var buf bytes.Buffer
+	if err := encodeChainActions(&buf, actions); err != nil {
+		return err
+	}
+
+	return b.db.Update(func(tx *bolt.Tx) error {
+		scopeBucket, err := b.scopedBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		return scopeBucket.Put(actionsKey, buf.Bytes())
+	})
+}
+
+// FetchChainActions returns the actions previously logged. If none exist, then
+// ErrNoChainActions is returned.
+//
+// NOTE: This is part of the ArbitratorLog interface.
+func (b *boltArbitratorLog) FetchChainActions() (ChainActionMap, error) {
+	var actions ChainActionMap
+	err := b.db.View(func(tx *bolt.Tx) error {
+		scopeBucket := b.fetchScopedBucket(tx)
+		if scopeBucket == nil {
+			return ErrNoChainActions
+		}
+
+		actionBytes := scopeBucket.Get(actionsKey)
+		if actionBytes == nil {
+			return ErrNoChainActions
+		}
+
+		var err error
+		actions, err = decodeChainActions(bytes.NewReader(actionBytes))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return actions, nil
+}
+
+// LogContractResolutions persists the information required to resolve the
+// outputs of our broadcast commitment transaction.
+//
+// NOTE: This is part of the ArbitratorLog interface.
+func (b *boltArbitratorLog) LogContractResolutions(
+	resolutions *ContractResolutions) error {
+
+	var buf bytes.Buffer
+	if err := encodeContractResolutions(&buf, resolutions); err != nil {
+		return err
+	}
+
+	return b.db.Update(func(tx *bolt.Tx) error {
+		scopeBucket, err := b.scopedBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		return scopeBucket.Put(resolutionsKey, buf.Bytes())
+	})
+}
+
+// FetchContractResolutions returns the contract resolutions previously logged.
+// If none exist, then ErrNoContractResolutions is returned.
+//
+// NOTE: This is part of the ArbitratorLog interface.
+func (b *boltArbitratorLog) FetchContractResolutions() (*ContractResolutions,
+	error) {
+
+	var resolutions *ContractResolutions
+	err := b.db.View(func(tx *bolt.Tx) error {
+		scopeBucket := b.fetchScopedBucket(tx)
+		if scopeBucket == nil {
+			return ErrNoContractResolutions
+		}
+
+		resBytes := scopeBucket.Get(resolutionsKey)
+		if resBytes == nil {
+			return ErrNoContractResolutions
+		}
+
+		var err error
+		resolutions, err = decodeContractResolutions(
+			bytes.NewReader(resBytes),
+		)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resolutions, nil
+}
+
+// MarkResolved records that the output identified by the passed resolver key
+// has been fully resolved.
+//
+// NOTE: This is part of the ArbitratorLog interface.
+func (b *boltArbitratorLog) MarkResolved(key []byte) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		scopeBucket, err := b.scopedBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		resolved, err := scopeBucket.CreateBucketIfNotExists(
+			resolvedBucket,
+		)
+		if err != nil {
+			return err
+		}
+
+		return resolved.Put(key, []byte{})
+	})
+}
+
+// IsResolved returns true if the output identified by the passed resolver key
+// has been marked as fully resolved.
+//
+// NOTE: This is part of the ArbitratorLog interface.
+func (b *boltArbitratorLog) IsResolved(key []byte) (bool, error) {
+	var isResolved bool
+	err := b.db.View(func(tx *bolt.Tx) error {
+		scopeBucket := b.fetchScopedBucket(tx)
+		if scopeBucket == nil {
+			return nil
+		}
+
+		resolved := scopeBucket.Bucket(resolvedBucket)
+		if resolved == nil {
+			return nil
+		}
+
+		isResolved = resolved.Get(key) != nil
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return isResolved, nil
+}
+
+// LogSecondLevelConf records the height at which the second-level HTLC
+// transaction of the output identified by the passed resolver key confirmed.
+//
+// NOTE: This is part of the ArbitratorLog interface.
+func (b *boltArbitratorLog) LogSecondLevelConf(key []byte,
+	confHeight uint32) error {
+
+	return b.db.Update(func(tx *bolt.Tx) error {
+		scopeBucket, err := b.scopedBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		confs, err := scopeBucket.CreateBucketIfNotExists(
+			secondLevelBucket,
+		)
+		if err != nil {
+			return err
+		}
+
+		var heightBytes [4]byte
+		byteOrder.PutUint32(heightBytes[:], confHeight)
+
+		return confs.Put(key, heightBytes[:])
+	})
+}
+
+// FetchSecondLevelConf returns the confirmation height of the second-level
+// HTLC transaction of the output identified by the passed resolver key. If
+// none has been logged, then false is returned.
+//
+// NOTE: This is part of the ArbitratorLog interface.
+func (b *boltArbitratorLog) FetchSecondLevelConf(key []byte) (uint32, bool,
+	error) {
+
+	var (
+		confHeight uint32
+		found      bool
+	)
+	err := b.db.View(func(tx *bolt.Tx) error {
+		scopeBucket := b.fetchScopedBucket(tx)
+		if scopeBucket == nil {
+			return nil
+		}
+
+		confs := scopeBucket.Bucket(secondLevelBucket)
+		if confs == nil {
+			return nil
+		}
+
+		heightBytes := confs.Get(key)
+		if len(heightBytes) != 4 {
+			return nil
+		}
+
+		confHeight = byteOrder.Uint32(heightBytes)
+		found = true
+		return nil
+	})
+	if err != nil {
+		return 0, false, err
+	}
+
+	return confHeight, found, nil
+}
+
+// WipeHistory removes all state of the arbitrator from the log.
+//
+// NOTE: This is part of the ArbitratorLog interface.
+func (b *boltArbitratorLog) WipeHistory() error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		logBucket := tx.Bucket(arbitratorLogBucket)
+		if logBucket == nil || logBucket.Bucket(b.scopeKey) == nil {
+			return nil
+		}
+
+		return logBucket.DeleteBucket(b.scopeKey)
+	})
+}
+
+// encodeChainActions serializes the passed chain actions to the target
+// writer.
+func encodeChainActions(w io.Writer, actions ChainActionMap) error {
+	var scratch [4]byte
+	byteOrder.PutUint32(scratch[:], uint32(len(actions)))
+	if _, err := w.Write(scratch[:]); err != nil {
+		return err
+	}
+
+	for action, htlcs := range actions {
+		if _, err := w.Write([]byte{uint8(action)}); err != nil {
+			return err
+		}
+
+		byteOrder.PutUint32(scratch[:], uint32(len(htlcs)))
+		if _, err := w.Write(scratch[:]); err != nil {
+			return err
+		}
+
+		for i := range htlcs {
+			if err := encodeHtlc(w, &htlcs[i]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// decodeChainActions deserializes a set of chain actions from the target
+// reader.
+func decodeChainActions(r io.Reader) (ChainActionMap, error) {
+	var scratch [4]byte
+	if _, err := io.ReadFull(r, scratch[:]); err != nil {
+		return nil, err
+	}
+	numActions := byteOrder.Uint32(scratch[:])
+
+	actions := make(ChainActionMap, numActions)
+	for i := uint32(0); i < numActions; i++ {
+		var action [1]byte
+		if _, err := io.ReadFull(r, action[:]); err != nil {
+			return nil, err
+		}
+
+		if _, err := io.ReadFull(r, scratch[:]); err != nil {
+			return nil, err
+		}
+		numHtlcs := byteOrder.Uint32(scratch[:])
+
+		htlcs := make([]channeldb.HTLC, numHtlcs)
+		for j := range htlcs {
+			if err := decodeHtlc(r, &htlcs[j]); err != nil {
+				return nil, err
+			}
+		}
+
+		actions[ChainAction(action[0])] = htlcs
+	}
+
+	return actions, nil
+}
+
+// encodeHtlc serializes the fields of the passed HTLC required to act upon it
+// on-chain to the target writer.
+func encodeHtlc(w io.Writer, htlc *channeldb.HTLC) error {
+	var scratch [49]byte
+	copy(scratch[:32], htlc.RHash[:])
+	byteOrder.PutUint64(scratch[32:40], uint64(htlc.Amt))
+	byteOrder.PutUint32(scratch[40:44], htlc.RefundTimeout)
+	byteOrder.PutUint32(scratch[44:48], uint32(htlc.OutputIndex))
+	if htlc.Incoming {
+		scratch[48] = 1
+	}
+
+	_, err := w.Write(scratch[:])
+	return err
+}
+
+// decodeHtlc deserializes an HTLC written by encodeHtlc from the target
+// reader.
+func decodeHtlc(r io.Reader, htlc *channeldb.HTLC) error {
+	var scratch [49]byte
+	if _, err := io.ReadFull(r, scratch[:]); err != nil {
+		return err
+	}
+
+	copy(htlc.RHash[:], scratch[:32])
+	htlc.Amt = lnwire.MilliAtom(byteOrder.Uint64(scratch[32:40]))
+	htlc.RefundTimeout = byteOrder.Uint32(scratch[40:44])
+	htlc.OutputIndex = int32(byteOrder.Uint32(scratch[44:48]))
+	htlc.Incoming = scratch[48] == 1
+
+	return nil
+}
+
+// encodeContractResolutions serializes the passed contract resolutions to the
+// target writer.
+func encodeContractResolutions(w io.Writer, c *ContractResolutions) error {
+	if err := c.CommitTx.Serialize(w); err != nil {
+		return err
+	}
+
+	var chanID [8]byte
+	byteOrder.PutUint64(chanID[:], c.ShortChanID.ToUint64())
+	if _, err := w.Write(chanID[:]); err != nil {
+		return err
+	}
+
+	var incubated [1]byte
+	if c.OutputsIncubated {
+		incubated[0] = 1
+	}
+	if _, err := w.Write(incubated[:]); err != nil {
+		return err
+	}
+
+	var scratch [4]byte
+	byteOrder.PutUint32(scratch[:], uint32(len(c.HtlcResolutions)))
+	if _, err := w.Write(scratch[:]); err != nil {
+		return err
+	}
+
+	for i := range c.HtlcResolutions {
+		res := &c.HtlcResolutions[i]
+
+		byteOrder.PutUint32(scratch[:], res.Expiry)
+		if _, err := w.Write(scratch[:]); err != nil {
+			return err
+		}
+		if err := res.SignedTimeoutTx.Serialize(w); err != nil {
+			return err
+		}
+		byteOrder.PutUint32(scratch[:], res.CsvDelay)
+		if _, err := w.Write(scratch[:]); err != nil {
+			return err
+		}
+		err := lnwallet.WriteSignDescriptor(w, &res.SweepSignDesc)
+		if err != nil {
+			return err
+		}
+	}
+
+	byteOrder.PutUint32(scratch[:], uint32(len(c.IncomingHtlcResolutions)))
+	if _, err := w.Write(scratch[:]); err != nil {
+		return err
+	}
+
+	for i := range c.IncomingHtlcResolutions {
+		res := &c.IncomingHtlcResolutions[i]
+
+		if err := res.SignedSuccessTx.Serialize(w); err != nil {
+			return err
+		}
+		byteOrder.PutUint32(scratch[:], res.CsvDelay)
+		if _, err := w.Write(scratch[:]); err != nil {
+			return err
+		}
+		err := lnwallet.WriteSignDescriptor(w, &res.SweepSignDesc)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decodeContractResolutions deserializes a set of contract resolutions from
+// the target reader.
+func decodeContractResolutions(r io.Reader) (*ContractResolutions, error) {
+	c := &ContractResolutions{
+		CommitTx: &wire.MsgTx{},
+	}
+	if err := c.CommitTx.Deserialize(r); err != nil {
+		return nil, err
+	}
+
+	var chanID [8]byte
+	if _, err := io.ReadFull(r, chanID[:]); err != nil {
+		return nil, err
+	}
+	c.ShortChanID = lnwire.NewShortChanIDFromInt(
+		byteOrder.Uint64(chanID[:]),
+	)
+
+	var incubated [1]byte
+	if _, err := io.ReadFull(r, incubated[:]); err != nil {
+		return nil, err
+	}
+	c.OutputsIncubated = incubated[0] == 1
+
+	var scratch [4]byte
+	if _, err := io.ReadFull(r, scratch[:]); err != nil {
+		return nil, err
+	}
+	numResolutions := byteOrder.Uint32(scratch[:])
+
+	c.HtlcResolutions = make(
+		[]lnwallet.OutgoingHtlcResolution, numResolutions,
+	)
+	for i := range c.HtlcResolutions {
+		res := &c.HtlcResolutions[i]
+
+		if _, err := io.ReadFull(r, scratch[:]); err != nil {
+			return nil, err
+		}
+		res.Expiry = byteOrder.Uint32(scratch[:])
+
+		res.SignedTimeoutTx = &wire.MsgTx{}
+		if err := res.SignedTimeoutTx.Deserialize(r); err != nil {
+			return nil, err
+		}
+		if _, err := io.ReadFull(r, scratch[:]); err != nil {
+			return nil, err
+		}
+		res.CsvDelay = byteOrder.Uint32(scratch[:])
+		err := lnwallet.ReadSignDescriptor(r, &res.SweepSignDesc)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := io.ReadFull(r, scratch[:]); err != nil {
+		return nil, err
+	}
+	numIncoming := byteOrder.Uint32(scratch[:])
+
+	c.IncomingHtlcResolutions = make(
+		[]lnwallet.IncomingHtlcResolution, numIncoming,
+	)
+	for i := range c.IncomingHtlcResolutions {
+		res := &c.IncomingHtlcResolutions[i]
+
+		res.SignedSuccessTx = &wire.MsgTx{}
+		if err := res.SignedSuccessTx.Deserialize(r); err != nil {
+			return nil, err
+		}
+		if _, err := io.ReadFull(r, scratch[:]); err != nil {
+			return nil, err
+		}
+		res.CsvDelay = byteOrder.Uint32(scratch[:])
+		err := lnwallet.ReadSignDescriptor(r, &res.SweepSignDesc)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
@@ -0,0 +1,338 @@
+package contractcourt
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+)
+
+var (
+	testChanPoint = wire.OutPoint{
+		Hash:  chainhash.Hash{1},
+		Index: 2,
+	}
+
+	testHtlcs = []channeldb.HTLC{
+		{
+			RHash:         [32]byte{1},
+			Amt:           1000,
+			RefundTimeout: 100,
+			OutputIndex:   0,
+		},
+		{
+			RHash:         [32]byte{2},
+			Amt:           2000,
+			RefundTimeout: 200,
+			OutputIndex:   -1,
+			Incoming:      true,
+		},
+	}
+)
+
+// makeTestDB creates a new channeldb within a temporary directory, returning
+// it along with a function which cleans it up.
+func makeTestDB(t *testing.T) (*channeldb.DB, func()) {
+	tempDirName, err := ioutil.TempDir("", "arblog")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+
+	db, err := channeldb.Open(tempDirName)
+	if err != nil {
+		os.RemoveAll(tempDirName)
+		t.Fatalf("unable to open channeldb: %v", err)
+	}
+
+	return db, func() {
+		db.Close()
+		os.RemoveAll(tempDirName)
+	}
+}
+
+// TestArbitratorLogState checks that the state committed to the log is
+// returned once fetched, and that the log is empty once wiped.
+func TestArbitratorLogState(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp := makeTestDB(t)
+	defer cleanUp()
+
+	arbLog := newBoltArbitratorLog(db, testChanPoint)
+
+	state, err := arbLog.CurrentState()
+	if err != nil {
+		t.Fatalf("unable to fetch state: %v", err)
+	}
+	if state != StateDefault {
+		t.Fatalf("expected %v, got %v", StateDefault, state)
+	}
+
+	if err := arbLog.CommitState(StateCommitmentBroadcasted); err != nil {
+		t.Fatalf("unable to commit state: %v", err)
+	}
+	state, err = arbLog.CurrentState()
+	if err != nil {
+		t.Fatalf("unable to fetch state: %v", err)
+	}
+	if state != StateCommitmentBroadcasted {
+		t.Fatalf("expected %v, got %v", StateCommitmentBroadcasted,
+			state)
+	}
+
+	// The log of another channel should be unaffected.
+	otherChanPoint := testChanPoint
+	otherChanPoint.Index++
+	state, err = newBoltArbitratorLog(db, otherChanPoint).CurrentState()
+	if err != nil {
+		t.Fatalf("unable to fetch state: %v", err)
+	}
+	if state != StateDefault {
+		t.Fatalf("expected %v, got %v", StateDefault, state)
+	}
+
+	if err := arbLog.WipeHistory(); err != nil {
+		t.Fatalf("unable to wipe history: %v", err)
+	}
+	state, err = arbLog.CurrentState()
+	if err != nil {
+		t.Fatalf("unable to fetch state: %v", err)
+	}
+	if state != StateDefault {
+		t.Fatalf("expected %v, got %v", StateDefault, state)
+	}
+}
+
+// TestArbitratorLogChainActions checks that the chain actions logged are
+// returned intact once fetched.
+func TestArbitratorLogChainActions(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp := makeTestDB(t)
+	defer cleanUp()
+
+	arbLog := newBoltArbitratorLog(db, testChanPoint)
+
+	if _, err := arbLog.FetchChainActions(); err != ErrNoChainActions {
+		t.Fatalf("expected ErrNoChainActions, got %v", err)
+	}
+
+	actions := ChainActionMap{
+		HtlcTimeoutAction: testHtlcs[:1],
+		HtlcFailNowAction: testHtlcs[1:],
+	}
+	if err := arbLog.LogChainActions(actions); err != nil {
+		t.Fatalf("unable to log chain actions: %v", err)
+	}
+
+	fetchedActions, err := arbLog.FetchChainActions()
+	if err != nil {
+		t.Fatalf("unable to fetch chain actions: %v", err)
+	}
+	if !reflect.DeepEqual(actions, fetchedActions) {
+		t.Fatalf("expected actions %v, got %v", actions,
+			fetchedActions)
+	}
+}
+
+// TestArbitratorLogContractResolutions checks that the contract resolutions
+// logged are returned intact once fetched, and that resolved outputs are
+// tracked.
+func TestArbitratorLogContractResolutions(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp := makeTestDB(t)
+	defer cleanUp()
+
+	arbLog := newBoltArbitratorLog(db, testChanPoint)
+
+	_, err := arbLog.FetchContractResolutions()
+	if err != ErrNoContractResolutions {
+		t.Fatalf("expected ErrNoContractResolutions, got %v", err)
+	}
+
+	privKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+
+	commitTx := wire.NewMsgTx(2)
+	commitTx.AddTxIn(&wire.TxIn{PreviousOutPoint: testChanPoint})
+	commitTx.AddTxOut(&wire.TxOut{Value: 1000, PkScript: []byte{1}})
+
+	timeoutTx := wire.NewMsgTx(2)
+	timeoutTx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{
+			Hash: commitTx.TxHash(),
+		},
+		Witness: wire.TxWitness{[]byte{2}},
+	})
+	timeoutTx.AddTxOut(&wire.TxOut{Value: 900, PkScript: []byte{3}})
+
+	successTx := wire.NewMsgTx(2)
+	successTx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{
+			Hash:  commitTx.TxHash(),
+			Index: 1,
+		},
+		Witness: wire.TxWitness{[]byte{6}},
+	})
+	successTx.AddTxOut(&wire.TxOut{Value: 800, PkScript: []byte{7}})
+
+	resolutions := &ContractResolutions{
+		CommitTx:         commitTx,
+		ShortChanID:      lnwire.NewShortChanIDFromInt(42),
+		OutputsIncubated: true,
+		HtlcResolutions: []lnwallet.OutgoingHtlcResolution{
+			{
+				Expiry:          100,
+				SignedTimeoutTx: timeoutTx,
+				CsvDelay:        144,
+				SweepSignDesc: lnwallet.SignDescriptor{
+					PubKey:        privKey.PubKey(),
+					SingleTweak:   []byte{4},
+					WitnessScript: []byte{5},
+					Output:        timeoutTx.TxOut[0],
+				},
+			},
+		},
+		IncomingHtlcResolutions: []lnwallet.IncomingHtlcResolution{
+			{
+				SignedSuccessTx: successTx,
+				CsvDelay:        144,
+				SweepSignDesc: lnwallet.SignDescriptor{
+					PubKey:        privKey.PubKey(),
+					SingleTweak:   []byte{4},
+					WitnessScript: []byte{8},
+					Output:        successTx.TxOut[0],
+				},
+			},
+		},
+	}
+	if err := arbLog.LogContractResolutions(resolutions); err != nil {
+		t.Fatalf("unable to log resolutions: %v", err)
+	}
+
+	fetched, err := arbLog.FetchContractResolutions()
+	if err != nil {
+		t.Fatalf("unable to fetch resolutions: %v", err)
+	}
+	if fetched.CommitTx.TxHash() != commitTx.TxHash() {
+		t.Fatalf("commitment mismatch")
+	}
+	if fetched.ShortChanID != resolutions.ShortChanID {
+		t.Fatalf("expected short chan id %v, got %v",
+			resolutions.ShortChanID, fetched.ShortChanID)
+	}
+	if !fetched.OutputsIncubated {
+		t.Fatalf("expected outputs to be incubated")
+	}
+	if len(fetched.HtlcResolutions) != 1 {
+		t.Fatalf("expected 1 htlc resolution, got %v",
+			len(fetched.HtlcResolutions))
+	}
+	res := fetched.HtlcResolutions[0]
+	if res.Expiry != 100 {
+		t.Fatalf("expected expiry 100, got %v", res.Expiry)
+	}
+	if res.SignedTimeoutTx.TxHash() != timeoutTx.TxHash() {
+		t.Fatalf("timeout tx mismatch")
+	}
+	if !res.SweepSignDesc.PubKey.IsEqual(privKey.PubKey()) {
+		t.Fatalf("sign descriptor key mismatch")
+	}
+	if !bytes.Equal(res.SweepSignDesc.WitnessScript, []byte{5}) {
+		t.Fatalf("sign descriptor witness script mismatch")
+	}
+	if res.CsvDelay != 144 {
+		t.Fatalf("expected csv delay 144, got %v", res.CsvDelay)
+	}
+
+	if len(fetched.IncomingHtlcResolutions) != 1 {
+		t.Fatalf("expected 1 incoming htlc resolution, got %v",
+			len(fetched.IncomingHtlcResolutions))
+	}
+	incomingRes := fetched.IncomingHtlcResolutions[0]
+	if incomingRes.SignedSuccessTx.TxHash() != successTx.TxHash() {
+		t.Fatalf("success tx mismatch")
+	}
+	if incomingRes.CsvDelay != 144 {
+		t.Fatalf("expected csv delay 144, got %v", incomingRes.CsvDelay)
+	}
+	if !bytes.Equal(incomingRes.SweepSignDesc.WitnessScript, []byte{8}) {
+		t.Fatalf("sign descriptor witness script mismatch")
+	}
+
+	// Once an output is marked as resolved, it should be reported as such.
+	key := []byte("resolver")
+	resolved, err := arbLog.IsResolved(key)
+	if err != nil {
+		t.Fatalf("unable to check resolution: %v", err)
+	}
+	if resolved {
+		t.Fatalf("output shouldn't be resolved")
+	}
+	if err := arbLog.MarkResolved(key); err != nil {
+		t.Fatalf("unable to mark resolved: %v", err)
+	}
+	resolved, err = arbLog.IsResolved(key)
+	if err != nil {
+		t.Fatalf("unable to check resolution: %v", err)
+	}
+	if !resolved {
+		t.Fatalf("output should be resolved")
+	}
+}
+
+// TestArbitratorLogSecondLevelConf checks that the confirmation height of a
+// resolver's second-level transaction is only returned once logged.
+func TestArbitratorLogSecondLevelConf(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp := makeTestDB(t)
+	defer cleanUp()
+
+	arbLog := newBoltArbitratorLog(db, testChanPoint)
+
+	key := []byte("resolver")
+	_, found, err := arbLog.FetchSecondLevelConf(key)
+	if err != nil {
+		t.Fatalf("unable to fetch second-level conf: %v", err)
+	}
+	if found {
+		t.Fatalf("second-level conf shouldn't be found")
+	}
+
+	if err := arbLog.LogSecondLevelConf(key, 120); err != nil {
+		t.Fatalf("unable to log second-level conf: %v", err)
+	}
+
+	confHeight, found, err := arbLog.FetchSecondLevelConf(key)
+	if err != nil {
+		t.Fatalf("unable to fetch second-level conf: %v", err)
+	}
+	if !found || confHeight != 120 {
+		t.Fatalf("expected conf height 120, got %v (found=%v)",
+			confHeight, found)
+	}
+
+	// Once the history is wiped, the confirmation should be gone as well.
+	if err := arbLog.WipeHistory(); err != nil {
+		t.Fatalf("unable to wipe history: %v", err)
+	}
+	_, found, err = arbLog.FetchSecondLevelConf(key)
+	if err != nil {
+		t.Fatalf("unable to fetch second-level conf: %v", err)
+	}
+	if found {
+		t.Fatalf("second-level conf should have been wiped")
+	}
+}
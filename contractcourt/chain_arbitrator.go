@@ -0,0 +1,470 @@
+package contractcourt
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/sweep"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// ResolutionMsg is a message sent by resolvers to outside sub-systems once an
+// outgoing contract has been fully resolved. For multi-hop HTLCs, this allows
+// the incoming HTLC to be settled or failed upstream.
+type ResolutionMsg struct {
+	// SourceChan identifies the channel the outgoing HTLC was sent over.
+	SourceChan lnwire.ShortChannelID
+
+	// PayHash is the payment hash of the resolved HTLC.
+	PayHash [32]byte
+
+	// Amt is the amount of the resolved HTLC.
+	Amt lnwire.MilliAtom
+
+	// Failure will be non-nil if the HTLC was timed out, or was trimmed as
+	// dust. It describes why the HTLC is to be failed upstream.
+	Failure lnwire.FailureMessage
+
+	// PreImage will be non-nil if the remote party claimed the HTLC
+	// on-chain, revealing its preimage.
+	PreImage *[32]byte
+}
+
+// PreimageDB is an interface which represents a persistent store of the
+// preimages we've learned, used to determine which incoming HTLCs we're able
+// to claim on-chain.
+type PreimageDB interface {
+	// LookupPreimage attempts to look up a preimage according to its
+	// payment hash. If found, the preimage is returned along with true.
+	LookupPreimage(hash []byte) ([]byte, bool)
+
+	// AddPreimage adds a newly discovered preimage to the store.
+	AddPreimage(preimage []byte) error
+}
+
+// ChainArbitratorConfig is the configuration shared by the ChainArbitrator and
+// every ChannelArbitrator it manages.
+type ChainArbitratorConfig struct {
+	// OutgoingBroadcastDelta is the number of blocks before the expiry of
+	// an outgoing HTLC at which we'll go to chain, ensuring it can be
+	// timed out on-chain before the incoming HTLC expires upstream.
+	OutgoingBroadcastDelta uint32
+
+	// IncomingBroadcastDelta is the number of blocks before the expiry of
+	// an incoming HTLC, for which we know the preimage, at which we'll go
+	// to chain to claim it before the remote party can time it out.
+	IncomingBroadcastDelta uint32
+
+	// ChainIO allows us to query the state of the current main chain.
+	ChainIO lnwallet.BlockChainIO
+
+	// Notifier is used to watch the chain for new blocks, confirmations,
+	// and spends.
+	Notifier chainntnfs.ChainNotifier
+
+	// Signer is used to sign our commitment transaction when force
+	// closing a channel.
+	Signer lnwallet.Signer
+
+	// FeeEstimator is used to instantiate the state machine of a channel
+	// when force closing it.
+	FeeEstimator lnwallet.FeeEstimator
+
+	// PreimageDB is the store of the preimages we've learned.
+	PreimageDB PreimageDB
+
+	// PublishTx broadcasts the passed transaction to the network.
+	PublishTx func(*wire.MsgTx) error
+
+	// MarkLinkInactive ensures no further updates are made to the channel
+	// with the passed funding outpoint, as it's going to chain.
+	MarkLinkInactive func(wire.OutPoint) error
+
+	// IncubateOutputs hands the delayed commitment output and outgoing
+	// HTLC outputs of a force closed channel over to the utxo nursery,
+	// which times out the HTLCs, then sweeps each output once mature.
+	IncubateOutputs func(*lnwallet.ForceCloseSummary) error
+
+	// SweepInput hands the passed input to the utxo sweeper, returning a
+	// channel over which the result of the sweep is sent. It's used to
+	// sweep the outputs of our second-level HTLC success transactions
+	// once mature.
+	SweepInput func(sweep.Input, sweep.Params) (chan sweep.Result, error)
+
+	// DeliverResolutionMsg delivers the passed resolution messages to the
+	// switch, settling or failing the corresponding incoming HTLCs.
+	DeliverResolutionMsg func(...ResolutionMsg) error
+}
+
+// ChainArbitrator is a sub-system which manages a ChannelArbitrator for each
+// of our channels which remains open, or which is being resolved on-chain
+// following a force close.
+type ChainArbitrator struct {
+	started uint32
+	stopped uint32
+
+	cfg ChainArbitratorConfig
+
+	chanDB *channeldb.DB
+
+	// activeChannels maps the funding outpoint of each arbitrated channel
+	// to its arbitrator.
+	activeChannels map[wire.OutPoint]*ChannelArbitrator
+
+	// activeWatchers maps the funding outpoint of each open channel to
+	// the chain watcher which detects its close.
+	activeWatchers map[wire.OutPoint]*chainWatcher
+
+	sync.Mutex
+}
+
+// NewChainArbitrator returns a new instance of the ChainArbitrator backed by
+// the passed database.
+func NewChainArbitrator(cfg ChainArbitratorConfig,
+	db *channeldb.DB) *ChainArbitrator {
+
+	return &ChainArbitrator{
+		cfg:            cfg,
+		chanDB:         db,
+		activeChannels: make(map[wire.OutPoint]*ChannelArbitrator),
+		activeWatchers: make(map[wire.OutPoint]*chainWatcher),
+	}
+}
+
+// newChannelArbitrator creates the arbitrator of the channel with the passed
+// funding outpoint and short channel ID. The chain events are nil if the
+// channel is no longer open.
+func (c *ChainArbitrator) newChannelArbitrator(chanPoint wire.OutPoint,
+	shortChanID lnwire.ShortChannelID,
+	chainEvents *ChainEventSubscription) *ChannelArbitrator {
+
+	// fetchChannel fetches the open channel, as a state machine capable
+	// of signing our commitment.
+	fetchChannel := func() (*lnwallet.LightningChannel, error) {
+		chanState, err := c.chanDB.FetchChannel(chanPoint)
+		if err != nil {
+			return nil, err
+		}
+
+		return lnwallet.NewLightningChannel(
+			c.cfg.Signer, nil, c.cfg.FeeEstimator, chanState,
+		)
+	}
+
+	arbCfg := ChannelArbitratorConfig{
+		ChanPoint:   chanPoint,
+		ShortChanID: shortChanID,
+		FetchHtlcs: func() ([]channeldb.HTLC, error) {
+			chanState, err := c.chanDB.FetchChannel(chanPoint)
+			if err != nil {
+				return nil, err
+			}
+
+			htlcs := make([]channeldb.HTLC, 0, len(chanState.Htlcs))
+			for _, htlc := range chanState.Htlcs {
+				htlcs = append(htlcs, *htlc)
+			}
+			return htlcs, nil
+		},
+		MarkLinkInactive: func() error {
+			return c.cfg.MarkLinkInactive(chanPoint)
+		},
+		ForceCloseChan: func() (*lnwallet.ForceCloseSummary,
+			[]channeldb.HTLC, error) {
+
+			channel, err := fetchChannel()
+			if err != nil {
+				return nil, nil, err
+			}
+			defer channel.Stop()
+
+			htlcs := channel.StateSnapshot().Htlcs
+			closeSummary, err := channel.ForceClose()
+			if err != nil {
+				return nil, nil, err
+			}
+
+			return closeSummary, htlcs, nil
+		},
+		MarkChannelClosed: func(
+			closeSummary *lnwallet.ForceCloseSummary) (bool, error) {
+
+			channel, err := fetchChannel()
+			if err != nil {
+				return false, err
+			}
+			defer channel.Stop()
+
+			return c.markChannelClosed(channel, closeSummary)
+		},
+		MarkChannelResolved: func() error {
+			return c.chanDB.MarkChanFullyClosed(&chanPoint)
+		},
+		ChainEvents:           chainEvents,
+		ChainArbitratorConfig: c.cfg,
+	}
+
+	return NewChannelArbitrator(
+		arbCfg, newBoltArbitratorLog(c.chanDB, chanPoint),
+	)
+}
+
+// markChannelClosed records that our commitment transaction has been
+// broadcast, then marks the channel as pending closed. Should our commitment
+// output not be dust, or should we have any outgoing HTLCs, then they're
+// handed to the utxo nursery, and true is returned.
+func (c *ChainArbitrator) markChannelClosed(channel *lnwallet.LightningChannel,
+	closeSummary *lnwallet.ForceCloseSummary) (bool, error) {
+
+	// Record that our commitment has been broadcast, so the channel is
+	// reported as such should we fail to complete the remainder of the
+	// force close below.
+	if err := channel.MarkCommitmentBroadcasted(); err != nil {
+		return false, err
+	}
+
+	chanInfo := channel.StateSnapshot()
+	closeInfo := &channeldb.ChannelCloseSummary{
+		ChanPoint:   closeSummary.ChanPoint,
+		ClosingTXID: closeSummary.CloseTx.TxHash(),
+		RemotePub:   &chanInfo.RemoteIdentity,
+		Capacity:    chanInfo.Capacity,
+		CloseType:   channeldb.ForceClose,
+		IsPending:   true,
+
+		HtlcResolutions: closeSummary.HtlcSummaries,
+	}
+
+	// If our commitment output isn't dust or we have active HTLC's on the
+	// commitment transaction, then we'll populate the balances on the
+	// close channel summary.
+	if closeSummary.SelfOutputSignDesc != nil ||
+		len(closeSummary.HtlcResolutions) == 0 {
+
+		closeInfo.SettledBalance = chanInfo.LocalBalance.ToSatoshis()
+		closeInfo.TimeLockedBalance = chanInfo.LocalBalance.ToSatoshis()
+	}
+
+	if err := channel.DeleteState(closeInfo); err != nil {
+		return false, err
+	}
+
+	if closeSummary.SelfOutputSignDesc == nil &&
+		len(closeSummary.HtlcResolutions) == 0 {
+
+		return false, nil
+	}
+
+	// Send the closed channel summary over to the utxoNursery in order to
+	// have its outputs swept back into the wallet once they're mature.
+	if err := c.cfg.IncubateOutputs(closeSummary); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Start launches an arbitrator for each open channel, and resumes those of
+// each force closed channel which is still being resolved.
+func (c *ChainArbitrator) Start() error {
+	if !atomic.CompareAndSwapUint32(&c.started, 0, 1) {
+		return nil
+	}
+
+	log.Tracef("Starting ChainArbitrator")
+
+	openChannels, err := c.chanDB.FetchAllChannels()
+	if err != nil && err != channeldb.ErrNoActiveChannels {
+		return err
+	}
+	for _, channel := range openChannels {
+		chanPoint := channel.FundingOutpoint
+
+		watcher := newChainWatcher(channel, c.cfg.Notifier)
+		c.activeWatchers[chanPoint] = watcher
+
+		c.activeChannels[chanPoint] = c.newChannelArbitrator(
+			chanPoint, channel.ShortChanID,
+			watcher.SubscribeChannelEvents(),
+		)
+	}
+
+	// Channels which have been force closed by us are removed from the
+	// set of open channels, so we'll resume the arbitrator of each whose
+	// log shows it's yet to fully resolve the contract. As the utxo
+	// nursery may mark a channel as fully closed before its arbitrator
+	// has resolved every HTLC, we'll consult the log of each closed
+	// channel rather than only those pending.
+	closedChannels, err := c.chanDB.FetchClosedChannels(false)
+	if err != nil && err != channeldb.ErrNoClosedChannels {
+		return err
+	}
+	for _, closeSummary := range closedChannels {
+		if closeSummary.CloseType != channeldb.ForceClose {
+			continue
+		}
+
+		chanPoint := closeSummary.ChanPoint
+		state, err := newBoltArbitratorLog(
+			c.chanDB, chanPoint,
+		).CurrentState()
+		if err != nil {
+			return err
+		}
+		if state == StateDefault || state == StateFullyResolved {
+			continue
+		}
+
+		// The short channel ID is only required to resolve the
+		// contract, so the one recorded within the log is used.
+		c.activeChannels[chanPoint] = c.newChannelArbitrator(
+			chanPoint, lnwire.ShortChannelID{}, nil,
+		)
+	}
+
+	// The watchers are started before the arbitrators, ensuring each
+	// arbitrator is subscribed before any close can be detected.
+	for chanPoint, watcher := range c.activeWatchers {
+		if err := watcher.Start(); err != nil {
+			return fmt.Errorf("unable to start chain watcher for "+
+				"ChannelPoint(%v): %v", chanPoint, err)
+		}
+	}
+	for chanPoint, arbitrator := range c.activeChannels {
+		if err := arbitrator.Start(); err != nil {
+			return fmt.Errorf("unable to start arbitrator for "+
+				"ChannelPoint(%v): %v", chanPoint, err)
+		}
+	}
+
+	return nil
+}
+
+// Stop signals the ChainArbitrator, along with every ChannelArbitrator, to
+// gracefully exit.
+func (c *ChainArbitrator) Stop() error {
+	if !atomic.CompareAndSwapUint32(&c.stopped, 0, 1) {
+		return nil
+	}
+
+	log.Infof("Stopping ChainArbitrator")
+
+	c.Lock()
+	arbitrators := make([]*ChannelArbitrator, 0, len(c.activeChannels))
+	for _, arbitrator := range c.activeChannels {
+		arbitrators = append(arbitrators, arbitrator)
+	}
+	watchers := make([]*chainWatcher, 0, len(c.activeWatchers))
+	for _, watcher := range c.activeWatchers {
+		watchers = append(watchers, watcher)
+	}
+	c.Unlock()
+
+	for _, watcher := range watchers {
+		if err := watcher.Stop(); err != nil {
+			return err
+		}
+	}
+	for _, arbitrator := range arbitrators {
+		if err := arbitrator.Stop(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WatchNewChannel launches a chain watcher and arbitrator for a newly opened
+// channel.
+func (c *ChainArbitrator) WatchNewChannel(chanPoint wire.OutPoint,
+	shortChanID lnwire.ShortChannelID) error {
+
+	c.Lock()
+	defer c.Unlock()
+
+	if _, ok := c.activeChannels[chanPoint]; ok {
+		return nil
+	}
+
+	channel, err := c.chanDB.FetchChannel(chanPoint)
+	if err != nil {
+		return err
+	}
+
+	watcher := newChainWatcher(channel, c.cfg.Notifier)
+	arbitrator := c.newChannelArbitrator(
+		chanPoint, shortChanID, watcher.SubscribeChannelEvents(),
+	)
+	if err := watcher.Start(); err != nil {
+		return err
+	}
+	if err := arbitrator.Start(); err != nil {
+		watcher.Stop()
+		return err
+	}
+	c.activeWatchers[chanPoint] = watcher
+	c.activeChannels[chanPoint] = arbitrator
+
+	return nil
+}
+
+// ForceCloseContract force closes the channel with the passed funding
+// outpoint, returning our commitment transaction once it has been broadcast.
+// The outputs of the contract will then be resolved by the channel's
+// arbitrator.
+func (c *ChainArbitrator) ForceCloseContract(
+	chanPoint wire.OutPoint) (*wire.MsgTx, error) {
+
+	c.Lock()
+	arbitrator, ok := c.activeChannels[chanPoint]
+	c.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("unable to find arbitrator for "+
+			"ChannelPoint(%v)", chanPoint)
+	}
+
+	return arbitrator.ForceClose()
+}
+
+// ResolveContract notifies the ChainArbitrator that the channel with the
+// passed funding outpoint has been closed by means other than our own
+// commitment transaction. If the channel's arbitrator hasn't gone to chain,
+// then it's no longer needed, so it's stopped along with the channel's chain
+// watcher.
+func (c *ChainArbitrator) ResolveContract(chanPoint wire.OutPoint) error {
+	c.Lock()
+	arbitrator, ok := c.activeChannels[chanPoint]
+	watcher := c.activeWatchers[chanPoint]
+	c.Unlock()
+	if !ok {
+		return nil
+	}
+
+	// If the arbitrator has gone to chain, then the channel was closed by
+	// our own commitment, which it'll continue to resolve.
+	state, err := arbitrator.log.CurrentState()
+	if err != nil {
+		return err
+	}
+	if state != StateDefault {
+		return nil
+	}
+
+	c.Lock()
+	delete(c.activeChannels, chanPoint)
+	delete(c.activeWatchers, chanPoint)
+	c.Unlock()
+
+	if watcher != nil {
+		if err := watcher.Stop(); err != nil {
+			return err
+		}
+	}
+
+	return arbitrator.Stop()
+}
@@ -0,0 +1,432 @@
+package contractcourt
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// CloseType describes the manner in which the funding output of a channel was
+// spent.
+type CloseType uint8
+
+const (
+	// CooperativeClose indicates that the funding output was spent by a
+	// closing transaction negotiated by both parties.
+	CooperativeClose CloseType = iota
+
+	// LocalForceClose indicates that the funding output was spent by our
+	// latest commitment transaction.
+	LocalForceClose
+
+	// RemoteForceClose indicates that the funding output was spent by the
+	// remote party's latest, unrevoked commitment transaction.
+	RemoteForceClose
+
+	// BreachClose indicates that the funding output was spent by a
+	// commitment transaction of the remote party which has since been
+	// revoked.
+	BreachClose
+)
+
+// String returns a human readable string describing the CloseType.
+func (c CloseType) String() string {
+	switch c {
+	case CooperativeClose:
+		return "CooperativeClose"
+
+	case LocalForceClose:
+		return "LocalForceClose"
+
+	case RemoteForceClose:
+		return "RemoteForceClose"
+
+	case BreachClose:
+		return "BreachClose"
+
+	default:
+		return fmt.Sprintf("unknown close type: %d", c)
+	}
+}
+
+// ChainCloseInfo describes a spend of the funding output of a channel, along
+// with how it was classified.
+type ChainCloseInfo struct {
+	// SpendDetail describes the transaction which spent the funding
+	// output, and the height at which it was confirmed.
+	*chainntnfs.SpendDetail
+
+	// CloseType is the manner in which the channel was closed.
+	CloseType CloseType
+
+	// CommitHeight is the commitment height recovered from the state hint
+	// of the spending transaction. It's only set for force closes and
+	// breaches.
+	CommitHeight uint64
+
+	// Htlcs are the HTLCs which were active within our latest commitment
+	// when the spend was detected. It's nil if the state of the channel
+	// had already been deleted by then.
+	Htlcs []channeldb.HTLC
+}
+
+// ChainEventSubscription is a subscription to the close of a channel as
+// detected by its chainWatcher. Each type of close is delivered over its own
+// channel, allowing the subscriber to dispatch the correct handler.
+type ChainEventSubscription struct {
+	// ChanPoint is the funding outpoint of the watched channel.
+	ChanPoint wire.OutPoint
+
+	// CooperativeClosure is sent upon once a cooperative close of the
+	// channel is detected.
+	CooperativeClosure chan *ChainCloseInfo
+
+	// LocalUnilateralClosure is sent upon once our commitment transaction
+	// is detected spending the funding output.
+	LocalUnilateralClosure chan *ChainCloseInfo
+
+	// RemoteUnilateralClosure is sent upon once the remote party's latest
+	// commitment transaction is detected spending the funding output.
+	RemoteUnilateralClosure chan *ChainCloseInfo
+
+	// ContractBreach is sent upon once a revoked commitment transaction
+	// of the remote party is detected spending the funding output.
+	ContractBreach chan *ChainCloseInfo
+
+	// Cancel cancels the subscription, after which no further events
+	// will be sent.
+	Cancel func()
+}
+
+// chainWatcher is a sub-system which watches the chain for the spend of the
+// funding output of a single channel. Once spent, the spending transaction is
+// classified as one of the four ways a channel can be closed, and delivered
+// to each subscriber.
+type chainWatcher struct {
+	started uint32
+	stopped uint32
+
+	// chanState is the state of the watched channel when the watcher was
+	// created. As it's never updated, the state is re-read from the
+	// database once the funding output has been spent.
+	chanState *channeldb.OpenChannel
+
+	// notifier is used to register for the spend of the funding output.
+	notifier chainntnfs.ChainNotifier
+
+	// stateHintObfuscator is used to recover the commitment height from
+	// the state hint of a spending commitment transaction.
+	stateHintObfuscator [lnwallet.StateHintSize]byte
+
+	// clientSubscriptions is the set of active subscriptions, keyed by
+	// their ID.
+	clientSubscriptions map[uint64]*ChainEventSubscription
+	clientID            uint64
+	sync.Mutex
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newChainWatcher returns a new chainWatcher for the passed channel.
+func newChainWatcher(chanState *channeldb.OpenChannel,
+	notifier chainntnfs.ChainNotifier) *chainWatcher {
+
+	// The state hint obfuscator is derived from the payment base points
+	// of the initiator and responder, in that order.
+	var stateHint [lnwallet.StateHintSize]byte
+	if chanState.IsInitiator {
+		stateHint = lnwallet.DeriveStateHintObfuscator(
+			chanState.LocalChanCfg.PaymentBasePoint,
+			chanState.RemoteChanCfg.PaymentBasePoint,
+		)
+	} else {
+		stateHint = lnwallet.DeriveStateHintObfuscator(
+			chanState.RemoteChanCfg.PaymentBasePoint,
+			chanState.LocalChanCfg.PaymentBasePoint,
+		)
+	}
+
+	return &chainWatcher{
+		chanState:           chanState,
+		notifier:            notifier,
+		stateHintObfuscator: stateHint,
+		clientSubscriptions: make(map[uint64]*ChainEventSubscription),
+		quit:                make(chan struct{}),
+	}
+}
+
+// Start registers for the spend of the funding output, then launches the
+// goroutine which waits for it.
+func (c *chainWatcher) Start() error {
+	if !atomic.CompareAndSwapUint32(&c.started, 0, 1) {
+		return nil
+	}
+
+	fundingOut := c.chanState.FundingOutpoint
+
+	log.Debugf("Starting chain watcher for ChannelPoint(%v)", fundingOut)
+
+	spendNtfn, err := c.notifier.RegisterSpendNtfn(
+		&fundingOut, c.chanState.FundingBroadcastHeight,
+	)
+	if err != nil {
+		return err
+	}
+
+	c.wg.Add(1)
+	go c.closeObserver(spendNtfn)
+
+	return nil
+}
+
+// Stop signals the closeObserver goroutine to exit, then waits for it to do
+// so.
+func (c *chainWatcher) Stop() error {
+	if !atomic.CompareAndSwapUint32(&c.stopped, 0, 1) {
+		return nil
+	}
+
+	close(c.quit)
+	c.wg.Wait()
+
+	return nil
+}
+
+// SubscribeChannelEvents returns a new subscription to the close of the
+// watched channel.
+func (c *chainWatcher) SubscribeChannelEvents() *ChainEventSubscription {
+	c.Lock()
+	clientID := c.clientID
+	c.clientID++
+	c.Unlock()
+
+	sub := &ChainEventSubscription{
+		ChanPoint:               c.chanState.FundingOutpoint,
+		CooperativeClosure:      make(chan *ChainCloseInfo, 1),
+		LocalUnilateralClosure:  make(chan *ChainCloseInfo, 1),
+		RemoteUnilateralClosure: make(chan *ChainCloseInfo, 1),
+		ContractBreach:          make(chan *ChainCloseInfo, 1),
+		Cancel: func() {
+			c.Lock()
+			delete(c.clientSubscriptions, clientID)
+			c.Unlock()
+		},
+	}
+
+	c.Lock()
+	c.clientSubscriptions[clientID] = sub
+	c.Unlock()
+
+	return sub
+}
+
+// classifySpend determines which of the four types of close the passed
+// spend of the funding output represents, according to the passed state of
+// the channel. Commitment transactions are distinguished from cooperative
+// closes by the state hint encoded within their lock time, which also allows
+// the commitment height of a transaction broadcast by the remote party to be
+// recovered. A commitment of the remote party is only a breach if we've
+// received the revocation of its height.
+func (c *chainWatcher) classifySpend(chanState *channeldb.OpenChannel,
+	commitSpend *chainntnfs.SpendDetail) *ChainCloseInfo {
+
+	closeInfo := &ChainCloseInfo{
+		SpendDetail: commitSpend,
+	}
+
+	// Only commitment transactions have the upper bits of their lock
+	// time set to the fixed timelock shift, as otherwise the lock time
+	// of the state hint could be mistaken for a valid lock time.
+	spendingTx := commitSpend.SpendingTx
+	if spendingTx.LockTime&^0xFFFFFF != lnwallet.TimelockShift {
+		closeInfo.CloseType = CooperativeClose
+		return closeInfo
+	}
+
+	closeInfo.CommitHeight = lnwallet.GetStateNumHint(
+		spendingTx, c.stateHintObfuscator,
+	)
+
+	localCommitHash := chanState.CommitTx.TxHash()
+	switch {
+	case commitSpend.SpenderTxHash.IsEqual(&localCommitHash):
+		closeInfo.CloseType = LocalForceClose
+
+	// If we've received the revocation of the broadcast height, then the
+	// remote party has broadcast a revoked state.
+	case isRevoked(chanState, closeInfo.CommitHeight):
+		closeInfo.CloseType = BreachClose
+
+	// Otherwise, the remote party has broadcast a commitment which has
+	// yet to be revoked. Its height may differ from ours by one in either
+	// direction, depending on which party last initiated a state
+	// transition.
+	default:
+		closeInfo.CloseType = RemoteForceClose
+	}
+
+	return closeInfo
+}
+
+// isRevoked returns true if we've received the revocation of the remote
+// party's commitment at the passed height.
+func isRevoked(chanState *channeldb.OpenChannel, height uint64) bool {
+	if chanState.RevocationStore == nil {
+		return false
+	}
+
+	_, err := chanState.RevocationStore.LookUp(height)
+	return err == nil
+}
+
+// classifyClose re-reads the state of the watched channel, which will have
+// advanced since the watcher was created, then classifies the passed spend of
+// the funding output against it. Should the channel have already been closed
+// within the database by another sub-system reacting to the same spend, then
+// the close summary recorded for the channel is consulted instead.
+func (c *chainWatcher) classifyClose(
+	commitSpend *chainntnfs.SpendDetail) *ChainCloseInfo {
+
+	fundingOut := c.chanState.FundingOutpoint
+	db := c.chanState.Db
+
+	chanState, err := db.FetchChannel(fundingOut)
+	switch {
+	case err == nil:
+		closeInfo := c.classifySpend(chanState, commitSpend)
+		for _, htlc := range chanState.Htlcs {
+			closeInfo.Htlcs = append(closeInfo.Htlcs, *htlc)
+		}
+		return closeInfo
+
+	case err != channeldb.ErrChannelNotFound:
+		log.Errorf("unable to fetch state of ChannelPoint(%v), "+
+			"using state at startup: %v", fundingOut, err)
+		return c.classifySpend(c.chanState, commitSpend)
+	}
+
+	// The commitment height, and whether the spend is a cooperative
+	// close, don't depend on the state of the channel.
+	closeInfo := c.classifySpend(c.chanState, commitSpend)
+	if closeInfo.CloseType == CooperativeClose {
+		return closeInfo
+	}
+
+	closeSummary, err := fetchCloseSummary(db, fundingOut)
+	if err != nil {
+		log.Errorf("unable to fetch close summary of "+
+			"ChannelPoint(%v), using state at startup: %v",
+			fundingOut, err)
+		return closeInfo
+	}
+	if closeSummary.ClosingTXID != *commitSpend.SpenderTxHash {
+		return closeInfo
+	}
+
+	switch closeSummary.CloseType {
+	case channeldb.BreachClose:
+		closeInfo.CloseType = BreachClose
+
+	// Force closes of both parties are recorded alike, so our own
+	// commitment is recognized by the one logged by the channel's
+	// arbitrator before broadcasting it.
+	case channeldb.ForceClose:
+		closeInfo.CloseType = RemoteForceClose
+
+		resolutions, err := newBoltArbitratorLog(
+			db, fundingOut,
+		).FetchContractResolutions()
+		switch {
+		case err == nil:
+			commitHash := resolutions.CommitTx.TxHash()
+			if commitSpend.SpenderTxHash.IsEqual(&commitHash) {
+				closeInfo.CloseType = LocalForceClose
+			}
+
+		case err != ErrNoContractResolutions:
+			log.Errorf("unable to fetch contract resolutions of "+
+				"ChannelPoint(%v): %v", fundingOut, err)
+		}
+	}
+
+	return closeInfo
+}
+
+// fetchCloseSummary returns the close summary of the channel with the passed
+// funding outpoint.
+func fetchCloseSummary(db *channeldb.DB,
+	chanPoint wire.OutPoint) (*channeldb.ChannelCloseSummary, error) {
+
+	closeSummaries, err := db.FetchClosedChannels(false)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, closeSummary := range closeSummaries {
+		if closeSummary.ChanPoint == chanPoint {
+			return closeSummary, nil
+		}
+	}
+
+	return nil, channeldb.ErrChannelNotFound
+}
+
+// closeObserver waits for the spend of the funding output, then classifies
+// the spend and delivers it to each subscriber.
+//
+// NOTE: This MUST be run as a goroutine.
+func (c *chainWatcher) closeObserver(spendNtfn *chainntnfs.SpendEvent) {
+	defer c.wg.Done()
+	defer spendNtfn.Cancel()
+
+	var commitSpend *chainntnfs.SpendDetail
+	select {
+	case spend, ok := <-spendNtfn.Spend:
+		if !ok {
+			return
+		}
+		commitSpend = spend
+
+	case <-c.quit:
+		return
+	}
+
+	closeInfo := c.classifyClose(commitSpend)
+
+	log.Infof("ChannelPoint(%v) closed by %v at height %v: %v",
+		c.chanState.FundingOutpoint, commitSpend.SpenderTxHash,
+		commitSpend.SpendingHeight, closeInfo.CloseType)
+
+	c.Lock()
+	subs := make([]*ChainEventSubscription, 0, len(c.clientSubscriptions))
+	for _, sub := range c.clientSubscriptions {
+		subs = append(subs, sub)
+	}
+	c.Unlock()
+
+	for _, sub := range subs {
+		var eventChan chan *ChainCloseInfo
+		switch closeInfo.CloseType {
+		case CooperativeClose:
+			eventChan = sub.CooperativeClosure
+		case LocalForceClose:
+			eventChan = sub.LocalUnilateralClosure
+		case RemoteForceClose:
+			eventChan = sub.RemoteUnilateralClosure
+		case BreachClose:
+			eventChan = sub.ContractBreach
+		}
+
+		select {
+		case eventChan <- closeInfo:
+		case <-c.quit:
+			return
+		}
+	}
+}
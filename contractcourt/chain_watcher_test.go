@@ -0,0 +1,143 @@
+package contractcourt
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/shachain"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// TestChainWatcherClassifySpend checks that each type of spend of the funding
+// output is classified correctly, and that the commitment height of force
+// closes is recovered from their state hint. Commitments of the remote party
+// should only be classified as breaches once their height has been revoked.
+func TestChainWatcherClassifySpend(t *testing.T) {
+	t.Parallel()
+
+	localKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+	remoteKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+
+	// The remote party has revoked every commitment below their current
+	// one, which lags a height behind ours as they've yet to receive our
+	// signature for the latest state.
+	const numUpdates = 5
+	producer := shachain.NewRevocationProducer(chainhash.Hash{1})
+	store := shachain.NewRevocationStore()
+	for i := uint64(0); i < numUpdates-1; i++ {
+		revocation, err := producer.AtIndex(i)
+		if err != nil {
+			t.Fatalf("unable to produce revocation: %v", err)
+		}
+		if err := store.AddNextEntry(revocation); err != nil {
+			t.Fatalf("unable to store revocation: %v", err)
+		}
+	}
+
+	chanState := &channeldb.OpenChannel{
+		FundingOutpoint: testChanPoint,
+		IsInitiator:     true,
+		NumUpdates:      numUpdates,
+		RevocationStore: store,
+	}
+	chanState.LocalChanCfg.PaymentBasePoint = localKey.PubKey()
+	chanState.RemoteChanCfg.PaymentBasePoint = remoteKey.PubKey()
+
+	watcher := newChainWatcher(chanState, nil)
+
+	// newCommitment returns a commitment spending the funding output with
+	// the passed state hint, with an output distinguishing it from the
+	// others.
+	newCommitment := func(height uint64, value int64) *wire.MsgTx {
+		tx := wire.NewMsgTx(2)
+		tx.AddTxIn(&wire.TxIn{PreviousOutPoint: testChanPoint})
+		tx.AddTxOut(&wire.TxOut{Value: value})
+
+		err := lnwallet.SetStateNumHint(
+			tx, height, watcher.stateHintObfuscator,
+		)
+		if err != nil {
+			t.Fatalf("unable to set state hint: %v", err)
+		}
+
+		return tx
+	}
+
+	chanState.CommitTx = *newCommitment(numUpdates, 1)
+
+	coopTx := wire.NewMsgTx(2)
+	coopTx.AddTxIn(wire.NewTxIn(&testChanPoint, nil, nil))
+	coopTx.AddTxOut(&wire.TxOut{Value: 2})
+
+	testCases := []struct {
+		name         string
+		tx           *wire.MsgTx
+		closeType    CloseType
+		commitHeight uint64
+	}{
+		{
+			name:      "cooperative close",
+			tx:        coopTx,
+			closeType: CooperativeClose,
+		},
+		{
+			name:         "local force close",
+			tx:           &chanState.CommitTx,
+			closeType:    LocalForceClose,
+			commitHeight: numUpdates,
+		},
+		{
+			name:         "remote force close",
+			tx:           newCommitment(numUpdates, 3),
+			closeType:    RemoteForceClose,
+			commitHeight: numUpdates,
+		},
+		{
+			name:         "remote force close of lagging state",
+			tx:           newCommitment(numUpdates-1, 6),
+			closeType:    RemoteForceClose,
+			commitHeight: numUpdates - 1,
+		},
+		{
+			name:         "remote force close of pending state",
+			tx:           newCommitment(numUpdates+1, 4),
+			closeType:    RemoteForceClose,
+			commitHeight: numUpdates + 1,
+		},
+		{
+			name:         "breach",
+			tx:           newCommitment(numUpdates-2, 5),
+			closeType:    BreachClose,
+			commitHeight: numUpdates - 2,
+		},
+	}
+
+	for _, test := range testCases {
+		txHash := test.tx.TxHash()
+		closeInfo := watcher.classifySpend(chanState, &chainntnfs.SpendDetail{
+			SpentOutPoint: &testChanPoint,
+			SpenderTxHash: &txHash,
+			SpendingTx:    test.tx,
+		})
+
+		if closeInfo.CloseType != test.closeType {
+			t.Fatalf("%v: expected %v, got %v", test.name,
+				test.closeType, closeInfo.CloseType)
+		}
+		if closeInfo.CommitHeight != test.commitHeight {
+			t.Fatalf("%v: expected commit height %v, got %v",
+				test.name, test.commitHeight,
+				closeInfo.CommitHeight)
+		}
+	}
+}
@@ -0,0 +1,1003 @@
+package contractcourt
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// ChainAction is an enum that describes the action to be taken for an HTLC
+// once the channel has gone to chain.
+type ChainAction uint8
+
+const (
+	// NoAction is the action taken for an HTLC which requires nothing of
+	// us on-chain, such as an incoming HTLC for which we lack the
+	// preimage, which will be timed out by the remote party.
+	NoAction ChainAction = 0
+
+	// HtlcTimeoutAction indicates that an outgoing HTLC is to be timed
+	// out on-chain with its second-level timeout transaction once it has
+	// expired.
+	HtlcTimeoutAction ChainAction = 1
+
+	// HtlcClaimAction indicates that an incoming HTLC, for which we know
+	// the preimage, is to be claimed on-chain before it expires.
+	HtlcClaimAction ChainAction = 2
+
+	// HtlcFailNowAction indicates that an outgoing HTLC can be failed
+	// back immediately once the commitment has confirmed, as it was
+	// trimmed as dust, so can't be claimed on-chain by either party.
+	HtlcFailNowAction ChainAction = 3
+)
+
+// String returns a human readable string describing the ChainAction.
+func (c ChainAction) String() string {
+	switch c {
+	case NoAction:
+		return "NoAction"
+
+	case HtlcTimeoutAction:
+		return "HtlcTimeoutAction"
+
+	case HtlcClaimAction:
+		return "HtlcClaimAction"
+
+	case HtlcFailNowAction:
+		return "HtlcFailNowAction"
+
+	default:
+		return fmt.Sprintf("unknown action: %d", c)
+	}
+}
+
+// ChainActionMap is a map of each chain action to the HTLCs it's to be taken
+// for.
+type ChainActionMap map[ChainAction][]channeldb.HTLC
+
+// transitionTrigger is an enum that denotes what caused the channel
+// arbitrator to attempt a state transition.
+type transitionTrigger uint8
+
+const (
+	// chainTrigger denotes that a new block has been connected, which may
+	// require us to go to chain to protect an HTLC nearing its expiry.
+	chainTrigger transitionTrigger = iota
+
+	// userTrigger denotes that the user has requested that the channel be
+	// force closed.
+	userTrigger
+
+	// confTrigger denotes that our commitment transaction has been
+	// confirmed.
+	confTrigger
+
+	// resolvedTrigger denotes that one of the contract's outputs has been
+	// fully resolved.
+	resolvedTrigger
+)
+
+// ChannelArbitratorConfig houses the channel specific configuration of a
+// ChannelArbitrator, along with the closures it requires to take action upon
+// the channel.
+type ChannelArbitratorConfig struct {
+	// ChanPoint is the funding outpoint of the channel being arbitrated.
+	ChanPoint wire.OutPoint
+
+	// ShortChanID is the short channel ID of the channel being
+	// arbitrated.
+	ShortChanID lnwire.ShortChannelID
+
+	// FetchHtlcs returns the HTLCs active within our latest commitment
+	// transaction.
+	FetchHtlcs func() ([]channeldb.HTLC, error)
+
+	// MarkLinkInactive is called once we've decided to go to chain, to
+	// ensure no further updates are made to the channel.
+	MarkLinkInactive func() error
+
+	// ForceCloseChan creates a signed version of our latest commitment
+	// transaction, along with the information required to resolve its
+	// outputs. The HTLCs present on the commitment are returned as well.
+	// The commitment isn't broadcast. If the channel has already been
+	// marked as closed, then channeldb.ErrChannelNotFound is returned.
+	ForceCloseChan func() (*lnwallet.ForceCloseSummary,
+		[]channeldb.HTLC, error)
+
+	// MarkChannelClosed marks the channel as pending closed once our
+	// commitment transaction has been broadcast, handing our delayed
+	// commitment output and outgoing HTLC outputs over to the utxo
+	// nursery. It returns true if any outputs were handed over.
+	MarkChannelClosed func(*lnwallet.ForceCloseSummary) (bool, error)
+
+	// MarkChannelResolved marks the channel as fully closed once every
+	// output of the contract has been resolved.
+	MarkChannelResolved func() error
+
+	// ChainEvents is the subscription to the close of the channel, as
+	// detected by its chain watcher. This is nil if the channel was
+	// already closed by our commitment when the arbitrator was created.
+	ChainEvents *ChainEventSubscription
+
+	ChainArbitratorConfig
+}
+
+// forceCloseReq is a request sent to the channel arbitrator to force close the
+// channel.
+type forceCloseReq struct {
+	// closeTx is sent our commitment transaction once it's been
+	// broadcast.
+	closeTx chan *wire.MsgTx
+
+	// errResp is sent upon should the force close fail.
+	errResp chan error
+}
+
+// ChannelArbitrator is the on-chain arbiter of a single channel. It tracks the
+// state of the channel's contract, deciding when the channel must be taken to
+// chain to protect an HTLC nearing its expiry, or when the user requests it.
+// Once our commitment transaction has been broadcast, it drives the resolution
+// of each of the contract's outputs until the contract has been fully
+// resolved. Every decision taken along the way is persisted within its
+// ArbitratorLog, allowing it to resume after a restart.
+type ChannelArbitrator struct {
+	started uint32
+	stopped uint32
+
+	cfg ChannelArbitratorConfig
+
+	log ArbitratorLog
+
+	// state is the current state of the arbitrator.
+	//
+	// NOTE: This MUST only be accessed from the channelAttendant
+	// goroutine.
+	state ArbitratorState
+
+	// activeResolvers is the set of resolvers currently resolving the
+	// outputs of the contract.
+	activeResolvers []ContractResolver
+
+	// resolvedContracts is sent the key of each resolver once it has
+	// fully resolved its output.
+	resolvedContracts chan []byte
+
+	// forceCloseReqs is sent upon to request the channel be force
+	// closed.
+	forceCloseReqs chan *forceCloseReq
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewChannelArbitrator returns a new instance of a ChannelArbitrator backed by
+// the passed log.
+func NewChannelArbitrator(cfg ChannelArbitratorConfig,
+	log ArbitratorLog) *ChannelArbitrator {
+
+	return &ChannelArbitrator{
+		cfg:               cfg,
+		log:               log,
+		resolvedContracts: make(chan []byte),
+		forceCloseReqs:    make(chan *forceCloseReq),
+		quit:              make(chan struct{}),
+	}
+}
+
+// Start starts all the goroutines that the ChannelArbitrator needs to operate,
+// resuming from the state recorded within its log.
+func (c *ChannelArbitrator) Start() error {
+	if !atomic.CompareAndSwapUint32(&c.started, 0, 1) {
+		return nil
+	}
+
+	var err error
+	c.state, err = c.log.CurrentState()
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("Starting ChannelArbitrator(%v), state=%v",
+		c.cfg.ChanPoint, c.state)
+
+	blockEpochs, err := c.cfg.Notifier.RegisterBlockEpochNtfn()
+	if err != nil {
+		return err
+	}
+
+	// If we were waiting on our commitment to confirm, or to resolve the
+	// outputs of the contract, then we'll pick up where we left off.
+	var confNtfn *chainntnfs.ConfirmationEvent
+	switch c.state {
+	case StateCommitmentBroadcasted:
+		confNtfn, err = c.rebroadcastCommitment()
+		if err != nil {
+			blockEpochs.Cancel()
+			return err
+		}
+
+	case StateWaitingFullResolution:
+		if err := c.launchResolvers(); err != nil {
+			blockEpochs.Cancel()
+			return err
+		}
+	}
+
+	c.wg.Add(1)
+	go c.channelAttendant(blockEpochs, confNtfn)
+
+	return nil
+}
+
+// Stop signals the ChannelArbitrator, along with any active resolvers, to
+// gracefully exit.
+func (c *ChannelArbitrator) Stop() error {
+	if !atomic.CompareAndSwapUint32(&c.stopped, 0, 1) {
+		return nil
+	}
+
+	log.Debugf("Stopping ChannelArbitrator(%v)", c.cfg.ChanPoint)
+
+	close(c.quit)
+	c.wg.Wait()
+
+	return nil
+}
+
+// ForceClose requests that the channel be force closed, returning our
+// commitment transaction once it has been broadcast.
+func (c *ChannelArbitrator) ForceClose() (*wire.MsgTx, error) {
+	req := &forceCloseReq{
+		closeTx: make(chan *wire.MsgTx, 1),
+		errResp: make(chan error, 1),
+	}
+
+	select {
+	case c.forceCloseReqs <- req:
+	case <-c.quit:
+		return nil, fmt.Errorf("channel arbitrator exiting")
+	}
+
+	select {
+	case closeTx := <-req.closeTx:
+		return closeTx, nil
+	case err := <-req.errResp:
+		return nil, err
+	case <-c.quit:
+		return nil, fmt.Errorf("channel arbitrator exiting")
+	}
+}
+
+// checkChainActions determines the action to be taken on-chain for each of the
+// passed HTLCs, and whether we need to go to chain at the passed height to
+// protect any of them. We go to chain once an outgoing HTLC is close enough to
+// its expiry that we must be able to time it out on-chain before our incoming
+// HTLC expires upstream, or once an incoming HTLC, for which we know the
+// preimage, is close enough to its expiry that the remote party may soon be
+// able to time it out. HTLCs which were trimmed as dust never require us to go
+// to chain, as they have no output to claim.
+func (c *ChannelArbitrator) checkChainActions(height uint32,
+	htlcs []channeldb.HTLC) (ChainActionMap, bool) {
+
+	actions := make(ChainActionMap)
+	var goToChain bool
+	for _, htlc := range htlcs {
+		isDust := htlc.OutputIndex < 0
+
+		switch {
+		// An outgoing dust HTLC can't be timed out on-chain, so it'll
+		// be failed back as soon as the commitment confirms.
+		case !htlc.Incoming && isDust:
+			actions[HtlcFailNowAction] = append(
+				actions[HtlcFailNowAction], htlc,
+			)
+
+		case !htlc.Incoming:
+			actions[HtlcTimeoutAction] = append(
+				actions[HtlcTimeoutAction], htlc,
+			)
+
+			deadline := height + c.cfg.OutgoingBroadcastDelta
+			if deadline >= htlc.RefundTimeout {
+				log.Infof("ChannelArbitrator(%v): outgoing "+
+					"htlc %x expires at height %v, going "+
+					"to chain at height %v",
+					c.cfg.ChanPoint, htlc.RHash[:],
+					htlc.RefundTimeout, height)
+
+				goToChain = true
+			}
+
+		// Incoming HTLCs are only worth going to chain for if we're
+		// able to claim them.
+		case isDust:
+
+		default:
+			_, ok := c.cfg.PreimageDB.LookupPreimage(htlc.RHash[:])
+			if !ok {
+				continue
+			}
+
+			actions[HtlcClaimAction] = append(
+				actions[HtlcClaimAction], htlc,
+			)
+
+			deadline := height + c.cfg.IncomingBroadcastDelta
+			if deadline >= htlc.RefundTimeout {
+				log.Infof("ChannelArbitrator(%v): incoming "+
+					"htlc %x expires at height %v, going "+
+					"to chain at height %v",
+					c.cfg.ChanPoint, htlc.RHash[:],
+					htlc.RefundTimeout, height)
+
+				goToChain = true
+			}
+		}
+	}
+
+	return actions, goToChain
+}
+
+// stateStep executes a single state transition in response to the passed
+// trigger, returning the next state of the arbitrator. If our commitment
+// transaction has just been broadcast, then it's returned as well.
+func (c *ChannelArbitrator) stateStep(height uint32,
+	trigger transitionTrigger) (ArbitratorState, *wire.MsgTx, error) {
+
+	var closeTx *wire.MsgTx
+
+	switch c.state {
+	// In the default state, we'll go to chain only if the user has
+	// requested it, or if an HTLC is nearing its expiry.
+	case StateDefault:
+		switch trigger {
+		case userTrigger:
+			log.Infof("ChannelArbitrator(%v): force close "+
+				"requested by user", c.cfg.ChanPoint)
+
+		case chainTrigger:
+			htlcs, err := c.cfg.FetchHtlcs()
+			if err != nil {
+				return c.state, nil, err
+			}
+
+			_, goToChain := c.checkChainActions(height, htlcs)
+			if !goToChain {
+				return c.state, nil, nil
+			}
+
+		default:
+			return c.state, nil, nil
+		}
+
+		if err := c.log.CommitState(StateBroadcastCommit); err != nil {
+			return c.state, nil, err
+		}
+
+		return StateBroadcastCommit, nil, nil
+
+	// We've decided to go to chain, so we'll ensure no further updates
+	// are made to the channel, then broadcast our latest commitment.
+	case StateBroadcastCommit:
+		if err := c.cfg.MarkLinkInactive(); err != nil {
+			return c.state, nil, err
+		}
+
+		closeSummary, htlcs, err := c.cfg.ForceCloseChan()
+		switch {
+		// If the channel has already been marked as closed, then our
+		// commitment was broadcast before we restarted, and our
+		// decisions have already been logged.
+		case err == channeldb.ErrChannelNotFound:
+			resolutions, err := c.log.FetchContractResolutions()
+			if err != nil {
+				return c.state, nil, err
+			}
+			closeTx = resolutions.CommitTx
+
+		case err != nil:
+			return c.state, nil, err
+
+		default:
+			// With the link inactive, the set of HTLCs on our
+			// commitment is now fixed, so we'll log the action to
+			// be taken for each of them once it confirms.
+			actions, _ := c.checkChainActions(height, htlcs)
+			if err := c.log.LogChainActions(actions); err != nil {
+				return c.state, nil, err
+			}
+
+			resolutions := &ContractResolutions{
+				CommitTx:    closeSummary.CloseTx,
+				ShortChanID: c.cfg.ShortChanID,
+			}
+			for _, res := range closeSummary.HtlcResolutions {
+				// Incoming and dust HTLCs lack a resolution.
+				if res.SignedTimeoutTx == nil {
+					continue
+				}
+
+				resolutions.HtlcResolutions = append(
+					resolutions.HtlcResolutions, res,
+				)
+			}
+
+			// Each incoming HTLC resolution is logged, though only
+			// those we know the preimage of will be claimed.
+			resolutions.IncomingHtlcResolutions =
+				closeSummary.IncomingHtlcResolutions
+			err = c.log.LogContractResolutions(resolutions)
+			if err != nil {
+				return c.state, nil, err
+			}
+
+			closeTx = closeSummary.CloseTx
+			log.Infof("ChannelArbitrator(%v): broadcasting "+
+				"commitment %v", c.cfg.ChanPoint,
+				closeTx.TxHash())
+
+			if err := c.cfg.PublishTx(closeTx); err != nil {
+				return c.state, nil, err
+			}
+
+			incubated, err := c.cfg.MarkChannelClosed(closeSummary)
+			if err != nil {
+				return c.state, nil, err
+			}
+
+			if incubated {
+				resolutions.OutputsIncubated = true
+				err := c.log.LogContractResolutions(resolutions)
+				if err != nil {
+					return c.state, nil, err
+				}
+			}
+		}
+
+		err = c.log.CommitState(StateCommitmentBroadcasted)
+		if err != nil {
+			return c.state, nil, err
+		}
+
+		return StateCommitmentBroadcasted, closeTx, nil
+
+	// We're waiting for our commitment to confirm, after which we can
+	// begin to resolve the outputs of the contract.
+	case StateCommitmentBroadcasted:
+		if trigger != confTrigger {
+			return c.state, nil, nil
+		}
+
+		log.Infof("ChannelArbitrator(%v): commitment confirmed, "+
+			"resolving contract", c.cfg.ChanPoint)
+
+		if err := c.log.CommitState(StateContractClosed); err != nil {
+			return c.state, nil, err
+		}
+
+		return StateContractClosed, nil, nil
+
+	// Our commitment has confirmed, so we'll fail back any dust HTLCs,
+	// then launch the resolvers of the remaining outputs.
+	case StateContractClosed:
+		resolutions, err := c.log.FetchContractResolutions()
+		if err != nil {
+			return c.state, nil, err
+		}
+		actions, err := c.log.FetchChainActions()
+		if err != nil && err != ErrNoChainActions {
+			return c.state, nil, err
+		}
+
+		var msgs []ResolutionMsg
+		for _, htlc := range actions[HtlcFailNowAction] {
+			msgs = append(msgs, ResolutionMsg{
+				SourceChan: resolutions.ShortChanID,
+				PayHash:    htlc.RHash,
+				Amt:        htlc.Amt,
+				Failure:    &lnwire.FailPermanentChannelFailure{},
+			})
+		}
+		if len(msgs) != 0 {
+			if err := c.cfg.DeliverResolutionMsg(msgs...); err != nil {
+				return c.state, nil, err
+			}
+		}
+
+		err = c.log.CommitState(StateWaitingFullResolution)
+		if err != nil {
+			return c.state, nil, err
+		}
+
+		if err := c.launchResolvers(); err != nil {
+			return c.state, nil, err
+		}
+
+		return StateWaitingFullResolution, nil, nil
+
+	// We're waiting for the resolvers of the contract to complete. Once
+	// they all have, the contract is fully resolved.
+	case StateWaitingFullResolution:
+		if len(c.activeResolvers) != 0 {
+			return c.state, nil, nil
+		}
+
+		// If any outputs are still being incubated, then the utxo
+		// nursery will mark the channel as fully closed once they've
+		// all been swept.
+		resolutions, err := c.log.FetchContractResolutions()
+		if err != nil {
+			return c.state, nil, err
+		}
+		if !resolutions.OutputsIncubated {
+			if err := c.cfg.MarkChannelResolved(); err != nil {
+				return c.state, nil, err
+			}
+		}
+
+		log.Infof("ChannelArbitrator(%v): contract fully resolved",
+			c.cfg.ChanPoint)
+
+		if err := c.log.CommitState(StateFullyResolved); err != nil {
+			return c.state, nil, err
+		}
+
+		return StateFullyResolved, nil, nil
+
+	default:
+		return c.state, nil, nil
+	}
+}
+
+// advanceState executes state transitions in response to the passed trigger
+// until the arbitrator reaches a state which requires an external event to
+// progress. Our commitment transaction is returned if it was broadcast along
+// the way.
+func (c *ChannelArbitrator) advanceState(height uint32,
+	trigger transitionTrigger) (*wire.MsgTx, error) {
+
+	var closeTx *wire.MsgTx
+	for {
+		priorState := c.state
+
+		nextState, tx, err := c.stateStep(height, trigger)
+		if err != nil {
+			log.Errorf("ChannelArbitrator(%v): unable to advance "+
+				"state from %v: %v", c.cfg.ChanPoint,
+				priorState, err)
+			return closeTx, err
+		}
+		if tx != nil {
+			closeTx = tx
+		}
+
+		if nextState == priorState {
+			return closeTx, nil
+		}
+
+		log.Debugf("ChannelArbitrator(%v): state transition %v -> %v",
+			c.cfg.ChanPoint, priorState, nextState)
+
+		c.state = nextState
+	}
+}
+
+// rebroadcastCommitment re-broadcasts our logged commitment transaction, to
+// ensure it propagates, and registers for its confirmation.
+func (c *ChannelArbitrator) rebroadcastCommitment() (
+	*chainntnfs.ConfirmationEvent, error) {
+
+	resolutions, err := c.log.FetchContractResolutions()
+	if err != nil {
+		return nil, err
+	}
+
+	// The commitment may well have been broadcast or confirmed already,
+	// so a failure here isn't an error.
+	if err := c.cfg.PublishTx(resolutions.CommitTx); err != nil {
+		log.Debugf("ChannelArbitrator(%v): unable to rebroadcast "+
+			"commitment: %v", c.cfg.ChanPoint, err)
+	}
+
+	return c.commitConfNtfn(resolutions.CommitTx)
+}
+
+// commitConfNtfn registers for the confirmation of our broadcast commitment
+// transaction.
+func (c *ChannelArbitrator) commitConfNtfn(
+	commitTx *wire.MsgTx) (*chainntnfs.ConfirmationEvent, error) {
+
+	_, height, err := c.cfg.ChainIO.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	commitHash := commitTx.TxHash()
+	return c.cfg.Notifier.RegisterConfirmationsNtfn(
+		&commitHash, 1, uint32(height),
+	)
+}
+
+// launchResolvers launches a resolver for each output of the contract which
+// hasn't yet been fully resolved.
+func (c *ChannelArbitrator) launchResolvers() error {
+	resolutions, err := c.log.FetchContractResolutions()
+	if err != nil {
+		return err
+	}
+	actions, err := c.log.FetchChainActions()
+	if err != nil && err != ErrNoChainActions {
+		return err
+	}
+
+	// Each HTLC resolution is matched with its HTLC by the output it
+	// spends on our commitment transaction.
+	timeoutHtlcs := make(map[uint32]channeldb.HTLC)
+	for _, htlc := range actions[HtlcTimeoutAction] {
+		timeoutHtlcs[uint32(htlc.OutputIndex)] = htlc
+	}
+	claimHtlcs := make(map[uint32]channeldb.HTLC)
+	for _, htlc := range actions[HtlcClaimAction] {
+		claimHtlcs[uint32(htlc.OutputIndex)] = htlc
+	}
+
+	var resolvers []ContractResolver
+
+	for _, res := range resolutions.HtlcResolutions {
+		htlcOutpoint := res.SignedTimeoutTx.TxIn[0].PreviousOutPoint
+		htlc, ok := timeoutHtlcs[htlcOutpoint.Index]
+		if !ok {
+			return fmt.Errorf("no htlc found for resolution "+
+				"spending %v", htlcOutpoint)
+		}
+
+		resolvers = append(resolvers, newHtlcTimeoutResolver(
+			res, htlc, resolutions.ShortChanID,
+			c.cfg.ChainArbitratorConfig, c.quit,
+		))
+	}
+
+	// Incoming HTLCs are only claimed if we knew their preimage when
+	// going to chain. The remainder will be timed out by the remote
+	// party.
+	for _, res := range resolutions.IncomingHtlcResolutions {
+		htlcOutpoint := res.SignedSuccessTx.TxIn[0].PreviousOutPoint
+		htlc, ok := claimHtlcs[htlcOutpoint.Index]
+		if !ok {
+			continue
+		}
+
+		resolvers = append(resolvers, newHtlcSuccessResolver(
+			res, htlc, c.log, c.cfg.ChainArbitratorConfig, c.quit,
+		))
+	}
+
+	for _, resolver := range resolvers {
+		isResolved, err := c.log.IsResolved(resolver.ResolverKey())
+		if err != nil {
+			return err
+		}
+		if isResolved {
+			continue
+		}
+
+		c.activeResolvers = append(c.activeResolvers, resolver)
+	}
+
+	log.Infof("ChannelArbitrator(%v): launching %v contract resolvers",
+		c.cfg.ChanPoint, len(c.activeResolvers))
+
+	for _, resolver := range c.activeResolvers {
+		c.wg.Add(1)
+		go c.resolveContract(resolver)
+	}
+
+	return nil
+}
+
+// resolveContract drives the passed resolver until its output has been fully
+// resolved, then notifies the channelAttendant.
+//
+// NOTE: This MUST be run as a goroutine.
+func (c *ChannelArbitrator) resolveContract(resolver ContractResolver) {
+	defer c.wg.Done()
+
+	if err := resolver.Resolve(); err != nil {
+		if err != errResolverShuttingDown {
+			log.Errorf("ChannelArbitrator(%v): unable to resolve "+
+				"contract: %v", c.cfg.ChanPoint, err)
+		}
+		return
+	}
+
+	select {
+	case c.resolvedContracts <- resolver.ResolverKey():
+	case <-c.quit:
+	}
+}
+
+// channelAttendant is the primary goroutine of the ChannelArbitrator. It
+// advances the arbitrator's state machine in response to new blocks, user
+// requests, the confirmation of our commitment, and the resolution of the
+// contract's outputs.
+//
+// NOTE: This MUST be run as a goroutine.
+func (c *ChannelArbitrator) channelAttendant(
+	blockEpochs *chainntnfs.BlockEpochEvent,
+	confNtfn *chainntnfs.ConfirmationEvent) {
+
+	defer c.wg.Done()
+	defer blockEpochs.Cancel()
+
+	// confChan is only set while we're waiting for our commitment to
+	// confirm.
+	var confChan chan *chainntnfs.TxConfirmation
+	if confNtfn != nil {
+		confChan = confNtfn.Confirmed
+	}
+
+	// The close of the channel is only watched for if it was still open
+	// when the arbitrator was created.
+	var (
+		coopCloseChan   chan *ChainCloseInfo
+		localCloseChan  chan *ChainCloseInfo
+		remoteCloseChan chan *ChainCloseInfo
+		breachChan      chan *ChainCloseInfo
+	)
+
+	// remoteConfChan is only set while we're waiting for a commitment of
+	// the remote party, described by remoteClose, to confirm.
+	var (
+		remoteConfChan chan *chainntnfs.TxConfirmation
+		remoteClose    *ChainCloseInfo
+	)
+	if c.cfg.ChainEvents != nil {
+		defer c.cfg.ChainEvents.Cancel()
+
+		coopCloseChan = c.cfg.ChainEvents.CooperativeClosure
+		localCloseChan = c.cfg.ChainEvents.LocalUnilateralClosure
+		remoteCloseChan = c.cfg.ChainEvents.RemoteUnilateralClosure
+		breachChan = c.cfg.ChainEvents.ContractBreach
+	}
+
+	var height uint32
+	for {
+		var (
+			closeTx *wire.MsgTx
+			err     error
+		)
+
+		select {
+		case epoch, ok := <-blockEpochs.Epochs:
+			if !ok {
+				return
+			}
+			height = uint32(epoch.Height)
+
+			// We mustn't go to chain ourselves while a commitment
+			// of the remote party is awaiting confirmation.
+			if remoteClose != nil {
+				continue
+			}
+
+			closeTx, err = c.advanceState(height, chainTrigger)
+
+		case req := <-c.forceCloseReqs:
+			if remoteClose != nil || (c.state != StateDefault &&
+				c.state != StateBroadcastCommit) {
+
+				req.errResp <- errors.New("channel is " +
+					"already being closed on-chain")
+				continue
+			}
+
+			closeTx, err = c.advanceState(height, userTrigger)
+			if err != nil {
+				req.errResp <- err
+				continue
+			}
+			req.closeTx <- closeTx
+
+		case conf, ok := <-confChan:
+			if !ok {
+				return
+			}
+			confChan = nil
+
+			_, err = c.advanceState(conf.BlockHeight, confTrigger)
+
+		case key := <-c.resolvedContracts:
+			if err := c.log.MarkResolved(key); err != nil {
+				log.Errorf("ChannelArbitrator(%v): unable to "+
+					"mark contract resolved: %v",
+					c.cfg.ChanPoint, err)
+				continue
+			}
+
+			for i, resolver := range c.activeResolvers {
+				if !bytes.Equal(resolver.ResolverKey(), key) {
+					continue
+				}
+
+				c.activeResolvers = append(
+					c.activeResolvers[:i],
+					c.activeResolvers[i+1:]...,
+				)
+				break
+			}
+
+			_, err = c.advanceState(height, resolvedTrigger)
+
+		// Our commitment has been detected spending the funding
+		// output. Unless we broadcast it ourselves, this is unexpected,
+		// as we'll lack the resolutions required to sweep its outputs.
+		case closeInfo := <-localCloseChan:
+			if c.state == StateDefault {
+				log.Warnf("ChannelArbitrator(%v): commitment "+
+					"%v broadcast without arbitration",
+					c.cfg.ChanPoint,
+					closeInfo.SpenderTxHash)
+				continue
+			}
+
+			log.Infof("ChannelArbitrator(%v): commitment %v "+
+				"detected on-chain", c.cfg.ChanPoint,
+				closeInfo.SpenderTxHash)
+
+		// The channel has been closed cooperatively, which needs no
+		// further action on our part, so we'll exit.
+		case closeInfo := <-coopCloseChan:
+			if c.handleExternalClose(closeInfo) {
+				return
+			}
+
+		// The remote party has broadcast one of their commitments,
+		// whose outputs are swept by the breach arbiter. We'll wait
+		// for it to confirm before resolving the HTLCs it can no
+		// longer be used to claim.
+		case closeInfo := <-remoteCloseChan:
+			confChan := c.handleRemoteClose(closeInfo)
+			if confChan != nil {
+				remoteConfChan, remoteClose = confChan, closeInfo
+			}
+		case closeInfo := <-breachChan:
+			confChan := c.handleRemoteClose(closeInfo)
+			if confChan != nil {
+				remoteConfChan, remoteClose = confChan, closeInfo
+			}
+
+		case _, ok := <-remoteConfChan:
+			if !ok {
+				return
+			}
+
+			c.resolveRemoteClose(remoteClose)
+			return
+
+		case <-c.quit:
+			return
+		}
+
+		// If we've just broadcast our commitment, then we'll now wait
+		// for it to confirm.
+		if err != nil || closeTx == nil {
+			continue
+		}
+		confNtfn, err := c.commitConfNtfn(closeTx)
+		if err != nil {
+			log.Errorf("ChannelArbitrator(%v): unable to register "+
+				"for commitment confirmation: %v",
+				c.cfg.ChanPoint, err)
+			continue
+		}
+		confChan = confNtfn.Confirmed
+	}
+}
+
+// handleRemoteClose handles the broadcast of a commitment of the remote party,
+// either their valid commitment or a revoked one. If we haven't gone to chain
+// ourselves, then the link is marked inactive, as the channel can no longer
+// be updated, and the confirmation of the commitment is registered for. The
+// channel is returned over which the confirmation will be delivered, or nil
+// should there be nothing further for us to do.
+func (c *ChannelArbitrator) handleRemoteClose(
+	closeInfo *ChainCloseInfo) chan *chainntnfs.TxConfirmation {
+
+	if c.state != StateDefault {
+		c.handleExternalClose(closeInfo)
+		return nil
+	}
+
+	log.Infof("ChannelArbitrator(%v): %v by %v at commit height %v "+
+		"detected, waiting for confirmation", c.cfg.ChanPoint,
+		closeInfo.CloseType, closeInfo.SpenderTxHash,
+		closeInfo.CommitHeight)
+
+	if err := c.cfg.MarkLinkInactive(); err != nil {
+		log.Errorf("ChannelArbitrator(%v): unable to mark link "+
+			"inactive: %v", c.cfg.ChanPoint, err)
+	}
+
+	confNtfn, err := c.cfg.Notifier.RegisterConfirmationsNtfn(
+		closeInfo.SpenderTxHash, 1, uint32(closeInfo.SpendingHeight),
+	)
+	if err != nil {
+		log.Errorf("ChannelArbitrator(%v): unable to register for "+
+			"confirmation of %v: %v", c.cfg.ChanPoint,
+			closeInfo.SpenderTxHash, err)
+		return nil
+	}
+
+	return confNtfn.Confirmed
+}
+
+// resolveRemoteClose resolves the HTLCs of the channel once the commitment of
+// the remote party described by the passed close info has confirmed, then
+// wipes the arbitrator's log. The justice transaction of a breach sweeps every
+// output of the revoked commitment, including those of our outgoing HTLCs, so
+// they're failed back right away. The outgoing HTLCs of a valid commitment may
+// still be claimed by the remote party, so they're left to time out upstream.
+func (c *ChannelArbitrator) resolveRemoteClose(closeInfo *ChainCloseInfo) {
+	log.Infof("ChannelArbitrator(%v): %v by %v confirmed, exiting",
+		c.cfg.ChanPoint, closeInfo.CloseType, closeInfo.SpenderTxHash)
+
+	if closeInfo.CloseType == BreachClose {
+		var msgs []ResolutionMsg
+		for _, htlc := range closeInfo.Htlcs {
+			if htlc.Incoming {
+				continue
+			}
+
+			msgs = append(msgs, ResolutionMsg{
+				SourceChan: c.cfg.ShortChanID,
+				PayHash:    htlc.RHash,
+				Amt:        htlc.Amt,
+				Failure:    &lnwire.FailPermanentChannelFailure{},
+			})
+		}
+		if len(msgs) != 0 {
+			if err := c.cfg.DeliverResolutionMsg(msgs...); err != nil {
+				log.Errorf("ChannelArbitrator(%v): unable to "+
+					"fail back htlcs: %v", c.cfg.ChanPoint,
+					err)
+			}
+		}
+	}
+
+	if err := c.log.WipeHistory(); err != nil {
+		log.Errorf("ChannelArbitrator(%v): unable to wipe log: %v",
+			c.cfg.ChanPoint, err)
+	}
+}
+
+// handleExternalClose handles the close of the channel by means other than
+// our own commitment transaction. If we haven't gone to chain ourselves, then
+// there's nothing left to arbitrate, so the log is wiped and true is returned
+// to signal the arbitrator to exit.
+func (c *ChannelArbitrator) handleExternalClose(closeInfo *ChainCloseInfo) bool {
+	if c.state != StateDefault {
+		log.Warnf("ChannelArbitrator(%v): %v by %v detected while in "+
+			"state %v", c.cfg.ChanPoint, closeInfo.CloseType,
+			closeInfo.SpenderTxHash, c.state)
+		return false
+	}
+
+	log.Infof("ChannelArbitrator(%v): channel closed by %v at height "+
+		"%v, exiting", c.cfg.ChanPoint, closeInfo.CloseType,
+		closeInfo.SpendingHeight)
+
+	if err := c.log.WipeHistory(); err != nil {
+		log.Errorf("ChannelArbitrator(%v): unable to wipe log: %v",
+			c.cfg.ChanPoint, err)
+	}
+
+	return true
+}
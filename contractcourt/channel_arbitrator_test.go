@@ -0,0 +1,272 @@
+package contractcourt
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// mockPreimageDB is an in-memory implementation of the PreimageDB interface.
+type mockPreimageDB struct {
+	preimages map[[32]byte][]byte
+}
+
+func (m *mockPreimageDB) LookupPreimage(hash []byte) ([]byte, bool) {
+	var h [32]byte
+	copy(h[:], hash)
+
+	preimage, ok := m.preimages[h]
+	return preimage, ok
+}
+
+func (m *mockPreimageDB) AddPreimage(preimage []byte) error {
+	return nil
+}
+
+// TestChannelArbitratorChainActions checks that HTLCs nearing their expiry
+// trigger a force close, and that each HTLC is assigned the correct action.
+func TestChannelArbitratorChainActions(t *testing.T) {
+	t.Parallel()
+
+	outgoing := channeldb.HTLC{
+		RHash:         [32]byte{1},
+		RefundTimeout: 100,
+		OutputIndex:   0,
+	}
+	outgoingDust := channeldb.HTLC{
+		RHash:         [32]byte{2},
+		RefundTimeout: 100,
+		OutputIndex:   -1,
+	}
+	incoming := channeldb.HTLC{
+		RHash:         [32]byte{3},
+		RefundTimeout: 200,
+		OutputIndex:   1,
+		Incoming:      true,
+	}
+	incomingUnknown := channeldb.HTLC{
+		RHash:         [32]byte{4},
+		RefundTimeout: 100,
+		OutputIndex:   2,
+		Incoming:      true,
+	}
+
+	arbitrator := NewChannelArbitrator(ChannelArbitratorConfig{
+		ChainArbitratorConfig: ChainArbitratorConfig{
+			OutgoingBroadcastDelta: 10,
+			IncomingBroadcastDelta: 20,
+			PreimageDB: &mockPreimageDB{
+				preimages: map[[32]byte][]byte{
+					incoming.RHash: {3},
+				},
+			},
+		},
+	}, nil)
+
+	htlcs := []channeldb.HTLC{
+		outgoing, outgoingDust, incoming, incomingUnknown,
+	}
+
+	actions, goToChain := arbitrator.checkChainActions(80, htlcs)
+	if goToChain {
+		t.Fatalf("shouldn't go to chain at height 80")
+	}
+	if len(actions[HtlcTimeoutAction]) != 1 ||
+		actions[HtlcTimeoutAction][0].RHash != outgoing.RHash {
+		t.Fatalf("wrong timeout actions: %v", actions[HtlcTimeoutAction])
+	}
+	if len(actions[HtlcFailNowAction]) != 1 ||
+		actions[HtlcFailNowAction][0].RHash != outgoingDust.RHash {
+		t.Fatalf("wrong fail now actions: %v", actions[HtlcFailNowAction])
+	}
+	if len(actions[HtlcClaimAction]) != 1 ||
+		actions[HtlcClaimAction][0].RHash != incoming.RHash {
+		t.Fatalf("wrong claim actions: %v", actions[HtlcClaimAction])
+	}
+
+	// Within the outgoing delta of the outgoing HTLC's expiry, we should
+	// go to chain.
+	if _, goToChain := arbitrator.checkChainActions(90, htlcs); !goToChain {
+		t.Fatalf("should go to chain at height 90")
+	}
+
+	// The incoming HTLC we can claim should also take us to chain within
+	// the incoming delta of its expiry.
+	_, goToChain = arbitrator.checkChainActions(
+		180, []channeldb.HTLC{incoming, incomingUnknown},
+	)
+	if !goToChain {
+		t.Fatalf("should go to chain at height 180")
+	}
+}
+
+// TestChannelArbitratorForceClose checks that a force close requested by the
+// user walks the arbitrator through each state until the contract is fully
+// resolved, failing back any dust HTLCs once the commitment confirms.
+func TestChannelArbitratorForceClose(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp := makeTestDB(t)
+	defer cleanUp()
+
+	commitTx := wire.NewMsgTx(2)
+	commitTx.AddTxIn(&wire.TxIn{PreviousOutPoint: testChanPoint})
+
+	dustHtlc := channeldb.HTLC{
+		RHash:         [32]byte{1},
+		Amt:           1000,
+		RefundTimeout: 100,
+		OutputIndex:   -1,
+	}
+
+	var (
+		linkInactive bool
+		published    []*wire.MsgTx
+		delivered    []ResolutionMsg
+		resolved     bool
+	)
+	arbLog := newBoltArbitratorLog(db, testChanPoint)
+	arbitrator := NewChannelArbitrator(ChannelArbitratorConfig{
+		ChanPoint:   testChanPoint,
+		ShortChanID: lnwire.NewShortChanIDFromInt(1),
+		MarkLinkInactive: func() error {
+			linkInactive = true
+			return nil
+		},
+		ForceCloseChan: func() (*lnwallet.ForceCloseSummary,
+			[]channeldb.HTLC, error) {
+
+			return &lnwallet.ForceCloseSummary{
+				ChanPoint: testChanPoint,
+				CloseTx:   commitTx,
+			}, []channeldb.HTLC{dustHtlc}, nil
+		},
+		MarkChannelClosed: func(
+			*lnwallet.ForceCloseSummary) (bool, error) {
+
+			return false, nil
+		},
+		MarkChannelResolved: func() error {
+			resolved = true
+			return nil
+		},
+		ChainArbitratorConfig: ChainArbitratorConfig{
+			PublishTx: func(tx *wire.MsgTx) error {
+				published = append(published, tx)
+				return nil
+			},
+			DeliverResolutionMsg: func(msgs ...ResolutionMsg) error {
+				delivered = append(delivered, msgs...)
+				return nil
+			},
+		},
+	}, arbLog)
+
+	closeTx, err := arbitrator.advanceState(50, userTrigger)
+	if err != nil {
+		t.Fatalf("unable to advance state: %v", err)
+	}
+	if closeTx == nil || closeTx.TxHash() != commitTx.TxHash() {
+		t.Fatalf("commitment wasn't returned")
+	}
+	if !linkInactive {
+		t.Fatalf("link wasn't marked inactive")
+	}
+	if len(published) != 1 {
+		t.Fatalf("expected commitment to be published")
+	}
+	if arbitrator.state != StateCommitmentBroadcasted {
+		t.Fatalf("expected %v, got %v", StateCommitmentBroadcasted,
+			arbitrator.state)
+	}
+
+	// Further blocks shouldn't advance the state until the commitment
+	// has confirmed.
+	if _, err := arbitrator.advanceState(51, chainTrigger); err != nil {
+		t.Fatalf("unable to advance state: %v", err)
+	}
+	if arbitrator.state != StateCommitmentBroadcasted {
+		t.Fatalf("expected %v, got %v", StateCommitmentBroadcasted,
+			arbitrator.state)
+	}
+
+	// Once confirmed, the dust HTLC should be failed back, and as no
+	// outputs remain to be resolved, the contract is fully resolved.
+	if _, err := arbitrator.advanceState(52, confTrigger); err != nil {
+		t.Fatalf("unable to advance state: %v", err)
+	}
+	if arbitrator.state != StateFullyResolved {
+		t.Fatalf("expected %v, got %v", StateFullyResolved,
+			arbitrator.state)
+	}
+	if len(delivered) != 1 || delivered[0].PayHash != dustHtlc.RHash ||
+		delivered[0].Failure == nil {
+		t.Fatalf("dust htlc wasn't failed back: %v", delivered)
+	}
+	if !resolved {
+		t.Fatalf("channel wasn't marked as resolved")
+	}
+
+	// The final state should have been persisted.
+	state, err := arbLog.CurrentState()
+	if err != nil {
+		t.Fatalf("unable to fetch state: %v", err)
+	}
+	if state != StateFullyResolved {
+		t.Fatalf("expected %v, got %v", StateFullyResolved, state)
+	}
+}
+
+// TestChannelArbitratorResolveBreach checks that once a breach of the channel
+// confirms, the outgoing HTLCs on the revoked commitment are failed back, as
+// each of its outputs is swept by the breach arbiter.
+func TestChannelArbitratorResolveBreach(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp := makeTestDB(t)
+	defer cleanUp()
+
+	outgoing := channeldb.HTLC{
+		RHash:       [32]byte{1},
+		Amt:         1000,
+		OutputIndex: 0,
+	}
+	incoming := channeldb.HTLC{
+		RHash:       [32]byte{2},
+		Amt:         2000,
+		OutputIndex: 1,
+		Incoming:    true,
+	}
+
+	var delivered []ResolutionMsg
+	arbitrator := NewChannelArbitrator(ChannelArbitratorConfig{
+		ChanPoint:   testChanPoint,
+		ShortChanID: lnwire.NewShortChanIDFromInt(1),
+		ChainArbitratorConfig: ChainArbitratorConfig{
+			DeliverResolutionMsg: func(msgs ...ResolutionMsg) error {
+				delivered = append(delivered, msgs...)
+				return nil
+			},
+		},
+	}, newBoltArbitratorLog(db, testChanPoint))
+
+	breachHash := chainhash.Hash{1}
+	arbitrator.resolveRemoteClose(&ChainCloseInfo{
+		SpendDetail: &chainntnfs.SpendDetail{
+			SpentOutPoint: &testChanPoint,
+			SpenderTxHash: &breachHash,
+		},
+		CloseType: BreachClose,
+		Htlcs:     []channeldb.HTLC{outgoing, incoming},
+	})
+
+	if len(delivered) != 1 || delivered[0].PayHash != outgoing.RHash ||
+		delivered[0].Failure == nil {
+		t.Fatalf("outgoing htlc wasn't failed back: %v", delivered)
+	}
+}
@@ -0,0 +1,272 @@
+package contractcourt
+
+import (
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/sweep"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// htlcSuccessResolver is a ContractResolver that claims an incoming HTLC on
+// our commitment transaction, for which we know the preimage. The HTLC's
+// second-level success transaction is broadcast with the preimage, after
+// which the time-locked output of the success transaction is swept once
+// mature. Should the remote party instead time out the HTLC, then there's
+// nothing left for us to claim.
+type htlcSuccessResolver struct {
+	// htlcResolution holds the signed success transaction of the HTLC.
+	htlcResolution lnwallet.IncomingHtlcResolution
+
+	// htlc is the HTLC being resolved.
+	htlc channeldb.HTLC
+
+	// arbLog is the log of the channel arbitrator, within which the
+	// confirmation of the success transaction is recorded.
+	arbLog ArbitratorLog
+
+	ChainArbitratorConfig
+
+	quit chan struct{}
+}
+
+// newHtlcSuccessResolver returns a new resolver which claims the passed HTLC
+// using its resolution.
+func newHtlcSuccessResolver(res lnwallet.IncomingHtlcResolution,
+	htlc channeldb.HTLC, arbLog ArbitratorLog, cfg ChainArbitratorConfig,
+	quit chan struct{}) *htlcSuccessResolver {
+
+	return &htlcSuccessResolver{
+		htlcResolution:        res,
+		htlc:                  htlc,
+		arbLog:                arbLog,
+		ChainArbitratorConfig: cfg,
+		quit:                  quit,
+	}
+}
+
+// A compile time check to ensure htlcSuccessResolver meets the
+// ContractResolver interface.
+var _ ContractResolver = (*htlcSuccessResolver)(nil)
+
+// htlcOutpoint returns the outpoint of the HTLC on our commitment transaction.
+func (h *htlcSuccessResolver) htlcOutpoint() wire.OutPoint {
+	return h.htlcResolution.SignedSuccessTx.TxIn[0].PreviousOutPoint
+}
+
+// ResolverKey returns an identifier unique to the output the resolver is
+// resolving, which is the outpoint of the HTLC.
+//
+// NOTE: This is part of the ContractResolver interface.
+func (h *htlcSuccessResolver) ResolverKey() []byte {
+	return outpointKey(h.htlcOutpoint())
+}
+
+// Resolve broadcasts the success transaction of the HTLC, re-broadcasting it
+// each block until the HTLC output has been spent. If it was spent by our
+// success transaction, then the output of the success transaction is swept
+// once its CSV delay has passed.
+//
+// NOTE: This is part of the ContractResolver interface.
+func (h *htlcSuccessResolver) Resolve() error {
+	// If our success transaction confirmed before we restarted, then
+	// only its output remains to be swept.
+	confHeight, claimed, err := h.arbLog.FetchSecondLevelConf(
+		h.ResolverKey(),
+	)
+	if err != nil {
+		return err
+	}
+	if claimed {
+		return h.sweepSuccessOutput(confHeight)
+	}
+
+	preimage, ok := h.PreimageDB.LookupPreimage(h.htlc.RHash[:])
+	if !ok {
+		return fmt.Errorf("unable to find preimage for htlc %x",
+			h.htlc.RHash[:])
+	}
+
+	// The success transaction was signed with a placeholder in place of
+	// the preimage, so we'll swap in the preimage before broadcasting it.
+	successTx := h.htlcResolution.SignedSuccessTx.Copy()
+	successTx.TxIn[0].Witness[lnwallet.SuccessPreimageIndex] = preimage
+	successHash := successTx.TxHash()
+
+	_, bestHeight, err := h.ChainIO.GetBestBlock()
+	if err != nil {
+		return err
+	}
+
+	htlcOutpoint := h.htlcOutpoint()
+	spendNtfn, err := h.Notifier.RegisterSpendNtfn(
+		&htlcOutpoint, uint32(bestHeight),
+	)
+	if err != nil {
+		return err
+	}
+	defer spendNtfn.Cancel()
+
+	blockEpochs, err := h.Notifier.RegisterBlockEpochNtfn()
+	if err != nil {
+		return err
+	}
+	defer blockEpochs.Cancel()
+
+	log.Infof("Claiming htlc %x on-chain with success tx %v",
+		h.htlc.RHash[:], successHash)
+
+	// As the success transaction is signed by the remote party, we're
+	// unable to bump its fee, so it's re-broadcast each block until the
+	// HTLC output has been spent.
+	for {
+		if err := h.PublishTx(successTx); err != nil {
+			log.Debugf("Unable to broadcast success tx %v for "+
+				"htlc %x: %v", successHash, h.htlc.RHash[:],
+				err)
+		}
+
+		select {
+		case _, ok := <-blockEpochs.Epochs:
+			if !ok {
+				return errResolverShuttingDown
+			}
+
+		case spend, ok := <-spendNtfn.Spend:
+			if !ok {
+				return errResolverShuttingDown
+			}
+
+			// Should the remote party have timed out the HTLC
+			// before our success transaction confirmed, then
+			// there's nothing left for us to claim.
+			if !spend.SpenderTxHash.IsEqual(&successHash) {
+				log.Warnf("Htlc %x timed out on-chain by "+
+					"remote party in %v", h.htlc.RHash[:],
+					spend.SpenderTxHash)
+				return nil
+			}
+
+			// We'll record the confirmation of the success
+			// transaction, ensuring its output is swept should we
+			// restart before then.
+			confHeight := uint32(spend.SpendingHeight)
+			err := h.arbLog.LogSecondLevelConf(
+				h.ResolverKey(), confHeight,
+			)
+			if err != nil {
+				return err
+			}
+
+			return h.sweepSuccessOutput(confHeight)
+
+		case <-h.quit:
+			return errResolverShuttingDown
+		}
+	}
+}
+
+// sweepSuccessOutput sweeps the time-locked output of our success
+// transaction, which confirmed at the passed height, once its CSV delay has
+// passed.
+func (h *htlcSuccessResolver) sweepSuccessOutput(confHeight uint32) error {
+	successTx := h.htlcResolution.SignedSuccessTx
+	outpoint := wire.OutPoint{
+		Hash:  successTx.TxHash(),
+		Index: 0,
+	}
+
+	// The HTLC was due to be resolved by its expiry, so should its output
+	// mature early enough, we'll aim to have it swept back into the
+	// wallet by the time we'd have gone to chain to claim the HTLC,
+	// escalating the fee rate as that height approaches. Otherwise the
+	// output is no longer time sensitive.
+	var params sweep.Params
+	maturityHeight := confHeight + h.htlcResolution.CsvDelay
+	deadline := sweep.DeadlineFromExpiry(
+		h.htlc.RefundTimeout, h.IncomingBroadcastDelta,
+	)
+	if deadline > int32(maturityHeight) {
+		params.Deadline = deadline
+	}
+
+	return sweepSecondLevelOutput(
+		&h.ChainArbitratorConfig, outpoint,
+		lnwallet.HtlcAcceptedSuccessSecondLevel,
+		&h.htlcResolution.SweepSignDesc, confHeight,
+		h.htlcResolution.CsvDelay, params, h.quit,
+	)
+}
+
+// sweepSecondLevelOutput waits for the time-locked output of a second-level
+// HTLC transaction, which confirmed at the passed height, to mature, then
+// hands it to the sweeper with the passed sweep parameters. It blocks until
+// the output has been swept.
+func sweepSecondLevelOutput(cfg *ChainArbitratorConfig, outpoint wire.OutPoint,
+	witnessType lnwallet.WitnessType, signDesc *lnwallet.SignDescriptor,
+	confHeight, csvDelay uint32, params sweep.Params,
+	quit chan struct{}) error {
+
+	blockEpochs, err := cfg.Notifier.RegisterBlockEpochNtfn()
+	if err != nil {
+		return err
+	}
+	defer blockEpochs.Cancel()
+
+	_, bestHeight, err := cfg.ChainIO.GetBestBlock()
+	if err != nil {
+		return err
+	}
+
+	maturityHeight := confHeight + csvDelay
+
+	log.Infof("Waiting for second-level output %v to mature at height %v",
+		outpoint, maturityHeight)
+
+	for uint32(bestHeight) < maturityHeight {
+		select {
+		case epoch, ok := <-blockEpochs.Epochs:
+			if !ok {
+				return errResolverShuttingDown
+			}
+			bestHeight = epoch.Height
+
+		case <-quit:
+			return errResolverShuttingDown
+		}
+	}
+
+	input := sweep.NewCsvInput(&outpoint, witnessType, signDesc, csvDelay)
+	resultChan, err := cfg.SweepInput(input, params)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case result := <-resultChan:
+		switch result.Err {
+		case nil:
+			log.Infof("Second-level output %v swept by %v", outpoint,
+				result.Tx.TxHash())
+
+		// Only we're able to spend the output once it has matured, so
+		// a spend by another transaction must be our own sweep from
+		// before a restart.
+		case sweep.ErrRemoteSpend:
+			log.Infof("Second-level output %v swept by %v prior to "+
+				"restart", outpoint, result.Tx.TxHash())
+
+		case sweep.ErrSweeperShuttingDown:
+			return errResolverShuttingDown
+
+		default:
+			return result.Err
+		}
+
+		return nil
+
+	case <-quit:
+		return errResolverShuttingDown
+	}
+}
@@ -0,0 +1,280 @@
+package contractcourt
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/sweep"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+)
+
+var errPublishSweep = errors.New("unable to publish sweep")
+
+type mockSigner struct{}
+
+func (m *mockSigner) SignOutputRaw(tx *wire.MsgTx,
+	signDesc *lnwallet.SignDescriptor) ([]byte, error) {
+
+	return []byte{}, nil
+}
+
+func (m *mockSigner) ComputeInputScript(tx *wire.MsgTx,
+	signDesc *lnwallet.SignDescriptor) (*lnwallet.InputScript, error) {
+
+	return &lnwallet.InputScript{}, nil
+}
+
+type mockChainIO struct {
+	bestHeight int32
+}
+
+func (m *mockChainIO) GetBestBlock() (*chainhash.Hash, int32, error) {
+	return &chainhash.Hash{}, m.bestHeight, nil
+}
+
+func (m *mockChainIO) GetUtxo(op *wire.OutPoint,
+	heightHint uint32) (*wire.TxOut, error) {
+
+	return nil, nil
+}
+
+func (m *mockChainIO) GetBlockHash(blockHeight int64) (*chainhash.Hash, error) {
+	return nil, nil
+}
+
+func (m *mockChainIO) GetBlock(blockHash *chainhash.Hash) (*wire.MsgBlock,
+	error) {
+
+	return nil, nil
+}
+
+// mockNotifier delivers the blocks sent on its epoch channel to whichever of
+// its subscribers receives them first, and the spends of the outpoints
+// registered with it.
+type mockNotifier struct {
+	sync.Mutex
+
+	spendChans map[wire.OutPoint]chan *chainntnfs.SpendDetail
+	epochChan  chan *chainntnfs.BlockEpoch
+}
+
+func newMockNotifier() *mockNotifier {
+	return &mockNotifier{
+		spendChans: make(map[wire.OutPoint]chan *chainntnfs.SpendDetail),
+		epochChan:  make(chan *chainntnfs.BlockEpoch),
+	}
+}
+
+func (m *mockNotifier) RegisterConfirmationsNtfn(txid *chainhash.Hash,
+	numConfs, heightHint uint32) (*chainntnfs.ConfirmationEvent, error) {
+
+	return nil, nil
+}
+
+func (m *mockNotifier) RegisterBlockEpochNtfn() (*chainntnfs.BlockEpochEvent,
+	error) {
+
+	return &chainntnfs.BlockEpochEvent{
+		Epochs: m.epochChan,
+		Cancel: func() {},
+	}, nil
+}
+
+func (m *mockNotifier) RegisterSpendNtfn(outpoint *wire.OutPoint,
+	heightHint uint32) (*chainntnfs.SpendEvent, error) {
+
+	m.Lock()
+	defer m.Unlock()
+
+	spendChan := make(chan *chainntnfs.SpendDetail, 1)
+	m.spendChans[*outpoint] = spendChan
+
+	return &chainntnfs.SpendEvent{
+		Spend:  spendChan,
+		Cancel: func() {},
+	}, nil
+}
+
+func (m *mockNotifier) Start() error {
+	return nil
+}
+
+func (m *mockNotifier) Stop() error {
+	return nil
+}
+
+// spend delivers a spend notification for each of the inputs of the passed
+// transaction which are being watched.
+func (m *mockNotifier) spend(tx *wire.MsgTx) {
+	m.Lock()
+	defer m.Unlock()
+
+	txHash := tx.TxHash()
+	for i, txIn := range tx.TxIn {
+		spendChan, ok := m.spendChans[txIn.PreviousOutPoint]
+		if !ok {
+			continue
+		}
+
+		outpoint := txIn.PreviousOutPoint
+		spendChan <- &chainntnfs.SpendDetail{
+			SpentOutPoint:     &outpoint,
+			SpenderTxHash:     &txHash,
+			SpendingTx:        tx,
+			SpenderInputIndex: uint32(i),
+		}
+		delete(m.spendChans, txIn.PreviousOutPoint)
+	}
+}
+
+// TestHtlcSuccessResolverSweepDeadline checks that the output of a success
+// transaction maturing before the expiry of its HTLC is swept with a deadline,
+// escalating the fee rate of the sweep each block until it's published, after
+// which the published sweep is left to confirm.
+func TestHtlcSuccessResolverSweepDeadline(t *testing.T) {
+	t.Parallel()
+
+	const (
+		confHeight = 100
+		csvDelay   = 5
+	)
+
+	notifier := newMockNotifier()
+	chainIO := &mockChainIO{bestHeight: confHeight + csvDelay}
+	timerChan := make(chan time.Time)
+
+	var (
+		publishMtx   sync.Mutex
+		publishError = errPublishSweep
+	)
+	publishChan := make(chan *wire.MsgTx, 10)
+	sweeper := sweep.New(&sweep.UtxoSweeperConfig{
+		GenSweepScript: func() ([]byte, error) {
+			return []byte{0, 20}, nil
+		},
+		FeeEstimator: lnwallet.StaticFeeEstimator{FeeRate: 40},
+		PublishTransaction: func(tx *wire.MsgTx) error {
+			publishMtx.Lock()
+			err := publishError
+			publishMtx.Unlock()
+
+			publishChan <- tx
+			return err
+		},
+		NewBatchTimer: func() <-chan time.Time {
+			return timerChan
+		},
+		Notifier:             notifier,
+		ChainIO:              chainIO,
+		Signer:               &mockSigner{},
+		MaxInputsPerTx:       sweep.DefaultMaxInputsPerTx,
+		MaxSweepAttempts:     sweep.DefaultMaxSweepAttempts,
+		NextAttemptDeltaFunc: sweep.DefaultNextAttemptDeltaFunc,
+		FeeRateBucketSize:    sweep.DefaultFeeRateBucketSize,
+		MaxFeePerWeight:      sweep.DefaultMaxFeePerWeight,
+	})
+	if err := sweeper.Start(); err != nil {
+		t.Fatalf("unable to start sweeper: %v", err)
+	}
+	defer sweeper.Stop()
+
+	successTx := wire.NewMsgTx(2)
+	successTx.AddTxIn(&wire.TxIn{PreviousOutPoint: testChanPoint})
+	successTx.AddTxOut(&wire.TxOut{Value: 100000})
+
+	resolver := newHtlcSuccessResolver(
+		lnwallet.IncomingHtlcResolution{
+			SignedSuccessTx: successTx,
+			CsvDelay:        csvDelay,
+			SweepSignDesc: lnwallet.SignDescriptor{
+				Output: successTx.TxOut[0],
+			},
+		},
+		channeldb.HTLC{
+			RHash:         [32]byte{1},
+			RefundTimeout: 200,
+			Incoming:      true,
+		},
+		nil,
+		ChainArbitratorConfig{
+			IncomingBroadcastDelta: 10,
+			ChainIO:                chainIO,
+			Notifier:               notifier,
+			SweepInput:             sweeper.SweepInput,
+		},
+		make(chan struct{}),
+	)
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- resolver.sweepSuccessOutput(confHeight)
+	}()
+
+	publishSweep := func() *wire.MsgTx {
+		select {
+		case timerChan <- time.Time{}:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("batch timer not started")
+		}
+
+		select {
+		case tx := <-publishChan:
+			return tx
+		case <-time.After(5 * time.Second):
+			t.Fatalf("sweep tx not published")
+		}
+
+		return nil
+	}
+	notifyEpoch := func(height int32) {
+		select {
+		case notifier.epochChan <- &chainntnfs.BlockEpoch{
+			Height: height,
+		}:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("block epoch not received")
+		}
+	}
+
+	// The first attempt to sweep the output fails, after which it's
+	// retried at the next block at a higher fee rate, as its deadline
+	// approaches.
+	firstTx := publishSweep()
+
+	publishMtx.Lock()
+	publishError = nil
+	publishMtx.Unlock()
+
+	notifyEpoch(confHeight + csvDelay + 40)
+	sweepTx := publishSweep()
+	if sweepTx.TxOut[0].Value >= firstTx.TxOut[0].Value {
+		t.Fatalf("expected fee rate to escalate, output value went "+
+			"from %v to %v", firstTx.TxOut[0].Value,
+			sweepTx.TxOut[0].Value)
+	}
+
+	// Now that the sweep has been published, it shouldn't be replaced at
+	// the next block.
+	notifyEpoch(confHeight + csvDelay + 41)
+	select {
+	case timerChan <- time.Time{}:
+		t.Fatalf("published sweep was replaced")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	notifier.spend(sweepTx)
+	select {
+	case err := <-errChan:
+		if err != nil {
+			t.Fatalf("unable to sweep success output: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("success output wasn't swept")
+	}
+}
@@ -0,0 +1,186 @@
+package contractcourt
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/wire"
+)
+
+var (
+	// errResolverShuttingDown is returned by a resolver which was signalled
+	// to exit before its output was resolved.
+	errResolverShuttingDown = fmt.Errorf("resolver shutting down")
+)
+
+// ContractResolver is an interface which represents a sub-system that drives
+// the resolution of a single output of a contract which has gone to chain.
+type ContractResolver interface {
+	// ResolverKey returns an identifier unique to the output the resolver
+	// is resolving, used to record its resolution within the arbitrator
+	// log.
+	ResolverKey() []byte
+
+	// Resolve blocks until the output has been fully resolved, or the
+	// resolver has been signalled to exit, in which case
+	// errResolverShuttingDown is returned.
+	Resolve() error
+}
+
+// htlcTimeoutResolver is a ContractResolver that resolves an outgoing HTLC on
+// our commitment transaction. The utxo nursery broadcasts the HTLC's
+// second-level timeout transaction once it has expired, and sweeps the
+// time-locked output of the timeout transaction once mature, so the resolver
+// only watches for the spend of the HTLC output. Once timed out, the HTLC is
+// failed back upstream. Should the remote party instead claim the HTLC with
+// its preimage, then the preimage is extracted from their spend, and the HTLC
+// is settled upstream.
+type htlcTimeoutResolver struct {
+	// htlcResolution holds the signed timeout transaction of the HTLC.
+	htlcResolution lnwallet.OutgoingHtlcResolution
+
+	// htlc is the HTLC being resolved.
+	htlc channeldb.HTLC
+
+	// shortChanID is the short channel ID of the channel the HTLC was
+	// sent over.
+	shortChanID lnwire.ShortChannelID
+
+	ChainArbitratorConfig
+
+	quit chan struct{}
+}
+
+// newHtlcTimeoutResolver returns a new resolver which times out the passed
+// HTLC using its resolution.
+func newHtlcTimeoutResolver(res lnwallet.OutgoingHtlcResolution,
+	htlc channeldb.HTLC, shortChanID lnwire.ShortChannelID,
+	cfg ChainArbitratorConfig, quit chan struct{}) *htlcTimeoutResolver {
+
+	return &htlcTimeoutResolver{
+		htlcResolution:        res,
+		htlc:                  htlc,
+		shortChanID:           shortChanID,
+		ChainArbitratorConfig: cfg,
+		quit:                  quit,
+	}
+}
+
+// A compile time check to ensure htlcTimeoutResolver meets the
+// ContractResolver interface.
+var _ ContractResolver = (*htlcTimeoutResolver)(nil)
+
+// htlcOutpoint returns the outpoint of the HTLC on our commitment transaction.
+func (h *htlcTimeoutResolver) htlcOutpoint() wire.OutPoint {
+	return h.htlcResolution.SignedTimeoutTx.TxIn[0].PreviousOutPoint
+}
+
+// ResolverKey returns an identifier unique to the output the resolver is
+// resolving, which is the outpoint of the HTLC.
+//
+// NOTE: This is part of the ContractResolver interface.
+func (h *htlcTimeoutResolver) ResolverKey() []byte {
+	return outpointKey(h.htlcOutpoint())
+}
+
+// Resolve waits for the HTLC output to be spent, then either fails or settles
+// the HTLC upstream, depending on whether it was spent by our timeout
+// transaction or claimed by the remote party.
+//
+// NOTE: This is part of the ContractResolver interface.
+func (h *htlcTimeoutResolver) Resolve() error {
+	htlcOutpoint := h.htlcOutpoint()
+
+	// The remote party may claim the HTLC with the preimage at any time
+	// before our timeout transaction confirms, so either spend is
+	// detected by watching the HTLC output.
+	spendNtfn, err := h.Notifier.RegisterSpendNtfn(
+		&htlcOutpoint, h.htlcResolution.Expiry,
+	)
+	if err != nil {
+		return err
+	}
+	defer spendNtfn.Cancel()
+
+	timeoutHash := h.htlcResolution.SignedTimeoutTx.TxHash()
+
+	select {
+	case spend, ok := <-spendNtfn.Spend:
+		if !ok {
+			return errResolverShuttingDown
+		}
+
+		return h.resolveSpend(
+			spend.SpenderTxHash.IsEqual(&timeoutHash),
+			spend.SpendingTx, spend.SpenderInputIndex,
+		)
+
+	case <-h.quit:
+		return errResolverShuttingDown
+	}
+}
+
+// resolveSpend fails the HTLC upstream if its output was spent by our timeout
+// transaction. Otherwise, the remote party claimed the HTLC, so its preimage
+// is extracted from the spending input, and the HTLC is settled upstream.
+func (h *htlcTimeoutResolver) resolveSpend(timedOut bool,
+	spendingTx *wire.MsgTx, inputIndex uint32) error {
+
+	if timedOut {
+		log.Infof("Htlc %x timed out on-chain, failing back",
+			h.htlc.RHash[:])
+
+		return h.DeliverResolutionMsg(ResolutionMsg{
+			SourceChan: h.shortChanID,
+			PayHash:    h.htlc.RHash,
+			Amt:        h.htlc.Amt,
+			Failure:    &lnwire.FailPermanentChannelFailure{},
+		})
+	}
+
+	// The remote party's success witness is of the form:
+	// <sig> <preimage> <witness script>
+	witness := spendingTx.TxIn[inputIndex].Witness
+	if len(witness) != 3 || len(witness[1]) != sha256.Size {
+		return fmt.Errorf("unable to extract preimage for htlc %x "+
+			"from witness of %v", h.htlc.RHash[:],
+			spendingTx.TxHash())
+	}
+
+	var preimage [32]byte
+	copy(preimage[:], witness[1])
+	payHash := sha256.Sum256(preimage[:])
+	if !bytes.Equal(payHash[:], h.htlc.RHash[:]) {
+		return fmt.Errorf("preimage %x extracted from %v doesn't "+
+			"match htlc %x", preimage[:], spendingTx.TxHash(),
+			h.htlc.RHash[:])
+	}
+
+	log.Infof("Htlc %x claimed on-chain by remote party, settling back",
+		h.htlc.RHash[:])
+
+	if err := h.PreimageDB.AddPreimage(preimage[:]); err != nil {
+		return err
+	}
+
+	return h.DeliverResolutionMsg(ResolutionMsg{
+		SourceChan: h.shortChanID,
+		PayHash:    h.htlc.RHash,
+		Amt:        h.htlc.Amt,
+		PreImage:   &preimage,
+	})
+}
+
+// outpointKey returns the serialized form of the passed outpoint, used as the
+// key of the resolver of the output.
+func outpointKey(op wire.OutPoint) []byte {
+	var key [36]byte
+	copy(key[:], op.Hash[:])
+	byteOrder.PutUint32(key[32:], op.Index)
+
+	return key[:]
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"net"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// dbStateServer is an implementation of the lnrpc.DatabaseStateServer
+// interface which reports the progress of any migrations being applied to the
+// channeldb. As it doesn't depend on any other sub-system, it's served before
+// the database has been opened.
+//
+// NOTE: As the server is served before the macaroon service has been created,
+// its methods aren't subject to macaroon authentication. They must therefore
+// only expose information that isn't sensitive.
+type dbStateServer struct{}
+
+// A compile time check to ensure dbStateServer implements the
+// lnrpc.DatabaseStateServer interface.
+var _ lnrpc.DatabaseStateServer = (*dbStateServer)(nil)
+
+// MigrationStatus returns the progress of any database migrations that are
+// being applied as the daemon starts up.
+func (d *dbStateServer) MigrationStatus(ctx context.Context,
+	in *lnrpc.MigrationStatusRequest) (*lnrpc.MigrationStatusResponse, error) {
+
+	status := channeldb.CurrentMigrationStatus()
+
+	resp := &lnrpc.MigrationStatusResponse{
+		Migrating:      status.Migrating,
+		CurrentVersion: status.CurrentVersion,
+		LatestVersion:  status.LatestVersion,
+		Migration:      status.Migration,
+		ItemsMigrated:  status.ItemsMigrated,
+		TotalItems:     status.TotalItems,
+	}
+	if !status.StartTime.IsZero() {
+		resp.StartTime = status.StartTime.Unix()
+	}
+
+	return resp, nil
+}
+
+// startDBStateServer starts a gRPC server listening on the target endpoint
+// which only serves the DatabaseState service. The returned function stops
+// the server, and closes its listener so the endpoint can be reused.
+func startDBStateServer(endpoint string,
+	opts []grpc.ServerOption) (func(), error) {
+
+	grpcServer := grpc.NewServer(opts...)
+	lnrpc.RegisterDatabaseStateServer(grpcServer, &dbStateServer{})
+
+	lis, err := net.Listen("tcp", endpoint)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		rpcsLog.Infof("Database state RPC server listening on %s",
+			lis.Addr())
+		grpcServer.Serve(lis)
+	}()
+
+	stop := func() {
+		grpcServer.Stop()
+		lis.Close()
+	}
+
+	return stop, nil
+}
@@ -3,6 +3,7 @@ package discovery
 import (
 	"bytes"
 	"fmt"
+	"runtime"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -18,6 +19,12 @@ import (
 	"github.com/roasbeef/btcd/wire"
 )
 
+var (
+	// ErrGossiperShuttingDown is returned for each network message which
+	// hasn't been processed by the time the gossiper shuts down.
+	ErrGossiperShuttingDown = errors.New("gossiper has shut down")
+)
+
 // networkMsg couples a routing related wire message with the peer that
 // originally sent it.
 type networkMsg struct {
@@ -26,6 +33,15 @@ type networkMsg struct {
 
 	isRemote bool
 
+	// sigsVerified indicates that the signatures of the message have
+	// already been verified by the validation pool, so they needn't be
+	// verified again while processing the message.
+	sigsVerified bool
+
+	// processed is closed once the networkHandler has processed the
+	// message.
+	processed chan struct{}
+
 	err chan error
 }
 
@@ -35,13 +51,25 @@ type syncRequest struct {
 	node *btcec.PublicKey
 }
 
-// feeUpdateRequest is a request that is sent to the server when a caller
-// wishes to update the fees for a particular set of tchannels. New UpdateFee
-// messages will be crafted to be sent out during the next broadcast epoch and
-// the fee updates committed to the lower layer.
-type feeUpdateRequest struct {
+// chanPolicyUpdateRequest is a request that is sent to the server when a
+// caller wishes to update the forwarding policy of a particular set of
+// channels. New ChannelUpdate messages will be crafted to be sent out during
+// the next broadcast epoch and the policy updates committed to the lower
+// layer.
+type chanPolicyUpdateRequest struct {
 	targetChans []wire.OutPoint
-	newSchema   routing.FeeSchema
+	newPolicy   routing.ChannelPolicy
+
+	errResp chan error
+}
+
+// chanStatusUpdateRequest is a request that is sent to the server when a
+// caller wishes to disable or re-enable one of our channels. A new
+// ChannelUpdate message with the disabled bit set or cleared will be crafted
+// to be sent out during the next broadcast epoch.
+type chanStatusUpdateRequest struct {
+	chanID   lnwire.ShortChannelID
+	disabled bool
 
 	errResp chan error
 }
@@ -137,8 +165,13 @@ type AuthenticatedGossiper struct {
 	// properly validate it an re-broadcast it out to the network.
 	waitingProofs *channeldb.WaitingProofStore
 
+	// validationPool verifies the signatures of new network messages
+	// concurrently, before handing them to the networkHandler in the
+	// order they were received.
+	validationPool *routing.ValidationPool
+
 	// networkMsgs is a channel that carries new network broadcasted
-	// message from outside the gossiper service to be processed by the
+	// message from the validation pool to be processed by the
 	// networkHandler.
 	networkMsgs chan *networkMsg
 
@@ -147,9 +180,13 @@ type AuthenticatedGossiper struct {
 	// our PoV.
 	syncRequests chan *syncRequest
 
-	// feeUpdates is a channel that requests to update the fee schedule of
-	// a set of channels is sent over.
-	feeUpdates chan *feeUpdateRequest
+	// chanPolicyUpdates is a channel that requests to update the
+	// forwarding policy of a set of channels is sent over.
+	chanPolicyUpdates chan *chanPolicyUpdateRequest
+
+	// chanStatusUpdates is a channel that requests to disable or
+	// re-enable one of our channels are sent over.
+	chanStatusUpdates chan *chanStatusUpdateRequest
 
 	// bestHeight is the height of the block at the tip of the main chain
 	// as we know it.
@@ -167,13 +204,19 @@ func New(cfg Config, selfKey *btcec.PublicKey) (*AuthenticatedGossiper, error) {
 		return nil, err
 	}
 
+	validationPool := routing.NewValidationPool(
+		runtime.NumCPU(), routing.DefaultValidationBatchSize,
+	)
+
 	return &AuthenticatedGossiper{
 		selfKey:                selfKey,
 		cfg:                    &cfg,
 		networkMsgs:            make(chan *networkMsg),
+		validationPool:         validationPool,
 		quit:                   make(chan struct{}),
 		syncRequests:           make(chan *syncRequest),
-		feeUpdates:             make(chan *feeUpdateRequest),
+		chanPolicyUpdates:      make(chan *chanPolicyUpdateRequest),
+		chanStatusUpdates:      make(chan *chanStatusUpdateRequest),
 		prematureAnnouncements: make(map[uint32][]*networkMsg),
 		waitingProofs:          storage,
 	}, nil
@@ -193,24 +236,45 @@ func (d *AuthenticatedGossiper) SynchronizeNode(pub *btcec.PublicKey) {
 	}
 }
 
-// PropagateFeeUpdate signals the AuthenticatedGossiper to update the fee
-// schema for the specified channels. If no channels are specified, then the
-// fee update will be applied to all outgoing channels from the source node.
-// Fee updates are done in two stages: first, the AuthenticatedGossiper ensures
-// the updated has been committed by dependant sub-systems, then it signs and
-// broadcasts new updates to the network.
-func (d *AuthenticatedGossiper) PropagateFeeUpdate(newSchema routing.FeeSchema,
-	chanPoints ...wire.OutPoint) error {
+// PropagateChanPolicyUpdate signals the AuthenticatedGossiper to update the
+// forwarding policy for the specified channels. If no channels are specified,
+// then the policy update will be applied to all outgoing channels from the
+// source node. Policy updates are done in two stages: first, the
+// AuthenticatedGossiper ensures the updated has been committed by dependant
+// sub-systems, then it signs and broadcasts new updates to the network.
+func (d *AuthenticatedGossiper) PropagateChanPolicyUpdate(
+	newPolicy routing.ChannelPolicy, chanPoints ...wire.OutPoint) error {
 
 	errChan := make(chan error, 1)
-	feeUpdate := &feeUpdateRequest{
+	policyUpdate := &chanPolicyUpdateRequest{
 		targetChans: chanPoints,
-		newSchema:   newSchema,
+		newPolicy:   newPolicy,
 		errResp:     errChan,
 	}
 
 	select {
-	case d.feeUpdates <- feeUpdate:
+	case d.chanPolicyUpdates <- policyUpdate:
+		return <-errChan
+	case <-d.quit:
+		return fmt.Errorf("AuthenticatedGossiper shutting down")
+	}
+}
+
+// PropagateChanStatusUpdate signals the AuthenticatedGossiper to disable or
+// re-enable the outgoing direction of the target channel. If the channel
+// already has the requested status, then no update is sent.
+func (d *AuthenticatedGossiper) PropagateChanStatusUpdate(
+	chanID lnwire.ShortChannelID, disabled bool) error {
+
+	errChan := make(chan error, 1)
+	statusUpdate := &chanStatusUpdateRequest{
+		chanID:   chanID,
+		disabled: disabled,
+		errResp:  errChan,
+	}
+
+	select {
+	case d.chanStatusUpdates <- statusUpdate:
 		return <-errChan
 	case <-d.quit:
 		return fmt.Errorf("AuthenticatedGossiper shutting down")
@@ -241,6 +305,10 @@ func (d *AuthenticatedGossiper) Start() error {
 	}
 	d.bestHeight = height
 
+	if err := d.validationPool.Start(); err != nil {
+		return err
+	}
+
 	d.wg.Add(1)
 	go d.networkHandler()
 
@@ -257,6 +325,8 @@ func (d *AuthenticatedGossiper) Stop() {
 
 	close(d.quit)
 	d.wg.Wait()
+
+	d.validationPool.Stop()
 }
 
 // ProcessRemoteAnnouncement sends a new remote announcement message along with
@@ -268,17 +338,14 @@ func (d *AuthenticatedGossiper) ProcessRemoteAnnouncement(msg lnwire.Message,
 	src *btcec.PublicKey) chan error {
 
 	nMsg := &networkMsg{
-		msg:      msg,
-		isRemote: true,
-		peer:     src,
-		err:      make(chan error, 1),
+		msg:       msg,
+		isRemote:  true,
+		peer:      src,
+		processed: make(chan struct{}),
+		err:       make(chan error, 1),
 	}
 
-	select {
-	case d.networkMsgs <- nMsg:
-	case <-d.quit:
-		nMsg.err <- errors.New("gossiper has shut down")
-	}
+	d.submitNetworkMsg(nMsg)
 
 	return nMsg.err
 }
@@ -294,21 +361,103 @@ func (d *AuthenticatedGossiper) ProcessLocalAnnouncement(msg lnwire.Message,
 	src *btcec.PublicKey) chan error {
 
 	nMsg := &networkMsg{
-		msg:      msg,
-		isRemote: false,
-		peer:     src,
-		err:      make(chan error, 1),
+		msg:       msg,
+		isRemote:  false,
+		peer:      src,
+		processed: make(chan struct{}),
+		err:       make(chan error, 1),
 	}
 
-	select {
-	case d.networkMsgs <- nMsg:
-	case <-d.quit:
-		nMsg.err <- errors.New("gossiper has shut down")
-	}
+	d.submitNetworkMsg(nMsg)
 
 	return nMsg.err
 }
 
+// submitNetworkMsg submits the network message to the validation pool, which
+// verifies its signatures concurrently with those of other messages. Once
+// verified, the message is handed to the networkHandler to be processed, in
+// the order the messages were submitted.
+func (d *AuthenticatedGossiper) submitNetworkMsg(nMsg *networkMsg) {
+	var sigsChecked bool
+	err := d.validationPool.Submit(&routing.ValidationJob{
+		Msg: nMsg.msg,
+		SigChecks: func() ([]*routing.SigCheck, error) {
+			checks, err := d.sigChecks(nMsg)
+			sigsChecked = len(checks) != 0
+			return checks, err
+		},
+		Commit: func(err error) {
+			// If the signatures couldn't be verified, then the
+			// message will be validated once more while being
+			// processed, so the failure is reported as usual.
+			nMsg.sigsVerified = sigsChecked && err == nil
+
+			// Once handed to the networkHandler, the outcome of
+			// processing the message is always delivered.
+			select {
+			case d.networkMsgs <- nMsg:
+			case <-d.quit:
+				nMsg.err <- ErrGossiperShuttingDown
+				return
+			}
+
+			// We'll wait for the message to be processed, as the
+			// messages submitted after it may depend on it.
+			select {
+			case <-nMsg.processed:
+			case <-d.quit:
+			}
+		},
+	})
+	if err != nil {
+		nMsg.err <- ErrGossiperShuttingDown
+	}
+}
+
+// sigChecks returns the signature checks which can be verified for the network
+// message ahead of it being processed. An empty set of checks is returned if
+// the message carries no signatures, or they can only be verified while the
+// message is being processed.
+func (d *AuthenticatedGossiper) sigChecks(
+	nMsg *networkMsg) ([]*routing.SigCheck, error) {
+
+	switch msg := nMsg.msg.(type) {
+	case *lnwire.NodeAnnouncement:
+		if !nMsg.isRemote {
+			return nil, nil
+		}
+
+		return nodeAnnSigChecks(msg)
+
+	case *lnwire.ChannelAnnouncement:
+		if !nMsg.isRemote {
+			return nil, nil
+		}
+
+		return chanAnnSigChecks(msg)
+
+	case *lnwire.ChannelUpdate:
+		// If the channel isn't known yet, then the update will be
+		// handled in full while being processed.
+		chanInfo, _, _, err := d.cfg.Router.GetChannelByID(
+			msg.ShortChannelID,
+		)
+		if err != nil {
+			return nil, nil
+		}
+
+		pubKey := chanInfo.NodeKey1
+		if msg.ChannelFlags&lnwire.ChanUpdateDirection != 0 {
+			pubKey = chanInfo.NodeKey2
+		}
+
+		return chanUpdateSigChecks(pubKey, msg)
+
+	default:
+		return nil, nil
+	}
+}
+
 // networkHandler is the primary goroutine that drives this service. The roles
 // of this goroutine includes answering queries related to the state of the
 // network, syncing up newly connected peers, and also periodically
@@ -336,17 +485,20 @@ func (d *AuthenticatedGossiper) networkHandler() {
 
 	for {
 		select {
-		// A new fee update has arrived. We'll commit it to the
+		// A new policy update has arrived. We'll commit it to the
 		// sub-systems below us, then craft, sign, and broadcast a new
 		// ChannelUpdate for the set of affected clients.
-		case feeUpdate := <-d.feeUpdates:
+		case policyUpdate := <-d.chanPolicyUpdates:
 			// First, we'll now create new fully signed updates for
 			// the affected channels and also update the underlying
 			// graph with the new state.
-			newChanUpdates, err := d.processFeeChanUpdate(feeUpdate)
+			newChanUpdates, err := d.processChanPolicyUpdate(
+				policyUpdate,
+			)
 			if err != nil {
-				log.Errorf("Unable to craft fee updates: %v", err)
-				feeUpdate.errResp <- err
+				log.Errorf("Unable to craft policy updates: %v",
+					err)
+				policyUpdate.errResp <- err
 				continue
 			}
 
@@ -356,7 +508,28 @@ func (d *AuthenticatedGossiper) networkHandler() {
 			announcementBatch = append(announcementBatch,
 				newChanUpdates...)
 
-			feeUpdate.errResp <- nil
+			policyUpdate.errResp <- nil
+
+		// A channel is to be disabled or re-enabled, so we'll craft,
+		// sign, and broadcast a new ChannelUpdate for it, unless it
+		// already has the requested status.
+		case statusUpdate := <-d.chanStatusUpdates:
+			newChanUpdate, err := d.processChanStatusUpdate(
+				statusUpdate,
+			)
+			if err != nil {
+				log.Errorf("Unable to craft status update: %v",
+					err)
+				statusUpdate.errResp <- err
+				continue
+			}
+
+			if newChanUpdate != nil {
+				announcementBatch = append(announcementBatch,
+					newChanUpdate)
+			}
+
+			statusUpdate.errResp <- nil
 
 		case announcement := <-d.networkMsgs:
 			// Process the network announcement to determine if
@@ -364,6 +537,7 @@ func (d *AuthenticatedGossiper) networkHandler() {
 			// edges to a prior vertex/edge we previously
 			// proceeded.
 			emittedAnnouncements := d.processNetworkAnnouncement(announcement)
+			close(announcement.processed)
 
 			// If the announcement was accepted, then add the
 			// emitted announcements to our announce batch to be
@@ -455,9 +629,11 @@ func (d *AuthenticatedGossiper) networkHandler() {
 					ShortChannelID:  lnwire.NewShortChanIDFromInt(p.ChannelID),
 					ChainHash:       ei.ChainHash,
 					Timestamp:       uint32(p.LastUpdate.Unix()),
-					Flags:           p.Flags,
+					MessageFlags:    p.MessageFlags,
+					ChannelFlags:    p.ChannelFlags,
 					TimeLockDelta:   p.TimeLockDelta,
 					HtlcMinimumMsat: p.MinHTLC,
+					HtlcMaximumMsat: p.MaxHTLC,
 					BaseFee:         uint32(p.FeeBaseMSat),
 					FeeRate:         uint32(p.FeeProportionalMillionths),
 				}
@@ -504,19 +680,22 @@ func (d *AuthenticatedGossiper) networkHandler() {
 	}
 }
 
-// processFeeChanUpdate generates a new set of channel updates with the new fee
-// schema applied for each specified channel identified by its channel point.
-// In the case that no channel points are specified, then the fee update will
-// be applied to all channels. Finally, the backing ChannelGraphSource is
-// updated with the latest information reflecting the
-// applied fee updates.
-//
-// TODO(roasbeef): generalize into generic for any channel update
-func (d *AuthenticatedGossiper) processFeeChanUpdate(feeUpdate *feeUpdateRequest) ([]lnwire.Message, error) {
+// processChanPolicyUpdate generates a new set of channel updates with the new
+// forwarding policy applied for each specified channel identified by its
+// channel point. In the case that no channel points are specified, then the
+// policy update will be applied to all channels. Finally, the backing
+// ChannelGraphSource is updated with the latest information reflecting the
+// applied policy updates. If the new policy is invalid for any of the
+// channels, then none of them is updated.
+func (d *AuthenticatedGossiper) processChanPolicyUpdate(
+	policyUpdate *chanPolicyUpdateRequest) ([]lnwire.Message, error) {
+
+	newPolicy := policyUpdate.newPolicy
+
 	// First, we'll construct a set of all the channels that need to be
 	// updated.
 	chansToUpdate := make(map[wire.OutPoint]struct{})
-	for _, chanPoint := range feeUpdate.targetChans {
+	for _, chanPoint := range policyUpdate.targetChans {
 		chansToUpdate[chanPoint] = struct{}{}
 	}
 
@@ -537,6 +716,13 @@ func (d *AuthenticatedGossiper) processFeeChanUpdate(feeUpdate *feeUpdateRequest
 			return nil
 		}
 
+		// Before updating anything, we'll ensure the new HTLC limits,
+		// if any, are valid for the channel. They're checked against
+		// the current value of the limit that's left unchanged.
+		if err := validateHTLCLimits(info, edge, newPolicy); err != nil {
+			return err
+		}
+
 		// Otherwise, add the channel update to our batch to be
 		// updated, as we'll be re-signing it shortly.
 		c := &lnwire.ChannelUpdate{
@@ -544,9 +730,11 @@ func (d *AuthenticatedGossiper) processFeeChanUpdate(feeUpdate *feeUpdateRequest
 			ChainHash:       info.ChainHash,
 			ShortChannelID:  lnwire.NewShortChanIDFromInt(edge.ChannelID),
 			Timestamp:       uint32(edge.LastUpdate.Unix()),
-			Flags:           edge.Flags,
+			MessageFlags:    edge.MessageFlags,
+			ChannelFlags:    edge.ChannelFlags,
 			TimeLockDelta:   edge.TimeLockDelta,
 			HtlcMinimumMsat: edge.MinHTLC,
+			HtlcMaximumMsat: edge.MaxHTLC,
 			BaseFee:         uint32(edge.FeeBaseMSat),
 			FeeRate:         uint32(edge.FeeProportionalMillionths),
 		}
@@ -569,17 +757,32 @@ func (d *AuthenticatedGossiper) processFeeChanUpdate(feeUpdate *feeUpdateRequest
 		edge := chanEdges[chanUpdate.ShortChannelID]
 		now := time.Now()
 
-		// First, we'll apply the new few schema update to the channel
-		// update and also the backing database struct.
-		chanUpdate.BaseFee = uint32(feeUpdate.newSchema.BaseFee)
-		chanUpdate.FeeRate = feeUpdate.newSchema.FeeRate
+		// First, we'll apply the new policy to the channel update and
+		// also the backing database struct.
+		chanUpdate.BaseFee = uint32(newPolicy.BaseFee)
+		chanUpdate.FeeRate = newPolicy.FeeRate
 		chanUpdate.Timestamp = uint32(now.Unix())
-		edge.FeeBaseMSat = feeUpdate.newSchema.BaseFee
-		edge.FeeProportionalMillionths = lnwire.MilliSatoshi(
-			feeUpdate.newSchema.FeeRate,
+		edge.FeeBaseMSat = newPolicy.BaseFee
+		edge.FeeProportionalMillionths = lnwire.MilliAtom(
+			newPolicy.FeeRate,
 		)
 		edge.LastUpdate = now
 
+		if newPolicy.TimeLockDelta != 0 {
+			chanUpdate.TimeLockDelta = newPolicy.TimeLockDelta
+			edge.TimeLockDelta = newPolicy.TimeLockDelta
+		}
+		if newPolicy.MinHTLC != 0 {
+			chanUpdate.HtlcMinimumMsat = newPolicy.MinHTLC
+			edge.MinHTLC = newPolicy.MinHTLC
+		}
+		if newPolicy.MaxHTLC != 0 {
+			chanUpdate.MessageFlags |= lnwire.ChanUpdateOptionMaxHtlc
+			chanUpdate.HtlcMaximumMsat = newPolicy.MaxHTLC
+			edge.MessageFlags = chanUpdate.MessageFlags
+			edge.MaxHTLC = newPolicy.MaxHTLC
+		}
+
 		// With the update applied, we'll generate a new signature over
 		// a digest of the channel announcement itself.
 		sig, err := SignAnnouncement(d.cfg.AnnSigner, d.selfKey,
@@ -612,6 +815,128 @@ func (d *AuthenticatedGossiper) processFeeChanUpdate(feeUpdate *feeUpdateRequest
 	return signedAnns, nil
 }
 
+// processChanStatusUpdate generates a new channel update for the target
+// channel with its disabled bit set or cleared as requested, and commits it
+// to the backing ChannelGraphSource. If the channel already has the requested
+// status, or hasn't been announced yet, then nil is returned.
+func (d *AuthenticatedGossiper) processChanStatusUpdate(
+	statusUpdate *chanStatusUpdateRequest) (lnwire.Message, error) {
+
+	var (
+		info *channeldb.ChannelEdgeInfo
+		edge *channeldb.ChannelEdgePolicy
+	)
+	err := d.cfg.Router.ForAllOutgoingChannels(func(
+		i *channeldb.ChannelEdgeInfo, e *channeldb.ChannelEdgePolicy) error {
+
+		if e != nil && e.ChannelID == statusUpdate.chanID.ToUint64() {
+			info, edge = i, e
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Channels which haven't been announced yet have no outgoing edge,
+	// so there's no update to send for them.
+	if edge == nil {
+		log.Debugf("No outgoing edge for chan_id=%v, skipping status "+
+			"update", statusUpdate.chanID)
+		return nil, nil
+	}
+
+	isDisabled := edge.ChannelFlags&lnwire.ChanUpdateDisabled != 0
+	if isDisabled == statusUpdate.disabled {
+		return nil, nil
+	}
+
+	if statusUpdate.disabled {
+		edge.ChannelFlags |= lnwire.ChanUpdateDisabled
+	} else {
+		edge.ChannelFlags &^= lnwire.ChanUpdateDisabled
+	}
+
+	// The new update must be strictly newer than the current one, or it
+	// would be ignored as stale.
+	now := time.Now()
+	if !now.Truncate(time.Second).After(edge.LastUpdate) {
+		now = edge.LastUpdate.Add(time.Second)
+	}
+	edge.LastUpdate = now
+
+	chanUpdate := &lnwire.ChannelUpdate{
+		ChainHash:       info.ChainHash,
+		ShortChannelID:  statusUpdate.chanID,
+		Timestamp:       uint32(now.Unix()),
+		MessageFlags:    edge.MessageFlags,
+		ChannelFlags:    edge.ChannelFlags,
+		TimeLockDelta:   edge.TimeLockDelta,
+		HtlcMinimumMsat: edge.MinHTLC,
+		HtlcMaximumMsat: edge.MaxHTLC,
+		BaseFee:         uint32(edge.FeeBaseMSat),
+		FeeRate:         uint32(edge.FeeProportionalMillionths),
+	}
+
+	sig, err := SignAnnouncement(d.cfg.AnnSigner, d.selfKey, chanUpdate)
+	if err != nil {
+		return nil, err
+	}
+	edge.Signature = sig
+	chanUpdate.Signature = sig
+
+	err = d.validateChannelUpdateAnn(d.selfKey, chanUpdate)
+	if err != nil {
+		return nil, fmt.Errorf("generated invalid channel update "+
+			"sig: %v", err)
+	}
+
+	log.Infof("Marking chan_id=%v as disabled=%v", statusUpdate.chanID,
+		statusUpdate.disabled)
+
+	edge.Node.PubKey.Curve = nil
+	if err := d.cfg.Router.UpdateEdge(edge); err != nil {
+		return nil, err
+	}
+
+	return chanUpdate, nil
+}
+
+// validateHTLCLimits ensures that the HTLC limits of the passed policy are
+// valid for the channel with the passed info and current policy. The maximum
+// HTLC may not exceed the capacity of the channel, nor be below the minimum
+// HTLC.
+func validateHTLCLimits(info *channeldb.ChannelEdgeInfo,
+	edge *channeldb.ChannelEdgePolicy, newPolicy routing.ChannelPolicy) error {
+
+	if newPolicy.MinHTLC == 0 && newPolicy.MaxHTLC == 0 {
+		return nil
+	}
+
+	minHTLC, maxHTLC := edge.MinHTLC, lnwire.MilliAtom(0)
+	if edge.MessageFlags.HasMaxHtlc() {
+		maxHTLC = edge.MaxHTLC
+	}
+	if newPolicy.MinHTLC != 0 {
+		minHTLC = newPolicy.MinHTLC
+	}
+	if newPolicy.MaxHTLC != 0 {
+		maxHTLC = newPolicy.MaxHTLC
+	}
+
+	capacity := lnwire.NewMSatFromSatoshis(info.Capacity)
+	if info.Capacity != 0 && maxHTLC > capacity {
+		return fmt.Errorf("max htlc of %v exceeds the capacity of "+
+			"ChannelPoint(%v)", maxHTLC, info.ChannelPoint)
+	}
+	if maxHTLC != 0 && minHTLC > maxHTLC {
+		return fmt.Errorf("min htlc of %v exceeds the max htlc of %v "+
+			"for ChannelPoint(%v)", minHTLC, maxHTLC,
+			info.ChannelPoint)
+	}
+
+	return nil
+}
+
 // processNetworkAnnouncement processes a new network relate authenticated
 // channel or node announcement or announcements proofs. If the announcement
 // didn't affect the internal state due to either being out of date, invalid,
@@ -632,7 +957,7 @@ func (d *AuthenticatedGossiper) processNetworkAnnouncement(nMsg *networkMsg) []l
 	// information about a node in one of the channels we know about, or a
 	// updating previously advertised information.
 	case *lnwire.NodeAnnouncement:
-		if nMsg.isRemote {
+		if nMsg.isRemote && !nMsg.sigsVerified {
 			if err := d.validateNodeAnn(msg); err != nil {
 				err := errors.Errorf("unable to validate "+
 					"node announcement: %v", err)
@@ -698,8 +1023,7 @@ func (d *AuthenticatedGossiper) processNetworkAnnouncement(nMsg *networkMsg) []l
 		// If this is a remote channel announcement, then we'll validate
 		// all the signatures within the proof as it should be well
 		// formed.
-		var proof *channeldb.ChannelAuthProof
-		if nMsg.isRemote {
+		if nMsg.isRemote && !nMsg.sigsVerified {
 			if err := d.validateChannelAnn(msg); err != nil {
 				err := errors.Errorf("unable to validate "+
 					"announcement: %v", err)
@@ -708,7 +1032,10 @@ func (d *AuthenticatedGossiper) processNetworkAnnouncement(nMsg *networkMsg) []l
 				nMsg.err <- err
 				return nil
 			}
+		}
 
+		var proof *channeldb.ChannelAuthProof
+		if nMsg.isRemote {
 			// If the proof checks out, then we'll save the proof
 			// itself to the database so we can fetch it later when
 			// gossiping with other nodes.
@@ -806,35 +1133,42 @@ func (d *AuthenticatedGossiper) processNetworkAnnouncement(nMsg *networkMsg) []l
 		// The flag on the channel update announcement tells us "which"
 		// side of the channels directed edge is being updated.
 		var pubKey *btcec.PublicKey
-		switch msg.Flags {
+		switch msg.ChannelFlags & lnwire.ChanUpdateDirection {
 		case 0:
 			pubKey = chanInfo.NodeKey1
-		case 1:
+		case lnwire.ChanUpdateDirection:
 			pubKey = chanInfo.NodeKey2
 		}
 
 		// Validate the channel announcement with the expected public
-		// key, In the case of an invalid channel , we'll return an
-		// error to the caller and exit early.
-		if err := d.validateChannelUpdateAnn(pubKey, msg); err != nil {
-			rErr := errors.Errorf("unable to validate channel "+
-				"update announcement for short_chan_id=%v: %v",
-				spew.Sdump(msg.ShortChannelID), err)
-
-			log.Error(rErr)
-			nMsg.err <- rErr
-			return nil
+		// key, unless the validation pool already has. In the case of
+		// an invalid channel , we'll return an error to the caller and
+		// exit early.
+		if !nMsg.sigsVerified {
+			err := d.validateChannelUpdateAnn(pubKey, msg)
+			if err != nil {
+				rErr := errors.Errorf("unable to validate "+
+					"channel update announcement for "+
+					"short_chan_id=%v: %v",
+					spew.Sdump(msg.ShortChannelID), err)
+
+				log.Error(rErr)
+				nMsg.err <- rErr
+				return nil
+			}
 		}
 
 		update := &channeldb.ChannelEdgePolicy{
 			Signature:                 msg.Signature,
 			ChannelID:                 shortChanID,
 			LastUpdate:                time.Unix(int64(msg.Timestamp), 0),
-			Flags:                     msg.Flags,
+			MessageFlags:              msg.MessageFlags,
+			ChannelFlags:              msg.ChannelFlags,
 			TimeLockDelta:             msg.TimeLockDelta,
 			MinHTLC:                   msg.HtlcMinimumMsat,
-			FeeBaseMSat:               lnwire.MilliSatoshi(msg.BaseFee),
-			FeeProportionalMillionths: lnwire.MilliSatoshi(msg.FeeRate),
+			MaxHTLC:                   msg.HtlcMaximumMsat,
+			FeeBaseMSat:               lnwire.MilliAtom(msg.BaseFee),
+			FeeProportionalMillionths: lnwire.MilliAtom(msg.FeeRate),
 		}
 
 		if err := d.cfg.Router.UpdateEdge(update); err != nil {
@@ -1106,9 +1440,20 @@ func (d *AuthenticatedGossiper) synchronizeWithNode(syncReq *syncRequest) error
 	// announcements, we first retrieve the initial announcement, as well as
 	// the latest channel update announcement for both of the directed edges
 	// that make up each channel, and queue these to be sent to the peer.
+	//
+	// Channels which have been marked as zombies are likely unusable, so
+	// we won't relay them.
+	zombies, err := d.cfg.Router.ZombieChannels()
+	if err != nil {
+		return err
+	}
 	var numEdges uint32
 	if err := d.cfg.Router.ForEachChannel(func(chanInfo *channeldb.ChannelEdgeInfo,
 		e1, e2 *channeldb.ChannelEdgePolicy) error {
+
+		if _, ok := zombies[chanInfo.ChannelID]; ok {
+			return nil
+		}
 		// First, using the parameters of the channel, along with the
 		// channel authentication proof, we'll create re-create the
 		// original authenticated channel announcement.
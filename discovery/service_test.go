@@ -109,6 +109,10 @@ type mockGraphSource struct {
 	infos      map[uint64]*channeldb.ChannelEdgeInfo
 	edges      map[uint64][]*channeldb.ChannelEdgePolicy
 	bestHeight uint32
+
+	// mu guards the maps above, as channels are looked up by the
+	// validation pool concurrently with their modification.
+	mu sync.Mutex
 }
 
 func newMockRouter(height uint32) *mockGraphSource {
@@ -122,11 +126,17 @@ func newMockRouter(height uint32) *mockGraphSource {
 var _ routing.ChannelGraphSource = (*mockGraphSource)(nil)
 
 func (r *mockGraphSource) AddNode(node *channeldb.LightningNode) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	r.nodes = append(r.nodes, node)
 	return nil
 }
 
 func (r *mockGraphSource) AddEdge(info *channeldb.ChannelEdgeInfo) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	if _, ok := r.infos[info.ChannelID]; ok {
 		return errors.New("info already exist")
 	}
@@ -135,6 +145,9 @@ func (r *mockGraphSource) AddEdge(info *channeldb.ChannelEdgeInfo) error {
 }
 
 func (r *mockGraphSource) UpdateEdge(edge *channeldb.ChannelEdgePolicy) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	r.edges[edge.ChannelID] = append(
 		r.edges[edge.ChannelID],
 		edge,
@@ -169,11 +182,18 @@ func (r *mockGraphSource) ForEachChannel(func(chanInfo *channeldb.ChannelEdgeInf
 	return nil
 }
 
+func (r *mockGraphSource) ZombieChannels() (map[uint64]struct{}, error) {
+	return nil, nil
+}
+
 func (r *mockGraphSource) GetChannelByID(chanID lnwire.ShortChannelID) (
 	*channeldb.ChannelEdgeInfo,
 	*channeldb.ChannelEdgePolicy,
 	*channeldb.ChannelEdgePolicy, error) {
 
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	chanInfo, ok := r.infos[chanID.ToUint64()]
 	if !ok {
 		return nil, nil, nil, errors.New("can't find channel info")
@@ -345,7 +365,7 @@ func createUpdateAnnouncement(blockHeight uint32) (*lnwire.ChannelUpdate, error)
 		},
 		Timestamp:       uint32(prand.Int31()),
 		TimeLockDelta:   uint16(prand.Int63()),
-		HtlcMinimumMsat: lnwire.MilliSatoshi(prand.Int63()),
+		HtlcMinimumMsat: lnwire.MilliAtom(prand.Int63()),
 		FeeRate:         uint32(prand.Int31()),
 		BaseFee:         uint32(prand.Int31()),
 	}
@@ -835,3 +855,68 @@ func TestOrphanSignatureAnnouncement(t *testing.T) {
 		t.Fatal("wrong number of objects in storage")
 	}
 }
+
+// TestValidateHTLCLimits asserts that policy updates are only accepted if the
+// resulting HTLC limits are valid for the channel.
+func TestValidateHTLCLimits(t *testing.T) {
+	t.Parallel()
+
+	info := &channeldb.ChannelEdgeInfo{
+		Capacity: 1000,
+	}
+	capacity := lnwire.NewMSatFromSatoshis(info.Capacity)
+
+	tests := []struct {
+		name      string
+		edge      *channeldb.ChannelEdgePolicy
+		newPolicy routing.ChannelPolicy
+		valid     bool
+	}{
+		{
+			name:  "limits unchanged",
+			edge:  &channeldb.ChannelEdgePolicy{MinHTLC: 1000},
+			valid: true,
+		},
+		{
+			name:      "max htlc at capacity",
+			edge:      &channeldb.ChannelEdgePolicy{},
+			newPolicy: routing.ChannelPolicy{MaxHTLC: capacity},
+			valid:     true,
+		},
+		{
+			name:      "max htlc above capacity",
+			edge:      &channeldb.ChannelEdgePolicy{},
+			newPolicy: routing.ChannelPolicy{MaxHTLC: capacity + 1},
+		},
+		{
+			name:      "max htlc below current min htlc",
+			edge:      &channeldb.ChannelEdgePolicy{MinHTLC: 1000},
+			newPolicy: routing.ChannelPolicy{MaxHTLC: 999},
+		},
+		{
+			name: "min htlc above current max htlc",
+			edge: &channeldb.ChannelEdgePolicy{
+				MessageFlags: lnwire.ChanUpdateOptionMaxHtlc,
+				MaxHTLC:      1000,
+			},
+			newPolicy: routing.ChannelPolicy{MinHTLC: 1001},
+		},
+		{
+			name:      "min htlc without max htlc",
+			edge:      &channeldb.ChannelEdgePolicy{},
+			newPolicy: routing.ChannelPolicy{MinHTLC: capacity * 2},
+			valid:     true,
+		},
+	}
+
+	for _, test := range tests {
+		err := validateHTLCLimits(info, test.edge, test.newPolicy)
+		if test.valid && err != nil {
+			t.Fatalf("%v: expected policy to be valid: %v",
+				test.name, err)
+		}
+		if !test.valid && err == nil {
+			t.Fatalf("%v: expected policy to be invalid", test.name)
+		}
+	}
+}
@@ -1,72 +1,125 @@
 package discovery
 
 import (
-	"github.com/davecgh/go-spew/spew"
 	"github.com/go-errors/errors"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing"
 	"github.com/roasbeef/btcd/btcec"
 	"github.com/roasbeef/btcd/chaincfg/chainhash"
 )
 
-// validateChannelAnn validates the channel announcement message and checks
-// that node signatures covers the announcement message, and that the bitcoin
-// signatures covers the node keys.
-func (d *AuthenticatedGossiper) validateChannelAnn(a *lnwire.ChannelAnnouncement) error {
+// chanAnnSigChecks assembles the signature checks of the channel announcement
+// message, which ensure that node signatures covers the announcement message,
+// and that the bitcoin signatures covers the node keys.
+func chanAnnSigChecks(a *lnwire.ChannelAnnouncement) ([]*routing.SigCheck, error) {
 	// First, we'll compute the digest (h) which is to be signed by each of
 	// the keys included within the node announcement message. This hash
 	// digest includes all the keys, so the (up to 4 signatures) will
 	// attest to the validity of each of the keys.
 	data, err := a.DataToSign()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	dataHash := chainhash.DoubleHashB(data)
 
-	// First we'll verify that the passed bitcoin key signature is indeed a
-	// signature over the computed hash digest.
-	if !a.BitcoinSig1.Verify(dataHash, copyPubKey(a.BitcoinKey1)) {
-		return errors.New("can't verify first bitcoin signature")
-	}
+	// The bitcoin key signatures should be signatures over the computed
+	// hash digest, and both node signatures attached should be valid
+	// signatures over the same digest.
+	return []*routing.SigCheck{
+		{
+			Sig:    a.BitcoinSig1,
+			PubKey: copyPubKey(a.BitcoinKey1),
+			Hash:   dataHash,
+			Desc:   "first bitcoin signature",
+		},
+		{
+			Sig:    a.BitcoinSig2,
+			PubKey: copyPubKey(a.BitcoinKey2),
+			Hash:   dataHash,
+			Desc:   "second bitcoin signature",
+		},
+		{
+			Sig:    a.NodeSig1,
+			PubKey: copyPubKey(a.NodeID1),
+			Hash:   dataHash,
+			Desc:   "data in first node signature",
+		},
+		{
+			Sig:    a.NodeSig2,
+			PubKey: copyPubKey(a.NodeID2),
+			Hash:   dataHash,
+			Desc:   "data in second node signature",
+		},
+	}, nil
+}
 
-	// If that checks out, then we'll verify that the second bitcoin
-	// signature is a valid signature of the bitcoin public key over hash
-	// digest as well.
-	if !a.BitcoinSig2.Verify(dataHash, copyPubKey(a.BitcoinKey2)) {
-		return errors.New("can't verify second bitcoin signature")
+// validateChannelAnn validates the channel announcement message and checks
+// that node signatures covers the announcement message, and that the bitcoin
+// signatures covers the node keys.
+func (d *AuthenticatedGossiper) validateChannelAnn(a *lnwire.ChannelAnnouncement) error {
+	checks, err := chanAnnSigChecks(a)
+	if err != nil {
+		return err
 	}
 
-	// Both node signatures attached should indeed be a valid signature
-	// over the selected digest of the channel announcement signature.
-	if !a.NodeSig1.Verify(dataHash, copyPubKey(a.NodeID1)) {
-		return errors.New("can't verify data in first node signature")
-	}
-	if !a.NodeSig2.Verify(dataHash, copyPubKey(a.NodeID2)) {
-		return errors.New("can't verify data in second node signature")
-	}
+	return routing.VerifySigChecks(checks)
+}
 
-	return nil
+// nodeAnnSigChecks assembles the signature check of the node announcement,
+// which ensures that the attached signature is a signature of the node
+// announcement under the specified node public key.
+func nodeAnnSigChecks(a *lnwire.NodeAnnouncement) ([]*routing.SigCheck, error) {
+	// Reconstruct the data of announcement which should be covered by the
+	// signature so we can verify the signature shortly below
+	data, err := a.DataToSign()
+	if err != nil {
+		return nil, err
+	}
 
+	return []*routing.SigCheck{
+		{
+			Sig:    a.Signature,
+			PubKey: copyPubKey(a.NodeID),
+			Hash:   chainhash.DoubleHashB(data),
+			Desc:   "signature on node announcement",
+		},
+	}, nil
 }
 
 // validateNodeAnn validates the node announcement by ensuring that the
 // attached signature is needed a signature of the node announcement under the
 // specified node public key.
 func (d *AuthenticatedGossiper) validateNodeAnn(a *lnwire.NodeAnnouncement) error {
-	// Reconstruct the data of announcement which should be covered by the
-	// signature so we can verify the signature shortly below
-	data, err := a.DataToSign()
+	checks, err := nodeAnnSigChecks(a)
 	if err != nil {
 		return err
 	}
 
 	// Finally ensure that the passed signature is valid, if not we'll
 	// return an error so this node announcement can be rejected.
-	dataHash := chainhash.DoubleHashB(data)
-	if !a.Signature.Verify(dataHash, copyPubKey(a.NodeID)) {
-		return errors.New("signature on node announcement is invalid")
+	return routing.VerifySigChecks(checks)
+}
+
+// chanUpdateSigChecks assembles the signature check of the channel update
+// announcement, which ensures that the included signature covers the
+// announcement and has been signed by the node's private key.
+func chanUpdateSigChecks(pubKey *btcec.PublicKey,
+	a *lnwire.ChannelUpdate) ([]*routing.SigCheck, error) {
+
+	data, err := a.DataToSign()
+	if err != nil {
+		return nil, errors.Errorf("unable to reconstruct message: %v",
+			err)
 	}
 
-	return nil
+	return []*routing.SigCheck{
+		{
+			Sig:    a.Signature,
+			PubKey: copyPubKey(pubKey),
+			Hash:   chainhash.DoubleHashB(data),
+			Desc:   "signature for channel update",
+		},
+	}, nil
 }
 
 // validateChannelUpdateAnn validates the channel update announcement by
@@ -75,16 +128,10 @@ func (d *AuthenticatedGossiper) validateNodeAnn(a *lnwire.NodeAnnouncement) erro
 func (d *AuthenticatedGossiper) validateChannelUpdateAnn(pubKey *btcec.PublicKey,
 	a *lnwire.ChannelUpdate) error {
 
-	data, err := a.DataToSign()
+	checks, err := chanUpdateSigChecks(pubKey, a)
 	if err != nil {
-		return errors.Errorf("unable to reconstruct message: %v", err)
-	}
-	dataHash := chainhash.DoubleHashB(data)
-
-	if !a.Signature.Verify(dataHash, copyPubKey(pubKey)) {
-		return errors.Errorf("invalid signature for channel "+
-			"update %v", spew.Sdump(a))
+		return err
 	}
 
-	return nil
+	return routing.VerifySigChecks(checks)
 }
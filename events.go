@@ -0,0 +1,68 @@
+package main
+
+import (
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/subscribe"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+// The types below are the events sent over the server's event bus. Producers
+// send them to the bus without any knowledge of their consumers, and each
+// consumer subscribes to only the event types it's interested in.
+
+// peerOnlineEvent is sent once a connection with a peer has been established
+// and the peer has been started.
+type peerOnlineEvent struct {
+	pubKey [33]byte
+}
+
+// peerOfflineEvent is sent once a peer has been disconnected.
+type peerOfflineEvent struct {
+	pubKey [33]byte
+}
+
+// channelOpenEvent is sent once a newly funded channel has been confirmed and
+// is ready to be used.
+type channelOpenEvent struct {
+	chanPoint   wire.OutPoint
+	shortChanID lnwire.ShortChannelID
+	remotePub   *btcec.PublicKey
+	capacity    btcutil.Amount
+}
+
+// channelCloseEvent is sent once the closing transaction of a channel has
+// been confirmed, or a breach of the channel has been detected.
+type channelCloseEvent struct {
+	chanPoint   wire.OutPoint
+	shortChanID lnwire.ShortChannelID
+	breach      bool
+}
+
+// blockEpochEvent is sent each time a new block is connected to the main
+// chain.
+type blockEpochEvent struct {
+	height int32
+	hash   chainhash.Hash
+}
+
+// customMessageEvent is sent each time a message within the custom type range
+// is received from a peer.
+type customMessageEvent struct {
+	peer [33]byte
+	msg  *lnwire.Custom
+}
+
+// sendEvent sends the passed event over the event bus, if non-nil. The event
+// is dropped if the bus is shutting down.
+func sendEvent(bus *subscribe.Server, event interface{}) {
+	if bus == nil {
+		return
+	}
+
+	if err := bus.SendUpdate(event); err != nil {
+		ltndLog.Debugf("unable to send event: %v", err)
+	}
+}
@@ -0,0 +1,304 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+)
+
+// extSignRequest is a single signing request sent to the external signer. The
+// signer is expected to sign the double-sha256 digest of the message under
+// the private key corresponding to the target public key.
+type extSignRequest struct {
+	// PubKey is the hex-encoded compressed public key to sign with.
+	PubKey string `json:"pubkey"`
+
+	// Msg is the hex-encoded message to be signed.
+	Msg string `json:"msg"`
+}
+
+// extSignResult is the result of a single signing request returned by the
+// external signer.
+type extSignResult struct {
+	// Signature is the hex-encoded DER signature over the request's
+	// message.
+	Signature string `json:"signature"`
+
+	// Error is a non-empty string if the external signer was unable to
+	// sign the request.
+	Error string `json:"error,omitempty"`
+}
+
+// extSignBatchRequest is the body of a request sent to the external signer.
+type extSignBatchRequest struct {
+	Requests []extSignRequest `json:"requests"`
+}
+
+// extSignBatchResponse is the body of a response returned by the external
+// signer. The results must be in the same order as the requests within the
+// batch.
+type extSignBatchResponse struct {
+	Results []extSignResult `json:"results"`
+}
+
+// pendingSignReq is a signing request that's waiting to be dispatched within
+// the next batch.
+type pendingSignReq struct {
+	cacheKey [32]byte
+	pubKey   *btcec.PublicKey
+	msg      []byte
+
+	sig chan *btcec.Signature
+	err chan error
+}
+
+// externalSigner is an implementation of the MessageSigner interface which
+// forwards all signing requests to an external signer bridge over HTTP. This
+// allows a node which doesn't have access to its private keys (such as a
+// watch-only node) to still sign the channel and node announcements required
+// to participate in gossip.
+//
+// Requests arriving within a short interval of each other are dispatched to
+// the external signer as a single batch, and the most recent signatures are
+// cached so that re-signing an unchanged announcement doesn't require a
+// round-trip.
+type externalSigner struct {
+	cfg *externalSignerConfig
+
+	client *http.Client
+
+	// batchMtx guards the current pending batch.
+	batchMtx sync.Mutex
+	batch    []*pendingSignReq
+
+	// cacheMtx guards the signature cache. cacheOrder tracks the order
+	// in which entries were inserted so the oldest can be evicted once
+	// the cache is full.
+	cacheMtx   sync.Mutex
+	cache      map[[32]byte]*btcec.Signature
+	cacheOrder [][32]byte
+}
+
+// newExternalSigner creates a new instance of the externalSigner which
+// forwards all signing requests to the signer located at the configured URL.
+func newExternalSigner(cfg *externalSignerConfig) *externalSigner {
+	return &externalSigner{
+		cfg: cfg,
+		client: &http.Client{
+			Timeout: cfg.Timeout,
+		},
+		cache: make(map[[32]byte]*btcec.Signature),
+	}
+}
+
+// SignMessage signs a double-sha256 digest of the passed msg under the private
+// key corresponding to the passed public key by forwarding the request to the
+// external signer. If a signature for the same key and message was recently
+// generated, then it's returned directly from the cache.
+//
+// NOTE: This is a part of the MessageSigner interface.
+func (e *externalSigner) SignMessage(pubKey *btcec.PublicKey,
+	msg []byte) (*btcec.Signature, error) {
+
+	// The cache is keyed by the hash of the public key and the message,
+	// as signatures are only valid for a particular pair of both.
+	h := sha256.New()
+	h.Write(pubKey.SerializeCompressed())
+	h.Write(msg)
+	var cacheKey [32]byte
+	copy(cacheKey[:], h.Sum(nil))
+
+	e.cacheMtx.Lock()
+	sig, ok := e.cache[cacheKey]
+	e.cacheMtx.Unlock()
+	if ok {
+		return sig, nil
+	}
+
+	req := &pendingSignReq{
+		cacheKey: cacheKey,
+		pubKey:   pubKey,
+		msg:      msg,
+		sig:      make(chan *btcec.Signature, 1),
+		err:      make(chan error, 1),
+	}
+
+	// Add the request to the pending batch. If this is the first request
+	// within the batch, then we'll schedule the batch to be dispatched
+	// once the batch interval has passed. If the batch is now full, then
+	// we'll dispatch it immediately.
+	e.batchMtx.Lock()
+	e.batch = append(e.batch, req)
+	switch {
+	case len(e.batch) >= e.cfg.MaxBatchSize:
+		batch := e.batch
+		e.batch = nil
+		go e.dispatchBatch(batch)
+
+	case len(e.batch) == 1:
+		time.AfterFunc(e.cfg.BatchInterval, e.flushBatch)
+	}
+	e.batchMtx.Unlock()
+
+	select {
+	case sig := <-req.sig:
+		return sig, nil
+	case err := <-req.err:
+		return nil, err
+	}
+}
+
+// flushBatch dispatches the current pending batch, if any.
+func (e *externalSigner) flushBatch() {
+	e.batchMtx.Lock()
+	batch := e.batch
+	e.batch = nil
+	e.batchMtx.Unlock()
+
+	if len(batch) == 0 {
+		return
+	}
+
+	e.dispatchBatch(batch)
+}
+
+// dispatchBatch sends the passed batch of requests to the external signer,
+// and delivers the result of each request to its caller. Each returned
+// signature is verified before being handed to the caller.
+func (e *externalSigner) dispatchBatch(batch []*pendingSignReq) {
+	ltndLog.Debugf("Sending batch of %v signing requests to external "+
+		"signer", len(batch))
+
+	results, err := e.sendBatch(batch)
+	if err != nil {
+		ltndLog.Errorf("Unable to sign batch with external signer: %v",
+			err)
+
+		for _, req := range batch {
+			req.err <- err
+		}
+		return
+	}
+
+	for i, req := range batch {
+		sig, err := parseExtSignResult(req, &results[i])
+		if err != nil {
+			req.err <- err
+			continue
+		}
+
+		e.addToCache(req.cacheKey, sig)
+		req.sig <- sig
+	}
+}
+
+// sendBatch sends the passed batch of requests to the external signer and
+// returns the raw results.
+func (e *externalSigner) sendBatch(
+	batch []*pendingSignReq) ([]extSignResult, error) {
+
+	batchReq := extSignBatchRequest{
+		Requests: make([]extSignRequest, len(batch)),
+	}
+	for i, req := range batch {
+		batchReq.Requests[i] = extSignRequest{
+			PubKey: hex.EncodeToString(req.pubKey.SerializeCompressed()),
+			Msg:    hex.EncodeToString(req.msg),
+		}
+	}
+
+	body, err := json.Marshal(&batchReq)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := e.client.Post(
+		e.cfg.URL, "application/json", bytes.NewReader(body),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("external signer returned status: %v",
+			resp.Status)
+	}
+
+	var batchResp extSignBatchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&batchResp); err != nil {
+		return nil, err
+	}
+
+	if len(batchResp.Results) != len(batch) {
+		return nil, fmt.Errorf("external signer returned %v results "+
+			"for %v requests", len(batchResp.Results), len(batch))
+	}
+
+	return batchResp.Results, nil
+}
+
+// parseExtSignResult parses the signature within the passed result, ensuring
+// it's a valid signature for the original request.
+func parseExtSignResult(req *pendingSignReq,
+	result *extSignResult) (*btcec.Signature, error) {
+
+	if result.Error != "" {
+		return nil, fmt.Errorf("external signer unable to sign: %v",
+			result.Error)
+	}
+
+	sigBytes, err := hex.DecodeString(result.Signature)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := btcec.ParseDERSignature(sigBytes, btcec.S256())
+	if err != nil {
+		return nil, err
+	}
+
+	digest := chainhash.DoubleHashB(req.msg)
+	if !sig.Verify(digest, req.pubKey) {
+		return nil, fmt.Errorf("external signer returned invalid "+
+			"signature for key %x", req.pubKey.SerializeCompressed())
+	}
+
+	return sig, nil
+}
+
+// addToCache adds the passed signature to the cache, evicting the oldest
+// entry if the cache is full.
+func (e *externalSigner) addToCache(key [32]byte, sig *btcec.Signature) {
+	if e.cfg.CacheSize <= 0 {
+		return
+	}
+
+	e.cacheMtx.Lock()
+	defer e.cacheMtx.Unlock()
+
+	if _, ok := e.cache[key]; ok {
+		return
+	}
+
+	if len(e.cacheOrder) >= e.cfg.CacheSize {
+		oldest := e.cacheOrder[0]
+		e.cacheOrder = e.cacheOrder[1:]
+		delete(e.cache, oldest)
+	}
+
+	e.cache[key] = sig
+	e.cacheOrder = append(e.cacheOrder, key)
+}
+
+// A compile time check to ensure that externalSigner implements the
+// MessageSigner interface.
+var _ lnwallet.MessageSigner = (*externalSigner)(nil)
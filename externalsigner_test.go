@@ -0,0 +1,162 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+)
+
+// newTestSignerBridge creates a test HTTP server which mimics an external
+// signer bridge by signing all requests with the passed private key. If
+// corrupt is true, then the signatures returned will be over the wrong digest.
+// The number of batches received is tracked by numBatches.
+func newTestSignerBridge(t *testing.T, priv *btcec.PrivateKey, corrupt bool,
+	numBatches *int32) *httptest.Server {
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter,
+		r *http.Request) {
+
+		atomic.AddInt32(numBatches, 1)
+
+		var req extSignBatchRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var resp extSignBatchResponse
+		for _, signReq := range req.Requests {
+			msg, err := hex.DecodeString(signReq.Msg)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if corrupt {
+				msg = append(msg, 0x00)
+			}
+
+			sig, err := priv.Sign(chainhash.DoubleHashB(msg))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			resp.Results = append(resp.Results, extSignResult{
+				Signature: hex.EncodeToString(sig.Serialize()),
+			})
+		}
+
+		if err := json.NewEncoder(w).Encode(&resp); err != nil {
+			t.Errorf("unable to write response: %v", err)
+		}
+	}))
+}
+
+// TestExternalSignerBatchingAndCache tests that concurrent signing requests
+// are dispatched to the external signer as a single batch, and that repeated
+// requests are served from the cache.
+func TestExternalSignerBatchingAndCache(t *testing.T) {
+	t.Parallel()
+
+	priv, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+
+	var numBatches int32
+	bridge := newTestSignerBridge(t, priv, false, &numBatches)
+	defer bridge.Close()
+
+	signer := newExternalSigner(&externalSignerConfig{
+		URL:           bridge.URL,
+		BatchInterval: 100 * time.Millisecond,
+		MaxBatchSize:  10,
+		CacheSize:     10,
+		Timeout:       5 * time.Second,
+	})
+
+	// We'll send several signing requests concurrently. As they all
+	// arrive within the batch interval, they should be sent to the
+	// external signer within a single batch.
+	const numMsgs = 5
+	var wg sync.WaitGroup
+	errs := make(chan error, numMsgs)
+	for i := 0; i < numMsgs; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			msg := []byte{byte(i)}
+			sig, err := signer.SignMessage(priv.PubKey(), msg)
+			if err != nil {
+				errs <- err
+				return
+			}
+			if !sig.Verify(chainhash.DoubleHashB(msg), priv.PubKey()) {
+				t.Errorf("invalid signature for msg %v", i)
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("unable to sign message: %v", err)
+	}
+
+	if n := atomic.LoadInt32(&numBatches); n != 1 {
+		t.Fatalf("expected 1 batch, got %v", n)
+	}
+
+	// Signing one of the messages again should be served from the cache
+	// without contacting the external signer.
+	if _, err := signer.SignMessage(priv.PubKey(), []byte{0}); err != nil {
+		t.Fatalf("unable to sign message: %v", err)
+	}
+	if n := atomic.LoadInt32(&numBatches); n != 1 {
+		t.Fatalf("expected cached signature, but got %v batches", n)
+	}
+}
+
+// TestExternalSignerInvalidSignature tests that signatures returned by the
+// external signer which don't verify are rejected.
+func TestExternalSignerInvalidSignature(t *testing.T) {
+	t.Parallel()
+
+	priv, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+
+	var numBatches int32
+	bridge := newTestSignerBridge(t, priv, true, &numBatches)
+	defer bridge.Close()
+
+	signer := newExternalSigner(&externalSignerConfig{
+		URL:           bridge.URL,
+		BatchInterval: time.Millisecond,
+		MaxBatchSize:  10,
+		CacheSize:     10,
+		Timeout:       5 * time.Second,
+	})
+
+	if _, err := signer.SignMessage(priv.PubKey(), []byte("msg")); err == nil {
+		t.Fatalf("expected invalid signature to be rejected")
+	}
+
+	// The invalid signature shouldn't have been cached, so a second
+	// attempt should once again contact the external signer.
+	if _, err := signer.SignMessage(priv.PubKey(), []byte("msg")); err == nil {
+		t.Fatalf("expected invalid signature to be rejected")
+	}
+	if n := atomic.LoadInt32(&numBatches); n != 2 {
+		t.Fatalf("expected 2 batches, got %v", n)
+	}
+}
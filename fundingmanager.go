@@ -19,6 +19,7 @@ import (
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/subscribe"
 	"github.com/roasbeef/btcd/btcec"
 	"github.com/roasbeef/btcd/chaincfg/chainhash"
 	"github.com/roasbeef/btcd/wire"
@@ -163,6 +164,10 @@ type fundingConfig struct {
 	// commitment transaction.
 	ArbiterChan chan<- *lnwallet.LightningChannel
 
+	// EventBus, if non-nil, is notified each time a newly funded channel
+	// is ready to be used.
+	EventBus *subscribe.Server
+
 	// Notifier is used by the FundingManager to determine when the
 	// channel's funding transaction has been confirmed on the blockchain
 	// so that the channel creation process can be completed.
@@ -212,7 +217,7 @@ type fundingConfig struct {
 	// channel extended to it. The function is able to take into account
 	// the amount of the channel, and any funds we'll be pushed in the
 	// process to determine how many confirmations we'll require.
-	NumRequiredConfs func(btcutil.Amount, lnwire.MilliSatoshi) uint16
+	NumRequiredConfs func(btcutil.Amount, lnwire.MilliAtom) uint16
 
 	// RequiredRemoteDelay is a function that maps the total amount in a
 	// proposed channel to the CSV delay that we'll require for the remote
@@ -220,6 +225,12 @@ type fundingConfig struct {
 	// in order to give us more time to claim funds in the case of a
 	// contract breach.
 	RequiredRemoteDelay func(btcutil.Amount) uint16
+
+	// UpfrontShutdownScript, if non-nil, generates the script we commit
+	// to pay out to upon the cooperative close of a channel extended to
+	// us, which is sent to the initiator within our AcceptChannel
+	// message. If nil, we don't commit to any script for such channels.
+	UpfrontShutdownScript func() (lnwire.DeliveryAddress, error)
 }
 
 // fundingManager acts as an orchestrator/bridge between the wallet's
@@ -633,10 +644,9 @@ func (f *fundingManager) handleFundingOpen(fmsg *fundingOpenMsg) {
 	// TODO(roasbeef): modify to only accept a _single_ pending channel per
 	// block unless white listed
 	if len(f.activeReservations[peerIDKey]) >= cfg.MaxPendingChannels {
-		errMsg := &lnwire.Error{
-			ChanID: fmsg.msg.PendingChannelID,
-			Data:   lnwire.ErrorData{byte(lnwire.ErrMaxPendingChannels)},
-		}
+		errMsg := lnwire.NewCodedError(
+			fmsg.msg.PendingChannelID, lnwire.ErrMaxPendingChannels, "",
+		)
 		if err := f.cfg.SendToPeer(fmsg.peerAddress.IdentityKey, errMsg); err != nil {
 			fndgLog.Errorf("unable to send max pending channels "+
 				"message to peer: %v", err)
@@ -655,10 +665,9 @@ func (f *fundingManager) handleFundingOpen(fmsg *fundingOpenMsg) {
 		return
 	}
 	if !isSynced {
-		errMsg := &lnwire.Error{
-			ChanID: fmsg.msg.PendingChannelID,
-			Data:   lnwire.ErrorData{byte(lnwire.ErrSynchronizingChain)},
-		}
+		errMsg := lnwire.NewCodedError(
+			fmsg.msg.PendingChannelID, lnwire.ErrSynchronizingChain, "",
+		)
 		if err := f.cfg.SendToPeer(fmsg.peerAddress.IdentityKey, errMsg); err != nil {
 			fndgLog.Errorf("unable to send error message to peer %v", err)
 			return
@@ -669,10 +678,29 @@ func (f *fundingManager) handleFundingOpen(fmsg *fundingOpenMsg) {
 	// We'll reject any request to create a channel that's above the
 	// current soft-limit for channel size.
 	if msg.FundingAmount > maxFundingAmount {
-		errMsg := &lnwire.Error{
-			ChanID: fmsg.msg.PendingChannelID,
-			Data:   lnwire.ErrorData{byte(lnwire.ErrChanTooLarge)},
+		errMsg := lnwire.NewCodedError(
+			fmsg.msg.PendingChannelID, lnwire.ErrChanTooLarge, "",
+		)
+		err := f.cfg.SendToPeer(fmsg.peerAddress.IdentityKey, errMsg)
+		if err != nil {
+			fndgLog.Errorf("unable to send error message to peer %v", err)
+			return
 		}
+		return
+	}
+
+	// The initiator's dust limit applies to their version of the
+	// commitment transaction. If it falls outside of the bounds we
+	// consider sane, then we'll reject the request, as we may otherwise
+	// be unable to enforce HTLCs on their commitment.
+	if err := lnwallet.ValidateDustLimit(msg.DustLimit); err != nil {
+		fndgLog.Errorf("Rejecting fundingRequest(pendingId=%x) from "+
+			"peer(%x): %v", msg.PendingChannelID,
+			fmsg.peerAddress.IdentityKey.SerializeCompressed(), err)
+
+		errMsg := lnwire.NewCodedError(
+			fmsg.msg.PendingChannelID, lnwire.ErrInvalidDustLimit, "",
+		)
 		err := f.cfg.SendToPeer(fmsg.peerAddress.IdentityKey, errMsg)
 		if err != nil {
 			fndgLog.Errorf("unable to send error message to peer %v", err)
@@ -681,6 +709,34 @@ func (f *fundingManager) handleFundingOpen(fmsg *fundingOpenMsg) {
 		return
 	}
 
+	// If the initiator committed to an upfront shutdown script, then
+	// we'll ensure it's a standard script, as otherwise we may be unable
+	// to cooperatively close the channel.
+	if len(msg.UpfrontShutdownScript) != 0 {
+		err := lnwallet.ValidateUpfrontShutdownScript(
+			msg.UpfrontShutdownScript,
+		)
+		if err != nil {
+			fndgLog.Errorf("Rejecting fundingRequest(pendingId=%x) "+
+				"from peer(%x): %v", msg.PendingChannelID,
+				fmsg.peerAddress.IdentityKey.SerializeCompressed(),
+				err)
+
+			errMsg := lnwire.NewCodedError(
+				fmsg.msg.PendingChannelID,
+				lnwire.ErrInvalidUpfrontShutdown, err.Error(),
+			)
+			err := f.cfg.SendToPeer(
+				fmsg.peerAddress.IdentityKey, errMsg,
+			)
+			if err != nil {
+				fndgLog.Errorf("unable to send error message "+
+					"to peer %v", err)
+			}
+			return
+		}
+	}
+
 	// TODO(roasbeef): validate sanity of all params sent
 
 	// TODO(roasbeef): error if funding flow already ongoing
@@ -702,8 +758,18 @@ func (f *fundingManager) handleFundingOpen(fmsg *fundingOpenMsg) {
 		fmsg.peerAddress.IdentityKey, fmsg.peerAddress.Address,
 		&chainHash)
 	if err != nil {
-		// TODO(roasbeef): push ErrorGeneric message
 		fndgLog.Errorf("Unable to initialize reservation: %v", err)
+
+		// We'll let the initiator know that we're unable to accept
+		// the channel at this time, although they may retry later on.
+		errMsg := lnwire.NewCodedError(
+			fmsg.msg.PendingChannelID,
+			lnwire.ErrTemporaryChannelFailure, "",
+		)
+		err := f.cfg.SendToPeer(fmsg.peerAddress.IdentityKey, errMsg)
+		if err != nil {
+			fndgLog.Errorf("unable to send error message to peer %v", err)
+		}
 		return
 	}
 
@@ -718,6 +784,7 @@ func (f *fundingManager) handleFundingOpen(fmsg *fundingOpenMsg) {
 	// We'll also validate and apply all the constraints the initiating
 	// party is attempting to dictate for our commitment transaction.
 	reservation.RequireLocalDelay(msg.CsvDelay)
+	reservation.SetRemoteUpfrontShutdown(msg.UpfrontShutdownScript)
 
 	fndgLog.Infof("Requiring %v confirmations for pendingChan(%x): "+
 		"amt=%v, push_amt=%v", numConfsReq, fmsg.msg.PendingChannelID,
@@ -777,6 +844,21 @@ func (f *fundingManager) handleFundingOpen(fmsg *fundingOpenMsg) {
 		return
 	}
 
+	// If we're to commit to an upfront shutdown script of our own, then
+	// we'll generate it now, such that it's recorded along with the
+	// channel and sent to the initiator.
+	var upfrontShutdown lnwire.DeliveryAddress
+	if f.cfg.UpfrontShutdownScript != nil {
+		upfrontShutdown, err = f.cfg.UpfrontShutdownScript()
+		if err != nil {
+			fndgLog.Errorf("unable to generate upfront shutdown "+
+				"script: %v", err)
+			cancelReservation()
+			return
+		}
+		reservation.SetOurUpfrontShutdown(upfrontShutdown)
+	}
+
 	fndgLog.Infof("Sending fundingResp for pendingID(%x)",
 		msg.PendingChannelID)
 
@@ -784,18 +866,19 @@ func (f *fundingManager) handleFundingOpen(fmsg *fundingOpenMsg) {
 	// contribution in the next message of the workflow.
 	ourContribution := reservation.OurContribution()
 	fundingAccept := lnwire.AcceptChannel{
-		PendingChannelID:     msg.PendingChannelID,
-		DustLimit:            ourContribution.DustLimit,
-		MaxValueInFlight:     ourContribution.MaxPendingAmount,
-		ChannelReserve:       ourContribution.ChanReserve,
-		MinAcceptDepth:       uint32(numConfsReq),
-		HtlcMinimum:          ourContribution.MinHTLC,
-		CsvDelay:             uint16(remoteCsvDelay),
-		FundingKey:           ourContribution.MultiSigKey,
-		RevocationPoint:      ourContribution.RevocationBasePoint,
-		PaymentPoint:         ourContribution.PaymentBasePoint,
-		DelayedPaymentPoint:  ourContribution.DelayBasePoint,
-		FirstCommitmentPoint: ourContribution.FirstCommitmentPoint,
+		PendingChannelID:      msg.PendingChannelID,
+		DustLimit:             ourContribution.DustLimit,
+		MaxValueInFlight:      ourContribution.MaxPendingAmount,
+		ChannelReserve:        ourContribution.ChanReserve,
+		MinAcceptDepth:        uint32(numConfsReq),
+		HtlcMinimum:           ourContribution.MinHTLC,
+		CsvDelay:              uint16(remoteCsvDelay),
+		FundingKey:            ourContribution.MultiSigKey,
+		RevocationPoint:       ourContribution.RevocationBasePoint,
+		PaymentPoint:          ourContribution.PaymentBasePoint,
+		DelayedPaymentPoint:   ourContribution.DelayBasePoint,
+		FirstCommitmentPoint:  ourContribution.FirstCommitmentPoint,
+		UpfrontShutdownScript: upfrontShutdown,
 	}
 	err = f.cfg.SendToPeer(fmsg.peerAddress.IdentityKey, &fundingAccept)
 	if err != nil {
@@ -840,11 +923,39 @@ func (f *fundingManager) handleFundingAccept(fmsg *fundingAcceptMsg) {
 
 	fndgLog.Infof("Recv'd fundingResponse for pendingID(%x)", pendingChanID)
 
+	// As with the initiator's proposal, we'll ensure the responder's dust
+	// limit for their commitment transaction is within sane bounds.
+	if err := lnwallet.ValidateDustLimit(msg.DustLimit); err != nil {
+		fndgLog.Errorf("Rejecting fundingResponse for pendingID(%x) "+
+			"from %x: %v", pendingChanID,
+			peerKey.SerializeCompressed(), err)
+		cancelReservation()
+		resCtx.err <- err
+		return
+	}
+
+	// The responder's upfront shutdown script, if any, must also be a
+	// standard script.
+	if len(msg.UpfrontShutdownScript) != 0 {
+		err := lnwallet.ValidateUpfrontShutdownScript(
+			msg.UpfrontShutdownScript,
+		)
+		if err != nil {
+			fndgLog.Errorf("Rejecting fundingResponse for "+
+				"pendingID(%x) from %x: %v", pendingChanID,
+				peerKey.SerializeCompressed(), err)
+			cancelReservation()
+			resCtx.err <- err
+			return
+		}
+	}
+
 	// We'll also specify the responder's preference for the number of
 	// required confirmations, and also the CSV delay that they specify for
 	// us within the reservation itself.
 	resCtx.reservation.SetNumConfsRequired(uint16(msg.MinAcceptDepth))
 	resCtx.reservation.RequireLocalDelay(uint16(msg.CsvDelay))
+	resCtx.reservation.SetRemoteUpfrontShutdown(msg.UpfrontShutdownScript)
 
 	// The remote node has responded with their portion of the channel
 	// contribution. At this point, we can process their contribution which
@@ -1515,6 +1626,14 @@ func (f *fundingManager) handleFundingLocked(fmsg *fundingLockedMsg) {
 	// contract by the remote party.
 	f.cfg.ArbiterChan <- channel
 
+	snapshot := channel.StateSnapshot()
+	sendEvent(f.cfg.EventBus, channelOpenEvent{
+		chanPoint:   *channel.ChannelPoint(),
+		shortChanID: channel.ShortChanID(),
+		remotePub:   &snapshot.RemoteIdentity,
+		capacity:    snapshot.Capacity,
+	})
+
 	// Launch a defer so we _ensure_ that the channel barrier is properly
 	// closed even if the target peer is not longer online at this point.
 	defer func() {
@@ -1594,7 +1713,7 @@ func (f *fundingManager) newChanAnnouncement(localPubKey, remotePubKey *btcec.Pu
 	// being updated within the ChannelUpdateAnnouncement announcement
 	// below. A value of zero means it's the edge of the "first" node and 1
 	// being the other node.
-	var chanFlags uint16
+	var chanFlags lnwire.ChanUpdateChanFlags
 
 	// The lexicographical ordering of the two identity public keys of the
 	// nodes indicates which of the nodes is "first". If our serialized
@@ -1619,14 +1738,14 @@ func (f *fundingManager) newChanAnnouncement(localPubKey, remotePubKey *btcec.Pu
 
 		// If we're the second node then update the chanFlags to
 		// indicate the "direction" of the update.
-		chanFlags = 1
+		chanFlags = lnwire.ChanUpdateDirection
 	}
 
 	chanUpdateAnn := &lnwire.ChannelUpdate{
 		ShortChannelID:  shortChanID,
 		ChainHash:       chainHash,
 		Timestamp:       uint32(time.Now().Unix()),
-		Flags:           chanFlags,
+		ChannelFlags:    chanFlags,
 		TimeLockDelta:   uint16(f.cfg.DefaultRoutingPolicy.TimeLockDelta),
 		HtlcMinimumMsat: f.cfg.DefaultRoutingPolicy.MinHTLC,
 		BaseFee:         uint32(f.cfg.DefaultRoutingPolicy.BaseFee),
@@ -1770,6 +1889,12 @@ func (f *fundingManager) handleInitFundingMsg(msg *initFundingMsg) {
 		ourDustLimit = lnwallet.DefaultDustLimit()
 	)
 
+	// If the caller specified a dust limit for our commitment
+	// transaction, then we'll propose it in place of the default.
+	if msg.dustLimit != 0 {
+		ourDustLimit = msg.dustLimit
+	}
+
 	fndgLog.Infof("Initiating fundingRequest(localAmt=%v, remoteAmt=%v, "+
 		"capacity=%v, chainhash=%v, addr=%v, dustLimit=%v)", localAmt,
 		msg.pushAmt, capacity, msg.chainHash, msg.peerAddress.Address,
@@ -1797,6 +1922,8 @@ func (f *fundingManager) handleInitFundingMsg(msg *initFundingMsg) {
 		msg.err <- err
 		return
 	}
+	reservation.SetOurDustLimit(ourDustLimit)
+	reservation.SetOurUpfrontShutdown(msg.upfrontShutdown)
 
 	// Obtain a new pending channel ID which is used to track this
 	// reservation throughout its lifetime.
@@ -1837,22 +1964,23 @@ func (f *fundingManager) handleInitFundingMsg(msg *initFundingMsg) {
 		msg.peerAddress.Address, chanID)
 
 	fundingOpen := lnwire.OpenChannel{
-		ChainHash:            *f.cfg.Wallet.Cfg.NetParams.GenesisHash,
-		PendingChannelID:     chanID,
-		FundingAmount:        capacity,
-		PushAmount:           msg.pushAmt,
-		DustLimit:            ourContribution.DustLimit,
-		MaxValueInFlight:     ourContribution.MaxPendingAmount,
-		ChannelReserve:       ourContribution.ChanReserve,
-		HtlcMinimum:          ourContribution.MinHTLC,
-		FeePerKiloWeight:     uint32(feePerKw),
-		CsvDelay:             uint16(remoteCsvDelay),
-		MaxAcceptedHTLCs:     ourContribution.MaxAcceptedHtlcs,
-		FundingKey:           ourContribution.MultiSigKey,
-		RevocationPoint:      ourContribution.RevocationBasePoint,
-		PaymentPoint:         ourContribution.PaymentBasePoint,
-		DelayedPaymentPoint:  ourContribution.DelayBasePoint,
-		FirstCommitmentPoint: ourContribution.FirstCommitmentPoint,
+		ChainHash:             *f.cfg.Wallet.Cfg.NetParams.GenesisHash,
+		PendingChannelID:      chanID,
+		FundingAmount:         capacity,
+		PushAmount:            msg.pushAmt,
+		DustLimit:             ourContribution.DustLimit,
+		MaxValueInFlight:      ourContribution.MaxPendingAmount,
+		ChannelReserve:        ourContribution.ChanReserve,
+		HtlcMinimum:           ourContribution.MinHTLC,
+		FeePerKiloWeight:      uint32(feePerKw),
+		CsvDelay:              uint16(remoteCsvDelay),
+		MaxAcceptedHTLCs:      ourContribution.MaxAcceptedHtlcs,
+		FundingKey:            ourContribution.MultiSigKey,
+		RevocationPoint:       ourContribution.RevocationBasePoint,
+		PaymentPoint:          ourContribution.PaymentBasePoint,
+		DelayedPaymentPoint:   ourContribution.DelayBasePoint,
+		FirstCommitmentPoint:  ourContribution.FirstCommitmentPoint,
+		UpfrontShutdownScript: msg.upfrontShutdown,
 	}
 	if err := f.cfg.SendToPeer(peerKey, &fundingOpen); err != nil {
 		fndgLog.Errorf("Unable to send funding request message: %v", err)
@@ -1911,11 +2039,31 @@ func (f *fundingManager) handleErrorMsg(fmsg *fundingErrorMsg) {
 
 	// If we did indeed find the funding workflow, then we'll return the
 	// error back to the caller (if any), and cancel the workflow itself.
-	lnErr := lnwire.ErrorCode(protocolErr.Data[0])
-	fndgLog.Errorf("Received funding error from %x: %v",
-		peerKey.SerializeCompressed(), lnErr,
-	)
-	resCtx.err <- grpc.Errorf(lnErr.ToGrpcCode(), lnErr.String())
+	// Errors which don't carry a known code are passed on verbatim.
+	lnErr, ok := protocolErr.Code()
+	switch {
+	case !ok:
+		fndgLog.Errorf("Received funding error from %x: %v",
+			peerKey.SerializeCompressed(), protocolErr,
+		)
+		resCtx.err <- fmt.Errorf("remote error: %v",
+			string(protocolErr.Data))
+
+	// If the error is only temporary, then the funding flow may be
+	// retried later on, so we'll make sure the caller is aware of this.
+	case lnErr.IsTemporary():
+		fndgLog.Warnf("Received temporary funding error from %x: %v",
+			peerKey.SerializeCompressed(), protocolErr,
+		)
+		resCtx.err <- grpc.Errorf(lnErr.ToGrpcCode(),
+			"%v, funding may be retried", lnErr)
+
+	default:
+		fndgLog.Errorf("Received funding error from %x: %v",
+			peerKey.SerializeCompressed(), protocolErr,
+		)
+		resCtx.err <- grpc.Errorf(lnErr.ToGrpcCode(), lnErr.String())
+	}
 
 	if _, err := f.cancelReservationCtx(peerKey, chanID); err != nil {
 		fndgLog.Warnf("unable to delete reservation: %v", err)
@@ -3,6 +3,7 @@
 package main
 
 import (
+	"bytes"
 	"fmt"
 	"io/ioutil"
 	"net"
@@ -127,13 +128,14 @@ func createTestWallet(tempTestDir string, netParams *chaincfg.Params,
 	}
 
 	wallet, err := lnwallet.NewLightningWallet(lnwallet.Config{
-		Database:         cdb,
-		Notifier:         notifier,
-		WalletController: wc,
-		Signer:           signer,
-		ChainIO:          bio,
-		FeeEstimator:     estimator,
-		NetParams:        *netParams,
+		Database:           cdb,
+		Notifier:           notifier,
+		WalletController:   wc,
+		Signer:             signer,
+		ChainIO:            bio,
+		FeeEstimator:       estimator,
+		NetParams:          *netParams,
+		DefaultConstraints: defaultChannelConstraints,
 	})
 	if err != nil {
 		return nil, err
@@ -209,7 +211,7 @@ func createTestFundingManager(t *testing.T, pubKey *btcec.PublicKey,
 			return nil, nil
 		},
 		NumRequiredConfs: func(chanAmt btcutil.Amount,
-			pushAmt lnwire.MilliSatoshi) uint16 {
+			pushAmt lnwire.MilliAtom) uint16 {
 
 			return uint16(cfg.DefaultNumChanConfs)
 		},
@@ -1016,3 +1018,170 @@ func TestFundingManagerFundingTimeout(t *testing.T) {
 			len(pendingChannels))
 	}
 }
+
+// TestFundingManagerDustLimit tests that the dust limit specified when
+// initiating a funding workflow is proposed to the remote party, and that the
+// responder rejects dust limits which fall outside of sane bounds.
+func TestFundingManagerDustLimit(t *testing.T) {
+	disableFndgLogger(t)
+
+	shutdownChannel := make(chan struct{})
+
+	alice, bob := setupFundingManagers(t, shutdownChannel)
+	defer tearDownFundingManagers(t, alice, bob, shutdownChannel)
+
+	// Alice will initiate a funding workflow with a custom dust limit for
+	// her commitment transaction.
+	dustLimit := lnwallet.MinDustLimit() * 2
+	updateChan := make(chan *lnrpc.OpenStatusUpdate)
+	errChan := make(chan error, 1)
+	initReq := &openChanReq{
+		targetPeerID:    int32(1),
+		targetPubkey:    bob.privKey.PubKey(),
+		chainHash:       *activeNetParams.GenesisHash,
+		localFundingAmt: 500000,
+		dustLimit:       dustLimit,
+		updates:         updateChan,
+		err:             errChan,
+	}
+	alice.fundingMgr.initFundingWorkflow(bobAddr, initReq)
+
+	var aliceMsg lnwire.Message
+	select {
+	case aliceMsg = <-alice.msgChan:
+	case err := <-initReq.err:
+		t.Fatalf("error init funding workflow: %v", err)
+	case <-time.After(time.Second * 5):
+		t.Fatalf("alice did not send OpenChannel message")
+	}
+	openChannelReq, ok := aliceMsg.(*lnwire.OpenChannel)
+	if !ok {
+		t.Fatalf("expected OpenChannel to be sent from alice, "+
+			"instead got %T", aliceMsg)
+	}
+	if openChannelReq.DustLimit != dustLimit {
+		t.Fatalf("expected dust limit %v, got %v", dustLimit,
+			openChannelReq.DustLimit)
+	}
+
+	// We'll now modify the request so the proposed dust limit is above the
+	// maximum. Bob should reject the request with an error.
+	openChannelReq.DustLimit = lnwallet.MaxDustLimit() + 1
+	bob.fundingMgr.processFundingOpen(openChannelReq, aliceAddr)
+
+	var bobMsg lnwire.Message
+	select {
+	case bobMsg = <-bob.msgChan:
+	case <-time.After(time.Second * 5):
+		t.Fatalf("bob did not send Error message")
+	}
+	errorMsg, ok := bobMsg.(*lnwire.Error)
+	if !ok {
+		t.Fatalf("expected Error to be sent from bob, instead got %T",
+			bobMsg)
+	}
+	if lnwire.ErrorCode(errorMsg.Data[0]) != lnwire.ErrInvalidDustLimit {
+		t.Fatalf("expected ErrInvalidDustLimit, got %v",
+			lnwire.ErrorCode(errorMsg.Data[0]))
+	}
+}
+
+// TestFundingManagerUpfrontShutdown tests that the upfront shutdown script
+// specified when initiating a funding workflow is proposed to the remote
+// party, that the responder rejects scripts which aren't standard, and that
+// the responder sends its own upfront shutdown script if it commits to one.
+func TestFundingManagerUpfrontShutdown(t *testing.T) {
+	disableFndgLogger(t)
+
+	shutdownChannel := make(chan struct{})
+
+	alice, bob := setupFundingManagers(t, shutdownChannel)
+	defer tearDownFundingManagers(t, alice, bob, shutdownChannel)
+
+	// Alice will initiate a funding workflow, committing to a p2wkh
+	// upfront shutdown script.
+	script := append([]byte{0x00, 0x14}, bytes.Repeat([]byte{0x01}, 20)...)
+	updateChan := make(chan *lnrpc.OpenStatusUpdate)
+	errChan := make(chan error, 1)
+	initReq := &openChanReq{
+		targetPeerID:    int32(1),
+		targetPubkey:    bob.privKey.PubKey(),
+		chainHash:       *activeNetParams.GenesisHash,
+		localFundingAmt: 500000,
+		upfrontShutdown: script,
+		updates:         updateChan,
+		err:             errChan,
+	}
+	alice.fundingMgr.initFundingWorkflow(bobAddr, initReq)
+
+	var aliceMsg lnwire.Message
+	select {
+	case aliceMsg = <-alice.msgChan:
+	case err := <-initReq.err:
+		t.Fatalf("error init funding workflow: %v", err)
+	case <-time.After(time.Second * 5):
+		t.Fatalf("alice did not send OpenChannel message")
+	}
+	openChannelReq, ok := aliceMsg.(*lnwire.OpenChannel)
+	if !ok {
+		t.Fatalf("expected OpenChannel to be sent from alice, "+
+			"instead got %T", aliceMsg)
+	}
+	if !bytes.Equal(openChannelReq.UpfrontShutdownScript, script) {
+		t.Fatalf("expected upfront shutdown script %x, got %x",
+			script, openChannelReq.UpfrontShutdownScript)
+	}
+
+	// We'll now modify the request so the upfront shutdown script isn't
+	// a standard script. Bob should reject the request with an error.
+	openChannelReq.UpfrontShutdownScript = []byte{0x6a}
+	bob.fundingMgr.processFundingOpen(openChannelReq, aliceAddr)
+
+	var bobMsg lnwire.Message
+	select {
+	case bobMsg = <-bob.msgChan:
+	case <-time.After(time.Second * 5):
+		t.Fatalf("bob did not send Error message")
+	}
+	errorMsg, ok := bobMsg.(*lnwire.Error)
+	if !ok {
+		t.Fatalf("expected Error to be sent from bob, instead got %T",
+			bobMsg)
+	}
+	code := lnwire.ErrorCode(errorMsg.Data[0])
+	if code != lnwire.ErrInvalidUpfrontShutdown {
+		t.Fatalf("expected ErrInvalidUpfrontShutdown, got %v", code)
+	}
+
+	// Finally, we'll have Bob commit to an upfront shutdown script of his
+	// own. Once Alice's request carries a standard script, Bob should
+	// accept it, sending his script back within his AcceptChannel
+	// message.
+	bobScript := append(
+		[]byte{0x00, 0x14}, bytes.Repeat([]byte{0x02}, 20)...,
+	)
+	bob.fundingMgr.cfg.UpfrontShutdownScript = func() (
+		lnwire.DeliveryAddress, error) {
+
+		return bobScript, nil
+	}
+	openChannelReq.UpfrontShutdownScript = script
+	bob.fundingMgr.processFundingOpen(openChannelReq, aliceAddr)
+
+	select {
+	case bobMsg = <-bob.msgChan:
+	case <-time.After(time.Second * 5):
+		t.Fatalf("bob did not send AcceptChannel message")
+	}
+	acceptChannelResponse, ok := bobMsg.(*lnwire.AcceptChannel)
+	if !ok {
+		t.Fatalf("expected AcceptChannel to be sent from bob, "+
+			"instead got %T", bobMsg)
+	}
+	if !bytes.Equal(acceptChannelResponse.UpfrontShutdownScript,
+		bobScript) {
+
+		t.Fatalf("expected upfront shutdown script %x, got %x",
+			bobScript, acceptChannelResponse.UpfrontShutdownScript)
+	}
+}
@@ -0,0 +1,19 @@
+// +build gofuzz
+
+package htlcswitchfuzz
+
+import (
+	"bytes"
+
+	"github.com/lightningnetwork/lnd/htlcswitch"
+)
+
+// Fuzz_hop_payload feeds raw input into the TLV onion hop payload parser, as
+// the payload of each hop is fully controlled by the sender of the HTLC.
+func Fuzz_hop_payload(data []byte) int {
+	if _, err := htlcswitch.NewPayloadFromReader(bytes.NewReader(data)); err != nil {
+		return 0
+	}
+
+	return 1
+}
@@ -0,0 +1,33 @@
+// +build gofuzz
+
+package lnwirefuzz
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// Fuzz_failure feeds raw input into the onion failure message parser, as
+// decrypted failures are fully controlled by the node which failed the HTLC.
+// Any parsed failure must survive an encode/decode round trip.
+func Fuzz_failure(data []byte) int {
+	failure, err := lnwire.DecodeFailure(bytes.NewReader(data), 0)
+	if err != nil {
+		return 0
+	}
+
+	encoded := encodeFailure(failure)
+	reparsed, err := lnwire.DecodeFailure(bytes.NewReader(encoded), 0)
+	if err != nil {
+		panic(fmt.Sprintf("unable to parse encoded %v: %v",
+			failure.Code(), err))
+	}
+	if !bytes.Equal(encoded, encodeFailure(reparsed)) {
+		panic(fmt.Sprintf("encoding of %v isn't stable",
+			failure.Code()))
+	}
+
+	return 1
+}
@@ -0,0 +1,33 @@
+// +build gofuzz
+
+package lnwirefuzz
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// encodeMessage serializes msg, panicking if it can't be encoded, as every
+// message accepted by the parser must be able to be sent back out.
+func encodeMessage(msg lnwire.Message) []byte {
+	var b bytes.Buffer
+	if _, err := lnwire.WriteMessage(&b, msg, 0); err != nil {
+		panic(fmt.Sprintf("unable to encode parsed %v: %v",
+			msg.MsgType(), err))
+	}
+
+	return b.Bytes()
+}
+
+// encodeFailure serializes failure, panicking if it can't be encoded.
+func encodeFailure(failure lnwire.FailureMessage) []byte {
+	var b bytes.Buffer
+	if err := lnwire.EncodeFailure(&b, failure, 0); err != nil {
+		panic(fmt.Sprintf("unable to encode parsed %v: %v",
+			failure.Code(), err))
+	}
+
+	return b.Bytes()
+}
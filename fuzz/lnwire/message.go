@@ -0,0 +1,70 @@
+// +build gofuzz
+
+package lnwirefuzz
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// Fuzz_message feeds raw peer input into the wire message parser. Both the
+// io.Reader and the buffered decoding paths must agree on the parsed message,
+// and any parsed message must survive an encode/decode round trip.
+func Fuzz_message(data []byte) int {
+	// The peer never hands a message larger than the maximum payload of a
+	// single brontide packet to the parser.
+	if len(data) > lnwire.MaxMessagePayload {
+		return -1
+	}
+
+	msg, err := lnwire.ReadMessage(bytes.NewReader(data), 0)
+	if err != nil {
+		// The buffered decoding path must reject the same input.
+		if _, err := lnwire.ReadMessageBytes(data, 0); err == nil {
+			panic("buffered decoding accepted rejected message")
+		}
+		return 0
+	}
+
+	// Payloads exceeding the limit of their message type are refused by
+	// the encoder, so they can't be expected to round trip.
+	if uint32(len(data)-2) > msg.MaxPayloadLength(0) {
+		return 0
+	}
+
+	bufMsg, err := lnwire.ReadMessageBytes(data, 0)
+	if err != nil {
+		panic(fmt.Sprintf("buffered decoding rejected %v: %v",
+			msg.MsgType(), err))
+	}
+
+	// A legacy ChannelReestablish lacks the data loss protection fields we
+	// always send, so it can't be re-encoded by us.
+	reestablish, ok := msg.(*lnwire.ChannelReestablish)
+	if ok && !reestablish.HasDataLossProtection() {
+		return 0
+	}
+
+	// Messages are only required to be canonical once they've been
+	// encoded by us, so we'll compare the encodings of both decoded
+	// messages, and then ensure the encoding is stable.
+	encoded := encodeMessage(msg)
+	if !bytes.Equal(encoded, encodeMessage(bufMsg)) {
+		panic(fmt.Sprintf("decoding paths disagree on %v",
+			msg.MsgType()))
+	}
+
+	reparsed, err := lnwire.ReadMessageBytes(encoded, 0)
+	if err != nil {
+		panic(fmt.Sprintf("unable to parse encoded %v: %v",
+			msg.MsgType(), err))
+	}
+	if !bytes.Equal(encoded, encodeMessage(reparsed)) {
+		panic(fmt.Sprintf("encoding of %v isn't stable",
+			msg.MsgType()))
+	}
+
+	return 1
+}
@@ -0,0 +1,38 @@
+// +build gofuzz
+
+package tlvfuzz
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+// Fuzz_bigsize feeds raw input into the BigSize integer parser. As only
+// canonical encodings are accepted, re-encoding a parsed integer must yield
+// exactly the bytes it was parsed from.
+func Fuzz_bigsize(data []byte) int {
+	r := bytes.NewReader(data)
+	v, err := tlv.ReadBigSize(r)
+	if err != nil {
+		return 0
+	}
+
+	var b bytes.Buffer
+	if err := tlv.WriteBigSize(&b, v); err != nil {
+		panic(err)
+	}
+
+	read := data[:len(data)-r.Len()]
+	if !bytes.Equal(b.Bytes(), read) {
+		panic(fmt.Sprintf("bigsize %d encoded as %x, parsed from %x",
+			v, b.Bytes(), read))
+	}
+	if uint64(len(read)) != tlv.BigSizeLen(v) {
+		panic(fmt.Sprintf("bigsize %d has length %d, expected %d", v,
+			len(read), tlv.BigSizeLen(v)))
+	}
+
+	return 1
+}
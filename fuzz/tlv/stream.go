@@ -0,0 +1,54 @@
+// +build gofuzz
+
+package tlvfuzz
+
+import (
+	"bytes"
+
+	"github.com/lightningnetwork/lnd/tlv"
+	"github.com/roasbeef/btcd/btcec"
+)
+
+// Fuzz_stream feeds raw input into a TLV stream holding a record of each of
+// the primitive types, which exercises each of the primitive decoders along
+// with the stream's own parsing.
+func Fuzz_stream(data []byte) int {
+	var (
+		u8     uint8
+		u16    uint16
+		u32    uint32
+		u64    uint64
+		b32    [32]byte
+		b33    [33]byte
+		pubKey *btcec.PublicKey
+		varB   []byte
+		tu32   uint32
+		tu64   uint64
+	)
+
+	stream, err := tlv.NewStream(
+		tlv.MakePrimitiveRecord(1, &u8),
+		tlv.MakePrimitiveRecord(2, &u16),
+		tlv.MakePrimitiveRecord(3, &u32),
+		tlv.MakePrimitiveRecord(4, &u64),
+		tlv.MakePrimitiveRecord(5, &b32),
+		tlv.MakePrimitiveRecord(6, &b33),
+		tlv.MakePrimitiveRecord(7, &pubKey),
+		tlv.MakePrimitiveRecord(8, &varB),
+		tlv.MakeDynamicRecord(9, &tu32, func() uint64 {
+			return tlv.SizeTUint32(tu32)
+		}, tlv.ETUint32, tlv.DTUint32),
+		tlv.MakeDynamicRecord(10, &tu64, func() uint64 {
+			return tlv.SizeTUint64(tu64)
+		}, tlv.ETUint64, tlv.DTUint64),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	if _, err := stream.DecodeWithParsedTypes(bytes.NewReader(data)); err != nil {
+		return 0
+	}
+
+	return 1
+}
@@ -4,9 +4,11 @@ import (
 	"bytes"
 	"crypto/sha256"
 	"encoding/hex"
+	"io"
 	"sync"
 
 	"github.com/go-errors/errors"
+	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/lnwire"
 )
 
@@ -38,23 +40,55 @@ type paymentCircuit struct {
 	// request back.
 	Dest lnwire.ShortChannelID
 
+	// IncomingAmount is the value of the HTLC received over the source
+	// channel.
+	IncomingAmount lnwire.MilliAtom
+
+	// OutgoingAmount is the value of the HTLC forwarded over the
+	// destination channel. The difference between the two amounts is the
+	// fee earned by the forward.
+	OutgoingAmount lnwire.MilliAtom
+
 	// Obfuscator is used to re-encrypt the onion failure before sending it
 	// back to the originator of the payment.
 	Obfuscator Obfuscator
 
+	// OnionBlob is the onion packet of the incoming HTLC. It's persisted
+	// along with the circuit, allowing the obfuscator to be re-derived
+	// should we restart before the circuit is completed.
+	OnionBlob []byte
+
 	// RefCount is used to count the circuits with the same circuit key.
 	RefCount int
 }
 
 // newPaymentCircuit creates new payment circuit instance.
 func newPaymentCircuit(src, dest lnwire.ShortChannelID, key circuitKey,
-	obfuscator Obfuscator) *paymentCircuit {
+	incomingAmt, outgoingAmt lnwire.MilliAtom, obfuscator Obfuscator,
+	onionBlob []byte) *paymentCircuit {
 	return &paymentCircuit{
-		Src:         src,
-		Dest:        dest,
-		PaymentHash: key,
-		RefCount:    1,
-		Obfuscator:  obfuscator,
+		Src:            src,
+		Dest:           dest,
+		PaymentHash:    key,
+		IncomingAmount: incomingAmt,
+		OutgoingAmount: outgoingAmt,
+		RefCount:       1,
+		Obfuscator:     obfuscator,
+		OnionBlob:      onionBlob,
+	}
+}
+
+// toDB returns the persisted form of the circuit with the passed reference
+// count.
+func (a *paymentCircuit) toDB(refCount int) *channeldb.PaymentCircuit {
+	return &channeldb.PaymentCircuit{
+		PaymentHash:    a.PaymentHash,
+		IncomingChan:   a.Src,
+		OutgoingChan:   a.Dest,
+		IncomingAmount: a.IncomingAmount,
+		OutgoingAmount: a.OutgoingAmount,
+		RefCount:       uint32(refCount),
+		OnionBlob:      a.OnionBlob,
 	}
 }
 
@@ -69,21 +103,191 @@ func (a *paymentCircuit) isEqual(b *paymentCircuit) bool {
 // circuits. Each circuit key (payment hash) may have several of circuits
 // corresponding to it due to the possibility of repeated payment hashes.
 //
-// TODO(andrew.shvv) make it persistent
+// If a store is provided, every circuit opened or closed is written to it
+// before the in-memory map is modified, so that the settles and fails of HTLCs
+// forwarded before a restart can still be matched with their circuit. The
+// circuits opened and closed while handling the updates locked in by a single
+// commitment update are written within a single batch, rather than one
+// database transaction per HTLC, so that persistence doesn't bound forwarding
+// throughput.
 type circuitMap struct {
 	sync.RWMutex
 	circuits map[circuitKey]*paymentCircuit
+
+	// store, if non-nil, is the store the circuits are persisted within.
+	store CircuitStore
+}
+
+// circuitBatch is a set of changes made to the circuits which are to be
+// written to the store within a single transaction. The in-memory circuits
+// are changed right away.
+type circuitBatch struct {
+	// writes maps the key of each circuit changed within the batch to the
+	// form it's to be persisted in, or nil if it's to be deleted.
+	writes map[circuitKey]*channeldb.PaymentCircuit
+
+	// prior holds each circuit changed within the batch as it was before
+	// the batch, allowing the changes to be undone should the batch fail
+	// to be written.
+	prior map[circuitKey]priorCircuit
 }
 
-// newCircuitMap creates a new instance of the circuitMap.
-func newCircuitMap() *circuitMap {
+// priorCircuit is the state of a circuit before it was changed within a
+// batch. A nil circuit indicates that it didn't exist yet.
+type priorCircuit struct {
+	circuit  *paymentCircuit
+	refCount int
+}
+
+// newCircuitBatch creates a new empty batch of changes to the circuits.
+func newCircuitBatch() *circuitBatch {
+	return &circuitBatch{
+		writes: make(map[circuitKey]*channeldb.PaymentCircuit),
+		prior:  make(map[circuitKey]priorCircuit),
+	}
+}
+
+// newCircuitMap creates a new instance of the circuitMap, backed by the
+// passed store if it's non-nil.
+func newCircuitMap(store CircuitStore) *circuitMap {
 	return &circuitMap{
 		circuits: make(map[circuitKey]*paymentCircuit),
+		store:    store,
 	}
 }
 
-// add adds a new active payment circuit to the circuitMap.
-func (m *circuitMap) add(circuit *paymentCircuit) error {
+// restore loads the circuits persisted within the store into the map. As the
+// obfuscators of the circuits aren't persisted, they're re-derived from the
+// onion blob of each circuit's incoming HTLC using the passed function.
+func (m *circuitMap) restore(decodeObfuscator func(io.Reader) (Obfuscator,
+	lnwire.FailCode)) error {
+
+	if m.store == nil {
+		return nil
+	}
+
+	circuits, err := m.store.FetchCircuits()
+	if err != nil {
+		return err
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	for _, c := range circuits {
+		circuit := &paymentCircuit{
+			PaymentHash:    c.PaymentHash,
+			Src:            c.IncomingChan,
+			Dest:           c.OutgoingChan,
+			IncomingAmount: c.IncomingAmount,
+			OutgoingAmount: c.OutgoingAmount,
+			OnionBlob:      c.OnionBlob,
+			RefCount:       int(c.RefCount),
+		}
+
+		// Should we be unable to re-derive the obfuscator, then the
+		// HTLC can still be failed back, though its failure will be
+		// unreadable by the sender.
+		if decodeObfuscator != nil {
+			obfuscator, failCode := decodeObfuscator(
+				bytes.NewReader(c.OnionBlob),
+			)
+			if failCode == lnwire.CodeNone {
+				circuit.Obfuscator = obfuscator
+			} else {
+				log.Warnf("Unable to derive obfuscator of "+
+					"circuit %x: %v", c.PaymentHash[:],
+					failCode)
+			}
+		}
+
+		m.circuits[circuit.PaymentHash] = circuit
+	}
+
+	return nil
+}
+
+// write persists the passed circuit under the passed key, or deletes the
+// circuit of the key if the circuit is nil, if the map is backed by a store.
+// If a batch is passed, then the change is instead collected within it, to be
+// written once the batch is committed.
+//
+// NOTE: This method must be called with the mutex held, and before the
+// in-memory circuit is changed.
+func (m *circuitMap) write(key circuitKey, circuit *channeldb.PaymentCircuit,
+	batch *circuitBatch) error {
+
+	if m.store == nil {
+		return nil
+	}
+
+	if batch != nil {
+		if _, ok := batch.prior[key]; !ok {
+			prior := priorCircuit{}
+			if c, ok := m.circuits[key]; ok {
+				prior.circuit = c
+				prior.refCount = c.RefCount
+			}
+			batch.prior[key] = prior
+		}
+		batch.writes[key] = circuit
+
+		return nil
+	}
+
+	if circuit == nil {
+		return m.store.UpdateCircuits(nil, [][32]byte{key})
+	}
+
+	return m.store.UpdateCircuits(
+		[]*channeldb.PaymentCircuit{circuit}, nil,
+	)
+}
+
+// commitBatch writes the changes collected within the passed batch to the
+// store within a single transaction. Should the write fail, then the changes
+// the batch made to the in-memory circuits are undone.
+func (m *circuitMap) commitBatch(batch *circuitBatch) error {
+	m.Lock()
+	defer m.Unlock()
+
+	if len(batch.writes) == 0 {
+		return nil
+	}
+
+	var (
+		circuits []*channeldb.PaymentCircuit
+		deleted  [][32]byte
+	)
+	for key, circuit := range batch.writes {
+		if circuit == nil {
+			deleted = append(deleted, [32]byte(key))
+			continue
+		}
+		circuits = append(circuits, circuit)
+	}
+
+	err := m.store.UpdateCircuits(circuits, deleted)
+	if err == nil {
+		return nil
+	}
+
+	for key, prior := range batch.prior {
+		if prior.circuit == nil {
+			delete(m.circuits, key)
+			continue
+		}
+
+		prior.circuit.RefCount = prior.refCount
+		m.circuits[key] = prior.circuit
+	}
+
+	return err
+}
+
+// add adds a new active payment circuit to the circuitMap. If a batch is
+// passed, then the circuit is only persisted once the batch is committed.
+func (m *circuitMap) add(circuit *paymentCircuit, batch *circuitBatch) error {
 	m.Lock()
 	defer m.Unlock()
 
@@ -93,21 +297,54 @@ func (m *circuitMap) add(circuit *paymentCircuit) error {
 	//
 	// TODO(roasbeef): include dest+src+amt in key
 	if c, ok := m.circuits[circuit.PaymentHash]; ok {
+		err := m.write(c.PaymentHash, c.toDB(c.RefCount+1), batch)
+		if err != nil {
+			return err
+		}
+
 		c.RefCount++
 		return nil
 	}
 
+	err := m.write(
+		circuit.PaymentHash, circuit.toDB(circuit.RefCount), batch,
+	)
+	if err != nil {
+		return err
+	}
+
 	m.circuits[circuit.PaymentHash] = circuit
 
 	return nil
 }
 
-// remove destroys the target circuit by removing it from the circuit map.
-func (m *circuitMap) remove(key circuitKey) (*paymentCircuit, error) {
+// lookup returns the target circuit without removing it from the circuit map.
+func (m *circuitMap) lookup(key circuitKey) (*paymentCircuit, bool) {
+	m.RLock()
+	defer m.RUnlock()
+
+	circuit, ok := m.circuits[key]
+	return circuit, ok
+}
+
+// remove destroys the target circuit by removing it from the circuit map. If a
+// batch is passed, then the removal is only persisted once the batch is
+// committed.
+func (m *circuitMap) remove(key circuitKey,
+	batch *circuitBatch) (*paymentCircuit, error) {
+
 	m.Lock()
 	defer m.Unlock()
 
 	if circuit, ok := m.circuits[key]; ok {
+		var persisted *channeldb.PaymentCircuit
+		if circuit.RefCount > 1 {
+			persisted = circuit.toDB(circuit.RefCount - 1)
+		}
+		if err := m.write(key, persisted, batch); err != nil {
+			return nil, err
+		}
+
 		if circuit.RefCount--; circuit.RefCount == 0 {
 			delete(m.circuits, key)
 		}
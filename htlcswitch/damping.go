@@ -0,0 +1,269 @@
+package htlcswitch
+
+import (
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// FlapDamping configures the automatic disabling of channels whose links
+// repeatedly go down, or repeatedly fail the HTLCs forwarded over them.
+// Disabled channels are advertised to the network as such, so that it stops
+// routing through them, and are re-enabled once they've been stable for a
+// while.
+type FlapDamping struct {
+	// MaxFlaps is the maximum number of times the link of a channel may
+	// be removed from the switch within each interval before the channel
+	// is disabled. A value of zero disables the limit.
+	MaxFlaps int
+
+	// MaxFailures is the maximum number of HTLCs the link of a channel
+	// may fail to add to the channel within each interval before the
+	// channel is disabled. A value of zero disables the limit.
+	MaxFailures int
+
+	// Interval is the duration of the sliding window flaps and failures
+	// are counted over.
+	Interval time.Duration
+
+	// StableInterval is the duration a disabled channel's link must be
+	// active, without any flaps or failures, before the channel is
+	// re-enabled.
+	StableInterval time.Duration
+}
+
+// enabled returns true if channels should be damped.
+func (d FlapDamping) enabled() bool {
+	return d.Interval > 0 && (d.MaxFlaps > 0 || d.MaxFailures > 0)
+}
+
+// linkHealth records the recent flaps and failures of a single channel's
+// link.
+type linkHealth struct {
+	flaps    []time.Time
+	failures []time.Time
+
+	// lastEvent is the time of the channel's last flap or failure.
+	lastEvent time.Time
+
+	// disabled is true if the channel has been disabled by the damper.
+	disabled bool
+}
+
+// prune removes the flaps and failures which have fallen out of the window
+// ending at the passed time.
+func (h *linkHealth) prune(now time.Time, interval time.Duration) {
+	cutoff := now.Add(-interval)
+	prune := func(events []time.Time) []time.Time {
+		var i int
+		for i < len(events) && !events[i].After(cutoff) {
+			i++
+		}
+		return events[i:]
+	}
+
+	h.flaps = prune(h.flaps)
+	h.failures = prune(h.failures)
+}
+
+// flapDamper tracks the flaps and failures of the links of each channel,
+// deciding when channels should be disabled, and when they're stable enough
+// to be re-enabled.
+//
+// NOTE: Failures are recorded from within the links' goroutines, so the
+// damper is safe for concurrent use.
+type flapDamper struct {
+	cfg FlapDamping
+
+	mtx   sync.Mutex
+	chans map[lnwire.ShortChannelID]*linkHealth
+
+	// now returns the current time, and is overridden within tests.
+	now func() time.Time
+}
+
+// newFlapDamper creates a new flapDamper which damps channels according to
+// the passed config.
+func newFlapDamper(cfg FlapDamping) *flapDamper {
+	return &flapDamper{
+		cfg:   cfg,
+		chans: make(map[lnwire.ShortChannelID]*linkHealth),
+		now:   time.Now,
+	}
+}
+
+// recordFlap records that the link of the target channel has gone down. True
+// is returned if the channel should now be disabled.
+func (d *flapDamper) recordFlap(chanID lnwire.ShortChannelID) bool {
+	return d.record(chanID, true)
+}
+
+// recordFailure records that the link of the target channel has failed to
+// add an HTLC to the channel. True is returned if the channel should now be
+// disabled.
+func (d *flapDamper) recordFailure(chanID lnwire.ShortChannelID) bool {
+	return d.record(chanID, false)
+}
+
+// record records a flap or failure of the target channel's link, returning
+// true if the channel has just exceeded one of the limits and should be
+// disabled.
+func (d *flapDamper) record(chanID lnwire.ShortChannelID, flap bool) bool {
+	if !d.cfg.enabled() {
+		return false
+	}
+
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	health, ok := d.chans[chanID]
+	if !ok {
+		health = &linkHealth{}
+		d.chans[chanID] = health
+	}
+
+	now := d.now()
+	health.prune(now, d.cfg.Interval)
+	health.lastEvent = now
+	if flap {
+		health.flaps = append(health.flaps, now)
+	} else {
+		health.failures = append(health.failures, now)
+	}
+
+	if health.disabled {
+		return false
+	}
+
+	exceeded := func(events []time.Time, max int) bool {
+		return max > 0 && len(events) > max
+	}
+	if !exceeded(health.flaps, d.cfg.MaxFlaps) &&
+		!exceeded(health.failures, d.cfg.MaxFailures) {
+
+		return false
+	}
+
+	health.disabled = true
+	return true
+}
+
+// isDisabled returns true if the target channel has been disabled by the
+// damper.
+func (d *flapDamper) isDisabled(chanID lnwire.ShortChannelID) bool {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	health, ok := d.chans[chanID]
+	return ok && health.disabled
+}
+
+// stableChannels returns the disabled channels which have been free of flaps
+// and failures for the stable interval, and whose links are active according
+// to the passed callback. They're marked as enabled once returned. The
+// records of enabled channels which have no recent flaps or failures are
+// discarded, so that those of idle channels don't accumulate.
+func (d *flapDamper) stableChannels(
+	isActive func(lnwire.ShortChannelID) bool) []lnwire.ShortChannelID {
+
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	now := d.now()
+
+	var stable []lnwire.ShortChannelID
+	for chanID, health := range d.chans {
+		health.prune(now, d.cfg.Interval)
+
+		if !health.disabled {
+			if len(health.flaps) == 0 && len(health.failures) == 0 {
+				delete(d.chans, chanID)
+			}
+			continue
+		}
+
+		if now.Sub(health.lastEvent) < d.cfg.StableInterval ||
+			!isActive(chanID) {
+
+			continue
+		}
+
+		health.disabled = false
+		stable = append(stable, chanID)
+	}
+
+	return stable
+}
+
+// dampingCheckInterval is the interval at which the switch checks whether
+// any disabled channels have become stable.
+const dampingCheckInterval = time.Minute
+
+// recordLinkFlap records that the link of the target channel is going down,
+// disabling the channel if it's been flapping.
+//
+// NOTE: This MUST be called from the htlcForwarder goroutine.
+func (s *Switch) recordLinkFlap(chanID lnwire.ChannelID) {
+	link, ok := s.linkIndex[chanID]
+	if !ok {
+		return
+	}
+
+	shortChanID := link.ShortChanID()
+	if shortChanID == (lnwire.ShortChannelID{}) {
+		return
+	}
+
+	if s.damper.recordFlap(shortChanID) {
+		log.Warnf("Link of short_chan_id=%v is flapping, disabling "+
+			"channel", shortChanID)
+		s.updateChanStatus(shortChanID, true)
+	}
+}
+
+// recordLinkFailure records that the link of the target channel has failed
+// to add an HTLC to the channel, disabling the channel if it's been failing
+// persistently.
+func (s *Switch) recordLinkFailure(chanID lnwire.ShortChannelID) {
+	if chanID == (lnwire.ShortChannelID{}) {
+		return
+	}
+
+	if s.damper.recordFailure(chanID) {
+		log.Warnf("Link of short_chan_id=%v is persistently failing "+
+			"htlcs, disabling channel", chanID)
+		s.updateChanStatus(chanID, true)
+	}
+}
+
+// enableStableChannels re-enables the channels disabled by the damper whose
+// links have since become stable.
+//
+// NOTE: This MUST be called from the htlcForwarder goroutine.
+func (s *Switch) enableStableChannels() {
+	isActive := func(chanID lnwire.ShortChannelID) bool {
+		_, ok := s.forwardingIndex[chanID]
+		return ok
+	}
+
+	for _, chanID := range s.damper.stableChannels(isActive) {
+		log.Infof("Link of short_chan_id=%v is stable, re-enabling "+
+			"channel", chanID)
+		s.updateChanStatus(chanID, false)
+	}
+}
+
+// updateChanStatus advertises the target channel as disabled or enabled
+// within its own goroutine, as advertising it may block on the gossiper.
+func (s *Switch) updateChanStatus(chanID lnwire.ShortChannelID,
+	disabled bool) {
+
+	go func() {
+		err := s.cfg.UpdateChanStatus(chanID, disabled)
+		if err != nil {
+			log.Errorf("unable to update status of "+
+				"short_chan_id=%v: %v", chanID, err)
+		}
+	}()
+}
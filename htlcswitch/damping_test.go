@@ -0,0 +1,172 @@
+package htlcswitch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// TestFlapDamper checks that the damper disables channels once their flaps or
+// failures exceed the configured limits, and only re-enables them once their
+// links have been stable for the stable interval.
+func TestFlapDamper(t *testing.T) {
+	t.Parallel()
+
+	damper := newFlapDamper(FlapDamping{
+		MaxFlaps:       2,
+		MaxFailures:    3,
+		Interval:       time.Hour,
+		StableInterval: 10 * time.Minute,
+	})
+
+	now := time.Unix(1000000, 0)
+	damper.now = func() time.Time {
+		return now
+	}
+
+	chanID1 := lnwire.NewShortChanIDFromInt(1)
+	chanID2 := lnwire.NewShortChanIDFromInt(2)
+
+	// The first two flaps are within the limit, while the third should
+	// disable the channel. Any further flaps shouldn't disable it again.
+	for i := 0; i < 2; i++ {
+		if damper.recordFlap(chanID1) {
+			t.Fatalf("channel disabled after %v flaps", i+1)
+		}
+	}
+	if !damper.recordFlap(chanID1) {
+		t.Fatalf("channel not disabled after exceeding flap limit")
+	}
+	if damper.recordFlap(chanID1) {
+		t.Fatalf("disabled channel disabled again")
+	}
+	if !damper.isDisabled(chanID1) {
+		t.Fatalf("channel should be disabled")
+	}
+
+	// Failures spread out beyond the interval shouldn't accumulate.
+	for i := 0; i < 5; i++ {
+		if damper.recordFailure(chanID2) {
+			t.Fatalf("channel disabled after spread out failures")
+		}
+		now = now.Add(30 * time.Minute)
+	}
+
+	// A disabled channel shouldn't be re-enabled while its link is
+	// inactive, nor before the stable interval has passed since its last
+	// flap.
+	damper.recordFlap(chanID1)
+	active := false
+	isActive := func(lnwire.ShortChannelID) bool {
+		return active
+	}
+	now = now.Add(time.Minute)
+	if stable := damper.stableChannels(isActive); len(stable) != 0 {
+		t.Fatalf("expected no stable channels, got %v", stable)
+	}
+	active = true
+	if stable := damper.stableChannels(isActive); len(stable) != 0 {
+		t.Fatalf("expected no stable channels, got %v", stable)
+	}
+
+	now = now.Add(10 * time.Minute)
+	stable := damper.stableChannels(isActive)
+	if len(stable) != 1 || stable[0] != chanID1 {
+		t.Fatalf("expected %v to be stable, got %v", chanID1, stable)
+	}
+	if damper.isDisabled(chanID1) {
+		t.Fatalf("channel should be re-enabled")
+	}
+
+	// Once all their flaps and failures have fallen out of the window,
+	// the records of enabled channels should be discarded.
+	now = now.Add(2 * time.Hour)
+	damper.stableChannels(isActive)
+	if len(damper.chans) != 0 {
+		t.Fatalf("expected no tracked channels, got %v",
+			len(damper.chans))
+	}
+}
+
+// TestSwitchFlapDamping checks that the switch disables a channel whose link
+// is repeatedly removed, and that it doesn't re-enable it while it's damped.
+func TestSwitchFlapDamping(t *testing.T) {
+	t.Parallel()
+
+	alicePeer := newMockServer(t, "alice")
+	aliceChannelLink := newMockChannelLink(chanID1, aliceChanID, alicePeer)
+
+	type statusUpdate struct {
+		chanID   lnwire.ShortChannelID
+		disabled bool
+	}
+	statusUpdates := make(chan statusUpdate, 10)
+
+	s := New(Config{
+		UpdateTopology: func(msg *lnwire.ChannelUpdate) error {
+			return nil
+		},
+		PreimageCache: newMockPreimageCache(),
+		FlapDamping: FlapDamping{
+			MaxFlaps:       1,
+			Interval:       time.Hour,
+			StableInterval: time.Hour,
+		},
+		UpdateChanStatus: func(chanID lnwire.ShortChannelID,
+			disabled bool) error {
+
+			statusUpdates <- statusUpdate{chanID, disabled}
+			return nil
+		},
+	})
+	s.Start()
+	defer s.Stop()
+
+	assertStatusUpdate := func(disabled bool) {
+		select {
+		case update := <-statusUpdates:
+			if update.chanID != aliceChanID {
+				t.Fatalf("expected update for %v, got %v",
+					aliceChanID, update.chanID)
+			}
+			if update.disabled != disabled {
+				t.Fatalf("expected disabled=%v, got %v",
+					disabled, update.disabled)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("no status update received")
+		}
+	}
+
+	// Each time the link is added while the channel isn't damped, the
+	// channel should be enabled.
+	if err := s.AddLink(aliceChannelLink); err != nil {
+		t.Fatalf("unable to add alice link: %v", err)
+	}
+	assertStatusUpdate(false)
+
+	if err := s.RemoveLink(chanID1); err != nil {
+		t.Fatalf("unable to remove alice link: %v", err)
+	}
+	if err := s.AddLink(aliceChannelLink); err != nil {
+		t.Fatalf("unable to add alice link: %v", err)
+	}
+	assertStatusUpdate(false)
+
+	// The second removal exceeds the flap limit, so the channel should be
+	// disabled, and not re-enabled once the link is added again.
+	if err := s.RemoveLink(chanID1); err != nil {
+		t.Fatalf("unable to remove alice link: %v", err)
+	}
+	assertStatusUpdate(true)
+
+	if err := s.AddLink(aliceChannelLink); err != nil {
+		t.Fatalf("unable to add alice link: %v", err)
+	}
+	select {
+	case update := <-statusUpdates:
+		t.Fatalf("unexpected status update: %v", update)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
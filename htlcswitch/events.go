@@ -0,0 +1,109 @@
+package htlcswitch
+
+import (
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// HtlcForwardEvent is sent over the switch's event bus each time the switch
+// forwards an HTLC to its outgoing link.
+type HtlcForwardEvent struct {
+	// PaymentHash is the payment hash of the HTLC.
+	PaymentHash [32]byte
+
+	// IncomingChanID is the channel the HTLC was received over.
+	IncomingChanID lnwire.ShortChannelID
+
+	// OutgoingChanID is the channel the HTLC was forwarded over.
+	OutgoingChanID lnwire.ShortChannelID
+
+	// IncomingAmount is the value of the incoming HTLC.
+	IncomingAmount lnwire.MilliAtom
+
+	// OutgoingAmount is the value of the forwarded HTLC.
+	OutgoingAmount lnwire.MilliAtom
+
+	// Timestamp is the time at which the HTLC was forwarded.
+	Timestamp time.Time
+}
+
+// HtlcForwardFailEvent is sent over the switch's event bus each time the
+// switch is unable to forward an HTLC, failing it back to the incoming
+// channel instead.
+type HtlcForwardFailEvent struct {
+	// PaymentHash is the payment hash of the HTLC.
+	PaymentHash [32]byte
+
+	// IncomingChanID is the channel the HTLC was received over.
+	IncomingChanID lnwire.ShortChannelID
+
+	// OutgoingChanID is the channel the HTLC was to be forwarded over.
+	OutgoingChanID lnwire.ShortChannelID
+
+	// Reason describes why the HTLC couldn't be forwarded.
+	Reason string
+
+	// Timestamp is the time at which the HTLC was failed.
+	Timestamp time.Time
+}
+
+// HtlcLinkFailEvent is sent over the switch's event bus each time a link
+// fails an HTLC, either an incoming HTLC which it refuses to forward or
+// settle, or an HTLC handed to it by the switch which it's unable to add to
+// its channel.
+type HtlcLinkFailEvent struct {
+	// PaymentHash is the payment hash of the HTLC.
+	PaymentHash [32]byte
+
+	// ChanID is the channel of the link which failed the HTLC.
+	ChanID lnwire.ShortChannelID
+
+	// Incoming is true if the failed HTLC was received over the channel,
+	// and false if it was to be sent over it.
+	Incoming bool
+
+	// FailureCode is the code of the failure sent back to the source of
+	// the HTLC.
+	FailureCode lnwire.FailCode
+
+	// FailureDetail describes why the HTLC was failed, if known beyond
+	// the failure code.
+	FailureDetail string
+
+	// Timestamp is the time at which the HTLC was failed.
+	Timestamp time.Time
+}
+
+// HtlcResolutionEvent is sent over the switch's event bus each time an HTLC
+// forwarded by the switch is resolved, once the settle or fail has been
+// propagated back to the incoming channel.
+type HtlcResolutionEvent struct {
+	// PaymentHash is the payment hash of the HTLC.
+	PaymentHash [32]byte
+
+	// IncomingChanID is the channel the HTLC was received over.
+	IncomingChanID lnwire.ShortChannelID
+
+	// OutgoingChanID is the channel the HTLC was forwarded over.
+	OutgoingChanID lnwire.ShortChannelID
+
+	// Settled is true if the HTLC was settled, and false if it was
+	// failed.
+	Settled bool
+
+	// Timestamp is the time at which the HTLC was resolved.
+	Timestamp time.Time
+}
+
+// notifyEvent sends the passed event over the switch's event bus, if one has
+// been configured.
+func (s *Switch) notifyEvent(event interface{}) {
+	if s.cfg.EventBus == nil {
+		return
+	}
+
+	if err := s.cfg.EventBus.SendUpdate(event); err != nil {
+		log.Debugf("unable to send event: %v", err)
+	}
+}
@@ -5,15 +5,51 @@ import (
 
 	"github.com/lightningnetwork/lightning-onion"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/btcec"
 )
 
+// ForwardingError is returned by the switch when a locally initiated payment
+// fails, either before it leaves our node or at some hop along its route. It
+// carries the onion failure code describing the failure, allowing the caller
+// to determine why the payment failed.
+type ForwardingError struct {
+	// FailureCode is the onion failure code describing the failure.
+	FailureCode lnwire.FailCode
+
+	// LocalFailure is true if the payment failed within our node, before
+	// it was forwarded to the first hop of its route.
+	LocalFailure bool
+
+	// ErrorSource is the public key of the node along the route that
+	// generated the failure. It's nil for local failures, or if the source
+	// of the failure couldn't be determined.
+	ErrorSource *btcec.PublicKey
+
+	// FailureMessage is the decrypted failure message sent by the node
+	// that generated the failure. It's nil for local failures.
+	FailureMessage lnwire.FailureMessage
+}
+
+// Error returns the string representation of the failure code.
+//
+// NOTE: Part of the error interface.
+func (f *ForwardingError) Error() string {
+	return f.FailureCode.String()
+}
+
+// A compile time check to ensure ForwardingError implements the error
+// interface.
+var _ error = (*ForwardingError)(nil)
+
 // Deobfuscator is an interface that is used to decrypt the onion encrypted
 // failure reason an extra out a well formed error.
 type Deobfuscator interface {
 	// Deobfuscate peels off each layer of onion encryption from the first
 	// hop, to the source of the error. A fully populated
-	// lnwire.FailureMessage is returned.
-	Deobfuscate(lnwire.OpaqueReason) (lnwire.FailureMessage, error)
+	// lnwire.FailureMessage is returned, along with the public key of the
+	// node that generated it.
+	Deobfuscate(lnwire.OpaqueReason) (*btcec.PublicKey,
+		lnwire.FailureMessage, error)
 }
 
 // Obfuscator is an interface that is used to encrypt HTLC related errors at
@@ -80,18 +116,24 @@ type FailureDeobfuscator struct {
 
 // Deobfuscate peels off each layer of onion encryption from the first hop, to
 // the source of the error. A fully populated lnwire.FailureMessage is
-// returned.
+// returned, along with the public key of the node that generated it.
 //
 // NOTE: Part of the Obfuscator interface.
-func (o *FailureDeobfuscator) Deobfuscate(reason lnwire.OpaqueReason) (lnwire.FailureMessage, error) {
+func (o *FailureDeobfuscator) Deobfuscate(reason lnwire.OpaqueReason) (
+	*btcec.PublicKey, lnwire.FailureMessage, error) {
 
-	_, failureData, err := o.OnionDeobfuscator.Deobfuscate(reason)
+	source, failureData, err := o.OnionDeobfuscator.Deobfuscate(reason)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	r := bytes.NewReader(failureData)
-	return lnwire.DecodeFailure(r, 0)
+	failure, err := lnwire.DecodeFailure(r, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return source, failure, nil
 }
 
 // A compile time check to ensure FailureDeobfuscator implements the
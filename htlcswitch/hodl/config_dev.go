@@ -0,0 +1,47 @@
+// +build dev
+
+package hodl
+
+// Config is a struct enumerating the flags which may be set from the command
+// line. They're only available within development builds.
+type Config struct {
+	ExitSettle     bool `long:"exit-settle" description:"Instructs the node to hold the HTLCs paying to its invoices, rather than settling them"`
+	AddIncoming    bool `long:"add-incoming" description:"Instructs the node to hold the HTLCs it receives, rather than forwarding them to the switch"`
+	SettleIncoming bool `long:"settle-incoming" description:"Instructs the node to hold the settles it receives, rather than forwarding them to the switch"`
+	FailIncoming   bool `long:"fail-incoming" description:"Instructs the node to hold the fails it receives, rather than forwarding them to the switch"`
+	AddOutgoing    bool `long:"add-outgoing" description:"Instructs the node to hold the HTLCs it forwards, rather than sending them to its peers"`
+	SettleOutgoing bool `long:"settle-outgoing" description:"Instructs the node to hold the settles it forwards, rather than sending them to its peers"`
+	FailOutgoing   bool `long:"fail-outgoing" description:"Instructs the node to hold the fails it forwards, rather than sending them to its peers"`
+	Commit         bool `long:"commit" description:"Instructs the node not to sign new commitments"`
+}
+
+// Mask returns the mask in which the flags set within the config are active.
+func (c *Config) Mask() Mask {
+	var flags []Flag
+	if c.ExitSettle {
+		flags = append(flags, ExitSettle)
+	}
+	if c.AddIncoming {
+		flags = append(flags, AddIncoming)
+	}
+	if c.SettleIncoming {
+		flags = append(flags, SettleIncoming)
+	}
+	if c.FailIncoming {
+		flags = append(flags, FailIncoming)
+	}
+	if c.AddOutgoing {
+		flags = append(flags, AddOutgoing)
+	}
+	if c.SettleOutgoing {
+		flags = append(flags, SettleOutgoing)
+	}
+	if c.FailOutgoing {
+		flags = append(flags, FailOutgoing)
+	}
+	if c.Commit {
+		flags = append(flags, Commit)
+	}
+
+	return MaskFromFlags(flags...)
+}
@@ -0,0 +1,12 @@
+// +build !dev
+
+package hodl
+
+// Config is an empty struct, as the hodl flags can't be set within production
+// builds.
+type Config struct{}
+
+// Mask returns MaskNone, as no flags can be active within production builds.
+func (c *Config) Mask() Mask {
+	return MaskNone
+}
@@ -0,0 +1,143 @@
+// Package hodl defines the flags which instruct a channel link to hold HTLCs
+// at specific points of its processing pipeline, rather than carrying on with
+// their processing. Holding HTLCs allows tests and developers to
+// deterministically reproduce scenarios which would otherwise depend on
+// timing, such as HTLCs which are never resolved off-chain and must be
+// resolved on-chain instead.
+package hodl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Flag is a single point of the link's processing pipeline at which HTLCs can
+// be held.
+type Flag uint32
+
+const (
+	// ExitSettle holds the HTLCs paying to one of our invoices, rather
+	// than settling them.
+	ExitSettle Flag = 1 << iota
+
+	// AddIncoming holds the locked in HTLCs received from our peer which
+	// are to be forwarded, rather than handing them to the switch.
+	AddIncoming
+
+	// SettleIncoming holds the settles received from our peer for the
+	// HTLCs we forwarded, rather than handing them to the switch.
+	SettleIncoming
+
+	// FailIncoming holds the locked in fails received from our peer for
+	// the HTLCs we forwarded, rather than handing them to the switch.
+	FailIncoming
+
+	// AddOutgoing holds the HTLCs handed to the link by the switch,
+	// rather than adding them to the channel and sending them to our
+	// peer.
+	AddOutgoing
+
+	// SettleOutgoing holds the settles handed to the link by the switch,
+	// rather than settling the incoming HTLCs with our peer.
+	SettleOutgoing
+
+	// FailOutgoing holds the fails handed to the link by the switch,
+	// rather than failing the incoming HTLCs with our peer.
+	FailOutgoing
+
+	// Commit prevents the link from signing new commitments, leaving any
+	// pending updates uncommitted.
+	Commit
+)
+
+// String returns a human readable identifier of the flag.
+func (f Flag) String() string {
+	switch f {
+	case ExitSettle:
+		return "ExitSettle"
+	case AddIncoming:
+		return "AddIncoming"
+	case SettleIncoming:
+		return "SettleIncoming"
+	case FailIncoming:
+		return "FailIncoming"
+	case AddOutgoing:
+		return "AddOutgoing"
+	case SettleOutgoing:
+		return "SettleOutgoing"
+	case FailOutgoing:
+		return "FailOutgoing"
+	case Commit:
+		return "Commit"
+	default:
+		return fmt.Sprintf("UnknownHodlFlag(%d)", uint32(f))
+	}
+}
+
+// Warning returns the message logged whenever the link holds an HTLC, or
+// skips a commitment, due to the flag being active.
+func (f Flag) Warning() string {
+	var msg string
+	switch f {
+	case ExitSettle:
+		msg = "will not attempt to settle ADD with sender"
+	case AddIncoming:
+		msg = "will not attempt to forward ADD to switch"
+	case SettleIncoming:
+		msg = "will not attempt to forward SETTLE to switch"
+	case FailIncoming:
+		msg = "will not attempt to forward FAIL to switch"
+	case AddOutgoing:
+		msg = "will not attempt to send ADD to peer"
+	case SettleOutgoing:
+		msg = "will not attempt to send SETTLE to peer"
+	case FailOutgoing:
+		msg = "will not attempt to send FAIL to peer"
+	case Commit:
+		msg = "will not attempt to sign new commitment"
+	default:
+		msg = "incorrect hodl flag usage"
+	}
+
+	return fmt.Sprintf("%v mode enabled -- %s", f, msg)
+}
+
+// Mask is a set of flags, each of which is active within the link.
+type Mask uint32
+
+// MaskNone is the mask in which no flags are active, which is the mask used
+// outside of testing and development.
+const MaskNone Mask = 0
+
+// MaskFromFlags returns the mask in which exactly the passed flags are
+// active.
+func MaskFromFlags(flags ...Flag) Mask {
+	var mask Mask
+	for _, flag := range flags {
+		mask |= Mask(flag)
+	}
+
+	return mask
+}
+
+// Active returns true if the passed flag is active within the mask.
+func (m Mask) Active(flag Flag) bool {
+	return m&Mask(flag) != 0
+}
+
+// String returns the names of the flags active within the mask.
+func (m Mask) String() string {
+	if m == MaskNone {
+		return "hodl.Mask(NONE)"
+	}
+
+	var activeFlags []string
+	for i := uint32(0); i < 32; i++ {
+		flag := Flag(1 << i)
+		if m.Active(flag) {
+			activeFlags = append(activeFlags, flag.String())
+		}
+	}
+
+	return fmt.Sprintf("hodl.Mask(%s)", strings.Join(activeFlags, "|"))
+}
@@ -0,0 +1,37 @@
+package hodl
+
+import (
+	"testing"
+)
+
+// TestMask asserts that only the flags a mask is created from are active
+// within it.
+func TestMask(t *testing.T) {
+	t.Parallel()
+
+	allFlags := []Flag{
+		ExitSettle, AddIncoming, SettleIncoming, FailIncoming,
+		AddOutgoing, SettleOutgoing, FailOutgoing, Commit,
+	}
+
+	for _, flag := range allFlags {
+		if MaskNone.Active(flag) {
+			t.Fatalf("expected %v to be inactive within empty "+
+				"mask", flag)
+		}
+	}
+
+	mask := MaskFromFlags(AddIncoming, Commit)
+	for _, flag := range allFlags {
+		expActive := flag == AddIncoming || flag == Commit
+		if mask.Active(flag) != expActive {
+			t.Fatalf("expected %v to be active: %v", flag,
+				expActive)
+		}
+	}
+
+	expStr := "hodl.Mask(AddIncoming|Commit)"
+	if mask.String() != expStr {
+		t.Fatalf("expected %v, got %v", expStr, mask.String())
+	}
+}
@@ -0,0 +1,349 @@
+package htlcswitch
+
+import (
+	"crypto/sha256"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+var (
+	// ErrInterceptorAlreadySet is returned when attempting to set a
+	// forward interceptor while another one is already registered with
+	// the switch.
+	ErrInterceptorAlreadySet = errors.New("forward interceptor already set")
+
+	// ErrForwardResolved is returned when attempting to resolve an
+	// intercepted forward which has already been resolved, either by the
+	// interceptor or because it timed out.
+	ErrForwardResolved = errors.New("intercepted forward already " +
+		"resolved")
+
+	// ErrInvalidInterceptPreimage is returned when attempting to settle an
+	// intercepted forward with a preimage that doesn't match its payment
+	// hash.
+	ErrInvalidInterceptPreimage = errors.New("preimage doesn't match " +
+		"payment hash")
+)
+
+// ForwardAction is the action taken to resolve an intercepted forward.
+type ForwardAction uint8
+
+const (
+	// ForwardResume resumes the forwarding of the HTLC, as if it had never
+	// been intercepted.
+	ForwardResume ForwardAction = iota
+
+	// ForwardFail fails the HTLC back to the incoming channel.
+	ForwardFail
+
+	// ForwardSettle settles the HTLC back to the incoming channel, using
+	// a preimage provided by the interceptor.
+	ForwardSettle
+)
+
+// String returns a human readable representation of the action.
+func (a ForwardAction) String() string {
+	switch a {
+	case ForwardResume:
+		return "resume"
+	case ForwardFail:
+		return "fail"
+	case ForwardSettle:
+		return "settle"
+	default:
+		return "unknown"
+	}
+}
+
+// ForwardInterceptor is handed every HTLC the switch is about to forward,
+// which is held until resolved through one of the Resume, Fail or Settle
+// methods of the intercepted forward. The interceptor is invoked within its
+// own goroutine.
+type ForwardInterceptor func(*InterceptedForward)
+
+// InterceptedForward is an HTLC which has been held by the switch while its
+// forward interceptor decides how it should be resolved. If it isn't resolved
+// within the switch's ForwardInterceptTimeout, it's failed back.
+type InterceptedForward struct {
+	// ID uniquely identifies the forward among those intercepted since
+	// the switch was started.
+	ID uint64
+
+	// PaymentHash is the payment hash of the HTLC.
+	PaymentHash [sha256.Size]byte
+
+	// IncomingChanID is the channel the HTLC was received over.
+	IncomingChanID lnwire.ShortChannelID
+
+	// OutgoingChanID is the channel the HTLC is to be forwarded over.
+	OutgoingChanID lnwire.ShortChannelID
+
+	// IncomingAmount is the value of the incoming HTLC.
+	IncomingAmount lnwire.MilliAtom
+
+	// OutgoingAmount is the value of the HTLC to be forwarded.
+	OutgoingAmount lnwire.MilliAtom
+
+	// IncomingExpiry is the absolute expiry height of the incoming HTLC.
+	IncomingExpiry uint32
+
+	// OutgoingExpiry is the absolute expiry height of the HTLC to be
+	// forwarded.
+	OutgoingExpiry uint32
+
+	// resolved is set atomically once the forward has been resolved.
+	resolved int32
+
+	// done is closed once the forward has been resolved.
+	done chan struct{}
+
+	packet *htlcPacket
+	s      *Switch
+}
+
+// Resume resumes the forwarding of the HTLC.
+func (f *InterceptedForward) Resume() error {
+	return f.resolve(ForwardResume, zeroPreimage)
+}
+
+// Fail fails the HTLC back to the incoming channel.
+func (f *InterceptedForward) Fail() error {
+	return f.resolve(ForwardFail, zeroPreimage)
+}
+
+// Settle settles the HTLC back to the incoming channel using the passed
+// preimage, which must match the payment hash of the HTLC.
+func (f *InterceptedForward) Settle(preimage [sha256.Size]byte) error {
+	if sha256.Sum256(preimage[:]) != f.PaymentHash {
+		return ErrInvalidInterceptPreimage
+	}
+
+	return f.resolve(ForwardSettle, preimage)
+}
+
+// resolve hands the resolution of the forward to the switch's main goroutine,
+// ensuring the forward is resolved at most once.
+func (f *InterceptedForward) resolve(action ForwardAction,
+	preimage [sha256.Size]byte) error {
+
+	if !atomic.CompareAndSwapInt32(&f.resolved, 0, 1) {
+		return ErrForwardResolved
+	}
+	close(f.done)
+
+	command := &resolveForwardCmd{
+		fwd:      f,
+		action:   action,
+		preimage: preimage,
+		err:      make(chan error, 1),
+	}
+
+	select {
+	case f.s.linkControl <- command:
+	case <-f.s.quit:
+		return ErrSwitchExiting
+	}
+
+	select {
+	case err := <-command.err:
+		return err
+	case <-f.s.quit:
+		return ErrSwitchExiting
+	}
+}
+
+// setInterceptorCmd is a command sent to the switch's main goroutine in order
+// to set or clear its forward interceptor.
+type setInterceptorCmd struct {
+	interceptor ForwardInterceptor
+	err         chan error
+}
+
+// SetInterceptor registers the passed interceptor with the switch, which will
+// be handed every HTLC forwarded from then on. Only a single interceptor may
+// be registered at a time, and it's unregistered by passing a nil
+// interceptor. Forwards intercepted before the interceptor is unregistered
+// remain held until resolved or timed out.
+func (s *Switch) SetInterceptor(interceptor ForwardInterceptor) error {
+	command := &setInterceptorCmd{
+		interceptor: interceptor,
+		err:         make(chan error, 1),
+	}
+
+	select {
+	case s.linkControl <- command:
+	case <-s.quit:
+		return ErrSwitchExiting
+	}
+
+	select {
+	case err := <-command.err:
+		return err
+	case <-s.quit:
+		return ErrSwitchExiting
+	}
+}
+
+// setInterceptor sets or clears the switch's forward interceptor.
+//
+// NOTE: This MUST be called from the htlcForwarder goroutine.
+func (s *Switch) setInterceptor(interceptor ForwardInterceptor) error {
+	if interceptor != nil && s.interceptor != nil {
+		return ErrInterceptorAlreadySet
+	}
+
+	s.interceptor = interceptor
+	return nil
+}
+
+// shouldIntercept returns true if the passed packet should be handed to the
+// forward interceptor rather than forwarded.
+//
+// NOTE: This MUST be called from the htlcForwarder goroutine.
+func (s *Switch) shouldIntercept(packet *htlcPacket) bool {
+	if s.interceptor == nil || packet.intercepted {
+		return false
+	}
+
+	_, ok := packet.htlc.(*lnwire.UpdateAddHTLC)
+	return ok
+}
+
+// interceptForward holds the passed add packet, handing it to the forward
+// interceptor. If the forward isn't resolved within the configured timeout,
+// it's failed back to the incoming channel.
+//
+// NOTE: This MUST be called from the htlcForwarder goroutine.
+func (s *Switch) interceptForward(packet *htlcPacket) error {
+	htlc := packet.htlc.(*lnwire.UpdateAddHTLC)
+	packet.intercepted = true
+
+	s.nextInterceptID++
+	fwd := &InterceptedForward{
+		ID:             s.nextInterceptID,
+		PaymentHash:    htlc.PaymentHash,
+		IncomingChanID: packet.src,
+		OutgoingChanID: packet.dest,
+		IncomingAmount: packet.incomingAmount,
+		OutgoingAmount: htlc.Amount,
+		IncomingExpiry: packet.incomingTimeout,
+		OutgoingExpiry: htlc.Expiry,
+		done:           make(chan struct{}),
+		packet:         packet,
+		s:              s,
+	}
+
+	log.Debugf("Intercepted forward %v of htlc(%x): %v->%v", fwd.ID,
+		htlc.PaymentHash[:], packet.src, packet.dest)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		select {
+		case <-time.After(s.cfg.ForwardInterceptTimeout):
+		case <-fwd.done:
+			return
+		case <-s.quit:
+			return
+		}
+
+		log.Infof("Intercepted forward %v of htlc(%x) timed out, "+
+			"failing back", fwd.ID, htlc.PaymentHash[:])
+
+		err := fwd.Fail()
+		if err != nil && err != ErrForwardResolved {
+			log.Errorf("unable to fail intercepted forward %v: %v",
+				fwd.ID, err)
+		}
+	}()
+
+	go s.interceptor(fwd)
+
+	return nil
+}
+
+// resolveForwardCmd is a command sent to the switch's main goroutine in order
+// to resolve an intercepted forward.
+type resolveForwardCmd struct {
+	fwd      *InterceptedForward
+	action   ForwardAction
+	preimage [sha256.Size]byte
+	err      chan error
+}
+
+// resolveForward carries out the resolution of an intercepted forward.
+//
+// NOTE: This MUST be called from the htlcForwarder goroutine.
+func (s *Switch) resolveForward(cmd *resolveForwardCmd) error {
+	packet := cmd.fwd.packet
+	htlc := packet.htlc.(*lnwire.UpdateAddHTLC)
+
+	log.Debugf("Resolving intercepted forward %v of htlc(%x): %v",
+		cmd.fwd.ID, htlc.PaymentHash[:], cmd.action)
+
+	// Should the resumed HTLC be unable to be forwarded, it's failed back
+	// and the error logged just as if it had never been intercepted, so
+	// the error isn't returned to the interceptor.
+	if cmd.action == ForwardResume {
+		s.handlePacketForward(packet)
+		return nil
+	}
+
+	source, err := s.getLinkByShortID(packet.src)
+	if err != nil {
+		err := errors.Errorf("unable to find channel link "+
+			"by channel point (%v): %v", packet.src, err)
+		log.Error(err)
+		return err
+	}
+
+	switch cmd.action {
+	case ForwardFail:
+		failure := lnwire.NewTemporaryChannelFailure(nil)
+		reason, err := packet.obfuscator.InitialObfuscate(failure)
+		if err != nil {
+			err := errors.Errorf("unable to obfuscate "+
+				"error: %v", err)
+			log.Error(err)
+			return err
+		}
+
+		go source.HandleSwitchPacket(newFailPacket(
+			packet.src,
+			&lnwire.UpdateFailHTLC{
+				Reason: reason,
+			},
+			htlc.PaymentHash, 0, true,
+		))
+
+		s.notifyForwardFail(packet, htlc, errors.New(
+			"intercepted forward failed back",
+		))
+
+	case ForwardSettle:
+		// As with a settle received downstream, the preimage is
+		// committed to disk before the settle is propagated back.
+		err := s.cfg.PreimageCache.AddPreimage(cmd.preimage[:])
+		if err != nil {
+			return errors.Errorf("unable to add preimage for "+
+				"payment hash %x: %v", htlc.PaymentHash[:], err)
+		}
+
+		go source.HandleSwitchPacket(newSettlePacket(
+			packet.src,
+			&lnwire.UpdateFufillHTLC{
+				PaymentPreimage: cmd.preimage,
+			},
+			htlc.PaymentHash, htlc.Amount,
+		))
+
+	default:
+		return errors.Errorf("unknown forward action: %v", cmd.action)
+	}
+
+	return nil
+}
@@ -1,6 +1,9 @@
 package htlcswitch
 
 import (
+	"time"
+
+	"github.com/lightningnetwork/lnd/amp"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwire"
@@ -15,8 +18,136 @@ type InvoiceDatabase interface {
 	LookupInvoice(chainhash.Hash) (*channeldb.Invoice, error)
 
 	// SettleInvoice attempts to mark an invoice corresponding to the
-	// passed payment hash as fully settled.
-	SettleInvoice(chainhash.Hash) error
+	// passed payment hash as fully settled, recording the HTLC which paid
+	// it.
+	SettleInvoice(chainhash.Hash, *channeldb.InvoiceHTLC) error
+
+	// AcceptInvoice attempts to mark the hold invoice corresponding to the
+	// passed payment hash as accepted, recording the HTLC which is to pay
+	// it. The passed callback is invoked once the invoice is either
+	// settled or canceled, at which point the HTLC is to be resolved.
+	AcceptInvoice(chainhash.Hash, *channeldb.InvoiceHTLC,
+		func(HodlEvent)) error
+
+	// AcceptAMPShard adds the passed shard of an atomic multi-path payment
+	// of the passed total amount to its set, recording the HTLC carrying
+	// it. The shards of a set are combined no matter the link they arrive
+	// over. The passed callback is invoked once the set is either settled,
+	// as all of its shards have arrived, or canceled, at which point the
+	// HTLC is to be resolved.
+	AcceptAMPShard(*amp.Shard, lnwire.MilliAtom, *channeldb.InvoiceHTLC,
+		func(HodlEvent)) error
+
+	// ResumeHodlHtlc registers the passed callback for the hold invoice,
+	// or the set of AMP shards, corresponding to the passed payment hash,
+	// whose HTLC was held before the link was restarted. Should the
+	// invoice or set no longer be accepted, the callback is invoked right
+	// away.
+	ResumeHodlHtlc(chainhash.Hash, func(HodlEvent)) error
+
+	// CancelInvoice attempts to cancel the invoice, or the set of AMP
+	// shards, corresponding to the passed payment hash.
+	CancelInvoice(chainhash.Hash) error
+}
+
+// HodlEvent describes how an HTLC paying a hold invoice, or carrying a shard of
+// an atomic multi-path payment, which was held by the link, is to be resolved.
+type HodlEvent struct {
+	// PaymentHash is the payment hash of the held HTLC.
+	PaymentHash [32]byte
+
+	// Preimage is the preimage the HTLC is to be settled with. If nil,
+	// the invoice has been canceled and the HTLC is to be failed back.
+	Preimage *[32]byte
+}
+
+// PreimageCache is an interface which represents a persistent store of the
+// preimages we've learned over the course of settling HTLCs. Preimages are
+// retained so that any HTLCs which must be claimed on-chain can still be
+// redeemed after a restart.
+type PreimageCache interface {
+	// LookupPreimage attempts to look up a preimage according to its
+	// payment hash. If found, the preimage is returned along with true.
+	LookupPreimage(hash []byte) ([]byte, bool)
+
+	// AddPreimage adds a newly discovered preimage to the cache.
+	AddPreimage(preimage []byte) error
+}
+
+// ResolutionStore is an interface which represents a persistent store of the
+// resolutions of outgoing HTLCs which have been resolved on-chain. Each
+// resolution is retained until the switch has handled it, so that it isn't
+// lost should we restart before then.
+type ResolutionStore interface {
+	// AddResolutionMsg adds the passed resolution to the store, assigning
+	// it its ID.
+	AddResolutionMsg(msg *channeldb.ResolutionMsg) error
+
+	// FetchResolutionMsgs returns all resolutions which have yet to be
+	// handled.
+	FetchResolutionMsgs() ([]*channeldb.ResolutionMsg, error)
+
+	// DeleteResolutionMsg removes the resolution with the passed ID once
+	// it has been handled.
+	DeleteResolutionMsg(id uint64) error
+}
+
+// CircuitStore is an interface which represents a persistent store of the
+// payment circuits opened by the switch, allowing the settles and fails of
+// forwarded HTLCs to be sent back to their incoming channel after a restart.
+type CircuitStore interface {
+	// UpdateCircuits writes the passed circuits, replacing any with the
+	// same payment hash, and deletes the circuits of the passed payment
+	// hashes.
+	UpdateCircuits(circuits []*channeldb.PaymentCircuit,
+		deleted [][32]byte) error
+
+	// FetchCircuits returns all circuits which have yet to be deleted.
+	FetchCircuits() ([]*channeldb.PaymentCircuit, error)
+}
+
+// HodlHtlcStore is an interface which represents a persistent store of the
+// HTLCs paying hold invoices which are held by the link, allowing them to be
+// resolved once their invoice is settled or canceled, even after the link has
+// been restarted.
+type HodlHtlcStore interface {
+	// AddHodlHtlc adds the passed HTLC to the set of held HTLCs.
+	AddHodlHtlc(htlc *channeldb.HodlHtlc) error
+
+	// DeleteHodlHtlc removes the held HTLC with the passed channel and
+	// HTLC ID once it has been resolved.
+	DeleteHodlHtlc(chanID lnwire.ShortChannelID, htlcID uint64) error
+
+	// FetchHodlHtlcs returns the HTLCs received over the passed channel
+	// which are still held.
+	FetchHodlHtlcs(chanID lnwire.ShortChannelID) ([]*channeldb.HodlHtlc,
+		error)
+}
+
+// ForwardingLog is an interface which represents a persistent log of the
+// HTLCs successfully forwarded by the switch, from which the fees earned by
+// each channel can be derived.
+type ForwardingLog interface {
+	// AddForwardingEvents appends the passed events to the log.
+	AddForwardingEvents([]channeldb.ForwardingEvent) error
+
+	// DeleteForwardingEvents removes the events which occurred before the
+	// passed time from the log, returning the number of events removed.
+	DeleteForwardingEvents(before time.Time) (int, error)
+}
+
+// ReplayLog is an interface which represents a persistent log of the onion
+// packets processed by our node, used to reject packets which are replayed.
+type ReplayLog interface {
+	// PutReplayEntry adds the packet identified by the passed hash prefix
+	// to the log, where it's kept until the passed CLTV expiry. If the
+	// packet is already present, then channeldb.ErrReplayedPacket is
+	// returned.
+	PutReplayEntry(hash *channeldb.HashPrefix, cltv uint32) error
+
+	// GarbageCollectReplayLog removes every entry whose CLTV expiry is at
+	// or below the passed height, returning the number removed.
+	GarbageCollectReplayLog(height uint32) (uint32, error)
 }
 
 // ChannelLink is an interface which represents the subsystem for managing the
@@ -67,11 +198,11 @@ type ChannelLink interface {
 	// represents the up to date available flow through the channel. This
 	// takes into account any forwarded but un-cleared HTLC's, and any
 	// HTLC's which have been set to the over flow queue.
-	Bandwidth() lnwire.MilliSatoshi
+	Bandwidth() lnwire.MilliAtom
 
 	// Stats return the statistics of channel link. Number of updates,
 	// total sent/received milli-satoshis.
-	Stats() (uint64, lnwire.MilliSatoshi, lnwire.MilliSatoshi)
+	Stats() (uint64, lnwire.MilliAtom, lnwire.MilliAtom)
 
 	// Peer returns the representation of remote peer with which we have
 	// the channel link opened.
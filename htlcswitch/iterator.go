@@ -1,10 +1,14 @@
 package htlcswitch
 
 import (
-	"encoding/binary"
+	"crypto/sha256"
 	"io"
+	"sync"
+	"sync/atomic"
 
 	"github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/lnwire"
 )
 
@@ -59,7 +63,7 @@ type ForwardingInfo struct {
 
 	// AmountToForward is the amount of milli-satoshis that the receiving
 	// node should forward to the next hop.
-	AmountToForward lnwire.MilliSatoshi
+	AmountToForward lnwire.MilliAtom
 
 	// OutgoingCTLV is the specified value of the CTLV timelock to be used
 	// in the outgoing HTLC.
@@ -82,6 +86,14 @@ type HopIterator interface {
 	// information given to it by the prior hop.
 	ForwardingInstructions() ForwardingInfo
 
+	// HopPayload returns the full payload of this hop, which along with
+	// the forwarding instructions includes any additional records carried
+	// to the exit hop, such as those of an atomic multi-path payment.
+	//
+	// NOTE: Like ForwardingInstructions, this advances the iterator, so
+	// only one of the two is to be called for each hop.
+	HopPayload() *Payload
+
 	// EncodeNextHop encodes the onion packet destined for the next hop
 	// into the passed io.Writer.
 	EncodeNextHop(w io.Writer) error
@@ -94,10 +106,9 @@ type sphinxHopIterator struct {
 	// nextPacket is the decoded onion packet for the _next_ hop.
 	nextPacket *sphinx.OnionPacket
 
-	// processedPacket is the outcome of processing an onion packet. It
-	// includes the information required to properly forward the packet to
-	// the next hop.
-	processedPacket *sphinx.ProcessedPacket
+	// payload is the payload of this hop, parsed from the hop data of the
+	// processed onion packet.
+	payload *Payload
 }
 
 // A compile time check to ensure sphinxHopIterator implements the HopIterator
@@ -118,23 +129,16 @@ func (r *sphinxHopIterator) EncodeNextHop(w io.Writer) error {
 //
 // NOTE: Part of the HopIterator interface.
 func (r *sphinxHopIterator) ForwardingInstructions() ForwardingInfo {
-	fwdInst := r.processedPacket.ForwardingInstructions
-
-	var nextHop lnwire.ShortChannelID
-	switch r.processedPacket.Action {
-	case sphinx.ExitNode:
-		nextHop = exitHop
-	case sphinx.MoreHops:
-		s := binary.BigEndian.Uint64(fwdInst.NextAddress[:])
-		nextHop = lnwire.NewShortChanIDFromInt(s)
-	}
+	return r.HopPayload().ForwardingInfo()
+}
 
-	return ForwardingInfo{
-		Network:         BitcoinHop,
-		NextHop:         nextHop,
-		AmountToForward: lnwire.MilliSatoshi(fwdInst.ForwardAmount),
-		OutgoingCTLV:    fwdInst.OutgoingCltv,
-	}
+// HopPayload returns the full payload of this hop, which along with the
+// forwarding instructions includes any additional records carried to the exit
+// hop.
+//
+// NOTE: Part of the HopIterator interface.
+func (r *sphinxHopIterator) HopPayload() *Payload {
+	return r.payload
 }
 
 // OnionProcessor is responsible for keeping all sphinx dependent parts inside
@@ -147,19 +151,133 @@ func (r *sphinxHopIterator) ForwardingInstructions() ForwardingInfo {
 // the hop iterator should contain sphinx router which makes their creations in
 // tests dependent from the sphinx internal parts.
 type OnionProcessor struct {
+	started int32
+	stopped int32
+
 	router *sphinx.Router
+
+	// replayLog, if non-nil, is the persistent log of the onion packets
+	// processed by our node, which is consulted in order to reject
+	// packets which are replayed, even across restarts.
+	replayLog ReplayLog
+
+	// notifier is used to learn of new blocks, at which point the
+	// entries of the replay log which have expired are removed.
+	notifier chainntnfs.ChainNotifier
+
+	wg   sync.WaitGroup
+	quit chan struct{}
 }
 
-// NewOnionProcessor creates new instance of decoder.
-func NewOnionProcessor(router *sphinx.Router) *OnionProcessor {
-	return &OnionProcessor{router}
+// NewOnionProcessor creates new instance of decoder. If the replay log is
+// nil, then replayed packets are only detected by the sphinx router itself.
+func NewOnionProcessor(router *sphinx.Router, replayLog ReplayLog,
+	notifier chainntnfs.ChainNotifier) *OnionProcessor {
+
+	return &OnionProcessor{
+		router:    router,
+		replayLog: replayLog,
+		notifier:  notifier,
+		quit:      make(chan struct{}),
+	}
+}
+
+// Start launches the goroutine which garbage collects the replay log as new
+// blocks arrive.
+func (p *OnionProcessor) Start() error {
+	if !atomic.CompareAndSwapInt32(&p.started, 0, 1) {
+		return nil
+	}
+
+	if p.replayLog == nil {
+		return nil
+	}
+
+	blockEpochs, err := p.notifier.RegisterBlockEpochNtfn()
+	if err != nil {
+		return err
+	}
+
+	p.wg.Add(1)
+	go p.garbageCollector(blockEpochs)
+
+	return nil
+}
+
+// Stop signals the replay log's garbage collector to exit, then waits until
+// it has.
+func (p *OnionProcessor) Stop() error {
+	if !atomic.CompareAndSwapInt32(&p.stopped, 0, 1) {
+		return nil
+	}
+
+	close(p.quit)
+	p.wg.Wait()
+
+	return nil
+}
+
+// garbageCollector removes the entries of the replay log whose CLTV expiry has
+// been reached each time a new block arrives. Once an HTLC has expired, the
+// onion packet it carried can no longer be replayed successfully, as the link
+// rejects any HTLC which has expired.
+//
+// NOTE: This MUST be run as a goroutine.
+func (p *OnionProcessor) garbageCollector(
+	blockEpochs *chainntnfs.BlockEpochEvent) {
+
+	defer p.wg.Done()
+	defer blockEpochs.Cancel()
+
+	for {
+		select {
+		case epoch, ok := <-blockEpochs.Epochs:
+			if !ok {
+				return
+			}
+
+			numExpired, err := p.replayLog.GarbageCollectReplayLog(
+				uint32(epoch.Height),
+			)
+			if err != nil {
+				log.Errorf("unable to garbage collect replay "+
+					"log at height %v: %v", epoch.Height, err)
+				continue
+			}
+
+			if numExpired > 0 {
+				log.Debugf("Removed %v expired entries from "+
+					"replay log at height %v", numExpired,
+					epoch.Height)
+			}
+
+		case <-p.quit:
+			return
+		}
+	}
+}
+
+// replayHashPrefix returns the hash prefix which identifies the passed onion
+// packet within the replay log. As the shared secret of a packet is derived
+// solely from its ephemeral key and our own node key, the ephemeral key
+// uniquely identifies the shared secret, so its hash is used instead.
+func replayHashPrefix(onionPkt *sphinx.OnionPacket) *channeldb.HashPrefix {
+	h := sha256.Sum256(onionPkt.EphemeralKey.SerializeCompressed())
+
+	var hashPrefix channeldb.HashPrefix
+	copy(hashPrefix[:], h[:])
+
+	return &hashPrefix
 }
 
 // DecodeHopIterator attempts to decode a valid sphinx packet from the passed io.Reader
 // instance using the rHash as the associated data when checking the relevant
-// MACs during the decoding process.
-func (p *OnionProcessor) DecodeHopIterator(r io.Reader, rHash []byte) (HopIterator,
-	lnwire.FailCode) {
+// MACs during the decoding process. The incoming CLTV is the expiry of the
+// HTLC which carried the packet, until which the packet is kept within the
+// replay log.
+func (p *OnionProcessor) DecodeHopIterator(r io.Reader, rHash []byte,
+	incomingCltv uint32) (HopIterator, lnwire.FailCode) {
+
 	onionPkt := &sphinx.OnionPacket{}
 	if err := onionPkt.Decode(r); err != nil {
 		return nil, lnwire.CodeTemporaryChannelFailure
@@ -184,12 +302,82 @@ func (p *OnionProcessor) DecodeHopIterator(r io.Reader, rHash []byte) (HopIterat
 		}
 	}
 
+	// Now that the packet is known to be authentic, we'll ensure it
+	// hasn't been processed before, as the sphinx router only remembers
+	// the packets processed since we were started. Replayed packets are
+	// failed just as any other temporary failure, so that the sender
+	// learns nothing of the packets we've previously processed.
+	if p.replayLog != nil {
+		err := p.replayLog.PutReplayEntry(
+			replayHashPrefix(onionPkt), incomingCltv,
+		)
+		switch {
+		case err == channeldb.ErrReplayedPacket:
+			log.Warnf("Rejecting replayed onion packet for "+
+				"payment hash %x", rHash)
+			return nil, lnwire.CodeTemporaryChannelFailure
+
+		case err != nil:
+			log.Errorf("unable to add onion packet to replay "+
+				"log: %v", err)
+			return nil, lnwire.CodeTemporaryChannelFailure
+		}
+	}
+
+	// If the hop data carries a TLV payload, then the remainder of the
+	// payload is carried by the frames which follow it, each of which is
+	// addressed to us.
+	if sphinxPacket.ForwardingInstructions.Realm == TLVRealm {
+		payload, err := p.decodeTLVPayload(sphinxPacket, rHash)
+		if err != nil {
+			log.Errorf("unable to decode tlv onion payload for "+
+				"payment hash %x: %v", rHash, err)
+			return nil, lnwire.CodeInvalidRealm
+		}
+
+		return &sphinxHopIterator{
+			payload: payload,
+		}, lnwire.CodeNone
+	}
+
+	// Otherwise, the fixed size hop data is parsed as a legacy payload,
+	// which never includes any additional records.
+	payload := NewLegacyPayload(&sphinxPacket.ForwardingInstructions)
+	if sphinxPacket.Action == sphinx.ExitNode {
+		payload.FwdInfo.NextHop = exitHop
+	}
+
 	return &sphinxHopIterator{
-		nextPacket:      sphinxPacket.NextPacket,
-		processedPacket: sphinxPacket,
+		nextPacket: sphinxPacket.NextPacket,
+		payload:    payload,
 	}, lnwire.CodeNone
 }
 
+// decodeTLVPayload collects the frames of hop data carrying a TLV payload,
+// starting with those of the passed processed packet, by processing the
+// packets which follow it until the exit node is reached. The TLV payload is
+// then parsed from the collected frames. As TLV payloads are only sent to the
+// final hop, a payload whose frames aren't followed by the exit node is
+// rejected.
+func (p *OnionProcessor) decodeTLVPayload(packet *sphinx.ProcessedPacket,
+	rHash []byte) (*Payload, error) {
+
+	frames := []*sphinx.HopData{&packet.ForwardingInstructions}
+	for packet.Action != sphinx.ExitNode {
+		var err error
+		packet, err = p.router.ProcessOnionPacket(
+			packet.NextPacket, rHash,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		frames = append(frames, &packet.ForwardingInstructions)
+	}
+
+	return NewPayloadFromTLVHopData(frames)
+}
+
 // DecodeOnionObfuscator takes an io.Reader which should contain the onion
 // packet as original received by a forwarding node and creates an Obfuscator
 // instance using the derived shared secret. In the case that en error occurs,
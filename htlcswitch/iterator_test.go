@@ -0,0 +1,214 @@
+package htlcswitch
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"testing"
+
+	"github.com/btcsuite/fastsha256"
+	"github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/tlv"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/chaincfg"
+)
+
+// TestOnionProcessorReplay asserts that an onion packet which has already been
+// processed is rejected as a replay, even by a fresh onion processor sharing
+// the same replay log, until the HTLC which carried it has expired.
+func TestOnionProcessorReplay(t *testing.T) {
+	t.Parallel()
+
+	nodeKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to create node key: %v", err)
+	}
+	sessionKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to create session key: %v", err)
+	}
+
+	rHash := fastsha256.Sum256([]byte{1})
+	onionPkt, err := sphinx.NewOnionPacket(
+		[]*btcec.PublicKey{nodeKey.PubKey()}, sessionKey,
+		[]sphinx.HopData{{OutgoingCltv: 100}}, rHash[:],
+	)
+	if err != nil {
+		t.Fatalf("unable to create onion packet: %v", err)
+	}
+	var onionBlob bytes.Buffer
+	if err := onionPkt.Encode(&onionBlob); err != nil {
+		t.Fatalf("unable to encode onion packet: %v", err)
+	}
+
+	// decode decodes the onion packet with a fresh onion processor, as
+	// would be the case after a restart.
+	replayLog := newMockReplayLog()
+	decode := func() lnwire.FailCode {
+		processor := NewOnionProcessor(
+			sphinx.NewRouter(nodeKey, &chaincfg.MainNetParams),
+			replayLog, nil,
+		)
+		_, failCode := processor.DecodeHopIterator(
+			bytes.NewReader(onionBlob.Bytes()), rHash[:], 100,
+		)
+		return failCode
+	}
+
+	if failCode := decode(); failCode != lnwire.CodeNone {
+		t.Fatalf("unable to decode onion packet: %v", failCode)
+	}
+
+	// The same packet should now be rejected as a replay.
+	failCode := decode()
+	if failCode != lnwire.CodeTemporaryChannelFailure {
+		t.Fatalf("expected replayed packet to be rejected with %v, "+
+			"got %v", lnwire.CodeTemporaryChannelFailure, failCode)
+	}
+
+	// Once the HTLC which carried the packet has expired, its entry can
+	// be removed from the replay log.
+	if _, err := replayLog.GarbageCollectReplayLog(99); err != nil {
+		t.Fatalf("unable to garbage collect replay log: %v", err)
+	}
+	if failCode := decode(); failCode != lnwire.CodeTemporaryChannelFailure {
+		t.Fatalf("expected replayed packet to be rejected with %v, "+
+			"got %v", lnwire.CodeTemporaryChannelFailure, failCode)
+	}
+	if _, err := replayLog.GarbageCollectReplayLog(100); err != nil {
+		t.Fatalf("unable to garbage collect replay log: %v", err)
+	}
+	if failCode := decode(); failCode != lnwire.CodeNone {
+		t.Fatalf("unable to decode onion packet: %v", failCode)
+	}
+}
+
+// TestOnionProcessorTLVPayload asserts that the TLV payload of the final hop,
+// which is carried by several frames of hop data, is reassembled by the hop
+// iterator of the final hop.
+func TestOnionProcessorTLVPayload(t *testing.T) {
+	t.Parallel()
+
+	hopKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to create hop key: %v", err)
+	}
+	finalKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to create final key: %v", err)
+	}
+	sessionKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to create session key: %v", err)
+	}
+
+	// The final hop's payload includes both payment data and the shard
+	// of an atomic multi-path payment, which requires several frames.
+	var (
+		amt  uint64 = 100000
+		cltv uint32 = 144
+		mpp         = lnwire.NewMPP(250000, [32]byte{1})
+		amp         = lnwire.NewAMP([32]byte{2}, [32]byte{3}, 4)
+	)
+	tlvStream, err := tlv.NewStream(
+		lnwire.NewAmtToFwdRecord(&amt),
+		lnwire.NewLockTimeRecord(&cltv),
+		mpp.Record(),
+		amp.Record(),
+	)
+	if err != nil {
+		t.Fatalf("unable to create tlv stream: %v", err)
+	}
+	var b bytes.Buffer
+	if err := tlvStream.Encode(&b); err != nil {
+		t.Fatalf("unable to encode tlv stream: %v", err)
+	}
+	frames, err := NewTLVHopData(b.Bytes())
+	if err != nil {
+		t.Fatalf("unable to create tlv hop data: %v", err)
+	}
+	if len(frames) < 2 {
+		t.Fatalf("expected payload to span several frames, got %v",
+			len(frames))
+	}
+
+	// The first hop is sent legacy hop data, while each frame of the
+	// final hop's payload is addressed to the final hop.
+	firstHop := sphinx.HopData{ForwardAmount: 100000, OutgoingCltv: 144}
+	binary.BigEndian.PutUint64(firstHop.NextAddress[:], 1)
+
+	nodes := []*btcec.PublicKey{hopKey.PubKey()}
+	hopData := []sphinx.HopData{firstHop}
+	for _, frame := range frames {
+		nodes = append(nodes, finalKey.PubKey())
+		hopData = append(hopData, frame)
+	}
+
+	rHash := fastsha256.Sum256([]byte{1})
+	onionPkt, err := sphinx.NewOnionPacket(
+		nodes, sessionKey, hopData, rHash[:],
+	)
+	if err != nil {
+		t.Fatalf("unable to create onion packet: %v", err)
+	}
+	var onionBlob bytes.Buffer
+	if err := onionPkt.Encode(&onionBlob); err != nil {
+		t.Fatalf("unable to encode onion packet: %v", err)
+	}
+
+	// The first hop should forward the packet as instructed by its
+	// legacy hop data.
+	hopProcessor := NewOnionProcessor(
+		sphinx.NewRouter(hopKey, &chaincfg.MainNetParams), nil, nil,
+	)
+	iterator, failCode := hopProcessor.DecodeHopIterator(
+		bytes.NewReader(onionBlob.Bytes()), rHash[:], 300,
+	)
+	if failCode != lnwire.CodeNone {
+		t.Fatalf("unable to decode onion packet: %v", failCode)
+	}
+	fwdInfo := iterator.ForwardingInstructions()
+	if fwdInfo.NextHop != lnwire.NewShortChanIDFromInt(1) {
+		t.Fatalf("expected next hop %v, got %v",
+			lnwire.NewShortChanIDFromInt(1), fwdInfo.NextHop)
+	}
+
+	var nextBlob bytes.Buffer
+	if err := iterator.EncodeNextHop(&nextBlob); err != nil {
+		t.Fatalf("unable to encode next onion packet: %v", err)
+	}
+
+	// The final hop should reassemble its TLV payload from all of its
+	// frames.
+	finalProcessor := NewOnionProcessor(
+		sphinx.NewRouter(finalKey, &chaincfg.MainNetParams), nil, nil,
+	)
+	iterator, failCode = finalProcessor.DecodeHopIterator(
+		bytes.NewReader(nextBlob.Bytes()), rHash[:], 144,
+	)
+	if failCode != lnwire.CodeNone {
+		t.Fatalf("unable to decode onion packet: %v", failCode)
+	}
+
+	payload := iterator.HopPayload()
+	fwdInfo = payload.ForwardingInfo()
+	if fwdInfo.NextHop != exitHop {
+		t.Fatalf("expected exit hop, got %v", fwdInfo.NextHop)
+	}
+	if fwdInfo.AmountToForward != lnwire.MilliAtom(amt) {
+		t.Fatalf("expected amount %v, got %v", amt,
+			fwdInfo.AmountToForward)
+	}
+	if fwdInfo.OutgoingCTLV != cltv {
+		t.Fatalf("expected cltv %v, got %v", cltv, fwdInfo.OutgoingCTLV)
+	}
+	if !reflect.DeepEqual(payload.MultiPath(), mpp) {
+		t.Fatalf("expected payment data %v, got %v", mpp,
+			payload.MultiPath())
+	}
+	if !reflect.DeepEqual(payload.AMPRecord(), amp) {
+		t.Fatalf("expected amp data %v, got %v", amp,
+			payload.AMPRecord())
+	}
+}
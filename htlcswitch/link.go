@@ -11,7 +11,10 @@ import (
 	"crypto/sha256"
 
 	"github.com/go-errors/errors"
+	"github.com/lightningnetwork/lnd/amp"
 	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/htlcswitch/hodl"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/roasbeef/btcd/chaincfg/chainhash"
@@ -20,17 +23,36 @@ import (
 )
 
 const (
-	// expiryGraceDelta is a grace period that the timeout of incoming
-	// HTLC's that pay directly to us (i.e we're the "exit node") must up
-	// hold. We'll reject any HTLC's who's timeout minus this value is less
-	// that or equal to the current block height. We require this in order
-	// to ensure that if the extending party goes to the chain, then we'll
-	// be able to claim the HTLC still.
-	//
-	// TODO(roasbeef): must be < default delta
-	expiryGraceDelta = 2
+	// DefaultExpiryGraceDelta is the default grace period, in blocks, that
+	// HTLCs must leave before their expiry. HTLCs paying directly to us
+	// (i.e we're the "exit node") are rejected unless their timeout
+	// exceeds the current height by more than this value, as are HTLCs to
+	// be forwarded whose outgoing timeout doesn't. We require this in
+	// order to ensure that if the extending party goes to the chain, then
+	// we'll still be able to claim the HTLC, and that HTLCs we forward
+	// don't expire before they can be resolved.
+	DefaultExpiryGraceDelta = 2
+
+	// DefaultBatchInterval is the default interval at which a link commits
+	// the updates it has pending, should its batch not fill up earlier.
+	DefaultBatchInterval = 50 * time.Millisecond
+
+	// DefaultBatchSize is the default number of pending updates which
+	// cause a link to commit them immediately, rather than waiting for its
+	// batch interval to elapse.
+	DefaultBatchSize = 10
+
+	// DefaultMailboxCapacity is the default number of packets from the
+	// switch, and of wire messages from the remote peer, which may be
+	// pending processing by a link.
+	DefaultMailboxCapacity = 500
 )
 
+// ErrDustExposureExceeded is returned when a dust HTLC would push the total
+// value of the dust HTLCs on either commitment of a channel above the link's
+// maximum dust exposure.
+var ErrDustExposureExceeded = errors.New("dust exposure limit exceeded")
+
 // ForwardingPolicy describes the set of constraints that a given ChannelLink
 // is to adhere to when forwarding HTLC's. For each incoming HTLC, this set of
 // constraints will be consulted in order to ensure that adequate fees are
@@ -40,17 +62,21 @@ const (
 // latest policy.
 type ForwardingPolicy struct {
 	// MinHTLC is the smallest HTLC that is to be forwarded.
-	MinHTLC lnwire.MilliSatoshi
+	MinHTLC lnwire.MilliAtom
+
+	// MaxHTLC is the largest HTLC that is to be forwarded. A zero value
+	// indicates that HTLCs aren't limited beyond the channel's capacity.
+	MaxHTLC lnwire.MilliAtom
 
 	// BaseFee is the base fee, expressed in milli-satoshi that must be
 	// paid for each incoming HTLC. This field, combined with FeeRate is
 	// used to compute the required fee for a given HTLC.
-	BaseFee lnwire.MilliSatoshi
+	BaseFee lnwire.MilliAtom
 
 	// FeeRate is the fee rate, expressed in milli-satoshi that must be
 	// paid for each incoming HTLC. This field combined with BaseFee is
 	// used to compute the required fee for a given HTLC.
-	FeeRate lnwire.MilliSatoshi
+	FeeRate lnwire.MilliAtom
 
 	// TimeLockDelta is the absolute time-lock value, expressed in blocks,
 	// that will be subtracted from an incoming HTLC's timelock value to
@@ -73,7 +99,7 @@ type ForwardingPolicy struct {
 //
 // TODO(roasbeef): also add in current available channel bandwidth, inverse
 // func
-func ExpectedFee(f ForwardingPolicy, htlcAmt lnwire.MilliSatoshi) lnwire.MilliSatoshi {
+func ExpectedFee(f ForwardingPolicy, htlcAmt lnwire.MilliAtom) lnwire.MilliAtom {
 
 	// TODO(roasbeef): write some basic table driven tests
 	return f.BaseFee + (htlcAmt*f.FeeRate)/1000000
@@ -96,8 +122,10 @@ type ChannelLinkConfig struct {
 
 	// DecodeHopIterator function is responsible for decoding HTLC Sphinx
 	// onion blob, and creating hop iterator which will give us next
-	// destination of HTLC.
-	DecodeHopIterator func(r io.Reader, rHash []byte) (HopIterator, lnwire.FailCode)
+	// destination of HTLC. The incoming CLTV is the expiry of the HTLC
+	// which carried the onion blob.
+	DecodeHopIterator func(r io.Reader, rHash []byte,
+		incomingCltv uint32) (HopIterator, lnwire.FailCode)
 
 	// DecodeOnionObfuscator function is responsible for decoding HTLC
 	// Sphinx onion blob, and creating onion failure obfuscator.
@@ -116,6 +144,19 @@ type ChannelLinkConfig struct {
 	// in thread-safe manner.
 	Registry InvoiceDatabase
 
+	// PreimageCache is a persistent cache of preimages, to which the
+	// preimage of every held HTLC is added before it's settled, so that
+	// the HTLC can still be claimed on-chain. If nil, preimages aren't
+	// added.
+	PreimageCache PreimageCache
+
+	// HodlStore is used to persist the HTLCs paying hold invoices, or
+	// carrying AMP shards, which are held by the link, so that they're
+	// still resolved once their invoice or set is settled or canceled
+	// after the link has been restarted. If nil, held HTLCs aren't
+	// persisted.
+	HodlStore HodlHtlcStore
+
 	// BlockEpochs is an active block epoch event stream backed by an
 	// active ChainNotifier instance. The ChannelLink will use new block
 	// notifications sent over this channel to decide when a _new_ HTLC is
@@ -132,6 +173,44 @@ type ChannelLinkConfig struct {
 	// with the debug htlc R-Hash are immediately settled in the next
 	// available state transition.
 	DebugHTLC bool
+
+	// MaxDustExposure is the maximum total value of the dust HTLCs on
+	// either commitment of the channel. The value of dust HTLCs is burned
+	// to fees should the channel be force closed, so new dust HTLCs which
+	// would push the total above this value are failed, bounding the
+	// value a peer is able to grief us out of. A value of zero disables
+	// the limit.
+	MaxDustExposure lnwire.MilliAtom
+
+	// BatchInterval is the interval at which the link commits the adds,
+	// settles and fails it has pending, bundling them within a single
+	// commitment update. A value of zero selects DefaultBatchInterval.
+	BatchInterval time.Duration
+
+	// BatchSize is the number of pending updates which cause the link to
+	// commit them immediately, rather than waiting for the batch interval
+	// to elapse. A value of zero selects DefaultBatchSize.
+	BatchSize uint32
+
+	// MailboxCapacity is the number of packets from the switch, and of
+	// wire messages from the remote peer, which may be pending processing
+	// by the link. It also bounds the number of HTLC adds held within the
+	// overflow queue. Once full, new adds from the switch are failed back
+	// with a temporary channel failure, while wire messages from the
+	// remote peer block its read loop until there's room again. A value
+	// of zero selects DefaultMailboxCapacity.
+	MailboxCapacity int
+
+	// ExpiryGraceDelta is the number of blocks incoming HTLCs, and the
+	// HTLCs forwarded for them, must leave before their expiry, or be
+	// rejected. It must be below the time lock delta of the forwarding
+	// policy. A value of zero selects DefaultExpiryGraceDelta.
+	ExpiryGraceDelta uint32
+
+	// HodlMask is a set of flags instructing the link to hold HTLCs at
+	// specific points of its processing pipeline. It's only meant to be
+	// used for testing and development.
+	HodlMask hodl.Mask
 }
 
 // channelLink is the service which drives a channel's commitment update
@@ -176,6 +255,17 @@ type channelLink struct {
 	// use this information to govern decisions based on HTLC timeouts.
 	bestHeight uint32
 
+	// hodlHtlcs tracks the HTLCs paying hold invoices which have been
+	// accepted, but are held until their invoice is either settled or
+	// canceled. The HTLCs carrying shards of AMP payments are held
+	// likewise, until their set is resolved. Held HTLCs are persisted
+	// within the HodlStore, and restored when the link is started.
+	hodlHtlcs map[lnwallet.PaymentHash]*hodlHtlc
+
+	// hodlQueue is a channel over which the resolutions of held HTLCs are
+	// delivered by the invoice registry.
+	hodlQueue chan HodlEvent
+
 	// channel is a lightning network channel to which we apply htlc
 	// updates.
 	channel *lnwallet.LightningChannel
@@ -188,11 +278,19 @@ type channelLink struct {
 	// been processed because of the commitment transaction overflow.
 	overflowQueue *packetQueue
 
-	// upstream is a channel that new messages sent from the remote peer to
-	// the local peer will be sent across.
+	// mailboxCapacity is the capacity of the upstream and downstream
+	// mailboxes, along with the overflow queue.
+	mailboxCapacity int
+
+	// expiryGraceDelta is the number of blocks incoming HTLCs must leave
+	// before their expiry.
+	expiryGraceDelta uint32
+
+	// upstream is a bounded mailbox that new messages sent from the
+	// remote peer to the local peer will be sent across.
 	upstream chan lnwire.Message
 
-	// downstream is a channel in which new multi-hop HTLC's to be
+	// downstream is a bounded mailbox in which new multi-hop HTLC's to be
 	// forwarded will be sent across. Messages from this channel are sent
 	// by the HTLC switch.
 	downstream chan *htlcPacket
@@ -219,17 +317,31 @@ type channelLink struct {
 func NewChannelLink(cfg ChannelLinkConfig, channel *lnwallet.LightningChannel,
 	currentHeight uint32) ChannelLink {
 
+	mailboxCapacity := cfg.MailboxCapacity
+	if mailboxCapacity == 0 {
+		mailboxCapacity = DefaultMailboxCapacity
+	}
+
+	expiryGraceDelta := cfg.ExpiryGraceDelta
+	if expiryGraceDelta == 0 {
+		expiryGraceDelta = DefaultExpiryGraceDelta
+	}
+
 	return &channelLink{
 		cfg:               cfg,
 		channel:           channel,
 		clearedOnionBlobs: make(map[uint64][lnwire.OnionPacketSize]byte),
-		upstream:          make(chan lnwire.Message),
-		downstream:        make(chan *htlcPacket),
+		mailboxCapacity:   mailboxCapacity,
+		expiryGraceDelta:  expiryGraceDelta,
+		upstream:          make(chan lnwire.Message, mailboxCapacity),
+		downstream:        make(chan *htlcPacket, mailboxCapacity),
 		linkControl:       make(chan interface{}),
 		cancelReasons:     make(map[uint64]lnwire.OpaqueReason),
 		logCommitTimer:    time.NewTimer(300 * time.Millisecond),
 		overflowQueue:     newWaitingQueue(),
 		bestHeight:        currentHeight,
+		hodlHtlcs:         make(map[lnwallet.PaymentHash]*hodlHtlc),
+		hodlQueue:         make(chan HodlEvent),
 		quit:              make(chan struct{}),
 	}
 }
@@ -250,6 +362,10 @@ func (l *channelLink) Start() error {
 
 	log.Infof("ChannelLink(%v) is starting", l)
 
+	if err := l.restoreHodlHtlcs(); err != nil {
+		return errors.Errorf("unable to restore held htlcs: %v", err)
+	}
+
 	l.wg.Add(1)
 	go l.htlcManager()
 
@@ -295,7 +411,11 @@ func (l *channelLink) htlcManager() {
 	//   * also need signals when new invoices are added by the
 	//   invoiceRegistry
 
-	batchTimer := time.NewTicker(50 * time.Millisecond)
+	batchInterval := l.cfg.BatchInterval
+	if batchInterval == 0 {
+		batchInterval = DefaultBatchInterval
+	}
+	batchTimer := time.NewTicker(batchInterval)
 	defer batchTimer.Stop()
 
 	// TODO(roasbeef): fail chan in case of protocol violation
@@ -321,6 +441,27 @@ out:
 			// TODO(roasbeef): check HTLC's for expiry
 			l.bestHeight = uint32(blockEpoch.Height)
 
+			// Any held HTLC whose expiry is about to become too
+			// close for us to claim it on-chain is canceled back.
+			if !l.cancelExpiringHodlHtlcs() {
+				continue
+			}
+			if err := l.updateCommitTx(); err != nil {
+				l.fail("unable to update commitment: %v", err)
+				break out
+			}
+
+		// A held HTLC paying a hold invoice is to be resolved, as its
+		// invoice has been either settled or canceled.
+		case event := <-l.hodlQueue:
+			if !l.resolveHodlHtlc(event) {
+				continue
+			}
+			if err := l.updateCommitTx(); err != nil {
+				l.fail("unable to update commitment: %v", err)
+				break out
+			}
+
 		// The underlying channel has notified us of a unilateral close
 		// carried out by the remote peer. In the case of such an
 		// event, we'll wipe the channel state from the peer, and mark
@@ -401,13 +542,7 @@ out:
 			// failed, then we'll free up a new slot.
 			htlc, ok := pkt.htlc.(*lnwire.UpdateAddHTLC)
 			if ok && l.overflowQueue.length() != 0 {
-				log.Infof("Downstream htlc add update with "+
-					"payment hash(%x) have been added to "+
-					"reprocessing queue, batch: %v",
-					htlc.PaymentHash[:],
-					l.batchCounter)
-
-				l.overflowQueue.consume(pkt)
+				l.queueOverflowAdd(pkt, htlc)
 				continue
 			}
 			l.handleDownStreamPkt(pkt)
@@ -430,6 +565,9 @@ out:
 				if req.policy.MinHTLC != 0 {
 					l.cfg.FwrdingPolicy.MinHTLC = req.policy.MinHTLC
 				}
+				if req.policy.MaxHTLC != 0 {
+					l.cfg.FwrdingPolicy.MaxHTLC = req.policy.MaxHTLC
+				}
 				if req.policy.BaseFee != 0 {
 					l.cfg.FwrdingPolicy.BaseFee = req.policy.BaseFee
 				}
@@ -458,79 +596,38 @@ out:
 // HTLCs, timeout previously cleared HTLCs, and finally to settle currently
 // cleared HTLCs with the upstream peer.
 func (l *channelLink) handleDownStreamPkt(pkt *htlcPacket) {
-	var isSettle bool
 	switch htlc := pkt.htlc.(type) {
 	case *lnwire.UpdateAddHTLC:
+		if l.hodlActive(hodl.AddOutgoing) {
+			return
+		}
+
 		// A new payment has been initiated via the downstream channel,
 		// so we add the new HTLC to our local log, then update the
-		// commitment chains.
+		// commitment chains. Should the HTLC push the dust exposure of
+		// the channel above our limit, it's failed back just as any
+		// other HTLC which can't be added to the state machine.
 		htlc.ChanID = l.ChanID()
-		index, err := l.channel.AddHTLC(htlc)
+		var index uint64
+		err := l.checkDustExposure(htlc.Amount, false)
+		if err == nil {
+			index, err = l.channel.AddHTLC(htlc)
+		}
 		if err != nil {
 			switch err {
 
 			// The channels spare bandwidth is fully allocated, so
 			// we'll put this HTLC into the overflow queue.
 			case lnwallet.ErrMaxHTLCNumber:
-				log.Infof("Downstream htlc add update with "+
-					"payment hash(%x) have been added to "+
-					"reprocessing queue, batch: %v",
-					htlc.PaymentHash[:],
-					l.batchCounter)
-				l.overflowQueue.consume(pkt)
+				l.queueOverflowAdd(pkt, htlc)
 				return
 
 			// The HTLC was unable to be added to the state
 			// machine, as a result, we'll signal the switch to
 			// cancel the pending payment.
 			default:
-				var (
-					isObfuscated bool
-					reason       lnwire.OpaqueReason
-				)
-
-				// We'll parse the sphinx packet enclosed so we
-				// can obtain the shared secret required to
-				// encrypt the error back to the source.
-				failure := lnwire.NewTemporaryChannelFailure(nil)
-				onionReader := bytes.NewReader(htlc.OnionBlob[:])
-				obfuscator, failCode := l.cfg.DecodeOnionObfuscator(onionReader)
-
-				switch {
-				// If we were unable to parse the onion blob,
-				// then we'll send an error back to the source.
-				case failCode != lnwire.CodeNone:
-					var b bytes.Buffer
-					err := lnwire.EncodeFailure(&b, failure, 0)
-					if err != nil {
-						log.Errorf("unable to encode failure: %v", err)
-						return
-					}
-					reason = lnwire.OpaqueReason(b.Bytes())
-					isObfuscated = false
-
-				// Otherwise, we'll send back a proper failure
-				// message.
-				default:
-					reason, err = obfuscator.InitialObfuscate(failure)
-					if err != nil {
-						log.Errorf("unable to obfuscate error: %v", err)
-						return
-					}
-					isObfuscated = true
-				}
-
-				upddateFail := &lnwire.UpdateFailHTLC{
-					Reason: reason,
-				}
-				failPkt := newFailPacket(
-					l.ShortChanID(), upddateFail,
-					htlc.PaymentHash, htlc.Amount,
-					isObfuscated,
-				)
-
-				go l.cfg.Switch.forward(failPkt)
 				log.Infof("Unable to handle downstream add HTLC: %v", err)
+				l.failDownstreamAdd(htlc, err)
 				return
 			}
 		}
@@ -543,6 +640,10 @@ func (l *channelLink) handleDownStreamPkt(pkt *htlcPacket) {
 		l.cfg.Peer.SendMessage(htlc)
 
 	case *lnwire.UpdateFufillHTLC:
+		if l.hodlActive(hodl.SettleOutgoing) {
+			return
+		}
+
 		// An HTLC we forward to the switch has just settled somewhere
 		// upstream. Therefore we settle the HTLC within the our local
 		// state machine.
@@ -563,9 +664,12 @@ func (l *channelLink) handleDownStreamPkt(pkt *htlcPacket) {
 		// Then we send the HTLC settle message to the connected peer
 		// so we can continue the propagation of the settle message.
 		l.cfg.Peer.SendMessage(htlc)
-		isSettle = true
 
 	case *lnwire.UpdateFailHTLC:
+		if l.hodlActive(hodl.FailOutgoing) {
+			return
+		}
+
 		// An HTLC cancellation has been triggered somewhere upstream,
 		// we'll remove then HTLC from our local state machine.
 		logIndex, err := l.channel.FailHTLC(pkt.payHash)
@@ -584,14 +688,18 @@ func (l *channelLink) handleDownStreamPkt(pkt *htlcPacket) {
 		// Finally, we send the HTLC message to the peer which
 		// initially created the HTLC.
 		l.cfg.Peer.SendMessage(htlc)
-		isSettle = true
 	}
 
 	l.batchCounter++
 
-	// If this newly added update exceeds the min batch size for adds, or
-	// this is a settle request, then initiate an update.
-	if l.batchCounter >= 10 || isSettle {
+	// If this newly added update fills up the current batch, then initiate
+	// an update. Otherwise, the update is bundled with any others which
+	// arrive before the batch timer next ticks.
+	batchSize := l.cfg.BatchSize
+	if batchSize == 0 {
+		batchSize = DefaultBatchSize
+	}
+	if l.batchCounter >= batchSize {
 		if err := l.updateCommitTx(); err != nil {
 			l.fail("unable to update commitment: %v", err)
 			return
@@ -624,14 +732,38 @@ func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
 	case *lnwire.UpdateFufillHTLC:
 		pre := msg.PaymentPreimage
 		idx := msg.ID
+		amt, err := l.channel.OutgoingHtlcAmount(idx)
+		if err != nil {
+			l.fail("unable to handle upstream settle HTLC: %v", err)
+			return
+		}
 		if err := l.channel.ReceiveHTLCSettle(pre, idx); err != nil {
 			// TODO(roasbeef): broadcast on-chain
 			l.fail("unable to handle upstream settle HTLC: %v", err)
 			return
 		}
 
-		// TODO(roasbeef): add preimage to DB in order to swipe
-		// repeated r-values
+		if l.hodlActive(hodl.SettleIncoming) {
+			return
+		}
+
+		// As the preimage is valid, the HTLC can be claimed upstream
+		// regardless of whether the settle is ever locked in, since
+		// we're able to claim it on-chain ourselves otherwise. So
+		// rather than waiting for the settle to be locked in, we'll
+		// pipeline it to the switch right away, cutting the latency
+		// of the payment by a full commitment round trip.
+		settlePacket := newSettlePacket(
+			l.ShortChanID(), &lnwire.UpdateFufillHTLC{
+				PaymentPreimage: pre,
+			}, sha256.Sum256(pre[:]), amt,
+		)
+		go func() {
+			if err := l.cfg.Switch.forward(settlePacket); err != nil {
+				log.Errorf("channel link(%v): unable to "+
+					"forward pipelined settle: %v", l, err)
+			}
+		}()
 
 	case *lnwire.UpdateFailMalformedHTLC:
 		// If remote side have been unable to parse the onion blob we
@@ -691,7 +823,7 @@ func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
 		// validate this new commitment, closing the link if invalid.
 		err := l.channel.ReceiveNewCommitment(msg.CommitSig, msg.HtlcSigs)
 		if err != nil {
-			l.fail("unable to accept new commitment: %v", err)
+			l.failBorked("unable to accept new commitment: %v", err)
 			return
 		}
 
@@ -700,7 +832,7 @@ func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
 		// state.
 		nextRevocation, err := l.channel.RevokeCurrentCommitment()
 		if err != nil {
-			log.Errorf("unable to revoke commitment: %v", err)
+			l.failBorked("unable to revoke commitment: %v", err)
 			return
 		}
 		l.cfg.Peer.SendMessage(nextRevocation)
@@ -741,7 +873,7 @@ func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
 		// revocation window.
 		htlcs, err := l.channel.ReceiveRevocation(msg)
 		if err != nil {
-			l.fail("unable to accept revocation: %v", err)
+			l.failBorked("unable to accept revocation: %v", err)
 			return
 		}
 
@@ -749,12 +881,19 @@ func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
 		// commitment transactions they might be safely propagated over
 		// htlc switch or settled if our node was last node in htlc
 		// path.
+		// They're forwarded as a single batch, allowing the switch
+		// to persist the circuits they open and close at once.
 		htlcsToForward := l.processLockedInHtlcs(htlcs)
 		go func() {
 			log.Debugf("ChannelPoint(%v) forwarding %v HTLC's",
 				l.channel.ChannelPoint(), len(htlcsToForward))
-			for _, packet := range htlcsToForward {
-				if err := l.cfg.Switch.forward(packet); err != nil {
+			if len(htlcsToForward) == 0 {
+				return
+			}
+
+			errs := l.cfg.Switch.forwardBatch(htlcsToForward)
+			for _, err := range errs {
+				if err != nil {
 					log.Errorf("channel link(%v): "+
 						"unhandled error while forwarding "+
 						"htlc packet over htlc  "+
@@ -777,6 +916,10 @@ func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
 // commitment to their commitment chain which includes all the latest updates
 // we've received+processed up to this point.
 func (l *channelLink) updateCommitTx() error {
+	if l.hodlActive(hodl.Commit) {
+		return nil
+	}
+
 	theirCommitSig, htlcSigs, err := l.channel.SignNextCommitment()
 	if err == lnwallet.ErrNoWindow {
 		log.Tracef("revocation window exhausted, unable to send %v",
@@ -838,7 +981,7 @@ func (l *channelLink) ChanID() lnwire.ChannelID {
 
 // getBandwidthCmd is a wrapper for get bandwidth handler.
 type getBandwidthCmd struct {
-	resp chan lnwire.MilliSatoshi
+	resp chan lnwire.MilliAtom
 }
 
 // Bandwidth returns the amount which current link might pass through channel
@@ -846,9 +989,9 @@ type getBandwidthCmd struct {
 // will not be changed during function execution.
 //
 // NOTE: Part of the ChannelLink interface.
-func (l *channelLink) Bandwidth() lnwire.MilliSatoshi {
+func (l *channelLink) Bandwidth() lnwire.MilliAtom {
 	command := &getBandwidthCmd{
-		resp: make(chan lnwire.MilliSatoshi, 1),
+		resp: make(chan lnwire.MilliAtom, 1),
 	}
 
 	select {
@@ -864,7 +1007,7 @@ func (l *channelLink) Bandwidth() lnwire.MilliSatoshi {
 //
 // NOTE: Should be used inside main goroutine only, otherwise the result might
 // not be accurate.
-func (l *channelLink) getBandwidth() lnwire.MilliSatoshi {
+func (l *channelLink) getBandwidth() lnwire.MilliAtom {
 	return l.channel.LocalAvailableBalance() - l.overflowQueue.pendingAmount()
 }
 
@@ -904,7 +1047,7 @@ func (l *channelLink) UpdateForwardingPolicy(newPolicy ForwardingPolicy) {
 // Stats returns the statistics of channel link.
 //
 // NOTE: Part of the ChannelLink interface.
-func (l *channelLink) Stats() (uint64, lnwire.MilliSatoshi, lnwire.MilliSatoshi) {
+func (l *channelLink) Stats() (uint64, lnwire.MilliAtom, lnwire.MilliAtom) {
 	snapshot := l.channel.StateSnapshot()
 
 	return snapshot.NumUpdates,
@@ -921,18 +1064,110 @@ func (l *channelLink) String() string {
 
 // HandleSwitchPacket handles the switch packets. This packets which might be
 // forwarded to us from another channel link in case the htlc update came from
-// another peer or if the update was created by user
+// another peer or if the update was created by user. Should the link's
+// mailbox be full, new adds are failed back rather than blocking the switch.
 //
 // NOTE: Part of the ChannelLink interface.
 func (l *channelLink) HandleSwitchPacket(packet *htlcPacket) {
+	htlc, ok := packet.htlc.(*lnwire.UpdateAddHTLC)
+	if !ok {
+		select {
+		case l.downstream <- packet:
+		case <-l.quit:
+		}
+		return
+	}
+
 	select {
 	case l.downstream <- packet:
 	case <-l.quit:
+	default:
+		log.Warnf("ChannelLink(%v): mailbox full, failing htlc(%x)",
+			l, htlc.PaymentHash[:])
+		l.failDownstreamAdd(htlc, errors.New("link mailbox full"))
+	}
+}
+
+// queueOverflowAdd adds the passed add packet to the overflow queue, to be
+// reprocessed once a slot frees up within the commitment transaction. Should
+// the overflow queue be full, then the add is failed back instead.
+func (l *channelLink) queueOverflowAdd(pkt *htlcPacket,
+	htlc *lnwire.UpdateAddHTLC) {
+
+	if l.overflowQueue.length() >= l.mailboxCapacity {
+		log.Warnf("ChannelLink(%v): overflow queue full, failing "+
+			"htlc(%x)", l, htlc.PaymentHash[:])
+		l.failDownstreamAdd(htlc, errors.New("link overflow queue full"))
+		return
 	}
+
+	log.Infof("Downstream htlc add update with payment hash(%x) have "+
+		"been added to reprocessing queue, batch: %v",
+		htlc.PaymentHash[:], l.batchCounter)
+	l.overflowQueue.consume(pkt)
+}
+
+// failDownstreamAdd fails back an add received from the switch which is
+// unable to be added to the channel for the passed reason, with a temporary
+// channel failure.
+func (l *channelLink) failDownstreamAdd(htlc *lnwire.UpdateAddHTLC,
+	failReason error) {
+
+	l.notifyLinkFail(
+		htlc.PaymentHash, false, lnwire.CodeTemporaryChannelFailure,
+		failReason.Error(),
+	)
+	l.cfg.Switch.recordLinkFailure(l.ShortChanID())
+
+	var (
+		isObfuscated bool
+		reason       lnwire.OpaqueReason
+	)
+
+	// We'll parse the sphinx packet enclosed so we can obtain the shared
+	// secret required to encrypt the error back to the source.
+	failure := lnwire.NewTemporaryChannelFailure(nil)
+	onionReader := bytes.NewReader(htlc.OnionBlob[:])
+	obfuscator, failCode := l.cfg.DecodeOnionObfuscator(onionReader)
+
+	switch {
+	// If we were unable to parse the onion blob, then we'll send an error
+	// back to the source.
+	case failCode != lnwire.CodeNone:
+		var b bytes.Buffer
+		if err := lnwire.EncodeFailure(&b, failure, 0); err != nil {
+			log.Errorf("unable to encode failure: %v", err)
+			return
+		}
+		reason = lnwire.OpaqueReason(b.Bytes())
+		isObfuscated = false
+
+	// Otherwise, we'll send back a proper failure message.
+	default:
+		var err error
+		reason, err = obfuscator.InitialObfuscate(failure)
+		if err != nil {
+			log.Errorf("unable to obfuscate error: %v", err)
+			return
+		}
+		isObfuscated = true
+	}
+
+	upddateFail := &lnwire.UpdateFailHTLC{
+		Reason: reason,
+	}
+	failPkt := newFailPacket(
+		l.ShortChanID(), upddateFail, htlc.PaymentHash, htlc.Amount,
+		isObfuscated,
+	)
+
+	go l.cfg.Switch.forward(failPkt)
 }
 
 // HandleChannelUpdate handles the htlc requests as settle/add/fail which sent
-// to us from remote peer we have a channel with.
+// to us from remote peer we have a channel with. Should the link's mailbox be
+// full, this blocks until there's room, applying backpressure to the remote
+// peer.
 //
 // NOTE: Part of the ChannelLink interface.
 func (l *channelLink) HandleChannelUpdate(message lnwire.Message) {
@@ -974,19 +1209,11 @@ func (l *channelLink) processLockedInHtlcs(
 		switch pd.EntryType {
 
 		// A settle for an HTLC we previously forwarded HTLC has been
-		// received. So we'll forward the HTLC to the switch which
-		// will handle propagating the settle to the prior hop.
+		// locked in. The settle was already pipelined to the switch
+		// once received, so all that's left is to notify the overflow
+		// queue that a spare spot has been freed up within the
+		// commitment state.
 		case lnwallet.Settle:
-			settleUpdate := &lnwire.UpdateFufillHTLC{
-				PaymentPreimage: pd.RPreimage,
-			}
-			settlePacket := newSettlePacket(l.ShortChanID(),
-				settleUpdate, pd.RHash, pd.Amount)
-
-			// Add the packet to the batch to be forwarded, and
-			// notify the overflow queue that a spare spot has been
-			// freed up within the commitment state.
-			packetsToForward = append(packetsToForward, settlePacket)
 			l.overflowQueue.release()
 
 		// A failureCode message for a previously forwarded HTLC has been
@@ -1008,7 +1235,11 @@ func (l *channelLink) processLockedInHtlcs(
 			// Add the packet to the batch to be forwarded, and
 			// notify the overflow queue that a spare spot has been
 			// freed up within the commitment state.
-			packetsToForward = append(packetsToForward, failPacket)
+			if !l.hodlActive(hodl.FailIncoming) {
+				packetsToForward = append(
+					packetsToForward, failPacket,
+				)
+			}
 			l.overflowQueue.release()
 
 		// An incoming HTLC add has been full-locked in. As a result we
@@ -1051,7 +1282,7 @@ func (l *channelLink) processLockedInHtlcs(
 			// losing their money entirely.
 			onionReader = bytes.NewReader(onionBlob[:])
 			chanIterator, failureCode := l.cfg.DecodeHopIterator(
-				onionReader, pd.RHash[:],
+				onionReader, pd.RHash[:], pd.Timeout,
 			)
 			if failureCode != lnwire.CodeNone {
 				// If we unable to process the onion blob than
@@ -1064,27 +1295,94 @@ func (l *channelLink) processLockedInHtlcs(
 				continue
 			}
 
+			// If the HTLC is dust, and pushes the dust exposure of
+			// the channel above our limit, then we'll fail it back
+			// rather than risk its value being burned to fees.
+			if err := l.checkDustExposure(pd.Amount, true); err != nil {
+				log.Errorf("rejecting htlc(%x) of %v: %v",
+					pd.RHash[:], pd.Amount, err)
+
+				failure := lnwire.NewTemporaryChannelFailure(nil)
+				l.sendHTLCError(pd.RHash, failure, obfuscator)
+				needUpdate = true
+				continue
+			}
+
 			heightNow := l.bestHeight
 
-			fwdInfo := chanIterator.ForwardingInstructions()
+			payload := chanIterator.HopPayload()
+			fwdInfo := payload.ForwardingInfo()
 			switch fwdInfo.NextHop {
 			case exitHop:
 				// First, we'll check the expiry of the HTLC
 				// itself against, the current block height. If
-				// the timeout is too soon, then we'll reject
-				// the HTLC.
-				if pd.Timeout-expiryGraceDelta <= heightNow {
+				// the timeout is too soon for us to claim the
+				// HTLC on-chain, then we'll reject the HTLC.
+				if pd.Timeout <= heightNow+l.expiryGraceDelta {
 					log.Errorf("htlc(%x) has an expiry "+
 						"that's too soon: expiry=%v, "+
 						"best_height=%v", pd.RHash[:],
 						pd.Timeout, heightNow)
 
-					failure := lnwire.FailFinalIncorrectCltvExpiry{}
-					l.sendHTLCError(pd.RHash, &failure, obfuscator)
+					failure := lnwire.FailFinalExpiryTooSoon{}
+					l.sendHTLCError(pd.RHash, failure, obfuscator)
+					needUpdate = true
+					continue
+				}
+
+				// We'll also ensure that the time-lock of the
+				// hop-payload was computed correctly by the
+				// sender, before even looking up the invoice.
+				// The failure carries the expiry of the HTLC
+				// we were extended.
+				if !l.cfg.DebugHTLC &&
+					fwdInfo.OutgoingCTLV != l.cfg.FwrdingPolicy.TimeLockDelta {
+
+					log.Errorf("Onion payload of incoming "+
+						"htlc(%x) has incorrect time-lock: "+
+						"expected %v, got %v",
+						pd.RHash[:], l.cfg.FwrdingPolicy.TimeLockDelta,
+						fwdInfo.OutgoingCTLV)
+
+					failure := lnwire.NewFinalIncorrectCltvExpiry(pd.Timeout)
+					l.sendHTLCError(pd.RHash, failure, obfuscator)
 					needUpdate = true
 					continue
 				}
 
+				// If the HTLC is a shard of an atomic
+				// multi-path payment, then it doesn't pay an
+				// invoice, and is instead held until the rest
+				// of the payment's shards have arrived.
+				if payload.AMPRecord() != nil {
+					if !l.cfg.DebugHTLC &&
+						pd.Amount < fwdInfo.AmountToForward {
+
+						log.Errorf("rejecting amp shard "+
+							"htlc(%x) due to incorrect "+
+							"amount: expected %v, "+
+							"received %v", pd.RHash[:],
+							fwdInfo.AmountToForward,
+							pd.Amount)
+						failure := lnwire.FailIncorrectPaymentAmount{}
+						l.sendHTLCError(pd.RHash, failure, obfuscator)
+						needUpdate = true
+						continue
+					}
+
+					if l.hodlActive(hodl.ExitSettle) {
+						continue
+					}
+
+					if l.processAMPShard(
+						pd, payload, obfuscator,
+						onionBlob[:],
+					) {
+						needUpdate = true
+					}
+					continue
+				}
+
 				// We're the designated payment destination.
 				// Therefore we attempt to see if we have an
 				// invoice locally which'll allow us to settle
@@ -1100,6 +1398,42 @@ func (l *channelLink) processLockedInHtlcs(
 					continue
 				}
 
+				// Canceled invoices can no longer be paid,
+				// while a hold invoice can only be paid by the
+				// single HTLC it has accepted.
+				switch invoice.Terms.State {
+				case channeldb.ContractCanceled,
+					channeldb.ContractAccepted:
+
+					log.Errorf("rejecting htlc(%x) paying %v "+
+						"invoice", pd.RHash[:],
+						invoice.Terms.State)
+					failure := lnwire.FailUnknownPaymentHash{}
+					l.sendHTLCError(pd.RHash, failure, obfuscator)
+					needUpdate = true
+					continue
+				}
+
+				// If the sender included a payment secret, then
+				// it must match the payment address of the
+				// invoice, otherwise the HTLC may be a probe
+				// by an intermediate node rather than a
+				// payment by the payer the invoice was given
+				// to. Invoices added before payment addresses
+				// were stored can't be paid this way.
+				mpp := payload.MultiPath()
+				if mpp != nil && (invoice.Terms.PaymentAddr == [32]byte{} ||
+					mpp.PaymentSecret != invoice.Terms.PaymentAddr) {
+
+					log.Errorf("rejecting htlc(%x) due to "+
+						"incorrect payment address",
+						pd.RHash[:])
+					failure := lnwire.FailUnknownPaymentHash{}
+					l.sendHTLCError(pd.RHash, failure, obfuscator)
+					needUpdate = true
+					continue
+				}
+
 				// As we're the exit hop, we'll double check
 				// the hop-payload included in the HTLC to
 				// ensure that it was crafted correctly by the
@@ -1120,23 +1454,6 @@ func (l *channelLink) processLockedInHtlcs(
 					continue
 				}
 
-				// We'll also ensure that our time-lock value
-				// has been computed correctly.
-				if !l.cfg.DebugHTLC &&
-					fwdInfo.OutgoingCTLV != l.cfg.FwrdingPolicy.TimeLockDelta {
-
-					log.Errorf("Onion payload of incoming "+
-						"htlc(%x) has incorrect time-lock: "+
-						"expected %v, got %v",
-						pd.RHash[:], l.cfg.FwrdingPolicy.TimeLockDelta,
-						fwdInfo.OutgoingCTLV)
-
-					failure := lnwire.NewFinalIncorrectCltvExpiry(fwdInfo.OutgoingCTLV)
-					l.sendHTLCError(pd.RHash, failure, obfuscator)
-					needUpdate = true
-					continue
-				}
-
 				// If we're not currently in debug mode, and
 				// the extended htlc doesn't meet the value
 				// requested, then we'll fail the htlc.
@@ -1153,7 +1470,67 @@ func (l *channelLink) processLockedInHtlcs(
 					continue
 				}
 
+				if l.hodlActive(hodl.ExitSettle) {
+					continue
+				}
+
+				// If the preimage of the invoice is unknown,
+				// it's a hold invoice, so rather than settling
+				// the HTLC, we'll hold it until the invoice is
+				// either settled or canceled.
 				preimage := invoice.Terms.PaymentPreimage
+				if preimage == channeldb.UnknownPreimage {
+					// The HTLC is persisted before
+					// the invoice is accepted, so
+					// that it's still resolved
+					// should the link be restarted
+					// in the meantime.
+					err := l.persistHodlHtlc(pd, onionBlob[:])
+					if err != nil {
+						log.Errorf("unable to persist "+
+							"held htlc: %v", err)
+						failure := lnwire.FailTemporaryNodeFailure{}
+						l.sendHTLCError(
+							pd.RHash, failure,
+							obfuscator,
+						)
+						needUpdate = true
+						continue
+					}
+
+					htlc := &channeldb.InvoiceHTLC{
+						ChanID:       l.ShortChanID(),
+						HtlcID:       pd.Index,
+						Amt:          pd.Amount,
+						Expiry:       pd.Timeout,
+						AcceptHeight: heightNow,
+					}
+					err = l.cfg.Registry.AcceptInvoice(
+						invoiceHash, htlc,
+						l.notifyHodlEvent,
+					)
+					if err != nil {
+						log.Errorf("unable to accept "+
+							"invoice: %v", err)
+						failure := lnwire.FailUnknownPaymentHash{}
+						l.sendHTLCError(
+							pd.RHash, failure,
+							obfuscator,
+						)
+						needUpdate = true
+						continue
+					}
+
+					log.Debugf("Holding htlc(%x) paying "+
+						"hold invoice", pd.RHash[:])
+
+					l.hodlHtlcs[pd.RHash] = &hodlHtlc{
+						obfuscator: obfuscator,
+						expiry:     pd.Timeout,
+					}
+					continue
+				}
+
 				logIndex, err := l.channel.SettleHTLC(preimage)
 				if err != nil {
 					l.fail("unable to settle htlc: %v", err)
@@ -1162,8 +1539,19 @@ func (l *channelLink) processLockedInHtlcs(
 
 				// Notify the invoiceRegistry of the invoices
 				// we just settled with this latest commitment
-				// update.
-				err = l.cfg.Registry.SettleInvoice(invoiceHash)
+				// update, along with the HTLC which settled
+				// it.
+				htlc := &channeldb.InvoiceHTLC{
+					ChanID:       l.ShortChanID(),
+					HtlcID:       pd.Index,
+					Amt:          pd.Amount,
+					Expiry:       pd.Timeout,
+					AcceptHeight: heightNow,
+					SettleTime:   time.Now(),
+				}
+				err = l.cfg.Registry.SettleInvoice(
+					invoiceHash, htlc,
+				)
 				if err != nil {
 					l.fail("unable to settle invoice: %v", err)
 					return nil
@@ -1185,10 +1573,11 @@ func (l *channelLink) processLockedInHtlcs(
 			default:
 				// We want to avoid forwarding an HTLC which
 				// will expire in the near future, so we'll
-				// reject an HTLC if its expiration time is too
-				// close to the current height.
+				// reject an HTLC if the expiration time of
+				// the outgoing HTLC doesn't leave the grace
+				// period after the current height.
 				timeDelta := l.cfg.FwrdingPolicy.TimeLockDelta
-				if pd.Timeout-timeDelta <= heightNow {
+				if pd.Timeout <= heightNow+timeDelta+l.expiryGraceDelta {
 					log.Errorf("htlc(%x) has an expiry "+
 						"that's too soon: expiry=%v, "+
 						"best_height=%v", pd.RHash[:],
@@ -1234,6 +1623,29 @@ func (l *channelLink) processLockedInHtlcs(
 					continue
 				}
 
+				// Similarly, we'll cancel the HTLC if it
+				// exceeds the maximum HTLC we advertised, if
+				// any.
+				maxHTLC := l.cfg.FwrdingPolicy.MaxHTLC
+				if maxHTLC != 0 && pd.Amount > maxHTLC {
+					log.Errorf("Incoming htlc(%x) is too "+
+						"large: max_htlc=%v, hltc_value=%v",
+						pd.RHash[:], maxHTLC, pd.Amount)
+
+					var failure lnwire.FailureMessage
+					update, err := l.cfg.GetLastChannelUpdate()
+					if err != nil {
+						failure = lnwire.NewTemporaryChannelFailure(nil)
+					} else {
+						failure = lnwire.NewTemporaryChannelFailure(
+							update)
+					}
+
+					l.sendHTLCError(pd.RHash, failure, obfuscator)
+					needUpdate = true
+					continue
+				}
+
 				// Next, using the amount of the incoming HTLC,
 				// we'll calculate the expected fee this
 				// incoming HTLC must carry in order to be
@@ -1332,8 +1744,15 @@ func (l *channelLink) processLockedInHtlcs(
 					continue
 				}
 
+				if l.hodlActive(hodl.AddIncoming) {
+					continue
+				}
+
 				updatePacket := newAddPacket(l.ShortChanID(),
 					fwdInfo.NextHop, addMsg, obfuscator)
+				updatePacket.incomingAmount = pd.Amount
+				updatePacket.incomingTimeout = pd.Timeout
+				updatePacket.incomingOnion = onionBlob[:]
 				packetsToForward = append(packetsToForward, updatePacket)
 			}
 		}
@@ -1352,6 +1771,296 @@ func (l *channelLink) processLockedInHtlcs(
 	return packetsToForward
 }
 
+// hodlActive returns true if the passed hodl flag is active within the link,
+// in which case a warning is logged, as the link is about to hold an HTLC.
+func (l *channelLink) hodlActive(flag hodl.Flag) bool {
+	if !l.cfg.HodlMask.Active(flag) {
+		return false
+	}
+
+	log.Warnf("ChannelLink(%v): %v", l, flag.Warning())
+	return true
+}
+
+// checkDustExposure returns ErrDustExposureExceeded if an HTLC of the passed
+// amount is dust on either commitment of the channel, and the total value of
+// the dust HTLCs on that commitment would exceed the maximum dust exposure.
+// Incoming HTLCs are already within the channel's update log once they're
+// checked, so only the value of an outgoing HTLC is added to the current
+// exposure.
+func (l *channelLink) checkDustExposure(amt lnwire.MilliAtom,
+	incoming bool) error {
+
+	if l.cfg.MaxDustExposure == 0 {
+		return nil
+	}
+
+	localDust, remoteDust := l.channel.HtlcIsDust(amt, incoming)
+	if !localDust && !remoteDust {
+		return nil
+	}
+
+	localExposure, remoteExposure := l.channel.DustExposure()
+	if !incoming {
+		localExposure += amt
+		remoteExposure += amt
+	}
+
+	switch {
+	case localDust && localExposure > l.cfg.MaxDustExposure:
+	case remoteDust && remoteExposure > l.cfg.MaxDustExposure:
+	default:
+		return nil
+	}
+
+	return ErrDustExposureExceeded
+}
+
+// hodlHtlc is an HTLC paying a hold invoice which is held by the link until
+// its invoice is either settled or canceled.
+type hodlHtlc struct {
+	// obfuscator is used to fail the HTLC back, should it be canceled.
+	obfuscator Obfuscator
+
+	// expiry is the absolute expiry height of the HTLC.
+	expiry uint32
+}
+
+// persistHodlHtlc adds the HTLC described by the passed payment descriptor,
+// which is about to be held, to the HodlStore.
+func (l *channelLink) persistHodlHtlc(pd *lnwallet.PaymentDescriptor,
+	onionBlob []byte) error {
+
+	if l.cfg.HodlStore == nil {
+		return nil
+	}
+
+	return l.cfg.HodlStore.AddHodlHtlc(&channeldb.HodlHtlc{
+		ChanID:      l.ShortChanID(),
+		HtlcID:      pd.Index,
+		PaymentHash: pd.RHash,
+		Expiry:      pd.Timeout,
+		OnionBlob:   onionBlob,
+	})
+}
+
+// restoreHodlHtlcs restores the HTLCs which were held by the link before it
+// was restarted, re-registering them with the invoice registry. Should the
+// invoice of a held HTLC have been settled or canceled in the meantime, the
+// HTLC is resolved once the htlcManager goroutine has started. HTLCs which
+// are no longer active within the channel, as they were resolved before the
+// restart, are removed from the HodlStore instead.
+//
+// NOTE: This MUST be called before the htlcManager goroutine is started.
+func (l *channelLink) restoreHodlHtlcs() error {
+	if l.cfg.HodlStore == nil {
+		return nil
+	}
+
+	htlcs, err := l.cfg.HodlStore.FetchHodlHtlcs(l.ShortChanID())
+	if err != nil {
+		return err
+	}
+	if len(htlcs) == 0 {
+		return nil
+	}
+
+	active := make(map[lnwallet.PaymentHash]struct{})
+	for _, htlc := range l.channel.StateSnapshot().Htlcs {
+		if htlc.Incoming {
+			active[htlc.RHash] = struct{}{}
+		}
+	}
+
+	for _, htlc := range htlcs {
+		rHash := lnwallet.PaymentHash(htlc.PaymentHash)
+		if _, ok := active[rHash]; !ok {
+			err := l.cfg.HodlStore.DeleteHodlHtlc(
+				htlc.ChanID, htlc.HtlcID,
+			)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		onionReader := bytes.NewReader(htlc.OnionBlob)
+		obfuscator, failCode := l.cfg.DecodeOnionObfuscator(onionReader)
+		if failCode != lnwire.CodeNone {
+			log.Errorf("unable to decode onion obfuscator of held "+
+				"htlc(%x): %v", rHash[:], failCode)
+			continue
+		}
+
+		log.Debugf("Restoring held htlc(%x)", rHash[:])
+
+		l.hodlHtlcs[rHash] = &hodlHtlc{
+			obfuscator: obfuscator,
+			expiry:     htlc.Expiry,
+		}
+
+		err := l.cfg.Registry.ResumeHodlHtlc(
+			chainhash.Hash(rHash), l.notifyHodlEvent,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// notifyHodlEvent hands the resolution of a held HTLC to the link's main
+// goroutine. It's invoked by the invoice registry once the hold invoice paid
+// by the HTLC is either settled or canceled.
+func (l *channelLink) notifyHodlEvent(event HodlEvent) {
+	select {
+	case l.hodlQueue <- event:
+	case <-l.quit:
+	}
+}
+
+// resolveHodlHtlc settles or fails back the held HTLC described by the passed
+// event, returning true if the HTLC was resolved, and the commitment is to be
+// updated. Events for HTLCs which are no longer held, such as those canceled
+// by the link itself, are ignored.
+//
+// NOTE: This MUST be called from the htlcManager goroutine.
+func (l *channelLink) resolveHodlHtlc(event HodlEvent) bool {
+	htlc, ok := l.hodlHtlcs[event.PaymentHash]
+	if !ok {
+		return false
+	}
+	delete(l.hodlHtlcs, event.PaymentHash)
+
+	if event.Preimage == nil {
+		log.Infof("Hold invoice of htlc(%x) canceled, failing back",
+			event.PaymentHash[:])
+
+		failure := lnwire.FailUnknownPaymentHash{}
+		l.sendHTLCError(event.PaymentHash, failure, htlc.obfuscator)
+		return true
+	}
+
+	// The preimage is added to the preimage cache before it's revealed,
+	// so that the HTLC can still be claimed on-chain should the channel
+	// be force closed.
+	if l.cfg.PreimageCache != nil {
+		err := l.cfg.PreimageCache.AddPreimage(event.Preimage[:])
+		if err != nil {
+			log.Errorf("unable to add preimage of held htlc(%x) "+
+				"to cache: %v", event.PaymentHash[:], err)
+			return false
+		}
+	}
+
+	logIndex, err := l.channel.SettleHTLC(*event.Preimage)
+	if err != nil {
+		log.Errorf("unable to settle held htlc(%x): %v",
+			event.PaymentHash[:], err)
+		return false
+	}
+
+	l.cfg.Peer.SendMessage(&lnwire.UpdateFufillHTLC{
+		ChanID:          l.ChanID(),
+		ID:              logIndex,
+		PaymentPreimage: *event.Preimage,
+	})
+
+	return true
+}
+
+// cancelExpiringHodlHtlcs fails back every held HTLC whose expiry is too close
+// to the current height for us to safely claim it, canceling its invoice. It
+// returns true if any HTLC was failed back, and the commitment is to be
+// updated.
+//
+// NOTE: This MUST be called from the htlcManager goroutine.
+func (l *channelLink) cancelExpiringHodlHtlcs() bool {
+	var canceled bool
+	for rHash, htlc := range l.hodlHtlcs {
+		if htlc.expiry > l.bestHeight+l.expiryGraceDelta {
+			continue
+		}
+
+		log.Infof("Held htlc(%x) expires at height %v, canceling "+
+			"hold invoice at height %v", rHash[:], htlc.expiry,
+			l.bestHeight)
+
+		delete(l.hodlHtlcs, rHash)
+		failure := lnwire.FailUnknownPaymentHash{}
+		l.sendHTLCError(rHash, failure, htlc.obfuscator)
+		canceled = true
+
+		err := l.cfg.Registry.CancelInvoice(chainhash.Hash(rHash))
+		if err != nil {
+			log.Errorf("unable to cancel hold invoice(%x): %v",
+				rHash[:], err)
+		}
+	}
+
+	return canceled
+}
+
+// processAMPShard holds the HTLC described by the passed payment descriptor,
+// which carries a shard of an atomic multi-path payment, handing the shard to
+// the invoice registry. The registry combines the shards of the payment, no
+// matter the link they arrive over, and resolves the HTLC once the payment's
+// set is either settled or canceled. As with HTLCs paying hold invoices, the
+// HTLC is persisted so that it's still resolved should the link be restarted
+// in the meantime. It returns true if the HTLC was failed back, and the
+// commitment is to be updated.
+//
+// NOTE: This MUST be called from the htlcManager goroutine.
+func (l *channelLink) processAMPShard(pd *lnwallet.PaymentDescriptor,
+	payload *Payload, obfuscator Obfuscator, onionBlob []byte) bool {
+
+	record := payload.AMPRecord()
+
+	if err := l.persistHodlHtlc(pd, onionBlob); err != nil {
+		log.Errorf("unable to persist amp shard htlc(%x): %v",
+			pd.RHash[:], err)
+
+		failure := lnwire.FailTemporaryNodeFailure{}
+		l.sendHTLCError(pd.RHash, failure, obfuscator)
+		return true
+	}
+
+	shard := &amp.Shard{
+		Hash:   pd.RHash,
+		Amt:    pd.Amount,
+		Record: record,
+	}
+	htlc := &channeldb.InvoiceHTLC{
+		ChanID:       l.ShortChanID(),
+		HtlcID:       pd.Index,
+		Amt:          pd.Amount,
+		Expiry:       pd.Timeout,
+		AcceptHeight: l.bestHeight,
+	}
+	err := l.cfg.Registry.AcceptAMPShard(
+		shard, payload.MultiPath().TotalMsat, htlc, l.notifyHodlEvent,
+	)
+	if err != nil {
+		log.Errorf("rejecting amp shard htlc(%x) of set %x: %v",
+			pd.RHash[:], record.SetID[:], err)
+
+		failure := lnwire.FailIncorrectPaymentAmount{}
+		l.sendHTLCError(pd.RHash, failure, obfuscator)
+		return true
+	}
+
+	log.Debugf("Holding amp shard htlc(%x) of set %x", pd.RHash[:],
+		record.SetID[:])
+
+	l.hodlHtlcs[pd.RHash] = &hodlHtlc{
+		obfuscator: obfuscator,
+		expiry:     pd.Timeout,
+	}
+
+	return false
+}
+
 // sendHTLCError functions cancels HTLC and send cancel message back to the
 // peer from which HTLC was received.
 func (l *channelLink) sendHTLCError(rHash [32]byte, failure lnwire.FailureMessage,
@@ -1373,6 +2082,8 @@ func (l *channelLink) sendHTLCError(rHash [32]byte, failure lnwire.FailureMessag
 		ID:     index,
 		Reason: reason,
 	})
+
+	l.notifyLinkFail(rHash, true, failure.Code(), "")
 }
 
 // sendMalformedHTLCError helper function which sends the malformed HTLC update
@@ -1391,6 +2102,23 @@ func (l *channelLink) sendMalformedHTLCError(rHash [32]byte, code lnwire.FailCod
 		ShaOnionBlob: sha256.Sum256(onionBlob),
 		FailureCode:  code,
 	})
+
+	l.notifyLinkFail(rHash, true, code, "")
+}
+
+// notifyLinkFail notifies the switch's event bus that the link has failed the
+// HTLC with the passed payment hash.
+func (l *channelLink) notifyLinkFail(rHash [32]byte, incoming bool,
+	code lnwire.FailCode, detail string) {
+
+	l.cfg.Switch.notifyEvent(HtlcLinkFailEvent{
+		PaymentHash:   rHash,
+		ChanID:        l.ShortChanID(),
+		Incoming:      incoming,
+		FailureCode:   code,
+		FailureDetail: detail,
+		Timestamp:     time.Now(),
+	})
 }
 
 // fail helper function which is used to encapsulate the action necessary for
@@ -1400,3 +2128,16 @@ func (l *channelLink) fail(format string, a ...interface{}) {
 	log.Error(reason)
 	l.cfg.Peer.Disconnect(reason)
 }
+
+// failBorked is used in place of fail when the channel's state machine has
+// hit an error it's unable to recover from. The channel is marked as borked
+// before the link is failed, ensuring it won't be loaded into the switch
+// again, and that no further updates to its state are accepted.
+func (l *channelLink) failBorked(format string, a ...interface{}) {
+	if err := l.channel.MarkBorked(); err != nil {
+		log.Errorf("unable to mark ChannelPoint(%v) as borked: %v",
+			l.channel.ChannelPoint(), err)
+	}
+
+	l.fail(format, a...)
+}
@@ -2,6 +2,7 @@ package htlcswitch
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"fmt"
 	"testing"
 	"time"
@@ -14,6 +15,9 @@ import (
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/go-errors/errors"
+	"github.com/lightningnetwork/lnd/amp"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/htlcswitch/hodl"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/roasbeef/btcd/chaincfg/chainhash"
@@ -129,10 +133,24 @@ func TestChannelLinkSingleHopPayment(t *testing.T) {
 
 	// Check that alice invoice was settled and bandwidth of HTLC
 	// links was changed.
-	if !invoice.Terms.Settled {
+	if invoice.Terms.State != channeldb.ContractSettled {
 		t.Fatal("invoice wasn't settled")
 	}
 
+	// The invoice should record the single HTLC which paid it.
+	if len(invoice.Htlcs) != 1 {
+		t.Fatalf("expected 1 invoice htlc, got %v", len(invoice.Htlcs))
+	}
+	htlc := invoice.Htlcs[0]
+	if htlc.ChanID != n.firstBobChannelLink.ShortChanID() {
+		t.Fatalf("expected htlc on channel %v, got %v",
+			n.firstBobChannelLink.ShortChanID(), htlc.ChanID)
+	}
+	if invoice.AmtPaid() != amount {
+		t.Fatalf("expected amount paid %v, got %v", amount,
+			invoice.AmtPaid())
+	}
+
 	if aliceBandwidthBefore-amount != n.aliceChannelLink.Bandwidth() {
 		t.Fatal("alice bandwidth should have descreased on payment " +
 			"amount")
@@ -332,7 +350,7 @@ func TestChannelLinkMultiHopPayment(t *testing.T) {
 
 	// Check that Carol invoice was settled and bandwidth of HTLC
 	// links were changed.
-	if !invoice.Terms.Settled {
+	if invoice.Terms.State != channeldb.ContractSettled {
 		t.Fatal("alice invoice wasn't settled")
 	}
 
@@ -361,6 +379,105 @@ func TestChannelLinkMultiHopPayment(t *testing.T) {
 	}
 }
 
+// TestChannelLinkBatchSize asserts that a link commits its pending updates as
+// soon as its batch fills up, without waiting for its batch interval to
+// elapse.
+func TestChannelLinkBatchSize(t *testing.T) {
+	t.Parallel()
+
+	n := newThreeHopNetwork(t,
+		btcutil.SatoshiPerBitcoin*3,
+		btcutil.SatoshiPerBitcoin*5,
+		testStartingHeight,
+	)
+
+	// With a batch interval far exceeding the duration of the test, the
+	// payment is only able to complete if every update is committed as
+	// soon as it's added.
+	for _, link := range []*channelLink{
+		n.aliceChannelLink, n.firstBobChannelLink,
+		n.secondBobChannelLink, n.carolChannelLink,
+	} {
+		link.cfg.BatchInterval = time.Hour
+		link.cfg.BatchSize = 1
+	}
+
+	if err := n.start(); err != nil {
+		t.Fatal(err)
+	}
+	defer n.stop()
+
+	amount := lnwire.NewMSatFromSatoshis(btcutil.SatoshiPerBitcoin)
+	htlcAmt, totalTimelock, hops := generateHops(amount,
+		testStartingHeight,
+		n.firstBobChannelLink, n.carolChannelLink)
+
+	invoice, err := n.makePayment(n.aliceServer, n.carolServer,
+		n.bobServer.PubKey(), hops, amount, htlcAmt,
+		totalTimelock)
+	if err != nil {
+		t.Fatalf("unable to send payment: %v", err)
+	}
+	if invoice.Terms.State != channeldb.ContractSettled {
+		t.Fatal("carol invoice wasn't settled")
+	}
+}
+
+// TestChannelLinkMailboxFull asserts that adds handed to a link whose mailbox
+// is full are failed back, rather than blocking the switch.
+func TestChannelLinkMailboxFull(t *testing.T) {
+	t.Parallel()
+
+	n := newThreeHopNetwork(t,
+		btcutil.SatoshiPerBitcoin*3,
+		btcutil.SatoshiPerBitcoin*5,
+		testStartingHeight,
+	)
+	if err := n.start(); err != nil {
+		t.Fatal(err)
+	}
+	defer n.stop()
+
+	// We'll create a link with room for a single packet which is never
+	// started, so its mailbox is never drained.
+	cfg := n.aliceChannelLink.cfg
+	cfg.MailboxCapacity = 1
+	link := NewChannelLink(
+		cfg, n.aliceChannelLink.channel, testStartingHeight,
+	).(*channelLink)
+
+	addPacket := func(i byte) *htlcPacket {
+		preimage := [sha256.Size]byte{i}
+		return newAddPacket(
+			n.firstBobChannelLink.ShortChanID(), link.ShortChanID(),
+			&lnwire.UpdateAddHTLC{
+				PaymentHash: sha256.Sum256(preimage[:]),
+				Amount:      1,
+			}, newMockObfuscator(),
+		)
+	}
+
+	link.HandleSwitchPacket(addPacket(1))
+
+	// With the mailbox full, the second add should be failed back rather
+	// than waiting for room within the mailbox.
+	done := make(chan struct{})
+	go func() {
+		link.HandleSwitchPacket(addPacket(2))
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("add to full mailbox blocked")
+	}
+
+	if len(link.downstream) != 1 {
+		t.Fatalf("expected 1 packet within mailbox, got %v",
+			len(link.downstream))
+	}
+}
+
 // TestExitNodeTimelockPayloadMismatch tests that when an exit node receives an
 // incoming HTLC, if the time lock encoded in the payload of the forwarded HTLC
 // doesn't match the expected payment value, then the HTLC will be rejected
@@ -439,6 +556,72 @@ func TestExitNodeAmountPayloadMismatch(t *testing.T) {
 	}
 }
 
+// TestExitNodePaymentAddrMismatch tests that when an exit node receives an
+// incoming HTLC carrying a payment secret, the HTLC is only settled if the
+// secret matches the payment address of the invoice it pays.
+func TestExitNodePaymentAddrMismatch(t *testing.T) {
+	t.Parallel()
+
+	n := newThreeHopNetwork(t,
+		btcutil.SatoshiPerBitcoin*5,
+		btcutil.SatoshiPerBitcoin*5,
+		testStartingHeight,
+	)
+	if err := n.start(); err != nil {
+		t.Fatal(err)
+	}
+	defer n.stop()
+
+	const amount = btcutil.SatoshiPerBitcoin
+	paymentAddr := [32]byte{1}
+
+	// sendPayment pays an invoice with the above payment address from
+	// Alice to Bob, including the passed payment secret within the payload
+	// of the final hop.
+	sendPayment := func(secret [32]byte) error {
+		htlcAmt, htlcExpiry, hops := generateHops(amount,
+			testStartingHeight, n.firstBobChannelLink)
+
+		var blob [lnwire.OnionPacketSize]byte
+		iterator := newMockAMPHopIterator(
+			lnwire.NewMPP(amount, secret), nil, hops...,
+		)
+		if err := iterator.EncodeNextHop(bytes.NewBuffer(blob[0:0])); err != nil {
+			t.Fatalf("unable to generate route: %v", err)
+		}
+
+		invoice, htlc, err := generatePayment(amount, htlcAmt,
+			htlcExpiry, blob)
+		if err != nil {
+			t.Fatalf("unable to generate payment: %v", err)
+		}
+		invoice.Terms.PaymentAddr = paymentAddr
+		if err := n.bobServer.registry.AddInvoice(invoice); err != nil {
+			t.Fatalf("unable to add invoice: %v", err)
+		}
+
+		_, err = n.aliceServer.htlcSwitch.SendHTLC(
+			n.bobServer.PubKey(), htlc, newMockDeobfuscator(),
+		)
+		return err
+	}
+
+	// A payment carrying a secret other than the invoice's payment
+	// address should be rejected.
+	err := sendPayment([32]byte{2})
+	if err == nil {
+		t.Fatalf("payment should have failed but didn't")
+	} else if err.Error() != lnwire.CodeUnknownPaymentHash.String() {
+		t.Fatalf("incorrect error, expected unknown payment hash, "+
+			"instead have: %v", err)
+	}
+
+	// While a payment carrying the payment address should succeed.
+	if err := sendPayment(paymentAddr); err != nil {
+		t.Fatalf("unable to send payment: %v", err)
+	}
+}
+
 // TestLinkForwardMinHTLCPolicyMismatch tests that if a node is an intermediate
 // node in a multi-hop payment, and receives an HTLC which violates its
 // specified multi-hop policy, then the HTLC is rejected.
@@ -568,6 +751,45 @@ func TestLinkForwardMinHTLCPolicyMismatch(t *testing.T) {
 	}
 }
 
+// TestLinkForwardMaxHTLCPolicyMismatch tests that if a node is an intermediate
+// node and receives an HTLC which is _above_ its max HTLC policy, then the
+// HTLC will be rejected.
+func TestLinkForwardMaxHTLCPolicyMismatch(t *testing.T) {
+	t.Parallel()
+
+	n := newThreeHopNetwork(t,
+		btcutil.SatoshiPerBitcoin*5,
+		btcutil.SatoshiPerBitcoin*5,
+		testStartingHeight,
+	)
+	if err := n.start(); err != nil {
+		t.Fatal(err)
+	}
+	defer n.stop()
+
+	amountNoFee := lnwire.NewMSatFromSatoshis(10)
+	htlcAmt, htlcExpiry, hops := generateHops(amountNoFee, testStartingHeight,
+		n.firstBobChannelLink, n.carolChannelLink)
+
+	// We'll limit the HTLCs Bob forwards to just below the amount of the
+	// HTLC we're about to send him.
+	newPolicy := n.globalPolicy
+	newPolicy.MaxHTLC = htlcAmt - 1
+	n.firstBobChannelLink.UpdateForwardingPolicy(newPolicy)
+
+	// As the HTLC now exceeds Bob's max HTLC policy, the payment should
+	// be rejected.
+	_, err := n.makePayment(n.aliceServer, n.carolServer,
+		n.bobServer.PubKey(), hops, amountNoFee, htlcAmt,
+		htlcExpiry)
+	if err == nil {
+		t.Fatalf("payment should have failed but didn't")
+	} else if err.Error() != lnwire.CodeTemporaryChannelFailure.String() {
+		t.Fatalf("incorrect error, expected temporary channel "+
+			"failure, instead have: %v", err)
+	}
+}
+
 // TestUpdateForwardingPolicy tests that the forwarding policy for a link is
 // able to be updated properly. We'll first create an HTLC that meets the
 // specified policy, assert that it succeeds, update the policy (to invalidate
@@ -609,7 +831,7 @@ func TestUpdateForwardingPolicy(t *testing.T) {
 
 	// Carol's invoice should now be shown as settled as the payment
 	// succeeded.
-	if !invoice.Terms.Settled {
+	if invoice.Terms.State != channeldb.ContractSettled {
 		t.Fatal("carol's invoice wasn't settled")
 	}
 	expectedAliceBandwidth := aliceBandwidthBefore - htlcAmt
@@ -691,7 +913,7 @@ func TestChannelLinkMultiHopInsufficientPayment(t *testing.T) {
 
 	// Check that alice invoice wasn't settled and bandwidth of htlc
 	// links hasn't been changed.
-	if invoice.Terms.Settled {
+	if invoice.Terms.State == channeldb.ContractSettled {
 		t.Fatal("alice invoice was settled")
 	}
 
@@ -773,7 +995,7 @@ func TestChannelLinkMultiHopUnknownPaymentHash(t *testing.T) {
 
 	// Check that alice invoice wasn't settled and bandwidth of htlc
 	// links hasn't been changed.
-	if invoice.Terms.Settled {
+	if invoice.Terms.State == channeldb.ContractSettled {
 		t.Fatal("alice invoice was settled")
 	}
 
@@ -840,7 +1062,7 @@ func TestChannelLinkMultiHopUnknownNextHop(t *testing.T) {
 
 	// Check that alice invoice wasn't settled and bandwidth of htlc
 	// links hasn't been changed.
-	if invoice.Terms.Settled {
+	if invoice.Terms.State == channeldb.ContractSettled {
 		t.Fatal("alice invoice was settled")
 	}
 
@@ -908,7 +1130,7 @@ func TestChannelLinkMultiHopDecodeError(t *testing.T) {
 
 	// Check that alice invoice wasn't settled and bandwidth of htlc
 	// links hasn't been changed.
-	if invoice.Terms.Settled {
+	if invoice.Terms.State == channeldb.ContractSettled {
 		t.Fatal("alice invoice was settled")
 	}
 
@@ -968,12 +1190,48 @@ func TestChannelLinkExpiryTooSoonExitNode(t *testing.T) {
 	if err == nil {
 		t.Fatalf("payment should have failed due to a too early " +
 			"time lock value")
-	} else if err.Error() != lnwire.CodeFinalIncorrectCltvExpiry.String() {
+	} else if err.Error() != lnwire.CodeFinalExpiryTooSoon.String() {
 		t.Fatalf("incorrect error, expected final time lock too "+
 			"early, instead have: %v", err)
 	}
 }
 
+// TestChannelLinkExpiryGraceDelta tests that an exit node rejects HTLCs which
+// haven't expired yet, but whose expiry falls within its configured grace
+// period.
+func TestChannelLinkExpiryGraceDelta(t *testing.T) {
+	t.Parallel()
+
+	const startingHeight = 200
+	n := newThreeHopNetwork(t,
+		btcutil.SatoshiPerBitcoin*3,
+		btcutil.SatoshiPerBitcoin*5,
+		startingHeight,
+	)
+
+	// With Bob's grace period widened to 10 blocks, an HTLC expiring 5
+	// blocks from now should be rejected.
+	n.firstBobChannelLink.expiryGraceDelta = 10
+	if err := n.start(); err != nil {
+		t.Fatalf("unable to start three hop network: %v", err)
+	}
+	defer n.stop()
+
+	amount := lnwire.NewMSatFromSatoshis(btcutil.SatoshiPerBitcoin)
+	htlcAmt, totalTimelock, hops := generateHops(amount,
+		startingHeight-1, n.firstBobChannelLink)
+
+	_, err := n.makePayment(n.aliceServer, n.bobServer,
+		n.bobServer.PubKey(), hops, amount, htlcAmt, totalTimelock)
+	if err == nil {
+		t.Fatalf("payment should have failed due to an expiry " +
+			"within the grace period")
+	} else if err.Error() != lnwire.CodeFinalExpiryTooSoon.String() {
+		t.Fatalf("incorrect error, expected final expiry too soon, "+
+			"instead have: %v", err)
+	}
+}
+
 // TestChannelLinkExpiryTooSoonExitNode tests that if we send a multi-hop HTLC,
 // and the time lock is too early for an intermediate node, then they cancel
 // the HTLC back to the sender.
@@ -1113,3 +1371,406 @@ func TestChannelLinkSingleHopMessageOrdering(t *testing.T) {
 		t.Fatalf("unable to make the payment: %v", err)
 	}
 }
+
+// TestChannelLinkHoldInvoice asserts that an HTLC paying a hold invoice is
+// held by the exit node until the invoice is either settled, in which case
+// the HTLC is settled with the revealed preimage, or canceled, in which case
+// the HTLC is failed back.
+func TestChannelLinkHoldInvoice(t *testing.T) {
+	t.Parallel()
+
+	n := newThreeHopNetwork(t,
+		btcutil.SatoshiPerBitcoin*3,
+		btcutil.SatoshiPerBitcoin*5,
+		testStartingHeight,
+	)
+	if err := n.start(); err != nil {
+		t.Fatal(err)
+	}
+	defer n.stop()
+
+	registry := n.bobServer.registry
+
+	// sendHoldPayment adds a hold invoice to Bob's registry, and sends an
+	// HTLC paying it from Alice, returning the preimage of the invoice
+	// along with the channel the result of the payment is delivered on.
+	sendHoldPayment := func() ([32]byte, *channeldb.Invoice, chan error) {
+		amount := lnwire.NewMSatFromSatoshis(btcutil.SatoshiPerBitcoin)
+		htlcAmt, totalTimelock, hops := generateHops(amount,
+			testStartingHeight, n.firstBobChannelLink)
+
+		blob, err := generateRoute(hops...)
+		if err != nil {
+			t.Fatalf("unable to generate route: %v", err)
+		}
+		invoice, htlc, err := generatePayment(amount, htlcAmt,
+			totalTimelock, blob)
+		if err != nil {
+			t.Fatalf("unable to generate payment: %v", err)
+		}
+
+		preimage := invoice.Terms.PaymentPreimage
+		invoice.Terms.PaymentPreimage = channeldb.UnknownPreimage
+		invoice.Terms.PaymentHash = htlc.PaymentHash
+		if err := registry.AddInvoice(invoice); err != nil {
+			t.Fatalf("unable to add invoice: %v", err)
+		}
+
+		errChan := make(chan error, 1)
+		go func() {
+			_, err := n.aliceServer.htlcSwitch.SendHTLC(
+				n.bobServer.PubKey(), htlc,
+				newMockDeobfuscator(),
+			)
+			errChan <- err
+		}()
+
+		return preimage, invoice, errChan
+	}
+
+	// waitForState waits for the passed invoice to reach the given state.
+	waitForState := func(invoice *channeldb.Invoice,
+		state channeldb.ContractState) {
+
+		timeout := time.After(5 * time.Second)
+		for {
+			registry.Lock()
+			invoiceState := invoice.Terms.State
+			registry.Unlock()
+			if invoiceState == state {
+				return
+			}
+
+			select {
+			case <-time.After(10 * time.Millisecond):
+			case <-timeout:
+				t.Fatalf("invoice didn't reach state %v, "+
+					"state is %v", state, invoiceState)
+			}
+		}
+	}
+
+	// The HTLC paying the first invoice should be held once accepted,
+	// until the invoice is settled.
+	preimage, invoice, errChan := sendHoldPayment()
+	waitForState(invoice, channeldb.ContractAccepted)
+
+	select {
+	case err := <-errChan:
+		t.Fatalf("payment completed while held: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	if err := registry.settleHoldInvoice(preimage); err != nil {
+		t.Fatalf("unable to settle hold invoice: %v", err)
+	}
+	select {
+	case err := <-errChan:
+		if err != nil {
+			t.Fatalf("unable to send payment: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("held htlc wasn't settled")
+	}
+
+	// The HTLC paying the second invoice should be failed back once the
+	// invoice is canceled.
+	_, invoice, errChan = sendHoldPayment()
+	waitForState(invoice, channeldb.ContractAccepted)
+
+	rhash := chainhash.Hash(invoice.Terms.PaymentHash)
+	if err := registry.CancelInvoice(rhash); err != nil {
+		t.Fatalf("unable to cancel hold invoice: %v", err)
+	}
+	select {
+	case err := <-errChan:
+		if err == nil {
+			t.Fatalf("payment of canceled invoice succeeded")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("held htlc wasn't failed back")
+	}
+}
+
+// TestChannelLinkRestoreHodlHtlcs asserts that an HTLC held by the exit node
+// is restored once its link is restarted, such that it's still settled once
+// its hold invoice is settled, rather than being left to time out on-chain.
+func TestChannelLinkRestoreHodlHtlcs(t *testing.T) {
+	t.Parallel()
+
+	n := newThreeHopNetwork(t,
+		btcutil.SatoshiPerBitcoin*3,
+		btcutil.SatoshiPerBitcoin*5,
+		testStartingHeight,
+	)
+	store := newMockHodlHtlcStore()
+	n.firstBobChannelLink.cfg.HodlStore = store
+	if err := n.start(); err != nil {
+		t.Fatal(err)
+	}
+	defer n.stop()
+
+	registry := n.bobServer.registry
+
+	// Add a hold invoice to Bob's registry, and send an HTLC paying it
+	// from Alice.
+	amount := lnwire.NewMSatFromSatoshis(btcutil.SatoshiPerBitcoin)
+	htlcAmt, totalTimelock, hops := generateHops(amount,
+		testStartingHeight, n.firstBobChannelLink)
+
+	blob, err := generateRoute(hops...)
+	if err != nil {
+		t.Fatalf("unable to generate route: %v", err)
+	}
+	invoice, htlc, err := generatePayment(amount, htlcAmt, totalTimelock,
+		blob)
+	if err != nil {
+		t.Fatalf("unable to generate payment: %v", err)
+	}
+
+	preimage := invoice.Terms.PaymentPreimage
+	invoice.Terms.PaymentPreimage = channeldb.UnknownPreimage
+	invoice.Terms.PaymentHash = htlc.PaymentHash
+	if err := registry.AddInvoice(invoice); err != nil {
+		t.Fatalf("unable to add invoice: %v", err)
+	}
+
+	errChan := make(chan error, 1)
+	go func() {
+		_, err := n.aliceServer.htlcSwitch.SendHTLC(
+			n.bobServer.PubKey(), htlc, newMockDeobfuscator(),
+		)
+		errChan <- err
+	}()
+
+	// Once accepted, the held HTLC should have been persisted.
+	timeout := time.After(5 * time.Second)
+	for {
+		registry.Lock()
+		state := invoice.Terms.State
+		registry.Unlock()
+		if state == channeldb.ContractAccepted && store.numHtlcs() == 1 {
+			break
+		}
+
+		select {
+		case <-time.After(10 * time.Millisecond):
+		case <-timeout:
+			t.Fatalf("held htlc wasn't persisted, invoice state "+
+				"is %v", state)
+		}
+	}
+
+	// Restart Bob's link, as is done whenever Alice disconnects. The new
+	// link should restore the held HTLC from the store.
+	oldLink := n.firstBobChannelLink
+	if err := n.bobServer.htlcSwitch.RemoveLink(oldLink.ChanID()); err != nil {
+		t.Fatalf("unable to remove link: %v", err)
+	}
+	newLink := NewChannelLink(oldLink.cfg, oldLink.channel,
+		testStartingHeight)
+	if err := n.bobServer.htlcSwitch.AddLink(newLink); err != nil {
+		t.Fatalf("unable to add link: %v", err)
+	}
+
+	// Settling the invoice should settle the HTLC through the new link.
+	if err := registry.settleHoldInvoice(preimage); err != nil {
+		t.Fatalf("unable to settle hold invoice: %v", err)
+	}
+	select {
+	case err := <-errChan:
+		if err != nil {
+			t.Fatalf("unable to send payment: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("restored htlc wasn't settled")
+	}
+}
+
+// TestChannelLinkAMPShards asserts that the exit hop holds the shards of an
+// atomic multi-path payment until all of them have arrived, no matter the link
+// they arrive over, settling them at once, and that every shard of a set is
+// failed back if any of them doesn't pay to the hash derived for it.
+func TestChannelLinkAMPShards(t *testing.T) {
+	t.Parallel()
+
+	n := newThreeHopNetwork(t,
+		btcutil.SatoshiPerBitcoin*3,
+		btcutil.SatoshiPerBitcoin*5,
+		testStartingHeight,
+	)
+	preimageCache := newMockPreimageCache()
+	n.firstBobChannelLink.cfg.PreimageCache = preimageCache
+	n.secondBobChannelLink.cfg.PreimageCache = preimageCache
+	if err := n.start(); err != nil {
+		t.Fatal(err)
+	}
+	defer n.stop()
+
+	total := lnwire.NewMSatFromSatoshis(btcutil.SatoshiPerBitcoin)
+	shardAmt := total / 2
+
+	// sendShard sends an HTLC paying the passed hash from the passed
+	// sender to Bob, over the passed link of Bob's. The HTLC carries the
+	// passed share of the root seed of the set. The channel the result of
+	// the payment is delivered on is returned.
+	sendShard := func(sender *mockServer, bobLink *channelLink,
+		setID [32]byte, share amp.Share, index uint32,
+		hash [32]byte) chan error {
+
+		htlcAmt, totalTimelock, hops := generateHops(shardAmt,
+			testStartingHeight, bobLink)
+
+		var blob [lnwire.OnionPacketSize]byte
+		iterator := newMockAMPHopIterator(
+			lnwire.NewMPP(total, [32]byte{}),
+			lnwire.NewAMP(share, setID, index), hops...,
+		)
+		if err := iterator.EncodeNextHop(bytes.NewBuffer(blob[0:0])); err != nil {
+			t.Fatalf("unable to generate route: %v", err)
+		}
+
+		htlc := &lnwire.UpdateAddHTLC{
+			PaymentHash: hash,
+			Amount:      htlcAmt,
+			Expiry:      totalTimelock,
+			OnionBlob:   blob,
+		}
+
+		errChan := make(chan error, 1)
+		go func() {
+			_, err := sender.htlcSwitch.SendHTLC(
+				n.bobServer.PubKey(), htlc,
+				newMockDeobfuscator(),
+			)
+			errChan <- err
+		}()
+
+		return errChan
+	}
+
+	// The root seed of each set is split into two shares, from which the
+	// hashes of its two shards are derived.
+	share1, share2 := amp.Share{1}, amp.Share{2}
+	root := share1.Xor(share2)
+	child1 := amp.DeriveChild(root, amp.ChildDesc{Share: share1, Index: 0})
+	child2 := amp.DeriveChild(root, amp.ChildDesc{Share: share2, Index: 1})
+
+	// The first shard, sent by Alice, should be held until the second
+	// one arrives from Carol, after which both of them should be settled.
+	setID := [32]byte{1}
+	errChan1 := sendShard(
+		n.aliceServer, n.firstBobChannelLink, setID, share1, 0,
+		child1.Hash,
+	)
+	select {
+	case err := <-errChan1:
+		t.Fatalf("shard resolved before set was complete: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	errChan2 := sendShard(
+		n.carolServer, n.secondBobChannelLink, setID, share2, 1,
+		child2.Hash,
+	)
+	for _, errChan := range []chan error{errChan1, errChan2} {
+		select {
+		case err := <-errChan:
+			if err != nil {
+				t.Fatalf("unable to send shard: %v", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("shard wasn't settled")
+		}
+	}
+
+	// The preimages of both shards should have been added to the
+	// preimage cache, so that they can still be claimed on-chain.
+	for _, child := range []*amp.Child{child1, child2} {
+		preimage, ok := preimageCache.LookupPreimage(child.Hash[:])
+		if !ok {
+			t.Fatalf("preimage of shard %x not cached", child.Hash)
+		}
+		if !bytes.Equal(preimage, child.Preimage[:]) {
+			t.Fatalf("expected preimage %x, got %x",
+				child.Preimage, preimage)
+		}
+	}
+
+	// If the second shard of a set pays to a hash other than the one
+	// derived for it, then both shards should be failed back once the
+	// set is complete.
+	share3, share4 := amp.Share{3}, amp.Share{4}
+	child3 := amp.DeriveChild(
+		share3.Xor(share4), amp.ChildDesc{Share: share3, Index: 0},
+	)
+
+	setID = [32]byte{2}
+	errChan1 = sendShard(
+		n.aliceServer, n.firstBobChannelLink, setID, share3, 0,
+		child3.Hash,
+	)
+	errChan2 = sendShard(
+		n.aliceServer, n.firstBobChannelLink, setID, share4, 1,
+		[32]byte{5},
+	)
+	for _, errChan := range []chan error{errChan1, errChan2} {
+		select {
+		case err := <-errChan:
+			if err == nil {
+				t.Fatalf("shard of mismatched set was settled")
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("shard wasn't failed back")
+		}
+	}
+}
+
+// TestChannelLinkHodlAddIncoming asserts that a link instructed to hold the
+// HTLCs it receives doesn't forward them, leaving the payment unresolved.
+func TestChannelLinkHodlAddIncoming(t *testing.T) {
+	t.Parallel()
+
+	n := newThreeHopNetwork(t,
+		btcutil.SatoshiPerBitcoin*3,
+		btcutil.SatoshiPerBitcoin*5,
+		testStartingHeight,
+	)
+	n.firstBobChannelLink.cfg.HodlMask = hodl.MaskFromFlags(
+		hodl.AddIncoming,
+	)
+	if err := n.start(); err != nil {
+		t.Fatalf("unable to start three hop network: %v", err)
+	}
+	defer n.stop()
+
+	carolBandwidthBefore := n.carolChannelLink.Bandwidth()
+
+	amount := lnwire.NewMSatFromSatoshis(btcutil.SatoshiPerBitcoin)
+	htlcAmt, totalTimelock, hops := generateHops(amount,
+		testStartingHeight,
+		n.firstBobChannelLink, n.carolChannelLink)
+
+	errChan := make(chan error, 1)
+	go func() {
+		_, err := n.makePayment(n.aliceServer, n.carolServer,
+			n.bobServer.PubKey(), hops, amount, htlcAmt,
+			totalTimelock)
+		errChan <- err
+	}()
+
+	// As Bob holds the HTLC once it's locked in, rather than forwarding
+	// it to Carol, the payment should remain unresolved, and Carol's
+	// bandwidth unchanged.
+	select {
+	case err := <-errChan:
+		t.Fatalf("payment shouldn't have been resolved: %v", err)
+	case <-time.After(time.Second):
+	}
+
+	if n.carolChannelLink.Bandwidth() != carolBandwidthBefore {
+		t.Fatalf("htlc was forwarded to carol: expected bandwidth "+
+			"%v, got %v", carolBandwidthBefore,
+			n.carolChannelLink.Bandwidth())
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"sync"
 	"testing"
+	"time"
 
 	"io"
 	"sync/atomic"
@@ -14,6 +15,7 @@ import (
 
 	"github.com/btcsuite/fastsha256"
 	"github.com/go-errors/errors"
+	"github.com/lightningnetwork/lnd/amp"
 	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/lnwallet"
@@ -61,6 +63,7 @@ func newMockServer(t *testing.T, name string) *mockServer {
 			UpdateTopology: func(msg *lnwire.ChannelUpdate) error {
 				return nil
 			},
+			PreimageCache: newMockPreimageCache(),
 		}),
 		recordFuncs: make([]func(lnwire.Message), 0),
 	}
@@ -98,22 +101,55 @@ func (s *mockServer) Start() error {
 	return nil
 }
 
+const (
+	// mockMPPFlag and mockAMPFlag indicate the presence of the records of
+	// a multi-path payment within the route encoded by a mock hop
+	// iterator.
+	mockMPPFlag byte = 1 << iota
+	mockAMPFlag
+)
+
 // mockHopIterator represents the test version of hop iterator which instead
 // of encrypting the path in onion blob just stores the path as a list of hops.
 type mockHopIterator struct {
 	hops []ForwardingInfo
+
+	// mpp and amp are the records of a multi-path payment carried to the
+	// exit hop, if any. The amp record is only set for atomic multi-path
+	// payments.
+	mpp *lnwire.MPP
+	amp *lnwire.AMP
 }
 
 func newMockHopIterator(hops ...ForwardingInfo) HopIterator {
 	return &mockHopIterator{hops: hops}
 }
 
+// newMockAMPHopIterator returns a mock hop iterator which carries the passed
+// records of a multi-path payment shard to the exit hop. The amp record may be
+// nil, in which case the shard isn't part of an atomic multi-path payment.
+func newMockAMPHopIterator(mpp *lnwire.MPP, amp *lnwire.AMP,
+	hops ...ForwardingInfo) HopIterator {
+
+	return &mockHopIterator{hops: hops, mpp: mpp, amp: amp}
+}
+
 func (r *mockHopIterator) ForwardingInstructions() ForwardingInfo {
 	h := r.hops[0]
 	r.hops = r.hops[1:]
 	return h
 }
 
+func (r *mockHopIterator) HopPayload() *Payload {
+	payload := &Payload{FwdInfo: r.ForwardingInstructions()}
+	if payload.FwdInfo.NextHop == exitHop {
+		payload.MPP = r.mpp
+		payload.AMP = r.amp
+	}
+
+	return payload
+}
+
 func (r *mockHopIterator) EncodeNextHop(w io.Writer) error {
 	var hopLength [4]byte
 	binary.BigEndian.PutUint32(hopLength[:], uint32(len(r.hops)))
@@ -128,6 +164,32 @@ func (r *mockHopIterator) EncodeNextHop(w io.Writer) error {
 		}
 	}
 
+	// The multi-path records, if any, follow the hops, preceded by flags
+	// indicating their presence.
+	var (
+		flags  byte
+		fields []interface{}
+	)
+	if r.mpp != nil {
+		flags |= mockMPPFlag
+		fields = append(fields, r.mpp.PaymentSecret, r.mpp.TotalMsat)
+	}
+	if r.amp != nil {
+		flags |= mockAMPFlag
+		fields = append(
+			fields, r.amp.RootShare, r.amp.SetID, r.amp.ChildIndex,
+		)
+	}
+	if _, err := w.Write([]byte{flags}); err != nil {
+		return err
+	}
+
+	for _, field := range fields {
+		if err := binary.Write(w, binary.BigEndian, field); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -184,14 +246,14 @@ func newMockDeobfuscator() Deobfuscator {
 	return &mockDeobfuscator{}
 }
 
-func (o *mockDeobfuscator) Deobfuscate(reason lnwire.OpaqueReason) (lnwire.FailureMessage,
-	error) {
+func (o *mockDeobfuscator) Deobfuscate(reason lnwire.OpaqueReason) (
+	*btcec.PublicKey, lnwire.FailureMessage, error) {
 	r := bytes.NewReader(reason)
 	failure, err := lnwire.DecodeFailure(r, 0)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return failure, nil
+	return nil, failure, nil
 }
 
 var _ Deobfuscator = (*mockDeobfuscator)(nil)
@@ -200,8 +262,8 @@ var _ Deobfuscator = (*mockDeobfuscator)(nil)
 // encoded array of hops.
 type mockIteratorDecoder struct{}
 
-func (p *mockIteratorDecoder) DecodeHopIterator(r io.Reader, meta []byte) (
-	HopIterator, lnwire.FailCode) {
+func (p *mockIteratorDecoder) DecodeHopIterator(r io.Reader, meta []byte,
+	incomingCltv uint32) (HopIterator, lnwire.FailCode) {
 
 	var b [4]byte
 	_, err := r.Read(b[:])
@@ -220,7 +282,33 @@ func (p *mockIteratorDecoder) DecodeHopIterator(r io.Reader, meta []byte) (
 		hops[i] = *f
 	}
 
-	return newMockHopIterator(hops...), lnwire.CodeNone
+	var flags [1]byte
+	if _, err := r.Read(flags[:]); err != nil || flags[0] == 0 {
+		return newMockHopIterator(hops...), lnwire.CodeNone
+	}
+
+	var (
+		mpp    *lnwire.MPP
+		amp    *lnwire.AMP
+		fields []interface{}
+	)
+	if flags[0]&mockMPPFlag != 0 {
+		mpp = &lnwire.MPP{}
+		fields = append(fields, &mpp.PaymentSecret, &mpp.TotalMsat)
+	}
+	if flags[0]&mockAMPFlag != 0 {
+		amp = &lnwire.AMP{}
+		fields = append(
+			fields, &amp.RootShare, &amp.SetID, &amp.ChildIndex,
+		)
+	}
+	for _, field := range fields {
+		if err := binary.Read(r, binary.BigEndian, field); err != nil {
+			return nil, lnwire.CodeTemporaryChannelFailure
+		}
+	}
+
+	return newMockAMPHopIterator(mpp, amp, hops...), lnwire.CodeNone
 }
 
 func (f *ForwardingInfo) decode(r io.Reader) error {
@@ -370,13 +458,13 @@ func (f *mockChannelLink) HandleChannelUpdate(lnwire.Message) {
 func (f *mockChannelLink) UpdateForwardingPolicy(_ ForwardingPolicy) {
 }
 
-func (f *mockChannelLink) Stats() (uint64, lnwire.MilliSatoshi, lnwire.MilliSatoshi) {
+func (f *mockChannelLink) Stats() (uint64, lnwire.MilliAtom, lnwire.MilliAtom) {
 	return 0, 0, 0
 }
 
 func (f *mockChannelLink) ChanID() lnwire.ChannelID           { return f.chanID }
 func (f *mockChannelLink) ShortChanID() lnwire.ShortChannelID { return f.shortChanID }
-func (f *mockChannelLink) Bandwidth() lnwire.MilliSatoshi     { return 99999999 }
+func (f *mockChannelLink) Bandwidth() lnwire.MilliAtom        { return 99999999 }
 func (f *mockChannelLink) Peer() Peer                         { return f.peer }
 func (f *mockChannelLink) Start() error                       { return nil }
 func (f *mockChannelLink) Stop()                              {}
@@ -385,12 +473,18 @@ var _ ChannelLink = (*mockChannelLink)(nil)
 
 type mockInvoiceRegistry struct {
 	sync.Mutex
-	invoices map[chainhash.Hash]*channeldb.Invoice
+	invoices      map[chainhash.Hash]*channeldb.Invoice
+	hodlResolvers map[chainhash.Hash]func(HodlEvent)
+	ampSets       map[[32]byte]*amp.Set
+	ampPreimages  map[chainhash.Hash][32]byte
 }
 
 func newMockRegistry() *mockInvoiceRegistry {
 	return &mockInvoiceRegistry{
-		invoices: make(map[chainhash.Hash]*channeldb.Invoice),
+		invoices:      make(map[chainhash.Hash]*channeldb.Invoice),
+		hodlResolvers: make(map[chainhash.Hash]func(HodlEvent)),
+		ampSets:       make(map[[32]byte]*amp.Set),
+		ampPreimages:  make(map[chainhash.Hash][32]byte),
 	}
 }
 
@@ -406,7 +500,8 @@ func (i *mockInvoiceRegistry) LookupInvoice(rHash chainhash.Hash) (*channeldb.In
 	return invoice, nil
 }
 
-func (i *mockInvoiceRegistry) SettleInvoice(rhash chainhash.Hash) error {
+func (i *mockInvoiceRegistry) SettleInvoice(rhash chainhash.Hash,
+	htlc *channeldb.InvoiceHTLC) error {
 
 	invoice, err := i.LookupInvoice(rhash)
 	if err != nil {
@@ -414,7 +509,10 @@ func (i *mockInvoiceRegistry) SettleInvoice(rhash chainhash.Hash) error {
 	}
 
 	i.Lock()
-	invoice.Terms.Settled = true
+	invoice.Terms.State = channeldb.ContractSettled
+	if htlc != nil {
+		invoice.Htlcs = append(invoice.Htlcs, *htlc)
+	}
 	i.Unlock()
 
 	return nil
@@ -424,13 +522,433 @@ func (i *mockInvoiceRegistry) AddInvoice(invoice *channeldb.Invoice) error {
 	i.Lock()
 	defer i.Unlock()
 
-	rhash := fastsha256.Sum256(invoice.Terms.PaymentPreimage[:])
+	rhash := invoice.Terms.PaymentHash
+	if invoice.Terms.PaymentPreimage != channeldb.UnknownPreimage {
+		rhash = fastsha256.Sum256(invoice.Terms.PaymentPreimage[:])
+	}
 	i.invoices[chainhash.Hash(rhash)] = invoice
 	return nil
 }
 
+func (i *mockInvoiceRegistry) AcceptInvoice(rhash chainhash.Hash,
+	htlc *channeldb.InvoiceHTLC, resolver func(HodlEvent)) error {
+
+	invoice, err := i.LookupInvoice(rhash)
+	if err != nil {
+		return err
+	}
+
+	i.Lock()
+	defer i.Unlock()
+
+	if invoice.Terms.State != channeldb.ContractOpen {
+		return errors.New("mock invoice not open")
+	}
+	invoice.Terms.State = channeldb.ContractAccepted
+	invoice.Htlcs = append(invoice.Htlcs, *htlc)
+	i.hodlResolvers[rhash] = resolver
+
+	return nil
+}
+
+func (i *mockInvoiceRegistry) AcceptAMPShard(shard *amp.Shard,
+	total lnwire.MilliAtom, htlc *channeldb.InvoiceHTLC,
+	resolver func(HodlEvent)) error {
+
+	i.Lock()
+	defer i.Unlock()
+
+	setID := shard.Record.SetID
+	set, ok := i.ampSets[setID]
+	if !ok {
+		set = amp.NewSet(setID, total)
+		i.ampSets[setID] = set
+	}
+	if err := set.AddShard(shard, total); err != nil {
+		return err
+	}
+	i.hodlResolvers[chainhash.Hash(shard.Hash)] = resolver
+
+	if !set.IsComplete() {
+		return nil
+	}
+
+	// Every shard is settled once the set is complete, unless any of them
+	// doesn't pay to the hash derived for it, in which case all of them
+	// are failed back.
+	preimages, err := set.Settle()
+	for idx, s := range set.Shards() {
+		rhash := chainhash.Hash(s.Hash)
+		resolver := i.hodlResolvers[rhash]
+		delete(i.hodlResolvers, rhash)
+
+		event := HodlEvent{PaymentHash: s.Hash}
+		if err == nil {
+			preimage := preimages[idx]
+			i.ampPreimages[rhash] = preimage
+			event.Preimage = &preimage
+		}
+		go resolver(event)
+	}
+
+	return nil
+}
+
+func (i *mockInvoiceRegistry) ResumeHodlHtlc(rhash chainhash.Hash,
+	resolver func(HodlEvent)) error {
+
+	// The HTLC may carry a shard of an AMP set, which is either still
+	// held, or has already been settled.
+	i.Lock()
+	preimage, settled := i.ampPreimages[rhash]
+	_, held := i.hodlResolvers[rhash]
+	if held {
+		i.hodlResolvers[rhash] = resolver
+	}
+	i.Unlock()
+
+	switch {
+	case settled:
+		go resolver(HodlEvent{
+			PaymentHash: rhash,
+			Preimage:    &preimage,
+		})
+		return nil
+
+	case held:
+		return nil
+	}
+
+	invoice, err := i.LookupInvoice(rhash)
+	if err != nil {
+		go resolver(HodlEvent{PaymentHash: rhash})
+		return nil
+	}
+
+	i.Lock()
+	defer i.Unlock()
+
+	switch invoice.Terms.State {
+	case channeldb.ContractAccepted:
+		i.hodlResolvers[rhash] = resolver
+
+	case channeldb.ContractSettled:
+		preimage := invoice.Terms.PaymentPreimage
+		go resolver(HodlEvent{
+			PaymentHash: rhash,
+			Preimage:    &preimage,
+		})
+
+	default:
+		go resolver(HodlEvent{PaymentHash: rhash})
+	}
+
+	return nil
+}
+
+// settleHoldInvoice settles the accepted hold invoice paid to the hash of the
+// passed preimage, resolving the HTLC held for it.
+func (i *mockInvoiceRegistry) settleHoldInvoice(preimage [32]byte) error {
+	rhash := chainhash.Hash(fastsha256.Sum256(preimage[:]))
+	invoice, err := i.LookupInvoice(rhash)
+	if err != nil {
+		return err
+	}
+
+	i.Lock()
+	resolver, ok := i.hodlResolvers[rhash]
+	if !ok {
+		i.Unlock()
+		return errors.New("mock invoice not accepted")
+	}
+	delete(i.hodlResolvers, rhash)
+	invoice.Terms.PaymentPreimage = preimage
+	invoice.Terms.State = channeldb.ContractSettled
+	i.Unlock()
+
+	go resolver(HodlEvent{
+		PaymentHash: rhash,
+		Preimage:    &preimage,
+	})
+
+	return nil
+}
+
+func (i *mockInvoiceRegistry) CancelInvoice(rhash chainhash.Hash) error {
+	invoice, err := i.LookupInvoice(rhash)
+	if err != nil {
+		return err
+	}
+
+	i.Lock()
+	resolver, ok := i.hodlResolvers[rhash]
+	delete(i.hodlResolvers, rhash)
+	invoice.Terms.State = channeldb.ContractCanceled
+	invoice.Htlcs = nil
+	i.Unlock()
+
+	if ok {
+		go resolver(HodlEvent{PaymentHash: rhash})
+	}
+
+	return nil
+}
+
 var _ InvoiceDatabase = (*mockInvoiceRegistry)(nil)
 
+type mockPreimageCache struct {
+	sync.Mutex
+	preimageMap map[[32]byte][]byte
+}
+
+func newMockPreimageCache() *mockPreimageCache {
+	return &mockPreimageCache{
+		preimageMap: make(map[[32]byte][]byte),
+	}
+}
+
+func (m *mockPreimageCache) LookupPreimage(hash []byte) ([]byte, bool) {
+	m.Lock()
+	defer m.Unlock()
+
+	var h [32]byte
+	copy(h[:], hash)
+
+	p, ok := m.preimageMap[h]
+	return p, ok
+}
+
+func (m *mockPreimageCache) AddPreimage(preimage []byte) error {
+	m.Lock()
+	defer m.Unlock()
+
+	m.preimageMap[sha256.Sum256(preimage)] = preimage
+
+	return nil
+}
+
+var _ PreimageCache = (*mockPreimageCache)(nil)
+
+type mockForwardingLog struct {
+	sync.Mutex
+	events []channeldb.ForwardingEvent
+}
+
+func (m *mockForwardingLog) AddForwardingEvents(
+	events []channeldb.ForwardingEvent) error {
+
+	m.Lock()
+	defer m.Unlock()
+
+	m.events = append(m.events, events...)
+
+	return nil
+}
+
+func (m *mockForwardingLog) DeleteForwardingEvents(
+	before time.Time) (int, error) {
+
+	m.Lock()
+	defer m.Unlock()
+
+	var kept []channeldb.ForwardingEvent
+	for _, event := range m.events {
+		if event.Timestamp.Before(before) {
+			continue
+		}
+		kept = append(kept, event)
+	}
+
+	numDeleted := len(m.events) - len(kept)
+	m.events = kept
+
+	return numDeleted, nil
+}
+
+func (m *mockForwardingLog) fetchEvents() []channeldb.ForwardingEvent {
+	m.Lock()
+	defer m.Unlock()
+
+	return append([]channeldb.ForwardingEvent(nil), m.events...)
+}
+
+var _ ForwardingLog = (*mockForwardingLog)(nil)
+
+type mockResolutionStore struct {
+	sync.Mutex
+	nextID      uint64
+	resolutions map[uint64]*channeldb.ResolutionMsg
+}
+
+func newMockResolutionStore() *mockResolutionStore {
+	return &mockResolutionStore{
+		resolutions: make(map[uint64]*channeldb.ResolutionMsg),
+	}
+}
+
+func (m *mockResolutionStore) AddResolutionMsg(
+	msg *channeldb.ResolutionMsg) error {
+
+	m.Lock()
+	defer m.Unlock()
+
+	m.nextID++
+	msg.ID = m.nextID
+	m.resolutions[msg.ID] = msg
+
+	return nil
+}
+
+func (m *mockResolutionStore) FetchResolutionMsgs() (
+	[]*channeldb.ResolutionMsg, error) {
+
+	m.Lock()
+	defer m.Unlock()
+
+	var msgs []*channeldb.ResolutionMsg
+	for id := uint64(1); id <= m.nextID; id++ {
+		if msg, ok := m.resolutions[id]; ok {
+			msgs = append(msgs, msg)
+		}
+	}
+
+	return msgs, nil
+}
+
+func (m *mockResolutionStore) DeleteResolutionMsg(id uint64) error {
+	m.Lock()
+	defer m.Unlock()
+
+	delete(m.resolutions, id)
+
+	return nil
+}
+
+func (m *mockResolutionStore) numPending() int {
+	m.Lock()
+	defer m.Unlock()
+
+	return len(m.resolutions)
+}
+
+var _ ResolutionStore = (*mockResolutionStore)(nil)
+
+type mockCircuitStore struct {
+	sync.Mutex
+	circuits map[[32]byte]*channeldb.PaymentCircuit
+
+	numUpdates  int
+	failUpdates bool
+}
+
+func newMockCircuitStore() *mockCircuitStore {
+	return &mockCircuitStore{
+		circuits: make(map[[32]byte]*channeldb.PaymentCircuit),
+	}
+}
+
+func (m *mockCircuitStore) UpdateCircuits(
+	circuits []*channeldb.PaymentCircuit, deleted [][32]byte) error {
+
+	m.Lock()
+	defer m.Unlock()
+
+	if m.failUpdates {
+		return errors.New("unable to update circuits")
+	}
+	m.numUpdates++
+
+	for _, circuit := range circuits {
+		m.circuits[circuit.PaymentHash] = circuit
+	}
+	for _, hash := range deleted {
+		delete(m.circuits, hash)
+	}
+
+	return nil
+}
+
+func (m *mockCircuitStore) FetchCircuits() ([]*channeldb.PaymentCircuit,
+	error) {
+
+	m.Lock()
+	defer m.Unlock()
+
+	var circuits []*channeldb.PaymentCircuit
+	for _, circuit := range m.circuits {
+		circuits = append(circuits, circuit)
+	}
+
+	return circuits, nil
+}
+
+func (m *mockCircuitStore) numCircuits() int {
+	m.Lock()
+	defer m.Unlock()
+
+	return len(m.circuits)
+}
+
+func (m *mockCircuitStore) updates() int {
+	m.Lock()
+	defer m.Unlock()
+
+	return m.numUpdates
+}
+
+func (m *mockCircuitStore) setFailUpdates(fail bool) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.failUpdates = fail
+}
+
+var _ CircuitStore = (*mockCircuitStore)(nil)
+
+type mockReplayLog struct {
+	sync.Mutex
+	entries map[channeldb.HashPrefix]uint32
+}
+
+func newMockReplayLog() *mockReplayLog {
+	return &mockReplayLog{
+		entries: make(map[channeldb.HashPrefix]uint32),
+	}
+}
+
+func (m *mockReplayLog) PutReplayEntry(hash *channeldb.HashPrefix,
+	cltv uint32) error {
+
+	m.Lock()
+	defer m.Unlock()
+
+	if _, ok := m.entries[*hash]; ok {
+		return channeldb.ErrReplayedPacket
+	}
+	m.entries[*hash] = cltv
+
+	return nil
+}
+
+func (m *mockReplayLog) GarbageCollectReplayLog(height uint32) (uint32,
+	error) {
+
+	m.Lock()
+	defer m.Unlock()
+
+	var numExpired uint32
+	for hash, cltv := range m.entries {
+		if cltv <= height {
+			delete(m.entries, hash)
+			numExpired++
+		}
+	}
+
+	return numExpired, nil
+}
+
+var _ ReplayLog = (*mockReplayLog)(nil)
+
 type mockSigner struct {
 	key *btcec.PrivateKey
 }
@@ -512,3 +1030,65 @@ func (m *mockNotifier) RegisterSpendNtfn(outpoint *wire.OutPoint) (*chainntnfs.S
 		Spend: make(chan *chainntnfs.SpendDetail),
 	}, nil
 }
+
+type mockHodlHtlcStore struct {
+	sync.Mutex
+	htlcs map[lnwire.ShortChannelID]map[uint64]*channeldb.HodlHtlc
+}
+
+func newMockHodlHtlcStore() *mockHodlHtlcStore {
+	return &mockHodlHtlcStore{
+		htlcs: make(map[lnwire.ShortChannelID]map[uint64]*channeldb.HodlHtlc),
+	}
+}
+
+func (m *mockHodlHtlcStore) AddHodlHtlc(htlc *channeldb.HodlHtlc) error {
+	m.Lock()
+	defer m.Unlock()
+
+	if _, ok := m.htlcs[htlc.ChanID]; !ok {
+		m.htlcs[htlc.ChanID] = make(map[uint64]*channeldb.HodlHtlc)
+	}
+	m.htlcs[htlc.ChanID][htlc.HtlcID] = htlc
+
+	return nil
+}
+
+func (m *mockHodlHtlcStore) DeleteHodlHtlc(chanID lnwire.ShortChannelID,
+	htlcID uint64) error {
+
+	m.Lock()
+	defer m.Unlock()
+
+	delete(m.htlcs[chanID], htlcID)
+
+	return nil
+}
+
+func (m *mockHodlHtlcStore) FetchHodlHtlcs(
+	chanID lnwire.ShortChannelID) ([]*channeldb.HodlHtlc, error) {
+
+	m.Lock()
+	defer m.Unlock()
+
+	var htlcs []*channeldb.HodlHtlc
+	for _, htlc := range m.htlcs[chanID] {
+		htlcs = append(htlcs, htlc)
+	}
+
+	return htlcs, nil
+}
+
+func (m *mockHodlHtlcStore) numHtlcs() int {
+	m.Lock()
+	defer m.Unlock()
+
+	var n int
+	for _, htlcs := range m.htlcs {
+		n += len(htlcs)
+	}
+
+	return n
+}
+
+var _ HodlHtlcStore = (*mockHodlHtlcStore)(nil)
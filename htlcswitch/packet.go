@@ -28,7 +28,21 @@ type htlcPacket struct {
 	src lnwire.ShortChannelID
 
 	// amount is the value of the HTLC that is being created or modified.
-	amount lnwire.MilliSatoshi
+	amount lnwire.MilliAtom
+
+	// incomingAmount and incomingTimeout are the value and expiry of the
+	// incoming HTLC which a forwarded add packet originates from.
+	//
+	// NOTE: These fields are initialized only in forwarded add packets.
+	incomingAmount  lnwire.MilliAtom
+	incomingTimeout uint32
+
+	// incomingOnion is the onion packet of the incoming HTLC which a
+	// forwarded add packet originates from. It's persisted within the
+	// packet's circuit.
+	//
+	// NOTE: This field is initialized only in forwarded add packets.
+	incomingOnion []byte
 
 	// htlc lnwire message type of which depends on switch request type.
 	htlc lnwire.Message
@@ -47,6 +61,23 @@ type htlcPacket struct {
 	// TODO(andrew.shvv) revisit after refactoring the way of returning
 	// errors inside the htlcswitch packet.
 	isObfuscated bool
+
+	// localFailure is set for fail packets which originate from an HTLC
+	// that was resolved on-chain rather than failed by the remote party.
+	// As such, the failure hasn't yet been encrypted, so it's wrapped in
+	// the initial layer of encryption rather than an additional one.
+	localFailure lnwire.FailureMessage
+
+	// resolution is set for settle and fail packets which originate from
+	// an HTLC that was resolved on-chain. Such packets are retained until
+	// handled, so their circuit is left in place should the link they're
+	// to be sent back over be down.
+	resolution bool
+
+	// intercepted is set once an add packet has been handed to the
+	// switch's forward interceptor, ensuring it isn't intercepted again
+	// once resumed.
+	intercepted bool
 }
 
 // newInitPacket creates htlc switch add packet which encapsulates the add htlc
@@ -75,7 +106,7 @@ func newAddPacket(src, dest lnwire.ShortChannelID,
 // settle htlc request which should be created and sent back by last hope in
 // htlc path.
 func newSettlePacket(src lnwire.ShortChannelID, htlc *lnwire.UpdateFufillHTLC,
-	payHash [sha256.Size]byte, amount lnwire.MilliSatoshi) *htlcPacket {
+	payHash [sha256.Size]byte, amount lnwire.MilliAtom) *htlcPacket {
 
 	return &htlcPacket{
 		src:     src,
@@ -90,7 +121,7 @@ func newSettlePacket(src lnwire.ShortChannelID, htlc *lnwire.UpdateFufillHTLC,
 // add request if something wrong happened on the path to the final
 // destination.
 func newFailPacket(src lnwire.ShortChannelID, htlc *lnwire.UpdateFailHTLC,
-	payHash [sha256.Size]byte, amount lnwire.MilliSatoshi,
+	payHash [sha256.Size]byte, amount lnwire.MilliAtom,
 	isObfuscated bool) *htlcPacket {
 
 	return &htlcPacket{
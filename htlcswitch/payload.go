@@ -0,0 +1,339 @@
+package htlcswitch
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// TLVRealm is the realm of the frames of hop data which carry the TLV
+	// payload of the final hop. As the onion packet only carries fixed
+	// size hop data, a TLV payload is split across several frames, each
+	// of which is addressed to the final hop itself.
+	TLVRealm = 0x01
+
+	// tlvFrameSize is the number of bytes of a TLV payload carried by each
+	// frame of hop data, spanning its next address, amount, outgoing CLTV
+	// and padding.
+	tlvFrameSize = 32
+)
+
+// PayloadViolation is an enum encapsulating the possible invalid payload
+// violations that can occur when processing or validating a payload.
+type PayloadViolation byte
+
+const (
+	// OmittedViolation indicates that a type was expected to be found in
+	// the payload but was absent.
+	OmittedViolation PayloadViolation = iota
+
+	// IncludedViolation indicates that a type was expected to be omitted
+	// from the payload but was present.
+	IncludedViolation
+)
+
+// String returns a human-readable description of the violation as a verb.
+func (p PayloadViolation) String() string {
+	switch p {
+	case OmittedViolation:
+		return "omitted"
+	case IncludedViolation:
+		return "included"
+	default:
+		return "unknown violation"
+	}
+}
+
+// ErrInvalidPayload is an error returned when a parsed onion payload either
+// included or omitted incorrect records for a particular hop type.
+type ErrInvalidPayload struct {
+	// Type is the type of the record that caused the violation.
+	Type tlv.Type
+
+	// Violation is an enum indicating the type of violation detected in
+	// processing Type.
+	Violation PayloadViolation
+
+	// FinalHop, if true, indicates that the violation is for the final
+	// hop in the route (identified by the absence of a next hop id),
+	// otherwise the violation is for an intermediate hop.
+	FinalHop bool
+}
+
+// Error returns a human-readable description of the invalid payload error.
+func (e ErrInvalidPayload) Error() string {
+	hopType := "intermediate"
+	if e.FinalHop {
+		hopType = "final"
+	}
+
+	return fmt.Sprintf("onion payload for %s hop %v record with type %d",
+		hopType, e.Violation, e.Type)
+}
+
+// Payload encapsulates all information delivered to a hop in an onion
+// payload, which may either be a TLV or a legacy payload. The primary
+// forwarding instructions can be accessed via ForwardingInfo, and additional
+// records can be accessed by other member functions.
+type Payload struct {
+	// FwdInfo holds the basic parameters required for HTLC forwarding,
+	// e.g. amount, cltv, and next hop.
+	FwdInfo ForwardingInfo
+
+	// MPP holds the payment secret and total amount of the payment, which
+	// are only carried by the payload of the final hop. It is nil if the
+	// sender didn't include them.
+	MPP *lnwire.MPP
+
+	// AMP holds the root share, set ID, and child index of a shard of an
+	// atomic multi-path payment, which are only carried by the payload of
+	// the final hop. It is nil if the HTLC isn't part of such a payment.
+	AMP *lnwire.AMP
+}
+
+// NewLegacyPayload builds a Payload from the fixed size hop data used by the
+// legacy, realm 0, onion format.
+func NewLegacyPayload(f *sphinx.HopData) *Payload {
+	nextHop := binary.BigEndian.Uint64(f.NextAddress[:])
+
+	return &Payload{
+		FwdInfo: ForwardingInfo{
+			Network:         BitcoinHop,
+			NextHop:         lnwire.NewShortChanIDFromInt(nextHop),
+			AmountToForward: lnwire.MilliAtom(f.ForwardAmount),
+			OutgoingCTLV:    f.OutgoingCltv,
+		},
+	}
+}
+
+// NewTLVHopData splits the passed TLV payload of the final hop into the
+// frames of hop data which carry it. The payload is prefixed by its length,
+// and the last frame is padded with zeroes.
+func NewTLVHopData(payload []byte) ([]sphinx.HopData, error) {
+	if len(payload) > math.MaxUint16 {
+		return nil, fmt.Errorf("tlv payload of %d bytes exceeds "+
+			"maximum of %d bytes", len(payload), math.MaxUint16)
+	}
+
+	numFrames := (2 + len(payload) + tlvFrameSize - 1) / tlvFrameSize
+	b := make([]byte, numFrames*tlvFrameSize)
+	binary.BigEndian.PutUint16(b[:2], uint16(len(payload)))
+	copy(b[2:], payload)
+
+	frames := make([]sphinx.HopData, numFrames)
+	for i := range frames {
+		f := b[i*tlvFrameSize : (i+1)*tlvFrameSize]
+
+		frames[i].Realm = TLVRealm
+		copy(frames[i].NextAddress[:], f[:8])
+		frames[i].ForwardAmount = binary.BigEndian.Uint64(f[8:16])
+		frames[i].OutgoingCltv = binary.BigEndian.Uint32(f[16:20])
+		copy(frames[i].ExtraBytes[:], f[20:])
+	}
+
+	return frames, nil
+}
+
+// NewPayloadFromTLVHopData reassembles the TLV payload carried by the passed
+// frames of hop data, then parses it into a Payload. As frames are only sent
+// to the final hop, the payload must be that of the final hop.
+func NewPayloadFromTLVHopData(frames []*sphinx.HopData) (*Payload, error) {
+	var b bytes.Buffer
+	for _, f := range frames {
+		if f.Realm != TLVRealm {
+			return nil, fmt.Errorf("expected realm %d for frame "+
+				"of tlv payload, got %d", TLVRealm, f.Realm)
+		}
+
+		var fwdAmt [8]byte
+		var cltv [4]byte
+		binary.BigEndian.PutUint64(fwdAmt[:], f.ForwardAmount)
+		binary.BigEndian.PutUint32(cltv[:], f.OutgoingCltv)
+
+		b.Write(f.NextAddress[:])
+		b.Write(fwdAmt[:])
+		b.Write(cltv[:])
+		b.Write(f.ExtraBytes[:])
+	}
+
+	if b.Len() < 2 {
+		return nil, fmt.Errorf("tlv payload is missing its length")
+	}
+	payloadLen := int(binary.BigEndian.Uint16(b.Next(2)))
+	if payloadLen > b.Len() {
+		return nil, fmt.Errorf("tlv payload of %d bytes exceeds the "+
+			"%d bytes carried by its frames", payloadLen, b.Len())
+	}
+
+	payload, err := NewPayloadFromReader(
+		bytes.NewReader(b.Next(payloadLen)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if payload.FwdInfo.NextHop != exitHop {
+		return nil, ErrInvalidPayload{
+			Type:      lnwire.NextHopOnionType,
+			Violation: IncludedViolation,
+		}
+	}
+
+	return payload, nil
+}
+
+// NewPayloadFromReader builds a new Payload from the passed io.Reader. The
+// reader should correspond to the bytes encapsulated in a TLV onion payload.
+func NewPayloadFromReader(r io.Reader) (*Payload, error) {
+	var (
+		cid  uint64
+		amt  uint64
+		cltv uint32
+		mpp  = &lnwire.MPP{}
+		amp  = &lnwire.AMP{}
+	)
+
+	tlvStream, err := tlv.NewStream(
+		lnwire.NewAmtToFwdRecord(&amt),
+		lnwire.NewLockTimeRecord(&cltv),
+		lnwire.NewNextHopIDRecord(&cid),
+		mpp.Record(),
+		amp.Record(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	parsedTypes, err := tlvStream.DecodeWithParsedTypes(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate whether the sender properly included or omitted tlv records
+	// in accordance with BOLT 04.
+	nextHop := lnwire.NewShortChanIDFromInt(cid)
+	if err := validateParsedTypes(parsedTypes, nextHop); err != nil {
+		return nil, err
+	}
+
+	// If no MPP field was parsed, set the MPP field on the resulting
+	// payload to nil.
+	if _, ok := parsedTypes[lnwire.MPPOnionType]; !ok {
+		mpp = nil
+	}
+
+	// Similarly for the AMP field.
+	if _, ok := parsedTypes[lnwire.AMPOnionType]; !ok {
+		amp = nil
+	}
+
+	return &Payload{
+		FwdInfo: ForwardingInfo{
+			Network:         BitcoinHop,
+			NextHop:         nextHop,
+			AmountToForward: lnwire.MilliAtom(amt),
+			OutgoingCTLV:    cltv,
+		},
+		MPP: mpp,
+		AMP: amp,
+	}, nil
+}
+
+// ForwardingInfo returns the basic parameters required for HTLC forwarding,
+// e.g. amount, cltv, and next hop.
+func (h *Payload) ForwardingInfo() ForwardingInfo {
+	return h.FwdInfo
+}
+
+// MultiPath returns the payment data parsed from the onion payload, or nil if
+// it wasn't included.
+func (h *Payload) MultiPath() *lnwire.MPP {
+	return h.MPP
+}
+
+// AMPRecord returns the shard data of an atomic multi-path payment parsed
+// from the onion payload, or nil if it wasn't included.
+func (h *Payload) AMPRecord() *lnwire.AMP {
+	return h.AMP
+}
+
+// validateParsedTypes checks the types parsed from a hop payload to ensure
+// that the proper fields are either included or omitted. The payload is that
+// of the exit hop if the parsed next hop is the exitHop. The requirements for
+// this method are described in BOLT 04.
+func validateParsedTypes(parsedTypes tlv.TypeSet,
+	nextHop lnwire.ShortChannelID) error {
+
+	isFinalHop := nextHop == exitHop
+
+	_, hasAmt := parsedTypes[lnwire.AmtOnionType]
+	_, hasLockTime := parsedTypes[lnwire.LockTimeOnionType]
+	_, hasNextHop := parsedTypes[lnwire.NextHopOnionType]
+	_, hasMPP := parsedTypes[lnwire.MPPOnionType]
+	_, hasAMP := parsedTypes[lnwire.AMPOnionType]
+
+	switch {
+
+	// All hops must include an amount to forward.
+	case !hasAmt:
+		return ErrInvalidPayload{
+			Type:      lnwire.AmtOnionType,
+			Violation: OmittedViolation,
+			FinalHop:  isFinalHop,
+		}
+
+	// All hops must include a cltv expiry.
+	case !hasLockTime:
+		return ErrInvalidPayload{
+			Type:      lnwire.LockTimeOnionType,
+			Violation: OmittedViolation,
+			FinalHop:  isFinalHop,
+		}
+
+	// The exit hop should omit the next hop id. If nextHop truly is the
+	// exit hop, then the next hop id record won't have been parsed, and
+	// isFinalHop will be true. If a zero next hop id was explicitly
+	// included, then it's also invalid.
+	case isFinalHop && hasNextHop:
+		return ErrInvalidPayload{
+			Type:      lnwire.NextHopOnionType,
+			Violation: IncludedViolation,
+			FinalHop:  true,
+		}
+
+	// Intermediate nodes should never receive MPP fields.
+	case !isFinalHop && hasMPP:
+		return ErrInvalidPayload{
+			Type:      lnwire.MPPOnionType,
+			Violation: IncludedViolation,
+			FinalHop:  isFinalHop,
+		}
+
+	// Intermediate nodes should never receive AMP fields.
+	case !isFinalHop && hasAMP:
+		return ErrInvalidPayload{
+			Type:      lnwire.AMPOnionType,
+			Violation: IncludedViolation,
+			FinalHop:  isFinalHop,
+		}
+
+	// The shards of an AMP payment must carry the total amount of the
+	// payment within the MPP field, as otherwise the receiver is unable
+	// to tell when all of them have arrived.
+	case hasAMP && !hasMPP:
+		return ErrInvalidPayload{
+			Type:      lnwire.MPPOnionType,
+			Violation: OmittedViolation,
+			FinalHop:  isFinalHop,
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,171 @@
+package htlcswitch
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// TestDecodeHopPayloadRecordValidation checks that TLV hop payloads are
+// rejected if they include or omit records as forbidden by BOLT 04.
+func TestDecodeHopPayloadRecordValidation(t *testing.T) {
+	t.Parallel()
+
+	var secret [32]byte
+	secret[0] = 0x01
+	mppRecord := append(
+		append([]byte{0x08, 0x21}, secret[:]...), 0x01,
+	)
+
+	var rootShare, setID [32]byte
+	rootShare[0] = 0x02
+	setID[0] = 0x03
+	ampRecord := append(
+		append(append([]byte{0x0e, 0x41}, rootShare[:]...), setID[:]...),
+		0x04,
+	)
+
+	tests := []struct {
+		name    string
+		payload []byte
+		expErr  error
+		expFwd  ForwardingInfo
+		expMPP  *lnwire.MPP
+		expAMP  *lnwire.AMP
+	}{
+		{
+			name:    "final hop valid",
+			payload: []byte{0x02, 0x00, 0x04, 0x00},
+		},
+		{
+			name: "intermediate hop valid",
+			payload: []byte{0x02, 0x00, 0x04, 0x00, 0x06, 0x08,
+				0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			},
+			expFwd: ForwardingInfo{
+				NextHop: lnwire.NewShortChanIDFromInt(
+					1 << 56,
+				),
+			},
+		},
+		{
+			name:    "final hop no amount",
+			payload: []byte{0x04, 0x00},
+			expErr: ErrInvalidPayload{
+				Type:      lnwire.AmtOnionType,
+				Violation: OmittedViolation,
+				FinalHop:  true,
+			},
+		},
+		{
+			name:    "final hop no expiry",
+			payload: []byte{0x02, 0x00},
+			expErr: ErrInvalidPayload{
+				Type:      lnwire.LockTimeOnionType,
+				Violation: OmittedViolation,
+				FinalHop:  true,
+			},
+		},
+		{
+			name: "final hop with zero next hop",
+			payload: []byte{0x02, 0x00, 0x04, 0x00, 0x06, 0x08,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			},
+			expErr: ErrInvalidPayload{
+				Type:      lnwire.NextHopOnionType,
+				Violation: IncludedViolation,
+				FinalHop:  true,
+			},
+		},
+		{
+			name: "final hop with payment data",
+			payload: append(
+				[]byte{0x02, 0x01, 0x0a, 0x04, 0x01, 0x28},
+				mppRecord...,
+			),
+			expFwd: ForwardingInfo{
+				AmountToForward: 10,
+				OutgoingCTLV:    40,
+			},
+			expMPP: lnwire.NewMPP(1, secret),
+		},
+		{
+			name: "intermediate hop with payment data",
+			payload: append([]byte{0x02, 0x00, 0x04, 0x00, 0x06,
+				0x08, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00}, mppRecord...,
+			),
+			expErr: ErrInvalidPayload{
+				Type:      lnwire.MPPOnionType,
+				Violation: IncludedViolation,
+				FinalHop:  false,
+			},
+		},
+		{
+			name: "final hop with amp data",
+			payload: append(append(
+				[]byte{0x02, 0x01, 0x0a, 0x04, 0x01, 0x28},
+				mppRecord...), ampRecord...,
+			),
+			expFwd: ForwardingInfo{
+				AmountToForward: 10,
+				OutgoingCTLV:    40,
+			},
+			expMPP: lnwire.NewMPP(1, secret),
+			expAMP: lnwire.NewAMP(rootShare, setID, 4),
+		},
+		{
+			name: "final hop with amp data but no payment data",
+			payload: append(
+				[]byte{0x02, 0x01, 0x0a, 0x04, 0x01, 0x28},
+				ampRecord...,
+			),
+			expErr: ErrInvalidPayload{
+				Type:      lnwire.MPPOnionType,
+				Violation: OmittedViolation,
+				FinalHop:  true,
+			},
+		},
+		{
+			name: "intermediate hop with amp data",
+			payload: append(append([]byte{0x02, 0x00, 0x04, 0x00,
+				0x06, 0x08, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00}, mppRecord...), ampRecord...,
+			),
+			expErr: ErrInvalidPayload{
+				Type:      lnwire.MPPOnionType,
+				Violation: IncludedViolation,
+				FinalHop:  false,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		payload, err := NewPayloadFromReader(
+			bytes.NewReader(test.payload),
+		)
+		if !reflect.DeepEqual(err, test.expErr) {
+			t.Fatalf("%s: expected error %v, got %v", test.name,
+				test.expErr, err)
+		}
+		if err != nil {
+			continue
+		}
+
+		if payload.ForwardingInfo() != test.expFwd {
+			t.Fatalf("%s: expected forwarding info %v, got %v",
+				test.name, test.expFwd,
+				payload.ForwardingInfo())
+		}
+		if !reflect.DeepEqual(payload.MultiPath(), test.expMPP) {
+			t.Fatalf("%s: expected payment data %v, got %v",
+				test.name, test.expMPP, payload.MultiPath())
+		}
+		if !reflect.DeepEqual(payload.AMPRecord(), test.expAMP) {
+			t.Fatalf("%s: expected amp data %v, got %v",
+				test.name, test.expAMP, payload.AMPRecord())
+		}
+	}
+}
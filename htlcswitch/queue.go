@@ -102,11 +102,11 @@ func (q *packetQueue) length() int {
 }
 
 // pendingAmount returns the amount of money which is stored in pending queue.
-func (q *packetQueue) pendingAmount() lnwire.MilliSatoshi {
+func (q *packetQueue) pendingAmount() lnwire.MilliAtom {
 	q.Lock()
 	defer q.Unlock()
 
-	var amount lnwire.MilliSatoshi
+	var amount lnwire.MilliAtom
 	for e := q.Front(); e != nil; e = e.Next() {
 		packet := e.Value.(*htlcPacket)
 		htlc := packet.htlc.(*lnwire.UpdateAddHTLC)
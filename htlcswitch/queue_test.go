@@ -16,16 +16,16 @@ func TestWaitingQueueThreadSafety(t *testing.T) {
 
 	q := newWaitingQueue()
 
-	a := make([]lnwire.MilliSatoshi, 1000)
+	a := make([]lnwire.MilliAtom, 1000)
 	for i := 0; i < len(a); i++ {
-		a[i] = lnwire.MilliSatoshi(i)
+		a[i] = lnwire.MilliAtom(i)
 		q.consume(&htlcPacket{
-			amount: lnwire.MilliSatoshi(i),
+			amount: lnwire.MilliAtom(i),
 			htlc:   &lnwire.UpdateAddHTLC{},
 		})
 	}
 
-	var b []lnwire.MilliSatoshi
+	var b []lnwire.MilliAtom
 	for i := 0; i < len(a); i++ {
 		q.release()
 
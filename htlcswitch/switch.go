@@ -2,6 +2,7 @@ package htlcswitch
 
 import (
 	"fmt"
+	"io"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -11,17 +12,56 @@ import (
 	"github.com/davecgh/go-spew/spew"
 
 	"github.com/go-errors/errors"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/contractcourt"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/subscribe"
 	"github.com/roasbeef/btcd/wire"
 	"github.com/roasbeef/btcutil"
 )
 
+const (
+	// fwdEventFlushInterval is the interval at which the forwards settled
+	// by the switch are written to the forwarding log.
+	fwdEventFlushInterval = 15 * time.Second
+
+	// fwdEventRetention is the duration for which forwards are retained
+	// within the forwarding log, which covers the month over which the
+	// fees earned by each channel are reported.
+	fwdEventRetention = 30 * 24 * time.Hour
+
+	// fwdEventPruneInterval is the interval at which the switch removes
+	// the forwards which have outlived fwdEventRetention from the
+	// forwarding log.
+	fwdEventPruneInterval = time.Hour
+
+	// unclaimedResultExpiry is the duration for which the result of a
+	// locally initiated HTLC is retained while nobody claims it. The
+	// preimage of a settle outlives it within the preimage cache.
+	unclaimedResultExpiry = 24 * time.Hour
+
+	// unclaimedResultPruneInterval is the interval at which the switch
+	// discards the unclaimed results which have expired.
+	unclaimedResultPruneInterval = time.Hour
+)
+
 var (
 	// ErrChannelLinkNotFound is used when channel link hasn't been found.
 	ErrChannelLinkNotFound = errors.New("channel link not found")
 
+	// ErrSwitchExiting is returned when waiting on the result of a payment
+	// is aborted because the switch is shutting down. The payment's HTLC
+	// may still be in flight.
+	ErrSwitchExiting = errors.New("switch is shutting down")
+
+	// ErrUnreadableFailureMessage is returned when the failure of a
+	// locally initiated payment can't be decrypted. This means that the
+	// node that generated the failure, or one of the nodes relaying it
+	// back to us, tampered with it, though it's unknown which one.
+	ErrUnreadableFailureMessage = errors.New("unreadable failure message")
+
 	// zeroPreimage is the empty preimage which is returned when we have
 	// some errors.
 	zeroPreimage [sha256.Size]byte
@@ -32,7 +72,7 @@ var (
 // successfully.
 type pendingPayment struct {
 	paymentHash lnwallet.PaymentHash
-	amount      lnwire.MilliSatoshi
+	amount      lnwire.MilliAtom
 
 	preimage chan [sha256.Size]byte
 	err      chan error
@@ -43,6 +83,14 @@ type pendingPayment struct {
 	deobfuscator Deobfuscator
 }
 
+// unclaimedResult is the settle or fail packet of a locally initiated HTLC
+// which arrived while nobody was waiting on it, along with the time at which
+// it arrived.
+type unclaimedResult struct {
+	packet   *htlcPacket
+	received time.Time
+}
+
 // plexPacket encapsulates switch packet and adds error channel to receive
 // error from request handler.
 type plexPacket struct {
@@ -50,6 +98,28 @@ type plexPacket struct {
 	err chan error
 }
 
+// plexBatch encapsulates the switch packets locked in by a single commitment
+// update, along with a channel to receive the error of each packet from the
+// request handler.
+type plexBatch struct {
+	pkts []*htlcPacket
+	errs chan []error
+}
+
+// pendingDispatch is a packet which is to be sent on to a link once the
+// circuit changes made while handling it have been persisted.
+type pendingDispatch struct {
+	// idx is the index of the packet within its batch.
+	idx int
+
+	// send sends the packet on to the link.
+	send func()
+
+	// onFail is called instead of send should the circuit changes fail to
+	// be persisted.
+	onFail func(error)
+}
+
 // ChannelCloseType is a enum which signals the type of channel closure the
 // peer should execute.
 type ChannelCloseType uint8
@@ -74,6 +144,11 @@ type ChanClose struct {
 	// ChanPoint represent the id of the channel which should be closed.
 	ChanPoint *wire.OutPoint
 
+	// DeliveryScript is an optional script our funds should be paid out
+	// to within a cooperative close. If unset, a fresh script is obtained
+	// from the wallet.
+	DeliveryScript []byte
+
 	// Updates is used by request creator to receive the notifications about
 	// execution of the close channel request.
 	Updates chan *lnrpc.CloseStatusUpdate
@@ -95,6 +170,60 @@ type Config struct {
 	//
 	// TODO(roasbeef): remove
 	UpdateTopology func(msg *lnwire.ChannelUpdate) error
+
+	// PreimageCache is a persistent cache of preimages. Any preimage
+	// learned from a settle received downstream is added to the cache
+	// before the settle is propagated back upstream.
+	PreimageCache PreimageCache
+
+	// GlobalForwardLimit bounds the total value of the HTLCs forwarded
+	// across all channels within each interval. HTLCs which would exceed
+	// the limit are failed back with a temporary_channel_failure.
+	GlobalForwardLimit ForwardLimit
+
+	// ChannelForwardLimit bounds the total value of the HTLCs forwarded
+	// over each outgoing channel within each interval.
+	ChannelForwardLimit ForwardLimit
+
+	// PeerForwardLimit bounds the total value of the HTLCs forwarded to
+	// each peer, across all channels with that peer, within each
+	// interval.
+	PeerForwardLimit ForwardLimit
+
+	// EventBus, if non-nil, is the event bus the switch notifies of the
+	// resolution of each HTLC it forwards.
+	EventBus *subscribe.Server
+
+	// ForwardInterceptTimeout is the duration an HTLC handed to the
+	// forward interceptor is held awaiting its resolution, after which
+	// it's failed back to the incoming channel.
+	ForwardInterceptTimeout time.Duration
+
+	// FlapDamping configures the automatic disabling of channels whose
+	// links repeatedly go down or fail HTLCs.
+	FlapDamping FlapDamping
+
+	// UpdateChanStatus advertises the target channel as disabled or
+	// enabled to the network. Channels are only damped if it's set.
+	UpdateChanStatus func(chanID lnwire.ShortChannelID, disabled bool) error
+
+	// FwdEventLog, if non-nil, is the log to which every successfully
+	// forwarded HTLC is written, allowing the fees earned by each channel
+	// to be accounted for.
+	FwdEventLog ForwardingLog
+
+	// ResolutionStore, if non-nil, is the store within which the
+	// resolutions of HTLCs resolved on-chain are retained until they've
+	// been settled or failed back upstream.
+	ResolutionStore ResolutionStore
+
+	// CircuitStore, if non-nil, is the store within which the circuits of
+	// forwarded HTLCs are persisted until they're completed.
+	CircuitStore CircuitStore
+
+	// DecodeOnionObfuscator re-derives the obfuscator of a persisted
+	// circuit from the onion blob of its incoming HTLC.
+	DecodeOnionObfuscator func(r io.Reader) (Obfuscator, lnwire.FailCode)
 }
 
 // Switch is the central messaging bus for all incoming/outgoing HTLCs.
@@ -121,6 +250,13 @@ type Switch struct {
 	pendingPayments map[lnwallet.PaymentHash][]*pendingPayment
 	pendingMutex    sync.RWMutex
 
+	// unclaimedResults holds the settle and fail packets of locally
+	// initiated HTLCs which arrived while no subsystem was waiting on
+	// them, e.g. after a restart. They're handed over once the result is
+	// queried through GetPaymentResult, or discarded once they expire.
+	// It's guarded by pendingMutex.
+	unclaimedResults map[lnwallet.PaymentHash][]*unclaimedResult
+
 	// circuits is storage for payment circuits which are used to
 	// forward the settle/fail htlc updates back to the add htlc initiator.
 	circuits *circuitMap
@@ -141,6 +277,31 @@ type Switch struct {
 	// channels that the switch maintains iwht that peer.
 	interfaceIndex map[[33]byte]map[ChannelLink]struct{}
 
+	// throttle enforces the configured limits on the value of the HTLCs
+	// forwarded by the switch.
+	throttle *forwardThrottle
+
+	// damper tracks the flaps and failures of the links of each channel,
+	// deciding when they should be disabled and re-enabled.
+	damper *flapDamper
+
+	// interceptor, if non-nil, is handed every HTLC the switch is about to
+	// forward, which is held until the interceptor resolves it.
+	interceptor ForwardInterceptor
+
+	// nextInterceptID is the ID assigned to the last intercepted forward.
+	nextInterceptID uint64
+
+	// resolutionMtx serializes the handling of the resolutions of HTLCs
+	// resolved on-chain, ensuring each is only handled once even as
+	// they're replayed.
+	resolutionMtx sync.Mutex
+
+	// pendingFwdEvents are the forwards settled since the forwarding log
+	// was last written to. They're batched in order to avoid a database
+	// write for every settled HTLC.
+	pendingFwdEvents []channeldb.ForwardingEvent
+
 	// htlcPlex is the channel which all connected links use to coordinate
 	// the setup/teardown of Sphinx (onion routing) payment circuits.
 	// Active links forward any add/settle messages over this channel each
@@ -148,6 +309,21 @@ type Switch struct {
 	// in.
 	htlcPlex chan *plexPacket
 
+	// htlcPlexBatch is the channel over which links forward all the
+	// add/settle/fail messages locked in by a single state transition at
+	// once, allowing the circuits they open and close to be persisted
+	// within a single database transaction.
+	htlcPlexBatch chan *plexBatch
+
+	// circuitBatch, if non-nil, collects the circuit changes made while
+	// handling the current batch of packets. It's only accessed by the
+	// htlcForwarder goroutine.
+	circuitBatch *circuitBatch
+
+	// pendingDispatches are the packets handled within the current batch
+	// which are to be sent on once the batch's circuits are persisted.
+	pendingDispatches []*pendingDispatch
+
 	// chanCloseRequests is used to transfer the channel close request to
 	// the channel close handler.
 	chanCloseRequests chan *ChanClose
@@ -159,14 +335,25 @@ type Switch struct {
 
 // New creates the new instance of htlc switch.
 func New(cfg Config) *Switch {
+	// Without a way to advertise the status of channels, there's no
+	// point in tracking their flaps.
+	damping := cfg.FlapDamping
+	if cfg.UpdateChanStatus == nil {
+		damping = FlapDamping{}
+	}
+
 	return &Switch{
 		cfg:               &cfg,
-		circuits:          newCircuitMap(),
+		circuits:          newCircuitMap(cfg.CircuitStore),
 		linkIndex:         make(map[lnwire.ChannelID]ChannelLink),
 		forwardingIndex:   make(map[lnwire.ShortChannelID]ChannelLink),
 		interfaceIndex:    make(map[[33]byte]map[ChannelLink]struct{}),
+		throttle:          newForwardThrottle(&cfg),
+		damper:            newFlapDamper(damping),
 		pendingPayments:   make(map[lnwallet.PaymentHash][]*pendingPayment),
+		unclaimedResults:  make(map[lnwallet.PaymentHash][]*unclaimedResult),
 		htlcPlex:          make(chan *plexPacket),
+		htlcPlexBatch:     make(chan *plexBatch),
 		chanCloseRequests: make(chan *ChanClose),
 		linkControl:       make(chan interface{}),
 		quit:              make(chan struct{}),
@@ -202,6 +389,59 @@ func (s *Switch) SendHTLC(nextNode [33]byte, htlc *lnwire.UpdateAddHTLC,
 		return zeroPreimage, err
 	}
 
+	return s.waitForResult(payment)
+}
+
+// GetPaymentResult blocks until the result of a previously sent HTLC paying
+// to the given hash and amount is known, returning the preimage if the HTLC
+// was settled, or the deobfuscated error if it failed. Unlike SendHTLC, no
+// new HTLC is sent, making this suitable for retrieving the outcome of HTLCs
+// which were still in flight when the daemon was restarted.
+func (s *Switch) GetPaymentResult(paymentHash lnwallet.PaymentHash,
+	amount lnwire.MilliAtom, deobfuscator Deobfuscator) ([sha256.Size]byte,
+	error) {
+
+	// If the HTLC was settled before we were restarted, then the preimage
+	// will have been committed to the cache, so there's nothing left to
+	// wait for.
+	if p, ok := s.cfg.PreimageCache.LookupPreimage(paymentHash[:]); ok {
+		var preimage [sha256.Size]byte
+		copy(preimage[:], p)
+		return preimage, nil
+	}
+
+	payment := &pendingPayment{
+		err:          make(chan error, 1),
+		preimage:     make(chan [sha256.Size]byte, 1),
+		paymentHash:  paymentHash,
+		amount:       amount,
+		deobfuscator: deobfuscator,
+	}
+
+	// We'll register the payment and claim any result which already
+	// arrived for it atomically, such that a result arriving in the
+	// meantime can't be missed.
+	s.pendingMutex.Lock()
+	s.pendingPayments[paymentHash] = append(
+		s.pendingPayments[paymentHash], payment)
+	packet := s.claimResult(amount, paymentHash)
+	s.pendingMutex.Unlock()
+
+	if packet != nil {
+		if err := s.handleLocalDispatch(payment, packet); err != nil {
+			s.removePendingPayment(amount, paymentHash)
+			return zeroPreimage, err
+		}
+	}
+
+	return s.waitForResult(payment)
+}
+
+// waitForResult blocks until the result of the pending payment is known, or
+// the switch shuts down.
+func (s *Switch) waitForResult(payment *pendingPayment) ([sha256.Size]byte,
+	error) {
+
 	// Returns channels so that other subsystem might wait/skip the
 	// waiting of handling of payment.
 	var preimage [sha256.Size]byte
@@ -211,19 +451,133 @@ func (s *Switch) SendHTLC(nextNode [33]byte, htlc *lnwire.UpdateAddHTLC,
 	case e := <-payment.err:
 		err = e
 	case <-s.quit:
-		return zeroPreimage, errors.New("switch is shutting down")
+		return zeroPreimage, ErrSwitchExiting
 	}
 
 	select {
 	case p := <-payment.preimage:
 		preimage = p
 	case <-s.quit:
-		return zeroPreimage, errors.New("switch is shutting down")
+		return zeroPreimage, ErrSwitchExiting
 	}
 
 	return preimage, err
 }
 
+// ProcessContractResolution is called by the chain arbitrator once an
+// outgoing HTLC of a channel which has gone to chain has been resolved. The
+// resolution is converted into a settle or fail packet, and forwarded back to
+// the source of the HTLC as if it was received from the channel's link. The
+// resolution is persisted beforehand, and only removed once handled, so should
+// we restart, or should the source link be down, it's replayed later on.
+func (s *Switch) ProcessContractResolution(msg contractcourt.ResolutionMsg) error {
+	resolution := &channeldb.ResolutionMsg{
+		SourceChan: msg.SourceChan,
+		PayHash:    msg.PayHash,
+		Amt:        msg.Amt,
+		Failure:    msg.Failure,
+		PreImage:   msg.PreImage,
+	}
+
+	s.resolutionMtx.Lock()
+	defer s.resolutionMtx.Unlock()
+
+	if s.cfg.ResolutionStore == nil {
+		return s.handleResolution(resolution)
+	}
+
+	if err := s.cfg.ResolutionStore.AddResolutionMsg(resolution); err != nil {
+		return err
+	}
+
+	// As the resolution has been persisted, it will be replayed should we
+	// be unable to handle it now.
+	if err := s.handleResolution(resolution); err != nil {
+		log.Warnf("Unable to handle resolution for %x, will retry: %v",
+			msg.PayHash[:], err)
+	}
+
+	return nil
+}
+
+// handleResolution forwards the settle or fail packet of the passed resolution
+// back to the source of the HTLC, deleting the resolution from the resolution
+// store once handled.
+//
+// NOTE: This method MUST be called with the resolutionMtx held.
+func (s *Switch) handleResolution(res *channeldb.ResolutionMsg) error {
+	var packet *htlcPacket
+	switch {
+	// If the remote party claimed the HTLC on-chain, then we've learned
+	// its preimage, so the HTLC can be settled upstream.
+	case res.PreImage != nil:
+		packet = newSettlePacket(res.SourceChan, &lnwire.UpdateFufillHTLC{
+			PaymentPreimage: *res.PreImage,
+		}, res.PayHash, res.Amt)
+
+	// Otherwise, the HTLC was either timed out on-chain or trimmed from
+	// the commitment transaction, so it's failed upstream.
+	case res.Failure != nil:
+		packet = newFailPacket(res.SourceChan, &lnwire.UpdateFailHTLC{},
+			res.PayHash, res.Amt, false)
+		packet.localFailure = res.Failure
+
+	default:
+		return errors.Errorf("resolution for %x has neither a preimage "+
+			"nor a failure", res.PayHash[:])
+	}
+	packet.resolution = true
+
+	if err := s.forward(packet); err != nil {
+		return err
+	}
+
+	if s.cfg.ResolutionStore != nil {
+		err := s.cfg.ResolutionStore.DeleteResolutionMsg(res.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Only once the resolution has been deleted is the circuit of the
+	// HTLC closed, ensuring the resolution can always be matched with it
+	// should it be replayed. A locally initiated HTLC has no circuit.
+	if _, err := s.circuits.remove(res.PayHash, nil); err != nil {
+		log.Debugf("No circuit to close for resolution of %x",
+			res.PayHash[:])
+	}
+
+	return nil
+}
+
+// replayResolutions attempts to handle every resolution which is yet to be
+// handled, as we restarted, or as the source link was down, before it could
+// be.
+//
+// NOTE: This MUST be run as a goroutine.
+func (s *Switch) replayResolutions() {
+	defer s.wg.Done()
+
+	s.resolutionMtx.Lock()
+	defer s.resolutionMtx.Unlock()
+
+	resolutions, err := s.cfg.ResolutionStore.FetchResolutionMsgs()
+	if err != nil {
+		log.Errorf("Unable to fetch resolutions: %v", err)
+		return
+	}
+
+	for _, res := range resolutions {
+		log.Debugf("Replaying resolution for %x from %v", res.PayHash[:],
+			res.SourceChan)
+
+		if err := s.handleResolution(res); err != nil {
+			log.Debugf("Unable to replay resolution for %x: %v",
+				res.PayHash[:], err)
+		}
+	}
+}
+
 // UpdateForwardingPolicies sends a message to the switch to update the
 // forwarding policies for the set of target channels. If the set of targeted
 // channels is nil, then the forwarding policies for all active channels with
@@ -320,6 +674,139 @@ func (s *Switch) forward(packet *htlcPacket) error {
 	}
 }
 
+// routePacket interprets the passed packet concretely, then either forwards it
+// along, or interprets a return packet to a locally initialized one.
+func (s *Switch) routePacket(packet *htlcPacket) error {
+	var (
+		paymentHash lnwallet.PaymentHash
+		amount      lnwire.MilliAtom
+	)
+
+	// Only three types of message should be forwarded: add, fails, and
+	// settles. Anything else is an error.
+	switch m := packet.htlc.(type) {
+	case *lnwire.UpdateAddHTLC:
+		paymentHash = m.PaymentHash
+		amount = m.Amount
+	case *lnwire.UpdateFufillHTLC, *lnwire.UpdateFailHTLC:
+		paymentHash = packet.payHash
+		amount = packet.amount
+	default:
+		return errors.New("wrong type of update")
+	}
+
+	// If we can locate this packet in our local records, then this means
+	// a local sub-system initiated it. Otherwise, this is just a packet to
+	// be forwarded, so we'll treat it as so.
+	//
+	// TODO(roasbeef): can fast path this
+	payment, err := s.findPayment(amount, paymentHash)
+	switch {
+	case err == nil:
+		return s.handleLocalDispatch(payment, packet)
+
+	// If a forward interceptor has been registered, then the HTLC is held
+	// until the interceptor resolves it.
+	case s.shouldIntercept(packet):
+		return s.interceptForward(packet)
+
+	default:
+		return s.handlePacketForward(packet)
+	}
+}
+
+// forwardBatch is used by channel links in order to forward all the updates
+// locked in by a single state transition at once. The circuits opened and
+// closed by the updates are persisted within a single database transaction,
+// rather than one per update. The error of each packet is returned in the
+// order the packets were passed.
+func (s *Switch) forwardBatch(packets []*htlcPacket) []error {
+	command := &plexBatch{
+		pkts: packets,
+		errs: make(chan []error, 1),
+	}
+
+	stopped := func() []error {
+		errs := make([]error, len(packets))
+		for i := range errs {
+			errs[i] = errors.New("Htlc Switch was stopped")
+		}
+		return errs
+	}
+
+	select {
+	case s.htlcPlexBatch <- command:
+	case <-s.quit:
+		return stopped()
+	}
+
+	select {
+	case errs := <-command.errs:
+		return errs
+	case <-s.quit:
+		return stopped()
+	}
+}
+
+// handlePacketBatch routes each of the passed packets, collecting the changes
+// they make to the circuits into a single batch. Packets which depend upon
+// those changes are only sent on once the batch has been persisted. Should it
+// fail to be persisted, then the adds are failed back, while the settles and
+// fails are dropped as their circuits are restored.
+//
+// NOTE: This MUST be called from the htlcForwarder goroutine.
+func (s *Switch) handlePacketBatch(packets []*htlcPacket) []error {
+	batch := newCircuitBatch()
+	s.circuitBatch = batch
+
+	errs := make([]error, len(packets))
+	for i, packet := range packets {
+		numPending := len(s.pendingDispatches)
+		errs[i] = s.routePacket(packet)
+		for _, dispatch := range s.pendingDispatches[numPending:] {
+			dispatch.idx = i
+		}
+	}
+
+	dispatches := s.pendingDispatches
+	s.circuitBatch = nil
+	s.pendingDispatches = nil
+
+	if err := s.circuits.commitBatch(batch); err != nil {
+		err = errors.Errorf("unable to write circuits: %v", err)
+		log.Error(err)
+
+		for _, dispatch := range dispatches {
+			dispatch.onFail(err)
+			errs[dispatch.idx] = err
+		}
+
+		return errs
+	}
+
+	for _, dispatch := range dispatches {
+		dispatch.send()
+	}
+
+	return errs
+}
+
+// dispatch sends a packet on to a link by calling send once the circuit
+// changes made while handling the packet have been persisted. Outside of a
+// batch they already have been, so send is called right away. Otherwise,
+// onFail is called instead should the batch fail to be persisted.
+func (s *Switch) dispatch(send func(), onFail func(error)) {
+	if s.circuitBatch == nil {
+		send()
+		return
+	}
+
+	s.pendingDispatches = append(s.pendingDispatches, &pendingDispatch{
+		send:   send,
+		onFail: onFail,
+	})
+}
+
 // handleLocalDispatch is used at the start/end of the htlc update life
 // cycle. At the start (1) it is used to send the htlc to the channel link
 // without creation of circuit. At the end (2) it is used to notify the user
@@ -344,7 +831,10 @@ func (s *Switch) handleLocalDispatch(payment *pendingPayment, packet *htlcPacket
 		if err != nil {
 			log.Errorf("unable to find links by "+
 				"destination %v", err)
-			return errors.New(lnwire.CodeUnknownNextPeer)
+			return &ForwardingError{
+				FailureCode:  lnwire.CodeUnknownNextPeer,
+				LocalFailure: true,
+			}
 		}
 
 		// Try to find destination channel link with appropriate
@@ -363,7 +853,10 @@ func (s *Switch) handleLocalDispatch(payment *pendingPayment, packet *htlcPacket
 		if destination == nil {
 			log.Errorf("unable to find appropriate channel link "+
 				"insufficient capacity, need %v", htlc.Amount)
-			return errors.New(lnwire.CodeTemporaryChannelFailure)
+			return &ForwardingError{
+				FailureCode:  lnwire.CodeTemporaryChannelFailure,
+				LocalFailure: true,
+			}
 		}
 
 		// Send the packet to the destination channel link which
@@ -374,6 +867,15 @@ func (s *Switch) handleLocalDispatch(payment *pendingPayment, packet *htlcPacket
 	// We've just received a settle update which means we can finalize
 	// the user payment and return successful response.
 	case *lnwire.UpdateFufillHTLC:
+		// We'll commit the preimage to disk before notifying the
+		// user, such that the payment can still be resolved should
+		// we be restarted before its outcome is recorded.
+		err := s.cfg.PreimageCache.AddPreimage(htlc.PaymentPreimage[:])
+		if err != nil {
+			log.Errorf("unable to add preimage for payment "+
+				"hash %x: %v", payment.paymentHash[:], err)
+		}
+
 		// Notify the user that his payment was
 		// successfully proceed.
 		payment.err <- nil
@@ -385,55 +887,75 @@ func (s *Switch) handleLocalDispatch(payment *pendingPayment, packet *htlcPacket
 	case *lnwire.UpdateFailHTLC:
 		var userErr error
 
-		// We'll attempt to fully decrypt the onion encrypted error. If
-		// we're unable to then we'll bail early.
-		failure, err := payment.deobfuscator.Deobfuscate(htlc.Reason)
-		if err != nil {
-			userErr = errors.Errorf("unable to de-obfuscate "+
-				"onion failure, htlc with hash(%v): %v",
-				payment.paymentHash[:], err)
-			log.Error(userErr)
-		} else {
-			// Process payment failure by updating the lightning
-			// network topology by using router subsystem handler.
-			var update *lnwire.ChannelUpdate
-
-			// Only a few error message actually contain a channel
-			// update message, so we'll filter out for those that
-			// do.
-			switch failure := failure.(type) {
-			case *lnwire.FailTemporaryChannelFailure:
-				update = failure.Update
-			case *lnwire.FailAmountBelowMinimum:
-				update = &failure.Update
-			case *lnwire.FailFeeInsufficient:
-				update = &failure.Update
-			case *lnwire.FailIncorrectCltvExpiry:
-				update = &failure.Update
-			case *lnwire.FailExpiryTooSoon:
-				update = &failure.Update
-			case *lnwire.FailChannelDisabled:
-				update = &failure.Update
+		// Failures resulting from an on-chain resolution of the HTLC
+		// were never encrypted, so they're returned as is.
+		if packet.localFailure != nil {
+			userErr = &ForwardingError{
+				FailureCode:    packet.localFailure.Code(),
+				LocalFailure:   true,
+				FailureMessage: packet.localFailure,
 			}
+		} else {
+			// We'll attempt to fully decrypt the onion encrypted
+			// error. If we're unable to then we'll bail early.
+			source, failure, err := payment.deobfuscator.Deobfuscate(
+				htlc.Reason,
+			)
+			if err != nil {
+				log.Errorf("unable to de-obfuscate onion "+
+					"failure, htlc with hash(%x): %v",
+					payment.paymentHash[:], err)
+				userErr = ErrUnreadableFailureMessage
+			} else {
+				// Process payment failure by updating the
+				// lightning network topology by using router
+				// subsystem handler.
+				var update *lnwire.ChannelUpdate
+
+				// Only a few error message actually contain a
+				// channel update message, so we'll filter out
+				// for those that do.
+				switch failure := failure.(type) {
+				case *lnwire.FailTemporaryChannelFailure:
+					update = failure.Update
+				case *lnwire.FailAmountBelowMinimum:
+					update = &failure.Update
+				case *lnwire.FailFeeInsufficient:
+					update = &failure.Update
+				case *lnwire.FailIncorrectCltvExpiry:
+					update = &failure.Update
+				case *lnwire.FailExpiryTooSoon:
+					update = &failure.Update
+				case *lnwire.FailChannelDisabled:
+					update = &failure.Update
+				}
 
-			// If we've been sent an error that includes an update,
-			// then we'll apply it to the local graph.
-			//
-			// TODO(roasbeef): instead, make all onion errors the
-			// error interface, and handle this within the router.
-			// Will allow us more flexibility w.r.t how we handle
-			// the error.
-			if update != nil {
-				log.Infof("Received payment failure(%v), "+
-					"applying lightning network topology update",
-					failure.Code())
-
-				if err := s.cfg.UpdateTopology(update); err != nil {
-					log.Errorf("unable to update topology: %v", err)
+				// If we've been sent an error that includes an
+				// update, then we'll apply it to the local
+				// graph.
+				//
+				// TODO(roasbeef): instead, make all onion
+				// errors the error interface, and handle this
+				// within the router. Will allow us more
+				// flexibility w.r.t how we handle the error.
+				if update != nil {
+					log.Infof("Received payment failure(%v), "+
+						"applying lightning network "+
+						"topology update", failure.Code())
+
+					err := s.cfg.UpdateTopology(update)
+					if err != nil {
+						log.Errorf("unable to update "+
+							"topology: %v", err)
+					}
 				}
-			}
 
-			userErr = errors.New(failure.Code())
+				userErr = &ForwardingError{
+					FailureCode:    failure.Code(),
+					ErrorSource:    source,
+					FailureMessage: failure,
+				}
+			}
 		}
 
 		// Notify user that his payment was discarded.
@@ -490,23 +1012,58 @@ func (s *Switch) handlePacketForward(packet *htlcPacket) error {
 			err = errors.Errorf("unable to find link with "+
 				"destination %v", packet.dest)
 			log.Error(err)
+			s.notifyForwardFail(packet, htlc, err)
 			return err
 		}
 		interfaceLinks, _ := s.getLinks(targetLink.Peer().PubKey())
 
 		// Try to find destination channel link with appropriate
-		// bandwidth.
-		var destination ChannelLink
-		for _, link := range interfaceLinks {
-			if link.Bandwidth() >= htlc.Amount {
-				destination = link
-				break
+		// bandwidth, which also hasn't exceeded its forwarding limits.
+		// The channel requested by the onion is preferred, but should
+		// it be unable to carry the HTLC, then any other channel to
+		// the same peer may be used instead. As fees and time lock
+		// deltas are enforced by the incoming link, this doesn't
+		// change the fee charged for the forward. If the switch-wide
+		// limit has been exceeded, then no link is suitable.
+		var (
+			destination ChannelLink
+			throttled   bool
+			candidates  []ChannelLink
+		)
+		if s.throttle.allowGlobal(htlc.Amount) {
+			candidates = append(candidates, targetLink)
+			for _, link := range interfaceLinks {
+				if link != targetLink {
+					candidates = append(candidates, link)
+				}
+			}
+		} else {
+			throttled = true
+		}
+		for _, link := range candidates {
+			if link.Bandwidth() < htlc.Amount {
+				continue
 			}
+			if !s.throttle.allowLink(link.ShortChanID(),
+				link.Peer().PubKey(), htlc.Amount) {
+
+				throttled = true
+				continue
+			}
+
+			destination = link
+			break
+		}
+		if destination != nil && destination != targetLink {
+			log.Debugf("Forwarding htlc(%x) over %v rather than "+
+				"requested channel %v", htlc.PaymentHash[:],
+				destination.ShortChanID(), packet.dest)
 		}
 
 		// If the channel link we're attempting to forward the update
-		// over has insufficient capacity, then we'll cancel the htlc
-		// as the payment cannot succeed.
+		// over has insufficient capacity, or forwarding the htlc would
+		// exceed its limits, then we'll cancel the htlc as the payment
+		// cannot currently succeed.
 		if destination == nil {
 			// If packet was forwarded from another
 			// channel link than we should notify this
@@ -529,10 +1086,18 @@ func (s *Switch) handlePacketForward(packet *htlcPacket) error {
 				0, true,
 			))
 
-			err = errors.Errorf("unable to find appropriate "+
-				"channel link insufficient capacity, need "+
-				"%v", htlc.Amount)
+			if throttled {
+				err = errors.Errorf("unable to forward htlc "+
+					"of %v, forwarding limit exceeded",
+					htlc.Amount)
+			} else {
+				err = errors.Errorf("unable to find "+
+					"appropriate channel link "+
+					"insufficient capacity, need %v",
+					htlc.Amount)
+			}
 			log.Error(err)
+			s.notifyForwardFail(packet, htlc, err)
 			return err
 		}
 
@@ -543,54 +1108,130 @@ func (s *Switch) handlePacketForward(packet *htlcPacket) error {
 			source.ShortChanID(),
 			destination.ShortChanID(),
 			htlc.PaymentHash,
+			packet.incomingAmount,
+			htlc.Amount,
 			packet.obfuscator,
-		)); err != nil {
-			failure := lnwire.NewTemporaryChannelFailure(nil)
-			reason, err := packet.obfuscator.InitialObfuscate(failure)
-			if err != nil {
-				err := errors.Errorf("unable to obfuscate "+
-					"error: %v", err)
-				log.Error(err)
-				return err
-			}
-
-			go source.HandleSwitchPacket(newFailPacket(
-				packet.src,
-				&lnwire.UpdateFailHTLC{
-					Reason: reason,
-				},
-				htlc.PaymentHash, 0, true,
-			))
-			err = errors.Errorf("unable to add circuit: "+
-				"%v", err)
-			log.Error(err)
-			return err
+			packet.incomingOnion,
+		), s.circuitBatch); err != nil {
+			return s.failCircuitAdd(source, packet, htlc, err)
 		}
 
+		s.throttle.record(
+			destination.ShortChanID(), destination.Peer().PubKey(),
+			htlc.Amount,
+		)
+
 		// Send the packet to the destination channel link which
-		// manages the channel.
-		destination.HandleSwitchPacket(packet)
+		// manages the channel, once its circuit has been persisted.
+		s.dispatch(func() {
+			destination.HandleSwitchPacket(packet)
+
+			s.notifyEvent(HtlcForwardEvent{
+				PaymentHash:    htlc.PaymentHash,
+				IncomingChanID: packet.src,
+				OutgoingChanID: destination.ShortChanID(),
+				IncomingAmount: packet.incomingAmount,
+				OutgoingAmount: htlc.Amount,
+				Timestamp:      time.Now(),
+			})
+		}, func(err error) {
+			s.failCircuitAdd(source, packet, htlc, err)
+		})
+
 		return nil
 
 	// We've just received a settle packet which means we can finalize the
 	// payment circuit by forwarding the settle msg to the channel from
 	// which htlc add packet was initially received.
 	case *lnwire.UpdateFufillHTLC, *lnwire.UpdateFailHTLC:
-		// Exit if we can't find and remove the active circuit to
-		// continue propagating the fail over.
-		circuit, err := s.circuits.remove(packet.payHash)
+		// If this packet is the resolution of an HTLC that went
+		// on-chain, then its circuit is left in place until the
+		// resolution has been deleted, allowing it to be replayed
+		// should the link it's to be sent back over be down, or should
+		// we restart before then.
+		var (
+			circuit *paymentCircuit
+			err     error
+		)
+		if packet.resolution {
+			var ok bool
+			circuit, ok = s.circuits.lookup(packet.payHash)
+			if !ok {
+				err = errors.Errorf("can't find circuit for "+
+					"key %x", packet.payHash[:])
+			}
+		} else {
+			circuit, err = s.circuits.remove(
+				packet.payHash, s.circuitBatch,
+			)
+		}
+
+		// As the circuits of forwarded HTLCs are persisted, a packet
+		// without one is the result of a locally initiated HTLC,
+		// which may have been sent before a restart. We'll hold onto
+		// it until it's claimed.
 		if err != nil {
-			err := errors.Errorf("unable to remove "+
-				"circuit for payment hash: %v", packet.payHash)
-			log.Error(err)
-			return err
+			return s.storeResult(packet)
 		}
 
-		// If this is failure than we need to obfuscate the error.
-		if htlc, ok := htlc.(*lnwire.UpdateFailHTLC); ok && !packet.isObfuscated {
-			htlc.Reason = circuit.Obfuscator.BackwardObfuscate(
-				htlc.Reason,
+		// Should the link a resolution is to be sent back over be
+		// down, then it's retained to be replayed once the link is
+		// back.
+		if packet.resolution {
+			if _, err := s.getLinkByShortID(circuit.Src); err != nil {
+				return errors.Errorf("source link %v for "+
+					"resolution of %x is unavailable",
+					circuit.Src, packet.payHash[:])
+			}
+		}
+
+		// If this is a settle, then we've just learned the preimage
+		// for the HTLC. We'll commit it to disk before propagating
+		// the settle back, ensuring the incoming HTLC can still be
+		// claimed on-chain should we be unable to settle it
+		// off-chain.
+		if htlc, ok := htlc.(*lnwire.UpdateFufillHTLC); ok {
+			err := s.cfg.PreimageCache.AddPreimage(
+				htlc.PaymentPreimage[:],
 			)
+			if err != nil {
+				log.Errorf("unable to add preimage for payment "+
+					"hash %x: %v", packet.payHash[:], err)
+			}
+		}
+
+		// If this is failure than we need to obfuscate the error. A
+		// failure resulting from an on-chain resolution has yet to be
+		// encrypted at all, so it receives the initial layer.
+		if htlc, ok := htlc.(*lnwire.UpdateFailHTLC); ok && !packet.isObfuscated {
+			switch {
+			// If we were unable to re-derive the obfuscator of a
+			// restored circuit, then the failure is sent back
+			// as is, as failing the HTLC takes precedence over
+			// the sender being able to read the failure.
+			case circuit.Obfuscator == nil:
+				log.Warnf("Unable to obfuscate failure for "+
+					"%x, circuit has no obfuscator",
+					packet.payHash[:])
+
+			case packet.localFailure != nil:
+				reason, err := circuit.Obfuscator.InitialObfuscate(
+					packet.localFailure,
+				)
+				if err != nil {
+					err := errors.Errorf("unable to obfuscate "+
+						"failure for %x: %v",
+						packet.payHash[:], err)
+					log.Error(err)
+					return err
+				}
+				htlc.Reason = reason
+
+			default:
+				htlc.Reason = circuit.Obfuscator.BackwardObfuscate(
+					htlc.Reason,
+				)
+			}
 		}
 
 		// Propagating settle/fail htlc back to src of add htlc packet.
@@ -607,7 +1248,14 @@ func (s *Switch) handlePacketForward(packet *htlcPacket) error {
 			"circuit for %x: %v<->%v", packet.payHash[:],
 			circuit.Src, circuit.Dest)
 
-		source.HandleSwitchPacket(packet)
+		s.dispatch(func() {
+			source.HandleSwitchPacket(packet)
+			s.recordResolution(packet, circuit)
+		}, func(err error) {
+			log.Errorf("Unable to close circuit for %x: %v",
+				packet.payHash[:], err)
+		})
+
 		return nil
 
 	default:
@@ -615,19 +1263,135 @@ func (s *Switch) handlePacketForward(packet *htlcPacket) error {
 	}
 }
 
-// CloseLink creates and sends the close channel command.
+// failCircuitAdd fails the passed add packet back to its source link, as its
+// circuit couldn't be persisted.
+func (s *Switch) failCircuitAdd(source ChannelLink, packet *htlcPacket,
+	htlc *lnwire.UpdateAddHTLC, err error) error {
+
+	failure := lnwire.NewTemporaryChannelFailure(nil)
+	reason, obfuscateErr := packet.obfuscator.InitialObfuscate(failure)
+	if obfuscateErr != nil {
+		err := errors.Errorf("unable to obfuscate error: %v",
+			obfuscateErr)
+		log.Error(err)
+		return err
+	}
+
+	go source.HandleSwitchPacket(newFailPacket(
+		packet.src,
+		&lnwire.UpdateFailHTLC{
+			Reason: reason,
+		},
+		htlc.PaymentHash, 0, true,
+	))
+	err = errors.Errorf("unable to add circuit: %v", err)
+	log.Error(err)
+	s.notifyForwardFail(packet, htlc, err)
+
+	return err
+}
+
+// recordResolution records the settle or fail of a forwarded HTLC which has
+// been sent back over the source link of its circuit, notifying the switch's
+// event bus, and adding settled forwards to the forwarding log.
+func (s *Switch) recordResolution(packet *htlcPacket,
+	circuit *paymentCircuit) {
+
+	now := time.Now()
+	_, settled := packet.htlc.(*lnwire.UpdateFufillHTLC)
+	if settled && s.cfg.FwdEventLog != nil {
+		s.pendingFwdEvents = append(
+			s.pendingFwdEvents, channeldb.ForwardingEvent{
+				Timestamp:      now,
+				IncomingChanID: circuit.Src,
+				OutgoingChanID: circuit.Dest,
+				AmtIn:          circuit.IncomingAmount,
+				AmtOut:         circuit.OutgoingAmount,
+			},
+		)
+	}
+
+	s.notifyEvent(HtlcResolutionEvent{
+		PaymentHash:    packet.payHash,
+		IncomingChanID: circuit.Src,
+		OutgoingChanID: circuit.Dest,
+		Settled:        settled,
+		Timestamp:      now,
+	})
+}
+
+// notifyForwardFail notifies the switch's event bus that the passed add packet
+// couldn't be forwarded, and was failed back for the given reason.
+func (s *Switch) notifyForwardFail(packet *htlcPacket,
+	htlc *lnwire.UpdateAddHTLC, reason error) {
+
+	s.notifyEvent(HtlcForwardFailEvent{
+		PaymentHash:    htlc.PaymentHash,
+		IncomingChanID: packet.src,
+		OutgoingChanID: packet.dest,
+		Reason:         reason.Error(),
+		Timestamp:      time.Now(),
+	})
+}
+
+// flushForwardingEvents writes the forwards settled since the last flush to
+// the forwarding log.
+//
+// NOTE: This MUST be called from the htlcForwarder goroutine.
+func (s *Switch) flushForwardingEvents() {
+	if len(s.pendingFwdEvents) == 0 {
+		return
+	}
+
+	log.Debugf("Writing %v forwarding events to log",
+		len(s.pendingFwdEvents))
+
+	err := s.cfg.FwdEventLog.AddForwardingEvents(s.pendingFwdEvents)
+	if err != nil {
+		log.Errorf("unable to write forwarding events: %v", err)
+		return
+	}
+
+	s.pendingFwdEvents = nil
+}
+
+// pruneForwardingEvents removes the forwards which were settled more than
+// fwdEventRetention before now from the forwarding log.
+func (s *Switch) pruneForwardingEvents(now time.Time) {
+	if s.cfg.FwdEventLog == nil {
+		return
+	}
+
+	numDeleted, err := s.cfg.FwdEventLog.DeleteForwardingEvents(
+		now.Add(-fwdEventRetention),
+	)
+	if err != nil {
+		log.Errorf("unable to prune forwarding log: %v", err)
+		return
+	}
+
+	if numDeleted > 0 {
+		log.Debugf("Pruned %v expired forwarding events from log",
+			numDeleted)
+	}
+}
+
+// CloseLink creates and sends the close channel command. The delivery script,
+// if any, is the script our funds are paid out to within a cooperative close.
 func (s *Switch) CloseLink(chanPoint *wire.OutPoint,
-	closeType ChannelCloseType) (chan *lnrpc.CloseStatusUpdate, chan error) {
+	closeType ChannelCloseType,
+	deliveryScript []byte) (chan *lnrpc.CloseStatusUpdate, chan error) {
 
 	// TODO(roasbeef) abstract out the close updates.
 	updateChan := make(chan *lnrpc.CloseStatusUpdate, 2)
 	errChan := make(chan error, 1)
 
 	command := &ChanClose{
-		CloseType: closeType,
-		ChanPoint: chanPoint,
-		Updates:   updateChan,
-		Err:       errChan,
+		CloseType:      closeType,
+		ChanPoint:      chanPoint,
+		DeliveryScript: deliveryScript,
+		Updates:        updateChan,
+		Err:            errChan,
 	}
 
 	select {
@@ -655,7 +1419,8 @@ func (s *Switch) CloseLink(chanPoint *wire.OutPoint,
 func (s *Switch) htlcForwarder() {
 	defer s.wg.Done()
 
-	// Remove all links once we've been signalled for shutdown.
+	// Remove all links once we've been signalled for shutdown, writing
+	// any forwards which remain pending to the log.
 	defer func() {
 		for _, link := range s.linkIndex {
 			if err := s.removeLink(link.ChanID()); err != nil {
@@ -663,6 +1428,8 @@ func (s *Switch) htlcForwarder() {
 					"channel link on stop: %v", err)
 			}
 		}
+
+		s.flushForwardingEvents()
 	}()
 
 	// TODO(roasbeef): cleared vs settled distinction
@@ -674,6 +1441,18 @@ func (s *Switch) htlcForwarder() {
 	logTicker := time.NewTicker(10 * time.Second)
 	defer logTicker.Stop()
 
+	dampingTicker := time.NewTicker(dampingCheckInterval)
+	defer dampingTicker.Stop()
+
+	fwdEventTicker := time.NewTicker(fwdEventFlushInterval)
+	defer fwdEventTicker.Stop()
+
+	unclaimedResultTicker := time.NewTicker(unclaimedResultPruneInterval)
+	defer unclaimedResultTicker.Stop()
+
+	fwdEventPruneTicker := time.NewTicker(fwdEventPruneInterval)
+	defer fwdEventPruneTicker.Stop()
+
 	for {
 		select {
 		// A local close request has arrived, we'll forward this to the
@@ -698,37 +1477,13 @@ func (s *Switch) htlcForwarder() {
 		// packet concretely, then either forward it along, or
 		// interpret a return packet to a locally initialized one.
 		case cmd := <-s.htlcPlex:
-			var (
-				paymentHash lnwallet.PaymentHash
-				amount      lnwire.MilliSatoshi
-			)
-
-			// Only three types of message should be forwarded:
-			// add, fails, and settles. Anything else is an error.
-			switch m := cmd.pkt.htlc.(type) {
-			case *lnwire.UpdateAddHTLC:
-				paymentHash = m.PaymentHash
-				amount = m.Amount
-			case *lnwire.UpdateFufillHTLC, *lnwire.UpdateFailHTLC:
-				paymentHash = cmd.pkt.payHash
-				amount = cmd.pkt.amount
-			default:
-				cmd.err <- errors.New("wrong type of update")
-				return
-			}
+			cmd.err <- s.routePacket(cmd.pkt)
 
-			// If we can locate this packet in our local records,
-			// then this means a local sub-system initiated it.
-			// Otherwise, this is just a packet to be forwarded, so
-			// we'll treat it as so.
-			//
-			// TODO(roasbeef): can fast path this
-			payment, err := s.findPayment(amount, paymentHash)
-			if err != nil {
-				cmd.err <- s.handlePacketForward(cmd.pkt)
-			} else {
-				cmd.err <- s.handleLocalDispatch(payment, cmd.pkt)
-			}
+		// The updates locked in by a state transition have arrived
+		// for forwarding, we'll route each of them, persisting the
+		// circuits they open and close at once.
+		case cmd := <-s.htlcPlexBatch:
+			cmd.errs <- s.handlePacketBatch(cmd.pkts)
 
 		// The log ticker has fired, so we'll calculate some forwarding
 		// stats for the last 10 seconds to display within the logs to
@@ -794,13 +1549,38 @@ func (s *Switch) htlcForwarder() {
 			totalSatSent += diffSatSent
 			totalSatRecv += diffSatRecv
 
+		// The damping ticker has fired, so we'll re-enable any
+		// disabled channels which have since become stable.
+		case <-dampingTicker.C:
+			s.enableStableChannels()
+
+		// The forwarding event ticker has fired, so we'll write the
+		// forwards settled since the last tick to the log.
+		case <-fwdEventTicker.C:
+			s.flushForwardingEvents()
+
+		// The forwarding log prune ticker has fired, so we'll remove
+		// the forwards which are too old to be reported on.
+		case <-fwdEventPruneTicker.C:
+			s.pruneForwardingEvents(time.Now())
+
+		// The unclaimed result ticker has fired, so we'll discard the
+		// results which nobody claimed in time.
+		case <-unclaimedResultTicker.C:
+			s.pruneUnclaimedResults(time.Now())
+
 		case req := <-s.linkControl:
 			switch cmd := req.(type) {
 			case *updatePoliciesCmd:
 				cmd.err <- s.updateLinkPolicies(cmd)
+			case *setInterceptorCmd:
+				cmd.err <- s.setInterceptor(cmd.interceptor)
+			case *resolveForwardCmd:
+				cmd.err <- s.resolveForward(cmd)
 			case *addLinkCmd:
 				cmd.err <- s.addLink(cmd.link)
 			case *removeLinkCmd:
+				s.recordLinkFlap(cmd.chanID)
 				cmd.err <- s.removeLink(cmd.chanID)
 			case *getLinkCmd:
 				link, err := s.getLink(cmd.chanID)
@@ -827,9 +1607,22 @@ func (s *Switch) Start() error {
 
 	log.Infof("Starting HTLC Switch")
 
+	// Restore the circuits of the HTLCs forwarded before we were shut
+	// down, so that their settles and fails can be sent back upstream.
+	if err := s.circuits.restore(s.cfg.DecodeOnionObfuscator); err != nil {
+		return err
+	}
+
 	s.wg.Add(1)
 	go s.htlcForwarder()
 
+	// Any resolutions we were unable to handle before we were shut down
+	// are replayed now.
+	if s.cfg.ResolutionStore != nil {
+		s.wg.Add(1)
+		go s.replayResolutions()
+	}
+
 	return nil
 }
 
@@ -866,10 +1659,22 @@ func (s *Switch) AddLink(link ChannelLink) error {
 
 	select {
 	case s.linkControl <- command:
-		return <-command.err
 	case <-s.quit:
 		return errors.New("Htlc Switch was stopped")
 	}
+
+	if err := <-command.err; err != nil {
+		return err
+	}
+
+	// As resolutions to be sent back over this link may have been
+	// awaiting it, we'll replay any that are yet to be handled.
+	if s.cfg.ResolutionStore != nil {
+		s.wg.Add(1)
+		go s.replayResolutions()
+	}
+
+	return nil
 }
 
 // addLink is used to add the newly created channel link and start use it to
@@ -894,6 +1699,16 @@ func (s *Switch) addLink(link ChannelLink) error {
 		return err
 	}
 
+	// The channel may have been left disabled by the damper before a
+	// restart, so it's re-enabled unless it's currently being damped. No
+	// update is sent if the channel is already enabled.
+	shortChanID := link.ShortChanID()
+	if s.damper.cfg.enabled() && shortChanID != (lnwire.ShortChannelID{}) &&
+		!s.damper.isDisabled(shortChanID) {
+
+		s.updateChanStatus(shortChanID, false)
+	}
+
 	log.Infof("Added channel link with chan_id=%v, short_chan_id=(%v), "+
 		"bandwidth=%v", link.ChanID(), spew.Sdump(link.ShortChanID()),
 		link.Bandwidth())
@@ -1036,7 +1851,7 @@ func (s *Switch) getLinks(destination [33]byte) ([]ChannelLink, error) {
 
 // removePendingPayment is the helper function which removes the pending user
 // payment.
-func (s *Switch) removePendingPayment(amount lnwire.MilliSatoshi,
+func (s *Switch) removePendingPayment(amount lnwire.MilliAtom,
 	hash lnwallet.PaymentHash) error {
 
 	s.pendingMutex.Lock()
@@ -1065,8 +1880,99 @@ func (s *Switch) removePendingPayment(amount lnwire.MilliSatoshi,
 		"hash(%v) and amount(%v)", hash, amount)
 }
 
+// storeResult retains the settle or fail packet of an HTLC for which neither a
+// pending payment nor a circuit is known, such that it can later be claimed
+// through GetPaymentResult. If a pending payment was registered in the
+// meantime, then the packet is dispatched to it directly.
+func (s *Switch) storeResult(packet *htlcPacket) error {
+	s.pendingMutex.Lock()
+	for _, payment := range s.pendingPayments[packet.payHash] {
+		if payment.amount == packet.amount {
+			s.pendingMutex.Unlock()
+			return s.handleLocalDispatch(payment, packet)
+		}
+	}
+
+	log.Debugf("Storing unclaimed result for payment hash %x",
+		packet.payHash[:])
+
+	s.unclaimedResults[packet.payHash] = append(
+		s.unclaimedResults[packet.payHash], &unclaimedResult{
+			packet:   packet,
+			received: time.Now(),
+		},
+	)
+	s.pendingMutex.Unlock()
+
+	// If this is a settle, then we'll commit the preimage to disk right
+	// away, ensuring the payment can be resolved even if we're restarted
+	// before the result is claimed.
+	if htlc, ok := packet.htlc.(*lnwire.UpdateFufillHTLC); ok {
+		err := s.cfg.PreimageCache.AddPreimage(htlc.PaymentPreimage[:])
+		if err != nil {
+			log.Errorf("unable to add preimage for payment "+
+				"hash %x: %v", packet.payHash[:], err)
+		}
+	}
+
+	return nil
+}
+
+// claimResult removes and returns the unclaimed result matching the given
+// hash and amount, if any.
+//
+// NOTE: This method MUST be called with the pendingMutex held.
+func (s *Switch) claimResult(amount lnwire.MilliAtom,
+	hash lnwallet.PaymentHash) *htlcPacket {
+
+	results := s.unclaimedResults[hash]
+	for i, result := range results {
+		if result.packet.amount != amount {
+			continue
+		}
+
+		results[i] = results[len(results)-1]
+		results[len(results)-1] = nil
+		s.unclaimedResults[hash] = results[:len(results)-1]
+
+		if len(s.unclaimedResults[hash]) == 0 {
+			delete(s.unclaimedResults, hash)
+		}
+
+		return result.packet
+	}
+
+	return nil
+}
+
+// pruneUnclaimedResults discards the unclaimed results which were received
+// more than unclaimedResultExpiry before now.
+func (s *Switch) pruneUnclaimedResults(now time.Time) {
+	s.pendingMutex.Lock()
+	defer s.pendingMutex.Unlock()
+
+	for hash, results := range s.unclaimedResults {
+		var kept []*unclaimedResult
+		for _, result := range results {
+			if now.Sub(result.received) > unclaimedResultExpiry {
+				log.Debugf("Discarding expired unclaimed result "+
+					"for payment hash %x", hash[:])
+				continue
+			}
+
+			kept = append(kept, result)
+		}
+
+		if len(kept) == 0 {
+			delete(s.unclaimedResults, hash)
+			continue
+		}
+		s.unclaimedResults[hash] = kept
+	}
+}
+
 // findPayment is the helper function which find the payment.
-func (s *Switch) findPayment(amount lnwire.MilliSatoshi,
+func (s *Switch) findPayment(amount lnwire.MilliAtom,
 	hash lnwallet.PaymentHash) (*pendingPayment, error) {
 
 	s.pendingMutex.RLock()
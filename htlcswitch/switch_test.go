@@ -3,12 +3,16 @@ package htlcswitch
 import (
 	"bytes"
 	"crypto/sha256"
+	"io"
 	"testing"
 	"time"
 
 	"github.com/btcsuite/fastsha256"
 	"github.com/go-errors/errors"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/contractcourt"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/subscribe"
 	"github.com/roasbeef/btcd/chaincfg/chainhash"
 	"github.com/roasbeef/btcd/wire"
 )
@@ -40,10 +44,12 @@ func TestSwitchForward(t *testing.T) {
 	aliceChannelLink := newMockChannelLink(chanID1, aliceChanID, alicePeer)
 	bobChannelLink := newMockChannelLink(chanID2, bobChanID, bobPeer)
 
+	preimageCache := newMockPreimageCache()
 	s := New(Config{
 		UpdateTopology: func(msg *lnwire.ChannelUpdate) error {
 			return nil
 		},
+		PreimageCache: preimageCache,
 	})
 	s.Start()
 	if err := s.AddLink(aliceChannelLink); err != nil {
@@ -107,6 +113,17 @@ func TestSwitchForward(t *testing.T) {
 	if s.circuits.pending() != 0 {
 		t.Fatal("wrong amount of circuits")
 	}
+
+	// The preimage learned from the settle should have been added to the
+	// preimage cache.
+	cachedPreimage, ok := preimageCache.LookupPreimage(rhash[:])
+	if !ok {
+		t.Fatal("preimage wasn't added to the cache")
+	}
+	if !bytes.Equal(cachedPreimage, preimage[:]) {
+		t.Fatalf("expected preimage %x, got %x", preimage[:],
+			cachedPreimage)
+	}
 }
 
 // TestSwitchCancel checks that if htlc was rejected we remove unused
@@ -126,6 +143,7 @@ func TestSwitchCancel(t *testing.T) {
 		UpdateTopology: func(msg *lnwire.ChannelUpdate) error {
 			return nil
 		},
+		PreimageCache: newMockPreimageCache(),
 	})
 	s.Start()
 	if err := s.AddLink(aliceChannelLink); err != nil {
@@ -206,6 +224,7 @@ func TestSwitchAddSamePayment(t *testing.T) {
 		UpdateTopology: func(msg *lnwire.ChannelUpdate) error {
 			return nil
 		},
+		PreimageCache: newMockPreimageCache(),
 	})
 	s.Start()
 	if err := s.AddLink(aliceChannelLink); err != nil {
@@ -306,6 +325,7 @@ func TestSwitchSendPayment(t *testing.T) {
 		UpdateTopology: func(msg *lnwire.ChannelUpdate) error {
 			return nil
 		},
+		PreimageCache: newMockPreimageCache(),
 	})
 	s.Start()
 	if err := s.AddLink(aliceChannelLink); err != nil {
@@ -412,3 +432,1150 @@ func TestSwitchSendPayment(t *testing.T) {
 		t.Fatal("wrong amount of pending payments")
 	}
 }
+
+// TestSwitchGetPaymentResult checks that the results of locally initiated
+// htlcs which arrive while nobody is waiting on them, as is the case after a
+// restart, are retained until they're queried through GetPaymentResult.
+func TestSwitchGetPaymentResult(t *testing.T) {
+	t.Parallel()
+
+	alicePeer := newMockServer(t, "alice")
+	aliceChannelLink := newMockChannelLink(chanID1, aliceChanID, alicePeer)
+
+	s := New(Config{
+		UpdateTopology: func(msg *lnwire.ChannelUpdate) error {
+			return nil
+		},
+		PreimageCache: newMockPreimageCache(),
+	})
+	s.Start()
+	if err := s.AddLink(aliceChannelLink); err != nil {
+		t.Fatalf("unable to add link: %v", err)
+	}
+
+	// First, we'll deliver a failure for an htlc which isn't known to the
+	// switch, as if it was sent before a restart.
+	obfuscator := newMockObfuscator()
+	failure := lnwire.FailIncorrectPaymentAmount{}
+	reason, err := obfuscator.InitialObfuscate(failure)
+	if err != nil {
+		t.Fatalf("unable obfuscate failure: %v", err)
+	}
+
+	failHash := fastsha256.Sum256([]byte{1})
+	failPacket := newFailPacket(aliceChannelLink.ShortChanID(),
+		&lnwire.UpdateFailHTLC{
+			Reason: reason,
+		},
+		failHash, 1, true)
+	if err := s.forward(failPacket); err != nil {
+		t.Fatalf("can't forward htlc packet: %v", err)
+	}
+
+	// The failure should be retained until it expires.
+	s.pruneUnclaimedResults(time.Now())
+	if len(s.unclaimedResults) != 1 {
+		t.Fatalf("expected 1 unclaimed result, got %v",
+			len(s.unclaimedResults))
+	}
+
+	// Querying the result should now yield the failure.
+	_, err = s.GetPaymentResult(failHash, 1, newMockDeobfuscator())
+	if err == nil || err.Error() !=
+		errors.New(lnwire.CodeIncorrectPaymentAmount).Error() {
+
+		t.Fatalf("expected incorrect payment amount failure, got %v",
+			err)
+	}
+
+	// Next, we'll query the result of an htlc before it's settled. The
+	// query should block until the settle arrives.
+	preimage := [sha256.Size]byte{2}
+	settleHash := fastsha256.Sum256(preimage[:])
+
+	type result struct {
+		preimage [sha256.Size]byte
+		err      error
+	}
+	resultChan := make(chan result, 1)
+	go func() {
+		p, err := s.GetPaymentResult(
+			settleHash, 1, newMockDeobfuscator(),
+		)
+		resultChan <- result{p, err}
+	}()
+
+	select {
+	case r := <-resultChan:
+		t.Fatalf("result returned before settle: %v", r.err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	settlePacket := newSettlePacket(aliceChannelLink.ShortChanID(),
+		&lnwire.UpdateFufillHTLC{
+			PaymentPreimage: preimage,
+		},
+		settleHash, 1)
+	if err := s.forward(settlePacket); err != nil {
+		t.Fatalf("can't forward htlc packet: %v", err)
+	}
+
+	select {
+	case r := <-resultChan:
+		if r.err != nil {
+			t.Fatalf("unexpected payment error: %v", r.err)
+		}
+		if r.preimage != preimage {
+			t.Fatalf("wrong preimage: expected %x, got %x",
+				preimage, r.preimage)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("payment result wasn't received")
+	}
+
+	// The preimage should have been committed to the cache, so querying
+	// the result again should return it immediately.
+	p, err := s.GetPaymentResult(settleHash, 1, newMockDeobfuscator())
+	if err != nil {
+		t.Fatalf("unexpected payment error: %v", err)
+	}
+	if p != preimage {
+		t.Fatalf("wrong preimage: expected %x, got %x", preimage, p)
+	}
+
+	if s.numPendingPayments() != 0 {
+		t.Fatal("wrong amount of pending payments")
+	}
+
+	// Finally, a result which nobody claims should be discarded once it
+	// expires.
+	if err := s.forward(failPacket); err != nil {
+		t.Fatalf("can't forward htlc packet: %v", err)
+	}
+	s.pruneUnclaimedResults(time.Now().Add(unclaimedResultExpiry + 1))
+	if len(s.unclaimedResults) != 0 {
+		t.Fatalf("expected expired result to be discarded, %v remain",
+			len(s.unclaimedResults))
+	}
+}
+
+// TestSwitchForwardLimit checks that htlcs which would exceed the forwarding
+// limit of their outgoing channel are failed back, and that they're once again
+// forwarded after the window has elapsed.
+func TestSwitchForwardLimit(t *testing.T) {
+	t.Parallel()
+
+	alicePeer := newMockServer(t, "alice")
+	bobPeer := newMockServer(t, "bob")
+
+	aliceChannelLink := newMockChannelLink(chanID1, aliceChanID, alicePeer)
+	bobChannelLink := newMockChannelLink(chanID2, bobChanID, bobPeer)
+
+	s := New(Config{
+		UpdateTopology: func(msg *lnwire.ChannelUpdate) error {
+			return nil
+		},
+		PreimageCache: newMockPreimageCache(),
+		ChannelForwardLimit: ForwardLimit{
+			MaxAmount: 1000,
+			Interval:  time.Hour,
+		},
+	})
+
+	// As the forward call blocks until the packet has been handled, the
+	// clock can safely be advanced between calls.
+	now := time.Unix(1000, 0)
+	s.throttle.now = func() time.Time {
+		return now
+	}
+
+	s.Start()
+	if err := s.AddLink(aliceChannelLink); err != nil {
+		t.Fatalf("unable to add alice link: %v", err)
+	}
+	if err := s.AddLink(bobChannelLink); err != nil {
+		t.Fatalf("unable to add bob link: %v", err)
+	}
+
+	forwardAdd := func(i byte) error {
+		preimage := [sha256.Size]byte{i}
+		rhash := fastsha256.Sum256(preimage[:])
+		return s.forward(newAddPacket(
+			aliceChannelLink.ShortChanID(),
+			bobChannelLink.ShortChanID(),
+			&lnwire.UpdateAddHTLC{
+				PaymentHash: rhash,
+				Amount:      600,
+			}, newMockObfuscator(),
+		))
+	}
+
+	// The first htlc is within the limit of bob's channel, so it should
+	// be forwarded.
+	if err := forwardAdd(1); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-bobChannelLink.packets:
+	case <-time.After(time.Second):
+		t.Fatal("request was not propagated to destination")
+	}
+
+	// The second htlc would exceed the limit, so it should be failed back
+	// to alice rather than forwarded.
+	if err := forwardAdd(2); err == nil {
+		t.Fatal("htlc exceeding the forwarding limit was forwarded")
+	}
+	select {
+	case packet := <-aliceChannelLink.packets:
+		if _, ok := packet.htlc.(*lnwire.UpdateFailHTLC); !ok {
+			t.Fatalf("expected fail htlc, got %T", packet.htlc)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("failure was not propagated to source")
+	}
+	if s.circuits.pending() != 1 {
+		t.Fatal("wrong amount of circuits")
+	}
+
+	// Once the first htlc falls out of the window, the htlc should be
+	// forwarded.
+	now = now.Add(time.Hour)
+	if err := forwardAdd(2); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-bobChannelLink.packets:
+	case <-time.After(time.Second):
+		t.Fatal("request was not propagated to destination")
+	}
+}
+
+// TestSwitchForwardNonStrict checks that htlcs are forwarded over the channel
+// requested by the onion whenever possible, and otherwise over another
+// channel to the same peer.
+func TestSwitchForwardNonStrict(t *testing.T) {
+	t.Parallel()
+
+	alicePeer := newMockServer(t, "alice")
+	bobPeer := newMockServer(t, "bob")
+
+	hash3, _ := chainhash.NewHash(bytes.Repeat([]byte("c"), 32))
+	chanID3 := lnwire.NewChanIDFromOutPoint(wire.NewOutPoint(hash3, 0))
+
+	aliceChannelLink := newMockChannelLink(chanID1, aliceChanID, alicePeer)
+	bobChannelLink := newMockChannelLink(chanID2, bobChanID, bobPeer)
+	bobChannelLink2 := newMockChannelLink(
+		chanID3, lnwire.NewShortChanIDFromInt(3), bobPeer,
+	)
+
+	s := New(Config{
+		UpdateTopology: func(msg *lnwire.ChannelUpdate) error {
+			return nil
+		},
+		PreimageCache: newMockPreimageCache(),
+		ChannelForwardLimit: ForwardLimit{
+			MaxAmount: 1000,
+			Interval:  time.Hour,
+		},
+	})
+	s.Start()
+	for _, link := range []ChannelLink{
+		aliceChannelLink, bobChannelLink, bobChannelLink2,
+	} {
+		if err := s.AddLink(link); err != nil {
+			t.Fatalf("unable to add link: %v", err)
+		}
+	}
+
+	forwardAdd := func(i byte) error {
+		preimage := [sha256.Size]byte{i}
+		rhash := fastsha256.Sum256(preimage[:])
+		return s.forward(newAddPacket(
+			aliceChannelLink.ShortChanID(),
+			bobChannelLink2.ShortChanID(),
+			&lnwire.UpdateAddHTLC{
+				PaymentHash: rhash,
+				Amount:      600,
+			}, newMockObfuscator(),
+		))
+	}
+
+	// The first htlc should be forwarded over the requested channel, even
+	// though both of bob's channels are able to carry it.
+	if err := forwardAdd(1); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-bobChannelLink2.packets:
+	case <-time.After(time.Second):
+		t.Fatal("request was not propagated to requested channel")
+	}
+
+	// The second htlc would exceed the limit of the requested channel, so
+	// it should be forwarded over bob's other channel instead.
+	if err := forwardAdd(2); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-bobChannelLink.packets:
+	case <-time.After(time.Second):
+		t.Fatal("request was not propagated to alternative channel")
+	}
+
+	// With both of bob's channels at their limit, the third htlc should
+	// be failed back.
+	if err := forwardAdd(3); err == nil {
+		t.Fatal("htlc exceeding the forwarding limit was forwarded")
+	}
+	select {
+	case packet := <-aliceChannelLink.packets:
+		if _, ok := packet.htlc.(*lnwire.UpdateFailHTLC); !ok {
+			t.Fatalf("expected fail htlc, got %T", packet.htlc)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("failure was not propagated to source")
+	}
+}
+
+// TestSwitchForwardInterceptor checks that htlcs are held while a forward
+// interceptor is registered, and that they're resumed, failed or settled as
+// directed by the interceptor, or failed back once they time out.
+func TestSwitchForwardInterceptor(t *testing.T) {
+	t.Parallel()
+
+	alicePeer := newMockServer(t, "alice")
+	bobPeer := newMockServer(t, "bob")
+
+	aliceChannelLink := newMockChannelLink(chanID1, aliceChanID, alicePeer)
+	bobChannelLink := newMockChannelLink(chanID2, bobChanID, bobPeer)
+
+	preimageCache := newMockPreimageCache()
+	s := New(Config{
+		UpdateTopology: func(msg *lnwire.ChannelUpdate) error {
+			return nil
+		},
+		PreimageCache:           preimageCache,
+		ForwardInterceptTimeout: 500 * time.Millisecond,
+	})
+	s.Start()
+	if err := s.AddLink(aliceChannelLink); err != nil {
+		t.Fatalf("unable to add alice link: %v", err)
+	}
+	if err := s.AddLink(bobChannelLink); err != nil {
+		t.Fatalf("unable to add bob link: %v", err)
+	}
+
+	forwards := make(chan *InterceptedForward, 1)
+	err := s.SetInterceptor(func(fwd *InterceptedForward) {
+		forwards <- fwd
+	})
+	if err != nil {
+		t.Fatalf("unable to set interceptor: %v", err)
+	}
+
+	// Only a single interceptor may be registered at a time.
+	err = s.SetInterceptor(func(*InterceptedForward) {})
+	if err != ErrInterceptorAlreadySet {
+		t.Fatalf("expected ErrInterceptorAlreadySet, got %v", err)
+	}
+
+	// forwardAdd forwards a new htlc from alice to bob, returning the
+	// resulting intercepted forward.
+	forwardAdd := func(preimage [sha256.Size]byte) *InterceptedForward {
+		rhash := fastsha256.Sum256(preimage[:])
+		err := s.forward(newAddPacket(
+			aliceChannelLink.ShortChanID(),
+			bobChannelLink.ShortChanID(),
+			&lnwire.UpdateAddHTLC{
+				PaymentHash: rhash,
+				Amount:      1,
+			}, newMockObfuscator(),
+		))
+		if err != nil {
+			t.Fatalf("unable to forward htlc: %v", err)
+		}
+
+		select {
+		case fwd := <-forwards:
+			if fwd.PaymentHash != rhash {
+				t.Fatalf("expected payment hash %x, got %x",
+					rhash[:], fwd.PaymentHash[:])
+			}
+			return fwd
+		case <-time.After(time.Second):
+			t.Fatal("htlc was not intercepted")
+		}
+		return nil
+	}
+
+	// assertPacket asserts that the passed link receives a packet of the
+	// same type as the passed htlc.
+	assertPacket := func(link *mockChannelLink, htlc lnwire.Message) {
+		select {
+		case packet := <-link.packets:
+			if packet.htlc.MsgType() != htlc.MsgType() {
+				t.Fatalf("expected %T, got %T", htlc,
+					packet.htlc)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("%T was not propagated", htlc)
+		}
+	}
+
+	// The first htlc is held until it's resumed, after which it should be
+	// forwarded to bob.
+	fwd := forwardAdd([sha256.Size]byte{1})
+	select {
+	case <-bobChannelLink.packets:
+		t.Fatal("intercepted htlc was forwarded")
+	case <-time.After(50 * time.Millisecond):
+	}
+	if err := fwd.Resume(); err != nil {
+		t.Fatalf("unable to resume forward: %v", err)
+	}
+	assertPacket(bobChannelLink, &lnwire.UpdateAddHTLC{})
+	if s.circuits.pending() != 1 {
+		t.Fatal("wrong amount of circuits")
+	}
+
+	// A forward may only be resolved once.
+	if err := fwd.Fail(); err != ErrForwardResolved {
+		t.Fatalf("expected ErrForwardResolved, got %v", err)
+	}
+
+	// The second htlc should be failed back to alice.
+	fwd = forwardAdd([sha256.Size]byte{2})
+	if err := fwd.Fail(); err != nil {
+		t.Fatalf("unable to fail forward: %v", err)
+	}
+	assertPacket(aliceChannelLink, &lnwire.UpdateFailHTLC{})
+
+	// The third htlc should be settled back to alice, but only with the
+	// correct preimage, which should then be added to the preimage cache.
+	preimage := [sha256.Size]byte{3}
+	fwd = forwardAdd(preimage)
+	err = fwd.Settle([sha256.Size]byte{4})
+	if err != ErrInvalidInterceptPreimage {
+		t.Fatalf("expected ErrInvalidInterceptPreimage, got %v", err)
+	}
+	if err := fwd.Settle(preimage); err != nil {
+		t.Fatalf("unable to settle forward: %v", err)
+	}
+	assertPacket(aliceChannelLink, &lnwire.UpdateFufillHTLC{})
+	if _, ok := preimageCache.LookupPreimage(fwd.PaymentHash[:]); !ok {
+		t.Fatal("preimage wasn't added to the cache")
+	}
+
+	// The fourth htlc isn't resolved at all, so it should be failed back
+	// to alice once it times out.
+	fwd = forwardAdd([sha256.Size]byte{5})
+	assertPacket(aliceChannelLink, &lnwire.UpdateFailHTLC{})
+	if err := fwd.Resume(); err != ErrForwardResolved {
+		t.Fatalf("expected ErrForwardResolved, got %v", err)
+	}
+
+	// Once the interceptor is unregistered, htlcs should be forwarded
+	// directly.
+	if err := s.SetInterceptor(nil); err != nil {
+		t.Fatalf("unable to clear interceptor: %v", err)
+	}
+	err = s.forward(newAddPacket(
+		aliceChannelLink.ShortChanID(),
+		bobChannelLink.ShortChanID(),
+		&lnwire.UpdateAddHTLC{
+			PaymentHash: fastsha256.Sum256([]byte{6}),
+			Amount:      1,
+		}, newMockObfuscator(),
+	))
+	if err != nil {
+		t.Fatalf("unable to forward htlc: %v", err)
+	}
+	assertPacket(bobChannelLink, &lnwire.UpdateAddHTLC{})
+}
+
+// TestSwitchHtlcEvents checks that the switch notifies its event bus of the
+// htlcs it forwards, and of those it's unable to forward.
+func TestSwitchHtlcEvents(t *testing.T) {
+	t.Parallel()
+
+	alicePeer := newMockServer(t, "alice")
+	bobPeer := newMockServer(t, "bob")
+
+	aliceChannelLink := newMockChannelLink(chanID1, aliceChanID, alicePeer)
+	bobChannelLink := newMockChannelLink(chanID2, bobChanID, bobPeer)
+
+	eventBus := subscribe.NewServer()
+	if err := eventBus.Start(); err != nil {
+		t.Fatalf("unable to start event bus: %v", err)
+	}
+	defer eventBus.Stop()
+
+	client, err := eventBus.Subscribe(
+		HtlcForwardEvent{}, HtlcForwardFailEvent{},
+	)
+	if err != nil {
+		t.Fatalf("unable to subscribe to event bus: %v", err)
+	}
+	defer client.Cancel()
+
+	s := New(Config{
+		UpdateTopology: func(msg *lnwire.ChannelUpdate) error {
+			return nil
+		},
+		PreimageCache: newMockPreimageCache(),
+		EventBus:      eventBus,
+	})
+	s.Start()
+	defer s.Stop()
+	if err := s.AddLink(aliceChannelLink); err != nil {
+		t.Fatalf("unable to add alice link: %v", err)
+	}
+	if err := s.AddLink(bobChannelLink); err != nil {
+		t.Fatalf("unable to add bob link: %v", err)
+	}
+
+	nextEvent := func() interface{} {
+		select {
+		case event := <-client.Updates():
+			return event
+		case <-time.After(time.Second):
+			t.Fatal("no event received")
+			return nil
+		}
+	}
+
+	// An htlc forwarded from alice to bob should result in a forward
+	// event.
+	rhash := fastsha256.Sum256([]byte{1})
+	err = s.forward(newAddPacket(
+		aliceChannelLink.ShortChanID(),
+		bobChannelLink.ShortChanID(),
+		&lnwire.UpdateAddHTLC{
+			PaymentHash: rhash,
+			Amount:      1,
+		}, newMockObfuscator(),
+	))
+	if err != nil {
+		t.Fatalf("unable to forward htlc: %v", err)
+	}
+
+	fwdEvent, ok := nextEvent().(HtlcForwardEvent)
+	if !ok {
+		t.Fatalf("expected forward event")
+	}
+	if fwdEvent.PaymentHash != rhash {
+		t.Fatalf("expected payment hash %x, got %x", rhash,
+			fwdEvent.PaymentHash)
+	}
+	if fwdEvent.IncomingChanID != aliceChanID ||
+		fwdEvent.OutgoingChanID != bobChanID {
+
+		t.Fatalf("expected forward %v->%v, got %v->%v", aliceChanID,
+			bobChanID, fwdEvent.IncomingChanID,
+			fwdEvent.OutgoingChanID)
+	}
+
+	// An htlc to an unknown channel should be failed back, resulting in a
+	// forward fail event.
+	rhash = fastsha256.Sum256([]byte{2})
+	unknownChanID := lnwire.NewShortChanIDFromInt(3)
+	err = s.forward(newAddPacket(
+		aliceChannelLink.ShortChanID(), unknownChanID,
+		&lnwire.UpdateAddHTLC{
+			PaymentHash: rhash,
+			Amount:      1,
+		}, newMockObfuscator(),
+	))
+	if err == nil {
+		t.Fatalf("expected forward to unknown channel to fail")
+	}
+
+	failEvent, ok := nextEvent().(HtlcForwardFailEvent)
+	if !ok {
+		t.Fatalf("expected forward fail event")
+	}
+	if failEvent.PaymentHash != rhash {
+		t.Fatalf("expected payment hash %x, got %x", rhash,
+			failEvent.PaymentHash)
+	}
+	if failEvent.OutgoingChanID != unknownChanID {
+		t.Fatalf("expected outgoing channel %v, got %v", unknownChanID,
+			failEvent.OutgoingChanID)
+	}
+}
+
+// TestSwitchForwardingLog asserts that the switch writes the forwards it
+// settles to the forwarding log, along with the amounts needed to account for
+// the fees they earned.
+func TestSwitchForwardingLog(t *testing.T) {
+	t.Parallel()
+
+	alicePeer := newMockServer(t, "alice")
+	bobPeer := newMockServer(t, "bob")
+
+	aliceChannelLink := newMockChannelLink(chanID1, aliceChanID, alicePeer)
+	bobChannelLink := newMockChannelLink(chanID2, bobChanID, bobPeer)
+
+	fwdLog := &mockForwardingLog{}
+	s := New(Config{
+		UpdateTopology: func(msg *lnwire.ChannelUpdate) error {
+			return nil
+		},
+		PreimageCache: newMockPreimageCache(),
+		FwdEventLog:   fwdLog,
+	})
+	s.Start()
+	if err := s.AddLink(aliceChannelLink); err != nil {
+		t.Fatalf("unable to add alice link: %v", err)
+	}
+	if err := s.AddLink(bobChannelLink); err != nil {
+		t.Fatalf("unable to add bob link: %v", err)
+	}
+
+	// forward forwards an htlc from alice to bob, which bob then either
+	// settles or fails.
+	forward := func(preimage [sha256.Size]byte, settle bool) {
+		rhash := fastsha256.Sum256(preimage[:])
+		packet := newAddPacket(
+			aliceChannelLink.ShortChanID(),
+			bobChannelLink.ShortChanID(),
+			&lnwire.UpdateAddHTLC{
+				PaymentHash: rhash,
+				Amount:      1000,
+			}, newMockObfuscator(),
+		)
+		packet.incomingAmount = 1010
+		if err := s.forward(packet); err != nil {
+			t.Fatalf("unable to forward htlc: %v", err)
+		}
+
+		select {
+		case <-bobChannelLink.packets:
+		case <-time.After(time.Second):
+			t.Fatal("request was not propagated to destination")
+		}
+
+		if settle {
+			packet = newSettlePacket(
+				bobChannelLink.ShortChanID(),
+				&lnwire.UpdateFufillHTLC{
+					PaymentPreimage: preimage,
+				},
+				rhash, 1000,
+			)
+		} else {
+			packet = newFailPacket(
+				bobChannelLink.ShortChanID(),
+				&lnwire.UpdateFailHTLC{},
+				rhash, 1000, true,
+			)
+		}
+		if err := s.forward(packet); err != nil {
+			t.Fatalf("unable to forward htlc: %v", err)
+		}
+
+		select {
+		case <-aliceChannelLink.packets:
+		case <-time.After(time.Second):
+			t.Fatal("request was not propagated to source")
+		}
+	}
+
+	// Only the settled forward should be written to the log, which
+	// happens at the latest once the switch is stopped.
+	forward([sha256.Size]byte{1}, true)
+	forward([sha256.Size]byte{2}, false)
+	if err := s.Stop(); err != nil {
+		t.Fatalf("unable to stop switch: %v", err)
+	}
+
+	events := fwdLog.fetchEvents()
+	if len(events) != 1 {
+		t.Fatalf("expected 1 forwarding event, got %v", len(events))
+	}
+	event := events[0]
+	if event.IncomingChanID != aliceChanID ||
+		event.OutgoingChanID != bobChanID {
+
+		t.Fatalf("expected forward %v->%v, got %v->%v", aliceChanID,
+			bobChanID, event.IncomingChanID, event.OutgoingChanID)
+	}
+	if event.Fee() != 10 {
+		t.Fatalf("expected fee of 10, got %v", event.Fee())
+	}
+}
+
+// TestSwitchPruneForwardingLog asserts that the switch only removes the
+// forwards which have outlived the retention period from the forwarding log.
+func TestSwitchPruneForwardingLog(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	fwdLog := &mockForwardingLog{
+		events: []channeldb.ForwardingEvent{
+			{Timestamp: now.Add(-fwdEventRetention - time.Hour)},
+			{Timestamp: now.Add(-fwdEventRetention + time.Hour)},
+			{Timestamp: now},
+		},
+	}
+	s := New(Config{
+		FwdEventLog: fwdLog,
+	})
+
+	s.pruneForwardingEvents(now)
+
+	events := fwdLog.fetchEvents()
+	if len(events) != 2 {
+		t.Fatalf("expected 2 forwarding events, got %v", len(events))
+	}
+	for _, event := range events {
+		if now.Sub(event.Timestamp) > fwdEventRetention {
+			t.Fatalf("expected event at %v to be pruned",
+				event.Timestamp)
+		}
+	}
+}
+
+// TestSwitchProcessContractResolution checks that the resolution of an
+// outgoing HTLC on-chain is propagated back to the source of the HTLC, with
+// the failure of a timed out HTLC being wrapped in its initial encryption.
+func TestSwitchProcessContractResolution(t *testing.T) {
+	t.Parallel()
+
+	alicePeer := newMockServer(t, "alice")
+	bobPeer := newMockServer(t, "bob")
+
+	aliceChannelLink := newMockChannelLink(chanID1, aliceChanID, alicePeer)
+	bobChannelLink := newMockChannelLink(chanID2, bobChanID, bobPeer)
+
+	s := New(Config{
+		UpdateTopology: func(msg *lnwire.ChannelUpdate) error {
+			return nil
+		},
+		PreimageCache: newMockPreimageCache(),
+	})
+	s.Start()
+	defer s.Stop()
+	if err := s.AddLink(aliceChannelLink); err != nil {
+		t.Fatalf("unable to add alice link: %v", err)
+	}
+	if err := s.AddLink(bobChannelLink); err != nil {
+		t.Fatalf("unable to add bob link: %v", err)
+	}
+
+	// Forward two HTLCs from Alice to Bob, the first of which will be
+	// timed out on-chain, and the second claimed on-chain by Bob.
+	preimages := [][sha256.Size]byte{{1}, {2}}
+	var hashes [][sha256.Size]byte
+	for _, preimage := range preimages {
+		rhash := fastsha256.Sum256(preimage[:])
+		hashes = append(hashes, rhash)
+
+		packet := newAddPacket(
+			aliceChannelLink.ShortChanID(),
+			bobChannelLink.ShortChanID(),
+			&lnwire.UpdateAddHTLC{
+				PaymentHash: rhash,
+				Amount:      1,
+			}, newMockObfuscator(),
+		)
+		if err := s.forward(packet); err != nil {
+			t.Fatal(err)
+		}
+
+		select {
+		case <-bobChannelLink.packets:
+		case <-time.After(time.Second):
+			t.Fatal("request was not propagated to destination")
+		}
+	}
+
+	err := s.ProcessContractResolution(contractcourt.ResolutionMsg{
+		SourceChan: bobChanID,
+		PayHash:    hashes[0],
+		Amt:        1,
+		Failure:    &lnwire.FailPermanentChannelFailure{},
+	})
+	if err != nil {
+		t.Fatalf("unable to process resolution: %v", err)
+	}
+
+	// Alice should receive a failure which decodes to the failure of the
+	// resolution.
+	select {
+	case packet := <-aliceChannelLink.packets:
+		htlc, ok := packet.htlc.(*lnwire.UpdateFailHTLC)
+		if !ok {
+			t.Fatalf("expected fail htlc, got %T", packet.htlc)
+		}
+		_, failure, err := newMockDeobfuscator().Deobfuscate(
+			htlc.Reason,
+		)
+		if err != nil {
+			t.Fatalf("unable to decode failure: %v", err)
+		}
+		if _, ok := failure.(*lnwire.FailPermanentChannelFailure); !ok {
+			t.Fatalf("expected permanent channel failure, got %T",
+				failure)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("failure was not propagated to source")
+	}
+
+	err = s.ProcessContractResolution(contractcourt.ResolutionMsg{
+		SourceChan: bobChanID,
+		PayHash:    hashes[1],
+		Amt:        1,
+		PreImage:   &preimages[1],
+	})
+	if err != nil {
+		t.Fatalf("unable to process resolution: %v", err)
+	}
+
+	select {
+	case packet := <-aliceChannelLink.packets:
+		htlc, ok := packet.htlc.(*lnwire.UpdateFufillHTLC)
+		if !ok {
+			t.Fatalf("expected settle htlc, got %T", packet.htlc)
+		}
+		if htlc.PaymentPreimage != preimages[1] {
+			t.Fatalf("expected preimage %x, got %x", preimages[1],
+				htlc.PaymentPreimage)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("settle was not propagated to source")
+	}
+
+	if s.circuits.pending() != 0 {
+		t.Fatal("wrong amount of circuits")
+	}
+}
+
+// TestSwitchResolutionReplay checks that the resolution of an HTLC on-chain is
+// retained while the link it's to be sent back over is down, and replayed once
+// the link is back, as well as upon restart, even though the circuit of the
+// HTLC was opened before the restart.
+func TestSwitchResolutionReplay(t *testing.T) {
+	t.Parallel()
+
+	alicePeer := newMockServer(t, "alice")
+	bobPeer := newMockServer(t, "bob")
+
+	aliceChannelLink := newMockChannelLink(chanID1, aliceChanID, alicePeer)
+	bobChannelLink := newMockChannelLink(chanID2, bobChanID, bobPeer)
+
+	store := newMockResolutionStore()
+	circuitStore := newMockCircuitStore()
+	decodeObfuscator := func(io.Reader) (Obfuscator, lnwire.FailCode) {
+		return newMockObfuscator(), lnwire.CodeNone
+	}
+	s := New(Config{
+		UpdateTopology: func(msg *lnwire.ChannelUpdate) error {
+			return nil
+		},
+		PreimageCache:         newMockPreimageCache(),
+		ResolutionStore:       store,
+		CircuitStore:          circuitStore,
+		DecodeOnionObfuscator: decodeObfuscator,
+	})
+	s.Start()
+	defer s.Stop()
+	if err := s.AddLink(aliceChannelLink); err != nil {
+		t.Fatalf("unable to add alice link: %v", err)
+	}
+	if err := s.AddLink(bobChannelLink); err != nil {
+		t.Fatalf("unable to add bob link: %v", err)
+	}
+
+	// forwardHTLC forwards an HTLC from Alice to Bob, which Bob will later
+	// claim on-chain.
+	forwardHTLC := func(rhash [32]byte) {
+		packet := newAddPacket(
+			aliceChannelLink.ShortChanID(),
+			bobChannelLink.ShortChanID(),
+			&lnwire.UpdateAddHTLC{
+				PaymentHash: rhash,
+				Amount:      1,
+			}, newMockObfuscator(),
+		)
+		if err := s.forward(packet); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case <-bobChannelLink.packets:
+		case <-time.After(time.Second):
+			t.Fatal("request was not propagated to destination")
+		}
+	}
+
+	// assertSettle asserts that the HTLC is settled back to Alice.
+	assertSettle := func(link *mockChannelLink, preimage [32]byte) {
+		select {
+		case packet := <-link.packets:
+			htlc, ok := packet.htlc.(*lnwire.UpdateFufillHTLC)
+			if !ok {
+				t.Fatalf("expected settle htlc, got %T",
+					packet.htlc)
+			}
+			if htlc.PaymentPreimage != preimage {
+				t.Fatalf("expected preimage %x, got %x",
+					preimage, htlc.PaymentPreimage)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("settle was not propagated to source")
+		}
+	}
+
+	// The resolution is handled in the background, so we'll wait for the
+	// resolution and its circuit to be removed.
+	waitForResolved := func(s *Switch) {
+		timeout := time.After(time.Second)
+		for store.numPending() != 0 || s.circuits.pending() != 0 ||
+			circuitStore.numCircuits() != 0 {
+
+			select {
+			case <-time.After(10 * time.Millisecond):
+			case <-timeout:
+				t.Fatalf("resolution wasn't removed once handled")
+			}
+		}
+	}
+
+	preimage := [sha256.Size]byte{1}
+	rhash := fastsha256.Sum256(preimage[:])
+	forwardHTLC(rhash)
+	if circuitStore.numCircuits() != 1 {
+		t.Fatal("circuit wasn't persisted")
+	}
+
+	// With Alice's link down, the resolution can't be sent back, so it
+	// should be retained along with its circuit.
+	if err := s.RemoveLink(aliceChannelLink.ChanID()); err != nil {
+		t.Fatalf("unable to remove alice link: %v", err)
+	}
+	err := s.ProcessContractResolution(contractcourt.ResolutionMsg{
+		SourceChan: bobChanID,
+		PayHash:    rhash,
+		Amt:        1,
+		PreImage:   &preimage,
+	})
+	if err != nil {
+		t.Fatalf("unable to process resolution: %v", err)
+	}
+	if store.numPending() != 1 {
+		t.Fatalf("expected 1 pending resolution, got %v",
+			store.numPending())
+	}
+	if s.circuits.pending() != 1 || circuitStore.numCircuits() != 1 {
+		t.Fatal("wrong amount of circuits")
+	}
+
+	// Once Alice's link is back, the resolution should be replayed,
+	// settling the HTLC back to her.
+	if err := s.AddLink(aliceChannelLink); err != nil {
+		t.Fatalf("unable to add alice link: %v", err)
+	}
+	assertSettle(aliceChannelLink, preimage)
+	waitForResolved(s)
+
+	// Forward another HTLC, whose resolution is only persisted once the
+	// switch has been shut down.
+	preimage2 := [sha256.Size]byte{2}
+	rhash2 := fastsha256.Sum256(preimage2[:])
+	forwardHTLC(rhash2)
+	s.Stop()
+
+	err = store.AddResolutionMsg(&channeldb.ResolutionMsg{
+		SourceChan: bobChanID,
+		PayHash:    rhash2,
+		Amt:        1,
+		PreImage:   &preimage2,
+	})
+	if err != nil {
+		t.Fatalf("unable to add resolution: %v", err)
+	}
+
+	// Upon restart, the circuit of the HTLC should be restored, allowing
+	// the resolution to be sent back to Alice rather than being mistaken
+	// for the result of a locally initiated payment.
+	s2 := New(Config{
+		UpdateTopology: func(msg *lnwire.ChannelUpdate) error {
+			return nil
+		},
+		PreimageCache:         newMockPreimageCache(),
+		ResolutionStore:       store,
+		CircuitStore:          circuitStore,
+		DecodeOnionObfuscator: decodeObfuscator,
+	})
+	if err := s2.Start(); err != nil {
+		t.Fatalf("unable to start switch: %v", err)
+	}
+	defer s2.Stop()
+	if s2.circuits.pending() != 1 {
+		t.Fatal("circuit wasn't restored")
+	}
+
+	aliceChannelLink2 := newMockChannelLink(chanID1, aliceChanID, alicePeer)
+	if err := s2.AddLink(aliceChannelLink2); err != nil {
+		t.Fatalf("unable to add alice link: %v", err)
+	}
+	assertSettle(aliceChannelLink2, preimage2)
+	waitForResolved(s2)
+}
+
+// TestSwitchForwardBatch checks that the circuits opened and closed by a batch
+// of packets are persisted within a single update, and that the packets of a
+// batch whose circuits can't be persisted aren't forwarded.
+func TestSwitchForwardBatch(t *testing.T) {
+	t.Parallel()
+
+	alicePeer := newMockServer(t, "alice")
+	bobPeer := newMockServer(t, "bob")
+
+	aliceChannelLink := newMockChannelLink(chanID1, aliceChanID, alicePeer)
+	bobChannelLink := newMockChannelLink(chanID2, bobChanID, bobPeer)
+
+	circuitStore := newMockCircuitStore()
+	s := New(Config{
+		UpdateTopology: func(msg *lnwire.ChannelUpdate) error {
+			return nil
+		},
+		PreimageCache: newMockPreimageCache(),
+		CircuitStore:  circuitStore,
+	})
+	s.Start()
+	defer s.Stop()
+	if err := s.AddLink(aliceChannelLink); err != nil {
+		t.Fatalf("unable to add alice link: %v", err)
+	}
+	if err := s.AddLink(bobChannelLink); err != nil {
+		t.Fatalf("unable to add bob link: %v", err)
+	}
+
+	// forwardBatch forwards the packets as a single batch, returning the
+	// packets received by the passed link in the meantime.
+	forwardBatch := func(packets []*htlcPacket,
+		link *mockChannelLink) ([]*htlcPacket, []error) {
+
+		errChan := make(chan []error, 1)
+		go func() {
+			errChan <- s.forwardBatch(packets)
+		}()
+
+		var received []*htlcPacket
+		for {
+			select {
+			case packet := <-link.packets:
+				received = append(received, packet)
+			case errs := <-errChan:
+				// Failed back adds are sent asynchronously,
+				// so we'll give them a moment to arrive.
+				select {
+				case packet := <-link.packets:
+					received = append(received, packet)
+				case <-time.After(50 * time.Millisecond):
+				}
+				return received, errs
+			case <-time.After(time.Second):
+				t.Fatal("batch wasn't forwarded")
+			}
+		}
+	}
+
+	preimages := [][sha256.Size]byte{{1}, {2}}
+	var adds, settles []*htlcPacket
+	for _, preimage := range preimages {
+		rhash := fastsha256.Sum256(preimage[:])
+		adds = append(adds, newAddPacket(
+			aliceChannelLink.ShortChanID(),
+			bobChannelLink.ShortChanID(),
+			&lnwire.UpdateAddHTLC{
+				PaymentHash: rhash,
+				Amount:      1,
+			}, newMockObfuscator(),
+		))
+		settles = append(settles, newSettlePacket(
+			bobChannelLink.ShortChanID(),
+			&lnwire.UpdateFufillHTLC{
+				PaymentPreimage: preimage,
+			}, rhash, 1,
+		))
+	}
+
+	// Both adds should reach Bob, with their circuits persisted within a
+	// single update.
+	received, errs := forwardBatch(adds, bobChannelLink)
+	for _, err := range errs {
+		if err != nil {
+			t.Fatalf("unable to forward add: %v", err)
+		}
+	}
+	if len(received) != 2 {
+		t.Fatalf("expected 2 adds forwarded, got %v", len(received))
+	}
+	if circuitStore.numCircuits() != 2 || circuitStore.updates() != 1 {
+		t.Fatalf("expected 2 circuits written in 1 update, got %v "+
+			"in %v", circuitStore.numCircuits(),
+			circuitStore.updates())
+	}
+
+	// Should the circuits be unable to be closed, then the settles
+	// shouldn't be sent back, and the circuits should be restored.
+	circuitStore.setFailUpdates(true)
+	received, errs = forwardBatch(settles, aliceChannelLink)
+	for _, err := range errs {
+		if err == nil {
+			t.Fatal("expected settle to fail")
+		}
+	}
+	if len(received) != 0 {
+		t.Fatalf("expected no settles, got %v", len(received))
+	}
+	if s.circuits.pending() != 2 {
+		t.Fatalf("expected 2 circuits, got %v", s.circuits.pending())
+	}
+
+	// Once the circuits can be closed, both settles should reach Alice,
+	// with the circuits deleted within a single update.
+	circuitStore.setFailUpdates(false)
+	received, errs = forwardBatch(settles, aliceChannelLink)
+	for _, err := range errs {
+		if err != nil {
+			t.Fatalf("unable to forward settle: %v", err)
+		}
+	}
+	if len(received) != 2 {
+		t.Fatalf("expected 2 settles, got %v", len(received))
+	}
+	if s.circuits.pending() != 0 || circuitStore.numCircuits() != 0 ||
+		circuitStore.updates() != 2 {
+
+		t.Fatalf("expected circuits deleted in 1 update, got %v "+
+			"circuits after %v updates", circuitStore.numCircuits(),
+			circuitStore.updates())
+	}
+
+	// An add whose circuit can't be persisted should be failed back to
+	// Alice rather than forwarded.
+	circuitStore.setFailUpdates(true)
+	received, errs = forwardBatch(adds[:1], aliceChannelLink)
+	if errs[0] == nil {
+		t.Fatal("expected add to fail")
+	}
+	if len(received) != 1 {
+		t.Fatalf("expected add to be failed back, got %v packets",
+			len(received))
+	}
+	if _, ok := received[0].htlc.(*lnwire.UpdateFailHTLC); !ok {
+		t.Fatalf("expected fail htlc, got %T", received[0].htlc)
+	}
+	select {
+	case <-bobChannelLink.packets:
+		t.Fatal("add forwarded without its circuit")
+	default:
+	}
+	if s.circuits.pending() != 0 {
+		t.Fatalf("expected no circuits, got %v", s.circuits.pending())
+	}
+}
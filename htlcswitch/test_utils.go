@@ -274,7 +274,7 @@ func getChanID(msg lnwire.Message) lnwire.ChannelID {
 
 // generatePayment generates the htlc add request by given path blob and
 // invoice which should be added by destination peer.
-func generatePayment(invoiceAmt, htlcAmt lnwire.MilliSatoshi, timelock uint32,
+func generatePayment(invoiceAmt, htlcAmt lnwire.MilliAtom, timelock uint32,
 	blob [lnwire.OnionPacketSize]byte) (*channeldb.Invoice, *lnwire.UpdateAddHTLC, error) {
 
 	var preimage [sha256.Size]byte
@@ -343,8 +343,8 @@ type threeHopNetwork struct {
 // generateHops creates the per hop payload, the total amount to be sent, and
 // also the time lock value needed to route a HTLC with the target amount over
 // the specified path.
-func generateHops(payAmt lnwire.MilliSatoshi, startingHeight uint32,
-	path ...*channelLink) (lnwire.MilliSatoshi, uint32, []ForwardingInfo) {
+func generateHops(payAmt lnwire.MilliAtom, startingHeight uint32,
+	path ...*channelLink) (lnwire.MilliAtom, uint32, []ForwardingInfo) {
 
 	lastHop := path[len(path)-1]
 
@@ -413,7 +413,7 @@ func generateHops(payAmt lnwire.MilliSatoshi, startingHeight uint32,
 // * from Alice to some another peer through the Bob
 func (n *threeHopNetwork) makePayment(sendingPeer, receivingPeer Peer,
 	firstHopPub [33]byte, hops []ForwardingInfo,
-	invoiceAmt, htlcAmt lnwire.MilliSatoshi,
+	invoiceAmt, htlcAmt lnwire.MilliAtom,
 	timelock uint32) (*channeldb.Invoice, error) {
 
 	sender := sendingPeer.(*mockServer)
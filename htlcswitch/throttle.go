@@ -0,0 +1,165 @@
+package htlcswitch
+
+import (
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// ForwardLimit bounds the total value of the HTLCs which may be forwarded
+// within a sliding window of time. The zero value imposes no limit.
+type ForwardLimit struct {
+	// MaxAmount is the maximum total value of the HTLCs which may be
+	// forwarded within any window of the target interval.
+	MaxAmount lnwire.MilliAtom
+
+	// Interval is the duration of the sliding window.
+	Interval time.Duration
+}
+
+// enabled returns true if the limit should be enforced.
+func (l ForwardLimit) enabled() bool {
+	return l.MaxAmount > 0 && l.Interval > 0
+}
+
+// forwardEntry records the value of a single forwarded HTLC.
+type forwardEntry struct {
+	timestamp time.Time
+	amount    lnwire.MilliAtom
+}
+
+// forwardWindow tracks the total value of the HTLCs forwarded within the
+// current window of a ForwardLimit.
+type forwardWindow struct {
+	entries []forwardEntry
+	total   lnwire.MilliAtom
+}
+
+// prune removes all the entries which have fallen out of the window ending
+// at the passed time.
+func (w *forwardWindow) prune(now time.Time, interval time.Duration) {
+	cutoff := now.Add(-interval)
+
+	var i int
+	for i < len(w.entries) && !w.entries[i].timestamp.After(cutoff) {
+		w.total -= w.entries[i].amount
+		i++
+	}
+	w.entries = w.entries[i:]
+}
+
+// forwardThrottle enforces the set of ForwardLimits the switch has been
+// configured with, both across the entire switch and for each individual
+// outgoing channel and peer.
+//
+// NOTE: The throttle isn't safe for concurrent use, and must only be accessed
+// from the htlcForwarder goroutine.
+type forwardThrottle struct {
+	globalLimit  ForwardLimit
+	channelLimit ForwardLimit
+	peerLimit    ForwardLimit
+
+	global   forwardWindow
+	channels map[lnwire.ShortChannelID]*forwardWindow
+	peers    map[[33]byte]*forwardWindow
+
+	// now returns the current time, and is overridden within tests.
+	now func() time.Time
+}
+
+// newForwardThrottle creates a new forwardThrottle which enforces the limits
+// within the passed config.
+func newForwardThrottle(cfg *Config) *forwardThrottle {
+	return &forwardThrottle{
+		globalLimit:  cfg.GlobalForwardLimit,
+		channelLimit: cfg.ChannelForwardLimit,
+		peerLimit:    cfg.PeerForwardLimit,
+		channels:     make(map[lnwire.ShortChannelID]*forwardWindow),
+		peers:        make(map[[33]byte]*forwardWindow),
+		now:          time.Now,
+	}
+}
+
+// exceeds returns true if forwarding the target amount within the window
+// would exceed the limit. A nil window is treated as being empty.
+func exceeds(limit ForwardLimit, window *forwardWindow, now time.Time,
+	amt lnwire.MilliAtom) bool {
+
+	if !limit.enabled() || window == nil {
+		return limit.enabled() && amt > limit.MaxAmount
+	}
+
+	window.prune(now, limit.Interval)
+	return window.total+amt > limit.MaxAmount
+}
+
+// allowGlobal returns true if forwarding the target amount wouldn't exceed
+// the switch-wide limit.
+func (t *forwardThrottle) allowGlobal(amt lnwire.MilliAtom) bool {
+	return !exceeds(t.globalLimit, &t.global, t.now(), amt)
+}
+
+// allowLink returns true if forwarding the target amount over the passed
+// channel to the passed peer wouldn't exceed either the per-channel or
+// per-peer limits.
+func (t *forwardThrottle) allowLink(chanID lnwire.ShortChannelID,
+	peer [33]byte, amt lnwire.MilliAtom) bool {
+
+	now := t.now()
+
+	// Windows which are empty once pruned are removed from the index, so
+	// that the windows of idle channels and peers don't accumulate.
+	chanWindow := t.channels[chanID]
+	if exceeds(t.channelLimit, chanWindow, now, amt) {
+		return false
+	}
+	if chanWindow != nil && len(chanWindow.entries) == 0 {
+		delete(t.channels, chanID)
+	}
+
+	peerWindow := t.peers[peer]
+	if exceeds(t.peerLimit, peerWindow, now, amt) {
+		return false
+	}
+	if peerWindow != nil && len(peerWindow.entries) == 0 {
+		delete(t.peers, peer)
+	}
+
+	return true
+}
+
+// record adds the value of an HTLC which has been forwarded over the passed
+// channel to the passed peer to all the windows that track it.
+func (t *forwardThrottle) record(chanID lnwire.ShortChannelID, peer [33]byte,
+	amt lnwire.MilliAtom) {
+
+	entry := forwardEntry{
+		timestamp: t.now(),
+		amount:    amt,
+	}
+
+	if t.globalLimit.enabled() {
+		t.global.entries = append(t.global.entries, entry)
+		t.global.total += amt
+	}
+
+	if t.channelLimit.enabled() {
+		window, ok := t.channels[chanID]
+		if !ok {
+			window = &forwardWindow{}
+			t.channels[chanID] = window
+		}
+		window.entries = append(window.entries, entry)
+		window.total += amt
+	}
+
+	if t.peerLimit.enabled() {
+		window, ok := t.peers[peer]
+		if !ok {
+			window = &forwardWindow{}
+			t.peers[peer] = window
+		}
+		window.entries = append(window.entries, entry)
+		window.total += amt
+	}
+}
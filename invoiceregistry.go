@@ -3,11 +3,14 @@ package main
 import (
 	"bytes"
 	"crypto/sha256"
+	"fmt"
 	"sync"
 	"time"
 
 	"github.com/davecgh/go-spew/spew"
+	"github.com/lightningnetwork/lnd/amp"
 	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/htlcswitch"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/roasbeef/btcd/chaincfg/chainhash"
 	"github.com/roasbeef/btcutil"
@@ -40,6 +43,16 @@ type invoiceRegistry struct {
 	// should be only created/used when manual tests require an invoice
 	// that *all* nodes are able to fully settle.
 	debugInvoices map[chainhash.Hash]*channeldb.Invoice
+
+	// circularInvoices is a map which stores the volatile invoices that
+	// allow circular payments, sent from our own node back to itself, to
+	// be settled. These only exist for the duration of the payment.
+	circularInvoices map[chainhash.Hash]*channeldb.Invoice
+
+	// hodlResolvers maps the payment hash of every accepted hold invoice,
+	// and of every shard of an accepted AMP set, to the callback through
+	// which the HTLC held for it is resolved.
+	hodlResolvers map[chainhash.Hash]func(htlcswitch.HodlEvent)
 }
 
 // newInvoiceRegistry creates a new invoice registry. The invoice registry
@@ -50,6 +63,8 @@ func newInvoiceRegistry(cdb *channeldb.DB) *invoiceRegistry {
 	return &invoiceRegistry{
 		cdb:                 cdb,
 		debugInvoices:       make(map[chainhash.Hash]*channeldb.Invoice),
+		circularInvoices:    make(map[chainhash.Hash]*channeldb.Invoice),
+		hodlResolvers:       make(map[chainhash.Hash]func(htlcswitch.HodlEvent)),
 		notificationClients: make(map[uint32]*invoiceSubscription),
 	}
 }
@@ -78,6 +93,37 @@ func (i *invoiceRegistry) AddDebugInvoice(amt btcutil.Amount, preimage chainhash
 	}))
 }
 
+// AddCircularPayment adds a volatile invoice for a circular payment of the
+// specified amount, identified by the passed preimage, which allows the
+// payment to be settled once it arrives back at our node. The returned
+// function removes the invoice, and must be called once the payment has
+// completed.
+func (i *invoiceRegistry) AddCircularPayment(preimage [32]byte,
+	amt lnwire.MilliAtom) func() {
+
+	paymentHash := chainhash.Hash(sha256.Sum256(preimage[:]))
+
+	invoice := &channeldb.Invoice{
+		CreationDate: time.Now(),
+		Terms: channeldb.ContractTerm{
+			Value:           amt,
+			PaymentPreimage: preimage,
+		},
+	}
+
+	i.Lock()
+	i.circularInvoices[paymentHash] = invoice
+	i.Unlock()
+
+	ltndLog.Debugf("Adding circular payment invoice %x", paymentHash[:])
+
+	return func() {
+		i.Lock()
+		delete(i.circularInvoices, paymentHash)
+		i.Unlock()
+	}
+}
+
 // AddInvoice adds a regular invoice for the specified amount, identified by
 // the passed preimage. Additionally, any memo or receipt data provided will
 // also be stored on-disk. Once this invoice is added, subsystems within the
@@ -89,20 +135,29 @@ func (i *invoiceRegistry) AddInvoice(invoice *channeldb.Invoice) error {
 	}))
 
 	// TODO(roasbeef): also check in memory for quick lookups/settles?
-	return i.cdb.AddInvoice(invoice)
+	if err := i.cdb.AddInvoice(invoice); err != nil {
+		return err
+	}
 
-	// TODO(roasbeef): re-enable?
-	//go i.notifyClients(invoice, false)
+	// Now that the invoice has been added, and assigned an add index, we
+	// can notify any/all registered invoice notification clients.
+	i.notifyClients(invoice, false)
+
+	return nil
 }
 
 // lookupInvoice looks up an invoice by its payment hash (R-Hash), if found
 // then we're able to pull the funds pending within an HTLC.
 // TODO(roasbeef): ignore if settled?
 func (i *invoiceRegistry) LookupInvoice(rHash chainhash.Hash) (*channeldb.Invoice, error) {
-	// First check the in-memory debug invoice index to see if this is an
-	// existing invoice added for debugging.
+	// First check the in-memory debug and circular invoice indexes to see
+	// if this is an existing invoice added for debugging, or one of our
+	// own circular payments.
 	i.RLock()
 	invoice, ok := i.debugInvoices[rHash]
+	if !ok {
+		invoice, ok = i.circularInvoices[rHash]
+	}
 	i.RUnlock()
 
 	// If found, then simply return the invoice directly.
@@ -115,10 +170,13 @@ func (i *invoiceRegistry) LookupInvoice(rHash chainhash.Hash) (*channeldb.Invoic
 	return i.cdb.LookupInvoice(rHash)
 }
 
-// SettleInvoice attempts to mark an invoice as settled. If the invoice is a
-// debug invoice, then this method is a noop as debug invoices are never fully
-// settled.
-func (i *invoiceRegistry) SettleInvoice(rHash chainhash.Hash) error {
+// SettleInvoice attempts to mark an invoice as settled, recording the passed
+// HTLC as one of those which paid it. If the invoice is a debug or circular
+// invoice, then this method is a noop as such invoices are never persisted.
+// The settlement of a circular payment is recorded by the payment itself.
+func (i *invoiceRegistry) SettleInvoice(rHash chainhash.Hash,
+	htlc *channeldb.InvoiceHTLC) error {
+
 	ltndLog.Debugf("Settling invoice %x", rHash[:])
 
 	// First check the in-memory debug invoice index to see if this is an
@@ -131,11 +189,17 @@ func (i *invoiceRegistry) SettleInvoice(rHash chainhash.Hash) error {
 
 		return nil
 	}
+	if _, ok := i.circularInvoices[rHash]; ok {
+		i.RUnlock()
+
+		ltndLog.Debugf("Settled circular payment %x", rHash[:])
+		return nil
+	}
 	i.RUnlock()
 
 	// If this isn't a debug invoice, then we'll attempt to settle an
 	// invoice matching this rHash on disk (if one exists).
-	if err := i.cdb.SettleInvoice(rHash); err != nil {
+	if err := i.cdb.SettleInvoice(rHash, htlc); err != nil {
 		return err
 	}
 
@@ -156,6 +220,337 @@ func (i *invoiceRegistry) SettleInvoice(rHash chainhash.Hash) error {
 	return nil
 }
 
+// AcceptInvoice marks the hold invoice matching the passed payment hash as
+// accepted, recording the passed HTLC as the one paying it. The HTLC is held
+// until the invoice is either settled through SettleHoldInvoice or canceled
+// through CancelInvoice, at which point the passed resolver is invoked.
+func (i *invoiceRegistry) AcceptInvoice(rHash chainhash.Hash,
+	htlc *channeldb.InvoiceHTLC, resolver func(htlcswitch.HodlEvent)) error {
+
+	ltndLog.Debugf("Accepting hold invoice %x", rHash[:])
+
+	i.Lock()
+	defer i.Unlock()
+
+	if err := i.cdb.AcceptInvoice(rHash, htlc); err != nil {
+		return err
+	}
+
+	i.hodlResolvers[rHash] = resolver
+
+	return nil
+}
+
+// ResumeHodlHtlc registers the passed resolver for the hold invoice matching
+// the passed payment hash, which was accepted before the link holding its
+// HTLC was restarted. Should the invoice have been settled or canceled in the
+// meantime, or never have been accepted, the resolver is invoked right away.
+func (i *invoiceRegistry) ResumeHodlHtlc(rHash chainhash.Hash,
+	resolver func(htlcswitch.HodlEvent)) error {
+
+	ltndLog.Debugf("Resuming hold invoice %x", rHash[:])
+
+	i.Lock()
+	defer i.Unlock()
+
+	// The HTLC may instead carry a shard of an AMP set, which is resolved
+	// along with the rest of its set.
+	set, err := i.cdb.LookupAMPSet(rHash)
+	switch {
+	case err == nil:
+		i.resumeAMPShard(set, rHash, resolver)
+		return nil
+
+	case err != channeldb.ErrAMPSetNotFound:
+		return err
+	}
+
+	invoice, err := i.cdb.LookupInvoice(rHash)
+	switch {
+	case err == channeldb.ErrInvoiceNotFound ||
+		err == channeldb.ErrNoInvoicesCreated:
+
+		go resolver(htlcswitch.HodlEvent{
+			PaymentHash: rHash,
+		})
+		return nil
+
+	case err != nil:
+		return err
+	}
+
+	switch invoice.Terms.State {
+	case channeldb.ContractAccepted:
+		i.hodlResolvers[rHash] = resolver
+
+	case channeldb.ContractSettled:
+		preimage := invoice.Terms.PaymentPreimage
+		go resolver(htlcswitch.HodlEvent{
+			PaymentHash: rHash,
+			Preimage:    &preimage,
+		})
+
+	default:
+		go resolver(htlcswitch.HodlEvent{
+			PaymentHash: rHash,
+		})
+	}
+
+	return nil
+}
+
+// SettleHoldInvoice settles the accepted hold invoice paid to the hash of the
+// passed preimage, settling the HTLC held for it.
+func (i *invoiceRegistry) SettleHoldInvoice(preimage [32]byte) error {
+	rHash := chainhash.Hash(sha256.Sum256(preimage[:]))
+
+	ltndLog.Debugf("Settling hold invoice %x", rHash[:])
+
+	i.Lock()
+	if err := i.cdb.SettleHoldInvoice(preimage); err != nil {
+		i.Unlock()
+		return err
+	}
+
+	resolver, ok := i.hodlResolvers[rHash]
+	delete(i.hodlResolvers, rHash)
+	i.Unlock()
+
+	if ok {
+		go resolver(htlcswitch.HodlEvent{
+			PaymentHash: rHash,
+			Preimage:    &preimage,
+		})
+	}
+
+	go func() {
+		invoice, err := i.cdb.LookupInvoice(rHash)
+		if err != nil {
+			ltndLog.Errorf("unable to find invoice: %v", err)
+			return
+		}
+
+		ltndLog.Infof("Payment received: %v", spew.Sdump(invoice))
+
+		i.notifyClients(invoice, true)
+	}()
+
+	return nil
+}
+
+// CancelInvoice cancels the invoice matching the passed payment hash, after
+// which it can no longer be paid. Should an HTLC be held for the invoice, it's
+// failed back.
+func (i *invoiceRegistry) CancelInvoice(rHash chainhash.Hash) error {
+	ltndLog.Debugf("Canceling invoice %x", rHash[:])
+
+	i.Lock()
+
+	// Should the payment hash be that of a shard of an AMP set, then the
+	// whole set is canceled.
+	set, err := i.cdb.LookupAMPSet(rHash)
+	switch {
+	case err == nil:
+		err := i.cancelAMPSet(set)
+		i.Unlock()
+		return err
+
+	case err != channeldb.ErrAMPSetNotFound:
+		i.Unlock()
+		return err
+	}
+
+	if err := i.cdb.CancelInvoice(rHash); err != nil {
+		i.Unlock()
+		return err
+	}
+
+	resolver, ok := i.hodlResolvers[rHash]
+	delete(i.hodlResolvers, rHash)
+	i.Unlock()
+
+	if ok {
+		go resolver(htlcswitch.HodlEvent{
+			PaymentHash: rHash,
+		})
+	}
+
+	return nil
+}
+
+// AcceptAMPShard adds the passed shard of an AMP payment of the passed total
+// amount to its set, recording the passed HTLC as the one carrying it. The
+// HTLC is held until its set is either settled or canceled, at which point the
+// passed resolver is invoked. Shards are combined no matter the link they
+// arrive over. Once the shards of the set carry the total amount of the
+// payment, the preimages of all of them are reconstructed, and the set is
+// settled at once. Should any shard not pay to the hash derived for it, then
+// the set is canceled instead.
+func (i *invoiceRegistry) AcceptAMPShard(shard *amp.Shard,
+	total lnwire.MilliAtom, htlc *channeldb.InvoiceHTLC,
+	resolver func(htlcswitch.HodlEvent)) error {
+
+	setID := shard.Record.SetID
+
+	ltndLog.Debugf("Accepting shard %x of amp set %x", shard.Hash[:],
+		setID[:])
+
+	i.Lock()
+	defer i.Unlock()
+
+	set, err := i.cdb.FetchAMPSet(setID)
+	switch {
+	case err == channeldb.ErrAMPSetNotFound:
+		set = &channeldb.AMPSet{
+			SetID: setID,
+			Total: total,
+			State: channeldb.ContractAccepted,
+		}
+
+	case err != nil:
+		return err
+
+	case set.State != channeldb.ContractAccepted:
+		return fmt.Errorf("amp set %x is already %v", setID[:],
+			set.State)
+	}
+
+	// The shards received so far are added to a fresh set first, so that
+	// the new shard is validated against all of them.
+	ampSet := amp.NewSet(setID, set.Total)
+	for _, s := range set.Shards {
+		err := ampSet.AddShard(&amp.Shard{
+			Hash:   s.PaymentHash,
+			Amt:    s.Htlc.Amt,
+			Record: lnwire.NewAMP(s.RootShare, setID, s.ChildIndex),
+		}, set.Total)
+		if err != nil {
+			return err
+		}
+	}
+	if err := ampSet.AddShard(shard, total); err != nil {
+		return err
+	}
+
+	set.Shards = append(set.Shards, channeldb.AMPShard{
+		Htlc:        *htlc,
+		PaymentHash: shard.Hash,
+		RootShare:   shard.Record.RootShare,
+		ChildIndex:  shard.Record.ChildIndex,
+	})
+	if err := i.cdb.PutAMPSet(set); err != nil {
+		return err
+	}
+	i.hodlResolvers[chainhash.Hash(shard.Hash)] = resolver
+
+	if !ampSet.IsComplete() {
+		ltndLog.Debugf("Holding shard %x of amp set %x, received %v "+
+			"of %v", shard.Hash[:], setID[:], ampSet.Received(),
+			ampSet.Total)
+		return nil
+	}
+
+	preimages, err := ampSet.Settle()
+	if err != nil {
+		ltndLog.Errorf("Unable to settle amp set %x, canceling it: %v",
+			setID[:], err)
+		return i.cancelAMPSet(set)
+	}
+
+	if err := i.cdb.SettleAMPSet(setID, preimages); err != nil {
+		return err
+	}
+
+	for idx, s := range set.Shards {
+		rHash := chainhash.Hash(s.PaymentHash)
+		resolver, ok := i.hodlResolvers[rHash]
+		if !ok {
+			continue
+		}
+		delete(i.hodlResolvers, rHash)
+
+		preimage := preimages[idx]
+		go resolver(htlcswitch.HodlEvent{
+			PaymentHash: s.PaymentHash,
+			Preimage:    &preimage,
+		})
+	}
+
+	// The set is recorded as an invoice paid to the hash of its first
+	// shard, which we'll notify any registered clients of.
+	go func() {
+		invoice, err := i.cdb.LookupInvoice(set.Shards[0].PaymentHash)
+		if err != nil {
+			ltndLog.Errorf("unable to find invoice: %v", err)
+			return
+		}
+
+		ltndLog.Infof("AMP payment received: %v", spew.Sdump(invoice))
+
+		i.notifyClients(invoice, true)
+	}()
+
+	return nil
+}
+
+// resumeAMPShard registers the passed resolver for the shard of the passed
+// set with the passed payment hash, should the set still be accepted.
+// Otherwise, the resolver is invoked right away.
+//
+// NOTE: The registry's lock MUST be held when calling this method.
+func (i *invoiceRegistry) resumeAMPShard(set *channeldb.AMPSet,
+	rHash chainhash.Hash, resolver func(htlcswitch.HodlEvent)) {
+
+	switch set.State {
+	case channeldb.ContractAccepted:
+		i.hodlResolvers[rHash] = resolver
+		return
+
+	case channeldb.ContractSettled:
+		for idx, s := range set.Shards {
+			if s.PaymentHash != rHash || idx >= len(set.Preimages) {
+				continue
+			}
+
+			preimage := set.Preimages[idx]
+			go resolver(htlcswitch.HodlEvent{
+				PaymentHash: rHash,
+				Preimage:    &preimage,
+			})
+			return
+		}
+	}
+
+	go resolver(htlcswitch.HodlEvent{
+		PaymentHash: rHash,
+	})
+}
+
+// cancelAMPSet cancels the passed AMP set, failing back the HTLCs held for
+// each of its shards.
+//
+// NOTE: The registry's lock MUST be held when calling this method.
+func (i *invoiceRegistry) cancelAMPSet(set *channeldb.AMPSet) error {
+	if err := i.cdb.CancelAMPSet(set.SetID); err != nil {
+		return err
+	}
+
+	for _, s := range set.Shards {
+		rHash := chainhash.Hash(s.PaymentHash)
+		resolver, ok := i.hodlResolvers[rHash]
+		if !ok {
+			continue
+		}
+		delete(i.hodlResolvers, rHash)
+
+		go resolver(htlcswitch.HodlEvent{
+			PaymentHash: s.PaymentHash,
+		})
+	}
+
+	return nil
+}
+
 // notifyClients notifies all currently registered invoice notification clients
 // of a newly added/settled invoice.
 func (i *invoiceRegistry) notifyClients(invoice *channeldb.Invoice, settle bool) {
@@ -170,9 +565,12 @@ func (i *invoiceRegistry) notifyClients(invoice *channeldb.Invoice, settle bool)
 			eventChan = client.NewInvoices
 		}
 
-		go func() {
-			eventChan <- invoice
-		}()
+		go func(client *invoiceSubscription) {
+			select {
+			case eventChan <- invoice:
+			case <-client.quit:
+			}
+		}(client)
 	}
 }
 
@@ -181,12 +579,18 @@ func (i *invoiceRegistry) notifyClients(invoice *channeldb.Invoice, settle bool)
 // will be sent over the NewInvoices channel. Similarly, for each newly settled
 // invoice, a copy of the invoice will be sent over the SettledInvoices
 // channel.
+//
+// NOTE: As notifications are dispatched concurrently, they may arrive out of
+// order. Callers that require strict ordering should use the AddIndex and
+// SettleIndex of each invoice.
 type invoiceSubscription struct {
 	NewInvoices     chan *channeldb.Invoice
 	SettledInvoices chan *channeldb.Invoice
 
 	inv *invoiceRegistry
 	id  uint32
+
+	quit chan struct{}
 }
 
 // Cancel unregisters the invoiceSubscription, freeing any previously allocated
@@ -195,6 +599,8 @@ func (i *invoiceSubscription) Cancel() {
 	i.inv.clientMtx.Lock()
 	delete(i.inv.notificationClients, i.id)
 	i.inv.clientMtx.Unlock()
+
+	close(i.quit)
 }
 
 // SubscribeNotifications returns an invoiceSubscription which allows the
@@ -205,6 +611,7 @@ func (i *invoiceRegistry) SubscribeNotifications() *invoiceSubscription {
 		NewInvoices:     make(chan *channeldb.Invoice),
 		SettledInvoices: make(chan *channeldb.Invoice),
 		inv:             i,
+		quit:            make(chan struct{}),
 	}
 
 	i.clientMtx.Lock()
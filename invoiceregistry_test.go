@@ -0,0 +1,149 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/amp"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/htlcswitch"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+)
+
+// TestInvoiceRegistryAMPShards tests that the invoice registry combines the
+// shards of an atomic multi-path payment, settling all of them once the set
+// is complete, even if the registry was restarted in the meantime.
+func TestInvoiceRegistryAMPShards(t *testing.T) {
+	t.Parallel()
+
+	tempDirName, err := ioutil.TempDir("", "channeldb")
+	if err != nil {
+		t.Fatalf("unable to initialize temp "+
+			"directory for channeldb: %v", err)
+	}
+	defer os.RemoveAll(tempDirName)
+
+	db, err := channeldb.Open(tempDirName)
+	if err != nil {
+		t.Fatalf("unable to open channeldb: %v", err)
+	}
+	defer db.Close()
+
+	const total = lnwire.MilliAtom(2000)
+	setID := [32]byte{1}
+	share1, share2 := amp.Share{1}, amp.Share{2}
+	root := share1.Xor(share2)
+	child1 := amp.DeriveChild(root, amp.ChildDesc{Share: share1, Index: 0})
+	child2 := amp.DeriveChild(root, amp.ChildDesc{Share: share2, Index: 1})
+
+	newShard := func(child *amp.Child) *amp.Shard {
+		return &amp.Shard{
+			Hash: child.Hash,
+			Amt:  total / 2,
+			Record: lnwire.NewAMP(
+				[32]byte(child.Share), setID, child.Index,
+			),
+		}
+	}
+	newHtlc := func(htlcID uint64) *channeldb.InvoiceHTLC {
+		return &channeldb.InvoiceHTLC{
+			ChanID: lnwire.NewShortChanIDFromInt(htlcID),
+			HtlcID: htlcID,
+			Amt:    total / 2,
+			Expiry: 100,
+		}
+	}
+
+	events := make(chan htlcswitch.HodlEvent, 2)
+	resolver := func(event htlcswitch.HodlEvent) {
+		events <- event
+	}
+
+	// The first shard should be held, as the set isn't complete yet.
+	invoices := newInvoiceRegistry(db)
+	err = invoices.AcceptAMPShard(
+		newShard(child1), total, newHtlc(1), resolver,
+	)
+	if err != nil {
+		t.Fatalf("unable to accept shard: %v", err)
+	}
+	select {
+	case event := <-events:
+		t.Fatalf("shard resolved before set was complete: %v", event)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// After a restart, the held shard is resumed, and the second shard
+	// arrives, which should settle both of them.
+	invoices = newInvoiceRegistry(db)
+	err = invoices.ResumeHodlHtlc(chainhash.Hash(child1.Hash), resolver)
+	if err != nil {
+		t.Fatalf("unable to resume shard: %v", err)
+	}
+	err = invoices.AcceptAMPShard(
+		newShard(child2), total, newHtlc(2), resolver,
+	)
+	if err != nil {
+		t.Fatalf("unable to accept shard: %v", err)
+	}
+
+	expected := map[[32]byte][32]byte{
+		child1.Hash: child1.Preimage,
+		child2.Hash: child2.Preimage,
+	}
+	for range expected {
+		select {
+		case event := <-events:
+			if event.Preimage == nil {
+				t.Fatalf("shard %x failed back", event.PaymentHash)
+			}
+			if *event.Preimage != expected[event.PaymentHash] {
+				t.Fatalf("expected preimage %x, got %x",
+					expected[event.PaymentHash],
+					*event.Preimage)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("shard wasn't settled")
+		}
+	}
+
+	// The payment should have been recorded as a settled invoice.
+	invoice, err := invoices.LookupInvoice(chainhash.Hash(child1.Hash))
+	if err != nil {
+		t.Fatalf("unable to look up invoice of set: %v", err)
+	}
+	if invoice.Terms.State != channeldb.ContractSettled {
+		t.Fatalf("expected invoice to be settled, is %v",
+			invoice.Terms.State)
+	}
+	if invoice.AmtPaid() != total {
+		t.Fatalf("expected invoice to be paid %v, got %v", total,
+			invoice.AmtPaid())
+	}
+
+	// Resuming a shard of the settled set should settle it right away.
+	err = invoices.ResumeHodlHtlc(chainhash.Hash(child2.Hash), resolver)
+	if err != nil {
+		t.Fatalf("unable to resume shard: %v", err)
+	}
+	select {
+	case event := <-events:
+		if event.Preimage == nil || *event.Preimage != child2.Preimage {
+			t.Fatalf("expected shard to be settled with %x, got %v",
+				child2.Preimage, event.Preimage)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("resumed shard wasn't settled")
+	}
+
+	// No further shards should be accepted for the settled set.
+	err = invoices.AcceptAMPShard(
+		newShard(child2), total, newHtlc(3), resolver,
+	)
+	if err == nil {
+		t.Fatalf("expected shard of settled set to be rejected")
+	}
+}
@@ -31,11 +31,13 @@ import (
 	proxy "github.com/grpc-ecosystem/grpc-gateway/runtime"
 	"github.com/lightningnetwork/lnd/autopilot"
 	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/htlcswitch/hodl"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/macaroons"
 	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/txscript"
 	"github.com/roasbeef/btcutil"
 )
 
@@ -78,6 +80,13 @@ func lndMain() error {
 	// Show version at startup.
 	ltndLog.Infof("Version %s", version())
 
+	// As the hodl flags cause the links to hold HTLCs indefinitely, we'll
+	// loudly warn about any which are active.
+	if hodlMask := cfg.Hodl.Mask(); hodlMask != hodl.MaskNone {
+		ltndLog.Warnf("Hodl flags are active, HTLCs will be held: %v",
+			hodlMask)
+	}
+
 	// Enable http profiling server if requested.
 	if cfg.Profile != "" {
 		go func() {
@@ -89,9 +98,78 @@ func lndMain() error {
 		}()
 	}
 
+	// Ensure we create TLS key and certificate if they don't exist
+	if !fileExists(cfg.TLSCertPath) && !fileExists(cfg.TLSKeyPath) {
+		if err := genCertPair(cfg.TLSCertPath, cfg.TLSKeyPath); err != nil {
+			return err
+		}
+	}
+
+	cert, err := tls.LoadX509KeyPair(cfg.TLSCertPath, cfg.TLSKeyPath)
+	if err != nil {
+		return err
+	}
+	tlsConf := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		/*
+		 * These cipher suites fit the following criteria:
+		 * - Don't use outdated algorithms like SHA-1 and 3DES
+		 * - Don't use ECB mode or other insecure symmetric methods
+		 * - Included in the TLS v1.2 suite
+		 * - Are available in the Go 1.7.6 standard library (more are
+		 *   available in 1.8.3 and will be added after lnd no longer
+		 *   supports 1.7, including suites that support CBC mode)
+		 *
+		 * The cipher suites are ordered from strongest to weakest
+		 * primitives, but the client's preference order has more
+		 * effect during negotiation.
+		**/
+		// TODO(aakselrod): add more cipher suites when 1.7 isn't
+		// supported.
+		CipherSuites: []uint16{
+			tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_RSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_RSA_WITH_AES_128_GCM_SHA256,
+		},
+		MinVersion: tls.VersionTLS12,
+	}
+	sCreds := credentials.NewTLS(tlsConf)
+	opts := []grpc.ServerOption{grpc.Creds(sCreds)}
+
+	// Before opening the channeldb, which may require applying lengthy
+	// schema migrations, we'll start a gRPC server which only serves the
+	// DatabaseState service. This allows the progress of any migrations
+	// to be monitored, rather than the daemon appearing to hang on
+	// startup. Once the database is open, it's replaced by the main gRPC
+	// server below.
+	grpcEndpoint := fmt.Sprintf("localhost:%d", loadedConfig.RPCPort)
+	stopDBStateServer, err := startDBStateServer(grpcEndpoint, opts)
+	if err != nil {
+		ltndLog.Errorf("unable to start database state server: %v", err)
+		return err
+	}
+
+	// If requested, compact the channeldb before opening it, reclaiming
+	// any free space left behind within the database file.
+	if cfg.DB.Compact {
+		_, err := channeldb.Compact(cfg.DataDir, cfg.DB.CompactThreshold)
+		if err != nil {
+			stopDBStateServer()
+			ltndLog.Errorf("unable to compact channeldb: %v", err)
+			return err
+		}
+	}
+
 	// Open the channeldb, which is dedicated to storing channel, and
 	// network related metadata.
-	chanDB, err := channeldb.Open(cfg.DataDir)
+	chanDB, err := channeldb.Open(
+		cfg.DataDir,
+		channeldb.OptionSetBatchCommitInterval(cfg.DB.BatchCommitInterval),
+	)
+	stopDBStateServer()
 	if err != nil {
 		ltndLog.Errorf("unable to open channeldb: %v", err)
 		return err
@@ -163,6 +241,22 @@ func lndMain() error {
 	if _, err := rand.Read(chanIDSeed[:]); err != nil {
 		return err
 	}
+	// If enabled, we'll commit to paying out to a fresh wallet address
+	// upon the cooperative close of each channel extended to us.
+	var upfrontShutdownScript func() (lnwire.DeliveryAddress, error)
+	if cfg.EnableUpfrontShutdown {
+		upfrontShutdownScript = func() (lnwire.DeliveryAddress, error) {
+			addr, err := activeChainControl.wallet.NewAddress(
+				lnwallet.WitnessPubKey, false,
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			return txscript.PayToAddrScript(addr)
+		}
+	}
+
 	fundingMgr, err := newFundingManager(fundingConfig{
 		IDKey:        idPrivKey.PubKey(),
 		Wallet:       activeChainControl.wallet,
@@ -171,6 +265,13 @@ func lndMain() error {
 		SignMessage: func(pubKey *btcec.PublicKey,
 			msg []byte) (*btcec.Signature, error) {
 
+			// If an external signer has been configured, then
+			// it's responsible for both our identity and funding
+			// keys.
+			if server.extSigner != nil {
+				return server.extSigner.SignMessage(pubKey, msg)
+			}
+
 			if pubKey.IsEqual(idPrivKey.PubKey()) {
 				return nodeSigner.SignMessage(pubKey, msg)
 			}
@@ -188,6 +289,7 @@ func lndMain() error {
 			return <-errChan
 		},
 		ArbiterChan:    server.breachArbiter.newContracts,
+		EventBus:       server.eventBus,
 		SendToPeer:     server.SendToPeer,
 		FindPeer:       server.FindPeer,
 		TempChanIDSeed: chanIDSeed,
@@ -210,7 +312,7 @@ func lndMain() error {
 			return nil, fmt.Errorf("unable to find channel")
 		},
 		DefaultRoutingPolicy: activeChainControl.routingPolicy,
-		NumRequiredConfs: func(chanAmt btcutil.Amount, pushAmt lnwire.MilliSatoshi) uint16 {
+		NumRequiredConfs: func(chanAmt btcutil.Amount, pushAmt lnwire.MilliAtom) uint16 {
 			// TODO(roasbeef): add configurable mapping
 			//  * simple switch initially
 			//  * assign coefficient, etc
@@ -221,6 +323,7 @@ func lndMain() error {
 			// configuration
 			return 4
 		},
+		UpfrontShutdownScript: upfrontShutdownScript,
 	})
 	if err != nil {
 		return err
@@ -230,57 +333,17 @@ func lndMain() error {
 	}
 	server.fundingMgr = fundingMgr
 
-	// Ensure we create TLS key and certificate if they don't exist
-	if !fileExists(cfg.TLSCertPath) && !fileExists(cfg.TLSKeyPath) {
-		if err := genCertPair(cfg.TLSCertPath, cfg.TLSKeyPath); err != nil {
-			return err
-		}
-	}
-
 	// Initialize, and register our implementation of the gRPC interface
 	// exported by the rpcServer.
 	rpcServer := newRPCServer(server, macaroonService)
 	if err := rpcServer.Start(); err != nil {
 		return err
 	}
-	cert, err := tls.LoadX509KeyPair(cfg.TLSCertPath, cfg.TLSKeyPath)
-	if err != nil {
-		return err
-	}
-	tlsConf := &tls.Config{
-		Certificates: []tls.Certificate{cert},
-		/*
-		 * These cipher suites fit the following criteria:
-		 * - Don't use outdated algorithms like SHA-1 and 3DES
-		 * - Don't use ECB mode or other insecure symmetric methods
-		 * - Included in the TLS v1.2 suite
-		 * - Are available in the Go 1.7.6 standard library (more are
-		 *   available in 1.8.3 and will be added after lnd no longer
-		 *   supports 1.7, including suites that support CBC mode)
-		 *
-		 * The cipher suites are ordered from strongest to weakest
-		 * primitives, but the client's preference order has more
-		 * effect during negotiation.
-		**/
-		// TODO(aakselrod): add more cipher suites when 1.7 isn't
-		// supported.
-		CipherSuites: []uint16{
-			tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
-			tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
-			tls.TLS_RSA_WITH_AES_256_GCM_SHA384,
-			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
-			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
-			tls.TLS_RSA_WITH_AES_128_GCM_SHA256,
-		},
-		MinVersion: tls.VersionTLS12,
-	}
-	sCreds := credentials.NewTLS(tlsConf)
-	opts := []grpc.ServerOption{grpc.Creds(sCreds)}
 	grpcServer := grpc.NewServer(opts...)
 	lnrpc.RegisterLightningServer(grpcServer, rpcServer)
+	lnrpc.RegisterDatabaseStateServer(grpcServer, &dbStateServer{})
 
 	// Next, Start the gRPC server listening for HTTP/2 connections.
-	grpcEndpoint := fmt.Sprintf("localhost:%d", loadedConfig.RPCPort)
 	lis, err := net.Listen("tcp", grpcEndpoint)
 	if err != nil {
 		fmt.Printf("failed to listen: %v", err)
@@ -307,6 +370,11 @@ func lndMain() error {
 	if err != nil {
 		return err
 	}
+	err = lnrpc.RegisterDatabaseStateHandlerFromEndpoint(ctx, mux,
+		grpcEndpoint, proxyOpts)
+	if err != nil {
+		return err
+	}
 	go func() {
 		restEndpoint := fmt.Sprintf(":%d", loadedConfig.RESTPort)
 		listener, err := tls.Listen("tcp", restEndpoint, tlsConf)
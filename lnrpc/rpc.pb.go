@@ -5,9 +5,13 @@
 Package lnrpc is a generated protocol buffer package.
 
 It is generated from these files:
+
 	rpc.proto
 
 It has these top-level messages:
+
+	MigrationStatusRequest
+	MigrationStatusResponse
 	Transaction
 	GetTransactionsRequest
 	TransactionDetails
@@ -34,6 +38,10 @@ It has these top-level messages:
 	ActiveChannel
 	ListChannelsRequest
 	ListChannelsResponse
+	HTLCResolution
+	ChannelCloseSummary
+	ClosedChannelsRequest
+	ClosedChannelsResponse
 	Peer
 	ListPeersRequest
 	ListPeersResponse
@@ -78,12 +86,15 @@ It has these top-level messages:
 	SetAliasRequest
 	SetAliasResponse
 	Invoice
+	InvoiceHTLC
 	AddInvoiceResponse
 	PaymentHash
 	ListInvoiceRequest
 	ListInvoiceResponse
+	SettleEventAck
 	InvoiceSubscription
 	Payment
+	HTLCAttempt
 	ListPaymentsRequest
 	ListPaymentsResponse
 	DeleteAllPaymentsRequest
@@ -97,6 +108,55 @@ It has these top-level messages:
 	FeeReportResponse
 	FeeUpdateRequest
 	FeeUpdateResponse
+	BlacklistedNode
+	ListBlacklistRequest
+	ListBlacklistResponse
+	UpdateBlacklistRequest
+	UpdateBlacklistResponse
+	CommitmentTxnsRequest
+	CommitmentTxnsResponse
+	ExportChannelDBRequest
+	ChannelDBChunk
+	SendCustomMessageRequest
+	SendCustomMessageResponse
+	SubscribeCustomMessagesRequest
+	CustomMessage
+	NodeHistory
+	PairHistory
+	QueryMissionControlRequest
+	QueryMissionControlResponse
+	ResetMissionControlRequest
+	ResetMissionControlResponse
+	XImportMissionControlRequest
+	XImportMissionControlResponse
+	BuildRouteRequest
+	BuildRouteResponse
+	SendToRouteRequest
+	RouteFailure
+	SendToRouteResponse
+	QueryProbabilityRequest
+	QueryProbabilityResponse
+	EdgeLocator
+	PolicyUpdateRequest
+	PolicyUpdateResponse
+	ForwardHtlcInterceptRequest
+	ForwardHtlcInterceptResponse
+	SettleInvoiceMsg
+	SettleInvoiceResp
+	CancelInvoiceMsg
+	CancelInvoiceResp
+	SubscribeHtlcEventsRequest
+	HtlcEvent
+	PendingSweep
+	PendingSweepsRequest
+	PendingSweepsResponse
+	BumpFeeRequest
+	BumpFeeResponse
+	PendingHTLC
+	RemoveSettleConsumerRequest
+	RemoveSettleConsumerResponse
+	HopHint
+	RouteHint
 */
 package lnrpc
 
@@ -121,6 +181,82 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
+type PaymentFailureReason int32
+
+const (
+	// / The payment hasn't failed.
+	PaymentFailureReason_FAILURE_REASON_NONE PaymentFailureReason = 0
+	// / The payment timed out before a successful payment attempt was made.
+	PaymentFailureReason_FAILURE_REASON_TIMEOUT PaymentFailureReason = 1
+	// / No route to the destination with sufficient capacity was found.
+	PaymentFailureReason_FAILURE_REASON_NO_ROUTE PaymentFailureReason = 2
+	// / An unexpected error occurred while attempting the payment.
+	PaymentFailureReason_FAILURE_REASON_ERROR PaymentFailureReason = 3
+	// *
+	// The destination rejected the payment hash, amount, or final expiry of the
+	// payment.
+	PaymentFailureReason_FAILURE_REASON_INCORRECT_PAYMENT_DETAILS PaymentFailureReason = 4
+	// / None of our channels had sufficient balance to send the payment.
+	PaymentFailureReason_FAILURE_REASON_INSUFFICIENT_BALANCE PaymentFailureReason = 5
+	// / We weren't connected to the first hop of any route to the destination.
+	PaymentFailureReason_FAILURE_REASON_PEER_OFFLINE PaymentFailureReason = 6
+	// / Every route to the destination required fees exceeding the fee limit.
+	PaymentFailureReason_FAILURE_REASON_FEE_LIMIT PaymentFailureReason = 7
+)
+
+var PaymentFailureReason_name = map[int32]string{
+	0: "FAILURE_REASON_NONE",
+	1: "FAILURE_REASON_TIMEOUT",
+	2: "FAILURE_REASON_NO_ROUTE",
+	3: "FAILURE_REASON_ERROR",
+	4: "FAILURE_REASON_INCORRECT_PAYMENT_DETAILS",
+	5: "FAILURE_REASON_INSUFFICIENT_BALANCE",
+	6: "FAILURE_REASON_PEER_OFFLINE",
+	7: "FAILURE_REASON_FEE_LIMIT",
+}
+var PaymentFailureReason_value = map[string]int32{
+	"FAILURE_REASON_NONE":                      0,
+	"FAILURE_REASON_TIMEOUT":                   1,
+	"FAILURE_REASON_NO_ROUTE":                  2,
+	"FAILURE_REASON_ERROR":                     3,
+	"FAILURE_REASON_INCORRECT_PAYMENT_DETAILS": 4,
+	"FAILURE_REASON_INSUFFICIENT_BALANCE":      5,
+	"FAILURE_REASON_PEER_OFFLINE":              6,
+	"FAILURE_REASON_FEE_LIMIT":                 7,
+}
+
+func (x PaymentFailureReason) String() string {
+	return proto.EnumName(PaymentFailureReason_name, int32(x))
+}
+func (PaymentFailureReason) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+type ResolveHoldForwardAction int32
+
+const (
+	// / Resume the forwarding of the HTLC.
+	ResolveHoldForwardAction_RESUME ResolveHoldForwardAction = 0
+	// / Fail the HTLC back to the incoming channel.
+	ResolveHoldForwardAction_FAIL ResolveHoldForwardAction = 1
+	// / Settle the HTLC back to the incoming channel using the given preimage.
+	ResolveHoldForwardAction_SETTLE ResolveHoldForwardAction = 2
+)
+
+var ResolveHoldForwardAction_name = map[int32]string{
+	0: "RESUME",
+	1: "FAIL",
+	2: "SETTLE",
+}
+var ResolveHoldForwardAction_value = map[string]int32{
+	"RESUME": 0,
+	"FAIL":   1,
+	"SETTLE": 2,
+}
+
+func (x ResolveHoldForwardAction) String() string {
+	return proto.EnumName(ResolveHoldForwardAction_name, int32(x))
+}
+func (ResolveHoldForwardAction) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
 type NewAddressRequest_AddressType int32
 
 const (
@@ -144,7 +280,244 @@ func (x NewAddressRequest_AddressType) String() string {
 	return proto.EnumName(NewAddressRequest_AddressType_name, int32(x))
 }
 func (NewAddressRequest_AddressType) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor0, []int{11, 0}
+	return fileDescriptor0, []int{13, 0}
+}
+
+type HTLCResolution_Outcome int32
+
+const (
+	HTLCResolution_TIMEOUT   HTLCResolution_Outcome = 0
+	HTLCResolution_UNCLAIMED HTLCResolution_Outcome = 1
+	HTLCResolution_DUST      HTLCResolution_Outcome = 2
+)
+
+var HTLCResolution_Outcome_name = map[int32]string{
+	0: "TIMEOUT",
+	1: "UNCLAIMED",
+	2: "DUST",
+}
+var HTLCResolution_Outcome_value = map[string]int32{
+	"TIMEOUT":   0,
+	"UNCLAIMED": 1,
+	"DUST":      2,
+}
+
+func (x HTLCResolution_Outcome) String() string {
+	return proto.EnumName(HTLCResolution_Outcome_name, int32(x))
+}
+func (HTLCResolution_Outcome) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{28, 0} }
+
+type ChannelCloseSummary_ClosureType int32
+
+const (
+	ChannelCloseSummary_COOPERATIVE_CLOSE ChannelCloseSummary_ClosureType = 0
+	ChannelCloseSummary_FORCE_CLOSE       ChannelCloseSummary_ClosureType = 1
+	ChannelCloseSummary_BREACH_CLOSE      ChannelCloseSummary_ClosureType = 2
+	ChannelCloseSummary_FUNDING_CANCELED  ChannelCloseSummary_ClosureType = 3
+)
+
+var ChannelCloseSummary_ClosureType_name = map[int32]string{
+	0: "COOPERATIVE_CLOSE",
+	1: "FORCE_CLOSE",
+	2: "BREACH_CLOSE",
+	3: "FUNDING_CANCELED",
+}
+var ChannelCloseSummary_ClosureType_value = map[string]int32{
+	"COOPERATIVE_CLOSE": 0,
+	"FORCE_CLOSE":       1,
+	"BREACH_CLOSE":      2,
+	"FUNDING_CANCELED":  3,
+}
+
+func (x ChannelCloseSummary_ClosureType) String() string {
+	return proto.EnumName(ChannelCloseSummary_ClosureType_name, int32(x))
+}
+func (ChannelCloseSummary_ClosureType) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{29, 0}
+}
+
+type Invoice_InvoiceState int32
+
+const (
+	Invoice_OPEN     Invoice_InvoiceState = 0
+	Invoice_SETTLED  Invoice_InvoiceState = 1
+	Invoice_CANCELED Invoice_InvoiceState = 2
+	Invoice_ACCEPTED Invoice_InvoiceState = 3
+)
+
+var Invoice_InvoiceState_name = map[int32]string{
+	0: "OPEN",
+	1: "SETTLED",
+	2: "CANCELED",
+	3: "ACCEPTED",
+}
+var Invoice_InvoiceState_value = map[string]int32{
+	"OPEN":     0,
+	"SETTLED":  1,
+	"CANCELED": 2,
+	"ACCEPTED": 3,
+}
+
+func (x Invoice_InvoiceState) String() string {
+	return proto.EnumName(Invoice_InvoiceState_name, int32(x))
+}
+func (Invoice_InvoiceState) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{75, 0} }
+
+type Payment_PaymentStatus int32
+
+const (
+	Payment_UNKNOWN   Payment_PaymentStatus = 0
+	Payment_IN_FLIGHT Payment_PaymentStatus = 1
+	Payment_SUCCEEDED Payment_PaymentStatus = 2
+	Payment_FAILED    Payment_PaymentStatus = 3
+)
+
+var Payment_PaymentStatus_name = map[int32]string{
+	0: "UNKNOWN",
+	1: "IN_FLIGHT",
+	2: "SUCCEEDED",
+	3: "FAILED",
+}
+var Payment_PaymentStatus_value = map[string]int32{
+	"UNKNOWN":   0,
+	"IN_FLIGHT": 1,
+	"SUCCEEDED": 2,
+	"FAILED":    3,
+}
+
+func (x Payment_PaymentStatus) String() string {
+	return proto.EnumName(Payment_PaymentStatus_name, int32(x))
+}
+func (Payment_PaymentStatus) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{83, 0} }
+
+type HTLCAttempt_HTLCStatus int32
+
+const (
+	HTLCAttempt_IN_FLIGHT HTLCAttempt_HTLCStatus = 0
+	HTLCAttempt_SUCCEEDED HTLCAttempt_HTLCStatus = 1
+	HTLCAttempt_FAILED    HTLCAttempt_HTLCStatus = 2
+)
+
+var HTLCAttempt_HTLCStatus_name = map[int32]string{
+	0: "IN_FLIGHT",
+	1: "SUCCEEDED",
+	2: "FAILED",
+}
+var HTLCAttempt_HTLCStatus_value = map[string]int32{
+	"IN_FLIGHT": 0,
+	"SUCCEEDED": 1,
+	"FAILED":    2,
+}
+
+func (x HTLCAttempt_HTLCStatus) String() string {
+	return proto.EnumName(HTLCAttempt_HTLCStatus_name, int32(x))
+}
+func (HTLCAttempt_HTLCStatus) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{84, 0} }
+
+type HtlcEvent_EventType int32
+
+const (
+	HtlcEvent_FORWARD      HtlcEvent_EventType = 0
+	HtlcEvent_FORWARD_FAIL HtlcEvent_EventType = 1
+	HtlcEvent_SETTLE       HtlcEvent_EventType = 2
+	HtlcEvent_LINK_FAIL    HtlcEvent_EventType = 3
+)
+
+var HtlcEvent_EventType_name = map[int32]string{
+	0: "FORWARD",
+	1: "FORWARD_FAIL",
+	2: "SETTLE",
+	3: "LINK_FAIL",
+}
+var HtlcEvent_EventType_value = map[string]int32{
+	"FORWARD":      0,
+	"FORWARD_FAIL": 1,
+	"SETTLE":       2,
+	"LINK_FAIL":    3,
+}
+
+func (x HtlcEvent_EventType) String() string {
+	return proto.EnumName(HtlcEvent_EventType_name, int32(x))
+}
+func (HtlcEvent_EventType) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{136, 0} }
+
+type MigrationStatusRequest struct {
+}
+
+func (m *MigrationStatusRequest) Reset()                    { *m = MigrationStatusRequest{} }
+func (m *MigrationStatusRequest) String() string            { return proto.CompactTextString(m) }
+func (*MigrationStatusRequest) ProtoMessage()               {}
+func (*MigrationStatusRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+type MigrationStatusResponse struct {
+	// / Whether database migrations are currently being applied
+	Migrating bool `protobuf:"varint,1,opt,name=migrating" json:"migrating,omitempty"`
+	// / The version the database is currently at
+	CurrentVersion uint32 `protobuf:"varint,2,opt,name=current_version" json:"current_version,omitempty"`
+	// / The version the database is being migrated to
+	LatestVersion uint32 `protobuf:"varint,3,opt,name=latest_version" json:"latest_version,omitempty"`
+	// / The number of the migration currently being applied
+	Migration uint32 `protobuf:"varint,4,opt,name=migration" json:"migration,omitempty"`
+	// / The number of items the current migration has migrated so far
+	ItemsMigrated uint64 `protobuf:"varint,5,opt,name=items_migrated" json:"items_migrated,omitempty"`
+	// / The total number of items the current migration needs to migrate, or zero if unknown
+	TotalItems uint64 `protobuf:"varint,6,opt,name=total_items" json:"total_items,omitempty"`
+	// / The unix timestamp at which the current migration was started
+	StartTime int64 `protobuf:"varint,7,opt,name=start_time" json:"start_time,omitempty"`
+}
+
+func (m *MigrationStatusResponse) Reset()                    { *m = MigrationStatusResponse{} }
+func (m *MigrationStatusResponse) String() string            { return proto.CompactTextString(m) }
+func (*MigrationStatusResponse) ProtoMessage()               {}
+func (*MigrationStatusResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+func (m *MigrationStatusResponse) GetMigrating() bool {
+	if m != nil {
+		return m.Migrating
+	}
+	return false
+}
+
+func (m *MigrationStatusResponse) GetCurrentVersion() uint32 {
+	if m != nil {
+		return m.CurrentVersion
+	}
+	return 0
+}
+
+func (m *MigrationStatusResponse) GetLatestVersion() uint32 {
+	if m != nil {
+		return m.LatestVersion
+	}
+	return 0
+}
+
+func (m *MigrationStatusResponse) GetMigration() uint32 {
+	if m != nil {
+		return m.Migration
+	}
+	return 0
+}
+
+func (m *MigrationStatusResponse) GetItemsMigrated() uint64 {
+	if m != nil {
+		return m.ItemsMigrated
+	}
+	return 0
+}
+
+func (m *MigrationStatusResponse) GetTotalItems() uint64 {
+	if m != nil {
+		return m.TotalItems
+	}
+	return 0
+}
+
+func (m *MigrationStatusResponse) GetStartTime() int64 {
+	if m != nil {
+		return m.StartTime
+	}
+	return 0
 }
 
 type Transaction struct {
@@ -167,7 +540,7 @@ type Transaction struct {
 func (m *Transaction) Reset()                    { *m = Transaction{} }
 func (m *Transaction) String() string            { return proto.CompactTextString(m) }
 func (*Transaction) ProtoMessage()               {}
-func (*Transaction) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+func (*Transaction) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
 
 func (m *Transaction) GetTxHash() string {
 	if m != nil {
@@ -224,7 +597,7 @@ type GetTransactionsRequest struct {
 func (m *GetTransactionsRequest) Reset()                    { *m = GetTransactionsRequest{} }
 func (m *GetTransactionsRequest) String() string            { return proto.CompactTextString(m) }
 func (*GetTransactionsRequest) ProtoMessage()               {}
-func (*GetTransactionsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+func (*GetTransactionsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
 
 type TransactionDetails struct {
 	// / The list of transactions relevant to the wallet.
@@ -234,7 +607,7 @@ type TransactionDetails struct {
 func (m *TransactionDetails) Reset()                    { *m = TransactionDetails{} }
 func (m *TransactionDetails) String() string            { return proto.CompactTextString(m) }
 func (*TransactionDetails) ProtoMessage()               {}
-func (*TransactionDetails) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+func (*TransactionDetails) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
 
 func (m *TransactionDetails) GetTransactions() []*Transaction {
 	if m != nil {
@@ -259,12 +632,46 @@ type SendRequest struct {
 	// details of the invoice, the sender has all the data necessary to send a
 	// payment to the recipient.
 	PaymentRequest string `protobuf:"bytes,6,opt,name=payment_request,json=paymentRequest" json:"payment_request,omitempty"`
+	// *
+	// The maximum total fee in satoshis that the sender is willing to pay in
+	// order to complete the payment. Routes requiring a larger fee won't be
+	// attempted. If neither this nor fee_limit_percent is set, the fee limit
+	// defaults to the amount of the payment.
+	FeeLimitSat int64 `protobuf:"varint,7,opt,name=fee_limit_sat,json=feeLimitSat" json:"fee_limit_sat,omitempty"`
+	// *
+	// The channels of ours that the first hop of the payment may be sent over.
+	// If empty, any of our channels may be used.
+	OutgoingChanIds []uint64 `protobuf:"varint,8,rep,packed,name=outgoing_chan_ids,json=outgoingChanIds" json:"outgoing_chan_ids,omitempty"`
+	// / The pubkey of the node that must precede the destination within the route, if any
+	LastHopPubkey []byte `protobuf:"bytes,9,opt,name=last_hop_pubkey,json=lastHopPubkey,proto3" json:"last_hop_pubkey,omitempty"`
+	// *
+	// The maximum total fee that the sender is willing to pay, expressed as a
+	// percentage of the amount of the payment. If fee_limit_sat is set as well,
+	// then the lower of the two limits applies.
+	FeeLimitPercent int64 `protobuf:"varint,10,opt,name=fee_limit_percent,json=feeLimitPercent" json:"fee_limit_percent,omitempty"`
+	// *
+	// The maximum total time lock delta, in blocks, of the routes attempted. If
+	// zero, a default limit of 2016 blocks applies.
+	CltvLimit uint32 `protobuf:"varint,11,opt,name=cltv_limit,json=cltvLimit" json:"cltv_limit,omitempty"`
+	// *
+	// The maximum number of shards the payment may be split into, should no
+	// single route be able to carry the full amount. If greater than one, then
+	// payment_addr must be set as well.
+	MaxParts uint32 `protobuf:"varint,12,opt,name=max_parts,json=maxParts" json:"max_parts,omitempty"`
+	// *
+	// The payment address of the invoice being paid, which is included within
+	// the payload of the final hop of each shard of the payment.
+	PaymentAddr []byte `protobuf:"bytes,13,opt,name=payment_addr,json=paymentAddr,proto3" json:"payment_addr,omitempty"`
+	// *
+	// The chains of private channels, supplied by the receiver, that lead towards
+	// the destination of the payment, which may be used to reach it.
+	RouteHints []*RouteHint `protobuf:"bytes,14,rep,name=route_hints,json=routeHints" json:"route_hints,omitempty"`
 }
 
 func (m *SendRequest) Reset()                    { *m = SendRequest{} }
 func (m *SendRequest) String() string            { return proto.CompactTextString(m) }
 func (*SendRequest) ProtoMessage()               {}
-func (*SendRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+func (*SendRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
 
 func (m *SendRequest) GetDest() []byte {
 	if m != nil {
@@ -308,16 +715,77 @@ func (m *SendRequest) GetPaymentRequest() string {
 	return ""
 }
 
+func (m *SendRequest) GetFeeLimitSat() int64 {
+	if m != nil {
+		return m.FeeLimitSat
+	}
+	return 0
+}
+
+func (m *SendRequest) GetOutgoingChanIds() []uint64 {
+	if m != nil {
+		return m.OutgoingChanIds
+	}
+	return nil
+}
+
+func (m *SendRequest) GetLastHopPubkey() []byte {
+	if m != nil {
+		return m.LastHopPubkey
+	}
+	return nil
+}
+
+func (m *SendRequest) GetFeeLimitPercent() int64 {
+	if m != nil {
+		return m.FeeLimitPercent
+	}
+	return 0
+}
+
+func (m *SendRequest) GetCltvLimit() uint32 {
+	if m != nil {
+		return m.CltvLimit
+	}
+	return 0
+}
+
+func (m *SendRequest) GetMaxParts() uint32 {
+	if m != nil {
+		return m.MaxParts
+	}
+	return 0
+}
+
+func (m *SendRequest) GetPaymentAddr() []byte {
+	if m != nil {
+		return m.PaymentAddr
+	}
+	return nil
+}
+
+func (m *SendRequest) GetRouteHints() []*RouteHint {
+	if m != nil {
+		return m.RouteHints
+	}
+	return nil
+}
+
 type SendResponse struct {
+	// *
+	// A human-readable description of why the payment failed, only set for failed
+	// payments.
 	PaymentError    string `protobuf:"bytes,1,opt,name=payment_error" json:"payment_error,omitempty"`
 	PaymentPreimage []byte `protobuf:"bytes,2,opt,name=payment_preimage,proto3" json:"payment_preimage,omitempty"`
 	PaymentRoute    *Route `protobuf:"bytes,3,opt,name=payment_route" json:"payment_route,omitempty"`
+	// / The reason the payment failed, only set for failed payments
+	PaymentFailureReason PaymentFailureReason `protobuf:"varint,4,opt,name=payment_failure_reason,enum=lnrpc.PaymentFailureReason" json:"payment_failure_reason,omitempty"`
 }
 
 func (m *SendResponse) Reset()                    { *m = SendResponse{} }
 func (m *SendResponse) String() string            { return proto.CompactTextString(m) }
 func (*SendResponse) ProtoMessage()               {}
-func (*SendResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+func (*SendResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
 
 func (m *SendResponse) GetPaymentError() string {
 	if m != nil {
@@ -340,6 +808,13 @@ func (m *SendResponse) GetPaymentRoute() *Route {
 	return nil
 }
 
+func (m *SendResponse) GetPaymentFailureReason() PaymentFailureReason {
+	if m != nil {
+		return m.PaymentFailureReason
+	}
+	return PaymentFailureReason_FAILURE_REASON_NONE
+}
+
 type ChannelPoint struct {
 	// / Txid of the funding transaction
 	FundingTxid []byte `protobuf:"bytes,1,opt,name=funding_txid,proto3" json:"funding_txid,omitempty"`
@@ -352,7 +827,7 @@ type ChannelPoint struct {
 func (m *ChannelPoint) Reset()                    { *m = ChannelPoint{} }
 func (m *ChannelPoint) String() string            { return proto.CompactTextString(m) }
 func (*ChannelPoint) ProtoMessage()               {}
-func (*ChannelPoint) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+func (*ChannelPoint) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
 
 func (m *ChannelPoint) GetFundingTxid() []byte {
 	if m != nil {
@@ -378,14 +853,17 @@ func (m *ChannelPoint) GetOutputIndex() uint32 {
 type LightningAddress struct {
 	// / The identity pubkey of the Lightning node
 	Pubkey string `protobuf:"bytes,1,opt,name=pubkey" json:"pubkey,omitempty"`
-	// / The network location of the lightning node, e.g. `69.69.69.69:1337` or `localhost:10011`
+	// *
+	// The network location of the lightning node, e.g. `69.69.69.69:1337`,
+	// `localhost:10011` or `[::1]:10011`. If the port is omitted, then the
+	// default port of the active network is assumed.
 	Host string `protobuf:"bytes,2,opt,name=host" json:"host,omitempty"`
 }
 
 func (m *LightningAddress) Reset()                    { *m = LightningAddress{} }
 func (m *LightningAddress) String() string            { return proto.CompactTextString(m) }
 func (*LightningAddress) ProtoMessage()               {}
-func (*LightningAddress) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+func (*LightningAddress) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
 
 func (m *LightningAddress) GetPubkey() string {
 	if m != nil {
@@ -409,7 +887,7 @@ type SendManyRequest struct {
 func (m *SendManyRequest) Reset()                    { *m = SendManyRequest{} }
 func (m *SendManyRequest) String() string            { return proto.CompactTextString(m) }
 func (*SendManyRequest) ProtoMessage()               {}
-func (*SendManyRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+func (*SendManyRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
 
 func (m *SendManyRequest) GetAddrToAmount() map[string]int64 {
 	if m != nil {
@@ -426,7 +904,7 @@ type SendManyResponse struct {
 func (m *SendManyResponse) Reset()                    { *m = SendManyResponse{} }
 func (m *SendManyResponse) String() string            { return proto.CompactTextString(m) }
 func (*SendManyResponse) ProtoMessage()               {}
-func (*SendManyResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+func (*SendManyResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
 
 func (m *SendManyResponse) GetTxid() string {
 	if m != nil {
@@ -445,7 +923,7 @@ type SendCoinsRequest struct {
 func (m *SendCoinsRequest) Reset()                    { *m = SendCoinsRequest{} }
 func (m *SendCoinsRequest) String() string            { return proto.CompactTextString(m) }
 func (*SendCoinsRequest) ProtoMessage()               {}
-func (*SendCoinsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+func (*SendCoinsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
 
 func (m *SendCoinsRequest) GetAddr() string {
 	if m != nil {
@@ -469,7 +947,7 @@ type SendCoinsResponse struct {
 func (m *SendCoinsResponse) Reset()                    { *m = SendCoinsResponse{} }
 func (m *SendCoinsResponse) String() string            { return proto.CompactTextString(m) }
 func (*SendCoinsResponse) ProtoMessage()               {}
-func (*SendCoinsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+func (*SendCoinsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
 
 func (m *SendCoinsResponse) GetTxid() string {
 	if m != nil {
@@ -492,7 +970,7 @@ type NewAddressRequest struct {
 func (m *NewAddressRequest) Reset()                    { *m = NewAddressRequest{} }
 func (m *NewAddressRequest) String() string            { return proto.CompactTextString(m) }
 func (*NewAddressRequest) ProtoMessage()               {}
-func (*NewAddressRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+func (*NewAddressRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
 
 func (m *NewAddressRequest) GetType() NewAddressRequest_AddressType {
 	if m != nil {
@@ -507,7 +985,7 @@ type NewWitnessAddressRequest struct {
 func (m *NewWitnessAddressRequest) Reset()                    { *m = NewWitnessAddressRequest{} }
 func (m *NewWitnessAddressRequest) String() string            { return proto.CompactTextString(m) }
 func (*NewWitnessAddressRequest) ProtoMessage()               {}
-func (*NewWitnessAddressRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+func (*NewWitnessAddressRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
 
 type NewAddressResponse struct {
 	// / The newly generated wallet address
@@ -517,7 +995,7 @@ type NewAddressResponse struct {
 func (m *NewAddressResponse) Reset()                    { *m = NewAddressResponse{} }
 func (m *NewAddressResponse) String() string            { return proto.CompactTextString(m) }
 func (*NewAddressResponse) ProtoMessage()               {}
-func (*NewAddressResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
+func (*NewAddressResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
 
 func (m *NewAddressResponse) GetAddress() string {
 	if m != nil {
@@ -534,7 +1012,7 @@ type SignMessageRequest struct {
 func (m *SignMessageRequest) Reset()                    { *m = SignMessageRequest{} }
 func (m *SignMessageRequest) String() string            { return proto.CompactTextString(m) }
 func (*SignMessageRequest) ProtoMessage()               {}
-func (*SignMessageRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
+func (*SignMessageRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
 
 func (m *SignMessageRequest) GetMsg() []byte {
 	if m != nil {
@@ -551,7 +1029,7 @@ type SignMessageResponse struct {
 func (m *SignMessageResponse) Reset()                    { *m = SignMessageResponse{} }
 func (m *SignMessageResponse) String() string            { return proto.CompactTextString(m) }
 func (*SignMessageResponse) ProtoMessage()               {}
-func (*SignMessageResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+func (*SignMessageResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
 
 func (m *SignMessageResponse) GetSignature() string {
 	if m != nil {
@@ -570,7 +1048,7 @@ type VerifyMessageRequest struct {
 func (m *VerifyMessageRequest) Reset()                    { *m = VerifyMessageRequest{} }
 func (m *VerifyMessageRequest) String() string            { return proto.CompactTextString(m) }
 func (*VerifyMessageRequest) ProtoMessage()               {}
-func (*VerifyMessageRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+func (*VerifyMessageRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
 
 func (m *VerifyMessageRequest) GetMsg() []byte {
 	if m != nil {
@@ -596,7 +1074,7 @@ type VerifyMessageResponse struct {
 func (m *VerifyMessageResponse) Reset()                    { *m = VerifyMessageResponse{} }
 func (m *VerifyMessageResponse) String() string            { return proto.CompactTextString(m) }
 func (*VerifyMessageResponse) ProtoMessage()               {}
-func (*VerifyMessageResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
+func (*VerifyMessageResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
 
 func (m *VerifyMessageResponse) GetValid() bool {
 	if m != nil {
@@ -623,7 +1101,7 @@ type ConnectPeerRequest struct {
 func (m *ConnectPeerRequest) Reset()                    { *m = ConnectPeerRequest{} }
 func (m *ConnectPeerRequest) String() string            { return proto.CompactTextString(m) }
 func (*ConnectPeerRequest) ProtoMessage()               {}
-func (*ConnectPeerRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
+func (*ConnectPeerRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
 
 func (m *ConnectPeerRequest) GetAddr() *LightningAddress {
 	if m != nil {
@@ -647,7 +1125,7 @@ type ConnectPeerResponse struct {
 func (m *ConnectPeerResponse) Reset()                    { *m = ConnectPeerResponse{} }
 func (m *ConnectPeerResponse) String() string            { return proto.CompactTextString(m) }
 func (*ConnectPeerResponse) ProtoMessage()               {}
-func (*ConnectPeerResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
+func (*ConnectPeerResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
 
 func (m *ConnectPeerResponse) GetPeerId() int32 {
 	if m != nil {
@@ -664,7 +1142,7 @@ type DisconnectPeerRequest struct {
 func (m *DisconnectPeerRequest) Reset()                    { *m = DisconnectPeerRequest{} }
 func (m *DisconnectPeerRequest) String() string            { return proto.CompactTextString(m) }
 func (*DisconnectPeerRequest) ProtoMessage()               {}
-func (*DisconnectPeerRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
+func (*DisconnectPeerRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
 
 func (m *DisconnectPeerRequest) GetPubKey() string {
 	if m != nil {
@@ -679,7 +1157,7 @@ type DisconnectPeerResponse struct {
 func (m *DisconnectPeerResponse) Reset()                    { *m = DisconnectPeerResponse{} }
 func (m *DisconnectPeerResponse) String() string            { return proto.CompactTextString(m) }
 func (*DisconnectPeerResponse) ProtoMessage()               {}
-func (*DisconnectPeerResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
+func (*DisconnectPeerResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
 
 type HTLC struct {
 	Incoming         bool   `protobuf:"varint,1,opt,name=incoming" json:"incoming,omitempty"`
@@ -692,7 +1170,7 @@ type HTLC struct {
 func (m *HTLC) Reset()                    { *m = HTLC{} }
 func (m *HTLC) String() string            { return proto.CompactTextString(m) }
 func (*HTLC) ProtoMessage()               {}
-func (*HTLC) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
+func (*HTLC) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{24} }
 
 func (m *HTLC) GetIncoming() bool {
 	if m != nil {
@@ -777,12 +1255,30 @@ type ActiveChannel struct {
 	// *
 	// The list of active, uncleared HTLCs currently pending within the channel.
 	PendingHtlcs []*HTLC `protobuf:"bytes,15,rep,name=pending_htlcs" json:"pending_htlcs,omitempty"`
+	// *
+	// The set of status flags applied to the channel. Channels which aren't in
+	// the ChanStatusDefault state, such as those which have been borked due to
+	// an unrecoverable error, are frozen and can't be used to forward payments.
+	ChanStatusFlags string `protobuf:"bytes,16,opt,name=chan_status_flags" json:"chan_status_flags,omitempty"`
+	// / The dust limit in satoshis of our commitment transaction
+	LocalDustLimit int64 `protobuf:"varint,17,opt,name=local_dust_limit" json:"local_dust_limit,omitempty"`
+	// / The dust limit in satoshis of the remote party's commitment transaction
+	RemoteDustLimit int64 `protobuf:"varint,18,opt,name=remote_dust_limit" json:"remote_dust_limit,omitempty"`
+	// / Whether we initiated the channel, and so paid for its funding
+	Initiator bool `protobuf:"varint,19,opt,name=initiator" json:"initiator,omitempty"`
+	// / The height at which the funding transaction was confirmed
+	ConfirmationHeight uint32 `protobuf:"varint,20,opt,name=confirmation_height" json:"confirmation_height,omitempty"`
+	// *
+	// The on-chain fee in satoshis we paid for the funding transaction. This is
+	// zero for channels initiated by the remote party, or opened before the fee
+	// was recorded.
+	FundingFee int64 `protobuf:"varint,21,opt,name=funding_fee" json:"funding_fee,omitempty"`
 }
 
 func (m *ActiveChannel) Reset()                    { *m = ActiveChannel{} }
 func (m *ActiveChannel) String() string            { return proto.CompactTextString(m) }
 func (*ActiveChannel) ProtoMessage()               {}
-func (*ActiveChannel) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
+func (*ActiveChannel) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{25} }
 
 func (m *ActiveChannel) GetActive() bool {
 	if m != nil {
@@ -889,13 +1385,55 @@ func (m *ActiveChannel) GetPendingHtlcs() []*HTLC {
 	return nil
 }
 
+func (m *ActiveChannel) GetChanStatusFlags() string {
+	if m != nil {
+		return m.ChanStatusFlags
+	}
+	return ""
+}
+
+func (m *ActiveChannel) GetLocalDustLimit() int64 {
+	if m != nil {
+		return m.LocalDustLimit
+	}
+	return 0
+}
+
+func (m *ActiveChannel) GetRemoteDustLimit() int64 {
+	if m != nil {
+		return m.RemoteDustLimit
+	}
+	return 0
+}
+
+func (m *ActiveChannel) GetInitiator() bool {
+	if m != nil {
+		return m.Initiator
+	}
+	return false
+}
+
+func (m *ActiveChannel) GetConfirmationHeight() uint32 {
+	if m != nil {
+		return m.ConfirmationHeight
+	}
+	return 0
+}
+
+func (m *ActiveChannel) GetFundingFee() int64 {
+	if m != nil {
+		return m.FundingFee
+	}
+	return 0
+}
+
 type ListChannelsRequest struct {
 }
 
 func (m *ListChannelsRequest) Reset()                    { *m = ListChannelsRequest{} }
 func (m *ListChannelsRequest) String() string            { return proto.CompactTextString(m) }
 func (*ListChannelsRequest) ProtoMessage()               {}
-func (*ListChannelsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{24} }
+func (*ListChannelsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{26} }
 
 type ListChannelsResponse struct {
 	// / The list of active channels
@@ -905,7 +1443,7 @@ type ListChannelsResponse struct {
 func (m *ListChannelsResponse) Reset()                    { *m = ListChannelsResponse{} }
 func (m *ListChannelsResponse) String() string            { return proto.CompactTextString(m) }
 func (*ListChannelsResponse) ProtoMessage()               {}
-func (*ListChannelsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{25} }
+func (*ListChannelsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{27} }
 
 func (m *ListChannelsResponse) GetChannels() []*ActiveChannel {
 	if m != nil {
@@ -914,84 +1452,286 @@ func (m *ListChannelsResponse) GetChannels() []*ActiveChannel {
 	return nil
 }
 
-type Peer struct {
-	// / The identity pubkey of the peer
-	PubKey string `protobuf:"bytes,1,opt,name=pub_key" json:"pub_key,omitempty"`
-	// / The peer's id from the local point of view
-	PeerId int32 `protobuf:"varint,2,opt,name=peer_id" json:"peer_id,omitempty"`
-	// / Network address of the peer; eg `127.0.0.1:10011`
-	Address string `protobuf:"bytes,3,opt,name=address" json:"address,omitempty"`
-	// / Bytes of data transmitted to this peer
-	BytesSent uint64 `protobuf:"varint,4,opt,name=bytes_sent" json:"bytes_sent,omitempty"`
-	// / Bytes of data transmitted from this peer
-	BytesRecv uint64 `protobuf:"varint,5,opt,name=bytes_recv" json:"bytes_recv,omitempty"`
-	// / Satoshis sent to this peer
-	SatSent int64 `protobuf:"varint,6,opt,name=sat_sent" json:"sat_sent,omitempty"`
-	// / Satoshis received from this peer
-	SatRecv int64 `protobuf:"varint,7,opt,name=sat_recv" json:"sat_recv,omitempty"`
-	// / A channel is inbound if the counterparty initiated the channel
-	Inbound bool `protobuf:"varint,8,opt,name=inbound" json:"inbound,omitempty"`
-	// / Ping time to this peer
-	PingTime int64 `protobuf:"varint,9,opt,name=ping_time" json:"ping_time,omitempty"`
+type HTLCResolution struct {
+	// / Whether the HTLC was offered to us, or offered by us
+	Incoming bool `protobuf:"varint,1,opt,name=incoming" json:"incoming,omitempty"`
+	// / The amount of the HTLC in milli-satoshis
+	AmountMsat uint64 `protobuf:"varint,2,opt,name=amount_msat" json:"amount_msat,omitempty"`
+	// / The payment hash of the HTLC
+	HashLock []byte `protobuf:"bytes,3,opt,name=hash_lock,proto3" json:"hash_lock,omitempty"`
+	// / The absolute height at which the HTLC times out
+	ExpirationHeight uint32 `protobuf:"varint,4,opt,name=expiration_height" json:"expiration_height,omitempty"`
+	// / The manner in which the HTLC is resolved on-chain
+	Outcome HTLCResolution_Outcome `protobuf:"varint,5,opt,name=outcome,enum=lnrpc.HTLCResolution_Outcome" json:"outcome,omitempty"`
 }
 
-func (m *Peer) Reset()                    { *m = Peer{} }
-func (m *Peer) String() string            { return proto.CompactTextString(m) }
-func (*Peer) ProtoMessage()               {}
-func (*Peer) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{26} }
+func (m *HTLCResolution) Reset()                    { *m = HTLCResolution{} }
+func (m *HTLCResolution) String() string            { return proto.CompactTextString(m) }
+func (*HTLCResolution) ProtoMessage()               {}
+func (*HTLCResolution) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{28} }
 
-func (m *Peer) GetPubKey() string {
+func (m *HTLCResolution) GetIncoming() bool {
 	if m != nil {
-		return m.PubKey
+		return m.Incoming
 	}
-	return ""
+	return false
 }
 
-func (m *Peer) GetPeerId() int32 {
+func (m *HTLCResolution) GetAmountMsat() uint64 {
 	if m != nil {
-		return m.PeerId
+		return m.AmountMsat
 	}
 	return 0
 }
 
-func (m *Peer) GetAddress() string {
+func (m *HTLCResolution) GetHashLock() []byte {
 	if m != nil {
-		return m.Address
+		return m.HashLock
 	}
-	return ""
+	return nil
 }
 
-func (m *Peer) GetBytesSent() uint64 {
+func (m *HTLCResolution) GetExpirationHeight() uint32 {
 	if m != nil {
-		return m.BytesSent
+		return m.ExpirationHeight
 	}
 	return 0
 }
 
-func (m *Peer) GetBytesRecv() uint64 {
+func (m *HTLCResolution) GetOutcome() HTLCResolution_Outcome {
 	if m != nil {
-		return m.BytesRecv
+		return m.Outcome
 	}
-	return 0
+	return HTLCResolution_TIMEOUT
 }
 
-func (m *Peer) GetSatSent() int64 {
+type ChannelCloseSummary struct {
+	// / The outpoint (txid:index) of the funding transaction
+	ChannelPoint string `protobuf:"bytes,1,opt,name=channel_point" json:"channel_point,omitempty"`
+	// / The txid of the transaction which closed the channel
+	ClosingTxHash string `protobuf:"bytes,2,opt,name=closing_tx_hash" json:"closing_tx_hash,omitempty"`
+	// / The identity pubkey of the remote node
+	RemotePubkey string `protobuf:"bytes,3,opt,name=remote_pubkey" json:"remote_pubkey,omitempty"`
+	// / The total capacity of the channel
+	Capacity int64 `protobuf:"varint,4,opt,name=capacity" json:"capacity,omitempty"`
+	// / Our settled balance at the time of channel closure
+	SettledBalance int64 `protobuf:"varint,5,opt,name=settled_balance" json:"settled_balance,omitempty"`
+	// / The sum of all the time-locked outputs at the time of channel closure
+	TimeLockedBalance int64 `protobuf:"varint,6,opt,name=time_locked_balance" json:"time_locked_balance,omitempty"`
+	// / Details how the channel was closed
+	CloseType ChannelCloseSummary_ClosureType `protobuf:"varint,7,opt,name=close_type,enum=lnrpc.ChannelCloseSummary_ClosureType" json:"close_type,omitempty"`
+	// / Whether the channel is still waiting to be fully resolved
+	Pending bool `protobuf:"varint,8,opt,name=pending" json:"pending,omitempty"`
+	// / The on-chain resolution of each HTLC pending at the time of closure
+	HtlcResolutions []*HTLCResolution `protobuf:"bytes,9,rep,name=htlc_resolutions" json:"htlc_resolutions,omitempty"`
+}
+
+func (m *ChannelCloseSummary) Reset()                    { *m = ChannelCloseSummary{} }
+func (m *ChannelCloseSummary) String() string            { return proto.CompactTextString(m) }
+func (*ChannelCloseSummary) ProtoMessage()               {}
+func (*ChannelCloseSummary) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{29} }
+
+func (m *ChannelCloseSummary) GetChannelPoint() string {
 	if m != nil {
-		return m.SatSent
+		return m.ChannelPoint
 	}
-	return 0
+	return ""
 }
 
-func (m *Peer) GetSatRecv() int64 {
+func (m *ChannelCloseSummary) GetClosingTxHash() string {
 	if m != nil {
-		return m.SatRecv
+		return m.ClosingTxHash
 	}
-	return 0
+	return ""
 }
 
-func (m *Peer) GetInbound() bool {
+func (m *ChannelCloseSummary) GetRemotePubkey() string {
 	if m != nil {
-		return m.Inbound
+		return m.RemotePubkey
+	}
+	return ""
+}
+
+func (m *ChannelCloseSummary) GetCapacity() int64 {
+	if m != nil {
+		return m.Capacity
+	}
+	return 0
+}
+
+func (m *ChannelCloseSummary) GetSettledBalance() int64 {
+	if m != nil {
+		return m.SettledBalance
+	}
+	return 0
+}
+
+func (m *ChannelCloseSummary) GetTimeLockedBalance() int64 {
+	if m != nil {
+		return m.TimeLockedBalance
+	}
+	return 0
+}
+
+func (m *ChannelCloseSummary) GetCloseType() ChannelCloseSummary_ClosureType {
+	if m != nil {
+		return m.CloseType
+	}
+	return ChannelCloseSummary_COOPERATIVE_CLOSE
+}
+
+func (m *ChannelCloseSummary) GetPending() bool {
+	if m != nil {
+		return m.Pending
+	}
+	return false
+}
+
+func (m *ChannelCloseSummary) GetHtlcResolutions() []*HTLCResolution {
+	if m != nil {
+		return m.HtlcResolutions
+	}
+	return nil
+}
+
+// *
+// If none of the closure type filters are set, then channels of all closure
+// types are returned.
+type ClosedChannelsRequest struct {
+	Cooperative     bool `protobuf:"varint,1,opt,name=cooperative" json:"cooperative,omitempty"`
+	Force           bool `protobuf:"varint,2,opt,name=force" json:"force,omitempty"`
+	Breach          bool `protobuf:"varint,3,opt,name=breach" json:"breach,omitempty"`
+	FundingCanceled bool `protobuf:"varint,4,opt,name=funding_canceled,json=fundingCanceled" json:"funding_canceled,omitempty"`
+}
+
+func (m *ClosedChannelsRequest) Reset()                    { *m = ClosedChannelsRequest{} }
+func (m *ClosedChannelsRequest) String() string            { return proto.CompactTextString(m) }
+func (*ClosedChannelsRequest) ProtoMessage()               {}
+func (*ClosedChannelsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{30} }
+
+func (m *ClosedChannelsRequest) GetCooperative() bool {
+	if m != nil {
+		return m.Cooperative
+	}
+	return false
+}
+
+func (m *ClosedChannelsRequest) GetForce() bool {
+	if m != nil {
+		return m.Force
+	}
+	return false
+}
+
+func (m *ClosedChannelsRequest) GetBreach() bool {
+	if m != nil {
+		return m.Breach
+	}
+	return false
+}
+
+func (m *ClosedChannelsRequest) GetFundingCanceled() bool {
+	if m != nil {
+		return m.FundingCanceled
+	}
+	return false
+}
+
+type ClosedChannelsResponse struct {
+	// / The list of closed channels
+	Channels []*ChannelCloseSummary `protobuf:"bytes,1,rep,name=channels" json:"channels,omitempty"`
+}
+
+func (m *ClosedChannelsResponse) Reset()                    { *m = ClosedChannelsResponse{} }
+func (m *ClosedChannelsResponse) String() string            { return proto.CompactTextString(m) }
+func (*ClosedChannelsResponse) ProtoMessage()               {}
+func (*ClosedChannelsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{31} }
+
+func (m *ClosedChannelsResponse) GetChannels() []*ChannelCloseSummary {
+	if m != nil {
+		return m.Channels
+	}
+	return nil
+}
+
+type Peer struct {
+	// / The identity pubkey of the peer
+	PubKey string `protobuf:"bytes,1,opt,name=pub_key" json:"pub_key,omitempty"`
+	// / The peer's id from the local point of view
+	PeerId int32 `protobuf:"varint,2,opt,name=peer_id" json:"peer_id,omitempty"`
+	// / Network address of the peer; eg `127.0.0.1:10011`
+	Address string `protobuf:"bytes,3,opt,name=address" json:"address,omitempty"`
+	// / Bytes of data transmitted to this peer
+	BytesSent uint64 `protobuf:"varint,4,opt,name=bytes_sent" json:"bytes_sent,omitempty"`
+	// / Bytes of data transmitted from this peer
+	BytesRecv uint64 `protobuf:"varint,5,opt,name=bytes_recv" json:"bytes_recv,omitempty"`
+	// / Satoshis sent to this peer
+	SatSent int64 `protobuf:"varint,6,opt,name=sat_sent" json:"sat_sent,omitempty"`
+	// / Satoshis received from this peer
+	SatRecv int64 `protobuf:"varint,7,opt,name=sat_recv" json:"sat_recv,omitempty"`
+	// / A channel is inbound if the counterparty initiated the channel
+	Inbound bool `protobuf:"varint,8,opt,name=inbound" json:"inbound,omitempty"`
+	// / Ping time to this peer
+	PingTime int64 `protobuf:"varint,9,opt,name=ping_time" json:"ping_time,omitempty"`
+}
+
+func (m *Peer) Reset()                    { *m = Peer{} }
+func (m *Peer) String() string            { return proto.CompactTextString(m) }
+func (*Peer) ProtoMessage()               {}
+func (*Peer) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{32} }
+
+func (m *Peer) GetPubKey() string {
+	if m != nil {
+		return m.PubKey
+	}
+	return ""
+}
+
+func (m *Peer) GetPeerId() int32 {
+	if m != nil {
+		return m.PeerId
+	}
+	return 0
+}
+
+func (m *Peer) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *Peer) GetBytesSent() uint64 {
+	if m != nil {
+		return m.BytesSent
+	}
+	return 0
+}
+
+func (m *Peer) GetBytesRecv() uint64 {
+	if m != nil {
+		return m.BytesRecv
+	}
+	return 0
+}
+
+func (m *Peer) GetSatSent() int64 {
+	if m != nil {
+		return m.SatSent
+	}
+	return 0
+}
+
+func (m *Peer) GetSatRecv() int64 {
+	if m != nil {
+		return m.SatRecv
+	}
+	return 0
+}
+
+func (m *Peer) GetInbound() bool {
+	if m != nil {
+		return m.Inbound
 	}
 	return false
 }
@@ -1009,7 +1749,7 @@ type ListPeersRequest struct {
 func (m *ListPeersRequest) Reset()                    { *m = ListPeersRequest{} }
 func (m *ListPeersRequest) String() string            { return proto.CompactTextString(m) }
 func (*ListPeersRequest) ProtoMessage()               {}
-func (*ListPeersRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{27} }
+func (*ListPeersRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{33} }
 
 type ListPeersResponse struct {
 	// / The list of currently connected peers
@@ -1019,7 +1759,7 @@ type ListPeersResponse struct {
 func (m *ListPeersResponse) Reset()                    { *m = ListPeersResponse{} }
 func (m *ListPeersResponse) String() string            { return proto.CompactTextString(m) }
 func (*ListPeersResponse) ProtoMessage()               {}
-func (*ListPeersResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{28} }
+func (*ListPeersResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{34} }
 
 func (m *ListPeersResponse) GetPeers() []*Peer {
 	if m != nil {
@@ -1034,7 +1774,7 @@ type GetInfoRequest struct {
 func (m *GetInfoRequest) Reset()                    { *m = GetInfoRequest{} }
 func (m *GetInfoRequest) String() string            { return proto.CompactTextString(m) }
 func (*GetInfoRequest) ProtoMessage()               {}
-func (*GetInfoRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{29} }
+func (*GetInfoRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{35} }
 
 type GetInfoResponse struct {
 	// / The identity pubkey of the current node.
@@ -1062,7 +1802,7 @@ type GetInfoResponse struct {
 func (m *GetInfoResponse) Reset()                    { *m = GetInfoResponse{} }
 func (m *GetInfoResponse) String() string            { return proto.CompactTextString(m) }
 func (*GetInfoResponse) ProtoMessage()               {}
-func (*GetInfoResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{30} }
+func (*GetInfoResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{36} }
 
 func (m *GetInfoResponse) GetIdentityPubkey() string {
 	if m != nil {
@@ -1143,7 +1883,7 @@ type ConfirmationUpdate struct {
 func (m *ConfirmationUpdate) Reset()                    { *m = ConfirmationUpdate{} }
 func (m *ConfirmationUpdate) String() string            { return proto.CompactTextString(m) }
 func (*ConfirmationUpdate) ProtoMessage()               {}
-func (*ConfirmationUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{31} }
+func (*ConfirmationUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{37} }
 
 func (m *ConfirmationUpdate) GetBlockSha() []byte {
 	if m != nil {
@@ -1173,7 +1913,7 @@ type ChannelOpenUpdate struct {
 func (m *ChannelOpenUpdate) Reset()                    { *m = ChannelOpenUpdate{} }
 func (m *ChannelOpenUpdate) String() string            { return proto.CompactTextString(m) }
 func (*ChannelOpenUpdate) ProtoMessage()               {}
-func (*ChannelOpenUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{32} }
+func (*ChannelOpenUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{38} }
 
 func (m *ChannelOpenUpdate) GetChannelPoint() *ChannelPoint {
 	if m != nil {
@@ -1190,7 +1930,7 @@ type ChannelCloseUpdate struct {
 func (m *ChannelCloseUpdate) Reset()                    { *m = ChannelCloseUpdate{} }
 func (m *ChannelCloseUpdate) String() string            { return proto.CompactTextString(m) }
 func (*ChannelCloseUpdate) ProtoMessage()               {}
-func (*ChannelCloseUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{33} }
+func (*ChannelCloseUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{39} }
 
 func (m *ChannelCloseUpdate) GetClosingTxid() []byte {
 	if m != nil {
@@ -1214,12 +1954,14 @@ type CloseChannelRequest struct {
 	ChannelPoint *ChannelPoint `protobuf:"bytes,1,opt,name=channel_point,json=channelPoint" json:"channel_point,omitempty"`
 	// / If true, then the channel will be closed forcibly. This means the current commitment transaction will be signed and broadcast.
 	Force bool `protobuf:"varint,2,opt,name=force" json:"force,omitempty"`
+	// / An optional address to send the funds to in the case of a cooperative close. If the channel committed to an upfront shutdown script when it was opened, then the address must match it.
+	DeliveryAddress string `protobuf:"bytes,3,opt,name=delivery_address,json=deliveryAddress" json:"delivery_address,omitempty"`
 }
 
 func (m *CloseChannelRequest) Reset()                    { *m = CloseChannelRequest{} }
 func (m *CloseChannelRequest) String() string            { return proto.CompactTextString(m) }
 func (*CloseChannelRequest) ProtoMessage()               {}
-func (*CloseChannelRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{34} }
+func (*CloseChannelRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{40} }
 
 func (m *CloseChannelRequest) GetChannelPoint() *ChannelPoint {
 	if m != nil {
@@ -1235,6 +1977,13 @@ func (m *CloseChannelRequest) GetForce() bool {
 	return false
 }
 
+func (m *CloseChannelRequest) GetDeliveryAddress() string {
+	if m != nil {
+		return m.DeliveryAddress
+	}
+	return ""
+}
+
 type CloseStatusUpdate struct {
 	// Types that are valid to be assigned to Update:
 	//	*CloseStatusUpdate_ClosePending
@@ -1246,7 +1995,7 @@ type CloseStatusUpdate struct {
 func (m *CloseStatusUpdate) Reset()                    { *m = CloseStatusUpdate{} }
 func (m *CloseStatusUpdate) String() string            { return proto.CompactTextString(m) }
 func (*CloseStatusUpdate) ProtoMessage()               {}
-func (*CloseStatusUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{35} }
+func (*CloseStatusUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{41} }
 
 type isCloseStatusUpdate_Update interface {
 	isCloseStatusUpdate_Update()
@@ -1395,7 +2144,7 @@ type PendingUpdate struct {
 func (m *PendingUpdate) Reset()                    { *m = PendingUpdate{} }
 func (m *PendingUpdate) String() string            { return proto.CompactTextString(m) }
 func (*PendingUpdate) ProtoMessage()               {}
-func (*PendingUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{36} }
+func (*PendingUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{42} }
 
 func (m *PendingUpdate) GetTxid() []byte {
 	if m != nil {
@@ -1422,12 +2171,28 @@ type OpenChannelRequest struct {
 	LocalFundingAmount int64 `protobuf:"varint,4,opt,name=local_funding_amount" json:"local_funding_amount,omitempty"`
 	// / The number of satoshis to push to the remote side as part of the initial commitment state
 	PushSat int64 `protobuf:"varint,5,opt,name=push_sat" json:"push_sat,omitempty"`
+	// *
+	// The dust limit in satoshis to propose for our commitment transaction. If
+	// unset, then a default derived from the network's relay rules is used.
+	DustLimit int64 `protobuf:"varint,6,opt,name=dust_limit" json:"dust_limit,omitempty"`
+	// *
+	// The network address of the node to open a channel with, e.g.
+	// `1.2.3.4:9735`, `example.com` or `[::1]:9735`. If set and we aren't
+	// already connected to the node, then a connection will first be
+	// established to it at this address. If the port is omitted, then the
+	// default port of the active network is assumed.
+	NodeAddr string `protobuf:"bytes,7,opt,name=node_addr" json:"node_addr,omitempty"`
+	// *
+	// An address to commit to as the upfront shutdown script of the channel. If
+	// set, then our funds will only ever be paid out to this address upon a
+	// cooperative close. It must be a p2pkh, p2sh, p2wkh or p2wsh address.
+	CloseAddress string `protobuf:"bytes,8,opt,name=close_address" json:"close_address,omitempty"`
 }
 
 func (m *OpenChannelRequest) Reset()                    { *m = OpenChannelRequest{} }
 func (m *OpenChannelRequest) String() string            { return proto.CompactTextString(m) }
 func (*OpenChannelRequest) ProtoMessage()               {}
-func (*OpenChannelRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{37} }
+func (*OpenChannelRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{43} }
 
 func (m *OpenChannelRequest) GetTargetPeerId() int32 {
 	if m != nil {
@@ -1464,6 +2229,27 @@ func (m *OpenChannelRequest) GetPushSat() int64 {
 	return 0
 }
 
+func (m *OpenChannelRequest) GetDustLimit() int64 {
+	if m != nil {
+		return m.DustLimit
+	}
+	return 0
+}
+
+func (m *OpenChannelRequest) GetNodeAddr() string {
+	if m != nil {
+		return m.NodeAddr
+	}
+	return ""
+}
+
+func (m *OpenChannelRequest) GetCloseAddress() string {
+	if m != nil {
+		return m.CloseAddress
+	}
+	return ""
+}
+
 type OpenStatusUpdate struct {
 	// Types that are valid to be assigned to Update:
 	//	*OpenStatusUpdate_ChanPending
@@ -1475,7 +2261,7 @@ type OpenStatusUpdate struct {
 func (m *OpenStatusUpdate) Reset()                    { *m = OpenStatusUpdate{} }
 func (m *OpenStatusUpdate) String() string            { return proto.CompactTextString(m) }
 func (*OpenStatusUpdate) ProtoMessage()               {}
-func (*OpenStatusUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{38} }
+func (*OpenStatusUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{44} }
 
 type isOpenStatusUpdate_Update interface {
 	isOpenStatusUpdate_Update()
@@ -1622,7 +2408,7 @@ type PendingChannelRequest struct {
 func (m *PendingChannelRequest) Reset()                    { *m = PendingChannelRequest{} }
 func (m *PendingChannelRequest) String() string            { return proto.CompactTextString(m) }
 func (*PendingChannelRequest) ProtoMessage()               {}
-func (*PendingChannelRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{39} }
+func (*PendingChannelRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{45} }
 
 type PendingChannelResponse struct {
 	// / The balance in satoshis encumbered in pending channels
@@ -1638,7 +2424,7 @@ type PendingChannelResponse struct {
 func (m *PendingChannelResponse) Reset()                    { *m = PendingChannelResponse{} }
 func (m *PendingChannelResponse) String() string            { return proto.CompactTextString(m) }
 func (*PendingChannelResponse) ProtoMessage()               {}
-func (*PendingChannelResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{40} }
+func (*PendingChannelResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{46} }
 
 func (m *PendingChannelResponse) GetTotalLimboBalance() int64 {
 	if m != nil {
@@ -1680,7 +2466,7 @@ func (m *PendingChannelResponse_PendingChannel) Reset()         { *m = PendingCh
 func (m *PendingChannelResponse_PendingChannel) String() string { return proto.CompactTextString(m) }
 func (*PendingChannelResponse_PendingChannel) ProtoMessage()    {}
 func (*PendingChannelResponse_PendingChannel) Descriptor() ([]byte, []int) {
-	return fileDescriptor0, []int{40, 0}
+	return fileDescriptor0, []int{46, 0}
 }
 
 func (m *PendingChannelResponse_PendingChannel) GetRemoteNodePub() string {
@@ -1744,10 +2530,12 @@ type PendingChannelResponse_PendingOpenChannel struct {
 func (m *PendingChannelResponse_PendingOpenChannel) Reset() {
 	*m = PendingChannelResponse_PendingOpenChannel{}
 }
-func (m *PendingChannelResponse_PendingOpenChannel) String() string { return proto.CompactTextString(m) }
-func (*PendingChannelResponse_PendingOpenChannel) ProtoMessage()    {}
+func (m *PendingChannelResponse_PendingOpenChannel) String() string {
+	return proto.CompactTextString(m)
+}
+func (*PendingChannelResponse_PendingOpenChannel) ProtoMessage() {}
 func (*PendingChannelResponse_PendingOpenChannel) Descriptor() ([]byte, []int) {
-	return fileDescriptor0, []int{40, 1}
+	return fileDescriptor0, []int{46, 1}
 }
 
 func (m *PendingChannelResponse_PendingOpenChannel) GetChannel() *PendingChannelResponse_PendingChannel {
@@ -1803,7 +2591,7 @@ func (m *PendingChannelResponse_ClosedChannel) Reset()         { *m = PendingCha
 func (m *PendingChannelResponse_ClosedChannel) String() string { return proto.CompactTextString(m) }
 func (*PendingChannelResponse_ClosedChannel) ProtoMessage()    {}
 func (*PendingChannelResponse_ClosedChannel) Descriptor() ([]byte, []int) {
-	return fileDescriptor0, []int{40, 2}
+	return fileDescriptor0, []int{46, 2}
 }
 
 func (m *PendingChannelResponse_ClosedChannel) GetChannel() *PendingChannelResponse_PendingChannel {
@@ -1831,15 +2619,19 @@ type PendingChannelResponse_ForceClosedChannel struct {
 	MaturityHeight uint32 `protobuf:"varint,4,opt,name=maturity_height" json:"maturity_height,omitempty"`
 	// / Remaining # of blocks until funds can be sweeped into the wallet
 	BlocksTilMaturity uint32 `protobuf:"varint,5,opt,name=blocks_til_maturity" json:"blocks_til_maturity,omitempty"`
+	// / The htlcs of the channel which are still being recovered
+	PendingHtlcs []*PendingHTLC `protobuf:"bytes,6,rep,name=pending_htlcs" json:"pending_htlcs,omitempty"`
 }
 
 func (m *PendingChannelResponse_ForceClosedChannel) Reset() {
 	*m = PendingChannelResponse_ForceClosedChannel{}
 }
-func (m *PendingChannelResponse_ForceClosedChannel) String() string { return proto.CompactTextString(m) }
-func (*PendingChannelResponse_ForceClosedChannel) ProtoMessage()    {}
+func (m *PendingChannelResponse_ForceClosedChannel) String() string {
+	return proto.CompactTextString(m)
+}
+func (*PendingChannelResponse_ForceClosedChannel) ProtoMessage() {}
 func (*PendingChannelResponse_ForceClosedChannel) Descriptor() ([]byte, []int) {
-	return fileDescriptor0, []int{40, 3}
+	return fileDescriptor0, []int{46, 3}
 }
 
 func (m *PendingChannelResponse_ForceClosedChannel) GetChannel() *PendingChannelResponse_PendingChannel {
@@ -1877,6 +2669,13 @@ func (m *PendingChannelResponse_ForceClosedChannel) GetBlocksTilMaturity() uint3
 	return 0
 }
 
+func (m *PendingChannelResponse_ForceClosedChannel) GetPendingHtlcs() []*PendingHTLC {
+	if m != nil {
+		return m.PendingHtlcs
+	}
+	return nil
+}
+
 type WalletBalanceRequest struct {
 	// / If only witness outputs should be considered when calculating the wallet's balance
 	WitnessOnly bool `protobuf:"varint,1,opt,name=witness_only,json=witnessOnly" json:"witness_only,omitempty"`
@@ -1885,7 +2684,7 @@ type WalletBalanceRequest struct {
 func (m *WalletBalanceRequest) Reset()                    { *m = WalletBalanceRequest{} }
 func (m *WalletBalanceRequest) String() string            { return proto.CompactTextString(m) }
 func (*WalletBalanceRequest) ProtoMessage()               {}
-func (*WalletBalanceRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{41} }
+func (*WalletBalanceRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{47} }
 
 func (m *WalletBalanceRequest) GetWitnessOnly() bool {
 	if m != nil {
@@ -1902,7 +2701,7 @@ type WalletBalanceResponse struct {
 func (m *WalletBalanceResponse) Reset()                    { *m = WalletBalanceResponse{} }
 func (m *WalletBalanceResponse) String() string            { return proto.CompactTextString(m) }
 func (*WalletBalanceResponse) ProtoMessage()               {}
-func (*WalletBalanceResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{42} }
+func (*WalletBalanceResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{48} }
 
 func (m *WalletBalanceResponse) GetBalance() int64 {
 	if m != nil {
@@ -1917,7 +2716,7 @@ type ChannelBalanceRequest struct {
 func (m *ChannelBalanceRequest) Reset()                    { *m = ChannelBalanceRequest{} }
 func (m *ChannelBalanceRequest) String() string            { return proto.CompactTextString(m) }
 func (*ChannelBalanceRequest) ProtoMessage()               {}
-func (*ChannelBalanceRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{43} }
+func (*ChannelBalanceRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{49} }
 
 type ChannelBalanceResponse struct {
 	// / Sum of channels balances denominated in satoshis
@@ -1927,7 +2726,7 @@ type ChannelBalanceResponse struct {
 func (m *ChannelBalanceResponse) Reset()                    { *m = ChannelBalanceResponse{} }
 func (m *ChannelBalanceResponse) String() string            { return proto.CompactTextString(m) }
 func (*ChannelBalanceResponse) ProtoMessage()               {}
-func (*ChannelBalanceResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{44} }
+func (*ChannelBalanceResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{50} }
 
 func (m *ChannelBalanceResponse) GetBalance() int64 {
 	if m != nil {
@@ -1941,12 +2740,22 @@ type QueryRoutesRequest struct {
 	PubKey string `protobuf:"bytes,1,opt,name=pub_key,json=pubKey" json:"pub_key,omitempty"`
 	// / The amount to send expressed in satoshis
 	Amt int64 `protobuf:"varint,2,opt,name=amt" json:"amt,omitempty"`
+	// *
+	// The channels of ours that the first hop of the routes may go over. If
+	// empty, any of our channels may be used.
+	OutgoingChanIds []uint64 `protobuf:"varint,3,rep,packed,name=outgoing_chan_ids,json=outgoingChanIds" json:"outgoing_chan_ids,omitempty"`
+	// / The pubkey of the node that must precede the destination within the routes, if any
+	LastHopPubkey []byte `protobuf:"bytes,4,opt,name=last_hop_pubkey,json=lastHopPubkey,proto3" json:"last_hop_pubkey,omitempty"`
+	// / The pubkeys of nodes that may not be used as hops within the routes
+	IgnoredNodes [][]byte `protobuf:"bytes,5,rep,name=ignored_nodes,json=ignoredNodes" json:"ignored_nodes,omitempty"`
+	// / The directed edges that may not be traversed by the routes
+	IgnoredEdges []*EdgeLocator `protobuf:"bytes,6,rep,name=ignored_edges,json=ignoredEdges" json:"ignored_edges,omitempty"`
 }
 
 func (m *QueryRoutesRequest) Reset()                    { *m = QueryRoutesRequest{} }
 func (m *QueryRoutesRequest) String() string            { return proto.CompactTextString(m) }
 func (*QueryRoutesRequest) ProtoMessage()               {}
-func (*QueryRoutesRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{45} }
+func (*QueryRoutesRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{51} }
 
 func (m *QueryRoutesRequest) GetPubKey() string {
 	if m != nil {
@@ -1962,6 +2771,34 @@ func (m *QueryRoutesRequest) GetAmt() int64 {
 	return 0
 }
 
+func (m *QueryRoutesRequest) GetOutgoingChanIds() []uint64 {
+	if m != nil {
+		return m.OutgoingChanIds
+	}
+	return nil
+}
+
+func (m *QueryRoutesRequest) GetLastHopPubkey() []byte {
+	if m != nil {
+		return m.LastHopPubkey
+	}
+	return nil
+}
+
+func (m *QueryRoutesRequest) GetIgnoredNodes() [][]byte {
+	if m != nil {
+		return m.IgnoredNodes
+	}
+	return nil
+}
+
+func (m *QueryRoutesRequest) GetIgnoredEdges() []*EdgeLocator {
+	if m != nil {
+		return m.IgnoredEdges
+	}
+	return nil
+}
+
 type QueryRoutesResponse struct {
 	Routes []*Route `protobuf:"bytes,1,rep,name=routes" json:"routes,omitempty"`
 }
@@ -1969,7 +2806,7 @@ type QueryRoutesResponse struct {
 func (m *QueryRoutesResponse) Reset()                    { *m = QueryRoutesResponse{} }
 func (m *QueryRoutesResponse) String() string            { return proto.CompactTextString(m) }
 func (*QueryRoutesResponse) ProtoMessage()               {}
-func (*QueryRoutesResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{46} }
+func (*QueryRoutesResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{52} }
 
 func (m *QueryRoutesResponse) GetRoutes() []*Route {
 	if m != nil {
@@ -1988,12 +2825,18 @@ type Hop struct {
 	AmtToForward int64  `protobuf:"varint,3,opt,name=amt_to_forward" json:"amt_to_forward,omitempty"`
 	Fee          int64  `protobuf:"varint,4,opt,name=fee" json:"fee,omitempty"`
 	Expiry       uint32 `protobuf:"varint,5,opt,name=expiry" json:"expiry,omitempty"`
+	// / The identity pubkey of the node at the end of this hop.
+	PubKey string `protobuf:"bytes,6,opt,name=pub_key" json:"pub_key,omitempty"`
+	// / The amount forwarded to the node at the end of this hop, in milli-atoms
+	AmtToForwardMsat int64 `protobuf:"varint,7,opt,name=amt_to_forward_msat" json:"amt_to_forward_msat,omitempty"`
+	// / The fee charged by the node forwarding over this hop, in milli-atoms
+	FeeMsat int64 `protobuf:"varint,8,opt,name=fee_msat" json:"fee_msat,omitempty"`
 }
 
 func (m *Hop) Reset()                    { *m = Hop{} }
 func (m *Hop) String() string            { return proto.CompactTextString(m) }
 func (*Hop) ProtoMessage()               {}
-func (*Hop) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{47} }
+func (*Hop) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{53} }
 
 func (m *Hop) GetChanId() uint64 {
 	if m != nil {
@@ -2030,6 +2873,27 @@ func (m *Hop) GetExpiry() uint32 {
 	return 0
 }
 
+func (m *Hop) GetPubKey() string {
+	if m != nil {
+		return m.PubKey
+	}
+	return ""
+}
+
+func (m *Hop) GetAmtToForwardMsat() int64 {
+	if m != nil {
+		return m.AmtToForwardMsat
+	}
+	return 0
+}
+
+func (m *Hop) GetFeeMsat() int64 {
+	if m != nil {
+		return m.FeeMsat
+	}
+	return 0
+}
+
 // *
 // A path through the channel graph which runs over one or more channels in
 // succession. This struct carries all the information required to craft the
@@ -2058,12 +2922,16 @@ type Route struct {
 	// *
 	// Contains details concerning the specific forwarding details at each hop.
 	Hops []*Hop `protobuf:"bytes,4,rep,name=hops" json:"hops,omitempty"`
+	// / The sum of the fees paid at each hop within the route, in milli-atoms
+	TotalFeesMsat int64 `protobuf:"varint,5,opt,name=total_fees_msat" json:"total_fees_msat,omitempty"`
+	// / The total amount of funds required to complete a payment over this route, in milli-atoms
+	TotalAmtMsat int64 `protobuf:"varint,6,opt,name=total_amt_msat" json:"total_amt_msat,omitempty"`
 }
 
 func (m *Route) Reset()                    { *m = Route{} }
 func (m *Route) String() string            { return proto.CompactTextString(m) }
 func (*Route) ProtoMessage()               {}
-func (*Route) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{48} }
+func (*Route) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{54} }
 
 func (m *Route) GetTotalTimeLock() uint32 {
 	if m != nil {
@@ -2093,6 +2961,20 @@ func (m *Route) GetHops() []*Hop {
 	return nil
 }
 
+func (m *Route) GetTotalFeesMsat() int64 {
+	if m != nil {
+		return m.TotalFeesMsat
+	}
+	return 0
+}
+
+func (m *Route) GetTotalAmtMsat() int64 {
+	if m != nil {
+		return m.TotalAmtMsat
+	}
+	return 0
+}
+
 type NodeInfoRequest struct {
 	// / The 33-byte hex-encoded compressed public of the target node
 	PubKey string `protobuf:"bytes,1,opt,name=pub_key,json=pubKey" json:"pub_key,omitempty"`
@@ -2101,7 +2983,7 @@ type NodeInfoRequest struct {
 func (m *NodeInfoRequest) Reset()                    { *m = NodeInfoRequest{} }
 func (m *NodeInfoRequest) String() string            { return proto.CompactTextString(m) }
 func (*NodeInfoRequest) ProtoMessage()               {}
-func (*NodeInfoRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{49} }
+func (*NodeInfoRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{55} }
 
 func (m *NodeInfoRequest) GetPubKey() string {
 	if m != nil {
@@ -2124,7 +3006,7 @@ type NodeInfo struct {
 func (m *NodeInfo) Reset()                    { *m = NodeInfo{} }
 func (m *NodeInfo) String() string            { return proto.CompactTextString(m) }
 func (*NodeInfo) ProtoMessage()               {}
-func (*NodeInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{50} }
+func (*NodeInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{56} }
 
 func (m *NodeInfo) GetNode() *LightningNode {
 	if m != nil {
@@ -2162,7 +3044,7 @@ type LightningNode struct {
 func (m *LightningNode) Reset()                    { *m = LightningNode{} }
 func (m *LightningNode) String() string            { return proto.CompactTextString(m) }
 func (*LightningNode) ProtoMessage()               {}
-func (*LightningNode) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{51} }
+func (*LightningNode) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{57} }
 
 func (m *LightningNode) GetLastUpdate() uint32 {
 	if m != nil {
@@ -2200,7 +3082,7 @@ type NodeAddress struct {
 func (m *NodeAddress) Reset()                    { *m = NodeAddress{} }
 func (m *NodeAddress) String() string            { return proto.CompactTextString(m) }
 func (*NodeAddress) ProtoMessage()               {}
-func (*NodeAddress) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{52} }
+func (*NodeAddress) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{58} }
 
 func (m *NodeAddress) GetNetwork() string {
 	if m != nil {
@@ -2226,7 +3108,7 @@ type RoutingPolicy struct {
 func (m *RoutingPolicy) Reset()                    { *m = RoutingPolicy{} }
 func (m *RoutingPolicy) String() string            { return proto.CompactTextString(m) }
 func (*RoutingPolicy) ProtoMessage()               {}
-func (*RoutingPolicy) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{53} }
+func (*RoutingPolicy) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{59} }
 
 func (m *RoutingPolicy) GetTimeLockDelta() uint32 {
 	if m != nil {
@@ -2280,7 +3162,7 @@ type ChannelEdge struct {
 func (m *ChannelEdge) Reset()                    { *m = ChannelEdge{} }
 func (m *ChannelEdge) String() string            { return proto.CompactTextString(m) }
 func (*ChannelEdge) ProtoMessage()               {}
-func (*ChannelEdge) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{54} }
+func (*ChannelEdge) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{60} }
 
 func (m *ChannelEdge) GetChannelId() uint64 {
 	if m != nil {
@@ -2344,7 +3226,7 @@ type ChannelGraphRequest struct {
 func (m *ChannelGraphRequest) Reset()                    { *m = ChannelGraphRequest{} }
 func (m *ChannelGraphRequest) String() string            { return proto.CompactTextString(m) }
 func (*ChannelGraphRequest) ProtoMessage()               {}
-func (*ChannelGraphRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{55} }
+func (*ChannelGraphRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{61} }
 
 // / Returns a new instance of the directed channel graph.
 type ChannelGraph struct {
@@ -2357,7 +3239,7 @@ type ChannelGraph struct {
 func (m *ChannelGraph) Reset()                    { *m = ChannelGraph{} }
 func (m *ChannelGraph) String() string            { return proto.CompactTextString(m) }
 func (*ChannelGraph) ProtoMessage()               {}
-func (*ChannelGraph) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{56} }
+func (*ChannelGraph) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{62} }
 
 func (m *ChannelGraph) GetNodes() []*LightningNode {
 	if m != nil {
@@ -2384,7 +3266,7 @@ type ChanInfoRequest struct {
 func (m *ChanInfoRequest) Reset()                    { *m = ChanInfoRequest{} }
 func (m *ChanInfoRequest) String() string            { return proto.CompactTextString(m) }
 func (*ChanInfoRequest) ProtoMessage()               {}
-func (*ChanInfoRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{57} }
+func (*ChanInfoRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{63} }
 
 func (m *ChanInfoRequest) GetChanId() uint64 {
 	if m != nil {
@@ -2399,7 +3281,7 @@ type NetworkInfoRequest struct {
 func (m *NetworkInfoRequest) Reset()                    { *m = NetworkInfoRequest{} }
 func (m *NetworkInfoRequest) String() string            { return proto.CompactTextString(m) }
 func (*NetworkInfoRequest) ProtoMessage()               {}
-func (*NetworkInfoRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{58} }
+func (*NetworkInfoRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{64} }
 
 type NetworkInfo struct {
 	GraphDiameter        uint32  `protobuf:"varint,1,opt,name=graph_diameter" json:"graph_diameter,omitempty"`
@@ -2411,12 +3293,14 @@ type NetworkInfo struct {
 	AvgChannelSize       float64 `protobuf:"fixed64,7,opt,name=avg_channel_size" json:"avg_channel_size,omitempty"`
 	MinChannelSize       int64   `protobuf:"varint,8,opt,name=min_channel_size" json:"min_channel_size,omitempty"`
 	MaxChannelSize       int64   `protobuf:"varint,9,opt,name=max_channel_size" json:"max_channel_size,omitempty"`
+	// / The number of nodes pruned from the graph since startup, as they no longer had any channels.
+	NumPrunedNodes uint64 `protobuf:"varint,10,opt,name=num_pruned_nodes" json:"num_pruned_nodes,omitempty"`
 }
 
 func (m *NetworkInfo) Reset()                    { *m = NetworkInfo{} }
 func (m *NetworkInfo) String() string            { return proto.CompactTextString(m) }
 func (*NetworkInfo) ProtoMessage()               {}
-func (*NetworkInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{59} }
+func (*NetworkInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{65} }
 
 func (m *NetworkInfo) GetGraphDiameter() uint32 {
 	if m != nil {
@@ -2481,13 +3365,20 @@ func (m *NetworkInfo) GetMaxChannelSize() int64 {
 	return 0
 }
 
+func (m *NetworkInfo) GetNumPrunedNodes() uint64 {
+	if m != nil {
+		return m.NumPrunedNodes
+	}
+	return 0
+}
+
 type StopRequest struct {
 }
 
 func (m *StopRequest) Reset()                    { *m = StopRequest{} }
 func (m *StopRequest) String() string            { return proto.CompactTextString(m) }
 func (*StopRequest) ProtoMessage()               {}
-func (*StopRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{60} }
+func (*StopRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{66} }
 
 type StopResponse struct {
 }
@@ -2495,7 +3386,7 @@ type StopResponse struct {
 func (m *StopResponse) Reset()                    { *m = StopResponse{} }
 func (m *StopResponse) String() string            { return proto.CompactTextString(m) }
 func (*StopResponse) ProtoMessage()               {}
-func (*StopResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{61} }
+func (*StopResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{67} }
 
 type GraphTopologySubscription struct {
 }
@@ -2503,7 +3394,7 @@ type GraphTopologySubscription struct {
 func (m *GraphTopologySubscription) Reset()                    { *m = GraphTopologySubscription{} }
 func (m *GraphTopologySubscription) String() string            { return proto.CompactTextString(m) }
 func (*GraphTopologySubscription) ProtoMessage()               {}
-func (*GraphTopologySubscription) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{62} }
+func (*GraphTopologySubscription) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{68} }
 
 type GraphTopologyUpdate struct {
 	NodeUpdates    []*NodeUpdate          `protobuf:"bytes,1,rep,name=node_updates,json=nodeUpdates" json:"node_updates,omitempty"`
@@ -2514,7 +3405,7 @@ type GraphTopologyUpdate struct {
 func (m *GraphTopologyUpdate) Reset()                    { *m = GraphTopologyUpdate{} }
 func (m *GraphTopologyUpdate) String() string            { return proto.CompactTextString(m) }
 func (*GraphTopologyUpdate) ProtoMessage()               {}
-func (*GraphTopologyUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{63} }
+func (*GraphTopologyUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{69} }
 
 func (m *GraphTopologyUpdate) GetNodeUpdates() []*NodeUpdate {
 	if m != nil {
@@ -2547,7 +3438,7 @@ type NodeUpdate struct {
 func (m *NodeUpdate) Reset()                    { *m = NodeUpdate{} }
 func (m *NodeUpdate) String() string            { return proto.CompactTextString(m) }
 func (*NodeUpdate) ProtoMessage()               {}
-func (*NodeUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{64} }
+func (*NodeUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{70} }
 
 func (m *NodeUpdate) GetAddresses() []string {
 	if m != nil {
@@ -2593,7 +3484,7 @@ type ChannelEdgeUpdate struct {
 func (m *ChannelEdgeUpdate) Reset()                    { *m = ChannelEdgeUpdate{} }
 func (m *ChannelEdgeUpdate) String() string            { return proto.CompactTextString(m) }
 func (*ChannelEdgeUpdate) ProtoMessage()               {}
-func (*ChannelEdgeUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{65} }
+func (*ChannelEdgeUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{71} }
 
 func (m *ChannelEdgeUpdate) GetChanId() uint64 {
 	if m != nil {
@@ -2651,7 +3542,7 @@ type ClosedChannelUpdate struct {
 func (m *ClosedChannelUpdate) Reset()                    { *m = ClosedChannelUpdate{} }
 func (m *ClosedChannelUpdate) String() string            { return proto.CompactTextString(m) }
 func (*ClosedChannelUpdate) ProtoMessage()               {}
-func (*ClosedChannelUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{66} }
+func (*ClosedChannelUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{72} }
 
 func (m *ClosedChannelUpdate) GetChanId() uint64 {
 	if m != nil {
@@ -2688,7 +3579,7 @@ type SetAliasRequest struct {
 func (m *SetAliasRequest) Reset()                    { *m = SetAliasRequest{} }
 func (m *SetAliasRequest) String() string            { return proto.CompactTextString(m) }
 func (*SetAliasRequest) ProtoMessage()               {}
-func (*SetAliasRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{67} }
+func (*SetAliasRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{73} }
 
 func (m *SetAliasRequest) GetNewAlias() string {
 	if m != nil {
@@ -2703,7 +3594,7 @@ type SetAliasResponse struct {
 func (m *SetAliasResponse) Reset()                    { *m = SetAliasResponse{} }
 func (m *SetAliasResponse) String() string            { return proto.CompactTextString(m) }
 func (*SetAliasResponse) ProtoMessage()               {}
-func (*SetAliasResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{68} }
+func (*SetAliasResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{74} }
 
 type Invoice struct {
 	// / An optional memo to attach along with the invoice
@@ -2729,12 +3620,37 @@ type Invoice struct {
 	// details of the invoice, the sender has all the data necessary to send a
 	// payment to the recipient.
 	PaymentRequest string `protobuf:"bytes,9,opt,name=payment_request" json:"payment_request,omitempty"`
+	// *
+	// The "add" index of this invoice. Each newly created invoice will increment
+	// this index making it monotonically increasing. Callers to the
+	// SubscribeInvoices call can use this to instantly get notified of all added
+	// invoices with an add_index greater than this one.
+	AddIndex uint64 `protobuf:"varint,10,opt,name=add_index" json:"add_index,omitempty"`
+	// *
+	// The "settle" index of this invoice. Each newly settled invoice will
+	// increment this index making it monotonically increasing. Callers to the
+	// SubscribeInvoices call can use this to instantly get notified of all
+	// settled invoices with an settle_index greater than this one.
+	SettleIndex uint64 `protobuf:"varint,11,opt,name=settle_index" json:"settle_index,omitempty"`
+	// / The HTLCs which paid this invoice, in the order they were settled.
+	Htlcs []*InvoiceHTLC `protobuf:"bytes,12,rep,name=htlcs" json:"htlcs,omitempty"`
+	// / The total amount paid to this invoice by its HTLCs, in milli-atoms.
+	AmtPaidMsat uint64 `protobuf:"varint,13,opt,name=amt_paid_msat" json:"amt_paid_msat,omitempty"`
+	// *
+	// The state of the invoice. A hold invoice is accepted once an HTLC paying
+	// it has been received, which is held until the invoice is either settled or
+	// canceled.
+	State Invoice_InvoiceState `protobuf:"varint,14,opt,name=state,enum=lnrpc.Invoice_InvoiceState" json:"state,omitempty"`
+	// *
+	// The payment address of the invoice, which payments split into multiple
+	// shards must carry within the payload of their final hop.
+	PaymentAddr []byte `protobuf:"bytes,15,opt,name=payment_addr,proto3" json:"payment_addr,omitempty"`
 }
 
 func (m *Invoice) Reset()                    { *m = Invoice{} }
 func (m *Invoice) String() string            { return proto.CompactTextString(m) }
 func (*Invoice) ProtoMessage()               {}
-func (*Invoice) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{69} }
+func (*Invoice) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{75} }
 
 func (m *Invoice) GetMemo() string {
 	if m != nil {
@@ -2799,32 +3715,171 @@ func (m *Invoice) GetPaymentRequest() string {
 	return ""
 }
 
-type AddInvoiceResponse struct {
-	RHash []byte `protobuf:"bytes,1,opt,name=r_hash,proto3" json:"r_hash,omitempty"`
-	// *
-	// A bare-bones invoice for a payment within the Lightning Network.  With the
-	// details of the invoice, the sender has all the data necessary to send a
-	// payment to the recipient.
-	PaymentRequest string `protobuf:"bytes,2,opt,name=payment_request" json:"payment_request,omitempty"`
+func (m *Invoice) GetAddIndex() uint64 {
+	if m != nil {
+		return m.AddIndex
+	}
+	return 0
 }
 
-func (m *AddInvoiceResponse) Reset()                    { *m = AddInvoiceResponse{} }
-func (m *AddInvoiceResponse) String() string            { return proto.CompactTextString(m) }
-func (*AddInvoiceResponse) ProtoMessage()               {}
-func (*AddInvoiceResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{70} }
+func (m *Invoice) GetSettleIndex() uint64 {
+	if m != nil {
+		return m.SettleIndex
+	}
+	return 0
+}
 
-func (m *AddInvoiceResponse) GetRHash() []byte {
+func (m *Invoice) GetHtlcs() []*InvoiceHTLC {
 	if m != nil {
-		return m.RHash
+		return m.Htlcs
 	}
 	return nil
 }
 
-func (m *AddInvoiceResponse) GetPaymentRequest() string {
+func (m *Invoice) GetAmtPaidMsat() uint64 {
 	if m != nil {
-		return m.PaymentRequest
+		return m.AmtPaidMsat
 	}
-	return ""
+	return 0
+}
+
+func (m *Invoice) GetState() Invoice_InvoiceState {
+	if m != nil {
+		return m.State
+	}
+	return Invoice_OPEN
+}
+
+func (m *Invoice) GetPaymentAddr() []byte {
+	if m != nil {
+		return m.PaymentAddr
+	}
+	return nil
+}
+
+type InvoiceHTLC struct {
+	// / The short channel ID of the channel the HTLC arrived on.
+	ChanId uint64 `protobuf:"varint,1,opt,name=chan_id" json:"chan_id,omitempty"`
+	// / The index of the HTLC within the channel's update log.
+	HtlcIndex uint64 `protobuf:"varint,2,opt,name=htlc_index" json:"htlc_index,omitempty"`
+	// / The amount of the HTLC in milli-atoms.
+	AmtMsat uint64 `protobuf:"varint,3,opt,name=amt_msat" json:"amt_msat,omitempty"`
+	// / The absolute block height at which the HTLC expires.
+	ExpiryHeight uint32 `protobuf:"varint,4,opt,name=expiry_height" json:"expiry_height,omitempty"`
+	// / The block height at which the HTLC was accepted.
+	AcceptHeight uint32 `protobuf:"varint,5,opt,name=accept_height" json:"accept_height,omitempty"`
+	// / The time at which the HTLC was settled, in seconds since the epoch.
+	SettleTime int64 `protobuf:"varint,6,opt,name=settle_time" json:"settle_time,omitempty"`
+}
+
+func (m *InvoiceHTLC) Reset()                    { *m = InvoiceHTLC{} }
+func (m *InvoiceHTLC) String() string            { return proto.CompactTextString(m) }
+func (*InvoiceHTLC) ProtoMessage()               {}
+func (*InvoiceHTLC) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{76} }
+
+func (m *InvoiceHTLC) GetChanId() uint64 {
+	if m != nil {
+		return m.ChanId
+	}
+	return 0
+}
+
+func (m *InvoiceHTLC) GetHtlcIndex() uint64 {
+	if m != nil {
+		return m.HtlcIndex
+	}
+	return 0
+}
+
+func (m *InvoiceHTLC) GetAmtMsat() uint64 {
+	if m != nil {
+		return m.AmtMsat
+	}
+	return 0
+}
+
+func (m *InvoiceHTLC) GetExpiryHeight() uint32 {
+	if m != nil {
+		return m.ExpiryHeight
+	}
+	return 0
+}
+
+func (m *InvoiceHTLC) GetAcceptHeight() uint32 {
+	if m != nil {
+		return m.AcceptHeight
+	}
+	return 0
+}
+
+func (m *InvoiceHTLC) GetSettleTime() int64 {
+	if m != nil {
+		return m.SettleTime
+	}
+	return 0
+}
+
+type AddInvoiceResponse struct {
+	RHash []byte `protobuf:"bytes,1,opt,name=r_hash,proto3" json:"r_hash,omitempty"`
+	// *
+	// A bare-bones invoice for a payment within the Lightning Network.  With the
+	// details of the invoice, the sender has all the data necessary to send a
+	// payment to the recipient.
+	PaymentRequest string `protobuf:"bytes,2,opt,name=payment_request" json:"payment_request,omitempty"`
+	// *
+	// The "add" index of this invoice. Each newly created invoice will increment
+	// this index making it monotonically increasing. Callers to the
+	// SubscribeInvoices call can use this to instantly get notified of all added
+	// invoices with an add_index greater than this one.
+	AddIndex uint64 `protobuf:"varint,16,opt,name=add_index" json:"add_index,omitempty"`
+	// *
+	// The payment address of the invoice, which payments split into multiple
+	// shards must carry within the payload of their final hop.
+	PaymentAddr []byte `protobuf:"bytes,17,opt,name=payment_addr,proto3" json:"payment_addr,omitempty"`
+	// *
+	// The chains of private channels leading to our node, which payers that
+	// don't know of them may use to reach it.
+	RouteHints []*RouteHint `protobuf:"bytes,18,rep,name=route_hints" json:"route_hints,omitempty"`
+}
+
+func (m *AddInvoiceResponse) Reset()                    { *m = AddInvoiceResponse{} }
+func (m *AddInvoiceResponse) String() string            { return proto.CompactTextString(m) }
+func (*AddInvoiceResponse) ProtoMessage()               {}
+func (*AddInvoiceResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{77} }
+
+func (m *AddInvoiceResponse) GetRHash() []byte {
+	if m != nil {
+		return m.RHash
+	}
+	return nil
+}
+
+func (m *AddInvoiceResponse) GetPaymentRequest() string {
+	if m != nil {
+		return m.PaymentRequest
+	}
+	return ""
+}
+
+func (m *AddInvoiceResponse) GetAddIndex() uint64 {
+	if m != nil {
+		return m.AddIndex
+	}
+	return 0
+}
+
+func (m *AddInvoiceResponse) GetPaymentAddr() []byte {
+	if m != nil {
+		return m.PaymentAddr
+	}
+	return nil
+}
+
+func (m *AddInvoiceResponse) GetRouteHints() []*RouteHint {
+	if m != nil {
+		return m.RouteHints
+	}
+	return nil
 }
 
 type PaymentHash struct {
@@ -2839,7 +3894,7 @@ type PaymentHash struct {
 func (m *PaymentHash) Reset()                    { *m = PaymentHash{} }
 func (m *PaymentHash) String() string            { return proto.CompactTextString(m) }
 func (*PaymentHash) ProtoMessage()               {}
-func (*PaymentHash) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{71} }
+func (*PaymentHash) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{78} }
 
 func (m *PaymentHash) GetRHashStr() string {
 	if m != nil {
@@ -2863,7 +3918,7 @@ type ListInvoiceRequest struct {
 func (m *ListInvoiceRequest) Reset()                    { *m = ListInvoiceRequest{} }
 func (m *ListInvoiceRequest) String() string            { return proto.CompactTextString(m) }
 func (*ListInvoiceRequest) ProtoMessage()               {}
-func (*ListInvoiceRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{72} }
+func (*ListInvoiceRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{79} }
 
 func (m *ListInvoiceRequest) GetPendingOnly() bool {
 	if m != nil {
@@ -2879,7 +3934,7 @@ type ListInvoiceResponse struct {
 func (m *ListInvoiceResponse) Reset()                    { *m = ListInvoiceResponse{} }
 func (m *ListInvoiceResponse) String() string            { return proto.CompactTextString(m) }
 func (*ListInvoiceResponse) ProtoMessage()               {}
-func (*ListInvoiceResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{73} }
+func (*ListInvoiceResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{80} }
 
 func (m *ListInvoiceResponse) GetInvoices() []*Invoice {
 	if m != nil {
@@ -2888,13 +3943,69 @@ func (m *ListInvoiceResponse) GetInvoices() []*Invoice {
 	return nil
 }
 
+type SettleEventAck struct {
+	// *
+	// The identifier of the durable consumer. Events acknowledged by a consumer
+	// won't be delivered to it again.
+	ConsumerId string `protobuf:"bytes,1,opt,name=consumer_id" json:"consumer_id,omitempty"`
+	// *
+	// If specified (non-zero), then all settlement events with a settle_index up
+	// to and including this value are acknowledged by the consumer.
+	SettleIndex uint64 `protobuf:"varint,2,opt,name=settle_index" json:"settle_index,omitempty"`
+}
+
+func (m *SettleEventAck) Reset()                    { *m = SettleEventAck{} }
+func (m *SettleEventAck) String() string            { return proto.CompactTextString(m) }
+func (*SettleEventAck) ProtoMessage()               {}
+func (*SettleEventAck) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{81} }
+
+func (m *SettleEventAck) GetConsumerId() string {
+	if m != nil {
+		return m.ConsumerId
+	}
+	return ""
+}
+
+func (m *SettleEventAck) GetSettleIndex() uint64 {
+	if m != nil {
+		return m.SettleIndex
+	}
+	return 0
+}
+
 type InvoiceSubscription struct {
+	// *
+	// If specified (non-zero), then we'll first start by sending out
+	// notifications for all added indexes with an add_index greater than this
+	// value. This allows callers to catch up on any events they missed while they
+	// weren't connected to the streaming RPC.
+	AddIndex uint64 `protobuf:"varint,1,opt,name=add_index" json:"add_index,omitempty"`
+	// *
+	// If specified (non-zero), then we'll first start by sending out
+	// notifications for all settled indexes with an settle_index greater than
+	// this value. This allows callers to catch up on any events they missed while
+	// they weren't connected to the streaming RPC.
+	SettleIndex uint64 `protobuf:"varint,2,opt,name=settle_index" json:"settle_index,omitempty"`
 }
 
 func (m *InvoiceSubscription) Reset()                    { *m = InvoiceSubscription{} }
 func (m *InvoiceSubscription) String() string            { return proto.CompactTextString(m) }
 func (*InvoiceSubscription) ProtoMessage()               {}
-func (*InvoiceSubscription) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{74} }
+func (*InvoiceSubscription) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{82} }
+
+func (m *InvoiceSubscription) GetAddIndex() uint64 {
+	if m != nil {
+		return m.AddIndex
+	}
+	return 0
+}
+
+func (m *InvoiceSubscription) GetSettleIndex() uint64 {
+	if m != nil {
+		return m.SettleIndex
+	}
+	return 0
+}
 
 type Payment struct {
 	// / The payment hash
@@ -2907,12 +4018,28 @@ type Payment struct {
 	Path []string `protobuf:"bytes,4,rep,name=path" json:"path,omitempty"`
 	// / The fee paid for this payment in satoshis
 	Fee int64 `protobuf:"varint,5,opt,name=fee" json:"fee,omitempty"`
+	// / The payment preimage, only set for succeeded payments
+	PaymentPreimage string `protobuf:"bytes,6,opt,name=payment_preimage" json:"payment_preimage,omitempty"`
+	// / The optional payment request being fulfilled
+	PaymentRequest string `protobuf:"bytes,7,opt,name=payment_request" json:"payment_request,omitempty"`
+	// / The status of the payment
+	Status Payment_PaymentStatus `protobuf:"varint,8,opt,name=status,enum=lnrpc.Payment_PaymentStatus" json:"status,omitempty"`
+	// / The HTLC attempts made in order to complete this payment
+	Htlcs []*HTLCAttempt `protobuf:"bytes,9,rep,name=htlcs" json:"htlcs,omitempty"`
+	// / The reason the payment failed, only set for failed payments
+	FailureReason string `protobuf:"bytes,10,opt,name=failure_reason" json:"failure_reason,omitempty"`
+	// / The stable code of the reason the payment failed, only set for failed payments
+	FailureCode PaymentFailureReason `protobuf:"varint,11,opt,name=failure_code,enum=lnrpc.PaymentFailureReason" json:"failure_code,omitempty"`
+	// *
+	// A human-readable description of why the payment failed, only set for failed
+	// payments.
+	FailureDetail string `protobuf:"bytes,12,opt,name=failure_detail" json:"failure_detail,omitempty"`
 }
 
 func (m *Payment) Reset()                    { *m = Payment{} }
 func (m *Payment) String() string            { return proto.CompactTextString(m) }
 func (*Payment) ProtoMessage()               {}
-func (*Payment) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{75} }
+func (*Payment) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{83} }
 
 func (m *Payment) GetPaymentHash() string {
 	if m != nil {
@@ -2949,23 +4076,185 @@ func (m *Payment) GetFee() int64 {
 	return 0
 }
 
+func (m *Payment) GetPaymentPreimage() string {
+	if m != nil {
+		return m.PaymentPreimage
+	}
+	return ""
+}
+
+func (m *Payment) GetPaymentRequest() string {
+	if m != nil {
+		return m.PaymentRequest
+	}
+	return ""
+}
+
+func (m *Payment) GetStatus() Payment_PaymentStatus {
+	if m != nil {
+		return m.Status
+	}
+	return Payment_UNKNOWN
+}
+
+func (m *Payment) GetHtlcs() []*HTLCAttempt {
+	if m != nil {
+		return m.Htlcs
+	}
+	return nil
+}
+
+func (m *Payment) GetFailureReason() string {
+	if m != nil {
+		return m.FailureReason
+	}
+	return ""
+}
+
+func (m *Payment) GetFailureCode() PaymentFailureReason {
+	if m != nil {
+		return m.FailureCode
+	}
+	return PaymentFailureReason_FAILURE_REASON_NONE
+}
+
+func (m *Payment) GetFailureDetail() string {
+	if m != nil {
+		return m.FailureDetail
+	}
+	return ""
+}
+
+type HTLCAttempt struct {
+	// / The unique ID of this attempt
+	AttemptId uint64 `protobuf:"varint,1,opt,name=attempt_id" json:"attempt_id,omitempty"`
+	// / The status of the HTLC
+	Status HTLCAttempt_HTLCStatus `protobuf:"varint,2,opt,name=status,enum=lnrpc.HTLCAttempt_HTLCStatus" json:"status,omitempty"`
+	// / The route taken by this HTLC
+	Route *Route `protobuf:"bytes,3,opt,name=route" json:"route,omitempty"`
+	// / The time in UNIX nanoseconds at which this HTLC was sent
+	AttemptTimeNs int64 `protobuf:"varint,4,opt,name=attempt_time_ns" json:"attempt_time_ns,omitempty"`
+	// *
+	// The time in UNIX nanoseconds at which this HTLC was settled or failed.
+	// This value will not be set if the HTLC is still IN_FLIGHT.
+	ResolveTimeNs int64 `protobuf:"varint,5,opt,name=resolve_time_ns" json:"resolve_time_ns,omitempty"`
+	// / A description of the failure, only set for failed HTLCs
+	Failure string `protobuf:"bytes,6,opt,name=failure" json:"failure,omitempty"`
+}
+
+func (m *HTLCAttempt) Reset()                    { *m = HTLCAttempt{} }
+func (m *HTLCAttempt) String() string            { return proto.CompactTextString(m) }
+func (*HTLCAttempt) ProtoMessage()               {}
+func (*HTLCAttempt) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{84} }
+
+func (m *HTLCAttempt) GetAttemptId() uint64 {
+	if m != nil {
+		return m.AttemptId
+	}
+	return 0
+}
+
+func (m *HTLCAttempt) GetStatus() HTLCAttempt_HTLCStatus {
+	if m != nil {
+		return m.Status
+	}
+	return HTLCAttempt_IN_FLIGHT
+}
+
+func (m *HTLCAttempt) GetRoute() *Route {
+	if m != nil {
+		return m.Route
+	}
+	return nil
+}
+
+func (m *HTLCAttempt) GetAttemptTimeNs() int64 {
+	if m != nil {
+		return m.AttemptTimeNs
+	}
+	return 0
+}
+
+func (m *HTLCAttempt) GetResolveTimeNs() int64 {
+	if m != nil {
+		return m.ResolveTimeNs
+	}
+	return 0
+}
+
+func (m *HTLCAttempt) GetFailure() string {
+	if m != nil {
+		return m.Failure
+	}
+	return ""
+}
+
 type ListPaymentsRequest struct {
+	// *
+	// If true, then in-flight and failed payments are returned along with the
+	// payments which succeeded.
+	IncludeIncomplete bool `protobuf:"varint,1,opt,name=include_incomplete" json:"include_incomplete,omitempty"`
+	// *
+	// The sequence number of the payment the query starts after, or ends before
+	// if reversed is set. The payment with this sequence number is excluded
+	// from the response. This can be set to the first or last index offset of a
+	// previous response in order to page through the payments.
+	IndexOffset uint64 `protobuf:"varint,2,opt,name=index_offset" json:"index_offset,omitempty"`
+	// / The maximum number of payments to return. If zero, all are returned.
+	MaxPayments uint64 `protobuf:"varint,3,opt,name=max_payments" json:"max_payments,omitempty"`
+	// *
+	// If true, then the payments created before the index offset are returned,
+	// starting with the newest payment if no index offset is specified. The
+	// payments within the response are still ordered from oldest to newest.
+	Reversed bool `protobuf:"varint,4,opt,name=reversed" json:"reversed,omitempty"`
 }
 
 func (m *ListPaymentsRequest) Reset()                    { *m = ListPaymentsRequest{} }
 func (m *ListPaymentsRequest) String() string            { return proto.CompactTextString(m) }
 func (*ListPaymentsRequest) ProtoMessage()               {}
-func (*ListPaymentsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{76} }
+func (*ListPaymentsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{85} }
+
+func (m *ListPaymentsRequest) GetIncludeIncomplete() bool {
+	if m != nil {
+		return m.IncludeIncomplete
+	}
+	return false
+}
+
+func (m *ListPaymentsRequest) GetIndexOffset() uint64 {
+	if m != nil {
+		return m.IndexOffset
+	}
+	return 0
+}
+
+func (m *ListPaymentsRequest) GetMaxPayments() uint64 {
+	if m != nil {
+		return m.MaxPayments
+	}
+	return 0
+}
+
+func (m *ListPaymentsRequest) GetReversed() bool {
+	if m != nil {
+		return m.Reversed
+	}
+	return false
+}
 
 type ListPaymentsResponse struct {
 	// / The list of payments
 	Payments []*Payment `protobuf:"bytes,1,rep,name=payments" json:"payments,omitempty"`
+	// / The sequence number of the first payment within the response
+	FirstIndexOffset uint64 `protobuf:"varint,2,opt,name=first_index_offset" json:"first_index_offset,omitempty"`
+	// / The sequence number of the last payment within the response
+	LastIndexOffset uint64 `protobuf:"varint,3,opt,name=last_index_offset" json:"last_index_offset,omitempty"`
 }
 
 func (m *ListPaymentsResponse) Reset()                    { *m = ListPaymentsResponse{} }
 func (m *ListPaymentsResponse) String() string            { return proto.CompactTextString(m) }
 func (*ListPaymentsResponse) ProtoMessage()               {}
-func (*ListPaymentsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{77} }
+func (*ListPaymentsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{86} }
 
 func (m *ListPaymentsResponse) GetPayments() []*Payment {
 	if m != nil {
@@ -2974,13 +4263,27 @@ func (m *ListPaymentsResponse) GetPayments() []*Payment {
 	return nil
 }
 
+func (m *ListPaymentsResponse) GetFirstIndexOffset() uint64 {
+	if m != nil {
+		return m.FirstIndexOffset
+	}
+	return 0
+}
+
+func (m *ListPaymentsResponse) GetLastIndexOffset() uint64 {
+	if m != nil {
+		return m.LastIndexOffset
+	}
+	return 0
+}
+
 type DeleteAllPaymentsRequest struct {
 }
 
 func (m *DeleteAllPaymentsRequest) Reset()                    { *m = DeleteAllPaymentsRequest{} }
 func (m *DeleteAllPaymentsRequest) String() string            { return proto.CompactTextString(m) }
 func (*DeleteAllPaymentsRequest) ProtoMessage()               {}
-func (*DeleteAllPaymentsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{78} }
+func (*DeleteAllPaymentsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{87} }
 
 type DeleteAllPaymentsResponse struct {
 }
@@ -2988,7 +4291,7 @@ type DeleteAllPaymentsResponse struct {
 func (m *DeleteAllPaymentsResponse) Reset()                    { *m = DeleteAllPaymentsResponse{} }
 func (m *DeleteAllPaymentsResponse) String() string            { return proto.CompactTextString(m) }
 func (*DeleteAllPaymentsResponse) ProtoMessage()               {}
-func (*DeleteAllPaymentsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{79} }
+func (*DeleteAllPaymentsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{88} }
 
 type DebugLevelRequest struct {
 	Show      bool   `protobuf:"varint,1,opt,name=show" json:"show,omitempty"`
@@ -2998,7 +4301,7 @@ type DebugLevelRequest struct {
 func (m *DebugLevelRequest) Reset()                    { *m = DebugLevelRequest{} }
 func (m *DebugLevelRequest) String() string            { return proto.CompactTextString(m) }
 func (*DebugLevelRequest) ProtoMessage()               {}
-func (*DebugLevelRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{80} }
+func (*DebugLevelRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{89} }
 
 func (m *DebugLevelRequest) GetShow() bool {
 	if m != nil {
@@ -3021,7 +4324,7 @@ type DebugLevelResponse struct {
 func (m *DebugLevelResponse) Reset()                    { *m = DebugLevelResponse{} }
 func (m *DebugLevelResponse) String() string            { return proto.CompactTextString(m) }
 func (*DebugLevelResponse) ProtoMessage()               {}
-func (*DebugLevelResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{81} }
+func (*DebugLevelResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{90} }
 
 func (m *DebugLevelResponse) GetSubSystems() string {
 	if m != nil {
@@ -3038,7 +4341,7 @@ type PayReqString struct {
 func (m *PayReqString) Reset()                    { *m = PayReqString{} }
 func (m *PayReqString) String() string            { return proto.CompactTextString(m) }
 func (*PayReqString) ProtoMessage()               {}
-func (*PayReqString) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{82} }
+func (*PayReqString) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{91} }
 
 func (m *PayReqString) GetPayReq() string {
 	if m != nil {
@@ -3056,7 +4359,7 @@ type PayReq struct {
 func (m *PayReq) Reset()                    { *m = PayReq{} }
 func (m *PayReq) String() string            { return proto.CompactTextString(m) }
 func (*PayReq) ProtoMessage()               {}
-func (*PayReq) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{83} }
+func (*PayReq) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{92} }
 
 func (m *PayReq) GetDestination() string {
 	if m != nil {
@@ -3085,7 +4388,7 @@ type FeeReportRequest struct {
 func (m *FeeReportRequest) Reset()                    { *m = FeeReportRequest{} }
 func (m *FeeReportRequest) String() string            { return proto.CompactTextString(m) }
 func (*FeeReportRequest) ProtoMessage()               {}
-func (*FeeReportRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{84} }
+func (*FeeReportRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{93} }
 
 type ChannelFeeReport struct {
 	// / The channel that this fee report belongs to.
@@ -3096,12 +4399,18 @@ type ChannelFeeReport struct {
 	FeePerMil int64 `protobuf:"varint,3,opt,name=fee_per_mil" json:"fee_per_mil,omitempty"`
 	// / The effective fee rate in milli-satoshis. Computed by dividing the fee_per_mil value by 1 million.
 	FeeRate float64 `protobuf:"fixed64,4,opt,name=fee_rate" json:"fee_rate,omitempty"`
+	// / The total fees in milli-satoshis earned by forwards out of this channel over the past day.
+	DayFeeSumMsat uint64 `protobuf:"varint,5,opt,name=day_fee_sum_msat" json:"day_fee_sum_msat,omitempty"`
+	// / The total fees in milli-satoshis earned by forwards out of this channel over the past week.
+	WeekFeeSumMsat uint64 `protobuf:"varint,6,opt,name=week_fee_sum_msat" json:"week_fee_sum_msat,omitempty"`
+	// / The total fees in milli-satoshis earned by forwards out of this channel over the past month.
+	MonthFeeSumMsat uint64 `protobuf:"varint,7,opt,name=month_fee_sum_msat" json:"month_fee_sum_msat,omitempty"`
 }
 
 func (m *ChannelFeeReport) Reset()                    { *m = ChannelFeeReport{} }
 func (m *ChannelFeeReport) String() string            { return proto.CompactTextString(m) }
 func (*ChannelFeeReport) ProtoMessage()               {}
-func (*ChannelFeeReport) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{85} }
+func (*ChannelFeeReport) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{94} }
 
 func (m *ChannelFeeReport) GetChanPoint() string {
 	if m != nil {
@@ -3131,15 +4440,42 @@ func (m *ChannelFeeReport) GetFeeRate() float64 {
 	return 0
 }
 
+func (m *ChannelFeeReport) GetDayFeeSumMsat() uint64 {
+	if m != nil {
+		return m.DayFeeSumMsat
+	}
+	return 0
+}
+
+func (m *ChannelFeeReport) GetWeekFeeSumMsat() uint64 {
+	if m != nil {
+		return m.WeekFeeSumMsat
+	}
+	return 0
+}
+
+func (m *ChannelFeeReport) GetMonthFeeSumMsat() uint64 {
+	if m != nil {
+		return m.MonthFeeSumMsat
+	}
+	return 0
+}
+
 type FeeReportResponse struct {
 	// / An array of channel fee reports which describes the current fee schedule for each channel.
 	ChannelFees []*ChannelFeeReport `protobuf:"bytes,1,rep,name=channel_fees" json:"channel_fees,omitempty"`
+	// / The total fees in milli-satoshis earned across all channels over the past day.
+	DayFeeSumMsat uint64 `protobuf:"varint,2,opt,name=day_fee_sum_msat" json:"day_fee_sum_msat,omitempty"`
+	// / The total fees in milli-satoshis earned across all channels over the past week.
+	WeekFeeSumMsat uint64 `protobuf:"varint,3,opt,name=week_fee_sum_msat" json:"week_fee_sum_msat,omitempty"`
+	// / The total fees in milli-satoshis earned across all channels over the past month.
+	MonthFeeSumMsat uint64 `protobuf:"varint,4,opt,name=month_fee_sum_msat" json:"month_fee_sum_msat,omitempty"`
 }
 
 func (m *FeeReportResponse) Reset()                    { *m = FeeReportResponse{} }
 func (m *FeeReportResponse) String() string            { return proto.CompactTextString(m) }
 func (*FeeReportResponse) ProtoMessage()               {}
-func (*FeeReportResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{86} }
+func (*FeeReportResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{95} }
 
 func (m *FeeReportResponse) GetChannelFees() []*ChannelFeeReport {
 	if m != nil {
@@ -3148,6 +4484,27 @@ func (m *FeeReportResponse) GetChannelFees() []*ChannelFeeReport {
 	return nil
 }
 
+func (m *FeeReportResponse) GetDayFeeSumMsat() uint64 {
+	if m != nil {
+		return m.DayFeeSumMsat
+	}
+	return 0
+}
+
+func (m *FeeReportResponse) GetWeekFeeSumMsat() uint64 {
+	if m != nil {
+		return m.WeekFeeSumMsat
+	}
+	return 0
+}
+
+func (m *FeeReportResponse) GetMonthFeeSumMsat() uint64 {
+	if m != nil {
+		return m.MonthFeeSumMsat
+	}
+	return 0
+}
+
 type FeeUpdateRequest struct {
 	// Types that are valid to be assigned to Scope:
 	//	*FeeUpdateRequest_Global
@@ -3162,7 +4519,7 @@ type FeeUpdateRequest struct {
 func (m *FeeUpdateRequest) Reset()                    { *m = FeeUpdateRequest{} }
 func (m *FeeUpdateRequest) String() string            { return proto.CompactTextString(m) }
 func (*FeeUpdateRequest) ProtoMessage()               {}
-func (*FeeUpdateRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{87} }
+func (*FeeUpdateRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{96} }
 
 type isFeeUpdateRequest_Scope interface {
 	isFeeUpdateRequest_Scope()
@@ -3292,17 +4649,1312 @@ type FeeUpdateResponse struct {
 func (m *FeeUpdateResponse) Reset()                    { *m = FeeUpdateResponse{} }
 func (m *FeeUpdateResponse) String() string            { return proto.CompactTextString(m) }
 func (*FeeUpdateResponse) ProtoMessage()               {}
-func (*FeeUpdateResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{88} }
+func (*FeeUpdateResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{97} }
 
-func init() {
-	proto.RegisterType((*Transaction)(nil), "lnrpc.Transaction")
-	proto.RegisterType((*GetTransactionsRequest)(nil), "lnrpc.GetTransactionsRequest")
-	proto.RegisterType((*TransactionDetails)(nil), "lnrpc.TransactionDetails")
-	proto.RegisterType((*SendRequest)(nil), "lnrpc.SendRequest")
-	proto.RegisterType((*SendResponse)(nil), "lnrpc.SendResponse")
-	proto.RegisterType((*ChannelPoint)(nil), "lnrpc.ChannelPoint")
-	proto.RegisterType((*LightningAddress)(nil), "lnrpc.LightningAddress")
-	proto.RegisterType((*SendManyRequest)(nil), "lnrpc.SendManyRequest")
+type BlacklistedNode struct {
+	// / The identity pubkey of the blacklisted node.
+	PubKey string `protobuf:"bytes,1,opt,name=pub_key" json:"pub_key,omitempty"`
+	// / If true, then the node will also never be used as the final destination of a payment.
+	AvoidAsDestination bool `protobuf:"varint,2,opt,name=avoid_as_destination" json:"avoid_as_destination,omitempty"`
+}
+
+func (m *BlacklistedNode) Reset()                    { *m = BlacklistedNode{} }
+func (m *BlacklistedNode) String() string            { return proto.CompactTextString(m) }
+func (*BlacklistedNode) ProtoMessage()               {}
+func (*BlacklistedNode) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{98} }
+
+func (m *BlacklistedNode) GetPubKey() string {
+	if m != nil {
+		return m.PubKey
+	}
+	return ""
+}
+
+func (m *BlacklistedNode) GetAvoidAsDestination() bool {
+	if m != nil {
+		return m.AvoidAsDestination
+	}
+	return false
+}
+
+type ListBlacklistRequest struct {
+}
+
+func (m *ListBlacklistRequest) Reset()                    { *m = ListBlacklistRequest{} }
+func (m *ListBlacklistRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListBlacklistRequest) ProtoMessage()               {}
+func (*ListBlacklistRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{99} }
+
+type ListBlacklistResponse struct {
+	// / The set of nodes currently within the node blacklist.
+	Nodes []*BlacklistedNode `protobuf:"bytes,1,rep,name=nodes" json:"nodes,omitempty"`
+}
+
+func (m *ListBlacklistResponse) Reset()                    { *m = ListBlacklistResponse{} }
+func (m *ListBlacklistResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListBlacklistResponse) ProtoMessage()               {}
+func (*ListBlacklistResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{100} }
+
+func (m *ListBlacklistResponse) GetNodes() []*BlacklistedNode {
+	if m != nil {
+		return m.Nodes
+	}
+	return nil
+}
+
+type UpdateBlacklistRequest struct {
+	// / The set of nodes to add to the blacklist.
+	Add []*BlacklistedNode `protobuf:"bytes,1,rep,name=add" json:"add,omitempty"`
+	// / The identity pubkeys of the nodes to remove from the blacklist.
+	Remove []string `protobuf:"bytes,2,rep,name=remove" json:"remove,omitempty"`
+}
+
+func (m *UpdateBlacklistRequest) Reset()                    { *m = UpdateBlacklistRequest{} }
+func (m *UpdateBlacklistRequest) String() string            { return proto.CompactTextString(m) }
+func (*UpdateBlacklistRequest) ProtoMessage()               {}
+func (*UpdateBlacklistRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{101} }
+
+func (m *UpdateBlacklistRequest) GetAdd() []*BlacklistedNode {
+	if m != nil {
+		return m.Add
+	}
+	return nil
+}
+
+func (m *UpdateBlacklistRequest) GetRemove() []string {
+	if m != nil {
+		return m.Remove
+	}
+	return nil
+}
+
+type UpdateBlacklistResponse struct {
+}
+
+func (m *UpdateBlacklistResponse) Reset()                    { *m = UpdateBlacklistResponse{} }
+func (m *UpdateBlacklistResponse) String() string            { return proto.CompactTextString(m) }
+func (*UpdateBlacklistResponse) ProtoMessage()               {}
+func (*UpdateBlacklistResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{102} }
+
+type CommitmentTxnsRequest struct {
+	// / The outpoint (txid:index) of the funding transaction of the channel.
+	ChannelPoint *ChannelPoint `protobuf:"bytes,1,opt,name=channel_point" json:"channel_point,omitempty"`
+}
+
+func (m *CommitmentTxnsRequest) Reset()                    { *m = CommitmentTxnsRequest{} }
+func (m *CommitmentTxnsRequest) String() string            { return proto.CompactTextString(m) }
+func (*CommitmentTxnsRequest) ProtoMessage()               {}
+func (*CommitmentTxnsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{103} }
+
+func (m *CommitmentTxnsRequest) GetChannelPoint() *ChannelPoint {
+	if m != nil {
+		return m.ChannelPoint
+	}
+	return nil
+}
+
+type CommitmentTxnsResponse struct {
+	// / The hex-encoded fully signed local commitment transaction.
+	CommitTx string `protobuf:"bytes,1,opt,name=commit_tx" json:"commit_tx,omitempty"`
+	// / The hex-encoded fully signed HTLC timeout transactions for each of our outgoing HTLCs.
+	HtlcTxns []string `protobuf:"bytes,2,rep,name=htlc_txns" json:"htlc_txns,omitempty"`
+}
+
+func (m *CommitmentTxnsResponse) Reset()                    { *m = CommitmentTxnsResponse{} }
+func (m *CommitmentTxnsResponse) String() string            { return proto.CompactTextString(m) }
+func (*CommitmentTxnsResponse) ProtoMessage()               {}
+func (*CommitmentTxnsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{104} }
+
+func (m *CommitmentTxnsResponse) GetCommitTx() string {
+	if m != nil {
+		return m.CommitTx
+	}
+	return ""
+}
+
+func (m *CommitmentTxnsResponse) GetHtlcTxns() []string {
+	if m != nil {
+		return m.HtlcTxns
+	}
+	return nil
+}
+
+type ExportChannelDBRequest struct {
+}
+
+func (m *ExportChannelDBRequest) Reset()                    { *m = ExportChannelDBRequest{} }
+func (m *ExportChannelDBRequest) String() string            { return proto.CompactTextString(m) }
+func (*ExportChannelDBRequest) ProtoMessage()               {}
+func (*ExportChannelDBRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{105} }
+
+type ChannelDBChunk struct {
+	// / The next chunk of the channel database snapshot.
+	Chunk []byte `protobuf:"bytes,1,opt,name=chunk,proto3" json:"chunk,omitempty"`
+}
+
+func (m *ChannelDBChunk) Reset()                    { *m = ChannelDBChunk{} }
+func (m *ChannelDBChunk) String() string            { return proto.CompactTextString(m) }
+func (*ChannelDBChunk) ProtoMessage()               {}
+func (*ChannelDBChunk) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{106} }
+
+func (m *ChannelDBChunk) GetChunk() []byte {
+	if m != nil {
+		return m.Chunk
+	}
+	return nil
+}
+
+type SendCustomMessageRequest struct {
+	// / The identity pubkey of the peer to send the message to.
+	Peer []byte `protobuf:"bytes,1,opt,name=peer,proto3" json:"peer,omitempty"`
+	// / The message type, which must be at least 32768.
+	Type uint32 `protobuf:"varint,2,opt,name=type" json:"type,omitempty"`
+	// / The opaque payload of the message.
+	Data []byte `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (m *SendCustomMessageRequest) Reset()                    { *m = SendCustomMessageRequest{} }
+func (m *SendCustomMessageRequest) String() string            { return proto.CompactTextString(m) }
+func (*SendCustomMessageRequest) ProtoMessage()               {}
+func (*SendCustomMessageRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{107} }
+
+func (m *SendCustomMessageRequest) GetPeer() []byte {
+	if m != nil {
+		return m.Peer
+	}
+	return nil
+}
+
+func (m *SendCustomMessageRequest) GetType() uint32 {
+	if m != nil {
+		return m.Type
+	}
+	return 0
+}
+
+func (m *SendCustomMessageRequest) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+type SendCustomMessageResponse struct {
+}
+
+func (m *SendCustomMessageResponse) Reset()                    { *m = SendCustomMessageResponse{} }
+func (m *SendCustomMessageResponse) String() string            { return proto.CompactTextString(m) }
+func (*SendCustomMessageResponse) ProtoMessage()               {}
+func (*SendCustomMessageResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{108} }
+
+type SubscribeCustomMessagesRequest struct {
+}
+
+func (m *SubscribeCustomMessagesRequest) Reset()         { *m = SubscribeCustomMessagesRequest{} }
+func (m *SubscribeCustomMessagesRequest) String() string { return proto.CompactTextString(m) }
+func (*SubscribeCustomMessagesRequest) ProtoMessage()    {}
+func (*SubscribeCustomMessagesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{109}
+}
+
+type CustomMessage struct {
+	// / The identity pubkey of the peer the message was received from.
+	Peer []byte `protobuf:"bytes,1,opt,name=peer,proto3" json:"peer,omitempty"`
+	// / The message type.
+	Type uint32 `protobuf:"varint,2,opt,name=type" json:"type,omitempty"`
+	// / The opaque payload of the message.
+	Data []byte `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (m *CustomMessage) Reset()                    { *m = CustomMessage{} }
+func (m *CustomMessage) String() string            { return proto.CompactTextString(m) }
+func (*CustomMessage) ProtoMessage()               {}
+func (*CustomMessage) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{110} }
+
+func (m *CustomMessage) GetPeer() []byte {
+	if m != nil {
+		return m.Peer
+	}
+	return nil
+}
+
+func (m *CustomMessage) GetType() uint32 {
+	if m != nil {
+		return m.Type
+	}
+	return 0
+}
+
+func (m *CustomMessage) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+type NodeHistory struct {
+	// / The hex-encoded identity pubkey of the node.
+	PubKey string `protobuf:"bytes,1,opt,name=pub_key" json:"pub_key,omitempty"`
+	// / The unix timestamp of the last payment attempt that failed due to the node, or zero if none.
+	FailTime int64 `protobuf:"varint,2,opt,name=fail_time" json:"fail_time,omitempty"`
+	// / The unix timestamp of the last payment attempt carried by the node, or zero if none.
+	SuccessTime int64 `protobuf:"varint,3,opt,name=success_time" json:"success_time,omitempty"`
+}
+
+func (m *NodeHistory) Reset()                    { *m = NodeHistory{} }
+func (m *NodeHistory) String() string            { return proto.CompactTextString(m) }
+func (*NodeHistory) ProtoMessage()               {}
+func (*NodeHistory) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{111} }
+
+func (m *NodeHistory) GetPubKey() string {
+	if m != nil {
+		return m.PubKey
+	}
+	return ""
+}
+
+func (m *NodeHistory) GetFailTime() int64 {
+	if m != nil {
+		return m.FailTime
+	}
+	return 0
+}
+
+func (m *NodeHistory) GetSuccessTime() int64 {
+	if m != nil {
+		return m.SuccessTime
+	}
+	return 0
+}
+
+type PairHistory struct {
+	// / The hex-encoded identity pubkey of the node at the start of the pair.
+	NodeFrom string `protobuf:"bytes,1,opt,name=node_from" json:"node_from,omitempty"`
+	// / The hex-encoded identity pubkey of the node at the end of the pair.
+	NodeTo string `protobuf:"bytes,2,opt,name=node_to" json:"node_to,omitempty"`
+	// / The unix timestamp of the last payment attempt that failed due to the pair, or zero if none.
+	FailTime int64 `protobuf:"varint,3,opt,name=fail_time" json:"fail_time,omitempty"`
+	// / The amount in milli-atoms of the last payment attempt that failed due to the pair.
+	FailAmtMsat int64 `protobuf:"varint,4,opt,name=fail_amt_msat" json:"fail_amt_msat,omitempty"`
+	// / The unix timestamp of the last payment attempt carried by the pair, or zero if none.
+	SuccessTime int64 `protobuf:"varint,5,opt,name=success_time" json:"success_time,omitempty"`
+	// / The amount in milli-atoms of the last payment attempt carried by the pair.
+	SuccessAmtMsat int64 `protobuf:"varint,6,opt,name=success_amt_msat" json:"success_amt_msat,omitempty"`
+}
+
+func (m *PairHistory) Reset()                    { *m = PairHistory{} }
+func (m *PairHistory) String() string            { return proto.CompactTextString(m) }
+func (*PairHistory) ProtoMessage()               {}
+func (*PairHistory) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{112} }
+
+func (m *PairHistory) GetNodeFrom() string {
+	if m != nil {
+		return m.NodeFrom
+	}
+	return ""
+}
+
+func (m *PairHistory) GetNodeTo() string {
+	if m != nil {
+		return m.NodeTo
+	}
+	return ""
+}
+
+func (m *PairHistory) GetFailTime() int64 {
+	if m != nil {
+		return m.FailTime
+	}
+	return 0
+}
+
+func (m *PairHistory) GetFailAmtMsat() int64 {
+	if m != nil {
+		return m.FailAmtMsat
+	}
+	return 0
+}
+
+func (m *PairHistory) GetSuccessTime() int64 {
+	if m != nil {
+		return m.SuccessTime
+	}
+	return 0
+}
+
+func (m *PairHistory) GetSuccessAmtMsat() int64 {
+	if m != nil {
+		return m.SuccessAmtMsat
+	}
+	return 0
+}
+
+type QueryMissionControlRequest struct {
+}
+
+func (m *QueryMissionControlRequest) Reset()                    { *m = QueryMissionControlRequest{} }
+func (m *QueryMissionControlRequest) String() string            { return proto.CompactTextString(m) }
+func (*QueryMissionControlRequest) ProtoMessage()               {}
+func (*QueryMissionControlRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{113} }
+
+type QueryMissionControlResponse struct {
+	// / The history of each node involved in past payment attempts.
+	Nodes []*NodeHistory `protobuf:"bytes,1,rep,name=nodes" json:"nodes,omitempty"`
+	// / The history of each directed pair of nodes involved in past payment attempts.
+	Pairs []*PairHistory `protobuf:"bytes,2,rep,name=pairs" json:"pairs,omitempty"`
+}
+
+func (m *QueryMissionControlResponse) Reset()                    { *m = QueryMissionControlResponse{} }
+func (m *QueryMissionControlResponse) String() string            { return proto.CompactTextString(m) }
+func (*QueryMissionControlResponse) ProtoMessage()               {}
+func (*QueryMissionControlResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{114} }
+
+func (m *QueryMissionControlResponse) GetNodes() []*NodeHistory {
+	if m != nil {
+		return m.Nodes
+	}
+	return nil
+}
+
+func (m *QueryMissionControlResponse) GetPairs() []*PairHistory {
+	if m != nil {
+		return m.Pairs
+	}
+	return nil
+}
+
+type ResetMissionControlRequest struct {
+}
+
+func (m *ResetMissionControlRequest) Reset()                    { *m = ResetMissionControlRequest{} }
+func (m *ResetMissionControlRequest) String() string            { return proto.CompactTextString(m) }
+func (*ResetMissionControlRequest) ProtoMessage()               {}
+func (*ResetMissionControlRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{115} }
+
+type ResetMissionControlResponse struct {
+}
+
+func (m *ResetMissionControlResponse) Reset()                    { *m = ResetMissionControlResponse{} }
+func (m *ResetMissionControlResponse) String() string            { return proto.CompactTextString(m) }
+func (*ResetMissionControlResponse) ProtoMessage()               {}
+func (*ResetMissionControlResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{116} }
+
+type XImportMissionControlRequest struct {
+	// / The node histories to import.
+	Nodes []*NodeHistory `protobuf:"bytes,1,rep,name=nodes" json:"nodes,omitempty"`
+	// / The pair histories to import.
+	Pairs []*PairHistory `protobuf:"bytes,2,rep,name=pairs" json:"pairs,omitempty"`
+}
+
+func (m *XImportMissionControlRequest) Reset()                    { *m = XImportMissionControlRequest{} }
+func (m *XImportMissionControlRequest) String() string            { return proto.CompactTextString(m) }
+func (*XImportMissionControlRequest) ProtoMessage()               {}
+func (*XImportMissionControlRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{117} }
+
+func (m *XImportMissionControlRequest) GetNodes() []*NodeHistory {
+	if m != nil {
+		return m.Nodes
+	}
+	return nil
+}
+
+func (m *XImportMissionControlRequest) GetPairs() []*PairHistory {
+	if m != nil {
+		return m.Pairs
+	}
+	return nil
+}
+
+type XImportMissionControlResponse struct {
+}
+
+func (m *XImportMissionControlResponse) Reset()         { *m = XImportMissionControlResponse{} }
+func (m *XImportMissionControlResponse) String() string { return proto.CompactTextString(m) }
+func (*XImportMissionControlResponse) ProtoMessage()    {}
+func (*XImportMissionControlResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{118}
+}
+
+type BuildRouteRequest struct {
+	// / The amount to send to the final hop, expressed in satoshis
+	Amt int64 `protobuf:"varint,1,opt,name=amt" json:"amt,omitempty"`
+	// / The 33-byte hex-encoded public keys of the hops to traverse, in order, with the last being the payment destination
+	HopPubkeys []string `protobuf:"bytes,2,rep,name=hop_pubkeys" json:"hop_pubkeys,omitempty"`
+}
+
+func (m *BuildRouteRequest) Reset()                    { *m = BuildRouteRequest{} }
+func (m *BuildRouteRequest) String() string            { return proto.CompactTextString(m) }
+func (*BuildRouteRequest) ProtoMessage()               {}
+func (*BuildRouteRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{119} }
+
+func (m *BuildRouteRequest) GetAmt() int64 {
+	if m != nil {
+		return m.Amt
+	}
+	return 0
+}
+
+func (m *BuildRouteRequest) GetHopPubkeys() []string {
+	if m != nil {
+		return m.HopPubkeys
+	}
+	return nil
+}
+
+type BuildRouteResponse struct {
+	// / The fully specified route over the requested hops
+	Route *Route `protobuf:"bytes,1,opt,name=route" json:"route,omitempty"`
+}
+
+func (m *BuildRouteResponse) Reset()                    { *m = BuildRouteResponse{} }
+func (m *BuildRouteResponse) String() string            { return proto.CompactTextString(m) }
+func (*BuildRouteResponse) ProtoMessage()               {}
+func (*BuildRouteResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{120} }
+
+func (m *BuildRouteResponse) GetRoute() *Route {
+	if m != nil {
+		return m.Route
+	}
+	return nil
+}
+
+type SendToRouteRequest struct {
+	// / The hash to use within the payment's HTLC, which may be omitted for circular payments
+	PaymentHash []byte `protobuf:"bytes,1,opt,name=payment_hash,proto3" json:"payment_hash,omitempty"`
+	// / The hex-encoded hash to use within the payment's HTLC, which may be omitted for circular payments
+	PaymentHashString string `protobuf:"bytes,2,opt,name=payment_hash_string" json:"payment_hash_string,omitempty"`
+	// / The route to send the payment over, as returned by BuildRoute
+	Route *Route `protobuf:"bytes,3,opt,name=route" json:"route,omitempty"`
+}
+
+func (m *SendToRouteRequest) Reset()                    { *m = SendToRouteRequest{} }
+func (m *SendToRouteRequest) String() string            { return proto.CompactTextString(m) }
+func (*SendToRouteRequest) ProtoMessage()               {}
+func (*SendToRouteRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{121} }
+
+func (m *SendToRouteRequest) GetPaymentHash() []byte {
+	if m != nil {
+		return m.PaymentHash
+	}
+	return nil
+}
+
+func (m *SendToRouteRequest) GetPaymentHashString() string {
+	if m != nil {
+		return m.PaymentHashString
+	}
+	return ""
+}
+
+func (m *SendToRouteRequest) GetRoute() *Route {
+	if m != nil {
+		return m.Route
+	}
+	return nil
+}
+
+type RouteFailure struct {
+	// / The onion failure code of the failure
+	Code uint32 `protobuf:"varint,1,opt,name=code" json:"code,omitempty"`
+	// / The index of the node that generated the failure, where 0 refers to our own node and i to the node at the end of the i-th hop of the route
+	FailureSourceIndex uint32 `protobuf:"varint,2,opt,name=failure_source_index" json:"failure_source_index,omitempty"`
+	// / The failure message sent by the failing node, encoded as specified in BOLT 4. It's empty for failures that occurred within our own node.
+	Message []byte `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (m *RouteFailure) Reset()                    { *m = RouteFailure{} }
+func (m *RouteFailure) String() string            { return proto.CompactTextString(m) }
+func (*RouteFailure) ProtoMessage()               {}
+func (*RouteFailure) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{122} }
+
+func (m *RouteFailure) GetCode() uint32 {
+	if m != nil {
+		return m.Code
+	}
+	return 0
+}
+
+func (m *RouteFailure) GetFailureSourceIndex() uint32 {
+	if m != nil {
+		return m.FailureSourceIndex
+	}
+	return 0
+}
+
+func (m *RouteFailure) GetMessage() []byte {
+	if m != nil {
+		return m.Message
+	}
+	return nil
+}
+
+type SendToRouteResponse struct {
+	// / The preimage of the payment, only set for successful payments
+	PaymentPreimage []byte `protobuf:"bytes,1,opt,name=payment_preimage,proto3" json:"payment_preimage,omitempty"`
+	// / The failure of the payment, only set for failed payments
+	Failure *RouteFailure `protobuf:"bytes,2,opt,name=failure" json:"failure,omitempty"`
+}
+
+func (m *SendToRouteResponse) Reset()                    { *m = SendToRouteResponse{} }
+func (m *SendToRouteResponse) String() string            { return proto.CompactTextString(m) }
+func (*SendToRouteResponse) ProtoMessage()               {}
+func (*SendToRouteResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{123} }
+
+func (m *SendToRouteResponse) GetPaymentPreimage() []byte {
+	if m != nil {
+		return m.PaymentPreimage
+	}
+	return nil
+}
+
+func (m *SendToRouteResponse) GetFailure() *RouteFailure {
+	if m != nil {
+		return m.Failure
+	}
+	return nil
+}
+
+type QueryProbabilityRequest struct {
+	// / The hex-encoded identity pubkey of the node forwarding the payment.
+	FromNode string `protobuf:"bytes,1,opt,name=from_node" json:"from_node,omitempty"`
+	// / The hex-encoded identity pubkey of the node receiving the payment.
+	ToNode string `protobuf:"bytes,2,opt,name=to_node" json:"to_node,omitempty"`
+	// / The amount in milli-atoms to be forwarded.
+	AmtMsat int64 `protobuf:"varint,3,opt,name=amt_msat" json:"amt_msat,omitempty"`
+}
+
+func (m *QueryProbabilityRequest) Reset()                    { *m = QueryProbabilityRequest{} }
+func (m *QueryProbabilityRequest) String() string            { return proto.CompactTextString(m) }
+func (*QueryProbabilityRequest) ProtoMessage()               {}
+func (*QueryProbabilityRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{124} }
+
+func (m *QueryProbabilityRequest) GetFromNode() string {
+	if m != nil {
+		return m.FromNode
+	}
+	return ""
+}
+
+func (m *QueryProbabilityRequest) GetToNode() string {
+	if m != nil {
+		return m.ToNode
+	}
+	return ""
+}
+
+func (m *QueryProbabilityRequest) GetAmtMsat() int64 {
+	if m != nil {
+		return m.AmtMsat
+	}
+	return 0
+}
+
+type QueryProbabilityResponse struct {
+	// / The estimated probability of the payment being successfully forwarded.
+	Probability float64 `protobuf:"fixed64,1,opt,name=probability" json:"probability,omitempty"`
+	// / The history of the pair of nodes, if any.
+	History *PairHistory `protobuf:"bytes,2,opt,name=history" json:"history,omitempty"`
+	// / The history of the receiving node, if any.
+	NodeHistory *NodeHistory `protobuf:"bytes,3,opt,name=node_history" json:"node_history,omitempty"`
+}
+
+func (m *QueryProbabilityResponse) Reset()                    { *m = QueryProbabilityResponse{} }
+func (m *QueryProbabilityResponse) String() string            { return proto.CompactTextString(m) }
+func (*QueryProbabilityResponse) ProtoMessage()               {}
+func (*QueryProbabilityResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{125} }
+
+func (m *QueryProbabilityResponse) GetProbability() float64 {
+	if m != nil {
+		return m.Probability
+	}
+	return 0
+}
+
+func (m *QueryProbabilityResponse) GetHistory() *PairHistory {
+	if m != nil {
+		return m.History
+	}
+	return nil
+}
+
+func (m *QueryProbabilityResponse) GetNodeHistory() *NodeHistory {
+	if m != nil {
+		return m.NodeHistory
+	}
+	return nil
+}
+
+type EdgeLocator struct {
+	// / The short channel id of the edge
+	ChannelId uint64 `protobuf:"varint,1,opt,name=channel_id" json:"channel_id,omitempty"`
+	// *
+	// The direction of the edge. If false, the edge leads from the node with the
+	// lexicographically smaller pubkey to the other. If true, it leads the
+	// opposite way.
+	DirectionReverse bool `protobuf:"varint,2,opt,name=direction_reverse" json:"direction_reverse,omitempty"`
+}
+
+func (m *EdgeLocator) Reset()                    { *m = EdgeLocator{} }
+func (m *EdgeLocator) String() string            { return proto.CompactTextString(m) }
+func (*EdgeLocator) ProtoMessage()               {}
+func (*EdgeLocator) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{126} }
+
+func (m *EdgeLocator) GetChannelId() uint64 {
+	if m != nil {
+		return m.ChannelId
+	}
+	return 0
+}
+
+func (m *EdgeLocator) GetDirectionReverse() bool {
+	if m != nil {
+		return m.DirectionReverse
+	}
+	return false
+}
+
+type PolicyUpdateRequest struct {
+	// / If set, then this policy update applies to all currently active channels.
+	Global bool `protobuf:"varint,1,opt,name=global" json:"global,omitempty"`
+	// / If set, this policy update will target a specific channel.
+	ChanPoint *ChannelPoint `protobuf:"bytes,2,opt,name=chan_point" json:"chan_point,omitempty"`
+	// / The base fee charged regardless of the number of milli-atoms sent.
+	BaseFeeMsat int64 `protobuf:"varint,3,opt,name=base_fee_msat" json:"base_fee_msat,omitempty"`
+	// / The effective fee rate in milli-atoms. The precision of this value goes up to 6 decimal places, so 1e-6.
+	FeeRate float64 `protobuf:"fixed64,4,opt,name=fee_rate" json:"fee_rate,omitempty"`
+	// / The required time lock delta for HTLCs forwarded over the channel, or zero to leave it unchanged.
+	TimeLockDelta uint32 `protobuf:"varint,5,opt,name=time_lock_delta" json:"time_lock_delta,omitempty"`
+	// / The smallest HTLC in milli-atoms that will be forwarded over the channel, or zero to leave it unchanged.
+	MinHtlcMsat int64 `protobuf:"varint,6,opt,name=min_htlc_msat" json:"min_htlc_msat,omitempty"`
+	// / The largest HTLC in milli-atoms that will be forwarded over the channel, or zero to leave it unchanged.
+	MaxHtlcMsat int64 `protobuf:"varint,7,opt,name=max_htlc_msat" json:"max_htlc_msat,omitempty"`
+}
+
+func (m *PolicyUpdateRequest) Reset()                    { *m = PolicyUpdateRequest{} }
+func (m *PolicyUpdateRequest) String() string            { return proto.CompactTextString(m) }
+func (*PolicyUpdateRequest) ProtoMessage()               {}
+func (*PolicyUpdateRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{127} }
+
+func (m *PolicyUpdateRequest) GetGlobal() bool {
+	if m != nil {
+		return m.Global
+	}
+	return false
+}
+
+func (m *PolicyUpdateRequest) GetChanPoint() *ChannelPoint {
+	if m != nil {
+		return m.ChanPoint
+	}
+	return nil
+}
+
+func (m *PolicyUpdateRequest) GetBaseFeeMsat() int64 {
+	if m != nil {
+		return m.BaseFeeMsat
+	}
+	return 0
+}
+
+func (m *PolicyUpdateRequest) GetFeeRate() float64 {
+	if m != nil {
+		return m.FeeRate
+	}
+	return 0
+}
+
+func (m *PolicyUpdateRequest) GetTimeLockDelta() uint32 {
+	if m != nil {
+		return m.TimeLockDelta
+	}
+	return 0
+}
+
+func (m *PolicyUpdateRequest) GetMinHtlcMsat() int64 {
+	if m != nil {
+		return m.MinHtlcMsat
+	}
+	return 0
+}
+
+func (m *PolicyUpdateRequest) GetMaxHtlcMsat() int64 {
+	if m != nil {
+		return m.MaxHtlcMsat
+	}
+	return 0
+}
+
+type PolicyUpdateResponse struct {
+}
+
+func (m *PolicyUpdateResponse) Reset()                    { *m = PolicyUpdateResponse{} }
+func (m *PolicyUpdateResponse) String() string            { return proto.CompactTextString(m) }
+func (*PolicyUpdateResponse) ProtoMessage()               {}
+func (*PolicyUpdateResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{128} }
+
+type ForwardHtlcInterceptRequest struct {
+	// / The identifier of the intercepted HTLC, to be included within the response resolving it.
+	InterceptId uint64 `protobuf:"varint,1,opt,name=intercept_id" json:"intercept_id,omitempty"`
+	// / The payment hash of the HTLC.
+	PaymentHash []byte `protobuf:"bytes,2,opt,name=payment_hash,proto3" json:"payment_hash,omitempty"`
+	// / The short channel id of the channel the HTLC was received over.
+	IncomingChanId uint64 `protobuf:"varint,3,opt,name=incoming_chan_id" json:"incoming_chan_id,omitempty"`
+	// / The short channel id of the channel the HTLC is to be forwarded over.
+	OutgoingChanId uint64 `protobuf:"varint,4,opt,name=outgoing_chan_id" json:"outgoing_chan_id,omitempty"`
+	// / The value of the incoming HTLC in milli-atoms.
+	IncomingAmtMsat int64 `protobuf:"varint,5,opt,name=incoming_amt_msat" json:"incoming_amt_msat,omitempty"`
+	// / The value of the HTLC to be forwarded in milli-atoms.
+	OutgoingAmtMsat int64 `protobuf:"varint,6,opt,name=outgoing_amt_msat" json:"outgoing_amt_msat,omitempty"`
+	// / The absolute expiry height of the incoming HTLC.
+	IncomingExpiry uint32 `protobuf:"varint,7,opt,name=incoming_expiry" json:"incoming_expiry,omitempty"`
+	// / The absolute expiry height of the HTLC to be forwarded.
+	OutgoingExpiry uint32 `protobuf:"varint,8,opt,name=outgoing_expiry" json:"outgoing_expiry,omitempty"`
+}
+
+func (m *ForwardHtlcInterceptRequest) Reset()                    { *m = ForwardHtlcInterceptRequest{} }
+func (m *ForwardHtlcInterceptRequest) String() string            { return proto.CompactTextString(m) }
+func (*ForwardHtlcInterceptRequest) ProtoMessage()               {}
+func (*ForwardHtlcInterceptRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{129} }
+
+func (m *ForwardHtlcInterceptRequest) GetInterceptId() uint64 {
+	if m != nil {
+		return m.InterceptId
+	}
+	return 0
+}
+
+func (m *ForwardHtlcInterceptRequest) GetPaymentHash() []byte {
+	if m != nil {
+		return m.PaymentHash
+	}
+	return nil
+}
+
+func (m *ForwardHtlcInterceptRequest) GetIncomingChanId() uint64 {
+	if m != nil {
+		return m.IncomingChanId
+	}
+	return 0
+}
+
+func (m *ForwardHtlcInterceptRequest) GetOutgoingChanId() uint64 {
+	if m != nil {
+		return m.OutgoingChanId
+	}
+	return 0
+}
+
+func (m *ForwardHtlcInterceptRequest) GetIncomingAmtMsat() int64 {
+	if m != nil {
+		return m.IncomingAmtMsat
+	}
+	return 0
+}
+
+func (m *ForwardHtlcInterceptRequest) GetOutgoingAmtMsat() int64 {
+	if m != nil {
+		return m.OutgoingAmtMsat
+	}
+	return 0
+}
+
+func (m *ForwardHtlcInterceptRequest) GetIncomingExpiry() uint32 {
+	if m != nil {
+		return m.IncomingExpiry
+	}
+	return 0
+}
+
+func (m *ForwardHtlcInterceptRequest) GetOutgoingExpiry() uint32 {
+	if m != nil {
+		return m.OutgoingExpiry
+	}
+	return 0
+}
+
+type ForwardHtlcInterceptResponse struct {
+	// / The identifier of the intercepted HTLC to resolve.
+	InterceptId uint64 `protobuf:"varint,1,opt,name=intercept_id" json:"intercept_id,omitempty"`
+	// / The action to resolve the HTLC with.
+	Action ResolveHoldForwardAction `protobuf:"varint,2,opt,name=action,enum=lnrpc.ResolveHoldForwardAction" json:"action,omitempty"`
+	// / The preimage to settle the HTLC with, if the action is SETTLE.
+	Preimage []byte `protobuf:"bytes,3,opt,name=preimage,proto3" json:"preimage,omitempty"`
+}
+
+func (m *ForwardHtlcInterceptResponse) Reset()                    { *m = ForwardHtlcInterceptResponse{} }
+func (m *ForwardHtlcInterceptResponse) String() string            { return proto.CompactTextString(m) }
+func (*ForwardHtlcInterceptResponse) ProtoMessage()               {}
+func (*ForwardHtlcInterceptResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{130} }
+
+func (m *ForwardHtlcInterceptResponse) GetInterceptId() uint64 {
+	if m != nil {
+		return m.InterceptId
+	}
+	return 0
+}
+
+func (m *ForwardHtlcInterceptResponse) GetAction() ResolveHoldForwardAction {
+	if m != nil {
+		return m.Action
+	}
+	return ResolveHoldForwardAction_RESUME
+}
+
+func (m *ForwardHtlcInterceptResponse) GetPreimage() []byte {
+	if m != nil {
+		return m.Preimage
+	}
+	return nil
+}
+
+type SettleInvoiceMsg struct {
+	// / The preimage of the hold invoice to settle.
+	Preimage []byte `protobuf:"bytes,1,opt,name=preimage,proto3" json:"preimage,omitempty"`
+}
+
+func (m *SettleInvoiceMsg) Reset()                    { *m = SettleInvoiceMsg{} }
+func (m *SettleInvoiceMsg) String() string            { return proto.CompactTextString(m) }
+func (*SettleInvoiceMsg) ProtoMessage()               {}
+func (*SettleInvoiceMsg) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{131} }
+
+func (m *SettleInvoiceMsg) GetPreimage() []byte {
+	if m != nil {
+		return m.Preimage
+	}
+	return nil
+}
+
+type SettleInvoiceResp struct {
+}
+
+func (m *SettleInvoiceResp) Reset()                    { *m = SettleInvoiceResp{} }
+func (m *SettleInvoiceResp) String() string            { return proto.CompactTextString(m) }
+func (*SettleInvoiceResp) ProtoMessage()               {}
+func (*SettleInvoiceResp) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{132} }
+
+type CancelInvoiceMsg struct {
+	// / The payment hash of the invoice to cancel.
+	PaymentHash []byte `protobuf:"bytes,1,opt,name=payment_hash,proto3" json:"payment_hash,omitempty"`
+}
+
+func (m *CancelInvoiceMsg) Reset()                    { *m = CancelInvoiceMsg{} }
+func (m *CancelInvoiceMsg) String() string            { return proto.CompactTextString(m) }
+func (*CancelInvoiceMsg) ProtoMessage()               {}
+func (*CancelInvoiceMsg) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{133} }
+
+func (m *CancelInvoiceMsg) GetPaymentHash() []byte {
+	if m != nil {
+		return m.PaymentHash
+	}
+	return nil
+}
+
+type CancelInvoiceResp struct {
+}
+
+func (m *CancelInvoiceResp) Reset()                    { *m = CancelInvoiceResp{} }
+func (m *CancelInvoiceResp) String() string            { return proto.CompactTextString(m) }
+func (*CancelInvoiceResp) ProtoMessage()               {}
+func (*CancelInvoiceResp) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{134} }
+
+type SubscribeHtlcEventsRequest struct {
+}
+
+func (m *SubscribeHtlcEventsRequest) Reset()                    { *m = SubscribeHtlcEventsRequest{} }
+func (m *SubscribeHtlcEventsRequest) String() string            { return proto.CompactTextString(m) }
+func (*SubscribeHtlcEventsRequest) ProtoMessage()               {}
+func (*SubscribeHtlcEventsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{135} }
+
+type HtlcEvent struct {
+	// / The type of the event.
+	EventType HtlcEvent_EventType `protobuf:"varint,1,opt,name=event_type,enum=lnrpc.HtlcEvent_EventType" json:"event_type,omitempty"`
+	// / The payment hash of the HTLC.
+	PaymentHash []byte `protobuf:"bytes,2,opt,name=payment_hash,proto3" json:"payment_hash,omitempty"`
+	// / The short channel id of the channel the HTLC was received over, or of the channel which failed it for link failures of incoming HTLCs.
+	IncomingChanId uint64 `protobuf:"varint,3,opt,name=incoming_chan_id" json:"incoming_chan_id,omitempty"`
+	// / The short channel id of the channel the HTLC was forwarded over, or of the channel which failed it for link failures of outgoing HTLCs.
+	OutgoingChanId uint64 `protobuf:"varint,4,opt,name=outgoing_chan_id" json:"outgoing_chan_id,omitempty"`
+	// / The value of the incoming HTLC in milli-atoms, set for forwards.
+	IncomingAmtMsat int64 `protobuf:"varint,5,opt,name=incoming_amt_msat" json:"incoming_amt_msat,omitempty"`
+	// / The value of the forwarded HTLC in milli-atoms, set for forwards.
+	OutgoingAmtMsat int64 `protobuf:"varint,6,opt,name=outgoing_amt_msat" json:"outgoing_amt_msat,omitempty"`
+	// / The unix timestamp in nanoseconds at which the event occurred.
+	TimestampNs uint64 `protobuf:"varint,7,opt,name=timestamp_ns" json:"timestamp_ns,omitempty"`
+	// / The wire failure code sent back to the source of the HTLC, set for link failures.
+	FailureCode uint32 `protobuf:"varint,8,opt,name=failure_code" json:"failure_code,omitempty"`
+	// / A description of why the HTLC was failed, if known.
+	FailureDetail string `protobuf:"bytes,9,opt,name=failure_detail" json:"failure_detail,omitempty"`
+}
+
+func (m *HtlcEvent) Reset()                    { *m = HtlcEvent{} }
+func (m *HtlcEvent) String() string            { return proto.CompactTextString(m) }
+func (*HtlcEvent) ProtoMessage()               {}
+func (*HtlcEvent) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{136} }
+
+func (m *HtlcEvent) GetEventType() HtlcEvent_EventType {
+	if m != nil {
+		return m.EventType
+	}
+	return HtlcEvent_FORWARD
+}
+
+func (m *HtlcEvent) GetPaymentHash() []byte {
+	if m != nil {
+		return m.PaymentHash
+	}
+	return nil
+}
+
+func (m *HtlcEvent) GetIncomingChanId() uint64 {
+	if m != nil {
+		return m.IncomingChanId
+	}
+	return 0
+}
+
+func (m *HtlcEvent) GetOutgoingChanId() uint64 {
+	if m != nil {
+		return m.OutgoingChanId
+	}
+	return 0
+}
+
+func (m *HtlcEvent) GetIncomingAmtMsat() int64 {
+	if m != nil {
+		return m.IncomingAmtMsat
+	}
+	return 0
+}
+
+func (m *HtlcEvent) GetOutgoingAmtMsat() int64 {
+	if m != nil {
+		return m.OutgoingAmtMsat
+	}
+	return 0
+}
+
+func (m *HtlcEvent) GetTimestampNs() uint64 {
+	if m != nil {
+		return m.TimestampNs
+	}
+	return 0
+}
+
+func (m *HtlcEvent) GetFailureCode() uint32 {
+	if m != nil {
+		return m.FailureCode
+	}
+	return 0
+}
+
+func (m *HtlcEvent) GetFailureDetail() string {
+	if m != nil {
+		return m.FailureDetail
+	}
+	return ""
+}
+
+type PendingSweep struct {
+	// / The outpoint of the output being swept, encoded as txid:output_index.
+	Outpoint string `protobuf:"bytes,1,opt,name=outpoint" json:"outpoint,omitempty"`
+	// / The type of witness used to spend the output.
+	WitnessType string `protobuf:"bytes,2,opt,name=witness_type" json:"witness_type,omitempty"`
+	// / The value of the output in atoms.
+	Amount int64 `protobuf:"varint,3,opt,name=amount" json:"amount,omitempty"`
+	// / The number of broadcast attempts made so far to sweep the output.
+	BroadcastAttempts uint32 `protobuf:"varint,4,opt,name=broadcast_attempts" json:"broadcast_attempts,omitempty"`
+	// / The height at which the next attempt to sweep the output will be broadcast.
+	NextBroadcastHeight uint32 `protobuf:"varint,5,opt,name=next_broadcast_height" json:"next_broadcast_height,omitempty"`
+	// / The fee rate in atoms per byte at which the output would be swept next.
+	FeePerByte int64 `protobuf:"varint,6,opt,name=fee_per_byte" json:"fee_per_byte,omitempty"`
+	// / The height by which the sweep of the output must confirm, or zero if it has no deadline.
+	DeadlineHeight uint32 `protobuf:"varint,7,opt,name=deadline_height" json:"deadline_height,omitempty"`
+}
+
+func (m *PendingSweep) Reset()                    { *m = PendingSweep{} }
+func (m *PendingSweep) String() string            { return proto.CompactTextString(m) }
+func (*PendingSweep) ProtoMessage()               {}
+func (*PendingSweep) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{137} }
+
+func (m *PendingSweep) GetOutpoint() string {
+	if m != nil {
+		return m.Outpoint
+	}
+	return ""
+}
+
+func (m *PendingSweep) GetWitnessType() string {
+	if m != nil {
+		return m.WitnessType
+	}
+	return ""
+}
+
+func (m *PendingSweep) GetAmount() int64 {
+	if m != nil {
+		return m.Amount
+	}
+	return 0
+}
+
+func (m *PendingSweep) GetBroadcastAttempts() uint32 {
+	if m != nil {
+		return m.BroadcastAttempts
+	}
+	return 0
+}
+
+func (m *PendingSweep) GetNextBroadcastHeight() uint32 {
+	if m != nil {
+		return m.NextBroadcastHeight
+	}
+	return 0
+}
+
+func (m *PendingSweep) GetFeePerByte() int64 {
+	if m != nil {
+		return m.FeePerByte
+	}
+	return 0
+}
+
+func (m *PendingSweep) GetDeadlineHeight() uint32 {
+	if m != nil {
+		return m.DeadlineHeight
+	}
+	return 0
+}
+
+type PendingSweepsRequest struct {
+}
+
+func (m *PendingSweepsRequest) Reset()                    { *m = PendingSweepsRequest{} }
+func (m *PendingSweepsRequest) String() string            { return proto.CompactTextString(m) }
+func (*PendingSweepsRequest) ProtoMessage()               {}
+func (*PendingSweepsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{138} }
+
+type PendingSweepsResponse struct {
+	// / The outputs currently being swept.
+	PendingSweeps []*PendingSweep `protobuf:"bytes,1,rep,name=pending_sweeps" json:"pending_sweeps,omitempty"`
+}
+
+func (m *PendingSweepsResponse) Reset()                    { *m = PendingSweepsResponse{} }
+func (m *PendingSweepsResponse) String() string            { return proto.CompactTextString(m) }
+func (*PendingSweepsResponse) ProtoMessage()               {}
+func (*PendingSweepsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{139} }
+
+func (m *PendingSweepsResponse) GetPendingSweeps() []*PendingSweep {
+	if m != nil {
+		return m.PendingSweeps
+	}
+	return nil
+}
+
+type BumpFeeRequest struct {
+	// / The outpoint of the output to bump the fee rate of, encoded as txid:output_index.
+	Outpoint string `protobuf:"bytes,1,opt,name=outpoint" json:"outpoint,omitempty"`
+	// / The fee rate in atoms per byte at which the output should be swept.
+	FeePerByte int64 `protobuf:"varint,2,opt,name=fee_per_byte" json:"fee_per_byte,omitempty"`
+}
+
+func (m *BumpFeeRequest) Reset()                    { *m = BumpFeeRequest{} }
+func (m *BumpFeeRequest) String() string            { return proto.CompactTextString(m) }
+func (*BumpFeeRequest) ProtoMessage()               {}
+func (*BumpFeeRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{140} }
+
+func (m *BumpFeeRequest) GetOutpoint() string {
+	if m != nil {
+		return m.Outpoint
+	}
+	return ""
+}
+
+func (m *BumpFeeRequest) GetFeePerByte() int64 {
+	if m != nil {
+		return m.FeePerByte
+	}
+	return 0
+}
+
+type BumpFeeResponse struct {
+}
+
+func (m *BumpFeeResponse) Reset()                    { *m = BumpFeeResponse{} }
+func (m *BumpFeeResponse) String() string            { return proto.CompactTextString(m) }
+func (*BumpFeeResponse) ProtoMessage()               {}
+func (*BumpFeeResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{141} }
+
+type PendingHTLC struct {
+	// / The direction within the channel that the htlc was sent
+	Incoming bool `protobuf:"varint,1,opt,name=incoming" json:"incoming,omitempty"`
+	// / The total value of the htlc
+	Amount int64 `protobuf:"varint,2,opt,name=amount" json:"amount,omitempty"`
+	// / The final output to be swept back to the user's wallet
+	Outpoint string `protobuf:"bytes,3,opt,name=outpoint" json:"outpoint,omitempty"`
+	// / The next block height at which we can spend the current stage
+	MaturityHeight uint32 `protobuf:"varint,4,opt,name=maturity_height" json:"maturity_height,omitempty"`
+	// *
+	// The number of blocks remaining until the current stage can be swept.
+	// Negative values indicate how many blocks have passed since becoming
+	// mature.
+	BlocksTilMaturity int32 `protobuf:"varint,5,opt,name=blocks_til_maturity" json:"blocks_til_maturity,omitempty"`
+	// / Indicates whether the htlc is in its first or second stage of recovery
+	Stage uint32 `protobuf:"varint,6,opt,name=stage" json:"stage,omitempty"`
+}
+
+func (m *PendingHTLC) Reset()                    { *m = PendingHTLC{} }
+func (m *PendingHTLC) String() string            { return proto.CompactTextString(m) }
+func (*PendingHTLC) ProtoMessage()               {}
+func (*PendingHTLC) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{142} }
+
+func (m *PendingHTLC) GetIncoming() bool {
+	if m != nil {
+		return m.Incoming
+	}
+	return false
+}
+
+func (m *PendingHTLC) GetAmount() int64 {
+	if m != nil {
+		return m.Amount
+	}
+	return 0
+}
+
+func (m *PendingHTLC) GetOutpoint() string {
+	if m != nil {
+		return m.Outpoint
+	}
+	return ""
+}
+
+func (m *PendingHTLC) GetMaturityHeight() uint32 {
+	if m != nil {
+		return m.MaturityHeight
+	}
+	return 0
+}
+
+func (m *PendingHTLC) GetBlocksTilMaturity() int32 {
+	if m != nil {
+		return m.BlocksTilMaturity
+	}
+	return 0
+}
+
+func (m *PendingHTLC) GetStage() uint32 {
+	if m != nil {
+		return m.Stage
+	}
+	return 0
+}
+
+type RemoveSettleConsumerRequest struct {
+	// / The identifier of the durable consumer to remove.
+	ConsumerId string `protobuf:"bytes,1,opt,name=consumer_id" json:"consumer_id,omitempty"`
+}
+
+func (m *RemoveSettleConsumerRequest) Reset()                    { *m = RemoveSettleConsumerRequest{} }
+func (m *RemoveSettleConsumerRequest) String() string            { return proto.CompactTextString(m) }
+func (*RemoveSettleConsumerRequest) ProtoMessage()               {}
+func (*RemoveSettleConsumerRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{143} }
+
+func (m *RemoveSettleConsumerRequest) GetConsumerId() string {
+	if m != nil {
+		return m.ConsumerId
+	}
+	return ""
+}
+
+type RemoveSettleConsumerResponse struct {
+}
+
+func (m *RemoveSettleConsumerResponse) Reset()                    { *m = RemoveSettleConsumerResponse{} }
+func (m *RemoveSettleConsumerResponse) String() string            { return proto.CompactTextString(m) }
+func (*RemoveSettleConsumerResponse) ProtoMessage()               {}
+func (*RemoveSettleConsumerResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{144} }
+
+type HopHint struct {
+	// / The public key of the node at the start of the channel.
+	NodeId string `protobuf:"bytes,1,opt,name=node_id" json:"node_id,omitempty"`
+	// / The unique identifier of the channel.
+	ChanId uint64 `protobuf:"varint,2,opt,name=chan_id" json:"chan_id,omitempty"`
+	// / The base fee of the channel denominated in milli-atoms.
+	FeeBaseMsat uint32 `protobuf:"varint,3,opt,name=fee_base_msat" json:"fee_base_msat,omitempty"`
+	// / The fee rate of the channel for sending one atom across it, in millionths.
+	FeeProportionalMillionths uint32 `protobuf:"varint,4,opt,name=fee_proportional_millionths" json:"fee_proportional_millionths,omitempty"`
+	// / The time-lock delta of the channel.
+	CltvExpiryDelta uint32 `protobuf:"varint,5,opt,name=cltv_expiry_delta" json:"cltv_expiry_delta,omitempty"`
+}
+
+func (m *HopHint) Reset()                    { *m = HopHint{} }
+func (m *HopHint) String() string            { return proto.CompactTextString(m) }
+func (*HopHint) ProtoMessage()               {}
+func (*HopHint) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{145} }
+
+func (m *HopHint) GetNodeId() string {
+	if m != nil {
+		return m.NodeId
+	}
+	return ""
+}
+
+func (m *HopHint) GetChanId() uint64 {
+	if m != nil {
+		return m.ChanId
+	}
+	return 0
+}
+
+func (m *HopHint) GetFeeBaseMsat() uint32 {
+	if m != nil {
+		return m.FeeBaseMsat
+	}
+	return 0
+}
+
+func (m *HopHint) GetFeeProportionalMillionths() uint32 {
+	if m != nil {
+		return m.FeeProportionalMillionths
+	}
+	return 0
+}
+
+func (m *HopHint) GetCltvExpiryDelta() uint32 {
+	if m != nil {
+		return m.CltvExpiryDelta
+	}
+	return 0
+}
+
+type RouteHint struct {
+	// / The hops of the chain of private channels, starting with the hop furthest from the destination.
+	HopHints []*HopHint `protobuf:"bytes,1,rep,name=hop_hints" json:"hop_hints,omitempty"`
+}
+
+func (m *RouteHint) Reset()                    { *m = RouteHint{} }
+func (m *RouteHint) String() string            { return proto.CompactTextString(m) }
+func (*RouteHint) ProtoMessage()               {}
+func (*RouteHint) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{146} }
+
+func (m *RouteHint) GetHopHints() []*HopHint {
+	if m != nil {
+		return m.HopHints
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*MigrationStatusRequest)(nil), "lnrpc.MigrationStatusRequest")
+	proto.RegisterType((*MigrationStatusResponse)(nil), "lnrpc.MigrationStatusResponse")
+	proto.RegisterType((*Transaction)(nil), "lnrpc.Transaction")
+	proto.RegisterType((*GetTransactionsRequest)(nil), "lnrpc.GetTransactionsRequest")
+	proto.RegisterType((*TransactionDetails)(nil), "lnrpc.TransactionDetails")
+	proto.RegisterType((*SendRequest)(nil), "lnrpc.SendRequest")
+	proto.RegisterType((*SendResponse)(nil), "lnrpc.SendResponse")
+	proto.RegisterType((*ChannelPoint)(nil), "lnrpc.ChannelPoint")
+	proto.RegisterType((*LightningAddress)(nil), "lnrpc.LightningAddress")
+	proto.RegisterType((*SendManyRequest)(nil), "lnrpc.SendManyRequest")
 	proto.RegisterType((*SendManyResponse)(nil), "lnrpc.SendManyResponse")
 	proto.RegisterType((*SendCoinsRequest)(nil), "lnrpc.SendCoinsRequest")
 	proto.RegisterType((*SendCoinsResponse)(nil), "lnrpc.SendCoinsResponse")
@@ -3321,6 +5973,10 @@ func init() {
 	proto.RegisterType((*ActiveChannel)(nil), "lnrpc.ActiveChannel")
 	proto.RegisterType((*ListChannelsRequest)(nil), "lnrpc.ListChannelsRequest")
 	proto.RegisterType((*ListChannelsResponse)(nil), "lnrpc.ListChannelsResponse")
+	proto.RegisterType((*HTLCResolution)(nil), "lnrpc.HTLCResolution")
+	proto.RegisterType((*ChannelCloseSummary)(nil), "lnrpc.ChannelCloseSummary")
+	proto.RegisterType((*ClosedChannelsRequest)(nil), "lnrpc.ClosedChannelsRequest")
+	proto.RegisterType((*ClosedChannelsResponse)(nil), "lnrpc.ClosedChannelsResponse")
 	proto.RegisterType((*Peer)(nil), "lnrpc.Peer")
 	proto.RegisterType((*ListPeersRequest)(nil), "lnrpc.ListPeersRequest")
 	proto.RegisterType((*ListPeersResponse)(nil), "lnrpc.ListPeersResponse")
@@ -3369,12 +6025,15 @@ func init() {
 	proto.RegisterType((*SetAliasRequest)(nil), "lnrpc.SetAliasRequest")
 	proto.RegisterType((*SetAliasResponse)(nil), "lnrpc.SetAliasResponse")
 	proto.RegisterType((*Invoice)(nil), "lnrpc.Invoice")
+	proto.RegisterType((*InvoiceHTLC)(nil), "lnrpc.InvoiceHTLC")
 	proto.RegisterType((*AddInvoiceResponse)(nil), "lnrpc.AddInvoiceResponse")
 	proto.RegisterType((*PaymentHash)(nil), "lnrpc.PaymentHash")
 	proto.RegisterType((*ListInvoiceRequest)(nil), "lnrpc.ListInvoiceRequest")
 	proto.RegisterType((*ListInvoiceResponse)(nil), "lnrpc.ListInvoiceResponse")
+	proto.RegisterType((*SettleEventAck)(nil), "lnrpc.SettleEventAck")
 	proto.RegisterType((*InvoiceSubscription)(nil), "lnrpc.InvoiceSubscription")
 	proto.RegisterType((*Payment)(nil), "lnrpc.Payment")
+	proto.RegisterType((*HTLCAttempt)(nil), "lnrpc.HTLCAttempt")
 	proto.RegisterType((*ListPaymentsRequest)(nil), "lnrpc.ListPaymentsRequest")
 	proto.RegisterType((*ListPaymentsResponse)(nil), "lnrpc.ListPaymentsResponse")
 	proto.RegisterType((*DeleteAllPaymentsRequest)(nil), "lnrpc.DeleteAllPaymentsRequest")
@@ -3388,148 +6047,1484 @@ func init() {
 	proto.RegisterType((*FeeReportResponse)(nil), "lnrpc.FeeReportResponse")
 	proto.RegisterType((*FeeUpdateRequest)(nil), "lnrpc.FeeUpdateRequest")
 	proto.RegisterType((*FeeUpdateResponse)(nil), "lnrpc.FeeUpdateResponse")
+	proto.RegisterType((*BlacklistedNode)(nil), "lnrpc.BlacklistedNode")
+	proto.RegisterType((*ListBlacklistRequest)(nil), "lnrpc.ListBlacklistRequest")
+	proto.RegisterType((*ListBlacklistResponse)(nil), "lnrpc.ListBlacklistResponse")
+	proto.RegisterType((*UpdateBlacklistRequest)(nil), "lnrpc.UpdateBlacklistRequest")
+	proto.RegisterType((*UpdateBlacklistResponse)(nil), "lnrpc.UpdateBlacklistResponse")
+	proto.RegisterType((*CommitmentTxnsRequest)(nil), "lnrpc.CommitmentTxnsRequest")
+	proto.RegisterType((*CommitmentTxnsResponse)(nil), "lnrpc.CommitmentTxnsResponse")
+	proto.RegisterType((*ExportChannelDBRequest)(nil), "lnrpc.ExportChannelDBRequest")
+	proto.RegisterType((*ChannelDBChunk)(nil), "lnrpc.ChannelDBChunk")
+	proto.RegisterType((*SendCustomMessageRequest)(nil), "lnrpc.SendCustomMessageRequest")
+	proto.RegisterType((*SendCustomMessageResponse)(nil), "lnrpc.SendCustomMessageResponse")
+	proto.RegisterType((*SubscribeCustomMessagesRequest)(nil), "lnrpc.SubscribeCustomMessagesRequest")
+	proto.RegisterType((*CustomMessage)(nil), "lnrpc.CustomMessage")
+	proto.RegisterType((*NodeHistory)(nil), "lnrpc.NodeHistory")
+	proto.RegisterType((*PairHistory)(nil), "lnrpc.PairHistory")
+	proto.RegisterType((*QueryMissionControlRequest)(nil), "lnrpc.QueryMissionControlRequest")
+	proto.RegisterType((*QueryMissionControlResponse)(nil), "lnrpc.QueryMissionControlResponse")
+	proto.RegisterType((*ResetMissionControlRequest)(nil), "lnrpc.ResetMissionControlRequest")
+	proto.RegisterType((*ResetMissionControlResponse)(nil), "lnrpc.ResetMissionControlResponse")
+	proto.RegisterType((*XImportMissionControlRequest)(nil), "lnrpc.XImportMissionControlRequest")
+	proto.RegisterType((*XImportMissionControlResponse)(nil), "lnrpc.XImportMissionControlResponse")
+	proto.RegisterType((*BuildRouteRequest)(nil), "lnrpc.BuildRouteRequest")
+	proto.RegisterType((*BuildRouteResponse)(nil), "lnrpc.BuildRouteResponse")
+	proto.RegisterType((*SendToRouteRequest)(nil), "lnrpc.SendToRouteRequest")
+	proto.RegisterType((*RouteFailure)(nil), "lnrpc.RouteFailure")
+	proto.RegisterType((*SendToRouteResponse)(nil), "lnrpc.SendToRouteResponse")
+	proto.RegisterType((*QueryProbabilityRequest)(nil), "lnrpc.QueryProbabilityRequest")
+	proto.RegisterType((*QueryProbabilityResponse)(nil), "lnrpc.QueryProbabilityResponse")
+	proto.RegisterType((*EdgeLocator)(nil), "lnrpc.EdgeLocator")
+	proto.RegisterType((*PolicyUpdateRequest)(nil), "lnrpc.PolicyUpdateRequest")
+	proto.RegisterType((*PolicyUpdateResponse)(nil), "lnrpc.PolicyUpdateResponse")
+	proto.RegisterType((*ForwardHtlcInterceptRequest)(nil), "lnrpc.ForwardHtlcInterceptRequest")
+	proto.RegisterType((*ForwardHtlcInterceptResponse)(nil), "lnrpc.ForwardHtlcInterceptResponse")
+	proto.RegisterType((*SettleInvoiceMsg)(nil), "lnrpc.SettleInvoiceMsg")
+	proto.RegisterType((*SettleInvoiceResp)(nil), "lnrpc.SettleInvoiceResp")
+	proto.RegisterType((*CancelInvoiceMsg)(nil), "lnrpc.CancelInvoiceMsg")
+	proto.RegisterType((*CancelInvoiceResp)(nil), "lnrpc.CancelInvoiceResp")
+	proto.RegisterType((*SubscribeHtlcEventsRequest)(nil), "lnrpc.SubscribeHtlcEventsRequest")
+	proto.RegisterType((*HtlcEvent)(nil), "lnrpc.HtlcEvent")
+	proto.RegisterType((*PendingSweep)(nil), "lnrpc.PendingSweep")
+	proto.RegisterType((*PendingSweepsRequest)(nil), "lnrpc.PendingSweepsRequest")
+	proto.RegisterType((*PendingSweepsResponse)(nil), "lnrpc.PendingSweepsResponse")
+	proto.RegisterType((*BumpFeeRequest)(nil), "lnrpc.BumpFeeRequest")
+	proto.RegisterType((*BumpFeeResponse)(nil), "lnrpc.BumpFeeResponse")
+	proto.RegisterType((*PendingHTLC)(nil), "lnrpc.PendingHTLC")
+	proto.RegisterType((*RemoveSettleConsumerRequest)(nil), "lnrpc.RemoveSettleConsumerRequest")
+	proto.RegisterType((*RemoveSettleConsumerResponse)(nil), "lnrpc.RemoveSettleConsumerResponse")
+	proto.RegisterType((*HopHint)(nil), "lnrpc.HopHint")
+	proto.RegisterType((*RouteHint)(nil), "lnrpc.RouteHint")
+	proto.RegisterEnum("lnrpc.PaymentFailureReason", PaymentFailureReason_name, PaymentFailureReason_value)
+	proto.RegisterEnum("lnrpc.ResolveHoldForwardAction", ResolveHoldForwardAction_name, ResolveHoldForwardAction_value)
 	proto.RegisterEnum("lnrpc.NewAddressRequest_AddressType", NewAddressRequest_AddressType_name, NewAddressRequest_AddressType_value)
+	proto.RegisterEnum("lnrpc.HTLCResolution_Outcome", HTLCResolution_Outcome_name, HTLCResolution_Outcome_value)
+	proto.RegisterEnum("lnrpc.ChannelCloseSummary_ClosureType", ChannelCloseSummary_ClosureType_name, ChannelCloseSummary_ClosureType_value)
+	proto.RegisterEnum("lnrpc.Invoice_InvoiceState", Invoice_InvoiceState_name, Invoice_InvoiceState_value)
+	proto.RegisterEnum("lnrpc.Payment_PaymentStatus", Payment_PaymentStatus_name, Payment_PaymentStatus_value)
+	proto.RegisterEnum("lnrpc.HTLCAttempt_HTLCStatus", HTLCAttempt_HTLCStatus_name, HTLCAttempt_HTLCStatus_value)
+	proto.RegisterEnum("lnrpc.HtlcEvent_EventType", HtlcEvent_EventType_name, HtlcEvent_EventType_value)
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for DatabaseState service
+
+type DatabaseStateClient interface {
+	// * lncli: `migrationstatus`
+	// MigrationStatus returns the progress of any database migrations that are
+	// being applied as the daemon starts up.
+	MigrationStatus(ctx context.Context, in *MigrationStatusRequest, opts ...grpc.CallOption) (*MigrationStatusResponse, error)
+}
+
+type databaseStateClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewDatabaseStateClient(cc *grpc.ClientConn) DatabaseStateClient {
+	return &databaseStateClient{cc}
+}
+
+func (c *databaseStateClient) MigrationStatus(ctx context.Context, in *MigrationStatusRequest, opts ...grpc.CallOption) (*MigrationStatusResponse, error) {
+	out := new(MigrationStatusResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.DatabaseState/MigrationStatus", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for DatabaseState service
+
+type DatabaseStateServer interface {
+	// * lncli: `migrationstatus`
+	// MigrationStatus returns the progress of any database migrations that are
+	// being applied as the daemon starts up.
+	MigrationStatus(context.Context, *MigrationStatusRequest) (*MigrationStatusResponse, error)
+}
+
+func RegisterDatabaseStateServer(s *grpc.Server, srv DatabaseStateServer) {
+	s.RegisterService(&_DatabaseState_serviceDesc, srv)
+}
+
+func _DatabaseState_MigrationStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MigrationStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DatabaseStateServer).MigrationStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.DatabaseState/MigrationStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DatabaseStateServer).MigrationStatus(ctx, req.(*MigrationStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _DatabaseState_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "lnrpc.DatabaseState",
+	HandlerType: (*DatabaseStateServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "MigrationStatus",
+			Handler:    _DatabaseState_MigrationStatus_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "rpc.proto",
+}
+
+// Client API for Lightning service
+
+type LightningClient interface {
+	// * lncli: `walletbalance`
+	// WalletBalance returns the sum of all confirmed unspent outputs under control
+	// by the wallet. This method can be modified by having the request specify
+	// only witness outputs should be factored into the final output sum.
+	WalletBalance(ctx context.Context, in *WalletBalanceRequest, opts ...grpc.CallOption) (*WalletBalanceResponse, error)
+	// * lncli: `channelbalance`
+	// ChannelBalance returns the total funds available across all open channels
+	// in satoshis.
+	ChannelBalance(ctx context.Context, in *ChannelBalanceRequest, opts ...grpc.CallOption) (*ChannelBalanceResponse, error)
+	// * lncli: `listchaintxns`
+	// GetTransactions returns a list describing all the known transactions
+	// relevant to the wallet.
+	GetTransactions(ctx context.Context, in *GetTransactionsRequest, opts ...grpc.CallOption) (*TransactionDetails, error)
+	// * lncli: `sendcoins`
+	// SendCoins executes a request to send coins to a particular address. Unlike
+	// SendMany, this RPC call only allows creating a single output at a time.
+	SendCoins(ctx context.Context, in *SendCoinsRequest, opts ...grpc.CallOption) (*SendCoinsResponse, error)
+	// *
+	// SubscribeTransactions creates a uni-directional stream from the server to
+	// the client in which any newly discovered transactions relevant to the
+	// wallet are sent over.
+	SubscribeTransactions(ctx context.Context, in *GetTransactionsRequest, opts ...grpc.CallOption) (Lightning_SubscribeTransactionsClient, error)
+	// * lncli: `sendmany`
+	// SendMany handles a request for a transaction that creates multiple specified
+	// outputs in parallel.
+	SendMany(ctx context.Context, in *SendManyRequest, opts ...grpc.CallOption) (*SendManyResponse, error)
+	// * lncli: `newaddress`
+	// NewAddress creates a new address under control of the local wallet.
+	NewAddress(ctx context.Context, in *NewAddressRequest, opts ...grpc.CallOption) (*NewAddressResponse, error)
+	// *
+	// NewWitnessAddress creates a new witness address under control of the local wallet.
+	NewWitnessAddress(ctx context.Context, in *NewWitnessAddressRequest, opts ...grpc.CallOption) (*NewAddressResponse, error)
+	// * lncli: `signmessage`
+	// SignMessage signs a message with this node's private key. The returned
+	// signature string is `zbase32` encoded and pubkey recoverable, meaning that
+	// only the message digest and signature are needed for verification.
+	SignMessage(ctx context.Context, in *SignMessageRequest, opts ...grpc.CallOption) (*SignMessageResponse, error)
+	// * lncli: `verifymessage`
+	// VerifyMessage verifies a signature over a msg. The signature must be
+	// zbase32 encoded and signed by an active node in the resident node's
+	// channel database. In addition to returning the validity of the signature,
+	// VerifyMessage also returns the recovered pubkey from the signature.
+	VerifyMessage(ctx context.Context, in *VerifyMessageRequest, opts ...grpc.CallOption) (*VerifyMessageResponse, error)
+	// * lncli: `connect`
+	// ConnectPeer attempts to establish a connection to a remote peer. This is at
+	// the networking level, and is used for communication between nodes. This is
+	// distinct from establishing a channel with a peer.
+	ConnectPeer(ctx context.Context, in *ConnectPeerRequest, opts ...grpc.CallOption) (*ConnectPeerResponse, error)
+	// * lncli: `disconnect`
+	// DisconnectPeer attempts to disconnect one peer from another identified by a
+	// given pubKey. In the case that we currently have a pending or active channel
+	// with the target peer, then this action will be not be allowed.
+	DisconnectPeer(ctx context.Context, in *DisconnectPeerRequest, opts ...grpc.CallOption) (*DisconnectPeerResponse, error)
+	// * lncli: `listpeers`
+	// ListPeers returns a verbose listing of all currently active peers.
+	ListPeers(ctx context.Context, in *ListPeersRequest, opts ...grpc.CallOption) (*ListPeersResponse, error)
+	// * lncli: `getinfo`
+	// GetInfo returns general information concerning the lightning node including
+	// it's identity pubkey, alias, the chains it is connected to, and information
+	// concerning the number of open+pending channels.
+	GetInfo(ctx context.Context, in *GetInfoRequest, opts ...grpc.CallOption) (*GetInfoResponse, error)
+	// * lncli: `pendingchannels`
+	// PendingChannels returns a list of all the channels that are currently
+	// considered "pending". A channel is pending if it has finished the funding
+	// workflow and is waiting for confirmations for the funding txn, or is in the
+	// process of closure, either initiated cooperatively or non-cooperatively.
+	PendingChannels(ctx context.Context, in *PendingChannelRequest, opts ...grpc.CallOption) (*PendingChannelResponse, error)
+	// * lncli: `listchannels`
+	// ListChannels returns a description of all the open channels that this node
+	// is a participant in.
+	ListChannels(ctx context.Context, in *ListChannelsRequest, opts ...grpc.CallOption) (*ListChannelsResponse, error)
+	// * lncli: `closedchannels`
+	// ClosedChannels returns a description of all the closed channels that
+	// this node was a participant in, optionally filtered by the manner in which
+	// each channel was closed.
+	ClosedChannels(ctx context.Context, in *ClosedChannelsRequest, opts ...grpc.CallOption) (*ClosedChannelsResponse, error)
+	// *
+	// OpenChannelSync is a synchronous version of the OpenChannel RPC call. This
+	// call is meant to be consumed by clients to the REST proxy. As with all
+	// other sync calls, all byte slices are intended to be populated as hex
+	// encoded strings.
+	OpenChannelSync(ctx context.Context, in *OpenChannelRequest, opts ...grpc.CallOption) (*ChannelPoint, error)
+	// * lncli: `openchannel`
+	// OpenChannel attempts to open a singly funded channel specified in the
+	// request to a remote peer.
+	OpenChannel(ctx context.Context, in *OpenChannelRequest, opts ...grpc.CallOption) (Lightning_OpenChannelClient, error)
+	// * lncli: `closechannel`
+	// CloseChannel attempts to close an active channel identified by its channel
+	// outpoint (ChannelPoint). The actions of this method can additionally be
+	// augmented to attempt a force close after a timeout period in the case of an
+	// inactive peer.
+	CloseChannel(ctx context.Context, in *CloseChannelRequest, opts ...grpc.CallOption) (Lightning_CloseChannelClient, error)
+	// * lncli: `sendpayment`
+	// SendPayment dispatches a bi-directional streaming RPC for sending payments
+	// through the Lightning Network. A single RPC invocation creates a persistent
+	// bi-directional stream allowing clients to rapidly send payments through the
+	// Lightning Network with a single persistent connection.
+	SendPayment(ctx context.Context, opts ...grpc.CallOption) (Lightning_SendPaymentClient, error)
+	// *
+	// SendPaymentSync is the synchronous non-streaming version of SendPayment.
+	// This RPC is intended to be consumed by clients of the REST proxy.
+	// Additionally, this RPC expects the destination's public key and the payment
+	// hash (if any) to be encoded as hex strings.
+	SendPaymentSync(ctx context.Context, in *SendRequest, opts ...grpc.CallOption) (*SendResponse, error)
+	// * lncli: `addinvoice`
+	// AddInvoice attempts to add a new invoice to the invoice database. Any
+	// duplicated invoices are rejected, therefore all invoices *must* have a
+	// unique payment preimage.
+	AddInvoice(ctx context.Context, in *Invoice, opts ...grpc.CallOption) (*AddInvoiceResponse, error)
+	// * lncli: `listinvoices`
+	// ListInvoices returns a list of all the invoices currently stored within the
+	// database. Any active debug invoices are ignored.
+	ListInvoices(ctx context.Context, in *ListInvoiceRequest, opts ...grpc.CallOption) (*ListInvoiceResponse, error)
+	// * lncli: `lookupinvoice`
+	// LookupInvoice attemps to look up an invoice according to its payment hash.
+	// The passed payment hash *must* be exactly 32 bytes, if not, an error is
+	// returned.
+	LookupInvoice(ctx context.Context, in *PaymentHash, opts ...grpc.CallOption) (*Invoice, error)
+	// *
+	// SubscribeInvoices returns a uni-directional stream (sever -> client) for
+	// notifying the client of newly added/settled invoices. The caller can
+	// optionally specify the add_index and/or the settle_index. If specified,
+	// then we'll first start by sending add invoice events for all invoices with
+	// an add_index greater than the specified value. If the settle_index is
+	// specified, the next we'll send out all settle events for invoices with a
+	// settle_index greater than the specified value. One or both of these fields
+	// can be set. If no fields are set, then we'll only send out the latest add
+	// and settle events.
+	SubscribeInvoices(ctx context.Context, in *InvoiceSubscription, opts ...grpc.CallOption) (Lightning_SubscribeInvoicesClient, error)
+	// *
+	// SubscribeSettledInvoices dispatches a bi-directional streaming RPC which
+	// delivers invoice settlement events to a durable consumer with
+	// at-least-once semantics. The first message sent by the client must
+	// specify the consumer_id, registering the consumer if it's new. The server
+	// will then send every invoice settled since the latest event acknowledged
+	// by the consumer, followed by each newly settled invoice. The client
+	// acknowledges events by sending their settle_index. Any events that
+	// haven't been acknowledged will be re-delivered once the consumer
+	// reconnects, even across restarts of the daemon. The number of consumers
+	// is bounded, and consumers which remain disconnected for too long are
+	// removed.
+	SubscribeSettledInvoices(ctx context.Context, opts ...grpc.CallOption) (Lightning_SubscribeSettledInvoicesClient, error)
+	// * lncli: `decodepayreq`
+	// DecodePayReq takes an encoded payment request string and attempts to decode
+	// it, returning a full description of the conditions encoded within the
+	// payment request.
+	DecodePayReq(ctx context.Context, in *PayReqString, opts ...grpc.CallOption) (*PayReq, error)
+	// * lncli: `listpayments`
+	// ListPayments returns a list of all outgoing payments.
+	ListPayments(ctx context.Context, in *ListPaymentsRequest, opts ...grpc.CallOption) (*ListPaymentsResponse, error)
+	// *
+	// DeleteAllPayments deletes all outgoing payments from DB.
+	DeleteAllPayments(ctx context.Context, in *DeleteAllPaymentsRequest, opts ...grpc.CallOption) (*DeleteAllPaymentsResponse, error)
+	// * lncli: `describegraph`
+	// DescribeGraph returns a description of the latest graph state from the
+	// point of view of the node. The graph information is partitioned into two
+	// components: all the nodes/vertexes, and all the edges that connect the
+	// vertexes themselves.  As this is a directed graph, the edges also contain
+	// the node directional specific routing policy which includes: the time lock
+	// delta, fee information, etc.
+	DescribeGraph(ctx context.Context, in *ChannelGraphRequest, opts ...grpc.CallOption) (*ChannelGraph, error)
+	// * lncli: `getchaninfo`
+	// GetChanInfo returns the latest authenticated network announcement for the
+	// given channel identified by its channel ID: an 8-byte integer which
+	// uniquely identifies the location of transaction's funding output within the
+	// blockchain.
+	GetChanInfo(ctx context.Context, in *ChanInfoRequest, opts ...grpc.CallOption) (*ChannelEdge, error)
+	// * lncli: `getnodeinfo`
+	// GetNodeInfo returns the latest advertised, aggregated, and authenticated
+	// channel information for the specified node identified by its public key.
+	GetNodeInfo(ctx context.Context, in *NodeInfoRequest, opts ...grpc.CallOption) (*NodeInfo, error)
+	// * lncli: `queryroutes`
+	// QueryRoutes attempts to query the daemon's Channel Router for a possible
+	// route to a target destination capable of carrying a specific amount of
+	// satoshis. The retuned route contains the full details required to craft and
+	// send an HTLC, also including the necessary information that should be
+	// present within the Sphinx packet encapsualted within the HTLC.
+	QueryRoutes(ctx context.Context, in *QueryRoutesRequest, opts ...grpc.CallOption) (*QueryRoutesResponse, error)
+	// * lncli: `getnetworkinfo`
+	// GetNetworkInfo returns some basic stats about the known channel graph from
+	// the point of view of the node.
+	GetNetworkInfo(ctx context.Context, in *NetworkInfoRequest, opts ...grpc.CallOption) (*NetworkInfo, error)
+	// * lncli: `stop`
+	// StopDaemon will send a shutdown request to the interrupt handler, triggering
+	// a graceful shutdown of the daemon.
+	StopDaemon(ctx context.Context, in *StopRequest, opts ...grpc.CallOption) (*StopResponse, error)
+	// *
+	// SubscribeChannelGraph launches a streaming RPC that allows the caller to
+	// receive notifications upon any changes to the channel graph topology from
+	// the point of view of the responding node. Events notified include: new
+	// nodes coming online, nodes updating their authenticated attributes, new
+	// channels being advertised, updates in the routing policy for a directional
+	// channel edge, and when channels are closed on-chain.
+	SubscribeChannelGraph(ctx context.Context, in *GraphTopologySubscription, opts ...grpc.CallOption) (Lightning_SubscribeChannelGraphClient, error)
+	// *
+	// SetAlias sets the alias for this node; e.g. "alice"
+	SetAlias(ctx context.Context, in *SetAliasRequest, opts ...grpc.CallOption) (*SetAliasResponse, error)
+	// * lncli: `debuglevel`
+	// DebugLevel allows a caller to programmatically set the logging verbosity of
+	// lnd. The logging can be targeted according to a coarse daemon-wide logging
+	// level, or in a granular fashion to specify the logging for a target
+	// sub-system.
+	DebugLevel(ctx context.Context, in *DebugLevelRequest, opts ...grpc.CallOption) (*DebugLevelResponse, error)
+	// * lncli: `feereport`
+	// FeeReport allows the caller to obtain a report detailing the current fee
+	// schedule enforced by the node globally for each channel.
+	FeeReport(ctx context.Context, in *FeeReportRequest, opts ...grpc.CallOption) (*FeeReportResponse, error)
+	// * lncli: `updatefees`
+	// UpdateFees allows the caller to update the fee schedule for all channels
+	// globally, or a particular channel.
+	UpdateFees(ctx context.Context, in *FeeUpdateRequest, opts ...grpc.CallOption) (*FeeUpdateResponse, error)
+	// * lncli: `listblacklist`
+	// ListBlacklist returns the set of nodes which are currently blacklisted, and
+	// will therefore never be used as an intermediate hop when routing payments.
+	ListBlacklist(ctx context.Context, in *ListBlacklistRequest, opts ...grpc.CallOption) (*ListBlacklistResponse, error)
+	// * lncli: `updateblacklist`
+	// UpdateBlacklist allows the caller to add nodes to, or remove nodes from,
+	// the persistent node blacklist consulted during path finding.
+	UpdateBlacklist(ctx context.Context, in *UpdateBlacklistRequest, opts ...grpc.CallOption) (*UpdateBlacklistResponse, error)
+	// * lncli: `getcommitmenttxns`
+	// GetCommitmentTxns returns our current fully signed commitment transaction
+	// for the target channel, along with the fully signed second-level HTLC
+	// timeout transactions which spend from it. These are exactly the
+	// transactions that would be broadcast were the channel to be force closed.
+	GetCommitmentTxns(ctx context.Context, in *CommitmentTxnsRequest, opts ...grpc.CallOption) (*CommitmentTxnsResponse, error)
+	// * lncli: `exportchanneldb`
+	// ExportChannelDB streams a consistent snapshot of the channel database,
+	// taken while the daemon is running. The concatenated chunks form a complete
+	// database file, which can be restored in place of channel.db while the
+	// daemon is stopped.
+	ExportChannelDB(ctx context.Context, in *ExportChannelDBRequest, opts ...grpc.CallOption) (Lightning_ExportChannelDBClient, error)
+	// * lncli: `sendcustom`
+	// SendCustomMessage sends a custom peer message to a connected peer. The
+	// message type must lie within the custom type range, starting at 32768, and
+	// its data is sent as is, without being interpreted by the daemon.
+	SendCustomMessage(ctx context.Context, in *SendCustomMessageRequest, opts ...grpc.CallOption) (*SendCustomMessageResponse, error)
+	// * lncli: `subscribecustom`
+	// SubscribeCustomMessages returns a uni-directional stream (server -> client)
+	// of the custom peer messages received from any connected peer, allowing
+	// applications to build their own protocols on top of the daemon's peer
+	// connections.
+	SubscribeCustomMessages(ctx context.Context, in *SubscribeCustomMessagesRequest, opts ...grpc.CallOption) (Lightning_SubscribeCustomMessagesClient, error)
+	// * lncli: `querymc`
+	// QueryMissionControl returns the router's mission control history, which
+	// records the outcome of past payment attempts for each node and directed
+	// pair of nodes, and is used to steer path finding away from hops that
+	// recently caused payments to fail.
+	QueryMissionControl(ctx context.Context, in *QueryMissionControlRequest, opts ...grpc.CallOption) (*QueryMissionControlResponse, error)
+	// * lncli: `resetmc`
+	// ResetMissionControl wipes the router's mission control history, lifting
+	// all penalties resulting from past payment failures.
+	ResetMissionControl(ctx context.Context, in *ResetMissionControlRequest, opts ...grpc.CallOption) (*ResetMissionControlResponse, error)
+	// *
+	// XImportMissionControl is an experimental API that seeds the router's
+	// mission control history with the passed entries, typically obtained from
+	// another node using QueryMissionControl. Imported observations only replace
+	// our own if they're more recent.
+	XImportMissionControl(ctx context.Context, in *XImportMissionControlRequest, opts ...grpc.CallOption) (*XImportMissionControlResponse, error)
+	// * lncli: `buildroute`
+	// BuildRoute constructs a fully specified route traversing the passed hops
+	// in order, starting from our own node. Between each pair of hops, the
+	// cheapest channel within the local graph able to carry the amount is
+	// selected, and the fees and time locks are filled in according to its
+	// policy. This allows applications to implement their own routing and
+	// rebalancing strategies.
+	BuildRoute(ctx context.Context, in *BuildRouteRequest, opts ...grpc.CallOption) (*BuildRouteResponse, error)
+	// * lncli: `sendtoroute`
+	// SendToRouteSync attempts to send a payment over the passed route, typically
+	// constructed using BuildRoute. Only a single attempt is made, which is
+	// recorded within the payments database like any other payment. If the
+	// payment fails, then the response identifies the node along the route that
+	// caused the failure, along with the failure message it sent.
+	// If no payment hash is specified, then the route must lead back to our own
+	// node. The payment is then settled by our own node without an invoice,
+	// which allows the balance of our channels to be shifted between them.
+	SendToRouteSync(ctx context.Context, in *SendToRouteRequest, opts ...grpc.CallOption) (*SendToRouteResponse, error)
+	// * lncli: `trackpayment`
+	// TrackPayment returns a uni-directional stream (server -> client) of the
+	// state of the payment with the passed payment hash. The current state of the
+	// payment is sent first, followed by its new state each time it changes: when
+	// an HTLC attempt is added or resolved, and finally once the payment either
+	// succeeds or fails, after which the stream is closed.
+	TrackPayment(ctx context.Context, in *PaymentHash, opts ...grpc.CallOption) (Lightning_TrackPaymentClient, error)
+	// * lncli: `queryprob`
+	// QueryProbability returns the router's current estimate of the probability
+	// of a payment of the passed amount being successfully forwarded from one
+	// node to another, along with the mission control history the estimate is
+	// based on. This allows external applications, such as rebalancers, to
+	// weigh candidate routes in the same way path finding does.
+	QueryProbability(ctx context.Context, in *QueryProbabilityRequest, opts ...grpc.CallOption) (*QueryProbabilityResponse, error)
+	// * lncli: `updatechanpolicy`
+	// UpdateChannelPolicy allows the caller to update the forwarding policy of
+	// all channels globally, or a particular channel. Besides the fee schedule,
+	// the time lock delta and HTLC limits of the channels may be updated. The
+	// new policy is applied to the forwarding links of the channels immediately,
+	// and fresh channel updates are broadcast to the network.
+	UpdateChannelPolicy(ctx context.Context, in *PolicyUpdateRequest, opts ...grpc.CallOption) (*PolicyUpdateResponse, error)
+	// *
+	// HtlcInterceptor dispatches a bi-directional streaming RPC which allows the
+	// caller to intercept the HTLCs forwarded by the daemon. Each HTLC about to
+	// be forwarded is sent over the stream and held until the client resolves
+	// it, by either resuming its forwarding, failing it back, or settling it
+	// with a preimage of its own. HTLCs which aren't resolved within the
+	// configured timeout are failed back. Only a single interceptor may be
+	// active at a time, and any HTLCs still held once the stream is closed are
+	// resumed.
+	HtlcInterceptor(ctx context.Context, opts ...grpc.CallOption) (Lightning_HtlcInterceptorClient, error)
+	// * lncli: `settleinvoice`
+	// SettleInvoice settles an accepted hold invoice using the given preimage,
+	// which must match the payment hash of the invoice. The HTLC held for the
+	// invoice is settled, revealing the preimage to the payer.
+	SettleInvoice(ctx context.Context, in *SettleInvoiceMsg, opts ...grpc.CallOption) (*SettleInvoiceResp, error)
+	// * lncli: `cancelinvoice`
+	// CancelInvoice cancels an invoice which hasn't been settled, after which it
+	// can no longer be paid. Should an HTLC be held for a hold invoice, it's
+	// failed back to the payer.
+	CancelInvoice(ctx context.Context, in *CancelInvoiceMsg, opts ...grpc.CallOption) (*CancelInvoiceResp, error)
+	// * lncli: `subscribehtlcevents`
+	// SubscribeHtlcEvents returns a uni-directional stream (server -> client) of
+	// the HTLC events of the switch, allowing operators to monitor the routing
+	// activity of the daemon in real time. An event is sent each time an HTLC is
+	// forwarded, fails to be forwarded, is failed by a channel link, or is
+	// resolved once settled or failed downstream.
+	SubscribeHtlcEvents(ctx context.Context, in *SubscribeHtlcEventsRequest, opts ...grpc.CallOption) (Lightning_SubscribeHtlcEventsClient, error)
+	// * lncli: `pendingsweeps`
+	// PendingSweeps returns a list of all the outputs that are currently being
+	// swept back into the wallet by the sweeper, along with the number of
+	// broadcast attempts made so far, and the fee rate at which they would be
+	// swept next.
+	PendingSweeps(ctx context.Context, in *PendingSweepsRequest, opts ...grpc.CallOption) (*PendingSweepsResponse, error)
+	// * lncli: `bumpfee`
+	// BumpFee bumps the fee rate of an output which is currently being swept.
+	// The output is swept immediately at no less than the requested fee rate,
+	// batched with any other outputs due to be swept at a similar fee rate.
+	BumpFee(ctx context.Context, in *BumpFeeRequest, opts ...grpc.CallOption) (*BumpFeeResponse, error)
+	// *
+	// RemoveSettleConsumer removes a durable consumer of invoice settlement
+	// events registered through SubscribeSettledInvoices, such that no further
+	// events are retained for it. The consumer must not be connected.
+	RemoveSettleConsumer(ctx context.Context, in *RemoveSettleConsumerRequest, opts ...grpc.CallOption) (*RemoveSettleConsumerResponse, error)
+}
+
+type lightningClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewLightningClient(cc *grpc.ClientConn) LightningClient {
+	return &lightningClient{cc}
+}
+
+func (c *lightningClient) WalletBalance(ctx context.Context, in *WalletBalanceRequest, opts ...grpc.CallOption) (*WalletBalanceResponse, error) {
+	out := new(WalletBalanceResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/WalletBalance", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ChannelBalance(ctx context.Context, in *ChannelBalanceRequest, opts ...grpc.CallOption) (*ChannelBalanceResponse, error) {
+	out := new(ChannelBalanceResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ChannelBalance", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) GetTransactions(ctx context.Context, in *GetTransactionsRequest, opts ...grpc.CallOption) (*TransactionDetails, error) {
+	out := new(TransactionDetails)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/GetTransactions", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) SendCoins(ctx context.Context, in *SendCoinsRequest, opts ...grpc.CallOption) (*SendCoinsResponse, error) {
+	out := new(SendCoinsResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/SendCoins", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) SubscribeTransactions(ctx context.Context, in *GetTransactionsRequest, opts ...grpc.CallOption) (Lightning_SubscribeTransactionsClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[0], c.cc, "/lnrpc.Lightning/SubscribeTransactions", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningSubscribeTransactionsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Lightning_SubscribeTransactionsClient interface {
+	Recv() (*Transaction, error)
+	grpc.ClientStream
+}
+
+type lightningSubscribeTransactionsClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningSubscribeTransactionsClient) Recv() (*Transaction, error) {
+	m := new(Transaction)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lightningClient) SendMany(ctx context.Context, in *SendManyRequest, opts ...grpc.CallOption) (*SendManyResponse, error) {
+	out := new(SendManyResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/SendMany", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) NewAddress(ctx context.Context, in *NewAddressRequest, opts ...grpc.CallOption) (*NewAddressResponse, error) {
+	out := new(NewAddressResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/NewAddress", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) NewWitnessAddress(ctx context.Context, in *NewWitnessAddressRequest, opts ...grpc.CallOption) (*NewAddressResponse, error) {
+	out := new(NewAddressResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/NewWitnessAddress", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) SignMessage(ctx context.Context, in *SignMessageRequest, opts ...grpc.CallOption) (*SignMessageResponse, error) {
+	out := new(SignMessageResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/SignMessage", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) VerifyMessage(ctx context.Context, in *VerifyMessageRequest, opts ...grpc.CallOption) (*VerifyMessageResponse, error) {
+	out := new(VerifyMessageResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/VerifyMessage", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ConnectPeer(ctx context.Context, in *ConnectPeerRequest, opts ...grpc.CallOption) (*ConnectPeerResponse, error) {
+	out := new(ConnectPeerResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ConnectPeer", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) DisconnectPeer(ctx context.Context, in *DisconnectPeerRequest, opts ...grpc.CallOption) (*DisconnectPeerResponse, error) {
+	out := new(DisconnectPeerResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/DisconnectPeer", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ListPeers(ctx context.Context, in *ListPeersRequest, opts ...grpc.CallOption) (*ListPeersResponse, error) {
+	out := new(ListPeersResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ListPeers", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) GetInfo(ctx context.Context, in *GetInfoRequest, opts ...grpc.CallOption) (*GetInfoResponse, error) {
+	out := new(GetInfoResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/GetInfo", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) PendingChannels(ctx context.Context, in *PendingChannelRequest, opts ...grpc.CallOption) (*PendingChannelResponse, error) {
+	out := new(PendingChannelResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/PendingChannels", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ListChannels(ctx context.Context, in *ListChannelsRequest, opts ...grpc.CallOption) (*ListChannelsResponse, error) {
+	out := new(ListChannelsResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ListChannels", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ClosedChannels(ctx context.Context, in *ClosedChannelsRequest, opts ...grpc.CallOption) (*ClosedChannelsResponse, error) {
+	out := new(ClosedChannelsResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ClosedChannels", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) OpenChannelSync(ctx context.Context, in *OpenChannelRequest, opts ...grpc.CallOption) (*ChannelPoint, error) {
+	out := new(ChannelPoint)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/OpenChannelSync", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) OpenChannel(ctx context.Context, in *OpenChannelRequest, opts ...grpc.CallOption) (Lightning_OpenChannelClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[1], c.cc, "/lnrpc.Lightning/OpenChannel", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningOpenChannelClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Lightning_OpenChannelClient interface {
+	Recv() (*OpenStatusUpdate, error)
+	grpc.ClientStream
+}
+
+type lightningOpenChannelClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningOpenChannelClient) Recv() (*OpenStatusUpdate, error) {
+	m := new(OpenStatusUpdate)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lightningClient) CloseChannel(ctx context.Context, in *CloseChannelRequest, opts ...grpc.CallOption) (Lightning_CloseChannelClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[2], c.cc, "/lnrpc.Lightning/CloseChannel", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningCloseChannelClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Lightning_CloseChannelClient interface {
+	Recv() (*CloseStatusUpdate, error)
+	grpc.ClientStream
+}
+
+type lightningCloseChannelClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningCloseChannelClient) Recv() (*CloseStatusUpdate, error) {
+	m := new(CloseStatusUpdate)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lightningClient) SendPayment(ctx context.Context, opts ...grpc.CallOption) (Lightning_SendPaymentClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[3], c.cc, "/lnrpc.Lightning/SendPayment", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningSendPaymentClient{stream}
+	return x, nil
+}
+
+type Lightning_SendPaymentClient interface {
+	Send(*SendRequest) error
+	Recv() (*SendResponse, error)
+	grpc.ClientStream
+}
+
+type lightningSendPaymentClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningSendPaymentClient) Send(m *SendRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *lightningSendPaymentClient) Recv() (*SendResponse, error) {
+	m := new(SendResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lightningClient) SendPaymentSync(ctx context.Context, in *SendRequest, opts ...grpc.CallOption) (*SendResponse, error) {
+	out := new(SendResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/SendPaymentSync", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) AddInvoice(ctx context.Context, in *Invoice, opts ...grpc.CallOption) (*AddInvoiceResponse, error) {
+	out := new(AddInvoiceResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/AddInvoice", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ListInvoices(ctx context.Context, in *ListInvoiceRequest, opts ...grpc.CallOption) (*ListInvoiceResponse, error) {
+	out := new(ListInvoiceResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ListInvoices", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) LookupInvoice(ctx context.Context, in *PaymentHash, opts ...grpc.CallOption) (*Invoice, error) {
+	out := new(Invoice)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/LookupInvoice", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) SubscribeInvoices(ctx context.Context, in *InvoiceSubscription, opts ...grpc.CallOption) (Lightning_SubscribeInvoicesClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[4], c.cc, "/lnrpc.Lightning/SubscribeInvoices", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningSubscribeInvoicesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Lightning_SubscribeInvoicesClient interface {
+	Recv() (*Invoice, error)
+	grpc.ClientStream
+}
+
+type lightningSubscribeInvoicesClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningSubscribeInvoicesClient) Recv() (*Invoice, error) {
+	m := new(Invoice)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lightningClient) SubscribeSettledInvoices(ctx context.Context, opts ...grpc.CallOption) (Lightning_SubscribeSettledInvoicesClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[5], c.cc, "/lnrpc.Lightning/SubscribeSettledInvoices", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningSubscribeSettledInvoicesClient{stream}
+	return x, nil
+}
+
+type Lightning_SubscribeSettledInvoicesClient interface {
+	Send(*SettleEventAck) error
+	Recv() (*Invoice, error)
+	grpc.ClientStream
+}
+
+type lightningSubscribeSettledInvoicesClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningSubscribeSettledInvoicesClient) Send(m *SettleEventAck) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *lightningSubscribeSettledInvoicesClient) Recv() (*Invoice, error) {
+	m := new(Invoice)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lightningClient) DecodePayReq(ctx context.Context, in *PayReqString, opts ...grpc.CallOption) (*PayReq, error) {
+	out := new(PayReq)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/DecodePayReq", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ListPayments(ctx context.Context, in *ListPaymentsRequest, opts ...grpc.CallOption) (*ListPaymentsResponse, error) {
+	out := new(ListPaymentsResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ListPayments", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) DeleteAllPayments(ctx context.Context, in *DeleteAllPaymentsRequest, opts ...grpc.CallOption) (*DeleteAllPaymentsResponse, error) {
+	out := new(DeleteAllPaymentsResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/DeleteAllPayments", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) DescribeGraph(ctx context.Context, in *ChannelGraphRequest, opts ...grpc.CallOption) (*ChannelGraph, error) {
+	out := new(ChannelGraph)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/DescribeGraph", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) GetChanInfo(ctx context.Context, in *ChanInfoRequest, opts ...grpc.CallOption) (*ChannelEdge, error) {
+	out := new(ChannelEdge)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/GetChanInfo", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) GetNodeInfo(ctx context.Context, in *NodeInfoRequest, opts ...grpc.CallOption) (*NodeInfo, error) {
+	out := new(NodeInfo)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/GetNodeInfo", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) QueryRoutes(ctx context.Context, in *QueryRoutesRequest, opts ...grpc.CallOption) (*QueryRoutesResponse, error) {
+	out := new(QueryRoutesResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/QueryRoutes", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) GetNetworkInfo(ctx context.Context, in *NetworkInfoRequest, opts ...grpc.CallOption) (*NetworkInfo, error) {
+	out := new(NetworkInfo)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/GetNetworkInfo", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) StopDaemon(ctx context.Context, in *StopRequest, opts ...grpc.CallOption) (*StopResponse, error) {
+	out := new(StopResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/StopDaemon", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) SubscribeChannelGraph(ctx context.Context, in *GraphTopologySubscription, opts ...grpc.CallOption) (Lightning_SubscribeChannelGraphClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[6], c.cc, "/lnrpc.Lightning/SubscribeChannelGraph", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningSubscribeChannelGraphClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Lightning_SubscribeChannelGraphClient interface {
+	Recv() (*GraphTopologyUpdate, error)
+	grpc.ClientStream
+}
+
+type lightningSubscribeChannelGraphClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningSubscribeChannelGraphClient) Recv() (*GraphTopologyUpdate, error) {
+	m := new(GraphTopologyUpdate)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lightningClient) SetAlias(ctx context.Context, in *SetAliasRequest, opts ...grpc.CallOption) (*SetAliasResponse, error) {
+	out := new(SetAliasResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/SetAlias", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) DebugLevel(ctx context.Context, in *DebugLevelRequest, opts ...grpc.CallOption) (*DebugLevelResponse, error) {
+	out := new(DebugLevelResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/DebugLevel", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) FeeReport(ctx context.Context, in *FeeReportRequest, opts ...grpc.CallOption) (*FeeReportResponse, error) {
+	out := new(FeeReportResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/FeeReport", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) UpdateFees(ctx context.Context, in *FeeUpdateRequest, opts ...grpc.CallOption) (*FeeUpdateResponse, error) {
+	out := new(FeeUpdateResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/UpdateFees", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ListBlacklist(ctx context.Context, in *ListBlacklistRequest, opts ...grpc.CallOption) (*ListBlacklistResponse, error) {
+	out := new(ListBlacklistResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ListBlacklist", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) UpdateBlacklist(ctx context.Context, in *UpdateBlacklistRequest, opts ...grpc.CallOption) (*UpdateBlacklistResponse, error) {
+	out := new(UpdateBlacklistResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/UpdateBlacklist", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) GetCommitmentTxns(ctx context.Context, in *CommitmentTxnsRequest, opts ...grpc.CallOption) (*CommitmentTxnsResponse, error) {
+	out := new(CommitmentTxnsResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/GetCommitmentTxns", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ExportChannelDB(ctx context.Context, in *ExportChannelDBRequest, opts ...grpc.CallOption) (Lightning_ExportChannelDBClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[7], c.cc, "/lnrpc.Lightning/ExportChannelDB", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningExportChannelDBClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Lightning_ExportChannelDBClient interface {
+	Recv() (*ChannelDBChunk, error)
+	grpc.ClientStream
+}
+
+type lightningExportChannelDBClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningExportChannelDBClient) Recv() (*ChannelDBChunk, error) {
+	m := new(ChannelDBChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lightningClient) SendCustomMessage(ctx context.Context, in *SendCustomMessageRequest, opts ...grpc.CallOption) (*SendCustomMessageResponse, error) {
+	out := new(SendCustomMessageResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/SendCustomMessage", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) SubscribeCustomMessages(ctx context.Context, in *SubscribeCustomMessagesRequest, opts ...grpc.CallOption) (Lightning_SubscribeCustomMessagesClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[8], c.cc, "/lnrpc.Lightning/SubscribeCustomMessages", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningSubscribeCustomMessagesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Lightning_SubscribeCustomMessagesClient interface {
+	Recv() (*CustomMessage, error)
+	grpc.ClientStream
+}
+
+type lightningSubscribeCustomMessagesClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningSubscribeCustomMessagesClient) Recv() (*CustomMessage, error) {
+	m := new(CustomMessage)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lightningClient) QueryMissionControl(ctx context.Context, in *QueryMissionControlRequest, opts ...grpc.CallOption) (*QueryMissionControlResponse, error) {
+	out := new(QueryMissionControlResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/QueryMissionControl", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ResetMissionControl(ctx context.Context, in *ResetMissionControlRequest, opts ...grpc.CallOption) (*ResetMissionControlResponse, error) {
+	out := new(ResetMissionControlResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ResetMissionControl", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) XImportMissionControl(ctx context.Context, in *XImportMissionControlRequest, opts ...grpc.CallOption) (*XImportMissionControlResponse, error) {
+	out := new(XImportMissionControlResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/XImportMissionControl", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) BuildRoute(ctx context.Context, in *BuildRouteRequest, opts ...grpc.CallOption) (*BuildRouteResponse, error) {
+	out := new(BuildRouteResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/BuildRoute", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) SendToRouteSync(ctx context.Context, in *SendToRouteRequest, opts ...grpc.CallOption) (*SendToRouteResponse, error) {
+	out := new(SendToRouteResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/SendToRouteSync", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) TrackPayment(ctx context.Context, in *PaymentHash, opts ...grpc.CallOption) (Lightning_TrackPaymentClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[9], c.cc, "/lnrpc.Lightning/TrackPayment", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningTrackPaymentClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Lightning_TrackPaymentClient interface {
+	Recv() (*Payment, error)
+	grpc.ClientStream
+}
+
+type lightningTrackPaymentClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningTrackPaymentClient) Recv() (*Payment, error) {
+	m := new(Payment)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lightningClient) QueryProbability(ctx context.Context, in *QueryProbabilityRequest, opts ...grpc.CallOption) (*QueryProbabilityResponse, error) {
+	out := new(QueryProbabilityResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/QueryProbability", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) UpdateChannelPolicy(ctx context.Context, in *PolicyUpdateRequest, opts ...grpc.CallOption) (*PolicyUpdateResponse, error) {
+	out := new(PolicyUpdateResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/UpdateChannelPolicy", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) HtlcInterceptor(ctx context.Context, opts ...grpc.CallOption) (Lightning_HtlcInterceptorClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[10], c.cc, "/lnrpc.Lightning/HtlcInterceptor", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningHtlcInterceptorClient{stream}
+	return x, nil
+}
+
+type Lightning_HtlcInterceptorClient interface {
+	Send(*ForwardHtlcInterceptResponse) error
+	Recv() (*ForwardHtlcInterceptRequest, error)
+	grpc.ClientStream
+}
+
+type lightningHtlcInterceptorClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningHtlcInterceptorClient) Send(m *ForwardHtlcInterceptResponse) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *lightningHtlcInterceptorClient) Recv() (*ForwardHtlcInterceptRequest, error) {
+	m := new(ForwardHtlcInterceptRequest)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
+func (c *lightningClient) SettleInvoice(ctx context.Context, in *SettleInvoiceMsg, opts ...grpc.CallOption) (*SettleInvoiceResp, error) {
+	out := new(SettleInvoiceResp)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/SettleInvoice", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+func (c *lightningClient) CancelInvoice(ctx context.Context, in *CancelInvoiceMsg, opts ...grpc.CallOption) (*CancelInvoiceResp, error) {
+	out := new(CancelInvoiceResp)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/CancelInvoice", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
 
-// Client API for Lightning service
+func (c *lightningClient) SubscribeHtlcEvents(ctx context.Context, in *SubscribeHtlcEventsRequest, opts ...grpc.CallOption) (Lightning_SubscribeHtlcEventsClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[11], c.cc, "/lnrpc.Lightning/SubscribeHtlcEvents", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningSubscribeHtlcEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
 
-type LightningClient interface {
+type Lightning_SubscribeHtlcEventsClient interface {
+	Recv() (*HtlcEvent, error)
+	grpc.ClientStream
+}
+
+type lightningSubscribeHtlcEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningSubscribeHtlcEventsClient) Recv() (*HtlcEvent, error) {
+	m := new(HtlcEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lightningClient) PendingSweeps(ctx context.Context, in *PendingSweepsRequest, opts ...grpc.CallOption) (*PendingSweepsResponse, error) {
+	out := new(PendingSweepsResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/PendingSweeps", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) BumpFee(ctx context.Context, in *BumpFeeRequest, opts ...grpc.CallOption) (*BumpFeeResponse, error) {
+	out := new(BumpFeeResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/BumpFee", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) RemoveSettleConsumer(ctx context.Context, in *RemoveSettleConsumerRequest, opts ...grpc.CallOption) (*RemoveSettleConsumerResponse, error) {
+	out := new(RemoveSettleConsumerResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/RemoveSettleConsumer", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Lightning service
+
+type LightningServer interface {
 	// * lncli: `walletbalance`
 	// WalletBalance returns the sum of all confirmed unspent outputs under control
 	// by the wallet. This method can be modified by having the request specify
 	// only witness outputs should be factored into the final output sum.
-	WalletBalance(ctx context.Context, in *WalletBalanceRequest, opts ...grpc.CallOption) (*WalletBalanceResponse, error)
+	WalletBalance(context.Context, *WalletBalanceRequest) (*WalletBalanceResponse, error)
 	// * lncli: `channelbalance`
 	// ChannelBalance returns the total funds available across all open channels
 	// in satoshis.
-	ChannelBalance(ctx context.Context, in *ChannelBalanceRequest, opts ...grpc.CallOption) (*ChannelBalanceResponse, error)
+	ChannelBalance(context.Context, *ChannelBalanceRequest) (*ChannelBalanceResponse, error)
 	// * lncli: `listchaintxns`
 	// GetTransactions returns a list describing all the known transactions
 	// relevant to the wallet.
-	GetTransactions(ctx context.Context, in *GetTransactionsRequest, opts ...grpc.CallOption) (*TransactionDetails, error)
+	GetTransactions(context.Context, *GetTransactionsRequest) (*TransactionDetails, error)
 	// * lncli: `sendcoins`
 	// SendCoins executes a request to send coins to a particular address. Unlike
 	// SendMany, this RPC call only allows creating a single output at a time.
-	SendCoins(ctx context.Context, in *SendCoinsRequest, opts ...grpc.CallOption) (*SendCoinsResponse, error)
+	SendCoins(context.Context, *SendCoinsRequest) (*SendCoinsResponse, error)
 	// *
 	// SubscribeTransactions creates a uni-directional stream from the server to
 	// the client in which any newly discovered transactions relevant to the
 	// wallet are sent over.
-	SubscribeTransactions(ctx context.Context, in *GetTransactionsRequest, opts ...grpc.CallOption) (Lightning_SubscribeTransactionsClient, error)
+	SubscribeTransactions(*GetTransactionsRequest, Lightning_SubscribeTransactionsServer) error
 	// * lncli: `sendmany`
 	// SendMany handles a request for a transaction that creates multiple specified
 	// outputs in parallel.
-	SendMany(ctx context.Context, in *SendManyRequest, opts ...grpc.CallOption) (*SendManyResponse, error)
+	SendMany(context.Context, *SendManyRequest) (*SendManyResponse, error)
 	// * lncli: `newaddress`
 	// NewAddress creates a new address under control of the local wallet.
-	NewAddress(ctx context.Context, in *NewAddressRequest, opts ...grpc.CallOption) (*NewAddressResponse, error)
+	NewAddress(context.Context, *NewAddressRequest) (*NewAddressResponse, error)
 	// *
 	// NewWitnessAddress creates a new witness address under control of the local wallet.
-	NewWitnessAddress(ctx context.Context, in *NewWitnessAddressRequest, opts ...grpc.CallOption) (*NewAddressResponse, error)
+	NewWitnessAddress(context.Context, *NewWitnessAddressRequest) (*NewAddressResponse, error)
 	// * lncli: `signmessage`
 	// SignMessage signs a message with this node's private key. The returned
 	// signature string is `zbase32` encoded and pubkey recoverable, meaning that
 	// only the message digest and signature are needed for verification.
-	SignMessage(ctx context.Context, in *SignMessageRequest, opts ...grpc.CallOption) (*SignMessageResponse, error)
+	SignMessage(context.Context, *SignMessageRequest) (*SignMessageResponse, error)
 	// * lncli: `verifymessage`
 	// VerifyMessage verifies a signature over a msg. The signature must be
 	// zbase32 encoded and signed by an active node in the resident node's
 	// channel database. In addition to returning the validity of the signature,
 	// VerifyMessage also returns the recovered pubkey from the signature.
-	VerifyMessage(ctx context.Context, in *VerifyMessageRequest, opts ...grpc.CallOption) (*VerifyMessageResponse, error)
+	VerifyMessage(context.Context, *VerifyMessageRequest) (*VerifyMessageResponse, error)
 	// * lncli: `connect`
 	// ConnectPeer attempts to establish a connection to a remote peer. This is at
 	// the networking level, and is used for communication between nodes. This is
 	// distinct from establishing a channel with a peer.
-	ConnectPeer(ctx context.Context, in *ConnectPeerRequest, opts ...grpc.CallOption) (*ConnectPeerResponse, error)
+	ConnectPeer(context.Context, *ConnectPeerRequest) (*ConnectPeerResponse, error)
 	// * lncli: `disconnect`
 	// DisconnectPeer attempts to disconnect one peer from another identified by a
 	// given pubKey. In the case that we currently have a pending or active channel
 	// with the target peer, then this action will be not be allowed.
-	DisconnectPeer(ctx context.Context, in *DisconnectPeerRequest, opts ...grpc.CallOption) (*DisconnectPeerResponse, error)
+	DisconnectPeer(context.Context, *DisconnectPeerRequest) (*DisconnectPeerResponse, error)
 	// * lncli: `listpeers`
 	// ListPeers returns a verbose listing of all currently active peers.
-	ListPeers(ctx context.Context, in *ListPeersRequest, opts ...grpc.CallOption) (*ListPeersResponse, error)
+	ListPeers(context.Context, *ListPeersRequest) (*ListPeersResponse, error)
 	// * lncli: `getinfo`
 	// GetInfo returns general information concerning the lightning node including
 	// it's identity pubkey, alias, the chains it is connected to, and information
 	// concerning the number of open+pending channels.
-	GetInfo(ctx context.Context, in *GetInfoRequest, opts ...grpc.CallOption) (*GetInfoResponse, error)
+	GetInfo(context.Context, *GetInfoRequest) (*GetInfoResponse, error)
 	// * lncli: `pendingchannels`
 	// PendingChannels returns a list of all the channels that are currently
 	// considered "pending". A channel is pending if it has finished the funding
 	// workflow and is waiting for confirmations for the funding txn, or is in the
 	// process of closure, either initiated cooperatively or non-cooperatively.
-	PendingChannels(ctx context.Context, in *PendingChannelRequest, opts ...grpc.CallOption) (*PendingChannelResponse, error)
+	PendingChannels(context.Context, *PendingChannelRequest) (*PendingChannelResponse, error)
 	// * lncli: `listchannels`
 	// ListChannels returns a description of all the open channels that this node
 	// is a participant in.
-	ListChannels(ctx context.Context, in *ListChannelsRequest, opts ...grpc.CallOption) (*ListChannelsResponse, error)
+	ListChannels(context.Context, *ListChannelsRequest) (*ListChannelsResponse, error)
+	// * lncli: `closedchannels`
+	// ClosedChannels returns a description of all the closed channels that
+	// this node was a participant in, optionally filtered by the manner in which
+	// each channel was closed.
+	ClosedChannels(context.Context, *ClosedChannelsRequest) (*ClosedChannelsResponse, error)
 	// *
 	// OpenChannelSync is a synchronous version of the OpenChannel RPC call. This
 	// call is meant to be consumed by clients to the REST proxy. As with all
 	// other sync calls, all byte slices are intended to be populated as hex
 	// encoded strings.
-	OpenChannelSync(ctx context.Context, in *OpenChannelRequest, opts ...grpc.CallOption) (*ChannelPoint, error)
+	OpenChannelSync(context.Context, *OpenChannelRequest) (*ChannelPoint, error)
 	// * lncli: `openchannel`
 	// OpenChannel attempts to open a singly funded channel specified in the
 	// request to a remote peer.
-	OpenChannel(ctx context.Context, in *OpenChannelRequest, opts ...grpc.CallOption) (Lightning_OpenChannelClient, error)
+	OpenChannel(*OpenChannelRequest, Lightning_OpenChannelServer) error
 	// * lncli: `closechannel`
 	// CloseChannel attempts to close an active channel identified by its channel
 	// outpoint (ChannelPoint). The actions of this method can additionally be
 	// augmented to attempt a force close after a timeout period in the case of an
 	// inactive peer.
-	CloseChannel(ctx context.Context, in *CloseChannelRequest, opts ...grpc.CallOption) (Lightning_CloseChannelClient, error)
+	CloseChannel(*CloseChannelRequest, Lightning_CloseChannelServer) error
 	// * lncli: `sendpayment`
 	// SendPayment dispatches a bi-directional streaming RPC for sending payments
 	// through the Lightning Network. A single RPC invocation creates a persistent
 	// bi-directional stream allowing clients to rapidly send payments through the
 	// Lightning Network with a single persistent connection.
-	SendPayment(ctx context.Context, opts ...grpc.CallOption) (Lightning_SendPaymentClient, error)
+	SendPayment(Lightning_SendPaymentServer) error
 	// *
 	// SendPaymentSync is the synchronous non-streaming version of SendPayment.
 	// This RPC is intended to be consumed by clients of the REST proxy.
 	// Additionally, this RPC expects the destination's public key and the payment
 	// hash (if any) to be encoded as hex strings.
-	SendPaymentSync(ctx context.Context, in *SendRequest, opts ...grpc.CallOption) (*SendResponse, error)
+	SendPaymentSync(context.Context, *SendRequest) (*SendResponse, error)
 	// * lncli: `addinvoice`
 	// AddInvoice attempts to add a new invoice to the invoice database. Any
 	// duplicated invoices are rejected, therefore all invoices *must* have a
 	// unique payment preimage.
-	AddInvoice(ctx context.Context, in *Invoice, opts ...grpc.CallOption) (*AddInvoiceResponse, error)
+	AddInvoice(context.Context, *Invoice) (*AddInvoiceResponse, error)
 	// * lncli: `listinvoices`
 	// ListInvoices returns a list of all the invoices currently stored within the
 	// database. Any active debug invoices are ignored.
-	ListInvoices(ctx context.Context, in *ListInvoiceRequest, opts ...grpc.CallOption) (*ListInvoiceResponse, error)
+	ListInvoices(context.Context, *ListInvoiceRequest) (*ListInvoiceResponse, error)
 	// * lncli: `lookupinvoice`
 	// LookupInvoice attemps to look up an invoice according to its payment hash.
 	// The passed payment hash *must* be exactly 32 bytes, if not, an error is
 	// returned.
-	LookupInvoice(ctx context.Context, in *PaymentHash, opts ...grpc.CallOption) (*Invoice, error)
+	LookupInvoice(context.Context, *PaymentHash) (*Invoice, error)
 	// *
 	// SubscribeInvoices returns a uni-directional stream (sever -> client) for
-	// notifying the client of newly added/settled invoices.
-	SubscribeInvoices(ctx context.Context, in *InvoiceSubscription, opts ...grpc.CallOption) (Lightning_SubscribeInvoicesClient, error)
+	// notifying the client of newly added/settled invoices. The caller can
+	// optionally specify the add_index and/or the settle_index. If specified,
+	// then we'll first start by sending add invoice events for all invoices with
+	// an add_index greater than the specified value. If the settle_index is
+	// specified, the next we'll send out all settle events for invoices with a
+	// settle_index greater than the specified value. One or both of these fields
+	// can be set. If no fields are set, then we'll only send out the latest add
+	// and settle events.
+	SubscribeInvoices(*InvoiceSubscription, Lightning_SubscribeInvoicesServer) error
+	// *
+	// SubscribeSettledInvoices dispatches a bi-directional streaming RPC which
+	// delivers invoice settlement events to a durable consumer with
+	// at-least-once semantics. The first message sent by the client must
+	// specify the consumer_id, registering the consumer if it's new. The server
+	// will then send every invoice settled since the latest event acknowledged
+	// by the consumer, followed by each newly settled invoice. The client
+	// acknowledges events by sending their settle_index. Any events that
+	// haven't been acknowledged will be re-delivered once the consumer
+	// reconnects, even across restarts of the daemon. The number of consumers
+	// is bounded, and consumers which remain disconnected for too long are
+	// removed.
+	SubscribeSettledInvoices(Lightning_SubscribeSettledInvoicesServer) error
 	// * lncli: `decodepayreq`
 	// DecodePayReq takes an encoded payment request string and attempts to decode
 	// it, returning a full description of the conditions encoded within the
 	// payment request.
-	DecodePayReq(ctx context.Context, in *PayReqString, opts ...grpc.CallOption) (*PayReq, error)
+	DecodePayReq(context.Context, *PayReqString) (*PayReq, error)
 	// * lncli: `listpayments`
 	// ListPayments returns a list of all outgoing payments.
-	ListPayments(ctx context.Context, in *ListPaymentsRequest, opts ...grpc.CallOption) (*ListPaymentsResponse, error)
+	ListPayments(context.Context, *ListPaymentsRequest) (*ListPaymentsResponse, error)
 	// *
 	// DeleteAllPayments deletes all outgoing payments from DB.
-	DeleteAllPayments(ctx context.Context, in *DeleteAllPaymentsRequest, opts ...grpc.CallOption) (*DeleteAllPaymentsResponse, error)
+	DeleteAllPayments(context.Context, *DeleteAllPaymentsRequest) (*DeleteAllPaymentsResponse, error)
 	// * lncli: `describegraph`
 	// DescribeGraph returns a description of the latest graph state from the
 	// point of view of the node. The graph information is partitioned into two
@@ -3537,32 +7532,32 @@ type LightningClient interface {
 	// vertexes themselves.  As this is a directed graph, the edges also contain
 	// the node directional specific routing policy which includes: the time lock
 	// delta, fee information, etc.
-	DescribeGraph(ctx context.Context, in *ChannelGraphRequest, opts ...grpc.CallOption) (*ChannelGraph, error)
+	DescribeGraph(context.Context, *ChannelGraphRequest) (*ChannelGraph, error)
 	// * lncli: `getchaninfo`
 	// GetChanInfo returns the latest authenticated network announcement for the
 	// given channel identified by its channel ID: an 8-byte integer which
 	// uniquely identifies the location of transaction's funding output within the
 	// blockchain.
-	GetChanInfo(ctx context.Context, in *ChanInfoRequest, opts ...grpc.CallOption) (*ChannelEdge, error)
+	GetChanInfo(context.Context, *ChanInfoRequest) (*ChannelEdge, error)
 	// * lncli: `getnodeinfo`
 	// GetNodeInfo returns the latest advertised, aggregated, and authenticated
 	// channel information for the specified node identified by its public key.
-	GetNodeInfo(ctx context.Context, in *NodeInfoRequest, opts ...grpc.CallOption) (*NodeInfo, error)
+	GetNodeInfo(context.Context, *NodeInfoRequest) (*NodeInfo, error)
 	// * lncli: `queryroutes`
 	// QueryRoutes attempts to query the daemon's Channel Router for a possible
 	// route to a target destination capable of carrying a specific amount of
 	// satoshis. The retuned route contains the full details required to craft and
 	// send an HTLC, also including the necessary information that should be
 	// present within the Sphinx packet encapsualted within the HTLC.
-	QueryRoutes(ctx context.Context, in *QueryRoutesRequest, opts ...grpc.CallOption) (*QueryRoutesResponse, error)
+	QueryRoutes(context.Context, *QueryRoutesRequest) (*QueryRoutesResponse, error)
 	// * lncli: `getnetworkinfo`
 	// GetNetworkInfo returns some basic stats about the known channel graph from
 	// the point of view of the node.
-	GetNetworkInfo(ctx context.Context, in *NetworkInfoRequest, opts ...grpc.CallOption) (*NetworkInfo, error)
+	GetNetworkInfo(context.Context, *NetworkInfoRequest) (*NetworkInfo, error)
 	// * lncli: `stop`
 	// StopDaemon will send a shutdown request to the interrupt handler, triggering
 	// a graceful shutdown of the daemon.
-	StopDaemon(ctx context.Context, in *StopRequest, opts ...grpc.CallOption) (*StopResponse, error)
+	StopDaemon(context.Context, *StopRequest) (*StopResponse, error)
 	// *
 	// SubscribeChannelGraph launches a streaming RPC that allows the caller to
 	// receive notifications upon any changes to the channel graph topology from
@@ -3570,1438 +7565,1322 @@ type LightningClient interface {
 	// nodes coming online, nodes updating their authenticated attributes, new
 	// channels being advertised, updates in the routing policy for a directional
 	// channel edge, and when channels are closed on-chain.
-	SubscribeChannelGraph(ctx context.Context, in *GraphTopologySubscription, opts ...grpc.CallOption) (Lightning_SubscribeChannelGraphClient, error)
+	SubscribeChannelGraph(*GraphTopologySubscription, Lightning_SubscribeChannelGraphServer) error
 	// *
 	// SetAlias sets the alias for this node; e.g. "alice"
-	SetAlias(ctx context.Context, in *SetAliasRequest, opts ...grpc.CallOption) (*SetAliasResponse, error)
-	// * lncli: `debuglevel`
-	// DebugLevel allows a caller to programmatically set the logging verbosity of
-	// lnd. The logging can be targeted according to a coarse daemon-wide logging
-	// level, or in a granular fashion to specify the logging for a target
-	// sub-system.
-	DebugLevel(ctx context.Context, in *DebugLevelRequest, opts ...grpc.CallOption) (*DebugLevelResponse, error)
-	// * lncli: `feereport`
-	// FeeReport allows the caller to obtain a report detailing the current fee
-	// schedule enforced by the node globally for each channel.
-	FeeReport(ctx context.Context, in *FeeReportRequest, opts ...grpc.CallOption) (*FeeReportResponse, error)
-	// * lncli: `updatefees`
-	// UpdateFees allows the caller to update the fee schedule for all channels
-	// globally, or a particular channel.
-	UpdateFees(ctx context.Context, in *FeeUpdateRequest, opts ...grpc.CallOption) (*FeeUpdateResponse, error)
-}
-
-type lightningClient struct {
-	cc *grpc.ClientConn
-}
-
-func NewLightningClient(cc *grpc.ClientConn) LightningClient {
-	return &lightningClient{cc}
-}
-
-func (c *lightningClient) WalletBalance(ctx context.Context, in *WalletBalanceRequest, opts ...grpc.CallOption) (*WalletBalanceResponse, error) {
-	out := new(WalletBalanceResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/WalletBalance", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
-}
-
-func (c *lightningClient) ChannelBalance(ctx context.Context, in *ChannelBalanceRequest, opts ...grpc.CallOption) (*ChannelBalanceResponse, error) {
-	out := new(ChannelBalanceResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ChannelBalance", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
-}
-
-func (c *lightningClient) GetTransactions(ctx context.Context, in *GetTransactionsRequest, opts ...grpc.CallOption) (*TransactionDetails, error) {
-	out := new(TransactionDetails)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/GetTransactions", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
-}
-
-func (c *lightningClient) SendCoins(ctx context.Context, in *SendCoinsRequest, opts ...grpc.CallOption) (*SendCoinsResponse, error) {
-	out := new(SendCoinsResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/SendCoins", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
-}
-
-func (c *lightningClient) SubscribeTransactions(ctx context.Context, in *GetTransactionsRequest, opts ...grpc.CallOption) (Lightning_SubscribeTransactionsClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[0], c.cc, "/lnrpc.Lightning/SubscribeTransactions", opts...)
-	if err != nil {
-		return nil, err
-	}
-	x := &lightningSubscribeTransactionsClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
-	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
-	}
-	return x, nil
-}
-
-type Lightning_SubscribeTransactionsClient interface {
-	Recv() (*Transaction, error)
-	grpc.ClientStream
-}
-
-type lightningSubscribeTransactionsClient struct {
-	grpc.ClientStream
-}
-
-func (x *lightningSubscribeTransactionsClient) Recv() (*Transaction, error) {
-	m := new(Transaction)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
-	}
-	return m, nil
-}
-
-func (c *lightningClient) SendMany(ctx context.Context, in *SendManyRequest, opts ...grpc.CallOption) (*SendManyResponse, error) {
-	out := new(SendManyResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/SendMany", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
-}
-
-func (c *lightningClient) NewAddress(ctx context.Context, in *NewAddressRequest, opts ...grpc.CallOption) (*NewAddressResponse, error) {
-	out := new(NewAddressResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/NewAddress", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
-}
-
-func (c *lightningClient) NewWitnessAddress(ctx context.Context, in *NewWitnessAddressRequest, opts ...grpc.CallOption) (*NewAddressResponse, error) {
-	out := new(NewAddressResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/NewWitnessAddress", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+	SetAlias(context.Context, *SetAliasRequest) (*SetAliasResponse, error)
+	// * lncli: `debuglevel`
+	// DebugLevel allows a caller to programmatically set the logging verbosity of
+	// lnd. The logging can be targeted according to a coarse daemon-wide logging
+	// level, or in a granular fashion to specify the logging for a target
+	// sub-system.
+	DebugLevel(context.Context, *DebugLevelRequest) (*DebugLevelResponse, error)
+	// * lncli: `feereport`
+	// FeeReport allows the caller to obtain a report detailing the current fee
+	// schedule enforced by the node globally for each channel.
+	FeeReport(context.Context, *FeeReportRequest) (*FeeReportResponse, error)
+	// * lncli: `updatefees`
+	// UpdateFees allows the caller to update the fee schedule for all channels
+	// globally, or a particular channel.
+	UpdateFees(context.Context, *FeeUpdateRequest) (*FeeUpdateResponse, error)
+	// * lncli: `listblacklist`
+	// ListBlacklist returns the set of nodes which are currently blacklisted, and
+	// will therefore never be used as an intermediate hop when routing payments.
+	ListBlacklist(context.Context, *ListBlacklistRequest) (*ListBlacklistResponse, error)
+	// * lncli: `updateblacklist`
+	// UpdateBlacklist allows the caller to add nodes to, or remove nodes from,
+	// the persistent node blacklist consulted during path finding.
+	UpdateBlacklist(context.Context, *UpdateBlacklistRequest) (*UpdateBlacklistResponse, error)
+	// * lncli: `getcommitmenttxns`
+	// GetCommitmentTxns returns our current fully signed commitment transaction
+	// for the target channel, along with the fully signed second-level HTLC
+	// timeout transactions which spend from it. These are exactly the
+	// transactions that would be broadcast were the channel to be force closed.
+	GetCommitmentTxns(context.Context, *CommitmentTxnsRequest) (*CommitmentTxnsResponse, error)
+	// * lncli: `exportchanneldb`
+	// ExportChannelDB streams a consistent snapshot of the channel database,
+	// taken while the daemon is running. The concatenated chunks form a complete
+	// database file, which can be restored in place of channel.db while the
+	// daemon is stopped.
+	ExportChannelDB(*ExportChannelDBRequest, Lightning_ExportChannelDBServer) error
+	// * lncli: `sendcustom`
+	// SendCustomMessage sends a custom peer message to a connected peer. The
+	// message type must lie within the custom type range, starting at 32768, and
+	// its data is sent as is, without being interpreted by the daemon.
+	SendCustomMessage(context.Context, *SendCustomMessageRequest) (*SendCustomMessageResponse, error)
+	// * lncli: `subscribecustom`
+	// SubscribeCustomMessages returns a uni-directional stream (server -> client)
+	// of the custom peer messages received from any connected peer, allowing
+	// applications to build their own protocols on top of the daemon's peer
+	// connections.
+	SubscribeCustomMessages(*SubscribeCustomMessagesRequest, Lightning_SubscribeCustomMessagesServer) error
+	// * lncli: `querymc`
+	// QueryMissionControl returns the router's mission control history, which
+	// records the outcome of past payment attempts for each node and directed
+	// pair of nodes, and is used to steer path finding away from hops that
+	// recently caused payments to fail.
+	QueryMissionControl(context.Context, *QueryMissionControlRequest) (*QueryMissionControlResponse, error)
+	// * lncli: `resetmc`
+	// ResetMissionControl wipes the router's mission control history, lifting
+	// all penalties resulting from past payment failures.
+	ResetMissionControl(context.Context, *ResetMissionControlRequest) (*ResetMissionControlResponse, error)
+	// *
+	// XImportMissionControl is an experimental API that seeds the router's
+	// mission control history with the passed entries, typically obtained from
+	// another node using QueryMissionControl. Imported observations only replace
+	// our own if they're more recent.
+	XImportMissionControl(context.Context, *XImportMissionControlRequest) (*XImportMissionControlResponse, error)
+	// * lncli: `buildroute`
+	// BuildRoute constructs a fully specified route traversing the passed hops
+	// in order, starting from our own node. Between each pair of hops, the
+	// cheapest channel within the local graph able to carry the amount is
+	// selected, and the fees and time locks are filled in according to its
+	// policy. This allows applications to implement their own routing and
+	// rebalancing strategies.
+	BuildRoute(context.Context, *BuildRouteRequest) (*BuildRouteResponse, error)
+	// * lncli: `sendtoroute`
+	// SendToRouteSync attempts to send a payment over the passed route, typically
+	// constructed using BuildRoute. Only a single attempt is made, which is
+	// recorded within the payments database like any other payment. If the
+	// payment fails, then the response identifies the node along the route that
+	// caused the failure, along with the failure message it sent.
+	// If no payment hash is specified, then the route must lead back to our own
+	// node. The payment is then settled by our own node without an invoice,
+	// which allows the balance of our channels to be shifted between them.
+	SendToRouteSync(context.Context, *SendToRouteRequest) (*SendToRouteResponse, error)
+	// * lncli: `trackpayment`
+	// TrackPayment returns a uni-directional stream (server -> client) of the
+	// state of the payment with the passed payment hash. The current state of the
+	// payment is sent first, followed by its new state each time it changes: when
+	// an HTLC attempt is added or resolved, and finally once the payment either
+	// succeeds or fails, after which the stream is closed.
+	TrackPayment(*PaymentHash, Lightning_TrackPaymentServer) error
+	// * lncli: `queryprob`
+	// QueryProbability returns the router's current estimate of the probability
+	// of a payment of the passed amount being successfully forwarded from one
+	// node to another, along with the mission control history the estimate is
+	// based on. This allows external applications, such as rebalancers, to
+	// weigh candidate routes in the same way path finding does.
+	QueryProbability(context.Context, *QueryProbabilityRequest) (*QueryProbabilityResponse, error)
+	// * lncli: `updatechanpolicy`
+	// UpdateChannelPolicy allows the caller to update the forwarding policy of
+	// all channels globally, or a particular channel. Besides the fee schedule,
+	// the time lock delta and HTLC limits of the channels may be updated. The
+	// new policy is applied to the forwarding links of the channels immediately,
+	// and fresh channel updates are broadcast to the network.
+	UpdateChannelPolicy(context.Context, *PolicyUpdateRequest) (*PolicyUpdateResponse, error)
+	// *
+	// HtlcInterceptor dispatches a bi-directional streaming RPC which allows the
+	// caller to intercept the HTLCs forwarded by the daemon. Each HTLC about to
+	// be forwarded is sent over the stream and held until the client resolves
+	// it, by either resuming its forwarding, failing it back, or settling it
+	// with a preimage of its own. HTLCs which aren't resolved within the
+	// configured timeout are failed back. Only a single interceptor may be
+	// active at a time, and any HTLCs still held once the stream is closed are
+	// resumed.
+	HtlcInterceptor(Lightning_HtlcInterceptorServer) error
+	// * lncli: `settleinvoice`
+	// SettleInvoice settles an accepted hold invoice using the given preimage,
+	// which must match the payment hash of the invoice. The HTLC held for the
+	// invoice is settled, revealing the preimage to the payer.
+	SettleInvoice(context.Context, *SettleInvoiceMsg) (*SettleInvoiceResp, error)
+	// * lncli: `cancelinvoice`
+	// CancelInvoice cancels an invoice which hasn't been settled, after which it
+	// can no longer be paid. Should an HTLC be held for a hold invoice, it's
+	// failed back to the payer.
+	CancelInvoice(context.Context, *CancelInvoiceMsg) (*CancelInvoiceResp, error)
+	// * lncli: `subscribehtlcevents`
+	// SubscribeHtlcEvents returns a uni-directional stream (server -> client) of
+	// the HTLC events of the switch, allowing operators to monitor the routing
+	// activity of the daemon in real time. An event is sent each time an HTLC is
+	// forwarded, fails to be forwarded, is failed by a channel link, or is
+	// resolved once settled or failed downstream.
+	SubscribeHtlcEvents(*SubscribeHtlcEventsRequest, Lightning_SubscribeHtlcEventsServer) error
+	// * lncli: `pendingsweeps`
+	// PendingSweeps returns a list of all the outputs that are currently being
+	// swept back into the wallet by the sweeper, along with the number of
+	// broadcast attempts made so far, and the fee rate at which they would be
+	// swept next.
+	PendingSweeps(context.Context, *PendingSweepsRequest) (*PendingSweepsResponse, error)
+	// * lncli: `bumpfee`
+	// BumpFee bumps the fee rate of an output which is currently being swept.
+	// The output is swept immediately at no less than the requested fee rate,
+	// batched with any other outputs due to be swept at a similar fee rate.
+	BumpFee(context.Context, *BumpFeeRequest) (*BumpFeeResponse, error)
+	// *
+	// RemoveSettleConsumer removes a durable consumer of invoice settlement
+	// events registered through SubscribeSettledInvoices, such that no further
+	// events are retained for it. The consumer must not be connected.
+	RemoveSettleConsumer(context.Context, *RemoveSettleConsumerRequest) (*RemoveSettleConsumerResponse, error)
 }
 
-func (c *lightningClient) SignMessage(ctx context.Context, in *SignMessageRequest, opts ...grpc.CallOption) (*SignMessageResponse, error) {
-	out := new(SignMessageResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/SignMessage", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+func RegisterLightningServer(s *grpc.Server, srv LightningServer) {
+	s.RegisterService(&_Lightning_serviceDesc, srv)
 }
 
-func (c *lightningClient) VerifyMessage(ctx context.Context, in *VerifyMessageRequest, opts ...grpc.CallOption) (*VerifyMessageResponse, error) {
-	out := new(VerifyMessageResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/VerifyMessage", in, out, c.cc, opts...)
-	if err != nil {
+func _Lightning_WalletBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WalletBalanceRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
-}
-
-func (c *lightningClient) ConnectPeer(ctx context.Context, in *ConnectPeerRequest, opts ...grpc.CallOption) (*ConnectPeerResponse, error) {
-	out := new(ConnectPeerResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ConnectPeer", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+	if interceptor == nil {
+		return srv.(LightningServer).WalletBalance(ctx, in)
 	}
-	return out, nil
-}
-
-func (c *lightningClient) DisconnectPeer(ctx context.Context, in *DisconnectPeerRequest, opts ...grpc.CallOption) (*DisconnectPeerResponse, error) {
-	out := new(DisconnectPeerResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/DisconnectPeer", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/WalletBalance",
 	}
-	return out, nil
-}
-
-func (c *lightningClient) ListPeers(ctx context.Context, in *ListPeersRequest, opts ...grpc.CallOption) (*ListPeersResponse, error) {
-	out := new(ListPeersResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ListPeers", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).WalletBalance(ctx, req.(*WalletBalanceRequest))
 	}
-	return out, nil
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *lightningClient) GetInfo(ctx context.Context, in *GetInfoRequest, opts ...grpc.CallOption) (*GetInfoResponse, error) {
-	out := new(GetInfoResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/GetInfo", in, out, c.cc, opts...)
-	if err != nil {
+func _Lightning_ChannelBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChannelBalanceRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
-}
-
-func (c *lightningClient) PendingChannels(ctx context.Context, in *PendingChannelRequest, opts ...grpc.CallOption) (*PendingChannelResponse, error) {
-	out := new(PendingChannelResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/PendingChannels", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+	if interceptor == nil {
+		return srv.(LightningServer).ChannelBalance(ctx, in)
 	}
-	return out, nil
-}
-
-func (c *lightningClient) ListChannels(ctx context.Context, in *ListChannelsRequest, opts ...grpc.CallOption) (*ListChannelsResponse, error) {
-	out := new(ListChannelsResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ListChannels", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ChannelBalance",
 	}
-	return out, nil
-}
-
-func (c *lightningClient) OpenChannelSync(ctx context.Context, in *OpenChannelRequest, opts ...grpc.CallOption) (*ChannelPoint, error) {
-	out := new(ChannelPoint)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/OpenChannelSync", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ChannelBalance(ctx, req.(*ChannelBalanceRequest))
 	}
-	return out, nil
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *lightningClient) OpenChannel(ctx context.Context, in *OpenChannelRequest, opts ...grpc.CallOption) (Lightning_OpenChannelClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[1], c.cc, "/lnrpc.Lightning/OpenChannel", opts...)
-	if err != nil {
+func _Lightning_GetTransactions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTransactionsRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	x := &lightningOpenChannelClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
+	if interceptor == nil {
+		return srv.(LightningServer).GetTransactions(ctx, in)
 	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/GetTransactions",
 	}
-	return x, nil
-}
-
-type Lightning_OpenChannelClient interface {
-	Recv() (*OpenStatusUpdate, error)
-	grpc.ClientStream
-}
-
-type lightningOpenChannelClient struct {
-	grpc.ClientStream
-}
-
-func (x *lightningOpenChannelClient) Recv() (*OpenStatusUpdate, error) {
-	m := new(OpenStatusUpdate)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).GetTransactions(ctx, req.(*GetTransactionsRequest))
 	}
-	return m, nil
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *lightningClient) CloseChannel(ctx context.Context, in *CloseChannelRequest, opts ...grpc.CallOption) (Lightning_CloseChannelClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[2], c.cc, "/lnrpc.Lightning/CloseChannel", opts...)
-	if err != nil {
+func _Lightning_SendCoins_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendCoinsRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	x := &lightningCloseChannelClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
+	if interceptor == nil {
+		return srv.(LightningServer).SendCoins(ctx, in)
 	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/SendCoins",
 	}
-	return x, nil
-}
-
-type Lightning_CloseChannelClient interface {
-	Recv() (*CloseStatusUpdate, error)
-	grpc.ClientStream
-}
-
-type lightningCloseChannelClient struct {
-	grpc.ClientStream
-}
-
-func (x *lightningCloseChannelClient) Recv() (*CloseStatusUpdate, error) {
-	m := new(CloseStatusUpdate)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).SendCoins(ctx, req.(*SendCoinsRequest))
 	}
-	return m, nil
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *lightningClient) SendPayment(ctx context.Context, opts ...grpc.CallOption) (Lightning_SendPaymentClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[3], c.cc, "/lnrpc.Lightning/SendPayment", opts...)
-	if err != nil {
-		return nil, err
+func _Lightning_SubscribeTransactions_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetTransactionsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
 	}
-	x := &lightningSendPaymentClient{stream}
-	return x, nil
+	return srv.(LightningServer).SubscribeTransactions(m, &lightningSubscribeTransactionsServer{stream})
 }
 
-type Lightning_SendPaymentClient interface {
-	Send(*SendRequest) error
-	Recv() (*SendResponse, error)
-	grpc.ClientStream
+type Lightning_SubscribeTransactionsServer interface {
+	Send(*Transaction) error
+	grpc.ServerStream
 }
 
-type lightningSendPaymentClient struct {
-	grpc.ClientStream
+type lightningSubscribeTransactionsServer struct {
+	grpc.ServerStream
 }
 
-func (x *lightningSendPaymentClient) Send(m *SendRequest) error {
-	return x.ClientStream.SendMsg(m)
+func (x *lightningSubscribeTransactionsServer) Send(m *Transaction) error {
+	return x.ServerStream.SendMsg(m)
 }
 
-func (x *lightningSendPaymentClient) Recv() (*SendResponse, error) {
-	m := new(SendResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
+func _Lightning_SendMany_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendManyRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return m, nil
-}
-
-func (c *lightningClient) SendPaymentSync(ctx context.Context, in *SendRequest, opts ...grpc.CallOption) (*SendResponse, error) {
-	out := new(SendResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/SendPaymentSync", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+	if interceptor == nil {
+		return srv.(LightningServer).SendMany(ctx, in)
 	}
-	return out, nil
-}
-
-func (c *lightningClient) AddInvoice(ctx context.Context, in *Invoice, opts ...grpc.CallOption) (*AddInvoiceResponse, error) {
-	out := new(AddInvoiceResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/AddInvoice", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/SendMany",
 	}
-	return out, nil
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).SendMany(ctx, req.(*SendManyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *lightningClient) ListInvoices(ctx context.Context, in *ListInvoiceRequest, opts ...grpc.CallOption) (*ListInvoiceResponse, error) {
-	out := new(ListInvoiceResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ListInvoices", in, out, c.cc, opts...)
-	if err != nil {
+func _Lightning_NewAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NewAddressRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(LightningServer).NewAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/NewAddress",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).NewAddress(ctx, req.(*NewAddressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *lightningClient) LookupInvoice(ctx context.Context, in *PaymentHash, opts ...grpc.CallOption) (*Invoice, error) {
-	out := new(Invoice)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/LookupInvoice", in, out, c.cc, opts...)
-	if err != nil {
+func _Lightning_NewWitnessAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NewWitnessAddressRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(LightningServer).NewWitnessAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/NewWitnessAddress",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).NewWitnessAddress(ctx, req.(*NewWitnessAddressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *lightningClient) SubscribeInvoices(ctx context.Context, in *InvoiceSubscription, opts ...grpc.CallOption) (Lightning_SubscribeInvoicesClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[4], c.cc, "/lnrpc.Lightning/SubscribeInvoices", opts...)
-	if err != nil {
+func _Lightning_SignMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignMessageRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	x := &lightningSubscribeInvoicesClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
+	if interceptor == nil {
+		return srv.(LightningServer).SignMessage(ctx, in)
 	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/SignMessage",
 	}
-	return x, nil
-}
-
-type Lightning_SubscribeInvoicesClient interface {
-	Recv() (*Invoice, error)
-	grpc.ClientStream
-}
-
-type lightningSubscribeInvoicesClient struct {
-	grpc.ClientStream
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).SignMessage(ctx, req.(*SignMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *lightningSubscribeInvoicesClient) Recv() (*Invoice, error) {
-	m := new(Invoice)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
+func _Lightning_VerifyMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyMessageRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return m, nil
+	if interceptor == nil {
+		return srv.(LightningServer).VerifyMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/VerifyMessage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).VerifyMessage(ctx, req.(*VerifyMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *lightningClient) DecodePayReq(ctx context.Context, in *PayReqString, opts ...grpc.CallOption) (*PayReq, error) {
-	out := new(PayReq)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/DecodePayReq", in, out, c.cc, opts...)
-	if err != nil {
+func _Lightning_ConnectPeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConnectPeerRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(LightningServer).ConnectPeer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ConnectPeer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ConnectPeer(ctx, req.(*ConnectPeerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *lightningClient) ListPayments(ctx context.Context, in *ListPaymentsRequest, opts ...grpc.CallOption) (*ListPaymentsResponse, error) {
-	out := new(ListPaymentsResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ListPayments", in, out, c.cc, opts...)
-	if err != nil {
+func _Lightning_DisconnectPeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DisconnectPeerRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(LightningServer).DisconnectPeer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/DisconnectPeer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).DisconnectPeer(ctx, req.(*DisconnectPeerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *lightningClient) DeleteAllPayments(ctx context.Context, in *DeleteAllPaymentsRequest, opts ...grpc.CallOption) (*DeleteAllPaymentsResponse, error) {
-	out := new(DeleteAllPaymentsResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/DeleteAllPayments", in, out, c.cc, opts...)
-	if err != nil {
+func _Lightning_ListPeers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPeersRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(LightningServer).ListPeers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ListPeers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ListPeers(ctx, req.(*ListPeersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *lightningClient) DescribeGraph(ctx context.Context, in *ChannelGraphRequest, opts ...grpc.CallOption) (*ChannelGraph, error) {
-	out := new(ChannelGraph)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/DescribeGraph", in, out, c.cc, opts...)
-	if err != nil {
+func _Lightning_GetInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetInfoRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(LightningServer).GetInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/GetInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).GetInfo(ctx, req.(*GetInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *lightningClient) GetChanInfo(ctx context.Context, in *ChanInfoRequest, opts ...grpc.CallOption) (*ChannelEdge, error) {
-	out := new(ChannelEdge)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/GetChanInfo", in, out, c.cc, opts...)
-	if err != nil {
+func _Lightning_PendingChannels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PendingChannelRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(LightningServer).PendingChannels(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/PendingChannels",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).PendingChannels(ctx, req.(*PendingChannelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *lightningClient) GetNodeInfo(ctx context.Context, in *NodeInfoRequest, opts ...grpc.CallOption) (*NodeInfo, error) {
-	out := new(NodeInfo)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/GetNodeInfo", in, out, c.cc, opts...)
-	if err != nil {
+func _Lightning_ListChannels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListChannelsRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(LightningServer).ListChannels(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ListChannels",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ListChannels(ctx, req.(*ListChannelsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *lightningClient) QueryRoutes(ctx context.Context, in *QueryRoutesRequest, opts ...grpc.CallOption) (*QueryRoutesResponse, error) {
-	out := new(QueryRoutesResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/QueryRoutes", in, out, c.cc, opts...)
-	if err != nil {
+func _Lightning_ClosedChannels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClosedChannelsRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(LightningServer).ClosedChannels(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ClosedChannels",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ClosedChannels(ctx, req.(*ClosedChannelsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *lightningClient) GetNetworkInfo(ctx context.Context, in *NetworkInfoRequest, opts ...grpc.CallOption) (*NetworkInfo, error) {
-	out := new(NetworkInfo)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/GetNetworkInfo", in, out, c.cc, opts...)
-	if err != nil {
+func _Lightning_OpenChannelSync_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OpenChannelRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(LightningServer).OpenChannelSync(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/OpenChannelSync",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).OpenChannelSync(ctx, req.(*OpenChannelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *lightningClient) StopDaemon(ctx context.Context, in *StopRequest, opts ...grpc.CallOption) (*StopResponse, error) {
-	out := new(StopResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/StopDaemon", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func _Lightning_OpenChannel_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(OpenChannelRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
 	}
-	return out, nil
+	return srv.(LightningServer).OpenChannel(m, &lightningOpenChannelServer{stream})
 }
 
-func (c *lightningClient) SubscribeChannelGraph(ctx context.Context, in *GraphTopologySubscription, opts ...grpc.CallOption) (Lightning_SubscribeChannelGraphClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[5], c.cc, "/lnrpc.Lightning/SubscribeChannelGraph", opts...)
-	if err != nil {
-		return nil, err
-	}
-	x := &lightningSubscribeChannelGraphClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
-	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
-	}
-	return x, nil
+type Lightning_OpenChannelServer interface {
+	Send(*OpenStatusUpdate) error
+	grpc.ServerStream
 }
 
-type Lightning_SubscribeChannelGraphClient interface {
-	Recv() (*GraphTopologyUpdate, error)
-	grpc.ClientStream
+type lightningOpenChannelServer struct {
+	grpc.ServerStream
 }
 
-type lightningSubscribeChannelGraphClient struct {
-	grpc.ClientStream
+func (x *lightningOpenChannelServer) Send(m *OpenStatusUpdate) error {
+	return x.ServerStream.SendMsg(m)
 }
 
-func (x *lightningSubscribeChannelGraphClient) Recv() (*GraphTopologyUpdate, error) {
-	m := new(GraphTopologyUpdate)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
+func _Lightning_CloseChannel_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(CloseChannelRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
 	}
-	return m, nil
+	return srv.(LightningServer).CloseChannel(m, &lightningCloseChannelServer{stream})
 }
 
-func (c *lightningClient) SetAlias(ctx context.Context, in *SetAliasRequest, opts ...grpc.CallOption) (*SetAliasResponse, error) {
-	out := new(SetAliasResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/SetAlias", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+type Lightning_CloseChannelServer interface {
+	Send(*CloseStatusUpdate) error
+	grpc.ServerStream
 }
 
-func (c *lightningClient) DebugLevel(ctx context.Context, in *DebugLevelRequest, opts ...grpc.CallOption) (*DebugLevelResponse, error) {
-	out := new(DebugLevelResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/DebugLevel", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+type lightningCloseChannelServer struct {
+	grpc.ServerStream
 }
 
-func (c *lightningClient) FeeReport(ctx context.Context, in *FeeReportRequest, opts ...grpc.CallOption) (*FeeReportResponse, error) {
-	out := new(FeeReportResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/FeeReport", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+func (x *lightningCloseChannelServer) Send(m *CloseStatusUpdate) error {
+	return x.ServerStream.SendMsg(m)
 }
 
-func (c *lightningClient) UpdateFees(ctx context.Context, in *FeeUpdateRequest, opts ...grpc.CallOption) (*FeeUpdateResponse, error) {
-	out := new(FeeUpdateResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/UpdateFees", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+func _Lightning_SendPayment_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(LightningServer).SendPayment(&lightningSendPaymentServer{stream})
 }
 
-// Server API for Lightning service
+type Lightning_SendPaymentServer interface {
+	Send(*SendResponse) error
+	Recv() (*SendRequest, error)
+	grpc.ServerStream
+}
 
-type LightningServer interface {
-	// * lncli: `walletbalance`
-	// WalletBalance returns the sum of all confirmed unspent outputs under control
-	// by the wallet. This method can be modified by having the request specify
-	// only witness outputs should be factored into the final output sum.
-	WalletBalance(context.Context, *WalletBalanceRequest) (*WalletBalanceResponse, error)
-	// * lncli: `channelbalance`
-	// ChannelBalance returns the total funds available across all open channels
-	// in satoshis.
-	ChannelBalance(context.Context, *ChannelBalanceRequest) (*ChannelBalanceResponse, error)
-	// * lncli: `listchaintxns`
-	// GetTransactions returns a list describing all the known transactions
-	// relevant to the wallet.
-	GetTransactions(context.Context, *GetTransactionsRequest) (*TransactionDetails, error)
-	// * lncli: `sendcoins`
-	// SendCoins executes a request to send coins to a particular address. Unlike
-	// SendMany, this RPC call only allows creating a single output at a time.
-	SendCoins(context.Context, *SendCoinsRequest) (*SendCoinsResponse, error)
-	// *
-	// SubscribeTransactions creates a uni-directional stream from the server to
-	// the client in which any newly discovered transactions relevant to the
-	// wallet are sent over.
-	SubscribeTransactions(*GetTransactionsRequest, Lightning_SubscribeTransactionsServer) error
-	// * lncli: `sendmany`
-	// SendMany handles a request for a transaction that creates multiple specified
-	// outputs in parallel.
-	SendMany(context.Context, *SendManyRequest) (*SendManyResponse, error)
-	// * lncli: `newaddress`
-	// NewAddress creates a new address under control of the local wallet.
-	NewAddress(context.Context, *NewAddressRequest) (*NewAddressResponse, error)
-	// *
-	// NewWitnessAddress creates a new witness address under control of the local wallet.
-	NewWitnessAddress(context.Context, *NewWitnessAddressRequest) (*NewAddressResponse, error)
-	// * lncli: `signmessage`
-	// SignMessage signs a message with this node's private key. The returned
-	// signature string is `zbase32` encoded and pubkey recoverable, meaning that
-	// only the message digest and signature are needed for verification.
-	SignMessage(context.Context, *SignMessageRequest) (*SignMessageResponse, error)
-	// * lncli: `verifymessage`
-	// VerifyMessage verifies a signature over a msg. The signature must be
-	// zbase32 encoded and signed by an active node in the resident node's
-	// channel database. In addition to returning the validity of the signature,
-	// VerifyMessage also returns the recovered pubkey from the signature.
-	VerifyMessage(context.Context, *VerifyMessageRequest) (*VerifyMessageResponse, error)
-	// * lncli: `connect`
-	// ConnectPeer attempts to establish a connection to a remote peer. This is at
-	// the networking level, and is used for communication between nodes. This is
-	// distinct from establishing a channel with a peer.
-	ConnectPeer(context.Context, *ConnectPeerRequest) (*ConnectPeerResponse, error)
-	// * lncli: `disconnect`
-	// DisconnectPeer attempts to disconnect one peer from another identified by a
-	// given pubKey. In the case that we currently have a pending or active channel
-	// with the target peer, then this action will be not be allowed.
-	DisconnectPeer(context.Context, *DisconnectPeerRequest) (*DisconnectPeerResponse, error)
-	// * lncli: `listpeers`
-	// ListPeers returns a verbose listing of all currently active peers.
-	ListPeers(context.Context, *ListPeersRequest) (*ListPeersResponse, error)
-	// * lncli: `getinfo`
-	// GetInfo returns general information concerning the lightning node including
-	// it's identity pubkey, alias, the chains it is connected to, and information
-	// concerning the number of open+pending channels.
-	GetInfo(context.Context, *GetInfoRequest) (*GetInfoResponse, error)
-	// * lncli: `pendingchannels`
-	// PendingChannels returns a list of all the channels that are currently
-	// considered "pending". A channel is pending if it has finished the funding
-	// workflow and is waiting for confirmations for the funding txn, or is in the
-	// process of closure, either initiated cooperatively or non-cooperatively.
-	PendingChannels(context.Context, *PendingChannelRequest) (*PendingChannelResponse, error)
-	// * lncli: `listchannels`
-	// ListChannels returns a description of all the open channels that this node
-	// is a participant in.
-	ListChannels(context.Context, *ListChannelsRequest) (*ListChannelsResponse, error)
-	// *
-	// OpenChannelSync is a synchronous version of the OpenChannel RPC call. This
-	// call is meant to be consumed by clients to the REST proxy. As with all
-	// other sync calls, all byte slices are intended to be populated as hex
-	// encoded strings.
-	OpenChannelSync(context.Context, *OpenChannelRequest) (*ChannelPoint, error)
-	// * lncli: `openchannel`
-	// OpenChannel attempts to open a singly funded channel specified in the
-	// request to a remote peer.
-	OpenChannel(*OpenChannelRequest, Lightning_OpenChannelServer) error
-	// * lncli: `closechannel`
-	// CloseChannel attempts to close an active channel identified by its channel
-	// outpoint (ChannelPoint). The actions of this method can additionally be
-	// augmented to attempt a force close after a timeout period in the case of an
-	// inactive peer.
-	CloseChannel(*CloseChannelRequest, Lightning_CloseChannelServer) error
-	// * lncli: `sendpayment`
-	// SendPayment dispatches a bi-directional streaming RPC for sending payments
-	// through the Lightning Network. A single RPC invocation creates a persistent
-	// bi-directional stream allowing clients to rapidly send payments through the
-	// Lightning Network with a single persistent connection.
-	SendPayment(Lightning_SendPaymentServer) error
-	// *
-	// SendPaymentSync is the synchronous non-streaming version of SendPayment.
-	// This RPC is intended to be consumed by clients of the REST proxy.
-	// Additionally, this RPC expects the destination's public key and the payment
-	// hash (if any) to be encoded as hex strings.
-	SendPaymentSync(context.Context, *SendRequest) (*SendResponse, error)
-	// * lncli: `addinvoice`
-	// AddInvoice attempts to add a new invoice to the invoice database. Any
-	// duplicated invoices are rejected, therefore all invoices *must* have a
-	// unique payment preimage.
-	AddInvoice(context.Context, *Invoice) (*AddInvoiceResponse, error)
-	// * lncli: `listinvoices`
-	// ListInvoices returns a list of all the invoices currently stored within the
-	// database. Any active debug invoices are ignored.
-	ListInvoices(context.Context, *ListInvoiceRequest) (*ListInvoiceResponse, error)
-	// * lncli: `lookupinvoice`
-	// LookupInvoice attemps to look up an invoice according to its payment hash.
-	// The passed payment hash *must* be exactly 32 bytes, if not, an error is
-	// returned.
-	LookupInvoice(context.Context, *PaymentHash) (*Invoice, error)
-	// *
-	// SubscribeInvoices returns a uni-directional stream (sever -> client) for
-	// notifying the client of newly added/settled invoices.
-	SubscribeInvoices(*InvoiceSubscription, Lightning_SubscribeInvoicesServer) error
-	// * lncli: `decodepayreq`
-	// DecodePayReq takes an encoded payment request string and attempts to decode
-	// it, returning a full description of the conditions encoded within the
-	// payment request.
-	DecodePayReq(context.Context, *PayReqString) (*PayReq, error)
-	// * lncli: `listpayments`
-	// ListPayments returns a list of all outgoing payments.
-	ListPayments(context.Context, *ListPaymentsRequest) (*ListPaymentsResponse, error)
-	// *
-	// DeleteAllPayments deletes all outgoing payments from DB.
-	DeleteAllPayments(context.Context, *DeleteAllPaymentsRequest) (*DeleteAllPaymentsResponse, error)
-	// * lncli: `describegraph`
-	// DescribeGraph returns a description of the latest graph state from the
-	// point of view of the node. The graph information is partitioned into two
-	// components: all the nodes/vertexes, and all the edges that connect the
-	// vertexes themselves.  As this is a directed graph, the edges also contain
-	// the node directional specific routing policy which includes: the time lock
-	// delta, fee information, etc.
-	DescribeGraph(context.Context, *ChannelGraphRequest) (*ChannelGraph, error)
-	// * lncli: `getchaninfo`
-	// GetChanInfo returns the latest authenticated network announcement for the
-	// given channel identified by its channel ID: an 8-byte integer which
-	// uniquely identifies the location of transaction's funding output within the
-	// blockchain.
-	GetChanInfo(context.Context, *ChanInfoRequest) (*ChannelEdge, error)
-	// * lncli: `getnodeinfo`
-	// GetNodeInfo returns the latest advertised, aggregated, and authenticated
-	// channel information for the specified node identified by its public key.
-	GetNodeInfo(context.Context, *NodeInfoRequest) (*NodeInfo, error)
-	// * lncli: `queryroutes`
-	// QueryRoutes attempts to query the daemon's Channel Router for a possible
-	// route to a target destination capable of carrying a specific amount of
-	// satoshis. The retuned route contains the full details required to craft and
-	// send an HTLC, also including the necessary information that should be
-	// present within the Sphinx packet encapsualted within the HTLC.
-	QueryRoutes(context.Context, *QueryRoutesRequest) (*QueryRoutesResponse, error)
-	// * lncli: `getnetworkinfo`
-	// GetNetworkInfo returns some basic stats about the known channel graph from
-	// the point of view of the node.
-	GetNetworkInfo(context.Context, *NetworkInfoRequest) (*NetworkInfo, error)
-	// * lncli: `stop`
-	// StopDaemon will send a shutdown request to the interrupt handler, triggering
-	// a graceful shutdown of the daemon.
-	StopDaemon(context.Context, *StopRequest) (*StopResponse, error)
-	// *
-	// SubscribeChannelGraph launches a streaming RPC that allows the caller to
-	// receive notifications upon any changes to the channel graph topology from
-	// the point of view of the responding node. Events notified include: new
-	// nodes coming online, nodes updating their authenticated attributes, new
-	// channels being advertised, updates in the routing policy for a directional
-	// channel edge, and when channels are closed on-chain.
-	SubscribeChannelGraph(*GraphTopologySubscription, Lightning_SubscribeChannelGraphServer) error
-	// *
-	// SetAlias sets the alias for this node; e.g. "alice"
-	SetAlias(context.Context, *SetAliasRequest) (*SetAliasResponse, error)
-	// * lncli: `debuglevel`
-	// DebugLevel allows a caller to programmatically set the logging verbosity of
-	// lnd. The logging can be targeted according to a coarse daemon-wide logging
-	// level, or in a granular fashion to specify the logging for a target
-	// sub-system.
-	DebugLevel(context.Context, *DebugLevelRequest) (*DebugLevelResponse, error)
-	// * lncli: `feereport`
-	// FeeReport allows the caller to obtain a report detailing the current fee
-	// schedule enforced by the node globally for each channel.
-	FeeReport(context.Context, *FeeReportRequest) (*FeeReportResponse, error)
-	// * lncli: `updatefees`
-	// UpdateFees allows the caller to update the fee schedule for all channels
-	// globally, or a particular channel.
-	UpdateFees(context.Context, *FeeUpdateRequest) (*FeeUpdateResponse, error)
+type lightningSendPaymentServer struct {
+	grpc.ServerStream
 }
 
-func RegisterLightningServer(s *grpc.Server, srv LightningServer) {
-	s.RegisterService(&_Lightning_serviceDesc, srv)
+func (x *lightningSendPaymentServer) Send(m *SendResponse) error {
+	return x.ServerStream.SendMsg(m)
 }
 
-func _Lightning_WalletBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(WalletBalanceRequest)
+func (x *lightningSendPaymentServer) Recv() (*SendRequest, error) {
+	m := new(SendRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Lightning_SendPaymentSync_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).WalletBalance(ctx, in)
+		return srv.(LightningServer).SendPaymentSync(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/WalletBalance",
+		FullMethod: "/lnrpc.Lightning/SendPaymentSync",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).WalletBalance(ctx, req.(*WalletBalanceRequest))
+		return srv.(LightningServer).SendPaymentSync(ctx, req.(*SendRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_ChannelBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ChannelBalanceRequest)
+func _Lightning_AddInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Invoice)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).ChannelBalance(ctx, in)
+		return srv.(LightningServer).AddInvoice(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/ChannelBalance",
+		FullMethod: "/lnrpc.Lightning/AddInvoice",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).ChannelBalance(ctx, req.(*ChannelBalanceRequest))
+		return srv.(LightningServer).AddInvoice(ctx, req.(*Invoice))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_GetTransactions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetTransactionsRequest)
+func _Lightning_ListInvoices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListInvoiceRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).GetTransactions(ctx, in)
+		return srv.(LightningServer).ListInvoices(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/GetTransactions",
+		FullMethod: "/lnrpc.Lightning/ListInvoices",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).GetTransactions(ctx, req.(*GetTransactionsRequest))
+		return srv.(LightningServer).ListInvoices(ctx, req.(*ListInvoiceRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_SendCoins_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SendCoinsRequest)
+func _Lightning_LookupInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PaymentHash)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).SendCoins(ctx, in)
+		return srv.(LightningServer).LookupInvoice(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/SendCoins",
+		FullMethod: "/lnrpc.Lightning/LookupInvoice",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).SendCoins(ctx, req.(*SendCoinsRequest))
+		return srv.(LightningServer).LookupInvoice(ctx, req.(*PaymentHash))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_SubscribeTransactions_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(GetTransactionsRequest)
+func _Lightning_SubscribeInvoices_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(InvoiceSubscription)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
-	return srv.(LightningServer).SubscribeTransactions(m, &lightningSubscribeTransactionsServer{stream})
+	return srv.(LightningServer).SubscribeInvoices(m, &lightningSubscribeInvoicesServer{stream})
 }
 
-type Lightning_SubscribeTransactionsServer interface {
-	Send(*Transaction) error
+type Lightning_SubscribeInvoicesServer interface {
+	Send(*Invoice) error
 	grpc.ServerStream
 }
 
-type lightningSubscribeTransactionsServer struct {
+type lightningSubscribeInvoicesServer struct {
 	grpc.ServerStream
 }
 
-func (x *lightningSubscribeTransactionsServer) Send(m *Transaction) error {
+func (x *lightningSubscribeInvoicesServer) Send(m *Invoice) error {
 	return x.ServerStream.SendMsg(m)
 }
 
-func _Lightning_SendMany_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SendManyRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(LightningServer).SendMany(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/SendMany",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).SendMany(ctx, req.(*SendManyRequest))
-	}
-	return interceptor(ctx, in, info, handler)
+func _Lightning_SubscribeSettledInvoices_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(LightningServer).SubscribeSettledInvoices(&lightningSubscribeSettledInvoicesServer{stream})
 }
 
-func _Lightning_NewAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(NewAddressRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(LightningServer).NewAddress(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/NewAddress",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).NewAddress(ctx, req.(*NewAddressRequest))
-	}
-	return interceptor(ctx, in, info, handler)
+type Lightning_SubscribeSettledInvoicesServer interface {
+	Send(*Invoice) error
+	Recv() (*SettleEventAck, error)
+	grpc.ServerStream
 }
 
-func _Lightning_NewWitnessAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(NewWitnessAddressRequest)
-	if err := dec(in); err != nil {
+type lightningSubscribeSettledInvoicesServer struct {
+	grpc.ServerStream
+}
+
+func (x *lightningSubscribeSettledInvoicesServer) Send(m *Invoice) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *lightningSubscribeSettledInvoicesServer) Recv() (*SettleEventAck, error) {
+	m := new(SettleEventAck)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
-	if interceptor == nil {
-		return srv.(LightningServer).NewWitnessAddress(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/NewWitnessAddress",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).NewWitnessAddress(ctx, req.(*NewWitnessAddressRequest))
-	}
-	return interceptor(ctx, in, info, handler)
+	return m, nil
 }
 
-func _Lightning_SignMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SignMessageRequest)
+func _Lightning_DecodePayReq_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PayReqString)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).SignMessage(ctx, in)
+		return srv.(LightningServer).DecodePayReq(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/SignMessage",
+		FullMethod: "/lnrpc.Lightning/DecodePayReq",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).SignMessage(ctx, req.(*SignMessageRequest))
+		return srv.(LightningServer).DecodePayReq(ctx, req.(*PayReqString))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_VerifyMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(VerifyMessageRequest)
+func _Lightning_ListPayments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPaymentsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).VerifyMessage(ctx, in)
+		return srv.(LightningServer).ListPayments(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/VerifyMessage",
+		FullMethod: "/lnrpc.Lightning/ListPayments",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).VerifyMessage(ctx, req.(*VerifyMessageRequest))
+		return srv.(LightningServer).ListPayments(ctx, req.(*ListPaymentsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_ConnectPeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ConnectPeerRequest)
+func _Lightning_DeleteAllPayments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteAllPaymentsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).ConnectPeer(ctx, in)
+		return srv.(LightningServer).DeleteAllPayments(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/ConnectPeer",
+		FullMethod: "/lnrpc.Lightning/DeleteAllPayments",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).ConnectPeer(ctx, req.(*ConnectPeerRequest))
+		return srv.(LightningServer).DeleteAllPayments(ctx, req.(*DeleteAllPaymentsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_DisconnectPeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DisconnectPeerRequest)
+func _Lightning_DescribeGraph_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChannelGraphRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).DisconnectPeer(ctx, in)
+		return srv.(LightningServer).DescribeGraph(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/DisconnectPeer",
+		FullMethod: "/lnrpc.Lightning/DescribeGraph",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).DisconnectPeer(ctx, req.(*DisconnectPeerRequest))
+		return srv.(LightningServer).DescribeGraph(ctx, req.(*ChannelGraphRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_ListPeers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListPeersRequest)
+func _Lightning_GetChanInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChanInfoRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).ListPeers(ctx, in)
+		return srv.(LightningServer).GetChanInfo(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/ListPeers",
+		FullMethod: "/lnrpc.Lightning/GetChanInfo",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).ListPeers(ctx, req.(*ListPeersRequest))
+		return srv.(LightningServer).GetChanInfo(ctx, req.(*ChanInfoRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_GetInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetInfoRequest)
+func _Lightning_GetNodeInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeInfoRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).GetInfo(ctx, in)
+		return srv.(LightningServer).GetNodeInfo(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/GetInfo",
+		FullMethod: "/lnrpc.Lightning/GetNodeInfo",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).GetInfo(ctx, req.(*GetInfoRequest))
+		return srv.(LightningServer).GetNodeInfo(ctx, req.(*NodeInfoRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_PendingChannels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(PendingChannelRequest)
+func _Lightning_QueryRoutes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryRoutesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).PendingChannels(ctx, in)
+		return srv.(LightningServer).QueryRoutes(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/PendingChannels",
+		FullMethod: "/lnrpc.Lightning/QueryRoutes",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).PendingChannels(ctx, req.(*PendingChannelRequest))
+		return srv.(LightningServer).QueryRoutes(ctx, req.(*QueryRoutesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_ListChannels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListChannelsRequest)
+func _Lightning_GetNetworkInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NetworkInfoRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).ListChannels(ctx, in)
+		return srv.(LightningServer).GetNetworkInfo(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/ListChannels",
+		FullMethod: "/lnrpc.Lightning/GetNetworkInfo",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).ListChannels(ctx, req.(*ListChannelsRequest))
+		return srv.(LightningServer).GetNetworkInfo(ctx, req.(*NetworkInfoRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_OpenChannelSync_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(OpenChannelRequest)
+func _Lightning_StopDaemon_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StopRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).OpenChannelSync(ctx, in)
+		return srv.(LightningServer).StopDaemon(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/OpenChannelSync",
+		FullMethod: "/lnrpc.Lightning/StopDaemon",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).OpenChannelSync(ctx, req.(*OpenChannelRequest))
+		return srv.(LightningServer).StopDaemon(ctx, req.(*StopRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_OpenChannel_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(OpenChannelRequest)
+func _Lightning_SubscribeChannelGraph_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GraphTopologySubscription)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
-	return srv.(LightningServer).OpenChannel(m, &lightningOpenChannelServer{stream})
+	return srv.(LightningServer).SubscribeChannelGraph(m, &lightningSubscribeChannelGraphServer{stream})
 }
 
-type Lightning_OpenChannelServer interface {
-	Send(*OpenStatusUpdate) error
+type Lightning_SubscribeChannelGraphServer interface {
+	Send(*GraphTopologyUpdate) error
 	grpc.ServerStream
 }
 
-type lightningOpenChannelServer struct {
+type lightningSubscribeChannelGraphServer struct {
 	grpc.ServerStream
 }
 
-func (x *lightningOpenChannelServer) Send(m *OpenStatusUpdate) error {
+func (x *lightningSubscribeChannelGraphServer) Send(m *GraphTopologyUpdate) error {
 	return x.ServerStream.SendMsg(m)
 }
 
-func _Lightning_CloseChannel_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(CloseChannelRequest)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+func _Lightning_SetAlias_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetAliasRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	return srv.(LightningServer).CloseChannel(m, &lightningCloseChannelServer{stream})
-}
-
-type Lightning_CloseChannelServer interface {
-	Send(*CloseStatusUpdate) error
-	grpc.ServerStream
-}
-
-type lightningCloseChannelServer struct {
-	grpc.ServerStream
-}
-
-func (x *lightningCloseChannelServer) Send(m *CloseStatusUpdate) error {
-	return x.ServerStream.SendMsg(m)
-}
-
-func _Lightning_SendPayment_Handler(srv interface{}, stream grpc.ServerStream) error {
-	return srv.(LightningServer).SendPayment(&lightningSendPaymentServer{stream})
-}
-
-type Lightning_SendPaymentServer interface {
-	Send(*SendResponse) error
-	Recv() (*SendRequest, error)
-	grpc.ServerStream
+	if interceptor == nil {
+		return srv.(LightningServer).SetAlias(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/SetAlias",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).SetAlias(ctx, req.(*SetAliasRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type lightningSendPaymentServer struct {
-	grpc.ServerStream
+func _Lightning_DebugLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DebugLevelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).DebugLevel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/DebugLevel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).DebugLevel(ctx, req.(*DebugLevelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *lightningSendPaymentServer) Send(m *SendResponse) error {
-	return x.ServerStream.SendMsg(m)
+func _Lightning_FeeReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FeeReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).FeeReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/FeeReport",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).FeeReport(ctx, req.(*FeeReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *lightningSendPaymentServer) Recv() (*SendRequest, error) {
-	m := new(SendRequest)
-	if err := x.ServerStream.RecvMsg(m); err != nil {
+func _Lightning_UpdateFees_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FeeUpdateRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return m, nil
+	if interceptor == nil {
+		return srv.(LightningServer).UpdateFees(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/UpdateFees",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).UpdateFees(ctx, req.(*FeeUpdateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_SendPaymentSync_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SendRequest)
+func _Lightning_ListBlacklist_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBlacklistRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).SendPaymentSync(ctx, in)
+		return srv.(LightningServer).ListBlacklist(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/SendPaymentSync",
+		FullMethod: "/lnrpc.Lightning/ListBlacklist",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).SendPaymentSync(ctx, req.(*SendRequest))
+		return srv.(LightningServer).ListBlacklist(ctx, req.(*ListBlacklistRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_AddInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Invoice)
+func _Lightning_UpdateBlacklist_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateBlacklistRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).AddInvoice(ctx, in)
+		return srv.(LightningServer).UpdateBlacklist(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/AddInvoice",
+		FullMethod: "/lnrpc.Lightning/UpdateBlacklist",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).AddInvoice(ctx, req.(*Invoice))
+		return srv.(LightningServer).UpdateBlacklist(ctx, req.(*UpdateBlacklistRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_ListInvoices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListInvoiceRequest)
+func _Lightning_GetCommitmentTxns_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CommitmentTxnsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).ListInvoices(ctx, in)
+		return srv.(LightningServer).GetCommitmentTxns(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/ListInvoices",
+		FullMethod: "/lnrpc.Lightning/GetCommitmentTxns",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).ListInvoices(ctx, req.(*ListInvoiceRequest))
+		return srv.(LightningServer).GetCommitmentTxns(ctx, req.(*CommitmentTxnsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_LookupInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(PaymentHash)
+func _Lightning_ExportChannelDB_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportChannelDBRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LightningServer).ExportChannelDB(m, &lightningExportChannelDBServer{stream})
+}
+
+type Lightning_ExportChannelDBServer interface {
+	Send(*ChannelDBChunk) error
+	grpc.ServerStream
+}
+
+type lightningExportChannelDBServer struct {
+	grpc.ServerStream
+}
+
+func (x *lightningExportChannelDBServer) Send(m *ChannelDBChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Lightning_SendCustomMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendCustomMessageRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).LookupInvoice(ctx, in)
+		return srv.(LightningServer).SendCustomMessage(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/LookupInvoice",
+		FullMethod: "/lnrpc.Lightning/SendCustomMessage",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).LookupInvoice(ctx, req.(*PaymentHash))
+		return srv.(LightningServer).SendCustomMessage(ctx, req.(*SendCustomMessageRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_SubscribeInvoices_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(InvoiceSubscription)
+func _Lightning_SubscribeCustomMessages_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeCustomMessagesRequest)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
-	return srv.(LightningServer).SubscribeInvoices(m, &lightningSubscribeInvoicesServer{stream})
+	return srv.(LightningServer).SubscribeCustomMessages(m, &lightningSubscribeCustomMessagesServer{stream})
 }
 
-type Lightning_SubscribeInvoicesServer interface {
-	Send(*Invoice) error
+type Lightning_SubscribeCustomMessagesServer interface {
+	Send(*CustomMessage) error
 	grpc.ServerStream
 }
 
-type lightningSubscribeInvoicesServer struct {
+type lightningSubscribeCustomMessagesServer struct {
 	grpc.ServerStream
 }
 
-func (x *lightningSubscribeInvoicesServer) Send(m *Invoice) error {
+func (x *lightningSubscribeCustomMessagesServer) Send(m *CustomMessage) error {
 	return x.ServerStream.SendMsg(m)
 }
 
-func _Lightning_DecodePayReq_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(PayReqString)
+func _Lightning_QueryMissionControl_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryMissionControlRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).DecodePayReq(ctx, in)
+		return srv.(LightningServer).QueryMissionControl(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/DecodePayReq",
+		FullMethod: "/lnrpc.Lightning/QueryMissionControl",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).DecodePayReq(ctx, req.(*PayReqString))
+		return srv.(LightningServer).QueryMissionControl(ctx, req.(*QueryMissionControlRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_ListPayments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListPaymentsRequest)
+func _Lightning_ResetMissionControl_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResetMissionControlRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).ListPayments(ctx, in)
+		return srv.(LightningServer).ResetMissionControl(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/ListPayments",
+		FullMethod: "/lnrpc.Lightning/ResetMissionControl",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).ListPayments(ctx, req.(*ListPaymentsRequest))
+		return srv.(LightningServer).ResetMissionControl(ctx, req.(*ResetMissionControlRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_DeleteAllPayments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DeleteAllPaymentsRequest)
+func _Lightning_XImportMissionControl_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(XImportMissionControlRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).DeleteAllPayments(ctx, in)
+		return srv.(LightningServer).XImportMissionControl(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/DeleteAllPayments",
+		FullMethod: "/lnrpc.Lightning/XImportMissionControl",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).DeleteAllPayments(ctx, req.(*DeleteAllPaymentsRequest))
+		return srv.(LightningServer).XImportMissionControl(ctx, req.(*XImportMissionControlRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_DescribeGraph_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ChannelGraphRequest)
+func _Lightning_BuildRoute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BuildRouteRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).DescribeGraph(ctx, in)
+		return srv.(LightningServer).BuildRoute(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/DescribeGraph",
+		FullMethod: "/lnrpc.Lightning/BuildRoute",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).DescribeGraph(ctx, req.(*ChannelGraphRequest))
+		return srv.(LightningServer).BuildRoute(ctx, req.(*BuildRouteRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_GetChanInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ChanInfoRequest)
+func _Lightning_SendToRouteSync_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendToRouteRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).GetChanInfo(ctx, in)
+		return srv.(LightningServer).SendToRouteSync(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/GetChanInfo",
+		FullMethod: "/lnrpc.Lightning/SendToRouteSync",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).GetChanInfo(ctx, req.(*ChanInfoRequest))
+		return srv.(LightningServer).SendToRouteSync(ctx, req.(*SendToRouteRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_GetNodeInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(NodeInfoRequest)
+func _Lightning_TrackPayment_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(PaymentHash)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LightningServer).TrackPayment(m, &lightningTrackPaymentServer{stream})
+}
+
+type Lightning_TrackPaymentServer interface {
+	Send(*Payment) error
+	grpc.ServerStream
+}
+
+type lightningTrackPaymentServer struct {
+	grpc.ServerStream
+}
+
+func (x *lightningTrackPaymentServer) Send(m *Payment) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Lightning_QueryProbability_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryProbabilityRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).GetNodeInfo(ctx, in)
+		return srv.(LightningServer).QueryProbability(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/GetNodeInfo",
+		FullMethod: "/lnrpc.Lightning/QueryProbability",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).GetNodeInfo(ctx, req.(*NodeInfoRequest))
+		return srv.(LightningServer).QueryProbability(ctx, req.(*QueryProbabilityRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_QueryRoutes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(QueryRoutesRequest)
+func _Lightning_UpdateChannelPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PolicyUpdateRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).QueryRoutes(ctx, in)
+		return srv.(LightningServer).UpdateChannelPolicy(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/QueryRoutes",
+		FullMethod: "/lnrpc.Lightning/UpdateChannelPolicy",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).QueryRoutes(ctx, req.(*QueryRoutesRequest))
+		return srv.(LightningServer).UpdateChannelPolicy(ctx, req.(*PolicyUpdateRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_GetNetworkInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(NetworkInfoRequest)
+func _Lightning_HtlcInterceptor_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(LightningServer).HtlcInterceptor(&lightningHtlcInterceptorServer{stream})
+}
+
+type Lightning_HtlcInterceptorServer interface {
+	Send(*ForwardHtlcInterceptRequest) error
+	Recv() (*ForwardHtlcInterceptResponse, error)
+	grpc.ServerStream
+}
+
+type lightningHtlcInterceptorServer struct {
+	grpc.ServerStream
+}
+
+func (x *lightningHtlcInterceptorServer) Send(m *ForwardHtlcInterceptRequest) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *lightningHtlcInterceptorServer) Recv() (*ForwardHtlcInterceptResponse, error) {
+	m := new(ForwardHtlcInterceptResponse)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Lightning_SettleInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SettleInvoiceMsg)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).GetNetworkInfo(ctx, in)
+		return srv.(LightningServer).SettleInvoice(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/GetNetworkInfo",
+		FullMethod: "/lnrpc.Lightning/SettleInvoice",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).GetNetworkInfo(ctx, req.(*NetworkInfoRequest))
+		return srv.(LightningServer).SettleInvoice(ctx, req.(*SettleInvoiceMsg))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_StopDaemon_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(StopRequest)
+func _Lightning_CancelInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelInvoiceMsg)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).StopDaemon(ctx, in)
+		return srv.(LightningServer).CancelInvoice(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/StopDaemon",
+		FullMethod: "/lnrpc.Lightning/CancelInvoice",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).StopDaemon(ctx, req.(*StopRequest))
+		return srv.(LightningServer).CancelInvoice(ctx, req.(*CancelInvoiceMsg))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_SubscribeChannelGraph_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(GraphTopologySubscription)
+func _Lightning_SubscribeHtlcEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeHtlcEventsRequest)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
-	return srv.(LightningServer).SubscribeChannelGraph(m, &lightningSubscribeChannelGraphServer{stream})
+	return srv.(LightningServer).SubscribeHtlcEvents(m, &lightningSubscribeHtlcEventsServer{stream})
 }
 
-type Lightning_SubscribeChannelGraphServer interface {
-	Send(*GraphTopologyUpdate) error
+type Lightning_SubscribeHtlcEventsServer interface {
+	Send(*HtlcEvent) error
 	grpc.ServerStream
 }
 
-type lightningSubscribeChannelGraphServer struct {
+type lightningSubscribeHtlcEventsServer struct {
 	grpc.ServerStream
 }
 
-func (x *lightningSubscribeChannelGraphServer) Send(m *GraphTopologyUpdate) error {
+func (x *lightningSubscribeHtlcEventsServer) Send(m *HtlcEvent) error {
 	return x.ServerStream.SendMsg(m)
 }
 
-func _Lightning_SetAlias_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SetAliasRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(LightningServer).SetAlias(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/SetAlias",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).SetAlias(ctx, req.(*SetAliasRequest))
-	}
-	return interceptor(ctx, in, info, handler)
-}
-
-func _Lightning_DebugLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DebugLevelRequest)
+func _Lightning_PendingSweeps_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PendingSweepsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).DebugLevel(ctx, in)
+		return srv.(LightningServer).PendingSweeps(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/DebugLevel",
+		FullMethod: "/lnrpc.Lightning/PendingSweeps",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).DebugLevel(ctx, req.(*DebugLevelRequest))
+		return srv.(LightningServer).PendingSweeps(ctx, req.(*PendingSweepsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_FeeReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(FeeReportRequest)
+func _Lightning_BumpFee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BumpFeeRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).FeeReport(ctx, in)
+		return srv.(LightningServer).BumpFee(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/FeeReport",
+		FullMethod: "/lnrpc.Lightning/BumpFee",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).FeeReport(ctx, req.(*FeeReportRequest))
+		return srv.(LightningServer).BumpFee(ctx, req.(*BumpFeeRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_UpdateFees_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(FeeUpdateRequest)
+func _Lightning_RemoveSettleConsumer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveSettleConsumerRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).UpdateFees(ctx, in)
+		return srv.(LightningServer).RemoveSettleConsumer(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/UpdateFees",
+		FullMethod: "/lnrpc.Lightning/RemoveSettleConsumer",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).UpdateFees(ctx, req.(*FeeUpdateRequest))
+		return srv.(LightningServer).RemoveSettleConsumer(ctx, req.(*RemoveSettleConsumerRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -5070,6 +8949,10 @@ var _Lightning_serviceDesc = grpc.ServiceDesc{
 			MethodName: "ListChannels",
 			Handler:    _Lightning_ListChannels_Handler,
 		},
+		{
+			MethodName: "ClosedChannels",
+			Handler:    _Lightning_ClosedChannels_Handler,
+		},
 		{
 			MethodName: "OpenChannelSync",
 			Handler:    _Lightning_OpenChannelSync_Handler,
@@ -5142,6 +9025,70 @@ var _Lightning_serviceDesc = grpc.ServiceDesc{
 			MethodName: "UpdateFees",
 			Handler:    _Lightning_UpdateFees_Handler,
 		},
+		{
+			MethodName: "ListBlacklist",
+			Handler:    _Lightning_ListBlacklist_Handler,
+		},
+		{
+			MethodName: "UpdateBlacklist",
+			Handler:    _Lightning_UpdateBlacklist_Handler,
+		},
+		{
+			MethodName: "GetCommitmentTxns",
+			Handler:    _Lightning_GetCommitmentTxns_Handler,
+		},
+		{
+			MethodName: "SendCustomMessage",
+			Handler:    _Lightning_SendCustomMessage_Handler,
+		},
+		{
+			MethodName: "QueryMissionControl",
+			Handler:    _Lightning_QueryMissionControl_Handler,
+		},
+		{
+			MethodName: "ResetMissionControl",
+			Handler:    _Lightning_ResetMissionControl_Handler,
+		},
+		{
+			MethodName: "XImportMissionControl",
+			Handler:    _Lightning_XImportMissionControl_Handler,
+		},
+		{
+			MethodName: "BuildRoute",
+			Handler:    _Lightning_BuildRoute_Handler,
+		},
+		{
+			MethodName: "SendToRouteSync",
+			Handler:    _Lightning_SendToRouteSync_Handler,
+		},
+		{
+			MethodName: "QueryProbability",
+			Handler:    _Lightning_QueryProbability_Handler,
+		},
+		{
+			MethodName: "UpdateChannelPolicy",
+			Handler:    _Lightning_UpdateChannelPolicy_Handler,
+		},
+		{
+			MethodName: "SettleInvoice",
+			Handler:    _Lightning_SettleInvoice_Handler,
+		},
+		{
+			MethodName: "CancelInvoice",
+			Handler:    _Lightning_CancelInvoice_Handler,
+		},
+		{
+			MethodName: "PendingSweeps",
+			Handler:    _Lightning_PendingSweeps_Handler,
+		},
+		{
+			MethodName: "BumpFee",
+			Handler:    _Lightning_BumpFee_Handler,
+		},
+		{
+			MethodName: "RemoveSettleConsumer",
+			Handler:    _Lightning_RemoveSettleConsumer_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -5170,11 +9117,43 @@ var _Lightning_serviceDesc = grpc.ServiceDesc{
 			Handler:       _Lightning_SubscribeInvoices_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "SubscribeSettledInvoices",
+			Handler:       _Lightning_SubscribeSettledInvoices_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 		{
 			StreamName:    "SubscribeChannelGraph",
 			Handler:       _Lightning_SubscribeChannelGraph_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "ExportChannelDB",
+			Handler:       _Lightning_ExportChannelDB_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SubscribeCustomMessages",
+			Handler:       _Lightning_SubscribeCustomMessages_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "TrackPayment",
+			Handler:       _Lightning_TrackPayment_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "HtlcInterceptor",
+			Handler:       _Lightning_HtlcInterceptor_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "SubscribeHtlcEvents",
+			Handler:       _Lightning_SubscribeHtlcEvents_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "rpc.proto",
 }
@@ -5182,288 +9161,514 @@ var _Lightning_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("rpc.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 4517 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x7b, 0x5d, 0x6f, 0x1c, 0x4b,
-	0x5a, 0x7f, 0x7a, 0x3c, 0x63, 0xcf, 0x3c, 0x33, 0xe3, 0x97, 0xf2, 0xdb, 0x64, 0x92, 0x93, 0x4d,
-	0x6a, 0xa3, 0x13, 0xff, 0xb3, 0x2b, 0x3b, 0xf1, 0xfe, 0xf7, 0x90, 0x4d, 0x80, 0x23, 0xe7, 0xd5,
-	0x87, 0xf5, 0xc9, 0xf1, 0xb6, 0x73, 0x4e, 0x60, 0x57, 0xa8, 0x69, 0x4f, 0x97, 0xc7, 0xbd, 0xe9,
-	0xe9, 0xee, 0xd3, 0x5d, 0x63, 0x67, 0x36, 0x8a, 0x84, 0x0e, 0x48, 0xdc, 0x80, 0x56, 0x68, 0x11,
-	0x88, 0x1b, 0xb4, 0x12, 0xe2, 0x12, 0x2e, 0xb8, 0xe5, 0x1b, 0x20, 0x90, 0x90, 0xf6, 0x8a, 0x1b,
-	0xae, 0xf8, 0x02, 0x5c, 0x70, 0x8f, 0xea, 0xb5, 0xab, 0xba, 0xdb, 0x49, 0x10, 0x88, 0x2b, 0x4f,
-	0xfd, 0xea, 0xe9, 0xa7, 0xaa, 0x9e, 0x7a, 0xea, 0x79, 0xab, 0x32, 0x74, 0xb2, 0x74, 0xb4, 0x9d,
-	0x66, 0x09, 0x4d, 0x50, 0x2b, 0x8a, 0xb3, 0x74, 0x34, 0xbc, 0x3a, 0x4e, 0x92, 0x71, 0x44, 0x76,
-	0xfc, 0x34, 0xdc, 0xf1, 0xe3, 0x38, 0xa1, 0x3e, 0x0d, 0x93, 0x38, 0x17, 0x44, 0xf8, 0x3f, 0x1c,
-	0xe8, 0xbe, 0xc8, 0xfc, 0x38, 0xf7, 0x47, 0x0c, 0x46, 0x03, 0x58, 0xa0, 0xaf, 0xbd, 0x53, 0x3f,
-	0x3f, 0x1d, 0x38, 0xd7, 0x9d, 0xad, 0x8e, 0xab, 0x9a, 0x68, 0x03, 0xe6, 0xfd, 0x49, 0x32, 0x8d,
-	0xe9, 0xa0, 0x71, 0xdd, 0xd9, 0x9a, 0x73, 0x65, 0x0b, 0x7d, 0x17, 0x56, 0xe2, 0xe9, 0xc4, 0x1b,
-	0x25, 0xf1, 0x49, 0x98, 0x4d, 0x04, 0xf3, 0xc1, 0xdc, 0x75, 0x67, 0xab, 0xe5, 0x56, 0x3b, 0xd0,
-	0x35, 0x80, 0xe3, 0x28, 0x19, 0xbd, 0x12, 0x43, 0x34, 0xf9, 0x10, 0x06, 0x82, 0x30, 0xf4, 0x64,
-	0x8b, 0x84, 0xe3, 0x53, 0x3a, 0x68, 0x71, 0x46, 0x16, 0xc6, 0x78, 0xd0, 0x70, 0x42, 0xbc, 0x9c,
-	0xfa, 0x93, 0x74, 0x30, 0xcf, 0x67, 0x63, 0x20, 0xbc, 0x3f, 0xa1, 0x7e, 0xe4, 0x9d, 0x10, 0x92,
-	0x0f, 0x16, 0x64, 0xbf, 0x46, 0xf0, 0x00, 0x36, 0x9e, 0x11, 0x6a, 0xac, 0x3a, 0x77, 0xc9, 0xd7,
-	0x53, 0x92, 0x53, 0x7c, 0x00, 0xc8, 0x80, 0x1f, 0x13, 0xea, 0x87, 0x51, 0x8e, 0x3e, 0x81, 0x1e,
-	0x35, 0x88, 0x07, 0xce, 0xf5, 0xb9, 0xad, 0xee, 0x2e, 0xda, 0xe6, 0xf2, 0xdd, 0x36, 0x3e, 0x70,
-	0x2d, 0x3a, 0xfc, 0x2f, 0x0e, 0x74, 0x8f, 0x48, 0x1c, 0x48, 0xee, 0x08, 0x41, 0x33, 0x20, 0x39,
-	0xe5, 0x82, 0xed, 0xb9, 0xfc, 0x37, 0xfa, 0x16, 0x74, 0xd9, 0x5f, 0x2f, 0xa7, 0x59, 0x18, 0x8f,
-	0xb9, 0x68, 0x3b, 0x2e, 0x30, 0xe8, 0x88, 0x23, 0x68, 0x19, 0xe6, 0xfc, 0x09, 0xe5, 0x02, 0x9d,
-	0x73, 0xd9, 0x4f, 0x74, 0x03, 0x7a, 0xa9, 0x3f, 0x9b, 0x90, 0x98, 0x16, 0x42, 0xec, 0xb9, 0x5d,
-	0x89, 0xed, 0x33, 0x29, 0x6e, 0xc3, 0xaa, 0x49, 0xa2, 0xb8, 0xb7, 0x38, 0xf7, 0x15, 0x83, 0x52,
-	0x0e, 0x72, 0x0b, 0x96, 0x14, 0x7d, 0x26, 0x26, 0xcb, 0xc5, 0xda, 0x71, 0x17, 0x25, 0xac, 0x04,
-	0xf4, 0x67, 0x0e, 0xf4, 0xc4, 0x92, 0xf2, 0x34, 0x89, 0x73, 0x82, 0x6e, 0x42, 0x5f, 0x7d, 0x49,
-	0xb2, 0x2c, 0xc9, 0xa4, 0xd6, 0xd8, 0x20, 0xba, 0x0d, 0xcb, 0x0a, 0x48, 0x33, 0x12, 0x4e, 0xfc,
-	0x31, 0xe1, 0x4b, 0xed, 0xb9, 0x15, 0x1c, 0xed, 0x16, 0x1c, 0xb3, 0x64, 0x4a, 0x09, 0x5f, 0x7a,
-	0x77, 0xb7, 0x27, 0xc5, 0xed, 0x32, 0xcc, 0xb5, 0x49, 0xf0, 0x37, 0x0e, 0xf4, 0x1e, 0x9d, 0xfa,
-	0x71, 0x4c, 0xa2, 0xc3, 0x24, 0x8c, 0x29, 0x53, 0xa3, 0x93, 0x69, 0x1c, 0x84, 0xf1, 0xd8, 0xa3,
-	0xaf, 0xc3, 0x40, 0x8a, 0xdc, 0xc2, 0xd8, 0xa4, 0xcc, 0x36, 0x13, 0x92, 0x94, 0x7f, 0x05, 0x67,
-	0xfc, 0x92, 0x29, 0x4d, 0xa7, 0xd4, 0x0b, 0xe3, 0x80, 0xbc, 0xe6, 0x73, 0xea, 0xbb, 0x16, 0x86,
-	0x7f, 0x13, 0x96, 0x0f, 0x98, 0x7e, 0xc6, 0x61, 0x3c, 0xde, 0x0b, 0x82, 0x8c, 0xe4, 0x39, 0x3b,
-	0x34, 0xe9, 0xf4, 0xf8, 0x15, 0x99, 0x49, 0xb9, 0xc8, 0x16, 0x53, 0x85, 0xd3, 0x24, 0xa7, 0x72,
-	0x3c, 0xfe, 0x1b, 0xff, 0xd2, 0x81, 0x25, 0x26, 0xdb, 0xcf, 0xfd, 0x78, 0xa6, 0x54, 0xe6, 0x00,
-	0x7a, 0x8c, 0xd5, 0x8b, 0x64, 0x4f, 0x1c, 0x3d, 0xa1, 0x7a, 0x5b, 0x52, 0x16, 0x25, 0xea, 0x6d,
-	0x93, 0xf4, 0x49, 0x4c, 0xb3, 0x99, 0x6b, 0x7d, 0x3d, 0xfc, 0x14, 0x56, 0x2a, 0x24, 0x4c, 0xc1,
-	0x8a, 0xf9, 0xb1, 0x9f, 0x68, 0x0d, 0x5a, 0x67, 0x7e, 0x34, 0x25, 0xf2, 0xa0, 0x8b, 0xc6, 0xfd,
-	0xc6, 0x3d, 0x07, 0x7f, 0x0c, 0xcb, 0xc5, 0x98, 0x52, 0x03, 0x10, 0x34, 0xb5, 0x88, 0x3b, 0x2e,
-	0xff, 0xcd, 0x44, 0xc1, 0xe8, 0x1e, 0x25, 0xa1, 0x3e, 0x5b, 0x8c, 0xce, 0x0f, 0x02, 0xa5, 0x20,
-	0xfc, 0xf7, 0x45, 0x36, 0x05, 0xdf, 0x82, 0x15, 0xe3, 0xfb, 0x77, 0x0c, 0xf4, 0x57, 0x0e, 0xac,
-	0x3c, 0x27, 0xe7, 0x52, 0xdc, 0x6a, 0xa8, 0x7b, 0xd0, 0xa4, 0xb3, 0x94, 0x70, 0xca, 0xc5, 0xdd,
-	0x9b, 0x52, 0x5a, 0x15, 0xba, 0x6d, 0xd9, 0x7c, 0x31, 0x4b, 0x89, 0xcb, 0xbf, 0xc0, 0x5f, 0x40,
-	0xd7, 0x00, 0xd1, 0x26, 0xac, 0xbe, 0xfc, 0xec, 0xc5, 0xf3, 0x27, 0x47, 0x47, 0xde, 0xe1, 0x97,
-	0x0f, 0x7f, 0xf8, 0xe4, 0x77, 0xbc, 0xfd, 0xbd, 0xa3, 0xfd, 0xe5, 0x4b, 0x68, 0x03, 0xd0, 0xf3,
-	0x27, 0x47, 0x2f, 0x9e, 0x3c, 0xb6, 0x70, 0x07, 0x2d, 0x41, 0xd7, 0x04, 0x1a, 0x78, 0x08, 0x83,
-	0xe7, 0xe4, 0xfc, 0x65, 0x48, 0x63, 0x92, 0xe7, 0xf6, 0xf0, 0x78, 0x1b, 0x90, 0x39, 0x27, 0xb9,
-	0xcc, 0x01, 0x2c, 0xf8, 0x02, 0x52, 0x16, 0x58, 0x36, 0xf1, 0xc7, 0x80, 0x8e, 0xc2, 0x71, 0xfc,
-	0x39, 0xc9, 0x73, 0x7f, 0x4c, 0xd4, 0x62, 0x97, 0x61, 0x6e, 0x92, 0x8f, 0xa5, 0x86, 0xb3, 0x9f,
-	0xf8, 0x7b, 0xb0, 0x6a, 0xd1, 0x49, 0xc6, 0x57, 0xa1, 0x93, 0x87, 0xe3, 0xd8, 0xa7, 0xd3, 0x8c,
-	0x48, 0xd6, 0x05, 0x80, 0x9f, 0xc2, 0xda, 0x57, 0x24, 0x0b, 0x4f, 0x66, 0xef, 0x63, 0x6f, 0xf3,
-	0x69, 0x94, 0xf9, 0x3c, 0x81, 0xf5, 0x12, 0x1f, 0x39, 0xbc, 0xd0, 0x2a, 0xb9, 0x7f, 0x6d, 0x57,
-	0x34, 0x8c, 0x03, 0xd2, 0x30, 0x0f, 0x08, 0xfe, 0x12, 0xd0, 0xa3, 0x24, 0x8e, 0xc9, 0x88, 0x1e,
-	0x12, 0x92, 0xa9, 0xc9, 0x7c, 0xc7, 0xd0, 0xa1, 0xee, 0xee, 0xa6, 0xdc, 0xd8, 0xf2, 0xa9, 0x93,
-	0xca, 0x85, 0xa0, 0x99, 0x92, 0x6c, 0xc2, 0x19, 0xb7, 0x5d, 0xfe, 0x1b, 0xef, 0xc0, 0xaa, 0xc5,
-	0xb6, 0x90, 0x79, 0x4a, 0x48, 0xe6, 0xc9, 0xd9, 0xb5, 0x5c, 0xd5, 0xc4, 0x77, 0x61, 0xfd, 0x71,
-	0x98, 0x8f, 0xaa, 0x53, 0x61, 0x9f, 0x4c, 0x8f, 0xbd, 0xe2, 0xe8, 0xa8, 0x26, 0x73, 0x2f, 0xe5,
-	0x4f, 0xc4, 0x30, 0xf8, 0xef, 0x1d, 0x68, 0xee, 0xbf, 0x38, 0x78, 0x84, 0x86, 0xd0, 0x0e, 0xe3,
-	0x51, 0x32, 0x61, 0x46, 0x59, 0x88, 0x43, 0xb7, 0x2f, 0xf4, 0xb3, 0x57, 0xa1, 0xc3, 0x6d, 0x39,
-	0xf3, 0x84, 0xdc, 0xfe, 0xf4, 0xdc, 0x02, 0x60, 0x5e, 0x98, 0xbc, 0x4e, 0xc3, 0x8c, 0xbb, 0x59,
-	0xe5, 0x3c, 0x9b, 0xdc, 0x4a, 0x55, 0x3b, 0x98, 0xe9, 0xcb, 0xc8, 0x59, 0x32, 0x12, 0x60, 0x40,
-	0x22, 0x7f, 0xc6, 0x9d, 0x43, 0xdf, 0xad, 0xe0, 0xf8, 0x9f, 0x9a, 0xd0, 0xdf, 0x1b, 0xd1, 0xf0,
-	0x8c, 0x48, 0x0b, 0xcb, 0x67, 0xc8, 0x01, 0x39, 0x77, 0xd9, 0x62, 0xbe, 0x20, 0x23, 0x93, 0x84,
-	0x12, 0xcf, 0xda, 0x52, 0x1b, 0x64, 0x54, 0x23, 0xc1, 0xc8, 0x4b, 0x99, 0xad, 0xe6, 0x6b, 0xe9,
-	0xb8, 0x36, 0xc8, 0xc4, 0xcb, 0x00, 0xb6, 0x23, 0x6c, 0x15, 0x4d, 0x57, 0x35, 0x99, 0xec, 0x46,
-	0x7e, 0xea, 0x8f, 0x42, 0x2a, 0xe6, 0x3c, 0xe7, 0xea, 0x36, 0xe3, 0x1d, 0x25, 0x23, 0x3f, 0xf2,
-	0x8e, 0xfd, 0xc8, 0x8f, 0x47, 0x44, 0x06, 0x07, 0x36, 0x88, 0x3e, 0x86, 0x45, 0x39, 0x25, 0x45,
-	0x26, 0x62, 0x84, 0x12, 0xca, 0xe2, 0x88, 0x51, 0x32, 0x99, 0x84, 0x94, 0x85, 0x0d, 0x83, 0xb6,
-	0x88, 0x23, 0x0a, 0x84, 0xaf, 0x44, 0xb4, 0xce, 0x85, 0xbc, 0x3b, 0x62, 0x34, 0x0b, 0x64, 0x5c,
-	0x4e, 0x08, 0xf1, 0x52, 0x92, 0x79, 0xaf, 0xce, 0x07, 0x20, 0xb8, 0x14, 0x08, 0xdb, 0xb9, 0x69,
-	0x9c, 0x13, 0x4a, 0x23, 0x12, 0xe8, 0x09, 0x75, 0x39, 0x59, 0xb5, 0x03, 0xdd, 0x81, 0x55, 0x11,
-	0xc9, 0xe4, 0x3e, 0x4d, 0xf2, 0xd3, 0x30, 0xf7, 0x72, 0x12, 0xd3, 0x41, 0x8f, 0xd3, 0xd7, 0x75,
-	0xa1, 0x7b, 0xb0, 0x59, 0x82, 0x33, 0x32, 0x22, 0xe1, 0x19, 0x09, 0x06, 0x7d, 0xfe, 0xd5, 0x45,
-	0xdd, 0xe8, 0x3a, 0x74, 0x59, 0x00, 0x37, 0x4d, 0x03, 0x9f, 0x92, 0x7c, 0xb0, 0xc8, 0xf7, 0xc1,
-	0x84, 0xd0, 0x5d, 0xe8, 0xa7, 0x44, 0xb8, 0xca, 0x53, 0x1a, 0x8d, 0xf2, 0xc1, 0x12, 0xf7, 0x4f,
-	0x5d, 0x79, 0x30, 0x99, 0xae, 0xbb, 0x36, 0x05, 0x5e, 0x87, 0xd5, 0x83, 0x30, 0xa7, 0x52, 0x97,
-	0xb4, 0x2d, 0xdc, 0x87, 0x35, 0x1b, 0x96, 0x27, 0xf3, 0x0e, 0xb4, 0xa5, 0x62, 0xe4, 0x83, 0x2e,
-	0x67, 0xbe, 0x26, 0x99, 0x5b, 0x3a, 0xe9, 0x6a, 0x2a, 0xfc, 0x87, 0x0d, 0x68, 0xb2, 0x53, 0x77,
-	0xf1, 0x09, 0x35, 0x8f, 0x7b, 0xc3, 0x3a, 0xee, 0xa6, 0xf1, 0x9d, 0xb3, 0x8c, 0x2f, 0x0f, 0x5c,
-	0x67, 0x94, 0x48, 0x79, 0x0b, 0x9d, 0x34, 0x90, 0xa2, 0x3f, 0x23, 0xa3, 0x33, 0xae, 0x98, 0xba,
-	0x9f, 0x21, 0x4c, 0x6d, 0x73, 0x9f, 0x8a, 0xaf, 0x85, 0x56, 0xea, 0xb6, 0xea, 0xe3, 0x5f, 0x2e,
-	0x14, 0x7d, 0xfc, 0xbb, 0x01, 0x2c, 0x84, 0xf1, 0x71, 0x32, 0x8d, 0x03, 0xae, 0x81, 0x6d, 0x57,
-	0x35, 0x99, 0x41, 0x48, 0x79, 0x90, 0x12, 0x4e, 0x88, 0x54, 0xbd, 0x02, 0xc0, 0x88, 0x45, 0x23,
-	0x39, 0xb7, 0x3f, 0x5a, 0xc8, 0x9f, 0xc0, 0x8a, 0x81, 0x49, 0x09, 0xdf, 0x80, 0x16, 0x5b, 0xbd,
-	0x0a, 0x6b, 0xd5, 0xde, 0x71, 0xc3, 0x25, 0x7a, 0xf0, 0x32, 0x2c, 0x3e, 0x23, 0xf4, 0xb3, 0xf8,
-	0x24, 0x51, 0x9c, 0xfe, 0xb3, 0x01, 0x4b, 0x1a, 0x92, 0x8c, 0xb6, 0x60, 0x29, 0x0c, 0x48, 0x4c,
-	0x43, 0x3a, 0xf3, 0xac, 0xa0, 0xa7, 0x0c, 0x33, 0x57, 0xe0, 0x47, 0xa1, 0x9f, 0x4b, 0x03, 0x21,
-	0x1a, 0x68, 0x17, 0xd6, 0x98, 0x6e, 0x29, 0x75, 0xd1, 0xdb, 0x2e, 0x62, 0xad, 0xda, 0x3e, 0x76,
-	0x1c, 0x18, 0x2e, 0x0c, 0x50, 0xf1, 0x89, 0x30, 0x7c, 0x75, 0x5d, 0x4c, 0x6a, 0x82, 0x13, 0x5b,
-	0xb2, 0xb0, 0x79, 0x05, 0x50, 0x49, 0x3f, 0xe6, 0x45, 0x9c, 0x57, 0x4e, 0x3f, 0x8c, 0x14, 0xa6,
-	0x5d, 0x49, 0x61, 0xb6, 0x60, 0x29, 0x9f, 0xc5, 0x23, 0x12, 0x78, 0x34, 0x61, 0xe3, 0x86, 0x31,
-	0xdf, 0x9d, 0xb6, 0x5b, 0x86, 0x79, 0xb2, 0x45, 0x72, 0x1a, 0x13, 0xca, 0xed, 0x42, 0xdb, 0x55,
-	0x4d, 0x66, 0x62, 0x39, 0x89, 0x50, 0xfa, 0x8e, 0x2b, 0x5b, 0xf8, 0x67, 0xdc, 0x2d, 0xea, 0x7c,
-	0xea, 0x4b, 0x7e, 0x0e, 0xd1, 0x15, 0xe8, 0x88, 0xf1, 0xf3, 0x53, 0x5f, 0x7a, 0xea, 0x36, 0x07,
-	0x8e, 0x4e, 0x7d, 0x96, 0x2e, 0x58, 0x4b, 0x12, 0x1a, 0xdf, 0xe5, 0xd8, 0xbe, 0x58, 0xd1, 0x4d,
-	0x58, 0x54, 0x99, 0x5a, 0xee, 0x45, 0xe4, 0x84, 0xaa, 0xf8, 0x36, 0x9e, 0x4e, 0xd8, 0x70, 0xf9,
-	0x01, 0x39, 0xa1, 0xf8, 0x39, 0xac, 0xc8, 0xd3, 0xf6, 0x45, 0x4a, 0xd4, 0xd0, 0x3f, 0x28, 0x5b,
-	0x73, 0xe1, 0x9a, 0x57, 0xa5, 0x16, 0x99, 0x41, 0x79, 0xc9, 0xc4, 0x63, 0x17, 0x90, 0xec, 0x7e,
-	0x14, 0x25, 0x39, 0x91, 0x0c, 0x31, 0xf4, 0x46, 0x51, 0x92, 0x97, 0x23, 0x77, 0x13, 0x63, 0x72,
-	0xcb, 0xa7, 0xa3, 0x11, 0x3b, 0xa5, 0xc2, 0xb9, 0xab, 0x26, 0x26, 0xb0, 0xca, 0x99, 0x29, 0xb3,
-	0xa0, 0x03, 0xc2, 0x0f, 0x9f, 0x65, 0x6f, 0x64, 0x26, 0x12, 0x6b, 0xd0, 0x3a, 0x49, 0xb2, 0x11,
-	0x91, 0x03, 0x89, 0x06, 0xfe, 0x57, 0x07, 0x56, 0xf8, 0x38, 0x47, 0xd4, 0xa7, 0xd3, 0x5c, 0x4e,
-	0xfd, 0xd7, 0xa1, 0xcf, 0xa6, 0x49, 0x94, 0x9a, 0xca, 0x51, 0xd6, 0xf4, 0x89, 0xe2, 0xa8, 0x20,
-	0xde, 0xbf, 0xe4, 0xda, 0xc4, 0xe8, 0x53, 0xe8, 0x99, 0xa9, 0x32, 0x1f, 0xb0, 0xbb, 0x7b, 0x59,
-	0x4d, 0xb1, 0xb2, 0xeb, 0xfb, 0x97, 0x5c, 0xeb, 0x03, 0xf4, 0x00, 0x80, 0xfb, 0x48, 0xce, 0x56,
-	0x66, 0x4d, 0x97, 0xed, 0x15, 0x1a, 0x82, 0xde, 0xbf, 0xe4, 0x1a, 0xe4, 0x0f, 0xdb, 0x30, 0x2f,
-	0x8c, 0x3a, 0x7e, 0x06, 0x7d, 0x6b, 0xa6, 0x56, 0xdc, 0xdd, 0x13, 0x71, 0x77, 0x25, 0x1f, 0x6a,
-	0xd4, 0xe4, 0x43, 0xff, 0xe6, 0x00, 0x62, 0x9a, 0x52, 0xda, 0x8b, 0x8f, 0x61, 0x91, 0xfa, 0xd9,
-	0x98, 0x50, 0xcf, 0x0e, 0xb9, 0x4a, 0x28, 0xf7, 0x3e, 0x49, 0x60, 0xc5, 0x12, 0x3d, 0xd7, 0x84,
-	0xd0, 0x36, 0x20, 0xa3, 0xa9, 0x92, 0x5c, 0x61, 0xb7, 0x6b, 0x7a, 0x98, 0x81, 0x11, 0x81, 0x80,
-	0x4a, 0xef, 0x64, 0x9c, 0xd5, 0xe4, 0xb6, 0xb3, 0xb6, 0x8f, 0x99, 0xe6, 0x74, 0xca, 0x32, 0x68,
-	0x9f, 0xaa, 0x68, 0x43, 0xb5, 0xf1, 0xaf, 0x1c, 0x58, 0x66, 0x0b, 0xb4, 0x94, 0xe0, 0x3e, 0x70,
-	0x05, 0xfa, 0x40, 0x1d, 0xb0, 0x68, 0xff, 0xe7, 0x2a, 0x70, 0x0f, 0x3a, 0x9c, 0x61, 0x92, 0x92,
-	0x58, 0x6a, 0xc0, 0xc0, 0xd6, 0x80, 0xe2, 0xe8, 0xee, 0x5f, 0x72, 0x0b, 0x62, 0x63, 0xff, 0x37,
-	0x61, 0x5d, 0xce, 0xd2, 0xde, 0x38, 0xfc, 0x47, 0x00, 0x1b, 0xe5, 0x1e, 0xed, 0xa5, 0x65, 0xe8,
-	0x11, 0x85, 0x93, 0xe3, 0x44, 0x47, 0x31, 0x8e, 0x19, 0x95, 0x58, 0x5d, 0xe8, 0x04, 0xd6, 0x95,
-	0x31, 0x67, 0xe3, 0x17, 0xa6, 0xbb, 0xc1, 0xbd, 0xd0, 0x1d, 0x5b, 0x5e, 0xa5, 0xf1, 0x14, 0x6c,
-	0x6a, 0x57, 0x3d, 0x3b, 0x34, 0x86, 0x81, 0x76, 0x1a, 0xd2, 0x84, 0x18, 0x8e, 0x85, 0x0d, 0xf5,
-	0x9d, 0x77, 0x0f, 0xc5, 0x8f, 0x4c, 0xa0, 0xd0, 0x0b, 0x99, 0xa1, 0xd7, 0x70, 0x4d, 0xf5, 0x71,
-	0x1b, 0x51, 0x1d, 0xae, 0xf9, 0x21, 0x2b, 0x7b, 0xca, 0xbe, 0xb5, 0xc7, 0x7c, 0x0f, 0xdf, 0xe1,
-	0x3f, 0x3a, 0xb0, 0x68, 0x73, 0x63, 0x2e, 0x48, 0xc6, 0xb2, 0xea, 0x18, 0x28, 0x57, 0x5c, 0x82,
-	0xab, 0xd1, 0x78, 0xa3, 0x2e, 0x1a, 0x37, 0x63, 0xee, 0xb9, 0xf7, 0xc5, 0xdc, 0xcd, 0x0f, 0x8b,
-	0xb9, 0x5b, 0x75, 0x31, 0xf7, 0xf0, 0x97, 0x0d, 0x40, 0xd5, 0xdd, 0x45, 0x4f, 0x45, 0x3a, 0x10,
-	0x93, 0x48, 0x1e, 0xa8, 0xef, 0x7e, 0x90, 0x82, 0x28, 0x58, 0x7d, 0xcc, 0x14, 0xd5, 0x3c, 0x30,
-	0xa6, 0x4f, 0xec, 0xbb, 0x75, 0x5d, 0x2c, 0x55, 0xe2, 0xae, 0x32, 0xf7, 0x68, 0x18, 0x45, 0xc5,
-	0xc9, 0xea, 0xbb, 0x15, 0xbc, 0x94, 0x30, 0x34, 0xdf, 0x9f, 0x30, 0xb4, 0xde, 0x9f, 0x30, 0xcc,
-	0x97, 0x13, 0x86, 0xe1, 0x1b, 0xe8, 0x5b, 0x0a, 0xf2, 0xbf, 0x26, 0x9c, 0xb2, 0xeb, 0x15, 0xaa,
-	0x60, 0x61, 0xc3, 0x6f, 0x1a, 0x80, 0xaa, 0x3a, 0xfa, 0x7f, 0x39, 0x05, 0xae, 0x70, 0x96, 0x99,
-	0x99, 0x93, 0x0a, 0x67, 0x19, 0x98, 0x2d, 0x58, 0x9a, 0xf8, 0x74, 0x9a, 0xb1, 0xb0, 0xd3, 0x4a,
-	0x87, 0xcb, 0x30, 0xd3, 0x89, 0x62, 0x27, 0x3d, 0xd5, 0x2b, 0x63, 0xc3, 0xba, 0x2e, 0xfc, 0x03,
-	0x58, 0x7b, 0xe9, 0x47, 0x11, 0xa1, 0x0f, 0xc5, 0x60, 0xca, 0xb5, 0xdd, 0x80, 0xde, 0xb9, 0xa8,
-	0xf4, 0x78, 0x49, 0x1c, 0xcd, 0x64, 0x7a, 0xdc, 0x95, 0xd8, 0x17, 0x71, 0x34, 0xc3, 0x77, 0x61,
-	0xbd, 0xf4, 0x69, 0x51, 0x82, 0xb0, 0xcd, 0xa6, 0x6a, 0x32, 0x83, 0x2c, 0xe5, 0x64, 0x0f, 0x87,
-	0x77, 0x61, 0xa3, 0xdc, 0xf1, 0x5e, 0x66, 0x9f, 0x02, 0xfa, 0xd1, 0x94, 0x64, 0x33, 0x5e, 0x46,
-	0xd5, 0x05, 0xb3, 0xcd, 0x72, 0xaa, 0x34, 0x9f, 0x4e, 0x8f, 0x7f, 0x48, 0x66, 0xaa, 0xfa, 0xdc,
-	0xd0, 0xd5, 0x67, 0xfc, 0x00, 0x56, 0x2d, 0x06, 0xba, 0x0e, 0x3c, 0xcf, 0x4b, 0xb1, 0x2a, 0x8d,
-	0xb0, 0xcb, 0xb5, 0xb2, 0x0f, 0xff, 0x85, 0x03, 0x73, 0xfb, 0x49, 0x6a, 0x66, 0xf7, 0x8e, 0x9d,
-	0xdd, 0x4b, 0x7b, 0xe4, 0x69, 0x73, 0xd3, 0x90, 0x47, 0xc4, 0x04, 0x99, 0x35, 0xf1, 0x27, 0x94,
-	0x05, 0xd2, 0x27, 0x49, 0x76, 0xee, 0x67, 0x81, 0xd4, 0x81, 0x12, 0xca, 0xa6, 0x5f, 0x9c, 0x44,
-	0xf6, 0x93, 0x05, 0xd6, 0xbc, 0x1c, 0xa2, 0xf6, 0x57, 0xb6, 0xf0, 0xcf, 0x1d, 0x68, 0xf1, 0xb9,
-	0x32, 0xc5, 0x11, 0x0e, 0x8b, 0xdf, 0x28, 0xf0, 0x6a, 0x8b, 0x23, 0x14, 0xa7, 0x04, 0x97, 0xee,
-	0x19, 0x1a, 0xe5, 0x7b, 0x06, 0x96, 0x6a, 0x88, 0x56, 0x51, 0xc0, 0x2f, 0x00, 0x74, 0x0d, 0x9a,
-	0xa7, 0x49, 0xaa, 0xdc, 0x02, 0xa8, 0x94, 0x39, 0x49, 0x5d, 0x8e, 0xe3, 0xdb, 0xb0, 0xf4, 0x3c,
-	0x09, 0x88, 0x91, 0x75, 0x5d, 0xb8, 0x4d, 0xf8, 0xf7, 0x1d, 0x68, 0x2b, 0x62, 0xb4, 0x05, 0x4d,
-	0x66, 0xde, 0x4b, 0x91, 0x87, 0x2e, 0x92, 0x31, 0x3a, 0x97, 0x53, 0xb0, 0xd3, 0xc6, 0xe3, 0xfe,
-	0xc2, 0xf7, 0xaa, 0xa8, 0xbf, 0xf0, 0x6b, 0x2c, 0x5c, 0xe3, 0x73, 0x2e, 0x39, 0x80, 0x12, 0x8a,
-	0x7f, 0xe1, 0x40, 0xdf, 0x1a, 0x83, 0x05, 0x70, 0x91, 0x9f, 0x53, 0x59, 0x2c, 0x90, 0x42, 0x34,
-	0x21, 0x33, 0x43, 0x6f, 0xd8, 0x19, 0xba, 0xce, 0x10, 0xe7, 0xcc, 0x0c, 0xf1, 0x0e, 0x74, 0x64,
-	0x3a, 0x4e, 0x94, 0xdc, 0xd4, 0x2d, 0x0c, 0x1b, 0x51, 0x95, 0xff, 0x0a, 0x22, 0xfc, 0x00, 0xba,
-	0x46, 0x0f, 0x1b, 0x30, 0x26, 0xf4, 0x3c, 0xc9, 0x5e, 0xa9, 0x92, 0x80, 0x6c, 0xea, 0xea, 0x74,
-	0xa3, 0xa8, 0x4e, 0xe3, 0xbf, 0x75, 0xa0, 0xcf, 0x74, 0x22, 0x8c, 0xc7, 0x87, 0x49, 0x14, 0x8e,
-	0x66, 0x5c, 0x37, 0xd4, 0xf6, 0x7b, 0x01, 0x89, 0xa8, 0xaf, 0x75, 0xc3, 0x86, 0x99, 0xc7, 0x9c,
-	0x84, 0x31, 0xaf, 0x79, 0x48, 0xcd, 0xd0, 0x6d, 0xa6, 0xe3, 0xcc, 0x9c, 0x1f, 0xfb, 0x39, 0xf1,
-	0x26, 0x2c, 0xb0, 0x94, 0x06, 0xcc, 0x02, 0x99, 0x59, 0x62, 0x40, 0xe6, 0x53, 0xe2, 0x4d, 0xc2,
-	0x28, 0x0a, 0x05, 0xad, 0xd0, 0xe5, 0xba, 0x2e, 0xfc, 0x0f, 0x0d, 0xe8, 0x4a, 0x83, 0xf0, 0x24,
-	0x18, 0x8b, 0xfa, 0x95, 0x74, 0xe3, 0xfa, 0xa0, 0x19, 0x88, 0xea, 0xb7, 0x1c, 0xbf, 0x81, 0x94,
-	0x37, 0x70, 0xae, 0xba, 0x81, 0x2c, 0x99, 0x4e, 0x02, 0x72, 0x97, 0x47, 0x18, 0xe2, 0x32, 0xaf,
-	0x00, 0x54, 0xef, 0x2e, 0xef, 0x6d, 0x15, 0xbd, 0x1c, 0xb0, 0x62, 0x8a, 0xf9, 0x52, 0x4c, 0x71,
-	0x0f, 0x7a, 0x92, 0x0d, 0x97, 0x3b, 0x2f, 0x8a, 0x14, 0xaa, 0x6c, 0xed, 0x89, 0x6b, 0x51, 0xaa,
-	0x2f, 0x77, 0xd5, 0x97, 0xed, 0xf7, 0x7d, 0xa9, 0x28, 0xf1, 0x3a, 0xac, 0x4a, 0xe1, 0x3d, 0xcb,
-	0xfc, 0xf4, 0x54, 0x19, 0xd9, 0x40, 0xdf, 0x2c, 0x71, 0x18, 0xdd, 0x86, 0x16, 0xfb, 0x4c, 0xd9,
-	0xb9, 0xfa, 0xe3, 0x25, 0x48, 0xd0, 0x16, 0xb4, 0x48, 0x30, 0x26, 0x2a, 0xa8, 0x45, 0x76, 0x28,
-	0xce, 0xf6, 0xc8, 0x15, 0x04, 0xec, 0xb0, 0x33, 0xb4, 0x74, 0xd8, 0x6d, 0x1b, 0x39, 0xcf, 0x9a,
-	0x9f, 0x05, 0x78, 0x0d, 0xd0, 0x73, 0xa1, 0xb5, 0x66, 0x45, 0xe6, 0x0f, 0xe6, 0xa0, 0x6b, 0xc0,
-	0xec, 0xdc, 0x8e, 0xd9, 0x84, 0xbd, 0x20, 0xf4, 0x27, 0x84, 0x92, 0x4c, 0x6a, 0x6a, 0x09, 0xe5,
-	0xa6, 0xf4, 0x6c, 0xec, 0x25, 0x53, 0xea, 0x05, 0x64, 0x9c, 0x11, 0x91, 0xe9, 0x3a, 0x6e, 0x09,
-	0x65, 0x74, 0x13, 0xff, 0xb5, 0x49, 0x27, 0xf4, 0xa1, 0x84, 0xaa, 0xfa, 0x8a, 0x90, 0x51, 0xb3,
-	0xa8, 0xaf, 0x08, 0x89, 0x94, 0x2d, 0x4e, 0xab, 0xc6, 0xe2, 0x7c, 0x02, 0x1b, 0xc2, 0xb6, 0xc8,
-	0xb3, 0xe9, 0x95, 0xd4, 0xe4, 0x82, 0x5e, 0x16, 0xa9, 0xb1, 0x39, 0x2b, 0x05, 0xcf, 0xc3, 0x9f,
-	0x89, 0xc2, 0xae, 0xe3, 0x56, 0x70, 0x46, 0xcb, 0x8e, 0xa3, 0x45, 0x2b, 0x0a, 0xbc, 0x15, 0x9c,
-	0xd3, 0xfa, 0xaf, 0x6d, 0xda, 0x8e, 0xa4, 0x2d, 0xe1, 0xb8, 0x0f, 0xdd, 0x23, 0x9a, 0xa4, 0x6a,
-	0x53, 0x16, 0xa1, 0x27, 0x9a, 0xf2, 0x02, 0xe0, 0x0a, 0x5c, 0xe6, 0x5a, 0xf4, 0x22, 0x49, 0x93,
-	0x28, 0x19, 0xcf, 0x8e, 0xa6, 0xc7, 0xf9, 0x28, 0x0b, 0x53, 0x16, 0x70, 0xe2, 0x7f, 0x76, 0x60,
-	0xd5, 0xea, 0x95, 0x19, 0xe5, 0xff, 0x17, 0x2a, 0xad, 0xeb, 0xb0, 0x42, 0xf1, 0x56, 0x0c, 0xc3,
-	0x27, 0x08, 0x45, 0x72, 0xfc, 0xa5, 0x2c, 0xcd, 0xee, 0xc1, 0x92, 0x9a, 0x99, 0xfa, 0x50, 0x68,
-	0xe1, 0xa0, 0xaa, 0x85, 0xf2, 0xfb, 0x45, 0xf9, 0x81, 0x62, 0xf1, 0x1b, 0x22, 0x18, 0x23, 0x01,
-	0x5f, 0xa3, 0xca, 0x97, 0x86, 0xea, 0x7b, 0x33, 0x00, 0x54, 0x33, 0x18, 0x69, 0x30, 0xc7, 0x7f,
-	0xec, 0x00, 0x14, 0xb3, 0x63, 0x8a, 0x51, 0x18, 0x6f, 0x87, 0x57, 0xb5, 0x0a, 0x80, 0x85, 0x4e,
-	0xba, 0x4a, 0x58, 0xf8, 0x83, 0xae, 0xc2, 0x58, 0x2c, 0x72, 0x0b, 0x96, 0xc6, 0x51, 0x72, 0xcc,
-	0xbd, 0x2b, 0xbf, 0x6b, 0xca, 0xe5, 0x35, 0xc8, 0xa2, 0x80, 0x9f, 0x4a, 0xb4, 0x70, 0x1e, 0x4d,
-	0xc3, 0x79, 0xe0, 0x3f, 0x69, 0xe8, 0xfa, 0x55, 0xb1, 0xe6, 0x0b, 0x4f, 0x19, 0xda, 0xad, 0x18,
-	0xc7, 0x0b, 0xea, 0x45, 0x3c, 0x89, 0x3e, 0x7c, 0x6f, 0x9a, 0xf4, 0x00, 0x16, 0x33, 0x61, 0x7d,
-	0x94, 0x69, 0x6a, 0xbe, 0xc3, 0x34, 0xf5, 0x33, 0xcb, 0xef, 0xfc, 0x3f, 0x58, 0xf6, 0x83, 0x33,
-	0x92, 0xd1, 0x90, 0x87, 0xc1, 0xdc, 0xbd, 0x0b, 0x83, 0xba, 0x64, 0xe0, 0xdc, 0xeb, 0xde, 0x82,
-	0x25, 0x79, 0xf5, 0xa4, 0x29, 0xe5, 0x55, 0x7e, 0x01, 0x33, 0x42, 0xfc, 0xd7, 0x8e, 0xac, 0x95,
-	0xd9, 0x7b, 0x78, 0xb1, 0x44, 0xcc, 0xd5, 0x35, 0x4a, 0xab, 0xfb, 0xb6, 0x2c, 0x7d, 0x05, 0x2a,
-	0xd6, 0x96, 0x05, 0x44, 0x01, 0xca, 0x32, 0xa3, 0x2d, 0xd2, 0xe6, 0x87, 0x88, 0x14, 0x6f, 0xc3,
-	0xd2, 0x11, 0xa1, 0x7b, 0x6c, 0x07, 0x95, 0x61, 0xbc, 0x02, 0x9d, 0x98, 0x9c, 0x7b, 0x62, 0x8b,
-	0x85, 0x1b, 0x6f, 0xc7, 0xe4, 0x9c, 0xd3, 0x60, 0x04, 0xcb, 0x05, 0xbd, 0x3c, 0x75, 0x7f, 0xda,
-	0x80, 0x85, 0xcf, 0xe2, 0xb3, 0x24, 0x1c, 0xf1, 0x62, 0xd6, 0x84, 0x4c, 0x12, 0x75, 0x89, 0xcc,
-	0x7e, 0xb3, 0xa8, 0x80, 0xdf, 0x79, 0xa4, 0x54, 0x56, 0x99, 0x54, 0x93, 0x79, 0xc8, 0xac, 0x78,
-	0xb1, 0x20, 0xb4, 0xcd, 0x40, 0x58, 0x34, 0x99, 0x99, 0x8f, 0x30, 0x64, 0xab, 0xb8, 0x41, 0x6f,
-	0x19, 0x37, 0xe8, 0xbc, 0x6c, 0x29, 0xae, 0x73, 0xf8, 0x96, 0xb4, 0x5d, 0xd5, 0xe4, 0x51, 0x6f,
-	0x46, 0xe4, 0xad, 0x1b, 0xf3, 0xb5, 0x0b, 0x32, 0xea, 0x35, 0x41, 0xe6, 0x8f, 0xc5, 0x07, 0x82,
-	0x46, 0xd8, 0x2b, 0x13, 0x62, 0xf1, 0x49, 0xf9, 0x1d, 0x47, 0x47, 0xa8, 0x49, 0x09, 0xc6, 0x5f,
-	0x01, 0xda, 0x0b, 0x02, 0x29, 0x15, 0x1d, 0xc5, 0x17, 0xeb, 0x71, 0xac, 0xf5, 0xd4, 0xf0, 0x6d,
-	0xd4, 0xf3, 0x7d, 0x02, 0xdd, 0x43, 0xe3, 0x21, 0x0a, 0x17, 0xa0, 0x7a, 0x82, 0x22, 0x85, 0x6e,
-	0x20, 0xc6, 0x80, 0x0d, 0x73, 0x40, 0xfc, 0x6b, 0x80, 0x0e, 0xc2, 0x9c, 0xea, 0xf9, 0xe9, 0xfc,
-	0x4a, 0x57, 0x79, 0x8c, 0xfc, 0x4a, 0x62, 0x3c, 0xbf, 0xda, 0x13, 0xd7, 0x4b, 0xe5, 0x85, 0xdd,
-	0x86, 0x76, 0x28, 0x20, 0x65, 0x3f, 0x17, 0xa5, 0xe2, 0x29, 0x4a, 0xdd, 0xcf, 0x02, 0x01, 0x09,
-	0x5a, 0xe6, 0xf9, 0xe7, 0x0e, 0x2c, 0xc8, 0xa5, 0x31, 0x37, 0x66, 0x3d, 0xc1, 0x11, 0x0b, 0xb3,
-	0xb0, 0xfa, 0x57, 0x14, 0xd5, 0x9d, 0x9e, 0xab, 0xdb, 0x69, 0x04, 0xcd, 0xd4, 0xa7, 0xa7, 0x3c,
-	0xc6, 0xed, 0xb8, 0xfc, 0xb7, 0xca, 0x65, 0x5a, 0x3a, 0x97, 0x51, 0x57, 0x69, 0x72, 0x52, 0xfa,
-	0x96, 0xe7, 0xa1, 0xb8, 0x4a, 0x2b, 0xe0, 0x42, 0x06, 0x72, 0x82, 0x65, 0x19, 0x48, 0x52, 0x57,
-	0xf7, 0xe3, 0x21, 0x0c, 0x1e, 0x93, 0x88, 0x50, 0xb2, 0x17, 0x45, 0x65, 0xfe, 0x57, 0xe0, 0x72,
-	0x4d, 0x9f, 0x3c, 0x6b, 0x4f, 0x61, 0xe5, 0x31, 0x39, 0x9e, 0x8e, 0x0f, 0xc8, 0x59, 0x51, 0xf2,
-	0x45, 0xd0, 0xcc, 0x4f, 0x93, 0x73, 0xb9, 0x5f, 0xfc, 0x37, 0xfa, 0x08, 0x20, 0x62, 0x34, 0x5e,
-	0x9e, 0x92, 0x91, 0x7a, 0x46, 0xc0, 0x91, 0xa3, 0x94, 0x8c, 0xf0, 0x27, 0x80, 0x4c, 0x3e, 0x72,
-	0x09, 0xec, 0x04, 0x4c, 0x8f, 0xbd, 0x7c, 0x96, 0x53, 0x32, 0x51, 0x87, 0xdf, 0x84, 0xf0, 0x2d,
-	0xe8, 0x1d, 0xfa, 0x33, 0x97, 0x7c, 0x2d, 0x5f, 0x36, 0xb1, 0x94, 0xc9, 0x9f, 0x31, 0xf5, 0xd4,
-	0x29, 0x13, 0xef, 0xc6, 0x19, 0xcc, 0x0b, 0x42, 0xc6, 0x34, 0x20, 0x39, 0x0d, 0x63, 0x51, 0x74,
-	0x95, 0x4c, 0x0d, 0xa8, 0xb2, 0xdd, 0x8d, 0x9a, 0xed, 0x96, 0x91, 0x8d, 0xba, 0x45, 0x95, 0xfb,
-	0x6a, 0x61, 0xcc, 0x38, 0x3d, 0x25, 0xc4, 0x25, 0x69, 0x92, 0xe9, 0x17, 0x55, 0x7f, 0xe9, 0xc0,
-	0xb2, 0x34, 0x7e, 0xba, 0x0f, 0xdd, 0xb0, 0x2c, 0xa5, 0x53, 0x57, 0x92, 0xbb, 0x09, 0x7d, 0x9e,
-	0x2b, 0xb0, 0x44, 0x80, 0x27, 0x06, 0x32, 0x51, 0xb6, 0x40, 0xb6, 0x36, 0x55, 0x39, 0x9a, 0x84,
-	0x91, 0x9c, 0x94, 0x09, 0x31, 0xab, 0xae, 0x72, 0x09, 0x6e, 0xc4, 0x1c, 0x57, 0xb7, 0xf1, 0x21,
-	0xac, 0x18, 0xf3, 0x95, 0x7b, 0xf0, 0x00, 0xd4, 0x0d, 0x89, 0xc8, 0x7b, 0x85, 0x2a, 0x6d, 0xda,
-	0x76, 0xbc, 0xf8, 0xcc, 0x22, 0xc6, 0x7f, 0xe7, 0x70, 0x11, 0xc8, 0x70, 0x41, 0x3f, 0xa5, 0x98,
-	0x17, 0x1e, 0x5c, 0x28, 0xc8, 0xfe, 0x25, 0x57, 0xb6, 0xd1, 0xf7, 0x3f, 0xd0, 0x09, 0xeb, 0xcb,
-	0x8c, 0x0b, 0x64, 0x33, 0x57, 0x27, 0x9b, 0x77, 0xac, 0xfc, 0xe1, 0x02, 0xb4, 0xf2, 0x51, 0x92,
-	0x12, 0xbc, 0xca, 0x45, 0xa0, 0xe6, 0x2b, 0x44, 0xb0, 0xfb, 0x37, 0x57, 0xa0, 0xa3, 0x03, 0x7e,
-	0xf4, 0x53, 0xe8, 0x5b, 0x25, 0x1d, 0x74, 0x45, 0xce, 0xb0, 0xae, 0x46, 0x34, 0xbc, 0x5a, 0xdf,
-	0x29, 0x8f, 0xcf, 0xb5, 0x6f, 0x7e, 0xf5, 0xef, 0xbf, 0x68, 0x0c, 0xd0, 0xc6, 0xce, 0xd9, 0xdd,
-	0x1d, 0x59, 0xb3, 0xd9, 0xe1, 0x25, 0x28, 0x71, 0x63, 0xf8, 0x0a, 0x16, 0xed, 0x92, 0x0f, 0xba,
-	0x6a, 0x8b, 0xa3, 0x34, 0xda, 0x47, 0x17, 0xf4, 0xca, 0xe1, 0xae, 0xf2, 0xe1, 0x36, 0xd0, 0x9a,
-	0x39, 0x9c, 0x0e, 0xc4, 0x09, 0xbf, 0xe3, 0x35, 0xdf, 0x49, 0x22, 0xc5, 0xaf, 0xfe, 0xfd, 0xe4,
-	0xf0, 0x72, 0xf5, 0x4d, 0xa4, 0x7c, 0x44, 0x89, 0x07, 0x7c, 0x28, 0x84, 0x96, 0xd9, 0x50, 0xe6,
-	0x33, 0x49, 0xf4, 0x13, 0xe8, 0xe8, 0xc7, 0x5e, 0x68, 0xd3, 0x78, 0xda, 0x66, 0x3e, 0x1f, 0x1b,
-	0x0e, 0xaa, 0x1d, 0x2a, 0xa8, 0xe6, 0x9c, 0xd7, 0x71, 0x85, 0xf3, 0x7d, 0xe7, 0x36, 0x3a, 0x80,
-	0x75, 0x69, 0xc5, 0x8f, 0xc9, 0x7f, 0x67, 0x25, 0x35, 0xaf, 0x3b, 0xef, 0x38, 0xe8, 0x01, 0xb4,
-	0xd5, 0xfb, 0x37, 0xb4, 0x51, 0xff, 0x08, 0x6f, 0xb8, 0x59, 0xc1, 0xe5, 0xc1, 0xd9, 0x03, 0x28,
-	0x9e, 0x7b, 0xa1, 0xc1, 0x45, 0xaf, 0xd2, 0xb4, 0x10, 0x6b, 0xde, 0x86, 0x8d, 0xf9, 0x6b, 0x37,
-	0xfb, 0x35, 0x19, 0xfa, 0x56, 0x41, 0x5f, 0xfb, 0xce, 0xec, 0x1d, 0x0c, 0xf1, 0x06, 0x97, 0xdd,
-	0x32, 0x5a, 0x64, 0xb2, 0x8b, 0xc9, 0xb9, 0x7a, 0xed, 0xf0, 0x18, 0xba, 0xc6, 0x13, 0x32, 0xa4,
-	0x38, 0x54, 0x9f, 0x9f, 0x0d, 0x87, 0x75, 0x5d, 0x72, 0xba, 0xbf, 0x05, 0x7d, 0xeb, 0x2d, 0x98,
-	0x3e, 0x19, 0x75, 0x2f, 0xcd, 0xf4, 0xc9, 0xa8, 0x7f, 0x3e, 0xf6, 0x63, 0xe8, 0x1a, 0x2f, 0xb7,
-	0x90, 0x71, 0x29, 0x56, 0x7a, 0x99, 0xa5, 0x67, 0x54, 0xf3, 0xd0, 0x0b, 0xaf, 0xf1, 0xf5, 0x2e,
-	0xe2, 0x0e, 0x5b, 0x2f, 0xbf, 0xf2, 0x67, 0x4a, 0xf2, 0x53, 0x58, 0xb4, 0x5f, 0x6c, 0xe9, 0x53,
-	0x55, 0xfb, 0xf6, 0x4b, 0x9f, 0xaa, 0x0b, 0x9e, 0x79, 0x49, 0x85, 0xbc, 0xbd, 0xaa, 0x07, 0xd9,
-	0x79, 0x23, 0x0b, 0x5b, 0x6f, 0xd1, 0x8f, 0x98, 0xe9, 0x90, 0x6f, 0x30, 0x50, 0xf1, 0x82, 0xcd,
-	0x7e, 0xa9, 0xa1, 0xb5, 0xbd, 0xf2, 0x5c, 0x03, 0xaf, 0x70, 0xe6, 0x5d, 0x54, 0xac, 0x00, 0x7d,
-	0x0e, 0x0b, 0xf2, 0x2d, 0x06, 0x5a, 0x2f, 0xb4, 0xda, 0x28, 0x0e, 0x0c, 0x37, 0xca, 0xb0, 0x64,
-	0xb6, 0xca, 0x99, 0xf5, 0x51, 0x97, 0x31, 0x1b, 0x13, 0x1a, 0x32, 0x1e, 0x11, 0x2c, 0xd9, 0xe5,
-	0xf9, 0x5c, 0x8b, 0xa3, 0xf6, 0x62, 0x50, 0x8b, 0xa3, 0xbe, 0xd6, 0x6f, 0x1b, 0x19, 0x65, 0x5c,
-	0x76, 0xd4, 0x9d, 0xe7, 0xef, 0x42, 0xcf, 0x7c, 0xf8, 0x83, 0x86, 0xc6, 0xca, 0x4b, 0x8f, 0x84,
-	0x86, 0x57, 0x6a, 0xfb, 0xec, 0xad, 0x45, 0x3d, 0x73, 0x18, 0xf4, 0x63, 0x58, 0x32, 0xee, 0x91,
-	0x8e, 0x66, 0xf1, 0x48, 0xab, 0x4e, 0xf5, 0x6e, 0x7a, 0x58, 0xe7, 0x5b, 0xf0, 0x26, 0x67, 0xbc,
-	0x82, 0x2d, 0xc6, 0x4c, 0x6d, 0x1e, 0x41, 0xd7, 0xbc, 0xa3, 0x7a, 0x07, 0xdf, 0x4d, 0xa3, 0xcb,
-	0xbc, 0x2d, 0xbe, 0xe3, 0xa0, 0x3f, 0x77, 0xa0, 0x67, 0x3e, 0x59, 0x40, 0x56, 0x7e, 0x5d, 0xe2,
-	0x33, 0x30, 0xfb, 0x4c, 0x46, 0xf8, 0x39, 0x9f, 0xe4, 0xfe, 0xed, 0xa7, 0x96, 0x90, 0xdf, 0x58,
-	0x31, 0xc3, 0xb6, 0xf9, 0xac, 0xf9, 0x6d, 0xb9, 0xd3, 0xbc, 0xbb, 0x7f, 0x7b, 0xc7, 0x41, 0xf7,
-	0xc5, 0xe3, 0x75, 0x15, 0xf2, 0x22, 0xc3, 0xac, 0x95, 0xc5, 0x65, 0xbe, 0x08, 0xdf, 0x72, 0xee,
-	0x38, 0xe8, 0xf7, 0xc4, 0x4b, 0x66, 0xf9, 0x2d, 0x97, 0xfa, 0x87, 0x7e, 0x8f, 0x6f, 0xf2, 0x95,
-	0x5c, 0xc3, 0x97, 0xad, 0x95, 0x94, 0xed, 0xfa, 0x21, 0x40, 0x91, 0xbf, 0xa0, 0x52, 0x30, 0xaf,
-	0x2d, 0x5e, 0x35, 0xc5, 0xb1, 0x77, 0x53, 0xc5, 0xfc, 0xc2, 0x08, 0xf4, 0x8c, 0xcc, 0x21, 0xd7,
-	0xdb, 0x59, 0xcd, 0x43, 0x86, 0xc3, 0xba, 0x2e, 0xc9, 0xff, 0xdb, 0x9c, 0xff, 0x47, 0xe8, 0x8a,
-	0xc9, 0x7f, 0xe7, 0x8d, 0x99, 0xb7, 0xbc, 0x45, 0x5f, 0x41, 0xff, 0x20, 0x49, 0x5e, 0x4d, 0x53,
-	0x9d, 0x96, 0xda, 0x91, 0x38, 0xcb, 0x9d, 0x86, 0xa5, 0x45, 0xe1, 0x1b, 0x9c, 0xf3, 0x15, 0x74,
-	0xd9, 0xe6, 0x5c, 0x64, 0x53, 0x6f, 0x91, 0x0f, 0x2b, 0xda, 0xdb, 0xe9, 0x85, 0x0c, 0x6d, 0x3e,
-	0x66, 0x52, 0x53, 0x19, 0xc3, 0x8a, 0x3f, 0xf4, 0x18, 0xb9, 0xe2, 0x79, 0xc7, 0x41, 0x87, 0xd0,
-	0x7b, 0x4c, 0x46, 0x49, 0x40, 0x64, 0xf4, 0xbc, 0x5a, 0xcc, 0x5c, 0x47, 0xdd, 0xc3, 0xbe, 0x05,
-	0xda, 0x16, 0x20, 0xf5, 0x67, 0x19, 0xf9, 0x7a, 0xe7, 0x8d, 0x0c, 0xcb, 0xdf, 0x2a, 0x0b, 0xa0,
-	0x52, 0x09, 0xcb, 0x02, 0x94, 0x72, 0x0f, 0xcb, 0x02, 0x54, 0x72, 0x0f, 0xcb, 0x02, 0xa8, 0x54,
-	0x06, 0x45, 0x2c, 0x23, 0x29, 0xa5, 0x2b, 0xda, 0x67, 0x5e, 0x94, 0xe4, 0x0c, 0xaf, 0x5f, 0x4c,
-	0x60, 0x8f, 0x76, 0xdb, 0x1e, 0xed, 0x08, 0xfa, 0x8f, 0x89, 0x10, 0x96, 0xa8, 0x17, 0x0f, 0x6d,
-	0x93, 0x62, 0xd6, 0x96, 0xcb, 0xe6, 0x86, 0xf7, 0xd9, 0x06, 0x9e, 0x17, 0x6b, 0xd1, 0x4f, 0xa0,
-	0xfb, 0x8c, 0x50, 0x55, 0x20, 0xd6, 0x91, 0x47, 0xa9, 0x62, 0x3c, 0xac, 0xa9, 0x2f, 0xe3, 0xeb,
-	0x9c, 0xdb, 0x10, 0x0d, 0x34, 0xb7, 0x1d, 0x12, 0x8c, 0x89, 0x38, 0xfc, 0x5e, 0x18, 0xbc, 0x45,
-	0xbf, 0xcd, 0x99, 0xeb, 0xdb, 0xa3, 0x0d, 0xa3, 0xae, 0x68, 0x32, 0x5f, 0x2a, 0xe1, 0x75, 0x9c,
-	0xe3, 0x24, 0x20, 0x86, 0xab, 0x8b, 0xa1, 0x6b, 0x5c, 0x15, 0xea, 0x03, 0x55, 0xbd, 0x7f, 0xd4,
-	0x07, 0xaa, 0xe6, 0x66, 0x11, 0x6f, 0xf1, 0x71, 0x30, 0xba, 0x5e, 0x8c, 0x23, 0x6e, 0x13, 0x8b,
-	0x91, 0x76, 0xde, 0xf8, 0x13, 0xfa, 0x16, 0xbd, 0xe4, 0xcf, 0x14, 0xcd, 0x22, 0x78, 0x11, 0xf9,
-	0x94, 0xeb, 0xe5, 0x5a, 0x58, 0x46, 0x97, 0x1d, 0x0d, 0x89, 0xa1, 0xb8, 0x47, 0xfc, 0x3e, 0xc0,
-	0x11, 0x4d, 0xd2, 0xc7, 0x3e, 0x99, 0x24, 0x71, 0x61, 0xc9, 0x8a, 0x42, 0x6f, 0x61, 0xc9, 0x8c,
-	0x6a, 0x2f, 0x7a, 0x69, 0xc4, 0x9e, 0xd6, 0x1d, 0x82, 0x52, 0xae, 0x0b, 0x6b, 0xc1, 0x5a, 0x20,
-	0x35, 0xf5, 0x60, 0x15, 0x86, 0x8a, 0x22, 0x97, 0x11, 0x86, 0x5a, 0x55, 0x32, 0x23, 0x0c, 0xb5,
-	0xab, 0x61, 0x2c, 0x0c, 0x2d, 0x32, 0x6b, 0x1d, 0x86, 0x56, 0x92, 0x76, 0x6d, 0x43, 0x6b, 0xd2,
-	0xf0, 0x43, 0xe8, 0x14, 0xb9, 0xaa, 0x1a, 0xa8, 0x9c, 0xd9, 0x6a, 0x67, 0x55, 0x49, 0x21, 0xf1,
-	0x32, 0x97, 0x33, 0xa0, 0x36, 0x93, 0x33, 0xbf, 0x2a, 0x7d, 0x01, 0x20, 0x56, 0xf7, 0x94, 0xb5,
-	0x0c, 0x96, 0x56, 0xa6, 0x68, 0xb2, 0xb4, 0x53, 0x32, 0x15, 0xc9, 0x60, 0xcd, 0xf2, 0xbe, 0x73,
-	0xfb, 0x78, 0x9e, 0xff, 0x8f, 0xdb, 0xf7, 0xfe, 0x2b, 0x00, 0x00, 0xff, 0xff, 0x7e, 0x4b, 0xa3,
-	0x9d, 0x15, 0x37, 0x00, 0x00,
+	// 8139 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xdd, 0x3d, 0x4b, 0x70, 0x24, 0xc9,
+	0x55, 0xdb, 0xdd, 0xfa, 0x66, 0xb7, 0x7e, 0xa5, 0x5f, 0x4f, 0xcf, 0xec, 0xcc, 0x6e, 0xcd, 0xe2,
+	0x5d, 0x8f, 0x8d, 0x66, 0x57, 0xb6, 0x77, 0xd7, 0xbb, 0x06, 0xa3, 0x91, 0x5a, 0x2b, 0xb1, 0x1a,
+	0x49, 0x2e, 0x69, 0x76, 0xfc, 0x09, 0xd3, 0x94, 0xba, 0x4b, 0x52, 0x7b, 0xba, 0xbb, 0xda, 0x5d,
+	0xd5, 0x33, 0x23, 0x6f, 0x0c, 0x01, 0x0e, 0x02, 0x38, 0x00, 0x01, 0x38, 0x02, 0x08, 0x0e, 0x0e,
+	0x07, 0x3e, 0x71, 0xc0, 0x8e, 0xe0, 0x0a, 0x1c, 0xe0, 0xc0, 0x81, 0x80, 0x20, 0x08, 0x47, 0x10,
+	0x01, 0x07, 0x22, 0x88, 0xe0, 0xc2, 0x81, 0x83, 0x0f, 0x9c, 0xe1, 0xbd, 0x97, 0x2f, 0xb3, 0x32,
+	0xab, 0xaa, 0xa5, 0x31, 0x5e, 0x73, 0xe0, 0x32, 0xea, 0x7c, 0xf9, 0x2a, 0x3f, 0x2f, 0x5f, 0xbe,
+	0x5f, 0xbe, 0xcc, 0x11, 0xd3, 0x83, 0x7e, 0x73, 0xad, 0x3f, 0x08, 0xe3, 0xd0, 0x19, 0xef, 0xf4,
+	0xa0, 0x50, 0xbb, 0x71, 0x16, 0x86, 0x67, 0x9d, 0xe0, 0xae, 0xdf, 0x6f, 0xdf, 0xf5, 0x7b, 0xbd,
+	0x30, 0xf6, 0xe3, 0x76, 0xd8, 0x8b, 0x24, 0x92, 0x5b, 0x15, 0x2b, 0xf7, 0xdb, 0x67, 0x03, 0x82,
+	0x1d, 0x41, 0xd5, 0x30, 0xf2, 0x82, 0xaf, 0x0f, 0x83, 0x28, 0x76, 0x7f, 0xb7, 0x28, 0x56, 0x33,
+	0x55, 0x51, 0x1f, 0x3e, 0x0d, 0x9c, 0x1b, 0x62, 0xba, 0x2b, 0xab, 0x7a, 0x67, 0xd5, 0xc2, 0x4b,
+	0x85, 0xd7, 0xa6, 0xbc, 0x04, 0xe0, 0xbc, 0x26, 0xe6, 0x9a, 0xc3, 0xc1, 0x20, 0xe8, 0xc5, 0x8d,
+	0xc7, 0xc1, 0x20, 0x82, 0xcf, 0xab, 0x45, 0xc0, 0x99, 0xf1, 0xd2, 0x60, 0xe7, 0x63, 0x62, 0xb6,
+	0xe3, 0xc7, 0xd0, 0x9b, 0x46, 0x2c, 0x11, 0x62, 0x0a, 0x6a, 0xf4, 0x07, 0x28, 0x63, 0x84, 0x92,
+	0x00, 0xb0, 0x95, 0x76, 0x1c, 0x74, 0xa3, 0x86, 0x04, 0x05, 0xad, 0xea, 0x38, 0xa0, 0x8c, 0x79,
+	0x29, 0xa8, 0xf3, 0x92, 0x28, 0xc7, 0x30, 0xfd, 0x4e, 0x83, 0xe0, 0xd5, 0x09, 0x42, 0x32, 0x41,
+	0xce, 0x4d, 0x21, 0xa2, 0xd8, 0x1f, 0xc4, 0x8d, 0xb8, 0xdd, 0x0d, 0xaa, 0x93, 0x80, 0x50, 0xf2,
+	0x0c, 0x88, 0xfb, 0xc3, 0x82, 0x28, 0x1f, 0x0f, 0xfc, 0x5e, 0xe4, 0x37, 0xa9, 0xe7, 0xaa, 0x98,
+	0x8c, 0x9f, 0x36, 0xce, 0xfd, 0xe8, 0x9c, 0xa8, 0x30, 0xed, 0xa9, 0xa2, 0xb3, 0x22, 0x26, 0xfc,
+	0x6e, 0x38, 0xec, 0xc5, 0x34, 0xf5, 0x92, 0xc7, 0x25, 0xe7, 0x93, 0x62, 0xa1, 0x37, 0xec, 0x36,
+	0x9a, 0x61, 0xef, 0xb4, 0x3d, 0xe8, 0xca, 0xa5, 0xa0, 0x49, 0x8f, 0x7b, 0xd9, 0x0a, 0x1c, 0xcf,
+	0x49, 0x27, 0x6c, 0x3e, 0x92, 0x5d, 0x8c, 0x51, 0x17, 0x06, 0xc4, 0x71, 0x45, 0x85, 0x4b, 0x41,
+	0xfb, 0xec, 0x3c, 0xa6, 0x79, 0x8f, 0x7b, 0x16, 0x0c, 0xdb, 0xc0, 0xb1, 0x37, 0x60, 0x1a, 0xdd,
+	0x3e, 0x4d, 0x1a, 0xe6, 0x94, 0x40, 0xa8, 0x9e, 0x48, 0x70, 0x1a, 0x04, 0x91, 0x9a, 0x73, 0x02,
+	0x41, 0x0e, 0x79, 0x2f, 0x88, 0x8d, 0x59, 0x6b, 0x0e, 0xd9, 0x13, 0x8e, 0x01, 0xde, 0x0a, 0x62,
+	0xbf, 0xdd, 0x89, 0x9c, 0x37, 0x45, 0x25, 0x36, 0x90, 0x81, 0x30, 0xa5, 0xd7, 0xca, 0xeb, 0xce,
+	0x1a, 0x71, 0xe3, 0x9a, 0xf1, 0x81, 0x67, 0xe1, 0xb9, 0xbf, 0x36, 0x26, 0xca, 0x47, 0x41, 0xaf,
+	0xc5, 0xad, 0x3b, 0x8e, 0x18, 0x6b, 0xc1, 0x5f, 0x22, 0x6c, 0xc5, 0xa3, 0xdf, 0xce, 0x2d, 0x51,
+	0xc6, 0xbf, 0x30, 0xf2, 0x01, 0x72, 0x5e, 0x51, 0x12, 0x04, 0x41, 0x47, 0x04, 0x71, 0xe6, 0x45,
+	0xc9, 0xef, 0xc6, 0x44, 0xd0, 0x92, 0x87, 0x3f, 0x9d, 0x97, 0x45, 0xa5, 0xef, 0x5f, 0x74, 0x91,
+	0xeb, 0x34, 0x11, 0x2b, 0x5e, 0x99, 0x61, 0x3b, 0x48, 0xc5, 0x35, 0xb1, 0x68, 0xa2, 0xa8, 0xd6,
+	0xc7, 0xa9, 0xf5, 0x05, 0x03, 0x93, 0x3b, 0x79, 0x55, 0xcc, 0x29, 0xfc, 0x81, 0x1c, 0x2c, 0x91,
+	0x75, 0xda, 0x9b, 0x65, 0xb0, 0x9a, 0x82, 0x2b, 0x66, 0x80, 0x84, 0x8d, 0x4e, 0xbb, 0xdb, 0x86,
+	0x31, 0xfb, 0x31, 0x53, 0xb7, 0x0c, 0xc0, 0x3d, 0x84, 0x1d, 0xf9, 0xb1, 0x73, 0x47, 0x2c, 0x84,
+	0xc3, 0xf8, 0x2c, 0x84, 0x86, 0x1b, 0xcd, 0x73, 0xbf, 0xd7, 0x68, 0xb7, 0xa2, 0xea, 0x14, 0xd0,
+	0x6c, 0xcc, 0x9b, 0x53, 0x15, 0x9b, 0x00, 0xdf, 0x6d, 0x45, 0xc0, 0xe8, 0x73, 0x1d, 0x1f, 0xa6,
+	0x7f, 0x1e, 0xf6, 0x1b, 0xfd, 0xe1, 0xc9, 0xa3, 0xe0, 0xa2, 0x3a, 0x4d, 0xd3, 0x99, 0x41, 0xf0,
+	0x4e, 0xd8, 0x3f, 0x24, 0x20, 0xb6, 0x99, 0xf4, 0xdb, 0x0f, 0x06, 0x4d, 0x18, 0x53, 0x55, 0x50,
+	0xdf, 0x73, 0xaa, 0xef, 0x43, 0x09, 0x76, 0x5e, 0x14, 0xa2, 0xd9, 0x89, 0x1f, 0x4b, 0xe4, 0x6a,
+	0x59, 0xee, 0x2d, 0x84, 0x10, 0x96, 0x73, 0x1d, 0x76, 0x9e, 0xff, 0xb4, 0xd1, 0x87, 0x2d, 0x10,
+	0x55, 0x2b, 0x54, 0x3b, 0x05, 0x80, 0x43, 0x2c, 0x9b, 0xb4, 0xf5, 0x5b, 0xad, 0x41, 0x75, 0xc6,
+	0xa2, 0xed, 0x06, 0x80, 0x9c, 0x37, 0x44, 0x79, 0x00, 0xd3, 0x08, 0x1a, 0xe7, 0xed, 0x1e, 0xb4,
+	0x30, 0x4b, 0xcc, 0x30, 0xcf, 0xcc, 0xe0, 0x61, 0xcd, 0x0e, 0x54, 0x78, 0x62, 0xa0, 0x7e, 0x46,
+	0xee, 0x7f, 0x14, 0x44, 0x45, 0x32, 0x02, 0x4b, 0x9b, 0x57, 0xc4, 0x8c, 0xea, 0x26, 0x18, 0x0c,
+	0xc2, 0x01, 0xef, 0x35, 0x1b, 0x08, 0x93, 0x9e, 0x57, 0x80, 0xfe, 0x20, 0x68, 0x77, 0xfd, 0xb3,
+	0x80, 0x18, 0xa4, 0xe2, 0x65, 0xe0, 0xce, 0x7a, 0xd2, 0x22, 0x75, 0x4c, 0x0c, 0x53, 0x5e, 0xaf,
+	0x98, 0xe3, 0xf2, 0x6c, 0x14, 0xe7, 0x48, 0xac, 0x28, 0xc0, 0x29, 0x30, 0xfa, 0x70, 0x10, 0xc0,
+	0xea, 0xfb, 0x11, 0x0b, 0xa4, 0xd9, 0xf5, 0xeb, 0xfc, 0xf1, 0xa1, 0x44, 0xda, 0x96, 0x38, 0x1e,
+	0xa1, 0x78, 0x23, 0x3e, 0x75, 0xbf, 0x09, 0x73, 0xc5, 0xd5, 0xed, 0x05, 0x9d, 0x43, 0x58, 0x69,
+	0x64, 0x99, 0xca, 0xe9, 0xb0, 0xd7, 0x42, 0x6e, 0x88, 0x9f, 0xb6, 0x5b, 0xcc, 0xfd, 0x16, 0x0c,
+	0x67, 0x6a, 0x96, 0x91, 0x5f, 0x79, 0x2b, 0x64, 0xe0, 0xd8, 0x1e, 0x8c, 0xbe, 0x3f, 0x8c, 0x1b,
+	0xed, 0x5e, 0x2b, 0x78, 0xca, 0xf2, 0xd5, 0x82, 0xb9, 0x3f, 0x2b, 0xe6, 0xf7, 0x50, 0x54, 0xf4,
+	0xe0, 0x4b, 0x5c, 0xb4, 0x20, 0x8a, 0x50, 0x7e, 0x31, 0x87, 0x49, 0x62, 0x73, 0x09, 0x77, 0xe5,
+	0x79, 0x18, 0xc5, 0xdc, 0x1f, 0xfd, 0x76, 0xbf, 0x53, 0x10, 0x73, 0xb8, 0x60, 0xf7, 0xfd, 0xde,
+	0x85, 0x62, 0xfd, 0x3d, 0x51, 0xc1, 0xa6, 0x8e, 0xc3, 0x0d, 0x29, 0x05, 0xa5, 0x14, 0x78, 0x8d,
+	0x69, 0x94, 0xc2, 0x5e, 0x33, 0x51, 0xeb, 0xbd, 0x78, 0x70, 0xe1, 0x59, 0x5f, 0xd7, 0x3e, 0x2f,
+	0x16, 0x32, 0x28, 0xb8, 0xd7, 0x93, 0xf1, 0xe1, 0x4f, 0x67, 0x49, 0x8c, 0x3f, 0xf6, 0x3b, 0xc3,
+	0x80, 0x65, 0xae, 0x2c, 0xbc, 0x53, 0x7c, 0xbb, 0xe0, 0x7e, 0x4c, 0xcc, 0x27, 0x7d, 0x32, 0x5b,
+	0xc1, 0x54, 0x34, 0x89, 0x61, 0x2a, 0xf8, 0x1b, 0x49, 0x81, 0x78, 0x9b, 0xb0, 0x16, 0x91, 0x21,
+	0x88, 0x88, 0xbb, 0x19, 0x0f, 0x7f, 0x8f, 0x12, 0xef, 0xee, 0xab, 0x62, 0xc1, 0xf8, 0xfe, 0x92,
+	0x8e, 0xbe, 0x5d, 0x10, 0x0b, 0xfb, 0xc1, 0x13, 0x26, 0xb7, 0xea, 0xea, 0x6d, 0xc0, 0xbc, 0xe8,
+	0x07, 0x84, 0x39, 0xbb, 0xfe, 0x0a, 0x53, 0x2b, 0x83, 0xb7, 0xc6, 0xc5, 0x63, 0xc0, 0xf5, 0xe8,
+	0x0b, 0xf7, 0x40, 0x94, 0x0d, 0xa0, 0xb3, 0x2a, 0x16, 0x1f, 0xee, 0x1e, 0xef, 0xd7, 0x8f, 0x8e,
+	0x1a, 0x87, 0x0f, 0xee, 0xbd, 0x5f, 0xff, 0x52, 0x63, 0x67, 0xe3, 0x68, 0x67, 0xfe, 0x05, 0x18,
+	0xb8, 0x03, 0xd0, 0xe3, 0xfa, 0x96, 0x05, 0x2f, 0x38, 0x73, 0xa2, 0x6c, 0x02, 0x8a, 0x6e, 0x4d,
+	0x54, 0xa1, 0xdf, 0x87, 0xed, 0xb8, 0x07, 0x6d, 0xda, 0xdd, 0xbb, 0x6b, 0xd0, 0x88, 0x31, 0x26,
+	0x9e, 0x26, 0x28, 0x43, 0x5f, 0x82, 0x94, 0x32, 0xe4, 0x22, 0x50, 0xdf, 0x39, 0x6a, 0x9f, 0xf5,
+	0xee, 0xc3, 0x6f, 0xd8, 0x7d, 0x6a, 0xb2, 0xb0, 0x7e, 0xdd, 0xe8, 0x8c, 0x39, 0x1c, 0x7f, 0xba,
+	0x9f, 0x12, 0x8b, 0x16, 0x5e, 0x62, 0x6d, 0x44, 0x00, 0x06, 0x0b, 0x64, 0x10, 0x70, 0xd3, 0x09,
+	0xc0, 0xdd, 0x16, 0x4b, 0x1f, 0x04, 0x83, 0xf6, 0xe9, 0xc5, 0x55, 0xcd, 0xdb, 0xed, 0x14, 0xd3,
+	0xed, 0xd4, 0xc5, 0x72, 0xaa, 0x1d, 0xee, 0x5e, 0x72, 0x15, 0xaf, 0xdf, 0x94, 0x27, 0x0b, 0xc6,
+	0x06, 0x29, 0x9a, 0x1b, 0xc4, 0x7d, 0x20, 0x9c, 0xcd, 0x10, 0xf6, 0x73, 0x13, 0x24, 0x6c, 0x30,
+	0x50, 0x83, 0xf9, 0x84, 0xc1, 0x43, 0xe5, 0xf5, 0x55, 0x5e, 0xd8, 0xf4, 0xae, 0x63, 0xe6, 0x02,
+	0x7e, 0x01, 0xa1, 0xdd, 0xa5, 0x86, 0xa7, 0x3c, 0xfa, 0xed, 0xde, 0x15, 0x8b, 0x56, 0xb3, 0x09,
+	0xcd, 0xfb, 0x50, 0x6e, 0xf0, 0xe8, 0xc6, 0x3d, 0x55, 0x74, 0xdf, 0x10, 0xcb, 0x5b, 0xed, 0xa8,
+	0x99, 0x1d, 0x0a, 0x7e, 0x32, 0x3c, 0x69, 0x24, 0x5b, 0x47, 0x15, 0x51, 0xd3, 0xa7, 0x3f, 0x91,
+	0xdd, 0xb8, 0x7f, 0x56, 0x10, 0x63, 0x3b, 0xc7, 0x7b, 0x9b, 0x4e, 0x4d, 0x4c, 0xb5, 0x7b, 0xcd,
+	0xb0, 0x9b, 0xd8, 0x7d, 0xba, 0x3c, 0xd2, 0xe4, 0x01, 0xb2, 0x93, 0x5a, 0x45, 0xa3, 0x84, 0xe4,
+	0x4f, 0xc5, 0x4b, 0x00, 0x68, 0x10, 0x05, 0x4f, 0xfb, 0x6d, 0x69, 0xca, 0x29, 0x3b, 0x46, 0x9a,
+	0x78, 0xd9, 0x0a, 0x14, 0x7d, 0x83, 0xe0, 0x71, 0xd8, 0x94, 0xc0, 0x56, 0xd0, 0xf1, 0x2f, 0x48,
+	0x4f, 0xcf, 0x78, 0x19, 0xb8, 0xfb, 0x37, 0x13, 0x62, 0x66, 0x03, 0x8c, 0x8b, 0xc7, 0x01, 0x4b,
+	0x58, 0x1a, 0x21, 0x01, 0x78, 0xec, 0x5c, 0x42, 0x05, 0x33, 0x08, 0xba, 0x21, 0x68, 0x29, 0x6b,
+	0x49, 0x6d, 0x20, 0x62, 0x35, 0x65, 0x43, 0x8d, 0x3e, 0xca, 0x6a, 0x9a, 0x0b, 0x60, 0x59, 0x40,
+	0x24, 0x2f, 0xab, 0x71, 0x9a, 0xc5, 0x98, 0xa7, 0x8a, 0x48, 0xbb, 0xa6, 0xdf, 0xf7, 0x9b, 0xed,
+	0x58, 0x8e, 0xb9, 0xe4, 0xe9, 0x32, 0xb6, 0x0d, 0xd4, 0x00, 0x93, 0xeb, 0xc4, 0xef, 0xf8, 0xbd,
+	0x66, 0xc0, 0x76, 0x9a, 0x0d, 0x44, 0x43, 0x97, 0x87, 0xa4, 0xd0, 0xa4, 0x41, 0x91, 0x82, 0xa2,
+	0x49, 0x07, 0x6b, 0x82, 0xca, 0x1f, 0xb4, 0x3d, 0x18, 0x13, 0x64, 0xd2, 0x25, 0x10, 0x9a, 0x89,
+	0x2c, 0x3d, 0x91, 0xf4, 0x9e, 0x96, 0xbd, 0x59, 0x40, 0x6c, 0x05, 0xad, 0x08, 0x60, 0xbf, 0xc6,
+	0xa3, 0x27, 0x6c, 0x3e, 0x18, 0x10, 0x5c, 0xb9, 0x21, 0x30, 0x47, 0x1c, 0x77, 0x82, 0x96, 0x1e,
+	0x50, 0x99, 0xd0, 0xb2, 0x15, 0xce, 0xeb, 0x62, 0x51, 0x1a, 0x95, 0x60, 0x07, 0x85, 0xd1, 0x79,
+	0x3b, 0x6a, 0x44, 0x68, 0x95, 0x54, 0x08, 0x3f, 0xaf, 0x0a, 0x84, 0xe1, 0x6a, 0x0a, 0x3c, 0x08,
+	0x9a, 0x01, 0xac, 0x57, 0x8b, 0x0c, 0x8d, 0x92, 0x37, 0xaa, 0x1a, 0x0d, 0x7d, 0xb4, 0xa5, 0x87,
+	0xfd, 0x16, 0xba, 0x11, 0x60, 0x74, 0x90, 0xa1, 0x6f, 0x80, 0xc0, 0x2c, 0x99, 0xe9, 0x07, 0x52,
+	0x55, 0x9e, 0xc7, 0x9d, 0x66, 0x54, 0x9d, 0x23, 0xfd, 0x54, 0xe6, 0x8d, 0x89, 0xbc, 0xee, 0xd9,
+	0x18, 0x38, 0x5d, 0x5a, 0xc9, 0x88, 0x5c, 0xa1, 0xc6, 0x69, 0xc7, 0x3f, 0x8b, 0xaa, 0xf3, 0xd2,
+	0x46, 0xcc, 0x54, 0x20, 0xa3, 0xca, 0xb5, 0x6b, 0x0d, 0xc1, 0x60, 0x93, 0xc6, 0xd5, 0x02, 0x8d,
+	0x3a, 0x03, 0xc7, 0x96, 0x79, 0x01, 0x0d, 0x64, 0x47, 0x12, 0x32, 0x53, 0x81, 0xdb, 0xa9, 0xdd,
+	0x6b, 0xc7, 0x6d, 0x98, 0xf5, 0xa0, 0xba, 0x28, 0x7d, 0x2f, 0x0d, 0x40, 0x32, 0x9b, 0x2e, 0x84,
+	0xda, 0x50, 0x4b, 0xb4, 0x47, 0xf2, 0xaa, 0x90, 0x58, 0xca, 0x6a, 0x40, 0x6e, 0x59, 0x66, 0x13,
+	0x35, 0x01, 0xb9, 0xcb, 0x62, 0x71, 0xaf, 0x1d, 0xc5, 0xbc, 0x8b, 0xb4, 0x16, 0xd8, 0x11, 0x4b,
+	0x36, 0x98, 0x65, 0xd2, 0xeb, 0xc0, 0xe7, 0x0c, 0x03, 0x76, 0x40, 0xb2, 0x2e, 0x31, 0x59, 0xad,
+	0xdd, 0xe8, 0x69, 0x2c, 0xf7, 0x57, 0x8b, 0x62, 0x96, 0x48, 0x1e, 0x44, 0x61, 0x67, 0x48, 0x9e,
+	0xd5, 0x65, 0x82, 0x06, 0x46, 0x2c, 0x45, 0x4b, 0xa3, 0x8b, 0x46, 0x75, 0x51, 0x2e, 0xaf, 0x01,
+	0xfa, 0x48, 0x45, 0xce, 0x5b, 0x62, 0x12, 0xac, 0x25, 0xe8, 0x3a, 0xa0, 0x5d, 0x3b, 0xbb, 0xfe,
+	0xa2, 0xc9, 0x24, 0x7a, 0xc4, 0x6b, 0x07, 0x12, 0xc9, 0x53, 0xd8, 0x20, 0xb2, 0x27, 0x19, 0xe6,
+	0x94, 0xc5, 0xe4, 0xf1, 0xee, 0xfd, 0xfa, 0xc1, 0x83, 0x63, 0x50, 0xc1, 0x33, 0x62, 0xfa, 0xc1,
+	0xfe, 0xe6, 0xde, 0x06, 0x00, 0xb6, 0x40, 0xf3, 0x4e, 0x89, 0xb1, 0xad, 0x07, 0x47, 0xc7, 0xa0,
+	0x72, 0x7f, 0x7d, 0x0c, 0x84, 0xbc, 0xa4, 0xc9, 0x66, 0x27, 0x8c, 0x82, 0xa3, 0x61, 0xb7, 0xeb,
+	0x0f, 0x72, 0x04, 0x4f, 0x21, 0x4f, 0xf0, 0xa0, 0xd7, 0x0d, 0x5f, 0x49, 0xeb, 0x4f, 0xfa, 0x3a,
+	0x52, 0x8c, 0xa5, 0xc1, 0x59, 0x71, 0x57, 0xca, 0x13, 0x77, 0xa6, 0xb8, 0x1a, 0x4b, 0x89, 0x2b,
+	0xe8, 0x2b, 0xbd, 0xf1, 0xa5, 0x44, 0x9b, 0xcb, 0xdb, 0xf6, 0xe8, 0x6b, 0x22, 0xe1, 0x0d, 0xec,
+	0x09, 0xde, 0xf6, 0xd9, 0x2a, 0x67, 0x1b, 0x1d, 0x12, 0x98, 0x7d, 0x83, 0x2c, 0xa1, 0x49, 0x22,
+	0xf9, 0xc7, 0x98, 0xe4, 0x39, 0xd4, 0x59, 0xc3, 0x02, 0xe8, 0x6f, 0xb2, 0x85, 0x8c, 0x2f, 0xa5,
+	0x6a, 0x24, 0x26, 0x26, 0x09, 0x38, 0xe5, 0xa9, 0xa2, 0xb3, 0x21, 0xe6, 0x71, 0x4b, 0x83, 0xbc,
+	0x50, 0x8b, 0x17, 0x81, 0x04, 0x44, 0x46, 0x5d, 0xce, 0x5d, 0x5a, 0x2f, 0x83, 0xee, 0x7e, 0x55,
+	0x94, 0x8d, 0x7e, 0x9d, 0x65, 0xb1, 0xb0, 0x79, 0x70, 0x70, 0x58, 0xf7, 0x36, 0x8e, 0x77, 0x3f,
+	0xa8, 0x37, 0x36, 0xf7, 0x0e, 0x8e, 0xea, 0xb0, 0xd2, 0x60, 0x54, 0x6d, 0x1f, 0x78, 0x9b, 0x0a,
+	0x50, 0x00, 0x9b, 0xa4, 0x72, 0xcf, 0xab, 0x6f, 0x6c, 0xee, 0x30, 0xa4, 0x08, 0xc6, 0xc5, 0xfc,
+	0xf6, 0x83, 0xfd, 0xad, 0xdd, 0xfd, 0xf7, 0x1a, 0x9b, 0x1b, 0xfb, 0x9b, 0xf5, 0x3d, 0xe0, 0x89,
+	0x92, 0xfb, 0x7b, 0x05, 0xb1, 0x4c, 0x93, 0x6c, 0xa5, 0x36, 0x1d, 0xf2, 0x7e, 0x33, 0x0c, 0x41,
+	0x02, 0xfb, 0x86, 0x1e, 0x33, 0x41, 0x68, 0xae, 0x9c, 0x86, 0xe0, 0xdb, 0xb1, 0xf9, 0x20, 0x0b,
+	0xa8, 0xfa, 0x4e, 0xc0, 0xe7, 0x68, 0x9e, 0xd3, 0x62, 0x83, 0xea, 0x93, 0x25, 0xe7, 0xe3, 0x89,
+	0x2f, 0xd1, 0x44, 0xf2, 0xc3, 0xda, 0xd1, 0x6a, 0x4f, 0x81, 0xa7, 0x28, 0xe1, 0x9b, 0x0c, 0x76,
+	0x0f, 0xc5, 0x4a, 0x7a, 0x4c, 0xbc, 0xe3, 0xdf, 0x34, 0x76, 0xbc, 0x34, 0xf4, 0x6b, 0xa3, 0x17,
+	0xcc, 0xde, 0xf7, 0x63, 0x68, 0x67, 0x8c, 0xb6, 0x49, 0x4c, 0x03, 0xa7, 0x68, 0x19, 0x38, 0xa6,
+	0xb9, 0x59, 0xb2, 0xcc, 0x4d, 0x8a, 0x9a, 0x5c, 0x80, 0x94, 0x97, 0x1a, 0x46, 0x6a, 0x61, 0x03,
+	0x92, 0xd4, 0x83, 0xc2, 0x78, 0xcc, 0xb1, 0x22, 0x03, 0x82, 0x9c, 0x0f, 0x42, 0x44, 0x7e, 0x2d,
+	0x19, 0x55, 0x97, 0x55, 0x1d, 0x7d, 0x39, 0x99, 0xd4, 0xd1, 0x77, 0x30, 0xa2, 0x76, 0xef, 0x04,
+	0xa4, 0x50, 0x4b, 0x71, 0x1c, 0x17, 0x51, 0x1e, 0xf5, 0x69, 0x07, 0x62, 0x58, 0x49, 0x2a, 0xdb,
+	0x04, 0xe0, 0x3a, 0xe8, 0x7f, 0x45, 0x64, 0x71, 0x69, 0xe1, 0xfa, 0xa6, 0x58, 0x30, 0x60, 0x4c,
+	0xe7, 0x97, 0xc5, 0x38, 0xce, 0x5e, 0x11, 0x59, 0x69, 0x2b, 0x32, 0xd5, 0x64, 0x8d, 0x3b, 0x2f,
+	0x66, 0xdf, 0x0b, 0xe2, 0xdd, 0xde, 0x69, 0xa8, 0x5a, 0xfa, 0xaf, 0xa2, 0x98, 0xd3, 0x20, 0x6e,
+	0x08, 0xf6, 0x6f, 0xbb, 0x05, 0xd3, 0x81, 0xbd, 0xdc, 0xb0, 0xdc, 0xbc, 0x34, 0x18, 0xb9, 0x09,
+	0xcc, 0x5d, 0x3f, 0x62, 0x59, 0x22, 0x0b, 0xe0, 0x3f, 0x2f, 0xa1, 0x36, 0x55, 0x0a, 0x52, 0x2f,
+	0xbe, 0xf4, 0x2e, 0x73, 0xeb, 0x50, 0x12, 0x20, 0x5c, 0x9a, 0x5c, 0xc9, 0x27, 0x52, 0xee, 0xe6,
+	0x55, 0x21, 0xd5, 0x64, 0x4b, 0x38, 0x65, 0x69, 0xe5, 0x25, 0x80, 0x4c, 0xec, 0x6b, 0x42, 0x7a,
+	0xb6, 0xe9, 0xd8, 0x97, 0x11, 0x3f, 0x9b, 0xca, 0xc4, 0xcf, 0x50, 0x8e, 0x5d, 0x00, 0x7b, 0xb7,
+	0x1a, 0x71, 0x88, 0xfd, 0xb6, 0x7b, 0xb4, 0x3a, 0xc0, 0xfc, 0x29, 0x30, 0x45, 0xfa, 0x80, 0x9a,
+	0xbd, 0x40, 0x06, 0x52, 0x60, 0x6d, 0xb9, 0x88, 0x3b, 0x8b, 0x50, 0xa4, 0xb2, 0x03, 0x47, 0x40,
+	0x96, 0xdc, 0x6f, 0x90, 0x23, 0xa0, 0xd5, 0xed, 0x03, 0xb2, 0x3c, 0x30, 0x9e, 0x22, 0xfb, 0x8f,
+	0xce, 0x7d, 0xf6, 0x4d, 0xa6, 0x08, 0x70, 0x74, 0xee, 0x63, 0x3c, 0xc5, 0x9a, 0x92, 0xe4, 0xf8,
+	0x32, 0xc1, 0x76, 0xe4, 0x8c, 0x5e, 0x11, 0xb3, 0x2a, 0x4c, 0x18, 0x35, 0x3a, 0xc1, 0x69, 0xac,
+	0x3c, 0x7a, 0x80, 0x62, 0x77, 0xd1, 0x1e, 0xc0, 0xdc, 0x7d, 0x90, 0x47, 0x92, 0x8a, 0x07, 0xb0,
+	0x0e, 0xdc, 0xf5, 0x67, 0xf3, 0xd4, 0x48, 0x79, 0x7d, 0xd1, 0xde, 0xaa, 0x14, 0x86, 0x48, 0xe9,
+	0x16, 0xd7, 0x83, 0xb9, 0x18, 0x3b, 0x99, 0x1b, 0x84, 0x15, 0x48, 0x54, 0x4b, 0x12, 0xab, 0x30,
+	0x61, 0x48, 0xb7, 0x68, 0xd8, 0x6c, 0xe2, 0x2e, 0x95, 0xf2, 0x48, 0x15, 0xdd, 0xdf, 0x29, 0x80,
+	0xb6, 0xc3, 0xd6, 0x94, 0x3d, 0xa0, 0x7d, 0xe0, 0xe7, 0x1f, 0x66, 0xa5, 0x69, 0xc6, 0x4e, 0xf2,
+	0x25, 0x1f, 0x48, 0x38, 0xf0, 0x07, 0x80, 0xb1, 0x06, 0x17, 0x0d, 0x5b, 0x60, 0xcc, 0x29, 0x38,
+	0xbb, 0x5f, 0xee, 0x3f, 0x83, 0x53, 0x2e, 0x45, 0x15, 0x99, 0x72, 0x3c, 0xcd, 0xcf, 0xc1, 0x80,
+	0x48, 0xad, 0x28, 0x75, 0x22, 0x07, 0xb4, 0xa4, 0x77, 0x1f, 0x41, 0x25, 0xf2, 0xce, 0x0b, 0x9e,
+	0x8d, 0xec, 0x7c, 0x1e, 0x88, 0x64, 0xb0, 0x01, 0x8d, 0xad, 0xbc, 0x7e, 0x4d, 0xcd, 0x26, 0xc3,
+	0x21, 0xd0, 0x82, 0xf5, 0x81, 0xf3, 0x2e, 0xe8, 0x43, 0x34, 0x2f, 0xa9, 0x59, 0x0e, 0x54, 0x5d,
+	0xcb, 0x11, 0xaf, 0xfa, 0x73, 0x03, 0xfd, 0xde, 0x94, 0x98, 0x90, 0x26, 0xaf, 0xfb, 0x9e, 0x98,
+	0xb1, 0x46, 0x6a, 0x45, 0x25, 0x2a, 0x32, 0x2a, 0x91, 0x89, 0x16, 0x15, 0x73, 0xa2, 0x45, 0x7f,
+	0x5d, 0x14, 0x0e, 0x72, 0x55, 0x6a, 0xd9, 0xc0, 0x37, 0x89, 0xfd, 0xc1, 0x59, 0x10, 0x37, 0x6c,
+	0x87, 0x34, 0x05, 0x25, 0xdb, 0x3c, 0x6c, 0x59, 0x9e, 0x56, 0xc5, 0x33, 0x41, 0xce, 0x9a, 0x70,
+	0x8c, 0xa2, 0x8a, 0xc6, 0xca, 0x25, 0xcb, 0xa9, 0x41, 0x61, 0x24, 0x4d, 0x6a, 0xa5, 0xc8, 0xd8,
+	0x0b, 0x95, 0x46, 0x4b, 0x6e, 0x1d, 0x8a, 0xf1, 0xfe, 0x10, 0x43, 0xbd, 0x7e, 0xac, 0x7c, 0x31,
+	0x55, 0x46, 0xa1, 0x61, 0xd8, 0xe1, 0x1c, 0x30, 0xb7, 0x0d, 0x70, 0x1a, 0x05, 0x39, 0xf4, 0x93,
+	0x32, 0x8c, 0xa0, 0x01, 0x64, 0xac, 0x11, 0x03, 0x28, 0x5e, 0x9b, 0x62, 0x63, 0xcd, 0x04, 0xba,
+	0x3f, 0x28, 0x88, 0x79, 0x24, 0xa2, 0xc5, 0x68, 0xef, 0x08, 0xe2, 0xe7, 0xe7, 0xe4, 0x33, 0x0b,
+	0xf7, 0xc7, 0x67, 0xb3, 0xb7, 0xc5, 0x34, 0x35, 0x08, 0x86, 0x44, 0x8f, 0xb9, 0xac, 0x6a, 0x73,
+	0x59, 0x22, 0x4a, 0xe0, 0xe3, 0x04, 0xd9, 0xe0, 0xb1, 0x55, 0xb1, 0xcc, 0xa3, 0xb4, 0x99, 0xc3,
+	0xfd, 0x2b, 0x21, 0x56, 0xd2, 0x35, 0xda, 0x5b, 0x60, 0xe7, 0x0f, 0x88, 0x7b, 0x12, 0x6a, 0x03,
+	0xb1, 0x60, 0xfa, 0x85, 0x56, 0x95, 0x73, 0x2a, 0x96, 0x95, 0x72, 0xc1, 0xfe, 0x13, 0x55, 0x52,
+	0x24, 0xad, 0xf8, 0xba, 0x4d, 0xaf, 0x54, 0x7f, 0x0a, 0x6c, 0x72, 0x70, 0x7e, 0x73, 0xce, 0x99,
+	0xa8, 0x6a, 0x25, 0xc6, 0x22, 0xcd, 0x50, 0x74, 0xd8, 0xd5, 0x27, 0x2e, 0xef, 0xca, 0xb2, 0x96,
+	0xbc, 0x91, 0x8d, 0x39, 0x4f, 0xc5, 0x4d, 0x55, 0x47, 0x22, 0x2b, 0xdb, 0xdd, 0xd8, 0xf3, 0xcc,
+	0x6c, 0x1b, 0xbf, 0xb5, 0xfb, 0xbc, 0xa2, 0xdd, 0xda, 0xdf, 0x16, 0xc4, 0xac, 0xdd, 0x1a, 0xaa,
+	0x44, 0xf6, 0x03, 0xd4, 0x56, 0x53, 0xa6, 0x41, 0x0a, 0x9c, 0x75, 0x4b, 0x8a, 0x79, 0x6e, 0x89,
+	0xe9, 0x46, 0x94, 0xae, 0x8a, 0x7a, 0x8c, 0x3d, 0x5f, 0xd4, 0x63, 0x3c, 0x2f, 0xea, 0x51, 0xfb,
+	0x0e, 0x08, 0xa6, 0xec, 0xea, 0x82, 0x3f, 0x31, 0xc9, 0x23, 0xe2, 0x0d, 0xf5, 0xc9, 0xe7, 0x62,
+	0x10, 0x05, 0x56, 0x1f, 0x8f, 0xf2, 0xac, 0x8b, 0xa3, 0x3d, 0xeb, 0x3b, 0x62, 0x9e, 0x54, 0x77,
+	0x04, 0x66, 0x5e, 0xa7, 0x93, 0xec, 0xac, 0x19, 0x2f, 0x03, 0x4f, 0x85, 0x6c, 0xc6, 0xae, 0x0e,
+	0xd9, 0x8c, 0x5f, 0x1d, 0xb2, 0x99, 0x48, 0x87, 0x6c, 0x6a, 0x1f, 0x8a, 0x19, 0x8b, 0x41, 0x3e,
+	0x32, 0xe2, 0xa4, 0x4d, 0x01, 0xc9, 0x0a, 0x16, 0xac, 0xf6, 0x17, 0xb0, 0x3e, 0x59, 0x1e, 0xfd,
+	0xbf, 0x1c, 0x02, 0x31, 0x9c, 0x25, 0x66, 0x4a, 0xcc, 0x70, 0x96, 0x80, 0x81, 0x2d, 0xd0, 0xc5,
+	0x98, 0x30, 0x9a, 0xc1, 0x56, 0x74, 0x20, 0x0d, 0x46, 0x9e, 0x48, 0x56, 0xb2, 0xa1, 0x6a, 0xd9,
+	0x56, 0xcd, 0xab, 0x42, 0xeb, 0xc6, 0x0e, 0x3c, 0x4d, 0x58, 0xc7, 0xa3, 0x3c, 0xb9, 0x9c, 0xf8,
+	0x93, 0xfb, 0x59, 0xb1, 0xf4, 0xd0, 0xef, 0x74, 0x82, 0xf8, 0x9e, 0x1c, 0xa6, 0x52, 0xbc, 0x60,
+	0x34, 0x3e, 0x91, 0x51, 0xfa, 0x46, 0xd8, 0xeb, 0x5c, 0x28, 0x97, 0x90, 0x61, 0x07, 0x00, 0xc2,
+	0x58, 0x70, 0xea, 0xd3, 0x24, 0x7c, 0x6c, 0x0b, 0x5c, 0x55, 0x44, 0x51, 0xce, 0x14, 0xb6, 0xbb,
+	0x73, 0xd7, 0xc1, 0x0b, 0x4c, 0x55, 0x5c, 0xd9, 0xd8, 0x0f, 0x0b, 0xc2, 0xf9, 0xc2, 0x10, 0x0c,
+	0x2d, 0x3a, 0x58, 0xd3, 0xbe, 0xec, 0x6a, 0xda, 0xeb, 0xc3, 0x18, 0xfa, 0xfb, 0xc1, 0x85, 0x3a,
+	0xc5, 0x2d, 0x26, 0xa7, 0xb8, 0xb9, 0xa7, 0xa4, 0xa5, 0xe7, 0x3e, 0x25, 0x1d, 0xcb, 0x3b, 0x25,
+	0xbd, 0x2d, 0x66, 0xda, 0x67, 0xbd, 0x70, 0x00, 0x66, 0x3e, 0xca, 0x34, 0x74, 0x31, 0x4a, 0x68,
+	0xbf, 0x32, 0x70, 0x1f, 0x61, 0xce, 0x5b, 0x09, 0x52, 0xd0, 0x3a, 0x0b, 0xd2, 0xeb, 0x55, 0x07,
+	0xd8, 0x1e, 0x86, 0x9d, 0xc3, 0x81, 0xfe, 0x10, 0x61, 0x91, 0xfb, 0xae, 0x58, 0xb4, 0xa6, 0xac,
+	0xcf, 0x32, 0x27, 0xe8, 0x38, 0x51, 0xf9, 0x70, 0xf6, 0x91, 0x23, 0xd7, 0xb9, 0xff, 0x5d, 0x10,
+	0x25, 0x18, 0xa8, 0x19, 0x4c, 0x2e, 0xd8, 0xc1, 0x64, 0x16, 0xbe, 0x0d, 0x2d, 0x5b, 0x8b, 0x2c,
+	0x0f, 0x4c, 0x20, 0x8a, 0x4e, 0xa0, 0x1e, 0x7a, 0x31, 0xa0, 0x00, 0x9e, 0xf8, 0x83, 0x16, 0x33,
+	0x7c, 0x0a, 0x8a, 0x04, 0x4f, 0xc4, 0x0e, 0xfe, 0x44, 0xaf, 0x86, 0x42, 0x61, 0x8a, 0x99, 0xb9,
+	0x64, 0x7a, 0xea, 0x13, 0xb6, 0xa7, 0x0e, 0x7b, 0xc1, 0x6e, 0x55, 0x46, 0xe7, 0xa4, 0x93, 0x9c,
+	0x57, 0x85, 0xaa, 0x01, 0x65, 0x13, 0xa1, 0xc9, 0x20, 0xb5, 0x2e, 0xbb, 0xff, 0x56, 0x10, 0xe3,
+	0x44, 0x13, 0xdc, 0x8d, 0xd2, 0x0a, 0xd0, 0xc1, 0x22, 0xa2, 0x05, 0xec, 0xc6, 0x14, 0x38, 0x95,
+	0xc9, 0x50, 0x4c, 0x67, 0x32, 0xa0, 0xe1, 0x26, 0x4b, 0x49, 0x8a, 0x40, 0x02, 0x80, 0xaf, 0xc7,
+	0x80, 0x63, 0x94, 0xae, 0x15, 0x2a, 0x12, 0x14, 0xf6, 0x3d, 0x82, 0x27, 0xe3, 0xc0, 0xb6, 0xe4,
+	0xa0, 0x39, 0xe6, 0x95, 0x02, 0x93, 0x29, 0xac, 0x9a, 0x95, 0x88, 0x52, 0x12, 0xa7, 0xa0, 0xee,
+	0x1d, 0x31, 0x87, 0x4c, 0x66, 0x38, 0xeb, 0x23, 0xb7, 0x84, 0xfb, 0xcb, 0x05, 0x31, 0xa5, 0x90,
+	0x61, 0x28, 0x63, 0xc8, 0xb1, 0x29, 0x03, 0x51, 0x9f, 0x26, 0x21, 0x9e, 0x47, 0x18, 0x28, 0x14,
+	0xc9, 0x5d, 0x4c, 0x4c, 0x24, 0xe5, 0x2c, 0x26, 0xe6, 0x87, 0x1e, 0x6e, 0x4a, 0x4f, 0xa7, 0xa0,
+	0xee, 0xb7, 0x0a, 0x62, 0xc6, 0xea, 0x03, 0x6d, 0x79, 0xda, 0x69, 0xd2, 0xfc, 0xe3, 0x65, 0x31,
+	0x41, 0x26, 0xbb, 0x14, 0x6d, 0x76, 0xd1, 0x81, 0x85, 0x92, 0x19, 0x58, 0x78, 0x5d, 0x4c, 0xb3,
+	0x89, 0x1c, 0xa8, 0x95, 0x50, 0x5b, 0x0d, 0x7b, 0x54, 0xe7, 0x64, 0x09, 0x12, 0xec, 0xb3, 0xb2,
+	0x51, 0x83, 0x1d, 0x82, 0x53, 0xfe, 0x24, 0x1c, 0x3c, 0x52, 0x91, 0x24, 0x2e, 0xea, 0x63, 0xdc,
+	0x62, 0x72, 0x8c, 0xeb, 0xfe, 0x29, 0x4c, 0x09, 0xb9, 0x0c, 0x26, 0x74, 0x18, 0x76, 0xda, 0x4d,
+	0x8a, 0x6c, 0x6a, 0x86, 0xc2, 0x73, 0xa4, 0xd8, 0xd7, 0xdc, 0x66, 0x83, 0x91, 0x7b, 0xbb, 0xed,
+	0x1e, 0x09, 0x67, 0xe6, 0x35, 0x5d, 0xc6, 0xdd, 0x89, 0x9c, 0x7c, 0xe2, 0x47, 0xcc, 0xde, 0xac,
+	0x67, 0x2c, 0x20, 0xee, 0x18, 0x04, 0x60, 0x72, 0x52, 0xa3, 0x0b, 0x96, 0x40, 0x5b, 0xe2, 0xca,
+	0x5d, 0x98, 0x57, 0xe5, 0xfe, 0x79, 0x51, 0x94, 0x59, 0xfa, 0xa2, 0x94, 0x21, 0xab, 0x81, 0xad,
+	0x2d, 0x2d, 0x22, 0x0c, 0x88, 0xaa, 0xb7, 0xec, 0x33, 0x03, 0x92, 0x5e, 0xc0, 0x52, 0x76, 0x01,
+	0xd9, 0xd9, 0x79, 0x83, 0x0c, 0xc1, 0xb1, 0xc4, 0xd9, 0x21, 0x80, 0xaa, 0x5d, 0xa7, 0xda, 0xf1,
+	0xa4, 0x96, 0x00, 0x96, 0xe9, 0x37, 0x91, 0x32, 0xfd, 0xde, 0x06, 0xc6, 0x94, 0xcd, 0x10, 0xdd,
+	0x49, 0x4c, 0x24, 0xac, 0x6c, 0xad, 0x89, 0x67, 0x61, 0xaa, 0x2f, 0xd7, 0xd5, 0x97, 0x53, 0x57,
+	0x7d, 0xa9, 0x30, 0xf1, 0x1c, 0x83, 0x89, 0xf7, 0xde, 0xc0, 0xef, 0x9f, 0x2b, 0x8d, 0xd6, 0xd2,
+	0x29, 0x18, 0x04, 0x06, 0x5d, 0x33, 0x2e, 0xf5, 0x41, 0xc1, 0x3a, 0xbc, 0xb0, 0xb7, 0x97, 0x44,
+	0x01, 0x76, 0x19, 0x97, 0x6a, 0xa1, 0x68, 0xf1, 0xaa, 0xb1, 0x46, 0x9e, 0x44, 0xc0, 0xcd, 0x4e,
+	0x0a, 0xca, 0xde, 0xec, 0xb6, 0x74, 0xc7, 0xd0, 0x11, 0xa8, 0x30, 0x77, 0x09, 0xcf, 0xd7, 0x89,
+	0x6b, 0xcd, 0x40, 0xde, 0xf7, 0x4b, 0xc0, 0xea, 0x09, 0x18, 0xf7, 0xed, 0x19, 0x0e, 0xb8, 0xd1,
+	0x6a, 0xfb, 0xdd, 0x20, 0x0e, 0x06, 0xcc, 0xa9, 0x29, 0x28, 0x29, 0x81, 0xc7, 0xe0, 0xdc, 0x80,
+	0x07, 0xdf, 0x0a, 0xce, 0x06, 0x81, 0x8c, 0x8f, 0x14, 0xbc, 0x14, 0x14, 0xf1, 0x30, 0xd5, 0xc7,
+	0xc0, 0xe3, 0x64, 0x3c, 0x1b, 0xaa, 0xc2, 0x72, 0x92, 0x46, 0x63, 0x49, 0x58, 0x4e, 0x52, 0x24,
+	0x2d, 0x71, 0xc6, 0x73, 0x24, 0xce, 0x9b, 0x62, 0x45, 0xca, 0x16, 0xde, 0x9b, 0x8d, 0x14, 0x9b,
+	0x8c, 0xa8, 0x45, 0x83, 0x1a, 0xc7, 0xac, 0x18, 0x3c, 0x6a, 0x7f, 0x43, 0x1e, 0x10, 0x14, 0xbc,
+	0x0c, 0x1c, 0x71, 0x71, 0x3b, 0x5a, 0xb8, 0x52, 0xc9, 0x64, 0xe0, 0x84, 0x0b, 0x73, 0xb4, 0x70,
+	0xa7, 0x19, 0x37, 0x05, 0x47, 0x5c, 0x8a, 0x41, 0x0e, 0x86, 0x3d, 0x6d, 0x38, 0x08, 0x5a, 0xbd,
+	0x0c, 0xdc, 0x9d, 0x11, 0xe5, 0xa3, 0x18, 0x14, 0x08, 0x2f, 0xe0, 0xac, 0xa8, 0xc8, 0x22, 0x9f,
+	0xaa, 0x5f, 0x17, 0xd7, 0x88, 0xe3, 0x8e, 0x43, 0x60, 0xd0, 0xf0, 0xec, 0xe2, 0x68, 0x78, 0x12,
+	0x35, 0x07, 0xed, 0x3e, 0xfa, 0x10, 0xee, 0xdf, 0x17, 0xc4, 0xa2, 0x55, 0xcb, 0x41, 0x82, 0x4f,
+	0x4b, 0xf6, 0xd7, 0x87, 0x9b, 0x92, 0x49, 0x17, 0x0c, 0x21, 0x29, 0x11, 0x65, 0x4c, 0xe5, 0x01,
+	0x9f, 0x77, 0x6e, 0x88, 0x39, 0x35, 0x0b, 0xf5, 0xa1, 0xe4, 0xd8, 0x6a, 0x96, 0x63, 0xf9, 0xfb,
+	0x59, 0xfe, 0x40, 0x35, 0xf1, 0x33, 0xd2, 0xbe, 0x86, 0xc9, 0x61, 0x85, 0x72, 0x81, 0x75, 0xa0,
+	0xdf, 0xb4, 0xe9, 0xd5, 0x08, 0x9a, 0x1a, 0x18, 0xb9, 0xbf, 0x59, 0x10, 0x22, 0x19, 0x1d, 0x32,
+	0x51, 0x22, 0xe8, 0x0b, 0x14, 0x38, 0x4d, 0x00, 0x68, 0xd3, 0xea, 0x40, 0x74, 0xa2, 0x3b, 0xca,
+	0x0a, 0x86, 0x36, 0xe2, 0xab, 0x62, 0xee, 0xac, 0x13, 0x9e, 0x90, 0xe2, 0xa5, 0x04, 0x8e, 0x88,
+	0x0f, 0xfa, 0x66, 0x25, 0x78, 0x9b, 0xa1, 0x89, 0xa2, 0x19, 0x33, 0x14, 0x8d, 0xfb, 0x5b, 0x45,
+	0x1d, 0x22, 0x4d, 0xe6, 0x3c, 0x72, 0x47, 0x3a, 0xeb, 0x19, 0x41, 0x3a, 0x22, 0x22, 0x49, 0x71,
+	0x91, 0xc3, 0x2b, 0x3d, 0xdf, 0x77, 0xc1, 0xa7, 0x95, 0x92, 0x4a, 0x89, 0xb1, 0xb1, 0x4b, 0xc4,
+	0xd8, 0xcc, 0xc0, 0xd2, 0x51, 0x1f, 0x87, 0x6d, 0xd0, 0x7a, 0x1c, 0x0c, 0xe2, 0x36, 0x79, 0x36,
+	0x64, 0x0a, 0x48, 0xe1, 0x3b, 0x67, 0xc0, 0x49, 0x43, 0x03, 0x95, 0x38, 0x9f, 0x43, 0x63, 0x72,
+	0xaa, 0x62, 0x02, 0x46, 0x44, 0xf7, 0xbb, 0x2a, 0x1a, 0x6b, 0xaf, 0xe1, 0x68, 0x8a, 0x98, 0xb3,
+	0x2b, 0xa6, 0x66, 0x77, 0x9b, 0x63, 0x60, 0x2d, 0xe5, 0x3e, 0x71, 0x8c, 0x5a, 0x02, 0x39, 0x92,
+	0x6d, 0x93, 0x74, 0xec, 0x79, 0x48, 0xea, 0xae, 0x61, 0xa2, 0x59, 0xbc, 0x81, 0x2b, 0xa8, 0x84,
+	0xe8, 0x75, 0x90, 0x46, 0xc1, 0x93, 0x86, 0x5c, 0x62, 0xa9, 0xf2, 0xa7, 0x00, 0x40, 0x38, 0x78,
+	0xb2, 0x92, 0xe0, 0xf3, 0xae, 0xfb, 0xcb, 0x31, 0x31, 0xb9, 0xdb, 0x7b, 0x1c, 0xb6, 0x9b, 0x14,
+	0x03, 0xed, 0x06, 0xdd, 0x50, 0x65, 0x66, 0xe1, 0x6f, 0xb4, 0x20, 0x28, 0x91, 0xa0, 0x1f, 0x73,
+	0x70, 0x52, 0x15, 0x51, 0x9b, 0x0e, 0x92, 0xdc, 0x42, 0xc9, 0x6d, 0x06, 0x04, 0x6d, 0xe6, 0x81,
+	0x99, 0x64, 0xca, 0xa5, 0x24, 0x2d, 0x6d, 0xdc, 0x48, 0x4b, 0xa3, 0xc8, 0xb8, 0x3c, 0x2c, 0xa5,
+	0x25, 0xc1, 0xc8, 0xb8, 0x2c, 0x92, 0x6d, 0x3f, 0x08, 0x38, 0x95, 0x05, 0xf5, 0xf2, 0x24, 0xdb,
+	0xf6, 0x26, 0x10, 0x75, 0xb7, 0xfc, 0x40, 0xe2, 0x48, 0xd9, 0x66, 0x82, 0xd0, 0x96, 0x49, 0xe7,
+	0xa9, 0x4e, 0x4b, 0x36, 0x49, 0x81, 0x79, 0x37, 0x72, 0xd0, 0x57, 0x4a, 0xb3, 0x04, 0x80, 0x22,
+	0x9d, 0x9b, 0x95, 0x08, 0x65, 0x42, 0xb0, 0x60, 0xa8, 0x08, 0xa5, 0x3f, 0x5b, 0xb1, 0x14, 0x21,
+	0x13, 0x9a, 0xfc, 0x59, 0x89, 0x80, 0xb3, 0x43, 0x0b, 0xb8, 0xef, 0xb7, 0xd9, 0x43, 0x98, 0xa1,
+	0xe6, 0x6c, 0xa0, 0xf3, 0x86, 0x18, 0xc7, 0x7c, 0x8a, 0x80, 0x92, 0x37, 0x92, 0xe4, 0x4a, 0x6e,
+	0x4f, 0xfd, 0xc5, 0xf0, 0x29, 0x68, 0x58, 0xc2, 0x74, 0x37, 0x44, 0xc5, 0x04, 0xe3, 0xc1, 0xfa,
+	0xc1, 0x61, 0x7d, 0x7f, 0xfe, 0x05, 0x3c, 0x7e, 0x3f, 0xaa, 0x1f, 0x1f, 0xef, 0xd1, 0x79, 0x7b,
+	0x45, 0x4c, 0xe9, 0x93, 0xd6, 0x22, 0x96, 0x36, 0x36, 0x37, 0xeb, 0x87, 0xc7, 0x78, 0xee, 0x8a,
+	0x33, 0xb5, 0x12, 0x5a, 0xe7, 0xe4, 0x89, 0x86, 0x09, 0x73, 0xff, 0xb1, 0x20, 0xca, 0xc6, 0xb4,
+	0x2e, 0xf1, 0xd1, 0x80, 0x5f, 0xe8, 0xe0, 0x38, 0x89, 0xa5, 0x83, 0x75, 0x96, 0x40, 0x70, 0x0b,
+	0x69, 0x0f, 0xa1, 0x44, 0xb5, 0xba, 0x8c, 0x54, 0x92, 0x1e, 0x97, 0x1d, 0x81, 0xb0, 0x81, 0x44,
+	0xcb, 0x66, 0x33, 0xe8, 0xc7, 0x66, 0x02, 0x38, 0x60, 0x59, 0x40, 0x83, 0x53, 0xe8, 0xfc, 0x71,
+	0xc2, 0xe2, 0x14, 0x3a, 0x81, 0xfc, 0x3b, 0x70, 0xd0, 0xc1, 0x82, 0xe6, 0x69, 0x69, 0x67, 0x35,
+	0x61, 0xe8, 0x82, 0xc5, 0xd0, 0x39, 0x8c, 0x55, 0x7c, 0x0e, 0xc6, 0x9a, 0xcf, 0x61, 0x2c, 0x8b,
+	0xdc, 0x0b, 0x59, 0x72, 0x83, 0x98, 0xb0, 0x12, 0x88, 0x9d, 0x11, 0x09, 0xc4, 0x26, 0x92, 0x5b,
+	0x17, 0xe5, 0x43, 0x23, 0xbf, 0x9b, 0xf6, 0xad, 0xca, 0xec, 0xe6, 0xbd, 0x6e, 0x40, 0x8c, 0x69,
+	0x16, 0xcd, 0x69, 0xba, 0x6f, 0x09, 0x07, 0xcf, 0x60, 0x35, 0x55, 0x74, 0xbc, 0x45, 0xc7, 0x8b,
+	0x8d, 0x78, 0x0b, 0xc3, 0x28, 0xde, 0xb2, 0x21, 0x13, 0x66, 0xd2, 0xe4, 0xbc, 0x83, 0x39, 0x2d,
+	0x04, 0x52, 0x6a, 0x7b, 0xd6, 0x66, 0x6b, 0x4f, 0xd7, 0xbb, 0x1f, 0x88, 0xd9, 0x23, 0x5a, 0xa0,
+	0xfa, 0x63, 0x4c, 0xa5, 0x06, 0xef, 0x96, 0x4e, 0xfe, 0x7b, 0xd1, 0xb0, 0x9b, 0x9c, 0xae, 0x4c,
+	0x7b, 0x26, 0x28, 0xb3, 0x4f, 0x8b, 0xd9, 0x7d, 0xea, 0x3e, 0x14, 0x8b, 0x6a, 0x93, 0x18, 0xd6,
+	0x86, 0xbd, 0x4e, 0x85, 0xab, 0x04, 0x40, 0x5e, 0xc3, 0xdf, 0x06, 0xb1, 0xca, 0x44, 0x37, 0xd7,
+	0xd5, 0xb8, 0x1b, 0x61, 0xc1, 0xf2, 0x73, 0x75, 0xb3, 0xa2, 0xaf, 0x94, 0x27, 0xfa, 0x30, 0x41,
+	0xd2, 0x8f, 0xcf, 0xc9, 0x41, 0x04, 0xb1, 0x8d, 0xbf, 0x55, 0x08, 0x63, 0x3c, 0x09, 0x61, 0xe4,
+	0x25, 0x84, 0x4b, 0xe5, 0x97, 0x4d, 0x08, 0xcf, 0xe1, 0xe8, 0xc9, 0x7c, 0x8e, 0xfe, 0xb4, 0x98,
+	0x90, 0x89, 0x5e, 0x24, 0x71, 0x67, 0xd7, 0x6f, 0xd8, 0x69, 0xdf, 0xea, 0x2f, 0x5f, 0x98, 0x61,
+	0xdc, 0x44, 0x3c, 0x4e, 0x5b, 0xe2, 0x11, 0x05, 0xc8, 0x46, 0x1c, 0x07, 0xdd, 0x7e, 0xac, 0xc4,
+	0x23, 0x58, 0xe1, 0xa9, 0xf4, 0x72, 0x21, 0x15, 0xb6, 0x0d, 0xc5, 0x03, 0x1f, 0x05, 0x69, 0xa2,
+	0x5a, 0x2f, 0x5f, 0x9d, 0x84, 0x6e, 0x7d, 0x60, 0x76, 0xd4, 0xa2, 0xab, 0x1b, 0x94, 0x8b, 0x67,
+	0x74, 0x24, 0xa1, 0xee, 0xb6, 0x98, 0xb1, 0xe6, 0x84, 0xd2, 0xf4, 0xc1, 0xfe, 0xfb, 0xfb, 0x07,
+	0x0f, 0xf7, 0x65, 0x32, 0xd3, 0xee, 0x7e, 0x63, 0x7b, 0x6f, 0xf7, 0xbd, 0x9d, 0x63, 0x10, 0xae,
+	0x50, 0x3c, 0x7a, 0x00, 0xf2, 0xb4, 0xbe, 0x45, 0xd2, 0x55, 0x88, 0x89, 0xed, 0x8d, 0x5d, 0x99,
+	0xd3, 0xf2, 0x3d, 0xf0, 0x5d, 0x8d, 0xf9, 0xe2, 0xae, 0xf4, 0xe5, 0x4f, 0xc3, 0x77, 0x4d, 0x20,
+	0xce, 0x67, 0x34, 0xa1, 0x8b, 0x99, 0xb4, 0x2b, 0x6e, 0x83, 0x7e, 0xa7, 0x28, 0xed, 0x8a, 0xf1,
+	0xd1, 0x29, 0xfd, 0xb2, 0x0a, 0x57, 0x5b, 0x75, 0x44, 0x5e, 0x7d, 0x2f, 0x62, 0xa7, 0x3b, 0x0d,
+	0x96, 0xa7, 0x21, 0x51, 0xd8, 0x79, 0x1c, 0x68, 0x4c, 0x0e, 0xfa, 0xa4, 0xc0, 0xa8, 0x06, 0x98,
+	0x70, 0x2a, 0x30, 0xc6, 0x45, 0xf7, 0x4d, 0x21, 0x92, 0x71, 0xda, 0x04, 0x7b, 0xc1, 0x26, 0x58,
+	0xc1, 0x20, 0x58, 0xd1, 0xfd, 0x93, 0x82, 0x14, 0x23, 0x4c, 0x7d, 0x6d, 0xf1, 0xac, 0x09, 0xa7,
+	0xdd, 0x6b, 0x76, 0x86, 0x2d, 0xdc, 0x7a, 0xcd, 0xb0, 0xdb, 0xef, 0x04, 0xb1, 0xca, 0x04, 0xca,
+	0xa9, 0xc1, 0xdd, 0x48, 0x5b, 0xb4, 0x11, 0x9e, 0x9e, 0xc2, 0x96, 0x55, 0xbb, 0xd7, 0x84, 0x21,
+	0x8e, 0xbc, 0xe6, 0x21, 0xbb, 0x62, 0x75, 0x64, 0xc1, 0x50, 0x5d, 0x0d, 0x02, 0xbc, 0x91, 0xa5,
+	0x53, 0x84, 0x74, 0x19, 0xaf, 0x00, 0x2c, 0xd9, 0x63, 0x4d, 0x64, 0x9e, 0x6e, 0xd4, 0x96, 0x79,
+	0x8c, 0xea, 0xe9, 0x7a, 0x9c, 0xd8, 0x69, 0x7b, 0x10, 0xf1, 0x41, 0xb3, 0x3d, 0xdc, 0x9c, 0x1a,
+	0xcc, 0xe3, 0xa3, 0x50, 0x85, 0x85, 0x2e, 0x47, 0x9e, 0xad, 0xc0, 0x84, 0xf6, 0xad, 0x00, 0x09,
+	0xb2, 0xd1, 0xe9, 0xa4, 0x48, 0x8a, 0x9e, 0x58, 0x4e, 0x1d, 0x1b, 0x8c, 0xdb, 0x62, 0x61, 0x2b,
+	0x38, 0x19, 0x9e, 0xed, 0xc1, 0x64, 0x3b, 0xc6, 0xa5, 0x80, 0xe8, 0x3c, 0x7c, 0xc2, 0x64, 0xa7,
+	0xdf, 0x78, 0x95, 0xa6, 0x83, 0x38, 0x8d, 0xa8, 0x1f, 0x34, 0x55, 0x82, 0x39, 0x41, 0x8e, 0x00,
+	0x00, 0x7c, 0xe0, 0x98, 0xed, 0x30, 0x81, 0x50, 0x39, 0x0f, 0x4f, 0x1a, 0xd1, 0x45, 0x44, 0x97,
+	0xd2, 0x58, 0xac, 0x1b, 0x20, 0xf7, 0x55, 0x51, 0x81, 0x31, 0x41, 0xc7, 0x7c, 0xfd, 0x08, 0x63,
+	0x84, 0xfe, 0x05, 0x0a, 0x24, 0x1d, 0x23, 0xa4, 0x6a, 0x77, 0x20, 0x26, 0x24, 0x22, 0x36, 0x8a,
+	0x97, 0xa2, 0xda, 0x3d, 0x79, 0x18, 0xcc, 0x8d, 0x1a, 0xa0, 0x8c, 0x88, 0x2e, 0xe6, 0x88, 0x68,
+	0x76, 0xe5, 0x55, 0x7e, 0x2d, 0xcb, 0x62, 0x0b, 0x86, 0x16, 0xf6, 0x76, 0x00, 0x02, 0xa6, 0x1f,
+	0x0e, 0xd4, 0xb5, 0x27, 0xf7, 0xbb, 0x45, 0x31, 0xcf, 0x16, 0xbc, 0xae, 0x03, 0xb5, 0x69, 0x9a,
+	0xfb, 0xb9, 0x19, 0x8c, 0x20, 0xfc, 0x29, 0x38, 0xa6, 0x83, 0xc2, 0x1c, 0xd3, 0xb6, 0x80, 0x94,
+	0xaf, 0xca, 0x27, 0x5a, 0x5d, 0x10, 0x5a, 0x25, 0x7d, 0xa5, 0x4a, 0x81, 0x54, 0x5c, 0x19, 0x83,
+	0x67, 0xc4, 0xa8, 0x05, 0x4f, 0x97, 0x51, 0x29, 0xb4, 0x80, 0x78, 0x58, 0x06, 0xbd, 0x99, 0x84,
+	0x71, 0xc1, 0x7d, 0x4f, 0xc3, 0x91, 0xbf, 0x9e, 0x04, 0xc1, 0x23, 0x1b, 0x59, 0xde, 0x1a, 0xcc,
+	0x56, 0x20, 0xf7, 0x76, 0xc3, 0x5e, 0x7c, 0x6e, 0xa3, 0x4f, 0x4a, 0xee, 0xcd, 0xd6, 0xb8, 0xff,
+	0x52, 0x10, 0x0b, 0x06, 0xe9, 0x98, 0x1d, 0xde, 0x15, 0x2a, 0xa7, 0x45, 0x46, 0xb1, 0xe5, 0x9e,
+	0x59, 0xb5, 0xfd, 0xa2, 0xe4, 0x33, 0x0b, 0x39, 0x77, 0x72, 0xc5, 0x1f, 0x65, 0x72, 0xa5, 0x1f,
+	0x6d, 0x72, 0x63, 0x23, 0x27, 0xf7, 0xbd, 0x02, 0xf1, 0x05, 0x07, 0x02, 0xf4, 0xcd, 0x83, 0x09,
+	0xe9, 0x9b, 0xcb, 0x5d, 0xb3, 0xf3, 0x82, 0xc7, 0x65, 0x90, 0xf5, 0xcf, 0xe7, 0x5e, 0xeb, 0xec,
+	0x96, 0x11, 0x0c, 0x53, 0xca, 0x63, 0x98, 0x4b, 0xd8, 0xe1, 0xde, 0x24, 0xb8, 0x19, 0xcd, 0xb0,
+	0x1f, 0xb8, 0x8b, 0xb4, 0x18, 0x6a, 0xbc, 0xbc, 0xf3, 0x1b, 0x62, 0xee, 0x5e, 0xc7, 0x6f, 0x3e,
+	0xea, 0x80, 0x64, 0x93, 0x07, 0x42, 0x97, 0x64, 0x2a, 0xae, 0x8b, 0x25, 0x1f, 0x0c, 0xab, 0x56,
+	0xc3, 0x8f, 0x1a, 0xe6, 0xe6, 0x93, 0xc9, 0x48, 0xb9, 0x75, 0xee, 0x8a, 0x94, 0x9a, 0xba, 0x13,
+	0xb5, 0x83, 0xea, 0x62, 0x39, 0x05, 0x67, 0xf6, 0xf8, 0xa4, 0x1d, 0x9b, 0x5c, 0x61, 0x1a, 0xa5,
+	0x46, 0xc9, 0xd1, 0x49, 0xf7, 0xcb, 0x62, 0x45, 0xce, 0x28, 0xdd, 0x01, 0xe8, 0xb5, 0x12, 0x98,
+	0x77, 0x57, 0xb4, 0x82, 0x28, 0x64, 0x1c, 0x83, 0x5b, 0xfc, 0x38, 0xa0, 0x80, 0x11, 0x08, 0x1b,
+	0x59, 0x72, 0xaf, 0x89, 0xd5, 0x4c, 0xdb, 0x4c, 0x36, 0x4f, 0x2c, 0x6f, 0xd2, 0xb1, 0x34, 0x8a,
+	0x92, 0xe3, 0xa7, 0xc9, 0x4d, 0xaa, 0x1f, 0x23, 0x03, 0xed, 0x58, 0xac, 0xa4, 0xdb, 0x4c, 0x6e,
+	0x07, 0xf1, 0x21, 0x78, 0xfc, 0x54, 0xdd, 0x0e, 0xd2, 0x00, 0xca, 0x04, 0x47, 0x8f, 0x2b, 0x86,
+	0x4f, 0x78, 0x06, 0x09, 0x00, 0x6f, 0xbc, 0xd4, 0x9f, 0xe2, 0x46, 0xe2, 0xae, 0xb7, 0xee, 0xa9,
+	0x15, 0x00, 0xeb, 0x48, 0xc3, 0x36, 0xcf, 0x87, 0xbd, 0x47, 0x68, 0xb0, 0x36, 0xf1, 0x07, 0xfb,
+	0x42, 0xb2, 0x00, 0x76, 0x7a, 0x95, 0x2e, 0x7c, 0x0d, 0xa3, 0x38, 0xec, 0xa6, 0x6e, 0x20, 0xd1,
+	0x3d, 0x1e, 0x0e, 0xcb, 0x56, 0x3c, 0xfa, 0x4d, 0x59, 0x57, 0x98, 0xd7, 0x2c, 0x0f, 0x62, 0xe8,
+	0x37, 0xdd, 0x74, 0xf5, 0x63, 0x9f, 0x23, 0x0a, 0xf4, 0x1b, 0x35, 0x52, 0x4e, 0xbb, 0x4c, 0xe0,
+	0x97, 0xc4, 0x4d, 0xb6, 0xde, 0x4f, 0x02, 0x0b, 0x43, 0x2b, 0xb4, 0xf7, 0xc5, 0x8c, 0x55, 0xf1,
+	0x63, 0x8d, 0xa5, 0x2d, 0x8f, 0x58, 0x76, 0x60, 0x8d, 0x43, 0xfb, 0x08, 0x30, 0xb5, 0x05, 0x80,
+	0xd8, 0x68, 0xf4, 0x48, 0x37, 0x53, 0x0a, 0xef, 0x04, 0x40, 0x5e, 0x84, 0xcc, 0xfd, 0x93, 0x08,
+	0xac, 0x4e, 0x4c, 0x18, 0x66, 0xe0, 0x81, 0xeb, 0xd6, 0x1e, 0xa8, 0xbe, 0x54, 0xae, 0xd5, 0xe9,
+	0x20, 0xec, 0xaa, 0xc5, 0xd5, 0x00, 0x3a, 0xec, 0xc1, 0x42, 0x1c, 0xaa, 0xd3, 0x25, 0x2e, 0xda,
+	0x23, 0x29, 0xa5, 0x47, 0x82, 0xc7, 0x33, 0x58, 0xd0, 0xde, 0x37, 0xe7, 0x9d, 0x58, 0xc0, 0xcc,
+	0x78, 0xc7, 0xb3, 0xe3, 0x45, 0x89, 0xab, 0xca, 0xa9, 0xc3, 0xbe, 0x0c, 0xdc, 0xbd, 0x21, 0x6a,
+	0x74, 0x22, 0x7c, 0xbf, 0x1d, 0xe1, 0xa5, 0xf6, 0x4d, 0x90, 0x9a, 0x83, 0x50, 0xa7, 0x48, 0x7d,
+	0x5d, 0x5c, 0xcf, 0xad, 0xd5, 0x19, 0xbb, 0xd6, 0xc6, 0x37, 0x0f, 0xc5, 0x98, 0x56, 0xc6, 0x91,
+	0x44, 0x1f, 0x28, 0x98, 0x3e, 0x92, 0x30, 0xa8, 0xea, 0x49, 0x04, 0x1c, 0x10, 0xb4, 0x1f, 0xc4,
+	0xf9, 0x03, 0x7a, 0x51, 0x5c, 0xcf, 0xad, 0x65, 0x1e, 0x1c, 0x88, 0x1b, 0x5f, 0xdc, 0xed, 0xe2,
+	0xde, 0xc9, 0xfd, 0xfc, 0x27, 0x32, 0xe0, 0x5b, 0xe2, 0xc5, 0x11, 0x7d, 0xf2, 0xa0, 0xde, 0x13,
+	0x0b, 0xf7, 0x86, 0xed, 0x4e, 0x4b, 0x5a, 0xfb, 0xc9, 0x45, 0x40, 0x3c, 0xf0, 0x2d, 0x24, 0xd9,
+	0x04, 0x60, 0x42, 0x24, 0xc9, 0x01, 0x4a, 0x2c, 0x98, 0x20, 0xf7, 0x6d, 0xe1, 0x98, 0x0d, 0xf1,
+	0x22, 0x68, 0xdf, 0xa2, 0x30, 0xd2, 0xb7, 0x70, 0x7f, 0xbb, 0x20, 0x1c, 0xdc, 0xb9, 0xc7, 0xa1,
+	0x35, 0x88, 0x3c, 0x97, 0xb8, 0x92, 0xb2, 0xb7, 0x5e, 0xcf, 0xbf, 0x87, 0x2e, 0x59, 0x3b, 0xaf,
+	0xea, 0x79, 0x9c, 0x1d, 0xb7, 0x2f, 0x2a, 0x54, 0x66, 0x5f, 0x10, 0x77, 0x78, 0x53, 0x1d, 0x1e,
+	0xc3, 0xae, 0x27, 0x5f, 0x10, 0x74, 0x97, 0xf2, 0xfa, 0xa2, 0x70, 0x88, 0xa9, 0x62, 0x66, 0xfe,
+	0x67, 0x6e, 0x1d, 0x6e, 0xbe, 0xae, 0x14, 0x2e, 0x2c, 0x2c, 0x54, 0x11, 0x7a, 0x5c, 0xb4, 0x28,
+	0xa0, 0x5d, 0x81, 0xac, 0x3f, 0x5e, 0x18, 0x71, 0x41, 0xfb, 0xa7, 0x13, 0x6f, 0xca, 0xb6, 0x06,
+	0xcc, 0xa9, 0x24, 0x2e, 0x56, 0x57, 0xac, 0xd2, 0xe6, 0x39, 0x1c, 0x80, 0x39, 0x71, 0xd2, 0xee,
+	0xb4, 0x63, 0x7d, 0x11, 0x19, 0x25, 0x01, 0xc8, 0x8a, 0x86, 0x3e, 0x30, 0x07, 0x09, 0xa2, 0x01,
+	0x94, 0xd6, 0x1d, 0xca, 0x3a, 0x96, 0x20, 0x5c, 0xcc, 0x04, 0xe7, 0x4a, 0x49, 0x70, 0xce, 0xfd,
+	0xe3, 0x82, 0xa8, 0x66, 0xfb, 0x4b, 0x0c, 0xfa, 0x7e, 0x02, 0xa6, 0x2e, 0x0b, 0x9e, 0x09, 0x02,
+	0x25, 0x3e, 0x79, 0x2e, 0x19, 0x9b, 0x27, 0x97, 0xc7, 0xf2, 0x0a, 0x05, 0xdf, 0x53, 0x20, 0xa9,
+	0xa6, 0x3e, 0x29, 0x59, 0x9f, 0x98, 0xfb, 0xc9, 0xc2, 0x73, 0xbf, 0x22, 0xca, 0x46, 0x76, 0xca,
+	0x95, 0x47, 0xc5, 0x60, 0x0f, 0xb6, 0xda, 0x83, 0x80, 0x1e, 0x63, 0x68, 0xb0, 0x5f, 0xc7, 0xb6,
+	0x4b, 0xb6, 0xc2, 0xfd, 0xa3, 0xa2, 0x58, 0x94, 0xa7, 0x11, 0xb6, 0x89, 0xb7, 0x62, 0x9b, 0x78,
+	0xda, 0xc0, 0xfb, 0xd4, 0xf3, 0x9e, 0x9f, 0x7c, 0xa4, 0xe6, 0x5d, 0xde, 0x69, 0xfe, 0x78, 0xfe,
+	0x69, 0x3e, 0xf4, 0xa5, 0x4e, 0xef, 0x4d, 0x29, 0x6e, 0x03, 0x09, 0x0b, 0x5c, 0xe2, 0x04, 0x8b,
+	0x23, 0xf3, 0x16, 0x10, 0xad, 0x3a, 0x9b, 0x36, 0x2c, 0x9d, 0xfe, 0xb3, 0x28, 0xae, 0x6f, 0xcb,
+	0x04, 0x98, 0x1d, 0x40, 0xde, 0xed, 0xc5, 0xf8, 0x06, 0x43, 0xdf, 0x78, 0x2e, 0x02, 0x9c, 0x72,
+	0x86, 0x25, 0x8b, 0x64, 0xc1, 0x72, 0xfd, 0xb6, 0xb4, 0x1c, 0x81, 0x8d, 0xa6, 0xee, 0xca, 0xa9,
+	0x64, 0x29, 0xb6, 0xec, 0x33, 0x70, 0xc4, 0x4d, 0x27, 0x56, 0xb1, 0x59, 0x9f, 0x81, 0x23, 0x8b,
+	0xe8, 0xef, 0xf5, 0xde, 0x90, 0x5a, 0x31, 0x5b, 0x81, 0xd8, 0xba, 0x85, 0x94, 0x6e, 0xcc, 0x56,
+	0xd0, 0x8d, 0x14, 0xd5, 0x04, 0x27, 0x1e, 0x4d, 0xca, 0x95, 0x4a, 0x81, 0x11, 0x53, 0x7f, 0xce,
+	0x98, 0x53, 0x12, 0x33, 0x05, 0x76, 0xff, 0xa0, 0x20, 0x6e, 0xe4, 0xd3, 0x5b, 0xcb, 0xf3, 0xab,
+	0x09, 0xfe, 0x96, 0xbc, 0x33, 0xcc, 0x86, 0xfc, 0xec, 0xfa, 0x2d, 0x25, 0x88, 0x64, 0xfc, 0x67,
+	0x27, 0xec, 0xb4, 0xb8, 0x8f, 0x0d, 0xf9, 0xaa, 0x09, 0xa3, 0x53, 0x8a, 0xb9, 0x7d, 0x56, 0xa4,
+	0xcb, 0xee, 0x1a, 0x9d, 0x4b, 0xc5, 0x9d, 0x80, 0x63, 0xb1, 0xf7, 0xa3, 0x33, 0x0b, 0xbf, 0x90,
+	0xc2, 0x5f, 0xc4, 0x67, 0x05, 0x0c, 0x7c, 0x9c, 0x81, 0xfb, 0x26, 0x78, 0xd9, 0x74, 0x37, 0xcb,
+	0x68, 0xe4, 0x39, 0xd4, 0x0c, 0x36, 0x66, 0x7d, 0x47, 0x8d, 0x81, 0x2d, 0xa0, 0x4d, 0x4a, 0x24,
+	0x16, 0x85, 0x9d, 0xb5, 0x39, 0xf9, 0x4f, 0x25, 0x31, 0xad, 0xa1, 0xce, 0x3b, 0x42, 0x04, 0xf8,
+	0xa3, 0x61, 0xbc, 0x55, 0xa0, 0xce, 0x81, 0x35, 0xd6, 0x1a, 0xfd, 0x2b, 0x6f, 0xe5, 0x25, 0xd8,
+	0xff, 0x6f, 0xf9, 0x17, 0xe6, 0x85, 0x22, 0x85, 0x9e, 0xd4, 0xc1, 0x28, 0xa1, 0xf4, 0xfb, 0x2d,
+	0x18, 0xbd, 0xed, 0x61, 0x86, 0x6c, 0x25, 0xdb, 0x5e, 0x15, 0x95, 0x9d, 0xce, 0x8d, 0xca, 0xd6,
+	0xc5, 0xb4, 0x26, 0x30, 0x46, 0x64, 0xb7, 0x0f, 0xbc, 0x87, 0x1b, 0xde, 0xd6, 0xfc, 0x0b, 0x78,
+	0xc7, 0x90, 0x0b, 0x0d, 0x0c, 0x25, 0xca, 0xa0, 0xa2, 0x3c, 0xfe, 0x9a, 0x2f, 0x62, 0xbc, 0x71,
+	0x6f, 0x77, 0xff, 0x7d, 0x59, 0x55, 0x42, 0x39, 0x5e, 0xe1, 0x9c, 0xd3, 0x23, 0xf0, 0xfa, 0xfb,
+	0xc8, 0x85, 0x78, 0xdb, 0xc3, 0x08, 0xd2, 0xe8, 0x32, 0x8e, 0x5f, 0x65, 0x9a, 0x6a, 0xbf, 0x61,
+	0xda, 0xb3, 0x60, 0xc6, 0x23, 0x00, 0x25, 0xeb, 0x11, 0x80, 0x35, 0xe1, 0x9c, 0x0c, 0x42, 0xbf,
+	0xd5, 0xc4, 0xb8, 0x1c, 0x47, 0x59, 0x55, 0xf6, 0x48, 0x4e, 0x8d, 0xf3, 0x69, 0xb1, 0xdc, 0x0b,
+	0x9e, 0xc6, 0x8d, 0xa4, 0xca, 0x3a, 0xe1, 0xca, 0xaf, 0x24, 0x0a, 0x73, 0x20, 0x08, 0xef, 0xf3,
+	0xf1, 0x72, 0x59, 0x30, 0x94, 0x1f, 0xad, 0xc0, 0x6f, 0x75, 0xda, 0xbd, 0x40, 0xb5, 0xc9, 0x92,
+	0x26, 0x05, 0x26, 0x39, 0x6e, 0xd0, 0x46, 0xef, 0x86, 0x63, 0x7d, 0xcd, 0x41, 0xc1, 0x75, 0xf0,
+	0x66, 0x56, 0x1d, 0x0d, 0x45, 0x54, 0xc3, 0xc6, 0xef, 0xa2, 0x9d, 0xdd, 0x4b, 0x5f, 0x79, 0x29,
+	0x54, 0xf7, 0x50, 0xcc, 0xde, 0x1b, 0x76, 0xfb, 0x14, 0xdb, 0x91, 0xfa, 0xe0, 0x8a, 0xb5, 0xb0,
+	0x66, 0x5a, 0xcc, 0xce, 0xd4, 0x5d, 0x10, 0x73, 0xba, 0x45, 0x56, 0x41, 0xff, 0x80, 0xfe, 0x55,
+	0x92, 0x63, 0xfc, 0xbf, 0x7a, 0xcf, 0xc1, 0x1c, 0x56, 0x29, 0x35, 0xac, 0x8f, 0x24, 0x75, 0x7a,
+	0x3c, 0x3f, 0x75, 0x7a, 0x89, 0x8e, 0x84, 0xf9, 0x10, 0x67, 0xc6, 0x93, 0x05, 0xf7, 0xf3, 0xe8,
+	0xa6, 0x60, 0x44, 0x42, 0x0a, 0xc8, 0x4d, 0x3e, 0x0f, 0xb3, 0xee, 0xcb, 0x5e, 0x76, 0x6a, 0xe6,
+	0xde, 0x14, 0x37, 0xf2, 0x1b, 0x60, 0x92, 0xfd, 0xa0, 0x20, 0x26, 0x77, 0xc2, 0xfe, 0x0e, 0x3f,
+	0xee, 0x40, 0x36, 0x96, 0x6e, 0x49, 0x15, 0xcd, 0x53, 0xe0, 0x62, 0x26, 0x53, 0x37, 0x9b, 0x0b,
+	0x38, 0x93, 0xce, 0x05, 0xfc, 0x39, 0x71, 0x9d, 0xd6, 0x6e, 0x10, 0xa2, 0x7b, 0x03, 0xfa, 0xc3,
+	0xef, 0xc8, 0xc4, 0x3f, 0x8c, 0xad, 0xa9, 0x8d, 0x72, 0x19, 0x0a, 0xbd, 0x4f, 0x80, 0x0f, 0x39,
+	0xf1, 0x09, 0xb1, 0x69, 0xed, 0x64, 0x2b, 0xdc, 0xcf, 0x8a, 0x69, 0x7d, 0x78, 0x0a, 0x9f, 0x4e,
+	0xa3, 0xf3, 0x23, 0x4f, 0x58, 0xed, 0x28, 0x3d, 0xcf, 0xdc, 0x4b, 0x10, 0xee, 0xfc, 0x61, 0x11,
+	0xf6, 0x45, 0xce, 0xf9, 0x12, 0x3e, 0x3a, 0x83, 0x62, 0xe5, 0x81, 0x57, 0x6f, 0x78, 0xf5, 0x8d,
+	0xa3, 0x83, 0xfd, 0xc6, 0xfe, 0xc1, 0x3e, 0xde, 0x83, 0xae, 0x89, 0x95, 0x54, 0x85, 0xba, 0x0d,
+	0x5f, 0x70, 0xae, 0x8b, 0xd5, 0xcc, 0x47, 0x0d, 0x0f, 0xea, 0x50, 0x5a, 0x55, 0xc5, 0x52, 0xaa,
+	0xb2, 0xee, 0x79, 0x07, 0xde, 0x7c, 0x09, 0x86, 0xfc, 0x5a, 0xaa, 0x66, 0x77, 0x7f, 0xf3, 0xc0,
+	0xf3, 0xea, 0x9b, 0xc7, 0x8d, 0xc3, 0x8d, 0x2f, 0xdd, 0xaf, 0xef, 0x1f, 0x37, 0xb6, 0xea, 0xc7,
+	0x80, 0x72, 0x34, 0x3f, 0xe6, 0xbc, 0x2a, 0x6e, 0x67, 0xb0, 0x8f, 0x1e, 0x6c, 0x6f, 0xef, 0x6e,
+	0xee, 0x22, 0xe2, 0xbd, 0x8d, 0x3d, 0xcc, 0x08, 0x98, 0x1f, 0x77, 0x6e, 0x81, 0x85, 0x66, 0x23,
+	0x1e, 0xd6, 0xeb, 0x5e, 0xe3, 0x60, 0x7b, 0x1b, 0xa4, 0x66, 0x7d, 0x7e, 0x02, 0xdc, 0x89, 0x6a,
+	0x0a, 0x61, 0xbb, 0x5e, 0x6f, 0xec, 0xed, 0xde, 0xdf, 0x3d, 0x9e, 0x9f, 0xbc, 0xf3, 0x39, 0x51,
+	0x1d, 0x65, 0x17, 0xa0, 0x14, 0xf6, 0xea, 0x47, 0x0f, 0xee, 0x23, 0x41, 0xa6, 0xc4, 0x58, 0x56,
+	0x36, 0xaf, 0xff, 0x6b, 0x41, 0xcc, 0x6c, 0xf9, 0xb1, 0x8f, 0x5c, 0x21, 0x53, 0x18, 0xba, 0x62,
+	0x2e, 0xf5, 0x04, 0x9f, 0xa3, 0x8e, 0xc1, 0xf2, 0x5f, 0xed, 0xab, 0xdd, 0x1c, 0x55, 0xad, 0x72,
+	0xce, 0xbe, 0xf9, 0x83, 0x7f, 0xff, 0x56, 0x71, 0xd9, 0x59, 0xbc, 0xfb, 0xf8, 0x8d, 0xbb, 0xfa,
+	0x09, 0x3d, 0x3e, 0x3b, 0xfb, 0x05, 0x31, 0x67, 0x19, 0x4a, 0xe0, 0x36, 0xdc, 0xe6, 0xf6, 0x2e,
+	0xb3, 0xa3, 0x6a, 0xee, 0xa5, 0x48, 0x34, 0xb0, 0xd7, 0x0a, 0xaf, 0x17, 0xd6, 0xbf, 0x79, 0x17,
+	0xb4, 0x8f, 0x4a, 0xa3, 0x74, 0xbe, 0x26, 0x66, 0xac, 0x5b, 0x09, 0x8e, 0x3a, 0xbc, 0xcc, 0xbb,
+	0xe6, 0x50, 0xbb, 0x91, 0x5f, 0xc9, 0xd3, 0xba, 0x49, 0xd3, 0xaa, 0x3a, 0x2b, 0x38, 0x2d, 0xbe,
+	0x76, 0x70, 0x97, 0x84, 0x88, 0xbc, 0xbe, 0xfb, 0x48, 0x87, 0xf3, 0x54, 0x67, 0x37, 0x6c, 0xef,
+	0x23, 0xd5, 0xdb, 0x8b, 0x23, 0x6a, 0xb9, 0xbb, 0x1b, 0xd4, 0xdd, 0x8a, 0xb3, 0x64, 0x76, 0xa7,
+	0xd3, 0x1b, 0x03, 0xba, 0x70, 0x6d, 0xbe, 0x98, 0xa7, 0x57, 0x2d, 0xff, 0x25, 0xbd, 0xda, 0xb5,
+	0xec, 0xeb, 0x78, 0xfc, 0x9c, 0x9e, 0x5b, 0xa5, 0xae, 0x1c, 0x67, 0x1e, 0xbb, 0x32, 0x1f, 0xcc,
+	0x73, 0xbe, 0x22, 0xa6, 0xf5, 0x5b, 0x53, 0xce, 0xaa, 0xf1, 0xb2, 0x96, 0xf9, 0x7a, 0x55, 0xad,
+	0x9a, 0xad, 0xb0, 0x59, 0xc1, 0xcd, 0xb4, 0xfc, 0x4e, 0xe1, 0x8e, 0xb3, 0x27, 0x96, 0xb5, 0x3d,
+	0xf8, 0xa3, 0xcc, 0x24, 0xe7, 0x9d, 0xbf, 0xd7, 0x0b, 0xa0, 0x18, 0xa7, 0xd4, 0xf3, 0x5b, 0xce,
+	0x4a, 0xfe, 0x1b, 0x60, 0xb5, 0xd5, 0x0c, 0x9c, 0xb5, 0xea, 0x86, 0x10, 0xc9, 0x6b, 0x53, 0x4e,
+	0x75, 0xd4, 0xa3, 0x58, 0x9a, 0x88, 0x39, 0x4f, 0x53, 0x9d, 0xd1, 0x63, 0x5b, 0xf6, 0x63, 0x56,
+	0xce, 0xad, 0x04, 0x3f, 0xf7, 0x99, 0xab, 0x4b, 0x1a, 0x74, 0x57, 0x88, 0x76, 0xf3, 0xce, 0x2c,
+	0xd2, 0xae, 0x17, 0x3c, 0x51, 0x4f, 0x0f, 0x6c, 0x89, 0xb2, 0xf1, 0x82, 0x95, 0xa3, 0x5a, 0xc8,
+	0xbe, 0x7e, 0x55, 0xab, 0xe5, 0x55, 0xf1, 0x70, 0x7f, 0x5e, 0xcc, 0x58, 0x4f, 0x51, 0xe9, 0x9d,
+	0x91, 0xf7, 0xd0, 0x95, 0xde, 0x19, 0xf9, 0xaf, 0x57, 0x7d, 0x59, 0x94, 0x8d, 0x87, 0xa3, 0x1c,
+	0xe3, 0x46, 0x68, 0xea, 0x61, 0x28, 0x3d, 0xa2, 0x9c, 0x77, 0xa6, 0xdc, 0x25, 0x9a, 0xef, 0xac,
+	0x3b, 0x8d, 0xf3, 0xa5, 0xfb, 0xf7, 0xc8, 0x24, 0x5f, 0x13, 0xb3, 0xf6, 0x83, 0x51, 0x7a, 0x57,
+	0xe5, 0x3e, 0x3d, 0xa5, 0x77, 0xd5, 0x88, 0x57, 0xa6, 0x98, 0x21, 0xef, 0x2c, 0xea, 0x4e, 0xee,
+	0x7e, 0xc8, 0xa1, 0xe5, 0x67, 0xce, 0x17, 0x50, 0x74, 0xf0, 0x83, 0x08, 0x4e, 0xf2, 0x80, 0x96,
+	0xfd, 0x6c, 0x82, 0xe6, 0xf6, 0xcc, 0xdb, 0x09, 0xee, 0x02, 0x35, 0x5e, 0x76, 0x92, 0x19, 0x38,
+	0xf7, 0xc5, 0x24, 0x3f, 0x8c, 0xe0, 0x2c, 0x27, 0x5c, 0x6d, 0xa4, 0x5c, 0xd7, 0x56, 0xd2, 0x60,
+	0x6e, 0x6c, 0x91, 0x1a, 0x9b, 0x71, 0xca, 0xd8, 0xd8, 0x59, 0x10, 0xb7, 0xb1, 0x8d, 0x8e, 0x98,
+	0xb3, 0xef, 0xa6, 0x45, 0x9a, 0x1c, 0xb9, 0xb7, 0x62, 0x35, 0x39, 0xf2, 0x2f, 0xba, 0xd9, 0x42,
+	0x46, 0x09, 0x97, 0xbb, 0xea, 0xc2, 0xef, 0x57, 0x45, 0xc5, 0x7c, 0x7d, 0xc7, 0xa9, 0x19, 0x33,
+	0x4f, 0x3d, 0x1a, 0x52, 0xbb, 0x9e, 0x5b, 0x67, 0x2f, 0xad, 0x53, 0x31, 0xbb, 0xc1, 0xa5, 0xb5,
+	0x1f, 0xfb, 0x48, 0x04, 0x66, 0xde, 0xbb, 0x24, 0x89, 0xc0, 0xcc, 0x7d, 0x21, 0xc4, 0x56, 0x3b,
+	0x7a, 0x2e, 0x32, 0x1f, 0x14, 0x58, 0x74, 0xce, 0xb8, 0xb0, 0x79, 0x74, 0xd1, 0x6b, 0x6a, 0x36,
+	0xcd, 0x5e, 0x34, 0xaf, 0xe5, 0x85, 0x8d, 0xdc, 0x55, 0x6a, 0x7f, 0xc1, 0xb5, 0x26, 0x81, 0x2c,
+	0xba, 0x29, 0xca, 0xe6, 0x65, 0xd0, 0x4b, 0xda, 0x5d, 0x35, 0xaa, 0xcc, 0x6b, 0xd9, 0x20, 0xbe,
+	0x7e, 0x1f, 0x5f, 0x69, 0x34, 0x9e, 0x2a, 0x70, 0xac, 0xac, 0xe7, 0x54, 0x3b, 0x55, 0xb3, 0xce,
+	0x6c, 0xc8, 0xdd, 0xa7, 0x41, 0xee, 0xdc, 0xd9, 0xb6, 0x88, 0xf0, 0xa1, 0x75, 0xd0, 0xb5, 0x66,
+	0xbe, 0xe0, 0xf8, 0x2c, 0x5d, 0x69, 0x5e, 0xc4, 0x7f, 0x06, 0x03, 0x7b, 0x47, 0x3e, 0x99, 0xaa,
+	0xf2, 0xae, 0x1c, 0x43, 0x84, 0xa6, 0xc9, 0x65, 0xbe, 0xa8, 0x89, 0xca, 0xd8, 0xf9, 0x45, 0xf9,
+	0x68, 0xa3, 0xca, 0xed, 0x41, 0xaa, 0x3f, 0xef, 0xf7, 0xee, 0x2b, 0x34, 0x93, 0x9b, 0xee, 0x35,
+	0x6b, 0x26, 0x69, 0x1d, 0x72, 0x28, 0x44, 0x92, 0x54, 0xe8, 0xa4, 0x72, 0xdd, 0xb4, 0x74, 0xcd,
+	0xe6, 0x1d, 0xaa, 0xd5, 0x84, 0x36, 0xe4, 0x82, 0xaa, 0xac, 0x38, 0xe0, 0xca, 0x8a, 0x91, 0x58,
+	0x17, 0xe9, 0xe5, 0xcc, 0xa6, 0xe9, 0xd5, 0x6a, 0x79, 0x55, 0xdc, 0xfe, 0x6d, 0x6a, 0xff, 0x45,
+	0xe7, 0xba, 0xd9, 0x38, 0xc8, 0x1a, 0x23, 0xad, 0xef, 0x99, 0xf3, 0x81, 0x98, 0xd9, 0x0b, 0xc3,
+	0x47, 0xc3, 0xbe, 0x4e, 0x16, 0xb6, 0x13, 0x57, 0x30, 0xb5, 0xb0, 0x96, 0x9a, 0x94, 0xfb, 0x32,
+	0xb5, 0x7c, 0xdd, 0xb9, 0x66, 0xb7, 0x9c, 0x24, 0x1b, 0x3e, 0x73, 0x7c, 0xb1, 0xa0, 0x35, 0xab,
+	0x9e, 0x48, 0xcd, 0x6e, 0xc7, 0xcc, 0xcd, 0xcb, 0xf4, 0x61, 0xd9, 0x3a, 0xba, 0x8f, 0x48, 0xb5,
+	0x09, 0x4b, 0x5b, 0x17, 0x55, 0xdd, 0x85, 0xf4, 0x6a, 0x5a, 0xba, 0xa7, 0x65, 0xbd, 0x9e, 0x66,
+	0x76, 0x61, 0xba, 0x13, 0xe2, 0x90, 0x43, 0x51, 0xd9, 0x0a, 0x30, 0x2a, 0xc1, 0x59, 0x25, 0x8b,
+	0x09, 0x01, 0x74, 0x36, 0x4a, 0x6d, 0xc6, 0x02, 0xda, 0x42, 0xab, 0xef, 0x5f, 0x0c, 0x82, 0xaf,
+	0x03, 0x61, 0x65, 0xba, 0xca, 0x33, 0x25, 0xb4, 0x0e, 0x75, 0x4a, 0x91, 0x29, 0xae, 0xed, 0x9c,
+	0x1c, 0x4b, 0x68, 0x65, 0x72, 0x72, 0x2c, 0xa1, 0xa5, 0x13, 0x88, 0x3a, 0x98, 0xa9, 0x93, 0x4a,
+	0xe3, 0xd1, 0x6a, 0x7e, 0x54, 0xf2, 0x4f, 0xed, 0xa5, 0xd1, 0x08, 0x76, 0x6f, 0x77, 0xec, 0xde,
+	0x8e, 0xc0, 0x5a, 0x0f, 0x24, 0x91, 0xe5, 0xc5, 0xa1, 0xd4, 0xa3, 0x47, 0xe6, 0x25, 0xa3, 0xb4,
+	0xd4, 0xa2, 0x3a, 0x5b, 0x27, 0xd1, 0xad, 0x1d, 0x30, 0xea, 0xca, 0xa0, 0x6c, 0xd4, 0x4d, 0x21,
+	0x6d, 0x2c, 0xa5, 0xae, 0x0e, 0xd5, 0x72, 0x2e, 0x1a, 0xb9, 0x2f, 0x51, 0x6b, 0x35, 0xa7, 0xaa,
+	0x5b, 0xbb, 0x8b, 0x57, 0x8f, 0xa4, 0x0c, 0x01, 0x4f, 0xf4, 0x99, 0xf3, 0x45, 0x6a, 0x5c, 0x5f,
+	0x23, 0x5c, 0x31, 0xce, 0x10, 0xcc, 0xc6, 0xe7, 0x52, 0xf0, 0xbc, 0x96, 0xd1, 0xef, 0x35, 0xb4,
+	0x73, 0x4f, 0x94, 0x8d, 0xdb, 0xae, 0x7a, 0x5f, 0x66, 0x2f, 0xfd, 0xea, 0x7d, 0x99, 0x73, 0x39,
+	0xd6, 0x7d, 0x8d, 0xfa, 0x71, 0x9d, 0x97, 0x92, 0x7e, 0xe4, 0x85, 0xd8, 0xa4, 0xa7, 0xbb, 0x1f,
+	0xfa, 0xdd, 0xf8, 0x99, 0xf3, 0x90, 0x9e, 0x39, 0x32, 0x6f, 0x43, 0x25, 0xc6, 0x5a, 0xfa, 0xe2,
+	0x94, 0x26, 0x96, 0x51, 0x65, 0x1b, 0x70, 0xb2, 0x2b, 0x52, 0xe2, 0x9f, 0x11, 0x02, 0xef, 0xe8,
+	0x6c, 0xf9, 0x41, 0x17, 0x7c, 0x36, 0x2d, 0x10, 0x93, 0x5b, 0x3c, 0x89, 0x40, 0x34, 0xae, 0xf2,
+	0xc0, 0x78, 0x12, 0x73, 0xd9, 0xba, 0x4c, 0xa6, 0x98, 0x6b, 0xe4, 0x45, 0x1f, 0x4d, 0x90, 0x9c,
+	0xcb, 0x3e, 0xca, 0x72, 0x96, 0x37, 0x18, 0x0c, 0xcb, 0xd9, 0xba, 0x02, 0x61, 0x58, 0xce, 0xf6,
+	0x55, 0x07, 0xb4, 0x9c, 0x93, 0x8c, 0x33, 0x6d, 0x39, 0x67, 0x92, 0xd9, 0xb4, 0x28, 0xce, 0x49,
+	0x4f, 0x3b, 0x14, 0xd3, 0x49, 0x0e, 0x97, 0xea, 0x28, 0x9d, 0xf1, 0xa5, 0x75, 0x5e, 0x26, 0x9f,
+	0xc9, 0x9d, 0x27, 0x3a, 0x0b, 0x67, 0x0a, 0xe9, 0x4c, 0x49, 0x4a, 0xc7, 0x42, 0xc8, 0xd9, 0x6d,
+	0x63, 0xc9, 0x68, 0xd2, 0x3a, 0x49, 0x32, 0x9b, 0x4c, 0x1d, 0xa3, 0xb0, 0xf1, 0xe5, 0xea, 0x26,
+	0x51, 0xd7, 0xf8, 0x78, 0x37, 0xd5, 0xc8, 0x98, 0x71, 0x4c, 0xf1, 0x91, 0x4e, 0x7f, 0xd1, 0x26,
+	0x73, 0x6e, 0x92, 0x8d, 0xbb, 0x4c, 0x1d, 0xcc, 0x39, 0x33, 0xe4, 0xdd, 0xe9, 0x16, 0xbf, 0x26,
+	0xe6, 0x52, 0x19, 0x2f, 0xda, 0x19, 0xca, 0xcf, 0xb2, 0xd1, 0xce, 0xf8, 0xa8, 0x44, 0x19, 0xf6,
+	0xed, 0x50, 0xcf, 0xa5, 0xfa, 0xfa, 0x7e, 0x41, 0x2c, 0xa0, 0x1c, 0xb0, 0x52, 0x5e, 0x12, 0x13,
+	0x2c, 0x2f, 0xbb, 0x26, 0x31, 0xc1, 0x72, 0xf3, 0x64, 0xdc, 0xaf, 0x52, 0x67, 0x0f, 0x9d, 0x07,
+	0xb6, 0x09, 0xa6, 0x91, 0x2f, 0x33, 0x44, 0x48, 0x73, 0x5d, 0x6a, 0x8c, 0x38, 0xbb, 0x62, 0x2e,
+	0x95, 0x4a, 0xa3, 0xa9, 0x93, 0x9f, 0x62, 0x53, 0x5b, 0xb6, 0x65, 0x18, 0xe7, 0xd9, 0x00, 0xcf,
+	0xc7, 0xfc, 0x88, 0xb2, 0x95, 0xc0, 0x72, 0xcb, 0xf4, 0x63, 0x73, 0xb2, 0x6d, 0xb4, 0x18, 0x1f,
+	0x9d, 0x36, 0xc3, 0xba, 0xc9, 0x5d, 0x20, 0x0a, 0x10, 0x0a, 0x1f, 0x59, 0x23, 0x07, 0x3d, 0x13,
+	0xab, 0x23, 0x92, 0x6a, 0x9c, 0x9f, 0x52, 0x4d, 0x5f, 0x9a, 0x74, 0x53, 0x53, 0x97, 0xb7, 0xac,
+	0x5a, 0xdb, 0xd8, 0xb0, 0x7a, 0xb5, 0x74, 0xf6, 0x53, 0x7e, 0x2f, 0xc0, 0xce, 0x6c, 0x70, 0x5e,
+	0x36, 0xc5, 0x65, 0x6e, 0xa6, 0x85, 0x8e, 0xbe, 0x5c, 0x92, 0x3e, 0xe2, 0xd6, 0x68, 0x10, 0x4b,
+	0x8e, 0x23, 0xc3, 0x3e, 0x84, 0xd3, 0xe4, 0x2e, 0x7e, 0xa5, 0x20, 0x16, 0x73, 0x32, 0x3d, 0x74,
+	0xd7, 0xa3, 0x73, 0x44, 0x74, 0xd7, 0x97, 0x25, 0x8a, 0xf0, 0xfc, 0xdd, 0x6a, 0xb6, 0xeb, 0xbb,
+	0x03, 0xfc, 0x0e, 0x89, 0xff, 0x1b, 0x05, 0xb1, 0x9c, 0x9b, 0xda, 0xa1, 0x03, 0x50, 0x97, 0x25,
+	0x9b, 0xd4, 0x5e, 0xb9, 0x1c, 0x29, 0xcf, 0x6a, 0x4d, 0x8d, 0xa4, 0x4d, 0x1f, 0xe2, 0x50, 0x5a,
+	0x42, 0x24, 0xa9, 0x1f, 0x5a, 0x68, 0x66, 0xd2, 0x4a, 0xb4, 0xd0, 0xcc, 0xe6, 0x89, 0x28, 0x2b,
+	0xd0, 0x5d, 0xc9, 0xe8, 0xb1, 0x13, 0x44, 0xc6, 0x5e, 0x62, 0x69, 0x7d, 0x73, 0x8e, 0x84, 0xe5,
+	0xf3, 0x64, 0xb3, 0x47, 0x92, 0x60, 0x41, 0x36, 0xad, 0xc2, 0xbd, 0x43, 0x9d, 0xbd, 0xe2, 0xde,
+	0x1a, 0x69, 0x8b, 0xcb, 0xce, 0xb1, 0x57, 0xb0, 0xbf, 0x8e, 0x07, 0x20, 0x63, 0xd2, 0x0e, 0x43,
+	0x9e, 0x49, 0xcb, 0x30, 0xf7, 0x55, 0x6a, 0xff, 0x65, 0xe7, 0x96, 0x69, 0xfc, 0x60, 0xfb, 0xcd,
+	0x47, 0x96, 0x61, 0x0b, 0x3c, 0xfc, 0x4b, 0x62, 0x3e, 0x9d, 0x16, 0xe1, 0xdc, 0x34, 0xb9, 0x33,
+	0x9b, 0x9f, 0x51, 0xbb, 0x35, 0xb2, 0x9e, 0xe7, 0xf7, 0x71, 0xea, 0xff, 0xb6, 0x7b, 0x33, 0x67,
+	0xd5, 0x8c, 0xac, 0x0a, 0x9c, 0x5e, 0x5b, 0x2c, 0x4a, 0x51, 0xab, 0x7d, 0x43, 0xba, 0x2f, 0xa9,
+	0xa8, 0x97, 0x93, 0xb0, 0xa0, 0xad, 0xcc, 0xdc, 0x03, 0xfb, 0x6b, 0xd4, 0xf5, 0xa2, 0x3b, 0xab,
+	0x48, 0x2b, 0xef, 0x6a, 0x62, 0x57, 0x3f, 0xe1, 0x50, 0xa9, 0x73, 0x22, 0x66, 0xac, 0x13, 0x5f,
+	0x23, 0xc0, 0x67, 0x9f, 0x1b, 0x1b, 0x01, 0xbe, 0xf4, 0x01, 0x31, 0x3b, 0x0a, 0xee, 0xa2, 0xed,
+	0x28, 0x10, 0x1e, 0xce, 0x01, 0xfa, 0xb0, 0x0e, 0x82, 0x75, 0x1f, 0xe9, 0x63, 0xe5, 0xc4, 0xa7,
+	0xcd, 0x9c, 0x1b, 0xe7, 0xf7, 0x21, 0x9f, 0x12, 0xc5, 0x3e, 0x7a, 0x62, 0x31, 0xe7, 0x5c, 0x59,
+	0xcb, 0x96, 0xd1, 0x67, 0xce, 0xb5, 0xf9, 0xf4, 0x89, 0xb2, 0x6d, 0x86, 0x62, 0xba, 0x05, 0x9d,
+	0x2b, 0xdb, 0xae, 0xcf, 0xa9, 0x7e, 0xe6, 0x4e, 0x9e, 0xcd, 0x69, 0x3b, 0x20, 0xef, 0x24, 0xaf,
+	0x76, 0x23, 0xbf, 0x32, 0x4f, 0x68, 0xca, 0x53, 0x3a, 0x1d, 0x7e, 0x79, 0x28, 0x26, 0xf9, 0x6c,
+	0x4d, 0x7b, 0x54, 0xf6, 0xe9, 0x9d, 0x8e, 0x1d, 0xa5, 0x8f, 0xe0, 0x5e, 0xa4, 0x56, 0x57, 0x5d,
+	0xb3, 0xd5, 0x13, 0xc0, 0x01, 0x4b, 0x06, 0x09, 0xd6, 0x10, 0x4b, 0x79, 0xc7, 0x51, 0x4e, 0x22,
+	0x6a, 0x47, 0x1e, 0x76, 0xd5, 0x6e, 0x5f, 0x8a, 0x23, 0xfb, 0x3f, 0x99, 0xa0, 0xff, 0xf8, 0xe7,
+	0x53, 0xff, 0x03, 0xb6, 0x47, 0xe2, 0xa5, 0x2a, 0x68, 0x00, 0x00,
 }
@@ -52,6 +52,24 @@ var (
 	// ErrInsufficientBalance is returned when a proposed HTLC would
 	// exceed the available balance.
 	ErrInsufficientBalance = fmt.Errorf("insufficient local balance")
+
+	// ErrCommitSyncLocalDataLoss is returned when the remote party proves
+	// during channel reestablishment that it knows of a state newer than
+	// our own, meaning we've lost data. Our latest commitment is then
+	// revoked, and MUST NOT be broadcast.
+	ErrCommitSyncLocalDataLoss = fmt.Errorf("possible local commitment " +
+		"state data loss")
+
+	// ErrInvalidLastCommitSecret is returned when the remote party claims
+	// to know of a newer state than our own, but the commitment secret it
+	// sent along doesn't match the one we would've revealed at that state.
+	ErrInvalidLastCommitSecret = fmt.Errorf("commit secret is incorrect")
+
+	// ErrForceCloseLocalDataLoss is returned when attempting to force
+	// close a channel for which we've lost local state, as our latest
+	// commitment may have been revoked.
+	ErrForceCloseLocalDataLoss = fmt.Errorf("cannot force close channel " +
+		"with local data loss")
 )
 
 // channelState is an enum like type which represents the current state of a
@@ -150,7 +168,7 @@ type PaymentDescriptor struct {
 	Timeout uint32
 
 	// Amount is the HTLC amount in milli-satoshis.
-	Amount lnwire.MilliSatoshi
+	Amount lnwire.MilliAtom
 
 	// Index is the log entry number that his HTLC update has within the
 	// log. Depending on if IsIncoming is true, this is either an entry the
@@ -180,7 +198,7 @@ type PaymentDescriptor struct {
 	// local node. This signature is generated by the remote node and
 	// stored by the local node in the case that local node needs to
 	// broadcast their commitment transaction.
-	sig *btcec.Signature
+	sig *lnwire.Sig
 
 	// addCommitHeight[Remote|Local] encodes the height of the commitment
 	// which included this HTLC on either the remote or local commitment
@@ -260,8 +278,8 @@ type commitment struct {
 	// within the commitment chain. This balance is computed by properly
 	// evaluating all the add/remove/settle log entries before the listed
 	// indexes.
-	ourBalance   lnwire.MilliSatoshi
-	theirBalance lnwire.MilliSatoshi
+	ourBalance   lnwire.MilliAtom
+	theirBalance lnwire.MilliAtom
 
 	// fee is the amount that will be paid as fees for this commitment
 	// transaction. The fee is recorded here so that it can be added
@@ -462,7 +480,11 @@ func (c *commitment) toChannelDelta(ourCommit bool) (*channeldb.ChannelDelta, er
 		}
 
 		if ourCommit && htlc.sig != nil {
-			h.Signature = htlc.sig.Serialize()
+			sig, err := htlc.sig.Serialize()
+			if err != nil {
+				return nil, err
+			}
+			h.Signature = sig
 		}
 
 		delta.Htlcs = append(delta.Htlcs, h)
@@ -483,7 +505,11 @@ func (c *commitment) toChannelDelta(ourCommit bool) (*channeldb.ChannelDelta, er
 		}
 
 		if ourCommit && htlc.sig != nil {
-			h.Signature = htlc.sig.Serialize()
+			sig, err := htlc.sig.Serialize()
+			if err != nil {
+				return nil, err
+			}
+			h.Signature = sig
 		}
 
 		delta.Htlcs = append(delta.Htlcs, h)
@@ -822,7 +848,7 @@ type LightningChannel struct {
 
 	// availableLocalBalance represent the amount of available money which
 	// might be processed by this channel at the specific point of time.
-	availableLocalBalance lnwire.MilliSatoshi
+	availableLocalBalance lnwire.MilliAtom
 
 	sync.RWMutex
 
@@ -849,12 +875,12 @@ func NewLightningChannel(signer Signer, events chainntnfs.ChainNotifier,
 
 	var stateHint [StateHintSize]byte
 	if state.IsInitiator {
-		stateHint = deriveStateHintObfuscator(
+		stateHint = DeriveStateHintObfuscator(
 			state.LocalChanCfg.PaymentBasePoint,
 			state.RemoteChanCfg.PaymentBasePoint,
 		)
 	} else {
-		stateHint = deriveStateHintObfuscator(
+		stateHint = DeriveStateHintObfuscator(
 			state.RemoteChanCfg.PaymentBasePoint,
 			state.LocalChanCfg.PaymentBasePoint,
 		)
@@ -1032,6 +1058,16 @@ type HtlcRetribution struct {
 	// OutPoint is the target outpoint of this HTLC pointing to the
 	// breached commitment transaction.
 	OutPoint wire.OutPoint
+
+	// SecondLevelWitnessScript is the witness script of the output of the
+	// remote party's second-level HTLC transaction. Should the remote
+	// party spend this HTLC with their second-level transaction, then its
+	// output can be swept using the revocation clause of this script.
+	SecondLevelWitnessScript []byte
+
+	// IsIncoming denotes whether the HTLC was incoming from our PoV,
+	// meaning that the remote party offered it to us.
+	IsIncoming bool
 }
 
 // BreachRetribution contains all the data necessary to bring a channel
@@ -1056,7 +1092,8 @@ type BreachRetribution struct {
 
 	// LocalOutputSignDesc is a SignDescriptor which is capable of
 	// generating the signature necessary to sweep the output within the
-	// BreachTransaction that pays directly us.
+	// BreachTransaction that pays directly us. If the output was trimmed
+	// as dust, then the value of its Output is zero.
 	LocalOutputSignDesc SignDescriptor
 
 	// LocalOutpoint is the outpoint of the output paying to us (the local
@@ -1066,7 +1103,8 @@ type BreachRetribution struct {
 	// RemoteOutputSignDesc is a SignDescriptor which is capable of
 	// generating the signature required to claim the funds as described
 	// within the revocation clause of the remote party's commitment
-	// output.
+	// output. If the output was trimmed as dust, then the value of its
+	// Output is zero.
 	RemoteOutputSignDesc SignDescriptor
 
 	// RemoteOutpoint is the output of the output paying to the remote
@@ -1143,27 +1181,48 @@ func newBreachRetribution(chanState *channeldb.OpenChannel, stateNum uint64,
 	}
 
 	// In order to fully populate the breach retribution struct, we'll need
-	// to find the exact index of the local+remote commitment outputs.
+	// to find the exact index of the local+remote commitment outputs. As
+	// either output may have been trimmed as dust, their amounts are taken
+	// from the commitment transaction itself, leaving them at zero if the
+	// output isn't present.
 	localOutpoint := wire.OutPoint{
 		Hash: commitHash,
 	}
 	remoteOutpoint := wire.OutPoint{
 		Hash: commitHash,
 	}
+	var localAmt, remoteAmt int64
 	for i, txOut := range broadcastCommitment.TxOut {
 		switch {
 		case bytes.Equal(txOut.PkScript, localPkScript):
 			localOutpoint.Index = uint32(i)
+			localAmt = txOut.Value
 		case bytes.Equal(txOut.PkScript, remoteWitnessHash):
 			remoteOutpoint.Index = uint32(i)
+			remoteAmt = txOut.Value
 		}
 	}
 
+	// Should the remote party spend any of the HTLC outputs with their
+	// second-level transactions, then we'll need the script of the
+	// resulting outputs in order to sweep them using the revocation key.
+	secondLevelScript, err := secondLevelHtlcScript(revocationKey,
+		remoteDelayKey, remoteDelay)
+	if err != nil {
+		return nil, err
+	}
+
 	// With the commitment outputs located, we'll now generate all the
 	// retribution structs for each of the HTLC transactions active on the
 	// remote commitment transaction.
-	htlcRetributions := make([]HtlcRetribution, len(chanState.Htlcs))
-	for i, htlc := range revokedSnapshot.Htlcs {
+	htlcRetributions := make([]HtlcRetribution, 0, len(revokedSnapshot.Htlcs))
+	for _, htlc := range revokedSnapshot.Htlcs {
+		// Dust HTLCs aren't present as outputs on the commitment
+		// transaction, so there's nothing for us to sweep.
+		if htlc.OutputIndex < 0 {
+			continue
+		}
+
 		var (
 			htlcScript []byte
 			err        error
@@ -1192,7 +1251,7 @@ func newBreachRetribution(chanState *channeldb.OpenChannel, stateNum uint64,
 			}
 		}
 
-		htlcRetributions[i] = HtlcRetribution{
+		htlcRetributions = append(htlcRetributions, HtlcRetribution{
 			SignDesc: SignDescriptor{
 				PubKey:        chanState.LocalChanCfg.RevocationBasePoint,
 				DoubleTweak:   commitmentSecret,
@@ -1206,7 +1265,9 @@ func newBreachRetribution(chanState *channeldb.OpenChannel, stateNum uint64,
 				Hash:  commitHash,
 				Index: uint32(htlc.OutputIndex),
 			},
-		}
+			SecondLevelWitnessScript: secondLevelScript,
+			IsIncoming:               htlc.Incoming,
+		})
 	}
 
 	// We'll need to reconstruct the single tweak so we can sweep our
@@ -1228,7 +1289,7 @@ func newBreachRetribution(chanState *channeldb.OpenChannel, stateNum uint64,
 			WitnessScript: localPkScript,
 			Output: &wire.TxOut{
 				PkScript: localWitnessHash,
-				Value:    int64(revokedSnapshot.LocalBalance.ToSatoshis()),
+				Value:    localAmt,
 			},
 			HashType: txscript.SigHashAll,
 		},
@@ -1239,7 +1300,7 @@ func newBreachRetribution(chanState *channeldb.OpenChannel, stateNum uint64,
 			WitnessScript: remotePkScript,
 			Output: &wire.TxOut{
 				PkScript: remoteWitnessHash,
-				Value:    int64(revokedSnapshot.RemoteBalance.ToSatoshis()),
+				Value:    remoteAmt,
 			},
 			HashType: txscript.SigHashAll,
 		},
@@ -1343,8 +1404,7 @@ func (lc *LightningChannel) closeObserver(channelCloseNtfn *chainntnfs.SpendEven
 		// immediately delete the state from disk, creating a close
 		// summary for future usage by related sub-systems.
 		//
-		// TODO(roasbeef): include HTLC's
-		//  * and time-locked balance, NEED TO???
+		// TODO(roasbeef): include time-locked balance, NEED TO???
 		closeSummary := channeldb.ChannelCloseSummary{
 			ChanPoint:      lc.channelState.FundingOutpoint,
 			ClosingTXID:    *commitSpend.SpenderTxHash,
@@ -1353,6 +1413,11 @@ func (lc *LightningChannel) closeObserver(channelCloseNtfn *chainntnfs.SpendEven
 			SettledBalance: lc.channelState.LocalBalance.ToSatoshis(),
 			CloseType:      channeldb.ForceClose,
 			IsPending:      true,
+			HtlcResolutions: htlcResolutionSummaries(
+				lc.channelState.FeePerKw, false,
+				lc.channelState.Htlcs, lc.localChanCfg,
+				lc.remoteChanCfg,
+			),
 		}
 		if err := lc.DeleteState(&closeSummary); err != nil {
 			walletLog.Errorf("unable to delete channel state: %v",
@@ -1953,7 +2018,7 @@ func (lc *LightningChannel) fetchCommitmentView(remoteChain bool,
 // reflects the current state of HTLCs within the remote or local commitment
 // chain.
 func (lc *LightningChannel) evaluateHTLCView(view *htlcView, ourBalance,
-	theirBalance *lnwire.MilliSatoshi, nextHeight uint64, remoteChain bool) *htlcView {
+	theirBalance *lnwire.MilliAtom, nextHeight uint64, remoteChain bool) *htlcView {
 
 	newView := &htlcView{}
 
@@ -2038,7 +2103,7 @@ func (lc *LightningChannel) evaluateHTLCView(view *htlcView, ourBalance,
 // If the HTLC hasn't yet been committed in either chain, then the height it
 // was committed is updated. Keeping track of this inclusion height allows us to
 // later compact the log once the change is fully committed in both chains.
-func processAddEntry(htlc *PaymentDescriptor, ourBalance, theirBalance *lnwire.MilliSatoshi,
+func processAddEntry(htlc *PaymentDescriptor, ourBalance, theirBalance *lnwire.MilliAtom,
 	nextHeight uint64, remoteChain bool, isIncoming bool) {
 
 	// If we're evaluating this entry for the remote chain (to create/view
@@ -2074,7 +2139,7 @@ func processAddEntry(htlc *PaymentDescriptor, ourBalance, theirBalance *lnwire.M
 // previously added HTLC. If the removal entry has already been processed, it
 // is skipped.
 func processRemoveEntry(htlc *PaymentDescriptor, ourBalance,
-	theirBalance *lnwire.MilliSatoshi, nextHeight uint64,
+	theirBalance *lnwire.MilliAtom, nextHeight uint64,
 	remoteChain bool, isIncoming bool) {
 
 	var removeHeight *uint64
@@ -2276,7 +2341,7 @@ func genRemoteHtlcSigJobs(commitPoint *btcec.PublicKey,
 // itself, while the second parameter is a slice of all HTLC signatures (if
 // any). The HTLC signatures are sorted according to the BIP 69 order of the
 // HTLC's on the commitment transaction.
-func (lc *LightningChannel) SignNextCommitment() (*btcec.Signature, []*btcec.Signature, error) {
+func (lc *LightningChannel) SignNextCommitment() (*lnwire.Sig, []*lnwire.Sig, error) {
 	lc.Lock()
 	defer lc.Unlock()
 
@@ -2349,7 +2414,7 @@ func (lc *LightningChannel) SignNextCommitment() (*btcec.Signature, []*btcec.Sig
 		close(cancelChan)
 		return nil, nil, err
 	}
-	sig, err := btcec.ParseSignature(rawSig, btcec.S256())
+	sig, err := lnwire.NewSigFromRawSignature(rawSig)
 	if err != nil {
 		close(cancelChan)
 		return nil, nil, err
@@ -2363,7 +2428,7 @@ func (lc *LightningChannel) SignNextCommitment() (*btcec.Signature, []*btcec.Sig
 
 	// With the jobs sorted, we'll now iterate through all the responses to
 	// gather each of the signatures in order.
-	htlcSigs := make([]*btcec.Signature, 0, len(sigBatch))
+	htlcSigs := make([]*lnwire.Sig, 0, len(sigBatch))
 	for _, htlcSigJob := range sortedSigs {
 		jobResp := <-htlcSigJob.resp
 
@@ -2474,7 +2539,7 @@ func (lc *LightningChannel) validateCommitmentSanity(theirLogCounter,
 // commitment state. The jobs generated are fully populated, and can be sent
 // directly into the pool of workers.
 func genHtlcSigValidationJobs(localCommitmentView *commitment,
-	commitPoint *btcec.PublicKey, htlcSigs []*btcec.Signature,
+	commitPoint *btcec.PublicKey, htlcSigs []*lnwire.Sig,
 	localChanCfg, remoteChanCfg *channeldb.ChannelConfig) []verifyJob {
 
 	// If this new commitment state doesn't have any HTLC's that are to be
@@ -2621,8 +2686,8 @@ func genHtlcSigValidationJobs(localCommitmentView *commitment,
 // to our local commitment chain. Once we send a revocation for our prior
 // state, then this newly added commitment becomes our current accepted channel
 // state.
-func (lc *LightningChannel) ReceiveNewCommitment(commitSig *btcec.Signature,
-	htlcSigs []*btcec.Signature) error {
+func (lc *LightningChannel) ReceiveNewCommitment(commitSig *lnwire.Sig,
+	htlcSigs []*lnwire.Sig) error {
 
 	lc.Lock()
 	defer lc.Unlock()
@@ -2717,7 +2782,10 @@ func (lc *LightningChannel) ReceiveNewCommitment(commitSig *btcec.Signature,
 
 	// The signature checks out, so we can now add the new commitment to
 	// our local commitment chain.
-	localCommitmentView.sig = commitSig.Serialize()
+	localCommitmentView.sig, err = commitSig.Serialize()
+	if err != nil {
+		return err
+	}
 	lc.localCommitChain.addCommitment(localCommitmentView)
 
 	// If we are not channel initiator, then the commitment just received
@@ -2758,6 +2826,100 @@ func (lc *LightningChannel) FullySynced() bool {
 	return !oweCommitment && localUpdatesSynced && remoteUpdatesSynced
 }
 
+// ChanSyncMsg returns the ChannelReestablish message that should be sent to
+// the remote party upon reconnection. The data loss protection fields are
+// always populated: the last commitment secret the remote party revealed to
+// us, and the commitment point of our current unrevoked commitment.
+func (lc *LightningChannel) ChanSyncMsg() (*lnwire.ChannelReestablish, error) {
+	lc.RLock()
+	defer lc.RUnlock()
+
+	remoteTailHeight := lc.remoteCommitChain.tail().height
+
+	// If the remote party has revoked at least a single state, then we'll
+	// send along the secret of the last state it revoked. Otherwise, the
+	// zero value is sent.
+	var lastCommitSecret [32]byte
+	if remoteTailHeight > 0 {
+		secret, err := lc.channelState.RevocationStore.LookUp(
+			remoteTailHeight - 1,
+		)
+		if err != nil {
+			return nil, err
+		}
+		copy(lastCommitSecret[:], secret[:])
+	}
+
+	currentSecret, err := lc.channelState.RevocationProducer.AtIndex(
+		lc.currentHeight,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnwire.ChannelReestablish{
+		ChanID: lnwire.NewChanIDFromOutPoint(
+			&lc.channelState.FundingOutpoint,
+		),
+		NextLocalCommitHeight:     lc.localCommitChain.tip().height + 1,
+		RemoteCommitTailHeight:    remoteTailHeight,
+		LastRemoteCommitSecret:    lastCommitSecret,
+		LocalUnrevokedCommitPoint: ComputeCommitmentPoint(currentSecret[:]),
+	}, nil
+}
+
+// CheckDataLoss inspects the ChannelReestablish message sent by the remote
+// party to determine whether we've lost channel state. If the remote party
+// has received more revocations from us than we know of, and the commitment
+// secret it sent along proves as much, then ErrCommitSyncLocalDataLoss is
+// returned. In this case our latest commitment has already been revoked, so
+// the caller MUST NOT broadcast it. If the remote party's claim can't be
+// verified, ErrInvalidLastCommitSecret is returned instead.
+func (lc *LightningChannel) CheckDataLoss(msg *lnwire.ChannelReestablish) error {
+	lc.RLock()
+	defer lc.RUnlock()
+
+	// If the remote party doesn't know of more revocations than we've
+	// sent, then we haven't lost any state.
+	if msg.RemoteCommitTailHeight <= lc.currentHeight {
+		return nil
+	}
+
+	// Without the data loss protection fields, the remote party has no
+	// way of proving its claim.
+	if !msg.HasDataLossProtection() {
+		return ErrInvalidLastCommitSecret
+	}
+
+	// Otherwise, the secret sent along must be the one we revealed when
+	// revoking the state directly preceding the claimed tail.
+	secret, err := lc.channelState.RevocationProducer.AtIndex(
+		msg.RemoteCommitTailHeight - 1,
+	)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(secret[:], msg.LastRemoteCommitSecret[:]) {
+		return ErrInvalidLastCommitSecret
+	}
+
+	walletLog.Errorf("ChannelPoint(%v): remote party knows of revocation "+
+		"for height %v, while our local commitment height is %v",
+		lc.channelState.FundingOutpoint,
+		msg.RemoteCommitTailHeight-1, lc.currentHeight)
+
+	return ErrCommitSyncLocalDataLoss
+}
+
+// MarkDataLoss marks the channel as having lost local state. Broadcasting our
+// latest commitment could be seen as a breach, so the channel is marked as
+// borked as well, preventing any further updates to its state.
+func (lc *LightningChannel) MarkDataLoss() error {
+	return lc.channelState.ApplyChanStatus(
+		channeldb.ChanStatusLocalDataLoss | channeldb.ChanStatusBorked,
+	)
+}
+
 // RevokeCurrentCommitment revokes the next lowest unrevoked commitment
 // transaction in the local commitment chain. As a result the edge of our
 // revocation window is extended by one, and the tail of our local commitment
@@ -2841,13 +3003,102 @@ func (lc *LightningChannel) RevokeCurrentCommitment() (*lnwire.RevokeAndAck, err
 
 // LocalAvailableBalance returns the amount of available money which might be
 // proceed by this channel at the specific point of time.
-func (lc *LightningChannel) LocalAvailableBalance() lnwire.MilliSatoshi {
+func (lc *LightningChannel) LocalAvailableBalance() lnwire.MilliAtom {
 	lc.Lock()
 	defer lc.Unlock()
 
 	return lc.availableLocalBalance
 }
 
+// HtlcIsDust returns whether an HTLC of the passed amount would be dust on the
+// local and the remote commitment respectively, at the current fee rate. The
+// incoming flag denotes whether the HTLC is offered by the remote party.
+func (lc *LightningChannel) HtlcIsDust(amt lnwire.MilliAtom,
+	incoming bool) (bool, bool) {
+
+	lc.Lock()
+	defer lc.Unlock()
+
+	return lc.htlcIsDust(amt, incoming, true),
+		lc.htlcIsDust(amt, incoming, false)
+}
+
+// htlcIsDust returns whether an HTLC of the passed amount would be dust on
+// either our commitment or the remote party's, at the current fee rate.
+//
+// NOTE: This method must be called with the channel's mutex held.
+func (lc *LightningChannel) htlcIsDust(amt lnwire.MilliAtom, incoming,
+	ourCommit bool) bool {
+
+	dustLimit := lc.remoteChanCfg.DustLimit
+	if ourCommit {
+		dustLimit = lc.localChanCfg.DustLimit
+	}
+
+	return htlcIsDust(incoming, ourCommit, lc.channelState.FeePerKw,
+		amt.ToSatoshis(), dustLimit)
+}
+
+// DustExposure returns the total value of the active HTLCs which are dust on
+// the local and the remote commitment respectively. As the value of dust
+// HTLCs is burned to fees should the channel be force closed, this is the
+// value at risk to such a close. Every HTLC added to either update log which
+// hasn't been removed is counted, whether or not it has been locked in, as it
+// may end up on either commitment.
+func (lc *LightningChannel) DustExposure() (lnwire.MilliAtom,
+	lnwire.MilliAtom) {
+
+	lc.Lock()
+	defer lc.Unlock()
+
+	view := lc.fetchHTLCView(
+		lc.remoteUpdateLog.logIndex, lc.localUpdateLog.logIndex,
+	)
+
+	// First, we'll note the HTLCs which have been removed by a settle or
+	// fail from the other side's log, so they can be skipped below.
+	removedOurs := make(map[uint64]struct{})
+	removedTheirs := make(map[uint64]struct{})
+	for _, entry := range view.ourUpdates {
+		if entry.EntryType != Add {
+			removedTheirs[entry.ParentIndex] = struct{}{}
+		}
+	}
+	for _, entry := range view.theirUpdates {
+		if entry.EntryType != Add {
+			removedOurs[entry.ParentIndex] = struct{}{}
+		}
+	}
+
+	var localDust, remoteDust lnwire.MilliAtom
+	addDust := func(entry *PaymentDescriptor, incoming bool) {
+		if lc.htlcIsDust(entry.Amount, incoming, true) {
+			localDust += entry.Amount
+		}
+		if lc.htlcIsDust(entry.Amount, incoming, false) {
+			remoteDust += entry.Amount
+		}
+	}
+	for _, entry := range view.ourUpdates {
+		if _, ok := removedOurs[entry.Index]; ok ||
+			entry.EntryType != Add {
+
+			continue
+		}
+		addDust(entry, false)
+	}
+	for _, entry := range view.theirUpdates {
+		if _, ok := removedTheirs[entry.Index]; ok ||
+			entry.EntryType != Add {
+
+			continue
+		}
+		addDust(entry, true)
+	}
+
+	return localDust, remoteDust
+}
+
 // ReceiveRevocation processes a revocation sent by the remote party for the
 // lowest unrevoked commitment within their commitment chain. We receive a
 // revocation either during the initial session negotiation wherein revocation
@@ -3084,6 +3335,23 @@ func (lc *LightningChannel) SettleHTLC(preimage [32]byte) (uint64, error) {
 	return targetHTLC.Index, nil
 }
 
+// OutgoingHtlcAmount returns the value of the outgoing HTLC at the passed
+// index into the local log. If the specified index doesn't exist within the
+// log, then an error is returned.
+func (lc *LightningChannel) OutgoingHtlcAmount(logIndex uint64) (lnwire.MilliAtom,
+	error) {
+
+	lc.Lock()
+	defer lc.Unlock()
+
+	htlc := lc.localUpdateLog.lookup(logIndex)
+	if htlc == nil {
+		return 0, fmt.Errorf("non existant log entry")
+	}
+
+	return htlc.Amount, nil
+}
+
 // ReceiveHTLCSettle attempts to settle an existing outgoing HTLC indexed by an
 // index into the local log. If the specified index doesn't exist within the
 // log, and error is returned. Similarly if the preimage is invalid w.r.t to
@@ -3195,6 +3463,26 @@ func (lc *LightningChannel) ShortChanID() lnwire.ShortChannelID {
 	return lc.channelState.ShortChanID
 }
 
+// LocalUpfrontShutdownScript returns the script we committed to pay out to
+// upon a cooperative close when the channel was funded, or nil if we made no
+// such commitment.
+func (lc *LightningChannel) LocalUpfrontShutdownScript() []byte {
+	lc.RLock()
+	defer lc.RUnlock()
+
+	return lc.channelState.UpfrontShutdownScript
+}
+
+// RemoteUpfrontShutdownScript returns the script the remote party committed
+// to pay out to upon a cooperative close when the channel was funded, or nil
+// if they made no such commitment.
+func (lc *LightningChannel) RemoteUpfrontShutdownScript() []byte {
+	lc.RLock()
+	defer lc.RUnlock()
+
+	return lc.channelState.RemoteUpfrontShutdownScript
+}
+
 // genHtlcScript generates the proper P2WSH public key scripts for the
 // HTLC output modified by two-bits denoting if this is an incoming HTLC, and
 // if the HTLC is being applied to their commitment transaction or ours.
@@ -3294,14 +3582,15 @@ func (lc *LightningChannel) addHTLC(commitTx *wire.MsgTx, ourCommit bool,
 // it with witness data.
 func (lc *LightningChannel) getSignedCommitTx() (*wire.MsgTx, error) {
 	// Fetch the current commitment transaction, along with their signature
-	// for the transaction.
-	commitTx := lc.channelState.CommitTx
+	// for the transaction. We operate on a deep copy so the witness we
+	// attach below doesn't leak into the persisted channel state.
+	commitTx := lc.channelState.CommitTx.Copy()
 	theirSig := append(lc.channelState.CommitSig, byte(txscript.SigHashAll))
 
 	// With this, we then generate the full witness so the caller can
 	// broadcast a fully signed transaction.
-	lc.signDesc.SigHashes = txscript.NewTxSigHashes(&commitTx)
-	ourSigRaw, err := lc.signer.SignOutputRaw(&commitTx, lc.signDesc)
+	lc.signDesc.SigHashes = txscript.NewTxSigHashes(commitTx)
+	ourSigRaw, err := lc.signer.SignOutputRaw(commitTx, lc.signDesc)
 	if err != nil {
 		return nil, err
 	}
@@ -3316,7 +3605,7 @@ func (lc *LightningChannel) getSignedCommitTx() (*wire.MsgTx, error) {
 	commitTx.TxIn[0].Witness = SpendMultiSig(lc.FundingWitnessScript, ourKey,
 		ourSig, theirKey, theirSig)
 
-	return &commitTx, nil
+	return commitTx, nil
 }
 
 // UnilateralCloseSummary describes the details of a detected unilateral
@@ -3368,12 +3657,44 @@ type OutgoingHtlcResolution struct {
 	// delay+claim state.
 	SignedTimeoutTx *wire.MsgTx
 
+	// CsvDelay is the relative time lock (expressed in blocks) that must
+	// pass after the timeout transaction has confirmed before its output
+	// can be swept.
+	CsvDelay uint32
+
 	// SweepSignDesc is a sign descriptor that has been populated with the
 	// necessary items required to spend the sole output of the above
 	// transaction.
 	SweepSignDesc SignDescriptor
 }
 
+// IncomingHtlcResolution houses the information necessary to claim an incoming
+// HTLC present on our commitment transaction once its preimage is known. The
+// HTLC is first transitioned to the delay+claim state by the second-level
+// success transaction, after which its output can be swept once the CSV delay
+// has passed.
+type IncomingHtlcResolution struct {
+	// SignedSuccessTx is the HTLC success transaction, signed by both
+	// parties. As the signatures don't commit to the preimage, the witness
+	// holds a zero placeholder in its place, which must be replaced by the
+	// preimage before the transaction is broadcast.
+	SignedSuccessTx *wire.MsgTx
+
+	// CsvDelay is the relative time lock (expressed in blocks) that must
+	// pass after the success transaction has confirmed before its output
+	// can be swept.
+	CsvDelay uint32
+
+	// SweepSignDesc is a sign descriptor that has been populated with the
+	// necessary items required to spend the sole output of the above
+	// transaction.
+	SweepSignDesc SignDescriptor
+}
+
+// SuccessPreimageIndex is the index of the preimage within the witness of the
+// success transaction of an IncomingHtlcResolution.
+const SuccessPreimageIndex = 3
+
 // newHtlcResolution generates a new HTLC resolution capable of allowing the
 // caller to sweep an outgoing HTLC present on either their, or the remote
 // party's commitment transaction.
@@ -3444,6 +3765,7 @@ func newHtlcResolution(signer Signer, localChanCfg *channeldb.ChannelConfig,
 	return &OutgoingHtlcResolution{
 		Expiry:          htlc.RefundTimeout,
 		SignedTimeoutTx: timeoutTx,
+		CsvDelay:        uint32(localChanCfg.CsvDelay),
 		SweepSignDesc: SignDescriptor{
 			PubKey:        localChanCfg.DelayBasePoint,
 			SingleTweak:   commitTweak,
@@ -3510,6 +3832,168 @@ func extractHtlcResolutions(feePerKw btcutil.Amount, ourCommit bool,
 	return htlcResolutions, localKey, nil
 }
 
+// newIncomingHtlcResolution generates a new HTLC resolution capable of allowing
+// the caller to claim an incoming HTLC present on our commitment transaction,
+// using the second-level success transaction signed by the remote party.
+func newIncomingHtlcResolution(signer Signer,
+	localChanCfg *channeldb.ChannelConfig, commitHash chainhash.Hash,
+	htlc *channeldb.HTLC, commitTweak []byte,
+	delayKey, localKey, remoteKey *btcec.PublicKey, revokeKey *btcec.PublicKey,
+	feePerKw btcutil.Amount) (*IncomingHtlcResolution, error) {
+
+	op := wire.OutPoint{
+		Hash:  commitHash,
+		Index: uint32(htlc.OutputIndex),
+	}
+
+	// As with the timeout transaction, the output of the success
+	// transaction is the value of the HTLC less the fee required at this
+	// state.
+	htlcFee := htlcSuccessFee(feePerKw)
+	secondLevelOutputAmt := htlc.Amt.ToSatoshis() - htlcFee
+
+	successTx, err := createHtlcSuccessTx(op, secondLevelOutputAmt,
+		uint32(localChanCfg.CsvDelay), revokeKey, delayKey,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// The HTLC output on our commitment uses the receiver's version of
+	// the HTLC script, which the success transaction spends by way of the
+	// multi-sig clause.
+	htlcCreationScript, err := receiverHTLCScript(htlc.RefundTimeout,
+		remoteKey, localKey, revokeKey, htlc.RHash[:])
+	if err != nil {
+		return nil, err
+	}
+	successSignDesc := SignDescriptor{
+		PubKey:        localChanCfg.PaymentBasePoint,
+		SingleTweak:   commitTweak,
+		WitnessScript: htlcCreationScript,
+		Output: &wire.TxOut{
+			Value: int64(htlc.Amt.ToSatoshis()),
+		},
+		HashType:   txscript.SigHashAll,
+		SigHashes:  txscript.NewTxSigHashes(successTx),
+		InputIndex: 0,
+	}
+
+	// We may not yet know the preimage, so a zero placeholder is used in
+	// its place. As neither signature commits to the preimage, it can be
+	// swapped in once known without invalidating the witness.
+	var zeroPreimage [32]byte
+	successWitness, err := receiverHtlcSpendRedeem(htlc.Signature,
+		zeroPreimage[:], signer, &successSignDesc, successTx)
+	if err != nil {
+		return nil, err
+	}
+	successTx.TxIn[0].Witness = successWitness
+
+	htlcSweepScript, err := secondLevelHtlcScript(revokeKey,
+		delayKey, uint32(localChanCfg.CsvDelay))
+	if err != nil {
+		return nil, err
+	}
+
+	return &IncomingHtlcResolution{
+		SignedSuccessTx: successTx,
+		CsvDelay:        uint32(localChanCfg.CsvDelay),
+		SweepSignDesc: SignDescriptor{
+			PubKey:        localChanCfg.DelayBasePoint,
+			SingleTweak:   commitTweak,
+			WitnessScript: htlcSweepScript,
+			Output: &wire.TxOut{
+				Value: int64(secondLevelOutputAmt),
+			},
+			HashType: txscript.SigHashAll,
+		},
+	}, nil
+}
+
+// extractIncomingHtlcResolutions creates an incoming HTLC resolution for each
+// non-dust incoming HTLC present on our commitment transaction. Unlike the
+// outgoing resolutions, these can only be created for our own commitment, as
+// the remote party's signatures are only held for our second-level
+// transactions.
+func extractIncomingHtlcResolutions(feePerKw btcutil.Amount, signer Signer,
+	htlcs []*channeldb.HTLC, commitPoint, revokeKey *btcec.PublicKey,
+	localChanCfg, remoteChanCfg *channeldb.ChannelConfig,
+	commitHash chainhash.Hash) ([]IncomingHtlcResolution, error) {
+
+	commitTweak := SingleTweakBytes(commitPoint,
+		localChanCfg.PaymentBasePoint)
+	localKey := TweakPubKey(localChanCfg.PaymentBasePoint, commitPoint)
+	delayKey := TweakPubKey(localChanCfg.DelayBasePoint, commitPoint)
+	remoteKey := TweakPubKey(remoteChanCfg.PaymentBasePoint, commitPoint)
+
+	var htlcResolutions []IncomingHtlcResolution
+	for _, htlc := range htlcs {
+		if !htlc.Incoming {
+			continue
+		}
+
+		if htlcIsDust(htlc.Incoming, true, feePerKw,
+			htlc.Amt.ToSatoshis(), localChanCfg.DustLimit) {
+			continue
+		}
+
+		ihr, err := newIncomingHtlcResolution(signer, localChanCfg,
+			commitHash, htlc, commitTweak, delayKey, localKey,
+			remoteKey, revokeKey, feePerKw)
+		if err != nil {
+			return nil, err
+		}
+
+		htlcResolutions = append(htlcResolutions, *ihr)
+	}
+
+	return htlcResolutions, nil
+}
+
+// htlcResolutionSummaries returns a compact summary detailing how each of the
+// passed HTLC's present on the commitment transaction that closed the channel
+// is to be resolved on-chain.
+func htlcResolutionSummaries(feePerKw btcutil.Amount, ourCommit bool,
+	htlcs []*channeldb.HTLC, localChanCfg,
+	remoteChanCfg *channeldb.ChannelConfig) []channeldb.HtlcResolutionSummary {
+
+	dustLimit := remoteChanCfg.DustLimit
+	if ourCommit {
+		dustLimit = localChanCfg.DustLimit
+	}
+
+	summaries := make([]channeldb.HtlcResolutionSummary, 0, len(htlcs))
+	for _, htlc := range htlcs {
+		summary := channeldb.HtlcResolutionSummary{
+			RHash:         htlc.RHash,
+			Amt:           htlc.Amt,
+			Incoming:      htlc.Incoming,
+			RefundTimeout: htlc.RefundTimeout,
+		}
+
+		// HTLC's which were trimmed from the commitment transaction
+		// have no output to resolve, while incoming HTLC's are only
+		// claimed on-chain should we know their preimage, which isn't
+		// known at this point.
+		switch {
+		case htlcIsDust(htlc.Incoming, ourCommit, feePerKw,
+			htlc.Amt.ToSatoshis(), dustLimit):
+			summary.Outcome = channeldb.HtlcOutcomeDust
+
+		case htlc.Incoming:
+			summary.Outcome = channeldb.HtlcOutcomeUnclaimed
+
+		default:
+			summary.Outcome = channeldb.HtlcOutcomeTimeout
+		}
+
+		summaries = append(summaries, summary)
+	}
+
+	return summaries
+}
+
 // ForceCloseSummary describes the final commitment state before the channel is
 // locked-down to initiate a force closure by broadcasting the latest state
 // on-chain. The summary includes all the information required to claim all
@@ -3544,6 +4028,16 @@ type ForceCloseSummary struct {
 	// local node to sweep any outgoing HTLC"s after the timeout period has
 	// passed.
 	HtlcResolutions []OutgoingHtlcResolution
+
+	// IncomingHtlcResolutions is a slice of HTLC resolutions which allows
+	// the local node to claim any incoming HTLC's for which the preimage
+	// is known, using their second-level success transactions.
+	IncomingHtlcResolutions []IncomingHtlcResolution
+
+	// HtlcSummaries details how each HTLC present on the commitment
+	// transaction is to be resolved, including those which were trimmed
+	// as dust.
+	HtlcSummaries []channeldb.HtlcResolutionSummary
 }
 
 // ForceClose executes a unilateral closure of the transaction at the current
@@ -3560,6 +4054,13 @@ func (lc *LightningChannel) ForceClose() (*ForceCloseSummary, error) {
 	lc.Lock()
 	defer lc.Unlock()
 
+	// If we've lost local state, then our latest commitment may already
+	// have been revoked, so broadcasting it would hand all funds to the
+	// remote party.
+	if lc.channelState.HasChanStatus(channeldb.ChanStatusLocalDataLoss) {
+		return nil, ErrForceCloseLocalDataLoss
+	}
+
 	// Set the channel state to indicate that the channel is now in a
 	// contested state.
 	lc.status = channelDispute
@@ -3644,6 +4145,13 @@ func (lc *LightningChannel) ForceClose() (*ForceCloseSummary, error) {
 	if err != nil {
 		return nil, err
 	}
+	incomingResolutions, err := extractIncomingHtlcResolutions(
+		lc.channelState.FeePerKw, lc.signer, lc.channelState.Htlcs,
+		commitPoint, revokeKey, lc.localChanCfg, lc.remoteChanCfg, txHash,
+	)
+	if err != nil {
+		return nil, err
+	}
 
 	// Finally, close the channel force close signal which notifies any
 	// subscribers that the channel has now been forcibly closed. This
@@ -3657,10 +4165,84 @@ func (lc *LightningChannel) ForceClose() (*ForceCloseSummary, error) {
 			Hash:  commitTx.TxHash(),
 			Index: delayIndex,
 		},
-		CloseTx:            commitTx,
-		SelfOutputSignDesc: selfSignDesc,
-		SelfOutputMaturity: csvTimeout,
-		HtlcResolutions:    htlcResolutions,
+		CloseTx:                 commitTx,
+		SelfOutputSignDesc:      selfSignDesc,
+		SelfOutputMaturity:      csvTimeout,
+		HtlcResolutions:         htlcResolutions,
+		IncomingHtlcResolutions: incomingResolutions,
+		HtlcSummaries: htlcResolutionSummaries(
+			lc.channelState.FeePerKw, true, lc.channelState.Htlcs,
+			lc.localChanCfg, lc.remoteChanCfg,
+		),
+	}, nil
+}
+
+// LocalCommitTxns houses the fully signed local commitment transaction of a
+// channel, along with the fully signed second-level HTLC timeout transactions
+// which spend each of our outgoing HTLC outputs within it.
+type LocalCommitTxns struct {
+	// CommitTx is the latest fully signed local commitment transaction.
+	// This is exactly the transaction that would be broadcast were the
+	// channel to be force closed at this instant.
+	CommitTx *wire.MsgTx
+
+	// HtlcTimeoutTxns is the set of fully signed HTLC timeout
+	// transactions for each non-dust outgoing HTLC present within the
+	// above commitment transaction.
+	HtlcTimeoutTxns []*wire.MsgTx
+}
+
+// LocalCommitTxns returns the fully signed latest local commitment
+// transaction, along with the second-level HTLC transactions spending from it.
+// Unlike ForceClose, this method doesn't modify the state of the channel, so
+// it can be used to inspect what would be broadcast in the case of a force
+// close.
+func (lc *LightningChannel) LocalCommitTxns() (*LocalCommitTxns, error) {
+	lc.Lock()
+	defer lc.Unlock()
+
+	commitTx, err := lc.getSignedCommitTx()
+	if err != nil {
+		return nil, err
+	}
+
+	// In order to re-create the second-level HTLC transactions, we'll
+	// need the commitment point and revocation key of our current state.
+	unusedRevocation, err := lc.channelState.RevocationProducer.AtIndex(
+		lc.currentHeight,
+	)
+	if err != nil {
+		return nil, err
+	}
+	commitPoint := ComputeCommitmentPoint(unusedRevocation[:])
+	revokeKey := DeriveRevocationPubkey(
+		lc.remoteChanCfg.RevocationBasePoint,
+		commitPoint,
+	)
+
+	htlcResolutions, _, err := extractHtlcResolutions(
+		lc.channelState.FeePerKw, true, lc.signer, lc.channelState.Htlcs,
+		commitPoint, revokeKey, lc.localChanCfg, lc.remoteChanCfg,
+		commitTx.TxHash(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// The set of resolutions contains an empty entry for each incoming or
+	// dust HTLC, so we'll only return those with a timeout transaction.
+	var htlcTxns []*wire.MsgTx
+	for _, resolution := range htlcResolutions {
+		if resolution.SignedTimeoutTx == nil {
+			continue
+		}
+
+		htlcTxns = append(htlcTxns, resolution.SignedTimeoutTx)
+	}
+
+	return &LocalCommitTxns{
+		CommitTx:        commitTx,
+		HtlcTimeoutTxns: htlcTxns,
 	}, nil
 }
 
@@ -3811,6 +4393,19 @@ func (lc *LightningChannel) DeleteState(c *channeldb.ChannelCloseSummary) error
 	return lc.channelState.CloseChannel(c)
 }
 
+// MarkBorked marks the channel as having entered an irreconcilable state.
+// Once borked, no further updates to the channel's state will be persisted,
+// leaving a unilateral close as the only way to resolve the channel.
+func (lc *LightningChannel) MarkBorked() error {
+	return lc.channelState.MarkBorked()
+}
+
+// MarkCommitmentBroadcasted records within the channel's persistent state that
+// one of our commitment transactions has been broadcast.
+func (lc *LightningChannel) MarkCommitmentBroadcasted() error {
+	return lc.channelState.MarkCommitmentBroadcasted()
+}
+
 // StateSnapshot returns a snapshot of the current fully committed state within
 // the channel.
 func (lc *LightningChannel) StateSnapshot() *channeldb.ChannelSnapshot {
@@ -224,9 +224,9 @@ func createTestChannels(revocationWindow int) (*LightningChannel, *LightningChan
 	aliceCfg := channeldb.ChannelConfig{
 		ChannelConstraints: channeldb.ChannelConstraints{
 			DustLimit:        aliceDustLimit,
-			MaxPendingAmount: lnwire.MilliSatoshi(rand.Int63()),
+			MaxPendingAmount: lnwire.MilliAtom(rand.Int63()),
 			ChanReserve:      btcutil.Amount(rand.Int63()),
-			MinHTLC:          lnwire.MilliSatoshi(rand.Int63()),
+			MinHTLC:          lnwire.MilliAtom(rand.Int63()),
 			MaxAcceptedHtlcs: uint16(rand.Int31()),
 		},
 		CsvDelay:            uint16(csvTimeoutAlice),
@@ -238,9 +238,9 @@ func createTestChannels(revocationWindow int) (*LightningChannel, *LightningChan
 	bobCfg := channeldb.ChannelConfig{
 		ChannelConstraints: channeldb.ChannelConstraints{
 			DustLimit:        bobDustLimit,
-			MaxPendingAmount: lnwire.MilliSatoshi(rand.Int63()),
+			MaxPendingAmount: lnwire.MilliAtom(rand.Int63()),
 			ChanReserve:      btcutil.Amount(rand.Int63()),
-			MinHTLC:          lnwire.MilliSatoshi(rand.Int63()),
+			MinHTLC:          lnwire.MilliAtom(rand.Int63()),
 			MaxAcceptedHtlcs: uint16(rand.Int31()),
 		},
 		CsvDelay:            uint16(csvTimeoutBob),
@@ -378,7 +378,7 @@ func calcStaticFee(numHTLCs int) btcutil.Amount {
 
 // createHTLC is a utility function for generating an HTLC with a given
 // preimage and a given amount.
-func createHTLC(data int, amount lnwire.MilliSatoshi) (*lnwire.UpdateAddHTLC, [32]byte) {
+func createHTLC(data int, amount lnwire.MilliAtom) (*lnwire.UpdateAddHTLC, [32]byte) {
 	preimage := bytes.Repeat([]byte{byte(data)}, 32)
 	paymentHash := sha256.Sum256(preimage)
 
@@ -515,8 +515,8 @@ func TestSimpleAddSettleWorkflow(t *testing.T) {
 	// At this point, both sides should have the proper number of satoshis
 	// sent, and commitment height updated within their local channel
 	// state.
-	aliceSent := lnwire.MilliSatoshi(0)
-	bobSent := lnwire.MilliSatoshi(0)
+	aliceSent := lnwire.MilliAtom(0)
+	bobSent := lnwire.MilliAtom(0)
 
 	if aliceChannel.channelState.TotalMSatSent != aliceSent {
 		t.Fatalf("alice has incorrect milli-satoshis sent: %v vs %v",
@@ -709,7 +709,7 @@ func TestCheckCommitTxSize(t *testing.T) {
 	// Adding HTLCs and check that size stays in allowable estimation
 	// error window.
 	for i := 1; i <= 10; i++ {
-		htlc, _ := createHTLC(i, lnwire.MilliSatoshi(1e7))
+		htlc, _ := createHTLC(i, lnwire.MilliAtom(1e7))
 
 		if _, err := aliceChannel.AddHTLC(htlc); err != nil {
 			t.Fatalf("alice unable to add htlc: %v", err)
@@ -728,7 +728,7 @@ func TestCheckCommitTxSize(t *testing.T) {
 	// Settle HTLCs and check that estimation is counting cost of settle
 	// HTLCs properly.
 	for i := 10; i >= 1; i-- {
-		_, preimage := createHTLC(i, lnwire.MilliSatoshi(1e7))
+		_, preimage := createHTLC(i, lnwire.MilliAtom(1e7))
 
 		settleIndex, err := bobChannel.SettleHTLC(preimage)
 		if err != nil {
@@ -995,6 +995,245 @@ func TestForceClose(t *testing.T) {
 	}
 }
 
+// TestLocalCommitTxns tests that the local commitment transaction and its
+// second-level HTLC transactions can be retrieved without modifying the state
+// of the channel.
+func TestLocalCommitTxns(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, bobChannel, cleanUp, err := createTestChannels(3)
+	if err != nil {
+		t.Fatalf("unable to create test channels: %v", err)
+	}
+	defer cleanUp()
+
+	// Add a single non-dust HTLC from Alice to Bob, and lock it into both
+	// commitment transactions.
+	htlcAmount := lnwire.NewMSatFromSatoshis(btcutil.SatoshiPerBitcoin)
+	htlc, _ := createHTLC(0, htlcAmount)
+	if _, err := aliceChannel.AddHTLC(htlc); err != nil {
+		t.Fatalf("alice unable to add htlc: %v", err)
+	}
+	if _, err := bobChannel.ReceiveHTLC(htlc); err != nil {
+		t.Fatalf("bob unable to receive htlc: %v", err)
+	}
+	if err := forceStateTransition(aliceChannel, bobChannel); err != nil {
+		t.Fatalf("Can't update the channel state: %v", err)
+	}
+
+	txns, err := aliceChannel.LocalCommitTxns()
+	if err != nil {
+		t.Fatalf("unable to fetch local commit txns: %v", err)
+	}
+
+	// The returned commitment transaction should be our current one, and
+	// should carry a witness.
+	commitTxHash := aliceChannel.channelState.CommitTx.TxHash()
+	if txns.CommitTx.TxHash() != commitTxHash {
+		t.Fatalf("incorrect commitment transaction txid")
+	}
+	if len(txns.CommitTx.TxIn[0].Witness) == 0 {
+		t.Fatalf("commitment transaction isn't signed")
+	}
+
+	// The persisted commitment transaction shouldn't have been modified.
+	if len(aliceChannel.channelState.CommitTx.TxIn[0].Witness) != 0 {
+		t.Fatalf("persisted commitment transaction was modified")
+	}
+
+	// Alice has a single outgoing HTLC, so there should be a single
+	// timeout transaction spending from the commitment transaction.
+	if len(txns.HtlcTimeoutTxns) != 1 {
+		t.Fatalf("expected 1 htlc timeout txn, got %v",
+			len(txns.HtlcTimeoutTxns))
+	}
+	timeoutTx := txns.HtlcTimeoutTxns[0]
+	if timeoutTx.TxIn[0].PreviousOutPoint.Hash != commitTxHash {
+		t.Fatalf("timeout txn doesn't spend commitment txn")
+	}
+	if len(timeoutTx.TxIn[0].Witness) == 0 {
+		t.Fatalf("timeout transaction isn't signed")
+	}
+
+	// Bob only has an incoming HTLC, so he has no timeout transactions.
+	txns, err = bobChannel.LocalCommitTxns()
+	if err != nil {
+		t.Fatalf("unable to fetch local commit txns: %v", err)
+	}
+	if len(txns.HtlcTimeoutTxns) != 0 {
+		t.Fatalf("expected no htlc timeout txns, got %v",
+			len(txns.HtlcTimeoutTxns))
+	}
+
+	// Finally, the channel should still be usable as neither call should
+	// have transitioned the channel into a closing state.
+	if aliceChannel.status == channelDispute {
+		t.Fatalf("channel status modified")
+	}
+	select {
+	case <-aliceChannel.ForceCloseSignal:
+		t.Fatalf("force close signal sent")
+	default:
+	}
+}
+
+// TestForceCloseIncomingHtlc checks that force closing a channel with an
+// incoming HTLC yields a success transaction which, once the preimage has been
+// swapped in, validly spends the HTLC output of the commitment transaction.
+func TestForceCloseIncomingHtlc(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, bobChannel, cleanUp, err := createTestChannels(3)
+	if err != nil {
+		t.Fatalf("unable to create test channels: %v", err)
+	}
+	defer cleanUp()
+
+	// Add a single non-dust HTLC from Alice to Bob, and lock it into both
+	// commitment transactions.
+	htlcAmount := lnwire.NewMSatFromSatoshis(btcutil.SatoshiPerBitcoin)
+	htlc, preimage := createHTLC(0, htlcAmount)
+	if _, err := aliceChannel.AddHTLC(htlc); err != nil {
+		t.Fatalf("alice unable to add htlc: %v", err)
+	}
+	if _, err := bobChannel.ReceiveHTLC(htlc); err != nil {
+		t.Fatalf("bob unable to receive htlc: %v", err)
+	}
+	if err := forceStateTransition(aliceChannel, bobChannel); err != nil {
+		t.Fatalf("Can't update the channel state: %v", err)
+	}
+
+	closeSummary, err := bobChannel.ForceClose()
+	if err != nil {
+		t.Fatalf("unable to force close channel: %v", err)
+	}
+
+	// Bob's only HTLC is incoming, so there should be a single incoming
+	// resolution, spending the HTLC output of Bob's commitment.
+	if len(closeSummary.IncomingHtlcResolutions) != 1 {
+		t.Fatalf("expected 1 incoming htlc resolution, got %v",
+			len(closeSummary.IncomingHtlcResolutions))
+	}
+	res := closeSummary.IncomingHtlcResolutions[0]
+	if res.CsvDelay != uint32(bobChannel.localChanCfg.CsvDelay) {
+		t.Fatalf("expected csv delay %v, got %v",
+			bobChannel.localChanCfg.CsvDelay, res.CsvDelay)
+	}
+
+	successTx := res.SignedSuccessTx.Copy()
+	htlcOutpoint := successTx.TxIn[0].PreviousOutPoint
+	if htlcOutpoint.Hash != closeSummary.CloseTx.TxHash() {
+		t.Fatalf("success txn doesn't spend commitment txn")
+	}
+	htlcOutput := closeSummary.CloseTx.TxOut[htlcOutpoint.Index]
+	if htlcOutput.Value != int64(htlcAmount.ToSatoshis()) {
+		t.Fatalf("success txn spends output of value %v, expected %v",
+			htlcOutput.Value, htlcAmount.ToSatoshis())
+	}
+
+	// With the preimage swapped in, the success transaction should
+	// validly spend the HTLC output.
+	successTx.TxIn[0].Witness[SuccessPreimageIndex] = preimage[:]
+	vm, err := txscript.NewEngine(htlcOutput.PkScript,
+		successTx, 0, txscript.StandardVerifyFlags, nil,
+		nil, htlcOutput.Value)
+	if err != nil {
+		t.Fatalf("unable to create engine: %v", err)
+	}
+	if err := vm.Execute(); err != nil {
+		t.Fatalf("htlc success spend is invalid: %v", err)
+	}
+
+	// The sweep sign descriptor should cover the output of the success
+	// transaction.
+	if res.SweepSignDesc.Output.Value != successTx.TxOut[0].Value {
+		t.Fatalf("expected sweep value %v, got %v",
+			successTx.TxOut[0].Value, res.SweepSignDesc.Output.Value)
+	}
+}
+
+// TestChanSyncDataLoss checks that the ChannelReestablish message always
+// carries the data loss protection fields, and that a remote party proving
+// that we've lost state prevents us from force closing the channel.
+func TestChanSyncDataLoss(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, bobChannel, cleanUp, err := createTestChannels(3)
+	if err != nil {
+		t.Fatalf("unable to create test channels: %v", err)
+	}
+	defer cleanUp()
+
+	// Before any state transitions, Bob hasn't received any revocations
+	// from Alice, so he should send the zero secret along.
+	bobSyncMsg, err := bobChannel.ChanSyncMsg()
+	if err != nil {
+		t.Fatalf("unable to create chan sync msg: %v", err)
+	}
+	if !bobSyncMsg.HasDataLossProtection() {
+		t.Fatalf("data loss protection fields not populated")
+	}
+	if bobSyncMsg.LastRemoteCommitSecret != [32]byte{} {
+		t.Fatalf("expected zero commit secret")
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := forceStateTransition(aliceChannel, bobChannel); err != nil {
+			t.Fatalf("unable to complete state update: %v", err)
+		}
+	}
+
+	// Now that Alice has revoked two states, Bob should send along the
+	// secret of the last one, and Alice shouldn't detect any data loss.
+	bobSyncMsg, err = bobChannel.ChanSyncMsg()
+	if err != nil {
+		t.Fatalf("unable to create chan sync msg: %v", err)
+	}
+	lastSecret, err := aliceChannel.channelState.RevocationProducer.AtIndex(
+		bobSyncMsg.RemoteCommitTailHeight - 1,
+	)
+	if err != nil {
+		t.Fatalf("unable to derive secret: %v", err)
+	}
+	if !bytes.Equal(lastSecret[:], bobSyncMsg.LastRemoteCommitSecret[:]) {
+		t.Fatalf("incorrect last commit secret")
+	}
+	if err := aliceChannel.CheckDataLoss(bobSyncMsg); err != nil {
+		t.Fatalf("unexpected data loss: %v", err)
+	}
+
+	// If Bob claims to know of a newer state than Alice without being able
+	// to prove it, then the claim should be rejected.
+	bobSyncMsg.RemoteCommitTailHeight++
+	err = aliceChannel.CheckDataLoss(bobSyncMsg)
+	if err != ErrInvalidLastCommitSecret {
+		t.Fatalf("expected ErrInvalidLastCommitSecret, got %v", err)
+	}
+
+	// Once the secret for Alice's current state is sent along, Alice
+	// should detect that she has lost state.
+	currentSecret, err := aliceChannel.channelState.RevocationProducer.AtIndex(
+		bobSyncMsg.RemoteCommitTailHeight - 1,
+	)
+	if err != nil {
+		t.Fatalf("unable to derive secret: %v", err)
+	}
+	copy(bobSyncMsg.LastRemoteCommitSecret[:], currentSecret[:])
+	err = aliceChannel.CheckDataLoss(bobSyncMsg)
+	if err != ErrCommitSyncLocalDataLoss {
+		t.Fatalf("expected ErrCommitSyncLocalDataLoss, got %v", err)
+	}
+
+	// With the channel marked as having lost state, Alice must refuse to
+	// broadcast her commitment.
+	if err := aliceChannel.MarkDataLoss(); err != nil {
+		t.Fatalf("unable to mark data loss: %v", err)
+	}
+	if _, err := aliceChannel.ForceClose(); err != ErrForceCloseLocalDataLoss {
+		t.Fatalf("expected ErrForceCloseLocalDataLoss, got %v", err)
+	}
+}
+
 // TestDustHTLCFees checks that fees are calculated correctly when HTLCs fall
 // below the nodes' dust limit. In these cases, the amount of the dust HTLCs
 // should be applied to the commitment transaction fee.
@@ -1149,6 +1388,83 @@ func TestHTLCDustLimit(t *testing.T) {
 	}
 }
 
+// TestDustExposure asserts that the dust exposure of a channel accounts for
+// the HTLCs which are dust on each commitment, until they're removed.
+func TestDustExposure(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, bobChannel, cleanUp, err := createTestChannels(3)
+	if err != nil {
+		t.Fatalf("unable to create test channels: %v", err)
+	}
+	defer cleanUp()
+
+	// As in TestHTLCDustLimit, the HTLC should be above Alice's dust limit
+	// and below Bob's, so it's only dust on Bob's commitment.
+	htlcSat := (btcutil.Amount(500) +
+		htlcTimeoutFee(aliceChannel.channelState.FeePerKw))
+	htlcAmount := lnwire.NewMSatFromSatoshis(htlcSat)
+
+	localDust, remoteDust := aliceChannel.HtlcIsDust(htlcAmount, false)
+	if localDust || !remoteDust {
+		t.Fatalf("htlc should only be dust on the remote commitment, "+
+			"got local=%v remote=%v", localDust, remoteDust)
+	}
+
+	assertExposure := func(channel *LightningChannel, expLocal,
+		expRemote lnwire.MilliAtom) {
+
+		local, remote := channel.DustExposure()
+		if local != expLocal || remote != expRemote {
+			t.Fatalf("expected dust exposure local=%v remote=%v, "+
+				"got local=%v remote=%v", expLocal, expRemote,
+				local, remote)
+		}
+	}
+
+	// The HTLC should be counted as soon as it's added, before it has been
+	// locked in.
+	htlc, preimage := createHTLC(0, htlcAmount)
+	if _, err := aliceChannel.AddHTLC(htlc); err != nil {
+		t.Fatalf("alice unable to add htlc: %v", err)
+	}
+	if _, err := bobChannel.ReceiveHTLC(htlc); err != nil {
+		t.Fatalf("bob unable to receive htlc: %v", err)
+	}
+	assertExposure(aliceChannel, 0, htlcAmount)
+	assertExposure(bobChannel, htlcAmount, 0)
+
+	localDust, remoteDust = bobChannel.HtlcIsDust(htlcAmount, true)
+	if !localDust || remoteDust {
+		t.Fatalf("htlc should only be dust on the local commitment, "+
+			"got local=%v remote=%v", localDust, remoteDust)
+	}
+
+	if err := forceStateTransition(aliceChannel, bobChannel); err != nil {
+		t.Fatalf("Can't update the channel state: %v", err)
+	}
+	assertExposure(aliceChannel, 0, htlcAmount)
+	assertExposure(bobChannel, htlcAmount, 0)
+
+	// Once settled, the HTLC should no longer be counted.
+	settleIndex, err := bobChannel.SettleHTLC(preimage)
+	if err != nil {
+		t.Fatalf("bob unable to settle inbound htlc: %v", err)
+	}
+	err = aliceChannel.ReceiveHTLCSettle(preimage, settleIndex)
+	if err != nil {
+		t.Fatalf("alice unable to accept settle of outbound htlc: %v", err)
+	}
+	assertExposure(aliceChannel, 0, 0)
+	assertExposure(bobChannel, 0, 0)
+
+	if err := forceStateTransition(bobChannel, aliceChannel); err != nil {
+		t.Fatalf("state transition error: %v", err)
+	}
+	assertExposure(aliceChannel, 0, 0)
+	assertExposure(bobChannel, 0, 0)
+}
+
 // TestChannelBalanceDustLimit tests the condition when the remaining balance
 // for one of the channel participants is so small as to be considered dust. In
 // this case, the output for that participant is removed and all funds (minus
@@ -1617,7 +1933,7 @@ func TestCooperativeCloseDustAdherence(t *testing.T) {
 		bobChannel.status = channelOpen
 	}
 
-	setBalances := func(aliceBalance, bobBalance lnwire.MilliSatoshi) {
+	setBalances := func(aliceBalance, bobBalance lnwire.MilliAtom) {
 		aliceChannel.channelState.LocalBalance = aliceBalance
 		aliceChannel.channelState.RemoteBalance = bobBalance
 		bobChannel.channelState.LocalBalance = bobBalance
@@ -1,12 +1,66 @@
 package lnwallet
 
 import (
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/roasbeef/btcutil"
 	"github.com/roasbeef/btcwallet/wallet/txrules"
 )
 
+// maxDustLimitMultiplier is the multiple of the minimum dust limit which
+// bounds the largest dust limit we'll accept. HTLCs below a commitment's dust
+// limit are trimmed to fees rather than being materialized as outputs, so
+// accepting an arbitrarily large dust limit from the remote party would allow
+// them to render sizable HTLCs unenforceable on-chain.
+const maxDustLimitMultiplier = 20
+
 // DefaultDustLimit is used to calculate the dust HTLC amount which will be
 // send to other node during funding process.
 func DefaultDustLimit() btcutil.Amount {
 	return txrules.GetDustThreshold(P2WSHSize, txrules.DefaultRelayFeePerKb)
 }
+
+// MinDustLimit returns the smallest dust limit that may be used for either
+// commitment transaction. Any lower, and outputs on the commitment
+// transaction may be rejected as non-standard under the network's default
+// relay policy.
+func MinDustLimit() btcutil.Amount {
+	return DefaultDustLimit()
+}
+
+// MaxDustLimit returns the largest dust limit that may be used for either
+// commitment transaction.
+func MaxDustLimit() btcutil.Amount {
+	return MinDustLimit() * maxDustLimitMultiplier
+}
+
+// ValidateDustLimit returns an error if the passed dust limit falls outside of
+// the bounds defined by MinDustLimit and MaxDustLimit.
+func ValidateDustLimit(dustLimit btcutil.Amount) error {
+	switch {
+	case dustLimit < MinDustLimit():
+		return fmt.Errorf("dust limit of %v is below the minimum of %v",
+			dustLimit, MinDustLimit())
+
+	case dustLimit > MaxDustLimit():
+		return fmt.Errorf("dust limit of %v is above the maximum of %v",
+			dustLimit, MaxDustLimit())
+	}
+
+	return nil
+}
+
+// ValidateUpfrontShutdownScript returns an error if the passed upfront
+// shutdown script doesn't match one of the standard script templates that a
+// cooperative close may pay out to: pay-to-pubkey-hash, pay-to-script-hash,
+// or their witness program equivalents. Any other script could render the
+// closing transaction non-standard, preventing it from being relayed.
+func ValidateUpfrontShutdownScript(script []byte) error {
+	if !lnwire.DeliveryAddress(script).IsStandard() {
+		return fmt.Errorf("upfront shutdown script %x is not a "+
+			"standard script", script)
+	}
+
+	return nil
+}
@@ -118,7 +118,7 @@ type ChannelReservation struct {
 	// pushMSat the amount of milli-satoshis that should be pushed to the
 	// responder of a single funding channel as part of the initial
 	// commitment state.
-	pushMSat lnwire.MilliSatoshi
+	pushMSat lnwire.MilliAtom
 
 	// chanOpen houses a struct containing the channel and additional
 	// confirmation details will be sent on once the channel is considered
@@ -135,12 +135,12 @@ type ChannelReservation struct {
 // creation of all channel reservations should be carried out via the
 // lnwallet.InitChannelReservation interface.
 func NewChannelReservation(capacity, fundingAmt, feePerKw btcutil.Amount,
-	wallet *LightningWallet, id uint64, pushMSat lnwire.MilliSatoshi,
+	wallet *LightningWallet, id uint64, pushMSat lnwire.MilliAtom,
 	chainHash *chainhash.Hash) *ChannelReservation {
 
 	var (
-		ourBalance   lnwire.MilliSatoshi
-		theirBalance lnwire.MilliSatoshi
+		ourBalance   lnwire.MilliAtom
+		theirBalance lnwire.MilliAtom
 		initiator    bool
 	)
 
@@ -236,6 +236,26 @@ func (r *ChannelReservation) SetNumConfsRequired(numConfs uint16) {
 	r.partialState.NumConfsRequired = numConfs
 }
 
+// SetOurUpfrontShutdown sets the script which we commit to pay out to when
+// the channel is cooperatively closed. An empty script indicates that we
+// don't commit to any script.
+func (r *ChannelReservation) SetOurUpfrontShutdown(script []byte) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.partialState.UpfrontShutdownScript = script
+}
+
+// SetRemoteUpfrontShutdown sets the script which the remote party has
+// committed to pay out to when the channel is cooperatively closed. An empty
+// script indicates that they haven't committed to any script.
+func (r *ChannelReservation) SetRemoteUpfrontShutdown(script []byte) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.partialState.RemoteUpfrontShutdownScript = script
+}
+
 // RequireLocalDelay sets the mandatory CSV delay that MUST be used when
 // creating the local commitment transaction. This is distinct from the normal
 // reservation workflow as the remote party will dictate this value for us.
@@ -248,6 +268,16 @@ func (r *ChannelReservation) RequireLocalDelay(csvDelay uint16) {
 	r.ourContribution.ChannelConfig.CsvDelay = csvDelay
 }
 
+// SetOurDustLimit overrides the default dust limit that we'll require for our
+// version of the commitment transaction. This must be called before our
+// contribution is sent to the remote party.
+func (r *ChannelReservation) SetOurDustLimit(dustLimit btcutil.Amount) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.ourContribution.ChannelConfig.DustLimit = dustLimit
+}
+
 // OurContribution returns the wallet's fully populated contribution to the
 // pending payment channel. See 'ChannelContribution' for further details
 // regarding the contents of a contribution.
@@ -108,6 +108,69 @@ func GenFundingPkScript(aPub, bPub []byte, amt int64) ([]byte, *wire.TxOut, erro
 	return witnessScript, wire.NewTxOut(amt, pkScript), nil
 }
 
+// FundingScriptVersion is the script version of the funding outputs created
+// by GenFundingPkScript. As output scripts aren't versioned on their own, the
+// witness version of the output's witness program acts as its script version.
+const FundingScriptVersion = 0
+
+// ErrUnsupportedScriptVersion is returned when verifying a funding output
+// whose script version we don't know how to validate.
+var ErrUnsupportedScriptVersion = fmt.Errorf("unsupported funding script " +
+	"version")
+
+// ScriptVersion returns the version of the passed output script, which is
+// the witness version of its witness program. If the script isn't a witness
+// program, then false is returned.
+func ScriptVersion(pkScript []byte) (int, bool) {
+	// A witness program consists of a version opcode followed by a single
+	// data push of 2 to 40 bytes.
+	if len(pkScript) < 4 || len(pkScript) > 42 {
+		return 0, false
+	}
+	if int(pkScript[1])+2 != len(pkScript) {
+		return 0, false
+	}
+
+	switch version := pkScript[0]; {
+	case version == txscript.OP_0:
+		return 0, true
+	case version >= txscript.OP_1 && version <= txscript.OP_16:
+		return int(version-txscript.OP_1) + 1, true
+	default:
+		return 0, false
+	}
+}
+
+// VerifyFundingOutput checks that the passed output is a funding output
+// paying to the 2-of-2 multi-sig of the passed keys. The version of the
+// output's script is checked before its contents, as the funding script can
+// only be recreated, and thus compared against, for versions we know of.
+// ErrUnsupportedScriptVersion is returned for the outputs of any other
+// version.
+func VerifyFundingOutput(aPub, bPub []byte, txOut *wire.TxOut) error {
+	version, ok := ScriptVersion(txOut.PkScript)
+	if !ok {
+		return fmt.Errorf("funding output script %x isn't a witness "+
+			"program", txOut.PkScript)
+	}
+	if version != FundingScriptVersion {
+		return ErrUnsupportedScriptVersion
+	}
+
+	// Recreate the funding output to ensure that the keys and value
+	// claimed for it correspond to reality.
+	_, fundingOutput, err := GenFundingPkScript(aPub, bPub, txOut.Value)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(fundingOutput.PkScript, txOut.PkScript) {
+		return fmt.Errorf("pkScript mismatch: expected %x, got %x",
+			fundingOutput.PkScript, txOut.PkScript)
+	}
+
+	return nil
+}
+
 // SpendMultiSig generates the witness stack required to redeem the 2-of-2 p2wsh
 // multi-sig output.
 func SpendMultiSig(witnessScript, pubA, sigA, pubB, sigB []byte) [][]byte {
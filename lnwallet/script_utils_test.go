@@ -1094,3 +1094,58 @@ func TestCommitTxStateHint(t *testing.T) {
 		t.Logf("Passed: %v", test.name)
 	}
 }
+
+// TestVerifyFundingOutput checks that funding outputs are only validated
+// against the recreated funding script if their script version is known.
+func TestVerifyFundingOutput(t *testing.T) {
+	t.Parallel()
+
+	_, alicePub := btcec.PrivKeyFromBytes(btcec.S256(), testWalletPrivKey)
+	_, bobPub := btcec.PrivKeyFromBytes(btcec.S256(), bobsPrivKey)
+	aliceKey := alicePub.SerializeCompressed()
+	bobKey := bobPub.SerializeCompressed()
+
+	_, fundingOutput, err := GenFundingPkScript(aliceKey, bobKey, 100000)
+	if err != nil {
+		t.Fatalf("unable to create funding output: %v", err)
+	}
+
+	version, ok := ScriptVersion(fundingOutput.PkScript)
+	if !ok || version != FundingScriptVersion {
+		t.Fatalf("expected funding script version %v, got %v",
+			FundingScriptVersion, version)
+	}
+	if err := VerifyFundingOutput(aliceKey, bobKey, fundingOutput); err != nil {
+		t.Fatalf("unable to verify funding output: %v", err)
+	}
+
+	// An output of a newer script version carrying the same program
+	// shouldn't be validated as a funding output.
+	newerScript := append([]byte{txscript.OP_1}, fundingOutput.PkScript[1:]...)
+	newerVersion, ok := ScriptVersion(newerScript)
+	if !ok || newerVersion != 1 {
+		t.Fatalf("expected script version 1, got %v", newerVersion)
+	}
+	err = VerifyFundingOutput(aliceKey, bobKey, wire.NewTxOut(
+		fundingOutput.Value, newerScript,
+	))
+	if err != ErrUnsupportedScriptVersion {
+		t.Fatalf("expected ErrUnsupportedScriptVersion, got %v", err)
+	}
+
+	// Nor should an output with an incorrect value or keys.
+	err = VerifyFundingOutput(aliceKey, bobKey, wire.NewTxOut(
+		fundingOutput.Value+1, fundingOutput.PkScript,
+	))
+	if err == nil {
+		t.Fatalf("expected incorrect value to be rejected")
+	}
+	if err := VerifyFundingOutput(bobKey, bobKey, fundingOutput); err == nil {
+		t.Fatalf("expected incorrect keys to be rejected")
+	}
+
+	// Scripts that aren't witness programs have no version.
+	if _, ok := ScriptVersion([]byte{txscript.OP_TRUE}); ok {
+		t.Fatalf("expected non-witness script to have no version")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"sync"
 	"sync/atomic"
 
+	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/roasbeef/btcd/btcec"
 	"github.com/roasbeef/btcd/wire"
 )
@@ -34,7 +35,7 @@ type verifyJob struct {
 
 	// sig is the raw signature generated using the above public key.  This
 	// is the signature to be verified.
-	sig *btcec.Signature
+	sig *lnwire.Sig
 
 	// sigHash is a function closure generates the sighashes that the
 	// passed signature is known to have signed.
@@ -113,7 +114,7 @@ type signJobResp struct {
 	// sig is the generated signature for a particular signJob In the case
 	// of an error during signature generation, then this value sent will
 	// be nil.
-	sig *btcec.Signature
+	sig *lnwire.Sig
 
 	// err is the error that occurred when executing the specified
 	// signature job. In the case that no error occurred, this value will
@@ -217,7 +218,9 @@ func (s *sigPool) poolWorker() {
 				}
 			}
 
-			sig, err := btcec.ParseSignature(rawSig, btcec.S256())
+			// The signature is converted directly to its wire
+			// format, as we never need its parsed form.
+			sig, err := lnwire.NewSigFromRawSignature(rawSig)
 			select {
 			case sigMsg.resp <- signJobResp{
 				sig: sig,
@@ -244,8 +247,9 @@ func (s *sigPool) poolWorker() {
 			rawSig := verifyMsg.sig
 
 			if !rawSig.Verify(sigHash, verifyMsg.pubKey) {
+				wireSig := rawSig.RawBytes()
 				err := fmt.Errorf("invalid signature "+
-					"sighash: %x, sig: %x", sigHash, rawSig.Serialize())
+					"sighash: %x, sig: %x", sigHash, wireSig[:])
 				select {
 				case verifyMsg.errResp <- err:
 				case <-verifyMsg.cancel:
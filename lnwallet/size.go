@@ -114,6 +114,152 @@ const (
 	// which will transition an incoming HTLC to the delay-and-claim state.
 	HtlcSuccessWeight = 703
 
+	// InputSize 41 bytes
+	//	- PreviousOutPoint:
+	//		- Hash: 32 bytes
+	//		- Index: 4 bytes
+	//	- OP_DATA: 1 byte (ScriptSigLength)
+	//	- ScriptSig: 0 bytes
+	//	- Sequence: 4 bytes
+	InputSize = 32 + 4 + 1 + 4
+
+	// ToLocalScriptSize 79 bytes
+	//	- OP_IF: 1 byte
+	//	- OP_DATA: 1 byte (revocationkey length)
+	//	- revocationkey: 33 bytes
+	//	- OP_ELSE: 1 byte
+	//	- OP_DATA: 1 byte (localkey length)
+	//	- local_delay: 4 bytes
+	//	- OP_CHECKSEQUENCEVERIFY: 1 byte
+	//	- OP_DROP: 1 byte
+	//	- OP_DATA: 1 byte (delayed_pubkey length)
+	//	- delayed_pubkey: 33 bytes
+	//	- OP_ENDIF: 1 byte
+	//	- OP_CHECKSIG: 1 byte
+	ToLocalScriptSize = 1 + 1 + 33 + 1 + 1 + 4 + 1 + 1 + 1 + 33 + 1 + 1
+
+	// ToLocalTimeoutWitnessSize 156 bytes
+	//	- NumberOfWitnessElements: 1 byte
+	//	- local_delay_sig_length: 1 byte
+	//	- local_delay_sig: 73 bytes
+	//	- zero_length: 1 byte
+	//	- witness_script_length: 1 byte
+	//	- witness_script (to_local_script)
+	ToLocalTimeoutWitnessSize = 1 + 1 + 73 + 1 + 1 + ToLocalScriptSize
+
+	// ToLocalPenaltyWitnessSize 157 bytes
+	//	- NumberOfWitnessElements: 1 byte
+	//	- revocation_sig_length: 1 byte
+	//	- revocation_sig: 73 bytes
+	//	- one_length: 1 byte
+	//	- one: 1 byte
+	//	- witness_script_length: 1 byte
+	//	- witness_script (to_local_script)
+	ToLocalPenaltyWitnessSize = 1 + 1 + 73 + 1 + 1 + 1 + ToLocalScriptSize
+
+	// P2WKHWitnessSize 109 bytes
+	//	- NumberOfWitnessElements: 1 byte
+	//	- SignatureLength: 1 byte
+	//	- Signature: 73 bytes
+	//	- PubKeyLength: 1 byte
+	//	- PubKey: 33 bytes
+	P2WKHWitnessSize = 1 + 1 + 73 + 1 + 33
+
+	// OfferedHtlcScriptSize 132 bytes
+	//	- OP_DUP: 1 byte
+	//	- OP_HASH160: 1 byte
+	//	- OP_DATA: 1 byte (RIPEMD160(SHA256(revocationkey)) length)
+	//	- RIPEMD160(SHA256(revocationkey)): 20 bytes
+	//	- OP_EQUAL: 1 byte
+	//	- OP_IF: 1 byte
+	//	- OP_CHECKSIG: 1 byte
+	//	- OP_ELSE: 1 byte
+	//	- OP_DATA: 1 byte (remotekey length)
+	//	- remotekey: 33 bytes
+	//	- OP_SWAP: 1 byte
+	//	- OP_SIZE: 1 byte
+	//	- OP_DATA: 1 byte (32 length)
+	//	- 32: 1 byte
+	//	- OP_EQUAL: 1 byte
+	//	- OP_NOTIF: 1 byte
+	//	- OP_DROP: 1 byte
+	//	- OP_2: 1 byte
+	//	- OP_SWAP: 1 byte
+	//	- OP_DATA: 1 byte (localkey length)
+	//	- localkey: 33 bytes
+	//	- OP_2: 1 byte
+	//	- OP_CHECKMULTISIG: 1 byte
+	//	- OP_ELSE: 1 byte
+	//	- OP_HASH160: 1 byte
+	//	- OP_DATA: 1 byte (RIPEMD160(payment_hash) length)
+	//	- RIPEMD160(payment_hash): 20 bytes
+	//	- OP_EQUALVERIFY: 1 byte
+	//	- OP_ENDIF: 1 byte
+	//	- OP_ENDIF: 1 byte
+	OfferedHtlcScriptSize = 1 + 1 + 1 + 20 + 1 + 1 + 1 + 1 + 1 + 33 + 1 + 1 +
+		1 + 1 + 1 + 1 + 1 + 1 + 1 + 1 + 33 + 1 + 1 + 1 + 1 + 1 + 20 + 1 +
+		1 + 1
+
+	// AcceptedHtlcScriptSize 140 bytes
+	//	- OP_DUP: 1 byte
+	//	- OP_HASH160: 1 byte
+	//	- OP_DATA: 1 byte (RIPEMD160(SHA256(revocationkey)) length)
+	//	- RIPEMD160(SHA256(revocationkey)): 20 bytes
+	//	- OP_EQUAL: 1 byte
+	//	- OP_IF: 1 byte
+	//	- OP_CHECKSIG: 1 byte
+	//	- OP_ELSE: 1 byte
+	//	- OP_DATA: 1 byte (remotekey length)
+	//	- remotekey: 33 bytes
+	//	- OP_SWAP: 1 byte
+	//	- OP_SIZE: 1 byte
+	//	- OP_DATA: 1 byte (32 length)
+	//	- 32: 1 byte
+	//	- OP_EQUAL: 1 byte
+	//	- OP_IF: 1 byte
+	//	- OP_HASH160: 1 byte
+	//	- OP_DATA: 1 byte (RIPEMD160(payment_hash) length)
+	//	- RIPEMD160(payment_hash): 20 bytes
+	//	- OP_EQUALVERIFY: 1 byte
+	//	- OP_2: 1 byte
+	//	- OP_SWAP: 1 byte
+	//	- OP_DATA: 1 byte (localkey length)
+	//	- localkey: 33 bytes
+	//	- OP_2: 1 byte
+	//	- OP_CHECKMULTISIG: 1 byte
+	//	- OP_ELSE: 1 byte
+	//	- OP_DROP: 1 byte
+	//	- OP_DATA: 1 byte (cltv_expiry length)
+	//	- cltv_expiry: 4 bytes
+	//	- OP_CHECKLOCKTIMEVERIFY: 1 byte
+	//	- OP_DROP: 1 byte
+	//	- OP_CHECKSIG: 1 byte
+	//	- OP_ENDIF: 1 byte
+	//	- OP_ENDIF: 1 byte
+	AcceptedHtlcScriptSize = 1 + 1 + 1 + 20 + 1 + 1 + 1 + 1 + 1 + 33 + 1 + 1 +
+		1 + 1 + 1 + 1 + 1 + 1 + 20 + 1 + 1 + 1 + 1 + 33 + 1 + 1 + 1 + 1 +
+		1 + 4 + 1 + 1 + 1 + 1 + 1
+
+	// OfferedHtlcPenaltyWitnessSize 242 bytes
+	//	- NumberOfWitnessElements: 1 byte
+	//	- revocation_sig_length: 1 byte
+	//	- revocation_sig: 73 bytes
+	//	- revocation_key_length: 1 byte
+	//	- revocation_key: 33 bytes
+	//	- witness_script_length: 1 byte
+	//	- witness_script (offered_htlc_script)
+	OfferedHtlcPenaltyWitnessSize = 1 + 1 + 73 + 1 + 33 + 1 + OfferedHtlcScriptSize
+
+	// AcceptedHtlcPenaltyWitnessSize 250 bytes
+	//	- NumberOfWitnessElements: 1 byte
+	//	- revocation_sig_length: 1 byte
+	//	- revocation_sig: 73 bytes
+	//	- revocation_key_length: 1 byte
+	//	- revocation_key: 33 bytes
+	//	- witness_script_length: 1 byte
+	//	- witness_script (accepted_htlc_script)
+	AcceptedHtlcPenaltyWitnessSize = 1 + 1 + 73 + 1 + 33 + 1 + AcceptedHtlcScriptSize
+
 	// MaxHTLCNumber is the maximum number HTLCs which can be included in a
 	// commitment transaction. This limit was chosen such that, in the case
 	// of a contract breach, the punishment transaction is able to sweep
@@ -105,7 +105,7 @@ type initFundingReserveMsg struct {
 
 	// pushMSat is the number of milli-satoshis that should be pushed over
 	// the responder as part of the initial channel creation.
-	pushMSat lnwire.MilliSatoshi
+	pushMSat lnwire.MilliAtom
 
 	// err is a channel in which all errors will be sent across. Will be
 	// nil if this initial set is successful.
@@ -445,7 +445,7 @@ out:
 // transaction, and that the signature we records for our version of the
 // commitment transaction is valid.
 func (l *LightningWallet) InitChannelReservation(
-	capacity, ourFundAmt btcutil.Amount, pushMSat lnwire.MilliSatoshi,
+	capacity, ourFundAmt btcutil.Amount, pushMSat lnwire.MilliAtom,
 	feePerKw btcutil.Amount,
 	theirID *btcec.PublicKey, theirAddr *net.TCPAddr,
 	chainHash *chainhash.Hash) (*ChannelReservation, error) {
@@ -510,13 +510,18 @@ func (l *LightningWallet) handleFundingReserveRequest(req *initFundingReserveMsg
 		//
 		// TODO(roasbeef): shouldn't be targeting next block
 		satPerByte := l.Cfg.FeeEstimator.EstimateFeePerByte(1)
-		err := l.selectCoinsAndChange(satPerByte, req.fundingAmount,
-			reservation.ourContribution)
+		fundingFee, err := l.selectCoinsAndChange(
+			satPerByte, req.fundingAmount, reservation.ourContribution,
+		)
 		if err != nil {
 			req.err <- err
 			req.resp <- nil
 			return
 		}
+
+		// As the initiator, we pay the entire fee of the funding
+		// transaction, so we'll record it within the channel state.
+		reservation.partialState.FundingFee = fundingFee
 	}
 
 	// Next, we'll grab a series of keys from the wallet which will be used
@@ -846,7 +851,7 @@ func (l *LightningWallet) handleContributionMsg(req *addContributionMsg) {
 	// both commitment transactions.
 	var stateObsfucator [StateHintSize]byte
 	if chanState.ChanType == channeldb.SingleFunder {
-		stateObsfucator = deriveStateHintObfuscator(
+		stateObsfucator = DeriveStateHintObfuscator(
 			ourContribution.PaymentBasePoint,
 			theirContribution.PaymentBasePoint,
 		)
@@ -855,12 +860,12 @@ func (l *LightningWallet) handleContributionMsg(req *addContributionMsg) {
 		theirSer := theirContribution.PaymentBasePoint.SerializeCompressed()
 		switch bytes.Compare(ourSer, theirSer) {
 		case -1:
-			stateObsfucator = deriveStateHintObfuscator(
+			stateObsfucator = DeriveStateHintObfuscator(
 				ourContribution.PaymentBasePoint,
 				theirContribution.PaymentBasePoint,
 			)
 		default:
-			stateObsfucator = deriveStateHintObfuscator(
+			stateObsfucator = DeriveStateHintObfuscator(
 				theirContribution.PaymentBasePoint,
 				ourContribution.PaymentBasePoint,
 			)
@@ -1157,7 +1162,7 @@ func (l *LightningWallet) handleSingleFunderSigs(req *addSingleFunderSigsMsg) {
 	// With both commitment transactions constructed, we can now use the
 	// generator state obfuscator to encode the current state number within
 	// both commitment transactions.
-	stateObsfucator := deriveStateHintObfuscator(
+	stateObsfucator := DeriveStateHintObfuscator(
 		pendingReservation.theirContribution.PaymentBasePoint,
 		pendingReservation.ourContribution.PaymentBasePoint)
 	err = initStateHints(ourCommitTx, theirCommitTx, stateObsfucator)
@@ -1266,10 +1271,10 @@ func (l *LightningWallet) handleSingleFunderSigs(req *addSingleFunderSigsMsg) {
 // outputs which sum to at least 'numCoins' amount of satoshis. If coin
 // selection is successful/possible, then the selected coins are available
 // within the passed contribution's inputs. If necessary, a change address will
-// also be generated.
+// also be generated. The fee paid by the resulting transaction is returned.
 // TODO(roasbeef): remove hardcoded fees and req'd confs for outputs.
 func (l *LightningWallet) selectCoinsAndChange(feeRate uint64, amt btcutil.Amount,
-	contribution *ChannelContribution) error {
+	contribution *ChannelContribution) (btcutil.Amount, error) {
 
 	// We hold the coin select mutex while querying for outputs, and
 	// performing coin selection in order to avoid inadvertent double
@@ -1285,15 +1290,15 @@ func (l *LightningWallet) selectCoinsAndChange(feeRate uint64, amt btcutil.Amoun
 	// TODO(roasbeef): make num confs a configuration parameter
 	coins, err := l.ListUnspentWitness(1)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	// Perform coin selection over our available, unlocked unspent outputs
 	// in order to find enough coins to meet the funding amount
 	// requirements.
-	selectedCoins, changeAmt, err := coinSelect(feeRate, amt, coins)
+	selectedCoins, changeAmt, fee, err := coinSelect(feeRate, amt, coins)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	// Lock the selected coins. These coins are now "reserved", this
@@ -1314,11 +1319,11 @@ func (l *LightningWallet) selectCoinsAndChange(feeRate uint64, amt btcutil.Amoun
 	if changeAmt != 0 {
 		changeAddr, err := l.NewAddress(WitnessPubKey, true)
 		if err != nil {
-			return err
+			return 0, err
 		}
 		changeScript, err := txscript.PayToAddrScript(changeAddr)
 		if err != nil {
-			return err
+			return 0, err
 		}
 
 		contribution.ChangeOutputs = make([]*wire.TxOut, 1)
@@ -1328,7 +1333,7 @@ func (l *LightningWallet) selectCoinsAndChange(feeRate uint64, amt btcutil.Amoun
 		}
 	}
 
-	return nil
+	return fee, nil
 }
 
 // deriveMasterRevocationRoot derives the private key which serves as the master
@@ -1343,7 +1348,7 @@ func (l *LightningWallet) deriveMasterRevocationRoot() (*btcec.PrivateKey, error
 	return masterElkremRoot.ECPrivKey()
 }
 
-// deriveStateHintObfuscator derives the bytes to be used for obfuscating the
+// DeriveStateHintObfuscator derives the bytes to be used for obfuscating the
 // state hints from the root to be used for a new channel. The obsfucsator is
 // generated via the following computation:
 //
@@ -1351,7 +1356,7 @@ func (l *LightningWallet) deriveMasterRevocationRoot() (*btcec.PrivateKey, error
 //     * where both keys are the multi-sig keys of the respective parties
 //
 // The first 6 bytes of the resulting hash are used as the state hint.
-func deriveStateHintObfuscator(key1, key2 *btcec.PublicKey) [StateHintSize]byte {
+func DeriveStateHintObfuscator(key1, key2 *btcec.PublicKey) [StateHintSize]byte {
 	h := sha256.New()
 	h.Write(key1.SerializeCompressed())
 	h.Write(key2.SerializeCompressed())
@@ -1418,9 +1423,11 @@ func selectInputs(amt btcutil.Amount, coins []*Utxo) (btcutil.Amount, []*wire.Ou
 // coinSelect attempts to select a sufficient amount of coins, including a
 // change output to fund amt satoshis, adhering to the specified fee rate. The
 // specified fee rate should be expressed in sat/byte for coin selection to
-// function properly.
+// function properly. The selected coins are returned along with the change
+// amount and the fee paid, which is whatever remains of the selected amount
+// once the funding amount and change are deducted.
 func coinSelect(feeRate uint64, amt btcutil.Amount,
-	coins []*Utxo) ([]*wire.OutPoint, btcutil.Amount, error) {
+	coins []*Utxo) ([]*wire.OutPoint, btcutil.Amount, btcutil.Amount, error) {
 
 	const (
 		// txOverhead is the overhead of a transaction residing within
@@ -1452,7 +1459,7 @@ func coinSelect(feeRate uint64, amt btcutil.Amount,
 		// the required fee.
 		totalSat, selectedUtxos, err := selectInputs(amtNeeded, coins)
 		if err != nil {
-			return nil, 0, err
+			return nil, 0, 0, err
 		}
 
 		// Based on the selected coins, estimate the size of the final
@@ -1479,7 +1486,7 @@ func coinSelect(feeRate uint64, amt btcutil.Amount,
 		// change output.
 		changeAmt := overShootAmt - requiredFee
 
-		return selectedUtxos, changeAmt, nil
+		return selectedUtxos, changeAmt, totalSat - amt - changeAmt, nil
 	}
 }
 
@@ -3,6 +3,7 @@ package lnwallet
 import (
 	"fmt"
 
+	"github.com/roasbeef/btcd/btcec"
 	"github.com/roasbeef/btcd/txscript"
 	"github.com/roasbeef/btcd/wire"
 )
@@ -25,8 +26,57 @@ const (
 	// of a malicious counterparty's who broadcasts a revoked commitment
 	// transaction.
 	CommitmentRevoke WitnessType = 2
+
+	// HtlcOfferedTimeoutSecondLevel is a witness that allows us to sweep
+	// the output of our second-level HTLC timeout transaction after its
+	// relative lock-time has passed.
+	HtlcOfferedTimeoutSecondLevel WitnessType = 3
+
+	// HtlcAcceptedSuccessSecondLevel is a witness that allows us to sweep
+	// the output of our second-level HTLC success transaction after its
+	// relative lock-time has passed.
+	HtlcAcceptedSuccessSecondLevel WitnessType = 4
+
+	// HtlcOfferedRevoke is a witness that allows us to sweep an HTLC we
+	// offered to a malicious counterparty who broadcasts a revoked
+	// commitment transaction.
+	HtlcOfferedRevoke WitnessType = 5
+
+	// HtlcAcceptedRevoke is a witness that allows us to sweep an HTLC
+	// offered to us by a malicious counterparty who broadcasts a revoked
+	// commitment transaction.
+	HtlcAcceptedRevoke WitnessType = 6
+
+	// HtlcSecondLevelRevoke is a witness that allows us to sweep the
+	// output of a second-level HTLC transaction spending an HTLC on a
+	// revoked commitment transaction of a malicious counterparty.
+	HtlcSecondLevelRevoke WitnessType = 7
 )
 
+// String returns a human readable version of the target WitnessType.
+func (wt WitnessType) String() string {
+	switch wt {
+	case CommitmentTimeLock:
+		return "CommitmentTimeLock"
+	case CommitmentNoDelay:
+		return "CommitmentNoDelay"
+	case CommitmentRevoke:
+		return "CommitmentRevoke"
+	case HtlcOfferedTimeoutSecondLevel:
+		return "HtlcOfferedTimeoutSecondLevel"
+	case HtlcAcceptedSuccessSecondLevel:
+		return "HtlcAcceptedSuccessSecondLevel"
+	case HtlcOfferedRevoke:
+		return "HtlcOfferedRevoke"
+	case HtlcAcceptedRevoke:
+		return "HtlcAcceptedRevoke"
+	case HtlcSecondLevelRevoke:
+		return "HtlcSecondLevelRevoke"
+	default:
+		return fmt.Sprintf("Unknown WitnessType: %v", uint16(wt))
+	}
+}
+
 // WitnessGenerator represents a function which is able to generate the final
 // witness for a particular public key script. This function acts as an
 // abstraction layer, hiding the details of the underlying script.
@@ -52,9 +102,36 @@ func (wt WitnessType) GenWitnessFunc(signer *Signer,
 			return CommitSpendNoDelay(*signer, desc, tx)
 		case CommitmentRevoke:
 			return CommitSpendRevoke(*signer, desc, tx)
+
+		// The second-level HTLC script shares the structure of the
+		// delayed commitment output script, so its delay clause is
+		// satisfied by the same witness.
+		case HtlcOfferedTimeoutSecondLevel, HtlcAcceptedSuccessSecondLevel:
+			return CommitSpendTimeout(*signer, desc, tx)
+
+		// The revocation key of the HTLC scripts is derived from our
+		// revocation base point and the commitment secret, which the
+		// sign descriptor holds as its public key and double tweak.
+		case HtlcOfferedRevoke:
+			return receiverHtlcSpendRevoke(
+				*signer, desc, revocationPubKey(desc), tx,
+			)
+		case HtlcAcceptedRevoke:
+			return senderHtlcSpendRevoke(
+				*signer, desc, revocationPubKey(desc), tx,
+			)
+		case HtlcSecondLevelRevoke:
+			return htlcSpendRevoke(*signer, desc, tx)
 		default:
 			return nil, fmt.Errorf("unknown witness type: %v", wt)
 		}
 	}
 
 }
+
+// revocationPubKey derives the revocation public key of a revoked commitment
+// from the passed sign descriptor, which holds our revocation base point and
+// the commitment secret of the revoked state.
+func revocationPubKey(desc *SignDescriptor) *btcec.PublicKey {
+	return DeriveRevocationPubkey(desc.PubKey, desc.DoubleTweak.PubKey())
+}
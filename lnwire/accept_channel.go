@@ -3,6 +3,7 @@ package lnwire
 import (
 	"io"
 
+	"github.com/lightningnetwork/lnd/tlv"
 	"github.com/roasbeef/btcd/btcec"
 	"github.com/roasbeef/btcutil"
 )
@@ -25,7 +26,7 @@ type AcceptChannel struct {
 	// MaxValueInFlight represents the maximum amount of coins that can be
 	// pending within the channel at any given time. If the amount of funds
 	// in limbo exceeds this amount, then the channel will be failed.
-	MaxValueInFlight MilliSatoshi
+	MaxValueInFlight MilliAtom
 
 	// ChannelReserve is the amount of BTC that the receiving party MUST
 	// maintain a balance above at all times. This is a safety mechanism to
@@ -39,7 +40,7 @@ type AcceptChannel struct {
 
 	// HtlcMinimum is the smallest HTLC that the sender of this message
 	// will accept.
-	HtlcMinimum MilliSatoshi
+	HtlcMinimum MilliAtom
 
 	// CsvDelay is the number of blocks to use for the relative time lock
 	// in the pay-to-self output of both commitment transactions.
@@ -80,6 +81,11 @@ type AcceptChannel struct {
 	// base point in order to derive the revocation keys that are placed
 	// within the commitment transaction of the sender.
 	FirstCommitmentPoint *btcec.PublicKey
+
+	// UpfrontShutdownScript is an optional script which the sender
+	// commits to pay out to when the channel is cooperatively closed. It's
+	// carried within the TLV stream appended to the message.
+	UpfrontShutdownScript DeliveryAddress
 }
 
 // A compile time check to ensure AcceptChannel implements the lnwire.Message
@@ -92,7 +98,7 @@ var _ Message = (*AcceptChannel)(nil)
 //
 // This is part of the lnwire.Message interface.
 func (a *AcceptChannel) Encode(w io.Writer, pver uint32) error {
-	return writeElements(w,
+	err := writeElements(w,
 		a.PendingChannelID[:],
 		a.DustLimit,
 		a.MaxValueInFlight,
@@ -107,6 +113,18 @@ func (a *AcceptChannel) Encode(w io.Writer, pver uint32) error {
 		a.DelayedPaymentPoint,
 		a.FirstCommitmentPoint,
 	)
+	if err != nil {
+		return err
+	}
+
+	// The upfront shutdown script is only appended if it's set.
+	var records []tlv.Record
+	if len(a.UpfrontShutdownScript) > 0 {
+		records = append(records, upfrontShutdownScriptRecord(
+			&a.UpfrontShutdownScript,
+		))
+	}
+	return encodeTLVStream(w, records...)
 }
 
 // Decode deserializes the serialized AcceptChannel stored in the passed
@@ -115,7 +133,7 @@ func (a *AcceptChannel) Encode(w io.Writer, pver uint32) error {
 //
 // This is part of the lnwire.Message interface.
 func (a *AcceptChannel) Decode(r io.Reader, pver uint32) error {
-	return readElements(r,
+	err := readElements(r,
 		a.PendingChannelID[:],
 		&a.DustLimit,
 		&a.MaxValueInFlight,
@@ -130,6 +148,14 @@ func (a *AcceptChannel) Decode(r io.Reader, pver uint32) error {
 		&a.DelayedPaymentPoint,
 		&a.FirstCommitmentPoint,
 	)
+	if err != nil {
+		return err
+	}
+
+	_, err = decodeTLVStream(
+		r, upfrontShutdownScriptRecord(&a.UpfrontShutdownScript),
+	)
+	return err
 }
 
 // MsgType returns the MessageType code which uniquely identifies this message
@@ -145,6 +171,7 @@ func (a *AcceptChannel) MsgType() MessageType {
 //
 // This is part of the lnwire.Message interface.
 func (a *AcceptChannel) MaxPayloadLength(uint32) uint32 {
-	// 32 + (8 * 4) + (4 * 1) + (2 * 2) + (33 * 5)
-	return 237
+	// The fixed size fields are followed by a TLV stream of unbounded
+	// length, so the message is only bounded by the maximum payload.
+	return MaxMessagePayload
 }
@@ -0,0 +1,114 @@
+package lnwire
+
+import (
+	"io"
+
+	"github.com/roasbeef/btcd/btcec"
+)
+
+// ChannelReestablish is sent by both sides of a channel upon reconnection in
+// order to synchronize their views of the channel's commitment chains. The
+// last two fields make up the option_data_loss_protect fields, which allow a
+// node that has lost state to detect as much, and to avoid broadcasting a
+// stale (revoked) commitment.
+type ChannelReestablish struct {
+	// ChanID is the channel ID of the channel state we're attempting to
+	// synchronize with the remote party.
+	ChanID ChannelID
+
+	// NextLocalCommitHeight is the next local commitment height of the
+	// sending party. If the height of the sender's commitment chain from
+	// the receiver's PoV is one less than this number, then the receiver
+	// should re-send the *exact* same state update it sent last.
+	NextLocalCommitHeight uint64
+
+	// RemoteCommitTailHeight is the height of the receiving party's
+	// unrevoked commitment from the PoV of the sender. In other words, it
+	// is the number of revocations the sender has received from the
+	// receiver.
+	RemoteCommitTailHeight uint64
+
+	// LastRemoteCommitSecret is the last commitment secret that the
+	// sending party has received from the receiving party. If the sender
+	// hasn't yet received any revocations, this is the zero value. With
+	// this secret, the receiving party is able to verify that the sender
+	// actually knows the state at RemoteCommitTailHeight, and that the
+	// receiver has lost data.
+	LastRemoteCommitSecret [32]byte
+
+	// LocalUnrevokedCommitPoint is the commitment point used within the
+	// sender's current unrevoked commitment transaction. In the case the
+	// receiver has lost data, it will need this point in order to sweep
+	// its output from the sender's commitment once it has been broadcast.
+	LocalUnrevokedCommitPoint *btcec.PublicKey
+}
+
+// A compile time check to ensure ChannelReestablish implements the
+// lnwire.Message interface.
+var _ Message = (*ChannelReestablish)(nil)
+
+// Encode serializes the target ChannelReestablish into the passed io.Writer
+// observing the protocol version specified. The data loss protection fields
+// are always included, so the commitment point MUST be set.
+//
+// This is part of the lnwire.Message interface.
+func (a *ChannelReestablish) Encode(w io.Writer, pver uint32) error {
+	return writeElements(w,
+		a.ChanID,
+		a.NextLocalCommitHeight,
+		a.RemoteCommitTailHeight,
+		a.LastRemoteCommitSecret[:],
+		a.LocalUnrevokedCommitPoint,
+	)
+}
+
+// Decode deserializes a serialized ChannelReestablish stored in the passed
+// io.Reader observing the specified protocol version. As older nodes may
+// omit the data loss protection fields, their absence isn't treated as an
+// error.
+//
+// This is part of the lnwire.Message interface.
+func (a *ChannelReestablish) Decode(r io.Reader, pver uint32) error {
+	err := readElements(r,
+		&a.ChanID,
+		&a.NextLocalCommitHeight,
+		&a.RemoteCommitTailHeight,
+	)
+	if err != nil {
+		return err
+	}
+
+	// If the remote party didn't send the data loss protection fields,
+	// then we're done here. Otherwise, both of them must be present.
+	_, err = io.ReadFull(r, a.LastRemoteCommitSecret[:])
+	if err == io.EOF {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	return readElements(r, &a.LocalUnrevokedCommitPoint)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (a *ChannelReestablish) MsgType() MessageType {
+	return MsgChannelReestablish
+}
+
+// MaxPayloadLength returns the maximum allowed payload length for a
+// ChannelReestablish message.
+//
+// This is part of the lnwire.Message interface.
+func (a *ChannelReestablish) MaxPayloadLength(pver uint32) uint32 {
+	// 32 + 8 + 8 + 32 + 33
+	return 113
+}
+
+// HasDataLossProtection returns true if the remote party sent the data loss
+// protection fields along with the message.
+func (a *ChannelReestablish) HasDataLossProtection() bool {
+	return a.LocalUnrevokedCommitPoint != nil
+}
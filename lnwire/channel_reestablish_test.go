@@ -0,0 +1,60 @@
+package lnwire
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestChannelReestablishLegacyDecode checks that a ChannelReestablish message
+// sent without the data loss protection fields can still be decoded, while a
+// message carrying only part of them is rejected.
+func TestChannelReestablishLegacyDecode(t *testing.T) {
+	t.Parallel()
+
+	commitPoint, err := randPubKey()
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+
+	msg := &ChannelReestablish{
+		ChanID:                    ChannelID{0x01},
+		NextLocalCommitHeight:     2,
+		RemoteCommitTailHeight:    1,
+		LastRemoteCommitSecret:    [32]byte{0x02},
+		LocalUnrevokedCommitPoint: commitPoint,
+	}
+
+	var b bytes.Buffer
+	if err := msg.Encode(&b, 0); err != nil {
+		t.Fatalf("unable to encode msg: %v", err)
+	}
+	if uint32(b.Len()) != msg.MaxPayloadLength(0) {
+		t.Fatalf("expected encoding of %d bytes, got %d",
+			msg.MaxPayloadLength(0), b.Len())
+	}
+
+	// Strip the data loss protection fields, mimicking a legacy node.
+	legacy := b.Bytes()[:48]
+
+	var decoded ChannelReestablish
+	if err := decoded.Decode(bytes.NewReader(legacy), 0); err != nil {
+		t.Fatalf("unable to decode legacy msg: %v", err)
+	}
+	if decoded.HasDataLossProtection() {
+		t.Fatalf("legacy msg shouldn't carry data loss protection")
+	}
+	if decoded.ChanID != msg.ChanID ||
+		decoded.NextLocalCommitHeight != msg.NextLocalCommitHeight ||
+		decoded.RemoteCommitTailHeight != msg.RemoteCommitTailHeight {
+
+		t.Fatalf("legacy msg decoded incorrectly: %v", decoded)
+	}
+
+	// A message carrying the commitment secret without the commitment
+	// point is malformed.
+	var partial ChannelReestablish
+	err = partial.Decode(bytes.NewReader(b.Bytes()[:80]), 0)
+	if err == nil {
+		t.Fatalf("expected partial msg to be rejected")
+	}
+}
@@ -2,12 +2,57 @@ package lnwire
 
 import (
 	"bytes"
+	"fmt"
+	"hash/crc32"
 	"io"
 
 	"github.com/roasbeef/btcd/btcec"
 	"github.com/roasbeef/btcd/chaincfg/chainhash"
 )
 
+// ChanUpdateMsgFlags is a bitfield that signals whether optional fields are
+// present in the ChannelUpdate.
+type ChanUpdateMsgFlags uint8
+
+const (
+	// ChanUpdateOptionMaxHtlc is a bit that indicates whether the
+	// optional htlc_maximum_msat field is present in this ChannelUpdate.
+	ChanUpdateOptionMaxHtlc ChanUpdateMsgFlags = 1 << iota
+)
+
+// String returns the bitfield flags as a string.
+func (c ChanUpdateMsgFlags) String() string {
+	return fmt.Sprintf("%08b", c)
+}
+
+// HasMaxHtlc returns true if the htlc_maximum_msat option bit is set in the
+// message flags.
+func (c ChanUpdateMsgFlags) HasMaxHtlc() bool {
+	return c&ChanUpdateOptionMaxHtlc != 0
+}
+
+// ChanUpdateChanFlags is a bitfield that signals various options concerning a
+// particular channel edge. Each bit is to be examined in order to determine
+// how the ChannelUpdate message is to be interpreted.
+type ChanUpdateChanFlags uint8
+
+const (
+	// ChanUpdateDirection indicates the direction of a channel update. If
+	// this bit is set to 0 if Node1 (the node with the "smaller" Node ID)
+	// is updating the channel, and to 1 otherwise.
+	ChanUpdateDirection ChanUpdateChanFlags = 1 << iota
+
+	// ChanUpdateDisabled is a bit that indicates if the channel edge
+	// selected by the ChanUpdateDirection bit is to be treated as being
+	// disabled.
+	ChanUpdateDisabled
+)
+
+// String returns the bitfield flags as a string.
+func (c ChanUpdateChanFlags) String() string {
+	return fmt.Sprintf("%08b", c)
+}
+
 // ChannelUpdate message is used after channel has been initially announced.
 // Each side independently announces its fees and minimum expiry for HTLCs and
 // other parameters. Also this message is used to redeclare initially setted
@@ -31,10 +76,23 @@ type ChannelUpdate struct {
 	// the last-received.
 	Timestamp uint32
 
-	// Flags least-significant bit must be set to 0 if the creating node
+	// MessageFlags is a bitfield that describes whether optional fields
+	// are present in this update. Currently, the least-significant bit
+	// must be set to 1 if the optional field HtlcMaximumMsat is included.
+	//
+	// NOTE: Together with ChannelFlags, this occupies the two bytes which
+	// formerly held a single flags field. As only the least-significant
+	// bit of that field was ever set, updates from older nodes are parsed
+	// unchanged.
+	MessageFlags ChanUpdateMsgFlags
+
+	// ChannelFlags is a bitfield that describes additional meta-data
+	// concerning how the update is to be interpreted. Currently, the
+	// least-significant bit must be set to 0 if the creating node
 	// corresponds to the first node in the previously sent channel
-	// announcement and 1 otherwise.
-	Flags uint16
+	// announcement and 1 otherwise. If the second bit is set, then the
+	// channel is set to be disabled.
+	ChannelFlags ChanUpdateChanFlags
 
 	// TimeLockDelta is the minimum number of blocks this node requires to
 	// be added to the expiry of HTLCs. This is a security parameter
@@ -44,7 +102,7 @@ type ChannelUpdate struct {
 	TimeLockDelta uint16
 
 	// HtlcMinimumMsat is the minimum HTLC value which will be accepted.
-	HtlcMinimumMsat MilliSatoshi
+	HtlcMinimumMsat MilliAtom
 
 	// BaseFee is the base fee that must be used for incoming HTLC's to
 	// this particular channel. This value will be tacked onto the required
@@ -54,6 +112,11 @@ type ChannelUpdate struct {
 	// FeeRate is the fee rate that will be charged per millionth of a
 	// satoshi.
 	FeeRate uint32
+
+	// HtlcMaximumMsat is the maximum HTLC value which will be accepted. It
+	// is only present on the wire if the ChanUpdateOptionMaxHtlc bit of
+	// MessageFlags is set.
+	HtlcMaximumMsat MilliAtom
 }
 
 // A compile time check to ensure ChannelUpdate implements the lnwire.Message
@@ -65,17 +128,30 @@ var _ Message = (*ChannelUpdate)(nil)
 //
 // This is part of the lnwire.Message interface.
 func (a *ChannelUpdate) Decode(r io.Reader, pver uint32) error {
-	return readElements(r,
+	err := readElements(r,
 		&a.Signature,
 		a.ChainHash[:],
 		&a.ShortChannelID,
 		&a.Timestamp,
-		&a.Flags,
+		&a.MessageFlags,
+		&a.ChannelFlags,
 		&a.TimeLockDelta,
 		&a.HtlcMinimumMsat,
 		&a.BaseFee,
 		&a.FeeRate,
 	)
+	if err != nil {
+		return err
+	}
+
+	// Now check whether the max HTLC field is present and read it if so.
+	if a.MessageFlags.HasMaxHtlc() {
+		if err := readElements(r, &a.HtlcMaximumMsat); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 // Encode serializes the target ChannelUpdate into the passed io.Writer
@@ -83,17 +159,29 @@ func (a *ChannelUpdate) Decode(r io.Reader, pver uint32) error {
 //
 // This is part of the lnwire.Message interface.
 func (a *ChannelUpdate) Encode(w io.Writer, pver uint32) error {
-	return writeElements(w,
+	err := writeElements(w,
 		a.Signature,
 		a.ChainHash[:],
 		a.ShortChannelID,
 		a.Timestamp,
-		a.Flags,
+		a.MessageFlags,
+		a.ChannelFlags,
 		a.TimeLockDelta,
 		a.HtlcMinimumMsat,
 		a.BaseFee,
 		a.FeeRate,
 	)
+	if err != nil {
+		return err
+	}
+
+	// Now append the optional max HTLC field if its presence is signalled
+	// within the message flags.
+	if a.MessageFlags.HasMaxHtlc() {
+		return writeElements(w, a.HtlcMaximumMsat)
+	}
+
+	return nil
 }
 
 // MsgType returns the integer uniquely identifying this message type on the
@@ -123,8 +211,11 @@ func (a *ChannelUpdate) MaxPayloadLength(pver uint32) uint32 {
 	// Timestamp - 4 bytes
 	length += 4
 
-	// Flags - 2 bytes
-	length += 2
+	// MessageFlags - 1 byte
+	length++
+
+	// ChannelFlags - 1 byte
+	length++
 
 	// Expiry - 2 bytes
 	length += 2
@@ -138,6 +229,9 @@ func (a *ChannelUpdate) MaxPayloadLength(pver uint32) uint32 {
 	// FeeProportionalMillionths - 4 bytes
 	length += 4
 
+	// HtlcMaximumMsat - 8 bytes
+	length += 8
+
 	return length
 }
 
@@ -151,7 +245,8 @@ func (a *ChannelUpdate) DataToSign() ([]byte, error) {
 		a.ChainHash[:],
 		a.ShortChannelID,
 		a.Timestamp,
-		a.Flags,
+		a.MessageFlags,
+		a.ChannelFlags,
 		a.TimeLockDelta,
 		a.HtlcMinimumMsat,
 		a.BaseFee,
@@ -161,5 +256,46 @@ func (a *ChannelUpdate) DataToSign() ([]byte, error) {
 		return nil, err
 	}
 
+	// The max HTLC field is covered by the signature only if present.
+	if a.MessageFlags.HasMaxHtlc() {
+		if err := writeElements(&w, a.HtlcMaximumMsat); err != nil {
+			return nil, err
+		}
+	}
+
 	return w.Bytes(), nil
 }
+
+// crc32cTable is the table of the Castagnoli polynomial, which is used to
+// compute the checksums of channel updates.
+var crc32cTable = crc32.MakeTable(crc32.Castagnoli)
+
+// Checksum returns the CRC32C checksum of the channel update without its
+// signature and timestamp fields, as included within the ReplyChannelRange
+// message. Two updates with the same checksum carry the same routing policy,
+// which allows a node to skip requesting an update that only differs from the
+// one it knows by its timestamp.
+func (a *ChannelUpdate) Checksum() (uint32, error) {
+	var w bytes.Buffer
+	err := writeElements(&w,
+		a.ChainHash[:],
+		a.ShortChannelID,
+		a.MessageFlags,
+		a.ChannelFlags,
+		a.TimeLockDelta,
+		a.HtlcMinimumMsat,
+		a.BaseFee,
+		a.FeeRate,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	if a.MessageFlags.HasMaxHtlc() {
+		if err := writeElements(&w, a.HtlcMaximumMsat); err != nil {
+			return 0, err
+		}
+	}
+
+	return crc32.Checksum(w.Bytes(), crc32cTable), nil
+}
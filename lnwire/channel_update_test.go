@@ -0,0 +1,99 @@
+package lnwire
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+// TestChannelUpdateLegacyFlags checks that a ChannelUpdate carrying the
+// former two byte flags field is parsed into the message and channel flags,
+// and that the optional max HTLC field is only read when signalled.
+func TestChannelUpdateLegacyFlags(t *testing.T) {
+	t.Parallel()
+
+	update := &ChannelUpdate{
+		Signature:       testSig,
+		ChainHash:       *shaHash1,
+		ShortChannelID:  NewShortChanIDFromInt(1),
+		Timestamp:       1504000000,
+		TimeLockDelta:   144,
+		HtlcMinimumMsat: 1000,
+		BaseFee:         1000,
+		FeeRate:         1,
+	}
+
+	// An update from an older node holds its direction within the
+	// least-significant bit of a big endian uint16.
+	var legacy bytes.Buffer
+	err := writeElements(&legacy,
+		update.Signature,
+		update.ChainHash[:],
+		update.ShortChannelID,
+		update.Timestamp,
+		uint16(1),
+		update.TimeLockDelta,
+		update.HtlcMinimumMsat,
+		update.BaseFee,
+		update.FeeRate,
+	)
+	if err != nil {
+		t.Fatalf("unable to encode legacy update: %v", err)
+	}
+
+	var decoded ChannelUpdate
+	err = decoded.Decode(bytes.NewReader(legacy.Bytes()), 0)
+	if err != nil {
+		t.Fatalf("unable to decode legacy update: %v", err)
+	}
+	if decoded.MessageFlags != 0 {
+		t.Fatalf("expected no message flags, got %v",
+			decoded.MessageFlags)
+	}
+	if decoded.ChannelFlags != ChanUpdateDirection {
+		t.Fatalf("expected channel flags %v, got %v",
+			ChanUpdateDirection, decoded.ChannelFlags)
+	}
+	if decoded.HtlcMaximumMsat != 0 {
+		t.Fatalf("expected no max htlc, got %v",
+			decoded.HtlcMaximumMsat)
+	}
+
+	// Signalling the max HTLC field should append it to the message, and
+	// cover it by the signature.
+	unsignedData, err := update.DataToSign()
+	if err != nil {
+		t.Fatalf("unable to get data to sign: %v", err)
+	}
+
+	update.MessageFlags = ChanUpdateOptionMaxHtlc
+	update.HtlcMaximumMsat = 500000
+	var b bytes.Buffer
+	if err := update.Encode(&b, 0); err != nil {
+		t.Fatalf("unable to encode update: %v", err)
+	}
+	if b.Len() != legacy.Len()+8 {
+		t.Fatalf("expected %d bytes, got %d", legacy.Len()+8, b.Len())
+	}
+	maxHtlc := binary.BigEndian.Uint64(b.Bytes()[legacy.Len():])
+	if MilliAtom(maxHtlc) != update.HtlcMaximumMsat {
+		t.Fatalf("expected max htlc %v, got %v",
+			update.HtlcMaximumMsat, maxHtlc)
+	}
+
+	signedData, err := update.DataToSign()
+	if err != nil {
+		t.Fatalf("unable to get data to sign: %v", err)
+	}
+	if len(signedData) != len(unsignedData)+8 {
+		t.Fatalf("max htlc not covered by signature")
+	}
+
+	// Finally, an update signalling the max HTLC field without carrying
+	// it should be rejected.
+	truncated := b.Bytes()[:legacy.Len()]
+	err = decoded.Decode(bytes.NewReader(truncated), 0)
+	if err == nil {
+		t.Fatalf("expected truncated update to be rejected")
+	}
+}
@@ -1,10 +1,6 @@
 package lnwire
 
-import (
-	"io"
-
-	"github.com/roasbeef/btcd/btcec"
-)
+import "io"
 
 // CommitSig is sent by either side to stage any pending HTLC's in the
 // receiver's pending set into a new commitment state.  Implicitly, the new
@@ -26,7 +22,7 @@ type CommitSig struct {
 	// If initiating a new commitment state, this signature shoud ONLY
 	// cover all of the sending party's pending log updates, and the log
 	// updates of the remote party that have been ACK'd.
-	CommitSig *btcec.Signature
+	CommitSig *Sig
 
 	// HtlcSigs is a signature for each relevant HTLC output within the
 	// created commitment. The order of the signatures is expected to be
@@ -35,7 +31,7 @@ type CommitSig struct {
 	// sender of this message), a signature for a HTLC timeout transaction
 	// should be signed, for each incoming HTLC the HTLC timeout
 	// transaction should be signed.
-	HtlcSigs []*btcec.Signature
+	HtlcSigs []*Sig
 }
 
 // NewCommitSig creates a new empty CommitSig message.
@@ -0,0 +1,77 @@
+package lnwire
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+// CustomTypeStart is the start of the custom type range for peer messages as
+// defined in BOLT 01. Messages of these types aren't interpreted by the
+// daemon, but are instead passed on to applications built on top of it.
+const CustomTypeStart MessageType = 32768
+
+// Custom represents an application-defined wire message, whose type lies
+// within the custom type range. Its payload is opaque to the daemon.
+type Custom struct {
+	// Type is the type of the message, which must be at least
+	// CustomTypeStart.
+	Type MessageType
+
+	// Data is the opaque payload of the message.
+	Data []byte
+}
+
+// A compile time check to ensure Custom implements the lnwire.Message
+// interface.
+var _ Message = (*Custom)(nil)
+
+// NewCustom instantiates a new custom message of the passed type, returning
+// an error if the type lies outside of the custom type range.
+func NewCustom(msgType MessageType, data []byte) (*Custom, error) {
+	if msgType < CustomTypeStart {
+		return nil, fmt.Errorf("msg type %d is below the custom type "+
+			"range starting at %d", msgType, CustomTypeStart)
+	}
+
+	return &Custom{
+		Type: msgType,
+		Data: data,
+	}, nil
+}
+
+// Encode serializes the target Custom message into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (c *Custom) Encode(w io.Writer, pver uint32) error {
+	_, err := w.Write(c.Data)
+	return err
+}
+
+// Decode deserializes a serialized Custom message stored in the passed
+// io.Reader observing the specified protocol version. As the payload is
+// opaque, the remainder of the message is read as its data.
+//
+// This is part of the lnwire.Message interface.
+func (c *Custom) Decode(r io.Reader, pver uint32) error {
+	var err error
+	c.Data, err = ioutil.ReadAll(r)
+	return err
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (c *Custom) MsgType() MessageType {
+	return c.Type
+}
+
+// MaxPayloadLength returns the maximum allowed payload size for a Custom
+// message observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (c *Custom) MaxPayloadLength(uint32) uint32 {
+	return MaxMessagePayload
+}
@@ -0,0 +1,40 @@
+package lnwire
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+// TestCustomMessage checks that messages within the custom type range are
+// round tripped with their opaque payload, and that custom messages can't be
+// created outside of that range.
+func TestCustomMessage(t *testing.T) {
+	t.Parallel()
+
+	msg, err := NewCustom(CustomTypeStart+1, []byte{0x01, 0x02, 0x03})
+	if err != nil {
+		t.Fatalf("unable to create custom message: %v", err)
+	}
+
+	var b bytes.Buffer
+	if _, err := WriteMessage(&b, msg, 0); err != nil {
+		t.Fatalf("unable to encode message: %v", err)
+	}
+	decoded, err := ReadMessage(&b, 0)
+	if err != nil {
+		t.Fatalf("unable to decode message: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, msg) {
+		t.Fatalf("expected %v, got %v", msg, decoded)
+	}
+
+	if msg.MsgType().String() != "Custom(32769)" {
+		t.Fatalf("unexpected message type string: %v",
+			msg.MsgType().String())
+	}
+
+	if _, err := NewCustom(CustomTypeStart-1, nil); err == nil {
+		t.Fatalf("expected type below custom range to be rejected")
+	}
+}
@@ -1,7 +1,9 @@
 package lnwire
 
 import (
+	"fmt"
 	"io"
+	"strings"
 
 	"google.golang.org/grpc/codes"
 )
@@ -32,20 +34,85 @@ const (
 	// FundingOpen request for a channel that is above their current
 	// soft-limit.
 	ErrChanTooLarge ErrorCode = 3
+
+	// ErrInvalidDustLimit is returned by a remote peer that receives a
+	// FundingOpen request with a dust limit outside of the bounds they
+	// consider sane.
+	ErrInvalidDustLimit ErrorCode = 4
+
+	// ErrInvalidUpfrontShutdown is returned by a remote peer that receives
+	// a FundingOpen request with an upfront shutdown script that doesn't
+	// match one of the standard script templates.
+	ErrInvalidUpfrontShutdown ErrorCode = 5
+
+	// ErrTemporaryChannelFailure is returned by a remote peer that is
+	// momentarily unable to proceed with a channel, for example because
+	// its wallet was unable to create a reservation for a FundingOpen
+	// request. The request may succeed if retried at a later time.
+	ErrTemporaryChannelFailure ErrorCode = 6
 )
 
+// errorCodeInfo describes a known ErrorCode.
+type errorCodeInfo struct {
+	// desc is a human readable description of the error.
+	desc string
+
+	// temporary indicates that the condition which triggered the error is
+	// transient, so the failed operation may be retried later on. All
+	// other errors require the channel they refer to be abandoned.
+	temporary bool
+
+	// patterns is a set of substrings of the free-form error messages
+	// sent by other implementations which signal this error.
+	patterns []string
+}
+
+// errorCodes is the registry of all known error codes.
+var errorCodes = map[ErrorCode]errorCodeInfo{
+	ErrMaxPendingChannels: {
+		desc:      "Number of pending channels exceed maximum",
+		temporary: true,
+		patterns:  []string{"too many pending channels"},
+	},
+	ErrSynchronizingChain: {
+		desc:      "Synchronizing blockchain",
+		temporary: true,
+		patterns:  []string{"still syncing", "not synced"},
+	},
+	ErrChanTooLarge: {
+		desc:     "channel too large",
+		patterns: []string{"funding amount too large"},
+	},
+	ErrInvalidDustLimit: {
+		desc:     "dust limit out of bounds",
+		patterns: []string{"dust limit"},
+	},
+	ErrInvalidUpfrontShutdown: {
+		desc:     "invalid upfront shutdown script",
+		patterns: []string{"upfront_shutdown_script"},
+	},
+	ErrTemporaryChannelFailure: {
+		desc:      "temporary channel failure",
+		temporary: true,
+		patterns:  []string{"temporary_channel_failure"},
+	},
+}
+
 // String returns a human readable version of the target ErrorCode.
 func (e ErrorCode) String() string {
-	switch e {
-	case ErrMaxPendingChannels:
-		return "Number of pending channels exceed maximum"
-	case ErrSynchronizingChain:
-		return "Synchronizing blockchain"
-	case ErrChanTooLarge:
-		return "channel too large"
-	default:
+	info, ok := errorCodes[e]
+	if !ok {
 		return "unknown error"
 	}
+
+	return info.desc
+}
+
+// IsTemporary returns true if the target ErrorCode signals a transient
+// failure, meaning the operation which triggered it may be retried later on
+// rather than the channel being abandoned.
+func (e ErrorCode) IsTemporary() bool {
+	return errorCodes[e].temporary
 }
 
 // ErrorData is a set of bytes associated with a particular sent error. A
@@ -76,6 +143,87 @@ func NewError() *Error {
 	return &Error{}
 }
 
+// NewCodedError creates a new Error message for the target channel carrying
+// the passed error code. Any detail is appended to the code within the
+// message's data, which allows the receiver to recover both through the Code
+// and Detail methods.
+func NewCodedError(chanID ChannelID, code ErrorCode, detail string) *Error {
+	data := make(ErrorData, 0, 1+len(detail))
+	data = append(data, byte(code))
+	data = append(data, detail...)
+
+	return &Error{
+		ChanID: chanID,
+		Data:   data,
+	}
+}
+
+// Code attempts to recover the ErrorCode signalled by the error. A coded
+// error's data starts with one of the known error codes, which can't be
+// mistaken for printable ASCII. Otherwise, the data is matched against the
+// patterns known to be used by other implementations. If the error doesn't
+// correspond to any known code, false is returned.
+func (c *Error) Code() (ErrorCode, bool) {
+	if len(c.Data) > 0 {
+		code := ErrorCode(c.Data[0])
+		if _, ok := errorCodes[code]; ok {
+			return code, true
+		}
+	}
+
+	msg := strings.ToLower(string(c.Data))
+	for code, info := range errorCodes {
+		for _, pattern := range info.patterns {
+			if strings.Contains(msg, pattern) {
+				return code, true
+			}
+		}
+	}
+
+	return 0, false
+}
+
+// Detail returns the structured data attached to the error. For coded errors
+// this is the data following the error code, otherwise the entire data is
+// returned.
+func (c *Error) Detail() ErrorData {
+	if len(c.Data) > 0 {
+		if _, ok := errorCodes[ErrorCode(c.Data[0])]; ok {
+			return c.Data[1:]
+		}
+	}
+
+	return c.Data
+}
+
+// IsTemporary returns true if the error signals a transient failure, after
+// which the failed operation may be retried. Errors which don't carry a known
+// code are assumed to require the channel they refer to be abandoned.
+func (c *Error) IsTemporary() bool {
+	code, ok := c.Code()
+	return ok && code.IsTemporary()
+}
+
+// Error returns a human readable version of the error.
+//
+// This is part of the error interface.
+func (c *Error) Error() string {
+	code, ok := c.Code()
+	if !ok {
+		return fmt.Sprintf("chan_id=%v, err=%q", c.ChanID,
+			string(c.Data))
+	}
+
+	detail := c.Detail()
+	if len(detail) == 0 {
+		return fmt.Sprintf("chan_id=%v, code=%d, err=%v", c.ChanID,
+			uint8(code), code)
+	}
+
+	return fmt.Sprintf("chan_id=%v, code=%d, err=%v: %q", c.ChanID,
+		uint8(code), code, string(detail))
+}
+
 // A compile time check to ensure Error implements the lnwire.Message
 // interface.
 var _ Message = (*Error)(nil)
@@ -0,0 +1,78 @@
+package lnwire
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestErrorCode checks that the error code and structured data of an Error
+// are recovered, both for coded errors and for the free-form errors sent by
+// other implementations.
+func TestErrorCode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		err       *Error
+		code      ErrorCode
+		known     bool
+		detail    []byte
+		temporary bool
+	}{
+		{
+			name:   "legacy coded error",
+			err:    &Error{Data: ErrorData{byte(ErrChanTooLarge)}},
+			code:   ErrChanTooLarge,
+			known:  true,
+			detail: []byte{},
+		},
+		{
+			name: "coded error with detail",
+			err: NewCodedError(
+				ChannelID{}, ErrTemporaryChannelFailure,
+				"wallet busy",
+			),
+			code:      ErrTemporaryChannelFailure,
+			known:     true,
+			detail:    []byte("wallet busy"),
+			temporary: true,
+		},
+		{
+			name:      "matched pattern",
+			err:       &Error{Data: ErrorData("Node is still syncing")},
+			code:      ErrSynchronizingChain,
+			known:     true,
+			detail:    []byte("Node is still syncing"),
+			temporary: true,
+		},
+		{
+			name:   "unknown error",
+			err:    &Error{Data: ErrorData("something went wrong")},
+			detail: []byte("something went wrong"),
+		},
+		{
+			name: "empty error",
+			err:  &Error{},
+		},
+	}
+
+	for _, test := range tests {
+		code, ok := test.err.Code()
+		if ok != test.known {
+			t.Fatalf("%s: expected known=%v, got %v", test.name,
+				test.known, ok)
+		}
+		if ok && code != test.code {
+			t.Fatalf("%s: expected code %v, got %v", test.name,
+				test.code, code)
+		}
+		if !bytes.Equal(test.err.Detail(), test.detail) {
+			t.Fatalf("%s: expected detail %x, got %x", test.name,
+				test.detail, test.err.Detail())
+		}
+		if test.err.IsTemporary() != test.temporary {
+			t.Fatalf("%s: expected temporary=%v", test.name,
+				test.temporary)
+		}
+	}
+}
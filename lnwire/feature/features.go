@@ -0,0 +1,109 @@
+package feature
+
+import "github.com/lightningnetwork/lnd/lnwire"
+
+// The names of all the features known to this node. Within a feature vector,
+// each feature occupies a pair of bits, the even bit signalling that the
+// feature is required and the odd bit that it's optional.
+const (
+	// Filler occupies the first pair of bits, which are reserved for data
+	// loss protection. It's only advertised for compatibility with the
+	// existing nodes within the network.
+	Filler lnwire.FeatureName = "filler"
+
+	// InitialRoutingSync signals that the sending node would like to
+	// receive a complete dump of the channel graph upon connection.
+	InitialRoutingSync lnwire.FeatureName = "announce-graph"
+
+	// UpfrontShutdownScript signals that the node is able to commit to a
+	// shutdown script when funding a channel.
+	UpfrontShutdownScript lnwire.FeatureName = "upfront-shutdown-script"
+
+	// GossipQueries signals that the node supports the gossip query
+	// messages.
+	GossipQueries lnwire.FeatureName = "gossip-queries"
+
+	// TLVOnion signals that the node is able to parse TLV hop payloads.
+	TLVOnion lnwire.FeatureName = "var-onion-optin"
+
+	// GossipQueriesEx signals that the node supports the timestamps and
+	// checksums within the gossip query messages.
+	GossipQueriesEx lnwire.FeatureName = "gossip-queries-ex"
+
+	// PaymentSecret signals that the node requires a payment secret to be
+	// included within the final hop payload of incoming payments.
+	PaymentSecret lnwire.FeatureName = "payment-secret"
+
+	// MPP signals that the node is able to receive payments split across
+	// multiple paths.
+	MPP lnwire.FeatureName = "basic-mpp"
+)
+
+// indices maps each known feature to the index of its pair of bits within a
+// feature vector.
+var indices = map[lnwire.FeatureName]int{
+	Filler:                0,
+	InitialRoutingSync:    1,
+	UpfrontShutdownScript: 2,
+	GossipQueries:         3,
+	TLVOnion:              4,
+	GossipQueriesEx:       5,
+	PaymentSecret:         7,
+	MPP:                   8,
+}
+
+// names maps the index of each known feature back to its name.
+var names = func() map[int]lnwire.FeatureName {
+	names := make(map[int]lnwire.FeatureName, len(indices))
+	for name, index := range indices {
+		names[index] = name
+	}
+	return names
+}()
+
+// deps is the set of features each feature directly depends upon. A feature
+// may only be set within a vector if all of its dependencies are set as well.
+var deps = map[lnwire.FeatureName][]lnwire.FeatureName{
+	GossipQueriesEx: {GossipQueries},
+	PaymentSecret:   {TLVOnion},
+	MPP:             {PaymentSecret},
+}
+
+// Index returns the index of the target feature's pair of bits within a
+// feature vector, and whether the feature is known at all.
+func Index(name lnwire.FeatureName) (int, bool) {
+	index, ok := indices[name]
+	return index, ok
+}
+
+// Name returns the name of the feature at the target index of a feature
+// vector, and whether the feature is known at all.
+func Name(index int) (lnwire.FeatureName, bool) {
+	name, ok := names[index]
+	return name, ok
+}
+
+// Dependencies returns the set of features the target feature depends upon,
+// including the transitive ones.
+func Dependencies(name lnwire.FeatureName) []lnwire.FeatureName {
+	var (
+		all  []lnwire.FeatureName
+		seen = make(map[lnwire.FeatureName]struct{})
+	)
+
+	queue := append([]lnwire.FeatureName(nil), deps[name]...)
+	for len(queue) > 0 {
+		dep := queue[0]
+		queue = queue[1:]
+
+		if _, ok := seen[dep]; ok {
+			continue
+		}
+		seen[dep] = struct{}{}
+
+		all = append(all, dep)
+		queue = append(queue, deps[dep]...)
+	}
+
+	return all
+}
@@ -0,0 +1,149 @@
+package feature
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// ErrUnknownRequired is returned when a feature vector requires a feature
+// which isn't known to us.
+var ErrUnknownRequired = errors.New("feature vector requires unknown feature")
+
+// Manager is the single source of truth for the features advertised by the
+// node. It produces the feature vectors for each of the sets, and validates
+// the feature vectors advertised by remote nodes.
+type Manager struct {
+	// fsets holds the feature vector of each of the sets.
+	fsets map[Set]*lnwire.FeatureVector
+}
+
+// NewManager creates a new feature manager advertising the default set of
+// features.
+func NewManager() (*Manager, error) {
+	return newManager(defaultSetDesc)
+}
+
+// newManager creates a new feature manager advertising the features within
+// the passed set description. An error is returned if a feature is unknown,
+// or is advertised without its dependencies.
+func newManager(desc setDesc) (*Manager, error) {
+	features := make(map[Set]map[int]lnwire.Feature)
+	for name, sets := range desc {
+		index, ok := Index(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown feature %v", name)
+		}
+
+		for set, flag := range sets {
+			if _, ok := features[set]; !ok {
+				features[set] = make(map[int]lnwire.Feature)
+			}
+			features[set][index] = lnwire.Feature{
+				Name: name,
+				Flag: flag,
+			}
+		}
+	}
+
+	fsets := make(map[Set]*lnwire.FeatureVector)
+	for _, set := range []Set{
+		SetInitLocal, SetInitGlobal, SetNodeAnn, SetInvoice,
+	} {
+		fsets[set] = lnwire.NewIndexedFeatureVector(features[set])
+	}
+
+	// The dependencies of the features sent within the Init message may
+	// be split across its local and global vectors, so we'll validate
+	// them together.
+	initFeatures := merge(fsets[SetInitLocal], fsets[SetInitGlobal])
+	if err := ValidateDeps(initFeatures); err != nil {
+		return nil, fmt.Errorf("invalid %v/%v: %v", SetInitLocal,
+			SetInitGlobal, err)
+	}
+	for _, set := range []Set{SetNodeAnn, SetInvoice} {
+		if err := ValidateDeps(fsets[set]); err != nil {
+			return nil, fmt.Errorf("invalid %v: %v", set, err)
+		}
+	}
+
+	return &Manager{
+		fsets: fsets,
+	}, nil
+}
+
+// Get returns a copy of the feature vector of the target set.
+func (m *Manager) Get(set Set) *lnwire.FeatureVector {
+	fv, ok := m.fsets[set]
+	if !ok {
+		return lnwire.NewFeatureVector(nil)
+	}
+
+	return fv.Copy()
+}
+
+// ValidateInit validates the feature vectors within an Init message sent by a
+// remote peer. The remote peer may not require any feature unknown to us,
+// and all of the features it sets must have their dependencies set as well.
+func (m *Manager) ValidateInit(msg *lnwire.Init) error {
+	features := merge(msg.LocalFeatures, msg.GlobalFeatures)
+
+	for _, index := range features.Indices() {
+		if _, ok := Name(index); ok {
+			continue
+		}
+
+		flag, _ := features.Flag(index)
+		if flag == lnwire.RequiredFlag {
+			return fmt.Errorf("%v: index %d", ErrUnknownRequired,
+				index)
+		}
+	}
+
+	return ValidateDeps(features)
+}
+
+// ValidateDeps ensures that all the known features set within the passed
+// feature vector have their dependencies set as well. As each feature's
+// direct dependencies must be set, so must its transitive ones.
+func ValidateDeps(fv *lnwire.FeatureVector) error {
+	for _, index := range fv.Indices() {
+		name, ok := Name(index)
+		if !ok {
+			continue
+		}
+
+		for _, dep := range deps[name] {
+			if _, ok := fv.Flag(indices[dep]); !ok {
+				return fmt.Errorf("feature %v depends on "+
+					"missing feature %v", name, dep)
+			}
+		}
+	}
+
+	return nil
+}
+
+// merge returns a feature vector holding the union of the features set within
+// the passed vectors. If a feature is set within both, the flag of the first
+// vector takes precedence.
+func merge(vectors ...*lnwire.FeatureVector) *lnwire.FeatureVector {
+	features := make(map[int]lnwire.Feature)
+	for i := len(vectors) - 1; i >= 0; i-- {
+		if vectors[i] == nil {
+			continue
+		}
+
+		for _, index := range vectors[i].Indices() {
+			flag, _ := vectors[i].Flag(index)
+			name, _ := Name(index)
+			features[index] = lnwire.Feature{
+				Name: name,
+				Flag: flag,
+			}
+		}
+	}
+
+	return lnwire.NewIndexedFeatureVector(features)
+}
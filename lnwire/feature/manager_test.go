@@ -0,0 +1,166 @@
+package feature
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// newVector creates a feature vector setting the passed features at their
+// known indices.
+func newVector(t *testing.T, features ...lnwire.Feature) *lnwire.FeatureVector {
+	indexed := make(map[int]lnwire.Feature)
+	for _, feature := range features {
+		index, ok := Index(feature.Name)
+		if !ok {
+			t.Fatalf("unknown feature %v", feature.Name)
+		}
+		indexed[index] = feature
+	}
+
+	return lnwire.NewIndexedFeatureVector(indexed)
+}
+
+// TestDependencies checks that the transitive dependencies of a feature are
+// returned.
+func TestDependencies(t *testing.T) {
+	t.Parallel()
+
+	deps := Dependencies(MPP)
+	expected := []lnwire.FeatureName{PaymentSecret, TLVOnion}
+	if !reflect.DeepEqual(deps, expected) {
+		t.Fatalf("expected dependencies %v, got %v", expected, deps)
+	}
+
+	if deps := Dependencies(GossipQueries); len(deps) != 0 {
+		t.Fatalf("expected no dependencies, got %v", deps)
+	}
+}
+
+// TestManagerSets checks that the manager produces the vectors advertised
+// within each set, and rejects set descriptions missing dependencies.
+func TestManagerSets(t *testing.T) {
+	t.Parallel()
+
+	mgr, err := newManager(setDesc{
+		TLVOnion: {
+			SetInitGlobal: lnwire.OptionalFlag,
+			SetInvoice:    lnwire.OptionalFlag,
+		},
+		PaymentSecret: {
+			SetInitLocal: lnwire.OptionalFlag,
+			SetInvoice:   lnwire.RequiredFlag,
+		},
+	})
+	if err != nil {
+		t.Fatalf("unable to create manager: %v", err)
+	}
+
+	invoiceFeatures := mgr.Get(SetInvoice)
+	flag, ok := invoiceFeatures.Flag(indices[PaymentSecret])
+	if !ok || flag != lnwire.RequiredFlag {
+		t.Fatalf("expected payment secret to be required")
+	}
+	if _, ok := invoiceFeatures.Flag(indices[TLVOnion]); !ok {
+		t.Fatalf("expected tlv onion to be set")
+	}
+	if len(mgr.Get(SetNodeAnn).Indices()) != 0 {
+		t.Fatalf("expected empty node announcement features")
+	}
+
+	// Advertising the payment secret within an invoice without its
+	// dependency should be rejected.
+	_, err = newManager(setDesc{
+		PaymentSecret: {
+			SetInvoice: lnwire.OptionalFlag,
+		},
+	})
+	if err == nil {
+		t.Fatalf("expected missing dependency to be rejected")
+	}
+
+	// The default manager should advertise our support for upfront
+	// shutdown scripts both within Init and our node announcement.
+	mgr, err = NewManager()
+	if err != nil {
+		t.Fatalf("unable to create default manager: %v", err)
+	}
+	for _, set := range []Set{SetInitLocal, SetNodeAnn} {
+		flag, ok := mgr.Get(set).Flag(indices[UpfrontShutdownScript])
+		if !ok || flag != lnwire.OptionalFlag {
+			t.Fatalf("expected upfront shutdown script to be "+
+				"optional within %v", set)
+		}
+	}
+}
+
+// TestValidateInit checks that remote Init messages requiring unknown
+// features, or missing the dependencies of the features they set, are
+// rejected.
+func TestValidateInit(t *testing.T) {
+	t.Parallel()
+
+	mgr, err := NewManager()
+	if err != nil {
+		t.Fatalf("unable to create manager: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		global *lnwire.FeatureVector
+		local  *lnwire.FeatureVector
+		valid  bool
+	}{
+		{
+			name:   "empty",
+			global: newVector(t),
+			local:  newVector(t),
+			valid:  true,
+		},
+		{
+			name: "dependency across vectors",
+			global: newVector(t, lnwire.Feature{
+				Name: TLVOnion, Flag: lnwire.OptionalFlag,
+			}),
+			local: newVector(t, lnwire.Feature{
+				Name: PaymentSecret, Flag: lnwire.RequiredFlag,
+			}),
+			valid: true,
+		},
+		{
+			name:   "missing dependency",
+			global: newVector(t),
+			local: newVector(t, lnwire.Feature{
+				Name: GossipQueriesEx, Flag: lnwire.OptionalFlag,
+			}),
+		},
+		{
+			name:   "unknown optional",
+			global: newVector(t),
+			local: lnwire.NewIndexedFeatureVector(map[int]lnwire.Feature{
+				100: {Flag: lnwire.OptionalFlag},
+			}),
+			valid: true,
+		},
+		{
+			name:   "unknown required",
+			global: newVector(t),
+			local: lnwire.NewIndexedFeatureVector(map[int]lnwire.Feature{
+				100: {Flag: lnwire.RequiredFlag},
+			}),
+		},
+	}
+
+	for _, test := range tests {
+		err := mgr.ValidateInit(
+			lnwire.NewInitMessage(test.global, test.local),
+		)
+		if test.valid && err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		if !test.valid && err == nil {
+			t.Fatalf("%s: expected error", test.name)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package feature
+
+import "github.com/lightningnetwork/lnd/lnwire"
+
+// Set identifies one of the feature vectors a node advertises.
+type Set uint8
+
+const (
+	// SetInitLocal is the local feature vector sent within the Init
+	// message, which only affects the protocol between two peers.
+	SetInitLocal Set = iota
+
+	// SetInitGlobal is the global feature vector sent within the Init
+	// message, which affects the routing of HTLCs.
+	SetInitGlobal
+
+	// SetNodeAnn is the feature vector advertised within our
+	// NodeAnnouncement.
+	SetNodeAnn
+
+	// SetInvoice is the feature vector advertised within our invoices.
+	SetInvoice
+)
+
+// String returns a human readable version of the target Set.
+func (s Set) String() string {
+	switch s {
+	case SetInitLocal:
+		return "SetInitLocal"
+	case SetInitGlobal:
+		return "SetInitGlobal"
+	case SetNodeAnn:
+		return "SetNodeAnn"
+	case SetInvoice:
+		return "SetInvoice"
+	default:
+		return "SetUnknown"
+	}
+}
+
+// setDesc describes the sets each feature is advertised within, along with
+// whether it's advertised as required or optional.
+type setDesc map[lnwire.FeatureName]map[Set]lnwire.FeatureFlag
+
+// defaultSetDesc is the set of features advertised by default. Features which
+// this node knows of but doesn't advertise yet are omitted.
+var defaultSetDesc = setDesc{
+	Filler: {
+		SetInitLocal: lnwire.OptionalFlag,
+	},
+	InitialRoutingSync: {
+		SetInitLocal: lnwire.OptionalFlag,
+	},
+	UpfrontShutdownScript: {
+		SetInitLocal: lnwire.OptionalFlag,
+		SetNodeAnn:   lnwire.OptionalFlag,
+	},
+}
@@ -4,16 +4,17 @@ import (
 	"encoding/binary"
 	"io"
 	"math"
+	"sort"
 
 	"github.com/go-errors/errors"
 )
 
-// featureFlag represent the status of the feature optional/required and needed
+// FeatureFlag represent the status of the feature optional/required and needed
 // to allow future incompatible changes, or backward compatible changes.
-type featureFlag uint8
+type FeatureFlag uint8
 
-// String returns the string representation for the featureFlag.
-func (f featureFlag) String() string {
+// String returns the string representation for the FeatureFlag.
+func (f FeatureFlag) String() string {
 	switch f {
 	case OptionalFlag:
 		return "optional"
@@ -24,20 +25,20 @@ func (f featureFlag) String() string {
 	}
 }
 
-// featureName represent the name of the feature and needed in order to have
+// FeatureName represent the name of the feature and needed in order to have
 // the compile errors if we specify wrong feature name.
-type featureName string
+type FeatureName string
 
 const (
 	// OptionalFlag represent the feature which we already have but it
 	// isn't required yet, and if remote peer doesn't have this feature we
 	// may turn it off without disconnecting with peer.
-	OptionalFlag featureFlag = 2 // 0b10
+	OptionalFlag FeatureFlag = 2 // 0b10
 
 	// RequiredFlag represent the features which is required for proper
 	// peer interaction, we disconnect with peer if it doesn't have this
 	// particular feature.
-	RequiredFlag featureFlag = 1 // 0b01
+	RequiredFlag FeatureFlag = 1 // 0b01
 
 	// flagMask is a mask which is needed to extract feature flag value.
 	flagMask = 3 // 0b11
@@ -64,8 +65,8 @@ const (
 // Feature represent the feature which is used on stage of initialization of
 // feature vector. Initial feature flags might be changed dynamically later.
 type Feature struct {
-	Name featureName
-	Flag featureFlag
+	Name FeatureName
+	Flag FeatureFlag
 }
 
 // FeatureVector represents the global/local feature vector. With this
@@ -76,17 +77,17 @@ type FeatureVector struct {
 	// feature name and its index within feature vector. Index within
 	// feature vector and actual binary position of feature are different
 	// things)
-	featuresMap map[featureName]int // name -> index
+	featuresMap map[FeatureName]int // name -> index
 
 	// flags is the map which stores the correspondence between feature
 	// index and its flag.
-	flags map[int]featureFlag // index -> flag
+	flags map[int]FeatureFlag // index -> flag
 }
 
 // NewFeatureVector creates new instance of feature vector.
 func NewFeatureVector(features []Feature) *FeatureVector {
-	featuresMap := make(map[featureName]int)
-	flags := make(map[int]featureFlag)
+	featuresMap := make(map[FeatureName]int)
+	flags := make(map[int]FeatureFlag)
 
 	for index, feature := range features {
 		featuresMap[feature.Name] = index
@@ -99,8 +100,46 @@ func NewFeatureVector(features []Feature) *FeatureVector {
 	}
 }
 
+// NewIndexedFeatureVector creates new instance of feature vector in which
+// each feature is placed at the index it's keyed by, rather than at its
+// position within a list. This allows vectors to be created in which only a
+// subset of the known features is set.
+func NewIndexedFeatureVector(features map[int]Feature) *FeatureVector {
+	featuresMap := make(map[FeatureName]int)
+	flags := make(map[int]FeatureFlag)
+
+	for index, feature := range features {
+		featuresMap[feature.Name] = index
+		flags[index] = feature.Flag
+	}
+
+	return &FeatureVector{
+		featuresMap: featuresMap,
+		flags:       flags,
+	}
+}
+
+// Flag returns the flag of the feature at the target index, and whether the
+// feature is set at all.
+func (f *FeatureVector) Flag(index int) (FeatureFlag, bool) {
+	flag, ok := f.flags[index]
+	return flag, ok
+}
+
+// Indices returns the indices of all the features set within the vector in
+// ascending order.
+func (f *FeatureVector) Indices() []int {
+	indices := make([]int, 0, len(f.flags))
+	for index := range f.flags {
+		indices = append(indices, index)
+	}
+	sort.Ints(indices)
+
+	return indices
+}
+
 // SetFeatureFlag assign flag to the feature.
-func (f *FeatureVector) SetFeatureFlag(name featureName, flag featureFlag) error {
+func (f *FeatureVector) SetFeatureFlag(name FeatureName, flag FeatureFlag) error {
 	position, ok := f.featuresMap[name]
 	if !ok {
 		return errors.Errorf("can't find feature with name: %v", name)
@@ -113,7 +152,16 @@ func (f *FeatureVector) SetFeatureFlag(name featureName, flag featureFlag) error
 // serializedSize returns the number of bytes which is needed to represent
 // feature vector in byte format.
 func (f *FeatureVector) serializedSize() uint16 {
-	return uint16(math.Ceil(float64(flagBitsSize*len(f.flags)) / 8))
+	// As the vector may be sparse, its size is dictated by the highest
+	// index set rather than the number of features.
+	numFeatures := 0
+	for index := range f.flags {
+		if index+1 > numFeatures {
+			numFeatures = index + 1
+		}
+	}
+
+	return uint16(math.Ceil(float64(flagBitsSize*numFeatures) / 8))
 }
 
 // NewFeatureVectorFromReader decodes the feature vector from binary
@@ -124,26 +172,20 @@ func (f *FeatureVector) serializedSize() uint16 {
 // assigned in pairs, so that optional features can later become compulsory.
 func NewFeatureVectorFromReader(r io.Reader) (*FeatureVector, error) {
 	f := &FeatureVector{
-		flags: make(map[int]featureFlag),
+		flags: make(map[int]FeatureFlag),
 	}
 
-	getFlag := func(data []byte, position int) featureFlag {
+	getFlag := func(data []byte, position int) FeatureFlag {
 		byteNumber := uint(position / 8)
 		bitNumber := uint(position % 8)
 
-		return featureFlag((data[byteNumber] >> bitNumber) & flagMask)
+		return FeatureFlag((data[byteNumber] >> bitNumber) & flagMask)
 	}
 
-	// Read the length of the feature vector.
-	var l [2]byte
-	if _, err := io.ReadFull(r, l[:]); err != nil {
-		return nil, err
-	}
-	length := binary.BigEndian.Uint16(l[:])
-
-	// Read the feature vector data.
-	data := make([]byte, length)
-	if _, err := io.ReadFull(r, data[:]); err != nil {
+	// Read the length prefixed feature vector data. As the data is only
+	// used to populate the set of flags, it's never retained.
+	data, err := readVarPayload(r)
+	if err != nil {
 		return nil, err
 	}
 
@@ -173,7 +215,7 @@ func NewFeatureVectorFromReader(r io.Reader) (*FeatureVector, error) {
 // Bits generally assigned in pairs, so that optional features can later become
 // compulsory.
 func (f *FeatureVector) Encode(w io.Writer) error {
-	setFlag := func(data []byte, position int, flag featureFlag) {
+	setFlag := func(data []byte, position int, flag FeatureFlag) {
 		byteNumber := uint(position / 8)
 		bitNumber := uint(position % 8)
 
@@ -251,16 +293,20 @@ func (f *FeatureVector) Compare(f2 *FeatureVector) (*SharedFeatures, error) {
 
 // Copy generate new distinct instance of the feature vector.
 func (f *FeatureVector) Copy() *FeatureVector {
-	features := make([]Feature, len(f.featuresMap))
-
+	featuresMap := make(map[FeatureName]int, len(f.featuresMap))
 	for name, index := range f.featuresMap {
-		features[index] = Feature{
-			Name: name,
-			Flag: f.flags[index],
-		}
+		featuresMap[name] = index
+	}
+
+	flags := make(map[int]FeatureFlag, len(f.flags))
+	for index, flag := range f.flags {
+		flags[index] = flag
 	}
 
-	return NewFeatureVector(features)
+	return &FeatureVector{
+		featuresMap: featuresMap,
+		flags:       flags,
+	}
 }
 
 // SharedFeatures is a product of comparison of two features vector which
@@ -278,7 +324,7 @@ func newSharedFeatures(f *FeatureVector) *SharedFeatures {
 // IsActive checks is feature active or not, it might be disabled during
 // comparision with remote feature vector if it was optional and remote peer
 // doesn't support it.
-func (f *SharedFeatures) IsActive(name featureName) bool {
+func (f *SharedFeatures) IsActive(name FeatureName) bool {
 	index, ok := f.featuresMap[name]
 	if !ok {
 		// If we even have no such feature in feature map, than it
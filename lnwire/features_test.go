@@ -146,9 +146,39 @@ func TestFeatureFlagString(t *testing.T) {
 			OptionalFlag.String())
 	}
 
-	fakeFlag := featureFlag(9)
+	fakeFlag := FeatureFlag(9)
 	if fakeFlag.String() != "<unknown>" {
 		t.Fatalf("incorrect string, expected <unknown> got %v",
 			fakeFlag.String())
 	}
 }
+
+// TestIndexedFeatureVector checks that a sparse feature vector is sized by
+// its highest index, and that it survives an encode/decode round trip.
+func TestIndexedFeatureVector(t *testing.T) {
+	t.Parallel()
+
+	f := NewIndexedFeatureVector(map[int]Feature{
+		1: {"first", OptionalFlag},
+		9: {"second", RequiredFlag},
+	})
+	if f.serializedSize() != 3 {
+		t.Fatalf("expected size 3, got %v", f.serializedSize())
+	}
+
+	var b bytes.Buffer
+	if err := f.Encode(&b); err != nil {
+		t.Fatalf("error while encoding feature vector: %v", err)
+	}
+	nf, err := NewFeatureVectorFromReader(&b)
+	if err != nil {
+		t.Fatalf("error while decoding feature vector: %v", err)
+	}
+
+	if !reflect.DeepEqual(nf.Indices(), []int{1, 9}) {
+		t.Fatalf("unexpected indices: %v", nf.Indices())
+	}
+	if flag, ok := nf.Flag(9); !ok || flag != RequiredFlag {
+		t.Fatalf("expected index 9 to be required")
+	}
+}
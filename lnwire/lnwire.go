@@ -4,6 +4,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"math"
 
 	"net"
@@ -19,6 +20,10 @@ import (
 // the wire protocol.
 const MaxSliceLength = 65535
 
+// maxSigsPerMsg is the maximum number of signatures a single message can
+// carry, as each signature occupies 64 bytes of the payload.
+const maxSigsPerMsg = MaxMessagePayload / 64
+
 // PkScript is simple type definition which represents a raw serialized public
 // key script.
 type PkScript []byte
@@ -31,8 +36,13 @@ const (
 	tcp4Addr  addressType = 1
 	tcp6Addr  addressType = 2
 	onionAddr addressType = 3
+	dnsAddr   addressType = 5
 )
 
+// maxHostnameLength is the maximum length of a hostname within a
+// DNSHostnameAddress, as its length is encoded as a single byte.
+const maxHostnameLength = 255
+
 // writeElement is a one-stop shop to write the big endian representation of
 // any element which is to be serialized for the wire protocol. The passed
 // io.Writer should be backed by an appropriately sized byte slice, or be able
@@ -55,13 +65,25 @@ func writeElement(w io.Writer, element interface{}) error {
 		if _, err := w.Write(b[:]); err != nil {
 			return err
 		}
+	case ChanUpdateMsgFlags:
+		var b [1]byte
+		b[0] = uint8(e)
+		if _, err := w.Write(b[:]); err != nil {
+			return err
+		}
+	case ChanUpdateChanFlags:
+		var b [1]byte
+		b[0] = uint8(e)
+		if _, err := w.Write(b[:]); err != nil {
+			return err
+		}
 	case ErrorCode:
 		var b [2]byte
 		binary.BigEndian.PutUint16(b[:], uint16(e))
 		if _, err := w.Write(b[:]); err != nil {
 			return err
 		}
-	case MilliSatoshi:
+	case MilliAtom:
 		var b [8]byte
 		binary.BigEndian.PutUint64(b[:], uint64(e))
 		if _, err := w.Write(b[:]); err != nil {
@@ -97,7 +119,20 @@ func writeElement(w io.Writer, element interface{}) error {
 		if _, err := w.Write(b[:]); err != nil {
 			return err
 		}
-	case []*btcec.Signature:
+	case *Sig:
+		if e == nil {
+			return fmt.Errorf("cannot write nil signature")
+		}
+
+		if _, err := w.Write(e.raw[:]); err != nil {
+			return err
+		}
+	case []*Sig:
+		if len(e) > maxSigsPerMsg {
+			return fmt.Errorf("%v signatures exceeds maximum of %v",
+				len(e), maxSigsPerMsg)
+		}
+
 		var b [2]byte
 		numSigs := uint16(len(e))
 		binary.BigEndian.PutUint16(b[:], numSigs)
@@ -280,9 +315,60 @@ func writeElement(w io.Writer, element interface{}) error {
 		if _, err := w.Write(port[:]); err != nil {
 			return err
 		}
+	case *DNSHostnameAddress:
+		if e == nil {
+			return fmt.Errorf("cannot write nil DNSHostnameAddress")
+		}
+
+		if len(e.Hostname) == 0 || len(e.Hostname) > maxHostnameLength {
+			return fmt.Errorf("invalid hostname length: %v",
+				len(e.Hostname))
+		}
+
+		var descriptor [2]byte
+		descriptor[0] = uint8(dnsAddr)
+		descriptor[1] = uint8(len(e.Hostname))
+		if _, err := w.Write(descriptor[:]); err != nil {
+			return err
+		}
+		if _, err := w.Write([]byte(e.Hostname)); err != nil {
+			return err
+		}
+
+		var port [2]byte
+		binary.BigEndian.PutUint16(port[:], e.Port)
+		if _, err := w.Write(port[:]); err != nil {
+			return err
+		}
+	case *OpaqueAddrs:
+		if e == nil {
+			return fmt.Errorf("cannot write nil OpaqueAddrs")
+		}
+
+		if _, err := w.Write(e.Payload); err != nil {
+			return err
+		}
 	case []net.Addr:
+		// As a set of opaque addresses occupies a single entry within
+		// the slice, the number of addresses on the wire needs to be
+		// tallied up.
+		var numAddrs int
+		for _, address := range e {
+			if opaque, ok := address.(*OpaqueAddrs); ok {
+				numAddrs += int(opaque.NumAddrs)
+				continue
+			}
+			numAddrs++
+		}
+
+		// We'll never send more addresses than we'd accept.
+		if numAddrs > MaxNodeAnnAddresses {
+			return fmt.Errorf("%v addresses exceeds maximum of %v",
+				numAddrs, MaxNodeAnnAddresses)
+		}
+
 		// Write out the number of addresses.
-		if err := writeElement(w, uint16(len(e))); err != nil {
+		if err := writeElement(w, uint16(numAddrs)); err != nil {
 			return err
 		}
 
@@ -330,60 +416,90 @@ func writeElements(w io.Writer, elements ...interface{}) error {
 	return nil
 }
 
+// readBytes returns the next n bytes read from r. If r is a msgBuffer, then
+// the bytes are sliced directly out of the underlying message buffer rather
+// than being copied, otherwise a new slice is allocated to read them into.
+//
+// NOTE: As the returned slice may reference a buffer owned by the caller of
+// ReadMessageBytes, it MUST NOT be retained within the decoded message.
+func readBytes(r io.Reader, n int) ([]byte, error) {
+	if mb, ok := r.(*msgBuffer); ok {
+		return mb.next(n)
+	}
+
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
 // readElement is a one-stop utility function to deserialize any datastructure
 // encoded using the serialization format of lnwire.
 func readElement(r io.Reader, element interface{}) error {
-	var err error
 	switch e := element.(type) {
 	case *uint8:
-		var b [1]uint8
-		if _, err := r.Read(b[:]); err != nil {
+		b, err := readBytes(r, 1)
+		if err != nil {
 			return err
 		}
 		*e = b[0]
 	case *uint16:
-		var b [2]byte
-		if _, err := io.ReadFull(r, b[:]); err != nil {
+		b, err := readBytes(r, 2)
+		if err != nil {
+			return err
+		}
+		*e = binary.BigEndian.Uint16(b)
+	case *ChanUpdateMsgFlags:
+		b, err := readBytes(r, 1)
+		if err != nil {
 			return err
 		}
-		*e = binary.BigEndian.Uint16(b[:])
+		*e = ChanUpdateMsgFlags(b[0])
+	case *ChanUpdateChanFlags:
+		b, err := readBytes(r, 1)
+		if err != nil {
+			return err
+		}
+		*e = ChanUpdateChanFlags(b[0])
 	case *ErrorCode:
-		var b [2]byte
-		if _, err := io.ReadFull(r, b[:]); err != nil {
+		b, err := readBytes(r, 2)
+		if err != nil {
 			return err
 		}
-		*e = ErrorCode(binary.BigEndian.Uint16(b[:]))
+		*e = ErrorCode(binary.BigEndian.Uint16(b))
 	case *uint32:
-		var b [4]byte
-		if _, err := io.ReadFull(r, b[:]); err != nil {
+		b, err := readBytes(r, 4)
+		if err != nil {
 			return err
 		}
-		*e = binary.BigEndian.Uint32(b[:])
+		*e = binary.BigEndian.Uint32(b)
 	case *uint64:
-		var b [8]byte
-		if _, err := io.ReadFull(r, b[:]); err != nil {
+		b, err := readBytes(r, 8)
+		if err != nil {
 			return err
 		}
-		*e = binary.BigEndian.Uint64(b[:])
-	case *MilliSatoshi:
-		var b [8]byte
-		if _, err := io.ReadFull(r, b[:]); err != nil {
+		*e = binary.BigEndian.Uint64(b)
+	case *MilliAtom:
+		b, err := readBytes(r, 8)
+		if err != nil {
 			return err
 		}
-		*e = MilliSatoshi(int64(binary.BigEndian.Uint64(b[:])))
+		*e = MilliAtom(int64(binary.BigEndian.Uint64(b)))
 	case *btcutil.Amount:
-		var b [8]byte
-		if _, err := io.ReadFull(r, b[:]); err != nil {
+		b, err := readBytes(r, 8)
+		if err != nil {
 			return err
 		}
-		*e = btcutil.Amount(int64(binary.BigEndian.Uint64(b[:])))
+		*e = btcutil.Amount(int64(binary.BigEndian.Uint64(b)))
 	case **btcec.PublicKey:
-		var b [btcec.PubKeyBytesLenCompressed]byte
-		if _, err = io.ReadFull(r, b[:]); err != nil {
+		b, err := readBytes(r, btcec.PubKeyBytesLenCompressed)
+		if err != nil {
 			return err
 		}
 
-		pubKey, err := btcec.ParsePubKey(b[:], btcec.S256())
+		pubKey, err := btcec.ParsePubKey(b, btcec.S256())
 		if err != nil {
 			return err
 		}
@@ -396,16 +512,33 @@ func readElement(r io.Reader, element interface{}) error {
 
 		*e = f
 
-	case *[]*btcec.Signature:
-		var l [2]byte
-		if _, err := io.ReadFull(r, l[:]); err != nil {
+	case **Sig:
+		b, err := readBytes(r, 64)
+		if err != nil {
+			return err
+		}
+
+		// The signature is only parsed once its parsed form is
+		// needed.
+		sig := &Sig{}
+		copy(sig.raw[:], b)
+		*e = sig
+	case *[]*Sig:
+		var numSigs uint16
+		if err := readElement(r, &numSigs); err != nil {
 			return err
 		}
-		numSigs := binary.BigEndian.Uint16(l[:])
 
-		var sigs []*btcec.Signature
+		// The number of signatures must be checked before allocating
+		// the slice holding them.
+		if numSigs > maxSigsPerMsg {
+			return fmt.Errorf("%v signatures exceeds maximum of %v",
+				numSigs, maxSigsPerMsg)
+		}
+
+		var sigs []*Sig
 		if numSigs > 0 {
-			sigs = make([]*btcec.Signature, numSigs)
+			sigs = make([]*Sig, numSigs)
 			for i := 0; i < int(numSigs); i++ {
 				if err := readElement(r, &sigs[i]); err != nil {
 					return err
@@ -416,62 +549,54 @@ func readElement(r io.Reader, element interface{}) error {
 		*e = sigs
 
 	case **btcec.Signature:
-		var b [64]byte
-		if _, err := io.ReadFull(r, b[:]); err != nil {
+		b, err := readBytes(r, 64)
+		if err != nil {
 			return err
 		}
-		err = deserializeSigFromWire(e, b)
-		if err != nil {
+
+		var sig [64]byte
+		copy(sig[:], b)
+		if err := deserializeSigFromWire(e, sig); err != nil {
 			return err
 		}
 	case *OpaqueReason:
-		var l [2]byte
-		if _, err := io.ReadFull(r, l[:]); err != nil {
+		b, err := readVarPayload(r)
+		if err != nil {
 			return err
 		}
-		reasonLen := binary.BigEndian.Uint16(l[:])
 
-		*e = OpaqueReason(make([]byte, reasonLen))
-		if _, err := io.ReadFull(r, *e); err != nil {
-			return err
-		}
+		*e = OpaqueReason(make([]byte, len(b)))
+		copy(*e, b)
 	case *ErrorData:
-		var l [2]byte
-		if _, err := io.ReadFull(r, l[:]); err != nil {
+		b, err := readVarPayload(r)
+		if err != nil {
 			return err
 		}
-		errorLen := binary.BigEndian.Uint16(l[:])
 
-		*e = ErrorData(make([]byte, errorLen))
-		if _, err := io.ReadFull(r, *e); err != nil {
-			return err
-		}
+		*e = ErrorData(make([]byte, len(b)))
+		copy(*e, b)
 	case *PingPayload:
-		var l [2]byte
-		if _, err := io.ReadFull(r, l[:]); err != nil {
+		b, err := readVarPayload(r)
+		if err != nil {
 			return err
 		}
-		pingLen := binary.BigEndian.Uint16(l[:])
 
-		*e = PingPayload(make([]byte, pingLen))
-		if _, err := io.ReadFull(r, *e); err != nil {
-			return err
-		}
+		*e = PingPayload(make([]byte, len(b)))
+		copy(*e, b)
 	case *PongPayload:
-		var l [2]byte
-		if _, err := io.ReadFull(r, l[:]); err != nil {
+		b, err := readVarPayload(r)
+		if err != nil {
 			return err
 		}
-		pongLen := binary.BigEndian.Uint16(l[:])
 
-		*e = PongPayload(make([]byte, pongLen))
-		if _, err := io.ReadFull(r, *e); err != nil {
-			return err
-		}
+		*e = PongPayload(make([]byte, len(b)))
+		copy(*e, b)
 	case []byte:
-		if _, err := io.ReadFull(r, e); err != nil {
+		b, err := readBytes(r, len(e))
+		if err != nil {
 			return err
 		}
+		copy(e, b)
 	case *PkScript:
 		pkScript, err := wire.ReadVarBytes(r, 0, 34, "pkscript")
 		if err != nil {
@@ -479,24 +604,17 @@ func readElement(r io.Reader, element interface{}) error {
 		}
 		*e = pkScript
 	case *wire.OutPoint:
-		var h [32]byte
-		if _, err = io.ReadFull(r, h[:]); err != nil {
-			return err
-		}
-		hash, err := chainhash.NewHash(h[:])
+		b, err := readBytes(r, chainhash.HashSize+2)
 		if err != nil {
 			return err
 		}
 
-		var idxBytes [2]byte
-		_, err = io.ReadFull(r, idxBytes[:])
-		if err != nil {
-			return err
-		}
-		index := binary.BigEndian.Uint16(idxBytes[:])
+		var hash chainhash.Hash
+		copy(hash[:], b[:chainhash.HashSize])
+		index := binary.BigEndian.Uint16(b[chainhash.HashSize:])
 
 		*e = wire.OutPoint{
-			Hash:  *hash,
+			Hash:  hash,
 			Index: uint32(index),
 		}
 	case *FailCode:
@@ -504,69 +622,120 @@ func readElement(r io.Reader, element interface{}) error {
 			return err
 		}
 	case *ChannelID:
-		if _, err := io.ReadFull(r, e[:]); err != nil {
+		b, err := readBytes(r, len(e))
+		if err != nil {
 			return err
 		}
+		copy(e[:], b)
 
 	case *ShortChannelID:
-		var blockHeight [4]byte
-		if _, err = io.ReadFull(r, blockHeight[1:]); err != nil {
-			return err
-		}
-
-		var txIndex [4]byte
-		if _, err = io.ReadFull(r, txIndex[1:]); err != nil {
-			return err
-		}
-
-		var txPosition [2]byte
-		if _, err = io.ReadFull(r, txPosition[:]); err != nil {
+		b, err := readBytes(r, 8)
+		if err != nil {
 			return err
 		}
 
+		// The block height and transaction index are each encoded
+		// using 3 bytes, followed by the 2-byte transaction position.
 		*e = ShortChannelID{
-			BlockHeight: binary.BigEndian.Uint32(blockHeight[:]),
-			TxIndex:     binary.BigEndian.Uint32(txIndex[:]),
-			TxPosition:  binary.BigEndian.Uint16(txPosition[:]),
+			BlockHeight: uint32(b[0])<<16 | uint32(b[1])<<8 |
+				uint32(b[2]),
+			TxIndex: uint32(b[3])<<16 | uint32(b[4])<<8 |
+				uint32(b[5]),
+			TxPosition: binary.BigEndian.Uint16(b[6:]),
 		}
 
 	case *[]net.Addr:
-		var numAddrsBytes [2]byte
-		if _, err = io.ReadFull(r, numAddrsBytes[:]); err != nil {
+		var numAddrs uint16
+		if err := readElement(r, &numAddrs); err != nil {
 			return err
 		}
 
-		numAddrs := binary.BigEndian.Uint16(numAddrsBytes[:])
+		// The number of addresses must be checked before allocating
+		// the slice holding them.
+		if numAddrs > MaxNodeAnnAddresses {
+			return fmt.Errorf("%v addresses exceeds maximum of %v",
+				numAddrs, MaxNodeAnnAddresses)
+		}
+
 		addresses := make([]net.Addr, 0, numAddrs)
 
+	addrLoop:
 		for i := 0; i < int(numAddrs); i++ {
-			var descriptor [1]byte
-			if _, err = io.ReadFull(r, descriptor[:]); err != nil {
+			var descriptor uint8
+			if err := readElement(r, &descriptor); err != nil {
 				return err
 			}
 
-			address := &net.TCPAddr{}
-			switch descriptor[0] {
-			case 1:
-				var ip [4]byte
-				if _, err = io.ReadFull(r, ip[:]); err != nil {
+			var address net.Addr
+			switch addressType(descriptor) {
+			case tcp4Addr, tcp6Addr:
+				ipLen := 4
+				if addressType(descriptor) == tcp6Addr {
+					ipLen = 16
+				}
+
+				ip, err := readBytes(r, ipLen)
+				if err != nil {
 					return err
 				}
-				address.IP = (net.IP)(ip[:])
-			case 2:
-				var ip [16]byte
-				if _, err = io.ReadFull(r, ip[:]); err != nil {
+
+				var port uint16
+				if err := readElement(r, &port); err != nil {
 					return err
 				}
-				address.IP = (net.IP)(ip[:])
-			}
 
-			var port [2]byte
-			if _, err = io.ReadFull(r, port[:]); err != nil {
-				return err
+				tcpAddr := &net.TCPAddr{
+					IP:   make(net.IP, ipLen),
+					Port: int(port),
+				}
+				copy(tcpAddr.IP, ip)
+				address = tcpAddr
+
+			case dnsAddr:
+				var hostnameLen uint8
+				if err := readElement(r, &hostnameLen); err != nil {
+					return err
+				}
+				if hostnameLen == 0 {
+					return fmt.Errorf("empty hostname")
+				}
+
+				hostname, err := readBytes(r, int(hostnameLen))
+				if err != nil {
+					return err
+				}
+
+				var port uint16
+				if err := readElement(r, &port); err != nil {
+					return err
+				}
+
+				address = &DNSHostnameAddress{
+					Hostname: string(hostname),
+					Port:     port,
+				}
+
+			// As we're unable to determine the length of an unknown
+			// address type, the remainder of the address list is
+			// retained as is. This relies on the address list being
+			// the final field of the message being decoded.
+			default:
+				rest, err := ioutil.ReadAll(r)
+				if err != nil {
+					return err
+				}
+
+				payload := make([]byte, 0, len(rest)+1)
+				payload = append(payload, descriptor)
+				payload = append(payload, rest...)
+
+				addresses = append(addresses, &OpaqueAddrs{
+					NumAddrs: numAddrs - uint16(i),
+					Payload:  payload,
+				})
+				break addrLoop
 			}
 
-			address.Port = int(binary.BigEndian.Uint16(port[:]))
 			addresses = append(addresses, address)
 		}
 		*e = addresses
@@ -580,17 +749,24 @@ func readElement(r io.Reader, element interface{}) error {
 			return err
 		}
 	case *DeliveryAddress:
-		var addrLen [2]byte
-		if _, err = io.ReadFull(r, addrLen[:]); err != nil {
+		var length uint16
+		if err := readElement(r, &length); err != nil {
 			return err
 		}
-		length := binary.BigEndian.Uint16(addrLen[:])
 
-		var addrBytes [34]byte
-		if _, err = io.ReadFull(r, addrBytes[:length]); err != nil {
+		// As we reject any delivery addresses larger than 34 bytes,
+		// the length must be checked before reading the address.
+		if length > 34 {
+			return fmt.Errorf("delivery address of %v bytes "+
+				"exceeds maximum of 34 bytes", length)
+		}
+
+		addr, err := readBytes(r, int(length))
+		if err != nil {
 			return err
 		}
-		*e = addrBytes[:length]
+		*e = make(DeliveryAddress, length)
+		copy(*e, addr)
 	default:
 		return fmt.Errorf("Unknown type in readElement: %T", e)
 	}
@@ -598,6 +774,17 @@ func readElement(r io.Reader, element interface{}) error {
 	return nil
 }
 
+// readVarPayload reads a variable length payload from r which is prefixed by
+// its 2-byte length.
+func readVarPayload(r io.Reader) ([]byte, error) {
+	var length uint16
+	if err := readElement(r, &length); err != nil {
+		return nil, err
+	}
+
+	return readBytes(r, int(length))
+}
+
 // readElements deserializes a variable number of elements into the passed
 // io.Reader, with each element being deserialized according to the readElement
 // function.
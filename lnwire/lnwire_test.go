@@ -39,7 +39,8 @@ var (
 	// TODO(roasbeef): randomly generate from three types of addrs
 	a1        = &net.TCPAddr{IP: (net.IP)([]byte{0x7f, 0x0, 0x0, 0x1}), Port: 8333}
 	a2, _     = net.ResolveTCPAddr("tcp", "[2001:db8:85a3:0:0:8a2e:370:7334]:80")
-	testAddrs = []net.Addr{a1, a2}
+	a3        = &DNSHostnameAddress{Hostname: "node.example.com", Port: 9735}
+	testAddrs = []net.Addr{a1, a2, a3}
 )
 
 func randPubKey() (*btcec.PublicKey, error) {
@@ -56,7 +57,7 @@ func randFeatureVector(r *rand.Rand) *FeatureVector {
 	features := make([]Feature, numFeatures)
 	for i := int32(0); i < numFeatures; i++ {
 		features[i] = Feature{
-			Flag: featureFlag(rand.Int31n(2) + 1),
+			Flag: FeatureFlag(rand.Int31n(2) + 1),
 		}
 	}
 
@@ -79,13 +80,43 @@ func TestMaxOutPointIndex(t *testing.T) {
 func TestEmptyMessageUnknownType(t *testing.T) {
 	t.Parallel()
 
-	fakeType := MessageType(math.MaxUint16)
+	fakeType := CustomTypeStart - 1
 	if _, err := makeEmptyMessage(fakeType); err == nil {
 		t.Fatalf("should not be able to make an empty message of an " +
 			"unknown type")
 	}
 }
 
+// TestReadUnknownMessage checks that reading a message of an unknown type
+// returns an UnknownMessage error, which signals whether the message is
+// optional based on the parity of its type.
+func TestReadUnknownMessage(t *testing.T) {
+	t.Parallel()
+
+	for _, msgType := range []MessageType{
+		CustomTypeStart - 1, CustomTypeStart - 2,
+	} {
+		var b bytes.Buffer
+		if err := writeElements(&b, uint16(msgType), []byte{0x01}); err != nil {
+			t.Fatalf("unable to write message: %v", err)
+		}
+
+		_, err := ReadMessageBytes(b.Bytes(), 0)
+		unknownErr, ok := err.(*UnknownMessage)
+		if !ok {
+			t.Fatalf("expected UnknownMessage error, got %v", err)
+		}
+		if unknownErr.MessageType() != msgType {
+			t.Fatalf("expected message type %d, got %d", msgType,
+				unknownErr.MessageType())
+		}
+		if unknownErr.IsOdd() != (msgType%2 == 1) {
+			t.Fatalf("unexpected parity for message type %d",
+				msgType)
+		}
+	}
+}
+
 // TestLightningWireProtocol uses the testing/quick package to create a series
 // of fuzz tests to attempt to break a primary scenario which is implemented as
 // property based testing scenario.
@@ -116,6 +147,20 @@ func TestLightningWireProtocol(t *testing.T) {
 			return false
 		}
 
+		// We'll also decode a copy of the serialized message directly
+		// from its buffer. Once decoded, the buffer is scribbled over
+		// to ensure the message doesn't reference it.
+		rawMsg := make([]byte, b.Len())
+		copy(rawMsg, b.Bytes())
+		bufMsg, err := ReadMessageBytes(rawMsg, 0)
+		if err != nil {
+			t.Fatalf("unable to read msg from bytes: %v", err)
+			return false
+		}
+		for i := range rawMsg {
+			rawMsg[i] = 0xff
+		}
+
 		// Finally, we'll deserialize the message from the written
 		// buffer, and finally assert that the messages are equal.
 		newMsg, err := ReadMessage(&b, 0)
@@ -128,6 +173,12 @@ func TestLightningWireProtocol(t *testing.T) {
 				"vs %v", spew.Sdump(msg), spew.Sdump(newMsg))
 			return false
 		}
+		if !reflect.DeepEqual(msg, bufMsg) {
+			t.Fatalf("messages don't match after decoding from "+
+				"bytes: %v vs %v", spew.Sdump(msg),
+				spew.Sdump(bufMsg))
+			return false
+		}
 
 		return true
 	}
@@ -150,11 +201,11 @@ func TestLightningWireProtocol(t *testing.T) {
 		MsgOpenChannel: func(v []reflect.Value, r *rand.Rand) {
 			req := OpenChannel{
 				FundingAmount:    btcutil.Amount(r.Int63()),
-				PushAmount:       MilliSatoshi(r.Int63()),
+				PushAmount:       MilliAtom(r.Int63()),
 				DustLimit:        btcutil.Amount(r.Int63()),
-				MaxValueInFlight: MilliSatoshi(r.Int63()),
+				MaxValueInFlight: MilliAtom(r.Int63()),
 				ChannelReserve:   btcutil.Amount(r.Int63()),
-				HtlcMinimum:      MilliSatoshi(r.Int31()),
+				HtlcMinimum:      MilliAtom(r.Int31()),
 				FeePerKiloWeight: uint32(r.Int63()),
 				CsvDelay:         uint16(r.Int31()),
 				MaxAcceptedHTLCs: uint16(r.Int31()),
@@ -198,15 +249,24 @@ func TestLightningWireProtocol(t *testing.T) {
 				return
 			}
 
+			if r.Intn(2) == 0 {
+				script := make(DeliveryAddress, 22)
+				if _, err := r.Read(script); err != nil {
+					t.Fatalf("unable to generate script: %v", err)
+					return
+				}
+				req.UpfrontShutdownScript = script
+			}
+
 			v[0] = reflect.ValueOf(req)
 		},
 		MsgAcceptChannel: func(v []reflect.Value, r *rand.Rand) {
 			req := AcceptChannel{
 				DustLimit:        btcutil.Amount(r.Int63()),
-				MaxValueInFlight: MilliSatoshi(r.Int63()),
+				MaxValueInFlight: MilliAtom(r.Int63()),
 				ChannelReserve:   btcutil.Amount(r.Int63()),
 				MinAcceptDepth:   uint32(r.Int31()),
-				HtlcMinimum:      MilliSatoshi(r.Int31()),
+				HtlcMinimum:      MilliAtom(r.Int31()),
 				CsvDelay:         uint16(r.Int31()),
 				MaxAcceptedHTLCs: uint16(r.Int31()),
 			}
@@ -243,6 +303,15 @@ func TestLightningWireProtocol(t *testing.T) {
 				return
 			}
 
+			if r.Intn(2) == 0 {
+				script := make(DeliveryAddress, 22)
+				if _, err := r.Read(script); err != nil {
+					t.Fatalf("unable to generate script: %v", err)
+					return
+				}
+				req.UpfrontShutdownScript = script
+			}
+
 			v[0] = reflect.ValueOf(req)
 		},
 		MsgFundingCreated: func(v []reflect.Value, r *rand.Rand) {
@@ -314,17 +383,25 @@ func TestLightningWireProtocol(t *testing.T) {
 				t.Fatalf("unable to generate chan id: %v", err)
 				return
 			}
-			req.CommitSig = testSig
+
+			// The signatures are created from their wire format, as
+			// decoded signatures aren't parsed until needed.
+			var rawSig [64]byte
+			if err := serializeSigToWire(&rawSig, testSig); err != nil {
+				t.Fatalf("unable to serialize sig: %v", err)
+				return
+			}
+			req.CommitSig = NewSigFromWire(rawSig)
 
 			// Only create the slice if there will be any signatures
 			// in it to prevent false positive test failures due to
 			// an empty slice versus a nil slice.
 			numSigs := uint16(r.Int31n(1020))
 			if numSigs > 0 {
-				req.HtlcSigs = make([]*btcec.Signature, numSigs)
+				req.HtlcSigs = make([]*Sig, numSigs)
 			}
 			for i := 0; i < int(numSigs); i++ {
-				req.HtlcSigs[i] = testSig
+				req.HtlcSigs[i] = NewSigFromWire(rawSig)
 			}
 
 			v[0] = reflect.ValueOf(*req)
@@ -348,6 +425,28 @@ func TestLightningWireProtocol(t *testing.T) {
 
 			v[0] = reflect.ValueOf(*req)
 		},
+		MsgChannelReestablish: func(v []reflect.Value, r *rand.Rand) {
+			req := ChannelReestablish{
+				NextLocalCommitHeight:  uint64(r.Int63()),
+				RemoteCommitTailHeight: uint64(r.Int63()),
+			}
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate chan id: %v", err)
+				return
+			}
+			if _, err := r.Read(req.LastRemoteCommitSecret[:]); err != nil {
+				t.Fatalf("unable to generate bytes: %v", err)
+				return
+			}
+			var err error
+			req.LocalUnrevokedCommitPoint, err = randPubKey()
+			if err != nil {
+				t.Fatalf("unable to generate key: %v", err)
+				return
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
 		MsgChannelAnnouncement: func(v []reflect.Value, r *rand.Rand) {
 			req := ChannelAnnouncement{
 				ShortChannelID: NewShortChanIDFromInt(uint64(r.Int63())),
@@ -422,12 +521,19 @@ func TestLightningWireProtocol(t *testing.T) {
 				Signature:       testSig,
 				ShortChannelID:  NewShortChanIDFromInt(uint64(r.Int63())),
 				Timestamp:       uint32(r.Int31()),
-				Flags:           uint16(r.Int31()),
+				MessageFlags:    ChanUpdateMsgFlags(r.Int31()),
+				ChannelFlags:    ChanUpdateChanFlags(r.Int31()),
 				TimeLockDelta:   uint16(r.Int31()),
-				HtlcMinimumMsat: MilliSatoshi(r.Int63()),
+				HtlcMinimumMsat: MilliAtom(r.Int63()),
 				BaseFee:         uint32(r.Int31()),
 				FeeRate:         uint32(r.Int31()),
 			}
+
+			// The max HTLC field is only encoded if its presence
+			// is signalled within the message flags.
+			if req.MessageFlags.HasMaxHtlc() {
+				req.HtlcMaximumMsat = MilliAtom(r.Int63())
+			}
 			if _, err := r.Read(req.ChainHash[:]); err != nil {
 				t.Fatalf("unable to generate chain hash: %v", err)
 				return
@@ -446,6 +552,60 @@ func TestLightningWireProtocol(t *testing.T) {
 				return
 			}
 
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgQueryChannelRange: func(v []reflect.Value, r *rand.Rand) {
+			req := QueryChannelRange{
+				FirstBlockHeight: uint32(r.Int31()),
+				NumBlocks:        uint32(r.Int31()),
+				QueryOptions:     QueryOptions(r.Int63n(4)),
+			}
+			if _, err := r.Read(req.ChainHash[:]); err != nil {
+				t.Fatalf("unable to generate chain hash: %v", err)
+				return
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgReplyChannelRange: func(v []reflect.Value, r *rand.Rand) {
+			req := ReplyChannelRange{
+				FirstBlockHeight: uint32(r.Int31()),
+				NumBlocks:        uint32(r.Int31()),
+				Complete:         uint8(r.Int31n(2)),
+				EncodingType:     ShortChanIDEncoding(r.Int31n(2)),
+			}
+			if _, err := r.Read(req.ChainHash[:]); err != nil {
+				t.Fatalf("unable to generate chain hash: %v", err)
+				return
+			}
+
+			numIDs := int(r.Int31n(500))
+			for i := 0; i < numIDs; i++ {
+				chanID := NewShortChanIDFromInt(uint64(r.Int63()))
+				req.ShortChanIDs = append(req.ShortChanIDs, chanID)
+			}
+
+			// The timestamps and checksums are optional, so we'll
+			// only include them half of the time.
+			if r.Int31n(2) == 0 {
+				req.Timestamps = make(
+					[]ChanUpdateTimestamps, numIDs,
+				)
+				for i := range req.Timestamps {
+					req.Timestamps[i].Timestamp1 = r.Uint32()
+					req.Timestamps[i].Timestamp2 = r.Uint32()
+				}
+			}
+			if r.Int31n(2) == 0 {
+				req.Checksums = make(
+					[]ChanUpdateChecksums, numIDs,
+				)
+				for i := range req.Checksums {
+					req.Checksums[i].Checksum1 = r.Uint32()
+					req.Checksums[i].Checksum2 = r.Uint32()
+				}
+			}
+
 			v[0] = reflect.ValueOf(req)
 		},
 	}
@@ -555,6 +715,12 @@ func TestLightningWireProtocol(t *testing.T) {
 				return mainScenario(&m)
 			},
 		},
+		{
+			msgType: MsgChannelReestablish,
+			scenario: func(m ChannelReestablish) bool {
+				return mainScenario(&m)
+			},
+		},
 		{
 			msgType: MsgUpdateFee,
 			scenario: func(m UpdateFee) bool {
@@ -592,6 +758,18 @@ func TestLightningWireProtocol(t *testing.T) {
 				return mainScenario(&m)
 			},
 		},
+		{
+			msgType: MsgQueryChannelRange,
+			scenario: func(m QueryChannelRange) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgReplyChannelRange,
+			scenario: func(m ReplyChannelRange) bool {
+				return mainScenario(&m)
+			},
+		},
 	}
 	for _, test := range tests {
 		var config *quick.Config
@@ -613,3 +791,97 @@ func TestLightningWireProtocol(t *testing.T) {
 	}
 
 }
+
+// TestDecodeCountLimits checks that messages carrying more elements than
+// we're willing to accept are rejected while being decoded, before the
+// elements are allocated.
+func TestDecodeCountLimits(t *testing.T) {
+	t.Parallel()
+
+	var chanID ChannelID
+	pubKey, err := randPubKey()
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+
+	// A NodeAnnouncement may only carry a limited number of addresses.
+	var nodeAnn bytes.Buffer
+	err = writeElements(&nodeAnn,
+		testSig,
+		NewFeatureVector(nil),
+		uint32(0),
+		pubKey,
+		RGB{},
+		make([]byte, 32),
+		uint16(MaxNodeAnnAddresses+1),
+	)
+	if err != nil {
+		t.Fatalf("unable to encode node announcement: %v", err)
+	}
+
+	// A CommitSig may only carry as many signatures as fit within a
+	// single payload.
+	var commitSig bytes.Buffer
+	err = writeElements(&commitSig,
+		chanID,
+		make([]byte, 64),
+		uint16(maxSigsPerMsg+1),
+	)
+	if err != nil {
+		t.Fatalf("unable to encode commit sig: %v", err)
+	}
+
+	// A ReplyChannelRange may only carry a limited number of short
+	// channel ID's, even using the plain encoding.
+	rawIDs := make([]byte, 1+(MaxShortChanIDsPerReply+1)*8)
+	var reply bytes.Buffer
+	err = writeElements(&reply,
+		shaHash1[:],
+		uint32(0),
+		uint32(1),
+		uint8(1),
+		uint16(len(rawIDs)),
+		rawIDs,
+	)
+	if err != nil {
+		t.Fatalf("unable to encode reply: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		msg     Message
+		payload []byte
+	}{
+		{
+			name:    "node announcement addresses",
+			msg:     &NodeAnnouncement{},
+			payload: nodeAnn.Bytes(),
+		},
+		{
+			name:    "commit sig htlc sigs",
+			msg:     &CommitSig{},
+			payload: commitSig.Bytes(),
+		},
+		{
+			name:    "reply channel range short channel ids",
+			msg:     &ReplyChannelRange{},
+			payload: reply.Bytes(),
+		},
+	}
+	for _, test := range tests {
+		err := test.msg.Decode(bytes.NewReader(test.payload), 0)
+		if err == nil {
+			t.Fatalf("%s: expected message to be rejected",
+				test.name)
+		}
+	}
+
+	// We should never send a message that we'd reject ourselves.
+	addrs := make([]net.Addr, MaxNodeAnnAddresses+1)
+	for i := range addrs {
+		addrs[i] = testAddrs[0]
+	}
+	if err := writeElement(&bytes.Buffer{}, addrs); err == nil {
+		t.Fatalf("expected too many addresses to be rejected")
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
+	"sync"
 )
 
 // MaxMessagePayload is the maximum bytes a message can be regardless of other
@@ -40,11 +41,14 @@ const (
 	MsgCommitSig                           = 132
 	MsgRevokeAndAck                        = 133
 	MsgUpdateFailMalformedHTLC             = 135
+	MsgChannelReestablish                  = 136
 	MsgUpdateFee                           = 137
 	MsgChannelAnnouncement                 = 256
 	MsgNodeAnnouncement                    = 257
 	MsgChannelUpdate                       = 258
 	MsgAnnounceSignatures                  = 259
+	MsgQueryChannelRange                   = 263
+	MsgReplyChannelRange                   = 264
 )
 
 // String return the string representation of message type.
@@ -78,6 +82,8 @@ func (t MessageType) String() string {
 		return "RevokeAndAck"
 	case MsgUpdateFailMalformedHTLC:
 		return "UpdateFailMalformedHTLC"
+	case MsgChannelReestablish:
+		return "ChannelReestablish"
 	case MsgError:
 		return "Error"
 	case MsgChannelAnnouncement:
@@ -94,7 +100,14 @@ func (t MessageType) String() string {
 		return "Pong"
 	case MsgUpdateFee:
 		return "UpdateFee"
+	case MsgQueryChannelRange:
+		return "QueryChannelRange"
+	case MsgReplyChannelRange:
+		return "ReplyChannelRange"
 	default:
+		if t >= CustomTypeStart {
+			return fmt.Sprintf("Custom(%d)", uint16(t))
+		}
 		return "<unknown>"
 	}
 }
@@ -109,8 +122,21 @@ type UnknownMessage struct {
 //
 // This is part of the error interface.
 func (u *UnknownMessage) Error() string {
-	return fmt.Sprintf("unable to parse message of unknown type: %v",
-		u.messageType)
+	return fmt.Sprintf("unable to parse message of unknown type: %d",
+		uint16(u.messageType))
+}
+
+// MessageType returns the type of the unknown message.
+func (u *UnknownMessage) MessageType() MessageType {
+	return u.messageType
+}
+
+// IsOdd returns true if the type of the unknown message is odd. Following the
+// "it's OK to be odd" rule of BOLT 01, unknown messages with an odd type are
+// optional and should be ignored, while unknown messages with an even type
+// are required, and the connection to the sender should be failed.
+func (u *UnknownMessage) IsOdd() bool {
+	return u.messageType%2 == 1
 }
 
 // Serializable is an interface which defines a lightning wire serializable
@@ -169,6 +195,8 @@ func makeEmptyMessage(msgType MessageType) (Message, error) {
 		msg = &UpdateFee{}
 	case MsgUpdateFailMalformedHTLC:
 		msg = &UpdateFailMalformedHTLC{}
+	case MsgChannelReestablish:
+		msg = &ChannelReestablish{}
 	case MsgError:
 		msg = &Error{}
 	case MsgChannelAnnouncement:
@@ -183,30 +211,57 @@ func makeEmptyMessage(msgType MessageType) (Message, error) {
 		msg = &AnnounceSignatures{}
 	case MsgPong:
 		msg = &Pong{}
+	case MsgQueryChannelRange:
+		msg = &QueryChannelRange{}
+	case MsgReplyChannelRange:
+		msg = &ReplyChannelRange{}
 	default:
-		return nil, fmt.Errorf("unknown message type [%d]", msgType)
+		// Any message within the custom type range is decoded as an
+		// opaque custom message.
+		if msgType >= CustomTypeStart {
+			msg = &Custom{Type: msgType}
+			break
+		}
+		return nil, &UnknownMessage{msgType}
 	}
 
 	return msg, nil
 }
 
+// writeBufferPool is a pool of buffers used to serialize messages. Each buffer
+// is sized to hold the largest possible message, so once the pool is warm,
+// messages are serialized without incurring any allocations.
+var writeBufferPool = &sync.Pool{
+	New: func() interface{} {
+		return bytes.NewBuffer(make([]byte, 0, MaxMessagePayload+2))
+	},
+}
+
 // WriteMessage writes a lightning Message to w including the necessary header
-// information and returns the number of bytes written.
+// information and returns the number of bytes written. The message is
+// serialized into a pooled buffer, and written out with a single call to
+// Write, so w may be message oriented.
 func WriteMessage(w io.Writer, msg Message, pver uint32) (int, error) {
-	totalBytes := 0
+	bw := writeBufferPool.Get().(*bytes.Buffer)
+	defer func() {
+		bw.Reset()
+		writeBufferPool.Put(bw)
+	}()
+
+	// First, we'll write out the message type itself, followed by the
+	// message payload.
+	var mType [2]byte
+	binary.BigEndian.PutUint16(mType[:], uint16(msg.MsgType()))
+	bw.Write(mType[:])
 
-	// Encode the message payload itself into a temporary buffer.
-	// TODO(roasbeef): create buffer pool
-	var bw bytes.Buffer
-	if err := msg.Encode(&bw, pver); err != nil {
-		return totalBytes, err
+	if err := msg.Encode(bw, pver); err != nil {
+		return 0, err
 	}
-	payload := bw.Bytes()
-	lenp := len(payload)
+	lenp := bw.Len() - len(mType)
 
 	// Enforce maximum overall message payload.
 	if lenp > MaxMessagePayload {
-		return totalBytes, fmt.Errorf("message payload is too large - "+
+		return 0, fmt.Errorf("message payload is too large - "+
 			"encoded %d bytes, but maximum message payload is %d bytes",
 			lenp, MaxMessagePayload)
 	}
@@ -214,40 +269,30 @@ func WriteMessage(w io.Writer, msg Message, pver uint32) (int, error) {
 	// Enforce maximum message payload on the message type.
 	mpl := msg.MaxPayloadLength(pver)
 	if uint32(lenp) > mpl {
-		return totalBytes, fmt.Errorf("message payload is too large - "+
+		return 0, fmt.Errorf("message payload is too large - "+
 			"encoded %d bytes, but maximum message payload of "+
 			"type %v is %d bytes", lenp, msg.MsgType(), mpl)
 	}
 
-	// With the initial sanity checks complete, we'll now write out the
-	// message type itself.
-	var mType [2]byte
-	binary.BigEndian.PutUint16(mType[:], uint16(msg.MsgType()))
-	n, err := w.Write(mType[:])
-	totalBytes += n
-	if err != nil {
-		return totalBytes, err
-	}
-
-	// With the message type written, we'll now write out the raw payload
-	// itself.
-	n, err = w.Write(payload)
-	totalBytes += n
-
-	return totalBytes, err
+	// With the sanity checks complete, we'll now write out the entire
+	// message in a single swoop.
+	return w.Write(bw.Bytes())
 }
 
 // ReadMessage reads, validates, and parses the next Lightning message from r
-// for the provided protocol version.
+// for the provided protocol version. If the message is of an unknown type, an
+// *UnknownMessage error is returned without its payload having been read, so
+// r should hold a single message, allowing the caller to skip it if its type
+// is odd.
 func ReadMessage(r io.Reader, pver uint32) (Message, error) {
 	// First, we'll read out the first two bytes of the message so we can
 	// create the proper empty message.
-	var mType [2]byte
-	if _, err := io.ReadFull(r, mType[:]); err != nil {
+	mType, err := readBytes(r, 2)
+	if err != nil {
 		return nil, err
 	}
 
-	msgType := MessageType(binary.BigEndian.Uint16(mType[:]))
+	msgType := MessageType(binary.BigEndian.Uint16(mType))
 
 	// Now that we know the target message type, we can create the proper
 	// empty message type and decode the message into it.
@@ -261,3 +306,62 @@ func ReadMessage(r io.Reader, pver uint32) (Message, error) {
 
 	return msg, nil
 }
+
+// ReadMessageBytes parses the Lightning message serialized within b for the
+// provided protocol version. Unlike ReadMessage, fields are sliced directly out
+// of b as they're decoded rather than being copied into intermediate buffers,
+// which avoids the majority of the allocations incurred while decoding large
+// messages. The returned message never references b, so the caller is free to
+// reuse b (for example by returning it to a buffer pool) once this method
+// returns.
+func ReadMessageBytes(b []byte, pver uint32) (Message, error) {
+	mb := &msgBuffer{buf: b}
+	return ReadMessage(mb, pver)
+}
+
+// msgBuffer is an io.Reader over a fully buffered serialized message. In
+// addition to the io.Reader interface, it allows the decoding logic to slice
+// fields directly out of the buffer via the next method.
+type msgBuffer struct {
+	buf []byte
+	off int
+}
+
+// Read reads the next len(p) bytes from the buffer, or until the buffer is
+// drained.
+//
+// NOTE: Part of the io.Reader interface.
+func (m *msgBuffer) Read(p []byte) (int, error) {
+	if m.off >= len(m.buf) {
+		if len(p) == 0 {
+			return 0, nil
+		}
+		return 0, io.EOF
+	}
+
+	n := copy(p, m.buf[m.off:])
+	m.off += n
+
+	return n, nil
+}
+
+// next returns a slice of the next n bytes of the buffer, advancing the read
+// offset past them. Mirroring io.ReadFull, io.EOF is returned if the buffer is
+// already drained, and io.ErrUnexpectedEOF if fewer than n bytes remain.
+func (m *msgBuffer) next(n int) ([]byte, error) {
+	remaining := len(m.buf) - m.off
+	switch {
+	case n == 0:
+		return m.buf[m.off:m.off], nil
+	case remaining == 0:
+		return nil, io.EOF
+	case remaining < n:
+		m.off = len(m.buf)
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	b := m.buf[m.off : m.off+n]
+	m.off += n
+
+	return b, nil
+}
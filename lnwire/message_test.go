@@ -0,0 +1,312 @@
+package lnwire
+
+import (
+	"bytes"
+	"net"
+	"testing"
+)
+
+// newBenchMessages returns a populated instance of every message type defined
+// within the package, to be used as input for the encoding and decoding
+// benchmarks below.
+func newBenchMessages(tb testing.TB) []Message {
+	pubKey, err := randPubKey()
+	if err != nil {
+		tb.Fatalf("unable to generate pubkey: %v", err)
+	}
+	alias, err := NewNodeAlias("kek")
+	if err != nil {
+		tb.Fatalf("unable to create node alias: %v", err)
+	}
+
+	features := NewFeatureVector([]Feature{
+		{Name: "feature1", Flag: OptionalFlag},
+		{Name: "feature2", Flag: RequiredFlag},
+	})
+
+	var chanID ChannelID
+	copy(chanID[:], revHash[:])
+	shortChanID := NewShortChanIDFromInt(0xdeadbeef)
+
+	commitSig, err := NewSigFromSignature(testSig)
+	if err != nil {
+		tb.Fatalf("unable to create sig: %v", err)
+	}
+	htlcSigs := make([]*Sig, 30)
+	for i := range htlcSigs {
+		htlcSigs[i] = commitSig
+	}
+
+	return []Message{
+		&Init{
+			GlobalFeatures: features,
+			LocalFeatures:  features,
+		},
+		&Error{
+			ChanID: chanID,
+			Data:   ErrorData(bytes.Repeat([]byte{0x01}, 200)),
+		},
+		&Ping{
+			NumPongBytes: 1000,
+			PaddingBytes: PingPayload(bytes.Repeat([]byte{0x01}, 1000)),
+		},
+		&Pong{
+			PongBytes: PongPayload(bytes.Repeat([]byte{0x01}, 1000)),
+		},
+		&OpenChannel{
+			ChainHash:            *shaHash1,
+			PendingChannelID:     revHash,
+			FundingAmount:        100000000,
+			PushAmount:           1000,
+			DustLimit:            573,
+			MaxValueInFlight:     100000000000,
+			ChannelReserve:       1000000,
+			HtlcMinimum:          1,
+			FeePerKiloWeight:     12500,
+			CsvDelay:             144,
+			MaxAcceptedHTLCs:     483,
+			FundingKey:           pubKey,
+			RevocationPoint:      pubKey,
+			PaymentPoint:         pubKey,
+			DelayedPaymentPoint:  pubKey,
+			FirstCommitmentPoint: pubKey,
+			ChannelFlags:         1,
+		},
+		&AcceptChannel{
+			PendingChannelID:     revHash,
+			DustLimit:            573,
+			MaxValueInFlight:     100000000000,
+			ChannelReserve:       1000000,
+			MinAcceptDepth:       3,
+			HtlcMinimum:          1,
+			CsvDelay:             144,
+			MaxAcceptedHTLCs:     483,
+			FundingKey:           pubKey,
+			RevocationPoint:      pubKey,
+			PaymentPoint:         pubKey,
+			DelayedPaymentPoint:  pubKey,
+			FirstCommitmentPoint: pubKey,
+		},
+		&FundingCreated{
+			PendingChannelID: revHash,
+			FundingPoint:     *outpoint1,
+			CommitSig:        testSig,
+		},
+		&FundingSigned{
+			ChanID:    chanID,
+			CommitSig: testSig,
+		},
+		&FundingLocked{
+			ChanID:                 chanID,
+			NextPerCommitmentPoint: pubKey,
+		},
+		&Shutdown{
+			ChannelID: chanID,
+			Address:   DeliveryAddress(bytes.Repeat([]byte{0x01}, 22)),
+		},
+		&ClosingSigned{
+			ChannelID:   chanID,
+			FeeSatoshis: 1000,
+			Signature:   testSig,
+		},
+		&UpdateAddHTLC{
+			ChanID:      chanID,
+			ID:          42,
+			Expiry:      500000,
+			Amount:      100000,
+			PaymentHash: revHash,
+		},
+		&UpdateFufillHTLC{
+			ChanID:          chanID,
+			ID:              42,
+			PaymentPreimage: revHash,
+		},
+		&UpdateFailHTLC{
+			ChanID: chanID,
+			ID:     42,
+			Reason: OpaqueReason(bytes.Repeat([]byte{0x01}, 292)),
+		},
+		&CommitSig{
+			ChanID:    chanID,
+			CommitSig: commitSig,
+			HtlcSigs:  htlcSigs,
+		},
+		&RevokeAndAck{
+			ChanID:            chanID,
+			Revocation:        revHash,
+			NextRevocationKey: pubKey,
+		},
+		&UpdateFee{
+			ChanID:   chanID,
+			FeePerKw: 12500,
+		},
+		&UpdateFailMalformedHTLC{
+			ChanID:       chanID,
+			ID:           42,
+			ShaOnionBlob: revHash,
+			FailureCode:  CodeInvalidOnionVersion,
+		},
+		&ChannelAnnouncement{
+			NodeSig1:       testSig,
+			NodeSig2:       testSig,
+			BitcoinSig1:    testSig,
+			BitcoinSig2:    testSig,
+			Features:       features,
+			ChainHash:      *shaHash1,
+			ShortChannelID: shortChanID,
+			NodeID1:        pubKey,
+			NodeID2:        pubKey,
+			BitcoinKey1:    pubKey,
+			BitcoinKey2:    pubKey,
+		},
+		&NodeAnnouncement{
+			Signature: testSig,
+			Features:  features,
+			Timestamp: 1504000000,
+			NodeID:    pubKey,
+			RGBColor:  RGB{red: 0xff, green: 0x00, blue: 0xff},
+			Alias:     alias,
+			Addresses: []net.Addr{a1, a2},
+		},
+		&ChannelUpdate{
+			Signature:       testSig,
+			ChainHash:       *shaHash1,
+			ShortChannelID:  shortChanID,
+			Timestamp:       1504000000,
+			ChannelFlags:    1,
+			TimeLockDelta:   144,
+			HtlcMinimumMsat: 1000,
+			BaseFee:         1000,
+			FeeRate:         1,
+		},
+		&AnnounceSignatures{
+			ChannelID:        chanID,
+			ShortChannelID:   shortChanID,
+			NodeSignature:    testSig,
+			BitcoinSignature: testSig,
+		},
+	}
+}
+
+// serializeMessage returns the wire serialization of msg, including its type
+// prefix.
+func serializeMessage(tb testing.TB, msg Message) []byte {
+	var b bytes.Buffer
+	if _, err := WriteMessage(&b, msg, 0); err != nil {
+		tb.Fatalf("unable to write %v: %v", msg.MsgType(), err)
+	}
+
+	return b.Bytes()
+}
+
+// TestReadMessageBytesAllocs asserts that decoding a message directly from its
+// serialized bytes incurs fewer allocations than decoding it from a generic
+// io.Reader, for each of the messages which are received in large bursts.
+//
+// NOTE: This test can't be run in parallel, as testing.AllocsPerRun panics if
+// called within a parallel test.
+func TestReadMessageBytesAllocs(t *testing.T) {
+	for _, msg := range newBenchMessages(t) {
+		switch msg.MsgType() {
+		case MsgChannelAnnouncement, MsgNodeAnnouncement,
+			MsgChannelUpdate, MsgCommitSig:
+		default:
+			continue
+		}
+
+		rawMsg := serializeMessage(t, msg)
+
+		readerAllocs := testing.AllocsPerRun(100, func() {
+			if _, err := ReadMessage(bytes.NewReader(rawMsg), 0); err != nil {
+				t.Fatalf("unable to read msg: %v", err)
+			}
+		})
+		bufAllocs := testing.AllocsPerRun(100, func() {
+			if _, err := ReadMessageBytes(rawMsg, 0); err != nil {
+				t.Fatalf("unable to read msg: %v", err)
+			}
+		})
+
+		if bufAllocs >= readerAllocs {
+			t.Fatalf("expected fewer allocations decoding %v from "+
+				"bytes: %v from bytes vs %v from reader",
+				msg.MsgType(), bufAllocs, readerAllocs)
+		}
+	}
+}
+
+// TestReadMessageBytesTruncated asserts that decoding a truncated message
+// directly from its serialized bytes fails rather than panicking.
+func TestReadMessageBytesTruncated(t *testing.T) {
+	t.Parallel()
+
+	for _, msg := range newBenchMessages(t) {
+		rawMsg := serializeMessage(t, msg)
+
+		for i := 0; i < len(rawMsg); i++ {
+			_, err := ReadMessageBytes(rawMsg[:i], 0)
+			if err == nil {
+				t.Fatalf("expected error decoding %v truncated "+
+					"to %v bytes", msg.MsgType(), i)
+			}
+		}
+	}
+}
+
+// BenchmarkWriteMessage benchmarks encoding each of the message types.
+func BenchmarkWriteMessage(b *testing.B) {
+	for _, msg := range newBenchMessages(b) {
+		msg := msg
+		b.Run(msg.MsgType().String(), func(b *testing.B) {
+			var buf bytes.Buffer
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				buf.Reset()
+				if _, err := WriteMessage(&buf, msg, 0); err != nil {
+					b.Fatalf("unable to write msg: %v", err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkReadMessage benchmarks decoding each of the message types from a
+// generic io.Reader.
+func BenchmarkReadMessage(b *testing.B) {
+	for _, msg := range newBenchMessages(b) {
+		rawMsg := serializeMessage(b, msg)
+		b.Run(msg.MsgType().String(), func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(rawMsg)))
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				r := bytes.NewReader(rawMsg)
+				if _, err := ReadMessage(r, 0); err != nil {
+					b.Fatalf("unable to read msg: %v", err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkReadMessageBytes benchmarks decoding each of the message types
+// directly from their serialized bytes.
+func BenchmarkReadMessageBytes(b *testing.B) {
+	for _, msg := range newBenchMessages(b) {
+		rawMsg := serializeMessage(b, msg)
+		b.Run(msg.MsgType().String(), func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(rawMsg)))
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				if _, err := ReadMessageBytes(rawMsg, 0); err != nil {
+					b.Fatalf("unable to read msg: %v", err)
+				}
+			}
+		})
+	}
+}
@@ -10,34 +10,33 @@ import (
 // the other way around.
 const mSatScale int64 = 1000
 
-// MilliSatoshi are the native unit of the Lightning Network. A milli-satoshi
-// is simply 1/1000th of a satoshi. There are 100 milli-satoshis in a single
-// satoshi. Within the network, all HTLC payments are denominated in
-// milli-satoshis. As milli-satoshis aren't deliverable on the native
-// blockchain, before settling to broadcasting, the values are rounded down to
-// the nearest satoshi.
-type MilliSatoshi int64
-
-// NewMSatFromSatoshis creates a new MilliSatoshi instance from a target amount
+// MilliAtom are the native unit of the Lightning Network. A milli-atom is
+// simply 1/1000th of an atom, the smallest unit of the chain's currency.
+// Within the network, all HTLC payments are denominated in milli-atoms. As
+// milli-atoms aren't deliverable on the native blockchain, before settling to
+// broadcasting, the values are rounded down to the nearest atom.
+type MilliAtom int64
+
+// NewMSatFromSatoshis creates a new MilliAtom instance from a target amount
 // of satoshis.
-func NewMSatFromSatoshis(sat btcutil.Amount) MilliSatoshi {
-	return MilliSatoshi(int64(sat) * mSatScale)
+func NewMSatFromSatoshis(sat btcutil.Amount) MilliAtom {
+	return MilliAtom(int64(sat) * mSatScale)
 }
 
-// ToBTC converts the target MilliSatoshi amount to its corresponding value
+// ToBTC converts the target MilliAtom amount to its corresponding value
 // when expressed in BTC.
-func (m MilliSatoshi) ToBTC() float64 {
+func (m MilliAtom) ToBTC() float64 {
 	sat := m.ToSatoshis()
 	return sat.ToBTC()
 }
 
-// ToSatoshis converts the target MilliSatoshi amount to satoshis. Simply, this
+// ToSatoshis converts the target MilliAtom amount to satoshis. Simply, this
 // sheds a factor of 1000 from the mSAT amount in order to convert it to SAT.
-func (m MilliSatoshi) ToSatoshis() btcutil.Amount {
+func (m MilliAtom) ToSatoshis() btcutil.Amount {
 	return btcutil.Amount(int64(m) / mSatScale)
 }
 
 // String returns the string representation of the mSAT amount.
-func (m MilliSatoshi) String() string {
+func (m MilliAtom) String() string {
 	return fmt.Sprintf("%v mSAT", int64(m))
 }
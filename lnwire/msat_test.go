@@ -10,7 +10,7 @@ func TestMilliSatoshiConversion(t *testing.T) {
 	t.Parallel()
 
 	testCases := []struct {
-		mSatAmount MilliSatoshi
+		mSatAmount MilliAtom
 
 		satAmount btcutil.Amount
 		btcAmount float64
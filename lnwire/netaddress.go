@@ -1,8 +1,10 @@
 package lnwire
 
 import (
+	"encoding/hex"
 	"fmt"
 	"net"
+	"strconv"
 
 	"github.com/roasbeef/btcd/btcec"
 	"github.com/roasbeef/btcd/wire"
@@ -51,3 +53,68 @@ func (n *NetAddress) String() string {
 func (n *NetAddress) Network() string {
 	return n.Address.Network()
 }
+
+// DNSHostnameAddress is a hostname and port pair which a node may advertise
+// within its NodeAnnouncement in place of a raw IP address. This allows nodes
+// behind a dynamic IP to remain reachable, as the hostname is resolved each
+// time a connection to the node is attempted.
+type DNSHostnameAddress struct {
+	// Hostname is the DNS name the node is reachable at.
+	Hostname string
+
+	// Port is the port the node is listening on.
+	Port uint16
+}
+
+// A compile time assertion to ensure that DNSHostnameAddress meets the
+// net.Addr interface.
+var _ net.Addr = (*DNSHostnameAddress)(nil)
+
+// String returns the string representation of the address in the
+// host:port format.
+//
+// This part of the net.Addr interface.
+func (d *DNSHostnameAddress) String() string {
+	return net.JoinHostPort(d.Hostname, strconv.Itoa(int(d.Port)))
+}
+
+// Network returns the name of the network the address is reachable over.
+//
+// This part of the net.Addr interface.
+func (d *DNSHostnameAddress) Network() string {
+	return "tcp"
+}
+
+// OpaqueAddrs holds the raw trailing portion of a NodeAnnouncement's address
+// list, starting from the first address whose type we're unable to parse. As
+// the length of an unknown address type can't be determined, the remainder of
+// the list is retained as is so that the announcement can be re-serialized,
+// and its signature verified, without loss.
+type OpaqueAddrs struct {
+	// NumAddrs is the number of addresses, according to the address count
+	// of the announcement, contained within the payload.
+	NumAddrs uint16
+
+	// Payload is the raw serialized addresses, including the type of the
+	// first unknown address.
+	Payload []byte
+}
+
+// A compile time assertion to ensure that OpaqueAddrs meets the net.Addr
+// interface.
+var _ net.Addr = (*OpaqueAddrs)(nil)
+
+// String returns the hex encoding of the opaque address payload.
+//
+// This part of the net.Addr interface.
+func (o *OpaqueAddrs) String() string {
+	return hex.EncodeToString(o.Payload)
+}
+
+// Network returns the name of the network the addresses are reachable over.
+// As the addresses are unknown to us, "opaque" is returned.
+//
+// This part of the net.Addr interface.
+func (o *OpaqueAddrs) Network() string {
+	return "opaque"
+}
@@ -1,8 +1,10 @@
 package lnwire
 
 import (
+	"bytes"
 	"encoding/hex"
 	"net"
+	"reflect"
 	"testing"
 
 	"github.com/roasbeef/btcd/btcec"
@@ -38,3 +40,87 @@ func TestNetAddressDisplay(t *testing.T) {
 		t.Fatalf("expected %v, got %v", expectedAddr, addrString)
 	}
 }
+
+// TestNodeAnnouncementAddrs checks that hostname addresses survive a round
+// trip through a NodeAnnouncement, and that any addresses of an unknown type
+// are retained such that the announcement re-serializes identically.
+func TestNodeAnnouncementAddrs(t *testing.T) {
+	t.Parallel()
+
+	dnsAddr := &DNSHostnameAddress{Hostname: "ln.example.com", Port: 9735}
+	if dnsAddr.String() != "ln.example.com:9735" {
+		t.Fatalf("unexpected address string: %v", dnsAddr)
+	}
+
+	nodeID, err := randPubKey()
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+
+	msg := &NodeAnnouncement{
+		Signature: testSig,
+		Features:  NewFeatureVector(nil),
+		Timestamp: 1,
+		NodeID:    nodeID,
+	}
+
+	// We'll first serialize the announcement without any addresses, such
+	// that the address list can be appended by hand.
+	var b bytes.Buffer
+	if err := msg.Encode(&b, 0); err != nil {
+		t.Fatalf("unable to encode msg: %v", err)
+	}
+	prefix := b.Bytes()[:b.Len()-2]
+
+	var addrs bytes.Buffer
+	if err := writeElements(&addrs, a1, dnsAddr); err != nil {
+		t.Fatalf("unable to write addrs: %v", err)
+	}
+
+	// The address of an unknown type is followed by one we know of, which
+	// should be retained along with it.
+	var unknown bytes.Buffer
+	unknown.Write([]byte{0x0b, 0x01, 0x02, 0x03})
+	if err := writeElement(&unknown, a1); err != nil {
+		t.Fatalf("unable to write addr: %v", err)
+	}
+
+	var payload bytes.Buffer
+	payload.Write(prefix)
+	if err := writeElement(&payload, uint16(4)); err != nil {
+		t.Fatalf("unable to write count: %v", err)
+	}
+	payload.Write(addrs.Bytes())
+	payload.Write(unknown.Bytes())
+
+	var decoded NodeAnnouncement
+	err = decoded.Decode(bytes.NewReader(payload.Bytes()), 0)
+	if err != nil {
+		t.Fatalf("unable to decode msg: %v", err)
+	}
+	if len(decoded.Addresses) != 3 {
+		t.Fatalf("expected 3 addresses, got %v", len(decoded.Addresses))
+	}
+	if !reflect.DeepEqual(decoded.Addresses[1], dnsAddr) {
+		t.Fatalf("expected %v, got %v", dnsAddr, decoded.Addresses[1])
+	}
+	expectedOpaque := &OpaqueAddrs{
+		NumAddrs: 2,
+		Payload:  unknown.Bytes(),
+	}
+	if !reflect.DeepEqual(decoded.Addresses[2], expectedOpaque) {
+		t.Fatalf("expected %v, got %v", expectedOpaque,
+			decoded.Addresses[2])
+	}
+
+	// Re-serializing the announcement should yield the exact bytes we
+	// started with, otherwise its signature would no longer be valid.
+	var reencoded bytes.Buffer
+	if err := decoded.Encode(&reencoded, 0); err != nil {
+		t.Fatalf("unable to encode msg: %v", err)
+	}
+	if !bytes.Equal(reencoded.Bytes(), payload.Bytes()) {
+		t.Fatalf("re-encoded msg doesn't match: expected %x, got %x",
+			payload.Bytes(), reencoded.Bytes())
+	}
+}
@@ -15,6 +15,12 @@ var (
 	endPort   uint16 = 49151
 )
 
+// MaxNodeAnnAddresses is the maximum number of addresses we'll accept within
+// a single NodeAnnouncement. Honest nodes only advertise a handful of
+// addresses, so the limit exists solely to bound the allocations made while
+// decoding an announcement.
+const MaxNodeAnnAddresses = 64
+
 // RGB is used to represent the "color" of a particular node encoded as a 24
 // bit value.
 type RGB struct {
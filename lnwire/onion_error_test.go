@@ -2,20 +2,21 @@ package lnwire
 
 import (
 	"bytes"
+	"encoding/binary"
 	"reflect"
 	"testing"
 )
 
 var (
 	testOnionHash     = []byte{}
-	testAmount        = MilliSatoshi(1)
+	testAmount        = MilliAtom(1)
 	testCtlvExpiry    = uint32(2)
 	testFlags         = uint16(2)
 	testChannelUpdate = ChannelUpdate{
 		Signature:      testSig,
 		ShortChannelID: NewShortChanIDFromInt(1),
 		Timestamp:      1,
-		Flags:          1,
+		ChannelFlags:   1,
 	}
 )
 
@@ -68,3 +69,24 @@ func TestEncodeDecodeCode(t *testing.T) {
 		}
 	}
 }
+
+// TestChannelUpdateLength checks that the length prefixing the channel update
+// within an onion error is that of the encoded update, which is shorter than
+// its maximum length when the optional max HTLC field is absent.
+func TestChannelUpdateLength(t *testing.T) {
+	failure := NewExpiryTooSoon(testChannelUpdate)
+
+	var b bytes.Buffer
+	if err := failure.Encode(&b, 0); err != nil {
+		t.Fatalf("unable to encode failure: %v", err)
+	}
+
+	length := binary.BigEndian.Uint16(b.Bytes()[:2])
+	if int(length) != b.Len()-2 {
+		t.Fatalf("expected update length %v, got %v", b.Len()-2,
+			length)
+	}
+	if uint32(length) == testChannelUpdate.MaxPayloadLength(0) {
+		t.Fatalf("update without max htlc encoded at its max length")
+	}
+}
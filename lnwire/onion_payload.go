@@ -0,0 +1,209 @@
+package lnwire
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// AmtOnionType is the type of the TLV record carrying the amount to
+	// forward within an onion hop payload.
+	AmtOnionType tlv.Type = 2
+
+	// LockTimeOnionType is the type of the TLV record carrying the
+	// outgoing CLTV value within an onion hop payload.
+	LockTimeOnionType tlv.Type = 4
+
+	// NextHopOnionType is the type of the TLV record carrying the short
+	// channel ID of the next hop within an onion hop payload. It's absent
+	// from the payload of the final hop.
+	NextHopOnionType tlv.Type = 6
+
+	// MPPOnionType is the type of the TLV record carrying the payment
+	// secret and total amount of a payment within the onion hop payload
+	// of the final hop.
+	MPPOnionType tlv.Type = 8
+
+	// AMPOnionType is the type of the TLV record carrying the share of an
+	// atomic multi-path payment within the onion hop payload of the final
+	// hop.
+	AMPOnionType tlv.Type = 14
+)
+
+// NewAmtToFwdRecord returns the TLV record of the passed amount to forward
+// within an onion hop payload.
+func NewAmtToFwdRecord(amt *uint64) tlv.Record {
+	return tlv.MakeDynamicRecord(
+		AmtOnionType, amt, func() uint64 {
+			return tlv.SizeTUint64(*amt)
+		}, tlv.ETUint64, tlv.DTUint64,
+	)
+}
+
+// NewLockTimeRecord returns the TLV record of the passed outgoing CLTV value
+// within an onion hop payload.
+func NewLockTimeRecord(lockTime *uint32) tlv.Record {
+	return tlv.MakeDynamicRecord(
+		LockTimeOnionType, lockTime, func() uint64 {
+			return tlv.SizeTUint32(*lockTime)
+		}, tlv.ETUint32, tlv.DTUint32,
+	)
+}
+
+// NewNextHopIDRecord returns the TLV record of the passed next hop short
+// channel ID within an onion hop payload.
+func NewNextHopIDRecord(cid *uint64) tlv.Record {
+	return tlv.MakePrimitiveRecord(NextHopOnionType, cid)
+}
+
+// MPP is the payment data carried within the onion hop payload of the final
+// hop. The payment secret, which is taken from the invoice being paid, proves
+// to the receiver that the sender knows the invoice, which prevents
+// intermediate nodes from probing for it. The total amount allows the
+// receiver to know when all the parts of a multi-part payment have arrived.
+type MPP struct {
+	// PaymentSecret is the payment secret of the invoice being paid.
+	PaymentSecret [32]byte
+
+	// TotalMsat is the total amount of the payment, which may be split
+	// across several HTLCs.
+	TotalMsat MilliAtom
+}
+
+// NewMPP creates the payment data of a payment of the passed total amount to
+// an invoice with the passed payment secret.
+func NewMPP(total MilliAtom, secret [32]byte) *MPP {
+	return &MPP{
+		PaymentSecret: secret,
+		TotalMsat:     total,
+	}
+}
+
+// Record returns the TLV record of the payment data within an onion hop
+// payload.
+func (m *MPP) Record() tlv.Record {
+	return tlv.MakeDynamicRecord(
+		MPPOnionType, m, func() uint64 {
+			return 32 + tlv.SizeTUint64(uint64(m.TotalMsat))
+		}, encodeMPP, decodeMPP,
+	)
+}
+
+// encodeMPP is a tlv.Encoder for *MPP values. The payment secret is followed
+// by the total amount as a truncated integer.
+func encodeMPP(w io.Writer, val interface{}) error {
+	mpp, ok := val.(*MPP)
+	if !ok {
+		return fmt.Errorf("expected *MPP, got %T", val)
+	}
+
+	if _, err := w.Write(mpp.PaymentSecret[:]); err != nil {
+		return err
+	}
+
+	total := uint64(mpp.TotalMsat)
+	return tlv.ETUint64(w, &total)
+}
+
+// decodeMPP is a tlv.Decoder for *MPP values.
+func decodeMPP(r io.Reader, val interface{}, l uint64) error {
+	mpp, ok := val.(*MPP)
+	if !ok {
+		return fmt.Errorf("expected *MPP, got %T", val)
+	}
+	if l < 32 || l > 40 {
+		return fmt.Errorf("invalid length %d for payment data, "+
+			"expected between 32 and 40 bytes", l)
+	}
+
+	if _, err := io.ReadFull(r, mpp.PaymentSecret[:]); err != nil {
+		return err
+	}
+
+	var total uint64
+	if err := tlv.DTUint64(r, &total, l-32); err != nil {
+		return err
+	}
+	mpp.TotalMsat = MilliAtom(total)
+
+	return nil
+}
+
+// AMP is the data carried within the onion hop payload of the final hop for
+// each shard of an atomic multi-path payment. Rather than paying to the hash
+// of a single preimage, each shard pays to the hash of a preimage derived
+// from the payment's root seed, which the receiver can only reconstruct once
+// it has received the shares of all shards.
+type AMP struct {
+	// RootShare is the share of the payment's root seed carried by the
+	// shard. The root seed is the XOR of the shares of all shards.
+	RootShare [32]byte
+
+	// SetID identifies the set of shards which make up the payment.
+	SetID [32]byte
+
+	// ChildIndex is the index used to derive the shard's preimage from
+	// the root seed.
+	ChildIndex uint32
+}
+
+// NewAMP creates the data of the shard of an atomic multi-path payment with
+// the passed root share, set ID, and child index.
+func NewAMP(rootShare, setID [32]byte, childIndex uint32) *AMP {
+	return &AMP{
+		RootShare:  rootShare,
+		SetID:      setID,
+		ChildIndex: childIndex,
+	}
+}
+
+// Record returns the TLV record of the shard data within an onion hop
+// payload.
+func (a *AMP) Record() tlv.Record {
+	return tlv.MakeDynamicRecord(
+		AMPOnionType, a, func() uint64 {
+			return 64 + tlv.SizeTUint32(a.ChildIndex)
+		}, encodeAMP, decodeAMP,
+	)
+}
+
+// encodeAMP is a tlv.Encoder for *AMP values. The root share and set ID are
+// followed by the child index as a truncated integer.
+func encodeAMP(w io.Writer, val interface{}) error {
+	amp, ok := val.(*AMP)
+	if !ok {
+		return fmt.Errorf("expected *AMP, got %T", val)
+	}
+
+	if _, err := w.Write(amp.RootShare[:]); err != nil {
+		return err
+	}
+	if _, err := w.Write(amp.SetID[:]); err != nil {
+		return err
+	}
+
+	return tlv.ETUint32(w, &amp.ChildIndex)
+}
+
+// decodeAMP is a tlv.Decoder for *AMP values.
+func decodeAMP(r io.Reader, val interface{}, l uint64) error {
+	amp, ok := val.(*AMP)
+	if !ok {
+		return fmt.Errorf("expected *AMP, got %T", val)
+	}
+	if l < 64 || l > 68 {
+		return fmt.Errorf("invalid length %d for amp data, "+
+			"expected between 64 and 68 bytes", l)
+	}
+
+	if _, err := io.ReadFull(r, amp.RootShare[:]); err != nil {
+		return err
+	}
+	if _, err := io.ReadFull(r, amp.SetID[:]); err != nil {
+		return err
+	}
+
+	return tlv.DTUint32(r, &amp.ChildIndex, l-64)
+}
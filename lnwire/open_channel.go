@@ -3,6 +3,7 @@ package lnwire
 import (
 	"io"
 
+	"github.com/lightningnetwork/lnd/tlv"
 	"github.com/roasbeef/btcd/btcec"
 	"github.com/roasbeef/btcd/chaincfg/chainhash"
 	"github.com/roasbeef/btcutil"
@@ -31,7 +32,7 @@ type OpenChannel struct {
 	// PushAmount is the value that the initiating party wishes to "push"
 	// to the responding as part of the first commitment state. If the
 	// responder accepts, then this will be their initial balance.
-	PushAmount MilliSatoshi
+	PushAmount MilliAtom
 
 	// DustLimit is the specific dust limit the sender of this message
 	// would like enforced on their version of the commitment transaction.
@@ -42,7 +43,7 @@ type OpenChannel struct {
 	// MaxValueInFlight represents the maximum amount of coins that can be
 	// pending within the channel at any given time. If the amount of funds
 	// in limbo exceeds this amount, then the channel will be failed.
-	MaxValueInFlight MilliSatoshi
+	MaxValueInFlight MilliAtom
 
 	// ChannelReserve is the amount of BTC that the receiving party MUST
 	// maintain a balance above at all times. This is a safety mechanism to
@@ -52,7 +53,7 @@ type OpenChannel struct {
 
 	// HtlcMinimum is the smallest HTLC that the sender of this message
 	// will accept.
-	HtlcMinimum MilliSatoshi
+	HtlcMinimum MilliAtom
 
 	// FeePerKiloWeight is the initial fee rate that the initiator suggests
 	// for both commitment transaction. This value is expressed in sat per
@@ -102,6 +103,11 @@ type OpenChannel struct {
 	// Currently, the least significant bit of this bit field indicates the
 	// initiator of the channel wishes to advertise this channel publicly.
 	ChannelFlags byte
+
+	// UpfrontShutdownScript is an optional script which the sender
+	// commits to pay out to when the channel is cooperatively closed. It's
+	// carried within the TLV stream appended to the message.
+	UpfrontShutdownScript DeliveryAddress
 }
 
 // A compile time check to ensure OpenChannel implements the lnwire.Message
@@ -114,7 +120,7 @@ var _ Message = (*OpenChannel)(nil)
 //
 // This is part of the lnwire.Message interface.
 func (o *OpenChannel) Encode(w io.Writer, pver uint32) error {
-	return writeElements(w,
+	err := writeElements(w,
 		o.ChainHash[:],
 		o.PendingChannelID[:],
 		o.FundingAmount,
@@ -133,6 +139,18 @@ func (o *OpenChannel) Encode(w io.Writer, pver uint32) error {
 		o.FirstCommitmentPoint,
 		o.ChannelFlags,
 	)
+	if err != nil {
+		return err
+	}
+
+	// The upfront shutdown script is only appended if it's set.
+	var records []tlv.Record
+	if len(o.UpfrontShutdownScript) > 0 {
+		records = append(records, upfrontShutdownScriptRecord(
+			&o.UpfrontShutdownScript,
+		))
+	}
+	return encodeTLVStream(w, records...)
 }
 
 // Decode deserializes the serialized OpenChannel stored in the passed
@@ -141,7 +159,7 @@ func (o *OpenChannel) Encode(w io.Writer, pver uint32) error {
 //
 // This is part of the lnwire.Message interface.
 func (o *OpenChannel) Decode(r io.Reader, pver uint32) error {
-	return readElements(r,
+	err := readElements(r,
 		o.ChainHash[:],
 		o.PendingChannelID[:],
 		&o.FundingAmount,
@@ -160,6 +178,14 @@ func (o *OpenChannel) Decode(r io.Reader, pver uint32) error {
 		&o.FirstCommitmentPoint,
 		&o.ChannelFlags,
 	)
+	if err != nil {
+		return err
+	}
+
+	_, err = decodeTLVStream(
+		r, upfrontShutdownScriptRecord(&o.UpfrontShutdownScript),
+	)
+	return err
 }
 
 // MsgType returns the MessageType code which uniquely identifies this message
@@ -175,6 +201,7 @@ func (o *OpenChannel) MsgType() MessageType {
 //
 // This is part of the lnwire.Message interface.
 func (o *OpenChannel) MaxPayloadLength(uint32) uint32 {
-	// (32 * 2) + (8 * 6) + (4 * 1) + (2 * 2) + (33 * 5) + 1
-	return 286
+	// The fixed size fields are followed by a TLV stream of unbounded
+	// length, so the message is only bounded by the maximum payload.
+	return MaxMessagePayload
 }
@@ -5,6 +5,17 @@ import "io"
 // PingPayload is a set of opaque bytes used to pad out a ping message.
 type PingPayload []byte
 
+const (
+	// MaxPongBytes is the maximum number of pong bytes a Ping may request
+	// while still expecting a response. Pings requesting more bytes than
+	// this are to be ignored, allowing them to be used as cover traffic.
+	MaxPongBytes = 65531
+
+	// MaxPingPadBytes is the maximum number of padding bytes a Ping can
+	// carry while remaining within its maximum payload length.
+	MaxPingPadBytes = 65532 - 4
+)
+
 // Ping defines a message which is sent by peers periodically to determine if
 // the connection is still valid. Each ping message carries the number of bytes
 // to pad the pong response with, and also a number of bytes to be ignored at
@@ -27,6 +38,17 @@ func NewPing(numBytes uint16) *Ping {
 	}
 }
 
+// NewPingWithPadding returns a new Ping message requesting numPongBytes pong
+// bytes, and padded out with numPadBytes zero bytes.
+func NewPingWithPadding(numPongBytes, numPadBytes uint16) *Ping {
+	ping := NewPing(numPongBytes)
+	if numPadBytes > 0 {
+		ping.PaddingBytes = make(PingPayload, numPadBytes)
+	}
+
+	return ping
+}
+
 // A compile time check to ensure Ping implements the lnwire.Message interface.
 var _ Message = (*Ping)(nil)
 
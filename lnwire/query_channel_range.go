@@ -0,0 +1,153 @@
+package lnwire
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/lightningnetwork/lnd/tlv"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+)
+
+// QueryOptions is a bitfield sent within a QueryChannelRange message, which
+// signals the extra information the sender would like to receive about each
+// channel within the ReplyChannelRange messages.
+type QueryOptions uint64
+
+const (
+	// QueryOptionTimestamps signals that the sender would like to receive
+	// the timestamps of the latest channel updates of each channel.
+	QueryOptionTimestamps QueryOptions = 1 << iota
+
+	// QueryOptionChecksums signals that the sender would like to receive
+	// the checksums of the latest channel updates of each channel.
+	QueryOptionChecksums
+)
+
+// QueryOptionsType is the type of the TLV record carrying the query options
+// within a QueryChannelRange message.
+const QueryOptionsType tlv.Type = 1
+
+// QueryChannelRange is a message sent by a node in order to query the
+// receiving node of the set of open channel they know of with short channel
+// ID's after the specified block height, capped at the number of blocks beyond
+// that block height. This will be used by nodes upon initial connect to
+// synchronize their views of the network.
+type QueryChannelRange struct {
+	// ChainHash denotes the target chain that we're trying to synchronize
+	// channel graph state for.
+	ChainHash chainhash.Hash
+
+	// FirstBlockHeight is the first block in the query range. The
+	// responder should send all new short channel IDs from this block
+	// until this block plus the specified number of blocks.
+	FirstBlockHeight uint32
+
+	// NumBlocks is the number of blocks beyond the first block that short
+	// channel ID's should be sent for.
+	NumBlocks uint32
+
+	// QueryOptions is the extra information the sender would like to
+	// receive about each channel. It's only included within the message
+	// if non-zero.
+	QueryOptions QueryOptions
+}
+
+// A compile time check to ensure QueryChannelRange implements the
+// lnwire.Message interface.
+var _ Message = (*QueryChannelRange)(nil)
+
+// Decode deserializes a serialized QueryChannelRange message stored in the
+// passed io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (q *QueryChannelRange) Decode(r io.Reader, pver uint32) error {
+	err := readElements(r,
+		q.ChainHash[:],
+		&q.FirstBlockHeight,
+		&q.NumBlocks,
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = decodeTLVStream(r, queryOptionsRecord(&q.QueryOptions))
+	return err
+}
+
+// Encode serializes the target QueryChannelRange into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (q *QueryChannelRange) Encode(w io.Writer, pver uint32) error {
+	err := writeElements(w,
+		q.ChainHash[:],
+		q.FirstBlockHeight,
+		q.NumBlocks,
+	)
+	if err != nil {
+		return err
+	}
+
+	var records []tlv.Record
+	if q.QueryOptions != 0 {
+		records = append(records, queryOptionsRecord(&q.QueryOptions))
+	}
+
+	return encodeTLVStream(w, records...)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (q *QueryChannelRange) MsgType() MessageType {
+	return MsgQueryChannelRange
+}
+
+// MaxPayloadLength returns the maximum allowed payload size for a
+// QueryChannelRange complete message observing the specified protocol
+// version.
+//
+// This is part of the lnwire.Message interface.
+func (q *QueryChannelRange) MaxPayloadLength(uint32) uint32 {
+	return MaxMessagePayload
+}
+
+// queryOptionsRecord returns the TLV record of the passed query options, which
+// are encoded as a BigSize integer.
+func queryOptionsRecord(opts *QueryOptions) tlv.Record {
+	return tlv.MakeDynamicRecord(
+		QueryOptionsType, opts, func() uint64 {
+			return tlv.BigSizeLen(uint64(*opts))
+		}, encodeQueryOptions, decodeQueryOptions,
+	)
+}
+
+// encodeQueryOptions is a tlv.Encoder for *QueryOptions values.
+func encodeQueryOptions(w io.Writer, val interface{}) error {
+	opts, ok := val.(*QueryOptions)
+	if !ok {
+		return fmt.Errorf("expected *QueryOptions, got %T", val)
+	}
+
+	return tlv.WriteBigSize(w, uint64(*opts))
+}
+
+// decodeQueryOptions is a tlv.Decoder for *QueryOptions values.
+func decodeQueryOptions(r io.Reader, val interface{}, l uint64) error {
+	opts, ok := val.(*QueryOptions)
+	if !ok {
+		return fmt.Errorf("expected *QueryOptions, got %T", val)
+	}
+
+	v, err := tlv.ReadBigSize(r)
+	if err != nil {
+		return err
+	}
+	if tlv.BigSizeLen(v) != l {
+		return fmt.Errorf("invalid length %d for query options", l)
+	}
+	*opts = QueryOptions(v)
+
+	return nil
+}
@@ -0,0 +1,476 @@
+package lnwire
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/lightningnetwork/lnd/tlv"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+)
+
+// ShortChanIDEncoding is an enum-like type that represents exactly how a set
+// of short channel ID's, along with the timestamps of their channel updates,
+// is encoded on the wire.
+type ShortChanIDEncoding uint8
+
+const (
+	// EncodingSortedPlain signals that the set of short channel ID's is
+	// encoded using the regular encoding, in a sorted order.
+	EncodingSortedPlain ShortChanIDEncoding = 0
+
+	// EncodingSortedZlib signals that the set of short channel ID's is
+	// encoded by first sorting the set of channel ID's, as then
+	// compressing them using zlib.
+	EncodingSortedZlib ShortChanIDEncoding = 1
+)
+
+const (
+	// TimestampsRecordType is the type of the TLV record carrying the
+	// timestamps of the latest channel updates of each channel within a
+	// ReplyChannelRange message.
+	TimestampsRecordType tlv.Type = 1
+
+	// ChecksumsRecordType is the type of the TLV record carrying the
+	// checksums of the latest channel updates of each channel within a
+	// ReplyChannelRange message.
+	ChecksumsRecordType tlv.Type = 3
+
+	// maxZlibBodySize is the max number of bytes that we'll decompress
+	// across all of the zlib encoded fields of a single message. We do
+	// this in order to limit the total amount of memory allocated while
+	// decoding a message, as zlib allows a small message to expand to
+	// roughly a thousand times its size.
+	maxZlibBodySize = 1 << 20
+
+	// zlibReadChunkSize is the number of bytes read from a zlib stream at
+	// a time while decompressing it.
+	zlibReadChunkSize = 4096
+
+	// MaxShortChanIDsPerReply is the maximum number of short channel ID's
+	// we'll accept within a single ReplyChannelRange. As the ID's may be
+	// zlib encoded, a reply could otherwise carry far more of them than
+	// would fit within the payload using the plain encoding.
+	MaxShortChanIDsPerReply = 8000
+)
+
+// ErrZlibLimitExceeded is returned when decompressing a zlib encoded field of
+// a message would exceed the number of bytes we're willing to decompress for
+// it.
+var ErrZlibLimitExceeded = errors.New("zlib data exceeds decompression " +
+	"limit")
+
+// ChanUpdateTimestamps holds the timestamps of the latest channel updates of
+// both directions of a channel. A zero timestamp signals that no channel
+// update is known for that direction.
+type ChanUpdateTimestamps struct {
+	// Timestamp1 is the timestamp of the channel update of the first
+	// node of the channel.
+	Timestamp1 uint32
+
+	// Timestamp2 is the timestamp of the channel update of the second
+	// node of the channel.
+	Timestamp2 uint32
+}
+
+// ChanUpdateChecksums holds the checksums of the latest channel updates of
+// both directions of a channel, as computed by ChannelUpdate.Checksum. A zero
+// checksum signals that no channel update is known for that direction.
+type ChanUpdateChecksums struct {
+	// Checksum1 is the checksum of the channel update of the first node
+	// of the channel.
+	Checksum1 uint32
+
+	// Checksum2 is the checksum of the channel update of the second node
+	// of the channel.
+	Checksum2 uint32
+}
+
+// ReplyChannelRange is the response to the QueryChannelRange message. It
+// includes the original query, and the next streaming chunk of encoded short
+// channel ID's as the response. If requested within the query, the timestamps
+// and checksums of the channel updates of each channel are included as well,
+// which allows the querying node to skip requesting the updates it already
+// knows about.
+type ReplyChannelRange struct {
+	// ChainHash denotes the target chain that the short channel ID's
+	// belong to.
+	ChainHash chainhash.Hash
+
+	// FirstBlockHeight is the first block covered by this reply.
+	FirstBlockHeight uint32
+
+	// NumBlocks is the number of blocks covered by this reply.
+	NumBlocks uint32
+
+	// Complete denotes if this is the conclusion of the set of streaming
+	// responses to the original query.
+	Complete uint8
+
+	// EncodingType is the encoding used for the set of short channel ID's,
+	// as well as for their timestamps if included.
+	EncodingType ShortChanIDEncoding
+
+	// ShortChanIDs is a slice of decoded short channel ID's.
+	ShortChanIDs []ShortChannelID
+
+	// Timestamps holds the timestamps of the channel updates of each of
+	// the ShortChanIDs, in the same order. It's nil if the timestamps
+	// weren't included within the message.
+	Timestamps []ChanUpdateTimestamps
+
+	// Checksums holds the checksums of the channel updates of each of the
+	// ShortChanIDs, in the same order. It's nil if the checksums weren't
+	// included within the message.
+	Checksums []ChanUpdateChecksums
+}
+
+// A compile time check to ensure ReplyChannelRange implements the
+// lnwire.Message interface.
+var _ Message = (*ReplyChannelRange)(nil)
+
+// Decode deserializes a serialized ReplyChannelRange message stored in the
+// passed io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (c *ReplyChannelRange) Decode(r io.Reader, pver uint32) error {
+	var numBytes uint16
+	err := readElements(r,
+		c.ChainHash[:],
+		&c.FirstBlockHeight,
+		&c.NumBlocks,
+		&c.Complete,
+		&numBytes,
+	)
+	if err != nil {
+		return err
+	}
+
+	encodedIDs, err := readBytes(r, int(numBytes))
+	if err != nil {
+		return err
+	}
+
+	// All of the zlib encoded fields of the message share a single
+	// decompression budget. The short channel ID's can't decompress to
+	// more than is needed to hold the maximum number of them.
+	var rawIDs []byte
+	c.EncodingType, rawIDs, err = decodeGossipData(
+		encodedIDs, MaxShortChanIDsPerReply*8,
+	)
+	if err != nil {
+		return err
+	}
+	if len(rawIDs)%8 != 0 {
+		return fmt.Errorf("invalid short channel ID's length %d",
+			len(rawIDs))
+	}
+
+	numIDs := len(rawIDs) / 8
+	if numIDs > MaxShortChanIDsPerReply {
+		return fmt.Errorf("%v short channel ID's exceeds maximum of %v",
+			numIDs, MaxShortChanIDsPerReply)
+	}
+	if numIDs > 0 {
+		c.ShortChanIDs = make([]ShortChannelID, numIDs)
+	}
+	for i := range c.ShortChanIDs {
+		chanID := binary.BigEndian.Uint64(rawIDs[i*8:])
+		c.ShortChanIDs[i] = NewShortChanIDFromInt(chanID)
+	}
+
+	var encodedTimestamps, rawChecksums []byte
+	parsedTypes, err := decodeTLVStream(r,
+		tlv.MakePrimitiveRecord(
+			TimestampsRecordType, &encodedTimestamps,
+		),
+		tlv.MakePrimitiveRecord(ChecksumsRecordType, &rawChecksums),
+	)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := parsedTypes[TimestampsRecordType]; ok {
+		// The timestamps can't decompress to more than is needed to
+		// hold those of each channel, nor beyond what's left of the
+		// message's budget.
+		limit := numIDs * 8
+		if remaining := maxZlibBodySize - len(rawIDs); limit > remaining {
+			limit = remaining
+		}
+
+		encoding, rawTimestamps, err := decodeGossipData(
+			encodedTimestamps, limit,
+		)
+		if err != nil {
+			return err
+		}
+		if encoding != c.EncodingType {
+			return fmt.Errorf("timestamps encoded using %v, "+
+				"expected %v", encoding, c.EncodingType)
+		}
+		if len(rawTimestamps) != numIDs*8 {
+			return fmt.Errorf("expected timestamps of %d "+
+				"channels, got %d bytes", numIDs,
+				len(rawTimestamps))
+		}
+
+		c.Timestamps = make([]ChanUpdateTimestamps, numIDs)
+		for i := range c.Timestamps {
+			c.Timestamps[i] = ChanUpdateTimestamps{
+				Timestamp1: binary.BigEndian.Uint32(
+					rawTimestamps[i*8:],
+				),
+				Timestamp2: binary.BigEndian.Uint32(
+					rawTimestamps[i*8+4:],
+				),
+			}
+		}
+	}
+
+	if _, ok := parsedTypes[ChecksumsRecordType]; ok {
+		if len(rawChecksums) != numIDs*8 {
+			return fmt.Errorf("expected checksums of %d channels, "+
+				"got %d bytes", numIDs, len(rawChecksums))
+		}
+
+		c.Checksums = make([]ChanUpdateChecksums, numIDs)
+		for i := range c.Checksums {
+			c.Checksums[i] = ChanUpdateChecksums{
+				Checksum1: binary.BigEndian.Uint32(
+					rawChecksums[i*8:],
+				),
+				Checksum2: binary.BigEndian.Uint32(
+					rawChecksums[i*8+4:],
+				),
+			}
+		}
+	}
+
+	return nil
+}
+
+// Encode serializes the target ReplyChannelRange into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (c *ReplyChannelRange) Encode(w io.Writer, pver uint32) error {
+	// We'll never send more short channel ID's than we'd accept.
+	if len(c.ShortChanIDs) > MaxShortChanIDsPerReply {
+		return fmt.Errorf("%v short channel ID's exceeds maximum of %v",
+			len(c.ShortChanIDs), MaxShortChanIDsPerReply)
+	}
+
+	rawIDs := make([]byte, len(c.ShortChanIDs)*8)
+	for i, chanID := range c.ShortChanIDs {
+		binary.BigEndian.PutUint64(rawIDs[i*8:], chanID.ToUint64())
+	}
+
+	encodedIDs, err := encodeGossipData(c.EncodingType, rawIDs)
+	if err != nil {
+		return err
+	}
+
+	err = writeElements(w,
+		c.ChainHash[:],
+		c.FirstBlockHeight,
+		c.NumBlocks,
+		c.Complete,
+		uint16(len(encodedIDs)),
+		encodedIDs,
+	)
+	if err != nil {
+		return err
+	}
+
+	var records []tlv.Record
+	if c.Timestamps != nil {
+		if len(c.Timestamps) != len(c.ShortChanIDs) {
+			return fmt.Errorf("expected timestamps of %d "+
+				"channels, got %d", len(c.ShortChanIDs),
+				len(c.Timestamps))
+		}
+
+		rawTimestamps := make([]byte, len(c.Timestamps)*8)
+		for i, timestamps := range c.Timestamps {
+			binary.BigEndian.PutUint32(
+				rawTimestamps[i*8:], timestamps.Timestamp1,
+			)
+			binary.BigEndian.PutUint32(
+				rawTimestamps[i*8+4:], timestamps.Timestamp2,
+			)
+		}
+
+		encodedTimestamps, err := encodeGossipData(
+			c.EncodingType, rawTimestamps,
+		)
+		if err != nil {
+			return err
+		}
+		records = append(records, tlv.MakePrimitiveRecord(
+			TimestampsRecordType, &encodedTimestamps,
+		))
+	}
+
+	if c.Checksums != nil {
+		if len(c.Checksums) != len(c.ShortChanIDs) {
+			return fmt.Errorf("expected checksums of %d channels, "+
+				"got %d", len(c.ShortChanIDs), len(c.Checksums))
+		}
+
+		rawChecksums := make([]byte, len(c.Checksums)*8)
+		for i, checksums := range c.Checksums {
+			binary.BigEndian.PutUint32(
+				rawChecksums[i*8:], checksums.Checksum1,
+			)
+			binary.BigEndian.PutUint32(
+				rawChecksums[i*8+4:], checksums.Checksum2,
+			)
+		}
+
+		records = append(records, tlv.MakePrimitiveRecord(
+			ChecksumsRecordType, &rawChecksums,
+		))
+	}
+
+	return encodeTLVStream(w, records...)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (c *ReplyChannelRange) MsgType() MessageType {
+	return MsgReplyChannelRange
+}
+
+// MaxPayloadLength returns the maximum allowed payload size for a
+// ReplyChannelRange complete message observing the specified protocol
+// version.
+//
+// This is part of the lnwire.Message interface.
+func (c *ReplyChannelRange) MaxPayloadLength(uint32) uint32 {
+	return MaxMessagePayload
+}
+
+// encodeGossipData encodes the passed raw data, which is either a set of short
+// channel ID's or their timestamps, using the passed encoding. The encoding
+// type is written as the first byte of the returned data.
+func encodeGossipData(encoding ShortChanIDEncoding, raw []byte) ([]byte,
+	error) {
+
+	switch encoding {
+	case EncodingSortedPlain:
+		return append([]byte{byte(encoding)}, raw...), nil
+
+	case EncodingSortedZlib:
+		var b bytes.Buffer
+		b.WriteByte(byte(encoding))
+
+		// An empty set is encoded as the encoding type alone, rather
+		// than as an empty zlib stream.
+		if len(raw) == 0 {
+			return b.Bytes(), nil
+		}
+
+		zw := zlib.NewWriter(&b)
+		if _, err := zw.Write(raw); err != nil {
+			return nil, err
+		}
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+
+		return b.Bytes(), nil
+
+	default:
+		return nil, fmt.Errorf("unknown short chan id encoding: %v",
+			encoding)
+	}
+}
+
+// decodeGossipData decodes data previously encoded with encodeGossipData,
+// returning the encoding type used along with the raw data. Zlib encoded data
+// is rejected if it decompresses to more than limit bytes.
+func decodeGossipData(data []byte, limit int) (ShortChanIDEncoding, []byte,
+	error) {
+
+	if len(data) == 0 {
+		return 0, nil, fmt.Errorf("missing short chan id encoding")
+	}
+
+	encoding := ShortChanIDEncoding(data[0])
+	data = data[1:]
+
+	switch encoding {
+	case EncodingSortedPlain:
+		return encoding, data, nil
+
+	case EncodingSortedZlib:
+		if len(data) == 0 {
+			return encoding, nil, nil
+		}
+
+		zr, err := zlib.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return 0, nil, err
+		}
+		defer zr.Close()
+
+		raw, err := readZlibLimited(zr, limit)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		return encoding, raw, nil
+
+	default:
+		return 0, nil, fmt.Errorf("unknown short chan id encoding: %v",
+			encoding)
+	}
+}
+
+// readZlibLimited reads the entirety of the passed zlib stream, failing with
+// ErrZlibLimitExceeded as soon as it yields more than limit bytes. The buffer
+// holding the decompressed data is never grown beyond limit bytes, so the
+// memory allocated is bounded by the limit rather than by the amount of data
+// the stream would expand to.
+func readZlibLimited(r io.Reader, limit int) ([]byte, error) {
+	var (
+		chunk [zlibReadChunkSize]byte
+		raw   []byte
+	)
+	for {
+		n, err := r.Read(chunk[:])
+		if len(raw)+n > limit {
+			return nil, ErrZlibLimitExceeded
+		}
+
+		// Grow the buffer as needed, doubling its capacity as append
+		// would, but capping it at the limit.
+		if len(raw)+n > cap(raw) {
+			newCap := 2 * cap(raw)
+			if newCap < len(raw)+n {
+				newCap = len(raw) + n
+			}
+			if newCap > limit {
+				newCap = limit
+			}
+
+			newRaw := make([]byte, len(raw), newCap)
+			copy(newRaw, raw)
+			raw = newRaw
+		}
+		raw = append(raw, chunk[:n]...)
+
+		switch {
+		case err == io.EOF:
+			return raw, nil
+		case err != nil:
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package lnwire
+
+import (
+	"bytes"
+	"compress/zlib"
+	"reflect"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+// TestReplyChannelRangeEncodings checks that a ReplyChannelRange carrying
+// timestamps and checksums decodes to the same message with either encoding
+// of its short channel ID's and timestamps.
+func TestReplyChannelRangeEncodings(t *testing.T) {
+	t.Parallel()
+
+	msg := &ReplyChannelRange{
+		ChainHash:        *shaHash1,
+		FirstBlockHeight: 100,
+		NumBlocks:        50,
+		Complete:         1,
+	}
+	for i := uint32(0); i < 100; i++ {
+		msg.ShortChanIDs = append(msg.ShortChanIDs, ShortChannelID{
+			BlockHeight: 100 + i/2,
+			TxIndex:     i,
+		})
+		msg.Timestamps = append(msg.Timestamps, ChanUpdateTimestamps{
+			Timestamp1: 1504000000 + i,
+		})
+		msg.Checksums = append(msg.Checksums, ChanUpdateChecksums{
+			Checksum1: i,
+			Checksum2: i * 2,
+		})
+	}
+
+	var encodedSizes []int
+	for _, encoding := range []ShortChanIDEncoding{
+		EncodingSortedPlain, EncodingSortedZlib,
+	} {
+		msg.EncodingType = encoding
+		rawMsg := serializeMessage(t, msg)
+		encodedSizes = append(encodedSizes, len(rawMsg))
+
+		decoded, err := ReadMessageBytes(rawMsg, 0)
+		if err != nil {
+			t.Fatalf("encoding %v: unable to decode message: %v",
+				encoding, err)
+		}
+		if !reflect.DeepEqual(decoded, msg) {
+			t.Fatalf("encoding %v: expected %v, got %v", encoding,
+				msg, decoded)
+		}
+	}
+
+	// As the short channel ID's and timestamps are sequential, the zlib
+	// encoding should be much more compact.
+	if encodedSizes[1] >= encodedSizes[0] {
+		t.Fatalf("expected zlib encoding to be smaller: plain %d "+
+			"bytes, zlib %d bytes", encodedSizes[0], encodedSizes[1])
+	}
+
+	// A message whose timestamps don't match its short channel ID's
+	// can't be encoded.
+	msg.Timestamps = msg.Timestamps[1:]
+	if err := msg.Encode(&bytes.Buffer{}, 0); err == nil {
+		t.Fatalf("expected mismatched timestamps to be rejected")
+	}
+}
+
+// zlibEncode returns raw compressed using zlib, prefixed with the zlib
+// encoding type.
+func zlibEncode(t *testing.T, raw []byte) []byte {
+	var compressed bytes.Buffer
+	compressed.WriteByte(byte(EncodingSortedZlib))
+	zw := zlib.NewWriter(&compressed)
+	if _, err := zw.Write(raw); err != nil {
+		t.Fatalf("unable to compress: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("unable to compress: %v", err)
+	}
+
+	return compressed.Bytes()
+}
+
+// TestReplyChannelRangeZlibLimit checks that zlib encoded short channel ID's
+// and timestamps exceeding their decompression limits are rejected.
+func TestReplyChannelRangeZlibLimit(t *testing.T) {
+	t.Parallel()
+
+	// A single short channel ID, which limits the timestamps to those of
+	// a single channel.
+	singleID := zlibEncode(t, make([]byte, 8))
+
+	tests := []struct {
+		name       string
+		encodedIDs []byte
+		timestamps []byte
+	}{
+		{
+			name: "oversized short channel ids",
+			encodedIDs: zlibEncode(
+				t, make([]byte, MaxShortChanIDsPerReply*8+8),
+			),
+		},
+		{
+			name:       "timestamps beyond channel count",
+			encodedIDs: singleID,
+			timestamps: zlibEncode(t, make([]byte, 64*1024)),
+		},
+	}
+
+	for _, test := range tests {
+		var b bytes.Buffer
+		err := writeElements(&b,
+			shaHash1[:],
+			uint32(0),
+			uint32(1),
+			uint8(1),
+			uint16(len(test.encodedIDs)),
+			test.encodedIDs,
+		)
+		if err != nil {
+			t.Fatalf("%s: unable to encode message: %v", test.name,
+				err)
+		}
+		if test.timestamps != nil {
+			err := encodeTLVStream(&b, tlv.MakePrimitiveRecord(
+				TimestampsRecordType, &test.timestamps,
+			))
+			if err != nil {
+				t.Fatalf("%s: unable to encode timestamps: %v",
+					test.name, err)
+			}
+		}
+
+		var msg ReplyChannelRange
+		err = msg.Decode(bytes.NewReader(b.Bytes()), 0)
+		if err != ErrZlibLimitExceeded {
+			t.Fatalf("%s: expected ErrZlibLimitExceeded, got %v",
+				test.name, err)
+		}
+	}
+}
+
+// TestChannelUpdateChecksum checks that the checksum of a channel update
+// ignores its signature and timestamp, but covers its routing policy.
+func TestChannelUpdateChecksum(t *testing.T) {
+	t.Parallel()
+
+	update := &ChannelUpdate{
+		Signature:       testSig,
+		ChainHash:       *shaHash1,
+		ShortChannelID:  NewShortChanIDFromInt(1),
+		Timestamp:       1504000000,
+		TimeLockDelta:   144,
+		HtlcMinimumMsat: 1000,
+		BaseFee:         1000,
+		FeeRate:         1,
+	}
+	checksum, err := update.Checksum()
+	if err != nil {
+		t.Fatalf("unable to compute checksum: %v", err)
+	}
+
+	// Refreshing the update with a new timestamp and signature shouldn't
+	// change its checksum.
+	refreshed := *update
+	refreshed.Timestamp++
+	refreshed.Signature = nil
+	refreshedChecksum, err := refreshed.Checksum()
+	if err != nil {
+		t.Fatalf("unable to compute checksum: %v", err)
+	}
+	if refreshedChecksum != checksum {
+		t.Fatalf("expected checksum %x, got %x", checksum,
+			refreshedChecksum)
+	}
+
+	// Changing the routing policy should change its checksum.
+	updated := *update
+	updated.MessageFlags = ChanUpdateOptionMaxHtlc
+	updated.HtlcMaximumMsat = 100000
+	updatedChecksum, err := updated.Checksum()
+	if err != nil {
+		t.Fatalf("unable to compute checksum: %v", err)
+	}
+	if updatedChecksum == checksum {
+		t.Fatalf("expected checksum to change with routing policy")
+	}
+}
@@ -1,7 +1,10 @@
 package lnwire
 
 import (
+	"errors"
 	"io"
+
+	"github.com/roasbeef/btcd/txscript"
 )
 
 // Shutdown is sent by either side in order to initiate the cooperative closure
@@ -22,6 +25,42 @@ type Shutdown struct {
 // p2wpkh.
 type DeliveryAddress []byte
 
+// ErrNonStandardDeliveryAddress is returned when a delivery address doesn't
+// match any of the standard script templates.
+var ErrNonStandardDeliveryAddress = errors.New("delivery address is not a " +
+	"standard script")
+
+// IsStandard returns true if the delivery address matches one of the standard
+// script templates a cooperative close may pay out to: pay-to-pubkey-hash,
+// pay-to-script-hash, or the version 0 witness programs paying to a 20-byte
+// pubkey hash or 32-byte script hash. Paying out to any other script could
+// render the closing transaction non-standard, preventing it from being
+// relayed.
+func (d DeliveryAddress) IsStandard() bool {
+	// The script classes of version 0 witness programs only match
+	// programs of their standard sizes, so a witness program of any other
+	// length is classified as non-standard.
+	switch txscript.GetScriptClass(d) {
+	case txscript.PubKeyHashTy, txscript.ScriptHashTy,
+		txscript.WitnessV0PubKeyHashTy, txscript.WitnessV0ScriptHashTy:
+
+		return true
+
+	default:
+		return false
+	}
+}
+
+// Validate returns ErrNonStandardDeliveryAddress if the delivery address
+// doesn't match one of the standard script templates.
+func (d DeliveryAddress) Validate() error {
+	if !d.IsStandard() {
+		return ErrNonStandardDeliveryAddress
+	}
+
+	return nil
+}
+
 // NewShutdown creates a new Shutdown message.
 func NewShutdown(cid ChannelID, addr DeliveryAddress) *Shutdown {
 	return &Shutdown{
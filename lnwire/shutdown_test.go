@@ -0,0 +1,97 @@
+package lnwire
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/roasbeef/btcd/txscript"
+)
+
+// TestDeliveryAddressIsStandard checks that only delivery addresses matching
+// one of the standard script templates are accepted.
+func TestDeliveryAddressIsStandard(t *testing.T) {
+	t.Parallel()
+
+	hash20 := bytes.Repeat([]byte{0x01}, 20)
+	hash24 := bytes.Repeat([]byte{0x01}, 24)
+	hash32 := bytes.Repeat([]byte{0x01}, 32)
+
+	script := func(builder *txscript.ScriptBuilder) DeliveryAddress {
+		s, err := builder.Script()
+		if err != nil {
+			t.Fatalf("unable to build script: %v", err)
+		}
+		return DeliveryAddress(s)
+	}
+
+	tests := []struct {
+		name     string
+		addr     DeliveryAddress
+		standard bool
+	}{
+		{
+			name: "p2pkh",
+			addr: script(txscript.NewScriptBuilder().
+				AddOp(txscript.OP_DUP).AddOp(txscript.OP_HASH160).
+				AddData(hash20).AddOp(txscript.OP_EQUALVERIFY).
+				AddOp(txscript.OP_CHECKSIG)),
+			standard: true,
+		},
+		{
+			name: "p2sh",
+			addr: script(txscript.NewScriptBuilder().
+				AddOp(txscript.OP_HASH160).AddData(hash20).
+				AddOp(txscript.OP_EQUAL)),
+			standard: true,
+		},
+		{
+			name: "p2wpkh",
+			addr: script(txscript.NewScriptBuilder().
+				AddOp(txscript.OP_0).AddData(hash20)),
+			standard: true,
+		},
+		{
+			name: "p2wsh",
+			addr: script(txscript.NewScriptBuilder().
+				AddOp(txscript.OP_0).AddData(hash32)),
+			standard: true,
+		},
+		{
+			name: "p2pkh missing checksig",
+			addr: script(txscript.NewScriptBuilder().
+				AddOp(txscript.OP_DUP).AddOp(txscript.OP_HASH160).
+				AddData(hash20).AddOp(txscript.OP_EQUALVERIFY).
+				AddOp(txscript.OP_EQUAL)),
+		},
+		{
+			name: "future witness version",
+			addr: script(txscript.NewScriptBuilder().
+				AddOp(txscript.OP_1).AddData(hash32)),
+		},
+		{
+			name: "witness v0 program of non-standard size",
+			addr: script(txscript.NewScriptBuilder().
+				AddOp(txscript.OP_0).AddData(hash24)),
+		},
+		{
+			name: "empty",
+			addr: DeliveryAddress{},
+		},
+	}
+
+	for _, test := range tests {
+		if test.addr.IsStandard() != test.standard {
+			t.Fatalf("%s: expected standard=%v", test.name,
+				test.standard)
+		}
+
+		err := test.addr.Validate()
+		if test.standard && err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		if !test.standard && err != ErrNonStandardDeliveryAddress {
+			t.Fatalf("%s: expected ErrNonStandardDeliveryAddress, "+
+				"got %v", test.name, err)
+		}
+	}
+}
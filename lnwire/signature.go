@@ -6,12 +6,125 @@ import (
 	"github.com/roasbeef/btcd/btcec"
 )
 
+// Sig is a fixed-sized ECDSA signature stored in the 64-byte format specified
+// by the Lightning RFC. A Sig is only parsed once its parsed form is first
+// needed, after which the result is cached. As a CommitSig may carry
+// hundreds of HTLC signatures, this spares the commitment hot path from
+// repeatedly converting each of them between the wire, DER and parsed
+// formats.
+//
+// NOTE: As parsing populates the cache, a Sig must not be used concurrently
+// from multiple goroutines.
+type Sig struct {
+	// raw is the signature in the wire format.
+	raw [64]byte
+
+	// sig is the cached parsed form of the signature, populated upon the
+	// first call to ToSignature.
+	sig *btcec.Signature
+}
+
+// NewSigFromWire creates a new Sig from a signature in the 64-byte wire
+// format. The signature isn't parsed until its parsed form is needed.
+func NewSigFromWire(raw [64]byte) *Sig {
+	return &Sig{raw: raw}
+}
+
+// NewSigFromRawSignature creates a new Sig from a DER encoded signature, such
+// as the ones returned by a Signer. The signature is converted to the wire
+// format without being parsed.
+func NewSigFromRawSignature(sig []byte) (*Sig, error) {
+	var s Sig
+	if err := derToWire(&s.raw, sig); err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+// NewSigFromSignature creates a new Sig from a parsed signature, which is
+// cached along with the wire format.
+func NewSigFromSignature(e *btcec.Signature) (*Sig, error) {
+	if e == nil {
+		return nil, fmt.Errorf("cannot create sig from nil signature")
+	}
+
+	s := &Sig{sig: e}
+	if err := serializeSigToWire(&s.raw, e); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// RawBytes returns the signature in the 64-byte wire format.
+func (s *Sig) RawBytes() [64]byte {
+	return s.raw
+}
+
+// ToSignature returns the parsed form of the signature. The signature is
+// only parsed upon the first call, with subsequent calls returning the
+// cached result.
+func (s *Sig) ToSignature() (*btcec.Signature, error) {
+	if s.sig != nil {
+		return s.sig, nil
+	}
+
+	var sig *btcec.Signature
+	if err := deserializeSigFromWire(&sig, s.raw); err != nil {
+		return nil, err
+	}
+	s.sig = sig
+
+	return sig, nil
+}
+
+// Verify returns true if the signature is a valid signature of the passed
+// hash by the passed public key. A signature that can't be parsed is never
+// valid.
+func (s *Sig) Verify(hash []byte, pubKey *btcec.PublicKey) bool {
+	sig, err := s.ToSignature()
+	if err != nil {
+		return false
+	}
+
+	return sig.Verify(hash, pubKey)
+}
+
+// Serialize returns the DER encoding of the signature, which is the format
+// signatures are persisted in.
+func (s *Sig) Serialize() ([]byte, error) {
+	sig, err := s.ToSignature()
+	if err != nil {
+		return nil, err
+	}
+
+	return sig.Serialize(), nil
+}
+
 // serializeSigToWire serializes a *Signature to [64]byte in the format
 // specified by the Lightning RFC.
 func serializeSigToWire(b *[64]byte, e *btcec.Signature) error {
-
 	// Serialize the signature with all the checks that entails.
-	sig := e.Serialize()
+	return derToWire(b, e.Serialize())
+}
+
+// derToWire converts a DER encoded signature to [64]byte in the format
+// specified by the Lightning RFC, without fully parsing the signature.
+func derToWire(b *[64]byte, sig []byte) error {
+	// As the signature hasn't been parsed, we'll ensure its layout
+	// matches the one described below before indexing into it.
+	if len(sig) < 8 || sig[0] != 0x30 || int(sig[1]) != len(sig)-2 ||
+		sig[2] != 0x02 {
+
+		return fmt.Errorf("malformed DER signature")
+	}
+	derRLen := int(sig[3])
+	if derRLen+6 > len(sig) || sig[4+derRLen] != 0x02 ||
+		derRLen+int(sig[5+derRLen])+6 != len(sig) {
+
+		return fmt.Errorf("malformed DER signature")
+	}
 
 	// Extract lengths of R and S. The DER representation is laid out as
 	// 0x30 <length> 0x02 <length r> r 0x02 <length s> s
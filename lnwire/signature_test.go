@@ -1,6 +1,7 @@
 package lnwire
 
 import (
+	"bytes"
 	"fmt"
 	"math/big"
 	"testing"
@@ -88,3 +89,76 @@ func TestSignatureSerializeDeserialize(t *testing.T) {
 			err.Error())
 	}
 }
+
+// TestSigCaching checks that a Sig created from a DER encoded signature
+// matches the wire format of the parsed signature, and that the signature is
+// only parsed once.
+func TestSigCaching(t *testing.T) {
+	t.Parallel()
+
+	priv, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+	hash := bytes.Repeat([]byte{0x01}, 32)
+	signature, err := priv.Sign(hash)
+	if err != nil {
+		t.Fatalf("unable to sign: %v", err)
+	}
+
+	sig, err := NewSigFromRawSignature(signature.Serialize())
+	if err != nil {
+		t.Fatalf("unable to create sig: %v", err)
+	}
+
+	var expected [64]byte
+	if err := serializeSigToWire(&expected, signature); err != nil {
+		t.Fatalf("unable to serialize sig: %v", err)
+	}
+	if sig.RawBytes() != expected {
+		t.Fatalf("expected wire sig %x, got %x", expected,
+			sig.RawBytes())
+	}
+
+	// The signature shouldn't be parsed until needed, after which the
+	// parsed form should be reused.
+	if sig.sig != nil {
+		t.Fatalf("sig parsed before being needed")
+	}
+	parsed, err := sig.ToSignature()
+	if err != nil {
+		t.Fatalf("unable to parse sig: %v", err)
+	}
+	parsedAgain, err := sig.ToSignature()
+	if err != nil {
+		t.Fatalf("unable to parse sig: %v", err)
+	}
+	if parsed != parsedAgain {
+		t.Fatalf("sig parsed more than once")
+	}
+	if !sig.Verify(hash, priv.PubKey()) {
+		t.Fatalf("unable to verify sig")
+	}
+
+	der, err := sig.Serialize()
+	if err != nil {
+		t.Fatalf("unable to serialize sig: %v", err)
+	}
+	if !bytes.Equal(der, signature.Serialize()) {
+		t.Fatalf("expected DER sig %x, got %x", signature.Serialize(),
+			der)
+	}
+
+	// Malformed DER signatures should be rejected without panicking.
+	malformed := [][]byte{
+		nil,
+		{0x30, 0x06, 0x02, 0x01, 0x01, 0x02, 0x01},
+		{0x30, 0x06, 0x02, 0x05, 0x01, 0x02, 0x01, 0x01},
+		{0x30, 0x06, 0x02, 0x01, 0x01, 0x03, 0x01, 0x01},
+	}
+	for _, der := range malformed {
+		if _, err := NewSigFromRawSignature(der); err == nil {
+			t.Fatalf("expected malformed sig %x to be rejected", der)
+		}
+	}
+}
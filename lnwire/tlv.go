@@ -0,0 +1,88 @@
+package lnwire
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// UpfrontShutdownScriptType is the type of the TLV record carrying the
+	// upfront shutdown script of the sender within the OpenChannel and
+	// AcceptChannel messages. If set, the sender commits to only paying
+	// out to this script when the channel is cooperatively closed.
+	UpfrontShutdownScriptType tlv.Type = 0
+)
+
+// encodeTLVStream appends the passed records to a message as a TLV stream. If
+// no records are passed, then nothing is written, which allows the message to
+// be read by nodes that don't understand TLV streams.
+func encodeTLVStream(w io.Writer, records ...tlv.Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	stream, err := tlv.NewStream(records...)
+	if err != nil {
+		return err
+	}
+
+	return stream.Encode(w)
+}
+
+// decodeTLVStream decodes the TLV stream found at the end of a message into
+// the passed records, returning the types of those that were present. A
+// message without a TLV stream decodes as an empty stream.
+func decodeTLVStream(r io.Reader, records ...tlv.Record) (tlv.TypeSet,
+	error) {
+
+	stream, err := tlv.NewStream(records...)
+	if err != nil {
+		return nil, err
+	}
+
+	return stream.DecodeWithParsedTypes(r)
+}
+
+// upfrontShutdownScriptRecord returns the TLV record of the passed upfront
+// shutdown script.
+func upfrontShutdownScriptRecord(addr *DeliveryAddress) tlv.Record {
+	return tlv.MakeDynamicRecord(
+		UpfrontShutdownScriptType, addr, func() uint64 {
+			return uint64(len(*addr))
+		}, encodeDeliveryAddress, decodeDeliveryAddress,
+	)
+}
+
+// encodeDeliveryAddress is a tlv.Encoder for *DeliveryAddress values.
+func encodeDeliveryAddress(w io.Writer, val interface{}) error {
+	addr, ok := val.(*DeliveryAddress)
+	if !ok {
+		return fmt.Errorf("expected *DeliveryAddress, got %T", val)
+	}
+
+	_, err := w.Write(*addr)
+	return err
+}
+
+// decodeDeliveryAddress is a tlv.Decoder for *DeliveryAddress values. As
+// with delivery addresses outside of a TLV stream, any address larger than 34
+// bytes is rejected.
+func decodeDeliveryAddress(r io.Reader, val interface{}, l uint64) error {
+	addr, ok := val.(*DeliveryAddress)
+	if !ok {
+		return fmt.Errorf("expected *DeliveryAddress, got %T", val)
+	}
+	if l > 34 {
+		return fmt.Errorf("delivery address of %v bytes exceeds "+
+			"maximum of 34 bytes", l)
+	}
+
+	b := make([]byte, l)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return err
+	}
+	*addr = b
+	return nil
+}
@@ -0,0 +1,110 @@
+package lnwire
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+// TestOpenChannelTLVStream checks that the TLV stream appended to an
+// OpenChannel message is decoded according to the rules of BOLT #1.
+func TestOpenChannelTLVStream(t *testing.T) {
+	t.Parallel()
+
+	pubKey, err := randPubKey()
+	if err != nil {
+		t.Fatalf("unable to generate pubkey: %v", err)
+	}
+	msg := &OpenChannel{
+		ChainHash:            *shaHash1,
+		PendingChannelID:     revHash,
+		FundingAmount:        100000000,
+		FundingKey:           pubKey,
+		RevocationPoint:      pubKey,
+		PaymentPoint:         pubKey,
+		DelayedPaymentPoint:  pubKey,
+		FirstCommitmentPoint: pubKey,
+	}
+
+	// Without an upfront shutdown script, no TLV stream is appended, so
+	// the message can be read by nodes which don't understand them.
+	legacyMsg := serializeMessage(t, msg)
+
+	script := bytes.Repeat([]byte{0x01}, 22)
+	tests := []struct {
+		name   string
+		stream []byte
+		script DeliveryAddress
+		fail   bool
+	}{
+		{
+			name: "no stream",
+		},
+		{
+			name:   "upfront shutdown script",
+			stream: append([]byte{0x00, 22}, script...),
+			script: script,
+		},
+		{
+			name: "unknown odd type",
+			stream: append(
+				append([]byte{0x00, 22}, script...),
+				0x03, 0x01, 0x2a,
+			),
+			script: script,
+		},
+		{
+			name:   "unknown even type",
+			stream: []byte{0x02, 0x01, 0x2a},
+			fail:   true,
+		},
+		{
+			name:   "oversized script",
+			stream: append([]byte{0x00, 35}, make([]byte, 35)...),
+			fail:   true,
+		},
+	}
+
+	for _, test := range tests {
+		rawMsg := append(append([]byte{}, legacyMsg...), test.stream...)
+		decoded, err := ReadMessageBytes(rawMsg, 0)
+		if test.fail {
+			if err == nil {
+				t.Fatalf("%s: expected decoding to fail",
+					test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unable to decode message: %v",
+				test.name, err)
+		}
+
+		openChan := decoded.(*OpenChannel)
+		if !bytes.Equal(openChan.UpfrontShutdownScript, test.script) {
+			t.Fatalf("%s: expected script %x, got %x", test.name,
+				test.script, openChan.UpfrontShutdownScript)
+		}
+	}
+
+	// Finally, a message encoded with an upfront shutdown script should
+	// carry it within its TLV stream.
+	msg.UpfrontShutdownScript = script
+	rawMsg := serializeMessage(t, msg)
+	if !bytes.HasPrefix(rawMsg, legacyMsg) {
+		t.Fatalf("fixed fields changed by upfront shutdown script")
+	}
+
+	var decodedScript DeliveryAddress
+	stream := tlv.MustNewStream(upfrontShutdownScriptRecord(&decodedScript))
+	err = stream.Decode(bytes.NewReader(rawMsg[len(legacyMsg):]))
+	if err != nil {
+		t.Fatalf("unable to decode tlv stream: %v", err)
+	}
+	if !reflect.DeepEqual(decodedScript, msg.UpfrontShutdownScript) {
+		t.Fatalf("expected script %x, got %x",
+			msg.UpfrontShutdownScript, decodedScript)
+	}
+}
@@ -32,7 +32,7 @@ type UpdateAddHTLC struct {
 	Expiry uint32
 
 	// Amount is the amount of millisatoshis this HTLC is worth.
-	Amount MilliSatoshi
+	Amount MilliAtom
 
 	// PaymentHash is the payment hash to be included in the HTLC this
 	// request creates. The pre-image to this HTLC must be revelaed by the
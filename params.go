@@ -11,44 +11,52 @@ import (
 var activeNetParams = bitcoinTestNetParams
 
 // bitcoinNetParams couples the p2p parameters of a network with the
-// corresponding RPC port of a daemon running on the particular network.
+// corresponding RPC port of a daemon running on the particular network, and
+// the default port of Lightning nodes on the network.
 type bitcoinNetParams struct {
 	*bitcoinCfg.Params
-	rpcPort string
+	rpcPort  string
+	peerPort int
 }
 
 // litecoinNetParams couples the p2p parameters of a network with the
-// corresponding RPC port of a daemon running on the particular network.
+// corresponding RPC port of a daemon running on the particular network, and
+// the default port of Lightning nodes on the network.
 type litecoinNetParams struct {
 	*litecoinCfg.Params
-	rpcPort string
+	rpcPort  string
+	peerPort int
 }
 
 // bitcoinTestNetParams contains parameters specific to the 3rd version of the
 // test network.
 var bitcoinTestNetParams = bitcoinNetParams{
-	Params:  &bitcoinCfg.TestNet3Params,
-	rpcPort: "18334",
+	Params:   &bitcoinCfg.TestNet3Params,
+	rpcPort:  "18334",
+	peerPort: defaultPeerPort,
 }
 
 // bitcoinSimNetParams contains parameters specific to the simulation test
 // network.
 var bitcoinSimNetParams = bitcoinNetParams{
-	Params:  &bitcoinCfg.SimNetParams,
-	rpcPort: "18556",
+	Params:   &bitcoinCfg.SimNetParams,
+	rpcPort:  "18556",
+	peerPort: defaultPeerPort,
 }
 
 // liteTestNetParams contains parameters specific to the 4th version of the
 // test network.
 var liteTestNetParams = litecoinNetParams{
-	Params:  &litecoinCfg.TestNet4Params,
-	rpcPort: "19334",
+	Params:   &litecoinCfg.TestNet4Params,
+	rpcPort:  "19334",
+	peerPort: defaultPeerPort,
 }
 
 // regTestNetParams contains parameters specific to a local regtest network.
 var regTestNetParams = bitcoinNetParams{
-	Params:  &bitcoinCfg.RegressionNetParams,
-	rpcPort: "18334",
+	Params:   &bitcoinCfg.RegressionNetParams,
+	rpcPort:  "18334",
+	peerPort: defaultPeerPort,
 }
 
 // applyLitecoinParams applies the relevant chain configuration parameters that
@@ -76,4 +84,5 @@ func applyLitecoinParams(params *bitcoinNetParams) {
 	params.HDCoinType = liteTestNetParams.HDCoinType
 
 	params.rpcPort = liteTestNetParams.rpcPort
+	params.peerPort = liteTestNetParams.peerPort
 }
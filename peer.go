@@ -2,6 +2,7 @@ package main
 
 import (
 	"container/list"
+	"encoding/binary"
 	"fmt"
 	"net"
 	"strings"
@@ -37,6 +38,14 @@ const (
 	// pingInterval is the interval at which ping messages are sent.
 	pingInterval = 1 * time.Minute
 
+	// pongMisbehavior is the number of misbehavior points assigned to a
+	// peer for each unsolicited or malformed pong.
+	pongMisbehavior = 10
+
+	// maxMisbehavior is the misbehavior score at which a peer is
+	// disconnected.
+	maxMisbehavior = 100
+
 	// outgoingQueueLen is the buffer size of the channel which houses
 	// messages to be sent across the wire, requested by objects outside
 	// this struct.
@@ -85,6 +94,15 @@ type peer struct {
 	// our last ping message.
 	pingLastSend int64
 
+	// pongExpected is one more than the number of pong bytes requested
+	// within our last ping message, or zero if we aren't awaiting a pong.
+	pongExpected uint32
+
+	// misbehaviorScore accumulates the misbehavior points assigned to the
+	// peer for protocol violations which don't warrant disconnecting it
+	// outright. Once it reaches maxMisbehavior, the peer is disconnected.
+	misbehaviorScore uint32
+
 	// MUST be used atomically.
 	started    int32
 	disconnect int32
@@ -146,6 +164,10 @@ type peer struct {
 	// on both sides.
 	globalSharedFeatures *lnwire.SharedFeatures
 
+	// quirks tracks the detected implementation of the remote peer, and
+	// determines which interoperability workarounds are applied to it.
+	quirks *peerQuirks
+
 	queueQuit chan struct{}
 	quit      chan struct{}
 	wg        sync.WaitGroup
@@ -181,6 +203,8 @@ func newPeer(conn net.Conn, connReq *connmgr.ConnReq, server *server,
 		localSharedFeatures:  nil,
 		globalSharedFeatures: nil,
 
+		quirks: newPeerQuirks(server.disabledQuirks),
+
 		queueQuit: make(chan struct{}),
 		quit:      make(chan struct{}),
 	}
@@ -308,6 +332,25 @@ func (p *peer) loadActiveChannels(chans []*channeldb.OpenChannel) error {
 			return fmt.Errorf("peer shutting down")
 		}
 
+		// If the channel failed the startup consistency check, then
+		// we won't add a link for it to the switch, as its state can't
+		// be trusted.
+		if _, ok := p.server.inconsistentChans[*chanPoint]; ok {
+			peerLog.Warnf("peerID(%v) not adding link for "+
+				"inconsistent ChannelPoint(%v)", p.id, chanPoint)
+			continue
+		}
+
+		// Similarly, channels which have hit an unrecoverable error
+		// within their state machine are frozen, so they're never
+		// added to the switch.
+		if !dbChan.HasChanStatus(channeldb.ChanStatusDefault) {
+			peerLog.Warnf("peerID(%v) not adding link for "+
+				"ChannelPoint(%v) with status %v", p.id,
+				chanPoint, dbChan.ChanStatus)
+			continue
+		}
+
 		blockEpoch, err := p.server.cc.chainNotifier.RegisterBlockEpochNtfn()
 		if err != nil {
 			return err
@@ -351,6 +394,9 @@ func (p *peer) loadActiveChannels(chans []*channeldb.OpenChannel) error {
 				FeeRate:       selfPolicy.FeeProportionalMillionths,
 				TimeLockDelta: uint32(selfPolicy.TimeLockDelta),
 			}
+			if selfPolicy.MessageFlags.HasMaxHtlc() {
+				forwardingPolicy.MaxHTLC = selfPolicy.MaxHTLC
+			}
 		} else {
 			forwardingPolicy = &p.server.cc.routingPolicy
 		}
@@ -369,9 +415,15 @@ func (p *peer) loadActiveChannels(chans []*channeldb.OpenChannel) error {
 			SettledContracts: p.server.breachArbiter.settledContracts,
 			DebugHTLC:        cfg.DebugHTLC,
 			Registry:         p.server.invoices,
+			PreimageCache:    p.server.witnessBeacon,
+			HodlStore:        p.server.chanDB,
 			Switch:           p.server.htlcSwitch,
 			FwrdingPolicy:    *forwardingPolicy,
 			BlockEpochs:      blockEpoch,
+			MaxDustExposure:  maxDustExposure(),
+			BatchInterval:    cfg.LinkBatchInterval,
+			ExpiryGraceDelta: cfg.ExpiryGraceDelta,
+			HodlMask:         cfg.Hodl.Mask(),
 		}
 		link := htlcswitch.NewChannelLink(linkCfg, lnChan,
 			uint32(currentHeight))
@@ -426,17 +478,35 @@ func (p *peer) readNextMessage() (lnwire.Message, error) {
 	// reading incrementally from the stream as the Lightning wire protocol
 	// is message oriented and allows nodes to pad on additional data to
 	// the message stream.
-	rawMsg, err := noiseConn.ReadNextMessage()
+	rawMsg, buf, err := noiseConn.ReadNextMessageBuf()
 	atomic.AddUint64(&p.bytesReceived, uint64(len(rawMsg)))
 	if err != nil {
 		return nil, err
 	}
+	defer brontide.ReleaseBuffer(buf)
 
-	// Next, create a new io.Reader implementation from the raw message,
-	// and use this to decode the message directly from.
-	msgReader := bytes.NewReader(rawMsg)
-	nextMsg, err := lnwire.ReadMessage(msgReader, 0)
+	// Next, decode the message directly from the raw message bytes, which
+	// allows its fields to be sliced out of the buffer rather than copied.
+	// As the decoded message never references the raw message, the buffer
+	// can be handed back to the pool once we return.
+	nextMsg, err := lnwire.ReadMessageBytes(rawMsg, 0)
 	if err != nil {
+		// If this is a gossip message which we failed to parse, and
+		// we're lenient towards this peer's gossip, then we'll signal
+		// that the message should be skipped rather than treating the
+		// error as fatal.
+		if len(rawMsg) >= 2 && p.quirks.Applies(quirkLenientGossip) {
+			msgType := lnwire.MessageType(
+				binary.BigEndian.Uint16(rawMsg[:2]),
+			)
+			if isGossipMsgType(msgType) {
+				return nil, &malformedGossipError{
+					msgType: msgType,
+					err:     err,
+				}
+			}
+		}
+
 		return nil, err
 	}
 
@@ -589,21 +659,38 @@ out:
 	for atomic.LoadInt32(&p.disconnect) == 0 {
 		nextMsg, err := p.readNextMessage()
 		if err != nil {
-			peerLog.Infof("unable to read message from %v: %v",
-				p, err)
-
-			switch err.(type) {
-			// If this is just a message we don't yet recognize,
-			// we'll continue processing as normal as this allows
-			// us to introduce new messages in a forwards
-			// compatible manner.
+			switch e := err.(type) {
+			// If this is just a message we don't yet recognize
+			// with an odd type, we'll continue processing as
+			// normal as this allows us to introduce new messages
+			// in a forwards compatible manner. Unknown messages
+			// with an even type are required however, so we'll
+			// disconnect the peer.
 			case *lnwire.UnknownMessage:
+				if e.IsOdd() {
+					peerLog.Debugf("Skipping unknown odd "+
+						"message type %d from %v",
+						uint16(e.MessageType()), p)
+					continue
+				}
+
+				peerLog.Infof("unable to read message from "+
+					"%v: %v", p, err)
+				break out
+
+			// If this is a malformed gossip message from a peer
+			// we're lenient towards, then we'll skip it.
+			case *malformedGossipError:
+				peerLog.Infof("unable to read message from "+
+					"%v: %v", p, err)
 				continue
 
 			// If the error we encountered wasn't just a message we
 			// didn't recognize, then we'll stop all processing s
 			// this is a fatal error.
 			default:
+				peerLog.Infof("unable to read message from "+
+					"%v: %v", p, err)
 				break out
 			}
 		}
@@ -615,15 +702,19 @@ out:
 
 		switch msg := nextMsg.(type) {
 		case *lnwire.Pong:
-			// When we receive a Pong message in response to our
-			// last ping message, we'll use the time in which we
-			// sent the ping message to measure a rough estimate of
-			// round trip time.
-			pingSendTime := atomic.LoadInt64(&p.pingLastSend)
-			delay := (time.Now().UnixNano() - pingSendTime) / 1000
-			atomic.StoreInt64(&p.pingTime, delay)
+			if err := p.handlePong(msg); err != nil {
+				peerLog.Infof("Disconnecting %v: %v", p, err)
+				break out
+			}
 
 		case *lnwire.Ping:
+			// Pings requesting more pong bytes than can ever be
+			// sent are only cover traffic, and aren't responded
+			// to.
+			if msg.NumPongBytes > lnwire.MaxPongBytes {
+				break
+			}
+
 			pongBytes := make([]byte, msg.NumPongBytes)
 			p.queueMsg(lnwire.NewPong(pongBytes), nil)
 
@@ -644,8 +735,34 @@ out:
 			p.closingSignedChanReqs <- msg
 
 		case *lnwire.Error:
+			p.quirks.DetectFromError(msg)
+
+			// If the error refers to one of our active channels,
+			// then it doesn't concern the funding manager. A
+			// temporary error requires no further action, while
+			// any other error signals that the remote party
+			// considers the channel failed.
+			p.activeChanMtx.RLock()
+			_, isActive := p.activeChannels[msg.ChanID]
+			p.activeChanMtx.RUnlock()
+			if isActive {
+				if msg.IsTemporary() {
+					peerLog.Warnf("Received temporary error "+
+						"from %v: %v", p, msg)
+					break
+				}
+
+				peerLog.Errorf("ChannelID(%v) failed by "+
+					"remote peer %v, channel should be "+
+					"abandoned: %v", msg.ChanID, p, msg)
+				break
+			}
+
 			p.server.fundingMgr.processFundingError(msg, p.addr)
 
+		case *lnwire.ChannelReestablish:
+			p.handleChanSync(msg)
+
 		// TODO(roasbeef): create ChanUpdater interface for the below
 		case *lnwire.UpdateAddHTLC:
 			isChanUpdate = true
@@ -673,6 +790,15 @@ out:
 
 			p.server.authGossiper.ProcessRemoteAnnouncement(msg,
 				p.addr.IdentityKey)
+
+		// Custom messages aren't interpreted by the daemon, instead
+		// they're handed to any subscribed applications.
+		case *lnwire.Custom:
+			sendEvent(p.server.eventBus, customMessageEvent{
+				peer: p.pubKeyBytes,
+				msg:  msg,
+			})
+
 		default:
 			peerLog.Errorf("unknown message received from peer "+
 				"%v", p)
@@ -718,6 +844,10 @@ func (p *peer) logWireMessage(msg lnwire.Message, read bool) {
 	switch m := msg.(type) {
 	case *lnwire.RevokeAndAck:
 		m.NextRevocationKey.Curve = nil
+	case *lnwire.ChannelReestablish:
+		if m.LocalUnrevokedCommitPoint != nil {
+			m.LocalUnrevokedCommitPoint.Curve = nil
+		}
 	case *lnwire.NodeAnnouncement:
 		m.NodeID.Curve = nil
 	case *lnwire.ChannelAnnouncement:
@@ -762,18 +892,12 @@ func (p *peer) writeMessage(msg lnwire.Message) error {
 	p.logWireMessage(msg, false)
 
 	// As the Lightning wire protocol is fully message oriented, we only
-	// allows one wire message per outer encapsulated crypto message. So
-	// we'll create a temporary buffer to write the message directly to.
-	var msgPayload [lnwire.MaxMessagePayload]byte
-	b := bytes.NewBuffer(msgPayload[0:0:len(msgPayload)])
-
-	// With the temp buffer created and sliced properly (length zero, full
-	// capacity), we'll now encode the message directly into this buffer.
-	n, err := lnwire.WriteMessage(b, msg, 0)
+	// allows one wire message per outer encapsulated crypto message. The
+	// message is serialized into a pooled buffer, and written out in a
+	// single swoop, so we can write it directly to the connection.
+	n, err := lnwire.WriteMessage(p.conn, msg, 0)
 	atomic.AddUint64(&p.bytesSent, uint64(n))
 
-	// Finally, write the message itself in a single swoop.
-	_, err = p.conn.Write(b.Bytes())
 	return err
 }
 
@@ -791,16 +915,21 @@ func (p *peer) writeHandler() {
 	for {
 		select {
 		case outMsg := <-p.sendQueue:
-			switch outMsg.msg.(type) {
+			switch msg := outMsg.msg.(type) {
 			// If we're about to send a ping message, then log the
 			// exact time in which we send the message so we can
 			// use the delay as a rough estimate of latency to the
-			// remote peer.
+			// remote peer. We'll also note the number of bytes
+			// the pong response should carry.
 			case *lnwire.Ping:
 				// TODO(roasbeef): do this before the write?
 				// possibly account for processing within func?
 				now := time.Now().UnixNano()
 				atomic.StoreInt64(&p.pingLastSend, now)
+				atomic.StoreUint32(
+					&p.pongExpected,
+					uint32(msg.NumPongBytes)+1,
+				)
 			}
 
 			// Write out the message to the socket, closing the
@@ -886,7 +1015,10 @@ out:
 	for {
 		select {
 		case <-pingTicker.C:
-			p.queueMsg(lnwire.NewPing(numPingBytes), nil)
+			ping := lnwire.NewPingWithPadding(
+				numPingBytes, p.server.pingPadBytes,
+			)
+			p.queueMsg(ping, nil)
 		case <-p.quit:
 			break out
 		}
@@ -898,6 +1030,54 @@ func (p *peer) PingTime() int64 {
 	return atomic.LoadInt64(&p.pingTime)
 }
 
+// handlePong validates a pong sent by the remote peer, which is only valid in
+// response to our last ping, and must carry exactly the number of bytes it
+// requested. Unsolicited or malformed pongs increase the peer's misbehavior
+// score, and an error is returned once the peer should be disconnected for
+// it. Otherwise, the pong is used to estimate the round trip time to the
+// peer.
+func (p *peer) handlePong(msg *lnwire.Pong) error {
+	expected := atomic.SwapUint32(&p.pongExpected, 0)
+	if expected == 0 {
+		return p.addMisbehavior(pongMisbehavior, "unsolicited pong")
+	}
+	if len(msg.PongBytes) != int(expected-1) {
+		return p.addMisbehavior(pongMisbehavior, fmt.Sprintf("pong of "+
+			"%d bytes, expected %d", len(msg.PongBytes),
+			expected-1))
+	}
+
+	// When we receive a Pong message in response to our last ping
+	// message, we'll use the time in which we sent the ping message to
+	// measure a rough estimate of round trip time.
+	pingSendTime := atomic.LoadInt64(&p.pingLastSend)
+	delay := (time.Now().UnixNano() - pingSendTime) / 1000
+	atomic.StoreInt64(&p.pingTime, delay)
+
+	return nil
+}
+
+// addMisbehavior increases the misbehavior score of the peer by the passed
+// number of points, logging the reason for doing so. An error is returned if
+// the score has reached maxMisbehavior, in which case the peer should be
+// disconnected.
+func (p *peer) addMisbehavior(points uint32, reason string) error {
+	score := atomic.AddUint32(&p.misbehaviorScore, points)
+	peerLog.Warnf("Misbehavior by %v: %v, score now %d", p, reason, score)
+
+	if score >= maxMisbehavior {
+		return fmt.Errorf("misbehavior score of %d reached limit of %d",
+			score, maxMisbehavior)
+	}
+
+	return nil
+}
+
+// MisbehaviorScore returns the accumulated misbehavior score of the peer.
+func (p *peer) MisbehaviorScore() uint32 {
+	return atomic.LoadUint32(&p.misbehaviorScore)
+}
+
 // queueMsg queues a new lnwire.Message to be eventually sent out on the
 // wire.
 func (p *peer) queueMsg(msg lnwire.Message, doneChan chan struct{}) {
@@ -962,8 +1142,43 @@ func (p *peer) channelManager() {
 	responderShutdownSigs := make(map[lnwire.ChannelID][]byte)
 	responderFeeProposals := make(map[lnwire.ChannelID]uint64)
 
+	// remoteFeeProposals holds the fee proposed within the last
+	// ClosingSigned received from the peer, allowing us to ensure each new
+	// proposal is a valid step within the fee negotiation.
+	remoteFeeProposals := make(map[lnwire.ChannelID]uint64)
+
 	// TODO(roasbeef): move to cfg closure func
-	genDeliveryScript := func() ([]byte, error) {
+	genDeliveryScript := func(chanID lnwire.ChannelID,
+		requested []byte) ([]byte, error) {
+
+		// If we committed to an upfront shutdown script when the
+		// channel was funded, then we're bound to pay out to it, so any
+		// other requested script is rejected.
+		p.activeChanMtx.RLock()
+		channel, ok := p.activeChannels[chanID]
+		p.activeChanMtx.RUnlock()
+		if ok {
+			script := channel.LocalUpfrontShutdownScript()
+			if len(script) != 0 {
+				if len(requested) != 0 &&
+					!bytes.Equal(requested, script) {
+
+					return nil, fmt.Errorf("delivery "+
+						"script %x doesn't match "+
+						"upfront shutdown script %x",
+						requested, script)
+				}
+
+				return script, nil
+			}
+		}
+
+		// Otherwise, we'll pay out to the requested script if one was
+		// specified.
+		if len(requested) != 0 {
+			return requested, nil
+		}
+
 		deliveryAddr, err := p.server.cc.wallet.NewAddress(
 			lnwallet.WitnessPubKey, false,
 		)
@@ -1019,9 +1234,15 @@ out:
 				SettledContracts: p.server.breachArbiter.settledContracts,
 				DebugHTLC:        cfg.DebugHTLC,
 				Registry:         p.server.invoices,
+				PreimageCache:    p.server.witnessBeacon,
+				HodlStore:        p.server.chanDB,
 				Switch:           p.server.htlcSwitch,
 				FwrdingPolicy:    p.server.cc.routingPolicy,
 				BlockEpochs:      blockEpoch,
+				MaxDustExposure:  maxDustExposure(),
+				BatchInterval:    cfg.LinkBatchInterval,
+				ExpiryGraceDelta: cfg.ExpiryGraceDelta,
+				HodlMask:         cfg.Hodl.Mask(),
 			}
 			link := htlcswitch.NewChannelLink(linkConfig, newChan,
 				uint32(currentHeight))
@@ -1054,11 +1275,13 @@ out:
 				chanShutdowns[chanID] = req
 
 				// As we need to close out the channel and
-				// claim our funds on-chain, we'll request a
-				// new delivery address from the wallet, and
-				// turn that into it corresponding output
-				// script.
-				deliveryScript, err = genDeliveryScript()
+				// claim our funds on-chain, we'll use the
+				// requested delivery script, or request a new
+				// delivery address from the wallet, and turn
+				// that into it corresponding output script.
+				deliveryScript, err = genDeliveryScript(
+					chanID, req.DeliveryScript,
+				)
 				if err != nil {
 					cErr := fmt.Errorf("Unable to generate "+
 						"delivery address: %v", err)
@@ -1093,7 +1316,7 @@ out:
 			// ID, then we'll ignore this message.
 			chanID := req.ChannelID
 			p.activeChanMtx.Lock()
-			_, ok := p.activeChannels[chanID]
+			channel, ok := p.activeChannels[chanID]
 			p.activeChanMtx.Unlock()
 			if !ok {
 				peerLog.Warnf("Received unsolicited shutdown msg: %v",
@@ -1101,6 +1324,43 @@ out:
 				continue
 			}
 
+			// The remote party may only close the channel to a
+			// standard script, as otherwise the closing
+			// transaction would fail to relay. If they committed
+			// to an upfront shutdown script when the channel was
+			// funded, then they may only close the channel to
+			// that script.
+			var err error
+			upfrontScript := channel.RemoteUpfrontShutdownScript()
+			switch {
+			case !req.Address.IsStandard():
+				err = fmt.Errorf("shutdown script %x is not a "+
+					"standard script", req.Address)
+
+			case len(upfrontScript) != 0 &&
+				!bytes.Equal(upfrontScript, req.Address):
+
+				err = fmt.Errorf("shutdown script %x doesn't "+
+					"match upfront shutdown script %x",
+					req.Address, upfrontScript)
+			}
+			if err != nil {
+				peerLog.Errorf("Rejecting shutdown for "+
+					"ChannelID(%v): %v", chanID, err)
+
+				p.queueMsg(&lnwire.Error{
+					ChanID: chanID,
+					Data:   lnwire.ErrorData(err.Error()),
+				}, nil)
+
+				if localReq, ok := chanShutdowns[chanID]; ok {
+					localReq.Err <- err
+					delete(chanShutdowns, chanID)
+				}
+				delete(deliveryAddrs, chanID)
+				continue
+			}
+
 			// First, we'll track their delivery script for when we
 			// ultimately create the cooperative closure
 			// transaction.
@@ -1128,7 +1388,7 @@ out:
 
 				// As we're the responder, we'll need to
 				// generate a delivery script of our own.
-				deliveryScript, err := genDeliveryScript()
+				deliveryScript, err := genDeliveryScript(chanID, nil)
 				if err != nil {
 					peerLog.Errorf("Unable to generate "+
 						"delivery address: %v", err)
@@ -1176,13 +1436,16 @@ out:
 				closeSig, proposedFee := p.handleClosingSigned(
 					localCloseReq, req,
 					deliveryAddrs[chanID], initiatorSig,
-					initiatorFeeProposals[req.ChannelID])
+					initiatorFeeProposals[req.ChannelID],
+					remoteFeeProposals[req.ChannelID])
 				if closeSig != nil {
 					initiatorShutdownSigs[req.ChannelID] = closeSig
 					initiatorFeeProposals[req.ChannelID] = proposedFee
+					remoteFeeProposals[req.ChannelID] = req.FeeSatoshis
 				} else {
 					delete(initiatorShutdownSigs, req.ChannelID)
 					delete(initiatorFeeProposals, req.ChannelID)
+					delete(remoteFeeProposals, req.ChannelID)
 					delete(chanShutdowns, req.ChannelID)
 					delete(deliveryAddrs, req.ChannelID)
 				}
@@ -1199,13 +1462,16 @@ out:
 			// updates, so just pass in nil instead.
 			closeSig, proposedFee := p.handleClosingSigned(nil, req,
 				deliveryAddrs[chanID], responderSig,
-				responderFeeProposals[req.ChannelID])
+				responderFeeProposals[req.ChannelID],
+				remoteFeeProposals[req.ChannelID])
 			if closeSig != nil {
 				responderShutdownSigs[req.ChannelID] = closeSig
 				responderFeeProposals[req.ChannelID] = proposedFee
+				remoteFeeProposals[req.ChannelID] = req.FeeSatoshis
 			} else {
 				delete(responderShutdownSigs, req.ChannelID)
 				delete(responderFeeProposals, req.ChannelID)
+				delete(remoteFeeProposals, req.ChannelID)
 				delete(deliveryAddrs, chanID)
 			}
 
@@ -1318,45 +1584,97 @@ func (p *peer) handleShutdownResponse(msg *lnwire.Shutdown,
 	return closeSig, proposedFee
 }
 
+// closeFeeBounds describes the range of closing transaction fees we're
+// willing to negotiate, relative to our ideal fee as given by the fee
+// estimator.
+type closeFeeBounds struct {
+	// maxFeeMultiple bounds the fees we'll agree to within
+	// [ourIdealFee/maxFeeMultiple, ourIdealFee*maxFeeMultiple].
+	maxFeeMultiple float64
+
+	// acceptWindow is the fraction of our ideal fee the peer's proposal
+	// may deviate from it while still being accepted outright, rather than
+	// countered with a compromise.
+	acceptWindow float64
+}
+
 // calculateCompromiseFee performs the current fee negotiation algorithm,
 // taking into consideration our ideal fee based on current fee environment,
-// the fee we last proposed (if any), and the fee proposed by the peer.
-func calculateCompromiseFee(ourIdealFee, lastSentFee, peerFee uint64) uint64 {
-	// We will accept a proposed fee in the interval
-	// [0.5*ourIdealFee, 2*ourIdealFee]. If the peer's fee doesn't fall in
-	// this range, we'll propose the average of the peer's fee and our last
-	// sent fee, as long as it is in this range.
-	// TODO(halseth): Dynamic fee to determine what we consider min/max for
-	// timely confirmation.
-	maxFee := 2 * ourIdealFee
-	minFee := ourIdealFee / 2
-
-	// If we didn't propose a fee before, just use our ideal fee value for
-	// the average calculation.
+// the fee we last proposed (if any), the fee proposed by the peer and the
+// bounds within which we negotiate. Unless the peer's fee is accepted, the
+// returned fee lies strictly between the fee we last proposed and the peer's,
+// so the negotiation converges even as our ideal fee shifts between rounds.
+// If no such fee lies within our bounds, then an error is returned.
+func calculateCompromiseFee(ourIdealFee, lastSentFee, peerFee uint64,
+	bounds closeFeeBounds) (uint64, error) {
+
+	ideal := float64(ourIdealFee)
+	maxFee := uint64(ideal * bounds.maxFeeMultiple)
+	minFee := uint64(ideal / bounds.maxFeeMultiple)
+
+	// We'll accept the peer's fee outright if it's within our accept
+	// window around our ideal fee, which itself can't extend beyond the
+	// bounds we'll agree to.
+	maxAccept := uint64(ideal + ideal*bounds.acceptWindow)
+	if maxAccept > maxFee {
+		maxAccept = maxFee
+	}
+	minAccept := minFee
+	if window := uint64(ideal * bounds.acceptWindow); window < ourIdealFee {
+		minAccept = ourIdealFee - window
+	}
+	if minAccept < minFee {
+		minAccept = minFee
+	}
+	if peerFee >= minAccept && peerFee <= maxAccept {
+		return peerFee, nil
+	}
+
+	// If we've already proposed a fee, then our new proposal must lie
+	// strictly between it and the peer's, while also being within our
+	// bounds.
+	if lastSentFee != 0 {
+		low, high := lastSentFee, peerFee
+		if low > high {
+			low, high = high, low
+		}
+		if high-low < 2 {
+			return 0, fmt.Errorf("no fee lies between our last "+
+				"proposal of %v and the peer's proposal of %v",
+				lastSentFee, peerFee)
+		}
+
+		low, high = low+1, high-1
+		if low > minFee {
+			minFee = low
+		}
+		if high < maxFee {
+			maxFee = high
+		}
+		if minFee > maxFee {
+			return 0, fmt.Errorf("no fee between our last "+
+				"proposal of %v and the peer's proposal of %v "+
+				"lies within our bounds", lastSentFee, peerFee)
+		}
+	}
+
+	// Otherwise, we'll propose the average of the peer's fee and our last
+	// sent fee, using our ideal fee if we didn't propose a fee before, as
+	// long as it's within our bounds.
 	if lastSentFee == 0 {
 		lastSentFee = ourIdealFee
 	}
 	avgFee := (lastSentFee + peerFee) / 2
 
 	switch {
-	case peerFee <= maxFee && peerFee >= minFee:
-		// Peer fee is in the accepted range.
-		return peerFee
-	case avgFee <= maxFee && avgFee >= minFee:
-		// The peer's fee is not in the accepted range, but the average
-		// fee is.
-		return avgFee
 	case avgFee > maxFee:
 		// TODO(halseth): We must ensure fee is not higher than the
 		// current fee on the commitment transaction.
-
-		// We cannot accept the average fee, as it is more than twice
-		// our own estimate. Set our proposed to the maximum we can
-		// accept.
-		return maxFee
+		return maxFee, nil
+	case avgFee < minFee:
+		return minFee, nil
 	default:
-		// Cannot accept the average, as we consider it too low.
-		return minFee
+		return avgFee, nil
 	}
 }
 
@@ -1371,7 +1689,8 @@ func calculateCompromiseFee(ourIdealFee, lastSentFee, peerFee uint64) uint64 {
 // reports status back to the caller if this was a local shutdown request).
 //
 // It returns the signature and the proposed fee included in the ClosingSigned
-// sent to the peer.
+// sent to the peer. The fee proposed within the last ClosingSigned received
+// from the peer (if any) should be passed as lastPeerFee.
 //
 // Following the broadcast, both the initiator and responder in the channel
 // closure workflow should watch the blockchain for a confirmation of the
@@ -1381,7 +1700,7 @@ func calculateCompromiseFee(ourIdealFee, lastSentFee, peerFee uint64) uint64 {
 // closure.
 func (p *peer) handleClosingSigned(localReq *htlcswitch.ChanClose,
 	msg *lnwire.ClosingSigned, deliveryScripts *closingScripts,
-	lastSig []byte, lastFee uint64) ([]byte, uint64) {
+	lastSig []byte, lastFee, lastPeerFee uint64) ([]byte, uint64) {
 
 	chanID := msg.ChannelID
 	p.activeChanMtx.RLock()
@@ -1402,7 +1721,7 @@ func (p *peer) handleClosingSigned(localReq *htlcswitch.ChanClose,
 	// with our new proposed fee. In case we can agree on a fee, it will
 	// assemble the close transaction, and we can go on to broadcasting it.
 	closeTx, ourSig, ourFee, err := p.negotiateFeeAndCreateCloseTx(channel,
-		msg, deliveryScripts, lastSig, lastFee)
+		msg, deliveryScripts, lastSig, lastFee, lastPeerFee)
 	if err != nil {
 		if localReq != nil {
 			localReq.Err <- err
@@ -1559,17 +1878,33 @@ func (p *peer) handleClosingSigned(localReq *htlcswitch.ChanClose,
 // case the peer propose a fee different from our previous proposal, but that
 // can be accepted, a ClosingSigned message with the accepted fee is sent,
 // before the closing transaction is broadcasted. In the case where we cannot
-// accept the peer's proposed fee, a new fee proposal will be sent.
-//
-// TODO(halseth): In the case where we cannot accept the fee, and we cannot
-// make more proposals, this method should return an error, and we should fail
-// the channel.
+// accept the peer's proposed fee, a new fee proposal will be sent. Should we
+// be unable to move our proposal any closer to the peer's, as it's beyond the
+// bounds we're willing to agree to, then an error is returned.
 func (p *peer) negotiateFeeAndCreateCloseTx(channel *lnwallet.LightningChannel,
 	msg *lnwire.ClosingSigned, deliveryScripts *closingScripts, ourSig []byte,
-	ourFeeProp uint64) (*wire.MsgTx, []byte, uint64, error) {
+	ourFeeProp, peerLastFeeProp uint64) (*wire.MsgTx, []byte, uint64, error) {
 
 	peerFeeProposal := msg.FeeSatoshis
 
+	// Before considering the peer's proposal, we'll ensure it's a valid
+	// step within the fee negotiation, unless we tolerate non-canonical
+	// fee steps from this peer.
+	if !isCanonicalCloseFee(peerFeeProposal, ourFeeProp, peerLastFeeProp) {
+		if !p.quirks.Applies(quirkCloseFeeSteps) {
+			err := fmt.Errorf("peer %v proposed fee of %v for "+
+				"ChannelID(%v), which isn't between our last "+
+				"proposal of %v and its last proposal of %v",
+				p, peerFeeProposal, msg.ChannelID, ourFeeProp,
+				peerLastFeeProp)
+			peerLog.Error(err)
+			return nil, nil, 0, err
+		}
+
+		peerLog.Debugf("Tolerating non-canonical fee proposal of %v "+
+			"from %v peer %v", peerFeeProposal, p.quirks.Impl(), p)
+	}
+
 	// If the fee proposed by the peer is different from what we proposed
 	// before (or we did not propose anything yet), we must check if we can
 	// accept the proposal, or if we should negotiate.
@@ -1579,21 +1914,21 @@ func (p *peer) negotiateFeeAndCreateCloseTx(channel *lnwallet.LightningChannel,
 		ourIdealFeeRate := p.server.cc.feeEstimator.
 			EstimateFeePerWeight(1) * 1000
 		ourIdealFee := channel.CalcFee(ourIdealFeeRate)
-		fee := calculateCompromiseFee(ourIdealFee, ourFeeProp,
-			peerFeeProposal)
-
-		// Our new proposed fee must be strictly between what we
-		// proposed before and what the peer proposed.
-		isAcceptable := false
-		if fee < peerFeeProposal && fee > ourFeeProp {
-			isAcceptable = true
-		}
-		if fee < ourFeeProp && fee > peerFeeProposal {
-			isAcceptable = true
-		}
+		fee, err := calculateCompromiseFee(
+			ourIdealFee, ourFeeProp, peerFeeProposal,
+			p.server.closeFeeBounds,
+		)
 
-		if !isAcceptable {
-			// TODO(halseth): fail channel
+		// If we're unable to move our proposal any closer to the
+		// peer's without going beyond the bounds we're willing to
+		// agree to, then the negotiation can't make any further
+		// progress.
+		if err != nil {
+			err = fmt.Errorf("unable to agree on closing fee "+
+				"with peer %v for ChannelID(%v): %v", p,
+				msg.ChannelID, err)
+			peerLog.Error(err)
+			return nil, nil, 0, err
 		}
 
 		// Since the compromise fee is different from the fee we last
@@ -1737,9 +2072,69 @@ func (p *peer) WipeChannel(channel *lnwallet.LightningChannel) error {
 	return nil
 }
 
+// handleChanSync handles a ChannelReestablish message sent by the remote
+// peer. If the data loss protection fields it carries prove that we've lost
+// state for the channel, then our latest commitment has already been revoked.
+// In this case the channel is marked as such and taken out of the switch,
+// ensuring we never broadcast the stale commitment, and the remote peer is
+// asked to close the channel instead.
+func (p *peer) handleChanSync(msg *lnwire.ChannelReestablish) {
+	p.activeChanMtx.RLock()
+	channel, ok := p.activeChannels[msg.ChanID]
+	p.activeChanMtx.RUnlock()
+	if !ok {
+		peerLog.Warnf("Received ChannelReestablish for unknown "+
+			"ChannelID(%v) from %v", msg.ChanID, p)
+		return
+	}
+
+	err := channel.CheckDataLoss(msg)
+	switch err {
+	case nil:
+		return
+
+	// The remote peer has proven that we've lost state, so we'll mark the
+	// channel to ensure we won't broadcast our commitment, and wait for
+	// the remote peer to close the channel.
+	case lnwallet.ErrCommitSyncLocalDataLoss:
+		peerLog.Errorf("ChannelID(%v) has lost local state, waiting "+
+			"for %v to close the channel", msg.ChanID, p)
+
+		if err := channel.MarkDataLoss(); err != nil {
+			peerLog.Errorf("unable to mark ChannelID(%v) with "+
+				"data loss: %v", msg.ChanID, err)
+		}
+		if err := p.WipeChannel(channel); err != nil {
+			peerLog.Errorf("unable to remove ChannelID(%v) "+
+				"from switch: %v", msg.ChanID, err)
+		}
+
+		p.queueMsg(&lnwire.Error{
+			ChanID: msg.ChanID,
+			Data:   lnwire.ErrorData(err.Error()),
+		}, nil)
+
+	// Otherwise, the remote peer claimed a newer state without being able
+	// to prove it. We don't act upon the claim, so our commitment remains
+	// safe to broadcast.
+	default:
+		peerLog.Warnf("Ignoring ChannelReestablish for "+
+			"ChannelID(%v) from %v: %v", msg.ChanID, p, err)
+	}
+}
+
 // handleInitMsg handles the incoming init message which contains global and
 // local features vectors. If feature vectors are incompatible then disconnect.
 func (p *peer) handleInitMsg(msg *lnwire.Init) error {
+	// Before comparing the feature vectors, we'll ensure the remote peer
+	// doesn't require any unknown features, and that all the features it
+	// sets have their dependencies set as well.
+	if err := p.server.featureMgr.ValidateInit(msg); err != nil {
+		err := errors.Errorf("invalid remote feature vectors: %v", err)
+		peerLog.Error(err)
+		return err
+	}
+
 	localSharedFeatures, err := p.server.localFeatures.Compare(msg.LocalFeatures)
 	if err != nil {
 		err := errors.Errorf("can't compare remote and local feature "+
@@ -1758,6 +2153,12 @@ func (p *peer) handleInitMsg(msg *lnwire.Init) error {
 	}
 	p.globalSharedFeatures = globalSharedFeatures
 
+	p.quirks.DetectFromInit(
+		msg, p.server.localFeatures, p.server.globalFeatures,
+	)
+	peerLog.Debugf("Detected implementation of peer %v: %v", p,
+		p.quirks.Impl())
+
 	return nil
 }
 
@@ -1815,9 +2216,11 @@ func createGetLastUpdate(router *routing.ChannelRouter,
 			ChainHash:       info.ChainHash,
 			ShortChannelID:  lnwire.NewShortChanIDFromInt(local.ChannelID),
 			Timestamp:       uint32(local.LastUpdate.Unix()),
-			Flags:           local.Flags,
+			MessageFlags:    local.MessageFlags,
+			ChannelFlags:    local.ChannelFlags,
 			TimeLockDelta:   local.TimeLockDelta,
 			HtlcMinimumMsat: local.MinHTLC,
+			HtlcMaximumMsat: local.MaxHTLC,
 			BaseFee:         uint32(local.FeeBaseMSat),
 			FeeRate:         uint32(local.FeeProportionalMillionths),
 		}
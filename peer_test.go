@@ -1,6 +1,8 @@
 package main
 
 import (
+	"bytes"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -519,3 +521,288 @@ func TestPeerChannelClosureFeeNegotiationsInitiator(t *testing.T) {
 		t.Fatalf("closing tx not broadcast")
 	}
 }
+
+// TestPeerChannelClosureUpfrontShutdown tests that a Shutdown message paying
+// out to a script other than the remote party's upfront shutdown script is
+// rejected.
+func TestPeerChannelClosureUpfrontShutdown(t *testing.T) {
+	disablePeerLogger(t)
+	t.Parallel()
+
+	notifier := &mockNotfier{
+		confChannel: make(chan *chainntnfs.TxConfirmation),
+	}
+	broadcastTxChan := make(chan *wire.MsgTx)
+
+	responder, responderChan, _, cleanUp, err := createTestPeer(
+		notifier, broadcastTxChan)
+	if err != nil {
+		t.Fatalf("unable to create test channels: %v", err)
+	}
+	defer cleanUp()
+
+	chanID := lnwire.NewChanIDFromOutPoint(responderChan.ChannelPoint())
+
+	// The remote party committed to dummyDeliveryScript when the channel
+	// was funded, so a Shutdown paying out to any other script should be
+	// answered with an Error rather than a Shutdown of our own.
+	otherScript := append([]byte{0x00, 0x14}, dummyDeliveryScript[2:22]...)
+	responder.shutdownChanReqs <- lnwire.NewShutdown(chanID, otherScript)
+
+	var msg lnwire.Message
+	select {
+	case outMsg := <-responder.outgoingQueue:
+		msg = outMsg.msg
+	case <-time.After(time.Second * 5):
+		t.Fatalf("did not receive error message")
+	}
+
+	errMsg, ok := msg.(*lnwire.Error)
+	if !ok {
+		t.Fatalf("expected Error message, got %T", msg)
+	}
+	if errMsg.ChanID != chanID {
+		t.Fatalf("expected error for channel %v, got %v", chanID,
+			errMsg.ChanID)
+	}
+}
+
+// TestPeerChannelClosureNonStandardScript tests that a Shutdown message paying
+// out to a non-standard script is rejected.
+func TestPeerChannelClosureNonStandardScript(t *testing.T) {
+	disablePeerLogger(t)
+	t.Parallel()
+
+	notifier := &mockNotfier{
+		confChannel: make(chan *chainntnfs.TxConfirmation),
+	}
+	broadcastTxChan := make(chan *wire.MsgTx)
+
+	responder, responderChan, _, cleanUp, err := createTestPeer(
+		notifier, broadcastTxChan)
+	if err != nil {
+		t.Fatalf("unable to create test channels: %v", err)
+	}
+	defer cleanUp()
+
+	chanID := lnwire.NewChanIDFromOutPoint(responderChan.ChannelPoint())
+
+	// A Shutdown paying out to a bare pubkey isn't standard, so it should
+	// be answered with an Error rather than a Shutdown of our own.
+	nonStandardScript := append(
+		[]byte{0x21}, bytes.Repeat([]byte{0x02}, 33)...,
+	)
+	nonStandardScript = append(nonStandardScript, 0xac)
+	responder.shutdownChanReqs <- lnwire.NewShutdown(
+		chanID, nonStandardScript,
+	)
+
+	var msg lnwire.Message
+	select {
+	case outMsg := <-responder.outgoingQueue:
+		msg = outMsg.msg
+	case <-time.After(time.Second * 5):
+		t.Fatalf("did not receive error message")
+	}
+
+	errMsg, ok := msg.(*lnwire.Error)
+	if !ok {
+		t.Fatalf("expected Error message, got %T", msg)
+	}
+	if errMsg.ChanID != chanID {
+		t.Fatalf("expected error for channel %v, got %v", chanID,
+			errMsg.ChanID)
+	}
+}
+
+// TestPeerPongValidation checks that only pongs carrying the number of bytes
+// requested by our last ping are accepted, and that any other pong increases
+// the peer's misbehavior score, until the peer is to be disconnected.
+func TestPeerPongValidation(t *testing.T) {
+	disablePeerLogger(t)
+	t.Parallel()
+
+	p := &peer{}
+
+	// A pong received without having sent a ping is unsolicited.
+	if err := p.handlePong(lnwire.NewPong(nil)); err != nil {
+		t.Fatalf("peer disconnected after a single misbehavior: %v",
+			err)
+	}
+	if p.MisbehaviorScore() != pongMisbehavior {
+		t.Fatalf("expected score %d, got %d", pongMisbehavior,
+			p.MisbehaviorScore())
+	}
+
+	// A pong carrying the requested number of bytes is accepted.
+	atomic.StoreUint32(&p.pongExpected, 16+1)
+	if err := p.handlePong(lnwire.NewPong(make([]byte, 16))); err != nil {
+		t.Fatalf("valid pong rejected: %v", err)
+	}
+	if p.MisbehaviorScore() != pongMisbehavior {
+		t.Fatalf("valid pong increased score to %d",
+			p.MisbehaviorScore())
+	}
+
+	// The same pong can't be accepted twice.
+	p.handlePong(lnwire.NewPong(make([]byte, 16)))
+	if p.MisbehaviorScore() != 2*pongMisbehavior {
+		t.Fatalf("expected score %d, got %d", 2*pongMisbehavior,
+			p.MisbehaviorScore())
+	}
+
+	// Nor can a pong carrying a different number of bytes.
+	atomic.StoreUint32(&p.pongExpected, 16+1)
+	p.handlePong(lnwire.NewPong(make([]byte, 15)))
+	if p.MisbehaviorScore() != 3*pongMisbehavior {
+		t.Fatalf("expected score %d, got %d", 3*pongMisbehavior,
+			p.MisbehaviorScore())
+	}
+
+	// Further misbehavior should eventually require the peer to be
+	// disconnected, once its score reaches the limit.
+	for i := 4; i < maxMisbehavior/pongMisbehavior; i++ {
+		if err := p.handlePong(lnwire.NewPong(nil)); err != nil {
+			t.Fatalf("peer disconnected with score %d: %v",
+				p.MisbehaviorScore(), err)
+		}
+	}
+	if err := p.handlePong(lnwire.NewPong(nil)); err == nil {
+		t.Fatalf("expected peer to be disconnected with score %d",
+			p.MisbehaviorScore())
+	}
+}
+
+// TestCalculateCompromiseFee asserts that the peer's fee is accepted within
+// our accept window, and that our counter proposals are otherwise kept within
+// the bounds of our max fee multiple, and strictly between our last proposal
+// and the peer's, even as our ideal fee shifts between rounds.
+func TestCalculateCompromiseFee(t *testing.T) {
+	t.Parallel()
+
+	const defaultIdealFee = 1000
+	defaultBounds := closeFeeBounds{
+		maxFeeMultiple: 2,
+		acceptWindow:   0.5,
+	}
+
+	tests := []struct {
+		name        string
+		idealFee    uint64
+		lastSentFee uint64
+		peerFee     uint64
+		bounds      closeFeeBounds
+		expectedFee uint64
+		expectErr   bool
+	}{
+		{
+			name:        "within accept window",
+			peerFee:     1200,
+			bounds:      defaultBounds,
+			expectedFee: 1200,
+		},
+		{
+			name:        "above accept window",
+			peerFee:     2500,
+			bounds:      defaultBounds,
+			expectedFee: 1750,
+		},
+		{
+			name:        "below accept window",
+			peerFee:     100,
+			bounds:      defaultBounds,
+			expectedFee: 550,
+		},
+		{
+			name:        "capped at max fee",
+			lastSentFee: 1750,
+			peerFee:     5000,
+			bounds:      defaultBounds,
+			expectedFee: 2000,
+		},
+		{
+			name:        "floored at min fee",
+			lastSentFee: 550,
+			peerFee:     100,
+			bounds:      defaultBounds,
+			expectedFee: 500,
+		},
+		{
+			name:    "accept window clamped to max fee",
+			peerFee: 1300,
+			bounds: closeFeeBounds{
+				maxFeeMultiple: 1.2,
+				acceptWindow:   0.5,
+			},
+			expectedFee: 1150,
+		},
+		{
+			name:    "no accept window",
+			peerFee: 1100,
+			bounds: closeFeeBounds{
+				maxFeeMultiple: 2,
+			},
+			expectedFee: 1050,
+		},
+		{
+			name:        "ideal fee shifted up between rounds",
+			idealFee:    1200,
+			lastSentFee: 1750,
+			peerFee:     3000,
+			bounds:      defaultBounds,
+			expectedFee: 2375,
+		},
+		{
+			// Our max fee is now below our last proposal, so it
+			// would move our proposal away from the peer's.
+			name:        "ideal fee shifted down between rounds",
+			idealFee:    800,
+			lastSentFee: 1750,
+			peerFee:     3000,
+			bounds:      defaultBounds,
+			expectErr:   true,
+		},
+		{
+			// Our min fee is now above the peer's proposal, so it
+			// would overshoot the fee the peer asked for.
+			name:        "ideal fee shifted beyond peer's proposal",
+			idealFee:    10000,
+			lastSentFee: 2000,
+			peerFee:     2500,
+			bounds:      defaultBounds,
+			expectErr:   true,
+		},
+		{
+			name:        "no fee between proposals",
+			lastSentFee: 2999,
+			peerFee:     3000,
+			bounds:      defaultBounds,
+			expectErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		idealFee := test.idealFee
+		if idealFee == 0 {
+			idealFee = defaultIdealFee
+		}
+
+		fee, err := calculateCompromiseFee(
+			idealFee, test.lastSentFee, test.peerFee, test.bounds,
+		)
+		switch {
+		case test.expectErr && err == nil:
+			t.Fatalf("%s: expected error, got fee %v", test.name,
+				fee)
+		case test.expectErr:
+			continue
+		case err != nil:
+			t.Fatalf("%s: unable to calculate fee: %v", test.name,
+				err)
+		}
+		if fee != test.expectedFee {
+			t.Fatalf("%s: expected fee %v, got %v", test.name,
+				test.expectedFee, fee)
+		}
+	}
+}
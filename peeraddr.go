@@ -0,0 +1,220 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	// onionSuffix is the suffix of the hostnames of Tor hidden services.
+	onionSuffix = ".onion"
+
+	// onionV2Len is the length of the base32 encoded service identifier of
+	// a version 2 hidden service.
+	onionV2Len = 16
+
+	// onionV3Len is the length of the base32 encoded service identifier of
+	// a version 3 hidden service.
+	onionV3Len = 56
+)
+
+// peerAddrErrorCode identifies the reason a peer address was rejected.
+type peerAddrErrorCode uint8
+
+const (
+	// peerAddrEmpty indicates that no address was specified.
+	peerAddrEmpty peerAddrErrorCode = iota
+
+	// peerAddrInvalidHost indicates that the host portion of the address
+	// is empty or malformed.
+	peerAddrInvalidHost
+
+	// peerAddrInvalidPort indicates that the port of the address isn't a
+	// number between 1 and 65535.
+	peerAddrInvalidPort
+
+	// peerAddrInvalidOnion indicates that the address is a malformed
+	// onion address.
+	peerAddrInvalidOnion
+
+	// peerAddrOnionUnsupported indicates that the address is a valid
+	// onion address, however connections to hidden services aren't
+	// supported as no Tor proxy is available.
+	peerAddrOnionUnsupported
+
+	// peerAddrUnresolvable indicates that the host of the address
+	// couldn't be resolved to an IP address.
+	peerAddrUnresolvable
+)
+
+// String returns a human readable description of the error code.
+func (c peerAddrErrorCode) String() string {
+	switch c {
+	case peerAddrEmpty:
+		return "empty address"
+	case peerAddrInvalidHost:
+		return "invalid host"
+	case peerAddrInvalidPort:
+		return "invalid port"
+	case peerAddrInvalidOnion:
+		return "invalid onion address"
+	case peerAddrOnionUnsupported:
+		return "onion addresses unsupported"
+	case peerAddrUnresolvable:
+		return "unresolvable host"
+	default:
+		return "unknown"
+	}
+}
+
+// peerAddrError is returned when a peer address received over RPC can't be
+// parsed or resolved.
+type peerAddrError struct {
+	// Code identifies the reason the address was rejected.
+	Code peerAddrErrorCode
+
+	// Addr is the address as it was received.
+	Addr string
+
+	// Err is the underlying error, if any.
+	Err error
+}
+
+// Error returns a human readable description of the error.
+//
+// NOTE: This is part of the error interface.
+func (e *peerAddrError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%v %q: %v", e.Code, e.Addr, e.Err)
+	}
+	return fmt.Sprintf("%v %q", e.Code, e.Addr)
+}
+
+// grpcCode returns the gRPC status code that best describes the error.
+func (e *peerAddrError) grpcCode() codes.Code {
+	switch e.Code {
+	case peerAddrOnionUnsupported:
+		return codes.Unimplemented
+	case peerAddrUnresolvable:
+		return codes.NotFound
+	default:
+		return codes.InvalidArgument
+	}
+}
+
+// peerAddrRPCError converts an error returned by parsePeerAddr into an error
+// carrying the appropriate gRPC status code, so that clients are able to
+// distinguish between the reasons an address was rejected.
+func peerAddrRPCError(err error) error {
+	addrErr, ok := err.(*peerAddrError)
+	if !ok {
+		return err
+	}
+
+	return grpc.Errorf(addrErr.grpcCode(), "%v", addrErr)
+}
+
+// parsePeerAddr parses the address of a peer received over RPC. The address
+// may be any of a hostname, an IPv4 literal or an IPv6 literal, optionally
+// followed by a port, e.g. "example.com", "1.2.3.4:9736", "::1" or
+// "[::1]:9736". If no port is specified, then the passed default port is
+// assumed. Clearnet hosts are resolved via the passed resolver. Onion
+// addresses are validated, however are rejected as no Tor proxy is available
+// to connect to them.
+func parsePeerAddr(addr string, defaultPort int,
+	resolve func(network, addr string) (*net.TCPAddr, error)) (*net.TCPAddr,
+	error) {
+
+	addr = strings.TrimSpace(addr)
+	if addr == "" {
+		return nil, &peerAddrError{Code: peerAddrEmpty}
+	}
+
+	newErr := func(code peerAddrErrorCode, err error) error {
+		return &peerAddrError{Code: code, Addr: addr, Err: err}
+	}
+
+	var (
+		host, port string
+		hasPort    bool
+	)
+
+	// An IPv6 literal without a port may be specified with or without
+	// brackets, neither of which SplitHostPort accepts, so we'll check
+	// for those first.
+	switch {
+	case net.ParseIP(addr) != nil:
+		host = addr
+
+	case strings.HasPrefix(addr, "[") && strings.HasSuffix(addr, "]"):
+		host = addr[1 : len(addr)-1]
+		if net.ParseIP(host) == nil {
+			return nil, newErr(peerAddrInvalidHost, nil)
+		}
+
+	default:
+		var err error
+		host, port, err = net.SplitHostPort(addr)
+		if err != nil {
+			// If the address has no port at all, then the
+			// entire address is the host.
+			if strings.Contains(addr, ":") {
+				return nil, newErr(peerAddrInvalidHost, err)
+			}
+			host = addr
+			break
+		}
+		hasPort = true
+	}
+
+	if host == "" {
+		return nil, newErr(peerAddrInvalidHost, nil)
+	}
+
+	portNum := defaultPort
+	if hasPort {
+		p, err := strconv.ParseUint(port, 10, 16)
+		if err != nil || p == 0 {
+			return nil, newErr(peerAddrInvalidPort, nil)
+		}
+		portNum = int(p)
+	}
+
+	if strings.HasSuffix(strings.ToLower(host), onionSuffix) {
+		if !isValidOnionHost(host) {
+			return nil, newErr(peerAddrInvalidOnion, nil)
+		}
+		return nil, newErr(peerAddrOnionUnsupported, nil)
+	}
+
+	tcpAddr, err := resolve(
+		"tcp", net.JoinHostPort(host, strconv.Itoa(portNum)),
+	)
+	if err != nil {
+		return nil, newErr(peerAddrUnresolvable, err)
+	}
+
+	return tcpAddr, nil
+}
+
+// isValidOnionHost returns true if the passed host is a well formed version 2
+// or version 3 onion address.
+func isValidOnionHost(host string) bool {
+	id := strings.TrimSuffix(strings.ToLower(host), onionSuffix)
+	if len(id) != onionV2Len && len(id) != onionV3Len {
+		return false
+	}
+
+	for _, c := range id {
+		if !(c >= 'a' && c <= 'z') && !(c >= '2' && c <= '7') {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"testing"
+)
+
+// TestParsePeerAddr asserts that peer addresses received over RPC are parsed
+// into the expected host and port, and that each malformed address is
+// rejected with the appropriate error code.
+func TestParsePeerAddr(t *testing.T) {
+	t.Parallel()
+
+	// The resolver only resolves IP literals and a single hostname, so
+	// that the test doesn't depend on DNS.
+	resolve := func(network, addr string) (*net.TCPAddr, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		if host == "example.com" {
+			host = "93.184.216.34"
+		}
+		if net.ParseIP(host) == nil {
+			return nil, errors.New("no such host")
+		}
+		return net.ResolveTCPAddr(network, net.JoinHostPort(host, port))
+	}
+
+	onionV2 := strings.Repeat("a", onionV2Len) + ".onion"
+	onionV3 := strings.Repeat("b", onionV3Len) + ".onion:9735"
+
+	tests := []struct {
+		addr     string
+		expected string
+		code     peerAddrErrorCode
+	}{
+		{addr: "1.2.3.4", expected: "1.2.3.4:9735"},
+		{addr: "1.2.3.4:9736", expected: "1.2.3.4:9736"},
+		{addr: " example.com ", expected: "93.184.216.34:9735"},
+		{addr: "example.com:1", expected: "93.184.216.34:1"},
+		{addr: "::1", expected: "[::1]:9735"},
+		{addr: "[::1]", expected: "[::1]:9735"},
+		{addr: "[::1]:9736", expected: "[::1]:9736"},
+		{addr: "", code: peerAddrEmpty},
+		{addr: ":9735", code: peerAddrInvalidHost},
+		{addr: "[example.com]", code: peerAddrInvalidHost},
+		{addr: "1:2:3", code: peerAddrInvalidHost},
+		{addr: "1.2.3.4:", code: peerAddrInvalidPort},
+		{addr: "1.2.3.4:0", code: peerAddrInvalidPort},
+		{addr: "1.2.3.4:65536", code: peerAddrInvalidPort},
+		{addr: "1.2.3.4:port", code: peerAddrInvalidPort},
+		{addr: "short.onion", code: peerAddrInvalidOnion},
+		{addr: strings.Repeat("1", onionV2Len) + ".onion",
+			code: peerAddrInvalidOnion},
+		{addr: onionV2, code: peerAddrOnionUnsupported},
+		{addr: onionV3, code: peerAddrOnionUnsupported},
+		{addr: "unknown.host", code: peerAddrUnresolvable},
+	}
+	for _, test := range tests {
+		addr, err := parsePeerAddr(test.addr, defaultPeerPort, resolve)
+		if test.expected != "" {
+			if err != nil {
+				t.Fatalf("unable to parse %q: %v", test.addr, err)
+			}
+			if addr.String() != test.expected {
+				t.Fatalf("expected %q to parse as %v, got %v",
+					test.addr, test.expected, addr)
+			}
+			continue
+		}
+
+		addrErr, ok := err.(*peerAddrError)
+		if !ok {
+			t.Fatalf("expected peerAddrError for %q, got %v",
+				test.addr, err)
+		}
+		if addrErr.Code != test.code {
+			t.Fatalf("expected %q to be rejected with %v, got %v",
+				test.addr, test.code, addrErr.Code)
+		}
+	}
+}
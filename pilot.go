@@ -7,6 +7,7 @@ import (
 	"github.com/davecgh/go-spew/spew"
 	"github.com/lightningnetwork/lnd/autopilot"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/subscribe"
 	"github.com/roasbeef/btcd/btcec"
 	"github.com/roasbeef/btcd/wire"
 	"github.com/roasbeef/btcutil"
@@ -49,13 +50,33 @@ func (c *chanController) OpenChannel(target *btcec.PublicKey,
 		// advertised IP addresses, or have made a connection.
 		var connected bool
 		for _, addr := range addrs {
-			// If the address doesn't already have a port, then
-			// we'll assume the current default port.
-			tcpAddr, ok := addr.(*net.TCPAddr)
-			if !ok {
+			var tcpAddr *net.TCPAddr
+			switch addr := addr.(type) {
+			case *net.TCPAddr:
+				tcpAddr = addr
+
+			// Advertised hostnames are resolved at connection
+			// time, skipping them if they fail to resolve.
+			case *lnwire.DNSHostnameAddress:
+				var err error
+				tcpAddr, err = net.ResolveTCPAddr(
+					"tcp", addr.String(),
+				)
+				if err != nil {
+					continue
+				}
+
+			// Any addresses we're unable to interpret are skipped.
+			case *lnwire.OpaqueAddrs:
+				continue
+
+			default:
 				return fmt.Errorf("TCP address required instead "+
 					"have %T", addr)
 			}
+
+			// If the address doesn't already have a port, then
+			// we'll assume the current default port.
 			if tcpAddr.Port == 0 {
 				tcpAddr.Port = defaultPeerPort
 			}
@@ -85,7 +106,7 @@ func (c *chanController) OpenChannel(target *btcec.PublicKey,
 
 	// With the connection established, we'll now establish our connection
 	// to the target peer, waiting for the first update before we exit.
-	updateStream, errChan := c.server.OpenChannel(-1, target, amt, 0)
+	updateStream, errChan := c.server.OpenChannel(-1, target, amt, 0, 0, nil)
 
 	select {
 	case err := <-errChan:
@@ -165,13 +186,15 @@ func initAutoPilot(svr *server, cfg *autoPilotConfig) (*autopilot.Agent, error)
 	}
 
 	// Finally, we'll need to subscribe to two things: incoming
-	// transactions that modify the wallet's balance, and also any graph
-	// topology updates.
+	// transactions that modify the wallet's balance, and also the opening
+	// and closing of our channels.
 	txnSubscription, err := svr.cc.wallet.SubscribeTransactions()
 	if err != nil {
 		return nil, err
 	}
-	graphSubscription, err := svr.chanRouter.SubscribeTopology()
+	chanSubscription, err := svr.eventBus.Subscribe(
+		channelOpenEvent{}, channelCloseEvent{},
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -195,60 +218,37 @@ func initAutoPilot(svr *server, cfg *autoPilotConfig) (*autopilot.Agent, error)
 	}()
 
 	// We'll also launch a goroutine to provide the agent with
-	// notifications for when the graph topology controlled by the node
-	// changes.
+	// notifications for when one of our channels is opened or closed.
 	svr.wg.Add(1)
 	go func() {
-		defer graphSubscription.Cancel()
+		defer chanSubscription.Cancel()
 		defer svr.wg.Done()
 
 		for {
 			select {
-			case topChange, ok := <-graphSubscription.TopologyChanges:
-				// If the router is shutting down, then we will
-				// as well.
-				if !ok {
-					return
+			case event := <-chanSubscription.Updates():
+				switch e := event.(type) {
+				case channelOpenEvent:
+					pilot.OnChannelOpen(autopilot.Channel{
+						ChanID:   e.shortChanID,
+						Capacity: e.capacity,
+						Node:     autopilot.NewNodeID(e.remotePub),
+					})
+
+				case channelCloseEvent:
+					pilot.OnChannelClose(e.shortChanID)
 				}
 
-				for _, edgeUpdate := range topChange.ChannelEdgeUpdates {
-					// If this isn't an advertisement by
-					// the backing lnd node, then we'll
-					// continue as we only want to add
-					// channels that we've created
-					// ourselves.
-					if !edgeUpdate.AdvertisingNode.IsEqual(self) {
-						continue
-					}
-
-					// If this is indeed a channel we
-					// opened, then we'll convert it to the
-					// autopilot.Channel format, and notify
-					// the pilot of the new channel.
-					chanNode := autopilot.NewNodeID(
-						edgeUpdate.ConnectingNode,
-					)
-					chanID := lnwire.NewShortChanIDFromInt(
-						edgeUpdate.ChanID,
-					)
-					edge := autopilot.Channel{
-						ChanID:   chanID,
-						Capacity: edgeUpdate.Capacity,
-						Node:     chanNode,
-					}
-					pilot.OnChannelOpen(edge)
-				}
-
-				// For each closed closed channel, we'll obtain
-				// the chanID of the closed channel and send it
-				// to the pilot.
-				for _, chanClose := range topChange.ClosedChannels {
-					chanID := lnwire.NewShortChanIDFromInt(
-						chanClose.ChanID,
-					)
-
-					pilot.OnChannelClose(chanID)
+			// If our subscription ended, either as the event bus
+			// is shutting down or as we fell behind on its
+			// updates, then we'll exit.
+			case <-chanSubscription.Quit():
+				err := chanSubscription.Err()
+				if err == subscribe.ErrClientTooSlow {
+					atplLog.Errorf("channel event "+
+						"subscription cancelled: %v", err)
 				}
+				return
 
 			case <-svr.quit:
 				return
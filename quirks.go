@@ -0,0 +1,260 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// peerImpl identifies the Lightning implementation a remote peer is running.
+type peerImpl uint8
+
+const (
+	// implUnknown is used for peers whose implementation hasn't (yet)
+	// been detected.
+	implUnknown peerImpl = iota
+
+	// implLnd is used for peers running lnd.
+	implLnd
+
+	// implCLightning is used for peers running c-lightning.
+	implCLightning
+
+	// implEclair is used for peers running eclair.
+	implEclair
+)
+
+// String returns a human readable name for the implementation.
+func (i peerImpl) String() string {
+	switch i {
+	case implLnd:
+		return "lnd"
+	case implCLightning:
+		return "c-lightning"
+	case implEclair:
+		return "eclair"
+	default:
+		return "unknown"
+	}
+}
+
+// quirk identifies a single documented deviation from the protocol
+// specification which we work around in order to interoperate with other
+// implementations.
+type quirk uint8
+
+const (
+	// quirkCloseFeeSteps tolerates ClosingSigned fee proposals which
+	// don't fall strictly between the remote party's previous proposal
+	// and our own, as required by BOLT#2. Without this quirk, such a
+	// proposal aborts the cooperative close.
+	quirkCloseFeeSteps quirk = iota
+
+	// quirkLenientGossip drops gossip messages which fail to parse rather
+	// than disconnecting from the peer which sent them, as implementations
+	// have disagreed on the encoding of some optional fields.
+	quirkLenientGossip
+)
+
+// String returns the name of the quirk.
+func (q quirk) String() string {
+	switch q {
+	case quirkCloseFeeSteps:
+		return "closefeesteps"
+	case quirkLenientGossip:
+		return "lenientgossip"
+	default:
+		return fmt.Sprintf("<unknown quirk %d>", uint8(q))
+	}
+}
+
+// quirkImpls maps each quirk to the set of implementations it's applied to.
+// Quirks are also applied to all peers whose implementation is unknown, in
+// order to maximize interoperability.
+var quirkImpls = map[quirk][]peerImpl{
+	quirkCloseFeeSteps: {implCLightning, implEclair},
+	quirkLenientGossip: {implCLightning, implEclair},
+}
+
+// errorFingerprints is a set of substrings which are known to be unique to
+// the error messages sent by a particular implementation. This is a heuristic
+// used to identify a peer's implementation when it isn't evident from its
+// init message.
+var errorFingerprints = []struct {
+	impl   peerImpl
+	substr string
+}{
+	{implCLightning, "Bad closing_signed"},
+	{implCLightning, "Unknown channel for"},
+	{implEclair, "local/remote feerates are too different"},
+	{implEclair, "invalid commitment signature"},
+}
+
+// disabledQuirks returns the set of quirks which have been disabled by the
+// passed configuration.
+func disabledQuirks(cfg *quirksConfig) map[quirk]struct{} {
+	disabled := make(map[quirk]struct{})
+	if cfg == nil {
+		return disabled
+	}
+
+	if cfg.NoCloseFeeSteps {
+		disabled[quirkCloseFeeSteps] = struct{}{}
+	}
+	if cfg.NoLenientGossip {
+		disabled[quirkLenientGossip] = struct{}{}
+	}
+
+	return disabled
+}
+
+// peerQuirks tracks the detected implementation of a single peer, and
+// determines which quirks should be applied when interacting with it.
+type peerQuirks struct {
+	mu   sync.RWMutex
+	impl peerImpl
+
+	// disabled is the set of quirks which have been disabled, and are
+	// never applied regardless of the peer's implementation.
+	disabled map[quirk]struct{}
+}
+
+// newPeerQuirks creates a new peerQuirks instance for a peer whose
+// implementation is unknown. The passed set of quirks are never applied.
+func newPeerQuirks(disabled map[quirk]struct{}) *peerQuirks {
+	return &peerQuirks{
+		disabled: disabled,
+	}
+}
+
+// Impl returns the detected implementation of the peer.
+func (q *peerQuirks) Impl() peerImpl {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	return q.impl
+}
+
+// Applies returns true if the passed quirk should be applied when interacting
+// with the peer.
+func (q *peerQuirks) Applies(qk quirk) bool {
+	if _, ok := q.disabled[qk]; ok {
+		return false
+	}
+
+	impl := q.Impl()
+	if impl == implUnknown {
+		return true
+	}
+	for _, quirkImpl := range quirkImpls[qk] {
+		if quirkImpl == impl {
+			return true
+		}
+	}
+
+	return false
+}
+
+// DetectFromInit attempts to detect the peer's implementation from its init
+// message. As lnd advertises a fixed set of feature vectors, a peer sending
+// identical vectors is assumed to be running lnd.
+func (q *peerQuirks) DetectFromInit(msg *lnwire.Init, localFeatures,
+	globalFeatures *lnwire.FeatureVector) {
+
+	if msg.LocalFeatures == nil || msg.GlobalFeatures == nil {
+		return
+	}
+
+	if !featuresEqual(msg.LocalFeatures, localFeatures) ||
+		!featuresEqual(msg.GlobalFeatures, globalFeatures) {
+		return
+	}
+
+	q.mu.Lock()
+	q.impl = implLnd
+	q.mu.Unlock()
+}
+
+// DetectFromError attempts to detect the peer's implementation from the
+// contents of an error it sent. Detection only takes place if the peer's
+// implementation isn't yet known.
+func (q *peerQuirks) DetectFromError(msg *lnwire.Error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.impl != implUnknown {
+		return
+	}
+
+	for _, fingerprint := range errorFingerprints {
+		if strings.Contains(string(msg.Data), fingerprint.substr) {
+			q.impl = fingerprint.impl
+			return
+		}
+	}
+}
+
+// featuresEqual returns true if the two feature vectors have identical
+// encodings.
+func featuresEqual(a, b *lnwire.FeatureVector) bool {
+	var bufA, bufB bytes.Buffer
+	if err := a.Encode(&bufA); err != nil {
+		return false
+	}
+	if err := b.Encode(&bufB); err != nil {
+		return false
+	}
+
+	return bytes.Equal(bufA.Bytes(), bufB.Bytes())
+}
+
+// isCanonicalCloseFee returns true if the remote party's fee proposal within a
+// ClosingSigned message is a valid step within the fee negotiation as
+// dictated by BOLT#2. A new proposal must either accept our last proposal, or
+// lie strictly between our last proposal and the remote party's previous
+// proposal. If either party has yet to make a proposal, then any fee is
+// valid.
+func isCanonicalCloseFee(feeProposal, ourLastFee, peerLastFee uint64) bool {
+	if ourLastFee == 0 || peerLastFee == 0 {
+		return true
+	}
+	if feeProposal == ourLastFee {
+		return true
+	}
+
+	low, high := ourLastFee, peerLastFee
+	if low > high {
+		low, high = high, low
+	}
+
+	return feeProposal > low && feeProposal < high
+}
+
+// malformedGossipError is returned when reading a gossip message from a peer
+// which fails to parse, and the lenient gossip quirk applies to the peer.
+type malformedGossipError struct {
+	msgType lnwire.MessageType
+	err     error
+}
+
+// Error returns a human readable description of the error.
+func (e *malformedGossipError) Error() string {
+	return fmt.Sprintf("malformed %v message: %v", e.msgType, e.err)
+}
+
+// isGossipMsgType returns true if the passed message type is that of a gossip
+// message.
+func isGossipMsgType(msgType lnwire.MessageType) bool {
+	switch msgType {
+	case lnwire.MsgChannelAnnouncement,
+		lnwire.MsgNodeAnnouncement,
+		lnwire.MsgChannelUpdate,
+		lnwire.MsgAnnounceSignatures:
+		return true
+	default:
+		return false
+	}
+}
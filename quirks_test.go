@@ -0,0 +1,107 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/lnwire/feature"
+)
+
+// TestPeerQuirksDetection tests that a peer's implementation is detected from
+// its init message and errors, and that quirks are only applied to the
+// appropriate implementations.
+func TestPeerQuirksDetection(t *testing.T) {
+	t.Parallel()
+
+	// Quirks should be applied to peers whose implementation is unknown,
+	// unless they've been disabled.
+	quirks := newPeerQuirks(map[quirk]struct{}{
+		quirkLenientGossip: {},
+	})
+	if !quirks.Applies(quirkCloseFeeSteps) {
+		t.Fatalf("quirk should apply to unknown implementation")
+	}
+	if quirks.Applies(quirkLenientGossip) {
+		t.Fatalf("disabled quirk shouldn't apply")
+	}
+
+	// A peer sending an error matching a known fingerprint should be
+	// detected as running that implementation.
+	quirks.DetectFromError(&lnwire.Error{
+		Data: lnwire.ErrorData("local/remote feerates are too different"),
+	})
+	if quirks.Impl() != implEclair {
+		t.Fatalf("expected %v, got %v", implEclair, quirks.Impl())
+	}
+	if !quirks.Applies(quirkCloseFeeSteps) {
+		t.Fatalf("quirk should apply to %v", implEclair)
+	}
+
+	// A peer sending the same feature vectors as us should be detected as
+	// lnd, to which no quirks are applied.
+	featureMgr, err := feature.NewManager()
+	if err != nil {
+		t.Fatalf("unable to create feature manager: %v", err)
+	}
+	globalFeatures := featureMgr.Get(feature.SetInitGlobal)
+	localFeatures := featureMgr.Get(feature.SetInitLocal)
+
+	quirks = newPeerQuirks(nil)
+	quirks.DetectFromInit(
+		lnwire.NewInitMessage(globalFeatures, localFeatures),
+		localFeatures, globalFeatures,
+	)
+	if quirks.Impl() != implLnd {
+		t.Fatalf("expected %v, got %v", implLnd, quirks.Impl())
+	}
+	if quirks.Applies(quirkCloseFeeSteps) ||
+		quirks.Applies(quirkLenientGossip) {
+
+		t.Fatalf("quirks shouldn't apply to %v", implLnd)
+	}
+
+	// Once detected, errors shouldn't change the implementation.
+	quirks.DetectFromError(&lnwire.Error{
+		Data: lnwire.ErrorData("Bad closing_signed"),
+	})
+	if quirks.Impl() != implLnd {
+		t.Fatalf("expected %v, got %v", implLnd, quirks.Impl())
+	}
+}
+
+// TestIsCanonicalCloseFee tests the validation of fee steps within the
+// cooperative close fee negotiation.
+func TestIsCanonicalCloseFee(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		fee, ourLast, peerLast uint64
+		canonical              bool
+	}{
+		// Initial proposals are always valid.
+		{fee: 500, ourLast: 0, peerLast: 0, canonical: true},
+		{fee: 500, ourLast: 1000, peerLast: 0, canonical: true},
+
+		// Accepting our proposal is valid.
+		{fee: 1000, ourLast: 1000, peerLast: 500, canonical: true},
+
+		// Stepping strictly between the proposals is valid.
+		{fee: 700, ourLast: 1000, peerLast: 500, canonical: true},
+		{fee: 700, ourLast: 500, peerLast: 1000, canonical: true},
+
+		// Repeating the previous proposal, or overshooting, isn't.
+		{fee: 500, ourLast: 1000, peerLast: 500, canonical: false},
+		{fee: 1200, ourLast: 1000, peerLast: 500, canonical: false},
+		{fee: 300, ourLast: 1000, peerLast: 500, canonical: false},
+	}
+
+	for i, test := range tests {
+		canonical := isCanonicalCloseFee(
+			test.fee, test.ourLast, test.peerLast,
+		)
+		if canonical != test.canonical {
+			t.Fatalf("test #%v: expected canonical=%v, got %v", i,
+				test.canonical, canonical)
+		}
+	}
+}
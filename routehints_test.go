@@ -0,0 +1,80 @@
+package main
+
+import (
+	"encoding/hex"
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/routing"
+	"github.com/roasbeef/btcd/btcec"
+)
+
+// TestRouteHintsRPCConversion asserts that route hints survive the round trip
+// through their RPC form, and that malformed hints received over RPC are
+// rejected.
+func TestRouteHintsRPCConversion(t *testing.T) {
+	t.Parallel()
+
+	var nodes []*btcec.PublicKey
+	for i := 0; i < 2; i++ {
+		priv, err := btcec.NewPrivateKey(btcec.S256())
+		if err != nil {
+			t.Fatalf("unable to generate key: %v", err)
+		}
+		nodes = append(nodes, priv.PubKey())
+	}
+
+	routeHints := [][]routing.HopHint{
+		{{
+			NodeID:                    nodes[0],
+			ChannelID:                 1,
+			FeeBaseMSat:               1000,
+			FeeProportionalMillionths: 10,
+			CLTVExpiryDelta:           144,
+		}},
+		{{
+			NodeID:          nodes[0],
+			ChannelID:       2,
+			CLTVExpiryDelta: 40,
+		}, {
+			NodeID:                    nodes[1],
+			ChannelID:                 3,
+			FeeBaseMSat:               1,
+			FeeProportionalMillionths: 1,
+			CLTVExpiryDelta:           9,
+		}},
+	}
+
+	parsed, err := unmarshalRouteHints(marshalRouteHints(routeHints))
+	if err != nil {
+		t.Fatalf("unable to parse route hints: %v", err)
+	}
+	if !reflect.DeepEqual(parsed, routeHints) {
+		t.Fatalf("expected route hints %v, got %v",
+			spew.Sdump(routeHints), spew.Sdump(parsed))
+	}
+
+	invalidHints := [][]*lnrpc.RouteHint{
+		// A route hint must have at least one hop.
+		{{}},
+
+		// The node ID must be a valid public key.
+		{{HopHints: []*lnrpc.HopHint{{NodeId: "02aa"}}}},
+
+		// The time-lock delta must fit within 16 bits.
+		{{HopHints: []*lnrpc.HopHint{{
+			NodeId: hex.EncodeToString(
+				nodes[0].SerializeCompressed(),
+			),
+			CltvExpiryDelta: 1 << 16,
+		}}}},
+	}
+	for i, rpcHints := range invalidHints {
+		if _, err := unmarshalRouteHints(rpcHints); err == nil {
+			t.Fatalf("expected invalid route hints #%v to be "+
+				"rejected", i)
+		}
+	}
+}
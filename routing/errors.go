@@ -1,6 +1,10 @@
 package routing
 
-import "github.com/go-errors/errors"
+import (
+	"github.com/go-errors/errors"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
 
 // errorCode is used to represent the various errors that can occur within this
 // package.
@@ -38,6 +42,19 @@ const (
 	// this update can't bring us something new, or because a node
 	// announcement was given for node not found in any channel.
 	ErrIgnored
+
+	// ErrTargetBlacklisted is returned when the target of a path-finding
+	// or payment attempt has been blacklisted as a destination.
+	ErrTargetBlacklisted
+
+	// ErrFeeLimitExceeded is returned when every route to the destination
+	// of a payment requires fees exceeding the payment's fee limit.
+	ErrFeeLimitExceeded
+
+	// ErrMissingPaymentAddr is returned when a payment is allowed to be
+	// split into multiple parts, yet lacks the payment address required
+	// for the receiver to reassemble them.
+	ErrMissingPaymentAddr
 )
 
 // routerError is a structure that represent the error inside the routing package,
@@ -91,3 +108,62 @@ func IsError(e interface{}, codes ...errorCode) bool {
 
 	return false
 }
+
+// PaymentError is returned by SendPayment when a payment fails. Along with the
+// error which caused the final payment attempt to fail, it carries the reason
+// the payment failed, as recorded within the payment store.
+type PaymentError struct {
+	// Reason is the reason the payment failed.
+	Reason channeldb.FailureReason
+
+	// Err is the error which caused the payment to fail.
+	Err error
+}
+
+// Error returns the string representation of the underlying error.
+//
+// NOTE: Part of the error interface.
+func (e *PaymentError) Error() string {
+	if e.Err == nil {
+		return e.Reason.String()
+	}
+
+	return e.Err.Error()
+}
+
+// A compile time check to ensure PaymentError implements the error interface.
+var _ error = (*PaymentError)(nil)
+
+// RouteError is returned by SendToRoute when a payment over the passed route
+// fails. Along with the reason the payment failed, it identifies the node
+// along the route that generated the failure, and carries the failure message
+// it sent.
+type RouteError struct {
+	// Reason is the reason the payment failed, as recorded within the
+	// payment store.
+	Reason channeldb.FailureReason
+
+	// FailureSourceIdx is the index of the node that generated the
+	// failure. An index of zero refers to our own node, while an index of
+	// i refers to the node at the end of the i-th hop of the route. If the
+	// source of the failure couldn't be determined, then it's -1.
+	FailureSourceIdx int
+
+	// FailureMessage is the decrypted failure message sent by the node
+	// that generated the failure. It's nil if the payment failed within
+	// our own node, or if the failure couldn't be decrypted.
+	FailureMessage lnwire.FailureMessage
+
+	// Err is the error which caused the payment to fail.
+	Err error
+}
+
+// Error returns the string representation of the underlying error.
+//
+// NOTE: Part of the error interface.
+func (e *RouteError) Error() string {
+	return e.Err.Error()
+}
+
+// A compile time check to ensure RouteError implements the error interface.
+var _ error = (*RouteError)(nil)
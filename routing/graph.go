@@ -1,4 +1,178 @@
 package routing
 
+import (
+	"sync"
+
+	"github.com/boltdb/bolt"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/roasbeef/btcd/btcec"
+)
+
 // TODO(roasbeef): abstract out graph to interface
 //  * add in-memory version of graph for tests
+
+// graphSession is a read-only view of the channel graph that is shared by a
+// series of path finding attempts, such as those made for the shards and
+// retries of a single payment. Rather than opening a new database transaction
+// for every node whose channels are traversed, the session pins a single read
+// transaction, so that each attempt operates on a consistent snapshot of the
+// graph. The nodes of the graph are also deserialized only once per snapshot.
+//
+// As long as the session holds a snapshot, the database is unable to grow its
+// memory map, which blocks writers that need it to. The snapshot is therefore
+// only held between calls to release, which should be made before waiting on
+// anything that may itself wait on a write to the graph.
+//
+// NOTE: A graphSession is safe for concurrent use, although its users are
+// serialized while accessing the graph.
+type graphSession struct {
+	graph *channeldb.ChannelGraph
+
+	// mtx guards the snapshot below, as a bolt transaction may not be
+	// used by several goroutines at once.
+	mtx sync.Mutex
+
+	// tx is the read transaction pinning the current snapshot, which is
+	// opened on first use, and nil after the session is released.
+	tx *bolt.Tx
+
+	// nodes caches every node of the current snapshot.
+	nodes []*channeldb.LightningNode
+}
+
+// newGraphSession creates a new session over the passed channel graph. No
+// snapshot is taken until the graph is first accessed.
+func newGraphSession(graph *channeldb.ChannelGraph) *graphSession {
+	return &graphSession{
+		graph: graph,
+	}
+}
+
+// snapshot returns the read transaction pinning the current snapshot of the
+// graph, opening it if needed.
+//
+// NOTE: This method must be called with the mutex held.
+func (g *graphSession) snapshot() (*bolt.Tx, error) {
+	if g.tx != nil {
+		return g.tx, nil
+	}
+
+	tx, err := g.graph.Database().Begin(false)
+	if err != nil {
+		return nil, err
+	}
+	g.tx = tx
+
+	return tx, nil
+}
+
+// forEachNode invokes the passed callback for every node within the current
+// snapshot of the graph. The nodes are shared by every user of the session,
+// so they must not be modified.
+func (g *graphSession) forEachNode(
+	cb func(*channeldb.LightningNode) error) error {
+
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+
+	if g.nodes == nil {
+		tx, err := g.snapshot()
+		if err != nil {
+			return err
+		}
+
+		var nodes []*channeldb.LightningNode
+		err = g.graph.ForEachNode(tx, func(_ *bolt.Tx,
+			node *channeldb.LightningNode) error {
+
+			nodes = append(nodes, node)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		g.nodes = nodes
+	}
+
+	for _, node := range g.nodes {
+		if err := cb(node); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// hasNode returns true if the node with the passed public key is part of the
+// current snapshot of the graph.
+func (g *graphSession) hasNode(pub *btcec.PublicKey) (bool, error) {
+	var exists bool
+	err := g.forEachNode(func(node *channeldb.LightningNode) error {
+		if node.PubKey.IsEqual(pub) {
+			exists = true
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+// zombieEdges returns the set of channel IDs of all channels marked as
+// zombies within the current snapshot of the graph.
+func (g *graphSession) zombieEdges() (map[uint64]struct{}, error) {
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+
+	tx, err := g.snapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	return g.graph.FetchZombieEdges(tx)
+}
+
+// forEachChannel invokes the passed callback for every channel of the passed
+// node within the current snapshot of the graph, along with the policies of
+// both of its directions. As with LightningNode.ForEachChannel, the first
+// policy is the outgoing edge to the connecting node, while the second is the
+// incoming edge from it.
+func (g *graphSession) forEachChannel(node *channeldb.LightningNode,
+	cb func(*channeldb.ChannelEdgeInfo, *channeldb.ChannelEdgePolicy,
+		*channeldb.ChannelEdgePolicy) error) error {
+
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+
+	tx, err := g.snapshot()
+	if err != nil {
+		return err
+	}
+
+	return node.ForEachChannel(tx, func(_ *bolt.Tx,
+		edgeInfo *channeldb.ChannelEdgeInfo,
+		outEdge, inEdge *channeldb.ChannelEdgePolicy) error {
+
+		return cb(edgeInfo, outEdge, inEdge)
+	})
+}
+
+// release discards the current snapshot of the graph, allowing writers to
+// proceed. The session remains usable, and takes a fresh snapshot on its next
+// access.
+func (g *graphSession) release() error {
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+
+	g.nodes = nil
+	if g.tx == nil {
+		return nil
+	}
+
+	err := g.tx.Rollback()
+	g.tx = nil
+
+	return err
+}
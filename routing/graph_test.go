@@ -0,0 +1,162 @@
+package routing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/lightningnetwork/lnd/channeldb"
+)
+
+// TestGraphSession asserts that a graph session traverses the same nodes and
+// channels as the graph itself, and that it picks up changes made to the
+// graph once released.
+func TestGraphSession(t *testing.T) {
+	t.Parallel()
+
+	graph, cleanUp, _, err := parseTestGraph(basicGraphFilePath)
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to create graph: %v", err)
+	}
+
+	session := newGraphSession(graph)
+	defer session.release()
+
+	countNodes := func() int {
+		var numNodes int
+		err := session.forEachNode(func(*channeldb.LightningNode) error {
+			numNodes++
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unable to traverse nodes: %v", err)
+		}
+		return numNodes
+	}
+
+	var expNodes int
+	err = graph.ForEachNode(nil, func(*bolt.Tx,
+		*channeldb.LightningNode) error {
+
+		expNodes++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unable to traverse nodes: %v", err)
+	}
+	if numNodes := countNodes(); numNodes != expNodes {
+		t.Fatalf("expected %v nodes, got %v", expNodes, numNodes)
+	}
+
+	// The channels of our own node should be traversed as well, with the
+	// outgoing edges leading to our peers.
+	sourceNode, err := graph.SourceNode()
+	if err != nil {
+		t.Fatalf("unable to fetch source node: %v", err)
+	}
+	var numChans int
+	err = session.forEachChannel(sourceNode, func(
+		_ *channeldb.ChannelEdgeInfo,
+		outEdge, _ *channeldb.ChannelEdgePolicy) error {
+
+		if outEdge.Node.PubKey.IsEqual(sourceNode.PubKey) {
+			t.Fatalf("outgoing edge leads back to source node")
+		}
+		numChans++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unable to traverse channels: %v", err)
+	}
+	if numChans != 3 {
+		t.Fatalf("expected 3 channels, got %v", numChans)
+	}
+
+	// Once released, a node added to the graph should be part of the
+	// session's next snapshot.
+	if err := session.release(); err != nil {
+		t.Fatalf("unable to release graph session: %v", err)
+	}
+	node, err := createTestNode()
+	if err != nil {
+		t.Fatalf("unable to create node: %v", err)
+	}
+	if err := graph.AddLightningNode(node); err != nil {
+		t.Fatalf("unable to add node: %v", err)
+	}
+	if numNodes := countNodes(); numNodes != expNodes+1 {
+		t.Fatalf("expected %v nodes, got %v", expNodes+1, numNodes)
+	}
+}
+
+// TestGraphSessionLookups asserts that the lookups made ahead of path finding
+// are served from the session's snapshot of the graph.
+func TestGraphSessionLookups(t *testing.T) {
+	t.Parallel()
+
+	graph, cleanUp, _, err := parseTestGraph(basicGraphFilePath)
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to create graph: %v", err)
+	}
+
+	session := newGraphSession(graph)
+	defer session.release()
+
+	sourceNode, err := graph.SourceNode()
+	if err != nil {
+		t.Fatalf("unable to fetch source node: %v", err)
+	}
+	exists, err := session.hasNode(sourceNode.PubKey)
+	if err != nil {
+		t.Fatalf("unable to look up node: %v", err)
+	}
+	if !exists {
+		t.Fatalf("source node not found")
+	}
+
+	// A node which isn't part of the graph shouldn't be found.
+	node, err := createTestNode()
+	if err != nil {
+		t.Fatalf("unable to create node: %v", err)
+	}
+	exists, err = session.hasNode(node.PubKey)
+	if err != nil {
+		t.Fatalf("unable to look up node: %v", err)
+	}
+	if exists {
+		t.Fatalf("unknown node found")
+	}
+
+	zombies, err := session.zombieEdges()
+	if err != nil {
+		t.Fatalf("unable to fetch zombies: %v", err)
+	}
+	if len(zombies) != 0 {
+		t.Fatalf("expected no zombies, got %v", len(zombies))
+	}
+
+	// Once released, the channels marked as zombies should be part of the
+	// session's next snapshot.
+	if err := session.release(); err != nil {
+		t.Fatalf("unable to release graph session: %v", err)
+	}
+	marked, err := graph.MarkZombieEdges(time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("unable to mark zombies: %v", err)
+	}
+	zombies, err = session.zombieEdges()
+	if err != nil {
+		t.Fatalf("unable to fetch zombies: %v", err)
+	}
+	if len(zombies) != len(marked) {
+		t.Fatalf("expected %v zombies, got %v", len(marked),
+			len(zombies))
+	}
+	for _, chanID := range marked {
+		if _, ok := zombies[chanID]; !ok {
+			t.Fatalf("channel %v not marked as zombie", chanID)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package routing
 
-import "github.com/lightningnetwork/lnd/channeldb"
+import (
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
 
 // nodeWithDist is a helper struct that couples the distance from the current
 // source to a node with a pointer to the node itself.
@@ -9,6 +12,20 @@ type nodeWithDist struct {
 	// current context.
 	dist float64
 
+	// weight is the cumulative weight of the path to this node, excluding
+	// the cost related to its probability of succeeding.
+	weight float64
+
+	// probability is the estimated probability of the path to this node
+	// succeeding.
+	probability float64
+
+	// fee is an estimate of the cumulative fee of the path to this node,
+	// while timeLock is the sum of the time lock deltas along it. Both are
+	// used to prune paths exceeding the limits of a route.
+	fee      lnwire.MilliAtom
+	timeLock uint32
+
 	// node is the vertex itself. This pointer can be used to explore all
 	// the outgoing edges (channels) emanating from a node.
 	node *channeldb.LightningNode
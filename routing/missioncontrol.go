@@ -0,0 +1,410 @@
+package routing
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+const (
+	// DefaultPenaltyHalfLife is the default duration after which the
+	// penalty applied to a node or channel that caused a payment to fail
+	// is halved.
+	DefaultPenaltyHalfLife = time.Hour
+
+	// DefaultAprioriHopProbability is the default probability assumed for
+	// a payment to be successfully forwarded over a hop that we have no
+	// history of.
+	DefaultAprioriHopProbability = 0.6
+
+	// prevSuccessProbability is the probability assumed for a payment to
+	// be successfully forwarded over a pair of nodes that has recently
+	// carried at least the same amount.
+	prevSuccessProbability = 0.95
+)
+
+// MissionControlConfig houses the parameters governing how mission control
+// estimates the probability of a payment being successfully forwarded over
+// the nodes and channels of the graph.
+type MissionControlConfig struct {
+	// PenaltyHalfLife is the duration after which the penalty resulting
+	// from a failed payment attempt is halved. The success probability of
+	// a node or pair that caused a payment to fail recovers towards the
+	// apriori probability accordingly.
+	PenaltyHalfLife time.Duration
+
+	// AprioriHopProbability is the probability assumed for a payment to
+	// be successfully forwarded over a hop that we have no history of. If
+	// zero, then DefaultAprioriHopProbability is used.
+	AprioriHopProbability float64
+}
+
+// nodePair is a directed pair of nodes. Pairs with a zero destination are
+// used to key the history of the source node itself.
+type nodePair struct {
+	from vertex
+	to   vertex
+}
+
+// missionControl records the outcome of past payment attempts, tracking for
+// each node and each directed pair of nodes when it last caused a payment to
+// fail, and when it last successfully carried one. From this history it
+// estimates the probability of a payment being successfully forwarded over
+// each edge, which path finding weighs against the fees of the edge. Failures
+// lower the probability of the affected edges, which recovers over time and
+// is restored once a later payment attempt succeeds. The history is persisted
+// so that it survives restarts.
+type missionControl struct {
+	cfg MissionControlConfig
+
+	// graph is the channel graph that the history is persisted within.
+	graph *channeldb.ChannelGraph
+
+	// source is our own node, which is the origin of every route.
+	source vertex
+
+	// now returns the current time, and can be overridden by tests.
+	now func() time.Time
+
+	mtx     sync.RWMutex
+	history map[nodePair]*channeldb.MissionControlEntry
+}
+
+// newMissionControl creates a new mission control instance, loading its
+// history from the passed channel graph.
+func newMissionControl(graph *channeldb.ChannelGraph, source vertex,
+	cfg MissionControlConfig) (*missionControl, error) {
+
+	entries, err := graph.FetchMissionControlEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	history := make(map[nodePair]*channeldb.MissionControlEntry, len(entries))
+	for _, entry := range entries {
+		history[nodePair{from: entry.From, to: entry.To}] = entry
+	}
+
+	if cfg.AprioriHopProbability == 0 {
+		cfg.AprioriHopProbability = DefaultAprioriHopProbability
+	}
+
+	return &missionControl{
+		cfg:     cfg,
+		graph:   graph,
+		source:  source,
+		now:     time.Now,
+		history: history,
+	}, nil
+}
+
+// recoveryFactor returns the factor by which the success probability of the
+// node or pair of the passed history entry is currently reduced. Directly
+// after a failure the factor is zero, and it then recovers towards one, with
+// the remaining reduction being halved every penalty half life. If the last
+// failure has since been followed by a success, then no reduction applies.
+//
+// NOTE: This method MUST be called with the mutex held.
+func (m *missionControl) recoveryFactor(entry *channeldb.MissionControlEntry,
+	now time.Time) float64 {
+
+	if entry.LastFail.IsZero() || !entry.LastFail.After(entry.LastSuccess) {
+		return 1
+	}
+
+	age := now.Sub(entry.LastFail)
+	if age <= 0 || m.cfg.PenaltyHalfLife <= 0 {
+		return 0
+	}
+
+	halvings := float64(age) / float64(m.cfg.PenaltyHalfLife)
+	return 1 - math.Pow(0.5, halvings)
+}
+
+// edgeProbability returns the estimated probability of a payment carrying amt
+// being successfully forwarded over the directed edge between the two passed
+// nodes. Without any history, the apriori hop probability is assumed, while a
+// pair that recently carried at least the same amount is assumed to be very
+// likely to succeed again. Both the history of the destination node, and of
+// the pair itself then reduce the probability if they recently caused a
+// payment to fail. As pairs commonly fail due to a lack of balance, a pair
+// failure only applies to amounts at least as large as the one that failed.
+func (m *missionControl) edgeProbability(from, to vertex,
+	amt lnwire.MilliAtom) float64 {
+
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	return m.probability(from, to, amt, m.now())
+}
+
+// probability returns the estimated probability of a payment carrying amt
+// being successfully forwarded over the directed edge between the two passed
+// nodes at the passed time.
+//
+// NOTE: This method MUST be called with the mutex held.
+func (m *missionControl) probability(from, to vertex, amt lnwire.MilliAtom,
+	now time.Time) float64 {
+
+	probability := m.cfg.AprioriHopProbability
+	pairEntry, havePair := m.history[nodePair{from: from, to: to}]
+	if havePair && pairEntry.LastSuccess.After(pairEntry.LastFail) &&
+		amt <= pairEntry.LastSuccessAmt {
+
+		probability = prevSuccessProbability
+	}
+
+	if nodeEntry, ok := m.history[nodePair{from: to}]; ok {
+		probability *= m.recoveryFactor(nodeEntry, now)
+	}
+	if havePair && amt >= pairEntry.LastFailAmt {
+		probability *= m.recoveryFactor(pairEntry, now)
+	}
+
+	return probability
+}
+
+// ProbabilityEstimate is the estimated probability of a payment being
+// successfully forwarded from one node to another, along with the history
+// mission control based the estimate upon.
+type ProbabilityEstimate struct {
+	// Probability is the estimated success probability, ranging from zero
+	// to one.
+	Probability float64
+
+	// PairHistory is the history of the directed pair of nodes, or nil if
+	// the pair wasn't involved in any past payment attempts.
+	PairHistory *channeldb.MissionControlEntry
+
+	// NodeHistory is the history of the destination node, or nil if the
+	// node wasn't involved in any past payment attempts.
+	NodeHistory *channeldb.MissionControlEntry
+}
+
+// estimate returns the estimated probability of a payment carrying amt being
+// successfully forwarded from one node to the other, along with copies of the
+// history entries the estimate is based on.
+func (m *missionControl) estimate(from, to vertex,
+	amt lnwire.MilliAtom) *ProbabilityEstimate {
+
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	estimate := &ProbabilityEstimate{
+		Probability: m.probability(from, to, amt, m.now()),
+	}
+	if entry, ok := m.history[nodePair{from: from, to: to}]; ok {
+		estimate.PairHistory = copyEntry(entry)
+	}
+	if entry, ok := m.history[nodePair{from: to}]; ok {
+		estimate.NodeHistory = copyEntry(entry)
+	}
+
+	return estimate
+}
+
+// routeProbability returns the estimated probability of a payment being
+// successfully forwarded over the passed route, which is the product of the
+// probabilities of each edge within it.
+func (m *missionControl) routeProbability(route *Route) float64 {
+	probability := 1.0
+	from := m.source
+	for _, hop := range route.Hops {
+		to := newVertex(hop.Channel.Node.PubKey)
+		probability *= m.edgeProbability(from, to, hopAmount(hop))
+		from = to
+	}
+
+	return probability
+}
+
+// hopAmount returns the amount carried over the channel leading to the passed
+// hop, which includes the fee the hop charges for forwarding.
+func hopAmount(hop *Hop) lnwire.MilliAtom {
+	return hop.AmtToForward + hop.Fee
+}
+
+// entry returns the history entry for the passed pair, creating it if it
+// doesn't exist yet.
+//
+// NOTE: This method MUST be called with the mutex held.
+func (m *missionControl) entry(pair nodePair) *channeldb.MissionControlEntry {
+	entry, ok := m.history[pair]
+	if !ok {
+		entry = &channeldb.MissionControlEntry{
+			From: pair.from,
+			To:   pair.to,
+		}
+		m.history[pair] = entry
+	}
+
+	return entry
+}
+
+// reportSuccess records that a payment was successfully carried over the
+// passed route, which restores the probability of every node and pair along
+// it.
+func (m *missionControl) reportSuccess(route *Route) {
+	m.mtx.Lock()
+
+	now := m.now()
+	updated := make([]*channeldb.MissionControlEntry, 0, 2*len(route.Hops))
+
+	from := m.source
+	for _, hop := range route.Hops {
+		to := newVertex(hop.Channel.Node.PubKey)
+
+		pairEntry := m.entry(nodePair{from: from, to: to})
+		pairEntry.LastSuccess = now
+		pairEntry.LastSuccessAmt = hopAmount(hop)
+
+		nodeEntry := m.entry(nodePair{from: to})
+		nodeEntry.LastSuccess = now
+
+		updated = append(updated, copyEntry(pairEntry), copyEntry(nodeEntry))
+		from = to
+	}
+
+	m.mtx.Unlock()
+
+	m.persist(updated)
+}
+
+// reportFailure interprets the failure of a payment attempt over the passed
+// route, based on the error returned by the switch, and records the outcome
+// for the nodes and pairs along the route accordingly. The interpretation is
+// returned, so the caller can act upon the reason of the failure.
+func (m *missionControl) reportFailure(route *Route,
+	sendErr error) *failureInterpretation {
+
+	i := interpretFailure(route, m.source, sendErr)
+
+	m.mtx.Lock()
+
+	now := m.now()
+	var updated []*channeldb.MissionControlEntry
+
+	// The pairs that forwarded the payment before it failed are recorded
+	// as successful, while the pairs or node responsible for the failure
+	// are penalized.
+	for _, result := range i.successPairs {
+		entry := m.entry(result.pair)
+		entry.LastSuccess = now
+		entry.LastSuccessAmt = result.amt
+		updated = append(updated, copyEntry(entry))
+	}
+	for _, result := range i.failedPairs {
+		entry := m.entry(result.pair)
+		entry.LastFail = now
+		entry.LastFailAmt = result.amt
+		updated = append(updated, copyEntry(entry))
+	}
+	if i.failedNode != nil {
+		entry := m.entry(nodePair{from: *i.failedNode})
+		entry.LastFail = now
+		updated = append(updated, copyEntry(entry))
+	}
+
+	m.mtx.Unlock()
+
+	m.persist(updated)
+
+	return i
+}
+
+// snapshot returns a copy of every entry within the history.
+func (m *missionControl) snapshot() []*channeldb.MissionControlEntry {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	entries := make([]*channeldb.MissionControlEntry, 0, len(m.history))
+	for _, entry := range m.history {
+		entries = append(entries, copyEntry(entry))
+	}
+
+	return entries
+}
+
+// reset wipes the history, both in memory and on disk.
+func (m *missionControl) reset() error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if err := m.graph.ResetMissionControl(); err != nil {
+		return err
+	}
+
+	m.history = make(map[nodePair]*channeldb.MissionControlEntry)
+
+	return nil
+}
+
+// importEntries merges the passed entries, typically obtained from another
+// node, into the history. For each node or pair, the imported failure and
+// success are only taken on if they're more recent than the ones we already
+// know of, so our own observations are never overridden by stale data.
+func (m *missionControl) importEntries(
+	entries []*channeldb.MissionControlEntry) error {
+
+	m.mtx.Lock()
+
+	updated := make([]*channeldb.MissionControlEntry, 0, len(entries))
+	for _, imported := range entries {
+		pair := nodePair{from: imported.From, to: imported.To}
+		entry := &channeldb.MissionControlEntry{
+			From: imported.From,
+			To:   imported.To,
+		}
+		if existing, ok := m.history[pair]; ok {
+			entry = copyEntry(existing)
+		}
+
+		var changed bool
+		if imported.LastFail.After(entry.LastFail) {
+			entry.LastFail = imported.LastFail
+			entry.LastFailAmt = imported.LastFailAmt
+			changed = true
+		}
+		if imported.LastSuccess.After(entry.LastSuccess) {
+			entry.LastSuccess = imported.LastSuccess
+			entry.LastSuccessAmt = imported.LastSuccessAmt
+			changed = true
+		}
+		if !changed {
+			continue
+		}
+
+		m.history[pair] = entry
+		updated = append(updated, copyEntry(entry))
+	}
+
+	m.mtx.Unlock()
+
+	if len(updated) == 0 {
+		return nil
+	}
+
+	return m.graph.PutMissionControlEntries(updated)
+}
+
+// persist writes the passed entries to the database, logging any failure as
+// the in-memory history remains authoritative until the next restart.
+func (m *missionControl) persist(entries []*channeldb.MissionControlEntry) {
+	if len(entries) == 0 {
+		return
+	}
+
+	if err := m.graph.PutMissionControlEntries(entries); err != nil {
+		log.Errorf("Unable to persist mission control history: %v", err)
+	}
+}
+
+// copyEntry returns a copy of the passed entry, so it can be persisted outside
+// of the mutex.
+func copyEntry(e *channeldb.MissionControlEntry) *channeldb.MissionControlEntry {
+	entryCopy := *e
+	return &entryCopy
+}
@@ -255,15 +255,15 @@ type ChannelEdgeUpdate struct {
 	Capacity btcutil.Amount
 
 	// MinHTLC is the minimum HTLC amount that this channel will forward.
-	MinHTLC lnwire.MilliSatoshi
+	MinHTLC lnwire.MilliAtom
 
 	// BaseFee is the base fee that will charged for all HTLC's forwarded
 	// across the this channel direction.
-	BaseFee lnwire.MilliSatoshi
+	BaseFee lnwire.MilliAtom
 
 	// FeeRate is the fee rate that will be shared for all HTLC's forwarded
 	// across this channel direction.
-	FeeRate lnwire.MilliSatoshi
+	FeeRate lnwire.MilliAtom
 
 	// TimeLockDelta is the time-lock expressed in blocks that will be
 	// added to outgoing HTLC's from incoming HTLC's. This value is the
@@ -322,7 +322,7 @@ func addToTopologyChange(graph *channeldb.ChannelGraph, update *TopologyChange,
 		// the second node.
 		sourceNode := edgeInfo.NodeKey1
 		connectingNode := edgeInfo.NodeKey2
-		if m.Flags == 1 {
+		if m.ChannelFlags&lnwire.ChanUpdateDirection == 1 {
 			sourceNode = edgeInfo.NodeKey2
 			connectingNode = edgeInfo.NodeKey1
 		}
@@ -71,9 +71,9 @@ func randEdgePolicy(chanID *lnwire.ShortChannelID,
 		ChannelID:                 chanID.ToUint64(),
 		LastUpdate:                time.Unix(int64(prand.Int31()), 0),
 		TimeLockDelta:             uint16(prand.Int63()),
-		MinHTLC:                   lnwire.MilliSatoshi(prand.Int31()),
-		FeeBaseMSat:               lnwire.MilliSatoshi(prand.Int31()),
-		FeeProportionalMillionths: lnwire.MilliSatoshi(prand.Int31()),
+		MinHTLC:                   lnwire.MilliAtom(prand.Int31()),
+		FeeBaseMSat:               lnwire.MilliAtom(prand.Int31()),
+		FeeProportionalMillionths: lnwire.MilliAtom(prand.Int31()),
 		Node: node,
 	}
 }
@@ -205,9 +205,165 @@ func (m *mockChain) GetBlock(blockHash *chainhash.Hash) (*wire.MsgBlock, error)
 	return block, nil
 }
 
+type mockPaymentStore struct {
+	payments map[[32]byte]*channeldb.MPPayment
+	nextID   uint64
+
+	sync.Mutex
+}
+
+// A compile time check to ensure mockPaymentStore implements the
+// PaymentStore interface.
+var _ PaymentStore = (*mockPaymentStore)(nil)
+
+func newMockPaymentStore() *mockPaymentStore {
+	return &mockPaymentStore{
+		payments: make(map[[32]byte]*channeldb.MPPayment),
+	}
+}
+
+func (m *mockPaymentStore) InitPayment(hash [32]byte,
+	info *channeldb.PaymentCreationInfo) error {
+
+	m.Lock()
+	defer m.Unlock()
+
+	if p, ok := m.payments[hash]; ok {
+		switch p.Status {
+		case channeldb.StatusInFlight:
+			return channeldb.ErrPaymentInFlight
+		case channeldb.StatusSucceeded:
+			return channeldb.ErrAlreadyPaid
+		}
+	}
+
+	m.payments[hash] = &channeldb.MPPayment{
+		Info:   info,
+		Status: channeldb.StatusInFlight,
+	}
+
+	return nil
+}
+
+func (m *mockPaymentStore) RegisterAttempt(hash [32]byte,
+	attempt *channeldb.HTLCAttemptInfo) error {
+
+	m.Lock()
+	defer m.Unlock()
+
+	p, ok := m.payments[hash]
+	if !ok {
+		return channeldb.ErrPaymentNotInitiated
+	}
+
+	m.nextID++
+	attempt.AttemptID = m.nextID
+	p.HTLCs = append(p.HTLCs, channeldb.HTLCAttempt{
+		HTLCAttemptInfo: *attempt,
+	})
+
+	return nil
+}
+
+func (m *mockPaymentStore) SettleAttempt(hash [32]byte, id uint64,
+	settle *channeldb.HTLCSettleInfo) error {
+
+	m.Lock()
+	defer m.Unlock()
+
+	p, ok := m.payments[hash]
+	if !ok {
+		return channeldb.ErrPaymentNotInitiated
+	}
+	for i := range p.HTLCs {
+		if p.HTLCs[i].AttemptID == id {
+			p.HTLCs[i].Settle = settle
+			p.Status = channeldb.StatusSucceeded
+			return nil
+		}
+	}
+
+	return channeldb.ErrAttemptNotFound
+}
+
+func (m *mockPaymentStore) FailAttempt(hash [32]byte, id uint64,
+	fail *channeldb.HTLCFailInfo) error {
+
+	m.Lock()
+	defer m.Unlock()
+
+	p, ok := m.payments[hash]
+	if !ok {
+		return channeldb.ErrPaymentNotInitiated
+	}
+	for i := range p.HTLCs {
+		if p.HTLCs[i].AttemptID == id {
+			p.HTLCs[i].Failure = fail
+			return nil
+		}
+	}
+
+	return channeldb.ErrAttemptNotFound
+}
+
+func (m *mockPaymentStore) FailPayment(hash [32]byte,
+	reason channeldb.FailureReason) error {
+
+	m.Lock()
+	defer m.Unlock()
+
+	p, ok := m.payments[hash]
+	if !ok {
+		return channeldb.ErrPaymentNotInitiated
+	}
+	p.FailureReason = &reason
+	p.Status = channeldb.StatusFailed
+
+	return nil
+}
+
+func (m *mockPaymentStore) FetchPayment(
+	hash [32]byte) (*channeldb.MPPayment, error) {
+
+	m.Lock()
+	defer m.Unlock()
+
+	p, ok := m.payments[hash]
+	if !ok {
+		return nil, channeldb.ErrPaymentNotInitiated
+	}
+
+	payment := *p
+	payment.HTLCs = append([]channeldb.HTLCAttempt(nil), p.HTLCs...)
+
+	return &payment, nil
+}
+
+func (m *mockPaymentStore) FetchInFlightPayments() ([]*channeldb.MPPayment,
+	error) {
+
+	m.Lock()
+	defer m.Unlock()
+
+	var inFlights []*channeldb.MPPayment
+	for _, p := range m.payments {
+		if p.Status != channeldb.StatusInFlight {
+			continue
+		}
+
+		payment := *p
+		payment.HTLCs = append([]channeldb.HTLCAttempt(nil), p.HTLCs...)
+		inFlights = append(inFlights, &payment)
+	}
+
+	return inFlights, nil
+}
+
 type mockChainView struct {
 	sync.RWMutex
 
+	chain *mockChain
+
 	newBlocks   chan *chainview.FilteredBlock
 	staleBlocks chan *chainview.FilteredBlock
 
@@ -218,8 +374,9 @@ type mockChainView struct {
 // chainview.FilteredChainView.
 var _ chainview.FilteredChainView = (*mockChainView)(nil)
 
-func newMockChainView() *mockChainView {
+func newMockChainView(chain *mockChain) *mockChainView {
 	return &mockChainView{
+		chain:       chain,
 		newBlocks:   make(chan *chainview.FilteredBlock, 10),
 		staleBlocks: make(chan *chainview.FilteredBlock, 10),
 		filter:      make(map[wire.OutPoint]struct{}),
@@ -259,7 +416,29 @@ func (m *mockChainView) DisconnectedBlocks() <-chan *chainview.FilteredBlock {
 }
 
 func (m *mockChainView) FilterBlock(blockHash *chainhash.Hash) (*chainview.FilteredBlock, error) {
-	return nil, nil
+	block, err := m.chain.GetBlock(blockHash)
+	if err != nil {
+		return nil, err
+	}
+
+	m.RLock()
+	defer m.RUnlock()
+
+	filteredBlock := &chainview.FilteredBlock{
+		Hash: *blockHash,
+	}
+	for _, tx := range block.Transactions {
+		for _, txIn := range tx.TxIn {
+			if _, ok := m.filter[txIn.PreviousOutPoint]; ok {
+				filteredBlock.Transactions = append(
+					filteredBlock.Transactions, tx,
+				)
+				break
+			}
+		}
+	}
+
+	return filteredBlock, nil
 }
 
 func (m *mockChainView) Start() error {
@@ -338,9 +517,9 @@ func TestEdgeUpdateNotification(t *testing.T) {
 	// Create random policy edges that are stemmed to the channel id
 	// created above.
 	edge1 := randEdgePolicy(chanID, node1)
-	edge1.Flags = 0
+	edge1.ChannelFlags = 0
 	edge2 := randEdgePolicy(chanID, node2)
-	edge2.Flags = 1
+	edge2.ChannelFlags = 1
 
 	if err := ctx.router.UpdateEdge(edge1); err != nil {
 		t.Fatalf("unable to add edge update: %v", err)
@@ -1,16 +1,19 @@
 package routing
 
 import (
+	"bytes"
 	"encoding/binary"
 	"fmt"
+	"io"
 	"math"
 
 	"container/heap"
 
-	"github.com/boltdb/bolt"
 	"github.com/lightningnetwork/lightning-onion"
 	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/htlcswitch"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/tlv"
 	"github.com/roasbeef/btcd/btcec"
 	"github.com/roasbeef/btcd/chaincfg/chainhash"
 	"github.com/roasbeef/btcutil"
@@ -25,8 +28,128 @@ const (
 
 	// infinity is used as a starting distance in our shortest path search.
 	infinity = math.MaxFloat64
+
+	// DefaultAttemptCost is the default virtual cost in path finding of a
+	// payment attempt. It is used to trade off potentially better routes
+	// against their probability of succeeding.
+	DefaultAttemptCost = lnwire.MilliAtom(100000)
+
+	// DefaultMinRouteProbability is the default minimum probability for
+	// routes returned from path finding.
+	DefaultMinRouteProbability = 0.01
+
+	// DefaultCltvLimit is the maximum total time lock delta, in blocks,
+	// of the routes used by payments that don't specify their own limit.
+	DefaultCltvLimit = 2016
+
+	// riskFactorBillionths controls the influence of the time lock delta
+	// of a hop on its weight. It is expressed as the value, in billionths
+	// of the amount carried, that is considered to be lost for each block
+	// the funds are locked up for.
+	riskFactorBillionths = 15
+
+	// hopHintCapacity is the capacity assumed for the channels described
+	// by route hints. As hints don't carry the capacity of the private
+	// channels they describe, we optimistically assume that they're able
+	// to carry any payment, leaving the nodes along them to reject it
+	// otherwise.
+	hopHintCapacity = btcutil.Amount(btcutil.MaxSatoshi)
 )
 
+// PathFindingConfig houses the knobs controlling how path finding trades off
+// the fees of a route against its probability of succeeding.
+type PathFindingConfig struct {
+	// AttemptCost is the virtual cost of a payment attempt. During path
+	// finding, it is divided by the probability of a route succeeding to
+	// arrive at the expected cost of the attempts needed to complete the
+	// payment, which is added to the fees of the route. A higher attempt
+	// cost thus favors routes that are more likely to succeed, even if
+	// they're more expensive.
+	AttemptCost lnwire.MilliAtom
+
+	// MinProbability is the minimum estimated probability of success for
+	// a route to be considered during path finding.
+	MinProbability float64
+}
+
+// RouteRestrictions houses the restrictions placed upon the routes returned by
+// path finding, beyond the amount they must be able to carry.
+type RouteRestrictions struct {
+	// OutgoingChannelIDs is the set of channels of the source node that
+	// the first hop of a route may go over. If empty, any channel of the
+	// source node may be used.
+	OutgoingChannelIDs []uint64
+
+	// LastHop, if set, is the node that must directly precede the target
+	// within a route.
+	LastHop *btcec.PublicKey
+
+	// FeeLimit, if set, is the maximum total fee of a route. A zero fee
+	// limit only allows routes without any fee. If nil, the fees of routes
+	// aren't limited.
+	FeeLimit *lnwire.MilliAtom
+
+	// CltvLimit is the maximum total time lock delta of a route, in
+	// blocks. A zero limit indicates that routes aren't limited.
+	CltvLimit uint32
+
+	// IgnoredNodes is the set of nodes that may not be used as a hop
+	// within a route.
+	IgnoredNodes []*btcec.PublicKey
+
+	// IgnoredEdges is the set of directed edges that may not be traversed
+	// by a route. The channels they belong to may still be traversed in
+	// the opposite direction.
+	IgnoredEdges map[EdgeLocator]struct{}
+}
+
+// EdgeLocator identifies a directed edge of the channel graph, which is one
+// of the two directions in which a channel may be traversed.
+type EdgeLocator struct {
+	// ChannelID is the unique identifier of the channel.
+	ChannelID uint64
+
+	// Direction is the direction in which the channel is traversed. As
+	// with the direction bit of channel updates, zero indicates traversal
+	// from the node with the lexicographically smaller public key to the
+	// other, while one indicates the reverse.
+	Direction uint8
+}
+
+// newEdgeLocator returns the locator of the edge traversing the passed
+// channel from one node to the other.
+func newEdgeLocator(chanID uint64, from, to vertex) EdgeLocator {
+	locator := EdgeLocator{ChannelID: chanID}
+	if bytes.Compare(from[:], to[:]) > 0 {
+		locator.Direction = 1
+	}
+
+	return locator
+}
+
+// HopHint describes a private channel, unknown to the channel graph, that may
+// be used to reach the destination of a payment. Route hints are supplied by
+// the receiver of a payment as chains of hop hints, each leading from a node
+// that we may be able to reach towards the next one, with the final hint of a
+// chain leading to the destination itself.
+type HopHint struct {
+	// NodeID is the public key of the node at the start of the channel.
+	NodeID *btcec.PublicKey
+
+	// ChannelID is the unique identifier of the channel.
+	ChannelID uint64
+
+	// FeeBaseMSat is the base fee of the channel in milli-satoshis.
+	FeeBaseMSat lnwire.MilliAtom
+
+	// FeeProportionalMillionths is the fee rate, in millionths of a
+	// satoshi, for every satoshi sent through the channel.
+	FeeProportionalMillionths lnwire.MilliAtom
+
+	// CLTVExpiryDelta is the time-lock delta of the channel.
+	CLTVExpiryDelta uint16
+}
+
 // ChannelHop is an intermediate hop within the network with a greater
 // multi-hop payment route. This struct contains the relevant routing policy of
 // the particular edge, as well as the total capacity, and origin chain of the
@@ -62,18 +185,55 @@ type Hop struct {
 	// AmtToForward is the amount that this hop will forward to the next
 	// hop. This value is less than the value that the incoming HTLC
 	// carries as a fee will be subtracted by the hop.
-	AmtToForward lnwire.MilliSatoshi
+	AmtToForward lnwire.MilliAtom
 
 	// Fee is the total fee that this hop will subtract from the incoming
 	// payment, this difference nets the hop fees for forwarding the
 	// payment.
-	Fee lnwire.MilliSatoshi
+	Fee lnwire.MilliAtom
+
+	// MPP encapsulates the payment secret and total amount of the payment,
+	// which are included within the TLV payload of the final hop. If set,
+	// then the final hop can verify that the sender knows the invoice
+	// being paid, and that all parts of the payment have arrived.
+	MPP *lnwire.MPP
+}
+
+// PackHopPayload writes to the passed io.Writer the TLV onion payload of this
+// hop. The next hop's channel ID is only included for intermediate hops,
+// while the payment data is only included for the final hop, signalled by a
+// zero nextChanID.
+func (h *Hop) PackHopPayload(w io.Writer, nextChanID uint64) error {
+	amt := uint64(h.AmtToForward)
+	records := []tlv.Record{
+		lnwire.NewAmtToFwdRecord(&amt),
+		lnwire.NewLockTimeRecord(&h.OutgoingTimeLock),
+	}
+
+	if nextChanID != 0 {
+		records = append(records, lnwire.NewNextHopIDRecord(&nextChanID))
+	}
+
+	if h.MPP != nil {
+		if nextChanID != 0 {
+			return fmt.Errorf("payment data can only be included " +
+				"in the payload of the final hop")
+		}
+		records = append(records, h.MPP.Record())
+	}
+
+	tlvStream, err := tlv.NewStream(records...)
+	if err != nil {
+		return err
+	}
+
+	return tlvStream.Encode(w)
 }
 
 // computeFee computes the fee to forward an HTLC of `amt` milli-satoshis over
 // the passed active payment channel. This value is currently computed as
 // specified in BOLT07, but will likely change in the near future.
-func computeFee(amt lnwire.MilliSatoshi, edge *ChannelHop) lnwire.MilliSatoshi {
+func computeFee(amt lnwire.MilliAtom, edge *ChannelHop) lnwire.MilliAtom {
 	return edge.FeeBaseMSat + (amt*edge.FeeProportionalMillionths)/1000000
 }
 
@@ -110,7 +270,7 @@ type Route struct {
 	// TotalFees is the sum of the fees paid at each hop within the final
 	// route. In the case of a one-hop payment, this value will be zero as
 	// we don't need to pay a fee it ourself.
-	TotalFees lnwire.MilliSatoshi
+	TotalFees lnwire.MilliAtom
 
 	// TotalAmount is the total amount of funds required to complete a
 	// payment over this route. This value includes the cumulative fees at
@@ -118,13 +278,87 @@ type Route struct {
 	// route will need to have at least this many satoshis, otherwise the
 	// route will fail at an intermediate node due to an insufficient
 	// amount of fees.
-	TotalAmount lnwire.MilliSatoshi
+	TotalAmount lnwire.MilliAtom
 
 	// Hops contains details concerning the specific forwarding details at
 	// each hop.
 	Hops []*Hop
 }
 
+// toDBRoute converts the route into the format used to persist payment
+// attempts within the database.
+func (r *Route) toDBRoute() channeldb.Route {
+	dbRoute := channeldb.Route{
+		TotalTimeLock: r.TotalTimeLock,
+		TotalFees:     r.TotalFees,
+		TotalAmount:   r.TotalAmount,
+		Hops:          make([]*channeldb.Hop, len(r.Hops)),
+	}
+	for i, hop := range r.Hops {
+		dbHop := &channeldb.Hop{
+			ChannelID:        hop.Channel.ChannelID,
+			OutgoingTimeLock: hop.OutgoingTimeLock,
+			AmtToForward:     hop.AmtToForward,
+			Fee:              hop.Fee,
+		}
+		pubKey := hop.Channel.Node.PubKey.SerializeCompressed()
+		copy(dbHop.PubKeyBytes[:], pubKey)
+
+		dbRoute.Hops[i] = dbHop
+	}
+
+	return dbRoute
+}
+
+// newRouteFromDBRoute converts a route persisted within the database back
+// into its in-memory form. Only the details needed to attribute the outcome of
+// a payment attempt to the hops of the route are restored.
+func newRouteFromDBRoute(dbRoute *channeldb.Route) (*Route, error) {
+	route := &Route{
+		TotalTimeLock: dbRoute.TotalTimeLock,
+		TotalFees:     dbRoute.TotalFees,
+		TotalAmount:   dbRoute.TotalAmount,
+		Hops:          make([]*Hop, len(dbRoute.Hops)),
+	}
+	for i, dbHop := range dbRoute.Hops {
+		pub, err := btcec.ParsePubKey(dbHop.PubKeyBytes[:], btcec.S256())
+		if err != nil {
+			return nil, err
+		}
+
+		route.Hops[i] = &Hop{
+			Channel: &ChannelHop{
+				ChannelEdgePolicy: &channeldb.ChannelEdgePolicy{
+					ChannelID: dbHop.ChannelID,
+					Node: &channeldb.LightningNode{
+						PubKey: pub,
+					},
+				},
+			},
+			OutgoingTimeLock: dbHop.OutgoingTimeLock,
+			AmtToForward:     dbHop.AmtToForward,
+			Fee:              dbHop.Fee,
+		}
+	}
+
+	return route, nil
+}
+
+// withMPP returns a copy of the route whose final hop carries the passed
+// payment data. The original route, which may be shared with the route cache,
+// is left untouched.
+func (r *Route) withMPP(mpp *lnwire.MPP) *Route {
+	routeCopy := *r
+	routeCopy.Hops = make([]*Hop, len(r.Hops))
+	copy(routeCopy.Hops, r.Hops)
+
+	finalHop := *r.Hops[len(r.Hops)-1]
+	finalHop.MPP = mpp
+	routeCopy.Hops[len(r.Hops)-1] = &finalHop
+
+	return &routeCopy
+}
+
 // ToHopPayloads converts a complete route into the series of per-hop payloads
 // that is to be encoded within each HTLC using an opaque Sphinx packet.
 func (r *Route) ToHopPayloads() []sphinx.HopData {
@@ -159,6 +393,24 @@ func (r *Route) ToHopPayloads() []sphinx.HopData {
 	return hopPayloads
 }
 
+// finalHopFrames returns the frames of hop data carrying the TLV payload of
+// the route's final hop, which replace its legacy hop data. A TLV payload is
+// only needed to deliver payment data to the final hop, so nil is returned
+// if there is none.
+func (r *Route) finalHopFrames() ([]sphinx.HopData, error) {
+	finalHop := r.Hops[len(r.Hops)-1]
+	if finalHop.MPP == nil {
+		return nil, nil
+	}
+
+	var b bytes.Buffer
+	if err := finalHop.PackHopPayload(&b, 0); err != nil {
+		return nil, err
+	}
+
+	return htlcswitch.NewTLVHopData(b.Bytes())
+}
+
 // sortableRoutes is a slice of routes that can be sorted. Routes are typically
 // sorted according to their total cumulative fee within the route. In the case
 // that two routes require and identical amount of fees, then the total
@@ -201,7 +453,7 @@ func (s sortableRoutes) Swap(i, j int) {
 //
 // NOTE: The passed slice of ChannelHops MUST be sorted in forward order: from
 // the source to the target node of the path finding attempt.
-func newRoute(amtToSend lnwire.MilliSatoshi, pathEdges []*ChannelHop,
+func newRoute(amtToSend lnwire.MilliAtom, pathEdges []*ChannelHop,
 	currentHeight uint32) (*Route, error) {
 
 	// First, we'll create a new empty route with enough hops to match the
@@ -247,6 +499,18 @@ func newRoute(amtToSend lnwire.MilliSatoshi, pathEdges []*ChannelHop,
 			return nil, newErrf(ErrInsufficientCapacity, err)
 		}
 
+		// Similarly, if the node forwarding over this channel
+		// advertised a maximum HTLC, then we ensure the amount to
+		// forward doesn't exceed it.
+		if edge.MessageFlags.HasMaxHtlc() &&
+			nextHop.AmtToForward > edge.MaxHTLC {
+
+			return nil, newErrf(ErrInsufficientCapacity, "channel "+
+				"%v can't carry the payment: need %v, max htlc "+
+				"is %v", edge.ChannelID, nextHop.AmtToForward,
+				edge.MaxHTLC)
+		}
+
 		// We don't pay any fees to ourselves on the first-hop channel,
 		// so we don't tally up the running fee and amount.
 		if i != len(pathEdges)-1 {
@@ -315,27 +579,136 @@ type edgeWithPrev struct {
 	prevNode *btcec.PublicKey
 }
 
-// edgeWeight computes the weight of an edge. This value is used when searching
-// for the shortest path within the channel graph between two nodes. Currently
-// this is just 1 + the cltv delta value required at this hop, this value
-// should be tuned with experimental and empirical data.
-//
-// TODO(roasbeef): compute robust weight metric
-func edgeWeight(e *channeldb.ChannelEdgePolicy) float64 {
-	return float64(1 + e.TimeLockDelta)
+// hintEdges converts the passed route hints into the set of additional edges
+// to traverse during path finding, keyed by the node each edge emanates from.
+// Each hint within a chain describes the channel leading from its node to the
+// node of the next hint, while the last hint leads to the target itself.
+func hintEdges(target *btcec.PublicKey,
+	routeHints [][]HopHint) map[vertex][]*channeldb.ChannelEdgePolicy {
+
+	edges := make(map[vertex][]*channeldb.ChannelEdgePolicy)
+
+	// Only the public keys of the nodes the edges lead to are needed, so
+	// we'll use a bare node for each of them.
+	nodes := make(map[vertex]*channeldb.LightningNode)
+	nodeFor := func(pub *btcec.PublicKey) *channeldb.LightningNode {
+		v := newVertex(pub)
+		if node, ok := nodes[v]; ok {
+			return node
+		}
+
+		node := &channeldb.LightningNode{PubKey: pub}
+		nodes[v] = node
+		return node
+	}
+
+	for _, routeHint := range routeHints {
+		for i, hopHint := range routeHint {
+			toPub := target
+			if i != len(routeHint)-1 {
+				toPub = routeHint[i+1].NodeID
+			}
+
+			from := newVertex(hopHint.NodeID)
+			edges[from] = append(edges[from], &channeldb.ChannelEdgePolicy{
+				ChannelID:                 hopHint.ChannelID,
+				TimeLockDelta:             hopHint.CLTVExpiryDelta,
+				FeeBaseMSat:               hopHint.FeeBaseMSat,
+				FeeProportionalMillionths: hopHint.FeeProportionalMillionths,
+				Node:                      nodeFor(toPub),
+			})
+		}
+	}
+
+	return edges
+}
+
+// edgeWeight computes the weight of an edge, excluding the cost related to
+// its probability of succeeding. This value is used when searching for the
+// shortest path within the channel graph between two nodes. The weight is the
+// fee charged for forwarding amt over the edge, plus a penalty for the time
+// the funds are locked up for which is proportional to the amount and the
+// time lock delta of the edge. As in newRoute, no fee is charged for the edge
+// leading to the final hop.
+func edgeWeight(amt lnwire.MilliAtom, e *ChannelHop, finalHop bool) float64 {
+	timeLockPenalty := float64(amt) * float64(e.TimeLockDelta) *
+		riskFactorBillionths / 1000000000
+
+	if finalHop {
+		return timeLockPenalty
+	}
+
+	return float64(computeFee(amt, e)) + timeLockPenalty
 }
 
+// edgeProbabilityFunc returns the estimated probability of a payment carrying
+// amt being successfully forwarded over the directed edge between the passed
+// nodes, based on the outcome of past payment attempts.
+type edgeProbabilityFunc func(from, to vertex, amt lnwire.MilliAtom) float64
+
 // findPath attempts to find a path from the source node within the
 // ChannelGraph to the target node that's capable of supporting a payment of
 // `amt` value. The current approach implemented is modified version of
 // Dijkstra's algorithm to find a single shortest path between the source node
-// and the destination. The distance metric used for edges is related to the
-// time-lock+fee costs along a particular edge. If a path is found, this
+// and the destination. The distance metric is the expected cost of a path:
+// the time-lock+fee costs along it, plus the configured attempt cost divided
+// by the probability of the path succeeding. If a path is found, this
 // function returns a slice of ChannelHop structs which encoded the chosen path
-// from the target to the source.
-func findPath(graph *channeldb.ChannelGraph, sourceNode *channeldb.LightningNode,
+// from the target to the source. If no probability function is passed, then
+// every edge is assumed to succeed, while a nil config imposes neither an
+// attempt cost nor a minimum probability. Any additional edges, keyed by the
+// node they emanate from, are traversed along with the channels of the graph,
+// allowing private channels learnt through route hints to be used. Edges
+// which are disabled, or whose maximum HTLC is below the amount, are never
+// traversed. The passed bandwidth hints carry the current outgoing bandwidth
+// of our own channels, and any of them unable to carry the amount is skipped.
+// If route restrictions are passed, then only paths abiding by them are
+// considered. The graph is traversed within the current snapshot of the
+// passed session.
+func findPath(graph *graphSession, sourceNode *channeldb.LightningNode,
 	target *btcec.PublicKey, ignoredNodes map[vertex]struct{},
-	ignoredEdges map[uint64]struct{}, amt lnwire.MilliSatoshi) ([]*ChannelHop, error) {
+	ignoredEdges map[uint64]struct{},
+	additionalEdges map[vertex][]*channeldb.ChannelEdgePolicy,
+	bandwidthHints map[uint64]lnwire.MilliAtom,
+	restrictions *RouteRestrictions, amt lnwire.MilliAtom,
+	probabilitySource edgeProbabilityFunc,
+	cfg *PathFindingConfig) ([]*ChannelHop, error) {
+
+	var (
+		attemptCost    float64
+		minProbability float64
+	)
+	if cfg != nil {
+		attemptCost = float64(cfg.AttemptCost)
+		minProbability = cfg.MinProbability
+	}
+
+	// If the route is restricted to leave the source over a set of its
+	// channels, or to reach the target through a specific node, we'll
+	// note those restrictions in a form that's quick to look up.
+	var (
+		outgoingChannels map[uint64]struct{}
+		lastHop          *vertex
+		feeLimit         *lnwire.MilliAtom
+		cltvLimit        uint32
+		ignoredDirEdges  map[EdgeLocator]struct{}
+	)
+	if restrictions != nil {
+		feeLimit = restrictions.FeeLimit
+		cltvLimit = restrictions.CltvLimit
+		ignoredDirEdges = restrictions.IgnoredEdges
+
+		if len(restrictions.OutgoingChannelIDs) != 0 {
+			outgoingChannels = make(map[uint64]struct{})
+			for _, chanID := range restrictions.OutgoingChannelIDs {
+				outgoingChannels[chanID] = struct{}{}
+			}
+		}
+		if restrictions.LastHop != nil {
+			v := newVertex(restrictions.LastHop)
+			lastHop = &v
+		}
+	}
 
 	// First we'll initialize an empty heap which'll help us to quickly
 	// locate the next edge we should visit next during our graph
@@ -346,7 +719,7 @@ func findPath(graph *channeldb.ChannelGraph, sourceNode *channeldb.LightningNode
 	// map for the node set with a distance of "infinity".  We also mark
 	// add the node to our set of unvisited nodes.
 	distance := make(map[vertex]nodeWithDist)
-	if err := graph.ForEachNode(nil, func(_ *bolt.Tx, node *channeldb.LightningNode) error {
+	if err := graph.forEachNode(func(node *channeldb.LightningNode) error {
 		// TODO(roasbeef): with larger graph can just use disk seeks
 		// with a visited map
 		distance[newVertex(node.PubKey)] = nodeWithDist{
@@ -358,19 +731,46 @@ func findPath(graph *channeldb.ChannelGraph, sourceNode *channeldb.LightningNode
 		return nil, err
 	}
 
+	// The nodes reached through route hints may be absent from the graph,
+	// as may the destination of the payment itself. We'll add those to the
+	// distance map as well, and keep track of them so that we don't look
+	// for their channels within the graph.
+	hintNodes := make(map[vertex]struct{})
+	for _, edges := range additionalEdges {
+		for _, edge := range edges {
+			v := newVertex(edge.Node.PubKey)
+			if _, ok := distance[v]; ok {
+				continue
+			}
+
+			distance[v] = nodeWithDist{
+				dist: infinity,
+				node: edge.Node,
+			}
+			hintNodes[v] = struct{}{}
+		}
+	}
+
 	// To start, we add the source of our path finding attempt to the
 	// distance map with with a distance of 0. This indicates our starting
 	// point in the graph traversal.
 	sourceVertex := newVertex(sourceNode.PubKey)
+	targetVertex := newVertex(target)
 	distance[sourceVertex] = nodeWithDist{
-		dist: 0,
-		node: sourceNode,
+		dist:        0,
+		probability: 1,
+		node:        sourceNode,
 	}
 
 	// To start, our source node will the sole item within our distance
 	// heap.
 	heap.Push(&nodeHeap, distance[sourceVertex])
 
+	// feeLimitHit records whether any edge was skipped due to the fee
+	// limit, in which case we'll report the limit as the reason no path
+	// was found.
+	var feeLimitHit bool
+
 	// We'll use this map as a series of "previous" hop pointers. So to get
 	// to `vertex` we'll take the edge that it's mapped to within `prev`.
 	prev := make(map[vertex]edgeWithPrev)
@@ -388,35 +788,113 @@ func findPath(graph *channeldb.ChannelGraph, sourceNode *channeldb.LightningNode
 			break
 		}
 
-		// Now that we've found the next potential step to take we'll
-		// examine all the outgoing edge (channels) from this node to
-		// further our graph traversal.
+		// processEdge relaxes the edge leading from our current pivot
+		// to toNode, governed by the passed policy, should it improve
+		// upon the best known distance to toNode.
 		pivot := newVertex(bestNode.PubKey)
-		err := bestNode.ForEachChannel(nil, func(tx *bolt.Tx,
-			edgeInfo *channeldb.ChannelEdgeInfo,
-			outEdge, inEdge *channeldb.ChannelEdgePolicy) error {
-
-			v := newVertex(outEdge.Node.PubKey)
+		processEdge := func(toNode *channeldb.LightningNode,
+			edge *channeldb.ChannelEdgePolicy,
+			capacity btcutil.Amount) {
 
-			// TODO(roasbeef): skip if disabled
+			v := newVertex(toNode.PubKey)
 
 			// If this vertex or edge has been black listed, then
 			// we'll skip exploring this edge during this
 			// iteration.
 			if _, ok := ignoredNodes[v]; ok {
-				return nil
+				return
 			}
-			if _, ok := ignoredEdges[outEdge.ChannelID]; ok {
-				return nil
+			if _, ok := ignoredEdges[edge.ChannelID]; ok {
+				return
 			}
-			if inEdge == nil {
-				return nil
+			if len(ignoredDirEdges) != 0 {
+				locator := newEdgeLocator(edge.ChannelID, pivot, v)
+				if _, ok := ignoredDirEdges[locator]; ok {
+					return
+				}
+			}
+
+			// Similarly, we'll skip any edges that would violate
+			// the restrictions placed upon the route: the first
+			// hop must go over one of the permitted channels, and
+			// the last hop must start from the permitted node.
+			if outgoingChannels != nil && pivot == sourceVertex {
+				_, ok := outgoingChannels[edge.ChannelID]
+				if !ok {
+					return
+				}
+			}
+			if lastHop != nil && v == targetVertex && pivot != *lastHop {
+				return
+			}
+
+			// If the node at the other end of this channel
+			// advertised a maximum HTLC that the amount exceeds,
+			// then the channel can't carry the payment.
+			if edge.MessageFlags.HasMaxHtlc() && amt > edge.MaxHTLC {
+				return
+			}
+
+			// We'll also skip any edge whose latest policy
+			// disables it.
+			if edge.ChannelFlags&lnwire.ChanUpdateDisabled != 0 {
+				return
+			}
+
+			// If this is one of our own channels, then the switch
+			// knows how much it's currently able to carry, which
+			// accounts for the HTLCs already in flight over it, or
+			// the link being offline altogether.
+			bandwidth, ok := bandwidthHints[edge.ChannelID]
+			if ok && bandwidth < amt {
+				return
+			}
+
+			// Compute the probability of the path to our current
+			// pivot being extended over this edge. If it drops
+			// below the minimum, then we won't consider the edge.
+			edgeProbability := 1.0
+			if probabilitySource != nil {
+				edgeProbability = probabilitySource(pivot, v, amt)
+			}
+			tempProbability := distance[pivot].probability *
+				edgeProbability
+			if tempProbability == 0 || tempProbability < minProbability {
+				return
 			}
 
 			// Compute the tentative distance to this new
-			// channel/edge which is the distance to our current
-			// pivot node plus the weight of this edge.
-			tempDist := distance[pivot].dist + edgeWeight(inEdge)
+			// channel/edge which is the weight of the path to our
+			// current pivot node plus the weight of this edge,
+			// along with the expected cost of the attempts needed
+			// for the extended path to succeed.
+			hop := &ChannelHop{
+				ChannelEdgePolicy: edge,
+				Capacity:          capacity,
+			}
+			tempWeight := distance[pivot].weight +
+				edgeWeight(amt, hop, v == targetVertex)
+			tempDist := tempWeight + attemptCost/tempProbability
+
+			// We'll also skip the edge if extending the path over
+			// it would exceed the fee or time lock limits of the
+			// route. The fee is estimated from the amount to send,
+			// as the fees of the following hops are yet unknown,
+			// so it can only underestimate the final fee of the
+			// route, which is checked once the route is built.
+			tempFee := distance[pivot].fee
+			if v != targetVertex {
+				tempFee += computeFee(amt, hop)
+			}
+			tempTimeLock := distance[pivot].timeLock +
+				uint32(edge.TimeLockDelta)
+			if feeLimit != nil && tempFee > *feeLimit {
+				feeLimitHit = true
+				return
+			}
+			if cltvLimit != 0 && tempTimeLock > cltvLimit {
+				return
+			}
 
 			// If this new tentative distance is better than the
 			// current best known distance to this node, then we
@@ -424,50 +902,81 @@ func findPath(graph *channeldb.ChannelGraph, sourceNode *channeldb.LightningNode
 			// our "next hop" map with this edge. We'll also shave
 			// off irrelevant edges by adding the sufficient
 			// capacity of an edge to our relaxation condition.
-			if tempDist < distance[v].dist &&
-				edgeInfo.Capacity >= amt.ToSatoshis() {
+			if tempDist >= distance[v].dist ||
+				capacity < amt.ToSatoshis() {
 
-				// TODO(roasbeef): need to also account
-				// for min HTLC
+				return
+			}
 
-				distance[v] = nodeWithDist{
-					dist: tempDist,
-					node: outEdge.Node,
-				}
-				prev[v] = edgeWithPrev{
-					// We'll use the *incoming* edge here
-					// as we need to use the routing policy
-					// specified by the node this channel
-					// connects to.
-					edge: &ChannelHop{
-						ChannelEdgePolicy: inEdge,
-						Capacity:          edgeInfo.Capacity,
-					},
-					prevNode: bestNode.PubKey,
+			// TODO(roasbeef): need to also account for min HTLC
+
+			distance[v] = nodeWithDist{
+				dist:        tempDist,
+				weight:      tempWeight,
+				probability: tempProbability,
+				fee:         tempFee,
+				timeLock:    tempTimeLock,
+				node:        toNode,
+			}
+			prev[v] = edgeWithPrev{
+				edge:     hop,
+				prevNode: bestNode.PubKey,
+			}
+
+			// In order for the path unwinding to work properly,
+			// we'll ensure that this edge properly points to the
+			// outgoing node.
+			//
+			// TODO(roasbeef): revisit, possibly switch db format?
+			prev[v].edge.Node = toNode
+
+			// Add this new node to our heap as we'd like to
+			// further explore down this edge.
+			heap.Push(&nodeHeap, distance[v])
+		}
+
+		// Now that we've found the next potential step to take we'll
+		// examine all the outgoing edge (channels) from this node to
+		// further our graph traversal, unless the node is only known
+		// to us through route hints.
+		if _, ok := hintNodes[pivot]; !ok {
+			err := graph.forEachChannel(bestNode, func(
+				edgeInfo *channeldb.ChannelEdgeInfo,
+				outEdge, inEdge *channeldb.ChannelEdgePolicy) error {
+
+				// We'll use the *incoming* edge here as we
+				// need to use the routing policy specified by
+				// the node this channel connects to.
+				if inEdge == nil {
+					return nil
 				}
 
-				// In order for the path unwinding to work
-				// properly, we'll ensure that this edge
-				// properly points to the outgoing node.
-				//
-				// TODO(roasbeef): revisit, possibly switch db
-				// format?
-				prev[v].edge.Node = outEdge.Node
-
-				// Add this new node to our heap as we'd like
-				// to further explore down this edge.
-				heap.Push(&nodeHeap, distance[v])
+				processEdge(outEdge.Node, inEdge,
+					edgeInfo.Capacity)
+				return nil
+			})
+			if err != nil {
+				return nil, err
 			}
-			return nil
-		})
-		if err != nil {
-			return nil, err
+		}
+
+		// Finally, we'll also examine the private channels leading
+		// from this node that were supplied through route hints. The
+		// node they lead to is looked up within our distance map, so
+		// that nodes which are part of the graph keep being traversed
+		// as such.
+		for _, edge := range additionalEdges[pivot] {
+			toNode := distance[newVertex(edge.Node.PubKey)].node
+			processEdge(toNode, edge, hopHintCapacity)
 		}
 	}
 
 	// If the target node isn't found in the prev hop map, then a path
 	// doesn't exist, so we terminate in an error.
-	if _, ok := prev[newVertex(target)]; !ok {
+	if _, ok := prev[targetVertex]; !ok && feeLimitHit {
+		return nil, newErrf(ErrFeeLimitExceeded, "unable to find a path "+
+			"to destination within the fee limit of %v", *feeLimit)
+	} else if !ok {
 		return nil, newErrf(ErrNoPathFound, "unable to find a path to "+
 			"destination")
 	}
@@ -477,7 +986,7 @@ func findPath(graph *channeldb.ChannelGraph, sourceNode *channeldb.LightningNode
 	// in the reverse direction which we'll use to properly calculate the
 	// timelock and fee values.
 	pathEdges := make([]*ChannelHop, 0, len(prev))
-	prevNode := newVertex(target)
+	prevNode := targetVertex
 	for prevNode != sourceVertex { // TODO(roasbeef): assumes no cycles
 		// Add the current hop to the limit of path edges then walk
 		// backwards from this hop via the prev pointer for this hop
@@ -518,12 +1027,43 @@ func findPath(graph *channeldb.ChannelGraph, sourceNode *channeldb.LightningNode
 // will be ignored by our modified Dijkstra's algorithm. With this approach, we
 // make our inner path finding algorithm aware of our k-shortest paths
 // algorithm, rather than attempting to use an unmodified path finding
-// algorithm in a block box manner.
-func findPaths(graph *channeldb.ChannelGraph, source *channeldb.LightningNode,
-	target *btcec.PublicKey, amt lnwire.MilliSatoshi) ([][]*ChannelHop, error) {
+// algorithm in a block box manner. Any vertexes within the passed blacklist
+// will never be used as a hop within the returned paths. Similarly, any edges
+// within the passed set of zombie channels will never be traversed. The
+// additional edges, bandwidth hints, route restrictions, optional probability
+// function and path finding config are passed through to each path finding
+// attempt.
+func findPaths(graph *graphSession, source *channeldb.LightningNode,
+	target *btcec.PublicKey, blacklist map[vertex]struct{},
+	zombies map[uint64]struct{},
+	additionalEdges map[vertex][]*channeldb.ChannelEdgePolicy,
+	bandwidthHints map[uint64]lnwire.MilliAtom,
+	restrictions *RouteRestrictions, amt lnwire.MilliAtom,
+	probabilitySource edgeProbabilityFunc,
+	cfg *PathFindingConfig) ([][]*ChannelHop, error) {
+
+	// newIgnoredVertexes returns a fresh set of ignored vertexes which is
+	// seeded with the contents of the blacklist.
+	newIgnoredVertexes := func() map[vertex]struct{} {
+		ignored := make(map[vertex]struct{}, len(blacklist))
+		for v := range blacklist {
+			ignored[v] = struct{}{}
+		}
+		return ignored
+	}
 
-	ignoredEdges := make(map[uint64]struct{})
-	ignoredVertexes := make(map[vertex]struct{})
+	// newIgnoredEdges similarly returns a fresh set of ignored edges which
+	// is seeded with the set of zombie channels.
+	newIgnoredEdges := func() map[uint64]struct{} {
+		ignored := make(map[uint64]struct{}, len(zombies))
+		for chanID := range zombies {
+			ignored[chanID] = struct{}{}
+		}
+		return ignored
+	}
+
+	ignoredEdges := newIgnoredEdges()
+	ignoredVertexes := newIgnoredVertexes()
 
 	// TODO(roasbeef): modifying ordering within heap to eliminate final
 	// sorting step?
@@ -536,7 +1076,8 @@ func findPaths(graph *channeldb.ChannelGraph, source *channeldb.LightningNode,
 	// selfNode) to the target destination that's capable of carrying amt
 	// satoshis along the path before fees are calculated.
 	startingPath, err := findPath(graph, source, target,
-		ignoredVertexes, ignoredEdges, amt)
+		ignoredVertexes, ignoredEdges, additionalEdges, bandwidthHints,
+		restrictions, amt, probabilitySource, cfg)
 	if err != nil {
 		log.Errorf("Unable to find path: %v", err)
 		return nil, err
@@ -570,8 +1111,8 @@ func findPaths(graph *channeldb.ChannelGraph, source *channeldb.LightningNode,
 			// we'll exclude from the next path finding attempt.
 			// These are required to ensure the paths are unique
 			// and loopless.
-			ignoredEdges = make(map[uint64]struct{})
-			ignoredVertexes = make(map[vertex]struct{})
+			ignoredEdges = newIgnoredEdges()
+			ignoredVertexes = newIgnoredVertexes()
 
 			// Our spur node is the i-th node in the prior shortest
 			// path, and our root path will be all nodes in the
@@ -607,12 +1148,26 @@ func findPaths(graph *channeldb.ChannelGraph, source *channeldb.LightningNode,
 			// the vertexes (other than the spur path) within the
 			// root path removed, we'll attempt to find another
 			// shortest path from the spur node to the destination.
+			// The first hop of the root path already abides by
+			// the outgoing channel restriction, so it only applies
+			// to spur paths starting from the source itself. The
+			// fee and time lock limits are applied to the spur
+			// path alone, so they're only enforced loosely here,
+			// and exactly once the full route is built.
+			spurRestrictions := restrictions
+			if i != 0 && restrictions != nil {
+				r := *restrictions
+				r.OutgoingChannelIDs = nil
+				spurRestrictions = &r
+			}
 			spurPath, err := findPath(graph, spurNode, target,
-				ignoredVertexes, ignoredEdges, amt)
+				ignoredVertexes, ignoredEdges, additionalEdges,
+				bandwidthHints, spurRestrictions, amt,
+				probabilitySource, cfg)
 
 			// If we weren't able to find a path, we'll continue to
 			// the next round.
-			if IsError(err, ErrNoPathFound) {
+			if IsError(err, ErrNoPathFound, ErrFeeLimitExceeded) {
 				continue
 			} else if err != nil {
 				return nil, err
@@ -10,11 +10,13 @@ import (
 	"math/big"
 	"net"
 	"os"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/htlcswitch"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/roasbeef/btcd/btcec"
 	"github.com/roasbeef/btcd/chaincfg/chainhash"
@@ -266,9 +268,9 @@ func parseTestGraph(path string) (*channeldb.ChannelGraph, func(), aliasMap, err
 			ChannelID:                 edge.ChannelID,
 			LastUpdate:                time.Now(),
 			TimeLockDelta:             edge.Expiry,
-			MinHTLC:                   lnwire.MilliSatoshi(edge.MinHTLC),
-			FeeBaseMSat:               lnwire.MilliSatoshi(edge.FeeBaseMsat),
-			FeeProportionalMillionths: lnwire.MilliSatoshi(edge.FeeRate),
+			MinHTLC:                   lnwire.MilliAtom(edge.MinHTLC),
+			FeeBaseMSat:               lnwire.MilliAtom(edge.FeeBaseMsat),
+			FeeProportionalMillionths: lnwire.MilliAtom(edge.FeeRate),
 		}
 
 		// As the graph itself is directed, we need to insert two edges
@@ -276,12 +278,12 @@ func parseTestGraph(path string) (*channeldb.ChannelGraph, func(), aliasMap, err
 		// node2->node1. A flag of 0 indicates this is the routing
 		// policy for the first node, and a flag of 1 indicates its the
 		// information for the second node.
-		edgePolicy.Flags = 0
+		edgePolicy.ChannelFlags = 0
 		if err := graph.UpdateEdgePolicy(edgePolicy); err != nil {
 			return nil, nil, nil, err
 		}
 
-		edgePolicy.Flags = 1
+		edgePolicy.ChannelFlags = 1
 		if err := graph.UpdateEdgePolicy(edgePolicy); err != nil {
 			return nil, nil, nil, err
 		}
@@ -299,6 +301,9 @@ func TestBasicGraphPathFinding(t *testing.T) {
 		t.Fatalf("unable to create graph: %v", err)
 	}
 
+	session := newGraphSession(graph)
+	defer session.release()
+
 	sourceNode, err := graph.SourceNode()
 	if err != nil {
 		t.Fatalf("unable to fetch source node: %v", err)
@@ -315,8 +320,8 @@ func TestBasicGraphPathFinding(t *testing.T) {
 
 	paymentAmt := lnwire.NewMSatFromSatoshis(100)
 	target := aliases["sophon"]
-	path, err := findPath(graph, sourceNode, target, ignoredVertexes,
-		ignoredEdges, paymentAmt)
+	path, err := findPath(session, sourceNode, target, ignoredVertexes,
+		ignoredEdges, nil, nil, nil, paymentAmt, nil, nil)
 	if err != nil {
 		t.Fatalf("unable to find path: %v", err)
 	}
@@ -411,8 +416,8 @@ func TestBasicGraphPathFinding(t *testing.T) {
 	// exist two possible paths in the graph, but the shorter (1 hop) path
 	// should be selected.
 	target = aliases["luoji"]
-	path, err = findPath(graph, sourceNode, target, ignoredVertexes,
-		ignoredEdges, paymentAmt)
+	path, err = findPath(session, sourceNode, target, ignoredVertexes,
+		ignoredEdges, nil, nil, nil, paymentAmt, nil, nil)
 	if err != nil {
 		t.Fatalf("unable to find route: %v", err)
 	}
@@ -453,6 +458,9 @@ func TestKShortestPathFinding(t *testing.T) {
 		t.Fatalf("unable to create graph: %v", err)
 	}
 
+	session := newGraphSession(graph)
+	defer session.release()
+
 	sourceNode, err := graph.SourceNode()
 	if err != nil {
 		t.Fatalf("unable to fetch source node: %v", err)
@@ -468,7 +476,8 @@ func TestKShortestPathFinding(t *testing.T) {
 
 	paymentAmt := lnwire.NewMSatFromSatoshis(100)
 	target := aliases["luoji"]
-	paths, err := findPaths(graph, sourceNode, target, paymentAmt)
+	paths, err := findPaths(session, sourceNode, target, nil, nil, nil, nil, nil,
+		paymentAmt, nil, nil)
 	if err != nil {
 		t.Fatalf("unable to find paths between roasbeef and "+
 			"luo ji: %v", err)
@@ -516,6 +525,9 @@ func TestNewRoutePathTooLong(t *testing.T) {
 		t.Fatalf("unable to create graph: %v", err)
 	}
 
+	session := newGraphSession(graph)
+	defer session.release()
+
 	sourceNode, err := graph.SourceNode()
 	if err != nil {
 		t.Fatalf("unable to fetch source node: %v", err)
@@ -529,8 +541,8 @@ func TestNewRoutePathTooLong(t *testing.T) {
 	// We start by confirminig that routing a payment 20 hops away is possible.
 	// Alice should be able to find a valid route to ursula.
 	target := aliases["ursula"]
-	_, err = findPath(graph, sourceNode, target, ignoredVertexes,
-		ignoredEdges, paymentAmt)
+	_, err = findPath(session, sourceNode, target, ignoredVertexes,
+		ignoredEdges, nil, nil, nil, paymentAmt, nil, nil)
 	if err != nil {
 		t.Fatalf("path should have been found")
 	}
@@ -538,8 +550,8 @@ func TestNewRoutePathTooLong(t *testing.T) {
 	// Vincent is 21 hops away from Alice, and thus no valid route should be
 	// presented to Alice.
 	target = aliases["vincent"]
-	path, err := findPath(graph, sourceNode, target, ignoredVertexes,
-		ignoredEdges, paymentAmt)
+	path, err := findPath(session, sourceNode, target, ignoredVertexes,
+		ignoredEdges, nil, nil, nil, paymentAmt, nil, nil)
 	if err == nil {
 		t.Fatalf("should not have been able to find path, supposed to be "+
 			"greater than 20 hops, found route with %v hops",
@@ -557,6 +569,9 @@ func TestPathNotAvailable(t *testing.T) {
 		t.Fatalf("unable to create graph: %v", err)
 	}
 
+	session := newGraphSession(graph)
+	defer session.release()
+
 	sourceNode, err := graph.SourceNode()
 	if err != nil {
 		t.Fatalf("unable to fetch source node: %v", err)
@@ -578,8 +593,8 @@ func TestPathNotAvailable(t *testing.T) {
 		t.Fatalf("unable to parse pubkey: %v", err)
 	}
 
-	_, err = findPath(graph, sourceNode, unknownNode, ignoredVertexes,
-		ignoredEdges, 100)
+	_, err = findPath(session, sourceNode, unknownNode, ignoredVertexes,
+		ignoredEdges, nil, nil, nil, 100, nil, nil)
 	if !IsError(err, ErrNoPathFound) {
 		t.Fatalf("path shouldn't have been found: %v", err)
 	}
@@ -594,6 +609,9 @@ func TestPathInsufficientCapacity(t *testing.T) {
 		t.Fatalf("unable to create graph: %v", err)
 	}
 
+	session := newGraphSession(graph)
+	defer session.release()
+
 	sourceNode, err := graph.SourceNode()
 	if err != nil {
 		t.Fatalf("unable to fetch source node: %v", err)
@@ -612,11 +630,388 @@ func TestPathInsufficientCapacity(t *testing.T) {
 	target := aliases["sophon"]
 
 	const payAmt = btcutil.SatoshiPerBitcoin
-	_, err = findPath(graph, sourceNode, target, ignoredVertexes,
-		ignoredEdges, payAmt)
+	_, err = findPath(session, sourceNode, target, ignoredVertexes,
+		ignoredEdges, nil, nil, nil, payAmt, nil, nil)
+	if !IsError(err, ErrNoPathFound) {
+		t.Fatalf("graph shouldn't be able to support payment: %v", err)
+	}
+}
+
+// TestPathMaxHtlc tests that a channel isn't used to carry a payment exceeding
+// the maximum HTLC advertised for it.
+func TestPathMaxHtlc(t *testing.T) {
+	t.Parallel()
+
+	graph, cleanUp, aliases, err := parseTestGraph(basicGraphFilePath)
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to create graph: %v", err)
+	}
+
+	session := newGraphSession(graph)
+	defer session.release()
+
+	sourceNode, err := graph.SourceNode()
+	if err != nil {
+		t.Fatalf("unable to fetch source node: %v", err)
+	}
+	ignoredEdges := make(map[uint64]struct{})
+	ignoredVertexes := make(map[vertex]struct{})
+
+	// First, we'll find a path to sophon, which can only be reached
+	// through son goku.
+	const payAmt = lnwire.MilliAtom(100000)
+	target := aliases["sophon"]
+	path, err := findPath(session, sourceNode, target, ignoredVertexes,
+		ignoredEdges, nil, nil, nil, payAmt, nil, nil)
+	if err != nil {
+		t.Fatalf("unable to find path: %v", err)
+	}
+
+	// Next, son goku will advertise a maximum HTLC for the final channel
+	// of the path that is lower than the amount we'd like to send.
+	lastHop := path[len(path)-1]
+	lastHop.MessageFlags = lnwire.ChanUpdateOptionMaxHtlc
+	lastHop.MaxHTLC = payAmt - 1
+	lastHop.LastUpdate = lastHop.LastUpdate.Add(time.Second)
+	if err := session.release(); err != nil {
+		t.Fatalf("unable to release graph session: %v", err)
+	}
+	if err := graph.UpdateEdgePolicy(lastHop.ChannelEdgePolicy); err != nil {
+		t.Fatalf("unable to update edge policy: %v", err)
+	}
+
+	// As the final channel can no longer carry the payment, no path
+	// should be found.
+	_, err = findPath(session, sourceNode, target, ignoredVertexes,
+		ignoredEdges, nil, nil, nil, payAmt, nil, nil)
 	if !IsError(err, ErrNoPathFound) {
 		t.Fatalf("graph shouldn't be able to support payment: %v", err)
 	}
+
+	// A payment within the limit should still be routed over it.
+	_, err = findPath(session, sourceNode, target, ignoredVertexes,
+		ignoredEdges, nil, nil, nil, payAmt-1, nil, nil)
+	if err != nil {
+		t.Fatalf("unable to find path: %v", err)
+	}
+}
+
+// TestPathDisabledAndBandwidth tests that disabled channels aren't used during
+// path finding, nor are our own channels lacking the bandwidth to carry the
+// payment.
+func TestPathDisabledAndBandwidth(t *testing.T) {
+	t.Parallel()
+
+	graph, cleanUp, aliases, err := parseTestGraph(basicGraphFilePath)
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to create graph: %v", err)
+	}
+
+	session := newGraphSession(graph)
+	defer session.release()
+
+	sourceNode, err := graph.SourceNode()
+	if err != nil {
+		t.Fatalf("unable to fetch source node: %v", err)
+	}
+	ignoredEdges := make(map[uint64]struct{})
+	ignoredVertexes := make(map[vertex]struct{})
+
+	// First, we'll find a path to sophon, which can only be reached
+	// through son goku.
+	const payAmt = lnwire.MilliAtom(100000)
+	target := aliases["sophon"]
+	path, err := findPath(session, sourceNode, target, ignoredVertexes,
+		ignoredEdges, nil, nil, nil, payAmt, nil, nil)
+	if err != nil {
+		t.Fatalf("unable to find path: %v", err)
+	}
+
+	// If the switch reports that our channel to son goku lacks the
+	// bandwidth to carry the payment, then no path should be found.
+	firstHop := path[0].ChannelID
+	bandwidthHints := map[uint64]lnwire.MilliAtom{
+		firstHop: payAmt - 1,
+	}
+	_, err = findPath(session, sourceNode, target, ignoredVertexes,
+		ignoredEdges, nil, bandwidthHints, nil, payAmt, nil, nil)
+	if !IsError(err, ErrNoPathFound) {
+		t.Fatalf("path shouldn't have been found: %v", err)
+	}
+
+	// Once the channel has enough bandwidth, it should be used again.
+	bandwidthHints[firstHop] = payAmt
+	_, err = findPath(session, sourceNode, target, ignoredVertexes,
+		ignoredEdges, nil, bandwidthHints, nil, payAmt, nil, nil)
+	if err != nil {
+		t.Fatalf("unable to find path: %v", err)
+	}
+
+	// Next, son goku will disable the final channel of the path.
+	lastHop := path[len(path)-1]
+	lastHop.ChannelFlags |= lnwire.ChanUpdateDisabled
+	lastHop.LastUpdate = lastHop.LastUpdate.Add(time.Second)
+	if err := session.release(); err != nil {
+		t.Fatalf("unable to release graph session: %v", err)
+	}
+	if err := graph.UpdateEdgePolicy(lastHop.ChannelEdgePolicy); err != nil {
+		t.Fatalf("unable to update edge policy: %v", err)
+	}
+
+	// As the final channel is now disabled, no path should be found.
+	_, err = findPath(session, sourceNode, target, ignoredVertexes,
+		ignoredEdges, nil, bandwidthHints, nil, payAmt, nil, nil)
+	if !IsError(err, ErrNoPathFound) {
+		t.Fatalf("path shouldn't have been found: %v", err)
+	}
+}
+
+// TestPathIgnoredEdgeDirection asserts that an ignored directed edge isn't
+// traversed, while its channel may still be traversed in the other direction.
+func TestPathIgnoredEdgeDirection(t *testing.T) {
+	t.Parallel()
+
+	graph, cleanUp, aliases, err := parseTestGraph(basicGraphFilePath)
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to create graph: %v", err)
+	}
+
+	session := newGraphSession(graph)
+	defer session.release()
+
+	sourceNode, err := graph.SourceNode()
+	if err != nil {
+		t.Fatalf("unable to fetch source node: %v", err)
+	}
+	ignoredEdges := make(map[uint64]struct{})
+	ignoredVertexes := make(map[vertex]struct{})
+
+	const (
+		payAmt     = lnwire.MilliAtom(100000)
+		directChan = 689530843
+	)
+	source := newVertex(sourceNode.PubKey)
+	target := aliases["luoji"]
+
+	// Ignoring the direction of our channel with luo ji that leads back
+	// to us shouldn't prevent us from using it.
+	restrictions := &RouteRestrictions{
+		IgnoredEdges: map[EdgeLocator]struct{}{
+			newEdgeLocator(directChan, newVertex(target), source): {},
+		},
+	}
+	path, err := findPath(session, sourceNode, target, ignoredVertexes,
+		ignoredEdges, nil, nil, restrictions, payAmt, nil, nil)
+	if err != nil {
+		t.Fatalf("unable to find path: %v", err)
+	}
+	if len(path) != 1 || path[0].ChannelID != directChan {
+		t.Fatalf("expected direct path to luo ji, got path of "+
+			"%v hops", len(path))
+	}
+
+	// Once the direction leading to luo ji is ignored, we should instead
+	// reach it through satoshi.
+	restrictions.IgnoredEdges = map[EdgeLocator]struct{}{
+		newEdgeLocator(directChan, source, newVertex(target)): {},
+	}
+	path, err = findPath(session, sourceNode, target, ignoredVertexes,
+		ignoredEdges, nil, nil, restrictions, payAmt, nil, nil)
+	if err != nil {
+		t.Fatalf("unable to find path: %v", err)
+	}
+	if len(path) != 2 || !path[0].Node.PubKey.IsEqual(aliases["satoshi"]) {
+		t.Fatalf("expected path to luo ji through satoshi, got "+
+			"path of %v hops", len(path))
+	}
+}
+
+// TestPathRouteHints asserts that private channels supplied through route
+// hints are used to reach a destination that isn't part of the graph, and
+// that their policies are respected.
+func TestPathRouteHints(t *testing.T) {
+	t.Parallel()
+
+	graph, cleanUp, aliases, err := parseTestGraph(basicGraphFilePath)
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to create graph: %v", err)
+	}
+
+	session := newGraphSession(graph)
+	defer session.release()
+
+	sourceNode, err := graph.SourceNode()
+	if err != nil {
+		t.Fatalf("unable to fetch source node: %v", err)
+	}
+	ignoredEdges := make(map[uint64]struct{})
+	ignoredVertexes := make(map[vertex]struct{})
+
+	// The destination is only reachable through a chain of two private
+	// channels: the first from luoji to an intermediate private node, and
+	// the second from that node to the destination itself.
+	privKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+	privNode := privKey.PubKey()
+	targetKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+	target := targetKey.PubKey()
+
+	const payAmt = lnwire.MilliAtom(100000)
+
+	// Without any hints, the destination can't be reached.
+	_, err = findPath(session, sourceNode, target, ignoredVertexes,
+		ignoredEdges, nil, nil, nil, payAmt, nil, nil)
+	if !IsError(err, ErrNoPathFound) {
+		t.Fatalf("path shouldn't have been found: %v", err)
+	}
+
+	routeHints := [][]HopHint{{
+		{
+			NodeID:          aliases["luoji"],
+			ChannelID:       1000,
+			FeeBaseMSat:     1000,
+			CLTVExpiryDelta: 10,
+		},
+		{
+			NodeID:          privNode,
+			ChannelID:       1001,
+			FeeBaseMSat:     2000,
+			CLTVExpiryDelta: 20,
+		},
+	}}
+	additionalEdges := hintEdges(target, routeHints)
+
+	path, err := findPath(session, sourceNode, target, ignoredVertexes,
+		ignoredEdges, additionalEdges, nil, nil, payAmt, nil, nil)
+	if err != nil {
+		t.Fatalf("unable to find path: %v", err)
+	}
+	route, err := newRoute(payAmt, path, 100)
+	if err != nil {
+		t.Fatalf("unable to create route: %v", err)
+	}
+
+	// The route should end with the two hinted channels.
+	numHops := len(route.Hops)
+	if numHops < 3 {
+		t.Fatalf("expected at least 3 hops, got %v", numHops)
+	}
+	hintHop := route.Hops[numHops-2]
+	finalHop := route.Hops[numHops-1]
+	if hintHop.Channel.ChannelID != 1000 ||
+		!hintHop.Channel.Node.PubKey.IsEqual(privNode) {
+
+		t.Fatalf("expected hop over channel 1000 to the private node, "+
+			"got channel %v", hintHop.Channel.ChannelID)
+	}
+	if finalHop.Channel.ChannelID != 1001 ||
+		!finalHop.Channel.Node.PubKey.IsEqual(target) {
+
+		t.Fatalf("expected hop over channel 1001 to the target, got "+
+			"channel %v", finalHop.Channel.ChannelID)
+	}
+
+	// The fee and time lock of the hinted channels should have been
+	// applied.
+	if hintHop.Fee != 1000 {
+		t.Fatalf("expected fee of 1000, got %v", hintHop.Fee)
+	}
+	if finalHop.OutgoingTimeLock != 20 {
+		t.Fatalf("expected final time lock delta of 20, got %v",
+			finalHop.OutgoingTimeLock)
+	}
+
+	// Finally, ignoring the first hinted channel should leave the
+	// destination unreachable once more.
+	ignoredEdges[1000] = struct{}{}
+	_, err = findPath(session, sourceNode, target, ignoredVertexes,
+		ignoredEdges, additionalEdges, nil, nil, payAmt, nil, nil)
+	if !IsError(err, ErrNoPathFound) {
+		t.Fatalf("path shouldn't have been found: %v", err)
+	}
+}
+
+// TestPackHopPayload checks that the TLV hop payloads of a route's hops can be
+// decoded by the hops they're destined to.
+func TestPackHopPayload(t *testing.T) {
+	t.Parallel()
+
+	var secret [32]byte
+	secret[31] = 0x01
+
+	intermediateHop := &Hop{
+		OutgoingTimeLock: 144,
+		AmtToForward:     100000,
+		Fee:              1000,
+	}
+	finalHop := &Hop{
+		OutgoingTimeLock: 100,
+		AmtToForward:     100000,
+		MPP:              lnwire.NewMPP(250000, secret),
+	}
+
+	const nextChanID = 12345
+	tests := []struct {
+		hop        *Hop
+		nextChanID uint64
+		expNextHop lnwire.ShortChannelID
+	}{
+		{
+			hop:        intermediateHop,
+			nextChanID: nextChanID,
+			expNextHop: lnwire.NewShortChanIDFromInt(nextChanID),
+		},
+		{
+			hop: finalHop,
+		},
+	}
+
+	for i, test := range tests {
+		var b bytes.Buffer
+		if err := test.hop.PackHopPayload(&b, test.nextChanID); err != nil {
+			t.Fatalf("hop %d: unable to pack payload: %v", i, err)
+		}
+
+		payload, err := htlcswitch.NewPayloadFromReader(&b)
+		if err != nil {
+			t.Fatalf("hop %d: unable to decode payload: %v", i, err)
+		}
+
+		fwdInfo := payload.ForwardingInfo()
+		if fwdInfo.NextHop != test.expNextHop {
+			t.Fatalf("hop %d: expected next hop %v, got %v", i,
+				test.expNextHop, fwdInfo.NextHop)
+		}
+		if fwdInfo.AmountToForward != test.hop.AmtToForward {
+			t.Fatalf("hop %d: expected amount %v, got %v", i,
+				test.hop.AmtToForward, fwdInfo.AmountToForward)
+		}
+		if fwdInfo.OutgoingCTLV != test.hop.OutgoingTimeLock {
+			t.Fatalf("hop %d: expected cltv %v, got %v", i,
+				test.hop.OutgoingTimeLock, fwdInfo.OutgoingCTLV)
+		}
+		if !reflect.DeepEqual(payload.MultiPath(), test.hop.MPP) {
+			t.Fatalf("hop %d: expected payment data %v, got %v", i,
+				test.hop.MPP, payload.MultiPath())
+		}
+	}
+
+	// Payment data may only be sent to the final hop.
+	intermediateHop.MPP = finalHop.MPP
+	err := intermediateHop.PackHopPayload(&bytes.Buffer{}, nextChanID)
+	if err == nil {
+		t.Fatalf("expected payment data for intermediate hop to be " +
+			"rejected")
+	}
 }
 
 func TestPathInsufficientCapacityWithFee(t *testing.T) {
@@ -0,0 +1,493 @@
+package routing
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/htlcswitch"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// shardResult is the outcome of a single shard of a payment, as sent back to
+// the payment lifecycle by the goroutine that dispatched it.
+type shardResult struct {
+	// shard is the shard the result pertains to.
+	shard *paymentShard
+
+	// preimage is the preimage revealed by the receiver if the shard
+	// succeeded.
+	preimage [32]byte
+
+	// err is the error returned by the switch if the shard failed.
+	err error
+}
+
+// paymentShard is a single HTLC sent in order to complete a payment. Unless
+// the payment is split into multiple parts, a payment consists of a single
+// shard carrying its full amount.
+type paymentShard struct {
+	// route is the route the shard was sent over.
+	route *Route
+
+	// amt is the amount the shard delivers to the receiver, excluding
+	// fees.
+	amt lnwire.MilliAtom
+
+	// attemptID is the ID of the HTLC attempt recording the shard within
+	// the payment store.
+	attemptID uint64
+}
+
+// paymentLifecycle drives a payment from initiation to completion. It
+// dispatches shards of the payment concurrently, splitting the payment into
+// up to MaxParts shards when no route is able to carry the remaining amount as
+// a whole, and re-routes the amount of shards that fail. The payment only
+// succeeds once the receiver has accepted its full amount and revealed the
+// preimage.
+type paymentLifecycle struct {
+	router  *ChannelRouter
+	payment *LightningPayment
+
+	// graph is the session over the channel graph that is shared by the
+	// route searches of the payment, so that the searches made for a
+	// shard operate on a single snapshot. It's released before writing
+	// to the database or waiting for the outcome of a shard, as a write
+	// would otherwise be unable to grow the database while the snapshot
+	// is held.
+	graph *graphSession
+
+	// maxParts is the maximum number of shards that may be in flight at
+	// once.
+	maxParts uint32
+
+	// remaining is the amount of the payment that isn't yet carried by a
+	// shard that's in flight or settled.
+	remaining lnwire.MilliAtom
+
+	// feeLimit is the maximum total fee of the payment, while feesPaid is
+	// the total fee of the shards that are in flight or settled, which is
+	// deducted from it.
+	feeLimit lnwire.MilliAtom
+	feesPaid lnwire.MilliAtom
+
+	// inFlight is the number of shards that are currently in flight.
+	inFlight uint32
+
+	// failedRoutes records the routes, keyed by their channels and
+	// amount, over which a shard has already failed. A route is never
+	// attempted again for the same amount within a payment.
+	failedRoutes map[string]struct{}
+
+	// results is the channel over which the goroutines dispatching shards
+	// send back their outcome.
+	results chan *shardResult
+
+	// preimage and successRoute are set once the first shard succeeds.
+	preimage     [32]byte
+	successRoute *Route
+
+	// failureReason and lastErr describe the latest failure, which is
+	// reported if the payment ultimately fails.
+	failureReason channeldb.FailureReason
+	lastErr       error
+
+	// terminal is set once a failure indicates that attempting the
+	// payment any further would fail in the same way.
+	terminal bool
+
+	// exiting is set once the switch shuts down while a shard is in
+	// flight. The payment is then left in flight within the payment
+	// store, so its outcome can be tracked once the router is restarted.
+	exiting bool
+}
+
+// newPaymentLifecycle creates the lifecycle of the passed payment, which must
+// already have been initiated within the payment store.
+func newPaymentLifecycle(r *ChannelRouter,
+	payment *LightningPayment) *paymentLifecycle {
+
+	maxParts := payment.MaxParts
+	if maxParts == 0 {
+		maxParts = 1
+	}
+
+	return &paymentLifecycle{
+		router:        r,
+		payment:       payment,
+		graph:         newGraphSession(r.cfg.Graph),
+		maxParts:      maxParts,
+		remaining:     payment.Amount,
+		feeLimit:      payment.feeLimit(),
+		failedRoutes:  make(map[string]struct{}),
+		results:       make(chan *shardResult, maxParts),
+		failureReason: channeldb.FailureReasonNoRoute,
+	}
+}
+
+// succeeded returns true once a shard of the payment has been settled.
+func (p *paymentLifecycle) succeeded() bool {
+	return p.successRoute != nil
+}
+
+// run drives the payment to completion, returning the preimage and the route
+// of the first successful shard. If the payment fails, it's marked as failed
+// within the payment store. In either case, run only returns once no shard of
+// the payment remains in flight.
+func (p *paymentLifecycle) run() ([32]byte, *Route, error) {
+	for {
+		// As long as part of the payment isn't carried by any shard,
+		// and the payment has neither succeeded nor terminally failed,
+		// we'll dispatch new shards.
+		for p.remaining > 0 && !p.succeeded() && !p.terminal &&
+			p.inFlight < p.maxParts {
+
+			shard, err := p.launchShard()
+			if err != nil {
+				return p.fail(channeldb.FailureReasonError, err)
+			}
+
+			// If no route is currently able to carry any part of
+			// the remaining amount, then we'll wait for the shards
+			// in flight, as their failure may free up the balance
+			// of our channels.
+			if shard == nil {
+				break
+			}
+		}
+
+		// We're done searching for routes for now, so we'll release
+		// our snapshot of the graph before waiting. A fresh one is
+		// taken once we search for routes again.
+		p.releaseGraph()
+
+		// Once nothing is in flight, the payment has either succeeded,
+		// or we've run out of routes to attempt.
+		if p.inFlight == 0 {
+			if p.succeeded() {
+				return p.preimage, p.successRoute, nil
+			}
+
+			return p.fail(p.failureReason, p.lastErr)
+		}
+
+		// Otherwise, we'll wait for the outcome of the next shard. The
+		// switch resolves every HTLC handed to it, even on shutdown,
+		// so this doesn't block indefinitely.
+		if err := p.handleResult(<-p.results); err != nil {
+			return p.fail(channeldb.FailureReasonError, err)
+		}
+	}
+}
+
+// fail waits for every shard in flight to be resolved, and then marks the
+// payment as failed within the payment store, returning the passed error to
+// the caller along with the reason the payment failed. If a shard in flight
+// succeeds in the meantime, then the payment is reported as successful
+// instead. If the switch shut down while a shard was in flight, then the
+// payment is left in flight, to be resumed once the router restarts.
+func (p *paymentLifecycle) fail(reason channeldb.FailureReason,
+	err error) ([32]byte, *Route, error) {
+
+	p.terminal = true
+	for p.inFlight > 0 {
+		if dbErr := p.handleResult(<-p.results); dbErr != nil {
+			log.Errorf("Unable to record outcome of payment %x: %v",
+				p.payment.PaymentHash, dbErr)
+		}
+	}
+	if p.succeeded() {
+		return p.preimage, p.successRoute, nil
+	}
+
+	if p.exiting {
+		return [32]byte{}, nil, htlcswitch.ErrSwitchExiting
+	}
+
+	p.router.failPayment(p.payment.PaymentHash, reason)
+
+	return [32]byte{}, nil, &PaymentError{
+		Reason: reason,
+		Err:    err,
+	}
+}
+
+// releaseGraph releases the lifecycle's snapshot of the channel graph.
+func (p *paymentLifecycle) releaseGraph() {
+	if err := p.graph.release(); err != nil {
+		log.Errorf("Unable to release graph session of payment %x: %v",
+			p.payment.PaymentHash, err)
+	}
+}
+
+// launchShard finds a route for the largest part of the remaining amount that
+// can be carried by a single shard, and dispatches the shard over it. The
+// amount is halved for as long as no route is found and the payment may still
+// be split further. If no route can be found at all, then a nil shard is
+// returned, and the reason is recorded as the payment's latest failure.
+func (p *paymentLifecycle) launchShard() (*paymentShard, error) {
+	amt := p.remaining
+	for {
+		if route := p.findShardRoute(amt); route != nil {
+			p.releaseGraph()
+			return p.sendShard(route, amt)
+		}
+
+		// Splitting the amount only helps if the other half can be
+		// dispatched as a separate shard as well.
+		canSplit := amt > 1 && p.inFlight+2 <= p.maxParts
+		if !canSplit {
+			return nil, nil
+		}
+
+		amt /= 2
+	}
+}
+
+// findShardRoute returns the route with the lowest expected cost that is able
+// to carry a shard of the passed amount within the remaining fee budget,
+// and over which no shard has failed yet. If no such route exists, then a nil
+// route is returned, and the reason is recorded as the payment's latest
+// failure.
+func (p *paymentLifecycle) findShardRoute(amt lnwire.MilliAtom) *Route {
+	routes, err := p.router.findPaymentRoutes(p.graph, p.payment, amt)
+	if err != nil {
+		// A failure to find any path doesn't reflect on the routes
+		// attempted so far, so we'll only surface it if nothing was
+		// attempted yet.
+		if p.lastErr == nil {
+			p.failureReason = channeldb.FailureReasonNoRoute
+			if IsError(err, ErrFeeLimitExceeded) {
+				p.failureReason = channeldb.FailureReasonFeeLimit
+			}
+			p.lastErr = err
+		}
+		return nil
+	}
+
+	// We'll only consider the routes that haven't failed yet for this
+	// amount. We'll also skip the routes whose fees exceed what remains
+	// of the payment's fee limit once the fees of its other shards are
+	// accounted for.
+	var (
+		candidates   []*Route
+		overFeeLimit bool
+	)
+	for _, route := range routes {
+		if _, ok := p.failedRoutes[routeKey(route)]; ok {
+			continue
+		}
+
+		if route.TotalFees > p.feeLimit-p.feesPaid {
+			overFeeLimit = true
+			continue
+		}
+
+		candidates = append(candidates, route)
+	}
+
+	if len(candidates) == 0 {
+		if overFeeLimit && p.lastErr == nil {
+			p.failureReason = channeldb.FailureReasonFeeLimit
+			p.lastErr = newErrf(ErrFeeLimitExceeded, "all routes "+
+				"to %x require fees exceeding the fee limit "+
+				"of %v", p.payment.Target.SerializeCompressed(),
+				p.feeLimit)
+		}
+		return nil
+	}
+
+	// As the routes may have been found before some of their hops caused
+	// recent payments to fail, we'll pick the route with the lowest
+	// expected cost according to the current probability estimates of
+	// mission control: its fees, along with the cost of the attempts
+	// needed for it to succeed. The sort is stable, so routes with equal
+	// costs are still ranked by their fees.
+	attemptCost := float64(p.router.cfg.PathFinding.AttemptCost)
+	costs := make(map[*Route]float64, len(candidates))
+	for _, route := range candidates {
+		probability := p.router.missionControl.routeProbability(route)
+		if probability == 0 {
+			costs[route] = infinity
+			continue
+		}
+
+		costs[route] = float64(route.TotalFees) +
+			attemptCost/probability
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return costs[candidates[i]] < costs[candidates[j]]
+	})
+
+	return candidates[0]
+}
+
+// sendShard records a shard of the passed amount over the passed route as an
+// HTLC attempt, and dispatches it to the switch within a new goroutine, whose
+// outcome is sent over the results channel.
+func (p *paymentLifecycle) sendShard(route *Route,
+	amt lnwire.MilliAtom) (*paymentShard, error) {
+
+	payment := p.payment
+
+	// If the payment has a payment address, then we'll include it within
+	// the payload of the final hop, along with the total amount of the
+	// payment, so the receiver is able to tell when every shard has
+	// arrived. The route may be shared with the route cache, so we'll
+	// modify a copy of it.
+	if payment.PaymentAddr != nil {
+		route = route.withMPP(
+			lnwire.NewMPP(payment.Amount, *payment.PaymentAddr),
+		)
+	}
+
+	log.Tracef("Attempting to send %v of payment %x, using route: %v",
+		amt, payment.PaymentHash, newLogClosure(func() string {
+			return spew.Sdump(route)
+		}),
+	)
+
+	// Generate the raw encoded sphinx packet to be included along with
+	// the htlcAdd message that we send directly to the switch.
+	onionBlob, circuit, err := generateSphinxPacket(
+		route, payment.PaymentHash[:],
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Craft an HTLC packet to send to the layer 2 switch. The metadata
+	// within this packet will be used to route the payment through the
+	// network, starting with the first-hop.
+	htlcAdd := &lnwire.UpdateAddHTLC{
+		Amount:      route.TotalAmount,
+		Expiry:      route.TotalTimeLock,
+		PaymentHash: payment.PaymentHash,
+	}
+	copy(htlcAdd.OnionBlob[:], onionBlob)
+
+	// Before sending the HTLC, we'll record the attempt so its outcome can
+	// be tracked even across restarts.
+	attempt := &channeldb.HTLCAttemptInfo{
+		Route:       route.toDBRoute(),
+		AttemptTime: time.Now(),
+		SessionKey:  circuit.SessionKey,
+	}
+	err = p.router.payments.RegisterAttempt(
+		payment.PaymentHash, attempt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	shard := &paymentShard{
+		route:     route,
+		amt:       amt,
+		attemptID: attempt.AttemptID,
+	}
+	p.remaining -= amt
+	p.feesPaid += route.TotalFees
+	p.inFlight++
+
+	// Attempt to send this shard through the network. The switch blocks
+	// until the shard is either settled or failed, so we'll wait for its
+	// outcome within a goroutine, allowing the other shards to be sent
+	// concurrently.
+	firstHop := route.Hops[0].Channel.Node.PubKey
+	go func() {
+		preimage, err := p.router.cfg.SendToSwitch(
+			firstHop, htlcAdd, circuit,
+		)
+		p.results <- &shardResult{
+			shard:    shard,
+			preimage: preimage,
+			err:      err,
+		}
+	}()
+
+	return shard, nil
+}
+
+// handleResult processes the outcome of a shard, recording it within the
+// payment store and mission control. The amount of a failed shard is returned
+// to the remaining amount of the payment, so it can be re-routed.
+func (p *paymentLifecycle) handleResult(result *shardResult) error {
+	shard := result.shard
+	hash := p.payment.PaymentHash
+	p.inFlight--
+
+	// If the switch is shutting down, then the shard's outcome is
+	// unknown, so we'll leave it in flight and stop dispatching shards.
+	if result.err == htlcswitch.ErrSwitchExiting {
+		p.terminal = true
+		p.exiting = true
+		return nil
+	}
+
+	if result.err != nil {
+		log.Errorf("Attempt to send %v of payment %x failed: %v",
+			shard.amt, hash, result.err)
+
+		p.remaining += shard.amt
+		p.feesPaid -= shard.route.TotalFees
+		p.failedRoutes[routeKey(shard.route)] = struct{}{}
+
+		err := p.router.payments.FailAttempt(
+			hash, shard.attemptID, &channeldb.HTLCFailInfo{
+				FailTime: time.Now(),
+				Message:  result.err.Error(),
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		// Record the failure with mission control, so the responsible
+		// node or channel is avoided by subsequent shards and
+		// payments. If the failure is terminal, then there's no use in
+		// attempting any other route.
+		failure := p.router.missionControl.reportFailure(
+			shard.route, result.err,
+		)
+		p.failureReason = failure.reason
+		p.lastErr = result.err
+		if failure.terminal {
+			p.terminal = true
+		}
+
+		return nil
+	}
+
+	p.router.missionControl.reportSuccess(shard.route)
+
+	if !p.succeeded() {
+		p.preimage = result.preimage
+		p.successRoute = shard.route
+	}
+
+	err := p.router.payments.SettleAttempt(
+		hash, shard.attemptID, &channeldb.HTLCSettleInfo{
+			Preimage:   result.preimage,
+			SettleTime: time.Now(),
+		},
+	)
+	if err != nil {
+		log.Errorf("Unable to record settle of payment %x: %v", hash,
+			err)
+	}
+
+	return nil
+}
+
+// routeKey returns a key uniquely identifying the channels traversed by the
+// passed route, along with the amount it delivers.
+func routeKey(route *Route) string {
+	key := fmt.Sprintf("%v", route.TotalAmount-route.TotalFees)
+	for _, hop := range route.Hops {
+		key += fmt.Sprintf(":%v", hop.Channel.ChannelID)
+	}
+
+	return key
+}
@@ -0,0 +1,266 @@
+package routing
+
+import (
+	"sync"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/subscribe"
+)
+
+// paymentEvent is sent over the payment tracker's event bus each time the
+// state of a payment changes. It carries a snapshot of the payment as of
+// right after the change.
+type paymentEvent struct {
+	paymentHash [32]byte
+	payment     *channeldb.MPPayment
+}
+
+// PaymentSubscription is a subscription to the state transitions of a single
+// payment.
+type PaymentSubscription struct {
+	// Updates is the channel over which a snapshot of the payment is
+	// sent each time its state changes: once it's initiated, an HTLC
+	// attempt is added or resolved, and finally once it either succeeds
+	// or fails. The first snapshot sent reflects the state of the payment
+	// at the time of the subscription. The channel is closed once the
+	// payment has succeeded or failed, or the subscription is cancelled.
+	Updates <-chan *channeldb.MPPayment
+
+	// Cancel cancels the subscription. It should be called once the
+	// subscriber is no longer interested in the payment's updates.
+	Cancel func()
+}
+
+// paymentTracker wraps a PaymentStore, notifying the subscribers of a payment
+// of each state transition recorded within the store.
+type paymentTracker struct {
+	PaymentStore
+
+	events *subscribe.Server
+
+	// paymentMtxs holds a mutex for each payment that's being modified or
+	// subscribed to. It serializes the modifications of a payment along
+	// with the events they produce, ensuring that a new subscriber
+	// receives every change made after the snapshot it starts out with,
+	// while modifications of unrelated payments proceed concurrently.
+	// It's guarded by mtx.
+	paymentMtxs map[[32]byte]*paymentMtx
+	mtx         sync.Mutex
+}
+
+// paymentMtx is the mutex of a single payment, along with the number of
+// callers holding or waiting on it.
+type paymentMtx struct {
+	sync.Mutex
+	refs int
+}
+
+// A compile time check to ensure paymentTracker implements the PaymentStore
+// interface.
+var _ PaymentStore = (*paymentTracker)(nil)
+
+// newPaymentTracker creates a new payment tracker on top of the passed store.
+func newPaymentTracker(store PaymentStore) *paymentTracker {
+	return &paymentTracker{
+		PaymentStore: store,
+		events:       subscribe.NewServer(),
+		paymentMtxs:  make(map[[32]byte]*paymentMtx),
+	}
+}
+
+// lockPayment acquires the mutex of the payment with the given hash.
+func (t *paymentTracker) lockPayment(paymentHash [32]byte) {
+	t.mtx.Lock()
+	mtx, ok := t.paymentMtxs[paymentHash]
+	if !ok {
+		mtx = &paymentMtx{}
+		t.paymentMtxs[paymentHash] = mtx
+	}
+	mtx.refs++
+	t.mtx.Unlock()
+
+	mtx.Lock()
+}
+
+// unlockPayment releases the mutex of the payment with the given hash,
+// discarding it once no other caller holds or waits on it.
+func (t *paymentTracker) unlockPayment(paymentHash [32]byte) {
+	t.mtx.Lock()
+	mtx := t.paymentMtxs[paymentHash]
+	mtx.refs--
+	if mtx.refs == 0 {
+		delete(t.paymentMtxs, paymentHash)
+	}
+	t.mtx.Unlock()
+
+	mtx.Unlock()
+}
+
+// Start starts the tracker's event bus.
+func (t *paymentTracker) Start() error {
+	return t.events.Start()
+}
+
+// Stop stops the tracker's event bus, closing all active subscriptions.
+func (t *paymentTracker) Stop() error {
+	return t.events.Stop()
+}
+
+// InitPayment atomically moves the payment into the InFlight state.
+//
+// NOTE: Part of the PaymentStore interface.
+func (t *paymentTracker) InitPayment(paymentHash [32]byte,
+	info *channeldb.PaymentCreationInfo) error {
+
+	return t.update(paymentHash, func() error {
+		return t.PaymentStore.InitPayment(paymentHash, info)
+	})
+}
+
+// RegisterAttempt atomically records the provided HTLCAttemptInfo.
+//
+// NOTE: Part of the PaymentStore interface.
+func (t *paymentTracker) RegisterAttempt(paymentHash [32]byte,
+	attempt *channeldb.HTLCAttemptInfo) error {
+
+	return t.update(paymentHash, func() error {
+		return t.PaymentStore.RegisterAttempt(paymentHash, attempt)
+	})
+}
+
+// SettleAttempt marks the given attempt settled with the preimage.
+//
+// NOTE: Part of the PaymentStore interface.
+func (t *paymentTracker) SettleAttempt(paymentHash [32]byte, attemptID uint64,
+	settleInfo *channeldb.HTLCSettleInfo) error {
+
+	return t.update(paymentHash, func() error {
+		return t.PaymentStore.SettleAttempt(
+			paymentHash, attemptID, settleInfo,
+		)
+	})
+}
+
+// FailAttempt marks the given payment attempt failed.
+//
+// NOTE: Part of the PaymentStore interface.
+func (t *paymentTracker) FailAttempt(paymentHash [32]byte, attemptID uint64,
+	failInfo *channeldb.HTLCFailInfo) error {
+
+	return t.update(paymentHash, func() error {
+		return t.PaymentStore.FailAttempt(
+			paymentHash, attemptID, failInfo,
+		)
+	})
+}
+
+// FailPayment transitions a payment into the Failed state.
+//
+// NOTE: Part of the PaymentStore interface.
+func (t *paymentTracker) FailPayment(paymentHash [32]byte,
+	reason channeldb.FailureReason) error {
+
+	return t.update(paymentHash, func() error {
+		return t.PaymentStore.FailPayment(paymentHash, reason)
+	})
+}
+
+// update applies the passed modification to the payment with the given hash,
+// and notifies the payment's subscribers of its new state if it succeeds.
+func (t *paymentTracker) update(paymentHash [32]byte, modify func() error) error {
+	t.lockPayment(paymentHash)
+	defer t.unlockPayment(paymentHash)
+
+	if err := modify(); err != nil {
+		return err
+	}
+
+	payment, err := t.PaymentStore.FetchPayment(paymentHash)
+	if err != nil {
+		log.Errorf("Unable to fetch payment %x to notify subscribers: "+
+			"%v", paymentHash, err)
+		return nil
+	}
+
+	err = t.events.SendUpdate(&paymentEvent{
+		paymentHash: paymentHash,
+		payment:     payment,
+	})
+	if err != nil {
+		log.Debugf("Unable to notify subscribers of payment %x: %v",
+			paymentHash, err)
+	}
+
+	return nil
+}
+
+// subscribe returns a subscription to the state transitions of the payment
+// with the given hash. An error is returned if the payment isn't known.
+func (t *paymentTracker) subscribe(
+	paymentHash [32]byte) (*PaymentSubscription, error) {
+
+	// We'll fetch the current state of the payment and register the
+	// client atomically, so no change can happen in between.
+	t.lockPayment(paymentHash)
+	payment, err := t.PaymentStore.FetchPayment(paymentHash)
+	if err != nil {
+		t.unlockPayment(paymentHash)
+		return nil, err
+	}
+	client, err := t.events.Subscribe(&paymentEvent{})
+	t.unlockPayment(paymentHash)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make(chan *channeldb.MPPayment)
+	cancel := make(chan struct{})
+	var cancelOnce sync.Once
+
+	go func() {
+		defer close(updates)
+		defer client.Cancel()
+
+		for {
+			select {
+			case updates <- payment:
+			case <-cancel:
+				return
+			case <-client.Quit():
+				return
+			}
+
+			// Once the payment has reached a final state, no
+			// further updates will follow.
+			if payment.Status != channeldb.StatusInFlight {
+				return
+			}
+
+			// Otherwise, we'll wait for the next update of this
+			// payment.
+			payment = nil
+			for payment == nil {
+				select {
+				case e := <-client.Updates():
+					event := e.(*paymentEvent)
+					if event.paymentHash == paymentHash {
+						payment = event.payment
+					}
+				case <-cancel:
+					return
+				case <-client.Quit():
+					return
+				}
+			}
+		}
+	}()
+
+	return &PaymentSubscription{
+		Updates: updates,
+		Cancel: func() {
+			cancelOnce.Do(func() {
+				close(cancel)
+			})
+		},
+	}, nil
+}
@@ -0,0 +1,302 @@
+package routing
+
+import (
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/htlcswitch"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// pairResult is the outcome attributed to a directed pair of nodes along a
+// route, along with the amount that was sent over it.
+type pairResult struct {
+	pair nodePair
+	amt  lnwire.MilliAtom
+}
+
+// failureInterpretation is the interpretation of the failure of a payment
+// attempt over a route. It determines which part of the route is to blame for
+// the failure, the reason to record for the payment should it ultimately fail,
+// and whether attempting the payment over other routes is of any use.
+//
+// Nodes along the route are referred to by their index, where zero refers to
+// our own node, and i refers to the node at the end of the i-th hop of the
+// route. The pair with index i is the pair of nodes i-1 and i, which is
+// connected by the channel of the i-th hop.
+type failureInterpretation struct {
+	route  *Route
+	source vertex
+
+	// failureSourceIdx is the index of the node that generated the
+	// failure, or -1 if it couldn't be determined.
+	failureSourceIdx int
+
+	// reason is the reason to record for the payment, should it fail
+	// because of this failure.
+	reason channeldb.FailureReason
+
+	// terminal indicates that attempting the payment over any other route
+	// would fail in the same way.
+	terminal bool
+
+	// failedNode is set if the failure is attributed to a node as a whole,
+	// rather than to one of its channels.
+	failedNode *vertex
+
+	// failedPairs is the set of pairs that are penalized for the failure.
+	// If it can't be determined which of a range of pairs is responsible,
+	// then each of them is penalized.
+	failedPairs []pairResult
+
+	// successPairs is the set of pairs that are known to have forwarded
+	// the payment before it failed.
+	successPairs []pairResult
+}
+
+// interpretFailure interprets the error returned by the switch for a payment
+// attempt over the passed route, which originates at the passed source node.
+func interpretFailure(route *Route, source vertex,
+	sendErr error) *failureInterpretation {
+
+	i := &failureInterpretation{
+		route:            route,
+		source:           source,
+		failureSourceIdx: -1,
+		reason:           channeldb.FailureReasonNoRoute,
+	}
+	if len(route.Hops) == 0 {
+		return i
+	}
+
+	// If the failure couldn't be decrypted, then one of the nodes along
+	// the route tampered with it, though we can't tell which one.
+	if sendErr == htlcswitch.ErrUnreadableFailureMessage {
+		i.processUnreadable()
+		return i
+	}
+
+	// Any other error that isn't a forwarding error doesn't concern the
+	// nodes along the route.
+	fErr, ok := sendErr.(*htlcswitch.ForwardingError)
+	if !ok {
+		return i
+	}
+
+	switch {
+	case fErr.LocalFailure:
+		i.failureSourceIdx = 0
+		i.processLocal(fErr.FailureCode)
+
+	// Without the source of the failure, we can only go by the failure
+	// code itself.
+	case fErr.ErrorSource == nil:
+		if isFinalFailure(fErr.FailureCode) {
+			i.reason = channeldb.FailureReasonIncorrectPaymentDetails
+			i.terminal = true
+		}
+
+	default:
+		errSource := newVertex(fErr.ErrorSource)
+		for idx, hop := range route.Hops {
+			if newVertex(hop.Channel.Node.PubKey) == errSource {
+				i.failureSourceIdx = idx + 1
+				break
+			}
+		}
+
+		switch i.failureSourceIdx {
+		// A failure generated by a node that isn't part of the route
+		// can't be genuine, so it's treated as if it were unreadable.
+		case -1:
+			i.processUnreadable()
+
+		case len(route.Hops):
+			i.processFinal(fErr.FailureCode)
+
+		default:
+			i.processIntermediate(fErr.FailureCode)
+		}
+	}
+
+	return i
+}
+
+// isFinalFailure returns true if the passed failure code may only be sent by
+// the final hop of a route, as it concerns the details of the payment itself.
+func isFinalFailure(code lnwire.FailCode) bool {
+	switch code {
+	case lnwire.CodeUnknownPaymentHash, lnwire.CodeIncorrectPaymentAmount,
+		lnwire.CodeFinalExpiryTooSoon,
+		lnwire.CodeFinalIncorrectCltvExpiry,
+		lnwire.CodeFinalIncorrectHtlcAmount:
+
+		return true
+	}
+
+	return false
+}
+
+// processLocal interprets a failure that occurred within our own node, before
+// the payment was forwarded to the first hop.
+func (i *failureInterpretation) processLocal(code lnwire.FailCode) {
+	// Our channel to the first hop is unable to carry the payment, as we
+	// either aren't connected to the first hop, or lack the balance.
+	i.failPair(1)
+
+	switch code {
+	case lnwire.CodeUnknownNextPeer:
+		i.reason = channeldb.FailureReasonPeerOffline
+	case lnwire.CodeTemporaryChannelFailure:
+		i.reason = channeldb.FailureReasonInsufficientBalance
+	default:
+		i.reason = channeldb.FailureReasonError
+	}
+}
+
+// processUnreadable interprets a failure whose source couldn't be determined.
+func (i *failureInterpretation) processUnreadable() {
+	n := len(i.route.Hops)
+
+	// If the route consists of a single hop, then the destination must
+	// have tampered with its own failure, so there's no use in trying
+	// again.
+	if n == 1 {
+		i.failNode(1)
+		i.reason = channeldb.FailureReasonError
+		i.terminal = true
+		return
+	}
+
+	// Otherwise, we'll penalize every pair along the route, including the
+	// one with our own peer, to ensure the responsible node is hit too.
+	for idx := 1; idx <= n; idx++ {
+		i.failPair(idx)
+	}
+}
+
+// processFinal interprets a failure generated by the final hop of the route.
+func (i *failureInterpretation) processFinal(code lnwire.FailCode) {
+	n := len(i.route.Hops)
+
+	switch code {
+	// The destination rejected the payment hash, amount, or expiry of the
+	// payment, so every route will meet the same fate. The payment did
+	// make it all the way to the destination though.
+	case lnwire.CodeUnknownPaymentHash, lnwire.CodeIncorrectPaymentAmount,
+		lnwire.CodeFinalExpiryTooSoon:
+
+		i.succeedPairs(n)
+		i.reason = channeldb.FailureReasonIncorrectPaymentDetails
+		i.terminal = true
+
+	// The HTLC that reached the destination doesn't match the onion
+	// payload we crafted for it. If we sent the HTLC to the destination
+	// ourselves, then the payment details are at fault. Otherwise, the
+	// node before the destination tampered with the HTLC.
+	case lnwire.CodeFinalIncorrectCltvExpiry,
+		lnwire.CodeFinalIncorrectHtlcAmount:
+
+		if n == 1 {
+			i.reason = channeldb.FailureReasonIncorrectPaymentDetails
+			i.terminal = true
+			return
+		}
+
+		i.succeedPairs(n - 1)
+		i.failPair(n)
+
+	// Any other failure sent by the destination means that it's unable to
+	// accept the payment, regardless of the route it arrives over.
+	default:
+		i.succeedPairs(n)
+		i.reason = channeldb.FailureReasonError
+		i.terminal = true
+	}
+}
+
+// processIntermediate interprets a failure generated by an intermediate hop
+// of the route.
+func (i *failureInterpretation) processIntermediate(code lnwire.FailCode) {
+	idx := i.failureSourceIdx
+
+	switch code {
+	// The node claims that the onion it received was malformed. Either
+	// the node before it corrupted the onion, or the node itself is lying,
+	// so we'll penalize the pair between them.
+	case lnwire.CodeInvalidRealm, lnwire.CodeInvalidOnionVersion,
+		lnwire.CodeInvalidOnionHmac, lnwire.CodeInvalidOnionKey:
+
+		i.succeedPairs(idx - 1)
+		i.failPair(idx)
+
+	// The node itself is failing, so we'll penalize it as a whole.
+	case lnwire.CodeTemporaryNodeFailure, lnwire.CodePermanentNodeFailure,
+		lnwire.CodeRequiredNodeFeatureMissing:
+
+		i.succeedPairs(idx - 1)
+		i.failNode(idx)
+
+	// The node was unable to forward the payment to the next hop, so
+	// we'll penalize the pair between them.
+	case lnwire.CodeTemporaryChannelFailure,
+		lnwire.CodePermanentChannelFailure,
+		lnwire.CodeRequiredChannelFeatureMissing,
+		lnwire.CodeUnknownNextPeer, lnwire.CodeChannelDisabled:
+
+		i.succeedPairs(idx)
+		i.failPair(idx + 1)
+
+	// Policy related failures aren't penalized, as the channel update
+	// they carry has already been applied to the graph, giving the
+	// channel a second chance under its new policy.
+	case lnwire.CodeAmountBelowMinimum, lnwire.CodeFeeInsufficient,
+		lnwire.CodeIncorrectCltvExpiry, lnwire.CodeExpiryTooSoon:
+
+		i.succeedPairs(idx)
+
+	// Any other failure, including those only the final hop may send,
+	// means the node is misbehaving, so we'll penalize it as a whole.
+	default:
+		i.succeedPairs(idx - 1)
+		i.failNode(idx)
+	}
+}
+
+// node returns the vertex of the node with the passed index.
+func (i *failureInterpretation) node(idx int) vertex {
+	if idx == 0 {
+		return i.source
+	}
+
+	return newVertex(i.route.Hops[idx-1].Channel.Node.PubKey)
+}
+
+// pairResult returns the pair with the passed index, along with the amount
+// sent over it.
+func (i *failureInterpretation) pairResult(idx int) pairResult {
+	return pairResult{
+		pair: nodePair{
+			from: i.node(idx - 1),
+			to:   i.node(idx),
+		},
+		amt: hopAmount(i.route.Hops[idx-1]),
+	}
+}
+
+// failPair penalizes the pair with the passed index.
+func (i *failureInterpretation) failPair(idx int) {
+	i.failedPairs = append(i.failedPairs, i.pairResult(idx))
+}
+
+// succeedPairs records the pairs up to and including the passed index as
+// having forwarded the payment.
+func (i *failureInterpretation) succeedPairs(lastIdx int) {
+	for idx := 1; idx <= lastIdx; idx++ {
+		i.successPairs = append(i.successPairs, i.pairResult(idx))
+	}
+}
+
+// failNode penalizes the node with the passed index as a whole.
+func (i *failureInterpretation) failNode(idx int) {
+	node := i.node(idx)
+	i.failedNode = &node
+}
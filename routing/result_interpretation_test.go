@@ -0,0 +1,198 @@
+package routing
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/htlcswitch"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/btcec"
+)
+
+// TestInterpretFailure asserts that failures of payment attempts are
+// attributed to the correct range of hops along the route.
+func TestInterpretFailure(t *testing.T) {
+	t.Parallel()
+
+	var keys [4]*btcec.PublicKey
+	for i := range keys {
+		priv, err := btcec.NewPrivateKey(btcec.S256())
+		if err != nil {
+			t.Fatalf("unable to generate key: %v", err)
+		}
+		keys[i] = priv.PubKey()
+	}
+
+	// We'll construct a route of three hops, from node 0, which is our own
+	// node, to node 3.
+	route := &Route{}
+	for i := 1; i < len(keys); i++ {
+		route.Hops = append(route.Hops, &Hop{
+			Channel: &ChannelHop{
+				ChannelEdgePolicy: &channeldb.ChannelEdgePolicy{
+					ChannelID: uint64(i),
+					Node: &channeldb.LightningNode{
+						PubKey: keys[i],
+					},
+				},
+			},
+			AmtToForward: lnwire.MilliAtom(1000 * (len(keys) - i)),
+			Fee:          100,
+		})
+	}
+
+	node := func(idx int) *vertex {
+		v := newVertex(keys[idx])
+		return &v
+	}
+	pairs := func(idxs ...int) []pairResult {
+		var results []pairResult
+		for _, idx := range idxs {
+			results = append(results, pairResult{
+				pair: nodePair{
+					from: newVertex(keys[idx-1]),
+					to:   newVertex(keys[idx]),
+				},
+				amt: hopAmount(route.Hops[idx-1]),
+			})
+		}
+		return results
+	}
+	remoteErr := func(idx int, code lnwire.FailCode) error {
+		return &htlcswitch.ForwardingError{
+			FailureCode: code,
+			ErrorSource: keys[idx],
+		}
+	}
+
+	tests := []struct {
+		name          string
+		err           error
+		expSourceIdx  int
+		expReason     channeldb.FailureReason
+		expTerminal   bool
+		expFailedNode *vertex
+		expFailed     []pairResult
+		expSuccess    []pairResult
+	}{
+		{
+			name:         "unrelated error",
+			err:          errors.New("unrelated"),
+			expSourceIdx: -1,
+			expReason:    channeldb.FailureReasonNoRoute,
+		},
+		{
+			name: "local insufficient balance",
+			err: &htlcswitch.ForwardingError{
+				FailureCode:  lnwire.CodeTemporaryChannelFailure,
+				LocalFailure: true,
+			},
+			expSourceIdx: 0,
+			expReason:    channeldb.FailureReasonInsufficientBalance,
+			expFailed:    pairs(1),
+		},
+		{
+			name:         "unreadable failure",
+			err:          htlcswitch.ErrUnreadableFailureMessage,
+			expSourceIdx: -1,
+			expReason:    channeldb.FailureReasonNoRoute,
+			expFailed:    pairs(1, 2, 3),
+		},
+		{
+			name: "failure from unknown node",
+			err: &htlcswitch.ForwardingError{
+				FailureCode: lnwire.CodeTemporaryChannelFailure,
+				ErrorSource: keys[0],
+			},
+			expSourceIdx: -1,
+			expReason:    channeldb.FailureReasonNoRoute,
+			expFailed:    pairs(1, 2, 3),
+		},
+		{
+			name:         "intermediate channel failure",
+			err:          remoteErr(1, lnwire.CodeTemporaryChannelFailure),
+			expSourceIdx: 1,
+			expReason:    channeldb.FailureReasonNoRoute,
+			expFailed:    pairs(2),
+			expSuccess:   pairs(1),
+		},
+		{
+			name:          "intermediate node failure",
+			err:           remoteErr(2, lnwire.CodeTemporaryNodeFailure),
+			expSourceIdx:  2,
+			expReason:     channeldb.FailureReasonNoRoute,
+			expFailedNode: node(2),
+			expSuccess:    pairs(1),
+		},
+		{
+			name:         "intermediate policy failure",
+			err:          remoteErr(2, lnwire.CodeFeeInsufficient),
+			expSourceIdx: 2,
+			expReason:    channeldb.FailureReasonNoRoute,
+			expSuccess:   pairs(1, 2),
+		},
+		{
+			name:         "intermediate bad onion",
+			err:          remoteErr(2, lnwire.CodeInvalidOnionHmac),
+			expSourceIdx: 2,
+			expReason:    channeldb.FailureReasonNoRoute,
+			expFailed:    pairs(2),
+			expSuccess:   pairs(1),
+		},
+		{
+			name:          "lying intermediate node",
+			err:           remoteErr(1, lnwire.CodeUnknownPaymentHash),
+			expSourceIdx:  1,
+			expReason:     channeldb.FailureReasonNoRoute,
+			expFailedNode: node(1),
+		},
+		{
+			name:         "final unknown payment hash",
+			err:          remoteErr(3, lnwire.CodeUnknownPaymentHash),
+			expSourceIdx: 3,
+			expReason:    channeldb.FailureReasonIncorrectPaymentDetails,
+			expTerminal:  true,
+			expSuccess:   pairs(1, 2, 3),
+		},
+		{
+			name:         "final incorrect htlc amount",
+			err:          remoteErr(3, lnwire.CodeFinalIncorrectHtlcAmount),
+			expSourceIdx: 3,
+			expReason:    channeldb.FailureReasonNoRoute,
+			expFailed:    pairs(3),
+			expSuccess:   pairs(1, 2),
+		},
+	}
+
+	source := newVertex(keys[0])
+	for _, test := range tests {
+		i := interpretFailure(route, source, test.err)
+
+		if i.failureSourceIdx != test.expSourceIdx {
+			t.Fatalf("%v: expected failure source %v, got %v",
+				test.name, test.expSourceIdx, i.failureSourceIdx)
+		}
+		if i.reason != test.expReason {
+			t.Fatalf("%v: expected reason %v, got %v", test.name,
+				test.expReason, i.reason)
+		}
+		if i.terminal != test.expTerminal {
+			t.Fatalf("%v: expected terminal %v, got %v", test.name,
+				test.expTerminal, i.terminal)
+		}
+		if !reflect.DeepEqual(i.failedNode, test.expFailedNode) {
+			t.Fatalf("%v: expected failed node %v, got %v",
+				test.name, test.expFailedNode, i.failedNode)
+		}
+		if !reflect.DeepEqual(i.failedPairs, test.expFailed) {
+			t.Fatalf("%v: expected failed pairs %v, got %v",
+				test.name, test.expFailed, i.failedPairs)
+		}
+		if !reflect.DeepEqual(i.successPairs, test.expSuccess) {
+			t.Fatalf("%v: expected success pairs %v, got %v",
+				test.name, test.expSuccess, i.successPairs)
+		}
+	}
+}
@@ -2,14 +2,18 @@ package routing
 
 import (
 	"bytes"
+	"crypto/rand"
 	"fmt"
+	"math"
 	"sort"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/boltdb/bolt"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/htlcswitch"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/routing/chainview"
@@ -23,6 +27,12 @@ import (
 	"github.com/lightningnetwork/lightning-onion"
 )
 
+const (
+	// zombieCheckInterval is the interval at which the router checks for
+	// channels which have gone stale and should be marked as zombies.
+	zombieCheckInterval = time.Hour
+)
+
 // ChannelGraphSource represent the source of information about the topology of
 // lightning network, it responsible for addition of nodes, edges
 // and applying edges updates, return the current block with with out
@@ -67,6 +77,10 @@ type ChannelGraphSource interface {
 	// graph.
 	ForEachChannel(func(chanInfo *channeldb.ChannelEdgeInfo,
 		e1, e2 *channeldb.ChannelEdgePolicy) error) error
+
+	// ZombieChannels returns the set of channel IDs of all channels which
+	// have been marked as zombies due to a lack of recent updates.
+	ZombieChannels() (map[uint64]struct{}, error)
 }
 
 // FeeSchema is the set fee configuration for a Lighting Node on the network.
@@ -75,7 +89,7 @@ type ChannelGraphSource interface {
 type FeeSchema struct {
 	// BaseFee is the base amount of milli-satoshis that will be chained
 	// for ANY payment forwarded.
-	BaseFee lnwire.MilliSatoshi
+	BaseFee lnwire.MilliAtom
 
 	// FeeRate is the rate that will be charged for forwarding payments.
 	// This value should be interpreted as the numerator for a fraction
@@ -84,6 +98,26 @@ type FeeSchema struct {
 	FeeRate uint32
 }
 
+// ChannelPolicy is the forwarding policy we advertise and enforce for one of
+// our channels. Besides the fee schema, which always applies, the policy may
+// set any of the remaining parameters, where a zero value leaves the current
+// value of that parameter unchanged.
+type ChannelPolicy struct {
+	FeeSchema
+
+	// TimeLockDelta is the number of blocks the time lock of an HTLC
+	// forwarded over the channel must be reduced by.
+	TimeLockDelta uint16
+
+	// MinHTLC is the smallest HTLC that will be forwarded over the
+	// channel.
+	MinHTLC lnwire.MilliAtom
+
+	// MaxHTLC is the largest HTLC that will be forwarded over the
+	// channel. It may not exceed the capacity of the channel.
+	MaxHTLC lnwire.MilliAtom
+}
+
 // Config defines the configuration for the ChannelRouter. ALL elements within
 // the configuration MUST be non-nil for the ChannelRouter to carry out its
 // duties.
@@ -109,21 +143,109 @@ type Config struct {
 	// payment was unsuccessful.
 	SendToSwitch func(firstHop *btcec.PublicKey, htlcAdd *lnwire.UpdateAddHTLC,
 		circuit *sphinx.Circuit) ([sha256.Size]byte, error)
+
+	// GetPaymentResult is a function that blocks until the link-layer
+	// switch learns the outcome of an HTLC with the given payment hash
+	// and amount that was sent before a restart. The circuit is used to
+	// decrypt the failure, should the HTLC have failed. If the switch
+	// shuts down first, then htlcswitch.ErrSwitchExiting is returned.
+	GetPaymentResult func(paymentHash [32]byte, amt lnwire.MilliAtom,
+		circuit *sphinx.Circuit) ([sha256.Size]byte, error)
+
+	// QueryBandwidth is a function that returns the current outgoing
+	// bandwidth of the passed channel of ours, as known to the link-layer
+	// switch. A channel without an active link has no bandwidth. It's used
+	// to skip the channels of ours that are unable to carry a payment
+	// during path finding. If nil, then the bandwidth of our channels
+	// isn't taken into account.
+	QueryBandwidth func(edge *channeldb.ChannelEdgeInfo) lnwire.MilliAtom
+
+	// AddCircularPayment registers the preimage of a circular payment,
+	// which is sent from our own node back to itself, so the payment can
+	// be settled without an invoice once it arrives. The amount is the
+	// value the payment is expected to deliver to our node. The returned
+	// function removes the preimage once the payment has completed. If
+	// nil, then circular payments aren't supported.
+	AddCircularPayment func(preimage [32]byte, amt lnwire.MilliAtom) func()
+
+	// ZombieHorizon is the duration after which a channel that hasn't
+	// received an update for either of its directed edges is marked as a
+	// zombie. Zombie channels aren't used for path finding until a fresh
+	// update arrives. If zero, then channels are never marked as zombies.
+	ZombieHorizon time.Duration
+
+	// MissionControl governs how the probability of a payment being
+	// successfully forwarded over the nodes and channels of the graph is
+	// estimated from the outcome of past payment attempts.
+	MissionControl MissionControlConfig
+
+	// PathFinding governs how path finding trades off the fees of a route
+	// against its probability of succeeding.
+	PathFinding PathFindingConfig
+
+	// Payments is used to persist the state of each outgoing payment, along
+	// with every HTLC attempt made in order to complete it.
+	Payments PaymentStore
+}
+
+// PaymentStore is an interface which tracks the lifecycle of outgoing
+// payments, along with every HTLC attempt made in order to complete them.
+// Recording each attempt allows the final state of a payment to be reported
+// accurately, and in-flight payments to be resumed after a restart.
+type PaymentStore interface {
+	// InitPayment atomically moves the payment into the InFlight state.
+	// This method checks that no succeeded or in-flight payment exists
+	// for this payment hash.
+	InitPayment([32]byte, *channeldb.PaymentCreationInfo) error
+
+	// RegisterAttempt atomically records the provided HTLCAttemptInfo,
+	// assigning it a unique attempt ID.
+	RegisterAttempt([32]byte, *channeldb.HTLCAttemptInfo) error
+
+	// SettleAttempt marks the given attempt settled with the preimage.
+	SettleAttempt([32]byte, uint64, *channeldb.HTLCSettleInfo) error
+
+	// FailAttempt marks the given payment attempt failed.
+	FailAttempt([32]byte, uint64, *channeldb.HTLCFailInfo) error
+
+	// FailPayment transitions a payment into the Failed state, recording
+	// the reason the payment failed.
+	FailPayment([32]byte, channeldb.FailureReason) error
+
+	// FetchPayment returns the payment with the given hash, along with
+	// all of its HTLC attempts.
+	FetchPayment([32]byte) (*channeldb.MPPayment, error)
+
+	// FetchInFlightPayments returns all payments which are still in
+	// flight, such that they can be resumed after a restart.
+	FetchInFlightPayments() ([]*channeldb.MPPayment, error)
 }
 
+// A compile time check to ensure channeldb.DB implements the PaymentStore
+// interface.
+var _ PaymentStore = (*channeldb.DB)(nil)
+
 // routeTuple is an entry within the ChannelRouter's route cache. We cache
 // prospective routes based on first the destination, and then the target
 // amount. We required the target amount as that will influence the available
-// set of paths for a payment.
+// set of paths for a payment. The fee and time lock limits of the payment
+// similarly restrict the paths found.
 type routeTuple struct {
-	amt  lnwire.MilliSatoshi
-	dest [33]byte
+	amt       lnwire.MilliAtom
+	dest      [33]byte
+	feeLimit  lnwire.MilliAtom
+	cltvLimit uint32
 }
 
-// newRouteTuple creates a new route tuple from the target and amount.
-func newRouteTuple(amt lnwire.MilliSatoshi, dest *btcec.PublicKey) routeTuple {
+// newRouteTuple creates a new route tuple from the target, amount and the
+// limits placed upon the routes.
+func newRouteTuple(amt lnwire.MilliAtom, dest *btcec.PublicKey,
+	feeLimit lnwire.MilliAtom, cltvLimit uint32) routeTuple {
+
 	r := routeTuple{
-		amt: amt,
+		amt:       amt,
+		feeLimit:  feeLimit,
+		cltvLimit: cltvLimit,
 	}
 	copy(r.dest[:], dest.SerializeCompressed())
 
@@ -140,6 +262,10 @@ func newRouteTuple(amt lnwire.MilliSatoshi, dest *btcec.PublicKey) routeTuple {
 type ChannelRouter struct {
 	ntfnClientCounter uint64
 
+	// numNodesPruned is the total number of nodes pruned from the graph
+	// since startup, as they no longer had any channels.
+	numNodesPruned uint64
+
 	started uint32
 	stopped uint32
 
@@ -165,6 +291,22 @@ type ChannelRouter struct {
 	routeCacheMtx sync.RWMutex
 	routeCache    map[routeTuple][]*Route
 
+	// blacklist is an in-memory copy of the persisted node blacklist.
+	// Nodes within this map will never be used as an intermediate hop
+	// during path finding. The value of each entry denotes whether the
+	// node should also be avoided as the final destination of a payment.
+	blacklistMtx sync.RWMutex
+	blacklist    map[vertex]bool
+
+	// missionControl records the outcome of past payment attempts, which
+	// is used to steer path finding away from nodes and channels that
+	// recently caused payments to fail.
+	missionControl *missionControl
+
+	// payments wraps the configured payment store, notifying subscribers
+	// of each state transition of the payments they're tracking.
+	payments *paymentTracker
+
 	// newBlocks is a channel in which new blocks connected to the end of
 	// the main chain are sent over.
 	newBlocks <-chan *chainview.FilteredBlock
@@ -205,6 +347,25 @@ func New(cfg Config) (*ChannelRouter, error) {
 		return nil, err
 	}
 
+	// We'll also load the node blacklist from disk so we can consult it
+	// during path finding without hitting the database.
+	blacklistedNodes, err := cfg.Graph.FetchBlacklistedNodes()
+	if err != nil {
+		return nil, err
+	}
+	blacklist := make(map[vertex]bool, len(blacklistedNodes))
+	for _, node := range blacklistedNodes {
+		blacklist[vertex(node.PubKey)] = node.AvoidAsDestination
+	}
+
+	// Similarly, we'll load the history of past payment attempts.
+	mc, err := newMissionControl(
+		cfg.Graph, newVertex(selfNode.PubKey), cfg.MissionControl,
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	return &ChannelRouter{
 		cfg:               &cfg,
 		selfNode:          selfNode,
@@ -212,6 +373,9 @@ func New(cfg Config) (*ChannelRouter, error) {
 		topologyClients:   make(map[uint64]*topologyClient),
 		ntfnClientUpdates: make(chan *topologyClientUpdate),
 		routeCache:        make(map[routeTuple][]*Route),
+		blacklist:         blacklist,
+		missionControl:    mc,
+		payments:          newPaymentTracker(cfg.Payments),
 		quit:              make(chan struct{}),
 	}, nil
 }
@@ -232,6 +396,12 @@ func (r *ChannelRouter) Start() error {
 		return err
 	}
 
+	// We'll also start the payment tracker, so that subscribers can be
+	// notified of the payments resumed below.
+	if err := r.payments.Start(); err != nil {
+		return err
+	}
+
 	// Once the instance is active, we'll fetch the channel we'll receive
 	// notifications over.
 	r.newBlocks = r.cfg.ChainView.FilteredBlocks()
@@ -257,6 +427,12 @@ func (r *ChannelRouter) Start() error {
 	r.wg.Add(1)
 	go r.networkHandler()
 
+	// Finally, we'll resume tracking the outcome of any payments that
+	// were still in flight when we were last shut down.
+	if err := r.resumePayments(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -277,6 +453,10 @@ func (r *ChannelRouter) Stop() error {
 	close(r.quit)
 	r.wg.Wait()
 
+	if err := r.payments.Stop(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -367,11 +547,43 @@ func (r *ChannelRouter) syncGraphWithChain() error {
 		numChansClosed += numClosed
 	}
 
+	// With the closed channels removed, prune any nodes which no longer
+	// have any channels.
+	if numChansClosed > 0 {
+		if err := r.pruneGraphNodes(); err != nil {
+			return err
+		}
+	}
+
 	log.Infof("Graph pruning complete: %v channels we're closed since "+
 		"height %v", numChansClosed, pruneHeight)
 	return nil
 }
 
+// pruneGraphNodes removes all nodes which no longer have any channels from the
+// channel graph, adding them to the count of pruned nodes.
+func (r *ChannelRouter) pruneGraphNodes() error {
+	numPruned, err := r.cfg.Graph.PruneGraphNodes()
+	if err != nil {
+		return err
+	}
+	if numPruned == 0 {
+		return nil
+	}
+
+	total := atomic.AddUint64(&r.numNodesPruned, uint64(numPruned))
+	log.Infof("Pruned %v unconnected nodes from the channel graph, %v "+
+		"pruned since startup", numPruned, total)
+
+	return nil
+}
+
+// NumNodesPruned returns the total number of nodes pruned from the channel
+// graph since startup, as they no longer had any channels.
+func (r *ChannelRouter) NumNodesPruned() uint64 {
+	return atomic.LoadUint64(&r.numNodesPruned)
+}
+
 // networkHandler is the primary goroutine for the ChannelRouter. The roles of
 // this goroutine include answering queries related to the state of the
 // network, pruning the graph on new block notification, applying network
@@ -381,7 +593,15 @@ func (r *ChannelRouter) syncGraphWithChain() error {
 func (r *ChannelRouter) networkHandler() {
 	defer r.wg.Done()
 
-	// TODO(roasbeef): ticker to check if should prune in two weeks or not
+	// If a zombie horizon has been set, then we'll periodically mark any
+	// channels which haven't been updated within it as zombies.
+	var zombieTicks <-chan time.Time
+	if r.cfg.ZombieHorizon != 0 {
+		zombieTicker := time.NewTicker(zombieCheckInterval)
+		defer zombieTicker.Stop()
+
+		zombieTicks = zombieTicker.C
+	}
 
 	for {
 		select {
@@ -476,6 +696,13 @@ func (r *ChannelRouter) networkHandler() {
 				continue
 			}
 
+			// With the closed channels removed, prune any nodes
+			// which no longer have any channels.
+			if err := r.pruneGraphNodes(); err != nil {
+				log.Errorf("unable to prune graph nodes: %v",
+					err)
+			}
+
 			// Notify all currently registered clients of the newly
 			// closed channels.
 			closeSummaries := createCloseSummaries(blockHeight, chansClosed...)
@@ -483,6 +710,29 @@ func (r *ChannelRouter) networkHandler() {
 				ClosedChannels: closeSummaries,
 			})
 
+		// It's time to check for any channels that have gone stale,
+		// marking them as zombies so they're no longer used.
+		case <-zombieTicks:
+			staleBefore := time.Now().Add(-r.cfg.ZombieHorizon)
+			zombies, err := r.cfg.Graph.MarkZombieEdges(staleBefore)
+			if err != nil {
+				log.Errorf("unable to mark zombie channels: %v",
+					err)
+				continue
+			}
+			if len(zombies) == 0 {
+				continue
+			}
+
+			log.Infof("Marked %v channels without updates since "+
+				"%v as zombies", len(zombies), staleBefore)
+
+			// As the cached routes may traverse the new zombie
+			// channels, we'll invalidate the route cache.
+			r.routeCacheMtx.Lock()
+			r.routeCache = make(map[routeTuple][]*Route)
+			r.routeCacheMtx.Unlock()
+
 		// A new notification client update has arrived. We're either
 		// gaining a new client, or cancelling notifications for an
 		// existing client.
@@ -625,25 +875,18 @@ func (r *ChannelRouter) processUpdate(msg interface{}) error {
 
 		// Recreate witness output to be sure that declared in channel
 		// edge bitcoin keys and channel value corresponds to the
-		// reality.
-		_, witnessOutput, err := lnwallet.GenFundingPkScript(
+		// reality. As the funding output can only be recreated for
+		// script versions we know of, its script version is checked
+		// first, so that outputs of newer versions aren't
+		// mis-validated.
+		err = lnwallet.VerifyFundingOutput(
 			msg.BitcoinKey1.SerializeCompressed(),
 			msg.BitcoinKey2.SerializeCompressed(),
-			chanUtxo.Value,
+			chanUtxo,
 		)
 		if err != nil {
-			return errors.Errorf("unable to create funding pk "+
-				"script: %v", err)
-		}
-
-		// By checking the equality of witness pkscripts we checks that
-		// funding witness script is multisignature lock which contains
-		// both local and remote public keys which was declared in
-		// channel edge and also that the announced channel value is
-		// right.
-		if !bytes.Equal(witnessOutput.PkScript, chanUtxo.PkScript) {
-			return errors.Errorf("pkScript mismatch: expected %x, "+
-				"got %x", witnessOutput.PkScript, chanUtxo.PkScript)
+			return errors.Errorf("invalid funding output for "+
+				"chan_id=%v: %v", msg.ChannelID, err)
 		}
 
 		// TODO(roasbeef): this is a hack, needs to be removed
@@ -688,7 +931,7 @@ func (r *ChannelRouter) processUpdate(msg interface{}) error {
 		// the direction of the edge they control. Therefore we first
 		// check if we already have the most up to date information for
 		// that edge. If so, then we can exit early.
-		switch msg.Flags {
+		switch msg.ChannelFlags & lnwire.ChanUpdateDirection {
 
 		// A flag set of 0 indicates this is an announcement for the
 		// "first" node in the channel.
@@ -696,19 +939,19 @@ func (r *ChannelRouter) processUpdate(msg interface{}) error {
 			if edge1Timestamp.After(msg.LastUpdate) ||
 				edge1Timestamp.Equal(msg.LastUpdate) {
 				return newErrf(ErrIgnored, "Ignoring announcement "+
-					"(flags=%v) for known chan_id=%v", msg.Flags,
+					"(flags=%v) for known chan_id=%v", msg.ChannelFlags,
 					msg.ChannelID)
 
 			}
 
 		// Similarly, a flag set of 1 indicates this is an announcement
 		// for the "second" node in the channel.
-		case 1:
+		case lnwire.ChanUpdateDirection:
 			if edge2Timestamp.After(msg.LastUpdate) ||
 				edge2Timestamp.Equal(msg.LastUpdate) {
 
 				return newErrf(ErrIgnored, "Ignoring announcement "+
-					"(flags=%v) for known chan_id=%v", msg.Flags,
+					"(flags=%v) for known chan_id=%v", msg.ChannelFlags,
 					msg.ChannelID)
 			}
 		}
@@ -813,20 +1056,81 @@ type routingMsg struct {
 // inner loop.  Once we have a set of candidate routes, we calculate the
 // required fee and time lock values running backwards along the route. The
 // route that will be ranked the highest is the one with the lowest cumulative
-// fee along the route.
+// fee along the route. The private channels described by the passed route
+// hints are considered along with the channels of the graph, allowing targets
+// that aren't part of the graph to be reached. If restrictions are passed,
+// then only routes abiding by them are returned.
 func (r *ChannelRouter) FindRoutes(target *btcec.PublicKey,
-	amt lnwire.MilliSatoshi) ([]*Route, error) {
+	amt lnwire.MilliAtom, routeHints [][]HopHint,
+	restrictions *RouteRestrictions) ([]*Route, error) {
+
+	graph := newGraphSession(r.cfg.Graph)
+	defer func() {
+		if err := graph.release(); err != nil {
+			log.Errorf("Unable to release graph session: %v", err)
+		}
+	}()
+
+	return r.findRoutes(graph, target, amt, routeHints, restrictions)
+}
+
+// findRoutes implements FindRoutes, searching the graph within the snapshot
+// of the passed session, which may be shared by several searches.
+func (r *ChannelRouter) findRoutes(graph *graphSession,
+	target *btcec.PublicKey, amt lnwire.MilliAtom, routeHints [][]HopHint,
+	restrictions *RouteRestrictions) ([]*Route, error) {
 
 	dest := target.SerializeCompressed()
 	log.Debugf("Searching for path to %x, sending %v", dest, amt)
 
 	// We can short circuit the routing by opportunistically checking to
-	// see if the target vertex event exists in the current graph.
-	if _, exists, err := r.cfg.Graph.HasLightningNode(target); err != nil {
+	// see if the target vertex event exists in the current graph. A
+	// target that we've been given route hints for may be private, so
+	// we'll only do so in their absence.
+	if len(routeHints) == 0 {
+		exists, err := graph.hasNode(target)
+		if err != nil {
+			return nil, err
+		} else if !exists {
+			log.Debugf("Target %x is not in known graph", dest)
+			return nil, newErrf(ErrTargetNotInNetwork,
+				"target not found")
+		}
+	}
+
+	// Before we attempt path finding, we'll consult the node blacklist. If
+	// the target has been blacklisted as a destination, then we'll bail
+	// out early. Otherwise, every blacklisted node other than the target
+	// itself will be excluded from path finding.
+	targetVertex := newVertex(target)
+	r.blacklistMtx.RLock()
+	if avoidAsDest, ok := r.blacklist[targetVertex]; ok && avoidAsDest {
+		r.blacklistMtx.RUnlock()
+		return nil, newErrf(ErrTargetBlacklisted, "target %x has been "+
+			"blacklisted", dest)
+	}
+	ignoredNodes := make(map[vertex]struct{}, len(r.blacklist))
+	for v := range r.blacklist {
+		if v == targetVertex {
+			continue
+		}
+		ignoredNodes[v] = struct{}{}
+	}
+	r.blacklistMtx.RUnlock()
+
+	// The nodes the caller asked us to ignore are excluded in the same
+	// way, which makes the target unreachable if it's among them.
+	if restrictions != nil {
+		for _, node := range restrictions.IgnoredNodes {
+			ignoredNodes[newVertex(node)] = struct{}{}
+		}
+	}
+
+	// Channels which have been marked as zombies are likely unusable, so
+	// we'll exclude them from path finding.
+	zombies, err := graph.zombieEdges()
+	if err != nil {
 		return nil, err
-	} else if !exists {
-		log.Debugf("Target %x is not in known graph", dest)
-		return nil, newErrf(ErrTargetNotInNetwork, "target not found")
 	}
 
 	// We'll also fetch the current block height so we can properly
@@ -836,10 +1140,24 @@ func (r *ChannelRouter) FindRoutes(target *btcec.PublicKey,
 		return nil, err
 	}
 
+	// Our own channels may be unable to carry the payment regardless of
+	// their capacity, so we'll query the switch for their current
+	// bandwidth.
+	bandwidthHints, err := r.bandwidthHints(graph)
+	if err != nil {
+		return nil, err
+	}
+
 	// Now that we know the destination is reachable within the graph,
 	// we'll execute our KSP algorithm to find the k-shortest paths from
-	// our source to the destination.
-	shortestPaths, err := findPaths(r.cfg.Graph, r.selfNode, target, amt)
+	// our source to the destination. Edges are weighed by their
+	// probability of succeeding, as estimated from the outcome of past
+	// payment attempts. The channels described by the route hints are
+	// injected into our view of the graph for the duration of the search.
+	shortestPaths, err := findPaths(graph, r.selfNode, target,
+		ignoredNodes, zombies, hintEdges(target, routeHints),
+		bandwidthHints, restrictions, amt,
+		r.missionControl.edgeProbability, &r.cfg.PathFinding)
 	if err != nil {
 		return nil, err
 	}
@@ -850,6 +1168,7 @@ func (r *ChannelRouter) FindRoutes(target *btcec.PublicKey,
 	// aren't able to support the total satoshis flow once fees have been
 	// factored in.
 	validRoutes := make(sortableRoutes, 0, len(shortestPaths))
+	var overFeeLimit bool
 	for _, path := range shortestPaths {
 		// Attempt to make the path into a route. We snip off the first
 		// hop in the path as it contains a "self-hop" that is inserted
@@ -859,6 +1178,22 @@ func (r *ChannelRouter) FindRoutes(target *btcec.PublicKey,
 			continue
 		}
 
+		// Path finding only estimates the fee of a route, so we'll
+		// now check the exact fee and time lock of the route against
+		// the limits placed upon it.
+		if restrictions != nil && restrictions.FeeLimit != nil &&
+			route.TotalFees > *restrictions.FeeLimit {
+
+			overFeeLimit = true
+			continue
+		}
+		if restrictions != nil && restrictions.CltvLimit != 0 &&
+			route.TotalTimeLock-uint32(currentHeight) >
+				restrictions.CltvLimit {
+
+			continue
+		}
+
 		// If the path as enough total flow to support the computed
 		// route, then we'll add it to our set of valid routes.
 		validRoutes = append(validRoutes, route)
@@ -866,7 +1201,13 @@ func (r *ChannelRouter) FindRoutes(target *btcec.PublicKey,
 
 	// If all our perspective routes were eliminating during the transition
 	// from path to route, then we'll return an error to the caller
-	if len(validRoutes) == 0 {
+	switch {
+	case len(validRoutes) == 0 && overFeeLimit:
+		return nil, newErrf(ErrFeeLimitExceeded, "all routes to %x "+
+			"require fees exceeding the fee limit of %v", dest,
+			*restrictions.FeeLimit)
+
+	case len(validRoutes) == 0:
 		return nil, newErr(ErrNoPathFound, "unable to find a path to "+
 			"destination")
 	}
@@ -885,6 +1226,137 @@ func (r *ChannelRouter) FindRoutes(target *btcec.PublicKey,
 	return validRoutes, nil
 }
 
+// BuildRoute constructs a route carrying amt to the last of the passed hops,
+// traversing each of them in order, starting from our own node. Unlike
+// FindRoutes, no path finding is carried out: between each consecutive pair
+// of nodes, the cheapest channel within the local graph that is able to carry
+// the amount is selected, after which the fees and time locks are filled in
+// according to the policies of the selected channels. This allows callers to
+// implement their own routing strategies, such as circular rebalancing
+// payments, which pass our own node as the last hop.
+func (r *ChannelRouter) BuildRoute(amt lnwire.MilliAtom,
+	hops []*btcec.PublicKey) (*Route, error) {
+
+	if len(hops) == 0 {
+		return nil, newErr(ErrNoRouteFound, "no hops specified")
+	}
+	if len(hops) > HopLimit {
+		return nil, newErrf(ErrMaxHopsExceeded, "route has %v hops, "+
+			"max is %v", len(hops), HopLimit)
+	}
+
+	// We'll fetch the current block height so we can properly calculate
+	// the required HTLC time locks within the route.
+	_, currentHeight, err := r.cfg.Chain.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	// A route never traverses the same channel twice, which also ensures
+	// that a circular route returns over a different channel than the one
+	// it leaves over.
+	pathEdges := make([]*ChannelHop, 0, len(hops))
+	usedChans := make(map[uint64]struct{}, len(hops))
+	fromNode := r.selfNode
+	for _, hop := range hops {
+		edge, err := r.cheapestChannel(fromNode, hop, amt, usedChans)
+		if err != nil {
+			return nil, err
+		}
+		pathEdges = append(pathEdges, edge)
+		usedChans[edge.ChannelID] = struct{}{}
+
+		fromNode = edge.Node
+	}
+
+	return newRoute(amt, pathEdges, uint32(currentHeight))
+}
+
+// bandwidthHints returns the current outgoing bandwidth of each of our
+// channels, keyed by their channel ID. If the router wasn't configured with a
+// way to query the bandwidth, then no hints are returned.
+func (r *ChannelRouter) bandwidthHints(
+	graph *graphSession) (map[uint64]lnwire.MilliAtom, error) {
+
+	if r.cfg.QueryBandwidth == nil {
+		return nil, nil
+	}
+
+	hints := make(map[uint64]lnwire.MilliAtom)
+	err := graph.forEachChannel(r.selfNode, func(
+		edgeInfo *channeldb.ChannelEdgeInfo,
+		_, _ *channeldb.ChannelEdgePolicy) error {
+
+		hints[edgeInfo.ChannelID] = r.cfg.QueryBandwidth(edgeInfo)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return hints, nil
+}
+
+// cheapestChannel returns the channel from the passed node to the passed
+// target with the lowest fee for forwarding amt, out of those with sufficient
+// capacity that aren't within the set of excluded channels. As in path
+// finding, the returned hop carries the policy of the target's end of the
+// channel.
+func (r *ChannelRouter) cheapestChannel(from *channeldb.LightningNode,
+	to *btcec.PublicKey, amt lnwire.MilliAtom,
+	excluded map[uint64]struct{}) (*ChannelHop, error) {
+
+	var cheapest *ChannelHop
+	err := from.ForEachChannel(nil, func(_ *bolt.Tx,
+		edgeInfo *channeldb.ChannelEdgeInfo,
+		outEdge, inEdge *channeldb.ChannelEdgePolicy) error {
+
+		if inEdge == nil || !outEdge.Node.PubKey.IsEqual(to) {
+			return nil
+		}
+		if _, ok := excluded[edgeInfo.ChannelID]; ok {
+			return nil
+		}
+
+		// We'll skip any channel that is unable to carry the amount,
+		// either due to its capacity or its maximum HTLC.
+		if edgeInfo.Capacity < amt.ToSatoshis() {
+			return nil
+		}
+		if inEdge.MessageFlags.HasMaxHtlc() && amt > inEdge.MaxHTLC {
+			return nil
+		}
+
+		hop := &ChannelHop{
+			ChannelEdgePolicy: inEdge,
+			Capacity:          edgeInfo.Capacity,
+		}
+		if cheapest != nil &&
+			computeFee(amt, hop) >= computeFee(amt, cheapest) {
+
+			return nil
+		}
+
+		// As with path finding, we'll ensure that the hop points to
+		// the node at the other end of the channel.
+		hop.Node = outEdge.Node
+		cheapest = hop
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if cheapest == nil {
+		return nil, newErrf(ErrNoRouteFound, "no channel from %x to %x "+
+			"able to carry %v", from.PubKey.SerializeCompressed(),
+			to.SerializeCompressed(), amt)
+	}
+
+	return cheapest, nil
+}
+
 // generateSphinxPacket generates then encodes a sphinx packet which encodes
 // the onion route specified by the passed layer 3 route. The blob returned
 // from this function can immediately be included within an HTLC add packet to
@@ -911,6 +1383,27 @@ func generateSphinxPacket(route *Route, paymentHash []byte) ([]byte,
 	// properly forward the payment.
 	hopPayloads := route.ToHopPayloads()
 
+	// If the final hop is to be sent a TLV payload, then its legacy hop
+	// data is replaced by the frames carrying the payload, each of which
+	// is addressed to the final hop itself. The circuit only spans the
+	// route's hops, as any failure of the final hop is encrypted with the
+	// shared secret of its first frame.
+	frames, err := route.finalHopFrames()
+	if err != nil {
+		return nil, nil, err
+	}
+	circuitNodes := nodes
+	if frames != nil {
+		finalNode := nodes[len(nodes)-1]
+		hopPayloads = append(hopPayloads[:len(hopPayloads)-1], frames...)
+
+		circuitNodes = make([]*btcec.PublicKey, len(nodes))
+		copy(circuitNodes, nodes)
+		for i := 1; i < len(frames); i++ {
+			nodes = append(nodes, finalNode)
+		}
+	}
+
 	log.Tracef("Constructed per-hop payloads for payment_hash=%x: %v",
 		paymentHash[:], spew.Sdump(hopPayloads))
 
@@ -945,7 +1438,7 @@ func generateSphinxPacket(route *Route, paymentHash []byte) ([]byte,
 
 	return onionBlob.Bytes(), &sphinx.Circuit{
 		SessionKey:  sessionKey,
-		PaymentPath: nodes,
+		PaymentPath: circuitNodes,
 	}, nil
 }
 
@@ -957,22 +1450,125 @@ type LightningPayment struct {
 
 	// Amount is the value of the payment to send through the network in
 	// milli-satoshis.
-	Amount lnwire.MilliSatoshi
+	Amount lnwire.MilliAtom
 
 	// PaymentHash is the r-hash value to use within the HTLC extended to
 	// the first hop.
 	PaymentHash [32]byte
 
+	// PaymentRequest is an optional payment request that this payment is
+	// attempting to complete. If set, it'll be stored along with the
+	// payment.
+	PaymentRequest []byte
+
+	// FeeLimit is the maximum total fee, in milli-satoshis, that we're
+	// willing to pay in order to complete the payment. Routes requiring a
+	// larger fee won't be attempted. If neither FeeLimit nor
+	// FeeLimitPercent is set, then the fee limit defaults to the amount
+	// of the payment.
+	FeeLimit lnwire.MilliAtom
+
+	// FeeLimitPercent is the maximum total fee that we're willing to pay,
+	// expressed as a percentage of the amount of the payment. If both
+	// FeeLimit and FeeLimitPercent are set, then the lower of the two
+	// limits applies.
+	FeeLimitPercent uint32
+
+	// CltvLimit is the maximum total time lock delta, in blocks, of the
+	// routes attempted. If zero, then DefaultCltvLimit applies.
+	CltvLimit uint32
+
+	// PaymentAddr is the payment address, or payment secret, of the
+	// invoice being paid. If set, then it's included within the payload
+	// of the final hop of every shard of the payment, along with the
+	// total amount of the payment.
+	PaymentAddr *[32]byte
+
+	// MaxParts is the maximum number of shards the payment may be split
+	// into. If no route is able to carry the full amount of the payment,
+	// then it's split into smaller shards which are sent concurrently. A
+	// value of zero or one disables splitting. Splitting requires a
+	// payment address to be set.
+	MaxParts uint32
+
+	// RouteHints are the chains of private channels, supplied by the
+	// receiver, that may be used to reach the target. They're taken into
+	// account during path finding along with the channels of the graph.
+	RouteHints [][]HopHint
+
+	// OutgoingChannelIDs is the set of our channels that the first hop of
+	// every route attempted may go over. If empty, any of our channels
+	// may be used.
+	OutgoingChannelIDs []uint64
+
+	// LastHop, if set, is the node that must directly precede the target
+	// within every route attempted.
+	LastHop *btcec.PublicKey
+
 	// TODO(roasbeef): add e2e message?
 }
 
+// feeLimit returns the maximum total fee that may be paid to complete the
+// payment, after applying the defaults. A zero fee limit, which may result
+// from a percentage limit of a small payment, only allows routes without any
+// fee.
+func (p *LightningPayment) feeLimit() lnwire.MilliAtom {
+	if p.FeeLimit == 0 && p.FeeLimitPercent == 0 {
+		return p.Amount
+	}
+
+	feeLimit := p.FeeLimit
+	if p.FeeLimitPercent != 0 {
+		percentLimit := percentOf(p.Amount, p.FeeLimitPercent)
+		if feeLimit == 0 || percentLimit < feeLimit {
+			feeLimit = percentLimit
+		}
+	}
+
+	return feeLimit
+}
+
+// percentOf returns the given percentage of amt, rounded down. The result
+// saturates at the maximum representable amount rather than overflowing.
+func percentOf(amt lnwire.MilliAtom, percent uint32) lnwire.MilliAtom {
+	const maxAmt = lnwire.MilliAtom(math.MaxUint64)
+
+	// We'll split the amount into its whole hundreds and the remainder,
+	// so that the percentage of each can be computed without the
+	// intermediate product overflowing.
+	pct := lnwire.MilliAtom(percent)
+	hundreds, rem := amt/100, amt%100
+	if pct != 0 && hundreds > maxAmt/pct {
+		return maxAmt
+	}
+
+	limit := hundreds * pct
+	remLimit := rem * pct / 100
+	if limit > maxAmt-remLimit {
+		return maxAmt
+	}
+
+	return limit + remLimit
+}
+
+// cltvLimit returns the maximum total time lock delta of the routes attempted
+// for the payment, after applying the default.
+func (p *LightningPayment) cltvLimit() uint32 {
+	if p.CltvLimit == 0 {
+		return DefaultCltvLimit
+	}
+
+	return p.CltvLimit
+}
+
 // SendPayment attempts to send a payment as described within the passed
 // LightningPayment. This function is blocking and will return either: when the
 // payment is successful, or all candidates routes have been attempted and
 // resulted in a failed payment. If the payment succeeds, then a non-nil Route
 // will be returned which describes the path the successful payment traversed
-// within the network to reach the destination. Additionally, the payment
-// preimage will also be returned.
+// within the network to reach the destination. If the payment was split into
+// multiple parts, then this is the route of the first part to succeed.
+// Additionally, the payment preimage will also be returned.
 func (r *ChannelRouter) SendPayment(payment *LightningPayment) ([32]byte, *Route, error) {
 	log.Tracef("Dispatching route for lightning payment: %v",
 		newLogClosure(func() string {
@@ -981,89 +1577,435 @@ func (r *ChannelRouter) SendPayment(payment *LightningPayment) ([32]byte, *Route
 		}),
 	)
 
-	var (
-		sendError error
-		preImage  [32]byte
+	// A payment may only be split into multiple parts if the receiver
+	// can tell them apart from independent payments, which requires the
+	// payment address of the invoice.
+	if payment.MaxParts > 1 && payment.PaymentAddr == nil {
+		return [32]byte{}, nil, newErr(ErrMissingPaymentAddr,
+			"multi-part payments require a payment address")
+	}
+
+	// Before we attempt to dispatch the payment, we'll record it within
+	// the payment store. This will fail if a payment to the same hash is
+	// already in flight, or has already succeeded.
+	info := &channeldb.PaymentCreationInfo{
+		PaymentHash:    payment.PaymentHash,
+		Value:          payment.Amount,
+		CreationDate:   time.Now(),
+		PaymentRequest: payment.PaymentRequest,
+	}
+	err := r.payments.InitPayment(payment.PaymentHash, info)
+	if err != nil {
+		return [32]byte{}, nil, err
+	}
+
+	// With the payment initiated, we'll hand it off to its lifecycle,
+	// which dispatches and tracks its shards until it either succeeds or
+	// fails.
+	return newPaymentLifecycle(r, payment).run()
+}
+
+// SendToRoute attempts to send a payment with the passed hash over the passed
+// route, which is typically constructed by the caller using BuildRoute. Unlike
+// SendPayment, only a single attempt is made. The payment is recorded within
+// the payment store like any other, and the outcome of the attempt is
+// reported to mission control. If the payment succeeds, then the preimage is
+// returned. Otherwise, a RouteError is returned which identifies the node
+// along the route that caused the payment to fail.
+func (r *ChannelRouter) SendToRoute(paymentHash [32]byte,
+	route *Route) ([32]byte, error) {
+
+	if len(route.Hops) == 0 {
+		return [32]byte{}, newErr(ErrNoRouteFound, "route has no hops")
+	}
+
+	log.Tracef("Dispatching payment %x over route: %v", paymentHash,
+		newLogClosure(func() string {
+			return spew.Sdump(route)
+		}),
 	)
 
-	// TODO(roasbeef): consult KSP cache before dispatching
+	// We'll generate the sphinx packet before recording the payment, so
+	// a malformed route doesn't leave the payment in flight.
+	onionBlob, circuit, err := generateSphinxPacket(route, paymentHash[:])
+	if err != nil {
+		return [32]byte{}, err
+	}
 
-	// Before attempting to perform a series of graph traversals to find
-	// the k-shortest paths to the destination, we'll first consult our
-	// path cache
-	rt := newRouteTuple(payment.Amount, payment.Target)
+	htlcAdd := &lnwire.UpdateAddHTLC{
+		Amount:      route.TotalAmount,
+		Expiry:      route.TotalTimeLock,
+		PaymentHash: paymentHash,
+	}
+	copy(htlcAdd.OnionBlob[:], onionBlob)
+
+	// Before dispatching the payment, we'll record it along with its
+	// single attempt within the payment store.
+	info := &channeldb.PaymentCreationInfo{
+		PaymentHash:  paymentHash,
+		Value:        route.TotalAmount - route.TotalFees,
+		CreationDate: time.Now(),
+	}
+	err = r.payments.InitPayment(paymentHash, info)
+	if err != nil {
+		return [32]byte{}, err
+	}
 
-	r.routeCacheMtx.RLock()
-	routes, ok := r.routeCache[rt]
-	r.routeCacheMtx.RUnlock()
+	attempt := &channeldb.HTLCAttemptInfo{
+		Route:       route.toDBRoute(),
+		AttemptTime: time.Now(),
+		SessionKey:  circuit.SessionKey,
+	}
+	err = r.payments.RegisterAttempt(paymentHash, attempt)
+	if err != nil {
+		r.failPayment(paymentHash, channeldb.FailureReasonError)
+		return [32]byte{}, err
+	}
 
-	// If we don't have a set of routes cached, we'll query the graph for a
-	// set of potential routes to the destination node that can support our
-	// payment amount. If no such routes can be found then an error will be
-	// returned.
-	if !ok {
-		freshRoutes, err := r.FindRoutes(payment.Target, payment.Amount)
+	firstHop := route.Hops[0].Channel.Node.PubKey
+	preimage, sendErr := r.cfg.SendToSwitch(firstHop, htlcAdd, circuit)
+	if sendErr == nil {
+		r.missionControl.reportSuccess(route)
+
+		err := r.payments.SettleAttempt(
+			paymentHash, attempt.AttemptID,
+			&channeldb.HTLCSettleInfo{
+				Preimage:   preimage,
+				SettleTime: time.Now(),
+			},
+		)
 		if err != nil {
-			return preImage, nil, err
+			log.Errorf("Unable to record settle of payment %x: %v",
+				paymentHash, err)
 		}
 
-		// Populate the cache with this set of fresh routes so we can
-		// reuse them in the future.
-		r.routeCacheMtx.Lock()
-		r.routeCache[rt] = freshRoutes
-		r.routeCacheMtx.Unlock()
+		return preimage, nil
+	}
 
-		routes = freshRoutes
+	// If the switch is shutting down, then the outcome of the payment is
+	// unknown, so we'll leave it in flight to be resumed on restart.
+	if sendErr == htlcswitch.ErrSwitchExiting {
+		return [32]byte{}, sendErr
 	}
 
-	// For each eligible path, we'll attempt to successfully send our
-	// target payment using the multi-hop route. We'll try each route
-	// serially until either once succeeds, or we've exhausted our set of
-	// available paths.
-	for _, route := range routes {
-		log.Tracef("Attempting to send payment %x, using route: %v",
-			payment.PaymentHash, newLogClosure(func() string {
-				return spew.Sdump(route)
-			}),
-		)
+	log.Errorf("Attempt to send payment %x over route failed: %v",
+		paymentHash, sendErr)
 
-		// Generate the raw encoded sphinx packet to be included along
-		// with the htlcAdd message that we send directly to the
-		// switch.
-		onionBlob, circuit, err := generateSphinxPacket(route,
-			payment.PaymentHash[:])
-		if err != nil {
-			return preImage, nil, err
+	err = r.payments.FailAttempt(
+		paymentHash, attempt.AttemptID, &channeldb.HTLCFailInfo{
+			FailTime: time.Now(),
+			Message:  sendErr.Error(),
+		},
+	)
+	if err != nil {
+		log.Errorf("Unable to record failure of payment %x: %v",
+			paymentHash, err)
+	}
+
+	failure := r.missionControl.reportFailure(route, sendErr)
+	r.failPayment(paymentHash, failure.reason)
+
+	routeErr := &RouteError{
+		Reason:           failure.reason,
+		FailureSourceIdx: failure.failureSourceIdx,
+		Err:              sendErr,
+	}
+	if fErr, ok := sendErr.(*htlcswitch.ForwardingError); ok {
+		routeErr.FailureMessage = fErr.FailureMessage
+	}
+
+	return [32]byte{}, routeErr
+}
+
+// SendCircularPayment sends a payment from our own node back to itself over
+// the passed circular route, which leaves over one of our channels and returns
+// over another, shifting the balance between the two by the amount of the
+// payment. As no invoice exists for such a payment, a random preimage is
+// generated and registered for the duration of the payment, which allows our
+// node to settle the payment once it arrives. As with SendToRoute, only a
+// single attempt is made, and the preimage is returned on success.
+func (r *ChannelRouter) SendCircularPayment(route *Route) ([32]byte, error) {
+	if r.cfg.AddCircularPayment == nil {
+		return [32]byte{}, errors.New("circular payments aren't " +
+			"supported")
+	}
+
+	numHops := len(route.Hops)
+	if numHops < 2 {
+		return [32]byte{}, newErr(ErrNoRouteFound, "circular route "+
+			"must have at least two hops")
+	}
+	firstHop, lastHop := route.Hops[0], route.Hops[numHops-1]
+	if !lastHop.Channel.Node.PubKey.IsEqual(r.selfNode.PubKey) {
+		return [32]byte{}, newErr(ErrNoRouteFound, "circular route "+
+			"must lead back to our own node")
+	}
+	if firstHop.Channel.ChannelID == lastHop.Channel.ChannelID {
+		return [32]byte{}, newErrf(ErrNoRouteFound, "circular route "+
+			"must return over a channel other than %v",
+			firstHop.Channel.ChannelID)
+	}
+
+	var preimage [32]byte
+	if _, err := rand.Read(preimage[:]); err != nil {
+		return [32]byte{}, err
+	}
+	paymentHash := sha256.Sum256(preimage[:])
+
+	// The preimage is only registered while the payment is being sent,
+	// so a payment that arrives after it has been given up on is failed.
+	removePreimage := r.cfg.AddCircularPayment(
+		preimage, lastHop.AmtToForward,
+	)
+	defer removePreimage()
+
+	log.Infof("Sending circular payment %x of %v over channels %v and %v",
+		paymentHash, lastHop.AmtToForward, firstHop.Channel.ChannelID,
+		lastHop.Channel.ChannelID)
+
+	return r.SendToRoute(paymentHash, route)
+}
+
+// failPayment marks the payment with the passed hash as failed within the
+// payment store, logging any error as the payment's outcome is reported to
+// the caller regardless.
+func (r *ChannelRouter) failPayment(paymentHash [32]byte,
+	reason channeldb.FailureReason) {
+
+	if err := r.payments.FailPayment(paymentHash, reason); err != nil {
+		log.Errorf("Unable to mark payment %x as failed: %v",
+			paymentHash, err)
+	}
+}
+
+// SubscribePayment returns a subscription to the state transitions of the
+// payment with the given hash, which allows the caller to track the progress
+// of a payment in real-time. An error is returned if no payment with the hash
+// exists.
+func (r *ChannelRouter) SubscribePayment(
+	paymentHash [32]byte) (*PaymentSubscription, error) {
+
+	return r.payments.subscribe(paymentHash)
+}
+
+// resumePayments launches a goroutine for each payment that was still in
+// flight when the router was last shut down. Each goroutine waits for the
+// outcome of the payment's outstanding HTLC attempts, and completes the
+// payment's record within the payment store accordingly.
+func (r *ChannelRouter) resumePayments() error {
+	payments, err := r.payments.FetchInFlightPayments()
+	if err != nil {
+		return err
+	}
+
+	for _, payment := range payments {
+		log.Infof("Resuming payment %x with %v attempt(s) in flight",
+			payment.Info.PaymentHash, len(payment.InFlightHTLCs()))
+
+		r.wg.Add(1)
+		go r.resumePayment(payment)
+	}
+
+	return nil
+}
+
+// attemptResult is the outcome of an HTLC attempt resumed after a restart.
+type attemptResult struct {
+	attempt  *channeldb.HTLCAttempt
+	preimage [32]byte
+	err      error
+}
+
+// resumePayment waits for the outcome of each in-flight HTLC attempt of the
+// passed payment, recording it within the payment store. Once every attempt
+// has been resolved, the payment is marked as failed unless one of its
+// attempts was settled. Failed attempts aren't retried, as the caller that
+// initiated the payment is no longer around to wait for it. If the switch
+// shuts down in the meantime, then the payment is left in flight, to be
+// resumed once again on the next start.
+//
+// NOTE: This MUST be run as a goroutine.
+func (r *ChannelRouter) resumePayment(payment *channeldb.MPPayment) {
+	defer r.wg.Done()
+
+	hash := payment.Info.PaymentHash
+	inFlight := payment.InFlightHTLCs()
+
+	results := make(chan *attemptResult, len(inFlight))
+	for i := range inFlight {
+		attempt := &inFlight[i]
+		go func() {
+			preimage, err := r.getAttemptResult(hash, attempt)
+			results <- &attemptResult{
+				attempt:  attempt,
+				preimage: preimage,
+				err:      err,
+			}
+		}()
+	}
+
+	var (
+		settled    bool
+		lastReason *channeldb.FailureReason
+	)
+	for range inFlight {
+		var result *attemptResult
+		select {
+		case result = <-results:
+		case <-r.quit:
+			return
 		}
 
-		// Craft an HTLC packet to send to the layer 2 switch. The
-		// metadata within this packet will be used to route the
-		// payment through the network, starting with the first-hop.
-		htlcAdd := &lnwire.UpdateAddHTLC{
-			Amount:      route.TotalAmount,
-			Expiry:      route.TotalTimeLock,
-			PaymentHash: payment.PaymentHash,
+		if result.err == htlcswitch.ErrSwitchExiting {
+			return
 		}
-		copy(htlcAdd.OnionBlob[:], onionBlob)
-
-		// Attempt to send this payment through the network to complete
-		// the payment. If this attempt fails, then we'll continue on
-		// to the next available route.
-		firstHop := route.Hops[0].Channel.Node.PubKey
-		preImage, sendError = r.cfg.SendToSwitch(firstHop, htlcAdd,
-			circuit)
-		if sendError != nil {
-			log.Errorf("Attempt to send payment %x failed: %v",
-				payment.PaymentHash, sendError)
+
+		attemptID := result.attempt.AttemptID
+		if result.err != nil {
+			log.Errorf("Resumed attempt %v of payment %x failed: %v",
+				attemptID, hash, result.err)
+
+			// We'll attribute the failure to the hops of the
+			// attempt's route, as with any other attempt.
+			reason := channeldb.FailureReasonError
+			route, err := newRouteFromDBRoute(&result.attempt.Route)
+			if err == nil {
+				failure := r.missionControl.reportFailure(
+					route, result.err,
+				)
+				reason = failure.reason
+			}
+			lastReason = &reason
+
+			err = r.payments.FailAttempt(
+				hash, attemptID, &channeldb.HTLCFailInfo{
+					FailTime: time.Now(),
+					Message:  result.err.Error(),
+				},
+			)
+			if err != nil {
+				log.Errorf("Unable to record failure of "+
+					"payment %x: %v", hash, err)
+			}
+
 			continue
 		}
 
-		return preImage, route, nil
+		log.Infof("Resumed attempt %v of payment %x succeeded",
+			attemptID, hash)
+
+		settled = true
+		err := r.payments.SettleAttempt(
+			hash, attemptID, &channeldb.HTLCSettleInfo{
+				Preimage:   result.preimage,
+				SettleTime: time.Now(),
+			},
+		)
+		if err != nil {
+			log.Errorf("Unable to record settle of payment %x: %v",
+				hash, err)
+		}
+	}
+
+	if settled {
+		return
+	}
+
+	// No attempt of the payment succeeded. If the payment had no attempts
+	// in flight, then we were shut down while it was being dispatched.
+	reason := channeldb.FailureReasonError
+	if lastReason != nil {
+		reason = *lastReason
+	}
+	r.failPayment(hash, reason)
+}
+
+// getAttemptResult blocks until the outcome of the passed HTLC attempt is
+// known, returning the preimage if the attempt was settled.
+func (r *ChannelRouter) getAttemptResult(paymentHash [32]byte,
+	attempt *channeldb.HTLCAttempt) ([32]byte, error) {
+
+	// Without the session key of the attempt's onion packet, we're unable
+	// to decrypt its failure, or to tell apart its outcome from that of
+	// any other attempt.
+	if attempt.SessionKey == nil {
+		return [32]byte{}, fmt.Errorf("attempt %v lacks the session "+
+			"key needed to resume it", attempt.AttemptID)
+	}
+
+	// We'll reconstruct the circuit of the attempt from its session key
+	// and the nodes along its route.
+	circuit := &sphinx.Circuit{
+		SessionKey:  attempt.SessionKey,
+		PaymentPath: make([]*btcec.PublicKey, len(attempt.Route.Hops)),
+	}
+	for i, hop := range attempt.Route.Hops {
+		pub, err := btcec.ParsePubKey(hop.PubKeyBytes[:], btcec.S256())
+		if err != nil {
+			return [32]byte{}, err
+		}
+		circuit.PaymentPath[i] = pub
 	}
 
-	// If we're unable to successfully make a payment using any of the
-	// routes we've found, then return an error.
-	return [32]byte{}, nil, sendError
+	return r.cfg.GetPaymentResult(
+		paymentHash, attempt.Route.TotalAmount, circuit,
+	)
+}
+
+// findPaymentRoutes returns the candidate routes able to carry amt to the
+// target, consulting the route cache before searching the graph. The returned
+// slice may be shared with the route cache, so it MUST NOT be modified.
+// Routes found with the help of route hints, or subject to the payment's
+// channel or last hop restrictions, are specific to the payment, so they
+// bypass the cache entirely. Every route returned abides by the fee and time
+// lock limits of the payment. The graph is searched within the snapshot of
+// the passed session.
+func (r *ChannelRouter) findPaymentRoutes(graph *graphSession,
+	payment *LightningPayment, amt lnwire.MilliAtom) ([]*Route, error) {
+
+	target := payment.Target
+	feeLimit := payment.feeLimit()
+	restrictions := &RouteRestrictions{
+		OutgoingChannelIDs: payment.OutgoingChannelIDs,
+		LastHop:            payment.LastHop,
+		FeeLimit:           &feeLimit,
+		CltvLimit:          payment.cltvLimit(),
+	}
+	if len(payment.RouteHints) != 0 ||
+		len(payment.OutgoingChannelIDs) != 0 || payment.LastHop != nil {
+
+		return r.findRoutes(graph, target, amt, payment.RouteHints,
+			restrictions)
+	}
+
+	// Before attempting to perform a series of graph traversals to find
+	// the k-shortest paths to the destination, we'll first consult our
+	// path cache.
+	rt := newRouteTuple(amt, target, feeLimit, restrictions.CltvLimit)
+
+	r.routeCacheMtx.RLock()
+	routes, ok := r.routeCache[rt]
+	r.routeCacheMtx.RUnlock()
+	if ok {
+		return routes, nil
+	}
+
+	// If we don't have a set of routes cached, we'll query the graph for a
+	// set of potential routes to the destination node that can support the
+	// amount. If no such routes can be found then an error will be
+	// returned.
+	routes, err := r.findRoutes(graph, target, amt, nil, restrictions)
+	if err != nil {
+		return nil, err
+	}
+
+	// Populate the cache with this set of fresh routes so we can reuse
+	// them in the future.
+	r.routeCacheMtx.Lock()
+	r.routeCache[rt] = routes
+	r.routeCacheMtx.Unlock()
+
+	return routes, nil
 }
 
 // AddNode is used to add information about a node to the router database. If
@@ -1174,6 +2116,14 @@ func (r *ChannelRouter) ForEachChannel(cb func(chanInfo *channeldb.ChannelEdgeIn
 	return r.cfg.Graph.ForEachChannel(cb)
 }
 
+// ZombieChannels returns the set of channel IDs of all channels which have
+// been marked as zombies due to a lack of recent updates.
+//
+// NOTE: This method is part of the ChannelGraphSource interface.
+func (r *ChannelRouter) ZombieChannels() (map[uint64]struct{}, error) {
+	return r.cfg.Graph.FetchZombieEdges(nil)
+}
+
 // AddProof updates the channel edge info with proof which is needed to
 // properly announce the edge to the rest of the network.
 //
@@ -1189,3 +2139,100 @@ func (r *ChannelRouter) AddProof(chanID lnwire.ShortChannelID,
 	info.AuthProof = proof
 	return r.cfg.Graph.UpdateChannelEdge(info)
 }
+
+// BlacklistNode adds the target node to the persistent node blacklist. Once
+// blacklisted, the node will never be used as an intermediate hop within a
+// route. If avoidAsDest is true, then we'll also refuse to find routes which
+// terminate at the node.
+func (r *ChannelRouter) BlacklistNode(pub *btcec.PublicKey,
+	avoidAsDest bool) error {
+
+	entry := &channeldb.BlacklistedNode{
+		AvoidAsDestination: avoidAsDest,
+	}
+	copy(entry.PubKey[:], pub.SerializeCompressed())
+
+	r.blacklistMtx.Lock()
+	defer r.blacklistMtx.Unlock()
+
+	if err := r.cfg.Graph.AddBlacklistedNode(entry); err != nil {
+		return err
+	}
+	r.blacklist[vertex(entry.PubKey)] = avoidAsDest
+
+	// As the set of eligible paths may have changed, we'll invalidate the
+	// route cache.
+	r.routeCacheMtx.Lock()
+	r.routeCache = make(map[routeTuple][]*Route)
+	r.routeCacheMtx.Unlock()
+
+	return nil
+}
+
+// UnblacklistNode removes the target node from the persistent node blacklist,
+// allowing it to be used once again during path finding.
+func (r *ChannelRouter) UnblacklistNode(pub *btcec.PublicKey) error {
+	r.blacklistMtx.Lock()
+	defer r.blacklistMtx.Unlock()
+
+	if err := r.cfg.Graph.RemoveBlacklistedNode(pub); err != nil {
+		return err
+	}
+	delete(r.blacklist, newVertex(pub))
+
+	// As the set of eligible paths may have changed, we'll invalidate the
+	// route cache.
+	r.routeCacheMtx.Lock()
+	r.routeCache = make(map[routeTuple][]*Route)
+	r.routeCacheMtx.Unlock()
+
+	return nil
+}
+
+// BlacklistedNodes returns the current contents of the node blacklist.
+func (r *ChannelRouter) BlacklistedNodes() []*channeldb.BlacklistedNode {
+	r.blacklistMtx.RLock()
+	defer r.blacklistMtx.RUnlock()
+
+	nodes := make([]*channeldb.BlacklistedNode, 0, len(r.blacklist))
+	for v, avoidAsDest := range r.blacklist {
+		nodes = append(nodes, &channeldb.BlacklistedNode{
+			PubKey:             [33]byte(v),
+			AvoidAsDestination: avoidAsDest,
+		})
+	}
+
+	return nodes
+}
+
+// QueryMissionControl returns the current mission control history, which
+// records the outcome of past payment attempts for each node and directed
+// pair of nodes.
+func (r *ChannelRouter) QueryMissionControl() []*channeldb.MissionControlEntry {
+	return r.missionControl.snapshot()
+}
+
+// ResetMissionControl wipes the mission control history, lifting all
+// penalties resulting from past payment failures.
+func (r *ChannelRouter) ResetMissionControl() error {
+	return r.missionControl.reset()
+}
+
+// QueryProbability returns the estimated probability of a payment carrying
+// amt being successfully forwarded from one node to another, along with the
+// history of the pair and of the destination node that the estimate is based
+// on.
+func (r *ChannelRouter) QueryProbability(from, to [33]byte,
+	amt lnwire.MilliAtom) *ProbabilityEstimate {
+
+	return r.missionControl.estimate(vertex(from), vertex(to), amt)
+}
+
+// ImportMissionControl seeds the mission control history with the passed
+// entries, typically obtained from another node. Imported observations only
+// replace our own if they're more recent.
+func (r *ChannelRouter) ImportMissionControl(
+	entries []*channeldb.MissionControlEntry) error {
+
+	return r.missionControl.importEntries(entries)
+}
@@ -2,13 +2,17 @@ package routing
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"errors"
 	"fmt"
 	"image/color"
+	"math"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/htlcswitch"
 	"github.com/roasbeef/btcd/wire"
 
 	"github.com/davecgh/go-spew/spew"
@@ -27,6 +31,8 @@ type testCtx struct {
 	chain *mockChain
 
 	chainView *mockChainView
+
+	payments *mockPaymentStore
 }
 
 func createTestCtx(startingHeight uint32, testGraph ...string) (*testCtx, func(), error) {
@@ -76,7 +82,8 @@ func createTestCtx(startingHeight uint32, testGraph ...string) (*testCtx, func()
 	// any p2p functionality, the peer send and switch send messages won't
 	// be populated.
 	chain := newMockChain(startingHeight)
-	chainView := newMockChainView()
+	chainView := newMockChainView(chain)
+	payments := newMockPaymentStore()
 	router, err := New(Config{
 		Graph:     graph,
 		Chain:     chain,
@@ -85,6 +92,7 @@ func createTestCtx(startingHeight uint32, testGraph ...string) (*testCtx, func()
 			_ *lnwire.UpdateAddHTLC, _ *sphinx.Circuit) ([32]byte, error) {
 			return [32]byte{}, nil
 		},
+		Payments: payments,
 	})
 	if err != nil {
 		return nil, nil, fmt.Errorf("unable to create router %v", err)
@@ -104,6 +112,7 @@ func createTestCtx(startingHeight uint32, testGraph ...string) (*testCtx, func()
 		aliases:   aliasMap,
 		chain:     chain,
 		chainView: chainView,
+		payments:  payments,
 	}, cleanUp, nil
 }
 
@@ -127,7 +136,7 @@ func TestFindRoutesFeeSorting(t *testing.T) {
 	// Execute a query for all possible routes between roasbeef and luo ji.
 	paymentAmt := lnwire.NewMSatFromSatoshis(100)
 	target := ctx.aliases["luoji"]
-	routes, err := ctx.router.FindRoutes(target, paymentAmt)
+	routes, err := ctx.router.FindRoutes(target, paymentAmt, nil, nil)
 	if err != nil {
 		t.Fatalf("unable to find any routes: %v", err)
 	}
@@ -211,66 +220,1645 @@ func TestSendPaymentRouteFailureFallback(t *testing.T) {
 			"instead passes through: %v",
 			route.Hops[0].Channel.Node.Alias)
 	}
+
+	// Both attempts should have been recorded within the payment store:
+	// the first as failed, and the second as settled.
+	dbPayment := ctx.payments.payments[payHash]
+	if dbPayment.Status != channeldb.StatusSucceeded {
+		t.Fatalf("payment should have succeeded, instead status is %v",
+			dbPayment.Status)
+	}
+	if len(dbPayment.HTLCs) != 2 {
+		t.Fatalf("expected 2 htlc attempts, instead have %v",
+			len(dbPayment.HTLCs))
+	}
+	if dbPayment.HTLCs[0].Failure == nil {
+		t.Fatalf("first attempt should have failed")
+	}
+	if dbPayment.HTLCs[1].Settle == nil ||
+		dbPayment.HTLCs[1].Settle.Preimage != preImage {
+
+		t.Fatalf("second attempt should have settled")
+	}
+
+	// As the payment has succeeded, a second attempt to pay the same
+	// payment hash should be rejected.
+	_, _, err = ctx.router.SendPayment(&payment)
+	if err != channeldb.ErrAlreadyPaid {
+		t.Fatalf("expected ErrAlreadyPaid, instead got: %v", err)
+	}
 }
 
-// TestAddProof checks that we can update the channel proof after channel
-// info was added to the database.
-func TestAddProof(t *testing.T) {
+// TestSendPaymentFailureReasons asserts that the reason a payment failed is
+// derived from the failures of its attempts, recorded within the payment
+// store, and returned to the caller.
+func TestSendPaymentFailureReasons(t *testing.T) {
 	t.Parallel()
 
-	ctx, cleanup, err := createTestCtx(0)
+	tests := []struct {
+		name        string
+		target      string
+		feeLimit    lnwire.MilliAtom
+		sendErr     error
+		reason      channeldb.FailureReason
+		numAttempts int
+	}{
+		{
+			// Every route fails as we're not connected to its
+			// first hop.
+			name:   "peer offline",
+			target: "luoji",
+			sendErr: &htlcswitch.ForwardingError{
+				FailureCode:  lnwire.CodeUnknownNextPeer,
+				LocalFailure: true,
+			},
+			reason:      channeldb.FailureReasonPeerOffline,
+			numAttempts: 2,
+		},
+		{
+			// Every route fails as we lack the balance to send
+			// the payment over the first hop.
+			name:   "insufficient balance",
+			target: "luoji",
+			sendErr: &htlcswitch.ForwardingError{
+				FailureCode:  lnwire.CodeTemporaryChannelFailure,
+				LocalFailure: true,
+			},
+			reason:      channeldb.FailureReasonInsufficientBalance,
+			numAttempts: 2,
+		},
+		{
+			// The destination rejects the payment hash, so no
+			// other route should be attempted.
+			name:   "incorrect payment details",
+			target: "luoji",
+			sendErr: &htlcswitch.ForwardingError{
+				FailureCode: lnwire.CodeUnknownPaymentHash,
+			},
+			reason:      channeldb.FailureReasonIncorrectPaymentDetails,
+			numAttempts: 1,
+		},
+		{
+			// Every route fails at a remote hop.
+			name:   "no route",
+			target: "luoji",
+			sendErr: &htlcswitch.ForwardingError{
+				FailureCode: lnwire.CodeTemporaryChannelFailure,
+			},
+			reason:      channeldb.FailureReasonNoRoute,
+			numAttempts: 2,
+		},
+		{
+			// The only route to sophon requires a fee exceeding
+			// the fee limit, so it's never attempted.
+			name:        "fee limit",
+			target:      "sophon",
+			feeLimit:    1,
+			reason:      channeldb.FailureReasonFeeLimit,
+			numAttempts: 0,
+		},
+	}
+
+	for _, test := range tests {
+		const startingBlockHeight = 101
+		ctx, cleanUp, err := createTestCtx(
+			startingBlockHeight, basicGraphFilePath,
+		)
+		if err != nil {
+			cleanUp()
+			t.Fatalf("unable to create router: %v", err)
+		}
+
+		ctx.router.cfg.SendToSwitch = func(_ *btcec.PublicKey,
+			_ *lnwire.UpdateAddHTLC, _ *sphinx.Circuit) ([32]byte, error) {
+
+			return [32]byte{}, test.sendErr
+		}
+
+		var payHash [32]byte
+		payment := LightningPayment{
+			Target:      ctx.aliases[test.target],
+			Amount:      lnwire.NewMSatFromSatoshis(100),
+			PaymentHash: payHash,
+			FeeLimit:    test.feeLimit,
+		}
+		_, _, err = ctx.router.SendPayment(&payment)
+		cleanUp()
+
+		pErr, ok := err.(*PaymentError)
+		if !ok {
+			t.Fatalf("%v: expected PaymentError, got: %v",
+				test.name, err)
+		}
+		if pErr.Reason != test.reason {
+			t.Fatalf("%v: expected reason %v, got %v", test.name,
+				test.reason, pErr.Reason)
+		}
+
+		dbPayment := ctx.payments.payments[payHash]
+		if dbPayment.FailureReason == nil ||
+			*dbPayment.FailureReason != test.reason {
+
+			t.Fatalf("%v: expected recorded reason %v, got %v",
+				test.name, test.reason, dbPayment.FailureReason)
+		}
+		if len(dbPayment.HTLCs) != test.numAttempts {
+			t.Fatalf("%v: expected %v attempts, got %v", test.name,
+				test.numAttempts, len(dbPayment.HTLCs))
+		}
+	}
+}
+
+// TestFindRoutesNodeBlacklist asserts that nodes within the router's node
+// blacklist are never used as intermediate hops, and that a node blacklisted
+// as a destination can't be routed to at all.
+func TestFindRoutesNodeBlacklist(t *testing.T) {
+	t.Parallel()
+
+	const startingBlockHeight = 101
+	ctx, cleanUp, err := createTestCtx(startingBlockHeight, basicGraphFilePath)
+	defer cleanUp()
 	if err != nil {
-		t.Fatal(err)
+		t.Fatalf("unable to create router: %v", err)
 	}
-	defer cleanup()
 
-	// Before creating out edge, we'll create two new nodes within the
-	// network that the channel will connect.
-	node1, err := createTestNode()
+	// We'll blacklist satoshi, which should remove the two hop route from
+	// roasbeef to luo ji, leaving only the direct route.
+	if err := ctx.router.BlacklistNode(ctx.aliases["satoshi"], false); err != nil {
+		t.Fatalf("unable to blacklist node: %v", err)
+	}
+
+	paymentAmt := lnwire.NewMSatFromSatoshis(100)
+	target := ctx.aliases["luoji"]
+	routes, err := ctx.router.FindRoutes(target, paymentAmt, nil, nil)
 	if err != nil {
-		t.Fatal(err)
+		t.Fatalf("unable to find any routes: %v", err)
 	}
-	node2, err := createTestNode()
+	if len(routes) != 1 {
+		t.Fatalf("1 route should've been selected, instead %v were: %v",
+			len(routes), spew.Sdump(routes))
+	}
+	if len(routes[0].Hops) != 1 {
+		t.Fatalf("expected direct route, instead route has %v hops",
+			len(routes[0].Hops))
+	}
+
+	// Blacklisting a node without avoiding it as a destination should
+	// still allow us to route to it directly.
+	if err := ctx.router.BlacklistNode(target, false); err != nil {
+		t.Fatalf("unable to blacklist node: %v", err)
+	}
+	_, err = ctx.router.FindRoutes(target, paymentAmt, nil, nil)
 	if err != nil {
-		t.Fatal(err)
+		t.Fatalf("unable to find any routes: %v", err)
 	}
 
-	// In order to be able to add the edge we should have a valid funding
-	// UTXO within the blockchain.
-	fundingTx, _, chanID, err := createChannelEdge(ctx,
-		bitcoinKey1.SerializeCompressed(), bitcoinKey2.SerializeCompressed(),
-		100, 0)
+	// If we instead avoid luo ji as a destination, then path finding
+	// should fail outright.
+	if err := ctx.router.BlacklistNode(target, true); err != nil {
+		t.Fatalf("unable to blacklist node: %v", err)
+	}
+	_, err = ctx.router.FindRoutes(target, paymentAmt, nil, nil)
+	if !IsError(err, ErrTargetBlacklisted) {
+		t.Fatalf("expected ErrTargetBlacklisted, instead got: %v", err)
+	}
+
+	// Finally, once both nodes are removed from the blacklist, both routes
+	// should be found once again.
+	if err := ctx.router.UnblacklistNode(target); err != nil {
+		t.Fatalf("unable to unblacklist node: %v", err)
+	}
+	if err := ctx.router.UnblacklistNode(ctx.aliases["satoshi"]); err != nil {
+		t.Fatalf("unable to unblacklist node: %v", err)
+	}
+	if len(ctx.router.BlacklistedNodes()) != 0 {
+		t.Fatalf("blacklist should be empty")
+	}
+	routes, err = ctx.router.FindRoutes(target, paymentAmt, nil, nil)
 	if err != nil {
-		t.Fatalf("unable create channel edge: %v", err)
+		t.Fatalf("unable to find any routes: %v", err)
 	}
-	fundingBlock := &wire.MsgBlock{
-		Transactions: []*wire.MsgTx{fundingTx},
+	if len(routes) != 2 {
+		t.Fatalf("2 routes should've been selected, instead %v were: %v",
+			len(routes), spew.Sdump(routes))
 	}
-	ctx.chain.addBlock(fundingBlock, chanID.BlockHeight)
+}
 
-	// After utxo was recreated adding the edge without the proof.
-	edge := &channeldb.ChannelEdgeInfo{
-		ChannelID:   chanID.ToUint64(),
-		NodeKey1:    node1.PubKey,
-		NodeKey2:    node2.PubKey,
-		BitcoinKey1: bitcoinKey1,
-		BitcoinKey2: bitcoinKey2,
-		AuthProof:   nil,
+// TestFindRoutesRestrictions asserts that routes returned by FindRoutes abide
+// by the outgoing channel, last hop, limit and ignore restrictions passed to
+// it.
+func TestFindRoutesRestrictions(t *testing.T) {
+	t.Parallel()
+
+	const startingBlockHeight = 101
+	ctx, cleanUp, err := createTestCtx(startingBlockHeight, basicGraphFilePath)
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to create router: %v", err)
 	}
 
-	if err := ctx.router.AddEdge(edge); err != nil {
-		t.Fatalf("unable to add edge: %v", err)
+	// Luo ji can be reached either directly, or through satoshi.
+	const (
+		luojiChanID   = 689530843
+		satoshiChanID = 2340213491
+		lastHopChanID = 523452362
+	)
+	paymentAmt := lnwire.NewMSatFromSatoshis(100)
+	target := ctx.aliases["luoji"]
+
+	// Restricting the first hop to our channel with satoshi should only
+	// leave the route through satoshi.
+	routes, err := ctx.router.FindRoutes(target, paymentAmt, nil,
+		&RouteRestrictions{
+			OutgoingChannelIDs: []uint64{satoshiChanID},
+		},
+	)
+	if err != nil {
+		t.Fatalf("unable to find any routes: %v", err)
+	}
+	for _, route := range routes {
+		if route.Hops[0].Channel.ChannelID != satoshiChanID {
+			t.Fatalf("route should leave over channel %v: %v",
+				satoshiChanID, spew.Sdump(route))
+		}
 	}
 
-	// Now we'll attempt to update the proof and check that it has been
-	// properly updated.
-	if err := ctx.router.AddProof(*chanID, &testAuthProof); err != nil {
-		t.Fatalf("unable to add proof: %v", err)
+	// Requiring satoshi as the last hop should have the same effect.
+	routes, err = ctx.router.FindRoutes(target, paymentAmt, nil,
+		&RouteRestrictions{
+			LastHop: ctx.aliases["satoshi"],
+		},
+	)
+	if err != nil {
+		t.Fatalf("unable to find any routes: %v", err)
+	}
+	for _, route := range routes {
+		lastHop := route.Hops[len(route.Hops)-1]
+		if lastHop.Channel.ChannelID != lastHopChanID {
+			t.Fatalf("route should arrive over channel %v: %v",
+				lastHopChanID, spew.Sdump(route))
+		}
 	}
 
-	info, _, _, err := ctx.router.GetChannelByID(*chanID)
-	if info.AuthProof == nil {
-		t.Fatal("proof have been updated")
+	// Limiting either the fee or the time lock of the routes should only
+	// leave the direct route, as it has no intermediate hop charging a
+	// fee, and a total time lock delta of a single block. A zero fee limit
+	// only allows routes without any fee, rather than lifting the limit.
+	var zeroFeeLimit, feeLimit lnwire.MilliAtom = 0, 1
+	for _, restrictions := range []*RouteRestrictions{
+		{FeeLimit: &zeroFeeLimit},
+		{FeeLimit: &feeLimit},
+		{CltvLimit: 1},
+	} {
+		routes, err = ctx.router.FindRoutes(
+			target, paymentAmt, nil, restrictions,
+		)
+		if err != nil {
+			t.Fatalf("unable to find any routes: %v", err)
+		}
+		if len(routes) != 1 || len(routes[0].Hops) != 1 {
+			t.Fatalf("expected only the direct route, instead "+
+				"found: %v", spew.Sdump(routes))
+		}
+	}
+
+	// Ignoring satoshi, or the direction of our channel with luo ji that
+	// leads to it, should leave only the direct route, or only the route
+	// through satoshi respectively.
+	self := newVertex(ctx.router.selfNode.PubKey)
+	directEdge := newEdgeLocator(luojiChanID, self, newVertex(target))
+	for _, test := range []struct {
+		restrictions *RouteRestrictions
+		expHops      int
+	}{
+		{
+			restrictions: &RouteRestrictions{
+				IgnoredNodes: []*btcec.PublicKey{
+					ctx.aliases["satoshi"],
+				},
+			},
+			expHops: 1,
+		},
+		{
+			restrictions: &RouteRestrictions{
+				IgnoredEdges: map[EdgeLocator]struct{}{
+					directEdge: {},
+				},
+			},
+			expHops: 2,
+		},
+	} {
+		routes, err = ctx.router.FindRoutes(
+			target, paymentAmt, nil, test.restrictions,
+		)
+		if err != nil {
+			t.Fatalf("unable to find any routes: %v", err)
+		}
+		if len(routes) != 1 || len(routes[0].Hops) != test.expHops {
+			t.Fatalf("expected a single route of %v hops, "+
+				"instead found: %v", test.expHops,
+				spew.Sdump(routes))
+		}
+	}
+
+	// Ignoring the target itself leaves no route at all.
+	_, err = ctx.router.FindRoutes(target, paymentAmt, nil,
+		&RouteRestrictions{
+			IgnoredNodes: []*btcec.PublicKey{target},
+		},
+	)
+	if !IsError(err, ErrNoPathFound) {
+		t.Fatalf("expected ErrNoPathFound, instead got: %v", err)
+	}
+
+	// Finally, leaving over our direct channel with luo ji while
+	// arriving through satoshi is impossible.
+	_, err = ctx.router.FindRoutes(target, paymentAmt, nil,
+		&RouteRestrictions{
+			OutgoingChannelIDs: []uint64{luojiChanID},
+			LastHop:            ctx.aliases["satoshi"],
+		},
+	)
+	if err == nil {
+		t.Fatalf("no route should have been found")
+	}
+}
+
+// TestPaymentFeeLimit asserts that the fee limit of a payment is derived from
+// its absolute and percentage limits, defaulting to the amount of the
+// payment.
+func TestPaymentFeeLimit(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		feeLimit        lnwire.MilliAtom
+		feeLimitPercent uint32
+		amount          lnwire.MilliAtom
+		expected        lnwire.MilliAtom
+	}{
+		{expected: 100000},
+		{feeLimit: 500, expected: 500},
+		{feeLimitPercent: 2, expected: 2000},
+		{feeLimit: 500, feeLimitPercent: 2, expected: 500},
+		{feeLimit: 5000, feeLimitPercent: 2, expected: 2000},
+
+		// A percentage limit may round down to zero, which only
+		// allows routes without any fee.
+		{feeLimitPercent: 1, amount: 99, expected: 0},
+
+		// Large percentages of large amounts saturate rather than
+		// overflow.
+		{
+			feeLimitPercent: math.MaxUint32,
+			amount:          math.MaxUint64 / 2,
+			expected:        math.MaxUint64,
+		},
+	}
+
+	for i, test := range tests {
+		amount := test.amount
+		if amount == 0 {
+			amount = 100000
+		}
+		payment := LightningPayment{
+			Amount:          amount,
+			FeeLimit:        test.feeLimit,
+			FeeLimitPercent: test.feeLimitPercent,
+		}
+		if limit := payment.feeLimit(); limit != test.expected {
+			t.Fatalf("test #%v: expected fee limit %v, got %v", i,
+				test.expected, limit)
+		}
+	}
+}
+
+// TestSendPaymentMissionControl asserts that a channel which caused a payment
+// to fail is avoided by subsequent payments, and that the failure is recorded
+// persistently.
+func TestSendPaymentMissionControl(t *testing.T) {
+	t.Parallel()
+
+	const startingBlockHeight = 101
+	ctx, cleanUp, err := createTestCtx(startingBlockHeight, basicGraphFilePath)
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to create router: %v", err)
+	}
+
+	mcCfg := MissionControlConfig{
+		PenaltyHalfLife:       DefaultPenaltyHalfLife,
+		AprioriHopProbability: DefaultAprioriHopProbability,
+	}
+	ctx.router.missionControl.cfg = mcCfg
+	ctx.router.cfg.PathFinding = PathFindingConfig{
+		AttemptCost:    DefaultAttemptCost,
+		MinProbability: DefaultMinRouteProbability,
+	}
+
+	// Our channel with luo ji will be unable to carry any payments, while
+	// the route through satoshi succeeds. We'll record the first hop of
+	// every attempt.
+	var firstHops []string
+	ctx.router.cfg.SendToSwitch = func(n *btcec.PublicKey,
+		_ *lnwire.UpdateAddHTLC, _ *sphinx.Circuit) ([32]byte, error) {
+
+		firstHop := "satoshi"
+		if ctx.aliases["luoji"].IsEqual(n) {
+			firstHop = "luoji"
+		}
+		firstHops = append(firstHops, firstHop)
+
+		if firstHop == "luoji" {
+			return [32]byte{}, &htlcswitch.ForwardingError{
+				FailureCode:  lnwire.CodeTemporaryChannelFailure,
+				LocalFailure: true,
+			}
+		}
+
+		return [32]byte{1}, nil
+	}
+
+	// The first payment should attempt the cheaper direct route before
+	// falling back to the route through satoshi.
+	payment := LightningPayment{
+		Target:      ctx.aliases["luoji"],
+		Amount:      lnwire.NewMSatFromSatoshis(1000),
+		PaymentHash: [32]byte{1},
+	}
+	if _, _, err := ctx.router.SendPayment(&payment); err != nil {
+		t.Fatalf("unable to send payment: %v", err)
+	}
+	if len(firstHops) != 2 || firstHops[0] != "luoji" {
+		t.Fatalf("expected direct route to be attempted first, "+
+			"instead attempted: %v", firstHops)
+	}
+
+	// As the direct route is now unlikely to succeed, a second payment of
+	// the same amount should go through satoshi right away.
+	firstHops = nil
+	payment.PaymentHash = [32]byte{2}
+	if _, _, err := ctx.router.SendPayment(&payment); err != nil {
+		t.Fatalf("unable to send payment: %v", err)
+	}
+	if len(firstHops) != 1 || firstHops[0] != "satoshi" {
+		t.Fatalf("expected only the route through satoshi to be "+
+			"attempted, instead attempted: %v", firstHops)
+	}
+
+	// The successful pair should now be considered very likely to carry
+	// the same amount again.
+	from := newVertex(ctx.router.selfNode.PubKey)
+	satoshi := newVertex(ctx.aliases["satoshi"])
+	mc := ctx.router.missionControl
+	probability := mc.edgeProbability(from, satoshi, payment.Amount)
+	if probability != prevSuccessProbability {
+		t.Fatalf("expected probability %v after success, instead "+
+			"have %v", prevSuccessProbability, probability)
+	}
+
+	// The failure should survive a restart, and the probability of the
+	// failed pair should recover over time.
+	mc, err = newMissionControl(
+		ctx.graph, newVertex(ctx.router.selfNode.PubKey), mcCfg,
+	)
+	if err != nil {
+		t.Fatalf("unable to create mission control: %v", err)
+	}
+	to := newVertex(ctx.aliases["luoji"])
+	probability = mc.edgeProbability(from, to, payment.Amount)
+	if probability >= DefaultAprioriHopProbability/2 {
+		t.Fatalf("unexpected probability after restart: %v",
+			probability)
+	}
+
+	mc.now = func() time.Time {
+		return time.Now().Add(DefaultPenaltyHalfLife)
+	}
+	recovered := mc.edgeProbability(from, to, payment.Amount)
+	if math.Abs(recovered-DefaultAprioriHopProbability/2) > 0.01 {
+		t.Fatalf("expected probability to recover to %v, instead "+
+			"have %v", DefaultAprioriHopProbability/2, recovered)
+	}
+
+	// Smaller payments shouldn't be affected by the failure of the pair.
+	probability = mc.edgeProbability(from, to, payment.Amount/2)
+	if probability != DefaultAprioriHopProbability {
+		t.Fatalf("expected apriori probability for smaller amounts, "+
+			"instead have %v", probability)
+	}
+}
+
+// TestSendPaymentMultiPart asserts that a payment which can't be carried by
+// any single route is split into multiple shards, each carrying the payment
+// address and the total amount of the payment, and that the payment only
+// succeeds once the receiver has accepted its full amount.
+func TestSendPaymentMultiPart(t *testing.T) {
+	t.Parallel()
+
+	const startingBlockHeight = 101
+	ctx, cleanUp, err := createTestCtx(startingBlockHeight, basicGraphFilePath)
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to create router: %v", err)
+	}
+
+	var (
+		preimage    = [32]byte{9}
+		paymentAddr = [32]byte{7}
+		amt         = lnwire.NewMSatFromSatoshis(1000)
+		maxHTLC     = lnwire.NewMSatFromSatoshis(600)
+	)
+
+	// Our channels are unable to carry HTLCs larger than maxHTLC. The
+	// receiver holds on to the HTLCs it accepts until their sum covers the
+	// full amount of the payment, and only then reveals the preimage.
+	var (
+		mtx      sync.Mutex
+		received lnwire.MilliAtom
+		release  = make(chan struct{})
+	)
+	ctx.router.cfg.SendToSwitch = func(_ *btcec.PublicKey,
+		htlc *lnwire.UpdateAddHTLC, _ *sphinx.Circuit) ([32]byte, error) {
+
+		if htlc.Amount > maxHTLC {
+			return [32]byte{}, &htlcswitch.ForwardingError{
+				FailureCode:  lnwire.CodeTemporaryChannelFailure,
+				LocalFailure: true,
+			}
+		}
+
+		mtx.Lock()
+		complete := received >= amt
+		received += htlc.Amount
+		if !complete && received >= amt {
+			close(release)
+		}
+		mtx.Unlock()
+
+		select {
+		case <-release:
+			return preimage, nil
+		case <-time.After(5 * time.Second):
+			return [32]byte{}, errors.New("payment incomplete")
+		}
+	}
+
+	// A payment that may be split must carry a payment address.
+	payment := LightningPayment{
+		Target:      ctx.aliases["luoji"],
+		Amount:      amt,
+		PaymentHash: [32]byte{1},
+		MaxParts:    4,
+	}
+	_, _, err = ctx.router.SendPayment(&payment)
+	if !IsError(err, ErrMissingPaymentAddr) {
+		t.Fatalf("expected ErrMissingPaymentAddr, instead got: %v", err)
+	}
+
+	payment.PaymentAddr = &paymentAddr
+	paymentPreimage, route, err := ctx.router.SendPayment(&payment)
+	if err != nil {
+		t.Fatalf("unable to send payment: %v", err)
+	}
+	if paymentPreimage != preimage {
+		t.Fatalf("incorrect preimage: expected %x, got %x", preimage,
+			paymentPreimage)
+	}
+
+	// The final hop of the route should carry the payment address along
+	// with the total amount of the payment.
+	mpp := route.Hops[len(route.Hops)-1].MPP
+	if mpp == nil || mpp.PaymentSecret != paymentAddr ||
+		mpp.TotalMsat != amt {
+
+		t.Fatalf("unexpected payment data: %v", spew.Sdump(mpp))
+	}
+
+	// Both routes should have failed to carry the full amount, after
+	// which the payment should have been split into two settled shards.
+	dbPayment := ctx.payments.payments[payment.PaymentHash]
+	if dbPayment.Status != channeldb.StatusSucceeded {
+		t.Fatalf("payment should have succeeded, instead status is %v",
+			dbPayment.Status)
+	}
+
+	var (
+		numFailed  int
+		settledAmt lnwire.MilliAtom
+	)
+	for _, htlc := range dbPayment.HTLCs {
+		switch {
+		case htlc.Failure != nil:
+			numFailed++
+		case htlc.Settle != nil:
+			settledAmt += htlc.Route.TotalAmount -
+				htlc.Route.TotalFees
+		default:
+			t.Fatalf("htlc attempt %v still in flight",
+				htlc.AttemptID)
+		}
+	}
+	if numFailed != 2 {
+		t.Fatalf("expected 2 failed attempts, instead have %v",
+			numFailed)
+	}
+	if settledAmt != amt {
+		t.Fatalf("expected settled shards to deliver %v, instead "+
+			"they deliver %v", amt, settledAmt)
+	}
+}
+
+// TestMissionControlImportReset checks that imported mission control history
+// only overrides our own observations if it's more recent, and that resetting
+// mission control wipes the history.
+func TestMissionControlImportReset(t *testing.T) {
+	t.Parallel()
+
+	const startingBlockHeight = 101
+	ctx, cleanUp, err := createTestCtx(startingBlockHeight, basicGraphFilePath)
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to create router: %v", err)
+	}
+
+	ctx.router.missionControl.cfg = MissionControlConfig{
+		PenaltyHalfLife:       DefaultPenaltyHalfLife,
+		AprioriHopProbability: DefaultAprioriHopProbability,
+	}
+	mc := ctx.router.missionControl
+
+	from := newVertex(ctx.router.selfNode.PubKey)
+	to := newVertex(ctx.aliases["luoji"])
+	amt := lnwire.NewMSatFromSatoshis(1000)
+
+	// Importing a recent failure of the pair should lower its probability.
+	now := time.Now()
+	err = ctx.router.ImportMissionControl([]*channeldb.MissionControlEntry{{
+		From:        from,
+		To:          to,
+		LastFail:    now,
+		LastFailAmt: amt,
+	}})
+	if err != nil {
+		t.Fatalf("unable to import mission control: %v", err)
+	}
+	if mc.edgeProbability(from, to, amt) >= DefaultAprioriHopProbability {
+		t.Fatalf("expected imported failure to lower the probability")
+	}
+
+	// An older success shouldn't restore the probability, while a newer
+	// one should.
+	err = ctx.router.ImportMissionControl([]*channeldb.MissionControlEntry{{
+		From:        from,
+		To:          to,
+		LastSuccess: now.Add(-time.Minute),
+	}})
+	if err != nil {
+		t.Fatalf("unable to import mission control: %v", err)
+	}
+	if mc.edgeProbability(from, to, amt) >= DefaultAprioriHopProbability {
+		t.Fatalf("expected stale success to be ignored")
+	}
+
+	err = ctx.router.ImportMissionControl([]*channeldb.MissionControlEntry{{
+		From:        from,
+		To:          to,
+		LastSuccess: now.Add(time.Minute),
+	}})
+	if err != nil {
+		t.Fatalf("unable to import mission control: %v", err)
+	}
+	if mc.edgeProbability(from, to, amt) != DefaultAprioriHopProbability {
+		t.Fatalf("expected newer success to restore the probability")
+	}
+
+	// The imported history should be reported by a query, and survive a
+	// restart.
+	entries := ctx.router.QueryMissionControl()
+	if len(entries) != 1 || entries[0].LastFailAmt != amt {
+		t.Fatalf("unexpected mission control entries: %v",
+			spew.Sdump(entries))
+	}
+	stored, err := ctx.graph.FetchMissionControlEntries()
+	if err != nil {
+		t.Fatalf("unable to fetch mission control entries: %v", err)
+	}
+	if len(stored) != 1 {
+		t.Fatalf("expected 1 stored entry, instead have %v", len(stored))
+	}
+
+	// Finally, resetting mission control should wipe the history, both in
+	// memory and on disk.
+	if err := ctx.router.ResetMissionControl(); err != nil {
+		t.Fatalf("unable to reset mission control: %v", err)
+	}
+	if entries := ctx.router.QueryMissionControl(); len(entries) != 0 {
+		t.Fatalf("expected empty history after reset, instead have %v",
+			len(entries))
+	}
+	stored, err = ctx.graph.FetchMissionControlEntries()
+	if err != nil {
+		t.Fatalf("unable to fetch mission control entries: %v", err)
+	}
+	if len(stored) != 0 {
+		t.Fatalf("expected no stored entries after reset, instead "+
+			"have %v", len(stored))
+	}
+}
+
+// TestQueryProbability checks that the probability estimate of a pair of nodes
+// is returned along with the history it's based on.
+func TestQueryProbability(t *testing.T) {
+	t.Parallel()
+
+	const startingBlockHeight = 101
+	ctx, cleanUp, err := createTestCtx(startingBlockHeight, basicGraphFilePath)
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to create router: %v", err)
+	}
+
+	ctx.router.missionControl.cfg = MissionControlConfig{
+		PenaltyHalfLife:       DefaultPenaltyHalfLife,
+		AprioriHopProbability: DefaultAprioriHopProbability,
+	}
+
+	from := newVertex(ctx.aliases["roasbeef"])
+	to := newVertex(ctx.aliases["luoji"])
+	amt := lnwire.NewMSatFromSatoshis(1000)
+
+	// Without any history, the apriori probability should be returned.
+	estimate := ctx.router.QueryProbability(from, to, amt)
+	if estimate.Probability != DefaultAprioriHopProbability {
+		t.Fatalf("expected apriori probability, got %v",
+			estimate.Probability)
+	}
+	if estimate.PairHistory != nil || estimate.NodeHistory != nil {
+		t.Fatalf("expected no history, got %v", spew.Sdump(estimate))
+	}
+
+	// After a recent failure of the destination node, the probability
+	// should be lowered, and the node history should be returned.
+	err = ctx.router.ImportMissionControl([]*channeldb.MissionControlEntry{{
+		From:     to,
+		LastFail: time.Now(),
+	}})
+	if err != nil {
+		t.Fatalf("unable to import mission control: %v", err)
+	}
+
+	estimate = ctx.router.QueryProbability(from, to, amt)
+	if estimate.Probability >= DefaultAprioriHopProbability {
+		t.Fatalf("expected node failure to lower the probability")
+	}
+	if estimate.PairHistory != nil {
+		t.Fatalf("expected no pair history, got %v",
+			spew.Sdump(estimate.PairHistory))
+	}
+	if estimate.NodeHistory == nil ||
+		estimate.NodeHistory.From != to ||
+		estimate.NodeHistory.LastFail.IsZero() {
+
+		t.Fatalf("unexpected node history: %v",
+			spew.Sdump(estimate.NodeHistory))
+	}
+
+	// Once the pair has carried the amount, the pair history should be
+	// returned as well.
+	err = ctx.router.ImportMissionControl([]*channeldb.MissionControlEntry{{
+		From:           from,
+		To:             to,
+		LastSuccess:    time.Now(),
+		LastSuccessAmt: amt,
+	}})
+	if err != nil {
+		t.Fatalf("unable to import mission control: %v", err)
+	}
+
+	estimate = ctx.router.QueryProbability(from, to, amt)
+	if estimate.PairHistory == nil ||
+		estimate.PairHistory.LastSuccessAmt != amt {
+
+		t.Fatalf("unexpected pair history: %v",
+			spew.Sdump(estimate.PairHistory))
+	}
+}
+
+// TestSendPaymentRouteHints asserts that a payment to a private destination
+// is routed over the channels described by its route hints.
+func TestSendPaymentRouteHints(t *testing.T) {
+	t.Parallel()
+
+	const startingBlockHeight = 101
+	ctx, cleanUp, err := createTestCtx(startingBlockHeight, basicGraphFilePath)
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to create router: %v", err)
+	}
+
+	// The destination of the payment isn't part of the graph, and can only
+	// be reached through its private channel with luo ji.
+	targetKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+	target := targetKey.PubKey()
+
+	var payHash [32]byte
+	payment := LightningPayment{
+		Target:      target,
+		Amount:      lnwire.NewMSatFromSatoshis(1000),
+		PaymentHash: payHash,
+	}
+
+	// Without any route hints, the payment should fail.
+	if _, _, err := ctx.router.SendPayment(&payment); err == nil {
+		t.Fatalf("payment to private destination should have failed")
+	}
+
+	var preImage [32]byte
+	copy(preImage[:], bytes.Repeat([]byte{9}, 32))
+	ctx.router.cfg.SendToSwitch = func(_ *btcec.PublicKey,
+		_ *lnwire.UpdateAddHTLC, _ *sphinx.Circuit) ([32]byte, error) {
+
+		return preImage, nil
+	}
+
+	payHash[0] = 1
+	payment.PaymentHash = payHash
+	payment.RouteHints = [][]HopHint{{{
+		NodeID:          ctx.aliases["luoji"],
+		ChannelID:       12345,
+		CLTVExpiryDelta: 40,
+	}}}
+	_, route, err := ctx.router.SendPayment(&payment)
+	if err != nil {
+		t.Fatalf("unable to send payment: %v", err)
+	}
+
+	// The route should end with the hinted channel.
+	finalHop := route.Hops[len(route.Hops)-1]
+	if finalHop.Channel.ChannelID != 12345 ||
+		!finalHop.Channel.Node.PubKey.IsEqual(target) {
+
+		t.Fatalf("route should end with the hinted channel, instead "+
+			"ends with channel %v", finalHop.Channel.ChannelID)
+	}
+	if finalHop.OutgoingTimeLock != 40 {
+		t.Fatalf("expected final time lock delta of 40, got %v",
+			finalHop.OutgoingTimeLock)
+	}
+}
+
+// TestBuildRoute checks that a route can be built over a manually selected
+// sequence of hops, and that hops which aren't connected by a channel able to
+// carry the payment are rejected.
+func TestBuildRoute(t *testing.T) {
+	t.Parallel()
+
+	const startingBlockHeight = 101
+	ctx, cleanUp, err := createTestCtx(startingBlockHeight, basicGraphFilePath)
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to create router: %v", err)
+	}
+
+	// We'll build a route to luo ji through satoshi, rather than over our
+	// direct channel with luo ji.
+	amt := lnwire.NewMSatFromSatoshis(1000)
+	hops := []*btcec.PublicKey{ctx.aliases["satoshi"], ctx.aliases["luoji"]}
+	route, err := ctx.router.BuildRoute(amt, hops)
+	if err != nil {
+		t.Fatalf("unable to build route: %v", err)
+	}
+
+	if len(route.Hops) != 2 {
+		t.Fatalf("expected 2 hops, instead have %v", len(route.Hops))
+	}
+	expectedChans := []uint64{2340213491, 523452362}
+	for i, hop := range route.Hops {
+		if !hop.Channel.Node.PubKey.IsEqual(hops[i]) {
+			t.Fatalf("hop %v leads to the wrong node", i)
+		}
+		if hop.Channel.ChannelID != expectedChans[i] {
+			t.Fatalf("expected hop %v to use channel %v, instead "+
+				"uses %v", i, expectedChans[i],
+				hop.Channel.ChannelID)
+		}
+	}
+
+	// Satoshi charges a base fee of 10 milli-atoms along with a rate of
+	// 0.1% for forwarding the payment, while no fee is paid to luo ji.
+	expectedFee := lnwire.MilliAtom(10 + 1000)
+	if route.TotalFees != expectedFee {
+		t.Fatalf("expected fee of %v, instead have %v", expectedFee,
+			route.TotalFees)
+	}
+	if route.TotalAmount != amt+expectedFee {
+		t.Fatalf("expected total amount of %v, instead have %v",
+			amt+expectedFee, route.TotalAmount)
+	}
+	if route.Hops[1].AmtToForward != amt || route.Hops[1].Fee != 0 {
+		t.Fatalf("unexpected final hop: %v", spew.Sdump(route.Hops[1]))
+	}
+	if route.TotalTimeLock != startingBlockHeight+2 {
+		t.Fatalf("expected total time lock of %v, instead have %v",
+			startingBlockHeight+2, route.TotalTimeLock)
+	}
+
+	// We don't have a channel with sophon, so we shouldn't be able to
+	// build a route to it directly.
+	_, err = ctx.router.BuildRoute(amt, []*btcec.PublicKey{
+		ctx.aliases["sophon"],
+	})
+	if !IsError(err, ErrNoRouteFound) {
+		t.Fatalf("expected ErrNoRouteFound, instead got: %v", err)
+	}
+
+	// Similarly, our channel with satoshi lacks the capacity to carry
+	// larger payments.
+	_, err = ctx.router.BuildRoute(lnwire.NewMSatFromSatoshis(20000), hops)
+	if !IsError(err, ErrNoRouteFound) {
+		t.Fatalf("expected ErrNoRouteFound, instead got: %v", err)
+	}
+}
+
+// TestSendToRoute checks that a payment can be sent over a manually built
+// route, that failures are attributed to the hop that generated them, and that
+// the payment is recorded within the payment store either way.
+func TestSendToRoute(t *testing.T) {
+	t.Parallel()
+
+	const startingBlockHeight = 101
+	ctx, cleanUp, err := createTestCtx(startingBlockHeight, basicGraphFilePath)
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to create router: %v", err)
+	}
+
+	amt := lnwire.NewMSatFromSatoshis(1000)
+	route, err := ctx.router.BuildRoute(amt, []*btcec.PublicKey{
+		ctx.aliases["satoshi"], ctx.aliases["luoji"],
+	})
+	if err != nil {
+		t.Fatalf("unable to build route: %v", err)
+	}
+
+	// The first attempt will be failed by satoshi, who is unable to
+	// forward the payment to luo ji.
+	failure := lnwire.NewTemporaryChannelFailure(nil)
+	sendErr := error(&htlcswitch.ForwardingError{
+		FailureCode:    failure.Code(),
+		ErrorSource:    ctx.aliases["satoshi"],
+		FailureMessage: failure,
+	})
+	ctx.router.cfg.SendToSwitch = func(_ *btcec.PublicKey,
+		_ *lnwire.UpdateAddHTLC, _ *sphinx.Circuit) ([32]byte, error) {
+
+		if sendErr != nil {
+			return [32]byte{}, sendErr
+		}
+		return [32]byte{1}, nil
+	}
+
+	payHash := [32]byte{1}
+	_, err = ctx.router.SendToRoute(payHash, route)
+	routeErr, ok := err.(*RouteError)
+	if !ok {
+		t.Fatalf("expected RouteError, got: %v", err)
+	}
+	if routeErr.FailureSourceIdx != 1 {
+		t.Fatalf("expected failure to be attributed to hop 1, "+
+			"instead attributed to hop %v",
+			routeErr.FailureSourceIdx)
+	}
+	if routeErr.FailureMessage != failure {
+		t.Fatalf("unexpected failure message: %v",
+			spew.Sdump(routeErr.FailureMessage))
+	}
+
+	dbPayment := ctx.payments.payments[payHash]
+	if dbPayment.Status != channeldb.StatusFailed ||
+		len(dbPayment.HTLCs) != 1 || dbPayment.HTLCs[0].Failure == nil {
+
+		t.Fatalf("expected a single failed attempt to be recorded: %v",
+			spew.Sdump(dbPayment))
+	}
+	if dbPayment.Info.Value != amt {
+		t.Fatalf("expected payment value %v, instead have %v", amt,
+			dbPayment.Info.Value)
+	}
+
+	// A local failure should be attributed to our own node.
+	sendErr = &htlcswitch.ForwardingError{
+		FailureCode:  lnwire.CodeUnknownNextPeer,
+		LocalFailure: true,
+	}
+	_, err = ctx.router.SendToRoute(payHash, route)
+	routeErr, ok = err.(*RouteError)
+	if !ok || routeErr.FailureSourceIdx != 0 {
+		t.Fatalf("expected failure attributed to our own node, "+
+			"got: %v", err)
+	}
+
+	// Finally, as the payment failed, it may be attempted again, this time
+	// successfully.
+	sendErr = nil
+	preimage, err := ctx.router.SendToRoute(payHash, route)
+	if err != nil {
+		t.Fatalf("unable to send payment: %v", err)
+	}
+	if preimage != [32]byte{1} {
+		t.Fatalf("unexpected preimage: %x", preimage)
+	}
+
+	dbPayment = ctx.payments.payments[payHash]
+	if dbPayment.Status != channeldb.StatusSucceeded {
+		t.Fatalf("payment should have succeeded, instead status is %v",
+			dbPayment.Status)
+	}
+}
+
+// TestSendCircularPayment checks that a payment can be sent from our own node
+// back to itself over a circular route, which is settled using a preimage
+// registered only for the duration of the payment.
+func TestSendCircularPayment(t *testing.T) {
+	t.Parallel()
+
+	const startingBlockHeight = 101
+	ctx, cleanUp, err := createTestCtx(startingBlockHeight, basicGraphFilePath)
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to create router: %v", err)
+	}
+
+	// We'll build a circular route, which leaves over our channel with luo
+	// ji and returns over our channel with satoshi.
+	amt := lnwire.NewMSatFromSatoshis(1000)
+	route, err := ctx.router.BuildRoute(amt, []*btcec.PublicKey{
+		ctx.aliases["luoji"], ctx.aliases["satoshi"],
+		ctx.aliases["roasbeef"],
+	})
+	if err != nil {
+		t.Fatalf("unable to build route: %v", err)
+	}
+	if route.Hops[0].Channel.ChannelID != 689530843 ||
+		route.Hops[2].Channel.ChannelID != 2340213491 {
+
+		t.Fatalf("unexpected circular route: %v", spew.Sdump(route))
+	}
+
+	// We only have a single channel with luo ji, so a route out to it and
+	// straight back can't be built.
+	_, err = ctx.router.BuildRoute(amt, []*btcec.PublicKey{
+		ctx.aliases["luoji"], ctx.aliases["roasbeef"],
+	})
+	if !IsError(err, ErrNoRouteFound) {
+		t.Fatalf("expected ErrNoRouteFound, instead got: %v", err)
+	}
+
+	// The preimage registered for the payment should be the one the HTLC
+	// is locked to, and must remain registered while it's in flight.
+	var (
+		registered bool
+		preimage   [32]byte
+		regAmt     lnwire.MilliAtom
+	)
+	ctx.router.cfg.AddCircularPayment = func(p [32]byte,
+		amt lnwire.MilliAtom) func() {
+
+		registered = true
+		preimage = p
+		regAmt = amt
+		return func() {
+			registered = false
+		}
+	}
+	ctx.router.cfg.SendToSwitch = func(_ *btcec.PublicKey,
+		htlcAdd *lnwire.UpdateAddHTLC, _ *sphinx.Circuit) ([32]byte,
+		error) {
+
+		if !registered {
+			return [32]byte{}, errors.New("preimage not registered")
+		}
+		if htlcAdd.PaymentHash != sha256.Sum256(preimage[:]) {
+			return [32]byte{}, errors.New("unknown payment hash")
+		}
+		return preimage, nil
+	}
+
+	settled, err := ctx.router.SendCircularPayment(route)
+	if err != nil {
+		t.Fatalf("unable to send circular payment: %v", err)
+	}
+	if settled != preimage {
+		t.Fatalf("expected preimage %x, instead got %x", preimage,
+			settled)
+	}
+	if registered {
+		t.Fatalf("preimage should be removed once the payment completes")
+	}
+	if regAmt != amt {
+		t.Fatalf("expected preimage to be registered for %v, instead "+
+			"registered for %v", amt, regAmt)
+	}
+
+	dbPayment := ctx.payments.payments[sha256.Sum256(preimage[:])]
+	if dbPayment == nil || dbPayment.Status != channeldb.StatusSucceeded {
+		t.Fatalf("expected payment to be recorded as succeeded: %v",
+			spew.Sdump(dbPayment))
+	}
+
+	// A route that doesn't lead back to our own node isn't circular.
+	route, err = ctx.router.BuildRoute(amt, []*btcec.PublicKey{
+		ctx.aliases["satoshi"], ctx.aliases["luoji"],
+	})
+	if err != nil {
+		t.Fatalf("unable to build route: %v", err)
+	}
+	_, err = ctx.router.SendCircularPayment(route)
+	if !IsError(err, ErrNoRouteFound) {
+		t.Fatalf("expected ErrNoRouteFound, instead got: %v", err)
+	}
+}
+
+// TestResumePayments tests that payments left in flight by a shutdown of the
+// switch are completed once the outcome of their attempts becomes known after
+// a restart.
+func TestResumePayments(t *testing.T) {
+	t.Parallel()
+
+	const startingBlockHeight = 101
+	ctx, cleanUp, err := createTestCtx(startingBlockHeight, basicGraphFilePath)
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to create router: %v", err)
+	}
+
+	amt := lnwire.NewMSatFromSatoshis(1000)
+	route, err := ctx.router.BuildRoute(amt, []*btcec.PublicKey{
+		ctx.aliases["satoshi"], ctx.aliases["luoji"],
+	})
+	if err != nil {
+		t.Fatalf("unable to build route: %v", err)
+	}
+
+	// We'll send two payments while the switch is shutting down, which
+	// should leave both of them in flight.
+	ctx.router.cfg.SendToSwitch = func(_ *btcec.PublicKey,
+		_ *lnwire.UpdateAddHTLC, _ *sphinx.Circuit) ([32]byte, error) {
+
+		return [32]byte{}, htlcswitch.ErrSwitchExiting
+	}
+
+	settleHash, failHash := [32]byte{1}, [32]byte{2}
+	for _, hash := range [][32]byte{settleHash, failHash} {
+		_, err := ctx.router.SendToRoute(hash, route)
+		if err != htlcswitch.ErrSwitchExiting {
+			t.Fatalf("expected switch exiting error, got: %v", err)
+		}
+
+		dbPayment := ctx.payments.payments[hash]
+		if dbPayment.Status != channeldb.StatusInFlight ||
+			len(dbPayment.InFlightHTLCs()) != 1 ||
+			dbPayment.HTLCs[0].SessionKey == nil {
+
+			t.Fatalf("expected a single attempt to be in flight: "+
+				"%v", spew.Sdump(dbPayment))
+		}
+	}
+
+	// Once restarted, the first payment's attempt will be settled, while
+	// the second payment's attempt fails at the destination.
+	preimage := [32]byte{3}
+	ctx.router.cfg.GetPaymentResult = func(hash [32]byte,
+		amt lnwire.MilliAtom, circuit *sphinx.Circuit) ([32]byte,
+		error) {
+
+		ctx.payments.Lock()
+		attempt := ctx.payments.payments[hash].HTLCs[0]
+		ctx.payments.Unlock()
+
+		if circuit.SessionKey != attempt.SessionKey ||
+			len(circuit.PaymentPath) != len(route.Hops) {
+
+			return [32]byte{}, errors.New("circuit mismatch")
+		}
+		if amt != route.TotalAmount {
+			return [32]byte{}, errors.New("amount mismatch")
+		}
+
+		if hash == settleHash {
+			return preimage, nil
+		}
+
+		return [32]byte{}, &htlcswitch.ForwardingError{
+			FailureCode:    lnwire.CodeUnknownPaymentHash,
+			ErrorSource:    ctx.aliases["luoji"],
+			FailureMessage: &lnwire.FailUnknownPaymentHash{},
+		}
+	}
+	if err := ctx.router.resumePayments(); err != nil {
+		t.Fatalf("unable to resume payments: %v", err)
+	}
+
+	paymentStatus := func(hash [32]byte) channeldb.PaymentStatus {
+		ctx.payments.Lock()
+		defer ctx.payments.Unlock()
+
+		return ctx.payments.payments[hash].Status
+	}
+
+	timeout := time.After(5 * time.Second)
+	for paymentStatus(settleHash) != channeldb.StatusSucceeded ||
+		paymentStatus(failHash) != channeldb.StatusFailed {
+
+		select {
+		case <-timeout:
+			t.Fatalf("payments weren't resumed: %v",
+				spew.Sdump(ctx.payments.payments))
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	ctx.payments.Lock()
+	defer ctx.payments.Unlock()
+
+	settled := ctx.payments.payments[settleHash].HTLCs[0].Settle
+	if settled == nil || settled.Preimage != preimage {
+		t.Fatalf("expected attempt to be settled with preimage %x",
+			preimage)
+	}
+
+	failed := ctx.payments.payments[failHash]
+	if *failed.FailureReason !=
+		channeldb.FailureReasonIncorrectPaymentDetails {
+
+		t.Fatalf("unexpected failure reason: %v",
+			*failed.FailureReason)
+	}
+}
+
+// TestSubscribePayment tests that the subscribers of a payment are notified
+// of its state transitions until it completes.
+func TestSubscribePayment(t *testing.T) {
+	t.Parallel()
+
+	const startingBlockHeight = 101
+	ctx, cleanUp, err := createTestCtx(startingBlockHeight, basicGraphFilePath)
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to create router: %v", err)
+	}
+
+	payHash := [32]byte{1}
+	preimage := [32]byte{2}
+
+	// Subscribing to an unknown payment should fail.
+	if _, err := ctx.router.SubscribePayment(payHash); err == nil {
+		t.Fatal("expected subscription to unknown payment to fail")
+	}
+
+	amt := lnwire.NewMSatFromSatoshis(1000)
+	route, err := ctx.router.BuildRoute(amt, []*btcec.PublicKey{
+		ctx.aliases["satoshi"], ctx.aliases["luoji"],
+	})
+	if err != nil {
+		t.Fatalf("unable to build route: %v", err)
+	}
+
+	// We'll hold the payment within the switch until we've subscribed to
+	// it.
+	sent := make(chan struct{})
+	release := make(chan struct{})
+	ctx.router.cfg.SendToSwitch = func(_ *btcec.PublicKey,
+		_ *lnwire.UpdateAddHTLC, _ *sphinx.Circuit) ([32]byte, error) {
+
+		close(sent)
+		<-release
+		return preimage, nil
+	}
+
+	errChan := make(chan error, 1)
+	go func() {
+		_, err := ctx.router.SendToRoute(payHash, route)
+		errChan <- err
+	}()
+
+	select {
+	case <-sent:
+	case <-time.After(5 * time.Second):
+		t.Fatal("payment wasn't sent")
+	}
+
+	sub, err := ctx.router.SubscribePayment(payHash)
+	if err != nil {
+		t.Fatalf("unable to subscribe to payment: %v", err)
+	}
+	defer sub.Cancel()
+
+	nextUpdate := func() *channeldb.MPPayment {
+		select {
+		case payment, ok := <-sub.Updates:
+			if !ok {
+				t.Fatal("subscription closed unexpectedly")
+			}
+			return payment
+		case <-time.After(5 * time.Second):
+			t.Fatal("payment update not received")
+		}
+		return nil
+	}
+
+	// The first update should reflect the attempt in flight.
+	payment := nextUpdate()
+	if payment.Status != channeldb.StatusInFlight ||
+		len(payment.InFlightHTLCs()) != 1 {
+
+		t.Fatalf("expected a single attempt in flight: %v",
+			spew.Sdump(payment))
+	}
+
+	// Once the payment is settled, the subscriber should learn its
+	// preimage, after which the subscription is closed.
+	close(release)
+	if err := <-errChan; err != nil {
+		t.Fatalf("unable to send payment: %v", err)
+	}
+
+	payment = nextUpdate()
+	settled := payment.SettledAttempt()
+	if payment.Status != channeldb.StatusSucceeded || settled == nil ||
+		settled.Settle.Preimage != preimage {
+
+		t.Fatalf("expected payment to succeed: %v",
+			spew.Sdump(payment))
+	}
+
+	select {
+	case _, ok := <-sub.Updates:
+		if ok {
+			t.Fatal("expected subscription to be closed")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("subscription wasn't closed")
+	}
+
+	// A subscription to the completed payment should only receive its
+	// final state.
+	sub, err = ctx.router.SubscribePayment(payHash)
+	if err != nil {
+		t.Fatalf("unable to subscribe to payment: %v", err)
+	}
+	defer sub.Cancel()
+
+	payment = nextUpdate()
+	if payment.Status != channeldb.StatusSucceeded {
+		t.Fatalf("expected payment to have succeeded: %v",
+			spew.Sdump(payment))
+	}
+	if _, ok := <-sub.Updates; ok {
+		t.Fatal("expected subscription to be closed")
+	}
+}
+
+// blockingPaymentStore is a payment store which blocks the initiation of the
+// payment with the given hash until it's released.
+type blockingPaymentStore struct {
+	*mockPaymentStore
+
+	blockedHash [32]byte
+	blocked     chan struct{}
+	release     chan struct{}
+}
+
+func (b *blockingPaymentStore) InitPayment(hash [32]byte,
+	info *channeldb.PaymentCreationInfo) error {
+
+	if hash == b.blockedHash {
+		close(b.blocked)
+		<-b.release
+	}
+
+	return b.mockPaymentStore.InitPayment(hash, info)
+}
+
+// TestPaymentTrackerConcurrentPayments asserts that the payment tracker only
+// serializes the modifications of the same payment, so that a slow write to
+// one payment doesn't hold up the others.
+func TestPaymentTrackerConcurrentPayments(t *testing.T) {
+	t.Parallel()
+
+	store := &blockingPaymentStore{
+		mockPaymentStore: newMockPaymentStore(),
+		blockedHash:      [32]byte{1},
+		blocked:          make(chan struct{}),
+		release:          make(chan struct{}),
+	}
+	tracker := newPaymentTracker(store)
+	if err := tracker.Start(); err != nil {
+		t.Fatalf("unable to start tracker: %v", err)
+	}
+	defer tracker.Stop()
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- tracker.InitPayment(
+			store.blockedHash, &channeldb.PaymentCreationInfo{},
+		)
+	}()
+
+	select {
+	case <-store.blocked:
+	case <-time.After(5 * time.Second):
+		t.Fatal("payment wasn't initiated")
+	}
+
+	// While the first payment is being written, another payment should
+	// be initiated without waiting on it.
+	done := make(chan error, 1)
+	go func() {
+		done <- tracker.InitPayment(
+			[32]byte{2}, &channeldb.PaymentCreationInfo{},
+		)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("unable to init payment: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("unrelated payment blocked on the first")
+	}
+
+	close(store.release)
+	if err := <-errChan; err != nil {
+		t.Fatalf("unable to init payment: %v", err)
+	}
+
+	if len(tracker.paymentMtxs) != 0 {
+		t.Fatalf("expected payment mutexes to be released, %v remain",
+			len(tracker.paymentMtxs))
+	}
+}
+
+// TestAddProof checks that we can update the channel proof after channel
+// info was added to the database.
+func TestAddProof(t *testing.T) {
+	t.Parallel()
+
+	ctx, cleanup, err := createTestCtx(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	// Before creating out edge, we'll create two new nodes within the
+	// network that the channel will connect.
+	node1, err := createTestNode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	node2, err := createTestNode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// In order to be able to add the edge we should have a valid funding
+	// UTXO within the blockchain.
+	fundingTx, _, chanID, err := createChannelEdge(ctx,
+		bitcoinKey1.SerializeCompressed(), bitcoinKey2.SerializeCompressed(),
+		100, 0)
+	if err != nil {
+		t.Fatalf("unable create channel edge: %v", err)
+	}
+	fundingBlock := &wire.MsgBlock{
+		Transactions: []*wire.MsgTx{fundingTx},
+	}
+	ctx.chain.addBlock(fundingBlock, chanID.BlockHeight)
+
+	// After utxo was recreated adding the edge without the proof.
+	edge := &channeldb.ChannelEdgeInfo{
+		ChannelID:   chanID.ToUint64(),
+		NodeKey1:    node1.PubKey,
+		NodeKey2:    node2.PubKey,
+		BitcoinKey1: bitcoinKey1,
+		BitcoinKey2: bitcoinKey2,
+		AuthProof:   nil,
+	}
+
+	if err := ctx.router.AddEdge(edge); err != nil {
+		t.Fatalf("unable to add edge: %v", err)
+	}
+
+	// Now we'll attempt to update the proof and check that it has been
+	// properly updated.
+	if err := ctx.router.AddProof(*chanID, &testAuthProof); err != nil {
+		t.Fatalf("unable to add proof: %v", err)
+	}
+
+	info, _, _, err := ctx.router.GetChannelByID(*chanID)
+	if info.AuthProof == nil {
+		t.Fatal("proof have been updated")
+	}
+}
+
+// TestRouterChansClosedOfflinePruneGraph tests that channels closed within
+// blocks connected while the router was offline are pruned from the graph
+// once the router syncs with the chain, and that the prune tip is advanced to
+// the chain tip.
+func TestRouterChansClosedOfflinePruneGraph(t *testing.T) {
+	t.Parallel()
+
+	const startingBlockHeight = 101
+	ctx, cleanUp, err := createTestCtx(startingBlockHeight)
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to create router: %v", err)
+	}
+
+	node1, err := createTestNode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	node2, err := createTestNode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// We'll first add a channel to the graph, funded within the block at
+	// our starting height.
+	fundingTx, chanUtxo, chanID, err := createChannelEdge(ctx,
+		bitcoinKey1.SerializeCompressed(), bitcoinKey2.SerializeCompressed(),
+		10000, startingBlockHeight)
+	if err != nil {
+		t.Fatalf("unable create channel edge: %v", err)
+	}
+	fundingBlock := &wire.MsgBlock{
+		Transactions: []*wire.MsgTx{fundingTx},
+	}
+	ctx.chain.addBlock(fundingBlock, startingBlockHeight)
+
+	edge := &channeldb.ChannelEdgeInfo{
+		ChannelID:   chanID.ToUint64(),
+		NodeKey1:    node1.PubKey,
+		NodeKey2:    node2.PubKey,
+		BitcoinKey1: bitcoinKey1,
+		BitcoinKey2: bitcoinKey2,
+		AuthProof:   nil,
+	}
+	if err := ctx.router.AddEdge(edge); err != nil {
+		t.Fatalf("unable to add edge: %v", err)
+	}
+
+	// The graph has been pruned up to the funding block.
+	fundingHash := fundingBlock.BlockHash()
+	_, err = ctx.graph.PruneGraph(nil, &fundingHash, startingBlockHeight)
+	if err != nil {
+		t.Fatalf("unable to prune graph: %v", err)
+	}
+
+	// Next, while the router is offline, two more blocks are mined, the
+	// second of which spends the channel's funding output.
+	emptyBlock := &wire.MsgBlock{}
+	ctx.chain.addBlock(emptyBlock, startingBlockHeight+1)
+
+	closeTx := wire.NewMsgTx(2)
+	closeTx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: *chanUtxo,
+	})
+	closeBlock := &wire.MsgBlock{
+		Transactions: []*wire.MsgTx{closeTx},
+	}
+	ctx.chain.addBlock(closeBlock, startingBlockHeight+2)
+	ctx.chain.setBestBlock(startingBlockHeight + 2)
+
+	// Syncing the graph with the chain should replay the missed blocks,
+	// pruning the closed channel.
+	if err := ctx.router.syncGraphWithChain(); err != nil {
+		t.Fatalf("unable to sync graph with chain: %v", err)
+	}
+
+	_, _, exists, err := ctx.graph.HasChannelEdge(chanID.ToUint64())
+	if err != nil {
+		t.Fatalf("unable to query for channel edge: %v", err)
+	}
+	if exists {
+		t.Fatal("closed channel wasn't pruned from the graph")
+	}
+
+	pruneHash, pruneHeight, err := ctx.graph.PruneTip()
+	if err != nil {
+		t.Fatalf("unable to fetch prune tip: %v", err)
+	}
+	closeHash := closeBlock.BlockHash()
+	if !pruneHash.IsEqual(&closeHash) ||
+		pruneHeight != startingBlockHeight+2 {
+
+		t.Fatalf("expected prune tip (%v, %v), got (%v, %v)",
+			closeHash, startingBlockHeight+2, pruneHash,
+			pruneHeight)
 	}
 }
 
@@ -374,7 +1962,7 @@ func TestAddEdgeUnknownVertexes(t *testing.T) {
 		FeeBaseMSat:               10,
 		FeeProportionalMillionths: 10000,
 	}
-	edgePolicy.Flags = 0
+	edgePolicy.ChannelFlags = 0
 
 	if err := ctx.router.UpdateEdge(edgePolicy); err != nil {
 		t.Fatalf("unable to update edge policy: %v", err)
@@ -390,7 +1978,7 @@ func TestAddEdgeUnknownVertexes(t *testing.T) {
 		FeeBaseMSat:               10,
 		FeeProportionalMillionths: 10000,
 	}
-	edgePolicy.Flags = 1
+	edgePolicy.ChannelFlags = 1
 
 	if err := ctx.router.UpdateEdge(edgePolicy); err != nil {
 		t.Fatalf("unable to update edge policy: %v", err)
@@ -470,7 +2058,7 @@ func TestAddEdgeUnknownVertexes(t *testing.T) {
 		FeeBaseMSat:               10,
 		FeeProportionalMillionths: 10000,
 	}
-	edgePolicy.Flags = 0
+	edgePolicy.ChannelFlags = 0
 
 	if err := ctx.router.UpdateEdge(edgePolicy); err != nil {
 		t.Fatalf("unable to update edge policy: %v", err)
@@ -485,7 +2073,7 @@ func TestAddEdgeUnknownVertexes(t *testing.T) {
 		FeeBaseMSat:               10,
 		FeeProportionalMillionths: 10000,
 	}
-	edgePolicy.Flags = 1
+	edgePolicy.ChannelFlags = 1
 
 	if err := ctx.router.UpdateEdge(edgePolicy); err != nil {
 		t.Fatalf("unable to update edge policy: %v", err)
@@ -494,7 +2082,7 @@ func TestAddEdgeUnknownVertexes(t *testing.T) {
 	// We should now be able to find one route to node 2.
 	paymentAmt := lnwire.NewMSatFromSatoshis(100)
 	targetNode := priv2.PubKey()
-	routes, err := ctx.router.FindRoutes(targetNode, paymentAmt)
+	routes, err := ctx.router.FindRoutes(targetNode, paymentAmt, nil, nil)
 	if err != nil {
 		t.Fatalf("unable to find any routes: %v", err)
 	}
@@ -536,7 +2124,7 @@ func TestAddEdgeUnknownVertexes(t *testing.T) {
 
 	// Should still be able to find the route, and the info should be
 	// updated.
-	routes, err = ctx.router.FindRoutes(targetNode, paymentAmt)
+	routes, err = ctx.router.FindRoutes(targetNode, paymentAmt, nil, nil)
 	if err != nil {
 		t.Fatalf("unable to find any routes: %v", err)
 	}
@@ -0,0 +1,394 @@
+package routing
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/go-errors/errors"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/btcec"
+)
+
+const (
+	// DefaultValidationBatchSize is the default maximum number of jobs
+	// that are handed to a validation worker at once.
+	DefaultValidationBatchSize = 50
+)
+
+var (
+	// ErrValidationPoolExiting is returned when a job is submitted to, or
+	// is waiting on, a validation pool that is shutting down.
+	ErrValidationPoolExiting = errors.New("validation pool is shutting " +
+		"down")
+)
+
+// SigCheck is a single signature which must be verified in order for an
+// announcement to be considered valid.
+type SigCheck struct {
+	// Sig is the signature to be verified.
+	Sig *btcec.Signature
+
+	// PubKey is the public key the signature should have been made with.
+	PubKey *btcec.PublicKey
+
+	// Hash is the digest of the data covered by the signature.
+	Hash []byte
+
+	// Desc is a short human readable description of the signature, used
+	// to describe the failure if the signature turns out to be invalid.
+	Desc string
+}
+
+// VerifySigChecks verifies each of the passed signatures in turn, returning an
+// error describing the first invalid signature, if any.
+func VerifySigChecks(checks []*SigCheck) error {
+	for _, check := range checks {
+		if !check.Sig.Verify(check.Hash, check.PubKey) {
+			return fmt.Errorf("can't verify %v", check.Desc)
+		}
+	}
+
+	return nil
+}
+
+// ValidationJob is an announcement submitted to the ValidationPool. The
+// signatures of the announcement are verified by one of the pool's workers,
+// after which the outcome is committed in the order the jobs were submitted.
+type ValidationJob struct {
+	// Msg is the announcement being validated. Its type and the channel
+	// it refers to are used to determine which of the previously
+	// submitted jobs it depends upon.
+	Msg lnwire.Message
+
+	// SigChecks assembles the set of signatures covering the
+	// announcement. It's only called once each job the announcement
+	// depends upon has been committed, so it may consult the channel
+	// graph for the keys of a newly announced channel. An empty set of
+	// checks indicates that there's nothing to verify.
+	SigChecks func() ([]*SigCheck, error)
+
+	// Commit is called with the outcome of the validation of the
+	// announcement. Jobs are committed one at a time, in the order they
+	// were submitted to the pool. Jobs which are abandoned as the pool
+	// stops are committed with ErrValidationPoolExiting instead, so each
+	// job submitted successfully is committed exactly once.
+	Commit func(err error)
+}
+
+// validationTask couples a submitted job with the state the pool needs to
+// track while it's being validated.
+type validationTask struct {
+	job *ValidationJob
+
+	// deps is the set of signals of the jobs this job depends upon. Each
+	// signal is closed once the respective job has been committed.
+	deps []chan struct{}
+
+	// committed is closed once this job has been committed.
+	committed chan struct{}
+
+	// result carries the outcome of the validation of this job from the
+	// worker to the committer.
+	result chan error
+}
+
+// validationBarrier tracks the dependencies between the jobs within the
+// ValidationPool. A ChannelUpdate can only be verified against the keys of the
+// channel it updates, so it must wait for the ChannelAnnouncement that
+// creates the channel to be committed if that announcement is still pending
+// within the pool.
+type validationBarrier struct {
+	// chanAnnSignals maps the short channel ID of each pending channel
+	// announcement to the signal closed once it's been committed.
+	chanAnnSignals map[lnwire.ShortChannelID]chan struct{}
+
+	sync.Mutex
+}
+
+// register records the dependencies of the passed task on the jobs submitted
+// before it. Tasks MUST be registered in the order they were submitted.
+func (b *validationBarrier) register(task *validationTask) {
+	b.Lock()
+	defer b.Unlock()
+
+	switch msg := task.job.Msg.(type) {
+	case *lnwire.ChannelAnnouncement:
+		b.chanAnnSignals[msg.ShortChannelID] = task.committed
+
+	case *lnwire.ChannelUpdate:
+		signal, ok := b.chanAnnSignals[msg.ShortChannelID]
+		if ok {
+			task.deps = append(task.deps, signal)
+		}
+	}
+}
+
+// signal marks the passed task as committed, releasing any tasks that depend
+// upon it.
+func (b *validationBarrier) signal(task *validationTask) {
+	b.Lock()
+	defer b.Unlock()
+
+	close(task.committed)
+
+	// If this was the latest announcement of its channel, then later jobs
+	// of the channel no longer need to wait on anything.
+	msg, ok := task.job.Msg.(*lnwire.ChannelAnnouncement)
+	if ok && b.chanAnnSignals[msg.ShortChannelID] == task.committed {
+		delete(b.chanAnnSignals, msg.ShortChannelID)
+	}
+}
+
+// ValidationPool is a pool of workers which verifies the signatures of
+// announcements concurrently. Submitted jobs are dispatched to the workers in
+// batches, while their dependencies on previously submitted jobs are tracked
+// by a validation barrier. The outcome of each job is committed by a single
+// goroutine in the order the jobs were submitted, so the effect of
+// validating the announcements concurrently is indistinguishable from
+// processing them one after another.
+type ValidationPool struct {
+	started uint32
+	stopped uint32
+
+	numWorkers int
+	batchSize  int
+
+	barrier *validationBarrier
+
+	// jobs carries the submitted jobs to the dispatcher.
+	jobs chan *validationTask
+
+	// batches carries the batches assembled by the dispatcher to the
+	// workers.
+	batches chan []*validationTask
+
+	// pending carries each task to the committer in the order it was
+	// submitted.
+	pending chan *validationTask
+
+	// uncommitted is the set of submitted tasks which have yet to be
+	// committed, which are committed with ErrValidationPoolExiting once
+	// the pool has stopped.
+	uncommitted   map[*validationTask]struct{}
+	uncommittedMu sync.Mutex
+
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+// NewValidationPool creates a new validation pool with the specified number
+// of workers, each of which is handed up to batchSize jobs at once. The
+// recommended number of workers is the number of physical CPU cores available
+// on the target machine.
+func NewValidationPool(numWorkers, batchSize int) *ValidationPool {
+	return &ValidationPool{
+		numWorkers: numWorkers,
+		batchSize:  batchSize,
+		barrier: &validationBarrier{
+			chanAnnSignals: make(
+				map[lnwire.ShortChannelID]chan struct{},
+			),
+		},
+		jobs:        make(chan *validationTask),
+		batches:     make(chan []*validationTask),
+		pending:     make(chan *validationTask, numWorkers*batchSize),
+		uncommitted: make(map[*validationTask]struct{}),
+		quit:        make(chan struct{}),
+	}
+}
+
+// Start launches the dispatcher, committer and workers of the pool.
+func (p *ValidationPool) Start() error {
+	if !atomic.CompareAndSwapUint32(&p.started, 0, 1) {
+		return nil
+	}
+
+	p.wg.Add(2 + p.numWorkers)
+	go p.dispatcher()
+	go p.committer()
+	for i := 0; i < p.numWorkers; i++ {
+		go p.poolWorker()
+	}
+
+	return nil
+}
+
+// Stop signals all goroutines of the pool to exit. Jobs which haven't been
+// committed by then are abandoned, and committed with
+// ErrValidationPoolExiting.
+func (p *ValidationPool) Stop() error {
+	if !atomic.CompareAndSwapUint32(&p.stopped, 0, 1) {
+		return nil
+	}
+
+	close(p.quit)
+	p.wg.Wait()
+
+	p.uncommittedMu.Lock()
+	abandoned := p.uncommitted
+	p.uncommitted = make(map[*validationTask]struct{})
+	p.uncommittedMu.Unlock()
+
+	for task := range abandoned {
+		task.job.Commit(ErrValidationPoolExiting)
+	}
+
+	return nil
+}
+
+// untrack removes the task from the set of uncommitted tasks, returning false
+// if it had already been removed.
+func (p *ValidationPool) untrack(task *validationTask) bool {
+	p.uncommittedMu.Lock()
+	defer p.uncommittedMu.Unlock()
+
+	if _, ok := p.uncommitted[task]; !ok {
+		return false
+	}
+	delete(p.uncommitted, task)
+
+	return true
+}
+
+// Submit queues the passed job for validation. The job will be committed
+// after all jobs submitted before it. If an error is returned, then the job
+// will never be committed.
+func (p *ValidationPool) Submit(job *ValidationJob) error {
+	task := &validationTask{
+		job:       job,
+		committed: make(chan struct{}),
+		result:    make(chan error, 1),
+	}
+
+	p.uncommittedMu.Lock()
+	p.uncommitted[task] = struct{}{}
+	p.uncommittedMu.Unlock()
+
+	select {
+	case p.jobs <- task:
+		return nil
+	case <-p.quit:
+		// If the pool has already abandoned the job, then it's been
+		// committed with the error.
+		if !p.untrack(task) {
+			return nil
+		}
+		return ErrValidationPoolExiting
+	}
+}
+
+// dispatcher assembles the submitted jobs into batches, registering each job
+// with the validation barrier before handing the batch to the workers.
+//
+// NOTE: This MUST be run as a goroutine.
+func (p *ValidationPool) dispatcher() {
+	defer p.wg.Done()
+
+	for {
+		var batch []*validationTask
+		select {
+		case task := <-p.jobs:
+			batch = append(batch, task)
+		case <-p.quit:
+			return
+		}
+
+		// We'll add any other jobs that are already waiting to the
+		// batch, up to the maximum batch size.
+	gather:
+		for len(batch) < p.batchSize {
+			select {
+			case task := <-p.jobs:
+				batch = append(batch, task)
+			default:
+				break gather
+			}
+		}
+
+		// The dependencies of each job are registered in order, and
+		// the committer is told of the job before any worker sees it.
+		for _, task := range batch {
+			p.barrier.register(task)
+
+			select {
+			case p.pending <- task:
+			case <-p.quit:
+				return
+			}
+		}
+
+		select {
+		case p.batches <- batch:
+		case <-p.quit:
+			return
+		}
+	}
+}
+
+// poolWorker validates the batches of jobs handed to it by the dispatcher,
+// one job at a time, passing the outcome of each on to the committer.
+//
+// NOTE: This MUST be run as a goroutine.
+func (p *ValidationPool) poolWorker() {
+	defer p.wg.Done()
+
+	for {
+		select {
+		case batch := <-p.batches:
+			for _, task := range batch {
+				task.result <- p.validate(task)
+			}
+
+		case <-p.quit:
+			return
+		}
+	}
+}
+
+// validate waits for the jobs the task depends upon to be committed, then
+// verifies the signatures covering its announcement.
+func (p *ValidationPool) validate(task *validationTask) error {
+	for _, dep := range task.deps {
+		select {
+		case <-dep:
+		case <-p.quit:
+			return ErrValidationPoolExiting
+		}
+	}
+
+	checks, err := task.job.SigChecks()
+	if err != nil {
+		return err
+	}
+
+	return VerifySigChecks(checks)
+}
+
+// committer commits the outcome of each job in the order the jobs were
+// submitted, releasing any jobs depending on them.
+//
+// NOTE: This MUST be run as a goroutine.
+func (p *ValidationPool) committer() {
+	defer p.wg.Done()
+
+	for {
+		var task *validationTask
+		select {
+		case task = <-p.pending:
+		case <-p.quit:
+			return
+		}
+
+		var err error
+		select {
+		case err = <-task.result:
+		case <-p.quit:
+			return
+		}
+
+		p.untrack(task)
+		task.job.Commit(err)
+		p.barrier.signal(task)
+	}
+}
@@ -0,0 +1,231 @@
+package routing
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+)
+
+// TestValidationPoolOrderedCommits asserts that the validation pool commits
+// jobs in the order they were submitted, that a channel update is only
+// validated once the announcement of its channel has been committed, and that
+// invalid signatures are reported.
+func TestValidationPoolOrderedCommits(t *testing.T) {
+	t.Parallel()
+
+	const numChans = 100
+
+	pool := NewValidationPool(4, 3)
+	if err := pool.Start(); err != nil {
+		t.Fatalf("unable to start validation pool: %v", err)
+	}
+	defer pool.Stop()
+
+	priv, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+	hash := chainhash.DoubleHashB([]byte("announcement"))
+	sig, err := priv.Sign(hash)
+	if err != nil {
+		t.Fatalf("unable to sign: %v", err)
+	}
+	invalidHash := chainhash.DoubleHashB([]byte("invalid"))
+
+	var (
+		mtx       sync.Mutex
+		committed = make(map[lnwire.ShortChannelID]struct{})
+		order     []int
+		errs      = make(map[int]error)
+	)
+
+	done := make(chan struct{})
+	numJobs := 0
+	submit := func(msg lnwire.Message, sigChecks func() error,
+		valid bool) {
+
+		idx := numJobs
+		numJobs++
+
+		check := &SigCheck{
+			Sig:    sig,
+			PubKey: priv.PubKey(),
+			Hash:   hash,
+			Desc:   "test signature",
+		}
+		if !valid {
+			check.Hash = invalidHash
+		}
+
+		err := pool.Submit(&ValidationJob{
+			Msg: msg,
+			SigChecks: func() ([]*SigCheck, error) {
+				if err := sigChecks(); err != nil {
+					return nil, err
+				}
+				return []*SigCheck{check}, nil
+			},
+			Commit: func(err error) {
+				mtx.Lock()
+				defer mtx.Unlock()
+
+				order = append(order, idx)
+				errs[idx] = err
+				if ann, ok := msg.(*lnwire.ChannelAnnouncement); ok {
+					committed[ann.ShortChannelID] = struct{}{}
+				}
+				if len(order) == 3*numChans {
+					close(done)
+				}
+			},
+		})
+		if err != nil {
+			t.Fatalf("unable to submit job: %v", err)
+		}
+	}
+
+	// For each channel, we'll submit its announcement followed by an
+	// update, which must not be validated before the announcement is
+	// committed, and finally an update with an invalid signature.
+	var depErr error
+	for i := 0; i < numChans; i++ {
+		chanID := lnwire.NewShortChanIDFromInt(uint64(i))
+
+		submit(&lnwire.ChannelAnnouncement{
+			ShortChannelID: chanID,
+		}, func() error {
+			// Slow down the announcements, so their updates would
+			// be validated first if they didn't wait for them.
+			time.Sleep(time.Millisecond)
+			return nil
+		}, true)
+
+		checkDep := func() error {
+			mtx.Lock()
+			defer mtx.Unlock()
+
+			if _, ok := committed[chanID]; !ok && depErr == nil {
+				depErr = fmt.Errorf("update of %v validated "+
+					"before its announcement", chanID)
+			}
+			return nil
+		}
+		update := &lnwire.ChannelUpdate{ShortChannelID: chanID}
+		submit(update, checkDep, true)
+
+		invalid := &lnwire.ChannelUpdate{ShortChannelID: chanID}
+		submit(invalid, checkDep, false)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("jobs weren't committed")
+	}
+
+	mtx.Lock()
+	defer mtx.Unlock()
+
+	if depErr != nil {
+		t.Fatal(depErr)
+	}
+
+	for i, idx := range order {
+		if i != idx {
+			t.Fatalf("job %v was committed at position %v",
+				idx, i)
+		}
+
+		switch {
+		case idx%3 == 2 && errs[idx] == nil:
+			t.Fatalf("expected job %v to fail validation", idx)
+		case idx%3 != 2 && errs[idx] != nil:
+			t.Fatalf("unable to validate job %v: %v", idx,
+				errs[idx])
+		}
+	}
+}
+
+// TestValidationPoolAbandonedJobs asserts that jobs which haven't been
+// committed by the time the validation pool stops are committed with
+// ErrValidationPoolExiting, while jobs which couldn't be submitted are never
+// committed.
+func TestValidationPoolAbandonedJobs(t *testing.T) {
+	t.Parallel()
+
+	pool := NewValidationPool(1, 1)
+	if err := pool.Start(); err != nil {
+		t.Fatalf("unable to start validation pool: %v", err)
+	}
+
+	// We'll submit a job whose validation is held up until after the pool
+	// has been signalled to stop.
+	validating := make(chan struct{})
+	release := make(chan struct{})
+	commitErrs := make(chan error, 1)
+	err := pool.Submit(&ValidationJob{
+		Msg: &lnwire.ChannelAnnouncement{},
+		SigChecks: func() ([]*SigCheck, error) {
+			close(validating)
+			<-release
+			return nil, nil
+		},
+		Commit: func(err error) {
+			commitErrs <- err
+		},
+	})
+	if err != nil {
+		t.Fatalf("unable to submit job: %v", err)
+	}
+
+	select {
+	case <-validating:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("job wasn't validated")
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		pool.Stop()
+		close(stopped)
+	}()
+
+	// Once the pool is stopping, we'll release the job, which should then
+	// be abandoned rather than committed with the outcome of its
+	// validation.
+	<-pool.quit
+	close(release)
+
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("pool didn't stop")
+	}
+
+	select {
+	case err := <-commitErrs:
+		if err != ErrValidationPoolExiting {
+			t.Fatalf("expected ErrValidationPoolExiting, got: %v",
+				err)
+		}
+	default:
+		t.Fatalf("abandoned job wasn't committed")
+	}
+
+	// Jobs submitted once the pool has stopped should be rejected without
+	// ever being committed.
+	err = pool.Submit(&ValidationJob{
+		Msg: &lnwire.ChannelAnnouncement{},
+		Commit: func(err error) {
+			t.Fatalf("rejected job was committed")
+		},
+	})
+	if err != ErrValidationPoolExiting {
+		t.Fatalf("expected ErrValidationPoolExiting, got: %v", err)
+	}
+}
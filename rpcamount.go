@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcutil"
+)
+
+const (
+	// maxRPCSatoshis is the largest amount denominated in satoshis that
+	// we'll accept over RPC. No valid amount can exceed the total supply
+	// of the chain.
+	maxRPCSatoshis = btcutil.Amount(btcutil.MaxSatoshi)
+
+	// maxRPCMilliSatoshis is the largest amount denominated in
+	// milli-satoshis that we'll accept over RPC.
+	maxRPCMilliSatoshis = lnwire.MilliAtom(btcutil.MaxSatoshi * 1000)
+)
+
+// satoshisFromRPC converts a raw amount received over RPC, which is expected
+// to be denominated in satoshis, into a btcutil.Amount. The name of the field
+// the amount was received in is used to annotate any validation error. As the
+// on-chain wallet operates in satoshis while the Lightning Network operates
+// in milli-satoshis, all amounts received over RPC should be converted into
+// their typed unit through either this function or milliSatoshisFromRPC, so
+// that any later mismatch between the units is caught at compile time.
+func satoshisFromRPC(field string, amt int64) (btcutil.Amount, error) {
+	sat := btcutil.Amount(amt)
+	switch {
+	case sat < 0:
+		return 0, fmt.Errorf("%v must not be negative, got %d sat",
+			field, amt)
+
+	case sat > maxRPCSatoshis:
+		return 0, fmt.Errorf("%v of %d sat exceeds the maximum "+
+			"amount of %d sat", field, amt, int64(maxRPCSatoshis))
+	}
+
+	return sat, nil
+}
+
+// milliSatoshisFromRPC converts a raw amount received over RPC, which is
+// expected to be denominated in milli-satoshis, into an lnwire.MilliAtom.
+// The name of the field the amount was received in is used to annotate any
+// validation error.
+func milliSatoshisFromRPC(field string, amt int64) (lnwire.MilliAtom, error) {
+	mSat := lnwire.MilliAtom(amt)
+	switch {
+	case mSat < 0:
+		return 0, fmt.Errorf("%v must not be negative, got %d mSAT",
+			field, amt)
+
+	case mSat > maxRPCMilliSatoshis:
+		return 0, fmt.Errorf("%v of %d mSAT exceeds the maximum "+
+			"amount of %d mSAT", field, amt,
+			int64(maxRPCMilliSatoshis))
+	}
+
+	return mSat, nil
+}
+
+// satoshisToRPC converts an amount denominated in satoshis into the raw form
+// used by the RPC fields which are denominated in satoshis. An amount
+// denominated in milli-satoshis must first be explicitly converted via
+// ToSatoshis.
+func satoshisToRPC(amt btcutil.Amount) int64 {
+	return int64(amt)
+}
+
+// milliSatoshisToRPC converts an amount denominated in milli-satoshis into the
+// raw form used by the RPC fields which are denominated in milli-satoshis.
+func milliSatoshisToRPC(amt lnwire.MilliAtom) int64 {
+	return int64(amt)
+}
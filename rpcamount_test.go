@@ -0,0 +1,58 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcutil"
+)
+
+// TestRPCAmountValidation asserts that amounts received over RPC are rejected
+// if they're negative or exceed the maximum amount of their unit.
+func TestRPCAmountValidation(t *testing.T) {
+	t.Parallel()
+
+	satTests := []struct {
+		amt   int64
+		valid bool
+	}{
+		{amt: 0, valid: true},
+		{amt: 100000, valid: true},
+		{amt: btcutil.MaxSatoshi, valid: true},
+		{amt: btcutil.MaxSatoshi + 1, valid: false},
+		{amt: -1, valid: false},
+	}
+	for _, test := range satTests {
+		sat, err := satoshisFromRPC("amt", test.amt)
+		switch {
+		case test.valid && err != nil:
+			t.Fatalf("unable to convert %v sat: %v", test.amt, err)
+		case !test.valid && err == nil:
+			t.Fatalf("expected %v sat to be rejected", test.amt)
+		case test.valid && sat != btcutil.Amount(test.amt):
+			t.Fatalf("expected %v sat, got %v", test.amt, sat)
+		}
+	}
+
+	mSatTests := []struct {
+		amt   int64
+		valid bool
+	}{
+		{amt: 0, valid: true},
+		{amt: 1000, valid: true},
+		{amt: btcutil.MaxSatoshi * 1000, valid: true},
+		{amt: btcutil.MaxSatoshi*1000 + 1, valid: false},
+		{amt: -1, valid: false},
+	}
+	for _, test := range mSatTests {
+		mSat, err := milliSatoshisFromRPC("amt_msat", test.amt)
+		switch {
+		case test.valid && err != nil:
+			t.Fatalf("unable to convert %v mSAT: %v", test.amt, err)
+		case !test.valid && err == nil:
+			t.Fatalf("expected %v mSAT to be rejected", test.amt)
+		case test.valid && mSat != lnwire.MilliAtom(test.amt):
+			t.Fatalf("expected %v mSAT, got %v", test.amt, mSat)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bytes"
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
@@ -62,13 +63,29 @@ var (
 		"listpayments",
 		"decodepayreq",
 		"feereport",
+		"listblacklist",
+		"closedchannels",
+		"querymissioncontrol",
+		"buildroute",
+		"trackpayment",
+		"queryprobability",
+		"pendingsweeps",
 	}
 )
 
 const (
 	// maxPaymentMSat is the maximum allowed payment permitted currently as
 	// defined in BOLT-0002.
-	maxPaymentMSat = lnwire.MilliSatoshi(math.MaxUint32)
+	maxPaymentMSat = lnwire.MilliAtom(math.MaxUint32)
+
+	// maxSettleConsumers is the maximum number of durable consumers of
+	// invoice settlement events that may be registered at once.
+	maxSettleConsumers = 100
+
+	// settleConsumerIdleTimeout is the duration after which a durable
+	// consumer of invoice settlement events that hasn't connected is
+	// removed, such that events are no longer retained for it.
+	settleConsumerIdleTimeout = 30 * 24 * time.Hour
 )
 
 // rpcServer is a gRPC, RPC front end to the lnd daemon.
@@ -83,6 +100,12 @@ type rpcServer struct {
 
 	server *server
 
+	// activeSettleConsumers tracks the number of streams connected for
+	// each durable consumer of invoice settlement events, ensuring
+	// connected consumers are never pruned as idle.
+	activeSettleConsumers map[string]int
+	settleConsumerMtx     sync.Mutex
+
 	wg sync.WaitGroup
 
 	quit chan struct{}
@@ -95,9 +118,10 @@ var _ lnrpc.LightningServer = (*rpcServer)(nil)
 // newRPCServer creates and returns a new instance of the rpcServer.
 func newRPCServer(s *server, authSvc *bakery.Service) *rpcServer {
 	return &rpcServer{
-		server:  s,
-		authSvc: authSvc,
-		quit:    make(chan struct{}, 1),
+		server:                s,
+		authSvc:               authSvc,
+		activeSettleConsumers: make(map[string]int),
+		quit:                  make(chan struct{}, 1),
 	}
 }
 
@@ -121,6 +145,29 @@ func (r *rpcServer) Stop() error {
 	return nil
 }
 
+// upfrontShutdownFromRPC converts the close address of an open channel
+// request into the upfront shutdown script we'll commit to. If no address was
+// specified, then a nil script is returned.
+func upfrontShutdownFromRPC(closeAddr string) ([]byte, error) {
+	if closeAddr == "" {
+		return nil, nil
+	}
+
+	addr, err := btcutil.DecodeAddress(closeAddr, activeNetParams.Params)
+	if err != nil {
+		return nil, fmt.Errorf("invalid close address: %v", err)
+	}
+	script, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		return nil, err
+	}
+	if err := lnwallet.ValidateUpfrontShutdownScript(script); err != nil {
+		return nil, err
+	}
+
+	return script, nil
+}
+
 // addrPairsToOutputs converts a map describing a set of outputs to be created,
 // the outputs themselves. The passed map pairs up an address, to a desired
 // output value amount. Each address is converted to its corresponding pkScript
@@ -138,7 +185,12 @@ func addrPairsToOutputs(addrPairs map[string]int64) ([]*wire.TxOut, error) {
 			return nil, err
 		}
 
-		outputs = append(outputs, wire.NewTxOut(amt, pkscript))
+		sat, err := satoshisFromRPC("amount", amt)
+		if err != nil {
+			return nil, err
+		}
+
+		outputs = append(outputs, wire.NewTxOut(int64(sat), pkscript))
 	}
 
 	return outputs, nil
@@ -377,19 +429,13 @@ func (r *rpcServer) ConnectPeer(ctx context.Context,
 		return nil, fmt.Errorf("cannot make connection to self")
 	}
 
-	// If the address doesn't already have a port, we'll assume the current
-	// default port.
-	var addr string
-	_, _, err = net.SplitHostPort(in.Addr.Host)
-	if err != nil {
-		addr = net.JoinHostPort(in.Addr.Host, strconv.Itoa(defaultPeerPort))
-	} else {
-		addr = in.Addr.Host
-	}
-
-	host, err := net.ResolveTCPAddr("tcp", addr)
+	// If the address doesn't already have a port, we'll assume the
+	// default port of the active network.
+	host, err := parsePeerAddr(
+		in.Addr.Host, activeNetParams.peerPort, net.ResolveTCPAddr,
+	)
 	if err != nil {
-		return nil, err
+		return nil, peerAddrRPCError(err)
 	}
 
 	peerAddr := &lnwire.NetAddress{
@@ -487,8 +533,16 @@ func (r *rpcServer) OpenChannel(in *lnrpc.OpenChannelRequest,
 			"not active yet")
 	}
 
-	localFundingAmt := btcutil.Amount(in.LocalFundingAmount)
-	remoteInitialBalance := btcutil.Amount(in.PushSat)
+	localFundingAmt, err := satoshisFromRPC(
+		"local_funding_amount", in.LocalFundingAmount,
+	)
+	if err != nil {
+		return err
+	}
+	remoteInitialBalance, err := satoshisFromRPC("push_sat", in.PushSat)
+	if err != nil {
+		return err
+	}
 
 	// Ensure that the initial balance of the remote party (if pushing
 	// satoshis) does not exceed the amount the local party has requested
@@ -521,10 +575,27 @@ func (r *rpcServer) OpenChannel(in *lnrpc.OpenChannelRequest,
 			"size is: %v (6k sat)", minChannelSize)
 	}
 
+	// If a dust limit for our commitment transaction was specified, then
+	// we'll ensure it falls within the bounds the remote party will
+	// accept.
+	dustLimit, err := satoshisFromRPC("dust_limit", in.DustLimit)
+	if err != nil {
+		return err
+	}
+	if dustLimit != 0 {
+		if err := lnwallet.ValidateDustLimit(dustLimit); err != nil {
+			return err
+		}
+	}
+
+	upfrontShutdown, err := upfrontShutdownFromRPC(in.CloseAddress)
+	if err != nil {
+		return err
+	}
+
 	var (
 		nodePubKey      *btcec.PublicKey
 		nodePubKeyBytes []byte
-		err             error
 	)
 
 	// TODO(roasbeef): also return channel ID?
@@ -545,6 +616,11 @@ func (r *rpcServer) OpenChannel(in *lnrpc.OpenChannelRequest,
 		}
 
 		nodePubKeyBytes = nodePubKey.SerializeCompressed()
+
+		err := r.connectForChannel(nodePubKey, in.NodeAddr)
+		if err != nil {
+			return err
+		}
 	}
 
 	// Instruct the server to trigger the necessary events to attempt to
@@ -552,7 +628,8 @@ func (r *rpcServer) OpenChannel(in *lnrpc.OpenChannelRequest,
 	// be used to consume updates of the state of the pending channel.
 	updateChan, errChan := r.server.OpenChannel(
 		in.TargetPeerId, nodePubKey, localFundingAmt,
-		lnwire.NewMSatFromSatoshis(remoteInitialBalance),
+		lnwire.NewMSatFromSatoshis(remoteInitialBalance), dustLimit,
+		upfrontShutdown,
 	)
 
 	var outpoint wire.OutPoint
@@ -595,6 +672,38 @@ out:
 	return nil
 }
 
+// connectForChannel establishes a connection to the target node at the passed
+// address ahead of opening a channel to it, if we aren't already connected to
+// the node. If the address is empty, then no connection is attempted.
+func (r *rpcServer) connectForChannel(nodePubKey *btcec.PublicKey,
+	nodeAddr string) error {
+
+	if nodeAddr == "" {
+		return nil
+	}
+	if _, err := r.server.FindPeer(nodePubKey); err == nil {
+		return nil
+	}
+
+	host, err := parsePeerAddr(
+		nodeAddr, activeNetParams.peerPort, net.ResolveTCPAddr,
+	)
+	if err != nil {
+		return peerAddrRPCError(err)
+	}
+
+	peerAddr := &lnwire.NetAddress{
+		IdentityKey: nodePubKey,
+		Address:     host,
+		ChainNet:    activeNetParams.Net,
+	}
+	if err := r.server.ConnectToPeer(peerAddr, false); err != nil {
+		return fmt.Errorf("unable to connect to %v: %v", peerAddr, err)
+	}
+
+	return nil
+}
+
 // OpenChannelSync is a synchronous version of the OpenChannel RPC call. This
 // call is meant to be consumed by clients to the REST proxy. As with all other
 // sync calls, all byte slices are instead to be populated as hex encoded
@@ -645,8 +754,20 @@ func (r *rpcServer) OpenChannelSync(ctx context.Context,
 		return nil, err
 	}
 
-	localFundingAmt := btcutil.Amount(in.LocalFundingAmount)
-	remoteInitialBalance := btcutil.Amount(in.PushSat)
+	if err := r.connectForChannel(nodepubKey, in.NodeAddr); err != nil {
+		return nil, err
+	}
+
+	localFundingAmt, err := satoshisFromRPC(
+		"local_funding_amount", in.LocalFundingAmount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	remoteInitialBalance, err := satoshisFromRPC("push_sat", in.PushSat)
+	if err != nil {
+		return nil, err
+	}
 
 	// Ensure that the initial balance of the remote party (if pushing
 	// satoshis) does not execeed the amount the local party has requested
@@ -656,9 +777,25 @@ func (r *rpcServer) OpenChannelSync(ctx context.Context,
 			"initial state must be below the local funding amount")
 	}
 
+	dustLimit, err := satoshisFromRPC("dust_limit", in.DustLimit)
+	if err != nil {
+		return nil, err
+	}
+	if dustLimit != 0 {
+		if err := lnwallet.ValidateDustLimit(dustLimit); err != nil {
+			return nil, err
+		}
+	}
+
+	upfrontShutdown, err := upfrontShutdownFromRPC(in.CloseAddress)
+	if err != nil {
+		return nil, err
+	}
+
 	updateChan, errChan := r.server.OpenChannel(
 		in.TargetPeerId, nodepubKey, localFundingAmt,
-		lnwire.NewMSatFromSatoshis(remoteInitialBalance),
+		lnwire.NewMSatFromSatoshis(remoteInitialBalance), dustLimit,
+		upfrontShutdown,
 	)
 
 	select {
@@ -715,6 +852,14 @@ func (r *rpcServer) CloseChannel(in *lnrpc.CloseChannelRequest,
 	rpcsLog.Tracef("[closechannel] request for ChannelPoint(%v)",
 		chanPoint)
 
+	// A delivery address only applies to a cooperative close, as a force
+	// close pays our funds to a script committed to when the channel was
+	// opened.
+	if force && in.DeliveryAddress != "" {
+		return fmt.Errorf("cannot specify a delivery address when " +
+			"force closing a channel")
+	}
+
 	var (
 		updateChan chan *lnrpc.CloseStatusUpdate
 		errChan    chan error
@@ -725,48 +870,21 @@ func (r *rpcServer) CloseChannel(in *lnrpc.CloseChannelRequest,
 	// transaction here rather than going to the switch as we don't require
 	// interaction from the peer.
 	if force {
-		// As the first part of the force closure, we first fetch the
-		// channel from the database, then execute a direct force
-		// closure broadcasting our current commitment transaction.
-		channel, err := r.fetchActiveChannel(*chanPoint)
-		if err != nil {
-			return err
-		}
-		defer channel.Stop()
-
 		_, bestHeight, err := r.server.cc.chainIO.GetBestBlock()
 		if err != nil {
 			return err
 		}
 
-		// As we're force closing this channel, as a precaution, we'll
-		// ensure that the switch doesn't continue to see this channel
-		// as eligible for forwarding HTLC's. If the peer is online,
-		// then we'll also purge all of its indexes.
-		remotePub := &channel.StateSnapshot().RemoteIdentity
-		if peer, err := r.server.FindPeer(remotePub); err == nil {
-			// TODO(roasbeef): actually get the active channel
-			// instead too?
-			//  * so only need to grab from database
-			peer.WipeChannel(channel)
-		} else {
-			chanID := lnwire.NewChanIDFromOutPoint(channel.ChannelPoint())
-			r.server.htlcSwitch.RemoveLink(chanID)
-		}
-
-		select {
-		case r.server.breachArbiter.settledContracts <- chanPoint:
-		case <-r.quit:
-			return fmt.Errorf("server shutting down")
-		}
-
-		// With the necessary indexes cleaned up, we'll now force close
-		// the channel.
-		closingTxid, closeSummary, err := r.forceCloseChan(channel)
+		// The channel's arbitrator will ensure the switch no longer
+		// sees the channel as eligible for forwarding HTLC's, then
+		// broadcast our commitment transaction. Once broadcast, the
+		// arbitrator will resolve each of the contract's outputs.
+		closeTx, err := r.server.chainArb.ForceCloseContract(*chanPoint)
 		if err != nil {
 			rpcsLog.Errorf("unable to force close transaction: %v", err)
 			return err
 		}
+		closingTxid := closeTx.TxHash()
 
 		// With the transaction broadcast, we send our first update to
 		// the client.
@@ -782,7 +900,7 @@ func (r *rpcServer) CloseChannel(in *lnrpc.CloseChannelRequest,
 		errChan = make(chan error, 1)
 		notifier := r.server.cc.chainNotifier
 		go waitForChanToClose(uint32(bestHeight), notifier, errChan, chanPoint,
-			closingTxid, func() {
+			&closingTxid, func() {
 				// Respond to the local subsystem which
 				// requested the channel closure.
 				updateChan <- &lnrpc.CloseStatusUpdate{
@@ -793,28 +911,29 @@ func (r *rpcServer) CloseChannel(in *lnrpc.CloseChannelRequest,
 						},
 					},
 				}
-
-				// If we didn't have an output active on the
-				// commitment transaction, and had no outgoing
-				// HTLC's then we can mark the channels as
-				// closed as there are no funds to be swept.
-				if closeSummary.SelfOutputSignDesc == nil &&
-					len(closeSummary.HtlcResolutions) == 0 {
-					err := r.server.chanDB.MarkChanFullyClosed(chanPoint)
-					if err != nil {
-						rpcsLog.Errorf("unable to "+
-							"mark channel as closed: %v", err)
-						return
-					}
-				}
 			})
 	} else {
 		// Otherwise, the caller has requested a regular interactive
 		// cooperative channel closure. So we'll forward the request to
 		// the htlc switch which will handle the negotiation and
 		// broadcast details.
+		var deliveryScript []byte
+		if in.DeliveryAddress != "" {
+			addr, err := btcutil.DecodeAddress(
+				in.DeliveryAddress, activeNetParams.Params,
+			)
+			if err != nil {
+				return fmt.Errorf("invalid delivery address: %v",
+					err)
+			}
+			deliveryScript, err = txscript.PayToAddrScript(addr)
+			if err != nil {
+				return err
+			}
+		}
+
 		updateChan, errChan = r.server.htlcSwitch.CloseLink(chanPoint,
-			htlcswitch.CloseRegular)
+			htlcswitch.CloseRegular, deliveryScript)
 	}
 out:
 	for {
@@ -851,25 +970,13 @@ out:
 // fetchActiveChannel attempts to locate a channel identified by it's channel
 // point from the database's set of all currently opened channels.
 func (r *rpcServer) fetchActiveChannel(chanPoint wire.OutPoint) (*lnwallet.LightningChannel, error) {
-	dbChannels, err := r.server.chanDB.FetchAllChannels()
-	if err != nil {
-		return nil, err
-	}
-
-	// With the channels fetched, attempt to locate the target channel
-	// according to its channel point.
-	var dbChan *channeldb.OpenChannel
-	for _, dbChannel := range dbChannels {
-		if dbChannel.FundingOutpoint == chanPoint {
-			dbChan = dbChannel
-			break
-		}
-	}
-
 	// If the channel cannot be located, then we exit with an error to the
 	// caller.
-	if dbChan == nil {
+	dbChan, err := r.server.chanDB.FetchChannel(chanPoint)
+	if err == channeldb.ErrChannelNotFound {
 		return nil, fmt.Errorf("unable to find channel")
+	} else if err != nil {
+		return nil, err
 	}
 
 	// Otherwise, we create a fully populated channel state machine which
@@ -878,72 +985,6 @@ func (r *rpcServer) fetchActiveChannel(chanPoint wire.OutPoint) (*lnwallet.Light
 		r.server.cc.feeEstimator, dbChan)
 }
 
-// forceCloseChan executes a unilateral close of the target channel by
-// broadcasting the current commitment state directly on-chain. Once the
-// commitment transaction has been broadcast, a struct describing the final
-// state of the channel is sent to the utxoNursery in order to ultimately sweep
-// the immature outputs.
-func (r *rpcServer) forceCloseChan(channel *lnwallet.LightningChannel) (*chainhash.Hash, *lnwallet.ForceCloseSummary, error) {
-
-	// Execute a unilateral close shutting down all further channel
-	// operation.
-	closeSummary, err := channel.ForceClose()
-	if err != nil {
-		return nil, nil, err
-	}
-
-	closeTx := closeSummary.CloseTx
-	txid := closeTx.TxHash()
-
-	// With the close transaction in hand, broadcast the transaction to the
-	// network, thereby entering the postk channel resolution state.
-	rpcsLog.Infof("Broadcasting force close transaction, ChannelPoint(%v): %v",
-		channel.ChannelPoint(), newLogClosure(func() string {
-			return spew.Sdump(closeTx)
-		}))
-	if err := r.server.cc.wallet.PublishTransaction(closeTx); err != nil {
-		return nil, nil, err
-	}
-
-	// Now that the closing transaction has been broadcast successfully,
-	// we'll mark this channel as being in the pending closed state. The
-	// UTXO nursery will mark the channel as fully closed once all the
-	// outputs have been swept.
-	//
-	// TODO(roasbeef): don't set local balance if close summary detects
-	// dust output?
-	chanPoint := channel.ChannelPoint()
-	chanInfo := channel.StateSnapshot()
-	closeInfo := &channeldb.ChannelCloseSummary{
-		ChanPoint:   *chanPoint,
-		ClosingTXID: closeTx.TxHash(),
-		RemotePub:   &chanInfo.RemoteIdentity,
-		Capacity:    chanInfo.Capacity,
-		CloseType:   channeldb.ForceClose,
-		IsPending:   true,
-	}
-
-	// If our commitment output isn't dust or we have active HTLC's on the
-	// commitment transaction, then we'll populate the balances on the
-	// close channel summary.
-	if closeSummary.SelfOutputSignDesc != nil ||
-		len(closeSummary.HtlcResolutions) == 0 {
-
-		closeInfo.SettledBalance = chanInfo.LocalBalance.ToSatoshis()
-		closeInfo.TimeLockedBalance = chanInfo.LocalBalance.ToSatoshis()
-	}
-
-	if err := channel.DeleteState(closeInfo); err != nil {
-		return nil, nil, err
-	}
-
-	// Send the closed channel summary over to the utxoNursery in order to
-	// have its outputs swept back into the wallet once they're mature.
-	r.server.utxoNursery.IncubateOutputs(closeSummary)
-
-	return &txid, closeSummary, nil
-}
-
 // GetInfo returns general information concerning the lightning node including
 // it's identity pubkey, alias, the chains it is connected to, and information
 // concerning the number of open+pending channels.
@@ -1035,8 +1076,8 @@ func (r *rpcServer) ListPeers(ctx context.Context,
 		// peer.
 		chans := serverPeer.ChannelSnapshots()
 		for _, c := range chans {
-			satSent += int64(c.TotalMilliSatoshisSent.ToSatoshis())
-			satRecv += int64(c.TotalMilliSatoshisReceived.ToSatoshis())
+			satSent += satoshisToRPC(c.TotalMilliSatoshisSent.ToSatoshis())
+			satRecv += satoshisToRPC(c.TotalMilliSatoshisReceived.ToSatoshis())
 		}
 
 		nodePub := serverPeer.addr.IdentityKey.SerializeCompressed()
@@ -1084,7 +1125,7 @@ func (r *rpcServer) WalletBalance(ctx context.Context,
 	rpcsLog.Debugf("[walletbalance] balance=%v", balance)
 
 	return &lnrpc.WalletBalanceResponse{
-		Balance: int64(balance),
+		Balance: satoshisToRPC(balance),
 	}, nil
 }
 
@@ -1113,7 +1154,7 @@ func (r *rpcServer) ChannelBalance(ctx context.Context,
 		}
 	}
 
-	return &lnrpc.ChannelBalanceResponse{Balance: int64(balance)}, nil
+	return &lnrpc.ChannelBalanceResponse{Balance: satoshisToRPC(balance)}, nil
 }
 
 // PendingChannels returns a list of all the channels that are currently
@@ -1162,12 +1203,12 @@ func (r *rpcServer) PendingChannels(ctx context.Context,
 			Channel: &lnrpc.PendingChannelResponse_PendingChannel{
 				RemoteNodePub: hex.EncodeToString(pub),
 				ChannelPoint:  pendingChan.FundingOutpoint.String(),
-				Capacity:      int64(pendingChan.Capacity),
-				LocalBalance:  int64(pendingChan.LocalBalance.ToSatoshis()),
-				RemoteBalance: int64(pendingChan.RemoteBalance.ToSatoshis()),
+				Capacity:      satoshisToRPC(pendingChan.Capacity),
+				LocalBalance:  satoshisToRPC(pendingChan.LocalBalance.ToSatoshis()),
+				RemoteBalance: satoshisToRPC(pendingChan.RemoteBalance.ToSatoshis()),
 			},
 			CommitWeight: commitWeight,
-			CommitFee:    int64(pendingChan.CommitFee),
+			CommitFee:    satoshisToRPC(pendingChan.CommitFee),
 			FeePerKw:     int64(pendingChan.FeePerKw),
 			// TODO(roasbeef): need to track confirmation height
 		}
@@ -1192,8 +1233,8 @@ func (r *rpcServer) PendingChannels(ctx context.Context,
 		channel := &lnrpc.PendingChannelResponse_PendingChannel{
 			RemoteNodePub: hex.EncodeToString(pub),
 			ChannelPoint:  chanPoint.String(),
-			Capacity:      int64(pendingClose.Capacity),
-			LocalBalance:  int64(pendingClose.SettledBalance),
+			Capacity:      satoshisToRPC(pendingClose.Capacity),
+			LocalBalance:  satoshisToRPC(pendingClose.SettledBalance),
 		}
 
 		closeTXID := pendingClose.ClosingTXID.String()
@@ -1246,6 +1287,23 @@ func (r *rpcServer) PendingChannels(ctx context.Context,
 						uint32(currentHeight))
 				}
 
+				// Each of the channel's outgoing HTLCs which
+				// are still being incubated are listed along
+				// with the maturity of their current stage.
+				for _, htlc := range nurseryInfo.htlcs {
+					pendingHtlc := &lnrpc.PendingHTLC{
+						Amount:         int64(htlc.amount),
+						Outpoint:       htlc.outpoint.String(),
+						MaturityHeight: htlc.maturityHeight,
+						BlocksTilMaturity: int32(htlc.maturityHeight) -
+							currentHeight,
+						Stage: htlc.stage,
+					}
+					forceClose.PendingHtlcs = append(
+						forceClose.PendingHtlcs, pendingHtlc,
+					)
+				}
+
 				resp.TotalLimboBalance += int64(nurseryInfo.limboBalance)
 			}
 
@@ -1303,6 +1361,10 @@ func (r *rpcServer) ListChannels(ctx context.Context,
 			peerOnline = true
 		}
 
+		// Channels which aren't in the default state are frozen, so
+		// they're never active regardless of the peer's status.
+		chanUsable := dbChannel.HasChanStatus(channeldb.ChanStatusDefault)
+
 		// As this is required for display purposes, we'll calculate
 		// the weight of the commitment transaction. We also add on the
 		// estimated weight of the witness to calculate the weight of
@@ -1313,26 +1375,32 @@ func (r *rpcServer) ListChannels(ctx context.Context,
 		commitWeight := commitBaseWeight + lnwallet.WitnessCommitmentTxWeight
 
 		channel := &lnrpc.ActiveChannel{
-			Active:                peerOnline,
+			Active:                peerOnline && chanUsable,
 			RemotePubkey:          nodeID,
 			ChannelPoint:          chanPoint.String(),
 			ChanId:                chanID,
-			Capacity:              int64(dbChannel.Capacity),
-			LocalBalance:          int64(dbChannel.LocalBalance.ToSatoshis()),
-			RemoteBalance:         int64(dbChannel.RemoteBalance.ToSatoshis()),
-			CommitFee:             int64(dbChannel.CommitFee),
+			Capacity:              satoshisToRPC(dbChannel.Capacity),
+			LocalBalance:          satoshisToRPC(dbChannel.LocalBalance.ToSatoshis()),
+			RemoteBalance:         satoshisToRPC(dbChannel.RemoteBalance.ToSatoshis()),
+			CommitFee:             satoshisToRPC(dbChannel.CommitFee),
 			CommitWeight:          commitWeight,
 			FeePerKw:              int64(dbChannel.FeePerKw),
-			TotalSatoshisSent:     int64(dbChannel.TotalMSatSent.ToSatoshis()),
-			TotalSatoshisReceived: int64(dbChannel.TotalMSatReceived.ToSatoshis()),
+			TotalSatoshisSent:     satoshisToRPC(dbChannel.TotalMSatSent.ToSatoshis()),
+			TotalSatoshisReceived: satoshisToRPC(dbChannel.TotalMSatReceived.ToSatoshis()),
 			NumUpdates:            dbChannel.NumUpdates,
 			PendingHtlcs:          make([]*lnrpc.HTLC, len(dbChannel.Htlcs)),
+			ChanStatusFlags:       dbChannel.ChanStatus.String(),
+			LocalDustLimit:        satoshisToRPC(dbChannel.LocalChanCfg.DustLimit),
+			RemoteDustLimit:       satoshisToRPC(dbChannel.RemoteChanCfg.DustLimit),
+			Initiator:             dbChannel.IsInitiator,
+			ConfirmationHeight:    dbChannel.FundingConfirmationHeight,
+			FundingFee:            satoshisToRPC(dbChannel.FundingFee),
 		}
 
 		for i, htlc := range dbChannel.Htlcs {
 			channel.PendingHtlcs[i] = &lnrpc.HTLC{
 				Incoming:         htlc.Incoming,
-				Amount:           int64(htlc.Amt),
+				Amount:           milliSatoshisToRPC(htlc.Amt),
 				HashLock:         htlc.RHash[:],
 				ExpirationHeight: htlc.RefundTimeout,
 			}
@@ -1344,30 +1412,107 @@ func (r *rpcServer) ListChannels(ctx context.Context,
 	return resp, nil
 }
 
-// savePayment saves a successfully completed payment to the database for
-// historical record keeping.
-func (r *rpcServer) savePayment(route *routing.Route, amount lnwire.MilliSatoshi, rHash []byte) error {
+// ClosedChannels returns a description of all the closed channels that this
+// node was a participant in, optionally filtered by the manner in which each
+// channel was closed.
+func (r *rpcServer) ClosedChannels(ctx context.Context,
+	in *lnrpc.ClosedChannelsRequest) (*lnrpc.ClosedChannelsResponse, error) {
 
-	paymentPath := make([][33]byte, len(route.Hops))
-	for i, hop := range route.Hops {
-		hopPub := hop.Channel.Node.PubKey.SerializeCompressed()
-		copy(paymentPath[i][:], hopPub)
+	// Check macaroon to see if this is allowed.
+	if r.authSvc != nil {
+		if err := macaroons.ValidateMacaroon(ctx, "closedchannels",
+			r.authSvc); err != nil {
+			return nil, err
+		}
 	}
 
-	payment := &channeldb.OutgoingPayment{
-		Invoice: channeldb.Invoice{
-			Terms: channeldb.ContractTerm{
-				Value: amount,
-			},
-			CreationDate: time.Now(),
-		},
-		Path:           paymentPath,
-		Fee:            route.TotalFees,
-		TimeLockLength: route.TotalTimeLock,
+	// If the caller didn't specify any filters, then we'll return the
+	// channels of all closure types.
+	filterResults := in.Cooperative || in.Force || in.Breach ||
+		in.FundingCanceled
+
+	resp := &lnrpc.ClosedChannelsResponse{}
+
+	dbChannels, err := r.server.chanDB.FetchClosedChannels(false)
+	switch {
+	case err == channeldb.ErrNoClosedChannels:
+		return resp, nil
+	case err != nil:
+		return nil, err
 	}
-	copy(payment.PaymentHash[:], rHash)
 
-	return r.server.chanDB.AddPayment(payment)
+	for _, dbChannel := range dbChannels {
+		var closeType lnrpc.ChannelCloseSummary_ClosureType
+		switch dbChannel.CloseType {
+		case channeldb.CooperativeClose:
+			if filterResults && !in.Cooperative {
+				continue
+			}
+			closeType = lnrpc.ChannelCloseSummary_COOPERATIVE_CLOSE
+
+		case channeldb.ForceClose:
+			if filterResults && !in.Force {
+				continue
+			}
+			closeType = lnrpc.ChannelCloseSummary_FORCE_CLOSE
+
+		case channeldb.BreachClose:
+			if filterResults && !in.Breach {
+				continue
+			}
+			closeType = lnrpc.ChannelCloseSummary_BREACH_CLOSE
+
+		case channeldb.FundingCanceled:
+			if filterResults && !in.FundingCanceled {
+				continue
+			}
+			closeType = lnrpc.ChannelCloseSummary_FUNDING_CANCELED
+		}
+
+		channel := &lnrpc.ChannelCloseSummary{
+			ChannelPoint:      dbChannel.ChanPoint.String(),
+			ClosingTxHash:     dbChannel.ClosingTXID.String(),
+			Capacity:          satoshisToRPC(dbChannel.Capacity),
+			SettledBalance:    satoshisToRPC(dbChannel.SettledBalance),
+			TimeLockedBalance: satoshisToRPC(dbChannel.TimeLockedBalance),
+			CloseType:         closeType,
+			Pending:           dbChannel.IsPending,
+		}
+		if dbChannel.RemotePub != nil {
+			channel.RemotePubkey = hex.EncodeToString(
+				dbChannel.RemotePub.SerializeCompressed(),
+			)
+		}
+
+		for _, htlc := range dbChannel.HtlcResolutions {
+			var outcome lnrpc.HTLCResolution_Outcome
+			switch htlc.Outcome {
+			case channeldb.HtlcOutcomeTimeout:
+				outcome = lnrpc.HTLCResolution_TIMEOUT
+			case channeldb.HtlcOutcomeUnclaimed:
+				outcome = lnrpc.HTLCResolution_UNCLAIMED
+			case channeldb.HtlcOutcomeDust:
+				outcome = lnrpc.HTLCResolution_DUST
+			}
+
+			// Copy the payment hash as the loop variable is reused
+			// on each iteration.
+			rHash := htlc.RHash
+			channel.HtlcResolutions = append(channel.HtlcResolutions,
+				&lnrpc.HTLCResolution{
+					Incoming:         htlc.Incoming,
+					AmountMsat:       uint64(htlc.Amt),
+					HashLock:         rHash[:],
+					ExpirationHeight: htlc.RefundTimeout,
+					Outcome:          outcome,
+				},
+			)
+		}
+
+		resp.Channels = append(resp.Channels, channel)
+	}
+
+	return resp, nil
 }
 
 // SendPayment dispatches a bi-directional streaming RPC for sending payments
@@ -1481,17 +1626,48 @@ func (r *rpcServer) SendPayment(paymentStream lnrpc.Lightning_SendPaymentServer)
 			// Currently, within the bootstrap phase of the
 			// network, we limit the largest payment size allotted
 			// to (2^32) - 1 mSAT or 4.29 million satoshis.
-			amt := btcutil.Amount(nextPayment.Amt)
+			amt, pErr := satoshisFromRPC("amt", nextPayment.Amt)
 			amtMSat := lnwire.NewMSatFromSatoshis(amt)
-			if amtMSat > maxPaymentMSat {
-				// In this case, we'll send an error to the
-				// caller, but continue our loop for the next
-				// payment.
-				pErr := fmt.Errorf("payment of %v is too "+
+			if pErr == nil && amtMSat > maxPaymentMSat {
+				pErr = fmt.Errorf("payment of %v is too "+
 					"large, max payment allowed is %v",
 					nextPayment.Amt,
 					maxPaymentMSat.ToSatoshis())
-
+			}
+			var feeLimit btcutil.Amount
+			if pErr == nil {
+				feeLimit, pErr = satoshisFromRPC(
+					"fee_limit_sat", nextPayment.FeeLimitSat,
+				)
+			}
+			var lastHop *btcec.PublicKey
+			if pErr == nil {
+				lastHop, pErr = parseLastHop(
+					nextPayment.LastHopPubkey,
+				)
+			}
+			var feeLimitPercent uint32
+			if pErr == nil {
+				feeLimitPercent, pErr = parseFeeLimitPercent(
+					nextPayment.FeeLimitPercent,
+				)
+			}
+			var paymentAddr *[32]byte
+			if pErr == nil {
+				paymentAddr, pErr = parsePaymentAddr(
+					nextPayment.PaymentAddr,
+				)
+			}
+			var routeHints [][]routing.HopHint
+			if pErr == nil {
+				routeHints, pErr = unmarshalRouteHints(
+					nextPayment.RouteHints,
+				)
+			}
+			if pErr != nil {
+				// In this case, we'll send an error to the
+				// caller, but continue our loop for the next
+				// payment.
 				if err := paymentStream.Send(&lnrpc.SendResponse{
 					PaymentError: pErr.Error(),
 				}); err != nil {
@@ -1536,31 +1712,35 @@ func (r *rpcServer) SendPayment(paymentStream lnrpc.Lightning_SendPaymentServer)
 				// returned. Otherwise, we'll get a non-nil
 				// error.
 				payment := &routing.LightningPayment{
-					Target:      destNode,
-					Amount:      amtMSat,
-					PaymentHash: rHash,
+					Target:         destNode,
+					Amount:         amtMSat,
+					PaymentHash:    rHash,
+					PaymentRequest: []byte(nextPayment.PaymentRequest),
+					FeeLimit: lnwire.NewMSatFromSatoshis(
+						feeLimit,
+					),
+					FeeLimitPercent:    feeLimitPercent,
+					CltvLimit:          nextPayment.CltvLimit,
+					OutgoingChannelIDs: nextPayment.OutgoingChanIds,
+					LastHop:            lastHop,
+					PaymentAddr:        paymentAddr,
+					MaxParts:           nextPayment.MaxParts,
+					RouteHints:         routeHints,
 				}
 				preImage, route, err := r.server.chanRouter.SendPayment(payment)
 				if err != nil {
 					// If we receive payment error than,
 					// instead of terminating the stream,
 					// send error response to the user.
-					err := paymentStream.Send(&lnrpc.SendResponse{
-						PaymentError: err.Error(),
-					})
+					err := paymentStream.Send(
+						paymentFailureResponse(err),
+					)
 					if err != nil {
 						errChan <- err
 					}
 					return
 				}
 
-				// Save the completed payment to the database
-				// for record keeping purposes.
-				if err := r.savePayment(route, amtMSat, rHash[:]); err != nil {
-					errChan <- err
-					return
-				}
-
 				err = paymentStream.Send(&lnrpc.SendResponse{
 					PaymentPreimage: preImage[:],
 					PaymentRoute:    marshalRoute(route),
@@ -1643,7 +1823,10 @@ func (r *rpcServer) SendPaymentSync(ctx context.Context,
 			return nil, err
 		}
 
-		amt = btcutil.Amount(nextPayment.Amt)
+		amt, err = satoshisFromRPC("amt", nextPayment.Amt)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// Currently, within the bootstrap phase of the network, we limit the
@@ -1656,21 +1839,53 @@ func (r *rpcServer) SendPaymentSync(ctx context.Context,
 			maxPaymentMSat.ToSatoshis())
 	}
 
+	feeLimit, err := satoshisFromRPC("fee_limit_sat", nextPayment.FeeLimitSat)
+	if err != nil {
+		return nil, err
+	}
+	feeLimitPercent, err := parseFeeLimitPercent(
+		nextPayment.FeeLimitPercent,
+	)
+	if err != nil {
+		return nil, err
+	}
+	lastHop, err := parseLastHop(nextPayment.LastHopPubkey)
+	if err != nil {
+		return nil, err
+	}
+	paymentAddr, err := parsePaymentAddr(nextPayment.PaymentAddr)
+	if err != nil {
+		return nil, err
+	}
+	routeHints, err := unmarshalRouteHints(nextPayment.RouteHints)
+	if err != nil {
+		return nil, err
+	}
+
 	// Finally, send a payment request to the channel router. If the
 	// payment succeeds, then the returned route will be that was used
 	// successfully within the payment.
 	preImage, route, err := r.server.chanRouter.SendPayment(&routing.LightningPayment{
-		Target:      destPub,
-		Amount:      amtMSat,
-		PaymentHash: rHash,
+		Target:             destPub,
+		Amount:             amtMSat,
+		PaymentHash:        rHash,
+		PaymentRequest:     []byte(nextPayment.PaymentRequest),
+		FeeLimit:           lnwire.NewMSatFromSatoshis(feeLimit),
+		FeeLimitPercent:    feeLimitPercent,
+		CltvLimit:          nextPayment.CltvLimit,
+		OutgoingChannelIDs: nextPayment.OutgoingChanIds,
+		LastHop:            lastHop,
+		PaymentAddr:        paymentAddr,
+		MaxParts:           nextPayment.MaxParts,
+		RouteHints:         routeHints,
 	})
-	if err != nil {
-		return nil, err
-	}
 
-	// With the payment completed successfully, we now ave the details of
-	// the completed payment to the database for historical record keeping.
-	if err := r.savePayment(route, amtMSat, rHash[:]); err != nil {
+	// If the payment itself failed, then we'll report the reason it
+	// failed within the response, as done for streaming payments.
+	if _, ok := err.(*routing.PaymentError); ok {
+		return paymentFailureResponse(err), nil
+	}
+	if err != nil {
 		return nil, err
 	}
 
@@ -1680,32 +1895,159 @@ func (r *rpcServer) SendPaymentSync(ctx context.Context,
 	}, nil
 }
 
-// AddInvoice attempts to add a new invoice to the invoice database. Any
-// duplicated invoices are rejected, therefore all invoices *must* have a
-// unique payment preimage.
-func (r *rpcServer) AddInvoice(ctx context.Context,
-	invoice *lnrpc.Invoice) (*lnrpc.AddInvoiceResponse, error) {
+// SendToRouteSync attempts to send a payment over the passed route, making a
+// single attempt which is recorded within the payments database. If the
+// payment fails at a node along the route, then the node is identified within
+// the response, along with the failure message it sent.
+func (r *rpcServer) SendToRouteSync(ctx context.Context,
+	req *lnrpc.SendToRouteRequest) (*lnrpc.SendToRouteResponse, error) {
 
 	// Check macaroon to see if this is allowed.
 	if r.authSvc != nil {
-		if err := macaroons.ValidateMacaroon(ctx, "addinvoice",
+		if err := macaroons.ValidateMacaroon(ctx, "sendpayment",
 			r.authSvc); err != nil {
 			return nil, err
 		}
 	}
 
-	var paymentPreimage [32]byte
+	// We don't allow payments to be sent while the daemon itself is still
+	// syncing as we may be trying to sent a payment over a "stale"
+	// channel.
+	if !r.server.Started() {
+		return nil, fmt.Errorf("chain backend is still syncing, server " +
+			"not active yet")
+	}
 
+	var (
+		rHash    [32]byte
+		circular bool
+	)
 	switch {
-	// If a preimage wasn't specified, then we'll generate a new preimage
-	// from fresh cryptographic randomness.
-	case len(invoice.RPreimage) == 0:
-		if _, err := rand.Read(paymentPreimage[:]); err != nil {
-			return nil, err
+	case len(req.PaymentHash) != 0:
+		if len(req.PaymentHash) != len(rHash) {
+			return nil, fmt.Errorf("payment hash must be exactly "+
+				"%v bytes, is instead %v", len(rHash),
+				len(req.PaymentHash))
 		}
+		copy(rHash[:], req.PaymentHash)
 
-	// Otherwise, if a preimage was specified, then it MUST be exactly
-	// 32-bytes.
+	case req.PaymentHashString != "":
+		paymentHash, err := hex.DecodeString(req.PaymentHashString)
+		if err != nil {
+			return nil, err
+		}
+		if len(paymentHash) != len(rHash) {
+			return nil, fmt.Errorf("payment hash must be exactly "+
+				"%v bytes, is instead %v", len(rHash),
+				len(paymentHash))
+		}
+		copy(rHash[:], paymentHash)
+
+	// Without a payment hash, the route must lead back to our own node,
+	// which settles the payment itself.
+	default:
+		circular = true
+	}
+
+	route, err := unmarshalRoute(req.Route)
+	if err != nil {
+		return nil, err
+	}
+	if route.TotalAmount > maxPaymentMSat {
+		return nil, fmt.Errorf("payment of %v is too large, max payment "+
+			"allowed is %v", route.TotalAmount.ToSatoshis(),
+			maxPaymentMSat.ToSatoshis())
+	}
+
+	var preimage [32]byte
+	if circular {
+		rpcsLog.Debugf("[sendtoroute] circular payment, amt=%v, "+
+			"hops=%v", route.TotalAmount, len(route.Hops))
+
+		preimage, err = r.server.chanRouter.SendCircularPayment(route)
+	} else {
+		rpcsLog.Debugf("[sendtoroute] payment_hash=%x, amt=%v, "+
+			"hops=%v", rHash[:], route.TotalAmount, len(route.Hops))
+
+		preimage, err = r.server.chanRouter.SendToRoute(rHash, route)
+	}
+
+	// If the payment failed along the route, then we'll report the node
+	// that caused the failure within the response.
+	if routeErr, ok := err.(*routing.RouteError); ok {
+		if routeErr.FailureSourceIdx < 0 {
+			return nil, routeErr
+		}
+
+		failure := &lnrpc.RouteFailure{
+			FailureSourceIndex: uint32(routeErr.FailureSourceIdx),
+		}
+		if fErr, ok := routeErr.Err.(*htlcswitch.ForwardingError); ok {
+			failure.Code = uint32(fErr.FailureCode)
+		}
+		if routeErr.FailureMessage != nil {
+			var b bytes.Buffer
+			err := lnwire.EncodeFailure(
+				&b, routeErr.FailureMessage, 0,
+			)
+			if err != nil {
+				return nil, err
+			}
+			failure.Message = b.Bytes()
+		}
+
+		return &lnrpc.SendToRouteResponse{
+			Failure: failure,
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.SendToRouteResponse{
+		PaymentPreimage: preimage[:],
+	}, nil
+}
+
+// AddInvoice attempts to add a new invoice to the invoice database. Any
+// duplicated invoices are rejected, therefore all invoices *must* have a
+// unique payment preimage.
+func (r *rpcServer) AddInvoice(ctx context.Context,
+	invoice *lnrpc.Invoice) (*lnrpc.AddInvoiceResponse, error) {
+
+	// Check macaroon to see if this is allowed.
+	if r.authSvc != nil {
+		if err := macaroons.ValidateMacaroon(ctx, "addinvoice",
+			r.authSvc); err != nil {
+			return nil, err
+		}
+	}
+
+	var (
+		paymentPreimage [32]byte
+		paymentHash     [32]byte
+	)
+
+	switch {
+	// If a payment hash was specified without a preimage, then this is a
+	// hold invoice, whose preimage is only provided once it's settled.
+	// The payment hash MUST be exactly 32-bytes.
+	case len(invoice.RPreimage) == 0 && len(invoice.RHash) > 0:
+		if len(invoice.RHash) != 32 {
+			return nil, fmt.Errorf("payment hash must be exactly "+
+				"32 bytes, is instead %v", len(invoice.RHash))
+		}
+		copy(paymentHash[:], invoice.RHash)
+
+	// If a preimage wasn't specified, then we'll generate a new preimage
+	// from fresh cryptographic randomness.
+	case len(invoice.RPreimage) == 0:
+		if _, err := rand.Read(paymentPreimage[:]); err != nil {
+			return nil, err
+		}
+
+	// Otherwise, if a preimage was specified, then it MUST be exactly
+	// 32-bytes.
 	case len(invoice.RPreimage) > 0 && len(invoice.RPreimage) != 32:
 		return nil, fmt.Errorf("payment preimage must be exactly "+
 			"32 bytes, is instead %v", len(invoice.RPreimage))
@@ -1727,7 +2069,10 @@ func (r *rpcServer) AddInvoice(ctx context.Context,
 			"(maxsize=%v)", len(invoice.Receipt), channeldb.MaxReceiptSize)
 	}
 
-	amt := btcutil.Amount(invoice.Value)
+	amt, err := satoshisFromRPC("value", invoice.Value)
+	if err != nil {
+		return nil, err
+	}
 	amtMSat := lnwire.NewMSatFromSatoshis(amt)
 	switch {
 	// The value of an invoice MUST NOT be zero.
@@ -1746,11 +2091,19 @@ func (r *rpcServer) AddInvoice(ctx context.Context,
 		Memo:         []byte(invoice.Memo),
 		Receipt:      invoice.Receipt,
 		Terms: channeldb.ContractTerm{
-			Value: amtMSat,
+			Value:       amtMSat,
+			PaymentHash: paymentHash,
 		},
 	}
 	copy(i.Terms.PaymentPreimage[:], paymentPreimage[:])
 
+	// Each invoice is given a random payment address, which payments split
+	// into multiple shards must carry to prove that they were sent by the
+	// payer the invoice was given to.
+	if _, err := rand.Read(i.Terms.PaymentAddr[:]); err != nil {
+		return nil, err
+	}
+
 	rpcsLog.Tracef("[addinvoice] adding new invoice %v",
 		newLogClosure(func() string {
 			return spew.Sdump(i)
@@ -1761,9 +2114,18 @@ func (r *rpcServer) AddInvoice(ctx context.Context,
 		return nil, err
 	}
 
-	// Next, generate the payment hash itself from the preimage. This will
-	// be used by clients to query for the state of a particular invoice.
-	rHash := sha256.Sum256(paymentPreimage[:])
+	// The payment hash has been either given, or generated from the
+	// preimage when the invoice was added. This will be used by clients
+	// to query for the state of a particular invoice.
+	rHash := i.Terms.PaymentHash
+
+	// Our channels which haven't been announced to the network are unknown
+	// to the payer, so we'll provide hints describing them, allowing the
+	// payer to reach us through them.
+	routeHints, err := r.selectRouteHints(amtMSat)
+	if err != nil {
+		return nil, err
+	}
 
 	// Finally we also create an encoded payment request which allows the
 	// caller to compactly send the invoice to the payer.
@@ -1776,9 +2138,236 @@ func (r *rpcServer) AddInvoice(ctx context.Context,
 	return &lnrpc.AddInvoiceResponse{
 		RHash:          rHash[:],
 		PaymentRequest: payReqString,
+		AddIndex:       i.AddIndex,
+		PaymentAddr:    i.Terms.PaymentAddr[:],
+		RouteHints:     marshalRouteHints(routeHints),
 	}, nil
 }
 
+// maxRouteHints is the maximum number of route hints provided along with an
+// invoice.
+const maxRouteHints = 20
+
+// selectRouteHints returns a route hint for each of our active channels which
+// hasn't been announced to the network, and whose remote party is able to
+// forward the passed amount to us over it. Channels for which we don't know
+// the remote party's policy yet are skipped.
+func (r *rpcServer) selectRouteHints(
+	amt lnwire.MilliAtom) ([][]routing.HopHint, error) {
+
+	graph := r.server.chanDB.ChannelGraph()
+
+	dbChannels, err := r.server.chanDB.FetchAllChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	var routeHints [][]routing.HopHint
+	for _, dbChannel := range dbChannels {
+		if len(routeHints) == maxRouteHints {
+			break
+		}
+
+		// Only channels which are able to carry the payment to us
+		// right now are of use to the payer.
+		switch {
+		case dbChannel.IsPending:
+			continue
+		case !dbChannel.HasChanStatus(channeldb.ChanStatusDefault):
+			continue
+		case dbChannel.RemoteBalance < amt:
+			continue
+		}
+		remotePub := dbChannel.IdentityPub
+		if _, err := r.server.FindPeer(remotePub); err != nil {
+			continue
+		}
+
+		chanID := dbChannel.ShortChanID.ToUint64()
+		info, p1, p2, err := graph.FetchChannelEdgesByID(chanID)
+		if err != nil {
+			rpcsLog.Debugf("Unable to fetch edges of channel %v: %v",
+				dbChannel.FundingOutpoint, err)
+			continue
+		}
+
+		// Channels which have been announced are already known to
+		// the payer.
+		if info.AuthProof != nil {
+			continue
+		}
+
+		// The hint describes the channel in the direction from the
+		// remote party towards us, so it carries their policy.
+		remotePolicy := p2
+		if info.NodeKey1.IsEqual(remotePub) {
+			remotePolicy = p1
+		}
+		if remotePolicy == nil {
+			continue
+		}
+
+		routeHints = append(routeHints, []routing.HopHint{{
+			NodeID:                    remotePub,
+			ChannelID:                 chanID,
+			FeeBaseMSat:               remotePolicy.FeeBaseMSat,
+			FeeProportionalMillionths: remotePolicy.FeeProportionalMillionths,
+			CLTVExpiryDelta:           remotePolicy.TimeLockDelta,
+		}})
+	}
+
+	return routeHints, nil
+}
+
+// marshalRouteHints converts the passed route hints to their RPC form.
+func marshalRouteHints(routeHints [][]routing.HopHint) []*lnrpc.RouteHint {
+	rpcHints := make([]*lnrpc.RouteHint, 0, len(routeHints))
+	for _, routeHint := range routeHints {
+		rpcHint := &lnrpc.RouteHint{
+			HopHints: make([]*lnrpc.HopHint, 0, len(routeHint)),
+		}
+		for _, hopHint := range routeHint {
+			rpcHint.HopHints = append(rpcHint.HopHints, &lnrpc.HopHint{
+				NodeId: hex.EncodeToString(
+					hopHint.NodeID.SerializeCompressed(),
+				),
+				ChanId:      hopHint.ChannelID,
+				FeeBaseMsat: uint32(hopHint.FeeBaseMSat),
+				FeeProportionalMillionths: uint32(
+					hopHint.FeeProportionalMillionths,
+				),
+				CltvExpiryDelta: uint32(hopHint.CLTVExpiryDelta),
+			})
+		}
+		rpcHints = append(rpcHints, rpcHint)
+	}
+
+	return rpcHints
+}
+
+// unmarshalRouteHints parses the route hints of a payment received over RPC.
+func unmarshalRouteHints(
+	rpcHints []*lnrpc.RouteHint) ([][]routing.HopHint, error) {
+
+	routeHints := make([][]routing.HopHint, 0, len(rpcHints))
+	for _, rpcHint := range rpcHints {
+		if len(rpcHint.HopHints) == 0 {
+			return nil, fmt.Errorf("route hint has no hops")
+		}
+
+		routeHint := make([]routing.HopHint, 0, len(rpcHint.HopHints))
+		for _, hopHint := range rpcHint.HopHints {
+			pubBytes, err := hex.DecodeString(hopHint.NodeId)
+			if err != nil {
+				return nil, fmt.Errorf("invalid hop hint node "+
+					"id: %v", err)
+			}
+			nodeID, err := btcec.ParsePubKey(pubBytes, btcec.S256())
+			if err != nil {
+				return nil, fmt.Errorf("invalid hop hint node "+
+					"id: %v", err)
+			}
+			if hopHint.CltvExpiryDelta > math.MaxUint16 {
+				return nil, fmt.Errorf("invalid hop hint cltv "+
+					"expiry delta of %v",
+					hopHint.CltvExpiryDelta)
+			}
+
+			routeHint = append(routeHint, routing.HopHint{
+				NodeID:      nodeID,
+				ChannelID:   hopHint.ChanId,
+				FeeBaseMSat: lnwire.MilliAtom(hopHint.FeeBaseMsat),
+				FeeProportionalMillionths: lnwire.MilliAtom(
+					hopHint.FeeProportionalMillionths,
+				),
+				CLTVExpiryDelta: uint16(hopHint.CltvExpiryDelta),
+			})
+		}
+		routeHints = append(routeHints, routeHint)
+	}
+
+	return routeHints, nil
+}
+
+// createRPCInvoice creates an RPC invoice from the passed invoice stored
+// within the database.
+func (r *rpcServer) createRPCInvoice(invoice *channeldb.Invoice) *lnrpc.Invoice {
+	rHash := invoice.Terms.PaymentHash
+	satAmt := invoice.Terms.Value.ToSatoshis()
+
+	// The preimage of a hold invoice is only revealed once settled.
+	var preimage []byte
+	if invoice.Terms.PaymentPreimage != channeldb.UnknownPreimage {
+		preimage = invoice.Terms.PaymentPreimage[:]
+	}
+
+	// Invoices added before payment addresses were stored have none.
+	var paymentAddr []byte
+	if invoice.Terms.PaymentAddr != [32]byte{} {
+		paymentAddr = invoice.Terms.PaymentAddr[:]
+	}
+
+	var state lnrpc.Invoice_InvoiceState
+	switch invoice.Terms.State {
+	case channeldb.ContractOpen:
+		state = lnrpc.Invoice_OPEN
+	case channeldb.ContractSettled:
+		state = lnrpc.Invoice_SETTLED
+	case channeldb.ContractCanceled:
+		state = lnrpc.Invoice_CANCELED
+	case channeldb.ContractAccepted:
+		state = lnrpc.Invoice_ACCEPTED
+	}
+
+	// The invoice is considered settled at the time its first HTLC was
+	// settled. Invoices settled before HTLCs were recorded carry no
+	// settle date.
+	var settleDate int64
+	htlcs := make([]*lnrpc.InvoiceHTLC, 0, len(invoice.Htlcs))
+	for _, htlc := range invoice.Htlcs {
+		// The HTLC held for an accepted hold invoice hasn't been
+		// settled yet.
+		var settleTime int64
+		if !htlc.SettleTime.IsZero() {
+			settleTime = htlc.SettleTime.Unix()
+		}
+		if settleDate == 0 {
+			settleDate = settleTime
+		}
+
+		htlcs = append(htlcs, &lnrpc.InvoiceHTLC{
+			ChanId:       htlc.ChanID.ToUint64(),
+			HtlcIndex:    htlc.HtlcID,
+			AmtMsat:      uint64(htlc.Amt),
+			ExpiryHeight: htlc.Expiry,
+			AcceptHeight: htlc.AcceptHeight,
+			SettleTime:   settleTime,
+		})
+	}
+
+	return &lnrpc.Invoice{
+		Memo:         string(invoice.Memo[:]),
+		Receipt:      invoice.Receipt[:],
+		RHash:        rHash[:],
+		RPreimage:    preimage,
+		Value:        satoshisToRPC(satAmt),
+		CreationDate: invoice.CreationDate.Unix(),
+		SettleDate:   settleDate,
+		Settled:      invoice.Terms.State == channeldb.ContractSettled,
+		PaymentRequest: zpay32.Encode(&zpay32.PaymentRequest{
+			Destination: r.server.identityPriv.PubKey(),
+			PaymentHash: rHash,
+			Amount:      satAmt,
+		}),
+		AddIndex:    invoice.AddIndex,
+		SettleIndex: invoice.SettleIndex,
+		Htlcs:       htlcs,
+		AmtPaidMsat: uint64(invoice.AmtPaid()),
+		State:       state,
+		PaymentAddr: paymentAddr,
+	}
+}
+
 // LookupInvoice attemps to look up an invoice according to its payment hash.
 // The passed payment hash *must* be exactly 32 bytes, if not an error is
 // returned.
@@ -1829,22 +2418,7 @@ func (r *rpcServer) LookupInvoice(ctx context.Context,
 			return spew.Sdump(invoice)
 		}))
 
-	preimage := invoice.Terms.PaymentPreimage
-	satAmt := invoice.Terms.Value.ToSatoshis()
-	return &lnrpc.Invoice{
-		Memo:         string(invoice.Memo[:]),
-		Receipt:      invoice.Receipt[:],
-		RHash:        rHash,
-		RPreimage:    preimage[:],
-		Value:        int64(satAmt),
-		CreationDate: invoice.CreationDate.Unix(),
-		Settled:      invoice.Terms.Settled,
-		PaymentRequest: zpay32.Encode(&zpay32.PaymentRequest{
-			Destination: r.server.identityPriv.PubKey(),
-			PaymentHash: sha256.Sum256(preimage[:]),
-			Amount:      satAmt,
-		}),
-	}, nil
+	return r.createRPCInvoice(invoice), nil
 }
 
 // ListInvoices returns a list of all the invoices currently stored within the
@@ -1867,26 +2441,7 @@ func (r *rpcServer) ListInvoices(ctx context.Context,
 
 	invoices := make([]*lnrpc.Invoice, len(dbInvoices))
 	for i, dbInvoice := range dbInvoices {
-		invoiceAmount := dbInvoice.Terms.Value.ToSatoshis()
-		paymentPreimge := dbInvoice.Terms.PaymentPreimage[:]
-		rHash := sha256.Sum256(paymentPreimge)
-
-		invoice := &lnrpc.Invoice{
-			Memo:         string(dbInvoice.Memo[:]),
-			Receipt:      dbInvoice.Receipt[:],
-			RHash:        rHash[:],
-			RPreimage:    paymentPreimge,
-			Value:        int64(invoiceAmount),
-			Settled:      dbInvoice.Terms.Settled,
-			CreationDate: dbInvoice.CreationDate.Unix(),
-			PaymentRequest: zpay32.Encode(&zpay32.PaymentRequest{
-				Destination: r.server.identityPriv.PubKey(),
-				PaymentHash: sha256.Sum256(paymentPreimge),
-				Amount:      invoiceAmount,
-			}),
-		}
-
-		invoices[i] = invoice
+		invoices[i] = r.createRPCInvoice(dbInvoice)
 	}
 
 	return &lnrpc.ListInvoiceResponse{
@@ -1907,118 +2462,389 @@ func (r *rpcServer) SubscribeInvoices(req *lnrpc.InvoiceSubscription,
 		}
 	}
 
+	// We'll register for notifications before querying for any backlog,
+	// ensuring that no events can slip through the gap between the two.
 	invoiceClient := r.server.invoices.SubscribeNotifications()
 	defer invoiceClient.Cancel()
 
-	for {
-		select {
-		// TODO(roasbeef): include newly added invoices?
-		case settledInvoice := <-invoiceClient.SettledInvoices:
-			preImage := settledInvoice.Terms.PaymentPreimage[:]
-			rHash := sha256.Sum256(preImage)
-			invoice := &lnrpc.Invoice{
-				Memo:      string(settledInvoice.Memo[:]),
-				Receipt:   settledInvoice.Receipt[:],
-				RHash:     rHash[:],
-				RPreimage: preImage,
-				Value:     int64(settledInvoice.Terms.Value.ToSatoshis()),
-				Settled:   settledInvoice.Terms.Settled,
-			}
-			if err := updateStream.Send(invoice); err != nil {
-				return err
-			}
-		case <-r.quit:
-			return nil
-		}
+	// If the caller specified an add or settle index, then we'll first
+	// deliver all events they've missed since those indexes. We track
+	// the latest index delivered from the backlog so any live
+	// notifications which were also part of the backlog aren't sent
+	// twice.
+	lastAddIndex := req.AddIndex
+	addedInvoices, err := r.server.chanDB.InvoicesAddedSince(req.AddIndex)
+	if err != nil && err != channeldb.ErrNoInvoicesCreated {
+		return err
 	}
-}
-
-// SubscribeTransactions creates a uni-directional stream (server -> client) in
-// which any newly discovered transactions relevant to the wallet are sent
-// over.
-func (r *rpcServer) SubscribeTransactions(req *lnrpc.GetTransactionsRequest,
-	updateStream lnrpc.Lightning_SubscribeTransactionsServer) error {
-
-	// Check macaroon to see if this is allowed.
-	if r.authSvc != nil {
-		if err := macaroons.ValidateMacaroon(updateStream.Context(),
-			"gettransactions", r.authSvc); err != nil {
+	for _, invoice := range addedInvoices {
+		if err := updateStream.Send(r.createRPCInvoice(invoice)); err != nil {
 			return err
 		}
+		lastAddIndex = invoice.AddIndex
 	}
 
-	txClient, err := r.server.cc.wallet.SubscribeTransactions()
-	if err != nil {
+	lastSettleIndex := req.SettleIndex
+	settledInvoices, err := r.server.chanDB.InvoicesSettledSince(
+		req.SettleIndex,
+	)
+	if err != nil && err != channeldb.ErrNoInvoicesCreated {
 		return err
 	}
-	defer txClient.Cancel()
+	for _, invoice := range settledInvoices {
+		if err := updateStream.Send(r.createRPCInvoice(invoice)); err != nil {
+			return err
+		}
+		lastSettleIndex = invoice.SettleIndex
+	}
 
 	for {
 		select {
-		case tx := <-txClient.ConfirmedTransactions():
-			detail := &lnrpc.Transaction{
-				TxHash:           tx.Hash.String(),
-				Amount:           int64(tx.Value),
-				NumConfirmations: tx.NumConfirmations,
-				BlockHash:        tx.BlockHash.String(),
-				TimeStamp:        tx.Timestamp,
-				TotalFees:        tx.TotalFees,
+		case newInvoice := <-invoiceClient.NewInvoices:
+			if newInvoice.AddIndex <= lastAddIndex {
+				continue
 			}
-			if err := updateStream.Send(detail); err != nil {
+
+			invoice := r.createRPCInvoice(newInvoice)
+			if err := updateStream.Send(invoice); err != nil {
 				return err
 			}
-		case tx := <-txClient.UnconfirmedTransactions():
-			detail := &lnrpc.Transaction{
-				TxHash:    tx.Hash.String(),
-				Amount:    int64(tx.Value),
-				TimeStamp: tx.Timestamp,
-				TotalFees: tx.TotalFees,
+
+		case settledInvoice := <-invoiceClient.SettledInvoices:
+			if settledInvoice.SettleIndex <= lastSettleIndex {
+				continue
 			}
-			if err := updateStream.Send(detail); err != nil {
+
+			invoice := r.createRPCInvoice(settledInvoice)
+			if err := updateStream.Send(invoice); err != nil {
 				return err
 			}
+
+		case <-updateStream.Context().Done():
+			return nil
+
 		case <-r.quit:
 			return nil
 		}
 	}
 }
 
-// GetTransactions returns a list of describing all the known transactions
-// relevant to the wallet.
-func (r *rpcServer) GetTransactions(ctx context.Context,
-	_ *lnrpc.GetTransactionsRequest) (*lnrpc.TransactionDetails, error) {
+// SubscribeSettledInvoices dispatches a bi-directional streaming RPC which
+// delivers invoice settlement events to a durable consumer. Events are
+// re-delivered until the consumer acknowledges them, providing at-least-once
+// delivery even if the consumer was offline when the invoice was settled. As
+// consumers are persisted, registering and acknowledging events requires
+// permission to write invoices.
+func (r *rpcServer) SubscribeSettledInvoices(
+	eventStream lnrpc.Lightning_SubscribeSettledInvoicesServer) error {
 
 	// Check macaroon to see if this is allowed.
 	if r.authSvc != nil {
-		if err := macaroons.ValidateMacaroon(ctx, "gettransactions",
-			r.authSvc); err != nil {
-			return nil, err
+		if err := macaroons.ValidateMacaroon(eventStream.Context(),
+			"writeinvoices", r.authSvc); err != nil {
+			return err
 		}
 	}
 
-	// TODO(btcsuite): add pagination support
-	transactions, err := r.server.cc.wallet.ListTransactionDetails()
+	// The first message sent by the client identifies the consumer, and
+	// may also acknowledge any events it processed before reconnecting.
+	req, err := eventStream.Recv()
 	if err != nil {
-		return nil, err
+		return err
+	}
+	consumerID := req.ConsumerId
+	if consumerID == "" {
+		return fmt.Errorf("consumer_id must be specified")
 	}
 
-	txDetails := &lnrpc.TransactionDetails{
-		Transactions: make([]*lnrpc.Transaction, len(transactions)),
+	// Before registering the consumer, we'll remove any consumers that
+	// haven't connected for too long, so they don't count towards the
+	// maximum number of consumers.
+	active := r.connectSettleConsumer(consumerID)
+	defer r.disconnectSettleConsumer(consumerID)
+
+	pruned, err := r.server.chanDB.PruneSettleConsumers(
+		time.Now().Add(-settleConsumerIdleTimeout), active,
+	)
+	if err != nil {
+		return err
 	}
-	for i, tx := range transactions {
-		txDetails.Transactions[i] = &lnrpc.Transaction{
-			TxHash:           tx.Hash.String(),
-			Amount:           int64(tx.Value),
-			NumConfirmations: tx.NumConfirmations,
-			BlockHash:        tx.BlockHash.String(),
-			BlockHeight:      tx.BlockHeight,
-			TimeStamp:        tx.Timestamp,
-			TotalFees:        tx.TotalFees,
-		}
+	for _, prunedID := range pruned {
+		rpcsLog.Infof("Removed idle settle event consumer %v", prunedID)
 	}
 
-	return txDetails, nil
-}
+	_, err = r.server.chanDB.RegisterSettleConsumer(
+		consumerID, maxSettleConsumers,
+	)
+	if err != nil {
+		return err
+	}
+
+	// Once the stream ends, we'll mark the consumer as active, such that
+	// it's only considered idle from the point it disconnected.
+	defer func() {
+		err := r.server.chanDB.AckSettleEvents(consumerID, 0)
+		if err != nil {
+			rpcsLog.Errorf("Unable to mark settle event consumer "+
+				"%v as active: %v", consumerID, err)
+		}
+	}()
+	if req.SettleIndex != 0 {
+		err := r.server.chanDB.AckSettleEvents(consumerID, req.SettleIndex)
+		if err != nil {
+			return err
+		}
+	}
+
+	rpcsLog.Debugf("Settle event consumer %v connected", consumerID)
+
+	// We'll register for notifications before querying for any pending
+	// events, ensuring that no settlements can slip through the gap
+	// between the two.
+	invoiceClient := r.server.invoices.SubscribeNotifications()
+	defer invoiceClient.Cancel()
+
+	// Launch a goroutine to read acknowledgements from the client, so we
+	// can continue to deliver events while waiting on them.
+	errChan := make(chan error, 1)
+	ackChan := make(chan uint64)
+	reqQuit := make(chan struct{})
+	defer close(reqQuit)
+	go func() {
+		for {
+			req, err := eventStream.Recv()
+			if err != nil {
+				if err == io.EOF {
+					err = nil
+				}
+				select {
+				case errChan <- err:
+				case <-reqQuit:
+				}
+				return
+			}
+
+			select {
+			case ackChan <- req.SettleIndex:
+			case <-reqQuit:
+				return
+			}
+		}
+	}()
+
+	// sendPending delivers all pending events for the consumer which
+	// haven't yet been sent over this stream. The database remains the
+	// source of truth, so events are always delivered in order of their
+	// settle index.
+	var sentIndex uint64
+	sendPending := func() error {
+		pending, err := r.server.chanDB.PendingSettleEvents(consumerID)
+		if err != nil {
+			return err
+		}
+		for _, invoice := range pending {
+			if invoice.SettleIndex <= sentIndex {
+				continue
+			}
+
+			if err := eventStream.Send(r.createRPCInvoice(invoice)); err != nil {
+				return err
+			}
+			sentIndex = invoice.SettleIndex
+		}
+
+		return nil
+	}
+
+	if err := sendPending(); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-invoiceClient.NewInvoices:
+
+		case <-invoiceClient.SettledInvoices:
+			if err := sendPending(); err != nil {
+				return err
+			}
+
+		case settleIndex := <-ackChan:
+			err := r.server.chanDB.AckSettleEvents(
+				consumerID, settleIndex,
+			)
+			if err != nil {
+				return err
+			}
+
+		case err := <-errChan:
+			return err
+
+		case <-eventStream.Context().Done():
+			return nil
+
+		case <-r.quit:
+			return nil
+		}
+	}
+}
+
+// connectSettleConsumer marks the target durable consumer of invoice
+// settlement events as connected, returning the set of all connected
+// consumers.
+func (r *rpcServer) connectSettleConsumer(
+	consumerID string) map[string]struct{} {
+
+	r.settleConsumerMtx.Lock()
+	defer r.settleConsumerMtx.Unlock()
+
+	r.activeSettleConsumers[consumerID]++
+
+	active := make(map[string]struct{}, len(r.activeSettleConsumers))
+	for id := range r.activeSettleConsumers {
+		active[id] = struct{}{}
+	}
+
+	return active
+}
+
+// disconnectSettleConsumer marks a stream of the target durable consumer of
+// invoice settlement events as disconnected.
+func (r *rpcServer) disconnectSettleConsumer(consumerID string) {
+	r.settleConsumerMtx.Lock()
+	defer r.settleConsumerMtx.Unlock()
+
+	r.activeSettleConsumers[consumerID]--
+	if r.activeSettleConsumers[consumerID] <= 0 {
+		delete(r.activeSettleConsumers, consumerID)
+	}
+}
+
+// RemoveSettleConsumer removes a durable consumer of invoice settlement events
+// registered through SubscribeSettledInvoices, such that no further events are
+// retained for it. The consumer must not be connected.
+func (r *rpcServer) RemoveSettleConsumer(ctx context.Context,
+	in *lnrpc.RemoveSettleConsumerRequest) (
+	*lnrpc.RemoveSettleConsumerResponse, error) {
+
+	// Check macaroon to see if this is allowed.
+	if r.authSvc != nil {
+		if err := macaroons.ValidateMacaroon(ctx, "writeinvoices",
+			r.authSvc); err != nil {
+			return nil, err
+		}
+	}
+
+	if in.ConsumerId == "" {
+		return nil, fmt.Errorf("consumer_id must be specified")
+	}
+
+	// The consumer's record is deleted while holding the mutex, ensuring
+	// it can't connect in the meantime.
+	r.settleConsumerMtx.Lock()
+	defer r.settleConsumerMtx.Unlock()
+
+	if _, ok := r.activeSettleConsumers[in.ConsumerId]; ok {
+		return nil, fmt.Errorf("settle event consumer %v is connected",
+			in.ConsumerId)
+	}
+
+	err := r.server.chanDB.RemoveSettleConsumer(in.ConsumerId)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcsLog.Infof("Removed settle event consumer %v", in.ConsumerId)
+
+	return &lnrpc.RemoveSettleConsumerResponse{}, nil
+}
+
+// SubscribeTransactions creates a uni-directional stream (server -> client) in
+// which any newly discovered transactions relevant to the wallet are sent
+// over.
+func (r *rpcServer) SubscribeTransactions(req *lnrpc.GetTransactionsRequest,
+	updateStream lnrpc.Lightning_SubscribeTransactionsServer) error {
+
+	// Check macaroon to see if this is allowed.
+	if r.authSvc != nil {
+		if err := macaroons.ValidateMacaroon(updateStream.Context(),
+			"gettransactions", r.authSvc); err != nil {
+			return err
+		}
+	}
+
+	txClient, err := r.server.cc.wallet.SubscribeTransactions()
+	if err != nil {
+		return err
+	}
+	defer txClient.Cancel()
+
+	for {
+		select {
+		case tx := <-txClient.ConfirmedTransactions():
+			detail := &lnrpc.Transaction{
+				TxHash:           tx.Hash.String(),
+				Amount:           satoshisToRPC(tx.Value),
+				NumConfirmations: tx.NumConfirmations,
+				BlockHash:        tx.BlockHash.String(),
+				TimeStamp:        tx.Timestamp,
+				TotalFees:        tx.TotalFees,
+			}
+			if err := updateStream.Send(detail); err != nil {
+				return err
+			}
+		case tx := <-txClient.UnconfirmedTransactions():
+			detail := &lnrpc.Transaction{
+				TxHash:    tx.Hash.String(),
+				Amount:    satoshisToRPC(tx.Value),
+				TimeStamp: tx.Timestamp,
+				TotalFees: tx.TotalFees,
+			}
+			if err := updateStream.Send(detail); err != nil {
+				return err
+			}
+		case <-r.quit:
+			return nil
+		}
+	}
+}
+
+// GetTransactions returns a list of describing all the known transactions
+// relevant to the wallet.
+func (r *rpcServer) GetTransactions(ctx context.Context,
+	_ *lnrpc.GetTransactionsRequest) (*lnrpc.TransactionDetails, error) {
+
+	// Check macaroon to see if this is allowed.
+	if r.authSvc != nil {
+		if err := macaroons.ValidateMacaroon(ctx, "gettransactions",
+			r.authSvc); err != nil {
+			return nil, err
+		}
+	}
+
+	// TODO(btcsuite): add pagination support
+	transactions, err := r.server.cc.wallet.ListTransactionDetails()
+	if err != nil {
+		return nil, err
+	}
+
+	txDetails := &lnrpc.TransactionDetails{
+		Transactions: make([]*lnrpc.Transaction, len(transactions)),
+	}
+	for i, tx := range transactions {
+		txDetails.Transactions[i] = &lnrpc.Transaction{
+			TxHash:           tx.Hash.String(),
+			Amount:           satoshisToRPC(tx.Value),
+			NumConfirmations: tx.NumConfirmations,
+			BlockHash:        tx.BlockHash.String(),
+			BlockHeight:      tx.BlockHeight,
+			TimeStamp:        tx.Timestamp,
+			TotalFees:        tx.TotalFees,
+		}
+	}
+
+	return txDetails, nil
+}
 
 // DescribeGraph returns a description of the latest graph state from the PoV
 // of the node. The graph information is partitioned into two components: all
@@ -2107,14 +2933,14 @@ func marshalDbEdge(edgeInfo *channeldb.ChannelEdgeInfo,
 		LastUpdate: uint32(lastUpdate),
 		Node1Pub:   hex.EncodeToString(edgeInfo.NodeKey1.SerializeCompressed()),
 		Node2Pub:   hex.EncodeToString(edgeInfo.NodeKey2.SerializeCompressed()),
-		Capacity:   int64(edgeInfo.Capacity),
+		Capacity:   satoshisToRPC(edgeInfo.Capacity),
 	}
 
 	if c1 != nil {
 		edge.Node1Policy = &lnrpc.RoutingPolicy{
 			TimeLockDelta:    uint32(c1.TimeLockDelta),
-			MinHtlc:          int64(c1.MinHTLC),
-			FeeBaseMsat:      int64(c1.FeeBaseMSat),
+			MinHtlc:          milliSatoshisToRPC(c1.MinHTLC),
+			FeeBaseMsat:      milliSatoshisToRPC(c1.FeeBaseMSat),
 			FeeRateMilliMsat: int64(c1.FeeProportionalMillionths),
 		}
 	}
@@ -2122,8 +2948,8 @@ func marshalDbEdge(edgeInfo *channeldb.ChannelEdgeInfo,
 	if c2 != nil {
 		edge.Node2Policy = &lnrpc.RoutingPolicy{
 			TimeLockDelta:    uint32(c2.TimeLockDelta),
-			MinHtlc:          int64(c2.MinHTLC),
-			FeeBaseMsat:      int64(c2.FeeBaseMSat),
+			MinHtlc:          milliSatoshisToRPC(c2.MinHTLC),
+			FeeBaseMsat:      milliSatoshisToRPC(c2.FeeBaseMSat),
 			FeeRateMilliMsat: int64(c2.FeeProportionalMillionths),
 		}
 	}
@@ -2228,7 +3054,7 @@ func (r *rpcServer) GetNodeInfo(ctx context.Context,
 			Alias:      node.Alias,
 		},
 		NumChannels:   numChannels,
-		TotalCapacity: int64(totalCapcity),
+		TotalCapacity: satoshisToRPC(totalCapcity),
 	}, nil
 }
 
@@ -2266,17 +3092,60 @@ func (r *rpcServer) QueryRoutes(ctx context.Context,
 	// Currently, within the bootstrap phase of the network, we limit the
 	// largest payment size allotted to (2^32) - 1 mSAT or 4.29 million
 	// satoshis.
-	amt := btcutil.Amount(in.Amt)
+	amt, err := satoshisFromRPC("amt", in.Amt)
+	if err != nil {
+		return nil, err
+	}
 	amtMSat := lnwire.NewMSatFromSatoshis(amt)
 	if amtMSat > maxPaymentMSat {
 		return nil, fmt.Errorf("payment of %v is too large, max payment "+
 			"allowed is %v", amt, maxPaymentMSat.ToSatoshis())
 	}
 
+	// The routes may be restricted to leave over a set of our channels,
+	// or to reach the destination through a specific node.
+	lastHop, err := parseLastHop(in.LastHopPubkey)
+	if err != nil {
+		return nil, err
+	}
+	restrictions := &routing.RouteRestrictions{
+		OutgoingChannelIDs: in.OutgoingChanIds,
+		LastHop:            lastHop,
+	}
+
+	// They may also be required to avoid a set of nodes and directed
+	// edges, which allows callers to iteratively search for alternative
+	// routes.
+	for _, ignoredNode := range in.IgnoredNodes {
+		node, err := btcec.ParsePubKey(ignoredNode, btcec.S256())
+		if err != nil {
+			return nil, fmt.Errorf("invalid ignored node: %v", err)
+		}
+		restrictions.IgnoredNodes = append(
+			restrictions.IgnoredNodes, node,
+		)
+	}
+	if len(in.IgnoredEdges) != 0 {
+		restrictions.IgnoredEdges = make(
+			map[routing.EdgeLocator]struct{}, len(in.IgnoredEdges),
+		)
+	}
+	for _, ignoredEdge := range in.IgnoredEdges {
+		locator := routing.EdgeLocator{
+			ChannelID: ignoredEdge.ChannelId,
+		}
+		if ignoredEdge.DirectionReverse {
+			locator.Direction = 1
+		}
+		restrictions.IgnoredEdges[locator] = struct{}{}
+	}
+
 	// Query the channel router for a possible path to the destination that
 	// can carry `in.Amt` satoshis _including_ the total fee required on
 	// the route.
-	routes, err := r.server.chanRouter.FindRoutes(pubKey, amtMSat)
+	routes, err := r.server.chanRouter.FindRoutes(
+		pubKey, amtMSat, nil, restrictions,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -2293,53 +3162,223 @@ func (r *rpcServer) QueryRoutes(ctx context.Context,
 	return routeResp, nil
 }
 
+// parseLastHop parses the optional last hop restriction of a payment or route
+// query. If no restriction was requested, then a nil public key is returned.
+func parseLastHop(lastHopPubkey []byte) (*btcec.PublicKey, error) {
+	if len(lastHopPubkey) == 0 {
+		return nil, nil
+	}
+
+	lastHop, err := btcec.ParsePubKey(lastHopPubkey, btcec.S256())
+	if err != nil {
+		return nil, fmt.Errorf("invalid last_hop_pubkey: %v", err)
+	}
+
+	return lastHop, nil
+}
+
+// parsePaymentAddr parses the payment address of an invoice being paid over
+// RPC, which is optional.
+func parsePaymentAddr(addr []byte) (*[32]byte, error) {
+	if len(addr) == 0 {
+		return nil, nil
+	}
+	if len(addr) != 32 {
+		return nil, fmt.Errorf("payment_addr must be exactly 32 "+
+			"bytes, is instead %v", len(addr))
+	}
+
+	var paymentAddr [32]byte
+	copy(paymentAddr[:], addr)
+
+	return &paymentAddr, nil
+}
+
+// parseFeeLimitPercent validates the fee limit of a payment received over RPC
+// as a percentage of its amount.
+func parseFeeLimitPercent(percent int64) (uint32, error) {
+	if percent < 0 || percent > math.MaxUint32 {
+		return 0, fmt.Errorf("invalid fee_limit_percent of %d", percent)
+	}
+
+	return uint32(percent), nil
+}
+
 func marshalRoute(route *routing.Route) *lnrpc.Route {
 	resp := &lnrpc.Route{
 		TotalTimeLock: route.TotalTimeLock,
-		TotalFees:     int64(route.TotalFees.ToSatoshis()),
-		TotalAmt:      int64(route.TotalAmount.ToSatoshis()),
+		TotalFees:     satoshisToRPC(route.TotalFees.ToSatoshis()),
+		TotalAmt:      satoshisToRPC(route.TotalAmount.ToSatoshis()),
 		Hops:          make([]*lnrpc.Hop, len(route.Hops)),
+		TotalFeesMsat: milliSatoshisToRPC(route.TotalFees),
+		TotalAmtMsat:  milliSatoshisToRPC(route.TotalAmount),
 	}
 	for i, hop := range route.Hops {
 		resp.Hops[i] = &lnrpc.Hop{
 			ChanId:       hop.Channel.ChannelID,
-			ChanCapacity: int64(hop.Channel.Capacity),
-			AmtToForward: int64(hop.AmtToForward.ToSatoshis()),
-			Fee:          int64(hop.Fee.ToSatoshis()),
+			ChanCapacity: satoshisToRPC(hop.Channel.Capacity),
+			AmtToForward: satoshisToRPC(hop.AmtToForward.ToSatoshis()),
+			Fee:          satoshisToRPC(hop.Fee.ToSatoshis()),
 			Expiry:       uint32(hop.OutgoingTimeLock),
+			PubKey: hex.EncodeToString(
+				hop.Channel.Node.PubKey.SerializeCompressed(),
+			),
+			AmtToForwardMsat: milliSatoshisToRPC(hop.AmtToForward),
+			FeeMsat:          milliSatoshisToRPC(hop.Fee),
 		}
 	}
 
 	return resp
 }
 
-// GetNetworkInfo returns some basic stats about the known channel graph from
-// the PoV of the node.
-func (r *rpcServer) GetNetworkInfo(ctx context.Context,
-	_ *lnrpc.NetworkInfoRequest) (*lnrpc.NetworkInfo, error) {
-
-	// Check macaroon to see if this is allowed.
-	if r.authSvc != nil {
-		if err := macaroons.ValidateMacaroon(ctx, "getnetworkinfo",
-			r.authSvc); err != nil {
-			return nil, err
+// marshalDBRoute converts a route stored within the payments database into
+// its RPC representation.
+func marshalDBRoute(route *channeldb.Route) *lnrpc.Route {
+	resp := &lnrpc.Route{
+		TotalTimeLock: route.TotalTimeLock,
+		TotalFees:     satoshisToRPC(route.TotalFees.ToSatoshis()),
+		TotalAmt:      satoshisToRPC(route.TotalAmount.ToSatoshis()),
+		Hops:          make([]*lnrpc.Hop, len(route.Hops)),
+		TotalFeesMsat: milliSatoshisToRPC(route.TotalFees),
+		TotalAmtMsat:  milliSatoshisToRPC(route.TotalAmount),
+	}
+	for i, hop := range route.Hops {
+		resp.Hops[i] = &lnrpc.Hop{
+			ChanId:           hop.ChannelID,
+			AmtToForward:     satoshisToRPC(hop.AmtToForward.ToSatoshis()),
+			Fee:              satoshisToRPC(hop.Fee.ToSatoshis()),
+			Expiry:           hop.OutgoingTimeLock,
+			PubKey:           hex.EncodeToString(hop.PubKeyBytes[:]),
+			AmtToForwardMsat: milliSatoshisToRPC(hop.AmtToForward),
+			FeeMsat:          milliSatoshisToRPC(hop.Fee),
 		}
 	}
 
-	graph := r.server.chanDB.ChannelGraph()
+	return resp
+}
 
-	var (
-		numNodes             uint32
-		numChannels          uint32
-		maxChanOut           uint32
-		totalNetworkCapacity btcutil.Amount
-		minChannelSize       btcutil.Amount = math.MaxInt64
-		maxChannelSize       btcutil.Amount
-	)
+// unmarshalRoute converts a route specified over RPC, typically obtained from
+// BuildRoute or QueryRoutes, into a route that can be passed to the router.
+// The milli-satoshi amounts of the route are used if set, falling back to the
+// satoshi amounts otherwise.
+func unmarshalRoute(rpcRoute *lnrpc.Route) (*routing.Route, error) {
+	if rpcRoute == nil || len(rpcRoute.Hops) == 0 {
+		return nil, fmt.Errorf("route must have at least one hop")
+	}
+	if len(rpcRoute.Hops) > routing.HopLimit {
+		return nil, fmt.Errorf("route has %v hops, max is %v",
+			len(rpcRoute.Hops), routing.HopLimit)
+	}
 
-	// We'll use this map to de-duplicate channels during our traversal.
-	// This is needed since channels are directional, so there will be two
-	// edges for each channel within the graph.
+	// amtFromRPC returns the milli-satoshi amount of a field if set, or
+	// otherwise converts its satoshi amount.
+	amtFromRPC := func(field string, sat,
+		mSat int64) (lnwire.MilliAtom, error) {
+
+		if mSat != 0 {
+			return milliSatoshisFromRPC(field+"_msat", mSat)
+		}
+
+		amt, err := satoshisFromRPC(field, sat)
+		if err != nil {
+			return 0, err
+		}
+		return lnwire.NewMSatFromSatoshis(amt), nil
+	}
+
+	route := &routing.Route{
+		TotalTimeLock: rpcRoute.TotalTimeLock,
+		Hops:          make([]*routing.Hop, len(rpcRoute.Hops)),
+	}
+
+	var err error
+	route.TotalFees, err = amtFromRPC(
+		"total_fees", rpcRoute.TotalFees, rpcRoute.TotalFeesMsat,
+	)
+	if err != nil {
+		return nil, err
+	}
+	route.TotalAmount, err = amtFromRPC(
+		"total_amt", rpcRoute.TotalAmt, rpcRoute.TotalAmtMsat,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, rpcHop := range rpcRoute.Hops {
+		pubKeyBytes, err := hex.DecodeString(rpcHop.PubKey)
+		if err != nil {
+			return nil, err
+		}
+		pubKey, err := btcec.ParsePubKey(pubKeyBytes, btcec.S256())
+		if err != nil {
+			return nil, err
+		}
+
+		capacity, err := satoshisFromRPC(
+			"chan_capacity", rpcHop.ChanCapacity,
+		)
+		if err != nil {
+			return nil, err
+		}
+		amtToForward, err := amtFromRPC(
+			"amt_to_forward", rpcHop.AmtToForward,
+			rpcHop.AmtToForwardMsat,
+		)
+		if err != nil {
+			return nil, err
+		}
+		fee, err := amtFromRPC("fee", rpcHop.Fee, rpcHop.FeeMsat)
+		if err != nil {
+			return nil, err
+		}
+
+		route.Hops[i] = &routing.Hop{
+			Channel: &routing.ChannelHop{
+				Capacity: capacity,
+				ChannelEdgePolicy: &channeldb.ChannelEdgePolicy{
+					ChannelID: rpcHop.ChanId,
+					Node: &channeldb.LightningNode{
+						PubKey: pubKey,
+					},
+				},
+			},
+			OutgoingTimeLock: rpcHop.Expiry,
+			AmtToForward:     amtToForward,
+			Fee:              fee,
+		}
+	}
+
+	return route, nil
+}
+
+// GetNetworkInfo returns some basic stats about the known channel graph from
+// the PoV of the node.
+func (r *rpcServer) GetNetworkInfo(ctx context.Context,
+	_ *lnrpc.NetworkInfoRequest) (*lnrpc.NetworkInfo, error) {
+
+	// Check macaroon to see if this is allowed.
+	if r.authSvc != nil {
+		if err := macaroons.ValidateMacaroon(ctx, "getnetworkinfo",
+			r.authSvc); err != nil {
+			return nil, err
+		}
+	}
+
+	graph := r.server.chanDB.ChannelGraph()
+
+	var (
+		numNodes             uint32
+		numChannels          uint32
+		maxChanOut           uint32
+		totalNetworkCapacity btcutil.Amount
+		minChannelSize       btcutil.Amount = math.MaxInt64
+		maxChannelSize       btcutil.Amount
+	)
+
+	// We'll use this map to de-duplicate channels during our traversal.
+	// This is needed since channels are directional, so there will be two
+	// edges for each channel within the graph.
 	seenChans := make(map[uint64]struct{})
 
 	// We'll run through all the known nodes in the within our view of the
@@ -2419,11 +3458,13 @@ func (r *rpcServer) GetNetworkInfo(ctx context.Context,
 		AvgOutDegree:         float64(numChannels) / float64(numNodes),
 		NumNodes:             numNodes,
 		NumChannels:          numChannels,
-		TotalNetworkCapacity: int64(totalNetworkCapacity),
+		TotalNetworkCapacity: satoshisToRPC(totalNetworkCapacity),
 		AvgChannelSize:       float64(totalNetworkCapacity) / float64(numChannels),
 
-		MinChannelSize: int64(minChannelSize),
-		MaxChannelSize: int64(maxChannelSize),
+		MinChannelSize: satoshisToRPC(minChannelSize),
+		MaxChannelSize: satoshisToRPC(maxChannelSize),
+
+		NumPrunedNodes: r.server.chanRouter.NumNodesPruned(),
 	}
 
 	// Similarly, if we don't have any channels, then we'll also set the
@@ -2547,11 +3588,11 @@ func marshallTopologyChange(topChange *routing.TopologyChange) *lnrpc.GraphTopol
 				FundingTxid: channelUpdate.ChanPoint.Hash[:],
 				OutputIndex: channelUpdate.ChanPoint.Index,
 			},
-			Capacity: int64(channelUpdate.Capacity),
+			Capacity: satoshisToRPC(channelUpdate.Capacity),
 			RoutingPolicy: &lnrpc.RoutingPolicy{
 				TimeLockDelta:    uint32(channelUpdate.TimeLockDelta),
-				MinHtlc:          int64(channelUpdate.MinHTLC),
-				FeeBaseMsat:      int64(channelUpdate.BaseFee),
+				MinHtlc:          milliSatoshisToRPC(channelUpdate.MinHTLC),
+				FeeBaseMsat:      milliSatoshisToRPC(channelUpdate.BaseFee),
 				FeeRateMilliMsat: int64(channelUpdate.FeeRate),
 			},
 			AdvertisingNode: encodeKey(channelUpdate.AdvertisingNode),
@@ -2563,7 +3604,7 @@ func marshallTopologyChange(topChange *routing.TopologyChange) *lnrpc.GraphTopol
 	for i, closedChan := range topChange.ClosedChannels {
 		closedChans[i] = &lnrpc.ClosedChannelUpdate{
 			ChanId:       closedChan.ChanID,
-			Capacity:     int64(closedChan.Capacity),
+			Capacity:     satoshisToRPC(closedChan.Capacity),
 			ClosedHeight: closedChan.ClosedHeight,
 			ChanPoint: &lnrpc.ChannelPoint{
 				FundingTxid: closedChan.ChanPoint.Hash[:],
@@ -2579,9 +3620,10 @@ func marshallTopologyChange(topChange *routing.TopologyChange) *lnrpc.GraphTopol
 	}
 }
 
-// ListPayments returns a list of all outgoing payments.
+// ListPayments returns a page of the outgoing payments, as specified by the
+// request's index offset, maximum number of payments and direction.
 func (r *rpcServer) ListPayments(ctx context.Context,
-	_ *lnrpc.ListPaymentsRequest) (*lnrpc.ListPaymentsResponse, error) {
+	req *lnrpc.ListPaymentsRequest) (*lnrpc.ListPaymentsResponse, error) {
 
 	// Check macaroon to see if this is allowed.
 	if r.authSvc != nil {
@@ -2591,31 +3633,168 @@ func (r *rpcServer) ListPayments(ctx context.Context,
 		}
 	}
 
-	rpcsLog.Debugf("[ListPayments]")
+	rpcsLog.Debugf("[ListPayments] index_offset=%v, max_payments=%v, "+
+		"reversed=%v, include_incomplete=%v", req.IndexOffset,
+		req.MaxPayments, req.Reversed, req.IncludeIncomplete)
 
-	payments, err := r.server.chanDB.FetchAllPayments()
-	if err != nil && err != channeldb.ErrNoPaymentsCreated {
+	resp, err := r.server.chanDB.QueryPayments(channeldb.PaymentsQuery{
+		IndexOffset:       req.IndexOffset,
+		MaxPayments:       req.MaxPayments,
+		Reversed:          req.Reversed,
+		IncludeIncomplete: req.IncludeIncomplete,
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	paymentsResp := &lnrpc.ListPaymentsResponse{
-		Payments: make([]*lnrpc.Payment, len(payments)),
+		Payments:         make([]*lnrpc.Payment, len(resp.Payments)),
+		FirstIndexOffset: resp.FirstIndexOffset,
+		LastIndexOffset:  resp.LastIndexOffset,
+	}
+	for i, payment := range resp.Payments {
+		paymentsResp.Payments[i] = marshalPayment(payment)
+	}
+
+	return paymentsResp, nil
+}
+
+// marshalFailureReason converts the reason a payment failed into its RPC
+// representation.
+func marshalFailureReason(
+	reason channeldb.FailureReason) lnrpc.PaymentFailureReason {
+
+	switch reason {
+	case channeldb.FailureReasonTimeout:
+		return lnrpc.PaymentFailureReason_FAILURE_REASON_TIMEOUT
+	case channeldb.FailureReasonNoRoute:
+		return lnrpc.PaymentFailureReason_FAILURE_REASON_NO_ROUTE
+	case channeldb.FailureReasonIncorrectPaymentDetails:
+		return lnrpc.PaymentFailureReason_FAILURE_REASON_INCORRECT_PAYMENT_DETAILS
+	case channeldb.FailureReasonInsufficientBalance:
+		return lnrpc.PaymentFailureReason_FAILURE_REASON_INSUFFICIENT_BALANCE
+	case channeldb.FailureReasonPeerOffline:
+		return lnrpc.PaymentFailureReason_FAILURE_REASON_PEER_OFFLINE
+	case channeldb.FailureReasonFeeLimit:
+		return lnrpc.PaymentFailureReason_FAILURE_REASON_FEE_LIMIT
+	}
+
+	return lnrpc.PaymentFailureReason_FAILURE_REASON_ERROR
+}
+
+// paymentFailureDetail returns a human-readable description of the reason a
+// payment failed, suggesting how the failure may be remedied where possible.
+func paymentFailureDetail(reason channeldb.FailureReason) string {
+	switch reason {
+	case channeldb.FailureReasonTimeout:
+		return "the payment timed out before it could be completed"
+	case channeldb.FailureReasonNoRoute:
+		return "unable to find a route to the destination with " +
+			"sufficient capacity"
+	case channeldb.FailureReasonIncorrectPaymentDetails:
+		return "the destination rejected the payment, the invoice " +
+			"may be unknown, expired, or for a different amount"
+	case channeldb.FailureReasonInsufficientBalance:
+		return "insufficient local balance in any channel to send " +
+			"the payment"
+	case channeldb.FailureReasonPeerOffline:
+		return "not connected to the first hop of any route to the " +
+			"destination"
+	case channeldb.FailureReasonFeeLimit:
+		return "every route to the destination requires fees " +
+			"exceeding the fee limit"
+	}
+
+	return "an unexpected error occurred while sending the payment"
+}
+
+// paymentFailureResponse returns the response sent to the caller for a
+// payment which failed with the passed error.
+func paymentFailureResponse(err error) *lnrpc.SendResponse {
+	pErr, ok := err.(*routing.PaymentError)
+	if !ok {
+		return &lnrpc.SendResponse{
+			PaymentError: err.Error(),
+			PaymentFailureReason: marshalFailureReason(
+				channeldb.FailureReasonError,
+			),
+		}
+	}
+
+	return &lnrpc.SendResponse{
+		PaymentError: fmt.Sprintf("%v: %v",
+			paymentFailureDetail(pErr.Reason), pErr),
+		PaymentFailureReason: marshalFailureReason(pErr.Reason),
+	}
+}
+
+// marshalPayment converts a payment stored within the payments database,
+// along with each of its HTLC attempts, into its RPC representation.
+func marshalPayment(payment *channeldb.MPPayment) *lnrpc.Payment {
+	rpcPayment := &lnrpc.Payment{
+		PaymentHash:    hex.EncodeToString(payment.Info.PaymentHash[:]),
+		Value:          satoshisToRPC(payment.Info.Value.ToSatoshis()),
+		CreationDate:   payment.Info.CreationDate.Unix(),
+		PaymentRequest: string(payment.Info.PaymentRequest),
+		Htlcs:          make([]*lnrpc.HTLCAttempt, len(payment.HTLCs)),
+	}
+
+	switch payment.Status {
+	case channeldb.StatusInFlight:
+		rpcPayment.Status = lnrpc.Payment_IN_FLIGHT
+	case channeldb.StatusSucceeded:
+		rpcPayment.Status = lnrpc.Payment_SUCCEEDED
+	case channeldb.StatusFailed:
+		rpcPayment.Status = lnrpc.Payment_FAILED
+	default:
+		rpcPayment.Status = lnrpc.Payment_UNKNOWN
+	}
+
+	if payment.FailureReason != nil {
+		reason := *payment.FailureReason
+		rpcPayment.FailureReason = reason.String()
+		rpcPayment.FailureCode = marshalFailureReason(reason)
+		rpcPayment.FailureDetail = paymentFailureDetail(reason)
 	}
-	for i, payment := range payments {
-		path := make([]string, len(payment.Path))
-		for i, hop := range payment.Path {
-			path[i] = hex.EncodeToString(hop[:])
+
+	for i, htlc := range payment.HTLCs {
+		rpcHtlc := &lnrpc.HTLCAttempt{
+			AttemptId:     htlc.AttemptID,
+			Status:        lnrpc.HTLCAttempt_IN_FLIGHT,
+			Route:         marshalDBRoute(&htlc.Route),
+			AttemptTimeNs: htlc.AttemptTime.UnixNano(),
 		}
 
-		paymentsResp.Payments[i] = &lnrpc.Payment{
-			PaymentHash:  hex.EncodeToString(payment.PaymentHash[:]),
-			Value:        int64(payment.Terms.Value.ToSatoshis()),
-			CreationDate: payment.CreationDate.Unix(),
-			Path:         path,
+		switch {
+		case htlc.Settle != nil:
+			rpcHtlc.Status = lnrpc.HTLCAttempt_SUCCEEDED
+			rpcHtlc.ResolveTimeNs = htlc.Settle.SettleTime.UnixNano()
+
+		case htlc.Failure != nil:
+			rpcHtlc.Status = lnrpc.HTLCAttempt_FAILED
+			rpcHtlc.ResolveTimeNs = htlc.Failure.FailTime.UnixNano()
+			rpcHtlc.Failure = htlc.Failure.Message
 		}
+
+		rpcPayment.Htlcs[i] = rpcHtlc
 	}
 
-	return paymentsResp, nil
+	// If the payment has succeeded, then we'll also populate the path,
+	// fee, and preimage using the settled attempt.
+	if settled := payment.SettledAttempt(); settled != nil {
+		path := make([]string, len(settled.Route.Hops))
+		for i, hop := range settled.Route.Hops {
+			path[i] = hex.EncodeToString(hop.PubKeyBytes[:])
+		}
+
+		rpcPayment.Path = path
+		rpcPayment.Fee = satoshisToRPC(settled.Route.TotalFees.ToSatoshis())
+		rpcPayment.PaymentPreimage = hex.EncodeToString(
+			settled.Settle.Preimage[:],
+		)
+	}
+
+	return rpcPayment
 }
 
 // DeleteAllPayments deletes all outgoing payments from DB.
@@ -2715,7 +3894,7 @@ func (r *rpcServer) DecodePayReq(ctx context.Context,
 	return &lnrpc.PayReq{
 		Destination: hex.EncodeToString(dest),
 		PaymentHash: hex.EncodeToString(payReq.PaymentHash[:]),
-		NumSatoshis: int64(payReq.Amount),
+		NumSatoshis: satoshisToRPC(payReq.Amount),
 	}, nil
 }
 
@@ -2745,6 +3924,46 @@ func (r *rpcServer) FeeReport(ctx context.Context,
 		return nil, err
 	}
 
+	// Next, we'll tally the fees earned over the past day, week, and
+	// month from the forwarding log. The fees of each forward are
+	// attributed to its outgoing channel.
+	type feeSums struct {
+		day, week, month lnwire.MilliAtom
+	}
+	var (
+		now      = time.Now()
+		dayAgo   = now.Add(-24 * time.Hour)
+		weekAgo  = now.Add(-7 * 24 * time.Hour)
+		monthAgo = now.Add(-30 * 24 * time.Hour)
+	)
+	fwdEvents, err := r.server.chanDB.ForwardingEvents(monthAgo)
+	if err != nil {
+		return nil, err
+	}
+
+	var totalFees feeSums
+	chanFees := make(map[lnwire.ShortChannelID]*feeSums)
+	for i := range fwdEvents {
+		event := &fwdEvents[i]
+		fees, ok := chanFees[event.OutgoingChanID]
+		if !ok {
+			fees = &feeSums{}
+			chanFees[event.OutgoingChanID] = fees
+		}
+
+		fee := event.Fee()
+		fees.month += fee
+		totalFees.month += fee
+		if !event.Timestamp.Before(weekAgo) {
+			fees.week += fee
+			totalFees.week += fee
+		}
+		if !event.Timestamp.Before(dayAgo) {
+			fees.day += fee
+			totalFees.day += fee
+		}
+	}
+
 	var feeReports []*lnrpc.ChannelFeeReport
 	err = selfNode.ForEachChannel(nil, func(_ *bolt.Tx, chanInfo *channeldb.ChannelEdgeInfo,
 		edgePolicy, _ *channeldb.ChannelEdgePolicy) error {
@@ -2757,13 +3976,19 @@ func (r *rpcServer) FeeReport(ctx context.Context,
 		feeRateFixedPoint := edgePolicy.FeeProportionalMillionths
 		feeRate := float64(feeRateFixedPoint) / float64(feeBase)
 
-		// TODO(roasbeef): also add stats for revenue for each channel
-		feeReports = append(feeReports, &lnrpc.ChannelFeeReport{
+		report := &lnrpc.ChannelFeeReport{
 			ChanPoint:   chanInfo.ChannelPoint.String(),
-			BaseFeeMsat: int64(edgePolicy.FeeBaseMSat),
+			BaseFeeMsat: milliSatoshisToRPC(edgePolicy.FeeBaseMSat),
 			FeePerMil:   int64(feeRateFixedPoint),
 			FeeRate:     feeRate,
-		})
+		}
+		shortChanID := lnwire.NewShortChanIDFromInt(chanInfo.ChannelID)
+		if fees, ok := chanFees[shortChanID]; ok {
+			report.DayFeeSumMsat = uint64(fees.day)
+			report.WeekFeeSumMsat = uint64(fees.week)
+			report.MonthFeeSumMsat = uint64(fees.month)
+		}
+		feeReports = append(feeReports, report)
 
 		return nil
 	})
@@ -2772,7 +3997,10 @@ func (r *rpcServer) FeeReport(ctx context.Context,
 	}
 
 	return &lnrpc.FeeReportResponse{
-		ChannelFees: feeReports,
+		ChannelFees:     feeReports,
+		DayFeeSumMsat:   uint64(totalFees.day),
+		WeekFeeSumMsat:  uint64(totalFees.week),
+		MonthFeeSumMsat: uint64(totalFees.month),
 	}, nil
 }
 
@@ -2828,7 +4056,12 @@ func (r *rpcServer) UpdateFees(ctx context.Context,
 	// gives us the fixed point, scaled by 1 million that's used within the
 	// protocol.
 	feeRateFixed := uint32(req.FeeRate * feeBase)
-	baseFeeMsat := lnwire.MilliSatoshi(req.BaseFeeMsat)
+	baseFeeMsat, err := milliSatoshisFromRPC(
+		"base_fee_msat", req.BaseFeeMsat,
+	)
+	if err != nil {
+		return nil, err
+	}
 	feeSchema := routing.FeeSchema{
 		BaseFee: baseFeeMsat,
 		FeeRate: feeRateFixed,
@@ -2839,14 +4072,123 @@ func (r *rpcServer) UpdateFees(ctx context.Context,
 		req.BaseFeeMsat, req.FeeRate, feeRateFixed,
 		spew.Sdump(targetChans))
 
+	err = r.applyChannelPolicy(
+		routing.ChannelPolicy{FeeSchema: feeSchema}, targetChans,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.FeeUpdateResponse{}, nil
+}
+
+// UpdateChannelPolicy allows the caller to update the forwarding policy of all
+// channels globally, or a particular channel.
+func (r *rpcServer) UpdateChannelPolicy(ctx context.Context,
+	req *lnrpc.PolicyUpdateRequest) (*lnrpc.PolicyUpdateResponse, error) {
+
+	if r.authSvc != nil {
+		if err := macaroons.ValidateMacaroon(ctx, "updatechannelpolicy",
+			r.authSvc); err != nil {
+			return nil, err
+		}
+	}
+
+	// The policy update must either target all channels, or a single
+	// channel by its channel point.
+	var targetChans []wire.OutPoint
+	switch {
+	case req.Global && req.ChanPoint != nil:
+		return nil, fmt.Errorf("global and chan_point are mutually " +
+			"exclusive")
+
+	case req.Global:
+
+	case req.ChanPoint != nil:
+		txid, err := chainhash.NewHash(req.ChanPoint.FundingTxid)
+		if err != nil {
+			return nil, err
+		}
+		targetChans = append(targetChans, wire.OutPoint{
+			Hash:  *txid,
+			Index: req.ChanPoint.OutputIndex,
+		})
+
+	default:
+		return nil, fmt.Errorf("either global or chan_point must be set")
+	}
+
+	// As with UpdateFees, we'll convert the floating point fee rate into
+	// the fixed point rate used within the protocol.
+	if req.FeeRate < minFeeRate {
+		return nil, fmt.Errorf("fee rate of %v is too small, min fee "+
+			"rate is %v", req.FeeRate, minFeeRate)
+	}
+	baseFeeMsat, err := milliSatoshisFromRPC(
+		"base_fee_msat", req.BaseFeeMsat,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if req.TimeLockDelta > math.MaxUint16 {
+		return nil, fmt.Errorf("time lock delta of %v is too large, "+
+			"max time lock delta is %v", req.TimeLockDelta,
+			math.MaxUint16)
+	}
+
+	// A time lock delta within the expiry grace delta would leave no room
+	// for the grace period, causing every forwarded HTLC to be rejected. A
+	// delta of zero leaves the current one unchanged.
+	if req.TimeLockDelta != 0 && req.TimeLockDelta <= cfg.ExpiryGraceDelta {
+		return nil, fmt.Errorf("time lock delta of %v is too small, "+
+			"it must exceed the expiry grace delta of %v",
+			req.TimeLockDelta, cfg.ExpiryGraceDelta)
+	}
+	minHTLC, err := milliSatoshisFromRPC("min_htlc_msat", req.MinHtlcMsat)
+	if err != nil {
+		return nil, err
+	}
+	maxHTLC, err := milliSatoshisFromRPC("max_htlc_msat", req.MaxHtlcMsat)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := routing.ChannelPolicy{
+		FeeSchema: routing.FeeSchema{
+			BaseFee: baseFeeMsat,
+			FeeRate: uint32(req.FeeRate * feeBase),
+		},
+		TimeLockDelta: uint16(req.TimeLockDelta),
+		MinHTLC:       minHTLC,
+		MaxHTLC:       maxHTLC,
+	}
+
+	rpcsLog.Tracef("[updatechannelpolicy] updating channel policy "+
+		"policy=%v, targets=%v", spew.Sdump(policy),
+		spew.Sdump(targetChans))
+
+	if err := r.applyChannelPolicy(policy, targetChans); err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.PolicyUpdateResponse{}, nil
+}
+
+// applyChannelPolicy applies the passed forwarding policy to the target
+// channels, or to all of our channels if none are targeted. The policy is
+// committed to the graph and broadcast to the network by the gossiper, and
+// applied to the forwarding links of the channels that are currently active.
+func (r *rpcServer) applyChannelPolicy(policy routing.ChannelPolicy,
+	targetChans []wire.OutPoint) error {
+
 	// With the scope resolved, we'll now send this to the
-	// AuthenticatedGossiper so it can propagate the new fee schema for out
+	// AuthenticatedGossiper so it can propagate the new policy for our
 	// target channel(s).
-	err := r.server.authGossiper.PropagateFeeUpdate(
-		feeSchema, targetChans...,
+	err := r.server.authGossiper.PropagateChanPolicyUpdate(
+		policy, targetChans...,
 	)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	// Finally, we'll apply the set of active links amongst the target
@@ -2855,16 +4197,1130 @@ func (r *rpcServer) UpdateFees(ctx context.Context,
 	// We create a partially policy as the logic won't overwrite a valid
 	// sub-policy with a "nil" one.
 	p := htlcswitch.ForwardingPolicy{
-		BaseFee: baseFeeMsat,
-		FeeRate: lnwire.MilliSatoshi(feeRateFixed),
+		BaseFee:       policy.BaseFee,
+		FeeRate:       lnwire.MilliAtom(policy.FeeRate),
+		TimeLockDelta: uint32(policy.TimeLockDelta),
+		MinHTLC:       policy.MinHTLC,
+		MaxHTLC:       policy.MaxHTLC,
 	}
 	err = r.server.htlcSwitch.UpdateForwardingPolicies(p, targetChans...)
 	if err != nil {
-		// If we're unable update the fees due to the links not being
-		// online, then we don't need to fail the call. We'll simply
-		// log the failure.
-		rpcsLog.Warnf("Unable to update link fees: %v", err)
+		// If we're unable update the policies due to the links not
+		// being online, then we don't need to fail the call. We'll
+		// simply log the failure.
+		rpcsLog.Warnf("Unable to update link policies: %v", err)
 	}
 
-	return &lnrpc.FeeUpdateResponse{}, nil
+	return nil
+}
+
+// ListBlacklist returns the set of nodes which are currently within the
+// router's node blacklist.
+func (r *rpcServer) ListBlacklist(ctx context.Context,
+	_ *lnrpc.ListBlacklistRequest) (*lnrpc.ListBlacklistResponse, error) {
+
+	if r.authSvc != nil {
+		if err := macaroons.ValidateMacaroon(ctx, "listblacklist",
+			r.authSvc); err != nil {
+			return nil, err
+		}
+	}
+
+	blacklist := r.server.chanRouter.BlacklistedNodes()
+	resp := &lnrpc.ListBlacklistResponse{
+		Nodes: make([]*lnrpc.BlacklistedNode, 0, len(blacklist)),
+	}
+	for _, node := range blacklist {
+		resp.Nodes = append(resp.Nodes, &lnrpc.BlacklistedNode{
+			PubKey:             hex.EncodeToString(node.PubKey[:]),
+			AvoidAsDestination: node.AvoidAsDestination,
+		})
+	}
+
+	return resp, nil
+}
+
+// UpdateBlacklist adds nodes to, or removes nodes from the router's
+// persistent node blacklist. Nodes within the blacklist will never be used as
+// an intermediate hop when routing payments.
+func (r *rpcServer) UpdateBlacklist(ctx context.Context,
+	req *lnrpc.UpdateBlacklistRequest) (*lnrpc.UpdateBlacklistResponse, error) {
+
+	if r.authSvc != nil {
+		if err := macaroons.ValidateMacaroon(ctx, "updateblacklist",
+			r.authSvc); err != nil {
+			return nil, err
+		}
+	}
+
+	parsePubKey := func(pubStr string) (*btcec.PublicKey, error) {
+		pubBytes, err := hex.DecodeString(pubStr)
+		if err != nil {
+			return nil, err
+		}
+		return btcec.ParsePubKey(pubBytes, btcec.S256())
+	}
+
+	for _, node := range req.Add {
+		pub, err := parsePubKey(node.PubKey)
+		if err != nil {
+			return nil, err
+		}
+
+		rpcsLog.Debugf("[updateblacklist] adding node=%x, "+
+			"avoid_as_dest=%v", pub.SerializeCompressed(),
+			node.AvoidAsDestination)
+
+		err = r.server.chanRouter.BlacklistNode(
+			pub, node.AvoidAsDestination,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, pubStr := range req.Remove {
+		pub, err := parsePubKey(pubStr)
+		if err != nil {
+			return nil, err
+		}
+
+		rpcsLog.Debugf("[updateblacklist] removing node=%x",
+			pub.SerializeCompressed())
+
+		if err := r.server.chanRouter.UnblacklistNode(pub); err != nil {
+			return nil, err
+		}
+	}
+
+	return &lnrpc.UpdateBlacklistResponse{}, nil
+}
+
+// GetCommitmentTxns returns our current fully signed commitment transaction
+// for the target channel, along with the fully signed second-level HTLC
+// timeout transactions which spend from it. The state of the channel isn't
+// modified, and nothing is broadcast.
+func (r *rpcServer) GetCommitmentTxns(ctx context.Context,
+	req *lnrpc.CommitmentTxnsRequest) (*lnrpc.CommitmentTxnsResponse, error) {
+
+	// As the returned transactions are fully signed, and could be used to
+	// force close the channel, this method isn't granted to read-only
+	// macaroons.
+	if r.authSvc != nil {
+		if err := macaroons.ValidateMacaroon(ctx, "getcommitmenttxns",
+			r.authSvc); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.ChannelPoint == nil {
+		return nil, fmt.Errorf("channel point must be specified")
+	}
+
+	// The funding txid may either be specified as raw bytes, or as a
+	// string when the request arrives via the REST proxy.
+	var (
+		txid *chainhash.Hash
+		err  error
+	)
+	if req.ChannelPoint.FundingTxidStr != "" {
+		txid, err = chainhash.NewHashFromStr(
+			req.ChannelPoint.FundingTxidStr,
+		)
+	} else {
+		txid, err = chainhash.NewHash(req.ChannelPoint.FundingTxid)
+	}
+	if err != nil {
+		return nil, err
+	}
+	chanPoint := wire.NewOutPoint(txid, req.ChannelPoint.OutputIndex)
+
+	rpcsLog.Debugf("[getcommitmenttxns] request for ChannelPoint(%v)",
+		chanPoint)
+
+	channel, err := r.fetchActiveChannel(*chanPoint)
+	if err != nil {
+		return nil, err
+	}
+	defer channel.Stop()
+
+	commitTxns, err := channel.LocalCommitTxns()
+	if err != nil {
+		return nil, err
+	}
+
+	serializeTx := func(tx *wire.MsgTx) (string, error) {
+		var b bytes.Buffer
+		if err := tx.Serialize(&b); err != nil {
+			return "", err
+		}
+		return hex.EncodeToString(b.Bytes()), nil
+	}
+
+	commitTx, err := serializeTx(commitTxns.CommitTx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.CommitmentTxnsResponse{
+		CommitTx: commitTx,
+		HtlcTxns: make([]string, 0, len(commitTxns.HtlcTimeoutTxns)),
+	}
+	for _, htlcTx := range commitTxns.HtlcTimeoutTxns {
+		txHex, err := serializeTx(htlcTx)
+		if err != nil {
+			return nil, err
+		}
+		resp.HtlcTxns = append(resp.HtlcTxns, txHex)
+	}
+
+	return resp, nil
+}
+
+// dbChunkSize is the maximum size of each chunk of the channel database sent
+// when exporting it. It's kept well below the maximum size of a gRPC message.
+const dbChunkSize = 1 << 20
+
+// dbChunkWriter is an io.Writer which sends everything written to it over an
+// ExportChannelDB stream, split into chunks of at most dbChunkSize bytes.
+type dbChunkWriter struct {
+	stream lnrpc.Lightning_ExportChannelDBServer
+}
+
+// Write sends the passed bytes over the stream.
+//
+// NOTE: Part of the io.Writer interface.
+func (w *dbChunkWriter) Write(p []byte) (int, error) {
+	var n int
+	for len(p) > 0 {
+		chunk := p
+		if len(chunk) > dbChunkSize {
+			chunk = chunk[:dbChunkSize]
+		}
+
+		err := w.stream.Send(&lnrpc.ChannelDBChunk{Chunk: chunk})
+		if err != nil {
+			return n, err
+		}
+
+		n += len(chunk)
+		p = p[len(chunk):]
+	}
+
+	return n, nil
+}
+
+// ExportChannelDB streams a consistent snapshot of the channel database, taken
+// while the daemon is running. The concatenated chunks form a complete
+// database file, which can be restored in place of channel.db while the
+// daemon is stopped.
+func (r *rpcServer) ExportChannelDB(in *lnrpc.ExportChannelDBRequest,
+	stream lnrpc.Lightning_ExportChannelDBServer) error {
+
+	// Check macaroon to see if this is allowed.
+	if r.authSvc != nil {
+		if err := macaroons.ValidateMacaroon(stream.Context(),
+			"exportchanneldb", r.authSvc); err != nil {
+			return err
+		}
+	}
+
+	rpcsLog.Infof("[exportchanneldb] exporting channel database snapshot")
+
+	n, err := r.server.chanDB.Backup(&dbChunkWriter{stream: stream})
+	if err != nil {
+		rpcsLog.Errorf("unable to export channel database: %v", err)
+		return err
+	}
+
+	rpcsLog.Infof("[exportchanneldb] exported %v bytes", n)
+
+	return nil
+}
+
+// SendCustomMessage sends a custom peer message to a connected peer. The
+// message type must lie within the custom type range, and its data is sent as
+// is, without being interpreted by the daemon.
+func (r *rpcServer) SendCustomMessage(ctx context.Context,
+	req *lnrpc.SendCustomMessageRequest) (*lnrpc.SendCustomMessageResponse,
+	error) {
+
+	if r.authSvc != nil {
+		if err := macaroons.ValidateMacaroon(ctx, "sendcustommessage",
+			r.authSvc); err != nil {
+			return nil, err
+		}
+	}
+
+	peerPub, err := btcec.ParsePubKey(req.Peer, btcec.S256())
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Type > math.MaxUint16 {
+		return nil, fmt.Errorf("msg type %d exceeds the maximum of %d",
+			req.Type, math.MaxUint16)
+	}
+	msg, err := lnwire.NewCustom(lnwire.MessageType(req.Type), req.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcsLog.Debugf("[sendcustommessage] sending msg type=%d of %d bytes "+
+		"to peer=%x", req.Type, len(req.Data), req.Peer)
+
+	if err := r.server.SendToPeer(peerPub, msg); err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.SendCustomMessageResponse{}, nil
+}
+
+// SubscribeCustomMessages returns a uni-directional stream (server -> client)
+// of the custom peer messages received from any connected peer.
+func (r *rpcServer) SubscribeCustomMessages(
+	req *lnrpc.SubscribeCustomMessagesRequest,
+	updateStream lnrpc.Lightning_SubscribeCustomMessagesServer) error {
+
+	// Check macaroon to see if this is allowed.
+	if r.authSvc != nil {
+		if err := macaroons.ValidateMacaroon(updateStream.Context(),
+			"subscribecustommessages", r.authSvc); err != nil {
+			return err
+		}
+	}
+
+	client, err := r.server.eventBus.Subscribe(customMessageEvent{})
+	if err != nil {
+		return err
+	}
+	defer client.Cancel()
+
+	for {
+		select {
+		case e := <-client.Updates():
+			event := e.(customMessageEvent)
+
+			msg := &lnrpc.CustomMessage{
+				Peer: event.peer[:],
+				Type: uint32(event.msg.Type),
+				Data: event.msg.Data,
+			}
+			if err := updateStream.Send(msg); err != nil {
+				return err
+			}
+
+		case <-client.Quit():
+			return client.Err()
+
+		case <-updateStream.Context().Done():
+			return nil
+
+		case <-r.quit:
+			return nil
+		}
+	}
+}
+
+// SubscribeHtlcEvents returns a uni-directional stream (server -> client) of
+// the HTLC events of the switch.
+func (r *rpcServer) SubscribeHtlcEvents(req *lnrpc.SubscribeHtlcEventsRequest,
+	updateStream lnrpc.Lightning_SubscribeHtlcEventsServer) error {
+
+	// Check macaroon to see if this is allowed.
+	if r.authSvc != nil {
+		if err := macaroons.ValidateMacaroon(updateStream.Context(),
+			"subscribehtlcevents", r.authSvc); err != nil {
+			return err
+		}
+	}
+
+	client, err := r.server.eventBus.Subscribe(
+		htlcswitch.HtlcForwardEvent{},
+		htlcswitch.HtlcForwardFailEvent{},
+		htlcswitch.HtlcLinkFailEvent{},
+		htlcswitch.HtlcResolutionEvent{},
+	)
+	if err != nil {
+		return err
+	}
+	defer client.Cancel()
+
+	for {
+		select {
+		case e := <-client.Updates():
+			if err := updateStream.Send(marshalHtlcEvent(e)); err != nil {
+				return err
+			}
+
+		case <-client.Quit():
+			return client.Err()
+
+		case <-updateStream.Context().Done():
+			return nil
+
+		case <-r.quit:
+			return nil
+		}
+	}
+}
+
+// marshalHtlcEvent converts an HTLC event of the switch into its RPC
+// representation.
+func marshalHtlcEvent(e interface{}) *lnrpc.HtlcEvent {
+	switch event := e.(type) {
+	case htlcswitch.HtlcForwardEvent:
+		return &lnrpc.HtlcEvent{
+			EventType:       lnrpc.HtlcEvent_FORWARD,
+			PaymentHash:     event.PaymentHash[:],
+			IncomingChanId:  event.IncomingChanID.ToUint64(),
+			OutgoingChanId:  event.OutgoingChanID.ToUint64(),
+			IncomingAmtMsat: int64(event.IncomingAmount),
+			OutgoingAmtMsat: int64(event.OutgoingAmount),
+			TimestampNs:     uint64(event.Timestamp.UnixNano()),
+		}
+
+	case htlcswitch.HtlcForwardFailEvent:
+		return &lnrpc.HtlcEvent{
+			EventType:      lnrpc.HtlcEvent_FORWARD_FAIL,
+			PaymentHash:    event.PaymentHash[:],
+			IncomingChanId: event.IncomingChanID.ToUint64(),
+			OutgoingChanId: event.OutgoingChanID.ToUint64(),
+			TimestampNs:    uint64(event.Timestamp.UnixNano()),
+			FailureDetail:  event.Reason,
+		}
+
+	case htlcswitch.HtlcLinkFailEvent:
+		rpcEvent := &lnrpc.HtlcEvent{
+			EventType:     lnrpc.HtlcEvent_LINK_FAIL,
+			PaymentHash:   event.PaymentHash[:],
+			TimestampNs:   uint64(event.Timestamp.UnixNano()),
+			FailureCode:   uint32(event.FailureCode),
+			FailureDetail: event.FailureDetail,
+		}
+		if event.Incoming {
+			rpcEvent.IncomingChanId = event.ChanID.ToUint64()
+		} else {
+			rpcEvent.OutgoingChanId = event.ChanID.ToUint64()
+		}
+		return rpcEvent
+
+	case htlcswitch.HtlcResolutionEvent:
+		rpcEvent := &lnrpc.HtlcEvent{
+			EventType:      lnrpc.HtlcEvent_SETTLE,
+			PaymentHash:    event.PaymentHash[:],
+			IncomingChanId: event.IncomingChanID.ToUint64(),
+			OutgoingChanId: event.OutgoingChanID.ToUint64(),
+			TimestampNs:    uint64(event.Timestamp.UnixNano()),
+		}
+		if !event.Settled {
+			rpcEvent.EventType = lnrpc.HtlcEvent_FORWARD_FAIL
+			rpcEvent.FailureDetail = "failed downstream"
+		}
+		return rpcEvent
+
+	default:
+		return &lnrpc.HtlcEvent{}
+	}
+}
+
+// QueryMissionControl returns the router's mission control history, split
+// into the history of individual nodes, and that of directed pairs of nodes.
+func (r *rpcServer) QueryMissionControl(ctx context.Context,
+	_ *lnrpc.QueryMissionControlRequest) (*lnrpc.QueryMissionControlResponse,
+	error) {
+
+	if r.authSvc != nil {
+		if err := macaroons.ValidateMacaroon(ctx, "querymissioncontrol",
+			r.authSvc); err != nil {
+			return nil, err
+		}
+	}
+
+	resp := &lnrpc.QueryMissionControlResponse{}
+	for _, entry := range r.server.chanRouter.QueryMissionControl() {
+		if entry.IsNodeEntry() {
+			resp.Nodes = append(resp.Nodes, marshalNodeHistory(entry))
+			continue
+		}
+
+		resp.Pairs = append(resp.Pairs, marshalPairHistory(entry))
+	}
+
+	return resp, nil
+}
+
+// unixTimestamp maps the zero time to a zero timestamp, so it isn't reported
+// as a large negative value.
+func unixTimestamp(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+// marshalNodeHistory converts the mission control history of a node into its
+// RPC representation.
+func marshalNodeHistory(entry *channeldb.MissionControlEntry) *lnrpc.NodeHistory {
+	return &lnrpc.NodeHistory{
+		PubKey:      hex.EncodeToString(entry.From[:]),
+		FailTime:    unixTimestamp(entry.LastFail),
+		SuccessTime: unixTimestamp(entry.LastSuccess),
+	}
+}
+
+// marshalPairHistory converts the mission control history of a directed pair
+// of nodes into its RPC representation.
+func marshalPairHistory(entry *channeldb.MissionControlEntry) *lnrpc.PairHistory {
+	return &lnrpc.PairHistory{
+		NodeFrom:       hex.EncodeToString(entry.From[:]),
+		NodeTo:         hex.EncodeToString(entry.To[:]),
+		FailTime:       unixTimestamp(entry.LastFail),
+		FailAmtMsat:    int64(entry.LastFailAmt),
+		SuccessTime:    unixTimestamp(entry.LastSuccess),
+		SuccessAmtMsat: int64(entry.LastSuccessAmt),
+	}
+}
+
+// ResetMissionControl wipes the router's mission control history, lifting all
+// penalties resulting from past payment failures.
+func (r *rpcServer) ResetMissionControl(ctx context.Context,
+	_ *lnrpc.ResetMissionControlRequest) (*lnrpc.ResetMissionControlResponse,
+	error) {
+
+	if r.authSvc != nil {
+		if err := macaroons.ValidateMacaroon(ctx, "resetmissioncontrol",
+			r.authSvc); err != nil {
+			return nil, err
+		}
+	}
+
+	rpcsLog.Infof("[resetmissioncontrol] resetting mission control history")
+
+	if err := r.server.chanRouter.ResetMissionControl(); err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.ResetMissionControlResponse{}, nil
+}
+
+// XImportMissionControl seeds the router's mission control history with the
+// passed node and pair histories. Imported observations only replace our own
+// if they're more recent.
+func (r *rpcServer) XImportMissionControl(ctx context.Context,
+	req *lnrpc.XImportMissionControlRequest) (
+	*lnrpc.XImportMissionControlResponse, error) {
+
+	if r.authSvc != nil {
+		if err := macaroons.ValidateMacaroon(ctx, "importmissioncontrol",
+			r.authSvc); err != nil {
+			return nil, err
+		}
+	}
+
+	parsePubKey := func(pubStr string) ([33]byte, error) {
+		var pub [33]byte
+
+		pubBytes, err := hex.DecodeString(pubStr)
+		if err != nil {
+			return pub, err
+		}
+		if len(pubBytes) != len(pub) {
+			return pub, fmt.Errorf("invalid pubkey length %v, "+
+				"expected %v", len(pubBytes), len(pub))
+		}
+		copy(pub[:], pubBytes)
+
+		return pub, nil
+	}
+
+	// timeFromUnix maps a zero timestamp to the zero time, so it's treated
+	// as the absence of an observation.
+	timeFromUnix := func(unix int64) (time.Time, error) {
+		switch {
+		case unix < 0:
+			return time.Time{}, fmt.Errorf("invalid timestamp %v",
+				unix)
+		case unix == 0:
+			return time.Time{}, nil
+		}
+		return time.Unix(unix, 0), nil
+	}
+
+	entries := make(
+		[]*channeldb.MissionControlEntry, 0,
+		len(req.Nodes)+len(req.Pairs),
+	)
+	for _, node := range req.Nodes {
+		pub, err := parsePubKey(node.PubKey)
+		if err != nil {
+			return nil, err
+		}
+		lastFail, err := timeFromUnix(node.FailTime)
+		if err != nil {
+			return nil, err
+		}
+		lastSuccess, err := timeFromUnix(node.SuccessTime)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, &channeldb.MissionControlEntry{
+			From:        pub,
+			LastFail:    lastFail,
+			LastSuccess: lastSuccess,
+		})
+	}
+
+	for _, pair := range req.Pairs {
+		from, err := parsePubKey(pair.NodeFrom)
+		if err != nil {
+			return nil, err
+		}
+		to, err := parsePubKey(pair.NodeTo)
+		if err != nil {
+			return nil, err
+		}
+		if from == to {
+			return nil, fmt.Errorf("pair from node %v to itself",
+				pair.NodeFrom)
+		}
+		if pair.FailAmtMsat < 0 || pair.SuccessAmtMsat < 0 {
+			return nil, fmt.Errorf("pair amounts must be " +
+				"non-negative")
+		}
+		lastFail, err := timeFromUnix(pair.FailTime)
+		if err != nil {
+			return nil, err
+		}
+		lastSuccess, err := timeFromUnix(pair.SuccessTime)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, &channeldb.MissionControlEntry{
+			From:           from,
+			To:             to,
+			LastFail:       lastFail,
+			LastFailAmt:    lnwire.MilliAtom(pair.FailAmtMsat),
+			LastSuccess:    lastSuccess,
+			LastSuccessAmt: lnwire.MilliAtom(pair.SuccessAmtMsat),
+		})
+	}
+
+	rpcsLog.Debugf("[importmissioncontrol] importing %v node and %v pair "+
+		"histories", len(req.Nodes), len(req.Pairs))
+
+	if err := r.server.chanRouter.ImportMissionControl(entries); err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.XImportMissionControlResponse{}, nil
+}
+
+// QueryProbability returns the router's estimate of the probability of a
+// payment of the passed amount being successfully forwarded from one node to
+// another, along with the mission control history the estimate is based on.
+func (r *rpcServer) QueryProbability(ctx context.Context,
+	req *lnrpc.QueryProbabilityRequest) (*lnrpc.QueryProbabilityResponse,
+	error) {
+
+	if r.authSvc != nil {
+		if err := macaroons.ValidateMacaroon(ctx, "queryprobability",
+			r.authSvc); err != nil {
+			return nil, err
+		}
+	}
+
+	parsePubKey := func(pubStr string) ([33]byte, error) {
+		var pub [33]byte
+
+		pubBytes, err := hex.DecodeString(pubStr)
+		if err != nil {
+			return pub, err
+		}
+		pubKey, err := btcec.ParsePubKey(pubBytes, btcec.S256())
+		if err != nil {
+			return pub, err
+		}
+		copy(pub[:], pubKey.SerializeCompressed())
+
+		return pub, nil
+	}
+
+	from, err := parsePubKey(req.FromNode)
+	if err != nil {
+		return nil, fmt.Errorf("invalid from node: %v", err)
+	}
+	to, err := parsePubKey(req.ToNode)
+	if err != nil {
+		return nil, fmt.Errorf("invalid to node: %v", err)
+	}
+	if from == to {
+		return nil, fmt.Errorf("from and to node must differ")
+	}
+	if req.AmtMsat <= 0 {
+		return nil, fmt.Errorf("amt_msat must be positive")
+	}
+	amt := lnwire.MilliAtom(req.AmtMsat)
+
+	estimate := r.server.chanRouter.QueryProbability(from, to, amt)
+
+	resp := &lnrpc.QueryProbabilityResponse{
+		Probability: estimate.Probability,
+	}
+	if estimate.PairHistory != nil {
+		resp.History = marshalPairHistory(estimate.PairHistory)
+	}
+	if estimate.NodeHistory != nil {
+		resp.NodeHistory = marshalNodeHistory(estimate.NodeHistory)
+	}
+
+	return resp, nil
+}
+
+// BuildRoute constructs a fully specified route traversing the passed hops in
+// order, starting from our own node, with the fees, time locks and channels of
+// each hop filled in from the local graph.
+func (r *rpcServer) BuildRoute(ctx context.Context,
+	req *lnrpc.BuildRouteRequest) (*lnrpc.BuildRouteResponse, error) {
+
+	if r.authSvc != nil {
+		if err := macaroons.ValidateMacaroon(ctx, "buildroute",
+			r.authSvc); err != nil {
+			return nil, err
+		}
+	}
+
+	amt, err := satoshisFromRPC("amt", req.Amt)
+	if err != nil {
+		return nil, err
+	}
+	amtMSat := lnwire.NewMSatFromSatoshis(amt)
+	if amtMSat == 0 {
+		return nil, fmt.Errorf("amt must be positive")
+	}
+	if amtMSat > maxPaymentMSat {
+		return nil, fmt.Errorf("payment of %v is too large, max payment "+
+			"allowed is %v", amt, maxPaymentMSat.ToSatoshis())
+	}
+
+	hops := make([]*btcec.PublicKey, 0, len(req.HopPubkeys))
+	for _, hopStr := range req.HopPubkeys {
+		pubKeyBytes, err := hex.DecodeString(hopStr)
+		if err != nil {
+			return nil, err
+		}
+		pubKey, err := btcec.ParsePubKey(pubKeyBytes, btcec.S256())
+		if err != nil {
+			return nil, err
+		}
+		hops = append(hops, pubKey)
+	}
+
+	rpcsLog.Debugf("[buildroute] amt=%v, hops=%v", amt, len(hops))
+
+	route, err := r.server.chanRouter.BuildRoute(amtMSat, hops)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.BuildRouteResponse{
+		Route: marshalRoute(route),
+	}, nil
+}
+
+// TrackPayment streams the state of the payment with the passed payment hash,
+// starting with its current state, followed by each state transition until
+// the payment either succeeds or fails.
+func (r *rpcServer) TrackPayment(req *lnrpc.PaymentHash,
+	updateStream lnrpc.Lightning_TrackPaymentServer) error {
+
+	// Check macaroon to see if this is allowed.
+	if r.authSvc != nil {
+		if err := macaroons.ValidateMacaroon(updateStream.Context(),
+			"trackpayment", r.authSvc); err != nil {
+			return err
+		}
+	}
+
+	var (
+		payHash [32]byte
+		rHash   []byte
+		err     error
+	)
+
+	// If the RHash as a raw string was provided, then decode that and use
+	// that directly. Otherwise, we use the raw bytes provided.
+	if req.RHashStr != "" {
+		rHash, err = hex.DecodeString(req.RHashStr)
+		if err != nil {
+			return err
+		}
+	} else {
+		rHash = req.RHash
+	}
+
+	// Ensure that the payment hash is *exactly* 32-bytes.
+	if len(rHash) != 32 {
+		return fmt.Errorf("payment hash must be exactly 32 bytes, is "+
+			"instead %v", len(rHash))
+	}
+	copy(payHash[:], rHash)
+
+	rpcsLog.Debugf("[trackpayment] payment_hash=%x", payHash[:])
+
+	sub, err := r.server.chanRouter.SubscribePayment(payHash)
+	if err != nil {
+		return err
+	}
+	defer sub.Cancel()
+
+	for {
+		select {
+		case payment, ok := <-sub.Updates:
+			// The subscription is closed once the payment has
+			// reached its final state.
+			if !ok {
+				return nil
+			}
+
+			err := updateStream.Send(marshalPayment(payment))
+			if err != nil {
+				return err
+			}
+
+		case <-updateStream.Context().Done():
+			return nil
+
+		case <-r.quit:
+			return nil
+		}
+	}
+}
+
+// HtlcInterceptor dispatches a bi-directional streaming RPC which allows the
+// caller to intercept the HTLCs forwarded by the daemon. Each HTLC is held by
+// the switch until the caller resolves it, or until it times out. Any HTLCs
+// which are still held once the stream is closed are resumed.
+func (r *rpcServer) HtlcInterceptor(
+	stream lnrpc.Lightning_HtlcInterceptorServer) error {
+
+	// Check macaroon to see if this is allowed.
+	if r.authSvc != nil {
+		if err := macaroons.ValidateMacaroon(stream.Context(),
+			"htlcinterceptor", r.authSvc); err != nil {
+			return err
+		}
+	}
+
+	// resumeForward resumes the forwarding of an HTLC which is no longer
+	// held by the interceptor, unless it has already been resolved.
+	resumeForward := func(fwd *htlcswitch.InterceptedForward) {
+		err := fwd.Resume()
+		if err != nil && err != htlcswitch.ErrForwardResolved {
+			rpcsLog.Errorf("unable to resume intercepted "+
+				"forward %v: %v", fwd.ID, err)
+		}
+	}
+
+	// The switch hands each intercepted forward over within its own
+	// goroutine, so we'll pass them to the main loop below. Should the
+	// stream be closed in the meantime, the forward is resumed.
+	forwards := make(chan *htlcswitch.InterceptedForward)
+	reqQuit := make(chan struct{})
+	interceptor := func(fwd *htlcswitch.InterceptedForward) {
+		select {
+		case forwards <- fwd:
+		case <-reqQuit:
+			resumeForward(fwd)
+		}
+	}
+	if err := r.server.htlcSwitch.SetInterceptor(interceptor); err != nil {
+		return err
+	}
+
+	rpcsLog.Infof("HTLC interceptor registered")
+
+	held := make(map[uint64]*htlcswitch.InterceptedForward)
+	defer func() {
+		err := r.server.htlcSwitch.SetInterceptor(nil)
+		if err != nil {
+			rpcsLog.Errorf("unable to unregister HTLC "+
+				"interceptor: %v", err)
+		}
+		close(reqQuit)
+
+		for _, fwd := range held {
+			resumeForward(fwd)
+		}
+
+		rpcsLog.Infof("HTLC interceptor unregistered")
+	}()
+
+	// Launch a goroutine to read the resolutions sent by the client, so
+	// we can continue to deliver intercepted forwards while waiting on
+	// them.
+	errChan := make(chan error, 1)
+	respChan := make(chan *lnrpc.ForwardHtlcInterceptResponse)
+	go func() {
+		for {
+			resp, err := stream.Recv()
+			if err != nil {
+				if err == io.EOF {
+					err = nil
+				}
+				select {
+				case errChan <- err:
+				case <-reqQuit:
+				}
+				return
+			}
+
+			select {
+			case respChan <- resp:
+			case <-reqQuit:
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case fwd := <-forwards:
+			held[fwd.ID] = fwd
+
+			err := stream.Send(&lnrpc.ForwardHtlcInterceptRequest{
+				InterceptId:     fwd.ID,
+				PaymentHash:     fwd.PaymentHash[:],
+				IncomingChanId:  fwd.IncomingChanID.ToUint64(),
+				OutgoingChanId:  fwd.OutgoingChanID.ToUint64(),
+				IncomingAmtMsat: int64(fwd.IncomingAmount),
+				OutgoingAmtMsat: int64(fwd.OutgoingAmount),
+				IncomingExpiry:  fwd.IncomingExpiry,
+				OutgoingExpiry:  fwd.OutgoingExpiry,
+			})
+			if err != nil {
+				return err
+			}
+
+		case resp := <-respChan:
+			fwd, ok := held[resp.InterceptId]
+			if !ok {
+				return fmt.Errorf("unknown intercept_id %v",
+					resp.InterceptId)
+			}
+
+			err := resolveInterceptedForward(fwd, resp)
+			switch {
+			// The forward may have already timed out, in which
+			// case it has been failed back.
+			case err == htlcswitch.ErrForwardResolved:
+				rpcsLog.Debugf("Intercepted forward %v already "+
+					"resolved", fwd.ID)
+
+			case err != nil:
+				return err
+			}
+			delete(held, resp.InterceptId)
+
+		case err := <-errChan:
+			return err
+
+		case <-stream.Context().Done():
+			return nil
+
+		case <-r.quit:
+			return nil
+		}
+	}
+}
+
+// resolveInterceptedForward resolves an intercepted forward as directed by
+// the passed response of the interceptor.
+func resolveInterceptedForward(fwd *htlcswitch.InterceptedForward,
+	resp *lnrpc.ForwardHtlcInterceptResponse) error {
+
+	switch resp.Action {
+	case lnrpc.ResolveHoldForwardAction_RESUME:
+		return fwd.Resume()
+
+	case lnrpc.ResolveHoldForwardAction_FAIL:
+		return fwd.Fail()
+
+	case lnrpc.ResolveHoldForwardAction_SETTLE:
+		if len(resp.Preimage) != 32 {
+			return fmt.Errorf("preimage must be exactly 32 bytes, "+
+				"is instead %v", len(resp.Preimage))
+		}
+
+		var preimage [32]byte
+		copy(preimage[:], resp.Preimage)
+
+		return fwd.Settle(preimage)
+
+	default:
+		return fmt.Errorf("unknown action: %v", resp.Action)
+	}
+}
+
+// SettleInvoice settles an accepted hold invoice using the passed preimage,
+// settling the HTLC held for it.
+func (r *rpcServer) SettleInvoice(ctx context.Context,
+	req *lnrpc.SettleInvoiceMsg) (*lnrpc.SettleInvoiceResp, error) {
+
+	// Check macaroon to see if this is allowed.
+	if r.authSvc != nil {
+		if err := macaroons.ValidateMacaroon(ctx, "settleinvoice",
+			r.authSvc); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(req.Preimage) != 32 {
+		return nil, fmt.Errorf("preimage must be exactly 32 bytes, "+
+			"is instead %v", len(req.Preimage))
+	}
+
+	var preimage [32]byte
+	copy(preimage[:], req.Preimage)
+
+	rpcsLog.Debugf("[settleinvoice] settling hold invoice %x",
+		sha256.Sum256(preimage[:]))
+
+	if err := r.server.invoices.SettleHoldInvoice(preimage); err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.SettleInvoiceResp{}, nil
+}
+
+// CancelInvoice cancels an invoice which hasn't been settled, failing back
+// the HTLC held for it should it be an accepted hold invoice.
+func (r *rpcServer) CancelInvoice(ctx context.Context,
+	req *lnrpc.CancelInvoiceMsg) (*lnrpc.CancelInvoiceResp, error) {
+
+	// Check macaroon to see if this is allowed.
+	if r.authSvc != nil {
+		if err := macaroons.ValidateMacaroon(ctx, "cancelinvoice",
+			r.authSvc); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(req.PaymentHash) != 32 {
+		return nil, fmt.Errorf("payment hash must be exactly 32 "+
+			"bytes, is instead %v", len(req.PaymentHash))
+	}
+
+	var paymentHash chainhash.Hash
+	copy(paymentHash[:], req.PaymentHash)
+
+	rpcsLog.Debugf("[cancelinvoice] canceling invoice %v", paymentHash)
+
+	if err := r.server.invoices.CancelInvoice(paymentHash); err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.CancelInvoiceResp{}, nil
+}
+
+// PendingSweeps returns a list of all the outputs that are currently being
+// swept back into the wallet by the sweeper, along with the number of
+// broadcast attempts made so far, and the fee rate at which they would be
+// swept next.
+func (r *rpcServer) PendingSweeps(ctx context.Context,
+	req *lnrpc.PendingSweepsRequest) (*lnrpc.PendingSweepsResponse, error) {
+
+	// Check macaroon to see if this is allowed.
+	if r.authSvc != nil {
+		if err := macaroons.ValidateMacaroon(ctx, "pendingsweeps",
+			r.authSvc); err != nil {
+			return nil, err
+		}
+	}
+
+	pendingInputs, err := r.server.sweeper.PendingInputs()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.PendingSweepsResponse{
+		PendingSweeps: make([]*lnrpc.PendingSweep, 0, len(pendingInputs)),
+	}
+	for _, pendingInput := range pendingInputs {
+		resp.PendingSweeps = append(resp.PendingSweeps, &lnrpc.PendingSweep{
+			Outpoint:            pendingInput.OutPoint.String(),
+			WitnessType:         pendingInput.WitnessType.String(),
+			Amount:              int64(pendingInput.Amount),
+			BroadcastAttempts:   uint32(pendingInput.BroadcastAttempts),
+			NextBroadcastHeight: uint32(pendingInput.NextBroadcastHeight),
+			FeePerByte:          int64(pendingInput.FeePerWeight * 4),
+			DeadlineHeight:      uint32(pendingInput.Deadline),
+		})
+	}
+
+	return resp, nil
+}
+
+// BumpFee bumps the fee rate of an output which is currently being swept. The
+// output is swept immediately at no less than the requested fee rate, batched
+// with any other outputs due to be swept at a similar fee rate.
+func (r *rpcServer) BumpFee(ctx context.Context,
+	req *lnrpc.BumpFeeRequest) (*lnrpc.BumpFeeResponse, error) {
+
+	// Check macaroon to see if this is allowed.
+	if r.authSvc != nil {
+		if err := macaroons.ValidateMacaroon(ctx, "bumpfee",
+			r.authSvc); err != nil {
+			return nil, err
+		}
+	}
+
+	outpoint, err := parseOutPoint(req.Outpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	// The sweeper works in fee rates per weight unit, so we'll convert
+	// the requested fee rate, rejecting any which would round down to
+	// zero.
+	feePerWeight := btcutil.Amount(req.FeePerByte / 4)
+	if feePerWeight <= 0 {
+		return nil, fmt.Errorf("fee rate of %v atoms/byte is too "+
+			"small, min fee rate is 4 atoms/byte", req.FeePerByte)
+	}
+
+	rpcsLog.Debugf("[bumpfee] outpoint=%v, fee_per_byte=%v", outpoint,
+		req.FeePerByte)
+
+	if err := r.server.sweeper.BumpFee(*outpoint, feePerWeight); err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.BumpFeeResponse{}, nil
+}
+
+// parseOutPoint parses an outpoint encoded as txid:output_index.
+func parseOutPoint(s string) (*wire.OutPoint, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("outpoint %q must be of the form "+
+			"txid:output_index", s)
+	}
+
+	txid, err := chainhash.NewHashFromStr(parts[0])
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid output index: %v", err)
+	}
+
+	return &wire.OutPoint{
+		Hash:  *txid,
+		Index: uint32(index),
+	}, nil
 }
@@ -14,20 +14,39 @@ import (
 	"github.com/boltdb/bolt"
 	"github.com/lightningnetwork/lightning-onion"
 	"github.com/lightningnetwork/lnd/brontide"
+	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/contractcourt"
 	"github.com/lightningnetwork/lnd/discovery"
 	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/lnwire/feature"
 	"github.com/lightningnetwork/lnd/routing"
+	"github.com/lightningnetwork/lnd/subscribe"
+	"github.com/lightningnetwork/lnd/sweep"
 	"github.com/roasbeef/btcd/btcec"
 	"github.com/roasbeef/btcd/chaincfg/chainhash"
 	"github.com/roasbeef/btcd/connmgr"
+	"github.com/roasbeef/btcd/wire"
 	"github.com/roasbeef/btcutil"
 
 	"github.com/go-errors/errors"
 	"github.com/lightningnetwork/lnd/htlcswitch"
 )
 
+const (
+	// defaultOutgoingBroadcastDelta is the number of blocks before the
+	// expiry of an outgoing HTLC at which we'll force close its channel,
+	// so it can be timed out on-chain.
+	defaultOutgoingBroadcastDelta = 10
+
+	// defaultIncomingBroadcastDelta is the number of blocks before the
+	// expiry of an incoming HTLC we're able to settle at which we'll
+	// force close its channel, so it can be claimed on-chain.
+	defaultIncomingBroadcastDelta = 10
+)
+
 // server is the main server of the Lightning Network Daemon. The server houses
 // global state pertaining to the wallet, database, and the rpcserver.
 // Additionally, the server is also used as a central messaging bus to interact
@@ -44,6 +63,16 @@ type server struct {
 	// that's backed by the identity private key of the running lnd node.
 	nodeSigner *nodeSigner
 
+	// extSigner is an implementation of the MessageSigner interface which
+	// forwards all signing requests to an external signer bridge. This
+	// will be nil if no external signer has been configured.
+	extSigner *externalSigner
+
+	// annSigner is the MessageSigner used to sign all of our channel and
+	// node announcements. This is the external signer if one has been
+	// configured, and the nodeSigner otherwise.
+	annSigner lnwallet.MessageSigner
+
 	// lightningID is the sha256 of the public key corresponding to our
 	// long-term identity private key.
 	lightningID [32]byte
@@ -68,16 +97,57 @@ type server struct {
 	invoices      *invoiceRegistry
 	breachArbiter *breachArbiter
 
+	// witnessBeacon is a persistent cache of all the preimages we've
+	// learned from HTLCs settled downstream of us.
+	witnessBeacon *preimageBeacon
+
 	chanRouter *routing.ChannelRouter
 
 	authGossiper *discovery.AuthenticatedGossiper
 
 	utxoNursery *utxoNursery
 
+	// sweeper is the sub-system which sweeps every matured or contested
+	// output back into the wallet, batching inputs into as few
+	// transactions as possible.
+	sweeper *sweep.UtxoSweeper
+
+	// chainArb is the sub-system which arbitrates each of our channels,
+	// taking them to chain once required and resolving their contracts.
+	chainArb *contractcourt.ChainArbitrator
+
+	// eventBus is the event bus over which sub-systems are notified of
+	// channel, peer, block and HTLC events, without the producers of
+	// those events needing any knowledge of their consumers.
+	eventBus *subscribe.Server
+
+	// inconsistentChans is the set of channels whose funding output was
+	// found to be spent without a corresponding close record during the
+	// startup consistency check. Links for these channels aren't added to
+	// the switch. This is populated once within Start before any peers
+	// are connected, and is read-only thereafter.
+	inconsistentChans map[wire.OutPoint]struct{}
+
+	// disabledQuirks is the set of interoperability quirks which have
+	// been disabled, and are never applied to any peer.
+	disabledQuirks map[quirk]struct{}
+
+	// pingPadBytes is the number of padding bytes included within each
+	// ping sent to our peers.
+	pingPadBytes uint16
+
+	// closeFeeBounds are the bounds within which we negotiate the fee of
+	// cooperative channel closures.
+	closeFeeBounds closeFeeBounds
+
 	sphinx *htlcswitch.OnionProcessor
 
 	connMgr *connmgr.ConnManager
 
+	// featureMgr is the source of truth for the features we advertise,
+	// and validates the features advertised by our peers.
+	featureMgr *feature.Manager
+
 	// globalFeatures feature vector which affects HTLCs and thus are also
 	// advertised to other nodes.
 	globalFeatures *lnwire.FeatureVector
@@ -111,14 +181,49 @@ func newServer(listenAddrs []string, chanDB *channeldb.DB, cc *chainControl,
 		}
 	}
 
+	featureMgr, err := feature.NewManager()
+	if err != nil {
+		return nil, err
+	}
+
+	invoices := newInvoiceRegistry(chanDB)
+
 	serializedPubKey := privKey.PubKey().SerializeCompressed()
 	s := &server{
 		chanDB: chanDB,
 		cc:     cc,
 
-		invoices: newInvoiceRegistry(chanDB),
+		invoices: invoices,
+
+		witnessBeacon: newPreimageBeacon(invoices, chanDB),
 
-		utxoNursery: newUtxoNursery(chanDB, cc.chainNotifier, cc.wallet),
+		disabledQuirks: disabledQuirks(cfg.Quirks),
+		pingPadBytes:   cfg.PingPadBytes,
+		closeFeeBounds: closeFeeBounds{
+			maxFeeMultiple: cfg.CoopClose.MaxFeeMultiple,
+			acceptWindow:   cfg.CoopClose.AcceptWindow,
+		},
+
+		sweeper: sweep.New(&sweep.UtxoSweeperConfig{
+			GenSweepScript: func() ([]byte, error) {
+				return newSweepPkScript(cc.wallet)
+			},
+			FeeEstimator:       cc.feeEstimator,
+			PublishTransaction: cc.wallet.PublishTransaction,
+			NewBatchTimer: func() <-chan time.Time {
+				return time.NewTimer(sweep.DefaultBatchWindowDuration).C
+			},
+			Notifier:             cc.chainNotifier,
+			ChainIO:              cc.chainIO,
+			Signer:               cc.wallet.Cfg.Signer,
+			MaxInputsPerTx:       sweep.DefaultMaxInputsPerTx,
+			MaxSweepAttempts:     sweep.DefaultMaxSweepAttempts,
+			NextAttemptDeltaFunc: sweep.DefaultNextAttemptDeltaFunc,
+			FeeRateBucketSize:    sweep.DefaultFeeRateBucketSize,
+			MaxFeePerWeight:      sweep.DefaultMaxFeePerWeight,
+		}),
+
+		eventBus: subscribe.NewServer(),
 
 		identityPriv: privKey,
 		nodeSigner:   newNodeSigner(privKey),
@@ -126,7 +231,9 @@ func newServer(listenAddrs []string, chanDB *channeldb.DB, cc *chainControl,
 		// TODO(roasbeef): derive proper onion key based on rotation
 		// schedule
 		sphinx: htlcswitch.NewOnionProcessor(
-			sphinx.NewRouter(privKey, activeNetParams.Params)),
+			sphinx.NewRouter(privKey, activeNetParams.Params),
+			chanDB, cc.chainNotifier,
+		),
 		lightningID: sha256.Sum256(serializedPubKey),
 
 		persistentPeers:    make(map[string]struct{}),
@@ -137,12 +244,24 @@ func newServer(listenAddrs []string, chanDB *channeldb.DB, cc *chainControl,
 		inboundPeers:  make(map[string]*peer),
 		outboundPeers: make(map[string]*peer),
 
-		globalFeatures: globalFeatures,
-		localFeatures:  localFeatures,
+		featureMgr:     featureMgr,
+		globalFeatures: featureMgr.Get(feature.SetInitGlobal),
+		localFeatures:  featureMgr.Get(feature.SetInitLocal),
 
 		quit: make(chan struct{}),
 	}
 
+	// If an external signer has been configured, then all of our
+	// announcements will be signed by it rather than by our identity key.
+	s.annSigner = s.nodeSigner
+	if cfg.ExternalSigner != nil && cfg.ExternalSigner.URL != "" {
+		srvrLog.Infof("Using external signer at %v for announcements",
+			cfg.ExternalSigner.URL)
+
+		s.extSigner = newExternalSigner(cfg.ExternalSigner)
+		s.annSigner = s.extSigner
+	}
+
 	// If the debug HTLC flag is on, then we invoice a "master debug"
 	// invoice which all outgoing payments will be sent and all incoming
 	// HTLCs with the debug R-Hash immediately settled.
@@ -180,6 +299,29 @@ func newServer(listenAddrs []string, chanDB *channeldb.DB, cc *chainControl,
 			s.authGossiper.ProcessRemoteAnnouncement(msg, nil)
 			return nil
 		},
+		PreimageCache:           s.witnessBeacon,
+		GlobalForwardLimit:      forwardLimit(cfg.Throttle.MaxForward),
+		ChannelForwardLimit:     forwardLimit(cfg.Throttle.MaxChannelForward),
+		PeerForwardLimit:        forwardLimit(cfg.Throttle.MaxPeerForward),
+		EventBus:                s.eventBus,
+		ForwardInterceptTimeout: cfg.HTLCInterceptTimeout,
+		FlapDamping: htlcswitch.FlapDamping{
+			MaxFlaps:       cfg.Damping.MaxFlaps,
+			MaxFailures:    cfg.Damping.MaxFailures,
+			Interval:       cfg.Damping.Interval,
+			StableInterval: cfg.Damping.StableInterval,
+		},
+		UpdateChanStatus: func(chanID lnwire.ShortChannelID,
+			disabled bool) error {
+
+			return s.authGossiper.PropagateChanStatusUpdate(
+				chanID, disabled,
+			)
+		},
+		FwdEventLog:           chanDB,
+		ResolutionStore:       chanDB,
+		CircuitStore:          chanDB,
+		DecodeOnionObfuscator: s.sphinx.DecodeOnionObfuscator,
 	})
 
 	// If external IP addresses have been specified, add those to the list
@@ -194,6 +336,26 @@ func newServer(listenAddrs []string, chanDB *channeldb.DB, cc *chainControl,
 			addr = ip
 		}
 
+		// If a hostname rather than an IP was specified, then we'll
+		// advertise the hostname itself, allowing peers to reach us
+		// even if the IP it resolves to changes.
+		host, portStr, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		if net.ParseIP(host) == nil {
+			port, err := strconv.ParseUint(portStr, 10, 16)
+			if err != nil {
+				return nil, err
+			}
+
+			selfAddrs = append(selfAddrs, &lnwire.DNSHostnameAddress{
+				Hostname: host,
+				Port:     uint16(port),
+			})
+			continue
+		}
+
 		lnAddr, err := net.ResolveTCPAddr("tcp", addr)
 		if err != nil {
 			return nil, err
@@ -215,7 +377,7 @@ func newServer(listenAddrs []string, chanDB *channeldb.DB, cc *chainControl,
 		Addresses:            selfAddrs,
 		PubKey:               privKey.PubKey(),
 		Alias:                alias.String(),
-		Features:             globalFeatures,
+		Features:             s.featureMgr.Get(feature.SetNodeAnn),
 	}
 
 	// If our information has changed since our last boot, then we'll
@@ -230,7 +392,7 @@ func newServer(listenAddrs []string, chanDB *channeldb.DB, cc *chainControl,
 		Alias:     alias,
 		Features:  selfNode.Features,
 	}
-	selfNode.AuthSig, err = discovery.SignAnnouncement(s.nodeSigner,
+	selfNode.AuthSig, err = discovery.SignAnnouncement(s.annSigner,
 		s.identityPriv.PubKey(), nodeAnn,
 	)
 	if err != nil {
@@ -265,11 +427,74 @@ func newServer(listenAddrs []string, chanDB *channeldb.DB, cc *chainControl,
 
 			return s.htlcSwitch.SendHTLC(firstHopPub, htlcAdd, errorDecryptor)
 		},
+		GetPaymentResult: func(paymentHash [32]byte,
+			amt lnwire.MilliAtom,
+			circuit *sphinx.Circuit) ([32]byte, error) {
+
+			errorDecryptor := &htlcswitch.FailureDeobfuscator{
+				OnionDeobfuscator: sphinx.NewOnionDeobfuscator(circuit),
+			}
+
+			return s.htlcSwitch.GetPaymentResult(
+				paymentHash, amt, errorDecryptor,
+			)
+		},
+		QueryBandwidth: func(edge *channeldb.ChannelEdgeInfo) lnwire.MilliAtom {
+			// If our channel has no active link within the switch,
+			// then it's unable to carry any payment.
+			chanID := lnwire.NewChanIDFromOutPoint(&edge.ChannelPoint)
+			link, err := s.htlcSwitch.GetLink(chanID)
+			if err != nil {
+				return 0
+			}
+
+			return link.Bandwidth()
+		},
+		AddCircularPayment: s.invoices.AddCircularPayment,
+		Payments:           chanDB,
+		ZombieHorizon:      cfg.ZombieHorizon,
+		MissionControl: routing.MissionControlConfig{
+			PenaltyHalfLife:       cfg.MissionControl.PenaltyHalfLife,
+			AprioriHopProbability: cfg.MissionControl.AprioriHopProbability,
+		},
+		PathFinding: routing.PathFindingConfig{
+			AttemptCost: lnwire.NewMSatFromSatoshis(
+				btcutil.Amount(cfg.MissionControl.AttemptCost),
+			),
+			MinProbability: cfg.MissionControl.MinRouteProbability,
+		},
 	})
 	if err != nil {
 		return nil, fmt.Errorf("can't create router: %v", err)
 	}
 
+	// With the router created, we'll add any nodes blacklisted within the
+	// config to the router's persistent node blacklist.
+	addToBlacklist := func(pubStrs []string, avoidAsDest bool) error {
+		for _, pubStr := range pubStrs {
+			pubBytes, err := hex.DecodeString(pubStr)
+			if err != nil {
+				return err
+			}
+			pub, err := btcec.ParsePubKey(pubBytes, btcec.S256())
+			if err != nil {
+				return err
+			}
+
+			if err := s.chanRouter.BlacklistNode(pub, avoidAsDest); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+	if err := addToBlacklist(cfg.BlacklistNodes, false); err != nil {
+		return nil, fmt.Errorf("unable to blacklist node: %v", err)
+	}
+	if err := addToBlacklist(cfg.BlacklistDests, true); err != nil {
+		return nil, fmt.Errorf("unable to blacklist node: %v", err)
+	}
+
 	s.authGossiper, err = discovery.New(discovery.Config{
 		Router:           s.chanRouter,
 		Notifier:         s.cc.chainNotifier,
@@ -278,7 +503,7 @@ func newServer(listenAddrs []string, chanDB *channeldb.DB, cc *chainControl,
 		ProofMatureDelta: 0,
 		TrickleDelay:     time.Millisecond * 300,
 		DB:               chanDB,
-		AnnSigner:        s.nodeSigner,
+		AnnSigner:        s.annSigner,
 	},
 		s.identityPriv.PubKey(),
 	)
@@ -286,8 +511,38 @@ func newServer(listenAddrs []string, chanDB *channeldb.DB, cc *chainControl,
 		return nil, err
 	}
 
+	s.utxoNursery = newUtxoNursery(
+		chanDB, cc.chainNotifier, cc.wallet, s.sweeper,
+	)
+
 	s.breachArbiter = newBreachArbiter(cc.wallet, chanDB, cc.chainNotifier,
-		s.htlcSwitch, s.cc.chainIO, s.cc.feeEstimator)
+		s.htlcSwitch, s.cc.chainIO, s.cc.feeEstimator, s.eventBus)
+
+	s.chainArb = contractcourt.NewChainArbitrator(contractcourt.ChainArbitratorConfig{
+		OutgoingBroadcastDelta: defaultOutgoingBroadcastDelta,
+		IncomingBroadcastDelta: defaultIncomingBroadcastDelta,
+		ChainIO:                cc.chainIO,
+		Notifier:               cc.chainNotifier,
+		Signer:                 cc.wallet.Cfg.Signer,
+		FeeEstimator:           cc.feeEstimator,
+		PreimageDB:             s.witnessBeacon,
+		PublishTx:              cc.wallet.PublishTransaction,
+		MarkLinkInactive:       s.markLinkInactive,
+		IncubateOutputs: func(closeSummary *lnwallet.ForceCloseSummary) error {
+			s.utxoNursery.IncubateOutputs(closeSummary)
+			return nil
+		},
+		SweepInput: s.sweeper.SweepInput,
+		DeliverResolutionMsg: func(msgs ...contractcourt.ResolutionMsg) error {
+			for _, msg := range msgs {
+				err := s.htlcSwitch.ProcessContractResolution(msg)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}, chanDB)
 
 	// TODO(roasbeef): introduce closure and config system to decouple the
 	// initialization above ^
@@ -327,6 +582,12 @@ func (s *server) Start() error {
 		return nil
 	}
 
+	// Start the event bus first, as the sub-systems started below may
+	// send events over it.
+	if err := s.eventBus.Start(); err != nil {
+		return err
+	}
+
 	// Start the notification server. This is used so channel management
 	// goroutines can be notified when a funding transaction reaches a
 	// sufficient number of confirmations, or when the input for the
@@ -336,15 +597,39 @@ func (s *server) Start() error {
 		return err
 	}
 
+	// If requested, cross-check our open channels against the chain
+	// before any of them are loaded into the switch.
+	if cfg.ChanConsistencyCheck {
+		inconsistentChans, err := checkChannelConsistency(
+			s.chanDB, s.cc.chainIO,
+		)
+		if err != nil {
+			return err
+		}
+		s.inconsistentChans = inconsistentChans
+	}
+
+	if err := s.sphinx.Start(); err != nil {
+		return err
+	}
 	if err := s.htlcSwitch.Start(); err != nil {
 		return err
 	}
+	if err := s.sweeper.Start(); err != nil {
+		return err
+	}
 	if err := s.utxoNursery.Start(); err != nil {
 		return err
 	}
 	if err := s.breachArbiter.Start(); err != nil {
 		return err
 	}
+	if err := s.chainArb.Start(); err != nil {
+		return err
+	}
+	if err := s.watchChannelEvents(); err != nil {
+		return err
+	}
 	if err := s.authGossiper.Start(); err != nil {
 		return err
 	}
@@ -352,6 +637,14 @@ func (s *server) Start() error {
 		return err
 	}
 
+	// Forward each new block to the event bus.
+	blockEpochs, err := s.cc.chainNotifier.RegisterBlockEpochNtfn()
+	if err != nil {
+		return err
+	}
+	s.wg.Add(1)
+	go s.notifyBlockEpochs(blockEpochs)
+
 	// With all the relevant sub-systems started, we'll now attempt to
 	// establish persistent connections to our direct channel collaborators
 	// within the network.
@@ -380,7 +673,10 @@ func (s *server) Stop() error {
 	s.cc.chainNotifier.Stop()
 	s.chanRouter.Stop()
 	s.htlcSwitch.Stop()
+	s.sphinx.Stop()
 	s.utxoNursery.Stop()
+	s.sweeper.Stop()
+	s.chainArb.Stop()
 	s.breachArbiter.Stop()
 	s.authGossiper.Stop()
 	s.cc.wallet.Shutdown()
@@ -397,6 +693,102 @@ func (s *server) Stop() error {
 	// Wait for all lingering goroutines to quit.
 	s.wg.Wait()
 
+	// Stop the event bus last, as the sub-systems stopped above may
+	// send events over it until they've exited.
+	s.eventBus.Stop()
+
+	return nil
+}
+
+// watchChannelEvents launches a goroutine which keeps the chain arbitrator
+// aware of each channel that's opened, and each that's closed other than by
+// the arbitrator itself.
+func (s *server) watchChannelEvents() error {
+	chanSubscription, err := s.eventBus.Subscribe(
+		channelOpenEvent{}, channelCloseEvent{},
+	)
+	if err != nil {
+		return err
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer chanSubscription.Cancel()
+		defer s.wg.Done()
+
+		for {
+			select {
+			case event := <-chanSubscription.Updates():
+				var err error
+				switch e := event.(type) {
+				case channelOpenEvent:
+					err = s.chainArb.WatchNewChannel(
+						e.chanPoint, e.shortChanID,
+					)
+
+				case channelCloseEvent:
+					err = s.chainArb.ResolveContract(
+						e.chanPoint,
+					)
+				}
+				if err != nil {
+					srvrLog.Errorf("unable to update chain "+
+						"arbitrator: %v", err)
+				}
+
+			// If our subscription ended, either as the event bus
+			// is shutting down or as we fell behind on its
+			// updates, then we'll exit.
+			case <-chanSubscription.Quit():
+				err := chanSubscription.Err()
+				if err == subscribe.ErrClientTooSlow {
+					srvrLog.Errorf("channel event "+
+						"subscription cancelled: %v", err)
+				}
+				return
+
+			case <-s.quit:
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+// markLinkInactive ensures that the switch no longer sees the channel with
+// the passed funding outpoint as eligible for forwarding HTLC's, as it's
+// about to be force closed. If the peer is online, then we'll also purge all
+// of its indexes.
+func (s *server) markLinkInactive(chanPoint wire.OutPoint) error {
+	dbChan, err := s.chanDB.FetchChannel(chanPoint)
+	if err != nil {
+		return err
+	}
+
+	if peer, err := s.FindPeer(dbChan.IdentityPub); err == nil {
+		channel, err := lnwallet.NewLightningChannel(
+			s.cc.wallet.Cfg.Signer, nil, s.cc.feeEstimator, dbChan,
+		)
+		if err != nil {
+			return err
+		}
+		if err := peer.WipeChannel(channel); err != nil {
+			return err
+		}
+	} else {
+		chanID := lnwire.NewChanIDFromOutPoint(&chanPoint)
+		s.htlcSwitch.RemoveLink(chanID)
+	}
+
+	// The breach arbiter no longer needs to watch the channel, as it's
+	// being closed by our own commitment.
+	select {
+	case s.breachArbiter.settledContracts <- &chanPoint:
+	case <-s.quit:
+		return fmt.Errorf("server shutting down")
+	}
+
 	return nil
 }
 
@@ -433,7 +825,7 @@ func (s *server) genNodeAnnouncement(
 
 	s.currentNodeAnn.Timestamp = newStamp
 	s.currentNodeAnn.Signature, err = discovery.SignAnnouncement(
-		s.nodeSigner, s.identityPriv.PubKey(), s.currentNodeAnn,
+		s.annSigner, s.identityPriv.PubKey(), s.currentNodeAnn,
 	)
 
 	return *s.currentNodeAnn, err
@@ -516,9 +908,22 @@ func (s *server) establishPersistentConnections() error {
 			}
 		} else {
 			for _, addr := range policy.Node.Addresses {
-				polTCPAddr, ok := addr.(*net.TCPAddr)
-				if ok {
-					addrs = append(addrs, polTCPAddr)
+				switch addr := addr.(type) {
+				case *net.TCPAddr:
+					addrs = append(addrs, addr)
+
+				// As the hostname may point to a dynamic IP,
+				// it's resolved each time we reconnect.
+				case *lnwire.DNSHostnameAddress:
+					tcpAddr, err := net.ResolveTCPAddr(
+						"tcp", addr.String(),
+					)
+					if err != nil {
+						srvrLog.Warnf("unable to resolve "+
+							"%v: %v", addr, err)
+						continue
+					}
+					addrs = append(addrs, tcpAddr)
 				}
 			}
 		}
@@ -534,6 +939,37 @@ func (s *server) establishPersistentConnections() error {
 		return err
 	}
 
+	// Finally, we'll add the addresses recorded within the peer address
+	// book, which allows us to reconnect to channel peers whose
+	// NodeAnnouncement is absent from the graph. As the dialed addresses
+	// are known to have been reachable, they'll be attempted first.
+	addrBook, err := s.chanDB.FetchAllPeerAddresses()
+	if err != nil {
+		return err
+	}
+	for _, entry := range addrBook {
+		pubStr := string(entry.PubKey.SerializeCompressed())
+		nodeAddrs, ok := nodeAddrsMap[pubStr]
+		if !ok {
+			continue
+		}
+
+		addrs := entry.Addresses()
+		for _, addr := range nodeAddrs.addresses {
+			var known bool
+			for _, bookAddr := range addrs {
+				if bookAddr.String() == addr.String() {
+					known = true
+					break
+				}
+			}
+			if !known {
+				addrs = append(addrs, addr)
+			}
+		}
+		nodeAddrs.addresses = addrs
+	}
+
 	// Iterate through the combined list of addresses from prior links and
 	// node announcements and attempt to reconnect to each node.
 	for pubStr, nodeAddr := range nodeAddrsMap {
@@ -746,6 +1182,8 @@ func (s *server) peerTerminationWatcher(p *peer) {
 
 	srvrLog.Debugf("Peer %v has been disconnected", p)
 
+	sendEvent(s.eventBus, peerOfflineEvent{pubKey: p.pubKeyBytes})
+
 	// If the server is exiting then we can bail out early ourselves as all
 	// the other sub-systems will already be shutting down.
 	if s.Stopped() {
@@ -788,10 +1226,25 @@ func (s *server) peerTerminationWatcher(p *peer) {
 			"connection to peer %v", p)
 
 		// If so, then we'll attempt to re-establish a persistent
-		// connection to the peer.
-		// TODO(roasbeef): look up latest info for peer in database
+		// connection to the peer. If the peer connected to us, then
+		// the address of the connection likely uses an ephemeral port,
+		// so we'll prefer an address from the peer address book.
+		addr := p.addr
+		if p.inbound {
+			entry, err := s.chanDB.FetchPeerAddresses(
+				p.addr.IdentityKey,
+			)
+			if err == nil && len(entry.Addresses()) > 0 {
+				addr = &lnwire.NetAddress{
+					IdentityKey: p.addr.IdentityKey,
+					Address:     entry.Addresses()[0],
+					ChainNet:    p.addr.ChainNet,
+				}
+			}
+		}
+
 		connReq := &connmgr.ConnReq{
-			Addr:      p.addr,
+			Addr:      addr,
 			Permanent: true,
 		}
 
@@ -832,8 +1285,11 @@ func (s *server) peerConnected(conn net.Conn, connReq *connmgr.ConnReq,
 		return
 	}
 
-	// TODO(roasbeef): update IP address for link-node
-	//  * also mark last-seen, do it one single transaction?
+	// Record the connection within the peer address book in the
+	// background, so we're able to reconnect to the peer even if its
+	// NodeAnnouncement is absent from the graph.
+	s.wg.Add(1)
+	go s.recordPeerAddresses(peerAddr, inbound)
 
 	// Attempt to start the peer, if we're unable to do so, then disconnect
 	// this peer.
@@ -842,9 +1298,101 @@ func (s *server) peerConnected(conn net.Conn, connReq *connmgr.ConnReq,
 		return
 	}
 
+	sendEvent(s.eventBus, peerOnlineEvent{pubKey: p.pubKeyBytes})
+
 	s.addPeer(p)
 }
 
+// notifyBlockEpochs sends a blockEpochEvent over the event bus for each new
+// block received from the passed epoch notification.
+//
+// NOTE: This MUST be run as a goroutine.
+func (s *server) notifyBlockEpochs(blockEpochs *chainntnfs.BlockEpochEvent) {
+	defer s.wg.Done()
+	defer blockEpochs.Cancel()
+
+	for {
+		select {
+		case epoch, ok := <-blockEpochs.Epochs:
+			if !ok {
+				return
+			}
+
+			sendEvent(s.eventBus, blockEpochEvent{
+				height: epoch.Height,
+				hash:   *epoch.Hash,
+			})
+
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// forwardLimit returns the htlcswitch.ForwardLimit which bounds the value of
+// the HTLCs forwarded within each configured interval to the passed amount in
+// satoshis. An amount of zero disables the limit.
+func forwardLimit(maxAmt int64) htlcswitch.ForwardLimit {
+	return htlcswitch.ForwardLimit{
+		MaxAmount: lnwire.NewMSatFromSatoshis(btcutil.Amount(maxAmt)),
+		Interval:  cfg.Throttle.Interval,
+	}
+}
+
+// maxDustExposure returns the configured maximum dust exposure of each
+// channel link, converting it from atoms to the milli-atoms used by the links.
+func maxDustExposure() lnwire.MilliAtom {
+	return lnwire.NewMSatFromSatoshis(btcutil.Amount(cfg.MaxDustExposure))
+}
+
+// recordPeerAddresses updates the peer address book entry of a newly
+// connected peer, if it's one of our channel counterparties. The address of
+// the connection is only recorded as a dialed address if the connection was
+// outbound, as the address of an inbound connection likely uses an ephemeral
+// port. The addresses the peer advertises within the graph are also recorded.
+//
+// NOTE: This MUST be run as a goroutine.
+func (s *server) recordPeerAddresses(peerAddr *lnwire.NetAddress,
+	inbound bool) {
+
+	defer s.wg.Done()
+
+	pub := peerAddr.IdentityKey
+	channels, err := s.chanDB.FetchOpenChannels(pub)
+	if err != nil {
+		srvrLog.Errorf("unable to fetch channels for peer %x: %v",
+			pub.SerializeCompressed(), err)
+		return
+	}
+	if len(channels) == 0 {
+		return
+	}
+
+	var dialed *net.TCPAddr
+	if !inbound {
+		dialed = peerAddr.Address
+	}
+
+	// If the peer's NodeAnnouncement is within the graph, then we'll
+	// replace the addresses it's known to advertise.
+	var advertised []*net.TCPAddr
+	node, err := s.chanDB.ChannelGraph().FetchLightningNode(pub)
+	if err == nil && node.HaveNodeAnnouncement {
+		advertised = make([]*net.TCPAddr, 0, len(node.Addresses))
+		for _, addr := range node.Addresses {
+			if tcpAddr, ok := addr.(*net.TCPAddr); ok {
+				advertised = append(advertised, tcpAddr)
+			}
+		}
+	}
+
+	err = s.chanDB.RecordPeerConnection(pub, dialed, advertised, time.Now())
+	if err != nil {
+		srvrLog.Errorf("unable to record addresses of peer %x: %v",
+			pub.SerializeCompressed(), err)
+	}
+}
+
 // shouldDropConnection determines if our local connection to a remote peer
 // should be dropped in the case of concurrent connection establishment. In
 // order to deterministically decide which connection should be dropped, we'll
@@ -1092,7 +1640,15 @@ type openChanReq struct {
 	localFundingAmt  btcutil.Amount
 	remoteFundingAmt btcutil.Amount
 
-	pushAmt lnwire.MilliSatoshi
+	pushAmt lnwire.MilliAtom
+
+	// dustLimit is the dust limit we'll propose for our commitment
+	// transaction. If zero, then the default dust limit is used.
+	dustLimit btcutil.Amount
+
+	// upfrontShutdown is the script we'll commit to pay out to upon a
+	// cooperative close. If empty, then we make no such commitment.
+	upfrontShutdown []byte
 
 	// TODO(roasbeef): add ability to specify channel constraints as well
 
@@ -1213,8 +1769,9 @@ func (s *server) DisconnectPeer(pubKey *btcec.PublicKey) error {
 //
 // NOTE: This function is safe for concurrent access.
 func (s *server) OpenChannel(peerID int32, nodeKey *btcec.PublicKey,
-	localAmt btcutil.Amount,
-	pushAmt lnwire.MilliSatoshi) (chan *lnrpc.OpenStatusUpdate, chan error) {
+	localAmt btcutil.Amount, pushAmt lnwire.MilliAtom,
+	dustLimit btcutil.Amount,
+	upfrontShutdown []byte) (chan *lnrpc.OpenStatusUpdate, chan error) {
 
 	updateChan := make(chan *lnrpc.OpenStatusUpdate, 1)
 	errChan := make(chan error, 1)
@@ -1257,6 +1814,8 @@ func (s *server) OpenChannel(peerID int32, nodeKey *btcec.PublicKey,
 		chainHash:       *activeNetParams.GenesisHash,
 		localFundingAmt: localAmt,
 		pushAmt:         pushAmt,
+		dustLimit:       dustLimit,
+		upfrontShutdown: upfrontShutdown,
 		updates:         updateChan,
 		err:             errChan,
 	}
@@ -0,0 +1,309 @@
+package subscribe
+
+import (
+	"container/list"
+	"errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	// ErrServerShuttingDown is returned when attempting to subscribe to
+	// or send an update to a server which is shutting down.
+	ErrServerShuttingDown = errors.New("subscribe server shutting down")
+
+	// ErrClientTooSlow is returned by a client whose subscription was
+	// cancelled as it fell too far behind on the updates sent to it.
+	ErrClientTooSlow = errors.New("subscribe client too slow")
+)
+
+const (
+	// DefaultMaxQueuedUpdates is the maximum number of updates which may
+	// be queued for a client before its subscription is cancelled.
+	DefaultMaxQueuedUpdates = 10000
+)
+
+// Server is an event bus which delivers the updates sent to it to all of its
+// subscribed clients. Producers send updates to the server without any
+// knowledge of the set of consumers, which allows new consumers to be added
+// without touching the producers. Each update is delivered to clients in the
+// order it was sent, and a slow client never blocks the producers or the
+// other clients, as the pending updates of each client are queued. A client
+// which falls too far behind has its subscription cancelled, rather than
+// having its queue grow without bound.
+type Server struct {
+	started uint32
+	stopped uint32
+
+	clientCounter uint64
+
+	clients map[uint64]*Client
+
+	// maxQueuedUpdates is the maximum number of updates which may be
+	// queued for a client before its subscription is cancelled.
+	maxQueuedUpdates int
+
+	clientUpdates chan *clientUpdate
+	updates       chan interface{}
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// clientUpdate is used to add a client to, or remove a client from, the set
+// of clients of the server.
+type clientUpdate struct {
+	cancel bool
+	client *Client
+}
+
+// NewServer creates a new Server.
+func NewServer() *Server {
+	return &Server{
+		clients:          make(map[uint64]*Client),
+		maxQueuedUpdates: DefaultMaxQueuedUpdates,
+		clientUpdates:    make(chan *clientUpdate),
+		updates:          make(chan interface{}),
+		quit:             make(chan struct{}),
+	}
+}
+
+// Start starts the server's event loop.
+func (s *Server) Start() error {
+	if !atomic.CompareAndSwapUint32(&s.started, 0, 1) {
+		return nil
+	}
+
+	s.wg.Add(1)
+	go s.eventLoop()
+
+	return nil
+}
+
+// Stop stops the server, cancelling the subscriptions of all its clients.
+func (s *Server) Stop() error {
+	if !atomic.CompareAndSwapUint32(&s.stopped, 0, 1) {
+		return nil
+	}
+
+	close(s.quit)
+	s.wg.Wait()
+
+	return nil
+}
+
+// Subscribe returns a new client which receives the updates sent to the
+// server. If any event types are passed, then the client only receives the
+// updates which share the type of one of them, e.g. passing ChannelEvent{}
+// subscribes the client to all updates of type ChannelEvent. Otherwise, the
+// client receives every update.
+func (s *Server) Subscribe(eventTypes ...interface{}) (*Client, error) {
+	client := &Client{
+		id:      atomic.AddUint64(&s.clientCounter, 1),
+		updates: make(chan interface{}),
+		queue:   list.New(),
+		signal:  make(chan struct{}, 1),
+		quit:    make(chan struct{}),
+	}
+	if len(eventTypes) != 0 {
+		client.eventTypes = make(map[reflect.Type]struct{})
+		for _, eventType := range eventTypes {
+			client.eventTypes[reflect.TypeOf(eventType)] = struct{}{}
+		}
+	}
+	client.cancel = func() {
+		select {
+		case s.clientUpdates <- &clientUpdate{
+			cancel: true,
+			client: client,
+		}:
+		case <-s.quit:
+		}
+	}
+
+	select {
+	case s.clientUpdates <- &clientUpdate{client: client}:
+	case <-s.quit:
+		return nil, ErrServerShuttingDown
+	}
+
+	return client, nil
+}
+
+// SendUpdate sends the passed update to all the clients subscribed to its
+// type. The update is queued for each client, so this never blocks on a slow
+// client. A client whose queue is full has its subscription cancelled
+// instead.
+func (s *Server) SendUpdate(update interface{}) error {
+	select {
+	case s.updates <- update:
+		return nil
+	case <-s.quit:
+		return ErrServerShuttingDown
+	}
+}
+
+// eventLoop manages the set of clients of the server, and dispatches each
+// update to the clients subscribed to it.
+//
+// NOTE: This MUST be run as a goroutine.
+func (s *Server) eventLoop() {
+	defer s.wg.Done()
+
+	for {
+		select {
+		case req := <-s.clientUpdates:
+			if !req.cancel {
+				s.clients[req.client.id] = req.client
+
+				s.wg.Add(1)
+				go req.client.dispatch(&s.wg)
+				continue
+			}
+
+			if _, ok := s.clients[req.client.id]; ok {
+				delete(s.clients, req.client.id)
+				close(req.client.quit)
+			}
+
+		case update := <-s.updates:
+			for id, client := range s.clients {
+				if !client.subscribedTo(update) {
+					continue
+				}
+
+				if client.enqueue(update, s.maxQueuedUpdates) {
+					continue
+				}
+
+				// The client isn't keeping up with its
+				// updates, so we'll cancel its subscription.
+				delete(s.clients, id)
+				client.err = ErrClientTooSlow
+				close(client.quit)
+			}
+
+		case <-s.quit:
+			for _, client := range s.clients {
+				client.err = ErrServerShuttingDown
+				close(client.quit)
+			}
+			return
+		}
+	}
+}
+
+// Client is a subscription to the updates sent to a Server.
+type Client struct {
+	id uint64
+
+	// eventTypes is the set of types of updates the client is subscribed
+	// to. If nil, then the client is subscribed to all updates.
+	eventTypes map[reflect.Type]struct{}
+
+	// updates is the channel the client's updates are delivered over.
+	updates chan interface{}
+
+	// queue holds the updates which are yet to be delivered to the
+	// client. Its access is guarded by mtx, and signal is sent to each
+	// time an update is added.
+	mtx    sync.Mutex
+	queue  *list.List
+	signal chan struct{}
+
+	// err is the reason the client's subscription ended, if it wasn't
+	// cancelled by the client itself. It's set before quit is closed.
+	err error
+
+	cancel func()
+	quit   chan struct{}
+}
+
+// Updates returns the channel over which the client's updates are delivered.
+func (c *Client) Updates() <-chan interface{} {
+	return c.updates
+}
+
+// Quit returns a channel which is closed once the client's subscription has
+// been cancelled, or the server has been stopped.
+func (c *Client) Quit() <-chan struct{} {
+	return c.quit
+}
+
+// Err returns the reason the client's subscription ended, which is either
+// ErrServerShuttingDown or ErrClientTooSlow, or nil if the client cancelled
+// it. It MUST only be called once the channel returned by Quit is closed.
+func (c *Client) Err() error {
+	return c.err
+}
+
+// Cancel cancels the client's subscription. No further updates are delivered
+// to the client once this returns.
+func (c *Client) Cancel() {
+	c.cancel()
+}
+
+// subscribedTo returns true if the client is subscribed to the passed update.
+func (c *Client) subscribedTo(update interface{}) bool {
+	if c.eventTypes == nil {
+		return true
+	}
+
+	_, ok := c.eventTypes[reflect.TypeOf(update)]
+	return ok
+}
+
+// enqueue adds an update to the client's queue of pending updates. False is
+// returned if the queue already holds maxQueued updates, in which case the
+// update isn't added.
+func (c *Client) enqueue(update interface{}, maxQueued int) bool {
+	c.mtx.Lock()
+	if c.queue.Len() >= maxQueued {
+		c.mtx.Unlock()
+		return false
+	}
+	c.queue.PushBack(update)
+	c.mtx.Unlock()
+
+	select {
+	case c.signal <- struct{}{}:
+	default:
+	}
+
+	return true
+}
+
+// dispatch delivers the client's pending updates in order until the client's
+// subscription is cancelled or the server is stopped.
+//
+// NOTE: This MUST be run as a goroutine.
+func (c *Client) dispatch(wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	for {
+		c.mtx.Lock()
+		next := c.queue.Front()
+		if next != nil {
+			c.queue.Remove(next)
+		}
+		c.mtx.Unlock()
+
+		// If there are no pending updates, then we'll wait until
+		// another is added.
+		if next == nil {
+			select {
+			case <-c.signal:
+				continue
+			case <-c.quit:
+				return
+			}
+		}
+
+		select {
+		case c.updates <- next.Value:
+		case <-c.quit:
+			return
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package subscribe
+
+import (
+	"testing"
+	"time"
+)
+
+type eventA struct {
+	n int
+}
+
+type eventB struct {
+	n int
+}
+
+// receive returns the next update delivered to the client, failing the test
+// if none arrives in time.
+func receive(t *testing.T, client *Client) interface{} {
+	select {
+	case update := <-client.Updates():
+		return update
+	case <-time.After(time.Second * 5):
+		t.Fatal("update not received")
+	}
+
+	return nil
+}
+
+// TestSubscribe checks that updates are delivered in order to the clients
+// subscribed to their types, and that no updates are delivered once a client
+// has been cancelled.
+func TestSubscribe(t *testing.T) {
+	t.Parallel()
+
+	s := NewServer()
+	if err := s.Start(); err != nil {
+		t.Fatalf("unable to start server: %v", err)
+	}
+	defer s.Stop()
+
+	all, err := s.Subscribe()
+	if err != nil {
+		t.Fatalf("unable to subscribe: %v", err)
+	}
+	onlyA, err := s.Subscribe(eventA{})
+	if err != nil {
+		t.Fatalf("unable to subscribe: %v", err)
+	}
+	cancelled, err := s.Subscribe(eventB{})
+	if err != nil {
+		t.Fatalf("unable to subscribe: %v", err)
+	}
+	cancelled.Cancel()
+
+	select {
+	case <-cancelled.Quit():
+	case <-time.After(time.Second * 5):
+		t.Fatal("cancelled client not quit")
+	}
+
+	// Send a number of updates before reading any of them, so that they
+	// must be queued.
+	const numUpdates = 10
+	for i := 0; i < numUpdates; i++ {
+		if err := s.SendUpdate(eventA{i}); err != nil {
+			t.Fatalf("unable to send update: %v", err)
+		}
+		if err := s.SendUpdate(eventB{i}); err != nil {
+			t.Fatalf("unable to send update: %v", err)
+		}
+	}
+
+	for i := 0; i < numUpdates; i++ {
+		if update := receive(t, all); update != (eventA{i}) {
+			t.Fatalf("expected %v, got %v", eventA{i}, update)
+		}
+		if update := receive(t, all); update != (eventB{i}) {
+			t.Fatalf("expected %v, got %v", eventB{i}, update)
+		}
+		if update := receive(t, onlyA); update != (eventA{i}) {
+			t.Fatalf("expected %v, got %v", eventA{i}, update)
+		}
+	}
+
+	select {
+	case update := <-cancelled.Updates():
+		t.Fatalf("cancelled client received update: %v", update)
+	case update := <-onlyA.Updates():
+		t.Fatalf("client received unsubscribed update: %v", update)
+	case <-time.After(time.Millisecond * 100):
+	}
+
+	// Once the server is stopped, all remaining clients should quit.
+	s.Stop()
+	for _, client := range []*Client{all, onlyA} {
+		select {
+		case <-client.Quit():
+		case <-time.After(time.Second * 5):
+			t.Fatal("client not quit after server stopped")
+		}
+		if err := client.Err(); err != ErrServerShuttingDown {
+			t.Fatalf("expected ErrServerShuttingDown, got %v", err)
+		}
+	}
+	if err := cancelled.Err(); err != nil {
+		t.Fatalf("expected no error for cancelled client, got %v", err)
+	}
+
+	if err := s.SendUpdate(eventA{}); err != ErrServerShuttingDown {
+		t.Fatalf("expected ErrServerShuttingDown, got %v", err)
+	}
+}
+
+// TestSubscribeSlowClient checks that a client which falls too far behind on
+// its updates has its subscription cancelled, without affecting the other
+// clients.
+func TestSubscribeSlowClient(t *testing.T) {
+	t.Parallel()
+
+	const maxQueued = 5
+
+	s := NewServer()
+	s.maxQueuedUpdates = maxQueued
+	if err := s.Start(); err != nil {
+		t.Fatalf("unable to start server: %v", err)
+	}
+	defer s.Stop()
+
+	slow, err := s.Subscribe()
+	if err != nil {
+		t.Fatalf("unable to subscribe: %v", err)
+	}
+	fast, err := s.Subscribe()
+	if err != nil {
+		t.Fatalf("unable to subscribe: %v", err)
+	}
+
+	// The slow client never reads its updates, so one more update than
+	// its queue and the update held by its dispatcher can take should
+	// cancel its subscription.
+	for i := 0; i < maxQueued+2; i++ {
+		if err := s.SendUpdate(eventA{i}); err != nil {
+			t.Fatalf("unable to send update: %v", err)
+		}
+		if update := receive(t, fast); update != (eventA{i}) {
+			t.Fatalf("expected %v, got %v", eventA{i}, update)
+		}
+	}
+
+	select {
+	case <-slow.Quit():
+	case <-time.After(time.Second * 5):
+		t.Fatal("slow client not quit")
+	}
+	if err := slow.Err(); err != ErrClientTooSlow {
+		t.Fatalf("expected ErrClientTooSlow, got %v", err)
+	}
+
+	select {
+	case <-fast.Quit():
+		t.Fatal("fast client quit")
+	default:
+	}
+}
@@ -0,0 +1,117 @@
+package sweep
+
+import (
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/roasbeef/btcd/txscript"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// Input represents an abstract UTXO which is to be spent using a sweeping
+// transaction. The method provided give the caller all information needed to
+// construct a valid input within a sweeping transaction to sweep this
+// lingering UTXO.
+type Input interface {
+	// OutPoint returns the reference to the output being spent, used to
+	// construct the corresponding transaction input.
+	OutPoint() *wire.OutPoint
+
+	// WitnessType returns an enum specifying the type of witness that must
+	// be generated in order to spend this output.
+	WitnessType() lnwallet.WitnessType
+
+	// SignDesc returns a reference to a spendable output's sign
+	// descriptor, which is used during signing to compute a valid
+	// signature that proves ownership of the output.
+	SignDesc() *lnwallet.SignDescriptor
+
+	// BlocksToMaturity returns the relative timelock, as a number of
+	// blocks, that must be built on top of the confirmation height before
+	// the output can be spent. It's used as the sequence of the input.
+	BlocksToMaturity() uint32
+
+	// BuildWitness returns a valid witness allowing this output to be
+	// spent, the witness should be attached to the transaction at the
+	// location determined by the given txinIdx.
+	BuildWitness(signer lnwallet.Signer, txn *wire.MsgTx,
+		hashCache *txscript.TxSigHashes, txinIdx int) ([][]byte, error)
+}
+
+// BaseInput contains all the information needed to sweep an output.
+type BaseInput struct {
+	outpoint         wire.OutPoint
+	witnessType      lnwallet.WitnessType
+	signDesc         lnwallet.SignDescriptor
+	blocksToMaturity uint32
+}
+
+// NewBaseInput allocates and assembles a new *BaseInput that can be used to
+// construct a sweep transaction.
+func NewBaseInput(outpoint *wire.OutPoint, witnessType lnwallet.WitnessType,
+	signDescriptor *lnwallet.SignDescriptor) *BaseInput {
+
+	return &BaseInput{
+		outpoint:    *outpoint,
+		witnessType: witnessType,
+		signDesc:    *signDescriptor,
+	}
+}
+
+// NewCsvInput assembles a new csv-locked input that can be used to construct
+// a sweep transaction.
+func NewCsvInput(outpoint *wire.OutPoint, witnessType lnwallet.WitnessType,
+	signDescriptor *lnwallet.SignDescriptor,
+	blocksToMaturity uint32) *BaseInput {
+
+	input := NewBaseInput(outpoint, witnessType, signDescriptor)
+	input.blocksToMaturity = blocksToMaturity
+
+	return input
+}
+
+// OutPoint returns the breached output's identifier that is to be included as
+// a transaction input.
+//
+// NOTE: This is part of the Input interface.
+func (bi *BaseInput) OutPoint() *wire.OutPoint {
+	return &bi.outpoint
+}
+
+// WitnessType returns the type of witness that must be generated to spend the
+// breached output.
+//
+// NOTE: This is part of the Input interface.
+func (bi *BaseInput) WitnessType() lnwallet.WitnessType {
+	return bi.witnessType
+}
+
+// SignDesc returns the breached output's SignDescriptor, which is used during
+// signing to compute the witness.
+//
+// NOTE: This is part of the Input interface.
+func (bi *BaseInput) SignDesc() *lnwallet.SignDescriptor {
+	return &bi.signDesc
+}
+
+// BlocksToMaturity returns the relative timelock of the input.
+//
+// NOTE: This is part of the Input interface.
+func (bi *BaseInput) BlocksToMaturity() uint32 {
+	return bi.blocksToMaturity
+}
+
+// BuildWitness computes a valid witness that allows us to spend from the
+// breached output. It does so by generating the witness generation function,
+// which is parameterized primarily by the witness type and sign descriptor.
+// The method then returns the witness computed by invoking this function.
+//
+// NOTE: This is part of the Input interface.
+func (bi *BaseInput) BuildWitness(signer lnwallet.Signer, txn *wire.MsgTx,
+	hashCache *txscript.TxSigHashes, txinIdx int) ([][]byte, error) {
+
+	witnessFunc := bi.witnessType.GenWitnessFunc(&signer, bi.SignDesc())
+
+	return witnessFunc(txn, hashCache, txinIdx)
+}
+
+// A compile time check to ensure BaseInput meets the Input interface.
+var _ Input = (*BaseInput)(nil)
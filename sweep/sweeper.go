@@ -0,0 +1,1018 @@
+package sweep
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+var (
+	// ErrRemoteSpend is returned in case an output that we try to sweep is
+	// confirmed in a tx of the remote party.
+	ErrRemoteSpend = errors.New("remote party swept utxo")
+
+	// ErrTooManyAttempts is returned in case sweeping an output has failed
+	// for the configured max number of attempts.
+	ErrTooManyAttempts = errors.New("sweep failed after max attempts")
+
+	// ErrSweepInputNotFound is returned when a fee bump is requested for
+	// an input which isn't pending within the sweeper.
+	ErrSweepInputNotFound = errors.New("input not found in sweeper")
+
+	// ErrSweeperShuttingDown is returned to callers of the sweeper once
+	// it has been signalled to exit.
+	ErrSweeperShuttingDown = errors.New("utxo sweeper shutting down")
+)
+
+const (
+	// DefaultBatchWindowDuration specifies duration of the sweep batch
+	// window. The sweep is held back during the batch window to allow
+	// more inputs to be added and thereby lower the fee per input.
+	DefaultBatchWindowDuration = 30 * time.Second
+
+	// DefaultMaxInputsPerTx specifies the default maximum number of
+	// inputs allowed in a single sweep tx. If more need to be swept,
+	// multiple txes are created and published.
+	DefaultMaxInputsPerTx = 100
+
+	// DefaultMaxSweepAttempts specifies the default maximum number of
+	// times an input is included in a publish attempt before giving up
+	// and returning an error to the caller.
+	DefaultMaxSweepAttempts = 10
+
+	// DefaultFeeRateBucketSize is the default size of the fee rate
+	// buckets, in sat/weight, into which inputs are clustered. Inputs
+	// whose fee rates fall within the same bucket are swept together.
+	DefaultFeeRateBucketSize = 10
+
+	// DefaultConfTarget is the confirmation target used to determine the
+	// fee rate of inputs which don't have a deadline.
+	DefaultConfTarget = 6
+
+	// DefaultMaxFeePerWeight is the default maximum fee rate, in
+	// sat/weight, the sweeper will pay to sweep an input. It's the fee
+	// rate inputs are swept at once their deadline has been reached.
+	DefaultMaxFeePerWeight = 100
+)
+
+// DefaultNextAttemptDeltaFunc returns the number of blocks to wait before
+// re-attempting to sweep an input, backing off exponentially with the number
+// of attempts made so far.
+func DefaultNextAttemptDeltaFunc(attempts int) int32 {
+	if attempts > 10 {
+		attempts = 10
+	}
+
+	return 1 << uint(attempts-1)
+}
+
+// Params contains the parameters that control the sweeping of an input.
+type Params struct {
+	// Deadline is the block height by which the sweep of the input must
+	// be confirmed, such as the CLTV expiry of the HTLC it pays out,
+	// less a safety margin. As the deadline approaches, the fee rate of
+	// the input is escalated, reaching the maximum fee rate at the
+	// deadline. A zero deadline means the input isn't time sensitive, and
+	// is swept at the fee rate for the default confirmation target.
+	Deadline int32
+}
+
+// DeadlineFromExpiry returns the deadline for sweeping an input paying out an
+// HTLC with the passed CLTV expiry, leaving safetyDelta blocks for the sweep
+// to confirm before the HTLC can be claimed by the remote party.
+func DeadlineFromExpiry(expiry, safetyDelta uint32) int32 {
+	if expiry <= safetyDelta {
+		return 1
+	}
+
+	return int32(expiry - safetyDelta)
+}
+
+// Result is the struct that is pushed through the result channel. Callers can
+// use this to be informed of the final sweep result. In case of a remote
+// spend, Err will be ErrRemoteSpend.
+type Result struct {
+	// Err is the final result of the sweep. It is nil when the input is
+	// swept successfully by us. ErrRemoteSpend is returned when another
+	// party took the input.
+	Err error
+
+	// Tx is the transaction that spent the input.
+	Tx *wire.MsgTx
+}
+
+// UtxoSweeperConfig contains dependencies of UtxoSweeper.
+type UtxoSweeperConfig struct {
+	// GenSweepScript generates a P2WKH script belonging to the wallet
+	// where funds can be swept.
+	GenSweepScript func() ([]byte, error)
+
+	// FeeEstimator is used when crafting sweep transactions to estimate
+	// the necessary fee relative to the expected size of the sweep
+	// transaction.
+	FeeEstimator lnwallet.FeeEstimator
+
+	// PublishTransaction facilitates the process of broadcasting a signed
+	// transaction to the appropriate network.
+	PublishTransaction func(*wire.MsgTx) error
+
+	// NewBatchTimer creates a channel that will be sent on when a certain
+	// time window has passed. During this time window, new inputs can
+	// still be added to the sweep tx that is about to be generated.
+	NewBatchTimer func() <-chan time.Time
+
+	// Notifier is an instance of a chain notifier we'll use to watch for
+	// new blocks, and the spends of the inputs being swept.
+	Notifier chainntnfs.ChainNotifier
+
+	// ChainIO is used to determine the current block height.
+	ChainIO lnwallet.BlockChainIO
+
+	// Signer is used by the sweeper to generate valid witnesses at the
+	// time the incubated outputs need to be spent.
+	Signer lnwallet.Signer
+
+	// MaxInputsPerTx specifies the default maximum number of inputs
+	// allowed in a single sweep tx. If more need to be swept, multiple
+	// txes are created and published.
+	MaxInputsPerTx int
+
+	// MaxSweepAttempts specifies the maximum number of times an input is
+	// included in a publish attempt before giving up and returning an
+	// error to the caller.
+	MaxSweepAttempts int
+
+	// NextAttemptDeltaFunc returns given the number of already attempted
+	// sweeps, how many blocks to wait before retrying to sweep.
+	NextAttemptDeltaFunc func(int) int32
+
+	// FeeRateBucketSize is the size of the fee rate buckets, in
+	// sat/weight, into which inputs are clustered.
+	FeeRateBucketSize int
+
+	// MaxFeePerWeight is the maximum fee rate, in sat/weight, the sweeper
+	// will pay to sweep an input. Inputs whose deadline has been reached
+	// are swept at this fee rate.
+	MaxFeePerWeight btcutil.Amount
+}
+
+// pendingInput is created when an input is offered to the sweeper. It tracks
+// the state of the input until it has been swept.
+type pendingInput struct {
+	// listeners is a list of channels over which the final outcome of
+	// the sweep needs to be broadcasted.
+	listeners []chan Result
+
+	// ntfnRegCancel is populated with a function that cancels the chain
+	// notifier spend registration.
+	ntfnRegCancel func()
+
+	// input is the original struct that contains the input and sign
+	// descriptor.
+	input Input
+
+	// params are the sweep parameters the input was offered with.
+	params Params
+
+	// startHeight is the height at which the input was offered to the
+	// sweeper, from which its fee rate escalates towards its deadline.
+	startHeight int32
+
+	// startFeeRate is the fee rate, in sat/weight, estimated to confirm
+	// the input by its deadline at the time it was offered to the
+	// sweeper.
+	startFeeRate btcutil.Amount
+
+	// publishAttempts records the number of attempts that have already
+	// been made to sweep this tx.
+	publishAttempts int
+
+	// minPublishHeight indicates the minimum block height at which this
+	// input may be (re)published.
+	minPublishHeight int32
+
+	// feeRateOverride is the fee rate, in sat/weight, the input was bumped
+	// to by the operator. If set, the input is swept at no less than this
+	// fee rate.
+	feeRateOverride btcutil.Amount
+}
+
+// PendingInput contains information about an input that is currently being
+// swept by the UtxoSweeper.
+type PendingInput struct {
+	// OutPoint is the identifying outpoint of the input being swept.
+	OutPoint wire.OutPoint
+
+	// WitnessType is the witness type of the input being swept.
+	WitnessType lnwallet.WitnessType
+
+	// Amount is the amount of the input being swept.
+	Amount btcutil.Amount
+
+	// BroadcastAttempts is the number of attempts we've made to sweep the
+	// input.
+	BroadcastAttempts int
+
+	// NextBroadcastHeight is the next height of the chain at which we'll
+	// attempt to broadcast a transaction sweeping the input.
+	NextBroadcastHeight int32
+
+	// FeePerWeight is the fee rate, in sat/weight, at which the input
+	// would be swept at the current height.
+	FeePerWeight btcutil.Amount
+
+	// Deadline is the block height by which the sweep of the input must
+	// be confirmed, or zero if it has no deadline.
+	Deadline int32
+}
+
+// bumpFeeReq is a request to the sweeper main loop to bump the fee rate of a
+// pending input.
+type bumpFeeReq struct {
+	outpoint     wire.OutPoint
+	feePerWeight btcutil.Amount
+	errChan      chan error
+}
+
+// sweepInputMessage structs are used in the internal channel between the
+// SweepInput call and the sweeper main loop.
+type sweepInputMessage struct {
+	input      Input
+	params     Params
+	resultChan chan Result
+}
+
+// UtxoSweeper is responsible for sweeping outputs back into the wallet. It
+// aggregates every input offered to it, such as commitment, HTLC, and justice
+// outputs, clusters them by the fee rate required to confirm them in time, and
+// sweeps each cluster with as few transactions as possible once a batch
+// window has passed. Failed publishes are retried with an exponential
+// back-off.
+type UtxoSweeper struct {
+	started uint32
+	stopped uint32
+
+	cfg *UtxoSweeperConfig
+
+	newInputs chan *sweepInputMessage
+	spendChan chan *chainntnfs.SpendDetail
+
+	// pendingSweepsReqs is a channel that will be sent requests by
+	// external callers in order to retrieve the set of pending inputs the
+	// UtxoSweeper is attempting to sweep.
+	pendingSweepsReqs chan chan map[wire.OutPoint]*PendingInput
+
+	// bumpFeeReqs is a channel that will be sent requests by external
+	// callers who wish to bump the fee rate of a given input.
+	bumpFeeReqs chan *bumpFeeReq
+
+	// pendingInputs is the total set of inputs the UtxoSweeper has been
+	// requested to sweep.
+	pendingInputs map[wire.OutPoint]*pendingInput
+
+	// sweepTxes is the set of sweep transactions we've published, used to
+	// distinguish our spends of an input from those of the remote party.
+	sweepTxes map[chainhash.Hash]struct{}
+
+	// timer is the channel that signals expiry of the sweep batch timer.
+	timer <-chan time.Time
+
+	// currentOutputScript is the script the sweep transactions pay to.
+	// A fresh script is generated once it's been used.
+	currentOutputScript []byte
+
+	// currentHeight is the best known height of the main chain.
+	currentHeight int32
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New returns a new Sweeper instance.
+func New(cfg *UtxoSweeperConfig) *UtxoSweeper {
+	return &UtxoSweeper{
+		cfg:               cfg,
+		newInputs:         make(chan *sweepInputMessage),
+		spendChan:         make(chan *chainntnfs.SpendDetail),
+		pendingSweepsReqs: make(chan chan map[wire.OutPoint]*PendingInput),
+		bumpFeeReqs:       make(chan *bumpFeeReq),
+		pendingInputs:     make(map[wire.OutPoint]*pendingInput),
+		sweepTxes:         make(map[chainhash.Hash]struct{}),
+		quit:              make(chan struct{}),
+	}
+}
+
+// Start starts the process of constructing and publishing sweep txes.
+func (s *UtxoSweeper) Start() error {
+	if !atomic.CompareAndSwapUint32(&s.started, 0, 1) {
+		return nil
+	}
+
+	log.Tracef("Sweeper starting")
+
+	_, bestHeight, err := s.cfg.ChainIO.GetBestBlock()
+	if err != nil {
+		return err
+	}
+	s.currentHeight = bestHeight
+
+	blockEpochs, err := s.cfg.Notifier.RegisterBlockEpochNtfn()
+	if err != nil {
+		return err
+	}
+
+	s.wg.Add(1)
+	go s.collector(blockEpochs)
+
+	return nil
+}
+
+// Stop stops sweeper from listening to block epochs and constructing sweep
+// txes.
+func (s *UtxoSweeper) Stop() error {
+	if !atomic.CompareAndSwapUint32(&s.stopped, 0, 1) {
+		return nil
+	}
+
+	log.Debugf("Sweeper shutting down")
+
+	close(s.quit)
+	s.wg.Wait()
+
+	log.Debugf("Sweeper shut down")
+
+	return nil
+}
+
+// SweepInput sweeps inputs back into the wallet. The inputs will be batched
+// and swept after the batch time window ends. If the passed params carry a
+// deadline, the fee rate of the input escalates as the deadline approaches,
+// otherwise the input is swept at the fee rate for the default confirmation
+// target.
+//
+// A channel is returned that receives the outcome of the sweep. If the input
+// is spent by another party, ErrRemoteSpend is returned.
+//
+// NOTE: Extreme care needs to be taken that input isn't changed externally.
+// Because it is an interface and we don't know what is exactly behind it, we
+// cannot make a local copy in sweeper.
+func (s *UtxoSweeper) SweepInput(input Input,
+	params Params) (chan Result, error) {
+
+	if input == nil || input.OutPoint() == nil || input.SignDesc() == nil {
+		return nil, errors.New("nil input received")
+	}
+
+	// Ensure the weight of the input can be estimated, as otherwise it
+	// could never be swept.
+	var weightEstimate weightEstimator
+	if err := weightEstimate.addInput(input.WitnessType()); err != nil {
+		return nil, err
+	}
+
+	log.Infof("Sweep request received: out_point=%v, witness_type=%v, "+
+		"deadline=%v", input.OutPoint(), input.WitnessType(),
+		params.Deadline)
+
+	sweeperInput := &sweepInputMessage{
+		input:      input,
+		params:     params,
+		resultChan: make(chan Result, 1),
+	}
+
+	select {
+	case s.newInputs <- sweeperInput:
+	case <-s.quit:
+		return nil, ErrSweeperShuttingDown
+	}
+
+	return sweeperInput.resultChan, nil
+}
+
+// PendingInputs returns the set of inputs that the UtxoSweeper is currently
+// attempting to sweep.
+func (s *UtxoSweeper) PendingInputs() (map[wire.OutPoint]*PendingInput, error) {
+	respChan := make(chan map[wire.OutPoint]*PendingInput, 1)
+	select {
+	case s.pendingSweepsReqs <- respChan:
+	case <-s.quit:
+		return nil, ErrSweeperShuttingDown
+	}
+
+	select {
+	case pendingSweeps := <-respChan:
+		return pendingSweeps, nil
+	case <-s.quit:
+		return nil, ErrSweeperShuttingDown
+	}
+}
+
+// BumpFee bumps the fee rate of the passed pending input to the passed fee
+// rate, in sat/weight. The input is swept immediately, along with any other
+// inputs that are due to be swept at a similar fee rate, rather than waiting
+// for the batch timer to expire or the input's back-off to pass.
+func (s *UtxoSweeper) BumpFee(outpoint wire.OutPoint,
+	feePerWeight btcutil.Amount) error {
+
+	if feePerWeight <= 0 {
+		return errors.New("fee rate must be positive")
+	}
+	if feePerWeight > s.cfg.MaxFeePerWeight {
+		return fmt.Errorf("fee rate of %v sat/weight exceeds the "+
+			"maximum fee rate of %v sat/weight", int64(feePerWeight),
+			int64(s.cfg.MaxFeePerWeight))
+	}
+
+	req := &bumpFeeReq{
+		outpoint:     outpoint,
+		feePerWeight: feePerWeight,
+		errChan:      make(chan error, 1),
+	}
+
+	select {
+	case s.bumpFeeReqs <- req:
+	case <-s.quit:
+		return ErrSweeperShuttingDown
+	}
+
+	select {
+	case err := <-req.errChan:
+		return err
+	case <-s.quit:
+		return ErrSweeperShuttingDown
+	}
+}
+
+// collector is the sweeper main loop. It processes new inputs, spend
+// notifications and counts down to publication of the sweep tx.
+//
+// NOTE: This MUST be run as a goroutine.
+func (s *UtxoSweeper) collector(blockEpochs *chainntnfs.BlockEpochEvent) {
+	defer s.wg.Done()
+	defer blockEpochs.Cancel()
+
+	for {
+		select {
+		// A new input is offered to the sweeper. We check to see if
+		// we are already trying to sweep this input and if not, set up
+		// a listener for spend and schedule a sweep.
+		case input := <-s.newInputs:
+			outpoint := *input.input.OutPoint()
+			pendInput, pending := s.pendingInputs[outpoint]
+			if pending {
+				log.Debugf("Already pending input %v received",
+					outpoint)
+
+				// Add additional result channel to signal
+				// spend of this input.
+				pendInput.listeners = append(
+					pendInput.listeners, input.resultChan,
+				)
+				continue
+			}
+
+			// Start watching for spend of this input, either by
+			// us or the remote party.
+			cancel, err := s.waitForSpend(outpoint)
+			if err != nil {
+				input.resultChan <- Result{Err: err}
+				continue
+			}
+
+			// Create a new pendingInput and initialize the
+			// listeners slice with the passed in result channel.
+			// If this input is offered for sweep again, the result
+			// channel will be appended to this slice.
+			pendInput = &pendingInput{
+				listeners:        []chan Result{input.resultChan},
+				ntfnRegCancel:    cancel,
+				input:            input.input,
+				params:           input.params,
+				startHeight:      s.currentHeight,
+				minPublishHeight: s.currentHeight,
+			}
+			if input.params.Deadline != 0 {
+				pendInput.startFeeRate = s.estimateFeeRate(
+					input.params.Deadline - s.currentHeight,
+				)
+			}
+			s.pendingInputs[outpoint] = pendInput
+
+			s.scheduleSweep()
+
+		// A spend of one of our inputs is detected. Signal sweep
+		// results to the caller(s).
+		case spend := <-s.spendChan:
+			// For testing purposes.
+			if spend == nil {
+				continue
+			}
+
+			// Check whether this spend is one of the sweep
+			// transactions we published.
+			spendHash := *spend.SpenderTxHash
+			_, isOurTx := s.sweepTxes[spendHash]
+
+			// If this isn't our transaction, it means someone else
+			// swept outputs that we were attempting to sweep. This
+			// can happen for contested outputs, such as HTLCs
+			// which the remote party can also claim.
+			if !isOurTx {
+				log.Debugf("Detected spend of pending inputs by "+
+					"remote tx %v", spendHash)
+			}
+
+			// Signal sweep results for inputs in this confirmed
+			// tx.
+			for _, txIn := range spend.SpendingTx.TxIn {
+				outpoint := txIn.PreviousOutPoint
+
+				// Check if this input is known to us. It could
+				// probably be unknown if we canceled the
+				// registration, deleted from pendingInputs but
+				// the ntfn was in-flight already. Or this could
+				// be not one of our inputs.
+				_, ok := s.pendingInputs[outpoint]
+				if !ok {
+					continue
+				}
+
+				// Return either a nil or a remote spend result.
+				var err error
+				if !isOurTx {
+					err = ErrRemoteSpend
+				}
+
+				// Signal result channels.
+				s.signalAndRemove(&outpoint, Result{
+					Tx:  spend.SpendingTx,
+					Err: err,
+				})
+			}
+
+			// Now that an input of ours is spent, we can try to
+			// resweep the remaining inputs.
+			s.scheduleSweep()
+
+		// A request for the set of pending inputs has been received.
+		case respChan := <-s.pendingSweepsReqs:
+			respChan <- s.handlePendingSweepsReq()
+
+		// A request to bump the fee rate of an input has been
+		// received.
+		case req := <-s.bumpFeeReqs:
+			req.errChan <- s.handleBumpFeeReq(req)
+
+		// The timer expires and we are going to (re)sweep.
+		case <-s.timer:
+			log.Debugf("Sweep timer expired")
+
+			// Set timer to nil so we know that a new timer needs
+			// to be started when new inputs arrive.
+			s.timer = nil
+
+			s.sweepClusters()
+
+			// Schedule a new sweep should any inputs remain.
+			s.scheduleSweep()
+
+		// A new block comes in. Things may have changed, so we retry a
+		// sweep.
+		case epoch, ok := <-blockEpochs.Epochs:
+			if !ok {
+				return
+			}
+
+			s.currentHeight = epoch.Height
+
+			log.Debugf("New blocks: height=%v", epoch.Height)
+
+			s.scheduleSweep()
+
+		case <-s.quit:
+			// Signal each pending input's listeners that the
+			// sweeper is shutting down, and cancel its spend
+			// notification.
+			for outpoint := range s.pendingInputs {
+				outpoint := outpoint
+				s.signalAndRemove(&outpoint, Result{
+					Err: ErrSweeperShuttingDown,
+				})
+			}
+			return
+		}
+	}
+}
+
+// estimateFeeRate returns the fee rate, in sat/weight, estimated to confirm
+// a transaction within the passed number of blocks, capped at the maximum fee
+// rate. If no blocks are left, the maximum fee rate is returned.
+func (s *UtxoSweeper) estimateFeeRate(numBlocks int32) btcutil.Amount {
+	if numBlocks <= 0 {
+		return s.cfg.MaxFeePerWeight
+	}
+
+	feeRate := btcutil.Amount(
+		s.cfg.FeeEstimator.EstimateFeePerWeight(uint32(numBlocks)),
+	)
+	if feeRate > s.cfg.MaxFeePerWeight {
+		feeRate = s.cfg.MaxFeePerWeight
+	}
+
+	return feeRate
+}
+
+// feeRateForInput returns the fee rate, in sat/weight, at which the passed
+// input is to be swept at the current height.
+func (s *UtxoSweeper) feeRateForInput(input *pendingInput) btcutil.Amount {
+	feeRate := s.deadlineFeeRate(input)
+
+	// If the operator bumped the fee rate of the input, it's swept at no
+	// less than the requested fee rate.
+	if input.feeRateOverride > feeRate {
+		feeRate = input.feeRateOverride
+	}
+
+	return feeRate
+}
+
+// deadlineFeeRate returns the fee rate, in sat/weight, of the passed input at
+// the current height as determined by its deadline. Inputs without a deadline
+// are swept at the fee rate for the default confirmation target. For inputs
+// with a deadline, the fee rate escalates linearly from the fee rate estimated
+// when the input was offered, to the maximum fee rate at the deadline. Should
+// the fee estimate for the remaining blocks be higher, then it's used instead.
+func (s *UtxoSweeper) deadlineFeeRate(input *pendingInput) btcutil.Amount {
+	deadline := input.params.Deadline
+	if deadline == 0 {
+		return s.estimateFeeRate(DefaultConfTarget)
+	}
+
+	blocksLeft := deadline - s.currentHeight
+	if blocksLeft <= 0 {
+		return s.cfg.MaxFeePerWeight
+	}
+
+	// The width of the escalation window is at least a block, as the
+	// deadline hasn't been reached yet.
+	width := deadline - input.startHeight
+	elapsed := s.currentHeight - input.startHeight
+
+	feeRange := s.cfg.MaxFeePerWeight - input.startFeeRate
+	feeRate := input.startFeeRate +
+		feeRange*btcutil.Amount(elapsed)/btcutil.Amount(width)
+
+	if estimate := s.estimateFeeRate(blocksLeft); estimate > feeRate {
+		feeRate = estimate
+	}
+
+	return feeRate
+}
+
+// inputCluster is a set of inputs whose fee rates fall within the same
+// bucket, swept together at the cluster's fee rate.
+type inputCluster struct {
+	feePerWeight btcutil.Amount
+	inputs       []Input
+}
+
+// clusterBySweepFeeRate clusters the inputs which are eligible to be
+// published at the current height into buckets of similar fee rate. Each
+// cluster is swept at the highest fee rate of its inputs, ensuring none of
+// them is swept at a lower fee rate than it requires.
+func (s *UtxoSweeper) clusterBySweepFeeRate() []inputCluster {
+	bucketSize := btcutil.Amount(s.cfg.FeeRateBucketSize)
+	if bucketSize <= 0 {
+		bucketSize = 1
+	}
+
+	clusters := make(map[btcutil.Amount]*inputCluster)
+	for _, input := range s.pendingInputs {
+		// Skip inputs that have a minimum publish height that is not
+		// yet reached.
+		if input.minPublishHeight > s.currentHeight {
+			continue
+		}
+
+		feeRate := s.feeRateForInput(input)
+		bucket := feeRate / bucketSize
+
+		cluster, ok := clusters[bucket]
+		if !ok {
+			cluster = &inputCluster{}
+			clusters[bucket] = cluster
+		}
+		if feeRate > cluster.feePerWeight {
+			cluster.feePerWeight = feeRate
+		}
+		cluster.inputs = append(cluster.inputs, input.input)
+	}
+
+	result := make([]inputCluster, 0, len(clusters))
+	for _, cluster := range clusters {
+		result = append(result, *cluster)
+	}
+
+	return result
+}
+
+// sweepClusters sweeps each cluster of inputs eligible to be published at the
+// current height.
+func (s *UtxoSweeper) sweepClusters() {
+	for _, cluster := range s.clusterBySweepFeeRate() {
+		s.sweepCluster(cluster)
+	}
+}
+
+// sweepCluster sweeps the inputs of the passed cluster at its fee rate, with
+// as few transactions as possible.
+func (s *UtxoSweeper) sweepCluster(cluster inputCluster) {
+	inputLists, err := generateInputPartitionings(
+		cluster.inputs, cluster.feePerWeight, s.cfg.MaxInputsPerTx,
+	)
+	if err != nil {
+		log.Errorf("Unable to examine pending inputs: %v", err)
+		return
+	}
+
+	for _, inputs := range inputLists {
+		err := s.sweep(inputs, cluster.feePerWeight)
+		if err != nil {
+			log.Errorf("Unable to sweep inputs: %v", err)
+		}
+	}
+}
+
+// handlePendingSweepsReq handles a request to retrieve all pending inputs the
+// UtxoSweeper is attempting to sweep.
+func (s *UtxoSweeper) handlePendingSweepsReq() map[wire.OutPoint]*PendingInput {
+	pendingInputs := make(
+		map[wire.OutPoint]*PendingInput, len(s.pendingInputs),
+	)
+	for outpoint, pendingInput := range s.pendingInputs {
+		pendingInputs[outpoint] = &PendingInput{
+			OutPoint:    outpoint,
+			WitnessType: pendingInput.input.WitnessType(),
+			Amount: btcutil.Amount(
+				pendingInput.input.SignDesc().Output.Value,
+			),
+			BroadcastAttempts:   pendingInput.publishAttempts,
+			NextBroadcastHeight: pendingInput.minPublishHeight,
+			FeePerWeight:        s.feeRateForInput(pendingInput),
+			Deadline:            pendingInput.params.Deadline,
+		}
+	}
+
+	return pendingInputs
+}
+
+// handleBumpFeeReq handles a request to bump the fee rate of a pending input.
+// The input is made eligible to be published immediately, and the cluster it
+// falls into at its new fee rate is swept right away.
+func (s *UtxoSweeper) handleBumpFeeReq(req *bumpFeeReq) error {
+	pendInput, ok := s.pendingInputs[req.outpoint]
+	if !ok {
+		return ErrSweepInputNotFound
+	}
+
+	log.Debugf("Bumping fee rate of input %v to %v sat/weight",
+		req.outpoint, int64(req.feePerWeight))
+
+	pendInput.feeRateOverride = req.feePerWeight
+	pendInput.minPublishHeight = s.currentHeight
+
+	for _, cluster := range s.clusterBySweepFeeRate() {
+		for _, input := range cluster.inputs {
+			if *input.OutPoint() != req.outpoint {
+				continue
+			}
+
+			s.sweepCluster(cluster)
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// scheduleSweep starts the sweep timer to create an opportunity for more
+// inputs to be added, unless it's already running, or none of the pending
+// inputs is currently worth sweeping.
+func (s *UtxoSweeper) scheduleSweep() {
+	// The timer is already ticking, no action needed for the sweep to
+	// happen.
+	if s.timer != nil {
+		log.Debugf("Timer still ticking")
+		return
+	}
+
+	// We'll only start our timer if at least one cluster would produce a
+	// sweep transaction, as otherwise it would fire without anything to
+	// sweep.
+	var startTimer bool
+	for _, cluster := range s.clusterBySweepFeeRate() {
+		inputLists, err := generateInputPartitionings(
+			cluster.inputs, cluster.feePerWeight,
+			s.cfg.MaxInputsPerTx,
+		)
+		if err != nil {
+			log.Errorf("Unable to examine pending inputs: %v", err)
+			continue
+		}
+
+		if len(inputLists) > 0 {
+			startTimer = true
+			break
+		}
+	}
+	if !startTimer {
+		return
+	}
+
+	// Start sweep timer to create opportunity for more inputs to be
+	// added before a tx is constructed.
+	s.timer = s.cfg.NewBatchTimer()
+
+	log.Debugf("Sweep timer started")
+}
+
+// signalAndRemove notifies the listeners of the final result of the input
+// sweep. It cancels any pending spend notification and removes the input from
+// the list of pending inputs. When this function returns, the sweeper has
+// completely forgotten about the input.
+func (s *UtxoSweeper) signalAndRemove(outpoint *wire.OutPoint, result Result) {
+	pendInput := s.pendingInputs[*outpoint]
+	listeners := pendInput.listeners
+
+	if result.Err == nil {
+		log.Debugf("Dispatching sweep success for %v to %v listeners",
+			outpoint, len(listeners))
+	} else {
+		log.Debugf("Dispatching sweep error for %v to %v listeners: %v",
+			outpoint, len(listeners), result.Err)
+	}
+
+	// Signal all listeners. Channel is buffered. Because we only send once
+	// on every channel, it should never block.
+	for _, resultChan := range listeners {
+		resultChan <- result
+	}
+
+	// Cancel spend notification with chain notifier. This is not necessary
+	// in case of a success, except for that a reorg could happen.
+	if pendInput.ntfnRegCancel != nil {
+		log.Debugf("Canceling spend ntfn for %v", outpoint)
+
+		pendInput.ntfnRegCancel()
+	}
+
+	// Inputs are no longer pending after result has been sent.
+	delete(s.pendingInputs, *outpoint)
+}
+
+// sweep takes a set of preselected inputs, creates a sweep tx and publishes
+// the tx. The output address is only marked as used if the publish succeeds.
+func (s *UtxoSweeper) sweep(inputs inputSet,
+	feePerWeight btcutil.Amount) error {
+
+	// Generate an output script if there isn't an unused script
+	// available.
+	if s.currentOutputScript == nil {
+		pkScript, err := s.cfg.GenSweepScript()
+		if err != nil {
+			return err
+		}
+		s.currentOutputScript = pkScript
+	}
+
+	// Create sweep tx.
+	tx, err := createSweepTx(
+		inputs, s.currentOutputScript, feePerWeight, s.cfg.Signer,
+	)
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("Publishing sweep tx %v, num_inputs=%v, height=%v",
+		tx.TxHash(), len(tx.TxIn), s.currentHeight)
+
+	// Publish sweep tx.
+	err = s.cfg.PublishTransaction(tx)
+
+	// In case of an error, keep the output script so that it can be
+	// reused for the next tx and causes no address inflation. The inputs
+	// are retried below.
+	if err != nil {
+		log.Errorf("Publish sweep tx %v got error: %v", tx.TxHash(),
+			err)
+	} else {
+		// The tx was published, so the output script has been used,
+		// and any spend of the inputs by it is ours.
+		s.sweepTxes[tx.TxHash()] = struct{}{}
+		s.currentOutputScript = nil
+	}
+
+	// Reschedule sweep.
+	for _, input := range tx.TxIn {
+		pi, ok := s.pendingInputs[input.PreviousOutPoint]
+		if !ok {
+			// It can be that the input has been removed because
+			// it exceeded the maximum number of attempts in a
+			// previous input set.
+			continue
+		}
+
+		// Record another publish attempt.
+		pi.publishAttempts++
+
+		// We don't care what the result of the publish call was. Even
+		// if it is published successfully, it can still be that it
+		// needs to be retried. Call NextAttemptDeltaFunc to calculate
+		// when to resweep this input.
+		nextAttemptDelta := s.cfg.NextAttemptDeltaFunc(
+			pi.publishAttempts,
+		)
+
+		// Inputs with a deadline are instead reswept at the next
+		// block if their sweep failed to publish, as their fee rate
+		// escalates with every block. A published sweep can't be
+		// replaced by one paying a higher fee rate though, as the
+		// backend doesn't support transaction replacement, so those
+		// are left to confirm, and only retried after the back-off.
+		if pi.params.Deadline != 0 && err != nil {
+			nextAttemptDelta = 1
+		}
+
+		pi.minPublishHeight = s.currentHeight + nextAttemptDelta
+
+		log.Debugf("Rescheduling input %v after %v attempts at "+
+			"height %v (delta %v)", input.PreviousOutPoint,
+			pi.publishAttempts, pi.minPublishHeight,
+			nextAttemptDelta)
+
+		// Inputs with a deadline aren't given up on, as that would
+		// forfeit them once the deadline passes.
+		if pi.params.Deadline == 0 &&
+			pi.publishAttempts >= s.cfg.MaxSweepAttempts {
+
+			// Signal result channels sweep result.
+			s.signalAndRemove(&input.PreviousOutPoint, Result{
+				Err: ErrTooManyAttempts,
+			})
+		}
+	}
+
+	return nil
+}
+
+// waitForSpend registers a spend notification with the chain notifier. It
+// returns a cancel function that can be used to cancel the registration.
+func (s *UtxoSweeper) waitForSpend(outpoint wire.OutPoint) (func(), error) {
+	spendEvent, err := s.cfg.Notifier.RegisterSpendNtfn(
+		&outpoint, uint32(s.currentHeight),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	s.wg.Add(1)
+	ntfnQuit := make(chan struct{})
+	go func() {
+		defer s.wg.Done()
+
+		select {
+		case spend, ok := <-spendEvent.Spend:
+			if !ok {
+				log.Debugf("Spend ntfn for %v canceled",
+					outpoint)
+				return
+			}
+
+			log.Debugf("Delivering spend ntfn for %v", outpoint)
+
+			select {
+			case s.spendChan <- spend:
+				log.Debugf("Delivered spend ntfn for %v",
+					outpoint)
+
+			case <-s.quit:
+			}
+
+		case <-ntfnQuit:
+		case <-s.quit:
+		}
+	}()
+
+	return func() {
+		spendEvent.Cancel()
+		close(ntfnQuit)
+	}, nil
+}
@@ -0,0 +1,523 @@
+package sweep
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+var errPublish = errors.New("unable to publish")
+
+type mockSigner struct{}
+
+func (m *mockSigner) SignOutputRaw(tx *wire.MsgTx,
+	signDesc *lnwallet.SignDescriptor) ([]byte, error) {
+
+	return []byte{}, nil
+}
+
+func (m *mockSigner) ComputeInputScript(tx *wire.MsgTx,
+	signDesc *lnwallet.SignDescriptor) (*lnwallet.InputScript, error) {
+
+	return &lnwallet.InputScript{}, nil
+}
+
+type mockChainIO struct{}
+
+func (m *mockChainIO) GetBestBlock() (*chainhash.Hash, int32, error) {
+	return &chainhash.Hash{}, 100, nil
+}
+
+func (m *mockChainIO) GetUtxo(op *wire.OutPoint,
+	heightHint uint32) (*wire.TxOut, error) {
+
+	return nil, nil
+}
+
+func (m *mockChainIO) GetBlockHash(blockHeight int64) (*chainhash.Hash, error) {
+	return nil, nil
+}
+
+func (m *mockChainIO) GetBlock(blockHash *chainhash.Hash) (*wire.MsgBlock,
+	error) {
+
+	return nil, nil
+}
+
+type mockNotifier struct {
+	sync.Mutex
+
+	spendChans map[wire.OutPoint]chan *chainntnfs.SpendDetail
+	epochChan  chan *chainntnfs.BlockEpoch
+}
+
+func newMockNotifier() *mockNotifier {
+	return &mockNotifier{
+		spendChans: make(map[wire.OutPoint]chan *chainntnfs.SpendDetail),
+		epochChan:  make(chan *chainntnfs.BlockEpoch),
+	}
+}
+
+func (m *mockNotifier) RegisterConfirmationsNtfn(txid *chainhash.Hash,
+	numConfs, heightHint uint32) (*chainntnfs.ConfirmationEvent, error) {
+
+	return nil, nil
+}
+
+func (m *mockNotifier) RegisterBlockEpochNtfn() (*chainntnfs.BlockEpochEvent,
+	error) {
+
+	return &chainntnfs.BlockEpochEvent{
+		Epochs: m.epochChan,
+		Cancel: func() {},
+	}, nil
+}
+
+func (m *mockNotifier) RegisterSpendNtfn(outpoint *wire.OutPoint,
+	heightHint uint32) (*chainntnfs.SpendEvent, error) {
+
+	m.Lock()
+	defer m.Unlock()
+
+	spendChan := make(chan *chainntnfs.SpendDetail, 1)
+	m.spendChans[*outpoint] = spendChan
+
+	return &chainntnfs.SpendEvent{
+		Spend:  spendChan,
+		Cancel: func() {},
+	}, nil
+}
+
+func (m *mockNotifier) Start() error {
+	return nil
+}
+
+func (m *mockNotifier) Stop() error {
+	return nil
+}
+
+// spend delivers a spend notification for each of the inputs of the passed
+// transaction which are being watched.
+func (m *mockNotifier) spend(tx *wire.MsgTx) {
+	m.Lock()
+	defer m.Unlock()
+
+	txHash := tx.TxHash()
+	for i, txIn := range tx.TxIn {
+		spendChan, ok := m.spendChans[txIn.PreviousOutPoint]
+		if !ok {
+			continue
+		}
+
+		outpoint := txIn.PreviousOutPoint
+		spendChan <- &chainntnfs.SpendDetail{
+			SpentOutPoint:     &outpoint,
+			SpenderTxHash:     &txHash,
+			SpendingTx:        tx,
+			SpenderInputIndex: uint32(i),
+		}
+		delete(m.spendChans, txIn.PreviousOutPoint)
+	}
+}
+
+// sweeperTestContext bundles a sweeper with the mocked dependencies used to
+// drive it.
+type sweeperTestContext struct {
+	t *testing.T
+
+	sweeper   *UtxoSweeper
+	notifier  *mockNotifier
+	timerChan chan time.Time
+
+	publishChan  chan *wire.MsgTx
+	publishError error
+	publishMtx   sync.Mutex
+}
+
+func newSweeperTestContext(t *testing.T) *sweeperTestContext {
+	ctx := &sweeperTestContext{
+		t:           t,
+		notifier:    newMockNotifier(),
+		timerChan:   make(chan time.Time),
+		publishChan: make(chan *wire.MsgTx, 10),
+	}
+
+	ctx.sweeper = New(&UtxoSweeperConfig{
+		GenSweepScript: func() ([]byte, error) {
+			return []byte{0, 20}, nil
+		},
+		FeeEstimator: lnwallet.StaticFeeEstimator{FeeRate: 40},
+		PublishTransaction: func(tx *wire.MsgTx) error {
+			ctx.publishMtx.Lock()
+			err := ctx.publishError
+			ctx.publishMtx.Unlock()
+
+			ctx.publishChan <- tx
+			return err
+		},
+		NewBatchTimer: func() <-chan time.Time {
+			return ctx.timerChan
+		},
+		Notifier:             ctx.notifier,
+		ChainIO:              &mockChainIO{},
+		Signer:               &mockSigner{},
+		MaxInputsPerTx:       DefaultMaxInputsPerTx,
+		MaxSweepAttempts:     2,
+		NextAttemptDeltaFunc: DefaultNextAttemptDeltaFunc,
+		FeeRateBucketSize:    DefaultFeeRateBucketSize,
+		MaxFeePerWeight:      DefaultMaxFeePerWeight,
+	})
+
+	if err := ctx.sweeper.Start(); err != nil {
+		t.Fatalf("unable to start sweeper: %v", err)
+	}
+
+	return ctx
+}
+
+func (ctx *sweeperTestContext) setPublishError(err error) {
+	ctx.publishMtx.Lock()
+	ctx.publishError = err
+	ctx.publishMtx.Unlock()
+}
+
+func (ctx *sweeperTestContext) sweepInput(input Input) chan Result {
+	resultChan, err := ctx.sweeper.SweepInput(input, Params{})
+	if err != nil {
+		ctx.t.Fatalf("unable to sweep input: %v", err)
+	}
+
+	return resultChan
+}
+
+// expireTimer fires the batch timer, and waits for the resulting sweep
+// transaction to be published.
+func (ctx *sweeperTestContext) expireTimer() *wire.MsgTx {
+	select {
+	case ctx.timerChan <- time.Time{}:
+	case <-time.After(5 * time.Second):
+		ctx.t.Fatalf("batch timer not started")
+	}
+
+	select {
+	case tx := <-ctx.publishChan:
+		return tx
+	case <-time.After(5 * time.Second):
+		ctx.t.Fatalf("sweep tx not published")
+	}
+
+	return nil
+}
+
+func (ctx *sweeperTestContext) notifyEpoch(height int32) {
+	select {
+	case ctx.notifier.epochChan <- &chainntnfs.BlockEpoch{
+		Height: height,
+	}:
+	case <-time.After(5 * time.Second):
+		ctx.t.Fatalf("block epoch not received")
+	}
+}
+
+func (ctx *sweeperTestContext) expectResult(resultChan chan Result,
+	expectedErr error) Result {
+
+	select {
+	case result := <-resultChan:
+		if result.Err != expectedErr {
+			ctx.t.Fatalf("expected result error %v, got %v",
+				expectedErr, result.Err)
+		}
+		return result
+	case <-time.After(5 * time.Second):
+		ctx.t.Fatalf("no sweep result received")
+	}
+
+	return Result{}
+}
+
+// TestSweeperBatchesInputs checks that inputs offered to the sweeper within
+// the same batch window are swept by a single transaction, and that each
+// caller is notified once that transaction confirms.
+func TestSweeperBatchesInputs(t *testing.T) {
+	t.Parallel()
+
+	ctx := newSweeperTestContext(t)
+	defer ctx.sweeper.Stop()
+
+	resultChan1 := ctx.sweepInput(newTestInput(0, 100000))
+	resultChan2 := ctx.sweepInput(newTestInput(1, 200000))
+
+	// Offering the same input twice shouldn't lead to it being swept
+	// twice, but both callers should be notified.
+	resultChan3 := ctx.sweepInput(newTestInput(0, 100000))
+
+	sweepTx := ctx.expireTimer()
+	if len(sweepTx.TxIn) != 2 {
+		t.Fatalf("expected sweep tx with 2 inputs, got %v",
+			len(sweepTx.TxIn))
+	}
+
+	ctx.notifier.spend(sweepTx)
+
+	for _, resultChan := range []chan Result{
+		resultChan1, resultChan2, resultChan3,
+	} {
+		result := ctx.expectResult(resultChan, nil)
+		if result.Tx.TxHash() != sweepTx.TxHash() {
+			t.Fatalf("expected sweep tx %v, got %v",
+				sweepTx.TxHash(), result.Tx.TxHash())
+		}
+	}
+}
+
+// TestSweeperRemoteSpend checks that an input spent by a transaction other
+// than our own sweep is reported to the caller as a remote spend.
+func TestSweeperRemoteSpend(t *testing.T) {
+	t.Parallel()
+
+	ctx := newSweeperTestContext(t)
+	defer ctx.sweeper.Stop()
+
+	input := newTestInput(0, 100000)
+	resultChan := ctx.sweepInput(input)
+
+	remoteTx := wire.NewMsgTx(2)
+	remoteTx.AddTxIn(&wire.TxIn{PreviousOutPoint: *input.OutPoint()})
+	ctx.notifier.spend(remoteTx)
+
+	ctx.expectResult(resultChan, ErrRemoteSpend)
+}
+
+// TestSweeperRetries checks that a failed sweep is retried once the back-off
+// has passed, and that the caller is notified after the maximum number of
+// attempts has been reached.
+func TestSweeperRetries(t *testing.T) {
+	t.Parallel()
+
+	ctx := newSweeperTestContext(t)
+	defer ctx.sweeper.Stop()
+
+	ctx.setPublishError(errPublish)
+
+	resultChan := ctx.sweepInput(newTestInput(0, 100000))
+
+	// The first attempt fails, after which the input is rescheduled for
+	// the next block.
+	ctx.expireTimer()
+	ctx.notifyEpoch(101)
+
+	// The second attempt also fails, which exhausts the attempts.
+	ctx.expireTimer()
+	ctx.expectResult(resultChan, ErrTooManyAttempts)
+}
+
+// TestSweeperFeeRateEscalation checks that the fee rate of an input with a
+// deadline escalates from the estimated fee rate to the maximum fee rate as
+// the deadline approaches, while inputs without a deadline are swept at the
+// fee rate for the default confirmation target.
+func TestSweeperFeeRateEscalation(t *testing.T) {
+	t.Parallel()
+
+	const (
+		estimatedFeeRate = 10
+		startHeight      = 100
+		deadline         = 110
+	)
+
+	sweeper := New(&UtxoSweeperConfig{
+		FeeEstimator: lnwallet.StaticFeeEstimator{
+			FeeRate: estimatedFeeRate * 4,
+		},
+		MaxFeePerWeight: DefaultMaxFeePerWeight,
+	})
+	sweeper.currentHeight = startHeight
+
+	input := &pendingInput{
+		params:       Params{Deadline: deadline},
+		startHeight:  startHeight,
+		startFeeRate: estimatedFeeRate,
+	}
+
+	testCases := []struct {
+		height  int32
+		feeRate btcutil.Amount
+	}{
+		{
+			height:  startHeight,
+			feeRate: estimatedFeeRate,
+		},
+		{
+			height: startHeight + 5,
+			feeRate: estimatedFeeRate +
+				(DefaultMaxFeePerWeight-estimatedFeeRate)/2,
+		},
+		{
+			height:  deadline,
+			feeRate: DefaultMaxFeePerWeight,
+		},
+		{
+			height:  deadline + 1,
+			feeRate: DefaultMaxFeePerWeight,
+		},
+	}
+
+	for _, test := range testCases {
+		sweeper.currentHeight = test.height
+
+		feeRate := sweeper.feeRateForInput(input)
+		if feeRate != test.feeRate {
+			t.Fatalf("height %v: expected fee rate %v, got %v",
+				test.height, test.feeRate, feeRate)
+		}
+	}
+
+	// An input without a deadline is swept at the estimated fee rate,
+	// regardless of the height.
+	input.params = Params{}
+	if feeRate := sweeper.feeRateForInput(input); feeRate != estimatedFeeRate {
+		t.Fatalf("expected fee rate %v, got %v", estimatedFeeRate,
+			feeRate)
+	}
+}
+
+// TestSweeperBumpFee checks that the pending inputs of the sweeper are
+// reported, and that bumping the fee rate of an input sweeps it immediately
+// at the requested fee rate.
+func TestSweeperBumpFee(t *testing.T) {
+	t.Parallel()
+
+	ctx := newSweeperTestContext(t)
+	defer ctx.sweeper.Stop()
+
+	input := newTestInput(0, 100000)
+	ctx.sweepInput(input)
+
+	pendingInputs, err := ctx.sweeper.PendingInputs()
+	if err != nil {
+		t.Fatalf("unable to fetch pending inputs: %v", err)
+	}
+	pendingInput, ok := pendingInputs[*input.OutPoint()]
+	if !ok {
+		t.Fatalf("input %v not pending", input.OutPoint())
+	}
+	if pendingInput.BroadcastAttempts != 0 {
+		t.Fatalf("expected no broadcast attempts, got %v",
+			pendingInput.BroadcastAttempts)
+	}
+	if pendingInput.FeePerWeight != 10 {
+		t.Fatalf("expected fee rate of 10, got %v",
+			pendingInput.FeePerWeight)
+	}
+
+	// Bumping the fee rate beyond the maximum fee rate, or of an input
+	// which isn't pending, should fail.
+	err = ctx.sweeper.BumpFee(*input.OutPoint(), DefaultMaxFeePerWeight+1)
+	if err == nil {
+		t.Fatalf("expected fee rate above maximum to be rejected")
+	}
+	err = ctx.sweeper.BumpFee(wire.OutPoint{Index: 1}, 50)
+	if err != ErrSweepInputNotFound {
+		t.Fatalf("expected ErrSweepInputNotFound, got %v", err)
+	}
+
+	// Bumping the fee rate of the input should sweep it right away, at
+	// the requested fee rate.
+	if err := ctx.sweeper.BumpFee(*input.OutPoint(), 50); err != nil {
+		t.Fatalf("unable to bump fee: %v", err)
+	}
+
+	var sweepTx *wire.MsgTx
+	select {
+	case sweepTx = <-ctx.publishChan:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("sweep tx not published")
+	}
+
+	fee, err := sweepTxFee(inputSet{input}, 50)
+	if err != nil {
+		t.Fatalf("unable to compute fee: %v", err)
+	}
+	expectedValue := input.SignDesc().Output.Value - int64(fee)
+	if sweepTx.TxOut[0].Value != expectedValue {
+		t.Fatalf("expected output value %v, got %v", expectedValue,
+			sweepTx.TxOut[0].Value)
+	}
+
+	pendingInputs, err = ctx.sweeper.PendingInputs()
+	if err != nil {
+		t.Fatalf("unable to fetch pending inputs: %v", err)
+	}
+	pendingInput = pendingInputs[*input.OutPoint()]
+	if pendingInput.BroadcastAttempts != 1 {
+		t.Fatalf("expected a single broadcast attempt, got %v",
+			pendingInput.BroadcastAttempts)
+	}
+	if pendingInput.FeePerWeight != 50 {
+		t.Fatalf("expected fee rate of 50, got %v",
+			pendingInput.FeePerWeight)
+	}
+}
+
+// TestSweeperDeadlineRetries checks that a failed sweep of an input with a
+// deadline is retried at the next block, at its escalated fee rate, while a
+// published sweep isn't replaced by one paying a higher fee rate.
+func TestSweeperDeadlineRetries(t *testing.T) {
+	t.Parallel()
+
+	ctx := newSweeperTestContext(t)
+	defer ctx.sweeper.Stop()
+
+	ctx.setPublishError(errPublish)
+
+	input := newTestInput(0, 100000)
+	resultChan, err := ctx.sweeper.SweepInput(input, Params{Deadline: 110})
+	if err != nil {
+		t.Fatalf("unable to sweep input: %v", err)
+	}
+
+	assertFeeRate := func(sweepTx *wire.MsgTx, feeRate btcutil.Amount) {
+		fee, err := sweepTxFee(inputSet{input}, feeRate)
+		if err != nil {
+			t.Fatalf("unable to compute fee: %v", err)
+		}
+		expectedValue := input.SignDesc().Output.Value - int64(fee)
+		if sweepTx.TxOut[0].Value != expectedValue {
+			t.Fatalf("expected output value %v, got %v",
+				expectedValue, sweepTx.TxOut[0].Value)
+		}
+	}
+
+	// The first attempt is made at the estimated fee rate, and fails.
+	assertFeeRate(ctx.expireTimer(), 10)
+
+	// Despite the attempts being exhausted, the input is retried at the
+	// next block, at the fee rate it has escalated to by then.
+	ctx.notifyEpoch(105)
+	assertFeeRate(ctx.expireTimer(), 55)
+
+	// The next attempt succeeds.
+	ctx.setPublishError(nil)
+	ctx.notifyEpoch(106)
+	sweepTx := ctx.expireTimer()
+	assertFeeRate(sweepTx, 64)
+
+	// As the published sweep can't be replaced, the input shouldn't be
+	// reswept at the next block, even though its fee rate has escalated.
+	ctx.notifyEpoch(107)
+	select {
+	case ctx.timerChan <- time.Time{}:
+		t.Fatalf("published sweep was replaced")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	ctx.notifier.spend(sweepTx)
+	ctx.expectResult(resultChan, nil)
+}
@@ -0,0 +1,179 @@
+package sweep
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/roasbeef/btcd/txscript"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+// inputSet is a set of inputs which are swept together within a single
+// transaction.
+type inputSet []Input
+
+// inputYield returns the value of the passed input, less the fee required to
+// spend it at the passed fee rate. An error is returned if the weight of the
+// input can't be estimated.
+func inputYield(input Input, feePerWeight btcutil.Amount) (btcutil.Amount,
+	error) {
+
+	var weightEstimate weightEstimator
+	if err := weightEstimate.addInput(input.WitnessType()); err != nil {
+		return 0, err
+	}
+
+	// Only the input and its witness are accounted for, as the base
+	// transaction and its output are shared by every input of the batch.
+	inputWeight := lnwallet.InputSize*4 + weightEstimate.inputWitnessSize
+	fee := feePerWeight * btcutil.Amount(inputWeight)
+
+	return btcutil.Amount(input.SignDesc().Output.Value) - fee, nil
+}
+
+// generateInputPartitionings partitions the passed inputs into the sets to be
+// swept by separate transactions at the passed fee rate. Inputs are added to
+// the sets in order of decreasing yield, such that each transaction is as
+// valuable as possible, up to the maximum number of inputs per transaction.
+// Inputs that would cost more to sweep than their value are left out, as are
+// sets which wouldn't produce an output above the dust limit.
+func generateInputPartitionings(inputs []Input,
+	feePerWeight btcutil.Amount, maxInputsPerTx int) ([]inputSet, error) {
+
+	type yieldedInput struct {
+		input Input
+		yield btcutil.Amount
+	}
+
+	yieldedInputs := make([]yieldedInput, 0, len(inputs))
+	for _, input := range inputs {
+		yield, err := inputYield(input, feePerWeight)
+		if err != nil {
+			return nil, err
+		}
+
+		// Inputs that aren't worth sweeping at this fee rate remain
+		// pending, as they may become worth sweeping once fees drop.
+		if yield <= 0 {
+			log.Debugf("Skipping input %v with negative yield %v",
+				input.OutPoint(), yield)
+			continue
+		}
+
+		yieldedInputs = append(yieldedInputs, yieldedInput{
+			input: input,
+			yield: yield,
+		})
+	}
+
+	sort.Slice(yieldedInputs, func(i, j int) bool {
+		return yieldedInputs[i].yield > yieldedInputs[j].yield
+	})
+
+	var sets []inputSet
+	for len(yieldedInputs) > 0 {
+		numInputs := len(yieldedInputs)
+		if numInputs > maxInputsPerTx {
+			numInputs = maxInputsPerTx
+		}
+
+		set := make(inputSet, 0, numInputs)
+		var inputTotal btcutil.Amount
+		for _, yieldedInput := range yieldedInputs[:numInputs] {
+			set = append(set, yieldedInput.input)
+			inputTotal += btcutil.Amount(
+				yieldedInput.input.SignDesc().Output.Value,
+			)
+		}
+		yieldedInputs = yieldedInputs[numInputs:]
+
+		// As the inputs are sorted by yield, no later set would
+		// produce an output above the dust limit either.
+		fee, err := sweepTxFee(set, feePerWeight)
+		if err != nil {
+			return nil, err
+		}
+		if inputTotal-fee < lnwallet.DefaultDustLimit() {
+			log.Debugf("Set of %v inputs yields dust output of %v",
+				len(set), inputTotal-fee)
+			break
+		}
+
+		sets = append(sets, set)
+	}
+
+	return sets, nil
+}
+
+// sweepTxFee returns the fee required for a transaction sweeping the passed
+// inputs into a single output at the passed fee rate.
+func sweepTxFee(inputs inputSet, feePerWeight btcutil.Amount) (btcutil.Amount,
+	error) {
+
+	var weightEstimate weightEstimator
+	for _, input := range inputs {
+		if err := weightEstimate.addInput(input.WitnessType()); err != nil {
+			return 0, err
+		}
+	}
+	weightEstimate.addP2WKHOutput()
+
+	return feePerWeight * btcutil.Amount(weightEstimate.weight()), nil
+}
+
+// createSweepTx builds a signed transaction sweeping the passed inputs into a
+// single output paying to the passed script, at the passed fee rate.
+func createSweepTx(inputs inputSet, outputPkScript []byte,
+	feePerWeight btcutil.Amount, signer lnwallet.Signer) (*wire.MsgTx,
+	error) {
+
+	fee, err := sweepTxFee(inputs, feePerWeight)
+	if err != nil {
+		return nil, err
+	}
+
+	var totalSum btcutil.Amount
+	for _, input := range inputs {
+		totalSum += btcutil.Amount(input.SignDesc().Output.Value)
+	}
+
+	sweepAmt := totalSum - fee
+	if sweepAmt < lnwallet.DefaultDustLimit() {
+		return nil, fmt.Errorf("sweep output of %v is below the dust "+
+			"limit", sweepAmt)
+	}
+
+	// Version 2 is required for the relative timelocks of any CSV locked
+	// inputs to be enforced.
+	sweepTx := wire.NewMsgTx(2)
+	sweepTx.AddTxOut(&wire.TxOut{
+		PkScript: outputPkScript,
+		Value:    int64(sweepAmt),
+	})
+	for _, input := range inputs {
+		sweepTx.AddTxIn(&wire.TxIn{
+			PreviousOutPoint: *input.OutPoint(),
+			Sequence:         input.BlocksToMaturity(),
+		})
+	}
+
+	// With all the inputs in place, each input's witness can now be
+	// generated.
+	hashCache := txscript.NewTxSigHashes(sweepTx)
+	for i, input := range inputs {
+		witness, err := input.BuildWitness(signer, sweepTx, hashCache, i)
+		if err != nil {
+			return nil, err
+		}
+
+		sweepTx.TxIn[i].Witness = witness
+	}
+
+	log.Infof("Created sweep tx %v with %v inputs and fee %v at fee rate "+
+		"%v sat/weight", sweepTx.TxHash(), len(inputs), fee,
+		int64(feePerWeight))
+
+	return sweepTx, nil
+}
@@ -0,0 +1,156 @@
+package sweep
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+// newTestInput returns a revoked commitment output of the passed value, which
+// is uniquely identified by the passed index.
+func newTestInput(index uint32, value int64) Input {
+	return NewBaseInput(
+		&wire.OutPoint{Index: index}, lnwallet.CommitmentRevoke,
+		&lnwallet.SignDescriptor{
+			Output: &wire.TxOut{Value: value},
+		},
+	)
+}
+
+// TestWeightEstimator checks that the weight of a sweep transaction is
+// estimated from the size of the inputs and outputs, and their witnesses.
+func TestWeightEstimator(t *testing.T) {
+	t.Parallel()
+
+	var weightEstimate weightEstimator
+	if err := weightEstimate.addInput(lnwallet.CommitmentRevoke); err != nil {
+		t.Fatalf("unable to add input: %v", err)
+	}
+	if err := weightEstimate.addInput(lnwallet.CommitmentNoDelay); err != nil {
+		t.Fatalf("unable to add input: %v", err)
+	}
+	weightEstimate.addP2WKHOutput()
+
+	// Version, input count, two inputs, output count, the output and lock
+	// time, followed by the witness header and both witnesses.
+	baseSize := 4 + 1 + 2*lnwallet.InputSize + 1 +
+		lnwallet.CommitmentKeyHashOutput + 4
+	expectedWeight := int64(baseSize*4 + lnwallet.WitnessHeaderSize +
+		lnwallet.ToLocalPenaltyWitnessSize + lnwallet.P2WKHWitnessSize)
+
+	if weightEstimate.weight() != expectedWeight {
+		t.Fatalf("expected weight %v, got %v", expectedWeight,
+			weightEstimate.weight())
+	}
+
+	// An input of an unknown witness type can't be estimated.
+	err := weightEstimate.addInput(lnwallet.WitnessType(99))
+	if err == nil {
+		t.Fatalf("expected unknown witness type to be rejected")
+	}
+}
+
+// TestGenerateInputPartitionings checks that inputs are partitioned in order
+// of decreasing yield, that no set exceeds the maximum number of inputs, and
+// that inputs which cost more to sweep than their value are left out.
+func TestGenerateInputPartitionings(t *testing.T) {
+	t.Parallel()
+
+	const feePerWeight = 10
+
+	inputs := []Input{
+		newTestInput(0, 100000),
+		newTestInput(1, 50000),
+		newTestInput(2, 3000),
+		newTestInput(3, 200000),
+		newTestInput(4, 150000),
+	}
+
+	sets, err := generateInputPartitionings(inputs, feePerWeight, 2)
+	if err != nil {
+		t.Fatalf("unable to partition inputs: %v", err)
+	}
+
+	// The input worth 3000 doesn't cover the fee to spend it, so it
+	// should be left out, and the remaining inputs sorted by yield.
+	expectedSets := [][]uint32{{3, 4}, {0, 1}}
+	if len(sets) != len(expectedSets) {
+		t.Fatalf("expected %v sets, got %v", len(expectedSets),
+			len(sets))
+	}
+	for i, set := range sets {
+		if len(set) != len(expectedSets[i]) {
+			t.Fatalf("set %v: expected %v inputs, got %v", i,
+				len(expectedSets[i]), len(set))
+		}
+		for j, input := range set {
+			if input.OutPoint().Index != expectedSets[i][j] {
+				t.Fatalf("set %v: expected input %v at %v, "+
+					"got %v", i, expectedSets[i][j], j,
+					input.OutPoint().Index)
+			}
+		}
+	}
+
+	// At a fee rate high enough for no input to be worth sweeping, no
+	// sets should be returned at all.
+	sets, err = generateInputPartitionings(inputs, 1000, 2)
+	if err != nil {
+		t.Fatalf("unable to partition inputs: %v", err)
+	}
+	if len(sets) != 0 {
+		t.Fatalf("expected no sets, got %v", len(sets))
+	}
+}
+
+// TestCreateSweepTx checks that the sweep transaction spends each input, and
+// pays their total value less the estimated fee to the output script.
+func TestCreateSweepTx(t *testing.T) {
+	t.Parallel()
+
+	const feePerWeight = 10
+
+	inputs := inputSet{
+		newTestInput(0, 100000),
+		newTestInput(1, 50000),
+	}
+	pkScript := []byte{0, 20}
+
+	sweepTx, err := createSweepTx(
+		inputs, pkScript, feePerWeight, &mockSigner{},
+	)
+	if err != nil {
+		t.Fatalf("unable to create sweep tx: %v", err)
+	}
+
+	if len(sweepTx.TxIn) != len(inputs) {
+		t.Fatalf("expected %v inputs, got %v", len(inputs),
+			len(sweepTx.TxIn))
+	}
+	for i, txIn := range sweepTx.TxIn {
+		if txIn.PreviousOutPoint != *inputs[i].OutPoint() {
+			t.Fatalf("input %v spends %v, expected %v", i,
+				txIn.PreviousOutPoint, inputs[i].OutPoint())
+		}
+		if len(txIn.Witness) == 0 {
+			t.Fatalf("input %v has no witness", i)
+		}
+	}
+
+	fee, err := sweepTxFee(inputs, feePerWeight)
+	if err != nil {
+		t.Fatalf("unable to compute fee: %v", err)
+	}
+	expectedValue := int64(btcutil.Amount(150000) - fee)
+
+	if len(sweepTx.TxOut) != 1 {
+		t.Fatalf("expected a single output, got %v",
+			len(sweepTx.TxOut))
+	}
+	if sweepTx.TxOut[0].Value != expectedValue {
+		t.Fatalf("expected output value %v, got %v", expectedValue,
+			sweepTx.TxOut[0].Value)
+	}
+}
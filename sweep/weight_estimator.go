@@ -0,0 +1,76 @@
+package sweep
+
+import (
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/roasbeef/btcd/blockchain"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// weightEstimator is able to calculate the weight of a sweep transaction
+// before it has been signed, using the worst case size of the witness of each
+// of its inputs.
+type weightEstimator struct {
+	numInputs          int
+	numOutputs         int
+	inputSize          int
+	outputSize         int
+	inputWitnessSize   int
+	hasWitnessFlagByte bool
+}
+
+// addInput updates the weight estimate to account for an additional input of
+// the passed witness type. An error is returned if the witness type isn't
+// known to the estimator.
+func (w *weightEstimator) addInput(witnessType lnwallet.WitnessType) error {
+	var witnessSize int
+	switch witnessType {
+	// The second-level HTLC script is the same size as the delayed
+	// commitment output script, and is spent with the same witness.
+	case lnwallet.CommitmentTimeLock,
+		lnwallet.HtlcOfferedTimeoutSecondLevel,
+		lnwallet.HtlcAcceptedSuccessSecondLevel:
+
+		witnessSize = lnwallet.ToLocalTimeoutWitnessSize
+
+	case lnwallet.CommitmentNoDelay:
+		witnessSize = lnwallet.P2WKHWitnessSize
+
+	case lnwallet.CommitmentRevoke:
+		witnessSize = lnwallet.ToLocalPenaltyWitnessSize
+
+	default:
+		return fmt.Errorf("unknown witness type: %v", witnessType)
+	}
+
+	w.numInputs++
+	w.inputSize += lnwallet.InputSize
+	w.inputWitnessSize += witnessSize
+	w.hasWitnessFlagByte = true
+
+	return nil
+}
+
+// addP2WKHOutput updates the weight estimate to account for an additional
+// pay-to-witness-key-hash output.
+func (w *weightEstimator) addP2WKHOutput() {
+	w.numOutputs++
+	w.outputSize += lnwallet.CommitmentKeyHashOutput
+}
+
+// weight returns the estimated weight of the transaction, which is four times
+// its size without witness data, plus the size of the witness data.
+func (w *weightEstimator) weight() int64 {
+	// Version, input and output counts, and lock time.
+	baseSize := 4 + wire.VarIntSerializeSize(uint64(w.numInputs)) +
+		w.inputSize + wire.VarIntSerializeSize(uint64(w.numOutputs)) +
+		w.outputSize + 4
+
+	weight := baseSize * blockchain.WitnessScaleFactor
+	if w.hasWitnessFlagByte {
+		weight += lnwallet.WitnessHeaderSize + w.inputWitnessSize
+	}
+
+	return int64(weight)
+}
@@ -42,8 +42,9 @@ var (
 		0x6a, 0x49, 0x18, 0x83, 0x31, 0x98, 0x47, 0x53,
 	}
 
-	// Just use some arbitrary bytes as delivery script.
-	dummyDeliveryScript = alicesPrivKey[:]
+	// Just use some arbitrary bytes as the hash of a standard p2wsh
+	// delivery script.
+	dummyDeliveryScript = append([]byte{0x00, 0x20}, alicesPrivKey...)
 )
 
 // createTestPeer creates a channel between two nodes, and returns a peer for
@@ -73,9 +74,9 @@ func createTestPeer(notifier chainntnfs.ChainNotifier,
 	aliceCfg := channeldb.ChannelConfig{
 		ChannelConstraints: channeldb.ChannelConstraints{
 			DustLimit:        aliceDustLimit,
-			MaxPendingAmount: lnwire.MilliSatoshi(rand.Int63()),
+			MaxPendingAmount: lnwire.MilliAtom(rand.Int63()),
 			ChanReserve:      btcutil.Amount(rand.Int63()),
-			MinHTLC:          lnwire.MilliSatoshi(rand.Int63()),
+			MinHTLC:          lnwire.MilliAtom(rand.Int63()),
 			MaxAcceptedHtlcs: uint16(rand.Int31()),
 		},
 		CsvDelay:            uint16(csvTimeoutAlice),
@@ -87,9 +88,9 @@ func createTestPeer(notifier chainntnfs.ChainNotifier,
 	bobCfg := channeldb.ChannelConfig{
 		ChannelConstraints: channeldb.ChannelConstraints{
 			DustLimit:        bobDustLimit,
-			MaxPendingAmount: lnwire.MilliSatoshi(rand.Int63()),
+			MaxPendingAmount: lnwire.MilliAtom(rand.Int63()),
 			ChanReserve:      btcutil.Amount(rand.Int63()),
-			MinHTLC:          lnwire.MilliSatoshi(rand.Int63()),
+			MinHTLC:          lnwire.MilliAtom(rand.Int63()),
 			MaxAcceptedHtlcs: uint16(rand.Int31()),
 		},
 		CsvDelay:            uint16(csvTimeoutBob),
@@ -156,6 +157,10 @@ func createTestPeer(notifier chainntnfs.ChainNotifier,
 		RevocationProducer:      alicePreimageProducer,
 		RevocationStore:         shachain.NewRevocationStore(),
 		Db:                      dbAlice,
+
+		// Bob commits to the delivery script he uses within each of
+		// the cooperative closes in the peer tests.
+		RemoteUpfrontShutdownScript: dummyDeliveryScript,
 	}
 
 	addr := &net.TCPAddr{
@@ -236,6 +241,10 @@ func createTestPeer(notifier chainntnfs.ChainNotifier,
 		chanDB:        dbAlice,
 		cc:            cc,
 		breachArbiter: breachArbiter,
+		closeFeeBounds: closeFeeBounds{
+			maxFeeMultiple: defaultCoopCloseMaxFeeMultiple,
+			acceptWindow:   defaultCoopCloseAcceptWindow,
+		},
 	}
 	s.htlcSwitch = htlcswitch.New(htlcswitch.Config{})
 	s.htlcSwitch.Start()
@@ -255,6 +264,8 @@ func createTestPeer(notifier chainntnfs.ChainNotifier,
 		localSharedFeatures:  nil,
 		globalSharedFeatures: nil,
 
+		quirks: newPeerQuirks(nil),
+
 		queueQuit: make(chan struct{}),
 		quit:      make(chan struct{}),
 	}
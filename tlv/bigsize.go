@@ -0,0 +1,112 @@
+package tlv
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// ErrBigSizeNotCanonical is returned when decoding a BigSize integer which
+// wasn't encoded using the fewest number of bytes possible.
+var ErrBigSizeNotCanonical = errors.New("decoded bigsize is not canonical")
+
+// BigSizeLen returns the number of bytes needed to encode the passed integer
+// as a BigSize.
+func BigSizeLen(v uint64) uint64 {
+	switch {
+	case v < 0xfd:
+		return 1
+	case v <= 0xffff:
+		return 3
+	case v <= 0xffffffff:
+		return 5
+	default:
+		return 9
+	}
+}
+
+// WriteBigSize writes the passed integer as a BigSize. A BigSize is encoded
+// in the same manner as a Bitcoin varint, but using big-endian rather than
+// little-endian byte order for the multi-byte forms.
+func WriteBigSize(w io.Writer, v uint64) error {
+	var b [9]byte
+	switch {
+	case v < 0xfd:
+		b[0] = uint8(v)
+		_, err := w.Write(b[:1])
+		return err
+
+	case v <= 0xffff:
+		b[0] = 0xfd
+		binary.BigEndian.PutUint16(b[1:3], uint16(v))
+		_, err := w.Write(b[:3])
+		return err
+
+	case v <= 0xffffffff:
+		b[0] = 0xfe
+		binary.BigEndian.PutUint32(b[1:5], uint32(v))
+		_, err := w.Write(b[:5])
+		return err
+
+	default:
+		b[0] = 0xff
+		binary.BigEndian.PutUint64(b[1:9], v)
+		_, err := w.Write(b[:9])
+		return err
+	}
+}
+
+// ReadBigSize reads a BigSize integer, rejecting any integer which wasn't
+// canonically encoded. io.EOF is only returned if no bytes were read, a
+// partially read integer results in io.ErrUnexpectedEOF.
+func ReadBigSize(r io.Reader) (uint64, error) {
+	var b [8]byte
+	if _, err := io.ReadFull(r, b[:1]); err != nil {
+		return 0, err
+	}
+
+	var (
+		v   uint64
+		min uint64
+	)
+	switch b[0] {
+	case 0xfd:
+		if _, err := io.ReadFull(r, b[:2]); err != nil {
+			return 0, unexpectedEOF(err)
+		}
+		v = uint64(binary.BigEndian.Uint16(b[:2]))
+		min = 0xfd
+
+	case 0xfe:
+		if _, err := io.ReadFull(r, b[:4]); err != nil {
+			return 0, unexpectedEOF(err)
+		}
+		v = uint64(binary.BigEndian.Uint32(b[:4]))
+		min = 0x10000
+
+	case 0xff:
+		if _, err := io.ReadFull(r, b[:8]); err != nil {
+			return 0, unexpectedEOF(err)
+		}
+		v = binary.BigEndian.Uint64(b[:8])
+		min = 0x100000000
+
+	default:
+		return uint64(b[0]), nil
+	}
+
+	if v < min {
+		return 0, ErrBigSizeNotCanonical
+	}
+
+	return v, nil
+}
+
+// unexpectedEOF converts an io.EOF encountered partway through reading a
+// value into io.ErrUnexpectedEOF.
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
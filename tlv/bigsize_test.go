@@ -0,0 +1,110 @@
+package tlv
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+// TestBigSize checks the encoding and decoding of BigSize integers against
+// the test vectors of BOLT #1.
+func TestBigSize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value uint64
+		bytes []byte
+	}{
+		{0, []byte{0x00}},
+		{252, []byte{0xfc}},
+		{253, []byte{0xfd, 0x00, 0xfd}},
+		{65535, []byte{0xfd, 0xff, 0xff}},
+		{65536, []byte{0xfe, 0x00, 0x01, 0x00, 0x00}},
+		{4294967295, []byte{0xfe, 0xff, 0xff, 0xff, 0xff}},
+		{4294967296, []byte{
+			0xff, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00,
+		}},
+		{18446744073709551615, []byte{
+			0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+		}},
+	}
+
+	for _, test := range tests {
+		var b bytes.Buffer
+		if err := WriteBigSize(&b, test.value); err != nil {
+			t.Fatalf("unable to write %d: %v", test.value, err)
+		}
+		if !bytes.Equal(b.Bytes(), test.bytes) {
+			t.Fatalf("wrong encoding of %d: expected %x, got %x",
+				test.value, test.bytes, b.Bytes())
+		}
+		if BigSizeLen(test.value) != uint64(len(test.bytes)) {
+			t.Fatalf("wrong length of %d: expected %d, got %d",
+				test.value, len(test.bytes),
+				BigSizeLen(test.value))
+		}
+
+		v, err := ReadBigSize(bytes.NewReader(test.bytes))
+		if err != nil {
+			t.Fatalf("unable to read %x: %v", test.bytes, err)
+		}
+		if v != test.value {
+			t.Fatalf("wrong decoding of %x: expected %d, got %d",
+				test.bytes, test.value, v)
+		}
+	}
+}
+
+// TestBigSizeDecodeErrors checks that BigSize integers which are either
+// truncated or not canonically encoded are rejected.
+func TestBigSizeDecodeErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		bytes []byte
+		err   error
+	}{
+		{
+			name:  "empty",
+			bytes: nil,
+			err:   io.EOF,
+		},
+		{
+			name:  "two byte not canonical",
+			bytes: []byte{0xfd, 0x00, 0xfc},
+			err:   ErrBigSizeNotCanonical,
+		},
+		{
+			name:  "four byte not canonical",
+			bytes: []byte{0xfe, 0x00, 0x00, 0xff, 0xff},
+			err:   ErrBigSizeNotCanonical,
+		},
+		{
+			name: "eight byte not canonical",
+			bytes: []byte{
+				0xff, 0x00, 0x00, 0x00, 0x00, 0xff, 0xff, 0xff,
+				0xff,
+			},
+			err: ErrBigSizeNotCanonical,
+		},
+		{
+			name:  "two byte short read",
+			bytes: []byte{0xfd, 0x00},
+			err:   io.ErrUnexpectedEOF,
+		},
+		{
+			name:  "eight byte short read",
+			bytes: []byte{0xff, 0x12, 0x34, 0x56},
+			err:   io.ErrUnexpectedEOF,
+		},
+	}
+
+	for _, test := range tests {
+		_, err := ReadBigSize(bytes.NewReader(test.bytes))
+		if err != test.err {
+			t.Fatalf("%s: expected error %v, got %v", test.name,
+				test.err, err)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package tlv
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/roasbeef/btcd/btcec"
+)
+
+// errUnknownType is returned by an encoder or decoder which is passed a value
+// of a type it doesn't handle.
+func errUnknownType(val interface{}, expected string) error {
+	return fmt.Errorf("unable to handle %T, expected %s", val, expected)
+}
+
+// errInvalidLength is returned by a decoder of a fixed size value which is
+// passed a record of the wrong length.
+func errInvalidLength(val interface{}, l, expected uint64) error {
+	return fmt.Errorf("invalid length %d for %T, expected %d", l, val,
+		expected)
+}
+
+// EUint8 is an Encoder for *uint8 values.
+func EUint8(w io.Writer, val interface{}) error {
+	if v, ok := val.(*uint8); ok {
+		_, err := w.Write([]byte{*v})
+		return err
+	}
+	return errUnknownType(val, "*uint8")
+}
+
+// DUint8 is a Decoder for *uint8 values.
+func DUint8(r io.Reader, val interface{}, l uint64) error {
+	v, ok := val.(*uint8)
+	if !ok {
+		return errUnknownType(val, "*uint8")
+	}
+	if l != 1 {
+		return errInvalidLength(val, l, 1)
+	}
+
+	var b [1]byte
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		return err
+	}
+	*v = b[0]
+	return nil
+}
+
+// EUint16 is an Encoder for *uint16 values.
+func EUint16(w io.Writer, val interface{}) error {
+	if v, ok := val.(*uint16); ok {
+		var b [2]byte
+		binary.BigEndian.PutUint16(b[:], *v)
+		_, err := w.Write(b[:])
+		return err
+	}
+	return errUnknownType(val, "*uint16")
+}
+
+// DUint16 is a Decoder for *uint16 values.
+func DUint16(r io.Reader, val interface{}, l uint64) error {
+	v, ok := val.(*uint16)
+	if !ok {
+		return errUnknownType(val, "*uint16")
+	}
+	if l != 2 {
+		return errInvalidLength(val, l, 2)
+	}
+
+	var b [2]byte
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		return err
+	}
+	*v = binary.BigEndian.Uint16(b[:])
+	return nil
+}
+
+// EUint32 is an Encoder for *uint32 values.
+func EUint32(w io.Writer, val interface{}) error {
+	if v, ok := val.(*uint32); ok {
+		var b [4]byte
+		binary.BigEndian.PutUint32(b[:], *v)
+		_, err := w.Write(b[:])
+		return err
+	}
+	return errUnknownType(val, "*uint32")
+}
+
+// DUint32 is a Decoder for *uint32 values.
+func DUint32(r io.Reader, val interface{}, l uint64) error {
+	v, ok := val.(*uint32)
+	if !ok {
+		return errUnknownType(val, "*uint32")
+	}
+	if l != 4 {
+		return errInvalidLength(val, l, 4)
+	}
+
+	var b [4]byte
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		return err
+	}
+	*v = binary.BigEndian.Uint32(b[:])
+	return nil
+}
+
+// EUint64 is an Encoder for *uint64 values.
+func EUint64(w io.Writer, val interface{}) error {
+	if v, ok := val.(*uint64); ok {
+		var b [8]byte
+		binary.BigEndian.PutUint64(b[:], *v)
+		_, err := w.Write(b[:])
+		return err
+	}
+	return errUnknownType(val, "*uint64")
+}
+
+// DUint64 is a Decoder for *uint64 values.
+func DUint64(r io.Reader, val interface{}, l uint64) error {
+	v, ok := val.(*uint64)
+	if !ok {
+		return errUnknownType(val, "*uint64")
+	}
+	if l != 8 {
+		return errInvalidLength(val, l, 8)
+	}
+
+	var b [8]byte
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		return err
+	}
+	*v = binary.BigEndian.Uint64(b[:])
+	return nil
+}
+
+// EBytes32 is an Encoder for *[32]byte values.
+func EBytes32(w io.Writer, val interface{}) error {
+	if v, ok := val.(*[32]byte); ok {
+		_, err := w.Write(v[:])
+		return err
+	}
+	return errUnknownType(val, "*[32]byte")
+}
+
+// DBytes32 is a Decoder for *[32]byte values.
+func DBytes32(r io.Reader, val interface{}, l uint64) error {
+	v, ok := val.(*[32]byte)
+	if !ok {
+		return errUnknownType(val, "*[32]byte")
+	}
+	if l != 32 {
+		return errInvalidLength(val, l, 32)
+	}
+
+	_, err := io.ReadFull(r, v[:])
+	return err
+}
+
+// EBytes33 is an Encoder for *[33]byte values.
+func EBytes33(w io.Writer, val interface{}) error {
+	if v, ok := val.(*[33]byte); ok {
+		_, err := w.Write(v[:])
+		return err
+	}
+	return errUnknownType(val, "*[33]byte")
+}
+
+// DBytes33 is a Decoder for *[33]byte values.
+func DBytes33(r io.Reader, val interface{}, l uint64) error {
+	v, ok := val.(*[33]byte)
+	if !ok {
+		return errUnknownType(val, "*[33]byte")
+	}
+	if l != 33 {
+		return errInvalidLength(val, l, 33)
+	}
+
+	_, err := io.ReadFull(r, v[:])
+	return err
+}
+
+// EPubKey is an Encoder for **btcec.PublicKey values, which are written in
+// their compressed form.
+func EPubKey(w io.Writer, val interface{}) error {
+	if v, ok := val.(**btcec.PublicKey); ok {
+		_, err := w.Write((*v).SerializeCompressed())
+		return err
+	}
+	return errUnknownType(val, "**btcec.PublicKey")
+}
+
+// DPubKey is a Decoder for **btcec.PublicKey values.
+func DPubKey(r io.Reader, val interface{}, l uint64) error {
+	v, ok := val.(**btcec.PublicKey)
+	if !ok {
+		return errUnknownType(val, "**btcec.PublicKey")
+	}
+	if l != 33 {
+		return errInvalidLength(val, l, 33)
+	}
+
+	var b [33]byte
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		return err
+	}
+	pubKey, err := btcec.ParsePubKey(b[:], btcec.S256())
+	if err != nil {
+		return err
+	}
+	*v = pubKey
+	return nil
+}
+
+// EVarBytes is an Encoder for *[]byte values. The length of the slice is
+// given by the length of the record, so it isn't written.
+func EVarBytes(w io.Writer, val interface{}) error {
+	if v, ok := val.(*[]byte); ok {
+		_, err := w.Write(*v)
+		return err
+	}
+	return errUnknownType(val, "*[]byte")
+}
+
+// DVarBytes is a Decoder for *[]byte values, which reads the full length of
+// the record into the slice.
+func DVarBytes(r io.Reader, val interface{}, l uint64) error {
+	v, ok := val.(*[]byte)
+	if !ok {
+		return errUnknownType(val, "*[]byte")
+	}
+
+	b := make([]byte, l)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return err
+	}
+	*v = b
+	return nil
+}
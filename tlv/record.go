@@ -0,0 +1,105 @@
+// Package tlv implements the type-length-value streams which may be appended
+// to wire messages and onion payloads, as specified within BOLT #1.
+package tlv
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/roasbeef/btcd/btcec"
+)
+
+// Type is the type of a record within a TLV stream. Following the "it's ok to
+// be odd" rule, records with an odd type are optional, and are skipped by
+// readers which don't know them. Records with an even type are required, and
+// readers which don't know them must refuse to decode the stream.
+type Type uint64
+
+// Encoder writes the value pointed to by val, which must be of the type
+// expected by the encoder.
+type Encoder func(w io.Writer, val interface{}) error
+
+// Decoder reads a value of length l into the value pointed to by val, which
+// must be of the type expected by the decoder.
+type Decoder func(r io.Reader, val interface{}, l uint64) error
+
+// SizeFunc returns the length of a record's encoded value.
+type SizeFunc func() uint64
+
+// Record is a single record within a TLV stream. It pairs the type of the
+// record with a pointer to the value it's encoded from and decoded into.
+type Record struct {
+	typ     Type
+	value   interface{}
+	size    SizeFunc
+	encoder Encoder
+	decoder Decoder
+}
+
+// Type returns the type of the record.
+func (r Record) Type() Type {
+	return r.typ
+}
+
+// Size returns the length of the record's encoded value.
+func (r Record) Size() uint64 {
+	return r.size()
+}
+
+// MakeStaticRecord creates a record whose value is always encoded using size
+// bytes.
+func MakeStaticRecord(typ Type, val interface{}, size uint64, encoder Encoder,
+	decoder Decoder) Record {
+
+	return Record{
+		typ:     typ,
+		value:   val,
+		size:    func() uint64 { return size },
+		encoder: encoder,
+		decoder: decoder,
+	}
+}
+
+// MakeDynamicRecord creates a record whose value has a variable length, which
+// is computed by the passed SizeFunc at the time the record is encoded.
+func MakeDynamicRecord(typ Type, val interface{}, size SizeFunc,
+	encoder Encoder, decoder Decoder) Record {
+
+	return Record{
+		typ:     typ,
+		value:   val,
+		size:    size,
+		encoder: encoder,
+		decoder: decoder,
+	}
+}
+
+// MakePrimitiveRecord creates a record for a pointer to one of the primitive
+// types natively supported by this package: *uint8, *uint16, *uint32,
+// *uint64, *[32]byte, *[33]byte, *[]byte and **btcec.PublicKey.
+//
+// NOTE: This function panics if passed an unsupported type, as that can only
+// be the result of a programming error.
+func MakePrimitiveRecord(typ Type, val interface{}) Record {
+	switch e := val.(type) {
+	case *uint8:
+		return MakeStaticRecord(typ, e, 1, EUint8, DUint8)
+	case *uint16:
+		return MakeStaticRecord(typ, e, 2, EUint16, DUint16)
+	case *uint32:
+		return MakeStaticRecord(typ, e, 4, EUint32, DUint32)
+	case *uint64:
+		return MakeStaticRecord(typ, e, 8, EUint64, DUint64)
+	case *[32]byte:
+		return MakeStaticRecord(typ, e, 32, EBytes32, DBytes32)
+	case *[33]byte:
+		return MakeStaticRecord(typ, e, 33, EBytes33, DBytes33)
+	case **btcec.PublicKey:
+		return MakeStaticRecord(typ, e, 33, EPubKey, DPubKey)
+	case *[]byte:
+		size := func() uint64 { return uint64(len(*e)) }
+		return MakeDynamicRecord(typ, e, size, EVarBytes, DVarBytes)
+	default:
+		panic(fmt.Sprintf("unsupported primitive type %T", val))
+	}
+}
@@ -0,0 +1,178 @@
+package tlv
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// MaxRecordSize is the maximum length of the value of a single record within
+// a stream. As a stream is always carried within a single wire message or
+// onion payload, no valid record can exceed it, and it bounds the allocation
+// made when reading a corrupted record.
+const MaxRecordSize = 65535
+
+var (
+	// ErrStreamNotCanonical is returned when decoding a stream whose
+	// records aren't sorted by strictly increasing type.
+	ErrStreamNotCanonical = errors.New("tlv stream is not canonical")
+
+	// ErrRecordTooLarge is returned when decoding a stream containing a
+	// record whose length exceeds MaxRecordSize.
+	ErrRecordTooLarge = errors.New("tlv record is too large")
+)
+
+// ErrUnknownRequiredType is returned when decoding a stream which contains a
+// record with an even type that the stream doesn't know how to interpret.
+type ErrUnknownRequiredType Type
+
+// Error returns a human readable description of the error.
+//
+// NOTE: This is part of the error interface.
+func (e ErrUnknownRequiredType) Error() string {
+	return fmt.Sprintf("unknown required tlv type %d", uint64(e))
+}
+
+// TypeSet is the set of record types found while decoding a stream. As the
+// records of a stream are optional, it allows a caller to distinguish a
+// record that was absent from one decoded as a zero value.
+type TypeSet map[Type]struct{}
+
+// Stream is an ordered set of records which are encoded together as a TLV
+// stream. A stream is able to decode the records it knows about, while
+// skipping any unknown records with an odd type.
+type Stream struct {
+	records []Record
+}
+
+// NewStream creates a new stream from the passed records, which must be
+// sorted by strictly increasing type.
+func NewStream(records ...Record) (*Stream, error) {
+	for i := 1; i < len(records); i++ {
+		if records[i].typ <= records[i-1].typ {
+			return nil, fmt.Errorf("tlv records not sorted: type "+
+				"%d follows type %d", records[i].typ,
+				records[i-1].typ)
+		}
+	}
+
+	return &Stream{records: records}, nil
+}
+
+// MustNewStream creates a new stream from the passed records, panicking if
+// they're not sorted by strictly increasing type. It should only be used with
+// a fixed set of records, where an error can only be a programming error.
+func MustNewStream(records ...Record) *Stream {
+	s, err := NewStream(records...)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// Encode writes each record of the stream, in order. Each record is written
+// as its type and the length of its value, both as BigSize integers, followed
+// by the value itself.
+func (s *Stream) Encode(w io.Writer) error {
+	for _, record := range s.records {
+		size := record.Size()
+		if size > MaxRecordSize {
+			return ErrRecordTooLarge
+		}
+
+		if err := WriteBigSize(w, uint64(record.typ)); err != nil {
+			return err
+		}
+		if err := WriteBigSize(w, size); err != nil {
+			return err
+		}
+		if err := record.encoder(w, record.value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Decode reads a stream until the reader is exhausted, decoding each known
+// record into its value. An empty reader is a valid, empty stream, which
+// allows a stream to be appended to an existing message. Records must be
+// sorted by strictly increasing type, and any record with an unknown even
+// type results in ErrUnknownRequiredType.
+func (s *Stream) Decode(r io.Reader) error {
+	_, err := s.DecodeWithParsedTypes(r)
+	return err
+}
+
+// DecodeWithParsedTypes is identical to Decode, but additionally returns the
+// set of known record types that were found within the stream.
+func (s *Stream) DecodeWithParsedTypes(r io.Reader) (TypeSet, error) {
+	parsedTypes := make(TypeSet)
+
+	var (
+		lastType  Type
+		first     = true
+		recordIdx int
+	)
+	for {
+		t, err := ReadBigSize(r)
+		switch {
+		// The stream may only end between records.
+		case err == io.EOF:
+			return parsedTypes, nil
+		case err != nil:
+			return nil, err
+		}
+
+		typ := Type(t)
+		if !first && typ <= lastType {
+			return nil, ErrStreamNotCanonical
+		}
+		first = false
+		lastType = typ
+
+		length, err := ReadBigSize(r)
+		if err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		if length > MaxRecordSize {
+			return nil, ErrRecordTooLarge
+		}
+
+		value := make([]byte, length)
+		if _, err := io.ReadFull(r, value); err != nil {
+			return nil, unexpectedEOF(err)
+		}
+
+		// As both the records and the stream are sorted, we only need
+		// to advance past the known records preceding this type.
+		for recordIdx < len(s.records) &&
+			s.records[recordIdx].typ < typ {
+
+			recordIdx++
+		}
+
+		if recordIdx == len(s.records) ||
+			s.records[recordIdx].typ != typ {
+
+			if typ%2 == 0 {
+				return nil, ErrUnknownRequiredType(typ)
+			}
+			continue
+		}
+
+		record := s.records[recordIdx]
+		valueReader := bytes.NewReader(value)
+		err = record.decoder(valueReader, record.value, length)
+		if err != nil {
+			return nil, err
+		}
+		if valueReader.Len() != 0 {
+			return nil, fmt.Errorf("tlv type %d: %d trailing bytes",
+				typ, valueReader.Len())
+		}
+
+		parsedTypes[typ] = struct{}{}
+	}
+}
@@ -0,0 +1,194 @@
+package tlv
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/roasbeef/btcd/btcec"
+)
+
+// testRecords is a set of values covering each supported record type, along
+// with the stream used to encode and decode them.
+type testRecords struct {
+	u8     uint8
+	u16    uint16
+	u32    uint32
+	u64    uint64
+	tu32   uint32
+	tu64   uint64
+	b32    [32]byte
+	b33    [33]byte
+	pubKey *btcec.PublicKey
+	bytes  []byte
+}
+
+func (r *testRecords) stream() *Stream {
+	return MustNewStream(
+		MakePrimitiveRecord(1, &r.u8),
+		MakePrimitiveRecord(3, &r.u16),
+		MakePrimitiveRecord(5, &r.u32),
+		MakePrimitiveRecord(7, &r.u64),
+		MakeDynamicRecord(
+			9, &r.tu32, func() uint64 {
+				return SizeTUint32(r.tu32)
+			}, ETUint32, DTUint32,
+		),
+		MakeDynamicRecord(
+			11, &r.tu64, func() uint64 {
+				return SizeTUint64(r.tu64)
+			}, ETUint64, DTUint64,
+		),
+		MakePrimitiveRecord(13, &r.b32),
+		MakePrimitiveRecord(15, &r.b33),
+		MakePrimitiveRecord(17, &r.pubKey),
+		MakePrimitiveRecord(19, &r.bytes),
+	)
+}
+
+// TestStreamRoundTrip checks that every supported record type is decoded to
+// the value it was encoded from.
+func TestStreamRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	privKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+
+	records := &testRecords{
+		u8:     0x01,
+		u16:    0x0203,
+		u32:    0x04050607,
+		u64:    0x08090a0b0c0d0e0f,
+		tu32:   0x0100,
+		tu64:   0x010000000000,
+		pubKey: privKey.PubKey(),
+		bytes:  []byte("tlv"),
+	}
+	records.b32[0] = 0x20
+	records.b33[0] = 0x21
+
+	var b bytes.Buffer
+	if err := records.stream().Encode(&b); err != nil {
+		t.Fatalf("unable to encode stream: %v", err)
+	}
+
+	decoded := &testRecords{}
+	parsedTypes, err := decoded.stream().DecodeWithParsedTypes(&b)
+	if err != nil {
+		t.Fatalf("unable to decode stream: %v", err)
+	}
+	if !reflect.DeepEqual(records, decoded) {
+		t.Fatalf("records don't match after round trip: expected "+
+			"%v, got %v", records, decoded)
+	}
+	if len(parsedTypes) != 10 {
+		t.Fatalf("expected 10 parsed types, got %d", len(parsedTypes))
+	}
+}
+
+// TestStreamDecode checks that a stream is decoded according to the rules of
+// BOLT #1: unknown odd records are skipped, while unknown even records,
+// unsorted records and truncated records are rejected.
+func TestStreamDecode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		bytes       []byte
+		err         error
+		parsedTypes TypeSet
+	}{
+		{
+			name:        "empty stream",
+			bytes:       nil,
+			parsedTypes: TypeSet{},
+		},
+		{
+			name:        "unknown odd type skipped",
+			bytes:       []byte{0x02, 0x01, 0x2a, 0x21, 0x00},
+			parsedTypes: TypeSet{2: struct{}{}},
+		},
+		{
+			name:  "unknown even type",
+			bytes: []byte{0x02, 0x01, 0x2a, 0x20, 0x00},
+			err:   ErrUnknownRequiredType(0x20),
+		},
+		{
+			name:  "duplicate type",
+			bytes: []byte{0x02, 0x01, 0x2a, 0x02, 0x01, 0x2a},
+			err:   ErrStreamNotCanonical,
+		},
+		{
+			name:  "decreasing type",
+			bytes: []byte{0x21, 0x00, 0x02, 0x01, 0x2a},
+			err:   ErrStreamNotCanonical,
+		},
+		{
+			name:  "missing length",
+			bytes: []byte{0x02},
+			err:   io.ErrUnexpectedEOF,
+		},
+		{
+			name:  "truncated value",
+			bytes: []byte{0x21, 0x02, 0x00},
+			err:   io.ErrUnexpectedEOF,
+		},
+		{
+			name:  "record too large",
+			bytes: []byte{0x21, 0xfe, 0x00, 0x01, 0x00, 0x00},
+			err:   ErrRecordTooLarge,
+		},
+		{
+			name:  "truncated integer not minimal",
+			bytes: []byte{0x04, 0x02, 0x00, 0x01},
+			err:   ErrTUintNotMinimal,
+		},
+	}
+
+	for _, test := range tests {
+		var (
+			u8   uint8
+			tu64 uint64
+		)
+		stream := MustNewStream(
+			MakePrimitiveRecord(2, &u8),
+			MakeDynamicRecord(
+				4, &tu64, func() uint64 {
+					return SizeTUint64(tu64)
+				}, ETUint64, DTUint64,
+			),
+		)
+
+		parsedTypes, err := stream.DecodeWithParsedTypes(
+			bytes.NewReader(test.bytes),
+		)
+		if err != test.err {
+			t.Fatalf("%s: expected error %v, got %v", test.name,
+				test.err, err)
+		}
+		if err != nil {
+			continue
+		}
+		if !reflect.DeepEqual(parsedTypes, test.parsedTypes) {
+			t.Fatalf("%s: expected parsed types %v, got %v",
+				test.name, test.parsedTypes, parsedTypes)
+		}
+	}
+}
+
+// TestNewStreamUnsorted checks that a stream can't be created from records
+// which aren't sorted by strictly increasing type.
+func TestNewStreamUnsorted(t *testing.T) {
+	t.Parallel()
+
+	var a, b uint8
+	_, err := NewStream(
+		MakePrimitiveRecord(3, &a), MakePrimitiveRecord(1, &b),
+	)
+	if err == nil {
+		t.Fatalf("expected unsorted records to be rejected")
+	}
+}
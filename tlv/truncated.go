@@ -0,0 +1,105 @@
+package tlv
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// ErrTUintNotMinimal is returned when decoding a truncated integer which has
+// leading zero bytes, and so wasn't encoded minimally.
+var ErrTUintNotMinimal = errors.New("truncated integer is not minimally " +
+	"encoded")
+
+// SizeTUint32 returns the number of bytes needed to encode the passed integer
+// as a truncated uint32, in which leading zero bytes are omitted.
+func SizeTUint32(v uint32) uint64 {
+	return SizeTUint64(uint64(v))
+}
+
+// SizeTUint64 returns the number of bytes needed to encode the passed integer
+// as a truncated uint64, in which leading zero bytes are omitted.
+func SizeTUint64(v uint64) uint64 {
+	var n uint64
+	for ; v != 0; v >>= 8 {
+		n++
+	}
+	return n
+}
+
+// ETUint32 is an Encoder for *uint32 values, which writes them as truncated
+// integers. The record must be sized using SizeTUint32.
+func ETUint32(w io.Writer, val interface{}) error {
+	if v, ok := val.(*uint32); ok {
+		return writeTUint(w, uint64(*v))
+	}
+	return errUnknownType(val, "*uint32")
+}
+
+// DTUint32 is a Decoder for *uint32 values encoded as truncated integers.
+func DTUint32(r io.Reader, val interface{}, l uint64) error {
+	v, ok := val.(*uint32)
+	if !ok {
+		return errUnknownType(val, "*uint32")
+	}
+	if l > 4 {
+		return errInvalidLength(val, l, 4)
+	}
+
+	t, err := readTUint(r, l)
+	if err != nil {
+		return err
+	}
+	*v = uint32(t)
+	return nil
+}
+
+// ETUint64 is an Encoder for *uint64 values, which writes them as truncated
+// integers. The record must be sized using SizeTUint64.
+func ETUint64(w io.Writer, val interface{}) error {
+	if v, ok := val.(*uint64); ok {
+		return writeTUint(w, *v)
+	}
+	return errUnknownType(val, "*uint64")
+}
+
+// DTUint64 is a Decoder for *uint64 values encoded as truncated integers.
+func DTUint64(r io.Reader, val interface{}, l uint64) error {
+	v, ok := val.(*uint64)
+	if !ok {
+		return errUnknownType(val, "*uint64")
+	}
+	if l > 8 {
+		return errInvalidLength(val, l, 8)
+	}
+
+	t, err := readTUint(r, l)
+	if err != nil {
+		return err
+	}
+	*v = t
+	return nil
+}
+
+// writeTUint writes the passed integer in big-endian byte order, omitting any
+// leading zero bytes.
+func writeTUint(w io.Writer, v uint64) error {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], v)
+	_, err := w.Write(b[8-SizeTUint64(v):])
+	return err
+}
+
+// readTUint reads a truncated integer of l bytes, which must not exceed 8,
+// rejecting any integer with a leading zero byte.
+func readTUint(r io.Reader, l uint64) (uint64, error) {
+	var b [8]byte
+	if _, err := io.ReadFull(r, b[8-l:]); err != nil {
+		return 0, err
+	}
+	if l > 0 && b[8-l] == 0 {
+		return 0, ErrTUintNotMinimal
+	}
+
+	return binary.BigEndian.Uint64(b[:]), nil
+}
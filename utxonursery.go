@@ -10,10 +10,10 @@ import (
 	"sync/atomic"
 
 	"github.com/boltdb/bolt"
-	"github.com/davecgh/go-spew/spew"
 	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/sweep"
 	"github.com/roasbeef/btcd/txscript"
 	"github.com/roasbeef/btcd/wire"
 	"github.com/roasbeef/btcutil"
@@ -31,19 +31,25 @@ var (
 	//               {outpoint2} -> info
 	preschoolBucket = []byte("psc")
 
-	// preschoolIndex is an index that maps original chanPoint that created
-	// the channel to all the active time-locked outpoints for that
-	// channel.
-	preschoolIndex = []byte("preschool-index")
+	// cribBucket stores the outgoing HTLC outputs of commitment
+	// transactions that have been broadcast. These outputs are locked by
+	// an absolute CLTV timeout, after which their second-level timeout
+	// transaction is broadcast. Once the timeout transaction has been
+	// confirmed, its output is moved to the kindergarten bucket. Should
+	// the remote party instead claim the HTLC with its preimage, then the
+	// output is removed from this bucket.
+	//
+	// mapping: htlcOutpoint -> babyOutput
+	cribBucket = []byte("crb")
 
 	// kindergartenBucket stores outputs from commitment transactions that
 	// have received an initial confirmation, but which aren't yet
 	// spendable because they require additional confirmations enforced by
 	// CheckSequenceVerify. Once required additional confirmations have
 	// been reported, a sweep transaction will be created to move the funds
-	// out of these outputs. After a further six confirmations have been
-	// reported, the outputs will be deleted from this bucket. The purpose
-	// of this additional wait time is to ensure that a block
+	// out of these outputs. Once the sweep transaction has received six
+	// confirmations, the outputs will be deleted from this bucket. The
+	// purpose of this additional wait time is to ensure that a block
 	// reorganization doesn't result in the sweep transaction getting
 	// re-organized out of the chain.
 	// TODO(roasbeef): modify schema later to be:
@@ -51,15 +57,6 @@ var (
 	//              {chanPoint} -> info
 	kindergartenBucket = []byte("kdg")
 
-	// contractIndex is an index that maps a contract's channel point to
-	// the current information pertaining to the maturity of outputs within
-	// that contract. Items are inserted into this index once they've been
-	// accepted to pre-school and deleted after the output has been fully
-	// swept.
-	//
-	// mapping: chanPoint -> graduationHeight || byte-offset-in-kindergartenBucket
-	contractIndex = []byte("contract-index")
-
 	// lastGraduatedHeightKey is used to persist the last block height that
 	// has been checked for graduating outputs. When the nursery is
 	// restarted, lastGraduatedHeightKey is used to determine the point
@@ -69,6 +66,13 @@ var (
 	byteOrder = binary.BigEndian
 )
 
+const (
+	// graduationConfs is the number of confirmations the sweep of a mature
+	// output must receive before the output is deleted from the
+	// kindergarten bucket.
+	graduationConfs = 6
+)
+
 var (
 	// ErrContractNotFound is returned when the nursery is unable to
 	// retreive information about a queried contract.
@@ -80,15 +84,27 @@ var (
 // peer. The nursery accepts outputs and "incubates" them until they've reached
 // maturity, then sweep the outputs into the source wallet. An output is
 // considered mature after the relative time-lock within the pkScript has
-// passed. As outputs reach their maturity age, they're swept in batches into
-// the source wallet, returning the outputs so they can be used within future
-// channels, or regular Bitcoin transactions.
+// passed. Outgoing HTLC outputs, which are locked by an absolute time-lock,
+// are first timed out by their second-level timeout transaction, the output
+// of which is then incubated like any other. As outputs reach their maturity
+// age, they're swept in batches into the source wallet, returning the outputs
+// so they can be used within future channels, or regular Bitcoin
+// transactions.
 type utxoNursery struct {
 	sync.RWMutex
 
 	notifier chainntnfs.ChainNotifier
 	wallet   *lnwallet.LightningWallet
 
+	// sweeper is used to sweep mature outputs back into the wallet,
+	// batched with any other outputs being swept.
+	sweeper *sweep.UtxoSweeper
+
+	// sweeping is the set of mature outputs which have been offered to
+	// the sweeper, and are yet to be swept. It's guarded by sweepingMtx.
+	sweeping    map[wire.OutPoint]struct{}
+	sweepingMtx sync.Mutex
+
 	db *channeldb.DB
 
 	requests chan *incubationRequest
@@ -100,13 +116,16 @@ type utxoNursery struct {
 }
 
 // newUtxoNursery creates a new instance of the utxoNursery from a
-// ChainNotifier and LightningWallet instance.
+// ChainNotifier, LightningWallet and UtxoSweeper instance.
 func newUtxoNursery(db *channeldb.DB, notifier chainntnfs.ChainNotifier,
-	wallet *lnwallet.LightningWallet) *utxoNursery {
+	wallet *lnwallet.LightningWallet,
+	sweeper *sweep.UtxoSweeper) *utxoNursery {
 
 	return &utxoNursery{
 		notifier: notifier,
 		wallet:   wallet,
+		sweeper:  sweeper,
+		sweeping: make(map[wire.OutPoint]struct{}),
 		requests: make(chan *incubationRequest),
 		db:       db,
 		quit:     make(chan struct{}),
@@ -128,16 +147,7 @@ func (u *utxoNursery) Start() error {
 	// outputs.
 	var lastGraduatedHeight uint32
 	err := u.db.View(func(tx *bolt.Tx) error {
-		kgtnBucket := tx.Bucket(kindergartenBucket)
-		if kgtnBucket == nil {
-			return nil
-		}
-		heightBytes := kgtnBucket.Get(lastGraduatedHeightKey)
-		if heightBytes == nil {
-			return nil
-		}
-
-		lastGraduatedHeight = byteOrder.Uint32(heightBytes)
+		lastGraduatedHeight = fetchLastGraduatedHeight(tx)
 		return nil
 	})
 	if err != nil {
@@ -147,6 +157,9 @@ func (u *utxoNursery) Start() error {
 	if err := u.reloadPreschool(lastGraduatedHeight); err != nil {
 		return err
 	}
+	if err := u.reloadCrib(lastGraduatedHeight); err != nil {
+		return err
+	}
 
 	// Register with the notifier to receive notifications for each newly
 	// connected block. We register during startup to ensure that no blocks
@@ -160,6 +173,22 @@ func (u *utxoNursery) Start() error {
 		return err
 	}
 
+	// The timeout transactions of any HTLCs which expired while we were
+	// offline are broadcast now, rather than waiting for the next block.
+	_, bestHeight, err := u.wallet.Cfg.ChainIO.GetBestBlock()
+	if err != nil {
+		return err
+	}
+	if err := u.broadcastTimeoutTxns(uint32(bestHeight)); err != nil {
+		return err
+	}
+
+	// Any mature outputs which were yet to be swept when we were shut
+	// down are offered to the sweeper once again.
+	if err := u.sweepMatureOutputs(uint32(bestHeight)); err != nil {
+		return err
+	}
+
 	u.wg.Add(1)
 	go u.incubator(newBlockChan, lastGraduatedHeight)
 
@@ -199,6 +228,44 @@ func (u *utxoNursery) reloadPreschool(heightHint uint32) error {
 	})
 }
 
+// reloadCrib re-registers for the spend of each HTLC output that had been
+// saved to the "crib" database bucket prior to shutdown.
+func (u *utxoNursery) reloadCrib(heightHint uint32) error {
+	var babies []*babyOutput
+	err := u.db.View(func(tx *bolt.Tx) error {
+		cribBkt := tx.Bucket(cribBucket)
+		if cribBkt == nil {
+			return nil
+		}
+
+		return cribBkt.ForEach(func(_, babyBytes []byte) error {
+			baby, err := deserializeBabyOutput(
+				bytes.NewReader(babyBytes),
+			)
+			if err != nil {
+				return err
+			}
+
+			babies = append(babies, baby)
+			return nil
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, baby := range babies {
+		if err := u.watchBaby(baby, heightHint); err != nil {
+			return err
+		}
+
+		utxnLog.Infof("Crib outpoint %v re-registered for spend "+
+			"notification.", baby.htlcOutpoint())
+	}
+
+	return nil
+}
+
 // catchUpKindergarten handles the graduation of kindergarten outputs from
 // blocks that were missed while the UTXO Nursery was down or offline.
 // graduateMissedBlocks is called during the startup of the UTXO Nursery.
@@ -252,8 +319,8 @@ func (u *utxoNursery) Stop() error {
 
 // kidOutput represents an output that's waiting for a required blockheight
 // before its funds will be available to be moved into the user's wallet.  The
-// struct includes a WitnessGenerator closure which will be used to generate
-// the witness required to sweep the output once it's mature.
+// struct includes the sign descriptor and witness type which the sweeper uses
+// to generate the witness required to sweep the output once it's mature.
 //
 // TODO(roasbeef): rename to immatureOutput?
 type kidOutput struct {
@@ -269,7 +336,30 @@ type kidOutput struct {
 
 	signDescriptor *lnwallet.SignDescriptor
 	witnessType    lnwallet.WitnessType
-	witnessFunc    lnwallet.WitnessGenerator
+}
+
+// babyOutput represents an outgoing HTLC output on our commitment transaction
+// which is locked by an absolute CLTV timeout. Once the HTLC has expired, its
+// second-level timeout transaction is broadcast. The output of the timeout
+// transaction is itself locked by a relative CSV delay, so once the timeout
+// transaction has been confirmed, the embedded kidOutput is moved to
+// kindergarten to await its maturity.
+type babyOutput struct {
+	// expiry is the absolute block height at which the timeout
+	// transaction can be broadcast.
+	expiry uint32
+
+	// timeoutTx is the fully signed second-level timeout transaction which
+	// spends the HTLC output.
+	timeoutTx *wire.MsgTx
+
+	kidOutput
+}
+
+// htlcOutpoint returns the outpoint of the HTLC output on our commitment
+// transaction, which is spent by the timeout transaction.
+func (b *babyOutput) htlcOutpoint() wire.OutPoint {
+	return b.timeoutTx.TxIn[0].PreviousOutPoint
 }
 
 // incubationRequest is a request to the utxoNursery to incubate a set of
@@ -277,6 +367,7 @@ type kidOutput struct {
 // available.
 type incubationRequest struct {
 	outputs []*kidOutput
+	babies  []*babyOutput
 }
 
 // incubateOutputs sends a request to utxoNursery to incubate the outputs
@@ -302,9 +393,36 @@ func (u *utxoNursery) IncubateOutputs(closeSummary *lnwallet.ForceCloseSummary)
 		incReq.outputs = append(incReq.outputs, selfOutput)
 	}
 
+	// Each of our outgoing HTLCs must first be timed out by its
+	// second-level timeout transaction, the output of which is then
+	// incubated until its CSV delay has passed.
+	for i := range closeSummary.HtlcResolutions {
+		htlcRes := &closeSummary.HtlcResolutions[i]
+		timeoutTx := htlcRes.SignedTimeoutTx
+		outputAmt := btcutil.Amount(timeoutTx.TxOut[0].Value)
+
+		baby := &babyOutput{
+			expiry:    htlcRes.Expiry,
+			timeoutTx: timeoutTx,
+			kidOutput: kidOutput{
+				originChanPoint: closeSummary.ChanPoint,
+				amt:             outputAmt,
+				outPoint: wire.OutPoint{
+					Hash:  timeoutTx.TxHash(),
+					Index: 0,
+				},
+				blocksToMaturity: htlcRes.CsvDelay,
+				signDescriptor:   &htlcRes.SweepSignDesc,
+				witnessType:      lnwallet.HtlcOfferedTimeoutSecondLevel,
+			},
+		}
+
+		incReq.babies = append(incReq.babies, baby)
+	}
+
 	// If there are no outputs to incubate, there is nothing to send to the
 	// request channel.
-	if len(incReq.outputs) != 0 {
+	if len(incReq.outputs) != 0 || len(incReq.babies) != 0 {
 		u.requests <- &incReq
 	}
 }
@@ -316,7 +434,9 @@ func (u *utxoNursery) IncubateOutputs(closeSummary *lnwallet.ForceCloseSummary)
 // enforced by CheckSequenceVerify). When the necessary block height has been
 // reached, the output has "matured" and the waitForGraduation function will
 // generate a sweep transaction to move funds from the commitment transaction
-// into the user's wallet.
+// into the user's wallet. Outgoing HTLC outputs instead begin within the
+// "crib", where they remain until their timeout transaction has been
+// confirmed.
 func (u *utxoNursery) incubator(newBlockChan *chainntnfs.BlockEpochEvent,
 	startingHeight uint32) {
 
@@ -328,11 +448,11 @@ out:
 	for {
 		select {
 
-		case preschoolRequest := <-u.requests:
+		case incRequest := <-u.requests:
 			utxnLog.Infof("Incubating %v new outputs",
-				len(preschoolRequest.outputs))
+				len(incRequest.outputs)+len(incRequest.babies))
 
-			for _, output := range preschoolRequest.outputs {
+			for _, output := range incRequest.outputs {
 				// We'll skip any zero value'd outputs as this
 				// indicates we don't have a settled balance
 				// within the commitment transaction.
@@ -368,6 +488,18 @@ out:
 				go output.waitForPromotion(u.db, confChan)
 			}
 
+			// Each outgoing HTLC output is placed within the
+			// crib, where it remains until the HTLC has either
+			// been timed out, or claimed by the remote party.
+			for _, baby := range incRequest.babies {
+				err := u.enterCrib(baby, currentHeight)
+				if err != nil {
+					utxnLog.Errorf("unable to add babyOutput "+
+						"to crib: %v, %v",
+						baby.htlcOutpoint(), err)
+				}
+			}
+
 		case epoch, ok := <-newBlockChan.Epochs:
 			// If the epoch channel has been closed, then the
 			// ChainNotifier is exiting which means the daemon is
@@ -387,6 +519,10 @@ out:
 			// entails successfully sweeping a time-locked output.
 			height := uint32(epoch.Height)
 			currentHeight = height
+			if err := u.broadcastTimeoutTxns(height); err != nil {
+				utxnLog.Errorf("error while broadcasting "+
+					"timeout transactions: %v", err)
+			}
 			if err := u.graduateKindergarten(height); err != nil {
 				utxnLog.Errorf("error while graduating "+
 					"kindergarten outputs: %v", err)
@@ -420,113 +556,193 @@ type contractMaturityReport struct {
 	// maturityHeight is the absolute block height that this output will mature
 	// at.
 	maturityHeight uint32
+
+	// htlcs records the maturity progress of each of the contract's
+	// outgoing HTLC outputs.
+	htlcs []htlcMaturityReport
+}
+
+// htlcMaturityReport is a report that details the maturity progress of a
+// single outgoing HTLC output of a force closed contract.
+type htlcMaturityReport struct {
+	// outpoint is the output currently being incubated. This is the HTLC
+	// output on the commitment transaction within the first stage, and
+	// the output of the timeout transaction within the second.
+	outpoint wire.OutPoint
+
+	// amount is the value of the output which will be swept into the
+	// wallet.
+	amount btcutil.Amount
+
+	// maturityHeight is the absolute block height at which the current
+	// stage can be spent. Within the first stage, this is the expiry of
+	// the HTLC.
+	maturityHeight uint32
+
+	// stage is the stage of incubation the HTLC output is within. The
+	// HTLC is within its first stage until its timeout transaction has
+	// been confirmed, after which it's within its second.
+	stage uint32
 }
 
 // NurseryReport attempts to return a nursery report stored for the target
 // outpoint. A nursery report details the maturity/sweeping progress for a
-// contract that was previously force closed. If a report entry for the target
-// chanPoint is unable to be constructed, then an error will be returned.
+// contract that was previously force closed. If none of the contract's
+// outputs are being incubated, then ErrContractNotFound is returned.
 func (u *utxoNursery) NurseryReport(chanPoint *wire.OutPoint) (*contractMaturityReport, error) {
-	var report *contractMaturityReport
+	var outputs *chanOutputs
 	if err := u.db.View(func(tx *bolt.Tx) error {
-		// First we'll examine the preschool bucket as the target
-		// contract may not yet have been confirmed.
-		psclBucket := tx.Bucket(preschoolBucket)
-		if psclBucket == nil {
-			return nil
-		}
-		psclIndex := tx.Bucket(preschoolIndex)
-		if psclIndex == nil {
-			return nil
-		}
+		var err error
+		outputs, err = fetchChanOutputs(
+			tx, chanPoint, fetchLastGraduatedHeight(tx),
+		)
+		return err
+	}); err != nil {
+		return nil, err
+	}
 
-		var b bytes.Buffer
-		if err := writeOutpoint(&b, chanPoint); err != nil {
-			return err
+	if outputs.empty() {
+		return nil, ErrContractNotFound
+	}
+
+	report := &contractMaturityReport{
+		chanPoint: *chanPoint,
+	}
+
+	// If our commitment output is still within preschool, then the
+	// commitment transaction hasn't yet confirmed, so its maturity height
+	// is unknown.
+	for _, kid := range outputs.preschool {
+		report.limboBalance += kid.amt
+		report.maturityRequirement = kid.blocksToMaturity
+	}
+
+	// Outgoing HTLC outputs within the crib mature once the HTLC has
+	// expired, at which point the timeout transaction can be broadcast.
+	for _, baby := range outputs.crib {
+		report.limboBalance += baby.amt
+		report.htlcs = append(report.htlcs, htlcMaturityReport{
+			outpoint:       baby.htlcOutpoint(),
+			amount:         baby.amt,
+			maturityHeight: baby.expiry,
+			stage:          1,
+		})
+	}
+
+	// Within kindergarten, the outputs of our timeout transactions are
+	// distinguished from our commitment output by their witness type.
+	for _, kid := range outputs.kindergarten {
+		report.limboBalance += kid.amt
+		maturityHeight := kid.confHeight + kid.blocksToMaturity
+
+		if kid.witnessType == lnwallet.HtlcOfferedTimeoutSecondLevel {
+			report.htlcs = append(report.htlcs, htlcMaturityReport{
+				outpoint:       kid.outPoint,
+				amount:         kid.amt,
+				maturityHeight: maturityHeight,
+				stage:          2,
+			})
+			continue
 		}
-		chanPointBytes := b.Bytes()
-
-		var outputReader *bytes.Reader
-
-		// If the target contract hasn't been confirmed yet, then we
-		// can just construct the report from this information.
-		if outPoint := psclIndex.Get(chanPointBytes); outPoint != nil {
-			// The channel entry hasn't yet been fully confirmed
-			// yet, so we'll dig into the preschool bucket to fetch
-			// the channel information.
-			outputBytes := psclBucket.Get(outPoint)
-			if outputBytes == nil {
-				return nil
-			}
 
-			outputReader = bytes.NewReader(outputBytes)
-		} else {
-			// Otherwise, we'll have to consult out contract index,
-			// so fetch that bucket as well as the kindergarten
-			// bucket.
-			indexBucket := tx.Bucket(contractIndex)
-			if indexBucket == nil {
-				return fmt.Errorf("contract not found, " +
-					"contract index not populated")
-			}
-			kgtnBucket := tx.Bucket(kindergartenBucket)
-			if kgtnBucket == nil {
-				return fmt.Errorf("contract not found, " +
-					"kindergarten bucket not populated")
-			}
+		report.confirmationHeight = kid.confHeight
+		report.maturityRequirement = kid.blocksToMaturity
+		report.maturityHeight = maturityHeight
+	}
 
-			// Attempt to query the index to see if we have an
-			// entry for this particular contract.
-			indexInfo := indexBucket.Get(chanPointBytes)
-			if indexInfo == nil {
-				return ErrContractNotFound
-			}
+	return report, nil
+}
+
+// chanOutputs houses the outputs of a single channel which remain within each
+// stage of incubation.
+type chanOutputs struct {
+	preschool    []*kidOutput
+	crib         []*babyOutput
+	kindergarten []*kidOutput
+}
+
+// empty returns true if none of the channel's outputs remain to be incubated.
+func (c *chanOutputs) empty() bool {
+	return len(c.preschool) == 0 && len(c.crib) == 0 &&
+		len(c.kindergarten) == 0
+}
+
+// fetchChanOutputs returns the outputs of the target channel which remain to
+// be incubated. Kindergarten outputs maturing at or below the passed height
+// have already graduated, so they're omitted.
+func fetchChanOutputs(tx *bolt.Tx, chanPoint *wire.OutPoint,
+	graduatedHeight uint32) (*chanOutputs, error) {
+
+	outputs := &chanOutputs{}
 
-			// If an entry is found, then using the height store in
-			// the first 4 bytes, we'll fetch the height that this
-			// entry matures at.
-			height := indexInfo[:4]
-			heightRow := kgtnBucket.Get(height)
-			if heightRow == nil {
-				return ErrContractNotFound
+	if psclBucket := tx.Bucket(preschoolBucket); psclBucket != nil {
+		err := psclBucket.ForEach(func(_, kidBytes []byte) error {
+			kid, err := deserializeKidOutput(bytes.NewReader(kidBytes))
+			if err != nil {
+				return err
 			}
 
-			// Once we have the entry itself, we'll slice of the
-			// last for bytes so we can seek into this row to fetch
-			// the contract's information.
-			offset := byteOrder.Uint32(indexInfo[4:])
-			outputReader = bytes.NewReader(heightRow[offset:])
+			if kid.originChanPoint == *chanPoint {
+				outputs.preschool = append(outputs.preschool, kid)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
 		}
+	}
+
+	if cribBkt := tx.Bucket(cribBucket); cribBkt != nil {
+		err := cribBkt.ForEach(func(_, babyBytes []byte) error {
+			baby, err := deserializeBabyOutput(
+				bytes.NewReader(babyBytes),
+			)
+			if err != nil {
+				return err
+			}
 
-		// With the proper set of bytes received, we'll deserialize the
-		// information for this immature output.
-		immatureOutput, err := deserializeKidOutput(outputReader)
+			if baby.originChanPoint == *chanPoint {
+				outputs.crib = append(outputs.crib, baby)
+			}
+			return nil
+		})
 		if err != nil {
-			return err
+			return nil, err
 		}
+	}
 
-		// TODO(roasbeef): should actually be list of outputs
-		report = &contractMaturityReport{
-			chanPoint:           *chanPoint,
-			limboBalance:        immatureOutput.amt,
-			maturityRequirement: immatureOutput.blocksToMaturity,
-		}
+	if kgtnBucket := tx.Bucket(kindergartenBucket); kgtnBucket != nil {
+		err := kgtnBucket.ForEach(func(heightBytes, kidBytes []byte) error {
+			// The last graduated height is stored alongside the
+			// rows of outputs, so we'll skip over it.
+			if bytes.Equal(heightBytes, lastGraduatedHeightKey) {
+				return nil
+			}
+			if byteOrder.Uint32(heightBytes) <= graduatedHeight {
+				return nil
+			}
 
-		// If the confirmation height is set, then this means the
-		// contract has been confirmed, and we know the final maturity
-		// height.
-		if immatureOutput.confHeight != 0 {
-			report.confirmationHeight = immatureOutput.confHeight
-			report.maturityHeight = (immatureOutput.blocksToMaturity +
-				immatureOutput.confHeight)
-		}
+			kids, err := deserializeKidList(bytes.NewReader(kidBytes))
+			if err != nil {
+				return err
+			}
 
-		return nil
-	}); err != nil {
-		return nil, err
+			for _, kid := range kids {
+				if kid.originChanPoint != *chanPoint {
+					continue
+				}
+				outputs.kindergarten = append(
+					outputs.kindergarten, kid,
+				)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	return report, nil
+	return outputs, nil
 }
 
 // enterPreschool is the first stage in the process of transferring funds from
@@ -539,12 +755,8 @@ func (k *kidOutput) enterPreschool(db *channeldb.DB) error {
 		if err != nil {
 			return err
 		}
-		psclIndex, err := tx.CreateBucketIfNotExists(preschoolIndex)
-		if err != nil {
-			return err
-		}
 
-		// Once we have the buckets we can insert the raw bytes of the
+		// Once we have the bucket we can insert the raw bytes of the
 		// immature outpoint into the preschool bucket.
 		var outpointBytes bytes.Buffer
 		if err := writeOutpoint(&outpointBytes, &k.outPoint); err != nil {
@@ -559,19 +771,6 @@ func (k *kidOutput) enterPreschool(db *channeldb.DB) error {
 			return err
 		}
 
-		// Additionally, we'll populate the preschool index so we can
-		// track all the immature outpoints for a particular channel's
-		// chanPoint.
-		var b bytes.Buffer
-		err = writeOutpoint(&b, &k.originChanPoint)
-		if err != nil {
-			return err
-		}
-		err = psclIndex.Put(b.Bytes(), outpointBytes.Bytes())
-		if err != nil {
-			return err
-		}
-
 		utxnLog.Infof("Outpoint %v now in preschool, waiting for "+
 			"initial confirmation", k.outPoint)
 
@@ -603,23 +802,14 @@ func (k *kidOutput) waitForPromotion(db *channeldb.DB, confChan *chainntnfs.Conf
 	// keyed by block height. Keys and values are serialized into byte
 	// array form prior to database insertion.
 	err := db.Update(func(tx *bolt.Tx) error {
-		var originPoint bytes.Buffer
-		if err := writeOutpoint(&originPoint, &k.originChanPoint); err != nil {
-			return err
-		}
-
 		psclBucket := tx.Bucket(preschoolBucket)
 		if psclBucket == nil {
 			return errors.New("unable to open preschool bucket")
 		}
-		psclIndex := tx.Bucket(preschoolIndex)
-		if psclIndex == nil {
-			return errors.New("unable to open preschool index")
-		}
 
 		// Now that the entry has been confirmed, in order to move it
 		// along in the maturity pipeline we first delete the entry
-		// from the preschool bucket, as well as the secondary index.
+		// from the preschool bucket.
 		var outpointBytes bytes.Buffer
 		if err := writeOutpoint(&outpointBytes, &k.outPoint); err != nil {
 			return err
@@ -629,69 +819,294 @@ func (k *kidOutput) waitForPromotion(db *channeldb.DB, confChan *chainntnfs.Conf
 				"preschool bucket: %v", k.outPoint)
 			return err
 		}
-		if err := psclIndex.Delete(originPoint.Bytes()); err != nil {
-			utxnLog.Errorf("unable to delete kindergarten output from "+
-				"preschool index: %v", k.outPoint)
+
+		return k.enterKindergarten(tx)
+	})
+	if err != nil {
+		utxnLog.Errorf("unable to move kid output from preschool bucket "+
+			"to kindergarten bucket: %v", err)
+	}
+}
+
+// enterKindergarten adds the confirmed output to the kindergarten bucket,
+// which is keyed by block height. The output will remain in this bucket until
+// it's fully mature.
+func (k *kidOutput) enterKindergarten(tx *bolt.Tx) error {
+	kgtnBucket, err := tx.CreateBucketIfNotExists(kindergartenBucket)
+	if err != nil {
+		return err
+	}
+
+	maturityHeight := k.confHeight + k.blocksToMaturity
+
+	// The rows at or below the last graduated height are considered to
+	// have graduated already, and aren't revisited once we catch up after
+	// a restart. Should the output have matured by then, it's placed at
+	// the next height to graduate instead, where it's swept right away.
+	lastGraduatedHeight := fetchLastGraduatedHeight(tx)
+	if maturityHeight <= lastGraduatedHeight {
+		maturityHeight = lastGraduatedHeight + 1
+	}
+
+	heightBytes := make([]byte, 4)
+	byteOrder.PutUint32(heightBytes, maturityHeight)
+
+	// If there're any existing outputs for this particular block height
+	// target, then we'll append this new output to the serialized list of
+	// outputs.
+	var b bytes.Buffer
+	if results := kgtnBucket.Get(heightBytes); results != nil {
+		b.Write(results)
+	}
+	if err := serializeKidOutput(&b, k); err != nil {
+		return err
+	}
+	if err := kgtnBucket.Put(heightBytes, b.Bytes()); err != nil {
+		return err
+	}
+
+	utxnLog.Infof("Outpoint %v now in kindergarten, will mature "+
+		"at height %v (delay of %v)", k.outPoint,
+		maturityHeight, k.blocksToMaturity)
+
+	return nil
+}
+
+// enterCrib is the first stage in the incubation of an outgoing HTLC output.
+// Once the output has been persisted within the "crib", we'll watch for the
+// spend of the HTLC output, broadcasting its timeout transaction once the HTLC
+// has expired.
+func (u *utxoNursery) enterCrib(baby *babyOutput, heightHint uint32) error {
+	err := u.db.Update(func(tx *bolt.Tx) error {
+		cribBkt, err := tx.CreateBucketIfNotExists(cribBucket)
+		if err != nil {
 			return err
 		}
 
-		// Next, fetch the kindergarten bucket. This output will remain
-		// in this bucket until it's fully mature.
-		kgtnBucket, err := tx.CreateBucketIfNotExists(kindergartenBucket)
-		if err != nil {
+		htlcOutpoint := baby.htlcOutpoint()
+		var outpointBytes bytes.Buffer
+		if err := writeOutpoint(&outpointBytes, &htlcOutpoint); err != nil {
+			return err
+		}
+		var babyBytes bytes.Buffer
+		if err := serializeBabyOutput(&babyBytes, baby); err != nil {
 			return err
 		}
 
-		maturityHeight := k.confHeight + k.blocksToMaturity
+		return cribBkt.Put(outpointBytes.Bytes(), babyBytes.Bytes())
+	})
+	if err != nil {
+		return err
+	}
 
-		heightBytes := make([]byte, 4)
-		byteOrder.PutUint32(heightBytes, maturityHeight)
-
-		// If there're any existing outputs for this particular block
-		// height target, then we'll append this new output to the
-		// serialized list of outputs.
-		var existingOutputs []byte
-		if results := kgtnBucket.Get(heightBytes); results != nil {
-			existingOutputs = results
+	utxnLog.Infof("Htlc outpoint %v now in crib, waiting for expiry at "+
+		"height %v", baby.htlcOutpoint(), baby.expiry)
+
+	return u.watchBaby(baby, heightHint)
+}
+
+// watchBaby registers for the spend of the HTLC output of the passed
+// babyOutput, launching a goroutine which advances the output once it has
+// been spent.
+func (u *utxoNursery) watchBaby(baby *babyOutput, heightHint uint32) error {
+	htlcOutpoint := baby.htlcOutpoint()
+	spendNtfn, err := u.notifier.RegisterSpendNtfn(&htlcOutpoint, heightHint)
+	if err != nil {
+		return err
+	}
+
+	u.wg.Add(1)
+	go u.waitForTimeout(baby, spendNtfn)
+
+	return nil
+}
+
+// waitForTimeout waits for the HTLC output of the passed babyOutput to be
+// spent, and for the spending transaction to confirm. Should it have been
+// spent by our timeout transaction, then the output of the timeout
+// transaction is atomically moved from the "crib" to the "kindergarten"
+// database bucket. Otherwise, the remote party claimed the HTLC, so the
+// output is removed from the crib, as there's nothing left for us to
+// incubate.
+//
+// NOTE: This MUST be run as a goroutine.
+func (u *utxoNursery) waitForTimeout(baby *babyOutput,
+	spendNtfn *chainntnfs.SpendEvent) {
+
+	defer u.wg.Done()
+	defer spendNtfn.Cancel()
+
+	var spend *chainntnfs.SpendDetail
+	select {
+	case s, ok := <-spendNtfn.Spend:
+		if !ok {
+			utxnLog.Errorf("notification chan closed, can't "+
+				"advance htlc output %v", baby.htlcOutpoint())
+			return
+		}
+		spend = s
+
+	case <-u.quit:
+		return
+	}
+
+	// The spend is detected as soon as the spending transaction enters
+	// the mempool, so we'll wait for it to confirm before advancing the
+	// output. Until then, the output remains within the crib, so our
+	// timeout transaction continues to be re-broadcast should the
+	// spending transaction be evicted.
+	spenderHash := *spend.SpenderTxHash
+	confNtfn, err := u.notifier.RegisterConfirmationsNtfn(
+		&spenderHash, 1, uint32(spend.SpendingHeight),
+	)
+	if err != nil {
+		utxnLog.Errorf("unable to register for confirmation of tx %v "+
+			"spending htlc output %v: %v", spenderHash,
+			baby.htlcOutpoint(), err)
+		return
+	}
+
+	var confHeight uint32
+	select {
+	case conf, ok := <-confNtfn.Confirmed:
+		if !ok {
+			utxnLog.Errorf("notification chan closed, can't "+
+				"advance htlc output %v", baby.htlcOutpoint())
+			return
 		}
+		confHeight = conf.BlockHeight
+
+	case <-u.quit:
+		return
+	}
+
+	htlcOutpoint := baby.htlcOutpoint()
+	timeoutHash := baby.timeoutTx.TxHash()
+	timedOut := spenderHash.IsEqual(&timeoutHash)
 
-		// We'll grab the output's offset in the value for its maturity
-		// height so we can add this to the contract index.
-		outputOffset := len(existingOutputs)
+	if timedOut {
+		baby.confHeight = confHeight
 
-		b := bytes.NewBuffer(existingOutputs)
-		if err := serializeKidOutput(b, k); err != nil {
+		utxnLog.Infof("Htlc outpoint %v timed out by %v in block %v, "+
+			"moving to kindergarten", htlcOutpoint, timeoutHash,
+			baby.confHeight)
+	} else {
+		utxnLog.Infof("Htlc outpoint %v claimed by remote party in %v, "+
+			"removing from crib", htlcOutpoint, spend.SpenderTxHash)
+	}
+
+	var graduatedHeight uint32
+	err = u.db.Update(func(tx *bolt.Tx) error {
+		cribBkt := tx.Bucket(cribBucket)
+		if cribBkt == nil {
+			return errors.New("unable to open crib bucket")
+		}
+
+		var outpointBytes bytes.Buffer
+		if err := writeOutpoint(&outpointBytes, &htlcOutpoint); err != nil {
 			return err
 		}
-		if err := kgtnBucket.Put(heightBytes, b.Bytes()); err != nil {
+		if err := cribBkt.Delete(outpointBytes.Bytes()); err != nil {
 			return err
 		}
 
-		// Finally, we'll insert a new entry into the contract index.
-		// The entry itself consists of 4 bytes for the height, and 4
-		// bytes for the offset within the value for the height.
-		var indexEntry [4 + 4]byte
-		copy(indexEntry[:4], heightBytes)
-		byteOrder.PutUint32(indexEntry[4:], uint32(outputOffset))
+		if timedOut {
+			return baby.enterKindergarten(tx)
+		}
 
-		indexBucket, err := tx.CreateBucketIfNotExists(contractIndex)
+		graduatedHeight = fetchLastGraduatedHeight(tx)
+		return nil
+	})
+	if err != nil {
+		utxnLog.Errorf("unable to move htlc output %v out of crib: %v",
+			htlcOutpoint, err)
+		return
+	}
+
+	// With the HTLC claimed by the remote party, this may have been the
+	// last output of the channel being incubated.
+	if !timedOut {
+		err := u.closeChanIfIncubated(&baby.originChanPoint, graduatedHeight)
 		if err != nil {
-			return err
+			utxnLog.Errorf("unable to mark ChannelPoint(%v) as "+
+				"fully closed: %v", baby.originChanPoint, err)
+		}
+	}
+}
+
+// broadcastTimeoutTxns broadcasts the timeout transaction of each HTLC output
+// within the crib which has expired as of the passed height. As the timeout
+// transactions are signed by the remote party, we're unable to bump their
+// fees, so they're re-broadcast each block until the HTLC output has been
+// spent.
+func (u *utxoNursery) broadcastTimeoutTxns(height uint32) error {
+	var babies []*babyOutput
+	err := u.db.View(func(tx *bolt.Tx) error {
+		cribBkt := tx.Bucket(cribBucket)
+		if cribBkt == nil {
+			return nil
+		}
+
+		return cribBkt.ForEach(func(_, babyBytes []byte) error {
+			baby, err := deserializeBabyOutput(
+				bytes.NewReader(babyBytes),
+			)
+			if err != nil {
+				return err
+			}
+
+			if baby.expiry <= height {
+				babies = append(babies, baby)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, baby := range babies {
+		timeoutTx := baby.timeoutTx
+
+		utxnLog.Debugf("Broadcasting timeout tx %v for expired htlc "+
+			"outpoint %v", timeoutTx.TxHash(), baby.htlcOutpoint())
+
+		if err := u.wallet.PublishTransaction(timeoutTx); err != nil {
+			utxnLog.Debugf("unable to broadcast timeout tx %v: %v",
+				timeoutTx.TxHash(), err)
 		}
-		err = indexBucket.Put(originPoint.Bytes(), indexEntry[:])
+	}
+
+	return nil
+}
+
+// closeChanIfIncubated marks the target channel as fully closed once none of
+// its outputs remain to be incubated. Kindergarten outputs maturing at or
+// below the passed height are considered to have graduated.
+func (u *utxoNursery) closeChanIfIncubated(chanPoint *wire.OutPoint,
+	graduatedHeight uint32) error {
+
+	var incubating bool
+	err := u.db.View(func(tx *bolt.Tx) error {
+		outputs, err := fetchChanOutputs(tx, chanPoint, graduatedHeight)
 		if err != nil {
 			return err
 		}
 
-		utxnLog.Infof("Outpoint %v now in kindergarten, will mature "+
-			"at height %v (delay of %v)", k.outPoint,
-			maturityHeight, k.blocksToMaturity)
+		incubating = !outputs.empty()
 		return nil
 	})
 	if err != nil {
-		utxnLog.Errorf("unable to move kid output from preschool bucket "+
-			"to kindergarten bucket: %v", err)
+		return err
+	}
+	if incubating {
+		return nil
 	}
+
+	utxnLog.Infof("All outputs of ChannelPoint(%v) incubated, marking "+
+		"channel as fully closed", chanPoint)
+
+	return u.db.MarkChanFullyClosed(chanPoint)
 }
 
 // graduateKindergarten handles the steps invoked with moving funds from a
@@ -702,38 +1117,30 @@ func (k *kidOutput) waitForPromotion(db *channeldb.DB, confChan *chainntnfs.Conf
 // nursery was offline.
 // TODO(roasbeef): single db transaction for the below
 func (u *utxoNursery) graduateKindergarten(blockHeight uint32) error {
-	// First fetch the set of outputs that we can "graduate" at this
-	// particular block height. We can graduate an output once we've
-	// reached its height maturity.
-	kgtnOutputs, err := fetchGraduatingOutputs(u.db, u.wallet, blockHeight)
-	if err != nil {
+	// First, we'll hand off every output which has reached its height
+	// maturity, yet is still to be swept, to the sweeper, which will sweep
+	// them into the wallet batched with any other outputs it's sweeping.
+	// This includes the outputs of earlier heights whose sweep failed, or
+	// which were being swept when we were shut down.
+	if err := u.sweepMatureOutputs(blockHeight); err != nil {
 		return err
 	}
 
-	// If we're able to graduate any outputs, then create a single
-	// transaction which sweeps them all into the wallet.
-	if len(kgtnOutputs) > 0 {
-		err := sweepGraduatingOutputs(u.wallet, kgtnOutputs)
+	// Now that the outputs maturing at this height have been handed off
+	// to the sweeper, we'll mark the channel of each as being fully
+	// closed within the database, unless it still has outputs being
+	// incubated.
+	kgtnOutputs, err := fetchGraduatingOutputs(u.db, blockHeight)
+	if err != nil {
+		return err
+	}
+	for _, closedChan := range kgtnOutputs {
+		err := u.closeChanIfIncubated(
+			&closedChan.originChanPoint, blockHeight,
+		)
 		if err != nil {
 			return err
 		}
-
-		// Now that the sweeping transaction has been broadcast, for
-		// each of the immature outputs, we'll mark them as being fully
-		// closed within the database.
-		for _, closedChan := range kgtnOutputs {
-			err := u.db.MarkChanFullyClosed(&closedChan.originChanPoint)
-			if err != nil {
-				return err
-			}
-		}
-	}
-
-	// Using a re-org safety margin of 6-blocks, delete any outputs which
-	// have graduated 6 blocks ago.
-	deleteHeight := blockHeight - 6
-	if err := deleteGraduatedOutputs(u.db, deleteHeight); err != nil {
-		return err
 	}
 
 	// Finally, record the last height at which we graduated outputs so we
@@ -746,7 +1153,7 @@ func (u *utxoNursery) graduateKindergarten(blockHeight uint32) error {
 // outputs have become newly spendable. If fetchGraduatingOutputs finds outputs
 // that are ready for "graduation," it passes them on to be swept.  This is the
 // third step in the output incubation process.
-func fetchGraduatingOutputs(db *channeldb.DB, wallet *lnwallet.LightningWallet,
+func fetchGraduatingOutputs(db *channeldb.DB,
 	blockHeight uint32) ([]*kidOutput, error) {
 
 	var results []byte
@@ -780,153 +1187,241 @@ func fetchGraduatingOutputs(db *channeldb.DB, wallet *lnwallet.LightningWallet,
 		utxnLog.Errorf("error while deserializing list of kidOutputs: %v", err)
 	}
 
-	// For each of the outputs, we also generate its proper witness
-	// function based on its witness type. This varies if the output is on
-	// our commitment transaction or theirs, and also if it's an HTLC
-	// output or not.
-	for _, kgtnOutput := range kgtnOutputs {
-		kgtnOutput.witnessFunc = kgtnOutput.witnessType.GenWitnessFunc(
-			&wallet.Cfg.Signer, kgtnOutput.signDescriptor,
-		)
-	}
-
 	utxnLog.Infof("New block: height=%v, sweeping %v mature outputs",
 		blockHeight, len(kgtnOutputs))
 
 	return kgtnOutputs, nil
 }
 
-// sweepGraduatingOutputs generates and broadcasts the transaction that
-// transfers control of funds from a channel commitment transaction to the
-// user's wallet.
-func sweepGraduatingOutputs(wallet *lnwallet.LightningWallet, kgtnOutputs []*kidOutput) error {
-	// Create a transaction which sweeps all the newly mature outputs into
-	// a output controlled by the wallet.
-	// TODO(roasbeef): can be more intelligent about buffering outputs to
-	// be more efficient on-chain.
-	sweepTx, err := createSweepTx(wallet, kgtnOutputs)
+// fetchMatureOutputs returns every output within the "kindergarten" database
+// bucket which matures at or below the passed height. As outputs are only
+// removed from the bucket once their sweep has confirmed, these are the
+// outputs which are yet to be swept.
+func fetchMatureOutputs(db *channeldb.DB,
+	blockHeight uint32) ([]*kidOutput, error) {
+
+	var kids []*kidOutput
+	err := db.View(func(tx *bolt.Tx) error {
+		kgtnBucket := tx.Bucket(kindergartenBucket)
+		if kgtnBucket == nil {
+			return nil
+		}
+
+		return kgtnBucket.ForEach(func(heightBytes, kidBytes []byte) error {
+			// The last graduated height is stored alongside the
+			// rows of outputs, so we'll skip over it.
+			if bytes.Equal(heightBytes, lastGraduatedHeightKey) {
+				return nil
+			}
+			if byteOrder.Uint32(heightBytes) > blockHeight {
+				return nil
+			}
+
+			rowKids, err := deserializeKidList(
+				bytes.NewReader(kidBytes),
+			)
+			if err != nil {
+				return err
+			}
+
+			kids = append(kids, rowKids...)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return kids, nil
+}
+
+// sweepMatureOutputs offers each of the outputs maturing at or below the
+// passed height which isn't already being swept to the sweeper, which
+// transfers control of the funds from the channel commitment transaction to
+// the user's wallet. Each output is retained within the "kindergarten" bucket
+// until its sweep has confirmed, so any output whose sweep fails is offered
+// again at the next block, and should we restart, it's offered again upon
+// startup.
+func (u *utxoNursery) sweepMatureOutputs(blockHeight uint32) error {
+	kgtnOutputs, err := fetchMatureOutputs(u.db, blockHeight)
 	if err != nil {
-		// TODO(roasbeef): retry logic?
-		utxnLog.Errorf("unable to create sweep tx: %v", err)
 		return err
 	}
 
-	utxnLog.Infof("Sweeping %v time-locked outputs "+
-		"with sweep tx (txid=%v): %v", len(kgtnOutputs),
-		sweepTx.TxHash(),
-		newLogClosure(func() string {
-			return spew.Sdump(sweepTx)
-		}))
+	u.sweepingMtx.Lock()
+	defer u.sweepingMtx.Unlock()
 
-	// With the sweep transaction fully signed, broadcast the transaction
-	// to the network. Additionally, we can stop tracking these outputs as
-	// they've just been swept.
-	if err := wallet.PublishTransaction(sweepTx); err != nil {
-		utxnLog.Errorf("unable to broadcast sweep tx: %v, %v",
-			err, spew.Sdump(sweepTx))
-		return err
+	for _, kid := range kgtnOutputs {
+		if _, ok := u.sweeping[kid.outPoint]; ok {
+			continue
+		}
+
+		input := sweep.NewCsvInput(
+			&kid.outPoint, kid.witnessType, kid.signDescriptor,
+			kid.blocksToMaturity,
+		)
+
+		// The outputs are solely ours once mature, so there's no
+		// deadline by which they must be swept. Unlike the outputs of
+		// success transactions, the outputs of timeout transactions
+		// only mature once their HTLC has expired, so there's no
+		// expiry to sweep them by either.
+		resultChan, err := u.sweeper.SweepInput(input, sweep.Params{})
+		if err != nil {
+			utxnLog.Errorf("unable to sweep output %v: %v",
+				kid.outPoint, err)
+			return err
+		}
+
+		utxnLog.Infof("Sweeping time-locked output %v of ChannelPoint(%v)",
+			kid.outPoint, kid.originChanPoint)
+
+		u.sweeping[kid.outPoint] = struct{}{}
+
+		u.wg.Add(1)
+		go u.waitForSweepResult(kid, resultChan, blockHeight)
 	}
 
 	return nil
 }
 
-// createSweepTx creates a final sweeping transaction with all witnesses in
-// place for all inputs. The created transaction has a single output sending
-// all the funds back to the source wallet.
-func createSweepTx(wallet *lnwallet.LightningWallet,
-	matureOutputs []*kidOutput) (*wire.MsgTx, error) {
+// waitForSweepResult waits for the final outcome of sweeping the passed
+// output. Once the sweep has confirmed, the output is removed from the
+// "kindergarten" bucket. Should the sweep fail, then the output is left
+// within the bucket, to be offered to the sweeper again at the next block.
+//
+// NOTE: This MUST be run as a goroutine.
+func (u *utxoNursery) waitForSweepResult(kid *kidOutput,
+	resultChan chan sweep.Result, heightHint uint32) {
 
-	pkScript, err := newSweepPkScript(wallet)
-	if err != nil {
-		return nil, err
-	}
+	defer u.wg.Done()
 
-	var totalSum btcutil.Amount
-	for _, o := range matureOutputs {
-		totalSum += o.amt
+	var result sweep.Result
+	select {
+	case result = <-resultChan:
+	case <-u.quit:
+		return
 	}
 
-	sweepTx := wire.NewMsgTx(2)
-	sweepTx.AddTxOut(&wire.TxOut{
-		PkScript: pkScript,
-		Value:    int64(totalSum - 5000),
-	})
-	for _, utxo := range matureOutputs {
-		sweepTx.AddTxIn(&wire.TxIn{
-			PreviousOutPoint: utxo.outPoint,
-			// TODO(roasbeef): assumes pure block delays
-			Sequence: utxo.blocksToMaturity,
-		})
+	switch result.Err {
+	// The output has been swept, either by us or, should we have
+	// broadcast a revoked state, by the remote party.
+	case nil, sweep.ErrRemoteSpend:
+
+	// The output will be offered again once we restart.
+	case sweep.ErrSweeperShuttingDown:
+		return
+
+	default:
+		utxnLog.Errorf("unable to sweep output %v, will retry at "+
+			"next block: %v", kid.outPoint, result.Err)
+
+		u.sweepingMtx.Lock()
+		delete(u.sweeping, kid.outPoint)
+		u.sweepingMtx.Unlock()
+		return
 	}
 
-	// TODO(roasbeef): insert fee calculation
-	//  * remove hardcoded fee above
+	// The sweep is detected as soon as the sweeping transaction enters the
+	// mempool, so we'll wait for it to confirm, with a re-org safety
+	// margin, before forgetting about the output.
+	sweepTxid := result.Tx.TxHash()
+	confNtfn, err := u.notifier.RegisterConfirmationsNtfn(
+		&sweepTxid, graduationConfs, heightHint,
+	)
+	if err != nil {
+		utxnLog.Errorf("unable to register for confirmation of sweep "+
+			"tx %v: %v", sweepTxid, err)
+		return
+	}
 
-	// With all the inputs in place, use each output's unique witness
-	// function to generate the final witness required for spending.
-	hashCache := txscript.NewTxSigHashes(sweepTx)
-	for i, txIn := range sweepTx.TxIn {
-		witness, err := matureOutputs[i].witnessFunc(sweepTx, hashCache, i)
-		if err != nil {
-			return nil, err
+	select {
+	case _, ok := <-confNtfn.Confirmed:
+		if !ok {
+			return
 		}
+	case <-u.quit:
+		return
+	}
+
+	utxnLog.Infof("Time-locked output %v swept by tx %v", kid.outPoint,
+		sweepTxid)
 
-		txIn.Witness = witness
+	if err := removeSweptOutput(u.db, kid); err != nil {
+		utxnLog.Errorf("unable to remove swept output %v from "+
+			"kindergarten: %v", kid.outPoint, err)
+		return
 	}
 
-	return sweepTx, nil
+	u.sweepingMtx.Lock()
+	delete(u.sweeping, kid.outPoint)
+	u.sweepingMtx.Unlock()
 }
 
-// deleteGraduatedOutputs removes outputs from the kindergarten database bucket
-// when six blockchain confirmations have passed since the outputs were swept.
-// We wait for six confirmations to ensure that the outputs will be swept if a
-// chain reorganization occurs. This is the final step in the output incubation
-// process.
-func deleteGraduatedOutputs(db *channeldb.DB, deleteHeight uint32) error {
+// removeSweptOutput removes the passed output from the kindergarten database
+// bucket once its sweep has confirmed. This is the final step in the output
+// incubation process.
+func removeSweptOutput(db *channeldb.DB, kid *kidOutput) error {
 	return db.Update(func(tx *bolt.Tx) error {
 		kgtnBucket := tx.Bucket(kindergartenBucket)
 		if kgtnBucket == nil {
 			return nil
 		}
 
-		heightBytes := make([]byte, 4)
-		byteOrder.PutUint32(heightBytes, deleteHeight)
-		results := kgtnBucket.Get(heightBytes)
-		if results == nil {
-			return nil
-		}
+		// The output is usually found within the row of its maturity
+		// height, though it's placed at a later height should it have
+		// matured before entering kindergarten, so we'll search each
+		// row for it.
+		var (
+			rowHeight []byte
+			rowBytes  bytes.Buffer
+		)
+		err := kgtnBucket.ForEach(func(heightBytes, kidBytes []byte) error {
+			if rowHeight != nil ||
+				bytes.Equal(heightBytes, lastGraduatedHeightKey) {
 
-		// Delete the row for this height within the kindergarten bucket.k
-		if err := kgtnBucket.Delete(heightBytes); err != nil {
-			return err
-		}
+				return nil
+			}
 
-		sweptOutputs, err := deserializeKidList(bytes.NewBuffer(results))
+			kids, err := deserializeKidList(bytes.NewReader(kidBytes))
+			if err != nil {
+				return err
+			}
+
+			// Re-serialize the row without the swept output.
+			var (
+				b     bytes.Buffer
+				found bool
+			)
+			for _, k := range kids {
+				if k.outPoint == kid.outPoint {
+					found = true
+					continue
+				}
+				if err := serializeKidOutput(&b, k); err != nil {
+					return err
+				}
+			}
+			if found {
+				rowHeight = append([]byte(nil), heightBytes...)
+				rowBytes = b
+			}
+
+			return nil
+		})
 		if err != nil {
 			return err
 		}
-		utxnLog.Infof("Deleting %v swept outputs from kindergarten bucket "+
-			"at block height: %v", len(sweptOutputs), deleteHeight)
-
-		// Additionally, for each output that has now been fully swept,
-		// we'll also remove the index entry for that output.
-		indexBucket := tx.Bucket(contractIndex)
-		if indexBucket == nil {
+		if rowHeight == nil {
 			return nil
 		}
-		for _, sweptOutput := range sweptOutputs {
-			var chanPoint bytes.Buffer
-			err := writeOutpoint(&chanPoint, &sweptOutput.originChanPoint)
-			if err != nil {
-				return err
-			}
 
-			if err := indexBucket.Delete(chanPoint.Bytes()); err != nil {
-				return err
-			}
+		// The row is deleted altogether once it's empty.
+		if rowBytes.Len() == 0 {
+			return kgtnBucket.Delete(rowHeight)
 		}
 
-		return nil
+		return kgtnBucket.Put(rowHeight, rowBytes.Bytes())
 	})
 }
 
@@ -946,6 +1441,22 @@ func putLastHeightGraduated(db *channeldb.DB, blockheight uint32) error {
 	})
 }
 
+// fetchLastGraduatedHeight returns the most recently processed blockheight, or
+// zero if no blocks have been processed yet.
+func fetchLastGraduatedHeight(tx *bolt.Tx) uint32 {
+	kgtnBucket := tx.Bucket(kindergartenBucket)
+	if kgtnBucket == nil {
+		return 0
+	}
+
+	heightBytes := kgtnBucket.Get(lastGraduatedHeightKey)
+	if heightBytes == nil {
+		return 0
+	}
+
+	return byteOrder.Uint32(heightBytes)
+}
+
 // newSweepPkScript creates a new public key script which should be used to
 // sweep any time-locked, or contested channel funds into the wallet.
 // Specifically, the script generated is a version 0,
@@ -982,7 +1493,7 @@ func deserializeKidList(r io.Reader) ([]*kidOutput, error) {
 // serializeKidOutput converts a KidOutput struct into a form
 // suitable for on-disk database storage. Note that the signDescriptor
 // struct field is included so that the output's witness can be generated
-// by createSweepTx() when the output becomes spendable.
+// by the sweeper when the output becomes spendable.
 func serializeKidOutput(w io.Writer, kid *kidOutput) error {
 	var scratch [8]byte
 	byteOrder.PutUint64(scratch[:], uint64(kid.amt))
@@ -1016,9 +1527,7 @@ func serializeKidOutput(w io.Writer, kid *kidOutput) error {
 }
 
 // deserializeKidOutput takes a byte array representation of a kidOutput
-// and converts it to an struct. Note that the witnessFunc method isn't added
-// during deserialization and must be added later based on the value of the
-// witnessType field.
+// and converts it to an struct.
 func deserializeKidOutput(r io.Reader) (*kidOutput, error) {
 	scratch := make([]byte, 8)
 
@@ -1062,6 +1571,49 @@ func deserializeKidOutput(r io.Reader) (*kidOutput, error) {
 	return kid, nil
 }
 
+// serializeBabyOutput converts a babyOutput struct into a form suitable for
+// on-disk database storage. The timeout transaction is stored in full, along
+// with the kidOutput which is incubated once it has been confirmed.
+func serializeBabyOutput(w io.Writer, baby *babyOutput) error {
+	var scratch [4]byte
+	byteOrder.PutUint32(scratch[:], baby.expiry)
+	if _, err := w.Write(scratch[:]); err != nil {
+		return err
+	}
+
+	if err := baby.timeoutTx.Serialize(w); err != nil {
+		return err
+	}
+
+	return serializeKidOutput(w, &baby.kidOutput)
+}
+
+// deserializeBabyOutput takes a byte array representation of a babyOutput
+// and converts it to an struct.
+func deserializeBabyOutput(r io.Reader) (*babyOutput, error) {
+	scratch := make([]byte, 4)
+
+	baby := &babyOutput{}
+
+	if _, err := r.Read(scratch[:]); err != nil {
+		return nil, err
+	}
+	baby.expiry = byteOrder.Uint32(scratch[:])
+
+	baby.timeoutTx = &wire.MsgTx{}
+	if err := baby.timeoutTx.Deserialize(r); err != nil {
+		return nil, err
+	}
+
+	kid, err := deserializeKidOutput(r)
+	if err != nil {
+		return nil, err
+	}
+	baby.kidOutput = *kid
+
+	return baby, nil
+}
+
 // TODO(bvu): copied from channeldb, remove repetition
 func writeOutpoint(w io.Writer, o *wire.OutPoint) error {
 	// TODO(roasbeef): make all scratch buffers on the stack
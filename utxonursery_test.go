@@ -4,9 +4,15 @@ package main
 
 import (
 	"bytes"
+	"io/ioutil"
+	"os"
 	"reflect"
 	"testing"
+	"time"
 
+	"github.com/boltdb/bolt"
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/roasbeef/btcd/btcec"
 	"github.com/roasbeef/btcd/chaincfg/chainhash"
@@ -253,3 +259,331 @@ func TestSerializeKidOutput(t *testing.T) {
 			deserializedKid)
 	}
 }
+
+func TestSerializeBabyOutput(t *testing.T) {
+	kid := kidOutputs[1]
+	descriptor := &signDescriptors[1]
+	pk, err := btcec.ParsePubKey(keys[1], btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to parse pub key: %v", keys[1])
+	}
+	descriptor.PubKey = pk
+	kid.signDescriptor = descriptor
+	kid.witnessType = lnwallet.HtlcOfferedTimeoutSecondLevel
+
+	timeoutTx := wire.NewMsgTx(2)
+	timeoutTx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: outPoints[2],
+		Witness:          [][]byte{{0x01}, {0x02}, {0x03}},
+	})
+	timeoutTx.AddTxOut(&wire.TxOut{
+		Value:    int64(kid.amt),
+		PkScript: []byte{0x00, 0x20},
+	})
+	timeoutTx.LockTime = 500000
+
+	baby := &babyOutput{
+		expiry:    500000,
+		timeoutTx: timeoutTx,
+		kidOutput: kid,
+	}
+
+	var b bytes.Buffer
+	if err := serializeBabyOutput(&b, baby); err != nil {
+		t.Fatalf("unable to serialize baby output: %v", err)
+	}
+
+	deserializedBaby, err := deserializeBabyOutput(&b)
+	if err != nil {
+		t.Fatalf("unable to deserialize baby output: %v", err)
+	}
+
+	if deserializedBaby.expiry != baby.expiry {
+		t.Fatalf("expected expiry %v, got %v", baby.expiry,
+			deserializedBaby.expiry)
+	}
+	if deserializedBaby.timeoutTx.WitnessHash() != timeoutTx.WitnessHash() {
+		t.Fatalf("timeout txns don't match %v vs %v",
+			timeoutTx.WitnessHash(),
+			deserializedBaby.timeoutTx.WitnessHash())
+	}
+	if !reflect.DeepEqual(&baby.kidOutput, &deserializedBaby.kidOutput) {
+		t.Fatalf("kidOutputs don't match %+v vs %+v", baby.kidOutput,
+			deserializedBaby.kidOutput)
+	}
+
+	// The HTLC output of the baby is the one spent by its timeout
+	// transaction.
+	if deserializedBaby.htlcOutpoint() != outPoints[2] {
+		t.Fatalf("expected htlc outpoint %v, got %v", outPoints[2],
+			deserializedBaby.htlcOutpoint())
+	}
+}
+
+// TestKindergartenRetainsUnsweptOutputs tests that mature outputs remain
+// within the kindergarten bucket, so they can be offered to the sweeper again,
+// until each of them is removed once its sweep has confirmed.
+func TestKindergartenRetainsUnsweptOutputs(t *testing.T) {
+	tempDirName, err := ioutil.TempDir("", "channeldb")
+	if err != nil {
+		t.Fatalf("unable to initialize temp "+
+			"directory for channeldb: %v", err)
+	}
+	defer os.RemoveAll(tempDirName)
+
+	db, err := channeldb.Open(tempDirName)
+	if err != nil {
+		t.Fatalf("unable to open channeldb: %v", err)
+	}
+	defer db.Close()
+
+	// Place two outputs maturing at the same height within kindergarten.
+	var kids []*kidOutput
+	for i := 0; i < 2; i++ {
+		kid := kidOutputs[i]
+		descriptor := signDescriptors[i]
+		pk, err := btcec.ParsePubKey(keys[i], btcec.S256())
+		if err != nil {
+			t.Fatalf("unable to parse pub key: %v", keys[i])
+		}
+		descriptor.PubKey = pk
+		kid.signDescriptor = &descriptor
+		kid.confHeight = 100
+		kid.blocksToMaturity = 10
+
+		kids = append(kids, &kid)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, kid := range kids {
+			if err := kid.enterKindergarten(tx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unable to add outputs to kindergarten: %v", err)
+	}
+	if err := putLastHeightGraduated(db, 110); err != nil {
+		t.Fatalf("unable to put last graduated height: %v", err)
+	}
+
+	assertMature := func(height uint32, expected []*kidOutput) {
+		mature, err := fetchMatureOutputs(db, height)
+		if err != nil {
+			t.Fatalf("unable to fetch mature outputs: %v", err)
+		}
+		if !reflect.DeepEqual(mature, expected) {
+			t.Fatalf("expected mature outputs %v at height %v, "+
+				"got %v", expected, height, mature)
+		}
+	}
+
+	// The outputs only mature once the height is reached, and remain
+	// mature at later heights until they've been swept.
+	assertMature(109, nil)
+	assertMature(110, kids)
+	assertMature(120, kids)
+
+	// Once the sweep of the first output has confirmed, only the second
+	// should remain.
+	if err := removeSweptOutput(db, kids[0]); err != nil {
+		t.Fatalf("unable to remove swept output: %v", err)
+	}
+	assertMature(120, kids[1:])
+
+	if err := removeSweptOutput(db, kids[1]); err != nil {
+		t.Fatalf("unable to remove swept output: %v", err)
+	}
+	assertMature(120, nil)
+}
+
+// TestKindergartenAfterGraduatedHeight tests that an output which matured at
+// or below the last graduated height is placed at the next height to graduate
+// upon entering kindergarten, and can be removed from there once swept.
+func TestKindergartenAfterGraduatedHeight(t *testing.T) {
+	tempDirName, err := ioutil.TempDir("", "channeldb")
+	if err != nil {
+		t.Fatalf("unable to initialize temp "+
+			"directory for channeldb: %v", err)
+	}
+	defer os.RemoveAll(tempDirName)
+
+	db, err := channeldb.Open(tempDirName)
+	if err != nil {
+		t.Fatalf("unable to open channeldb: %v", err)
+	}
+	defer db.Close()
+
+	if err := putLastHeightGraduated(db, 110); err != nil {
+		t.Fatalf("unable to put last graduated height: %v", err)
+	}
+
+	kid := kidOutputs[0]
+	descriptor := signDescriptors[0]
+	pk, err := btcec.ParsePubKey(keys[0], btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to parse pub key: %v", keys[0])
+	}
+	descriptor.PubKey = pk
+	kid.signDescriptor = &descriptor
+	kid.confHeight = 100
+	kid.blocksToMaturity = 5
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		return kid.enterKindergarten(tx)
+	})
+	if err != nil {
+		t.Fatalf("unable to add output to kindergarten: %v", err)
+	}
+
+	// The output should graduate at the height following the last
+	// graduated height, rather than at its maturity height.
+	graduating, err := fetchGraduatingOutputs(db, 105)
+	if err != nil {
+		t.Fatalf("unable to fetch graduating outputs: %v", err)
+	}
+	if len(graduating) != 0 {
+		t.Fatalf("expected no outputs to graduate at height 105, "+
+			"got %v", graduating)
+	}
+	graduating, err = fetchGraduatingOutputs(db, 111)
+	if err != nil {
+		t.Fatalf("unable to fetch graduating outputs: %v", err)
+	}
+	if len(graduating) != 1 || graduating[0].outPoint != kid.outPoint {
+		t.Fatalf("expected output %v to graduate at height 111, "+
+			"got %v", kid.outPoint, graduating)
+	}
+
+	if err := removeSweptOutput(db, &kid); err != nil {
+		t.Fatalf("unable to remove swept output: %v", err)
+	}
+	mature, err := fetchMatureOutputs(db, 111)
+	if err != nil {
+		t.Fatalf("unable to fetch mature outputs: %v", err)
+	}
+	if len(mature) != 0 {
+		t.Fatalf("expected swept output to be removed, got %v", mature)
+	}
+}
+
+// TestWaitForTimeoutConfirmation tests that an HTLC output remains within the
+// crib once its timeout transaction has entered the mempool, and only moves
+// to kindergarten once the timeout transaction has confirmed.
+func TestWaitForTimeoutConfirmation(t *testing.T) {
+	tempDirName, err := ioutil.TempDir("", "channeldb")
+	if err != nil {
+		t.Fatalf("unable to initialize temp "+
+			"directory for channeldb: %v", err)
+	}
+	defer os.RemoveAll(tempDirName)
+
+	db, err := channeldb.Open(tempDirName)
+	if err != nil {
+		t.Fatalf("unable to open channeldb: %v", err)
+	}
+	defer db.Close()
+
+	notifier := &mockNotifier{
+		confChannel: make(chan *chainntnfs.TxConfirmation),
+		epochChan:   make(chan *chainntnfs.BlockEpoch),
+	}
+	nursery := newUtxoNursery(db, notifier, nil, nil)
+	defer func() {
+		close(nursery.quit)
+		nursery.wg.Wait()
+	}()
+
+	kid := kidOutputs[1]
+	descriptor := signDescriptors[1]
+	pk, err := btcec.ParsePubKey(keys[1], btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to parse pub key: %v", keys[1])
+	}
+	descriptor.PubKey = pk
+	kid.signDescriptor = &descriptor
+	kid.witnessType = lnwallet.HtlcOfferedTimeoutSecondLevel
+	kid.blocksToMaturity = 5
+
+	timeoutTx := wire.NewMsgTx(2)
+	timeoutTx.AddTxIn(&wire.TxIn{PreviousOutPoint: outPoints[2]})
+	timeoutTx.AddTxOut(&wire.TxOut{Value: int64(kid.amt)})
+	kid.outPoint = wire.OutPoint{Hash: timeoutTx.TxHash()}
+
+	baby := &babyOutput{
+		expiry:    100,
+		timeoutTx: timeoutTx,
+		kidOutput: kid,
+	}
+	if err := nursery.enterCrib(baby, 100); err != nil {
+		t.Fatalf("unable to add output to crib: %v", err)
+	}
+
+	inCrib := func() bool {
+		var found bool
+		err := db.View(func(tx *bolt.Tx) error {
+			cribBkt := tx.Bucket(cribBucket)
+			if cribBkt == nil {
+				return nil
+			}
+
+			var outpointBytes bytes.Buffer
+			htlcOutpoint := baby.htlcOutpoint()
+			err := writeOutpoint(&outpointBytes, &htlcOutpoint)
+			if err != nil {
+				return err
+			}
+
+			found = cribBkt.Get(outpointBytes.Bytes()) != nil
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unable to read crib: %v", err)
+		}
+		return found
+	}
+
+	spendChan := make(chan *chainntnfs.SpendDetail)
+	nursery.wg.Add(1)
+	go nursery.waitForTimeout(baby, &chainntnfs.SpendEvent{
+		Spend:  spendChan,
+		Cancel: func() {},
+	})
+
+	// The timeout transaction enters the mempool, which shouldn't move
+	// the output out of the crib.
+	timeoutHash := timeoutTx.TxHash()
+	spendChan <- &chainntnfs.SpendDetail{
+		SpentOutPoint:  &outPoints[2],
+		SpenderTxHash:  &timeoutHash,
+		SpendingTx:     timeoutTx,
+		SpendingHeight: 101,
+	}
+	if !inCrib() {
+		t.Fatalf("output left crib before timeout tx confirmed")
+	}
+
+	// Once confirmed, the output should move to kindergarten, maturing
+	// relative to the height it confirmed at.
+	notifier.confChannel <- &chainntnfs.TxConfirmation{BlockHeight: 103}
+
+	for i := 0; i < 50; i++ {
+		if !inCrib() {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if inCrib() {
+		t.Fatalf("output didn't leave crib once timeout tx confirmed")
+	}
+
+	mature, err := fetchMatureOutputs(db, 108)
+	if err != nil {
+		t.Fatalf("unable to fetch mature outputs: %v", err)
+	}
+	if len(mature) != 1 || mature[0].outPoint != kid.outPoint {
+		t.Fatalf("expected output %v to mature at height 108, got %v",
+			kid.outPoint, mature)
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/htlcswitch"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+)
+
+// preimageBeacon is an implementation of the htlcswitch.PreimageCache
+// interface which is backed by the persistent witness cache within channeldb,
+// along with the invoice registry. Preimages added to the beacon survive
+// restarts, allowing any HTLCs we must claim on-chain to be redeemed with
+// preimages learned before the restart.
+type preimageBeacon struct {
+	invoices *invoiceRegistry
+
+	wCache *channeldb.WitnessCache
+}
+
+// newPreimageBeacon creates a new preimage beacon backed by the target
+// database and invoice registry.
+func newPreimageBeacon(invoices *invoiceRegistry,
+	chanDB *channeldb.DB) *preimageBeacon {
+
+	return &preimageBeacon{
+		invoices: invoices,
+		wCache:   chanDB.NewWitnessCache(),
+	}
+}
+
+// LookupPreimage attempts to look up a preimage according to its payment hash.
+// If found, the preimage is returned along with true.
+//
+// NOTE: This is part of the htlcswitch.PreimageCache interface.
+func (p *preimageBeacon) LookupPreimage(payHash []byte) ([]byte, bool) {
+	// First, we'll check the invoice registry, as the preimage of an
+	// invoice we created ourselves is known without it being added to the
+	// witness cache. The preimage of a hold invoice is only known once
+	// it's been settled, and that of a canceled invoice is never
+	// revealed.
+	var invoiceHash chainhash.Hash
+	copy(invoiceHash[:], payHash)
+	invoice, err := p.invoices.LookupInvoice(invoiceHash)
+	switch {
+	case err == nil &&
+		invoice.Terms.PaymentPreimage != channeldb.UnknownPreimage &&
+		invoice.Terms.State != channeldb.ContractCanceled:
+
+		preimage := invoice.Terms.PaymentPreimage
+		return preimage[:], true
+
+	case err != nil && err != channeldb.ErrInvoiceNotFound &&
+		err != channeldb.ErrNoInvoicesCreated:
+
+		ltndLog.Errorf("unable to lookup invoice for payment hash "+
+			"%x: %v", payHash, err)
+	}
+
+	// Otherwise, the preimage may have been learned from a settle sent
+	// to us downstream, or derived from an AMP payment, in which case
+	// it'll be found within the witness cache.
+	preimage, err := p.wCache.LookupWitness(
+		channeldb.Sha256HashWitness, payHash,
+	)
+	if err != nil {
+		if err != channeldb.ErrNoWitnesses {
+			ltndLog.Errorf("unable to lookup preimage for payment "+
+				"hash %x: %v", payHash, err)
+		}
+		return nil, false
+	}
+
+	return preimage, true
+}
+
+// AddPreimage adds a newly discovered preimage to the beacon.
+//
+// NOTE: This is part of the htlcswitch.PreimageCache interface.
+func (p *preimageBeacon) AddPreimage(preimage []byte) error {
+	ltndLog.Debugf("Adding preimage=%x to witness cache", preimage)
+
+	return p.wCache.AddWitness(channeldb.Sha256HashWitness, preimage)
+}
+
+// A compile time check to ensure preimageBeacon meets the
+// htlcswitch.PreimageCache interface.
+var _ htlcswitch.PreimageCache = (*preimageBeacon)(nil)
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+)
+
+// TestPreimageBeaconInvoices tests that the preimage beacon returns the
+// preimages of our own invoices, even though they were never added to the
+// witness cache, unless the preimage is unknown or the invoice is canceled.
+func TestPreimageBeaconInvoices(t *testing.T) {
+	t.Parallel()
+
+	tempDirName, err := ioutil.TempDir("", "channeldb")
+	if err != nil {
+		t.Fatalf("unable to initialize temp "+
+			"directory for channeldb: %v", err)
+	}
+	defer os.RemoveAll(tempDirName)
+
+	db, err := channeldb.Open(tempDirName)
+	if err != nil {
+		t.Fatalf("unable to open channeldb: %v", err)
+	}
+	defer db.Close()
+
+	invoices := newInvoiceRegistry(db)
+	beacon := newPreimageBeacon(invoices, db)
+
+	// Without any invoices, no preimage should be found.
+	preimage := [32]byte{1}
+	payHash := sha256.Sum256(preimage[:])
+	if _, ok := beacon.LookupPreimage(payHash[:]); ok {
+		t.Fatal("found preimage without any invoices")
+	}
+
+	// Once an invoice paying to the hash has been added, its preimage
+	// should be found.
+	err = invoices.AddInvoice(&channeldb.Invoice{
+		Terms: channeldb.ContractTerm{
+			PaymentPreimage: preimage,
+			Value:           1000,
+		},
+	})
+	if err != nil {
+		t.Fatalf("unable to add invoice: %v", err)
+	}
+	found, ok := beacon.LookupPreimage(payHash[:])
+	if !ok {
+		t.Fatal("preimage of invoice not found")
+	}
+	if !bytes.Equal(found, preimage[:]) {
+		t.Fatalf("expected preimage %x, got %x", preimage, found)
+	}
+
+	// The preimage of a hold invoice is unknown until it's settled.
+	holdPreimage := [32]byte{2}
+	holdHash := sha256.Sum256(holdPreimage[:])
+	err = invoices.AddInvoice(&channeldb.Invoice{
+		Terms: channeldb.ContractTerm{
+			PaymentHash: holdHash,
+			Value:       1000,
+		},
+	})
+	if err != nil {
+		t.Fatalf("unable to add hold invoice: %v", err)
+	}
+	if _, ok := beacon.LookupPreimage(holdHash[:]); ok {
+		t.Fatal("found preimage of unsettled hold invoice")
+	}
+
+	// Once canceled, the preimage of an invoice should no longer be
+	// revealed.
+	if err := invoices.CancelInvoice(payHash); err != nil {
+		t.Fatalf("unable to cancel invoice: %v", err)
+	}
+	if _, ok := beacon.LookupPreimage(payHash[:]); ok {
+		t.Fatal("found preimage of canceled invoice")
+	}
+
+	// Preimages learned elsewhere should still be found within the
+	// witness cache.
+	if err := beacon.AddPreimage(holdPreimage[:]); err != nil {
+		t.Fatalf("unable to add preimage: %v", err)
+	}
+	found, ok = beacon.LookupPreimage(holdHash[:])
+	if !ok {
+		t.Fatal("preimage within witness cache not found")
+	}
+	if !bytes.Equal(found, holdPreimage[:]) {
+		t.Fatalf("expected preimage %x, got %x", holdPreimage, found)
+	}
+}